@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+// An EnclaveConstructor creates an enclave out of the configuration of an
+// enclave key store.
+type EnclaveConstructor func(config map[string]string) (sw.Enclave, error)
+
+var (
+	enclavesMutex sync.RWMutex
+	enclaves      = map[string]EnclaveConstructor{}
+)
+
+// RegisterEnclave makes an enclave available to the enclave key store under a
+// name. It is meant to be called from the init function of the package
+// implementing the enclave, and panics if the name is already registered.
+func RegisterEnclave(name string, constructor EnclaveConstructor) {
+	enclavesMutex.Lock()
+	defer enclavesMutex.Unlock()
+
+	if constructor == nil {
+		panic("enclave constructor is nil")
+	}
+	if _, ok := enclaves[name]; ok {
+		panic("enclave " + name + " is already registered")
+	}
+	enclaves[name] = constructor
+}
+
+func newEnclave(name string, config map[string]string) (sw.Enclave, error) {
+	enclavesMutex.RLock()
+	constructor, ok := enclaves[name]
+	registered := make([]string, 0, len(enclaves))
+	for n := range enclaves {
+		registered = append(registered, n)
+	}
+	enclavesMutex.RUnlock()
+
+	if !ok {
+		sort.Strings(registered)
+		return nil, errors.Errorf("enclave '%s' is not registered, registered enclaves: %v", name, registered)
+	}
+	return constructor(config)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopEnclave struct{}
+
+func unregisterEnclave(name string) {
+	enclavesMutex.Lock()
+	delete(enclaves, name)
+	enclavesMutex.Unlock()
+}
+
+func (noopEnclave) Seal(name string, data []byte) ([]byte, error)     { return data, nil }
+func (noopEnclave) Unseal(name string, sealed []byte) ([]byte, error) { return sealed, nil }
+
+func TestRegisterEnclave(t *testing.T) {
+	defer unregisterEnclave("test-register")
+	var config map[string]string
+	RegisterEnclave("test-register", func(c map[string]string) (sw.Enclave, error) {
+		config = c
+		return noopEnclave{}, nil
+	})
+
+	enclave, err := newEnclave("test-register", map[string]string{"device": "/dev/sgx"})
+	assert.NoError(t, err)
+	assert.Equal(t, noopEnclave{}, enclave)
+	assert.Equal(t, map[string]string{"device": "/dev/sgx"}, config)
+
+	assert.PanicsWithValue(t, "enclave test-register is already registered", func() {
+		RegisterEnclave("test-register", func(map[string]string) (sw.Enclave, error) { return nil, nil })
+	})
+	assert.PanicsWithValue(t, "enclave constructor is nil", func() { RegisterEnclave("test-nil", nil) })
+
+	_, err = newEnclave("missing", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "enclave 'missing' is not registered")
+}
+
+func TestSWFactoryGetEnclaveKeystore(t *testing.T) {
+	defer unregisterEnclave("test-factory")
+	RegisterEnclave("test-factory", func(map[string]string) (sw.Enclave, error) { return noopEnclave{}, nil })
+
+	dir, err := ioutil.TempDir("", "enclavefactory")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keystore := filepath.Join(dir, "keystore")
+	require.NoError(t, os.Mkdir(keystore, 0755))
+
+	fks, err := sw.NewFileBasedKeyStore(nil, keystore, false)
+	require.NoError(t, err)
+	fileCSP, err := sw.NewDefaultSecurityLevelWithKeystore(fks)
+	require.NoError(t, err)
+	privKey, err := fileCSP.KeyGen(&bccsp.SM2KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	privKeyFile := filepath.Join(keystore, hex.EncodeToString(privKey.SKI())+"_sk")
+	require.FileExists(t, privKeyFile)
+
+	f := &SWFactory{}
+	csp, err := f.Get(&FactoryOpts{
+		SwOpts: &SwOpts{
+			SecLevel:     256,
+			HashFamily:   "SHA2",
+			FileKeystore: &FileKeystoreOpts{KeyStorePath: keystore},
+			EnclaveKeystore: &EnclaveKeystoreOpts{
+				Enclave:      "test-factory",
+				KeyStorePath: filepath.Join(dir, "sealed"),
+				ImportKeys:   true,
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.NoFileExists(t, privKeyFile)
+
+	key, err := csp.GetKey(privKey.SKI())
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+
+	_, err = f.Get(&FactoryOpts{
+		SwOpts: &SwOpts{
+			SecLevel:        256,
+			HashFamily:      "SHA2",
+			EnclaveKeystore: &EnclaveKeystoreOpts{Enclave: "missing", KeyStorePath: dir},
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to initialize enclave key store: enclave 'missing' is not registered")
+}
+
+func TestSWFactoryGetKeyringKeystore(t *testing.T) {
+	f := &SWFactory{}
+	_, err := f.Get(&FactoryOpts{
+		SwOpts: &SwOpts{
+			SecLevel:        256,
+			HashFamily:      "SHA2",
+			KeyringKeystore: &KeyringKeystoreOpts{},
+		},
+	})
+	assert.EqualError(t, err, "Failed to initialize keyring key store: invalid keyring service. It must not be empty")
+}
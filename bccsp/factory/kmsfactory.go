@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/kms"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+const (
+	// KMSBasedFactoryName is the name of the factory of the KMS-based BCCSP implementation
+	KMSBasedFactoryName = "KMS"
+)
+
+// KMSFactory is the factory of the KMS-based BCCSP.
+type KMSFactory struct{}
+
+// Name returns the name of this factory
+func (f *KMSFactory) Name() string {
+	return KMSBasedFactoryName
+}
+
+// Get returns an instance of BCCSP using Opts. The keys which are not held
+// by the KMS are kept in the file key store of the SW options, if any.
+func (f *KMSFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
+	// Validate arguments
+	if config == nil || config.KMSOpts == nil {
+		return nil, errors.New("Invalid config. It must not be nil.")
+	}
+
+	var ks bccsp.KeyStore = sw.NewDummyKeyStore()
+	if config.SwOpts != nil && config.SwOpts.FileKeystore != nil && config.SwOpts.FileKeystore.KeyStorePath != "" {
+		fks, err := sw.NewFileBasedKeyStore(nil, config.SwOpts.FileKeystore.KeyStorePath, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to initialize software key store")
+		}
+		ks = fks
+	}
+
+	return kms.New(*config.KMSOpts, ks)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKMSFactoryName(t *testing.T) {
+	f := &KMSFactory{}
+	assert.Equal(t, f.Name(), KMSBasedFactoryName)
+}
+
+func TestKMSFactoryGetInvalidArgs(t *testing.T) {
+	f := &KMSFactory{}
+
+	_, err := f.Get(nil)
+	assert.EqualError(t, err, "Invalid config. It must not be nil.")
+
+	_, err = f.Get(&FactoryOpts{})
+	assert.EqualError(t, err, "Invalid config. It must not be nil.")
+
+	_, err = f.Get(&FactoryOpts{KMSOpts: &kms.KMSOpts{SecLevel: 256, HashFamily: "SHA2"}})
+	assert.EqualError(t, err, "no KMS endpoint configured")
+}
+
+func TestKMSFactoryGet(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"latest_version":1,"keys":{"1":{"public_key":%q}}}}`,
+			pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}))
+	defer server.Close()
+
+	keystoreDir, err := ioutil.TempDir("", "kmsfactory")
+	require.NoError(t, err)
+	defer os.RemoveAll(keystoreDir)
+
+	opts := &FactoryOpts{
+		ProviderName: "KMS",
+		SwOpts: &SwOpts{
+			FileKeystore: &FileKeystoreOpts{KeyStorePath: keystoreDir},
+		},
+		KMSOpts: &kms.KMSOpts{
+			SecLevel:    256,
+			HashFamily:  "SHA2",
+			Provider:    "vault",
+			Endpoints:   []string{server.URL},
+			Credentials: kms.Credentials{Token: "token"},
+			KeyIDs:      []string{"peer0"},
+		},
+	}
+	csp, err := GetBCCSPFromOpts(opts)
+	require.NoError(t, err)
+
+	pub, err := csp.KeyImport(&priv.PublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	key, err := csp.GetKey(pub.SKI())
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+
+	// software keys are kept in the file key store
+	swKey, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	files, err := ioutil.ReadDir(keystoreDir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	_, err = csp.GetKey(swKey.SKI())
+	assert.NoError(t, err)
+}
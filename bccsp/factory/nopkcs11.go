@@ -10,6 +10,7 @@ package factory
 
 import (
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/kms"
 	"github.com/pkg/errors"
 )
 
@@ -17,8 +18,9 @@ const pkcs11Enabled = false
 
 // FactoryOpts holds configuration information used to initialize factory implementations
 type FactoryOpts struct {
-	ProviderName string  `mapstructure:"default" json:"default" yaml:"Default"`
-	SwOpts       *SwOpts `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SW,omitempty"`
+	ProviderName string       `mapstructure:"default" json:"default" yaml:"Default"`
+	SwOpts       *SwOpts      `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SW,omitempty"`
+	KMSOpts      *kms.KMSOpts `mapstructure:"KMS,omitempty" json:"KMS,omitempty" yaml:"KMS,omitempty"`
 }
 
 // InitFactories must be called before using factory interfaces
@@ -57,6 +59,16 @@ func initFactories(config *FactoryOpts) error {
 		}
 	}
 
+	// KMS-Based BCCSP
+	if config.ProviderName == "KMS" && config.KMSOpts != nil {
+		f := &KMSFactory{}
+		var err error
+		defaultBCCSP, err = initBCCSP(f, config)
+		if err != nil {
+			return errors.Wrapf(err, "Failed initializing KMS.BCCSP")
+		}
+	}
+
 	if defaultBCCSP == nil {
 		return errors.Errorf("Could not find default `%s` BCCSP", config.ProviderName)
 	}
@@ -70,6 +82,8 @@ func GetBCCSPFromOpts(config *FactoryOpts) (bccsp.BCCSP, error) {
 	switch config.ProviderName {
 	case "SW":
 		f = &SWFactory{}
+	case "KMS":
+		f = &KMSFactory{}
 	default:
 		return nil, errors.Errorf("Could not find BCCSP, no '%s' provider", config.ProviderName)
 	}
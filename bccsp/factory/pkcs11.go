@@ -10,6 +10,7 @@ package factory
 
 import (
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/kms"
 	"github.com/hyperledger/fabric/bccsp/pkcs11"
 	"github.com/pkg/errors"
 )
@@ -21,6 +22,7 @@ type FactoryOpts struct {
 	ProviderName string             `mapstructure:"default" json:"default" yaml:"Default"`
 	SwOpts       *SwOpts            `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SW,omitempty"`
 	Pkcs11Opts   *pkcs11.PKCS11Opts `mapstructure:"PKCS11,omitempty" json:"PKCS11,omitempty" yaml:"PKCS11"`
+	KMSOpts      *kms.KMSOpts       `mapstructure:"KMS,omitempty" json:"KMS,omitempty" yaml:"KMS,omitempty"`
 }
 
 // InitFactories must be called before using factory interfaces
@@ -69,6 +71,16 @@ func initFactories(config *FactoryOpts) error {
 		}
 	}
 
+	// KMS-Based BCCSP
+	if config.ProviderName == "KMS" && config.KMSOpts != nil {
+		f := &KMSFactory{}
+		var err error
+		defaultBCCSP, err = initBCCSP(f, config)
+		if err != nil {
+			return errors.Wrapf(err, "Failed initializing KMS.BCCSP")
+		}
+	}
+
 	if defaultBCCSP == nil {
 		return errors.Errorf("Could not find default `%s` BCCSP", config.ProviderName)
 	}
@@ -82,6 +94,8 @@ func GetBCCSPFromOpts(config *FactoryOpts) (bccsp.BCCSP, error) {
 	switch config.ProviderName {
 	case "SW":
 		f = &SWFactory{}
+	case "KMS":
+		f = &KMSFactory{}
 	case "PKCS11":
 		f = &PKCS11Factory{}
 	default:
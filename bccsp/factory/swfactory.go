@@ -45,6 +45,30 @@ func (f *SWFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 
 	var ks bccsp.KeyStore
 	switch {
+	case swOpts.KeyringKeystore != nil:
+		store, err := sw.NewKeyringSecretStore(swOpts.KeyringKeystore.Service)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Failed to initialize keyring key store")
+		}
+		sks, err := newSecretStoreKeyStore(store, swOpts.KeyringKeystore.ImportKeys, swOpts.FileKeystore)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Failed to initialize keyring key store")
+		}
+		ks = sks
+	case swOpts.EnclaveKeystore != nil:
+		enclave, err := newEnclave(swOpts.EnclaveKeystore.Enclave, swOpts.EnclaveKeystore.Config)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Failed to initialize enclave key store")
+		}
+		store, err := sw.NewEnclaveSecretStore(enclave, swOpts.EnclaveKeystore.KeyStorePath)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Failed to initialize enclave key store")
+		}
+		sks, err := newSecretStoreKeyStore(store, swOpts.EnclaveKeystore.ImportKeys, swOpts.FileKeystore)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Failed to initialize enclave key store")
+		}
+		ks = sks
 	case swOpts.FileKeystore != nil:
 		fks, err := sw.NewFileBasedKeyStore(nil, swOpts.FileKeystore.KeyStorePath, false)
 		if err != nil {
@@ -61,6 +85,21 @@ func (f *SWFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 	return sw.NewWithParams(swOpts.SecLevel, swOpts.HashFamily, ks)
 }
 
+// newSecretStoreKeyStore creates a key store over a secret store and, when
+// requested, moves the keys of the file key store into it.
+func newSecretStoreKeyStore(store sw.SecretStore, importKeys bool, fileKeystore *FileKeystoreOpts) (bccsp.KeyStore, error) {
+	ks, err := sw.NewSecretStoreKeyStore(store, false)
+	if err != nil {
+		return nil, err
+	}
+	if importKeys && fileKeystore != nil && fileKeystore.KeyStorePath != "" {
+		if _, err := sw.ImportKeys(ks, fileKeystore.KeyStorePath); err != nil {
+			return nil, errors.WithMessagef(err, "Failed to import keys from %s", fileKeystore.KeyStorePath)
+		}
+	}
+	return ks, nil
+}
+
 // SwOpts contains options for the SWFactory
 type SwOpts struct {
 	// Default algorithms when not specified (Deprecated?)
@@ -69,6 +108,10 @@ type SwOpts struct {
 	FileKeystore  *FileKeystoreOpts  `mapstructure:"filekeystore,omitempty" json:"filekeystore,omitempty" yaml:"FileKeyStore"`
 	DummyKeystore *DummyKeystoreOpts `mapstructure:"dummykeystore,omitempty" json:"dummykeystore,omitempty"`
 	InmemKeystore *InmemKeystoreOpts `mapstructure:"inmemkeystore,omitempty" json:"inmemkeystore,omitempty"`
+	// KeyringKeystore and EnclaveKeystore take precedence over FileKeystore,
+	// whose path is then only used to import keys from
+	KeyringKeystore *KeyringKeystoreOpts `mapstructure:"keyringkeystore,omitempty" json:"keyringkeystore,omitempty" yaml:"KeyringKeyStore"`
+	EnclaveKeystore *EnclaveKeystoreOpts `mapstructure:"enclavekeystore,omitempty" json:"enclavekeystore,omitempty" yaml:"EnclaveKeyStore"`
 }
 
 // Pluggable Keystores, could add JKS, P12, etc..
@@ -80,3 +123,25 @@ type DummyKeystoreOpts struct{}
 
 // InmemKeystoreOpts - empty, as there is no config for the in-memory keystore
 type InmemKeystoreOpts struct{}
+
+// KeyringKeystoreOpts configures a key store keeping the keys in the keyring
+// of the operating system.
+type KeyringKeystoreOpts struct {
+	// Service is the name the keys are stored under in the keyring
+	Service string `mapstructure:"service" yaml:"Service"`
+	// ImportKeys moves the keys of the file key store into the keyring
+	ImportKeys bool `mapstructure:"importkeys" yaml:"ImportKeys"`
+}
+
+// EnclaveKeystoreOpts configures a key store keeping the keys sealed by an
+// enclave registered with RegisterEnclave.
+type EnclaveKeystoreOpts struct {
+	// Enclave is the name the enclave was registered with
+	Enclave string `mapstructure:"enclave" yaml:"Enclave"`
+	// Config is passed to the constructor of the enclave
+	Config map[string]string `mapstructure:"config" yaml:"Config"`
+	// KeyStorePath is the folder the sealed keys are written to
+	KeyStorePath string `mapstructure:"keystore" yaml:"KeyStore"`
+	// ImportKeys moves the keys of the file key store into the enclave key store
+	ImportKeys bool `mapstructure:"importkeys" yaml:"ImportKeys"`
+}
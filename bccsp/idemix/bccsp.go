@@ -22,6 +22,17 @@ type csp struct {
 }
 
 func New(keyStore bccsp.KeyStore) (*csp, error) {
+	return newCSP(keyStore, false)
+}
+
+// NewWithExportableKeys returns an idemix BCCSP whose issuer, revocation and
+// user secret keys can be exported via their Bytes method. It is meant for the
+// tools of the issuer, such as idemixgen, that store the keys they generate.
+func NewWithExportableKeys(keyStore bccsp.KeyStore) (*csp, error) {
+	return newCSP(keyStore, true)
+}
+
+func newCSP(keyStore bccsp.KeyStore, exportable bool) (*csp, error) {
 	base, err := sw.New(keyStore)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed instantiating base bccsp")
@@ -30,9 +41,9 @@ func New(keyStore bccsp.KeyStore) (*csp, error) {
 	csp := &csp{CSP: base}
 
 	// key generators
-	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixIssuerKeyGenOpts{}), &handlers.IssuerKeyGen{Issuer: &bridge.Issuer{NewRand: bridge.NewRandOrPanic}})
-	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixUserSecretKeyGenOpts{}), &handlers.UserKeyGen{User: &bridge.User{NewRand: bridge.NewRandOrPanic}})
-	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixRevocationKeyGenOpts{}), &handlers.RevocationKeyGen{Revocation: &bridge.Revocation{}})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixIssuerKeyGenOpts{}), &handlers.IssuerKeyGen{Exportable: exportable, Issuer: &bridge.Issuer{NewRand: bridge.NewRandOrPanic}})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixUserSecretKeyGenOpts{}), &handlers.UserKeyGen{Exportable: exportable, User: &bridge.User{NewRand: bridge.NewRandOrPanic}})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixRevocationKeyGenOpts{}), &handlers.RevocationKeyGen{Exportable: exportable, Revocation: &bridge.Revocation{}})
 
 	// key derivers
 	base.AddWrapper(reflect.TypeOf(handlers.NewUserSecretKey(nil, false)), &handlers.NymKeyDerivation{
@@ -69,7 +80,8 @@ func New(keyStore bccsp.KeyStore) (*csp, error) {
 
 	// importers
 	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixUserSecretKeyImportOpts{}), &handlers.UserKeyImporter{
-		User: &bridge.User{},
+		Exportable: exportable,
+		User:       &bridge.User{},
 	})
 	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixIssuerPublicKeyImportOpts{}), &handlers.IssuerPublicKeyImporter{
 		Issuer: &bridge.Issuer{},
@@ -78,6 +90,13 @@ func New(keyStore bccsp.KeyStore) (*csp, error) {
 		User: &bridge.User{},
 	})
 	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixRevocationPublicKeyImportOpts{}), &handlers.RevocationPublicKeyImporter{})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixIssuerKeyImportOpts{}), &handlers.IssuerKeyImporter{
+		Exportable: exportable,
+		Issuer:     &bridge.Issuer{},
+	})
+	base.AddWrapper(reflect.TypeOf(&bccsp.IdemixRevocationKeyImportOpts{}), &handlers.RevocationKeyImporter{
+		Exportable: exportable,
+	})
 
 	return csp, nil
 }
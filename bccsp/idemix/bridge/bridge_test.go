@@ -166,6 +166,66 @@ var _ = Describe("Idemix Bridge", func() {
 			})
 
 		})
+
+		Context("secret key import", func() {
+			var (
+				key handlers.IssuerSecretKey
+				raw []byte
+			)
+
+			BeforeEach(func() {
+				var err error
+				key, err = Issuer.NewKey([]string{"A", "B"})
+				Expect(err).NotTo(HaveOccurred())
+				raw, err = key.Bytes()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("success", func() {
+				key2, err := Issuer.NewKeyFromBytes(raw, []string{"A", "B"})
+				Expect(err).NotTo(HaveOccurred())
+
+				raw2, err := key2.Bytes()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(raw2).To(BeEquivalentTo(raw))
+				Expect(key2.Public().Hash()).To(BeEquivalentTo(key.Public().Hash()))
+			})
+
+			It("fails to unmarshal issuer key", func() {
+				key, err := Issuer.NewKeyFromBytes([]byte{0, 1, 2, 3, 4}, nil)
+				Expect(err).To(MatchError(ContainSubstring("failed to unmarshal issuer key")))
+				Expect(key).To(BeNil())
+			})
+
+			It("fails on missing public key", func() {
+				raw, err := proto.Marshal(&cryptolib.IssuerKey{Isk: key.(*bridge.IssuerSecretKey).SK.Isk})
+				Expect(err).NotTo(HaveOccurred())
+
+				key, err := Issuer.NewKeyFromBytes(raw, nil)
+				Expect(err).To(MatchError("invalid issuer key, the secret and the public key must be set"))
+				Expect(key).To(BeNil())
+			})
+
+			It("fails to verify attributes", func() {
+				key, err := Issuer.NewKeyFromBytes(raw, []string{"A"})
+				Expect(err).To(MatchError("invalid number of attributes, expected [2], got [1]"))
+				Expect(key).To(BeNil())
+			})
+
+			It("fails on a secret key not matching the public key", func() {
+				other, err := Issuer.NewKey([]string{"A", "B"})
+				Expect(err).NotTo(HaveOccurred())
+				raw, err := proto.Marshal(&cryptolib.IssuerKey{
+					Isk: other.(*bridge.IssuerSecretKey).SK.Isk,
+					Ipk: key.(*bridge.IssuerSecretKey).SK.Ipk,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				key, err := Issuer.NewKeyFromBytes(raw, nil)
+				Expect(err).To(MatchError("invalid issuer key, the secret key does not match the public key"))
+				Expect(key).To(BeNil())
+			})
+		})
 	})
 
 	Describe("user", func() {
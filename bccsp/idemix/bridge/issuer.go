@@ -10,6 +10,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-amcl/amcl"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/idemix/handlers"
 	cryptolib "github.com/hyperledger/fabric/idemix"
@@ -83,9 +84,55 @@ func (*Issuer) NewPublicKeyFromBytes(raw []byte, attributes []string) (res handl
 			Cause:    err})
 	}
 
-	err = ipk.SetHash()
+	err = checkIssuerPublicKey(ipk, attributes)
 	if err != nil {
-		return nil, errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
+		return nil, err
+	}
+
+	res = &IssuerPublicKey{PK: ipk}
+
+	return
+}
+
+// NewKeyFromBytes converts the passed bytes, the serialization of an issuer
+// key-pair, to an issuer secret key
+func (*Issuer) NewKeyFromBytes(raw []byte, attributes []string) (res handlers.IssuerSecretKey, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = nil
+			err = errors.Errorf("failure [%s]", r)
+		}
+	}()
+
+	sk := new(cryptolib.IssuerKey)
+	err = proto.Unmarshal(raw, sk)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal issuer key")
+	}
+	if len(sk.Isk) == 0 || sk.Ipk == nil {
+		return nil, errors.New("invalid issuer key, the secret and the public key must be set")
+	}
+
+	err = checkIssuerPublicKey(sk.Ipk, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the secret key matches the public key
+	w := cryptolib.GenG2.Mul(FP256BN.FromBytes(sk.Isk))
+	if !w.Equals(cryptolib.Ecp2FromProto(sk.Ipk.W)) {
+		return nil, errors.New("invalid issuer key, the secret key does not match the public key")
+	}
+
+	res = &IssuerSecretKey{SK: sk}
+
+	return
+}
+
+func checkIssuerPublicKey(ipk *cryptolib.IssuerPublicKey, attributes []string) error {
+	err := ipk.SetHash()
+	if err != nil {
+		return errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
 			Type:     bccsp.IdemixIssuerPublicKeyImporterHashError,
 			ErrorMsg: "setting the hash of the issuer public key failed",
 			Cause:    err})
@@ -93,7 +140,7 @@ func (*Issuer) NewPublicKeyFromBytes(raw []byte, attributes []string) (res handl
 
 	err = ipk.Check()
 	if err != nil {
-		return nil, errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
+		return errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
 			Type:     bccsp.IdemixIssuerPublicKeyImporterValidationError,
 			ErrorMsg: "invalid issuer public key",
 			Cause:    err})
@@ -102,7 +149,7 @@ func (*Issuer) NewPublicKeyFromBytes(raw []byte, attributes []string) (res handl
 	if len(attributes) != 0 {
 		// Check the attributes
 		if len(attributes) != len(ipk.AttributeNames) {
-			return nil, errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
+			return errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
 				Type: bccsp.IdemixIssuerPublicKeyImporterNumAttributesError,
 				ErrorMsg: fmt.Sprintf("invalid number of attributes, expected [%d], got [%d]",
 					len(ipk.AttributeNames), len(attributes)),
@@ -111,7 +158,7 @@ func (*Issuer) NewPublicKeyFromBytes(raw []byte, attributes []string) (res handl
 
 		for i, attr := range attributes {
 			if ipk.AttributeNames[i] != attr {
-				return nil, errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
+				return errors.WithStack(&bccsp.IdemixIssuerPublicKeyImporterError{
 					Type:     bccsp.IdemixIssuerPublicKeyImporterAttributeNameError,
 					ErrorMsg: fmt.Sprintf("invalid attribute name at position [%d]", i),
 				})
@@ -119,7 +166,5 @@ func (*Issuer) NewPublicKeyFromBytes(raw []byte, attributes []string) (res handl
 		}
 	}
 
-	res = &IssuerPublicKey{PK: ipk}
-
-	return
+	return nil
 }
@@ -40,6 +40,10 @@ type Issuer interface {
 	// NewPublicKeyFromBytes converts the passed bytes to an Issuer public key
 	// It makes sure that the so obtained public key has the passed attributes, if specified
 	NewPublicKeyFromBytes(raw []byte, attributes []string) (IssuerPublicKey, error)
+
+	// NewKeyFromBytes converts the passed bytes to an Issuer key
+	// It makes sure that the so obtained key has the passed attributes, if specified
+	NewKeyFromBytes(raw []byte, attributes []string) (IssuerSecretKey, error)
 }
 
 // Big represent a big integer
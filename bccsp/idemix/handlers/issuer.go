@@ -134,3 +134,35 @@ func (i *IssuerPublicKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImpor
 
 	return &issuerPublicKey{pk}, nil
 }
+
+// IssuerKeyImporter imports issuer secret keys
+type IssuerKeyImporter struct {
+	// exportable is a flag to allow an issuer secret key to be marked as exportable.
+	// If a secret key is marked as exportable, its Bytes method will return the key's byte representation.
+	Exportable bool
+	// Issuer implements the underlying cryptographic algorithms
+	Issuer Issuer
+}
+
+func (i *IssuerKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
+	der, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("invalid raw, expected byte array")
+	}
+
+	if len(der) == 0 {
+		return nil, errors.New("invalid raw, it must not be nil")
+	}
+
+	o, ok := opts.(*bccsp.IdemixIssuerKeyImportOpts)
+	if !ok {
+		return nil, errors.New("invalid options, expected *bccsp.IdemixIssuerKeyImportOpts")
+	}
+
+	sk, err := i.Issuer.NewKeyFromBytes(der, o.AttributeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuerSecretKey{exportable: i.Exportable, sk: sk}, nil
+}
@@ -256,4 +256,113 @@ var _ = Describe("Issuer", func() {
 		})
 
 	})
+
+	Describe("when importing an issuer secret key", func() {
+		var (
+			IssuerKeyImporter *handlers.IssuerKeyImporter
+
+			fakeIssuer *mock.Issuer
+		)
+
+		BeforeEach(func() {
+			fakeIssuer = &mock.Issuer{}
+
+			IssuerKeyImporter = &handlers.IssuerKeyImporter{}
+			IssuerKeyImporter.Issuer = fakeIssuer
+		})
+
+		Context("and the underlying cryptographic algorithm succeed", func() {
+			var (
+				fakeIssuerSecretKey *mock.IssuerSecretKey
+				fakeRaw             []byte
+				skBytes             []byte
+			)
+
+			BeforeEach(func() {
+				fakeRaw = []byte("a fake raw")
+				skBytes = []byte("a fake secret")
+
+				fakeIssuerPublicKey := &mock.IssuerPublicKey{}
+				fakeIssuerPublicKey.HashReturns([]byte("a fake SKI"))
+
+				fakeIssuerSecretKey = &mock.IssuerSecretKey{}
+				fakeIssuerSecretKey.BytesReturns(skBytes, nil)
+				fakeIssuerSecretKey.PublicReturns(fakeIssuerPublicKey)
+
+				fakeIssuer.NewKeyFromBytesReturns(fakeIssuerSecretKey, nil)
+			})
+
+			Context("and the secret key is exportable", func() {
+				BeforeEach(func() {
+					IssuerKeyImporter.Exportable = true
+				})
+
+				It("returns no error and a key", func() {
+					sk, err := IssuerKeyImporter.KeyImport(fakeRaw, &bccsp.IdemixIssuerKeyImportOpts{AttributeNames: []string{"A"}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(sk).To(BeEquivalentTo(handlers.NewIssuerSecretKey(fakeIssuerSecretKey, true)))
+					Expect(sk.SKI()).To(BeEquivalentTo([]byte("a fake SKI")))
+
+					raw, attributes := fakeIssuer.NewKeyFromBytesArgsForCall(0)
+					Expect(raw).To(BeEquivalentTo(fakeRaw))
+					Expect(attributes).To(Equal([]string{"A"}))
+
+					raw, err = sk.Bytes()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(raw).To(BeEquivalentTo(skBytes))
+				})
+			})
+
+			Context("and the secret key is not exportable", func() {
+				It("returns no error and a key", func() {
+					sk, err := IssuerKeyImporter.KeyImport(fakeRaw, &bccsp.IdemixIssuerKeyImportOpts{})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(sk).To(BeEquivalentTo(handlers.NewIssuerSecretKey(fakeIssuerSecretKey, false)))
+
+					raw, err := sk.Bytes()
+					Expect(err).To(MatchError("not exportable"))
+					Expect(raw).To(BeNil())
+				})
+			})
+		})
+
+		Context("and the underlying cryptographic algorithm fails", func() {
+			BeforeEach(func() {
+				fakeIssuer.NewKeyFromBytesReturns(nil, errors.New("new-key error"))
+			})
+
+			It("returns an error", func() {
+				sk, err := IssuerKeyImporter.KeyImport([]byte{1, 2, 3}, &bccsp.IdemixIssuerKeyImportOpts{})
+				Expect(err).To(MatchError("new-key error"))
+				Expect(sk).To(BeNil())
+			})
+		})
+
+		Context("and the arguments are not well formed", func() {
+
+			Context("and the raw is nil", func() {
+				It("returns error", func() {
+					sk, err := IssuerKeyImporter.KeyImport(nil, &bccsp.IdemixIssuerKeyImportOpts{})
+					Expect(err).To(MatchError("invalid raw, expected byte array"))
+					Expect(sk).To(BeNil())
+				})
+			})
+
+			Context("and the raw is empty", func() {
+				It("returns error", func() {
+					sk, err := IssuerKeyImporter.KeyImport([]byte{}, &bccsp.IdemixIssuerKeyImportOpts{})
+					Expect(err).To(MatchError("invalid raw, it must not be nil"))
+					Expect(sk).To(BeNil())
+				})
+			})
+
+			Context("and the option is not of type *bccsp.IdemixIssuerKeyImportOpts", func() {
+				It("returns error", func() {
+					sk, err := IssuerKeyImporter.KeyImport([]byte{1, 2, 3}, &bccsp.IdemixIssuerPublicKeyImportOpts{})
+					Expect(err).To(MatchError("invalid options, expected *bccsp.IdemixIssuerKeyImportOpts"))
+					Expect(sk).To(BeNil())
+				})
+			})
+		})
+	})
 })
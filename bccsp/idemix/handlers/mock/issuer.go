@@ -21,6 +21,20 @@ type Issuer struct {
 		result1 handlers.IssuerSecretKey
 		result2 error
 	}
+	NewKeyFromBytesStub        func([]byte, []string) (handlers.IssuerSecretKey, error)
+	newKeyFromBytesMutex       sync.RWMutex
+	newKeyFromBytesArgsForCall []struct {
+		arg1 []byte
+		arg2 []string
+	}
+	newKeyFromBytesReturns struct {
+		result1 handlers.IssuerSecretKey
+		result2 error
+	}
+	newKeyFromBytesReturnsOnCall map[int]struct {
+		result1 handlers.IssuerSecretKey
+		result2 error
+	}
 	NewPublicKeyFromBytesStub        func([]byte, []string) (handlers.IssuerPublicKey, error)
 	newPublicKeyFromBytesMutex       sync.RWMutex
 	newPublicKeyFromBytesArgsForCall []struct {
@@ -107,6 +121,80 @@ func (fake *Issuer) NewKeyReturnsOnCall(i int, result1 handlers.IssuerSecretKey,
 	}{result1, result2}
 }
 
+func (fake *Issuer) NewKeyFromBytes(arg1 []byte, arg2 []string) (handlers.IssuerSecretKey, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.newKeyFromBytesMutex.Lock()
+	ret, specificReturn := fake.newKeyFromBytesReturnsOnCall[len(fake.newKeyFromBytesArgsForCall)]
+	fake.newKeyFromBytesArgsForCall = append(fake.newKeyFromBytesArgsForCall, struct {
+		arg1 []byte
+		arg2 []string
+	}{arg1Copy, arg2Copy})
+	fake.recordInvocation("NewKeyFromBytes", []interface{}{arg1Copy, arg2Copy})
+	fake.newKeyFromBytesMutex.Unlock()
+	if fake.NewKeyFromBytesStub != nil {
+		return fake.NewKeyFromBytesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.newKeyFromBytesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Issuer) NewKeyFromBytesCallCount() int {
+	fake.newKeyFromBytesMutex.RLock()
+	defer fake.newKeyFromBytesMutex.RUnlock()
+	return len(fake.newKeyFromBytesArgsForCall)
+}
+
+func (fake *Issuer) NewKeyFromBytesCalls(stub func([]byte, []string) (handlers.IssuerSecretKey, error)) {
+	fake.newKeyFromBytesMutex.Lock()
+	defer fake.newKeyFromBytesMutex.Unlock()
+	fake.NewKeyFromBytesStub = stub
+}
+
+func (fake *Issuer) NewKeyFromBytesArgsForCall(i int) ([]byte, []string) {
+	fake.newKeyFromBytesMutex.RLock()
+	defer fake.newKeyFromBytesMutex.RUnlock()
+	argsForCall := fake.newKeyFromBytesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Issuer) NewKeyFromBytesReturns(result1 handlers.IssuerSecretKey, result2 error) {
+	fake.newKeyFromBytesMutex.Lock()
+	defer fake.newKeyFromBytesMutex.Unlock()
+	fake.NewKeyFromBytesStub = nil
+	fake.newKeyFromBytesReturns = struct {
+		result1 handlers.IssuerSecretKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Issuer) NewKeyFromBytesReturnsOnCall(i int, result1 handlers.IssuerSecretKey, result2 error) {
+	fake.newKeyFromBytesMutex.Lock()
+	defer fake.newKeyFromBytesMutex.Unlock()
+	fake.NewKeyFromBytesStub = nil
+	if fake.newKeyFromBytesReturnsOnCall == nil {
+		fake.newKeyFromBytesReturnsOnCall = make(map[int]struct {
+			result1 handlers.IssuerSecretKey
+			result2 error
+		})
+	}
+	fake.newKeyFromBytesReturnsOnCall[i] = struct {
+		result1 handlers.IssuerSecretKey
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Issuer) NewPublicKeyFromBytes(arg1 []byte, arg2 []string) (handlers.IssuerPublicKey, error) {
 	var arg1Copy []byte
 	if arg1 != nil {
@@ -186,6 +274,8 @@ func (fake *Issuer) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.newKeyMutex.RLock()
 	defer fake.newKeyMutex.RUnlock()
+	fake.newKeyFromBytesMutex.RLock()
+	defer fake.newKeyFromBytesMutex.RUnlock()
 	fake.newPublicKeyFromBytesMutex.RLock()
 	defer fake.newPublicKeyFromBytesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -166,6 +166,50 @@ func (i *RevocationPublicKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyI
 	return &revocationPublicKey{ecdsaPublicKey}, nil
 }
 
+// RevocationKeyImporter imports revocation secret keys. The raw key is
+// either an *ecdsa.PrivateKey, or its PEM or DER encoding.
+type RevocationKeyImporter struct {
+	// exportable is a flag to allow a revocation secret key to be marked as exportable.
+	// If a secret key is marked as exportable, its Bytes method will return the key's byte representation.
+	Exportable bool
+}
+
+func (i *RevocationKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
+	if _, ok := opts.(*bccsp.IdemixRevocationKeyImportOpts); !ok {
+		return nil, errors.New("invalid options, expected *bccsp.IdemixRevocationKeyImportOpts")
+	}
+
+	var privKey *ecdsa.PrivateKey
+	var ok bool
+	switch r := raw.(type) {
+	case *ecdsa.PrivateKey:
+		privKey = r
+	case []byte:
+		if len(r) == 0 {
+			return nil, errors.New("invalid raw, it must not be nil")
+		}
+		der := r
+		if block, _ := pem.Decode(r); block != nil {
+			der = block.Bytes
+		}
+		key, err := x509.ParseECPrivateKey(der)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to parse revocation ECDSA private key bytes")
+		}
+		privKey, ok = key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("key is of type %v, not of type ECDSA", reflect.TypeOf(key))
+		}
+	default:
+		return nil, errors.New("invalid raw, expected byte array or *ecdsa.PrivateKey")
+	}
+	if privKey == nil {
+		return nil, errors.New("invalid raw, it must not be nil")
+	}
+
+	return &revocationSecretKey{exportable: i.Exportable, privKey: privKey}, nil
+}
+
 type CriSigner struct {
 	Revocation Revocation
 }
@@ -213,6 +213,71 @@ var _ = Describe("Revocation", func() {
 
 	})
 
+	Context("when importing a revocation secret key", func() {
+		var (
+			RevocationKeyImporter *handlers.RevocationKeyImporter
+
+			key *ecdsa.PrivateKey
+			der []byte
+		)
+
+		BeforeEach(func() {
+			RevocationKeyImporter = &handlers.RevocationKeyImporter{}
+
+			var err error
+			key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			der, err = x509.MarshalECPrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("imports PEM encoded keys", func() {
+			pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+			k, err := RevocationKeyImporter.KeyImport(pemBytes, &bccsp.IdemixRevocationKeyImportOpts{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k).To(BeEquivalentTo(handlers.NewRevocationSecretKey(key, false)))
+		})
+
+		It("imports DER encoded keys", func() {
+			k, err := RevocationKeyImporter.KeyImport(der, &bccsp.IdemixRevocationKeyImportOpts{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k).To(BeEquivalentTo(handlers.NewRevocationSecretKey(key, false)))
+		})
+
+		It("imports exportable keys", func() {
+			RevocationKeyImporter.Exportable = true
+			k, err := RevocationKeyImporter.KeyImport(key, &bccsp.IdemixRevocationKeyImportOpts{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k).To(BeEquivalentTo(handlers.NewRevocationSecretKey(key, true)))
+
+			raw, err := k.Bytes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(raw).To(BeEquivalentTo(key.D.Bytes()))
+		})
+
+		It("returns an error on invalid options", func() {
+			k, err := RevocationKeyImporter.KeyImport(der, &bccsp.IdemixRevocationPublicKeyImportOpts{})
+			Expect(err).To(MatchError("invalid options, expected *bccsp.IdemixRevocationKeyImportOpts"))
+			Expect(k).To(BeNil())
+		})
+
+		It("returns an error on empty raw", func() {
+			k, err := RevocationKeyImporter.KeyImport([]byte{}, &bccsp.IdemixRevocationKeyImportOpts{})
+			Expect(err).To(MatchError("invalid raw, it must not be nil"))
+			Expect(k).To(BeNil())
+		})
+
+		It("returns an error on invalid raw", func() {
+			k, err := RevocationKeyImporter.KeyImport(RevocationKeyImporter, &bccsp.IdemixRevocationKeyImportOpts{})
+			Expect(err).To(MatchError("invalid raw, expected byte array or *ecdsa.PrivateKey"))
+			Expect(k).To(BeNil())
+
+			k, err = RevocationKeyImporter.KeyImport([]byte("fake-raw"), &bccsp.IdemixRevocationKeyImportOpts{})
+			Expect(err).To(MatchError(ContainSubstring("Failed to parse revocation ECDSA private key bytes")))
+			Expect(k).To(BeNil())
+		})
+	})
+
 })
 
 var _ = Describe("CRI", func() {
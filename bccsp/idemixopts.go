@@ -60,6 +60,24 @@ func (o *IdemixIssuerPublicKeyImportOpts) Ephemeral() bool {
 	return o.Temporary
 }
 
+// IdemixIssuerKeyImportOpts contains the options for importing of an Idemix issuer secret key.
+type IdemixIssuerKeyImportOpts struct {
+	Temporary bool
+	// AttributeNames is a list of attributes to ensure the imported key has
+	AttributeNames []string
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (*IdemixIssuerKeyImportOpts) Algorithm() string {
+	return IDEMIX
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (o *IdemixIssuerKeyImportOpts) Ephemeral() bool {
+	return o.Temporary
+}
+
 // IdemixUserSecretKeyGenOpts contains the options for the generation of an Idemix credential secret key.
 type IdemixUserSecretKeyGenOpts struct {
 	Temporary bool
@@ -281,6 +299,22 @@ func (o *IdemixRevocationPublicKeyImportOpts) Ephemeral() bool {
 	return o.Temporary
 }
 
+// IdemixRevocationKeyImportOpts contains the options for importing of an Idemix revocation secret key.
+type IdemixRevocationKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (*IdemixRevocationKeyImportOpts) Algorithm() string {
+	return IDEMIX
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (o *IdemixRevocationKeyImportOpts) Ephemeral() bool {
+	return o.Temporary
+}
+
 // IdemixCRISignerOpts contains the options to generate an Idemix CRI.
 // The CRI is supposed to be generated by the Issuing authority and
 // can be verified publicly by using the revocation public key.
@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+const aliyunAPIVersion = "2016-01-20"
+
+// aliyunClient uses the RPC API of the Alibaba Cloud KMS, whose requests are
+// signed with an access key.
+type aliyunClient struct {
+	endpoint        string
+	accessKeyID     string
+	accessKeySecret string
+	httpClient      *http.Client
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+type aliyunPublicKeyResponse struct {
+	PublicKey string `json:"PublicKey"`
+}
+
+type aliyunSignResponse struct {
+	Value string `json:"Value"`
+}
+
+type aliyunErrorResponse struct {
+	Code      string `json:"Code"`
+	Message   string `json:"Message"`
+	RequestID string `json:"RequestId"`
+}
+
+// splitAliyunKeyID splits the ID of a key version into its key ID and key
+// version ID.
+func splitAliyunKeyID(keyID string) (string, string, error) {
+	parts := strings.Split(keyID, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid aliyun key ID '%s', expected <key ID>/<key version ID>", keyID)
+	}
+	return parts[0], parts[1], nil
+}
+
+func (c *aliyunClient) PublicKey(keyID string) ([]byte, error) {
+	id, version, err := splitAliyunKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &aliyunPublicKeyResponse{}
+	err = c.call("GetPublicKey", map[string]string{"KeyId": id, "KeyVersionId": version}, resp)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode([]byte(resp.PublicKey))
+	if block == nil {
+		return nil, errors.New("GetPublicKey did not return a PEM encoded public key")
+	}
+	return block.Bytes, nil
+}
+
+func (c *aliyunClient) Sign(keyID, algorithm string, digest []byte) ([]byte, error) {
+	id, version, err := splitAliyunKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var signAlgorithm string
+	switch {
+	case algorithm == bccsp.SM2:
+		signAlgorithm = "SM2DSA"
+	case algorithm == bccsp.ECDSA && len(digest) == 32:
+		signAlgorithm = "ECDSA_SHA_256"
+	default:
+		return nil, errors.Errorf("unsupported signature algorithm %s with a %d bytes digest", algorithm, len(digest))
+	}
+
+	resp := &aliyunSignResponse{}
+	err = c.call("AsymmetricSign", map[string]string{
+		"KeyId":        id,
+		"KeyVersionId": version,
+		"Algorithm":    signAlgorithm,
+		"Digest":       base64.StdEncoding.EncodeToString(digest),
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.StdEncoding.DecodeString(resp.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "AsymmetricSign returned an invalid signature")
+	}
+	return signature, nil
+}
+
+// call invokes the action with the given parameters, and decodes the JSON
+// response into result.
+func (c *aliyunClient) call(action string, params map[string]string, result interface{}) error {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "failed generating nonce")
+	}
+
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	query.Set("Action", action)
+	query.Set("Format", "JSON")
+	query.Set("Version", aliyunAPIVersion)
+	query.Set("AccessKeyId", c.accessKeyID)
+	query.Set("SignatureMethod", "HMAC-SHA1")
+	query.Set("SignatureVersion", "1.0")
+	query.Set("SignatureNonce", hex.EncodeToString(nonce))
+	query.Set("Timestamp", now().UTC().Format("2006-01-02T15:04:05Z"))
+	query.Set("Signature", aliyunSignature(http.MethodGet, query, c.accessKeySecret))
+
+	resp, err := c.httpClient.Get(c.endpoint + "/?" + query.Encode())
+	if err != nil {
+		return errors.Wrapf(err, "%s request failed", action)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed reading %s response", action)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := &aliyunErrorResponse{}
+		if err := json.Unmarshal(body, errResp); err != nil || errResp.Code == "" {
+			return errors.Errorf("%s failed with status %d", action, resp.StatusCode)
+		}
+		return errors.Errorf("%s failed with status %d: %s: %s (request ID %s)", action, resp.StatusCode, errResp.Code, errResp.Message, errResp.RequestID)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return errors.Wrapf(err, "failed decoding %s response", action)
+	}
+	return nil
+}
+
+// aliyunSignature computes the signature of an RPC request: the HMAC-SHA1,
+// keyed by the access key secret, of the method and the percent-encoded
+// sorted query.
+func aliyunSignature(method string, query url.Values, secret string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, aliyunPercentEncode(k)+"="+aliyunPercentEncode(query.Get(k)))
+	}
+	stringToSign := method + "&" + aliyunPercentEncode("/") + "&" + aliyunPercentEncode(strings.Join(pairs, "&"))
+
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func aliyunPercentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.Replace(s, "+", "%20", -1)
+	s = strings.Replace(s, "*", "%2A", -1)
+	s = strings.Replace(s, "%7E", "~", -1)
+	return s
+}
@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// cachePath returns the path of the cached public key of a KMS key. Key IDs
+// are hashed as they may contain characters which are not allowed in file
+// names.
+func cachePath(dir, keyID string) string {
+	hash := sha256.Sum256([]byte(keyID))
+	return filepath.Join(dir, hex.EncodeToString(hash[:])+"_pk.pem")
+}
+
+func readCachedPublicKey(dir, keyID string) ([]byte, error) {
+	raw, err := ioutil.ReadFile(cachePath(dir, keyID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading cached public key")
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.Errorf("cached public key of KMS key %s is not a PEM encoded public key", keyID)
+	}
+	return block.Bytes, nil
+}
+
+func writeCachedPublicKey(dir, keyID string, der []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "failed creating cache folder")
+	}
+
+	path := cachePath(dir, keyID)
+	tmp := path + ".tmp"
+	raw := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err := ioutil.WriteFile(tmp, raw, 0644); err != nil {
+		return errors.Wrap(err, "failed writing cached public key")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "failed writing cached public key")
+	}
+	return nil
+}
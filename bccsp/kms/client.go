@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// newClient creates a client of the configured KMS which fails over between
+// the endpoints.
+func newClient(opts KMSOpts) (Client, error) {
+	if len(opts.Endpoints) == 0 {
+		return nil, errors.New("no KMS endpoint configured")
+	}
+
+	httpClient, err := newHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]Client, 0, len(opts.Endpoints))
+	for _, endpoint := range opts.Endpoints {
+		switch strings.ToLower(opts.Provider) {
+		case AliyunProvider:
+			if opts.Credentials.AccessKeyID == "" || opts.Credentials.AccessKeySecret == "" {
+				return nil, errors.New("the aliyun KMS provider requires an access key ID and secret")
+			}
+			clients = append(clients, &aliyunClient{
+				endpoint:        baseURL(endpoint),
+				accessKeyID:     opts.Credentials.AccessKeyID,
+				accessKeySecret: opts.Credentials.AccessKeySecret,
+				httpClient:      httpClient,
+			})
+		case VaultProvider:
+			if opts.Credentials.Token == "" {
+				return nil, errors.New("the vault KMS provider requires a token")
+			}
+			mountPath := opts.MountPath
+			if mountPath == "" {
+				mountPath = defaultVaultMountPath
+			}
+			clients = append(clients, &vaultClient{
+				endpoint:   baseURL(endpoint),
+				mountPath:  strings.Trim(mountPath, "/"),
+				token:      opts.Credentials.Token,
+				httpClient: httpClient,
+			})
+		default:
+			return nil, errors.Errorf("unknown KMS provider '%s'", opts.Provider)
+		}
+	}
+
+	return &failoverClient{endpoints: opts.Endpoints, clients: clients}, nil
+}
+
+func newHTTPClient(opts KMSOpts) (*http.Client, error) {
+	timeout := defaultTimeout
+	if opts.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(opts.Timeout); err != nil || timeout <= 0 {
+			return nil, errors.Errorf("invalid KMS timeout '%s'", opts.Timeout)
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(opts.TLSCACerts) != 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, path := range opts.TLSCACerts {
+			pem, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed reading TLS CA certificate %s", path)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, errors.Errorf("no certificate found in TLS CA certificate %s", path)
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// baseURL returns the URL of an endpoint, which defaults to HTTPS.
+func baseURL(endpoint string) string {
+	endpoint = strings.TrimRight(endpoint, "/")
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	return endpoint
+}
+
+// failoverClient sends the requests to the endpoint which last succeeded and,
+// when a request fails, fails over to the next endpoints in turn.
+type failoverClient struct {
+	endpoints []string
+	clients   []Client
+
+	mutex   sync.Mutex
+	current int
+}
+
+func (f *failoverClient) PublicKey(keyID string) ([]byte, error) {
+	var der []byte
+	err := f.do(func(c Client) error {
+		var err error
+		der, err = c.PublicKey(keyID)
+		return err
+	})
+	return der, err
+}
+
+func (f *failoverClient) Sign(keyID, algorithm string, digest []byte) ([]byte, error) {
+	var signature []byte
+	err := f.do(func(c Client) error {
+		var err error
+		signature, err = c.Sign(keyID, algorithm, digest)
+		return err
+	})
+	return signature, err
+}
+
+func (f *failoverClient) do(request func(Client) error) error {
+	f.mutex.Lock()
+	start := f.current
+	f.mutex.Unlock()
+
+	var errs []string
+	for i := range f.clients {
+		idx := (start + i) % len(f.clients)
+		err := request(f.clients[idx])
+		if err == nil {
+			if idx != start {
+				f.mutex.Lock()
+				f.current = idx
+				f.mutex.Unlock()
+				logger.Warningf("Failed over to KMS endpoint %s", f.endpoints[idx])
+			}
+			return nil
+		}
+		logger.Warningf("Request to KMS endpoint %s failed: %s", f.endpoints[idx], err)
+		errs = append(errs, f.endpoints[idx]+": "+err.Error())
+	}
+
+	return errors.Errorf("all KMS endpoints failed: [%s]", strings.Join(errs, "; "))
+}
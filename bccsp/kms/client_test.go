@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailover(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	first, second := newFakeClient(), newFakeClient()
+	first.keys["key"], second.keys["key"] = priv, priv
+	client := &failoverClient{endpoints: []string{"first", "second"}, clients: []Client{first, second}}
+
+	_, err = client.PublicKey("key")
+	require.NoError(t, err)
+	assert.Equal(t, 0, client.current)
+
+	first.err = errors.New("connection refused")
+	_, err = client.Sign("key", bccsp.ECDSA, make([]byte, 32))
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.current, "the client must fail over to the second endpoint")
+	assert.Equal(t, 1, first.signCalls)
+	assert.Equal(t, 1, second.signCalls)
+
+	// the second endpoint remains in use once the first one is back
+	first.err = nil
+	_, err = client.Sign("key", bccsp.ECDSA, make([]byte, 32))
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.signCalls)
+	assert.Equal(t, 2, second.signCalls)
+
+	second.err = errors.New("timeout")
+	_, err = client.Sign("key", bccsp.ECDSA, make([]byte, 32))
+	require.NoError(t, err)
+	assert.Equal(t, 0, client.current)
+
+	first.err = errors.New("connection refused")
+	_, err = client.Sign("key", bccsp.ECDSA, make([]byte, 32))
+	assert.EqualError(t, err, "all KMS endpoints failed: [first: connection refused; second: timeout]")
+}
+
+func TestBaseURL(t *testing.T) {
+	assert.Equal(t, "https://kms.cn-hangzhou.aliyuncs.com", baseURL("kms.cn-hangzhou.aliyuncs.com"))
+	assert.Equal(t, "http://127.0.0.1:8200", baseURL("http://127.0.0.1:8200/"))
+}
+
+func TestAliyunClient(t *testing.T) {
+	priv, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+
+	var lastQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastQuery = r.URL.Query()
+
+		// the signature covers all the other parameters
+		signature := lastQuery.Get("Signature")
+		unsigned := url.Values{}
+		for k, v := range lastQuery {
+			if k != "Signature" {
+				unsigned[k] = v
+			}
+		}
+		if lastQuery.Get("AccessKeyId") != "access-key-id" || aliyunSignature(http.MethodGet, unsigned, "access-key-secret") != signature {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"Code":"IncompleteSignature","Message":"The request signature does not conform to Aliyun standards.","RequestId":"request-id"}`)
+			return
+		}
+
+		switch lastQuery.Get("Action") {
+		case "GetPublicKey":
+			json.NewEncoder(w).Encode(map[string]string{
+				"PublicKey": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			})
+		case "AsymmetricSign":
+			json.NewEncoder(w).Encode(map[string]string{
+				"Value": base64.StdEncoding.EncodeToString([]byte("signature")),
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := &aliyunClient{
+		endpoint:        server.URL,
+		accessKeyID:     "access-key-id",
+		accessKeySecret: "access-key-secret",
+		httpClient:      server.Client(),
+		now:             func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}
+
+	pub, err := client.PublicKey("key-id/key-version-id")
+	require.NoError(t, err)
+	assert.Equal(t, der, pub)
+	assert.Equal(t, "key-id", lastQuery.Get("KeyId"))
+	assert.Equal(t, "key-version-id", lastQuery.Get("KeyVersionId"))
+	assert.Equal(t, "2020-01-02T03:04:05Z", lastQuery.Get("Timestamp"))
+	assert.Equal(t, "2016-01-20", lastQuery.Get("Version"))
+
+	signature, err := client.Sign("key-id/key-version-id", bccsp.SM2, []byte("digest"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("signature"), signature)
+	assert.Equal(t, "SM2DSA", lastQuery.Get("Algorithm"))
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("digest")), lastQuery.Get("Digest"))
+
+	_, err = client.Sign("key-id/key-version-id", bccsp.ECDSA, make([]byte, 32))
+	require.NoError(t, err)
+	assert.Equal(t, "ECDSA_SHA_256", lastQuery.Get("Algorithm"))
+
+	_, err = client.Sign("key-id/key-version-id", bccsp.ECDSA, make([]byte, 48))
+	assert.EqualError(t, err, "unsupported signature algorithm ECDSA with a 48 bytes digest")
+
+	_, err = client.PublicKey("key-id")
+	assert.EqualError(t, err, "invalid aliyun key ID 'key-id', expected <key ID>/<key version ID>")
+
+	client.accessKeySecret = "wrong-secret"
+	_, err = client.PublicKey("key-id/key-version-id")
+	assert.EqualError(t, err, "GetPublicKey failed with status 400: IncompleteSignature: The request signature does not conform to Aliyun standards. (request ID request-id)")
+}
+
+func TestAliyunPercentEncode(t *testing.T) {
+	assert.Equal(t, "a%20b%2Ac~d%2F", aliyunPercentEncode("a b*c~d/"))
+}
+
+func TestVaultClient(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+
+	var lastSignRequest vaultSignRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/fabric-transit/keys/peer0":
+			fmt.Fprintf(w, `{"data":{"latest_version":2,"keys":{"1":{"public_key":"old"},"2":{"public_key":%q}}}}`,
+				pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/fabric-transit/sign/peer0":
+			json.NewDecoder(r.Body).Decode(&lastSignRequest)
+			fmt.Fprintf(w, `{"data":{"signature":"vault:v2:%s"}}`, base64.StdEncoding.EncodeToString([]byte("signature")))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := &vaultClient{
+		endpoint:   server.URL,
+		mountPath:  "fabric-transit",
+		token:      "token",
+		httpClient: server.Client(),
+	}
+
+	pub, err := client.PublicKey("peer0")
+	require.NoError(t, err)
+	assert.Equal(t, der, pub)
+
+	digest := make([]byte, 32)
+	signature, err := client.Sign("peer0", bccsp.ECDSA, digest)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("signature"), signature)
+	assert.Equal(t, vaultSignRequest{
+		Input:               base64.StdEncoding.EncodeToString(digest),
+		Prehashed:           true,
+		HashAlgorithm:       "sha2-256",
+		MarshalingAlgorithm: "asn1",
+	}, lastSignRequest)
+
+	_, err = client.Sign("peer0", bccsp.SM2, digest)
+	assert.EqualError(t, err, "unsupported signature algorithm SM2")
+
+	_, err = client.PublicKey("peer1")
+	assert.EqualError(t, err, "request to keys/peer1 failed with status 404")
+
+	client.token = "wrong-token"
+	_, err = client.PublicKey("peer0")
+	assert.EqualError(t, err, "request to keys/peer0 failed with status 403: permission denied")
+}
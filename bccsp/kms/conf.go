@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import "time"
+
+const (
+	// AliyunProvider is the name of the Alibaba Cloud KMS provider
+	AliyunProvider = "aliyun"
+	// VaultProvider is the name of the HashiCorp Vault transit engine provider
+	VaultProvider = "vault"
+
+	defaultTimeout        = 10 * time.Second
+	defaultVaultMountPath = "transit"
+)
+
+// KMSOpts contains options for the KMSFactory
+type KMSOpts struct {
+	// Default algorithms when not specified (Deprecated?)
+	SecLevel   int    `mapstructure:"security" json:"security"`
+	HashFamily string `mapstructure:"hash" json:"hash"`
+	// Provider is the KMS API the keys are held by, aliyun or vault
+	Provider string `mapstructure:"provider" json:"provider"`
+	// Endpoints are the addresses of the KMS. They are tried in order,
+	// failing over to the next endpoint when a request fails.
+	Endpoints []string `mapstructure:"endpoints" json:"endpoints"`
+	// Credentials authenticate the requests to the KMS
+	Credentials Credentials `mapstructure:"credentials" json:"credentials"`
+	// KeyIDs identify the KMS keys to sign with. Aliyun keys are identified
+	// by their key ID and key version ID separated by a slash, Vault keys by
+	// their name.
+	KeyIDs []string `mapstructure:"keyids" json:"keyids"`
+	// CacheDir is the folder the public keys of the KMS keys are cached in,
+	// so that they are available when the KMS is unreachable at startup.
+	CacheDir string `mapstructure:"cachedir,omitempty" json:"cachedir,omitempty"`
+	// Timeout bounds the requests to a KMS endpoint, e.g. 5s
+	Timeout string `mapstructure:"timeout,omitempty" json:"timeout,omitempty"`
+	// TLSCACerts are the paths of the PEM encoded CA certificates the TLS
+	// certificates of the endpoints are verified with, in addition to the
+	// system ones.
+	TLSCACerts []string `mapstructure:"tlscacerts,omitempty" json:"tlscacerts,omitempty"`
+	// MountPath is the path the Vault transit engine is mounted at
+	MountPath string `mapstructure:"mountpath,omitempty" json:"mountpath,omitempty"`
+}
+
+// Credentials authenticate the requests to a KMS.
+type Credentials struct {
+	// AccessKeyID and AccessKeySecret sign the Aliyun requests
+	AccessKeyID     string `mapstructure:"accesskeyid,omitempty" json:"accesskeyid,omitempty"`
+	AccessKeySecret string `mapstructure:"accesskeysecret,omitempty" json:"accesskeysecret,omitempty"`
+	// Token is the Vault token
+	Token string `mapstructure:"token,omitempty" json:"token,omitempty"`
+}
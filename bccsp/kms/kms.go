@@ -0,0 +1,229 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"sync"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("bccsp_kms")
+
+// Client is the API of a KMS the keys are held by.
+type Client interface {
+	// PublicKey returns the DER encoded PKIX public key of the key.
+	PublicKey(keyID string) ([]byte, error)
+	// Sign signs the digest with the key and returns the ASN.1 DER encoded
+	// signature. The algorithm is bccsp.SM2 or bccsp.ECDSA. SM2 digests
+	// already include the identifier of the signer.
+	Sign(keyID, algorithm string, digest []byte) ([]byte, error)
+}
+
+type impl struct {
+	bccsp.BCCSP
+
+	client   Client
+	cacheDir string
+
+	keysLock sync.RWMutex
+	keys     map[string]*kmsKey
+}
+
+// An Option is used to configure the Provider.
+type Option func(p *impl) error
+
+// WithClient returns an option that configures the Provider to use the
+// provided client in place of the clients of the configured endpoints.
+func WithClient(client Client) Option {
+	return func(i *impl) error {
+		i.client = client
+		return nil
+	}
+}
+
+// New returns a new instance of a BCCSP that signs with the SM2 and ECDSA
+// keys held by a KMS. The public keys of the KMS keys are retrieved when the
+// provider is created, and cached in the cache folder of opts, if any, for
+// the times the KMS is unreachable.
+//
+// All other cryptographic functions are delegated to a software based BCCSP
+// implementation that is configured to use the security level and hashing
+// familly from opts and the key store that is provided.
+func New(opts KMSOpts, keyStore bccsp.KeyStore, options ...Option) (bccsp.BCCSP, error) {
+	swCSP, err := sw.NewWithParams(opts.SecLevel, opts.HashFamily, keyStore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing fallback SW BCCSP")
+	}
+
+	csp := &impl{
+		BCCSP:    swCSP,
+		cacheDir: opts.CacheDir,
+		keys:     map[string]*kmsKey{},
+	}
+
+	for _, o := range options {
+		if err := o(csp); err != nil {
+			return nil, err
+		}
+	}
+
+	if csp.client == nil {
+		csp.client, err = newClient(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, keyID := range opts.KeyIDs {
+		if err := csp.loadKey(keyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return csp, nil
+}
+
+// loadKey retrieves the public key of the KMS key, from the KMS or else from
+// the cache, and makes the key available to GetKey.
+func (csp *impl) loadKey(keyID string) error {
+	der, err := csp.client.PublicKey(keyID)
+	if err != nil {
+		if csp.cacheDir == "" {
+			return errors.WithMessagef(err, "failed retrieving public key of KMS key %s", keyID)
+		}
+		logger.Warningf("Failed retrieving public key of KMS key %s, using the cached one: %s", keyID, err)
+		der, err = readCachedPublicKey(csp.cacheDir, keyID)
+		if err != nil {
+			return errors.WithMessagef(err, "failed retrieving public key of KMS key %s from the KMS or the cache", keyID)
+		}
+	} else if csp.cacheDir != "" {
+		if err := writeCachedPublicKey(csp.cacheDir, keyID, der); err != nil {
+			logger.Warningf("Failed caching public key of KMS key %s: %s", keyID, err)
+		}
+	}
+
+	key, err := csp.newKMSKey(keyID, der)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid public key of KMS key %s", keyID)
+	}
+
+	csp.keysLock.Lock()
+	defer csp.keysLock.Unlock()
+	csp.keys[hex.EncodeToString(key.SKI())] = key
+	logger.Debugf("Loaded %s KMS key %s with SKI %x", key.algorithm, keyID, key.SKI())
+	return nil
+}
+
+func (csp *impl) newKMSKey(keyID string, der []byte) (*kmsKey, error) {
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing public key")
+	}
+
+	key := &kmsKey{keyID: keyID}
+	switch pub := pub.(type) {
+	case *sm2.PublicKey:
+		key.algorithm = bccsp.SM2
+		key.sm2PubKey = pub
+		key.pub, err = csp.BCCSP.KeyImport(pub, &bccsp.SM2GoPublicKeyImportOpts{Temporary: true})
+	case *ecdsa.PublicKey:
+		key.algorithm = bccsp.ECDSA
+		key.ecdsaPubKey = pub
+		key.pub, err = csp.BCCSP.KeyImport(pub, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	default:
+		return nil, errors.Errorf("unsupported public key type %T", pub)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetKey returns the KMS key with the given SKI or, if there is none, the
+// key the fallback SW BCCSP has.
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	csp.keysLock.RLock()
+	key, ok := csp.keys[hex.EncodeToString(ski)]
+	csp.keysLock.RUnlock()
+	if ok {
+		return key, nil
+	}
+	return csp.BCCSP.GetKey(ski)
+}
+
+// Sign signs digest using key k. Signatures with KMS keys are made by the
+// KMS.
+func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	key, ok := k.(*kmsKey)
+	if !ok {
+		return csp.BCCSP.Sign(k, digest, opts)
+	}
+	if len(digest) == 0 {
+		return nil, errors.New("Invalid digest. Cannot be empty.")
+	}
+
+	switch key.algorithm {
+	case bccsp.SM2:
+		signature, err := csp.client.Sign(key.keyID, bccsp.SM2, sm2Digest(key.sm2PubKey, digest))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed signing with KMS key %s", key.keyID)
+		}
+		return signature, nil
+	default:
+		signature, err := csp.client.Sign(key.keyID, bccsp.ECDSA, digest)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed signing with KMS key %s", key.keyID)
+		}
+		return utils.SignatureToLowS(key.ecdsaPubKey, signature)
+	}
+}
+
+// Verify verifies signature against key k and digest. Signatures of KMS
+// keys are verified with their public key.
+func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if key, ok := k.(*kmsKey); ok {
+		return csp.BCCSP.Verify(key.pub, signature, digest, opts)
+	}
+	return csp.BCCSP.Verify(k, signature, digest, opts)
+}
+
+// sm2Digest computes the SM2 message digest, which includes the default
+// identifier of the signer, in the same way sm2.Sign does.
+func sm2Digest(pub *sm2.PublicKey, msg []byte) []byte {
+	params := sm2.P256Sm2().Params()
+	id := []byte("1234567812345678")
+	entl := uint16(len(id) * 8)
+
+	z := []byte{byte(entl >> 8), byte(entl)}
+	z = append(z, id...)
+	z = append(z, sm2.SM2PARAM_A.Bytes()...)
+	z = append(z, params.B.Bytes()...)
+	z = append(z, params.Gx.Bytes()...)
+	z = append(z, params.Gy.Bytes()...)
+	z = append(z, padTo32(pub.X.Bytes())...)
+	z = append(z, padTo32(pub.Y.Bytes())...)
+
+	m := append(sm3.SumSM3(z), msg...)
+	return sm3.SumSM3(m)
+}
+
+func padTo32(b []byte) []byte {
+	if len(b) >= 32 {
+		return b
+	}
+	return append(make([]byte, 32-len(b)), b...)
+}
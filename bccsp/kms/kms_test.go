@@ -0,0 +1,259 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient holds software keys in place of a KMS.
+type fakeClient struct {
+	mutex     sync.Mutex
+	keys      map[string]interface{}
+	err       error
+	signCalls int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{keys: map[string]interface{}{}}
+}
+
+func (c *fakeClient) PublicKey(keyID string) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	switch k := c.keys[keyID].(type) {
+	case *sm2.PrivateKey:
+		return x509.MarshalPKIXPublicKey(&k.PublicKey)
+	case *ecdsa.PrivateKey:
+		return x509.MarshalPKIXPublicKey(&k.PublicKey)
+	default:
+		return nil, errors.Errorf("key %s not found", keyID)
+	}
+}
+
+func (c *fakeClient) Sign(keyID, algorithm string, digest []byte) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.signCalls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	switch k := c.keys[keyID].(type) {
+	case *sm2.PrivateKey:
+		if algorithm != bccsp.SM2 {
+			return nil, errors.Errorf("wrong algorithm %s", algorithm)
+		}
+		r, s, err := sm2.SignWithDigest(rand.Reader, k, digest)
+		if err != nil {
+			return nil, err
+		}
+		return sw.MarshalSM2Signature(r, s)
+	case *ecdsa.PrivateKey:
+		if algorithm != bccsp.ECDSA {
+			return nil, errors.Errorf("wrong algorithm %s", algorithm)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			return nil, err
+		}
+		// return high-S signatures, which the provider is expected to normalize
+		if ok, _ := utils.IsLowS(&k.PublicKey, s); ok {
+			s = new(big.Int).Sub(k.Curve.Params().N, s)
+		}
+		return utils.MarshalECDSASignature(r, s)
+	default:
+		return nil, errors.Errorf("key %s not found", keyID)
+	}
+}
+
+func newTestCSP(t *testing.T, client Client, cacheDir string, keyIDs ...string) bccsp.BCCSP {
+	csp, err := New(KMSOpts{
+		SecLevel:   256,
+		HashFamily: "SHA2",
+		KeyIDs:     keyIDs,
+		CacheDir:   cacheDir,
+	}, sw.NewDummyKeyStore(), WithClient(client))
+	require.NoError(t, err)
+	return csp
+}
+
+func TestSM2Sign(t *testing.T) {
+	priv, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	client := newFakeClient()
+	client.keys["sm2-key"] = priv
+	csp := newTestCSP(t, client, "", "sm2-key")
+
+	pub, err := csp.KeyImport(&priv.PublicKey, &bccsp.SM2GoPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	key, err := csp.GetKey(pub.SKI())
+	require.NoError(t, err)
+	assert.True(t, key.Private())
+	assert.False(t, key.Symmetric())
+	_, err = key.Bytes()
+	assert.EqualError(t, err, "Not supported.")
+	keyPub, err := key.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, pub.SKI(), keyPub.SKI())
+
+	msg := []byte("hello world")
+	signature, err := csp.Sign(key, msg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.signCalls)
+
+	r, s, err := sw.UnmarshalSM2Signature(signature)
+	require.NoError(t, err)
+	assert.True(t, sm2.Verify(&priv.PublicKey, msg, r, s), "the signature must be a valid SM2 signature of the message")
+
+	valid, err := csp.Verify(key, signature, msg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = csp.Verify(pub, signature, msg, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = csp.Sign(key, nil, nil)
+	assert.EqualError(t, err, "Invalid digest. Cannot be empty.")
+}
+
+func TestECDSASign(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	client := newFakeClient()
+	client.keys["ecdsa-key"] = priv
+	csp := newTestCSP(t, client, "", "ecdsa-key")
+
+	pub, err := csp.KeyImport(&priv.PublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	key, err := csp.GetKey(pub.SKI())
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("hello world"))
+	signature, err := csp.Sign(key, digest[:], nil)
+	require.NoError(t, err)
+
+	_, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	lowS, err := utils.IsLowS(&priv.PublicKey, s)
+	require.NoError(t, err)
+	assert.True(t, lowS)
+
+	valid, err := csp.Verify(key, signature, digest[:], nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	client.err = errors.New("kms unavailable")
+	_, err = csp.Sign(key, digest[:], nil)
+	assert.EqualError(t, err, "failed signing with KMS key ecdsa-key: kms unavailable")
+}
+
+func TestSoftwareKeys(t *testing.T) {
+	csp := newTestCSP(t, newFakeClient(), "")
+
+	key, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("hello world"))
+	signature, err := csp.Sign(key, digest[:], nil)
+	require.NoError(t, err)
+	valid, err := csp.Verify(key, signature, digest[:], nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = csp.GetKey([]byte("unknown"))
+	assert.Error(t, err)
+}
+
+func TestPublicKeyCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "kms-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	priv, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	client := newFakeClient()
+	client.keys["sm2-key"] = priv
+	newTestCSP(t, client, cacheDir, "sm2-key")
+
+	cached, err := readCachedPublicKey(cacheDir, "sm2-key")
+	require.NoError(t, err)
+	expected, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, expected, cached)
+
+	// the KMS is unreachable at startup
+	client.err = errors.New("kms unavailable")
+	csp := newTestCSP(t, client, cacheDir, "sm2-key")
+	pub, err := csp.KeyImport(&priv.PublicKey, &bccsp.SM2GoPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	key, err := csp.GetKey(pub.SKI())
+	require.NoError(t, err)
+
+	client.err = nil
+	signature, err := csp.Sign(key, []byte("hello world"), nil)
+	require.NoError(t, err)
+	valid, err := csp.Verify(pub, signature, []byte("hello world"), nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	client.err = errors.New("kms unavailable")
+	_, err = New(KMSOpts{SecLevel: 256, HashFamily: "SHA2", KeyIDs: []string{"other-key"}, CacheDir: cacheDir}, sw.NewDummyKeyStore(), WithClient(client))
+	assert.Contains(t, err.Error(), "failed retrieving public key of KMS key other-key from the KMS or the cache: failed reading cached public key")
+
+	_, err = New(KMSOpts{SecLevel: 256, HashFamily: "SHA2", KeyIDs: []string{"sm2-key"}}, sw.NewDummyKeyStore(), WithClient(client))
+	assert.EqualError(t, err, "failed retrieving public key of KMS key sm2-key: kms unavailable")
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := New(KMSOpts{}, sw.NewDummyKeyStore(), WithClient(newFakeClient()))
+	assert.EqualError(t, err, "Failed initializing fallback SW BCCSP: Failed initializing configuration at [0,]: Hash Family not supported []")
+
+	_, err = New(KMSOpts{SecLevel: 256, HashFamily: "SHA2"}, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "no KMS endpoint configured")
+
+	_, err = New(KMSOpts{SecLevel: 256, HashFamily: "SHA2", Provider: "other", Endpoints: []string{"kms"}}, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "unknown KMS provider 'other'")
+
+	_, err = New(KMSOpts{SecLevel: 256, HashFamily: "SHA2", Provider: "aliyun", Endpoints: []string{"kms"}}, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "the aliyun KMS provider requires an access key ID and secret")
+
+	_, err = New(KMSOpts{SecLevel: 256, HashFamily: "SHA2", Provider: "vault", Endpoints: []string{"kms"}}, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "the vault KMS provider requires a token")
+
+	_, err = New(KMSOpts{
+		SecLevel:    256,
+		HashFamily:  "SHA2",
+		Provider:    "vault",
+		Endpoints:   []string{"kms"},
+		Credentials: Credentials{Token: "token"},
+		TLSCACerts:  []string{"testdata/missing.pem"},
+	}, sw.NewDummyKeyStore())
+	assert.Contains(t, err.Error(), "failed reading TLS CA certificate testdata/missing.pem")
+
+	_, err = New(KMSOpts{SecLevel: 256, HashFamily: "SHA2", Endpoints: []string{"kms"}, Timeout: "soon"}, sw.NewDummyKeyStore())
+	assert.EqualError(t, err, "invalid KMS timeout 'soon'")
+}
@@ -0,0 +1,152 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// vaultClient uses the transit secrets engine of HashiCorp Vault, which
+// holds ECDSA keys.
+type vaultClient struct {
+	endpoint   string
+	mountPath  string
+	token      string
+	httpClient *http.Client
+}
+
+type vaultKeyResponse struct {
+	Data struct {
+		LatestVersion int `json:"latest_version"`
+		Keys          map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+	} `json:"data"`
+}
+
+type vaultSignRequest struct {
+	Input               string `json:"input"`
+	Prehashed           bool   `json:"prehashed"`
+	HashAlgorithm       string `json:"hash_algorithm"`
+	MarshalingAlgorithm string `json:"marshaling_algorithm"`
+}
+
+type vaultSignResponse struct {
+	Data struct {
+		Signature string `json:"signature"`
+	} `json:"data"`
+}
+
+type vaultErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+func (c *vaultClient) PublicKey(keyID string) ([]byte, error) {
+	resp := &vaultKeyResponse{}
+	if err := c.call(http.MethodGet, "keys/"+url.PathEscape(keyID), nil, resp); err != nil {
+		return nil, err
+	}
+	key, ok := resp.Data.Keys[strconv.Itoa(resp.Data.LatestVersion)]
+	if !ok {
+		return nil, errors.Errorf("key %s has no version %d", keyID, resp.Data.LatestVersion)
+	}
+	block, _ := pem.Decode([]byte(key.PublicKey))
+	if block == nil {
+		return nil, errors.Errorf("key %s has no PEM encoded public key", keyID)
+	}
+	return block.Bytes, nil
+}
+
+func (c *vaultClient) Sign(keyID, algorithm string, digest []byte) ([]byte, error) {
+	if algorithm != bccsp.ECDSA {
+		return nil, errors.Errorf("unsupported signature algorithm %s", algorithm)
+	}
+	var hashAlgorithm string
+	switch len(digest) {
+	case 32:
+		hashAlgorithm = "sha2-256"
+	case 48:
+		hashAlgorithm = "sha2-384"
+	default:
+		return nil, errors.Errorf("unsupported digest length %d", len(digest))
+	}
+
+	req := &vaultSignRequest{
+		Input:               base64.StdEncoding.EncodeToString(digest),
+		Prehashed:           true,
+		HashAlgorithm:       hashAlgorithm,
+		MarshalingAlgorithm: "asn1",
+	}
+	resp := &vaultSignResponse{}
+	if err := c.call(http.MethodPost, "sign/"+url.PathEscape(keyID), req, resp); err != nil {
+		return nil, err
+	}
+
+	// signatures are prefixed by vault and the version of the key,
+	// e.g. vault:v1:MEUCIQ...
+	parts := strings.Split(resp.Data.Signature, ":")
+	signature, err := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return nil, errors.Wrap(err, "vault returned an invalid signature")
+	}
+	return signature, nil
+}
+
+// call sends a request to the path of the transit engine, and decodes the
+// JSON response into result.
+func (c *vaultClient) call(method, path string, body, result interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return errors.Wrap(err, "failed encoding request")
+		}
+	}
+
+	target := c.endpoint + "/v1/" + c.mountPath + "/" + path
+	req, err := http.NewRequest(method, target, bytes.NewReader(reqBody))
+	if err != nil {
+		return errors.Wrap(err, "failed creating request")
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "request to %s failed", path)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed reading response of %s", path)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := &vaultErrorResponse{}
+		if err := json.Unmarshal(respBody, errResp); err != nil || len(errResp.Errors) == 0 {
+			return errors.Errorf("request to %s failed with status %d", path, resp.StatusCode)
+		}
+		return errors.Errorf("request to %s failed with status %d: %s", path, resp.StatusCode, strings.Join(errResp.Errors, ", "))
+	}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return errors.Wrapf(err, "failed decoding response of %s", path)
+	}
+	return nil
+}
@@ -29,6 +29,12 @@ const (
 	SM3SIG = "SM3SIG"
 	// SM2ReRand SM2 key re-randomization
 	SM2ReRand = "SM2"
+	// SM2KeyAgreement SM2 key agreement (GB/T 32918.3)
+	SM2KeyAgreement = "SM2_KA"
+	// SM2ECDH Elliptic Curve Diffie-Hellman over the SM2 curve
+	SM2ECDH = "SM2_ECDH"
+	// SM3KDF SM3 based key derivation function (GB/T 32918.4)
+	SM3KDF = "SM3_KDF"
 
 	// AES Advanced Encryption Standard at the default security level.
 	// Each BCCSP may or may not support default security level. If not supported than
@@ -377,3 +383,83 @@ func (opts *SM2GoPublicKeyImportOpts) Algorithm() string {
 func (opts *SM2GoPublicKeyImportOpts) Ephemeral() bool {
 	return opts.Temporary
 }
+
+// SM2KeyAgreementOpts contains options for the SM2 key agreement (GB/T 32918.3),
+// which derives a symmetric key shared with a peer from the static SM2 private key
+// the opts are applied to, the ephemeral SM2 private key of this party, and the
+// static and ephemeral SM2 public keys of the peer.
+type SM2KeyAgreementOpts struct {
+	Temporary bool
+	// Initiator is true for the party initiating the key agreement
+	Initiator bool
+	// EphemeralKey is the ephemeral SM2 private key of this party
+	EphemeralKey Key
+	// PeerPublicKey is the static SM2 public key of the peer
+	PeerPublicKey Key
+	// PeerEphemeralKey is the ephemeral SM2 public key of the peer
+	PeerEphemeralKey Key
+	// ID and PeerID are the distinguishing identifiers of this party and of
+	// the peer, the default SM2 identifier is used when they are empty
+	ID     []byte
+	PeerID []byte
+	// KeyLen is the length in bytes of the derived key
+	KeyLen int
+}
+
+// Algorithm returns the key derivation algorithm identifier (to be used).
+func (opts *SM2KeyAgreementOpts) Algorithm() string {
+	return SM2KeyAgreement
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *SM2KeyAgreementOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// SM2ECDHKeyOpts contains options for the Diffie-Hellman key agreement over the
+// SM2 curve, which derives a symmetric key shared with a peer from the SM2
+// private key the opts are applied to and the SM2 public key of the peer.
+// The shared secret is expanded with the SM3 key derivation function.
+type SM2ECDHKeyOpts struct {
+	Temporary bool
+	// PeerPublicKey is the SM2 public key of the peer
+	PeerPublicKey Key
+	// SharedInfo is optional data shared by the parties, input to the
+	// key derivation function
+	SharedInfo []byte
+	// KeyLen is the length in bytes of the derived key
+	KeyLen int
+}
+
+// Algorithm returns the key derivation algorithm identifier (to be used).
+func (opts *SM2ECDHKeyOpts) Algorithm() string {
+	return SM2ECDH
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *SM2ECDHKeyOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// SM3KDFOpts contains options for deriving a symmetric key from another one
+// with the SM3 key derivation function (GB/T 32918.4).
+type SM3KDFOpts struct {
+	Temporary bool
+	// Info is optional data bound to the derived key
+	Info []byte
+	// KeyLen is the length in bytes of the derived key
+	KeyLen int
+}
+
+// Algorithm returns the key derivation algorithm identifier (to be used).
+func (opts *SM3KDFOpts) Algorithm() string {
+	return SM3KDF
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *SM3KDFOpts) Ephemeral() bool {
+	return opts.Temporary
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// An Enclave seals data so that only the enclave can unseal it, such as a
+// trusted execution environment binding the data to the platform and to the
+// code running in it.
+type Enclave interface {
+	// Seal returns the sealed form of the data, labelled with a name the
+	// enclave may bind to the sealed data.
+	Seal(name string, data []byte) ([]byte, error)
+
+	// Unseal returns the data of a sealed form returned by Seal for the
+	// same name.
+	Unseal(name string, sealed []byte) ([]byte, error)
+}
+
+const sealedSuffix = ".sealed"
+
+// NewEnclaveSecretStore returns a secret store keeping the secrets sealed by
+// an enclave in the files of a directory. Only the sealed form of the secrets
+// is written to disk.
+func NewEnclaveSecretStore(enclave Enclave, dir string) (SecretStore, error) {
+	if enclave == nil {
+		return nil, errors.New("invalid enclave. It must be different from nil")
+	}
+	if dir == "" {
+		return nil, errors.New("invalid sealed key store path. It must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed creating sealed key store at %s", dir)
+	}
+	return &enclaveSecretStore{enclave: enclave, dir: dir}, nil
+}
+
+type enclaveSecretStore struct {
+	enclave Enclave
+	dir     string
+}
+
+// Put seals a secret and writes it to its file.
+func (s *enclaveSecretStore) Put(name string, secret []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	sealed, err := s.enclave.Seal(name, secret)
+	if err != nil {
+		return errors.WithMessagef(err, "failed sealing secret %s", name)
+	}
+	return errors.Wrapf(ioutil.WriteFile(path, sealed, 0600), "failed writing sealed secret %s", name)
+}
+
+// Get reads a sealed secret from its file and unseals it, or returns nil when
+// there is none.
+func (s *enclaveSecretStore) Get(name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading sealed secret %s", name)
+	}
+	secret, err := s.enclave.Unseal(name, sealed)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed unsealing secret %s", name)
+	}
+	return secret, nil
+}
+
+func (s *enclaveSecretStore) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", errors.Errorf("invalid secret name %q", name)
+	}
+	return filepath.Join(s.dir, name+sealedSuffix), nil
+}
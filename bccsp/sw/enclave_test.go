@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnclaveSecretStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enclaveks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewEnclaveSecretStore(&xorEnclave{}, dir)
+	require.NoError(t, err)
+
+	secret, err := store.Get("abcd_sk")
+	assert.NoError(t, err)
+	assert.Nil(t, secret)
+
+	err = store.Put("abcd_sk", []byte("secret"))
+	require.NoError(t, err)
+	secret, err = store.Get("abcd_sk")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), secret)
+	sealed, err := ioutil.ReadFile(filepath.Join(dir, "abcd_sk.sealed"))
+	require.NoError(t, err)
+	assert.NotEqual(t, []byte("secret"), sealed)
+
+	err = store.Put("../abcd_sk", []byte("secret"))
+	assert.EqualError(t, err, `invalid secret name "../abcd_sk"`)
+
+	_, err = NewEnclaveSecretStore(nil, dir)
+	assert.EqualError(t, err, "invalid enclave. It must be different from nil")
+	_, err = NewEnclaveSecretStore(&xorEnclave{}, "")
+	assert.EqualError(t, err, "invalid sealed key store path. It must not be empty")
+
+	store, err = NewEnclaveSecretStore(&xorEnclave{err: errors.New("enclave lost")}, dir)
+	require.NoError(t, err)
+	_, err = store.Get("abcd_sk")
+	assert.EqualError(t, err, "failed unsealing secret abcd_sk: enclave lost")
+	err = store.Put("abcd_sk", []byte("secret"))
+	assert.EqualError(t, err, "failed sealing secret abcd_sk: enclave lost")
+}
+
+type xorEnclave struct {
+	err error
+}
+
+func (e *xorEnclave) Seal(name string, data []byte) ([]byte, error) {
+	return e.xor(data)
+}
+
+func (e *xorEnclave) Unseal(name string, sealed []byte) ([]byte, error) {
+	return e.xor(sealed)
+}
+
+func (e *xorEnclave) xor(data []byte) ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ 0x5a
+	}
+	return out, nil
+}
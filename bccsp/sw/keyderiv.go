@@ -134,17 +134,23 @@ func (kd *aesPrivateKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts
 
 	aesK := k.(*aesPrivateKey)
 
-	switch hmacOpts := opts.(type) {
+	switch derivOpts := opts.(type) {
 	case *bccsp.HMACTruncated256AESDeriveKeyOpts:
 		mac := hmac.New(kd.conf.hashFunction, aesK.privKey)
-		mac.Write(hmacOpts.Argument())
+		mac.Write(derivOpts.Argument())
 		return &aesPrivateKey{mac.Sum(nil)[:kd.conf.aesBitLength], false}, nil
 
 	case *bccsp.HMACDeriveKeyOpts:
 		mac := hmac.New(kd.conf.hashFunction, aesK.privKey)
-		mac.Write(hmacOpts.Argument())
+		mac.Write(derivOpts.Argument())
 		return &aesPrivateKey{mac.Sum(nil), true}, nil
 
+	case *bccsp.SM3KDFOpts:
+		if derivOpts.KeyLen <= 0 {
+			return nil, errors.New("Invalid key length. It must be positive.")
+		}
+		return &aesPrivateKey{sm3KDF(append(append([]byte{}, aesK.privKey...), derivOpts.Info...), derivOpts.KeyLen), false}, nil
+
 	default:
 		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
 	}
@@ -240,6 +246,19 @@ func (kd *sm2PrivateKeyKeyDeriver) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts
 			return nil, errors.New("Failed temporary public key IsOnCurve check.")
 		}
 		return &sm2PrivateKey{tempSK}, nil
+	// Derive a key shared with a peer
+	case *bccsp.SM2KeyAgreementOpts:
+		key, err := sm2KeyAgreement(sm2K.privKey, opts.(*bccsp.SM2KeyAgreementOpts))
+		if err != nil {
+			return nil, fmt.Errorf("Failed SM2 key agreement [%s]", err)
+		}
+		return &aesPrivateKey{key, false}, nil
+	case *bccsp.SM2ECDHKeyOpts:
+		key, err := sm2ECDH(sm2K.privKey, opts.(*bccsp.SM2ECDHKeyOpts))
+		if err != nil {
+			return nil, fmt.Errorf("Failed SM2 ECDH [%s]", err)
+		}
+		return &aesPrivateKey{key, false}, nil
 	default:
 		return nil, fmt.Errorf("Unsupported 'KeyDerivOpts' provided [%v]", opts)
 	}
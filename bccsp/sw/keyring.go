@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// commandRunner runs a command feeding it stdin, and returns its standard
+// output and exit code. The error is reserved to failures to run the command.
+type commandRunner func(stdin []byte, name string, args ...string) (stdout []byte, exitCode int, err error)
+
+func runCommand(stdin []byte, name string, args ...string) ([]byte, int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		logger.Debugf("Command %s exited with code %d: %s", name, exitErr.ExitCode(), stderr.String())
+		return stdout.Bytes(), exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed running %s", name)
+	}
+	return stdout.Bytes(), 0, nil
+}
+
+// keyringTool drives the command line tool managing the keyring of an
+// operating system.
+type keyringTool interface {
+	put(run commandRunner, service, account, secret string) error
+	get(run commandRunner, service, account string) (secret string, found bool, err error)
+}
+
+// NewKeyringSecretStore returns a secret store keeping the secrets in the
+// keyring of the operating system, under the given service name. The keyring
+// is reached through secret-tool, the Secret Service client of libsecret, on
+// Linux and through security on macOS. The secrets never appear on the command
+// line of the tools.
+func NewKeyringSecretStore(service string) (SecretStore, error) {
+	if service == "" {
+		return nil, errors.New("invalid keyring service. It must not be empty")
+	}
+
+	var tool keyringTool
+	var toolName string
+	switch runtime.GOOS {
+	case "darwin":
+		tool, toolName = &macosKeychain{}, "security"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		tool, toolName = &secretService{}, "secret-tool"
+	default:
+		return nil, errors.Errorf("keyring key store is not supported on %s", runtime.GOOS)
+	}
+	if _, err := exec.LookPath(toolName); err != nil {
+		return nil, errors.Wrapf(err, "keyring tool %s is not available", toolName)
+	}
+
+	return &keyringSecretStore{service: service, tool: tool, run: runCommand}, nil
+}
+
+type keyringSecretStore struct {
+	service string
+	tool    keyringTool
+	run     commandRunner
+}
+
+// Put stores a secret in the keyring. Secrets are base64 encoded as keyrings
+// are meant to hold text.
+func (s *keyringSecretStore) Put(name string, secret []byte) error {
+	return s.tool.put(s.run, s.service, name, base64.StdEncoding.EncodeToString(secret))
+}
+
+// Get returns a secret from the keyring, or nil when there is none.
+func (s *keyringSecretStore) Get(name string) ([]byte, error) {
+	encoded, found, err := s.tool.get(s.run, s.service, name)
+	if err != nil || !found {
+		return nil, err
+	}
+	secret, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed decoding secret %s of keyring service %s", name, s.service)
+	}
+	return secret, nil
+}
+
+// secretService drives secret-tool, which reads the secret to store from its
+// standard input.
+type secretService struct{}
+
+func (secretService) put(run commandRunner, service, account, secret string) error {
+	label := fmt.Sprintf("%s %s", service, account)
+	_, code, err := run([]byte(secret), "secret-tool", "store", "--label="+label, "service", service, "account", account)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return errors.Errorf("secret-tool failed storing secret %s of service %s with exit code %d", account, service, code)
+	}
+	return nil
+}
+
+func (secretService) get(run commandRunner, service, account string) (string, bool, error) {
+	out, code, err := run(nil, "secret-tool", "lookup", "service", service, "account", account)
+	if err != nil {
+		return "", false, err
+	}
+	// secret-tool exits with code 1 and prints nothing when no secret matches
+	if code == 1 && len(bytes.TrimSpace(out)) == 0 {
+		return "", false, nil
+	}
+	if code != 0 {
+		return "", false, errors.Errorf("secret-tool failed looking up secret %s of service %s with exit code %d", account, service, code)
+	}
+	return string(bytes.TrimSpace(out)), true, nil
+}
+
+// macosKeychain drives security in interactive mode, which reads its
+// commands from the standard input, to keep the secret off its command line.
+type macosKeychain struct{}
+
+// errSecItemNotFound is the exit code of security when no item matches.
+const errSecItemNotFound = 44
+
+func (macosKeychain) put(run commandRunner, service, account, secret string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", strconv.Quote(service), strconv.Quote(account), strconv.Quote(secret))
+	_, code, err := run([]byte(command), "security", "-i")
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return errors.Errorf("security failed storing secret %s of service %s with exit code %d", account, service, code)
+	}
+	return nil
+}
+
+func (macosKeychain) get(run commandRunner, service, account string) (string, bool, error) {
+	out, code, err := run(nil, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	if err != nil {
+		return "", false, err
+	}
+	if code == errSecItemNotFound {
+		return "", false, nil
+	}
+	if code != 0 {
+		return "", false, errors.Errorf("security failed looking up secret %s of service %s with exit code %d", account, service, code)
+	}
+	return string(bytes.TrimSpace(out)), true, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type commandCall struct {
+	stdin string
+	args  []string
+}
+
+type fakeCommand struct {
+	calls    []commandCall
+	stdout   string
+	exitCode int
+	err      error
+}
+
+func (c *fakeCommand) run(stdin []byte, name string, args ...string) ([]byte, int, error) {
+	c.calls = append(c.calls, commandCall{stdin: string(stdin), args: append([]string{name}, args...)})
+	return []byte(c.stdout), c.exitCode, c.err
+}
+
+func TestKeyringSecretStoreSecretService(t *testing.T) {
+	cmd := &fakeCommand{}
+	store := &keyringSecretStore{service: "peer0", tool: &secretService{}, run: cmd.run}
+
+	err := store.Put("abcd_sk", []byte("secret"))
+	require.NoError(t, err)
+	require.Len(t, cmd.calls, 1)
+	assert.Equal(t, []string{"secret-tool", "store", "--label=peer0 abcd_sk", "service", "peer0", "account", "abcd_sk"}, cmd.calls[0].args)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("secret")), cmd.calls[0].stdin)
+
+	cmd.stdout = base64.StdEncoding.EncodeToString([]byte("secret")) + "\n"
+	secret, err := store.Get("abcd_sk")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), secret)
+	assert.Equal(t, []string{"secret-tool", "lookup", "service", "peer0", "account", "abcd_sk"}, cmd.calls[1].args)
+
+	cmd.stdout, cmd.exitCode = "", 1
+	secret, err = store.Get("abcd_sk")
+	assert.NoError(t, err)
+	assert.Nil(t, secret)
+
+	cmd.exitCode = 2
+	_, err = store.Get("abcd_sk")
+	assert.EqualError(t, err, "secret-tool failed looking up secret abcd_sk of service peer0 with exit code 2")
+	err = store.Put("abcd_sk", []byte("secret"))
+	assert.EqualError(t, err, "secret-tool failed storing secret abcd_sk of service peer0 with exit code 2")
+
+	cmd.exitCode, cmd.stdout = 0, "%%%"
+	_, err = store.Get("abcd_sk")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed decoding secret abcd_sk of keyring service peer0")
+
+	cmd.err = errors.New("no dbus session")
+	_, err = store.Get("abcd_sk")
+	assert.EqualError(t, err, "no dbus session")
+}
+
+func TestKeyringSecretStoreMacOSKeychain(t *testing.T) {
+	cmd := &fakeCommand{}
+	store := &keyringSecretStore{service: "peer0", tool: &macosKeychain{}, run: cmd.run}
+
+	err := store.Put("abcd_sk", []byte("secret"))
+	require.NoError(t, err)
+	require.Len(t, cmd.calls, 1)
+	assert.Equal(t, []string{"security", "-i"}, cmd.calls[0].args)
+	assert.True(t, strings.HasPrefix(cmd.calls[0].stdin, `add-generic-password -U -s "peer0" -a "abcd_sk" -w `))
+	assert.Contains(t, cmd.calls[0].stdin, base64.StdEncoding.EncodeToString([]byte("secret")))
+
+	cmd.stdout = base64.StdEncoding.EncodeToString([]byte("secret")) + "\n"
+	secret, err := store.Get("abcd_sk")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), secret)
+	assert.Equal(t, []string{"security", "find-generic-password", "-s", "peer0", "-a", "abcd_sk", "-w"}, cmd.calls[1].args)
+
+	cmd.stdout, cmd.exitCode = "", errSecItemNotFound
+	secret, err = store.Get("abcd_sk")
+	assert.NoError(t, err)
+	assert.Nil(t, secret)
+
+	cmd.exitCode = 51
+	_, err = store.Get("abcd_sk")
+	assert.EqualError(t, err, "security failed looking up secret abcd_sk of service peer0 with exit code 51")
+}
+
+func TestNewKeyringSecretStore(t *testing.T) {
+	_, err := NewKeyringSecretStore("")
+	assert.EqualError(t, err, "invalid keyring service. It must not be empty")
+}
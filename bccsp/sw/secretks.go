@@ -0,0 +1,220 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// A SecretStore keeps named secrets out of the reach of the file system,
+// such as in the keyring of the operating system or sealed by an enclave.
+type SecretStore interface {
+	// Put stores a secret under a name, replacing any secret stored under it.
+	Put(name string, secret []byte) error
+
+	// Get returns the secret stored under a name, or nil when there is none.
+	Get(name string) ([]byte, error)
+}
+
+// NewSecretStoreKeyStore instantiates a key store keeping the keys in a
+// secret store. Each key is stored as a PEM block under a name made of the
+// key's SKI and a flag identifying its type, as the file-based key store names
+// its files. A read only key store forbids any store operation.
+func NewSecretStoreKeyStore(store SecretStore, readOnly bool) (bccsp.KeyStore, error) {
+	if store == nil {
+		return nil, errors.New("invalid secret store. It must be different from nil")
+	}
+	return &secretStoreKeyStore{store: store, readOnly: readOnly}, nil
+}
+
+type secretStoreKeyStore struct {
+	store    SecretStore
+	readOnly bool
+
+	m sync.Mutex
+}
+
+// ReadOnly returns true if this KeyStore is read only, false otherwise.
+func (ks *secretStoreKeyStore) ReadOnly() bool {
+	return ks.readOnly
+}
+
+// GetKey returns a key object whose SKI is the one passed.
+func (ks *secretStoreKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
+	if len(ski) == 0 {
+		return nil, errors.New("invalid SKI. Cannot be of zero length")
+	}
+	alias := hex.EncodeToString(ski)
+
+	ks.m.Lock()
+	defer ks.m.Unlock()
+
+	raw, err := ks.store.Get(alias + "_sk")
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed loading secret key [%x]", ski)
+	}
+	if raw != nil {
+		key, err := pemToPrivateKey(raw, nil)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed parsing secret key [%x]", ski)
+		}
+		switch k := key.(type) {
+		case *ecdsa.PrivateKey:
+			return &ecdsaPrivateKey{k}, nil
+		case *sm2.PrivateKey:
+			return &sm2PrivateKey{k}, nil
+		default:
+			return nil, errors.New("secret key type not recognized")
+		}
+	}
+
+	raw, err = ks.store.Get(alias + "_pk")
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed loading public key [%x]", ski)
+	}
+	if raw != nil {
+		key, err := pemToPublicKey(raw, nil)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed parsing public key [%x]", ski)
+		}
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			return &ecdsaPublicKey{k}, nil
+		case *sm2.PublicKey:
+			return &sm2PublicKey{k}, nil
+		default:
+			return nil, errors.New("public key type not recognized")
+		}
+	}
+
+	raw, err = ks.store.Get(alias + "_key")
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed loading key [%x]", ski)
+	}
+	if raw != nil {
+		key, err := pemToAES(raw, nil)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed parsing key [%x]", ski)
+		}
+		return &aesPrivateKey{key, false}, nil
+	}
+
+	return nil, errors.Errorf("key with SKI %x not found in secret store", ski)
+}
+
+// StoreKey stores the key k in this KeyStore.
+// If this KeyStore is read only then the method will fail.
+func (ks *secretStoreKeyStore) StoreKey(k bccsp.Key) error {
+	if ks.readOnly {
+		return errors.New("read only KeyStore")
+	}
+	if k == nil {
+		return errors.New("invalid key. It must be different from nil")
+	}
+
+	var (
+		suffix string
+		raw    []byte
+		err    error
+	)
+	switch kk := k.(type) {
+	case *ecdsaPrivateKey:
+		suffix = "sk"
+		raw, err = privateKeyToPEM(kk.privKey, nil)
+	case *sm2PrivateKey:
+		suffix = "sk"
+		raw, err = privateKeyToPEM(kk.privKey, nil)
+	case *ecdsaPublicKey:
+		suffix = "pk"
+		raw, err = publicKeyToPEM(kk.pubKey, nil)
+	case *sm2PublicKey:
+		suffix = "pk"
+		raw, err = publicKeyToPEM(kk.pubKey, nil)
+	case *aesPrivateKey:
+		suffix = "key"
+		raw, err = aesToEncryptedPEM(kk.privKey, nil)
+	default:
+		return errors.Errorf("key type not recognized [%s]", k)
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "failed converting key [%x] to PEM", k.SKI())
+	}
+
+	ks.m.Lock()
+	defer ks.m.Unlock()
+
+	if err := ks.store.Put(hex.EncodeToString(k.SKI())+"_"+suffix, raw); err != nil {
+		return errors.WithMessagef(err, "failed storing key [%x]", k.SKI())
+	}
+	return nil
+}
+
+// ImportKeys moves the private keys of the PEM files found in a directory,
+// such as the keystore folder of an MSP, into a key store. Each file is
+// removed once its key can be read back from the key store, so that no key is
+// left on disk. It returns the number of keys imported.
+func ImportKeys(ks bccsp.KeyStore, dir string) (int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed reading directory %s", dir)
+	}
+
+	imported := 0
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), "_sk") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return imported, errors.Wrapf(err, "failed reading %s", path)
+		}
+		key, err := pemToPrivateKey(raw, nil)
+		if err != nil {
+			logger.Warningf("Skipping %s, it does not hold a private key: %s", path, err)
+			continue
+		}
+
+		var k bccsp.Key
+		switch kk := key.(type) {
+		case *ecdsa.PrivateKey:
+			k = &ecdsaPrivateKey{kk}
+		case *sm2.PrivateKey:
+			k = &sm2PrivateKey{kk}
+		default:
+			logger.Warningf("Skipping %s, the type of its private key is not supported", path)
+			continue
+		}
+
+		if err := ks.StoreKey(k); err != nil {
+			return imported, errors.WithMessagef(err, "failed importing %s", path)
+		}
+		if _, err := ks.GetKey(k.SKI()); err != nil {
+			return imported, errors.WithMessagef(err, "failed verifying the import of %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return imported, errors.Wrapf(err, "failed removing %s", path)
+		}
+		logger.Infof("Imported private key [%x] from %s", k.SKI(), path)
+		imported++
+	}
+
+	return imported, nil
+}
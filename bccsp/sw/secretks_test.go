@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapSecretStore struct {
+	secrets map[string][]byte
+	err     error
+}
+
+func (s *mapSecretStore) Put(name string, secret []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.secrets[name] = secret
+	return nil
+}
+
+func (s *mapSecretStore) Get(name string) ([]byte, error) {
+	return s.secrets[name], s.err
+}
+
+func TestSecretStoreKeyStore(t *testing.T) {
+	store := &mapSecretStore{secrets: map[string][]byte{}}
+	ks, err := NewSecretStoreKeyStore(store, false)
+	require.NoError(t, err)
+	assert.False(t, ks.ReadOnly())
+
+	sm2Key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	aesKey, err := GetRandomBytes(32)
+	require.NoError(t, err)
+	// public keys share the SKI of their private key
+	sm2PubKey, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecdsaPubKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	sm2Priv := &sm2PrivateKey{sm2Key}
+	for _, k := range []bccsp.Key{
+		sm2Priv,
+		&sm2PublicKey{&sm2PubKey.PublicKey},
+		&ecdsaPrivateKey{ecdsaKey},
+		&ecdsaPublicKey{&ecdsaPubKey.PublicKey},
+		&aesPrivateKey{aesKey, false},
+	} {
+		err := ks.StoreKey(k)
+		require.NoError(t, err)
+		loaded, err := ks.GetKey(k.SKI())
+		require.NoError(t, err)
+		assert.Equal(t, k.SKI(), loaded.SKI())
+		assert.Equal(t, k.Private(), loaded.Private())
+		assert.Equal(t, k.Symmetric(), loaded.Symmetric())
+	}
+
+	assert.Contains(t, store.secrets, hex.EncodeToString(sm2Priv.SKI())+"_sk")
+	assert.True(t, bytes.HasPrefix(store.secrets[hex.EncodeToString(sm2Priv.SKI())+"_sk"], []byte("-----BEGIN")))
+
+	_, err = ks.GetKey([]byte{1, 2, 3})
+	assert.EqualError(t, err, "key with SKI 010203 not found in secret store")
+	_, err = ks.GetKey(nil)
+	assert.EqualError(t, err, "invalid SKI. Cannot be of zero length")
+	assert.EqualError(t, ks.StoreKey(nil), "invalid key. It must be different from nil")
+	assert.Error(t, ks.StoreKey(&mocks.MockKey{}))
+
+	store.err = errors.New("keyring locked")
+	_, err = ks.GetKey(sm2Priv.SKI())
+	assert.EqualError(t, err, "failed loading secret key ["+hex.EncodeToString(sm2Priv.SKI())+"]: keyring locked")
+	err = ks.StoreKey(sm2Priv)
+	assert.EqualError(t, err, "failed storing key ["+hex.EncodeToString(sm2Priv.SKI())+"]: keyring locked")
+}
+
+func TestSecretStoreKeyStoreReadOnly(t *testing.T) {
+	ks, err := NewSecretStoreKeyStore(&mapSecretStore{secrets: map[string][]byte{}}, true)
+	require.NoError(t, err)
+	assert.True(t, ks.ReadOnly())
+
+	sm2Key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	assert.EqualError(t, ks.StoreKey(&sm2PrivateKey{sm2Key}), "read only KeyStore")
+
+	_, err = NewSecretStoreKeyStore(nil, false)
+	assert.EqualError(t, err, "invalid secret store. It must be different from nil")
+}
+
+func TestImportKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "importkeys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sm2Key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	raw, err := privateKeyToPEM(sm2Key, nil)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "priv_sk"), raw, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "garbage_sk"), []byte("garbage"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cert.pem"), []byte("not a key"), 0600))
+
+	ks, err := NewSecretStoreKeyStore(&mapSecretStore{secrets: map[string][]byte{}}, false)
+	require.NoError(t, err)
+	imported, err := ImportKeys(ks, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, imported)
+
+	k, err := ks.GetKey((&sm2PrivateKey{sm2Key}).SKI())
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.NoFileExists(t, filepath.Join(dir, "priv_sk"))
+	assert.FileExists(t, filepath.Join(dir, "garbage_sk"))
+	assert.FileExists(t, filepath.Join(dir, "cert.pem"))
+
+	imported, err = ImportKeys(ks, filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, imported)
+}
+
+func TestImportKeysStoreFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "importkeys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sm2Key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	raw, err := privateKeyToPEM(sm2Key, nil)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "priv_sk"), raw, 0600))
+
+	ks, err := NewSecretStoreKeyStore(&mapSecretStore{secrets: map[string][]byte{}, err: errors.New("keyring locked")}, false)
+	require.NoError(t, err)
+	_, err = ImportKeys(ks, dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "keyring locked")
+	assert.FileExists(t, filepath.Join(dir, "priv_sk"))
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// defaultSM2UserID is the distinguishing identifier used by the parties of an SM2
+// key agreement which do not provide one.
+var defaultSM2UserID = []byte("1234567812345678")
+
+// sm3KDF is the key derivation function of GB/T 32918.4, expanding z into a key
+// of keyLen bytes.
+func sm3KDF(z []byte, keyLen int) []byte {
+	key := make([]byte, 0, keyLen+sm3.Size)
+	counter := make([]byte, 4)
+	for ct := uint32(1); len(key) < keyLen; ct++ {
+		binary.BigEndian.PutUint32(counter, ct)
+		h := sm3.New()
+		h.Write(z)
+		h.Write(counter)
+		key = h.Sum(key)
+	}
+	return key[:keyLen]
+}
+
+// fieldBytes returns the big endian encoding of a field element over 32 bytes.
+func fieldBytes(v *big.Int) []byte {
+	b := v.Bytes()
+	buf := make([]byte, 32)
+	copy(buf[32-len(b):], b)
+	return buf
+}
+
+// sm2UserHash computes the hash Z of a party of an SM2 key agreement, binding its
+// distinguishing identifier to its public key and the curve parameters.
+func sm2UserHash(pub *sm2.PublicKey, id []byte) ([]byte, error) {
+	if len(id) == 0 {
+		id = defaultSM2UserID
+	}
+	if len(id) >= 8192 {
+		return nil, errors.New("the identifier must be shorter than 8192 bytes")
+	}
+	params := pub.Curve.Params()
+	a := new(big.Int).Sub(params.P, big.NewInt(3))
+
+	h := sm3.New()
+	entl := make([]byte, 2)
+	binary.BigEndian.PutUint16(entl, uint16(len(id)*8))
+	h.Write(entl)
+	h.Write(id)
+	for _, v := range []*big.Int{a, params.B, params.Gx, params.Gy, pub.X, pub.Y} {
+		h.Write(fieldBytes(v))
+	}
+	return h.Sum(nil), nil
+}
+
+// sm2PublicKeyFrom returns the SM2 public key of a public or private SM2 key.
+func sm2PublicKeyFrom(k bccsp.Key) (*sm2.PublicKey, error) {
+	switch key := k.(type) {
+	case *sm2PublicKey:
+		return key.pubKey, nil
+	case *sm2PrivateKey:
+		return &key.privKey.PublicKey, nil
+	default:
+		return nil, errors.Errorf("an SM2 key is expected, got [%T]", k)
+	}
+}
+
+// reducedX computes 2^w + (x & (2^w - 1)) with w = 127, as defined by the SM2 key
+// agreement for a curve of order n with 256 bits.
+func reducedX(x *big.Int) *big.Int {
+	w := uint(127)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), w), big.NewInt(1))
+	reduced := new(big.Int).And(x, mask)
+	return reduced.SetBit(reduced, int(w), 1)
+}
+
+// sm2KeyAgreement derives the key shared with a peer according to the SM2 key
+// agreement of GB/T 32918.3. The optional key confirmation is left to the caller.
+func sm2KeyAgreement(priv *sm2.PrivateKey, opts *bccsp.SM2KeyAgreementOpts) ([]byte, error) {
+	if opts.KeyLen <= 0 {
+		return nil, errors.New("the length of the derived key must be positive")
+	}
+	ephemeral, ok := opts.EphemeralKey.(*sm2PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("the ephemeral key must be an SM2 private key, got [%T]", opts.EphemeralKey)
+	}
+	peerPub, err := sm2PublicKeyFrom(opts.PeerPublicKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid peer public key")
+	}
+	peerEphemeral, err := sm2PublicKeyFrom(opts.PeerEphemeralKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid peer ephemeral key")
+	}
+
+	curve := priv.Curve
+	n := curve.Params().N
+	if !curve.IsOnCurve(peerPub.X, peerPub.Y) {
+		return nil, errors.New("the peer public key is not on the SM2 curve")
+	}
+	if !curve.IsOnCurve(peerEphemeral.X, peerEphemeral.Y) {
+		return nil, errors.New("the peer ephemeral key is not on the SM2 curve")
+	}
+
+	// t = (d + x1' * r) mod n
+	t := new(big.Int).Mul(reducedX(ephemeral.privKey.X), ephemeral.privKey.D)
+	t.Add(t, priv.D)
+	t.Mod(t, n)
+
+	// U = [t](P + [x2']R), the cofactor of the SM2 curve being 1
+	x, y := curve.ScalarMult(peerEphemeral.X, peerEphemeral.Y, reducedX(peerEphemeral.X).Bytes())
+	x, y = curve.Add(peerPub.X, peerPub.Y, x, y)
+	x, y = curve.ScalarMult(x, y, t.Bytes())
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("the key agreement resulted in the point at infinity")
+	}
+
+	z, err := sm2UserHash(&priv.PublicKey, opts.ID)
+	if err != nil {
+		return nil, err
+	}
+	peerZ, err := sm2UserHash(peerPub, opts.PeerID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid peer identifier")
+	}
+	// the hash of the initiator always comes first
+	if !opts.Initiator {
+		z, peerZ = peerZ, z
+	}
+
+	input := append(fieldBytes(x), fieldBytes(y)...)
+	input = append(input, z...)
+	input = append(input, peerZ...)
+	return sm3KDF(input, opts.KeyLen), nil
+}
+
+// sm2ECDH derives the key shared with a peer from the x coordinate of the
+// Diffie-Hellman point over the SM2 curve, expanded with the SM3 key derivation
+// function.
+func sm2ECDH(priv *sm2.PrivateKey, opts *bccsp.SM2ECDHKeyOpts) ([]byte, error) {
+	if opts.KeyLen <= 0 {
+		return nil, errors.New("the length of the derived key must be positive")
+	}
+	peerPub, err := sm2PublicKeyFrom(opts.PeerPublicKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid peer public key")
+	}
+	if !priv.Curve.IsOnCurve(peerPub.X, peerPub.Y) {
+		return nil, errors.New("the peer public key is not on the SM2 curve")
+	}
+
+	x, y := priv.Curve.ScalarMult(peerPub.X, peerPub.Y, priv.D.Bytes())
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("the key agreement resulted in the point at infinity")
+	}
+	return sm3KDF(append(fieldBytes(x), opts.SharedInfo...), opts.KeyLen), nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateSM2Key(t *testing.T, csp bccsp.BCCSP) (bccsp.Key, bccsp.Key) {
+	priv, err := csp.KeyGen(&bccsp.SM2KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	pub, err := priv.PublicKey()
+	require.NoError(t, err)
+	return priv, pub
+}
+
+func TestSM2KeyAgreement(t *testing.T) {
+	t.Parallel()
+
+	csp, err := NewDefaultSecurityLevelWithKeystore(NewDummyKeyStore())
+	require.NoError(t, err)
+
+	staticA, pubA := generateSM2Key(t, csp)
+	ephemeralA, ephemeralPubA := generateSM2Key(t, csp)
+	staticB, pubB := generateSM2Key(t, csp)
+	ephemeralB, ephemeralPubB := generateSM2Key(t, csp)
+
+	keyA, err := csp.KeyDeriv(staticA, &bccsp.SM2KeyAgreementOpts{
+		Temporary:        true,
+		Initiator:        true,
+		EphemeralKey:     ephemeralA,
+		PeerPublicKey:    pubB,
+		PeerEphemeralKey: ephemeralPubB,
+		ID:               []byte("alice@org1"),
+		PeerID:           []byte("bob@org2"),
+		KeyLen:           16,
+	})
+	require.NoError(t, err)
+	keyB, err := csp.KeyDeriv(staticB, &bccsp.SM2KeyAgreementOpts{
+		Temporary:        true,
+		EphemeralKey:     ephemeralB,
+		PeerPublicKey:    pubA,
+		PeerEphemeralKey: ephemeralPubA,
+		ID:               []byte("bob@org2"),
+		PeerID:           []byte("alice@org1"),
+		KeyLen:           16,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, keyA.Symmetric())
+	_, err = keyA.Bytes()
+	assert.Error(t, err, "the shared key must not be exportable")
+	assert.Len(t, keyA.(*aesPrivateKey).privKey, 16)
+	assert.Equal(t, keyA.(*aesPrivateKey).privKey, keyB.(*aesPrivateKey).privKey)
+
+	// Both parties can encrypt with the shared key
+	ciphertext, err := csp.Encrypt(keyA, []byte("hello"), &bccsp.AESCBCPKCS7ModeOpts{})
+	require.NoError(t, err)
+	plaintext, err := csp.Decrypt(keyB, ciphertext, &bccsp.AESCBCPKCS7ModeOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), plaintext)
+
+	// A mismatch of identifiers leads to different keys
+	keyB, err = csp.KeyDeriv(staticB, &bccsp.SM2KeyAgreementOpts{
+		Temporary:        true,
+		EphemeralKey:     ephemeralB,
+		PeerPublicKey:    pubA,
+		PeerEphemeralKey: ephemeralPubA,
+		KeyLen:           16,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, keyA.(*aesPrivateKey).privKey, keyB.(*aesPrivateKey).privKey)
+}
+
+func TestSM2KeyAgreementErrors(t *testing.T) {
+	t.Parallel()
+
+	csp, err := NewDefaultSecurityLevelWithKeystore(NewDummyKeyStore())
+	require.NoError(t, err)
+
+	static, _ := generateSM2Key(t, csp)
+	ephemeral, ephemeralPub := generateSM2Key(t, csp)
+	_, peerPub := generateSM2Key(t, csp)
+
+	_, err = csp.KeyDeriv(static, &bccsp.SM2KeyAgreementOpts{
+		Temporary:        true,
+		EphemeralKey:     ephemeralPub,
+		PeerPublicKey:    peerPub,
+		PeerEphemeralKey: ephemeralPub,
+		KeyLen:           16,
+	})
+	assert.Contains(t, err.Error(), "the ephemeral key must be an SM2 private key")
+
+	_, err = csp.KeyDeriv(static, &bccsp.SM2KeyAgreementOpts{
+		Temporary:        true,
+		EphemeralKey:     ephemeral,
+		PeerPublicKey:    peerPub,
+		PeerEphemeralKey: ephemeralPub,
+	})
+	assert.Contains(t, err.Error(), "the length of the derived key must be positive")
+
+	offCurve := &sm2PublicKey{&sm2.PublicKey{Curve: sm2.P256Sm2(), X: big.NewInt(1), Y: big.NewInt(1)}}
+	_, err = csp.KeyDeriv(static, &bccsp.SM2KeyAgreementOpts{
+		Temporary:        true,
+		EphemeralKey:     ephemeral,
+		PeerPublicKey:    peerPub,
+		PeerEphemeralKey: offCurve,
+		KeyLen:           16,
+	})
+	assert.Contains(t, err.Error(), "the peer ephemeral key is not on the SM2 curve")
+
+	_, err = csp.KeyDeriv(static, &bccsp.SM2ECDHKeyOpts{Temporary: true, PeerPublicKey: &aesPrivateKey{}, KeyLen: 16})
+	assert.Contains(t, err.Error(), "invalid peer public key: an SM2 key is expected")
+}
+
+func TestSM2ECDH(t *testing.T) {
+	t.Parallel()
+
+	csp, err := NewDefaultSecurityLevelWithKeystore(NewDummyKeyStore())
+	require.NoError(t, err)
+
+	privA, pubA := generateSM2Key(t, csp)
+	privB, pubB := generateSM2Key(t, csp)
+
+	keyA, err := csp.KeyDeriv(privA, &bccsp.SM2ECDHKeyOpts{Temporary: true, PeerPublicKey: pubB, SharedInfo: []byte("pvtdata"), KeyLen: 32})
+	require.NoError(t, err)
+	keyB, err := csp.KeyDeriv(privB, &bccsp.SM2ECDHKeyOpts{Temporary: true, PeerPublicKey: pubA, SharedInfo: []byte("pvtdata"), KeyLen: 32})
+	require.NoError(t, err)
+	assert.Len(t, keyA.(*aesPrivateKey).privKey, 32)
+	assert.Equal(t, keyA.(*aesPrivateKey).privKey, keyB.(*aesPrivateKey).privKey)
+
+	keyB, err = csp.KeyDeriv(privB, &bccsp.SM2ECDHKeyOpts{Temporary: true, PeerPublicKey: pubA, KeyLen: 32})
+	require.NoError(t, err)
+	assert.NotEqual(t, keyA.(*aesPrivateKey).privKey, keyB.(*aesPrivateKey).privKey)
+}
+
+func TestSM3KDF(t *testing.T) {
+	t.Parallel()
+
+	z := []byte("shared secret")
+	long := sm3KDF(z, 80)
+	assert.Len(t, long, 80)
+	assert.Equal(t, long[:16], sm3KDF(z, 16))
+	assert.NotEqual(t, long[:16], sm3KDF([]byte("another secret"), 16))
+
+	csp, err := NewDefaultSecurityLevelWithKeystore(NewDummyKeyStore())
+	require.NoError(t, err)
+	k := &aesPrivateKey{privKey: z, exportable: true}
+
+	dk, err := csp.KeyDeriv(k, &bccsp.SM3KDFOpts{Temporary: true, Info: []byte("info"), KeyLen: 16})
+	require.NoError(t, err)
+	assert.Equal(t, sm3KDF([]byte("shared secretinfo"), 16), dk.(*aesPrivateKey).privKey)
+	assert.Equal(t, []byte("shared secret"), k.privKey, "the derivation must not alter the key")
+	_, err = dk.Bytes()
+	assert.Error(t, err)
+
+	_, err = csp.KeyDeriv(k, &bccsp.SM3KDFOpts{Temporary: true})
+	assert.Contains(t, err.Error(), "Invalid key length. It must be positive.")
+}
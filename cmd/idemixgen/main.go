@@ -12,18 +12,18 @@ package main
 // the Identity Mixer MSP
 
 import (
-	"crypto/ecdsa"
-	"crypto/x509"
-	"encoding/pem"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
-	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	idemixcsp "github.com/hyperledger/fabric/bccsp/idemix"
+	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/tools/idemixgen/idemixca"
 	"github.com/hyperledger/fabric/common/tools/idemixgen/metadata"
-	"github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric/core/peer/idemixaudit"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -33,6 +33,7 @@ const (
 	IdemixDirIssuer             = "ca"
 	IdemixConfigIssuerSecretKey = "IssuerSecretKey"
 	IdemixConfigRevocationKey   = "RevocationKey"
+	IdemixConfigAuditEscrowKey  = "AuditEscrowKey"
 )
 
 // command line flags
@@ -42,12 +43,17 @@ var (
 	outputDir = app.Flag("output", "The output directory in which to place artifacts").Default("idemix-config").String()
 
 	genIssuerKey            = app.Command("ca-keygen", "Generate CA key material")
+	genAuditEscrow          = genIssuerKey.Flag("audit-escrow", "Require the signers to escrow their enrollment id for an auditor, whose key is generated").Bool()
 	genSignerConfig         = app.Command("signerconfig", "Generate a default signer for this Idemix MSP")
 	genCAInput              = genSignerConfig.Flag("ca-input", "The folder where CA's secrets are stored").String()
 	genCredOU               = genSignerConfig.Flag("org-unit", "The Organizational Unit of the default signer").Short('u').String()
 	genCredIsAdmin          = genSignerConfig.Flag("admin", "Make the default signer admin").Short('a').Bool()
 	genCredEnrollmentId     = genSignerConfig.Flag("enrollmentId", "The enrollment id of the default signer").Short('e').String()
 	genCredRevocationHandle = genSignerConfig.Flag("revocationHandle", "The handle used to revoke this signer").Short('r').Int()
+	auditLink               = app.Command("audit-link", "Link the transactions of an audit report with the audit escrow key of an MSP")
+	auditLinkReport         = auditLink.Flag("report", "The audit report returned by qscc's AuditIdemixTransactions").Required().String()
+	auditLinkEscrowKey      = auditLink.Flag("escrow-key", "The audit escrow key of the MSP").Required().String()
+	auditLinkMSPID          = auditLink.Flag("msp-id", "The ID of the MSP").Required().String()
 
 	version = app.Command("version", "Show version information")
 )
@@ -58,18 +64,21 @@ func main() {
 	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
 
 	case genIssuerKey.FullCommand():
-		isk, ipk, err := idemixca.GenerateIssuerKey()
+		csp := newCSP()
+		issuerKey, err := idemixca.GenerateIssuerKey(csp)
 		handleError(err)
-
-		revocationKey, err := idemix.GenerateLongTermRevocationKey()
-		handleError(err)
-		encodedRevocationSK, err := x509.MarshalECPrivateKey(revocationKey)
+		isk, ipk, err := idemixca.ExportIssuerKey(issuerKey)
 		handleError(err)
-		pemEncodedRevocationSK := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encodedRevocationSK})
+		var auditEscrowKey []byte
+		if *genAuditEscrow {
+			auditEscrowKey, ipk, err = idemixca.GenerateAuditEscrowKey(ipk)
+			handleError(err)
+		}
+
+		revocationKey, err := idemixca.GenerateRevocationKey(csp)
 		handleError(err)
-		encodedRevocationPK, err := x509.MarshalPKIXPublicKey(revocationKey.Public())
+		pemEncodedRevocationSK, pemEncodedRevocationPK, err := idemixca.ExportRevocationKey(revocationKey)
 		handleError(err)
-		pemEncodedRevocationPK := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedRevocationPK})
 
 		// Prevent overwriting the existing key
 		path := filepath.Join(*outputDir, IdemixDirIssuer)
@@ -84,6 +93,9 @@ func main() {
 		writeFile(filepath.Join(*outputDir, IdemixDirIssuer, IdemixConfigIssuerSecretKey), isk)
 		writeFile(filepath.Join(*outputDir, IdemixDirIssuer, IdemixConfigRevocationKey), pemEncodedRevocationSK)
 		writeFile(filepath.Join(*outputDir, IdemixDirIssuer, msp.IdemixConfigFileIssuerPublicKey), ipk)
+		if auditEscrowKey != nil {
+			writeFile(filepath.Join(*outputDir, IdemixDirIssuer, IdemixConfigAuditEscrowKey), auditEscrowKey)
+		}
 		writeFile(filepath.Join(*outputDir, msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationPublicKey), pemEncodedRevocationPK)
 		writeFile(filepath.Join(*outputDir, msp.IdemixConfigDirMsp, msp.IdemixConfigFileIssuerPublicKey), ipk)
 
@@ -97,11 +109,13 @@ func main() {
 		if *genCAInput == "" {
 			genCAInput = outputDir
 		}
-		ipk, ipkRaw := readIssuerKey()
-		rsk := readRevocationKey()
+		csp := newCSP()
+		ipk, ipkRaw := readIssuerKey(csp)
+		rsk := readRevocationKey(csp)
 		rpk := readRevocationPublicKey()
 
 		config, err := idemixca.GenerateSignerConfig(
+			csp,
 			roleMask,
 			*genCredOU,
 			*genCredEnrollmentId,
@@ -124,6 +138,21 @@ func main() {
 			writeFile(filepath.Join(*outputDir, msp.IdemixConfigDirMsp, msp.IdemixConfigFileIssuerPublicKey), ipkRaw)
 		}
 
+	case auditLink.FullCommand():
+		raw, err := ioutil.ReadFile(*auditLinkReport)
+		if err != nil {
+			handleError(errors.Wrapf(err, "failed to open audit report file: %s", *auditLinkReport))
+		}
+		report := &idemixaudit.Report{}
+		handleError(errors.Wrap(json.Unmarshal(raw, report), "failed to unmarshal audit report"))
+		key, err := idemixaudit.LoadEscrowKey(*auditLinkEscrowKey)
+		handleError(err)
+
+		idemixaudit.Link(report, *auditLinkMSPID, key)
+		linked, err := json.MarshalIndent(report, "", "\t")
+		handleError(err)
+		fmt.Println(string(linked))
+
 	case version.FullCommand():
 		printVersion()
 
@@ -139,8 +168,15 @@ func writeFile(path string, contents []byte) {
 	handleError(ioutil.WriteFile(path, contents, 0640))
 }
 
+// newCSP returns the idemix BCCSP through which the keys are generated and used
+func newCSP() bccsp.BCCSP {
+	csp, err := idemixcsp.NewWithExportableKeys(sw.NewDummyKeyStore())
+	handleError(err)
+	return csp
+}
+
 // readIssuerKey reads the issuer key from the current directory
-func readIssuerKey() (*idemix.IssuerKey, []byte) {
+func readIssuerKey(csp bccsp.BCCSP) (bccsp.Key, []byte) {
 	path := filepath.Join(*genCAInput, IdemixDirIssuer, IdemixConfigIssuerSecretKey)
 	isk, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -151,25 +187,20 @@ func readIssuerKey() (*idemix.IssuerKey, []byte) {
 	if err != nil {
 		handleError(errors.Wrapf(err, "failed to open issuer public key file: %s", path))
 	}
-	ipk := &idemix.IssuerPublicKey{}
-	handleError(proto.Unmarshal(ipkBytes, ipk))
-	key := &idemix.IssuerKey{Isk: isk, Ipk: ipk}
+	key, err := idemixca.ImportIssuerKey(csp, isk, ipkBytes)
+	handleError(err)
 
 	return key, ipkBytes
 }
 
-func readRevocationKey() *ecdsa.PrivateKey {
+func readRevocationKey(csp bccsp.BCCSP) bccsp.Key {
 	path := filepath.Join(*genCAInput, IdemixDirIssuer, IdemixConfigRevocationKey)
 	keyBytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		handleError(errors.Wrapf(err, "failed to open revocation secret key file: %s", path))
 	}
 
-	block, _ := pem.Decode(keyBytes)
-	if block == nil {
-		handleError(errors.Errorf("failed to decode ECDSA private key"))
-	}
-	key, err := x509.ParseECPrivateKey(block.Bytes)
+	key, err := idemixca.ImportRevocationKey(csp, keyBytes)
 	handleError(err)
 
 	return key
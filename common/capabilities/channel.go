@@ -28,6 +28,44 @@ const (
 
 	// ChannelV2_0 is the capabilities string for standard new non-backwards compatible fabric v2.0 channel capabilities.
 	ChannelV2_0 = "V2_0"
+
+	// ChannelWeightedPolicies is the capabilities string for version 1 signature policies, which may
+	// express weighted thresholds. It must only be enabled once every node of the channel understands them.
+	ChannelWeightedPolicies = "V2_2_WEIGHTED_POLICIES"
+
+	// ChannelCommutativeCounters is the capabilities string for the increments of counter keys, which
+	// do not conflict with concurrent increments. It must only be enabled once every peer of the channel
+	// applies them.
+	ChannelCommutativeCounters = "V2_2_COMMUTATIVE_COUNTERS"
+
+	// ChannelSignatureHashMigration is the capabilities string for the acceptance of signatures hashed
+	// with the hash family of the signer's key rather than with the one configured for its MSP, which
+	// lets the identities of a channel migrate from one signature algorithm to the other one by one.
+	ChannelSignatureHashMigration = "V2_2_SIGNATURE_HASH_MIGRATION"
+
+	// ChannelCustomTransactions is the capabilities string for the custom transactions, which are
+	// applied by the processors registered for their types rather than endorsed. It must only be
+	// enabled once every peer of the channel registers the same processors.
+	ChannelCustomTransactions = "V2_2_CUSTOM_TRANSACTIONS"
+
+	// ChannelSM3Hashing is the capabilities string for the SM3 hashing algorithm in the channel config.
+	// It must only be enabled once every node of the channel understands it.
+	ChannelSM3Hashing = "V2_2_SM3_HASHING"
+
+	// ChannelIdemixAuditEscrow is the capabilities string for the idemix issuer public keys requiring
+	// the signatures to escrow an attribute for an auditor, which changes the zero-knowledge proof of
+	// the signatures. It must only be enabled once every node of the channel verifies the escrow.
+	ChannelIdemixAuditEscrow = "V2_2_IDEMIX_AUDIT_ESCROW"
+
+	// ChannelTemplates is the capabilities string for the channel templates of the consortiums, from
+	// which the ordering system channel creates the channels. It must only be enabled once every
+	// orderer node creates the channels from the templates.
+	ChannelTemplates = "V2_2_CHANNEL_TEMPLATES"
+
+	// ChannelChaincodeMetadata is the capabilities string for the operator defined metadata of the
+	// chaincode definitions, which is part of the definitions the peers agree on. It must only be
+	// enabled once every peer of the channel carries the metadata in the definitions.
+	ChannelChaincodeMetadata = "V2_2_CHAINCODE_METADATA"
 )
 
 // ChannelProvider provides capabilities information for channel level config.
@@ -38,6 +76,15 @@ type ChannelProvider struct {
 	v142 bool
 	v143 bool
 	v20  bool
+
+	weightedPolicies       bool
+	commutativeCounters    bool
+	signatureHashMigration bool
+	customTransactions     bool
+	sm3Hashing             bool
+	idemixAuditEscrow      bool
+	channelTemplates       bool
+	chaincodeMetadata      bool
 }
 
 // NewChannelProvider creates a channel capabilities provider.
@@ -49,6 +96,14 @@ func NewChannelProvider(capabilities map[string]*cb.Capability) *ChannelProvider
 	_, cp.v142 = capabilities[ChannelV1_4_2]
 	_, cp.v143 = capabilities[ChannelV1_4_3]
 	_, cp.v20 = capabilities[ChannelV2_0]
+	_, cp.weightedPolicies = capabilities[ChannelWeightedPolicies]
+	_, cp.commutativeCounters = capabilities[ChannelCommutativeCounters]
+	_, cp.signatureHashMigration = capabilities[ChannelSignatureHashMigration]
+	_, cp.customTransactions = capabilities[ChannelCustomTransactions]
+	_, cp.sm3Hashing = capabilities[ChannelSM3Hashing]
+	_, cp.idemixAuditEscrow = capabilities[ChannelIdemixAuditEscrow]
+	_, cp.channelTemplates = capabilities[ChannelTemplates]
+	_, cp.chaincodeMetadata = capabilities[ChannelChaincodeMetadata]
 	return cp
 }
 
@@ -61,6 +116,22 @@ func (cp *ChannelProvider) Type() string {
 func (cp *ChannelProvider) HasCapability(capability string) bool {
 	switch capability {
 	// Add new capability names here
+	case ChannelChaincodeMetadata:
+		return true
+	case ChannelTemplates:
+		return true
+	case ChannelIdemixAuditEscrow:
+		return true
+	case ChannelSM3Hashing:
+		return true
+	case ChannelCustomTransactions:
+		return true
+	case ChannelSignatureHashMigration:
+		return true
+	case ChannelCommutativeCounters:
+		return true
+	case ChannelWeightedPolicies:
+		return true
 	case ChannelV2_0:
 		return true
 	case ChannelV1_4_3:
@@ -79,6 +150,8 @@ func (cp *ChannelProvider) HasCapability(capability string) bool {
 // MSPVersion returns the level of MSP support required by this channel.
 func (cp *ChannelProvider) MSPVersion() msp.MSPVersion {
 	switch {
+	case (cp.v143 || cp.v20) && cp.signatureHashMigration:
+		return msp.MSPv2_2
 	case cp.v143 || cp.v20:
 		return msp.MSPv1_4_3
 	case cp.v13 || cp.v142:
@@ -99,3 +172,43 @@ func (cp *ChannelProvider) ConsensusTypeMigration() bool {
 func (cp *ChannelProvider) OrgSpecificOrdererEndpoints() bool {
 	return cp.v142 || cp.v143 || cp.v20
 }
+
+// WeightedSignaturePolicies returns true if signature policies of version 1, in which a rule
+// repeated within an n-out-of gate counts once per occurrence, are evaluated with weighted semantics.
+func (cp *ChannelProvider) WeightedSignaturePolicies() bool {
+	return cp.weightedPolicies
+}
+
+// CommutativeCounters returns true if the write-sets of transactions may increment counter keys,
+// the increments being added to the committed values of the keys without mvcc conflicts.
+func (cp *ChannelProvider) CommutativeCounters() bool {
+	return cp.commutativeCounters
+}
+
+// CustomTransactions returns true if the transactions of the custom types registered with the
+// peers are valid on the channel.
+func (cp *ChannelProvider) CustomTransactions() bool {
+	return cp.customTransactions
+}
+
+// SM3Hashing returns true if the channel config may set SM3 as the hashing algorithm.
+func (cp *ChannelProvider) SM3Hashing() bool {
+	return cp.sm3Hashing
+}
+
+// IdemixAuditEscrow returns true if the idemix issuer public keys of the channel may require the
+// signatures to escrow an attribute for an auditor.
+func (cp *ChannelProvider) IdemixAuditEscrow() bool {
+	return cp.idemixAuditEscrow
+}
+
+// ChannelTemplates returns true if the consortiums of the ordering system channel may define
+// channel templates, from which the channel creation requests have the channels created.
+func (cp *ChannelProvider) ChannelTemplates() bool {
+	return cp.channelTemplates
+}
+
+// ChaincodeMetadata returns true if the chaincode definitions may carry operator defined metadata.
+func (cp *ChannelProvider) ChaincodeMetadata() bool {
+	return cp.chaincodeMetadata
+}
@@ -98,6 +98,59 @@ func TestChannelV20(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
 	assert.True(t, cp.ConsensusTypeMigration())
 	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.WeightedSignaturePolicies())
+	assert.False(t, cp.CommutativeCounters())
+	assert.False(t, cp.CustomTransactions())
+	assert.False(t, cp.SM3Hashing())
+	assert.False(t, cp.IdemixAuditEscrow())
+	assert.False(t, cp.ChannelTemplates())
+	assert.False(t, cp.ChaincodeMetadata())
+}
+
+func TestChannelWeightedPolicies(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:             {},
+		ChannelWeightedPolicies: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
+	assert.True(t, cp.WeightedSignaturePolicies())
+}
+
+func TestChannelCommutativeCounters(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:                {},
+		ChannelCommutativeCounters: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.CommutativeCounters())
+	assert.False(t, cp.WeightedSignaturePolicies())
+}
+
+func TestChannelSignatureHashMigration(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:                   {},
+		ChannelSignatureHashMigration: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.MSPVersion() == msp.MSPv2_2)
+
+	// It requires the MSP behaviour of v1.4.3
+	cp = NewChannelProvider(map[string]*cb.Capability{
+		ChannelV1_3:                   {},
+		ChannelSignatureHashMigration: {},
+	})
+	assert.True(t, cp.MSPVersion() == msp.MSPv1_3)
+}
+
+func TestChannelCustomTransactions(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:               {},
+		ChannelCustomTransactions: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.CustomTransactions())
+	assert.False(t, cp.CommutativeCounters())
 }
 
 func TestChannelNotSupported(t *testing.T) {
@@ -108,3 +161,43 @@ func TestChannelNotSupported(t *testing.T) {
 	})
 	assert.EqualError(t, cp.Supported(), "Channel capability Bogus_Not_Supported is required but not supported")
 }
+
+func TestChannelSM3Hashing(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:       {},
+		ChannelSM3Hashing: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.SM3Hashing())
+	assert.False(t, cp.CustomTransactions())
+}
+
+func TestChannelIdemixAuditEscrow(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:              {},
+		ChannelIdemixAuditEscrow: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.IdemixAuditEscrow())
+	assert.False(t, cp.SM3Hashing())
+}
+
+func TestChannelTemplates(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:      {},
+		ChannelTemplates: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.ChannelTemplates())
+	assert.False(t, cp.IdemixAuditEscrow())
+}
+
+func TestChannelChaincodeMetadata(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV2_0:              {},
+		ChannelChaincodeMetadata: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.ChaincodeMetadata())
+	assert.False(t, cp.ChannelTemplates())
+}
@@ -21,14 +21,24 @@ const (
 
 	// OrdererV2_0 is the capabilities string that defines new Fabric v2.0 orderer capabilities.
 	OrdererV2_0 = "V2_0"
+
+	// OrdererRaftLearners is the capabilities string for the etcdraft consenters
+	// which replicate a channel as Raft learners, without voting.
+	OrdererRaftLearners = "V2_2_RAFT_LEARNERS"
+
+	// OrdererBlockRetention is the capabilities string for the etcdraft consenters
+	// which prune the blocks of a channel beyond its configured retention.
+	OrdererBlockRetention = "V2_2_BLOCK_RETENTION"
 )
 
 // OrdererProvider provides capabilities information for orderer level config.
 type OrdererProvider struct {
 	*registry
-	v11BugFixes bool
-	v142        bool
-	V20         bool
+	v11BugFixes    bool
+	v142           bool
+	V20            bool
+	raftLearners   bool
+	blockRetention bool
 }
 
 // NewOrdererProvider creates an orderer capabilities provider.
@@ -38,6 +48,8 @@ func NewOrdererProvider(capabilities map[string]*cb.Capability) *OrdererProvider
 	_, cp.v11BugFixes = capabilities[OrdererV1_1]
 	_, cp.v142 = capabilities[OrdererV1_4_2]
 	_, cp.V20 = capabilities[OrdererV2_0]
+	_, cp.raftLearners = capabilities[OrdererRaftLearners]
+	_, cp.blockRetention = capabilities[OrdererBlockRetention]
 	return cp
 }
 
@@ -56,6 +68,10 @@ func (cp *OrdererProvider) HasCapability(capability string) bool {
 		return true
 	case OrdererV2_0:
 		return true
+	case OrdererRaftLearners:
+		return true
+	case OrdererBlockRetention:
+		return true
 	default:
 		return false
 	}
@@ -93,3 +109,18 @@ func (cp *OrdererProvider) ConsensusTypeMigration() bool {
 func (cp *OrdererProvider) UseChannelCreationPolicyAsAdmins() bool {
 	return cp.V20
 }
+
+// RaftLearners specifies whether the etcdraft consensus metadata may list
+// learners, the consenters which replicate the channel without voting. The
+// orderers which don't support this capability would otherwise ignore the
+// learners and count them in the quorum.
+func (cp *OrdererProvider) RaftLearners() bool {
+	return cp.raftLearners
+}
+
+// BlockRetention specifies whether the etcdraft consensus metadata may set a
+// block retention. The orderers which don't support this capability would
+// otherwise keep every block while the others prune them.
+func (cp *OrdererProvider) BlockRetention() bool {
+	return cp.blockRetention
+}
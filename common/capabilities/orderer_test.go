@@ -58,6 +58,26 @@ func TestOrdererV20(t *testing.T) {
 	assert.True(t, op.Resubmission())
 	assert.True(t, op.ExpirationCheck())
 	assert.True(t, op.ConsensusTypeMigration())
+	assert.False(t, op.RaftLearners())
+	assert.False(t, op.BlockRetention())
+}
+
+func TestOrdererRaftLearners(t *testing.T) {
+	op := NewOrdererProvider(map[string]*cb.Capability{
+		OrdererV2_0:         {},
+		OrdererRaftLearners: {},
+	})
+	assert.NoError(t, op.Supported())
+	assert.True(t, op.RaftLearners())
+}
+
+func TestOrdererBlockRetention(t *testing.T) {
+	op := NewOrdererProvider(map[string]*cb.Capability{
+		OrdererV2_0:           {},
+		OrdererBlockRetention: {},
+	})
+	assert.NoError(t, op.Supported())
+	assert.True(t, op.BlockRetention())
 }
 
 func TestNotSupported(t *testing.T) {
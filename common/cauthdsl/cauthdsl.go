@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	mb "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -90,3 +91,65 @@ func compile(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal) (func([]
 		return nil, fmt.Errorf("Unknown type: %T:%v", t, t)
 	}
 }
+
+// compileWeighted is like compile, but it gives n-out-of gates weighted semantics: identical
+// rules of a gate are evaluated only once and, if satisfied, count as many times as they occur.
+// For instance, OutOf(3, 'A.member', 'A.member', 'B.member', 'C.member') is satisfied by a
+// member of A together with a member of either B or C.
+func compileWeighted(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal) (func([]msp.Identity, []bool) bool, error) {
+	if policy == nil {
+		return nil, fmt.Errorf("Empty policy element")
+	}
+
+	t, ok := policy.Type.(*cb.SignaturePolicy_NOutOf_)
+	if !ok {
+		return compile(policy, identities)
+	}
+
+	var rules []*cb.SignaturePolicy
+	var weights []int32
+	for _, rule := range t.NOutOf.Rules {
+		found := false
+		for i := range rules {
+			if proto.Equal(rules[i], rule) {
+				weights[i]++
+				found = true
+				break
+			}
+		}
+		if !found {
+			rules = append(rules, rule)
+			weights = append(weights, 1)
+		}
+	}
+
+	policies := make([]func([]msp.Identity, []bool) bool, len(rules))
+	for i, rule := range rules {
+		compiledPolicy, err := compileWeighted(rule, identities)
+		if err != nil {
+			return nil, err
+		}
+		policies[i] = compiledPolicy
+	}
+	return func(signedData []msp.Identity, used []bool) bool {
+		grepKey := time.Now().UnixNano()
+		cauthdslLogger.Debugf("%p weighted gate %d evaluation starts", signedData, grepKey)
+		verified := int32(0)
+		_used := make([]bool, len(used))
+		for i, policy := range policies {
+			copy(_used, used)
+			if policy(signedData, _used) {
+				verified += weights[i]
+				copy(used, _used)
+			}
+		}
+
+		if verified >= t.NOutOf.N {
+			cauthdslLogger.Debugf("%p weighted gate %d evaluation succeeds", signedData, grepKey)
+		} else {
+			cauthdslLogger.Debugf("%p weighted gate %d evaluation fails", signedData, grepKey)
+		}
+
+		return verified >= t.NOutOf.N
+	}, nil
+}
@@ -149,6 +149,50 @@ func TestComplexNestedSignature(t *testing.T) {
 	}
 }
 
+func TestWeightedSignature(t *testing.T) {
+	// signer0 counts twice: either signer0 alone or signer1 with signer2 satisfy the policy
+	threeSigners := append(signers, []byte("signer2"))
+	policy := policydsl.Envelope(policydsl.NOutOf(2, []*cb.SignaturePolicy{
+		policydsl.SignedBy(0),
+		policydsl.SignedBy(0),
+		policydsl.SignedBy(1),
+		policydsl.SignedBy(2),
+	}), threeSigners)
+
+	spe, err := compileWeighted(policy.Rule, policy.Identities)
+	assert.NoError(t, err)
+	assert.True(t, spe(toIdentities([][]byte{signers[0]}, &mockDeserializer{})))
+	assert.True(t, spe(toIdentities([][]byte{signers[1], []byte("signer2")}, &mockDeserializer{})))
+	assert.False(t, spe(toIdentities([][]byte{signers[1]}, &mockDeserializer{})))
+
+	// without weighted semantics, the repeated rule requires two distinct signatures of signer0
+	spe, err = compile(policy.Rule, policy.Identities)
+	assert.NoError(t, err)
+	assert.False(t, spe(toIdentities([][]byte{signers[0]}, &mockDeserializer{})))
+}
+
+func TestWeightedNestedSignature(t *testing.T) {
+	// 2 out of: signer0 (weight 1), and 1 out of signer1 and signer2 (weight 2)
+	threeSigners := append(signers, []byte("signer2"))
+	sub := policydsl.Or(policydsl.SignedBy(1), policydsl.SignedBy(2))
+	policy := policydsl.Envelope(policydsl.NOutOf(2, []*cb.SignaturePolicy{
+		policydsl.SignedBy(0),
+		sub,
+		sub,
+	}), threeSigners)
+
+	spe, err := compileWeighted(policy.Rule, policy.Identities)
+	assert.NoError(t, err)
+	assert.True(t, spe(toIdentities([][]byte{[]byte("signer2")}, &mockDeserializer{})))
+	assert.False(t, spe(toIdentities([][]byte{signers[0]}, &mockDeserializer{})))
+
+	_, err = compileWeighted(nil, nil)
+	assert.EqualError(t, err, "Empty policy element")
+
+	_, err = compileWeighted(policydsl.NOutOf(1, []*cb.SignaturePolicy{policydsl.SignedBy(3)}), policy.Identities)
+	assert.EqualError(t, err, "identity index out of range, requested 3, but identities length is 3")
+}
+
 func TestNegatively(t *testing.T) {
 	rpolicy := policydsl.Envelope(policydsl.And(policydsl.SignedBy(0), policydsl.SignedBy(1)), signers)
 	rpolicy.Rule.Type = nil
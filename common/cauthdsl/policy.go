@@ -17,8 +17,13 @@ import (
 	"github.com/pkg/errors"
 )
 
+// WeightedPolicyVersion is the version of the signature policy envelopes
+// whose n-out-of gates carry weighted thresholds.
+const WeightedPolicyVersion = 1
+
 type provider struct {
 	deserializer msp.IdentityDeserializer
+	weighted     bool
 }
 
 // NewPolicyProvider provides a policy generator for cauthdsl type policies
@@ -28,6 +33,15 @@ func NewPolicyProvider(deserializer msp.IdentityDeserializer) policies.Provider
 	}
 }
 
+// NewWeightedPolicyProvider provides a policy generator for cauthdsl type policies
+// which, in addition to version 0 policies, understands the weighted policies of version 1
+func NewWeightedPolicyProvider(deserializer msp.IdentityDeserializer) policies.Provider {
+	return &provider{
+		deserializer: deserializer,
+		weighted:     true,
+	}
+}
+
 // NewPolicy creates a new policy based on the policy bytes
 func (pr *provider) NewPolicy(data []byte) (policies.Policy, proto.Message, error) {
 	sigPolicy := &cb.SignaturePolicyEnvelope{}
@@ -35,11 +49,16 @@ func (pr *provider) NewPolicy(data []byte) (policies.Policy, proto.Message, erro
 		return nil, nil, fmt.Errorf("Error unmarshaling to SignaturePolicy: %s", err)
 	}
 
-	if sigPolicy.Version != 0 {
+	switch {
+	case sigPolicy.Version == 0:
+	case sigPolicy.Version == WeightedPolicyVersion && pr.weighted:
+	case pr.weighted:
+		return nil, nil, fmt.Errorf("This evaluator only understands messages of version 0 and %d, but version was %d", WeightedPolicyVersion, sigPolicy.Version)
+	default:
 		return nil, nil, fmt.Errorf("This evaluator only understands messages of version 0, but version was %d", sigPolicy.Version)
 	}
 
-	compiled, err := compile(sigPolicy.Rule, sigPolicy.Identities)
+	compiled, err := compileEnvelope(sigPolicy, pr.weighted)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -55,6 +74,9 @@ func (pr *provider) NewPolicy(data []byte) (policies.Policy, proto.Message, erro
 // EnvelopeBasedPolicyProvider allows to create a new policy from SignaturePolicyEnvelope struct instead of []byte
 type EnvelopeBasedPolicyProvider struct {
 	Deserializer msp.IdentityDeserializer
+	// Weighted enables the weighted semantics of version 1 policies. When it is not set,
+	// version 1 policies are evaluated like version 0 ones, as prior releases did.
+	Weighted bool
 }
 
 // NewPolicy creates a new policy from the policy envelope
@@ -63,7 +85,7 @@ func (pp *EnvelopeBasedPolicyProvider) NewPolicy(sigPolicy *cb.SignaturePolicyEn
 		return nil, errors.New("invalid arguments")
 	}
 
-	compiled, err := compile(sigPolicy.Rule, sigPolicy.Identities)
+	compiled, err := compileEnvelope(sigPolicy, pp.Weighted)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +97,15 @@ func (pp *EnvelopeBasedPolicyProvider) NewPolicy(sigPolicy *cb.SignaturePolicyEn
 	}, nil
 }
 
+// compileEnvelope compiles the rule of the envelope, with weighted semantics
+// if the envelope is of version 1 and weighted policies are enabled
+func compileEnvelope(sigPolicy *cb.SignaturePolicyEnvelope, weighted bool) (func([]msp.Identity, []bool) bool, error) {
+	if weighted && sigPolicy.Version == WeightedPolicyVersion {
+		return compileWeighted(sigPolicy.Rule, sigPolicy.Identities)
+	}
+	return compile(sigPolicy.Rule, sigPolicy.Identities)
+}
+
 type policy struct {
 	signaturePolicyEnvelope *cb.SignaturePolicyEnvelope
 	evaluator               func([]msp.Identity, []bool) bool
@@ -135,6 +135,41 @@ func TestEnvelopeBasedPolicyProvider(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestWeightedPolicyProvider(t *testing.T) {
+	weighted := policydsl.Envelope(policydsl.NOutOf(2, []*cb.SignaturePolicy{
+		policydsl.SignedBy(0),
+		policydsl.SignedBy(0),
+		policydsl.SignedBy(1),
+	}), signers)
+	weighted.Version = WeightedPolicyVersion
+	signedData := []*protoutil.SignedData{{Identity: signers[0], Data: []byte("data"), Signature: []byte("sig")}}
+
+	_, _, err := NewPolicyProvider(&mockDeserializer{}).NewPolicy(marshalOrPanic(weighted))
+	assert.EqualError(t, err, "This evaluator only understands messages of version 0, but version was 1")
+
+	p, _, err := NewWeightedPolicyProvider(&mockDeserializer{}).NewPolicy(marshalOrPanic(weighted))
+	assert.NoError(t, err)
+	assert.NoError(t, p.EvaluateSignedData(signedData))
+
+	_, _, err = NewWeightedPolicyProvider(&mockDeserializer{}).NewPolicy(marshalOrPanic(&cb.SignaturePolicyEnvelope{Version: 2}))
+	assert.EqualError(t, err, "This evaluator only understands messages of version 0 and 1, but version was 2")
+
+	// a version 0 policy with a repeated rule keeps requiring distinct signatures
+	weighted.Version = 0
+	p, _, err = NewWeightedPolicyProvider(&mockDeserializer{}).NewPolicy(marshalOrPanic(weighted))
+	assert.NoError(t, err)
+	assert.EqualError(t, p.EvaluateSignedData(signedData), "signature set did not satisfy policy")
+
+	weighted.Version = WeightedPolicyVersion
+	ep, err := (&EnvelopeBasedPolicyProvider{Deserializer: &mockDeserializer{}}).NewPolicy(weighted)
+	assert.NoError(t, err)
+	assert.EqualError(t, ep.EvaluateSignedData(signedData), "signature set did not satisfy policy")
+
+	ep, err = (&EnvelopeBasedPolicyProvider{Deserializer: &mockDeserializer{}, Weighted: true}).NewPolicy(weighted)
+	assert.NoError(t, err)
+	assert.NoError(t, ep.EvaluateSignedData(signedData))
+}
+
 func TestConverter(t *testing.T) {
 	p := policy{}
 
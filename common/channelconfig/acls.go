@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package channelconfig
 
 import (
+	"sort"
+	"strings"
+
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 )
 
@@ -19,6 +22,17 @@ func (ag *aclsProvider) PolicyRefForAPI(aclName string) string {
 	return ag.aclPolicyRefs[aclName]
 }
 
+func (ag *aclsProvider) APIsWithPrefix(prefix string) []string {
+	var apis []string
+	for aclName := range ag.aclPolicyRefs {
+		if strings.HasPrefix(aclName, prefix) {
+			apis = append(apis, aclName)
+		}
+	}
+	sort.Strings(apis)
+	return apis
+}
+
 // this translates policies to absolute paths if needed
 func newAPIsProvider(acls map[string]*pb.APIResource) *aclsProvider {
 	aclPolicyRefs := make(map[string]string)
@@ -40,6 +40,18 @@ func TestGreenAPIsPath(t *testing.T) {
 	})
 }
 
+func TestAPIsWithPrefix(t *testing.T) {
+	ag := newAPIsProvider(map[string]*pb.APIResource{
+		"event/ChaincodeEvents/mycc":    {PolicyRef: "Readers"},
+		"event/ChaincodeEvents/assets":  {PolicyRef: "Admins"},
+		"event/FilteredBlock":           {PolicyRef: "Readers"},
+		"event/ChaincodeEvents/unsetcc": {PolicyRef: ""},
+	})
+
+	assert.Equal(t, []string{"event/ChaincodeEvents/assets", "event/ChaincodeEvents/mycc"}, ag.APIsWithPrefix("event/ChaincodeEvents/"))
+	assert.Empty(t, ag.APIsWithPrefix("missing/"))
+}
+
 func TestNilACLs(t *testing.T) {
 	ccg := newAPIsProvider(nil)
 
@@ -87,6 +87,10 @@ type Consortium interface {
 
 	// Organizations returns the organizations for this consortium
 	Organizations() map[string]Org
+
+	// ChannelTemplates returns the application group templates, by name, which
+	// channel creation requests for this consortium may reference
+	ChannelTemplates() map[string]*cb.ConfigGroup
 }
 
 // Orderer stores the common shared orderer config
@@ -135,6 +139,32 @@ type ChannelCapabilities interface {
 
 	// OrgSpecificOrdererEndpoints return true if the channel config processing allows orderer orgs to specify their own endpoints
 	OrgSpecificOrdererEndpoints() bool
+
+	// WeightedSignaturePolicies returns true if version 1 signature policies, which may express
+	// weighted thresholds, are evaluated with weighted semantics.
+	WeightedSignaturePolicies() bool
+
+	// CommutativeCounters returns true if the write-sets of transactions may increment counter keys
+	// without mvcc conflicts.
+	CommutativeCounters() bool
+
+	// CustomTransactions returns true if the transactions of the custom types registered with the
+	// peers are valid on the channel.
+	CustomTransactions() bool
+
+	// SM3Hashing returns true if the channel config may set SM3 as the hashing algorithm.
+	SM3Hashing() bool
+
+	// IdemixAuditEscrow returns true if the idemix issuer public keys of the channel may require the
+	// signatures to escrow an attribute for an auditor.
+	IdemixAuditEscrow() bool
+
+	// ChannelTemplates returns true if the consortiums of the ordering system channel may define
+	// channel templates.
+	ChannelTemplates() bool
+
+	// ChaincodeMetadata returns true if the chaincode definitions may carry operator defined metadata.
+	ChaincodeMetadata() bool
 }
 
 // ApplicationCapabilities defines the capabilities for the application portion of a channel
@@ -220,6 +250,12 @@ type OrdererCapabilities interface {
 	// channel creation logic using channel creation policy as the Admins policy if
 	// the creation transaction appears to support it.
 	UseChannelCreationPolicyAsAdmins() bool
+
+	// RaftLearners specifies whether the etcdraft consensus metadata may list learners.
+	RaftLearners() bool
+
+	// BlockRetention specifies whether the etcdraft consensus metadata may set a block retention.
+	BlockRetention() bool
 }
 
 // PolicyMapper is an interface for
@@ -227,6 +263,10 @@ type PolicyMapper interface {
 	// PolicyRefForAPI takes the name of an API, and returns the policy name
 	// or the empty string if the API is not found
 	PolicyRefForAPI(apiName string) string
+
+	// APIsWithPrefix returns the sorted names of the APIs with a policy
+	// starting with the given prefix
+	APIsWithPrefix(prefix string) []string
 }
 
 // Resources is the common set of config resources for all channels
@@ -196,7 +196,11 @@ func NewBundle(channelID string, config *cb.Config, bccsp bccsp.BCCSP) (*Bundle,
 		case cb.Policy_UNKNOWN:
 			// Do not register a handler
 		case cb.Policy_SIGNATURE:
-			policyProviderMap[pType] = cauthdsl.NewPolicyProvider(channelConfig.MSPManager())
+			if channelConfig.Capabilities().WeightedSignaturePolicies() {
+				policyProviderMap[pType] = cauthdsl.NewWeightedPolicyProvider(channelConfig.MSPManager())
+			} else {
+				policyProviderMap[pType] = cauthdsl.NewPolicyProvider(channelConfig.MSPManager())
+			}
 		case cb.Policy_MSP:
 			// Add hook for MSP Handler here
 		}
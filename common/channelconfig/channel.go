@@ -93,6 +93,7 @@ func NewChannelConfig(channelGroup *cb.ConfigGroup, bccsp bccsp.BCCSP) (*Channel
 	}
 
 	mspConfigHandler := NewMSPConfigHandler(capabilities.MSPVersion(), bccsp)
+	mspConfigHandler.idemixAuditEscrow = capabilities.IdemixAuditEscrow()
 
 	var err error
 	for groupName, group := range channelGroup.Groups {
@@ -102,7 +103,7 @@ func NewChannelConfig(channelGroup *cb.ConfigGroup, bccsp bccsp.BCCSP) (*Channel
 		case OrdererGroupKey:
 			cc.ordererConfig, err = NewOrdererConfig(group, mspConfigHandler, capabilities)
 		case ConsortiumsGroupKey:
-			cc.consortiumsConfig, err = NewConsortiumsConfig(group, mspConfigHandler)
+			cc.consortiumsConfig, err = NewConsortiumsConfig(group, mspConfigHandler, capabilities)
 		default:
 			return nil, fmt.Errorf("Disallowed channel group: %s", group)
 		}
@@ -168,13 +169,11 @@ func (cc *ChannelConfig) Capabilities() ChannelCapabilities {
 
 // Validate inspects the generated configuration protos and ensures that the values are correct
 func (cc *ChannelConfig) Validate(channelCapabilities ChannelCapabilities) error {
-	for _, validator := range []func() error{
-		cc.validateHashingAlgorithm,
-		cc.validateBlockDataHashingStructure,
-	} {
-		if err := validator(); err != nil {
-			return err
-		}
+	if err := cc.validateHashingAlgorithm(channelCapabilities); err != nil {
+		return err
+	}
+	if err := cc.validateBlockDataHashingStructure(); err != nil {
+		return err
 	}
 
 	if !channelCapabilities.OrgSpecificOrdererEndpoints() {
@@ -184,12 +183,18 @@ func (cc *ChannelConfig) Validate(channelCapabilities ChannelCapabilities) error
 	return nil
 }
 
-func (cc *ChannelConfig) validateHashingAlgorithm() error {
+func (cc *ChannelConfig) validateHashingAlgorithm(channelCapabilities ChannelCapabilities) error {
 	switch cc.protos.HashingAlgorithm.Name {
 	case bccsp.SHA256:
 		cc.hashingAlgorithm = util.ComputeSHA256
 	case bccsp.SHA3_256:
 		cc.hashingAlgorithm = util.ComputeSHA3256
+	case bccsp.SM3:
+		// The nodes without the capability reject the config
+		if !channelCapabilities.SM3Hashing() {
+			return fmt.Errorf("Hashing algorithm %s requires the %s capability", bccsp.SM3, capabilities.ChannelSM3Hashing)
+		}
+		cc.hashingAlgorithm = util.ComputeSM3
 	default:
 		return fmt.Errorf("Unknown hashing algorithm type: %s", cc.protos.HashingAlgorithm.Name)
 	}
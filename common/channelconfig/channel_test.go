@@ -14,6 +14,7 @@ import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,23 +36,33 @@ func TestChannelConfig(t *testing.T) {
 }
 
 func TestHashingAlgorithm(t *testing.T) {
+	v20 := capabilities.NewChannelProvider(map[string]*cb.Capability{capabilities.ChannelV2_0: {}})
 	cc := &ChannelConfig{protos: &ChannelProtos{HashingAlgorithm: &cb.HashingAlgorithm{}}}
-	assert.Error(t, cc.validateHashingAlgorithm(), "Must supply hashing algorithm")
+	assert.Error(t, cc.validateHashingAlgorithm(v20), "Must supply hashing algorithm")
 
 	cc = &ChannelConfig{protos: &ChannelProtos{HashingAlgorithm: &cb.HashingAlgorithm{Name: "MD5"}}}
-	assert.Error(t, cc.validateHashingAlgorithm(), "Bad hashing algorithm supplied")
+	assert.Error(t, cc.validateHashingAlgorithm(v20), "Bad hashing algorithm supplied")
 
 	cc = &ChannelConfig{protos: &ChannelProtos{HashingAlgorithm: &cb.HashingAlgorithm{Name: bccsp.SHA256}}}
-	assert.NoError(t, cc.validateHashingAlgorithm(), "Allowed hashing algorith SHA256 supplied")
+	assert.NoError(t, cc.validateHashingAlgorithm(v20), "Allowed hashing algorith SHA256 supplied")
 
 	assert.Equal(t, reflect.ValueOf(util.ComputeSHA256).Pointer(), reflect.ValueOf(cc.HashingAlgorithm()).Pointer(),
 		"Unexpected hashing algorithm returned")
 
 	cc = &ChannelConfig{protos: &ChannelProtos{HashingAlgorithm: &cb.HashingAlgorithm{Name: bccsp.SHA3_256}}}
-	assert.NoError(t, cc.validateHashingAlgorithm(), "Allowed hashing algorith SHA3_256 supplied")
+	assert.NoError(t, cc.validateHashingAlgorithm(v20), "Allowed hashing algorith SHA3_256 supplied")
 
 	assert.Equal(t, reflect.ValueOf(util.ComputeSHA3256).Pointer(), reflect.ValueOf(cc.HashingAlgorithm()).Pointer(),
 		"Unexpected hashing algorithm returned")
+
+	cc = &ChannelConfig{protos: &ChannelProtos{HashingAlgorithm: &cb.HashingAlgorithm{Name: bccsp.SM3}}}
+	assert.EqualError(t, cc.validateHashingAlgorithm(v20), "Hashing algorithm SM3 requires the V2_2_SM3_HASHING capability")
+
+	sm3Hashing := capabilities.NewChannelProvider(map[string]*cb.Capability{capabilities.ChannelV2_0: {}, capabilities.ChannelSM3Hashing: {}})
+	assert.NoError(t, cc.validateHashingAlgorithm(sm3Hashing), "Allowed hashing algorith SM3 supplied")
+
+	assert.Equal(t, reflect.ValueOf(util.ComputeSM3).Pointer(), reflect.ValueOf(cc.HashingAlgorithm()).Pointer(),
+		"Unexpected hashing algorithm returned")
 }
 
 func TestBlockDataHashingStructure(t *testing.T) {
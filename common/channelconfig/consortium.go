@@ -8,6 +8,7 @@ package channelconfig
 
 import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/pkg/errors"
 )
 
@@ -15,11 +16,21 @@ const (
 	// ChannelCreationPolicyKey is the key used in the consortium config to denote the policy
 	// to be used in evaluating whether a channel creation request is authorized
 	ChannelCreationPolicyKey = "ChannelCreationPolicy"
+
+	// ChannelTemplatesKey is the key used in the consortium config to denote the named
+	// templates of application groups which channel creation requests may reference,
+	// it requires the V2_2_CHANNEL_TEMPLATES channel capability
+	ChannelTemplatesKey = "ChannelTemplates"
+
+	// ChannelTemplateIsolatedDataKey is the key of the isolated data of a channel creation
+	// config update which holds the name of the channel template to create the channel from
+	ChannelTemplateIsolatedDataKey = "channel_template"
 )
 
 // ConsortiumProtos holds the config protos for the consortium config
 type ConsortiumProtos struct {
 	ChannelCreationPolicy *cb.Policy
+	ChannelTemplates      *cb.ConfigGroup
 }
 
 // ConsortiumConfig holds the consortium's configuration information
@@ -29,7 +40,13 @@ type ConsortiumConfig struct {
 }
 
 // NewConsortiumConfig creates a new instance of the consortium's config
-func NewConsortiumConfig(consortiumGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler) (*ConsortiumConfig, error) {
+func NewConsortiumConfig(consortiumName string, consortiumGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler, channelCapabilities ChannelCapabilities) (*ConsortiumConfig, error) {
+	if !channelCapabilities.ChannelTemplates() {
+		if _, ok := consortiumGroup.Values[ChannelTemplatesKey]; ok {
+			return nil, errors.Errorf("Consortium %s cannot contain channel templates until the %s capability has been enabled", consortiumName, capabilities.ChannelTemplates)
+		}
+	}
+
 	cc := &ConsortiumConfig{
 		protos: &ConsortiumProtos{},
 		orgs:   make(map[string]Org),
@@ -46,6 +63,10 @@ func NewConsortiumConfig(consortiumGroup *cb.ConfigGroup, mspConfig *MSPConfigHa
 		}
 	}
 
+	if err := cc.validateChannelTemplates(); err != nil {
+		return nil, err
+	}
+
 	return cc, nil
 }
 
@@ -59,3 +80,28 @@ func (cc *ConsortiumConfig) Organizations() map[string]Org {
 func (cc *ConsortiumConfig) ChannelCreationPolicy() *cb.Policy {
 	return cc.protos.ChannelCreationPolicy
 }
+
+// ChannelTemplates returns the application group templates, by name, which
+// channel creation requests for this consortium may reference
+func (cc *ConsortiumConfig) ChannelTemplates() map[string]*cb.ConfigGroup {
+	return cc.protos.ChannelTemplates.Groups
+}
+
+func (cc *ConsortiumConfig) validateChannelTemplates() error {
+	for templateName, template := range cc.protos.ChannelTemplates.Groups {
+		if template == nil {
+			return errors.Errorf("channel template %s is empty", templateName)
+		}
+		// the orderer adds the creation policy of the consortium to the application
+		// group of the channels created from the template
+		if _, ok := template.Policies[ChannelCreationPolicyKey]; ok {
+			return errors.Errorf("channel template %s cannot define the %s policy", templateName, ChannelCreationPolicyKey)
+		}
+		for orgName := range template.Groups {
+			if _, ok := cc.orgs[orgName]; !ok {
+				return errors.Errorf("channel template %s references org %s which is not a member of the consortium", templateName, orgName)
+			}
+		}
+	}
+	return nil
+}
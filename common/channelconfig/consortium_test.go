@@ -10,14 +10,16 @@ import (
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestConsortiumConfig(t *testing.T) {
 	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
 	assert.NoError(t, err)
-	cc, err := NewConsortiumConfig(&cb.ConfigGroup{}, NewMSPConfigHandler(msp.MSPv1_0, cryptoProvider))
+	cc, err := NewConsortiumConfig("SampleConsortium", &cb.ConfigGroup{}, NewMSPConfigHandler(msp.MSPv1_0, cryptoProvider), capabilities.NewChannelProvider(nil))
 	assert.NoError(t, err)
 	orgs := cc.Organizations()
 	assert.Equal(t, 0, len(orgs))
@@ -25,3 +27,36 @@ func TestConsortiumConfig(t *testing.T) {
 	policy := cc.ChannelCreationPolicy()
 	assert.EqualValues(t, cb.Policy_UNKNOWN, policy.Type, "Expected policy type to be UNKNOWN")
 }
+
+func TestConsortiumChannelTemplates(t *testing.T) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	channelCapabilities := capabilities.NewChannelProvider(map[string]*cb.Capability{capabilities.ChannelTemplates: {}})
+
+	templates := map[string]*cb.ConfigGroup{
+		"gold": {Policies: map[string]*cb.ConfigPolicy{AdminsPolicyKey: {}}},
+	}
+	consortiumGroup := &cb.ConfigGroup{
+		Values: map[string]*cb.ConfigValue{
+			ChannelTemplatesKey: {Value: protoutil.MarshalOrPanic(ChannelTemplatesValue(templates).Value())},
+		},
+	}
+	cc, err := NewConsortiumConfig("SampleConsortium", consortiumGroup, NewMSPConfigHandler(msp.MSPv1_0, cryptoProvider), channelCapabilities)
+	assert.NoError(t, err)
+	assert.Len(t, cc.ChannelTemplates(), 1)
+	assert.Contains(t, cc.ChannelTemplates(), "gold")
+
+	_, err = NewConsortiumConfig("SampleConsortium", consortiumGroup, NewMSPConfigHandler(msp.MSPv1_0, cryptoProvider), capabilities.NewChannelProvider(nil))
+	assert.EqualError(t, err, "Consortium SampleConsortium cannot contain channel templates until the V2_2_CHANNEL_TEMPLATES capability has been enabled")
+
+	templates["gold"].Groups = map[string]*cb.ConfigGroup{"Org1": {}}
+	consortiumGroup.Values[ChannelTemplatesKey].Value = protoutil.MarshalOrPanic(ChannelTemplatesValue(templates).Value())
+	_, err = NewConsortiumConfig("SampleConsortium", consortiumGroup, NewMSPConfigHandler(msp.MSPv1_0, cryptoProvider), channelCapabilities)
+	assert.EqualError(t, err, "channel template gold references org Org1 which is not a member of the consortium")
+
+	templates["gold"].Groups = nil
+	templates["gold"].Policies[ChannelCreationPolicyKey] = &cb.ConfigPolicy{}
+	consortiumGroup.Values[ChannelTemplatesKey].Value = protoutil.MarshalOrPanic(ChannelTemplatesValue(templates).Value())
+	_, err = NewConsortiumConfig("SampleConsortium", consortiumGroup, NewMSPConfigHandler(msp.MSPv1_0, cryptoProvider), channelCapabilities)
+	assert.EqualError(t, err, "channel template gold cannot define the ChannelCreationPolicy policy")
+}
@@ -21,14 +21,14 @@ type ConsortiumsConfig struct {
 }
 
 // NewConsortiumsConfig creates a new instance of the consoritums config
-func NewConsortiumsConfig(consortiumsGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler) (*ConsortiumsConfig, error) {
+func NewConsortiumsConfig(consortiumsGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler, channelCapabilities ChannelCapabilities) (*ConsortiumsConfig, error) {
 	cc := &ConsortiumsConfig{
 		consortiums: make(map[string]Consortium),
 	}
 
 	for consortiumName, consortiumGroup := range consortiumsGroup.Groups {
 		var err error
-		if cc.consortiums[consortiumName], err = NewConsortiumConfig(consortiumGroup, mspConfig); err != nil {
+		if cc.consortiums[consortiumName], err = NewConsortiumConfig(consortiumName, consortiumGroup, mspConfig, channelCapabilities); err != nil {
 			return nil, err
 		}
 	}
@@ -9,10 +9,11 @@ import (
 	"testing"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestConsortiums(t *testing.T) {
-	_, err := NewConsortiumsConfig(&cb.ConfigGroup{}, nil)
+	_, err := NewConsortiumsConfig(&cb.ConfigGroup{}, nil, capabilities.NewChannelProvider(nil))
 	assert.NoError(t, err)
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/idemix"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/cache"
 	"github.com/pkg/errors"
@@ -27,6 +29,8 @@ type MSPConfigHandler struct {
 	version msp.MSPVersion
 	idMap   map[string]*pendingMSPConfig
 	bccsp   bccsp.BCCSP
+	// idemixAuditEscrow allows the idemix issuer public keys to require an audit escrow
+	idemixAuditEscrow bool
 }
 
 func NewMSPConfigHandler(mspVersion msp.MSPVersion, bccsp bccsp.BCCSP) *MSPConfigHandler {
@@ -59,6 +63,11 @@ func (bh *MSPConfigHandler) ProposeMSP(mspConfig *mspprotos.MSPConfig) (msp.MSP,
 			return nil, errors.WithMessage(err, "creating the MSP cache failed")
 		}
 	case int32(msp.IDEMIX):
+		if !bh.idemixAuditEscrow {
+			if err := checkNoAuditEscrow(mspConfig); err != nil {
+				return nil, err
+			}
+		}
 		// create the idemix msp instance
 		theMsp, err = msp.New(
 			&msp.IdemixNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: bh.version}},
@@ -107,3 +116,21 @@ func (bh *MSPConfigHandler) CreateMSPManager() (msp.MSPManager, error) {
 	err := manager.Setup(mspList)
 	return manager, err
 }
+
+// checkNoAuditEscrow returns an error if the issuer public key of the idemix
+// MSP config requires the signatures to escrow an attribute for an auditor,
+// which the nodes predating the audit escrow would not verify.
+func checkNoAuditEscrow(mspConfig *mspprotos.MSPConfig) error {
+	conf := &mspprotos.IdemixMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, conf); err != nil {
+		return errors.Wrap(err, "failed unmarshalling idemix msp config")
+	}
+	ipk := &idemix.IssuerPublicKey{}
+	if err := proto.Unmarshal(conf.Ipk, ipk); err != nil {
+		return errors.Wrap(err, "failed unmarshalling issuer public key of idemix msp config")
+	}
+	if ipk.AuditEscrowPk != nil || ipk.AuditEscrowAttribute != "" {
+		return errors.Errorf("idemix MSP %s requires an audit escrow, which requires the %s capability", conf.Name, capabilities.ChannelIdemixAuditEscrow)
+	}
+	return nil
+}
@@ -9,11 +9,14 @@ package channelconfig
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/idemix"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,3 +68,33 @@ func TestMSPConfigFailure(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestMSPConfigIdemixAuditEscrow(t *testing.T) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	conf, err := msp.GetIdemixMspConfig("../../msp/testdata/idemix/MSP1OU1", "MSP1OU1")
+	assert.NoError(t, err)
+
+	idemixConf := &mspprotos.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConf))
+	ipk := &idemix.IssuerPublicKey{}
+	assert.NoError(t, proto.Unmarshal(idemixConf.Ipk, ipk))
+	rng, err := idemix.GetRand()
+	assert.NoError(t, err)
+	escrowKey, err := idemix.NewAuditEscrowKey(rng)
+	assert.NoError(t, err)
+	assert.NoError(t, ipk.SetAuditEscrow(escrowKey.Pk, msp.AttributeNameEnrollmentId))
+	idemixConf.Ipk = protoutil.MarshalOrPanic(ipk)
+	escrowConf := &mspprotos.MSPConfig{Type: conf.Type, Config: protoutil.MarshalOrPanic(idemixConf)}
+
+	mspCH := NewMSPConfigHandler(msp.MSPv1_4_3, cryptoProvider)
+	_, err = mspCH.ProposeMSP(conf)
+	assert.NoError(t, err)
+	_, err = mspCH.ProposeMSP(escrowConf)
+	assert.EqualError(t, err, "idemix MSP MSP1OU1 requires an audit escrow, which requires the V2_2_IDEMIX_AUDIT_ESCROW capability")
+
+	mspCH = NewMSPConfigHandler(msp.MSPv1_4_3, cryptoProvider)
+	mspCH.idemixAuditEscrow = true
+	_, err = mspCH.ProposeMSP(escrowConf)
+	assert.NoError(t, err)
+}
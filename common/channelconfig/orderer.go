@@ -8,6 +8,7 @@ package channelconfig
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -243,20 +244,19 @@ func (oc *OrdererConfig) validateKafkaBrokers() error {
 
 // This does just a barebones sanity check.
 func brokerEntrySeemsValid(broker string) bool {
-	if !strings.Contains(broker, ":") {
+	host, port, err := net.SplitHostPort(broker)
+	if err != nil {
 		return false
 	}
 
-	parts := strings.Split(broker, ":")
-	if len(parts) > 2 {
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
 		return false
 	}
 
-	host := parts[0]
-	port := parts[1]
-
-	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
-		return false
+	// IPv6 addresses are enclosed in square brackets, e.g., [2001:db8::1]:7050
+	if strings.HasPrefix(broker, "[") {
+		ip := net.ParseIP(host)
+		return ip != nil && ip.To4() == nil
 	}
 
 	// Valid hostnames may contain only the ASCII letters 'a' through 'z' (in a
@@ -43,9 +43,14 @@ func TestBatchTimeout(t *testing.T) {
 }
 
 func TestKafkaBrokers(t *testing.T) {
-	oc := &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1:9092", "foo.bar:9092"}}}}
+	oc := &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1:9092", "foo.bar:9092", "[2001:db8::1]:9092", "[::1]:9092"}}}}
 	assert.NoError(t, oc.validateKafkaBrokers(), "Valid kafka brokers")
 
 	oc = &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1", "foo.bar", "127.0.0.1:-1", "localhost:65536", "foo.bar.:9092", ".127.0.0.1:9092", "-foo.bar:9092"}}}}
 	assert.Error(t, oc.validateKafkaBrokers(), "Invalid kafka brokers")
+
+	for _, broker := range []string{"2001:db8::1:9092", "[2001:db8::1]", "[foo.bar]:9092", "[127.0.0.1]:9092", "[2001:db8::1]:-1"} {
+		oc = &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{broker}}}}
+		assert.Error(t, oc.validateKafkaBrokers(), "Invalid kafka broker %s", broker)
+	}
 }
@@ -223,6 +223,17 @@ func ChannelCreationPolicyValue(policy *cb.Policy) *StandardConfigValue {
 	}
 }
 
+// ChannelTemplatesValue returns the config definition for a consortium's channel templates, each of which
+// is a sub-group keyed by the template name. It is a value for the /Channel/Consortiums/*/*.
+func ChannelTemplatesValue(templates map[string]*cb.ConfigGroup) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: ChannelTemplatesKey,
+		value: &cb.ConfigGroup{
+			Groups: templates,
+		},
+	}
+}
+
 // ACLValues returns the config definition for an applications resources based ACL definitions.
 // It is a value for the /Channel/Application/.
 func ACLValues(acls map[string]string) *StandardConfigValue {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"sort"
 	"strings"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -201,3 +202,98 @@ func (vi *ValidatorImpl) computeUpdateResult(updatedConfig map[string]comparable
 	}
 	return newConfigMap
 }
+
+// PolicyRequirement describes a policy which must be satisfied in order
+// for a config update to modify a particular config element.
+type PolicyRequirement struct {
+	// Element is the fully qualified key of the modified config element
+	Element string
+	// PolicyPath is the fully qualified path of the element's mod_policy
+	PolicyPath string
+	// Policy is the resolved mod_policy, or nil if it could not be found
+	Policy policies.Policy
+}
+
+// RequiredPolicies computes, without evaluating any signatures, the set of
+// policies which must be satisfied for the given config update to be
+// authorized against the current config.  The read set and the versions
+// of the delta set are checked in the same way as during validation.
+func (vi *ValidatorImpl) RequiredPolicies(configUpdate *cb.ConfigUpdate) ([]*PolicyRequirement, error) {
+	if configUpdate == nil {
+		return nil, errors.Errorf("cannot process nil ConfigUpdate")
+	}
+
+	if configUpdate.ChannelId != vi.channelID {
+		return nil, errors.Errorf("ConfigUpdate for channel '%s' but validator for channel '%s'", configUpdate.ChannelId, vi.channelID)
+	}
+
+	readSet, err := mapConfig(configUpdate.ReadSet, vi.namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error mapping ReadSet")
+	}
+	if err := vi.verifyReadSet(readSet); err != nil {
+		return nil, errors.Wrapf(err, "error validating ReadSet")
+	}
+
+	writeSet, err := mapConfig(configUpdate.WriteSet, vi.namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error mapping WriteSet")
+	}
+
+	deltaSet := computeDeltaSet(readSet, writeSet)
+	if len(deltaSet) == 0 {
+		return nil, errors.Errorf("delta set was empty -- update would have no effect")
+	}
+
+	keys := make([]string, 0, len(deltaSet))
+	for key := range deltaSet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var requirements []*PolicyRequirement
+	for _, key := range keys {
+		value := deltaSet[key]
+		existing, ok := vi.configMap[key]
+		if !ok {
+			if value.version() != 0 {
+				return nil, errors.Errorf("attempted to set key %s to version %d, but key does not exist", key, value.version())
+			}
+			// New elements are authorized by the mod_policy of their modified parent
+			continue
+		}
+		if value.version() != existing.version()+1 {
+			return nil, errors.Errorf("attempt to set key %s to version %d, but key is at version %d", key, value.version(), existing.version())
+		}
+
+		requirement := &PolicyRequirement{
+			Element:    key,
+			PolicyPath: qualifiedModPolicy(existing),
+		}
+		if policy, ok := vi.policyForItem(existing); ok {
+			requirement.Policy = policy
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, nil
+}
+
+// qualifiedModPolicy resolves the mod_policy of a config element to an
+// absolute policy path, mirroring the lookup performed by policyForItem.
+func qualifiedModPolicy(item comparable) string {
+	modPolicy := item.modPolicy()
+	if len(modPolicy) == 0 || modPolicy[0] == policies.PathSeparator[0] {
+		return modPolicy
+	}
+
+	path := item.path
+	if item.ConfigGroup != nil {
+		path = append(append([]string{}, item.path...), item.key)
+	}
+	if len(path) == 0 {
+		return policies.PathSeparator + modPolicy
+	}
+
+	return policies.PathSeparator + strings.Join(path, policies.PathSeparator) + policies.PathSeparator + modPolicy
+}
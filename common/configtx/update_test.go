@@ -232,3 +232,84 @@ func TestValidateModPolicy(t *testing.T) {
 		assert.Regexp(t, "path element at 1 is invalid", validateModPolicy("foo//bar"))
 	})
 }
+
+func TestRequiredPolicies(t *testing.T) {
+	pm := &mockpolicies.PolicyManager{}
+	pm.GetPolicyReturns(&mockpolicies.Policy{}, true)
+	pm.ManagerReturns(pm, true)
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				"Application": {
+					ModPolicy: "Admins",
+					Values: map[string]*cb.ConfigValue{
+						"ACLs": {ModPolicy: "Admins"},
+					},
+				},
+			},
+			ModPolicy: "/Channel/Admins",
+		},
+	}
+	vi, err := NewValidatorImpl("foochannel", config, "Channel", pm)
+	assert.NoError(t, err)
+
+	t.Run("Green path", func(t *testing.T) {
+		requirements, err := vi.RequiredPolicies(&cb.ConfigUpdate{
+			ChannelId: "foochannel",
+			ReadSet: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					"Application": {},
+				},
+			},
+			WriteSet: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					"Application": {
+						Values: map[string]*cb.ConfigValue{
+							"ACLs": {Version: 1, ModPolicy: "Admins"},
+							"New":  {ModPolicy: "Admins"},
+						},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, requirements, 1)
+		assert.Equal(t, "[Value]  /Channel/Application/ACLs", requirements[0].Element)
+		assert.Equal(t, "/Channel/Application/Admins", requirements[0].PolicyPath)
+		assert.NotNil(t, requirements[0].Policy)
+	})
+
+	t.Run("Wrong channel", func(t *testing.T) {
+		_, err := vi.RequiredPolicies(&cb.ConfigUpdate{ChannelId: "barchannel"})
+		assert.EqualError(t, err, "ConfigUpdate for channel 'barchannel' but validator for channel 'foochannel'")
+	})
+
+	t.Run("Empty delta set", func(t *testing.T) {
+		_, err := vi.RequiredPolicies(&cb.ConfigUpdate{
+			ChannelId: "foochannel",
+			ReadSet:   &cb.ConfigGroup{},
+			WriteSet:  &cb.ConfigGroup{},
+		})
+		assert.EqualError(t, err, "delta set was empty -- update would have no effect")
+	})
+
+	t.Run("Version skip", func(t *testing.T) {
+		_, err := vi.RequiredPolicies(&cb.ConfigUpdate{
+			ChannelId: "foochannel",
+			ReadSet: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{"Application": {}},
+			},
+			WriteSet: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					"Application": {
+						Values: map[string]*cb.ConfigValue{
+							"ACLs": {Version: 2, ModPolicy: "Admins"},
+						},
+					},
+				},
+			},
+		})
+		assert.EqualError(t, err, "attempt to set key [Value]  /Channel/Application/ACLs to version 2, but key is at version 0")
+	})
+}
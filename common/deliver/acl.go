@@ -32,6 +32,7 @@ func NewSessionAC(chain ConfigSequencer, env *common.Envelope, policyChecker Pol
 
 	return &SessionAccessControl{
 		envelope:       env,
+		signedData:     signedData[0],
 		channelID:      channelID,
 		sequencer:      chain,
 		policyChecker:  policyChecker,
@@ -39,6 +40,22 @@ func NewSessionAC(chain ConfigSequencer, env *common.Envelope, policyChecker Pol
 	}, nil
 }
 
+// NewDelegatedSessionAC creates an instance of SessionAccessControl for a request
+// carrying a delegation token. The access is determined for the issuer of the
+// token, which is verified by the TokenAuthenticator whenever the policy is
+// evaluated, and the session ends when the token expires.
+func NewDelegatedSessionAC(chain Chain, token *DelegationToken, policyChecker PolicyChecker, channelID string, expiresAt ExpiresAtFunc, authenticator *TokenAuthenticator) (*SessionAccessControl, error) {
+	ac, err := NewSessionAC(chain, token.envelope, policyChecker, channelID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	ac.tokenExpiresAt = token.Claims.ExpiresAt
+	ac.verifyToken = func() error {
+		return authenticator.Verify(token, chain, channelID, time.Now())
+	}
+	return ac, nil
+}
+
 // SessionAccessControl holds access control related data for a common Envelope
 // that is used to determine if a request is allowed for the identity
 // associated with the request envelope.
@@ -47,9 +64,16 @@ type SessionAccessControl struct {
 	policyChecker      PolicyChecker
 	channelID          string
 	envelope           *common.Envelope
+	signedData         *protoutil.SignedData
 	lastConfigSequence uint64
 	sessionEndTime     time.Time
 	usedAtLeastOnce    bool
+
+	eventPolicyChecker ChaincodeEventPolicyChecker
+	eventAccess        *ChaincodeEventAccess
+
+	tokenExpiresAt time.Time
+	verifyToken    func() error
 }
 
 // Evaluate uses the PolicyChecker to determine if a request should be allowed.
@@ -59,6 +83,9 @@ func (ac *SessionAccessControl) Evaluate() error {
 	if !ac.sessionEndTime.IsZero() && time.Now().After(ac.sessionEndTime) {
 		return errors.Errorf("deliver client identity expired %v before", time.Since(ac.sessionEndTime))
 	}
+	if !ac.tokenExpiresAt.IsZero() && !time.Now().Before(ac.tokenExpiresAt) {
+		return errors.Errorf("delegation token expired %v before", time.Since(ac.tokenExpiresAt))
+	}
 
 	policyCheckNeeded := !ac.usedAtLeastOnce
 
@@ -72,5 +99,66 @@ func (ac *SessionAccessControl) Evaluate() error {
 	}
 
 	ac.usedAtLeastOnce = true
-	return ac.policyChecker.CheckPolicy(ac.envelope, ac.channelID)
+	if ac.verifyToken != nil {
+		if err := ac.verifyToken(); err != nil {
+			return err
+		}
+	}
+	err := ac.policyChecker.CheckPolicy(ac.envelope, ac.channelID)
+	if ac.eventPolicyChecker == nil {
+		return err
+	}
+
+	policies, eventErr := ac.eventPolicyChecker.CheckChaincodeEventPolicies(ac.envelope, ac.channelID)
+	if eventErr != nil {
+		return eventErr
+	}
+	access := NewChaincodeEventAccess(err == nil, policies)
+	if err != nil && !access.any() {
+		return err
+	}
+	ac.eventAccess = access
+	return nil
+}
+
+// ChaincodeEventAccess returns the chaincode events the identity may receive as of
+// the last evaluation, or nil if the chaincode events are not restricted.
+func (ac *SessionAccessControl) ChaincodeEventAccess() *ChaincodeEventAccess {
+	return ac.eventAccess
+}
+
+// ChaincodeEventAccess tells which chaincode events may be delivered to a client.
+type ChaincodeEventAccess struct {
+	// Unrestricted is true if the client satisfies the policy of the deliver
+	// service, and may therefore receive the events of the chaincodes without
+	// a policy of their own.
+	Unrestricted bool
+
+	policies map[string]bool
+}
+
+// NewChaincodeEventAccess returns a ChaincodeEventAccess for a client satisfying,
+// or not, the policy of the deliver service and the policies of the chaincodes.
+func NewChaincodeEventAccess(unrestricted bool, policies map[string]bool) *ChaincodeEventAccess {
+	return &ChaincodeEventAccess{
+		Unrestricted: unrestricted,
+		policies:     policies,
+	}
+}
+
+// Allowed returns whether the events of the chaincode may be delivered.
+func (a *ChaincodeEventAccess) Allowed(chaincodeName string) bool {
+	if satisfied, ok := a.policies[chaincodeName]; ok {
+		return satisfied
+	}
+	return a.Unrestricted
+}
+
+func (a *ChaincodeEventAccess) any() bool {
+	for _, satisfied := range a.policies {
+		if satisfied {
+			return true
+		}
+	}
+	return false
 }
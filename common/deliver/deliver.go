@@ -69,6 +69,16 @@ func (pcf PolicyCheckerFunc) CheckPolicy(envelope *cb.Envelope, channelID string
 	return pcf(envelope, channelID)
 }
 
+//go:generate counterfeiter -o mock/chaincode_event_policy_checker.go -fake-name ChaincodeEventPolicyChecker . ChaincodeEventPolicyChecker
+
+// ChaincodeEventPolicyChecker checks the envelope against the policies restricting
+// the delivery of the events of individual chaincodes.
+type ChaincodeEventPolicyChecker interface {
+	// CheckChaincodeEventPolicies returns, for each chaincode of the channel whose
+	// events are restricted by a policy, whether the envelope satisfies the policy.
+	CheckChaincodeEventPolicies(envelope *cb.Envelope, channelID string) (map[string]bool, error)
+}
+
 //go:generate counterfeiter -o mock/inspector.go -fake-name Inspector . Inspector
 
 // Inspector verifies an appropriate binding between the message and the context.
@@ -114,6 +124,14 @@ type ResponseSender interface {
 	DataType() string
 }
 
+// ChaincodeEventResponseSender is implemented by the response senders which
+// can restrict the chaincode events they deliver.
+type ChaincodeEventResponseSender interface {
+	// SendRestrictedBlockResponse sends the block with the chaincode events
+	// allowed by the access.
+	SendRestrictedBlockResponse(data *cb.Block, channelID string, access *ChaincodeEventAccess) error
+}
+
 // Filtered is a marker interface that indicates a response sender
 // is configured to send filtered blocks
 // Note: this is replaced by "data_type" label. Keep it for now until we decide how to take care of compatibility issue.
@@ -127,6 +145,16 @@ type Server struct {
 	Receiver
 	PolicyChecker
 	ResponseSender
+
+	// ChaincodeEventPolicyChecker, when set and supported by the ResponseSender,
+	// restricts the delivery of the events of the chaincodes with a policy. A
+	// client which does not satisfy the PolicyChecker is then still served the
+	// events of the chaincodes whose policies it satisfies.
+	ChaincodeEventPolicyChecker ChaincodeEventPolicyChecker
+
+	// TokenAuthenticator, when set, accepts the requests of the clients which
+	// present a delegation token in place of their own identity.
+	TokenAuthenticator *TokenAuthenticator
 }
 
 // ExtractChannelHeaderCertHash extracts the TLS cert hash from a channel header.
@@ -198,7 +226,18 @@ func isFiltered(srv *Server) bool {
 
 func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.Envelope) (status cb.Status, err error) {
 	addr := util.ExtractRemoteAddress(ctx)
-	payload, chdr, shdr, err := h.parseEnvelope(ctx, envelope)
+	var token *DelegationToken
+	if srv.TokenAuthenticator != nil {
+		token, err = extractDelegationToken(ctx)
+		if err != nil {
+			logger.Warningf("invalid delegation token from %s: %s", addr, err)
+			return cb.Status_BAD_REQUEST, nil
+		}
+	}
+
+	// the requests carrying a delegation token are not bound to the TLS
+	// certificate of the client, which may not have any
+	payload, chdr, _, err := h.parseEnvelope(ctx, envelope, token == nil)
 	if err != nil {
 		logger.Warningf("error parsing envelope from %s: %s", addr, err)
 		return cb.Status_BAD_REQUEST, nil
@@ -242,11 +281,21 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 	default:
 	}
 
-	accessControl, err := NewSessionAC(chain, envelope, srv.PolicyChecker, chdr.ChannelId, h.ExpirationCheckFunc)
+	var accessControl *SessionAccessControl
+	if token != nil {
+		logger.Debugf("[channel: %s] Client %s presented a delegation token issued to [%s]", chdr.ChannelId, addr, token.Claims.Subject)
+		accessControl, err = NewDelegatedSessionAC(chain, token, srv.PolicyChecker, chdr.ChannelId, h.ExpirationCheckFunc, srv.TokenAuthenticator)
+	} else {
+		accessControl, err = NewSessionAC(chain, envelope, srv.PolicyChecker, chdr.ChannelId, h.ExpirationCheckFunc)
+	}
 	if err != nil {
 		logger.Warningf("[channel: %s] failed to create access control object due to %s", chdr.ChannelId, err)
 		return cb.Status_BAD_REQUEST, nil
 	}
+	eventSender, restrictEvents := srv.ResponseSender.(ChaincodeEventResponseSender)
+	if restrictEvents && srv.ChaincodeEventPolicyChecker != nil {
+		accessControl.eventPolicyChecker = srv.ChaincodeEventPolicyChecker
+	}
 
 	if err := accessControl.Evaluate(); err != nil {
 		logger.Warningf("[channel: %s] Client %s is not authorized: %s", chdr.ChannelId, addr, err)
@@ -260,7 +309,8 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 
 	logger.Debugf("[channel: %s] Received seekInfo (%p) %v from %s", chdr.ChannelId, seekInfo, seekInfo, addr)
 
-	cursor, number := chain.Reader().Iterator(seekInfo.Start)
+	reader := chain.Reader()
+	cursor, number := reader.Iterator(seekInfo.Start)
 	defer cursor.Close()
 	var stopNum uint64
 	switch stop := seekInfo.Stop.Type.(type) {
@@ -282,6 +332,18 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 			logger.Warningf("[channel: %s] Received invalid seekInfo message from %s: start number %d greater than stop number %d", chdr.ChannelId, addr, number, stopNum)
 			return cb.Status_BAD_REQUEST, nil
 		}
+	case *ab.SeekPosition_Transaction:
+		logger.Warningf("[channel: %s] Received invalid seekInfo message from %s: a transaction can only be sought as the start position", chdr.ChannelId, addr)
+		return cb.Status_BAD_REQUEST, nil
+	}
+
+	// a ledger retaining only its most recent blocks keeps the config blocks
+	// below its first retained block, which are delivered one at a time
+	if pruned, ok := reader.(blockledger.Pruned); ok {
+		if firstRetained := pruned.FirstRetainedBlock(); number < firstRetained && !isRetainedBlock(reader, number, stopNum) {
+			logger.Warningf("[channel: %s] Rejecting deliver request from %s for block [%d], the blocks below block [%d] are pruned", chdr.ChannelId, addr, number, firstRetained)
+			return cb.Status_GONE, nil
+		}
 	}
 
 	for {
@@ -328,8 +390,12 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 
 		logger.Debugf("[channel: %s] Delivering block [%d] for (%p) for %s", chdr.ChannelId, block.Header.Number, seekInfo, addr)
 
-		signedData := &protoutil.SignedData{Data: envelope.Payload, Identity: shdr.Creator, Signature: envelope.Signature}
-		if err := srv.SendBlockResponse(block, chdr.ChannelId, chain, signedData); err != nil {
+		if access := accessControl.ChaincodeEventAccess(); access != nil {
+			err = eventSender.SendRestrictedBlockResponse(block, chdr.ChannelId, access)
+		} else {
+			err = srv.SendBlockResponse(block, chdr.ChannelId, chain, accessControl.signedData)
+		}
+		if err != nil {
 			logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
 			return cb.Status_INTERNAL_SERVER_ERROR, err
 		}
@@ -346,7 +412,17 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 	return cb.Status_SUCCESS, nil
 }
 
-func (h *Handler) parseEnvelope(ctx context.Context, envelope *cb.Envelope) (*cb.Payload, *cb.ChannelHeader, *cb.SignatureHeader, error) {
+// isRetainedBlock returns whether a request for the blocks from start to stop
+// asks for a single block kept below the first retained block of the ledger.
+func isRetainedBlock(reader blockledger.Reader, start, stop uint64) bool {
+	if start != stop {
+		return false
+	}
+	_, err := reader.RetrieveBlockByNumber(start)
+	return err == nil
+}
+
+func (h *Handler) parseEnvelope(ctx context.Context, envelope *cb.Envelope, inspectBinding bool) (*cb.Payload, *cb.ChannelHeader, *cb.SignatureHeader, error) {
 	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
 	if err != nil {
 		return nil, nil, nil, err
@@ -366,7 +442,7 @@ func (h *Handler) parseEnvelope(ctx context.Context, envelope *cb.Envelope) (*cb
 		return nil, nil, nil, err
 	}
 
-	err = h.validateChannelHeader(ctx, chdr)
+	err = h.validateChannelHeader(ctx, chdr, inspectBinding)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -374,7 +450,7 @@ func (h *Handler) parseEnvelope(ctx context.Context, envelope *cb.Envelope) (*cb
 	return payload, chdr, shdr, nil
 }
 
-func (h *Handler) validateChannelHeader(ctx context.Context, chdr *cb.ChannelHeader) error {
+func (h *Handler) validateChannelHeader(ctx context.Context, chdr *cb.ChannelHeader, inspectBinding bool) error {
 	if chdr.GetTimestamp() == nil {
 		err := errors.New("channel header in envelope must contain timestamp")
 		return err
@@ -388,6 +464,10 @@ func (h *Handler) validateChannelHeader(ctx context.Context, chdr *cb.ChannelHea
 		return err
 	}
 
+	if !inspectBinding {
+		return nil
+	}
+
 	err := h.BindingInspector.Inspect(ctx, chdr)
 	if err != nil {
 		return err
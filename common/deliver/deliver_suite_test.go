@@ -27,6 +27,14 @@ type privateDataResponseSender interface {
 	deliver.ResponseSender
 }
 
+//go:generate counterfeiter -o mock/restricted_response_sender.go -fake-name RestrictedResponseSender . restrictedResponseSender
+
+type restrictedResponseSender interface {
+	deliver.ResponseSender
+	deliver.Filtered
+	deliver.ChaincodeEventResponseSender
+}
+
 func TestDeliver(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Deliver Suite")
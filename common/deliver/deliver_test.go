@@ -29,6 +29,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
@@ -574,6 +575,106 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when the chaincode events are restricted", func() {
+			var (
+				fakeResponseSender     *mock.RestrictedResponseSender
+				fakeEventPolicyChecker *mock.ChaincodeEventPolicyChecker
+			)
+
+			BeforeEach(func() {
+				fakeResponseSender = &mock.RestrictedResponseSender{}
+				fakeResponseSender.IsFilteredReturns(true)
+				fakeResponseSender.DataTypeReturns("filtered_block")
+				fakeEventPolicyChecker = &mock.ChaincodeEventPolicyChecker{}
+				fakeEventPolicyChecker.CheckChaincodeEventPoliciesReturns(map[string]bool{"mycc": true, "othercc": false}, nil)
+				server.ResponseSender = fakeResponseSender
+				server.ChaincodeEventPolicyChecker = fakeEventPolicyChecker
+			})
+
+			It("sends the blocks restricted to the allowed chaincode events", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeEventPolicyChecker.CheckChaincodeEventPoliciesCallCount()).To(Equal(1))
+				env, channelID := fakeEventPolicyChecker.CheckChaincodeEventPoliciesArgsForCall(0)
+				Expect(env).To(Equal(envelope))
+				Expect(channelID).To(Equal("chain-id"))
+
+				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(0))
+				Expect(fakeResponseSender.SendRestrictedBlockResponseCallCount()).To(Equal(1))
+				block, channelID, access := fakeResponseSender.SendRestrictedBlockResponseArgsForCall(0)
+				Expect(block.Header.Number).To(Equal(uint64(100)))
+				Expect(channelID).To(Equal("chain-id"))
+				Expect(access.Unrestricted).To(BeTrue())
+				Expect(access.Allowed("mycc")).To(BeTrue())
+				Expect(access.Allowed("othercc")).To(BeFalse())
+				Expect(access.Allowed("unrestrictedcc")).To(BeTrue())
+
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+			})
+
+			Context("when the client does not satisfy the policy of the deliver service", func() {
+				BeforeEach(func() {
+					fakePolicyChecker.CheckPolicyReturns(errors.New("no-access-for-you"))
+				})
+
+				It("sends the events of the chaincodes whose policies it satisfies", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendRestrictedBlockResponseCallCount()).To(Equal(1))
+					_, _, access := fakeResponseSender.SendRestrictedBlockResponseArgsForCall(0)
+					Expect(access.Unrestricted).To(BeFalse())
+					Expect(access.Allowed("mycc")).To(BeTrue())
+					Expect(access.Allowed("othercc")).To(BeFalse())
+					Expect(access.Allowed("unrestrictedcc")).To(BeFalse())
+				})
+
+				Context("when the client does not satisfy any chaincode event policy", func() {
+					BeforeEach(func() {
+						fakeEventPolicyChecker.CheckChaincodeEventPoliciesReturns(map[string]bool{"othercc": false}, nil)
+					})
+
+					It("sends status forbidden", func() {
+						err := handler.Handle(context.Background(), server)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeResponseSender.SendRestrictedBlockResponseCallCount()).To(Equal(0))
+						Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+						Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_FORBIDDEN))
+					})
+				})
+			})
+
+			Context("when the chaincode event policies cannot be checked", func() {
+				BeforeEach(func() {
+					fakeEventPolicyChecker.CheckChaincodeEventPoliciesReturns(nil, errors.New("boom"))
+				})
+
+				It("sends status forbidden", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_FORBIDDEN))
+				})
+			})
+
+			Context("when the response sender cannot restrict the chaincode events", func() {
+				BeforeEach(func() {
+					server.ResponseSender = &mock.FilteredResponseSender{}
+				})
+
+				It("does not check the chaincode event policies", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeEventPolicyChecker.CheckChaincodeEventPoliciesCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when blocks with private data are requested", func() {
 			var (
 				fakeResponseSender *mock.PrivateDataResponseSender
@@ -771,6 +872,163 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when the client presents a delegation token", func() {
+			var (
+				ctx               context.Context
+				fakePolicyManager *mock.PolicyManager
+				fakePolicy        *mock.Policy
+				encodedToken      string
+			)
+
+			BeforeEach(func() {
+				fakePolicy = &mock.Policy{}
+				fakePolicyManager = &mock.PolicyManager{}
+				fakePolicyManager.GetPolicyReturns(fakePolicy, true)
+				fakeChain.PolicyManagerReturns(fakePolicyManager)
+
+				var err error
+				encodedToken, err = deliver.CreateDelegationToken(&tokenSigner{identity: []byte("admin-identity")}, "chain-id", &deliver.DelegationClaims{
+					Subject:   "gateway",
+					ExpiresAt: time.Now().Add(time.Minute),
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				server.TokenAuthenticator = &deliver.TokenAuthenticator{
+					IssuerPolicy: "/Channel/Application/Admins",
+					MaxLifetime:  time.Hour,
+				}
+			})
+
+			JustBeforeEach(func() {
+				ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(deliver.DelegationTokenHeader, "Bearer "+encodedToken))
+			})
+
+			It("checks the access of the issuer of the token instead of the client", func() {
+				err := handler.Handle(ctx, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeInspector.InspectCallCount()).To(Equal(0))
+				Expect(fakePolicy.EvaluateSignedDataCallCount()).To(Equal(1))
+				Expect(fakePolicyChecker.CheckPolicyCallCount()).To(Equal(1))
+				env, channelID := fakePolicyChecker.CheckPolicyArgsForCall(0)
+				Expect(channelID).To(Equal("chain-id"))
+				shdr, err := protoutil.UnmarshalSignatureHeader(protoutil.UnmarshalPayloadOrPanic(env.Payload).Header.SignatureHeader)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(shdr.Creator).To(Equal([]byte("admin-identity")))
+
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+			})
+
+			Context("when the issuer is not authorized", func() {
+				BeforeEach(func() {
+					fakePolicy.EvaluateSignedDataReturns(errors.New("not an admin"))
+				})
+
+				It("sends status forbidden", func() {
+					err := handler.Handle(ctx, server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakePolicyChecker.CheckPolicyCallCount()).To(Equal(0))
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_FORBIDDEN))
+				})
+			})
+
+			Context("when the token is malformed", func() {
+				BeforeEach(func() {
+					encodedToken = "garbage"
+				})
+
+				It("sends status bad request", func() {
+					err := handler.Handle(ctx, server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_BAD_REQUEST))
+				})
+			})
+
+			Context("when the server does not accept delegation tokens", func() {
+				BeforeEach(func() {
+					server.TokenAuthenticator = nil
+				})
+
+				It("ignores the token", func() {
+					err := handler.Handle(ctx, server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeInspector.InspectCallCount()).To(Equal(1))
+					Expect(fakePolicy.EvaluateSignedDataCallCount()).To(Equal(0))
+					env, _ := fakePolicyChecker.CheckPolicyArgsForCall(0)
+					Expect(env).To(Equal(envelope))
+				})
+			})
+		})
+
+		Context("when the ledger retains only its most recent blocks", func() {
+			BeforeEach(func() {
+				fakeBlockReader.IteratorReturns(fakeBlockIterator, 0)
+				fakeBlockReader.RetrieveBlockByNumberReturns(nil, errors.New("pruned"))
+				fakeChain.ReaderReturns(&prunedBlockReader{BlockReader: fakeBlockReader, firstRetainedBlock: 50})
+
+				seekInfo = &ab.SeekInfo{
+					Start: seekOldest,
+					Stop: &ab.SeekPosition{
+						Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 51}},
+					},
+				}
+			})
+
+			It("sends status gone for the pruned blocks", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeBlockIterator.NextCallCount()).To(Equal(0))
+				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(0))
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_GONE))
+			})
+
+			Context("when a single retained config block is requested", func() {
+				BeforeEach(func() {
+					fakeBlockReader.IteratorReturns(fakeBlockIterator, 20)
+					fakeBlockReader.RetrieveBlockByNumberReturns(&cb.Block{Header: &cb.BlockHeader{Number: 20}}, nil)
+					fakeBlockIterator.NextReturns(&cb.Block{Header: &cb.BlockHeader{Number: 20}}, cb.Status_SUCCESS)
+					seekInfo.Stop = &ab.SeekPosition{
+						Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 20}},
+					}
+				})
+
+				It("delivers the block", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeBlockReader.RetrieveBlockByNumberArgsForCall(0)).To(Equal(uint64(20)))
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+				})
+			})
+
+			Context("when the start block is retained", func() {
+				BeforeEach(func() {
+					fakeBlockReader.IteratorReturns(fakeBlockIterator, 50)
+					fakeBlockIterator.NextReturnsOnCall(0, &cb.Block{Header: &cb.BlockHeader{Number: 50}}, cb.Status_SUCCESS)
+					fakeBlockIterator.NextReturnsOnCall(1, &cb.Block{Header: &cb.BlockHeader{Number: 51}}, cb.Status_SUCCESS)
+				})
+
+				It("delivers the blocks", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(2))
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendStatusResponseArgsForCall(0)).To(Equal(cb.Status_SUCCESS))
+				})
+			})
+		})
+
 		Context("when the client disconnects before reading from the chain", func() {
 			var (
 				ctx    context.Context
@@ -974,6 +1232,27 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when seek info stops at a transaction", func() {
+			BeforeEach(func() {
+				seekInfo = &ab.SeekInfo{
+					Start: seekOldest,
+					Stop: &ab.SeekPosition{
+						Type: &ab.SeekPosition_Transaction{Transaction: &ab.SeekTransaction{TxId: "txid"}},
+					},
+				}
+			})
+
+			It("sends status bad request", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeBlockIterator.NextCallCount()).To(Equal(0))
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+				Expect(resp).To(Equal(cb.Status_BAD_REQUEST))
+			})
+		})
+
 		Context("when fail if not ready is set and the next block is unavailable", func() {
 			BeforeEach(func() {
 				fakeBlockReader.HeightReturns(1000)
@@ -1017,3 +1296,16 @@ var _ = Describe("Deliver", func() {
 		})
 	})
 })
+
+type prunedBlockReader struct {
+	*mock.BlockReader
+	firstRetainedBlock uint64
+}
+
+func (r *prunedBlockReader) FirstRetainedBlock() uint64 {
+	return r.firstRetainedBlock
+}
+
+func (r *prunedBlockReader) Prune(belowBlockNum uint64) error {
+	return nil
+}
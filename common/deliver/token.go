@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DelegationTokenHeader is the gRPC metadata key deliver clients present
+	// their delegation token in, as "Bearer <token>".
+	DelegationTokenHeader = "authorization"
+
+	delegationTokenScheme = "Bearer "
+)
+
+// DelegationClaims are the claims of a delegation token.
+type DelegationClaims struct {
+	// Subject names the bearer the token was issued to, for auditing.
+	Subject string `json:"subject,omitempty"`
+	// ExpiresAt is the time after which the token is no longer accepted.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DelegationToken grants its bearer the access of its issuer to the deliver
+// service of a channel until it expires. It is an envelope signed by the
+// issuer, whose channel header binds the token to the channel and records
+// the time it was issued at, and whose payload holds the claims.
+type DelegationToken struct {
+	ChannelID string
+	IssuedAt  time.Time
+	Claims    *DelegationClaims
+
+	envelope *cb.Envelope
+}
+
+// CreateDelegationToken returns a delegation token for the channel signed by
+// the signer, encoded to be presented by a deliver client.
+func CreateDelegationToken(signer identity.SignerSerializer, channelID string, claims *DelegationClaims) (string, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to serialize the issuer of the delegation token")
+	}
+	nonce, err := protoutil.CreateNonce()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the claims of the delegation token")
+	}
+
+	payload := &cb.Payload{
+		Header: protoutil.MakePayloadHeader(
+			protoutil.MakeChannelHeader(cb.HeaderType_MESSAGE, 0, channelID, 0),
+			protoutil.MakeSignatureHeader(creator, nonce),
+		),
+		Data: data,
+	}
+	payloadBytes := protoutil.MarshalOrPanic(payload)
+	sig, err := signer.Sign(payloadBytes)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to sign the delegation token")
+	}
+
+	env := &cb.Envelope{Payload: payloadBytes, Signature: sig}
+	return base64.RawURLEncoding.EncodeToString(protoutil.MarshalOrPanic(env)), nil
+}
+
+// ParseDelegationToken decodes a delegation token. The signature of the token
+// is not verified.
+func ParseDelegationToken(encoded string) (*DelegationToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "delegation token is not base64url encoded")
+	}
+	env := &cb.Envelope{}
+	if err := proto.Unmarshal(raw, env); err != nil {
+		return nil, errors.Wrap(err, "malformed delegation token")
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed delegation token")
+	}
+	if payload.Header == nil {
+		return nil, errors.New("delegation token has no header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed delegation token")
+	}
+	if chdr.Type != int32(cb.HeaderType_MESSAGE) || chdr.Timestamp == nil {
+		return nil, errors.New("delegation token has an invalid channel header")
+	}
+	claims := &DelegationClaims{}
+	if err := json.Unmarshal(payload.Data, claims); err != nil {
+		return nil, errors.Wrap(err, "malformed claims in delegation token")
+	}
+
+	return &DelegationToken{
+		ChannelID: chdr.ChannelId,
+		IssuedAt:  time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)),
+		Claims:    claims,
+		envelope:  env,
+	}, nil
+}
+
+// extractDelegationToken returns the delegation token presented in the
+// metadata of the stream, if any.
+func extractDelegationToken(ctx context.Context) (*DelegationToken, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	for _, value := range md.Get(DelegationTokenHeader) {
+		if strings.HasPrefix(value, delegationTokenScheme) {
+			return ParseDelegationToken(strings.TrimPrefix(value, delegationTokenScheme))
+		}
+	}
+	return nil, nil
+}
+
+// TokenAuthenticator authenticates the deliver requests carrying a delegation
+// token. Such requests need neither be signed by the client nor be bound to
+// its TLS certificate: the access control of the deliver service is applied
+// to the issuer of the token instead.
+type TokenAuthenticator struct {
+	// IssuerPolicy is the channel policy the issuers of the tokens must
+	// satisfy, e.g. /Channel/Application/Admins.
+	IssuerPolicy string
+	// MaxLifetime bounds the time between the issuance and the expiration
+	// of the tokens, and the time until the expiration of the tokens.
+	MaxLifetime time.Duration
+	// ClockSkew is the tolerated difference between the clocks of the
+	// issuers and of this peer. The tokens issued later than ClockSkew after
+	// the current time are rejected.
+	ClockSkew time.Duration
+}
+
+// Verify checks that the token was issued for the channel by an identity
+// satisfying the issuer policy and that it has not expired. As the issuer
+// chooses the time of issuance, the token must neither be issued in the
+// future nor expire later than MaxLifetime after the current time.
+func (ta *TokenAuthenticator) Verify(token *DelegationToken, chain Chain, channelID string, now time.Time) error {
+	if token.ChannelID != channelID {
+		return errors.Errorf("delegation token was issued for channel [%s]", token.ChannelID)
+	}
+	if !now.Before(token.Claims.ExpiresAt) {
+		return errors.Errorf("delegation token expired %v before", now.Sub(token.Claims.ExpiresAt))
+	}
+	if token.IssuedAt.After(now.Add(ta.ClockSkew)) {
+		return errors.Errorf("delegation token is issued %v in the future", token.IssuedAt.Sub(now))
+	}
+	if lifetime := token.Claims.ExpiresAt.Sub(token.IssuedAt); lifetime > ta.MaxLifetime {
+		return errors.Errorf("delegation token lifetime %s exceeds the maximum of %s", lifetime, ta.MaxLifetime)
+	}
+	if latest := now.Add(ta.MaxLifetime); token.Claims.ExpiresAt.After(latest) {
+		return errors.Errorf("delegation token expires %v after the maximum lifetime of %s from now", token.Claims.ExpiresAt.Sub(latest), ta.MaxLifetime)
+	}
+
+	policy, ok := chain.PolicyManager().GetPolicy(ta.IssuerPolicy)
+	if !ok {
+		return errors.Errorf("could not find policy [%s] for the issuers of delegation tokens", ta.IssuerPolicy)
+	}
+	signedData, err := protoutil.EnvelopeAsSignedData(token.envelope)
+	if err != nil {
+		return err
+	}
+	if err := policy.EvaluateSignedData(signedData); err != nil {
+		return errors.WithMessage(err, "issuer of the delegation token is not authorized")
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver_test
+
+import (
+	"encoding/base64"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/deliver"
+	"github.com/hyperledger/fabric/common/deliver/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+type tokenSigner struct {
+	identity []byte
+}
+
+func (s *tokenSigner) Sign(msg []byte) ([]byte, error) {
+	return append([]byte("signature-over-"), msg[:4]...), nil
+}
+
+func (s *tokenSigner) Serialize() ([]byte, error) {
+	return s.identity, nil
+}
+
+var _ = Describe("DelegationToken", func() {
+	var (
+		signer    *tokenSigner
+		expiresAt time.Time
+	)
+
+	BeforeEach(func() {
+		signer = &tokenSigner{identity: []byte("admin-identity")}
+		expiresAt = time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	})
+
+	It("round trips", func() {
+		encoded, err := deliver.CreateDelegationToken(signer, "chain-id", &deliver.DelegationClaims{
+			Subject:   "gateway",
+			ExpiresAt: expiresAt,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		token, err := deliver.ParseDelegationToken(encoded)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.ChannelID).To(Equal("chain-id"))
+		Expect(token.Claims.Subject).To(Equal("gateway"))
+		Expect(token.Claims.ExpiresAt.Equal(expiresAt)).To(BeTrue())
+		Expect(token.IssuedAt).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	Context("when the token is not base64url encoded", func() {
+		It("returns an error", func() {
+			_, err := deliver.ParseDelegationToken("not a token!")
+			Expect(err).To(MatchError(ContainSubstring("delegation token is not base64url encoded")))
+		})
+	})
+
+	Context("when the token is not an envelope", func() {
+		It("returns an error", func() {
+			_, err := deliver.ParseDelegationToken(base64.RawURLEncoding.EncodeToString([]byte("garbage")))
+			Expect(err).To(MatchError(ContainSubstring("malformed delegation token")))
+		})
+	})
+
+	Context("when the token has another header type", func() {
+		It("returns an error", func() {
+			env := &cb.Envelope{
+				Payload: protoutil.MarshalOrPanic(&cb.Payload{
+					Header: protoutil.MakePayloadHeader(
+						protoutil.MakeChannelHeader(cb.HeaderType_CONFIG, 0, "chain-id", 0),
+						protoutil.MakeSignatureHeader([]byte("admin-identity"), nil),
+					),
+				}),
+			}
+			_, err := deliver.ParseDelegationToken(base64.RawURLEncoding.EncodeToString(protoutil.MarshalOrPanic(env)))
+			Expect(err).To(MatchError("delegation token has an invalid channel header"))
+		})
+	})
+
+	Describe("TokenAuthenticator", func() {
+		var (
+			token             *deliver.DelegationToken
+			fakeChain         *mock.Chain
+			fakePolicyManager *mock.PolicyManager
+			fakePolicy        *mock.Policy
+			authenticator     *deliver.TokenAuthenticator
+		)
+
+		BeforeEach(func() {
+			fakePolicy = &mock.Policy{}
+			fakePolicyManager = &mock.PolicyManager{}
+			fakePolicyManager.GetPolicyReturns(fakePolicy, true)
+			fakeChain = &mock.Chain{}
+			fakeChain.PolicyManagerReturns(fakePolicyManager)
+
+			authenticator = &deliver.TokenAuthenticator{
+				IssuerPolicy: "/Channel/Application/Admins",
+				MaxLifetime:  time.Hour,
+			}
+		})
+
+		JustBeforeEach(func() {
+			encoded, err := deliver.CreateDelegationToken(signer, "chain-id", &deliver.DelegationClaims{
+				ExpiresAt: expiresAt,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			token, err = deliver.ParseDelegationToken(encoded)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("evaluates the issuer policy against the token", func() {
+			err := authenticator.Verify(token, fakeChain, "chain-id", time.Now())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePolicyManager.GetPolicyArgsForCall(0)).To(Equal("/Channel/Application/Admins"))
+			Expect(fakePolicy.EvaluateSignedDataCallCount()).To(Equal(1))
+			signedData := fakePolicy.EvaluateSignedDataArgsForCall(0)
+			Expect(signedData).To(HaveLen(1))
+			Expect(signedData[0].Identity).To(Equal([]byte("admin-identity")))
+		})
+
+		It("rejects the tokens issued for another channel", func() {
+			err := authenticator.Verify(token, fakeChain, "other-chain", time.Now())
+			Expect(err).To(MatchError("delegation token was issued for channel [chain-id]"))
+		})
+
+		It("rejects the expired tokens", func() {
+			err := authenticator.Verify(token, fakeChain, "chain-id", expiresAt.Add(time.Second))
+			Expect(err).To(MatchError("delegation token expired 1s before"))
+		})
+
+		Context("when the token outlives the maximum lifetime", func() {
+			BeforeEach(func() {
+				expiresAt = time.Now().Add(2 * time.Hour)
+			})
+
+			It("returns an error", func() {
+				err := authenticator.Verify(token, fakeChain, "chain-id", time.Now())
+				Expect(err).To(MatchError(ContainSubstring("exceeds the maximum of 1h0m0s")))
+			})
+		})
+
+		Context("when the token is issued in the future", func() {
+			BeforeEach(func() {
+				authenticator.ClockSkew = time.Minute
+				expiresAt = time.Now().Add(50 * time.Minute)
+			})
+
+			It("returns an error", func() {
+				err := authenticator.Verify(token, fakeChain, "chain-id", time.Now().Add(-10*time.Minute))
+				Expect(err).To(MatchError(ContainSubstring("delegation token is issued")))
+				Expect(err).To(MatchError(ContainSubstring("in the future")))
+			})
+
+			It("accepts the token within the clock skew", func() {
+				err := authenticator.Verify(token, fakeChain, "chain-id", time.Now().Add(-30*time.Second))
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the token expires later than the maximum lifetime from now", func() {
+			BeforeEach(func() {
+				authenticator.ClockSkew = 10 * time.Minute
+				expiresAt = time.Now().Add(58 * time.Minute)
+			})
+
+			It("returns an error", func() {
+				err := authenticator.Verify(token, fakeChain, "chain-id", time.Now().Add(-5*time.Minute))
+				Expect(err).To(MatchError(ContainSubstring("after the maximum lifetime of 1h0m0s from now")))
+			})
+		})
+
+		Context("when the issuer policy does not exist", func() {
+			BeforeEach(func() {
+				fakePolicyManager.GetPolicyReturns(nil, false)
+			})
+
+			It("returns an error", func() {
+				err := authenticator.Verify(token, fakeChain, "chain-id", time.Now())
+				Expect(err).To(MatchError("could not find policy [/Channel/Application/Admins] for the issuers of delegation tokens"))
+			})
+		})
+
+		Context("when the issuer does not satisfy the policy", func() {
+			BeforeEach(func() {
+				fakePolicy.EvaluateSignedDataReturns(errors.New("not an admin"))
+			})
+
+			It("returns an error", func() {
+				err := authenticator.Verify(token, fakeChain, "chain-id", time.Now())
+				Expect(err).To(MatchError("issuer of the delegation token is not authorized: not an admin"))
+			})
+		})
+	})
+})
@@ -54,3 +54,18 @@ func (e VSCCExecutionFailureError) Error() string {
 func (e *VSCCExecutionFailureError) IsValid() bool {
 	return e.Err == nil
 }
+
+// VSCCNonDeterministicOutputError error to mark transaction
+// whose endorsements do not agree on the output of the chaincode
+type VSCCNonDeterministicOutputError struct {
+	Err error
+}
+
+// Error returns reasons which lead to the failure
+func (e VSCCNonDeterministicOutputError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *VSCCNonDeterministicOutputError) IsValid() bool {
+	return e.Err == nil
+}
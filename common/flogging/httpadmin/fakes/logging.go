@@ -3,11 +3,25 @@ package fakes
 
 import (
 	"sync"
+	"time"
 
+	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/flogging/httpadmin"
 )
 
 type Logging struct {
+	ActivateLevelsStub        func(map[string]string, time.Duration) error
+	activateLevelsMutex       sync.RWMutex
+	activateLevelsArgsForCall []struct {
+		arg1 map[string]string
+		arg2 time.Duration
+	}
+	activateLevelsReturns struct {
+		result1 error
+	}
+	activateLevelsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ActivateSpecStub        func(string) error
 	activateSpecMutex       sync.RWMutex
 	activateSpecArgsForCall []struct {
@@ -19,6 +33,39 @@ type Logging struct {
 	activateSpecReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ActivateSpecWithTTLStub        func(string, time.Duration) error
+	activateSpecWithTTLMutex       sync.RWMutex
+	activateSpecWithTTLArgsForCall []struct {
+		arg1 string
+		arg2 time.Duration
+	}
+	activateSpecWithTTLReturns struct {
+		result1 error
+	}
+	activateSpecWithTTLReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EncodingStub        func() flogging.Encoding
+	encodingMutex       sync.RWMutex
+	encodingArgsForCall []struct {
+	}
+	encodingReturns struct {
+		result1 flogging.Encoding
+	}
+	encodingReturnsOnCall map[int]struct {
+		result1 flogging.Encoding
+	}
+	SetJSONOutputStub        func(bool) error
+	setJSONOutputMutex       sync.RWMutex
+	setJSONOutputArgsForCall []struct {
+		arg1 bool
+	}
+	setJSONOutputReturns struct {
+		result1 error
+	}
+	setJSONOutputReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SpecStub        func() string
 	specMutex       sync.RWMutex
 	specArgsForCall []struct {
@@ -33,6 +80,67 @@ type Logging struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *Logging) ActivateLevels(arg1 map[string]string, arg2 time.Duration) error {
+	fake.activateLevelsMutex.Lock()
+	ret, specificReturn := fake.activateLevelsReturnsOnCall[len(fake.activateLevelsArgsForCall)]
+	fake.activateLevelsArgsForCall = append(fake.activateLevelsArgsForCall, struct {
+		arg1 map[string]string
+		arg2 time.Duration
+	}{arg1, arg2})
+	fake.recordInvocation("ActivateLevels", []interface{}{arg1, arg2})
+	fake.activateLevelsMutex.Unlock()
+	if fake.ActivateLevelsStub != nil {
+		return fake.ActivateLevelsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.activateLevelsReturns
+	return fakeReturns.result1
+}
+
+func (fake *Logging) ActivateLevelsCallCount() int {
+	fake.activateLevelsMutex.RLock()
+	defer fake.activateLevelsMutex.RUnlock()
+	return len(fake.activateLevelsArgsForCall)
+}
+
+func (fake *Logging) ActivateLevelsCalls(stub func(map[string]string, time.Duration) error) {
+	fake.activateLevelsMutex.Lock()
+	defer fake.activateLevelsMutex.Unlock()
+	fake.ActivateLevelsStub = stub
+}
+
+func (fake *Logging) ActivateLevelsArgsForCall(i int) (map[string]string, time.Duration) {
+	fake.activateLevelsMutex.RLock()
+	defer fake.activateLevelsMutex.RUnlock()
+	argsForCall := fake.activateLevelsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Logging) ActivateLevelsReturns(result1 error) {
+	fake.activateLevelsMutex.Lock()
+	defer fake.activateLevelsMutex.Unlock()
+	fake.ActivateLevelsStub = nil
+	fake.activateLevelsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Logging) ActivateLevelsReturnsOnCall(i int, result1 error) {
+	fake.activateLevelsMutex.Lock()
+	defer fake.activateLevelsMutex.Unlock()
+	fake.ActivateLevelsStub = nil
+	if fake.activateLevelsReturnsOnCall == nil {
+		fake.activateLevelsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.activateLevelsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Logging) ActivateSpec(arg1 string) error {
 	fake.activateSpecMutex.Lock()
 	ret, specificReturn := fake.activateSpecReturnsOnCall[len(fake.activateSpecArgsForCall)]
@@ -93,6 +201,179 @@ func (fake *Logging) ActivateSpecReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Logging) ActivateSpecWithTTL(arg1 string, arg2 time.Duration) error {
+	fake.activateSpecWithTTLMutex.Lock()
+	ret, specificReturn := fake.activateSpecWithTTLReturnsOnCall[len(fake.activateSpecWithTTLArgsForCall)]
+	fake.activateSpecWithTTLArgsForCall = append(fake.activateSpecWithTTLArgsForCall, struct {
+		arg1 string
+		arg2 time.Duration
+	}{arg1, arg2})
+	fake.recordInvocation("ActivateSpecWithTTL", []interface{}{arg1, arg2})
+	fake.activateSpecWithTTLMutex.Unlock()
+	if fake.ActivateSpecWithTTLStub != nil {
+		return fake.ActivateSpecWithTTLStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.activateSpecWithTTLReturns
+	return fakeReturns.result1
+}
+
+func (fake *Logging) ActivateSpecWithTTLCallCount() int {
+	fake.activateSpecWithTTLMutex.RLock()
+	defer fake.activateSpecWithTTLMutex.RUnlock()
+	return len(fake.activateSpecWithTTLArgsForCall)
+}
+
+func (fake *Logging) ActivateSpecWithTTLCalls(stub func(string, time.Duration) error) {
+	fake.activateSpecWithTTLMutex.Lock()
+	defer fake.activateSpecWithTTLMutex.Unlock()
+	fake.ActivateSpecWithTTLStub = stub
+}
+
+func (fake *Logging) ActivateSpecWithTTLArgsForCall(i int) (string, time.Duration) {
+	fake.activateSpecWithTTLMutex.RLock()
+	defer fake.activateSpecWithTTLMutex.RUnlock()
+	argsForCall := fake.activateSpecWithTTLArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Logging) ActivateSpecWithTTLReturns(result1 error) {
+	fake.activateSpecWithTTLMutex.Lock()
+	defer fake.activateSpecWithTTLMutex.Unlock()
+	fake.ActivateSpecWithTTLStub = nil
+	fake.activateSpecWithTTLReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Logging) ActivateSpecWithTTLReturnsOnCall(i int, result1 error) {
+	fake.activateSpecWithTTLMutex.Lock()
+	defer fake.activateSpecWithTTLMutex.Unlock()
+	fake.ActivateSpecWithTTLStub = nil
+	if fake.activateSpecWithTTLReturnsOnCall == nil {
+		fake.activateSpecWithTTLReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.activateSpecWithTTLReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Logging) Encoding() flogging.Encoding {
+	fake.encodingMutex.Lock()
+	ret, specificReturn := fake.encodingReturnsOnCall[len(fake.encodingArgsForCall)]
+	fake.encodingArgsForCall = append(fake.encodingArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Encoding", []interface{}{})
+	fake.encodingMutex.Unlock()
+	if fake.EncodingStub != nil {
+		return fake.EncodingStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.encodingReturns
+	return fakeReturns.result1
+}
+
+func (fake *Logging) EncodingCallCount() int {
+	fake.encodingMutex.RLock()
+	defer fake.encodingMutex.RUnlock()
+	return len(fake.encodingArgsForCall)
+}
+
+func (fake *Logging) EncodingCalls(stub func() flogging.Encoding) {
+	fake.encodingMutex.Lock()
+	defer fake.encodingMutex.Unlock()
+	fake.EncodingStub = stub
+}
+
+func (fake *Logging) EncodingReturns(result1 flogging.Encoding) {
+	fake.encodingMutex.Lock()
+	defer fake.encodingMutex.Unlock()
+	fake.EncodingStub = nil
+	fake.encodingReturns = struct {
+		result1 flogging.Encoding
+	}{result1}
+}
+
+func (fake *Logging) EncodingReturnsOnCall(i int, result1 flogging.Encoding) {
+	fake.encodingMutex.Lock()
+	defer fake.encodingMutex.Unlock()
+	fake.EncodingStub = nil
+	if fake.encodingReturnsOnCall == nil {
+		fake.encodingReturnsOnCall = make(map[int]struct {
+			result1 flogging.Encoding
+		})
+	}
+	fake.encodingReturnsOnCall[i] = struct {
+		result1 flogging.Encoding
+	}{result1}
+}
+
+func (fake *Logging) SetJSONOutput(arg1 bool) error {
+	fake.setJSONOutputMutex.Lock()
+	ret, specificReturn := fake.setJSONOutputReturnsOnCall[len(fake.setJSONOutputArgsForCall)]
+	fake.setJSONOutputArgsForCall = append(fake.setJSONOutputArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	fake.recordInvocation("SetJSONOutput", []interface{}{arg1})
+	fake.setJSONOutputMutex.Unlock()
+	if fake.SetJSONOutputStub != nil {
+		return fake.SetJSONOutputStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.setJSONOutputReturns
+	return fakeReturns.result1
+}
+
+func (fake *Logging) SetJSONOutputCallCount() int {
+	fake.setJSONOutputMutex.RLock()
+	defer fake.setJSONOutputMutex.RUnlock()
+	return len(fake.setJSONOutputArgsForCall)
+}
+
+func (fake *Logging) SetJSONOutputCalls(stub func(bool) error) {
+	fake.setJSONOutputMutex.Lock()
+	defer fake.setJSONOutputMutex.Unlock()
+	fake.SetJSONOutputStub = stub
+}
+
+func (fake *Logging) SetJSONOutputArgsForCall(i int) bool {
+	fake.setJSONOutputMutex.RLock()
+	defer fake.setJSONOutputMutex.RUnlock()
+	argsForCall := fake.setJSONOutputArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Logging) SetJSONOutputReturns(result1 error) {
+	fake.setJSONOutputMutex.Lock()
+	defer fake.setJSONOutputMutex.Unlock()
+	fake.SetJSONOutputStub = nil
+	fake.setJSONOutputReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Logging) SetJSONOutputReturnsOnCall(i int, result1 error) {
+	fake.setJSONOutputMutex.Lock()
+	defer fake.setJSONOutputMutex.Unlock()
+	fake.SetJSONOutputStub = nil
+	if fake.setJSONOutputReturnsOnCall == nil {
+		fake.setJSONOutputReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setJSONOutputReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Logging) Spec() string {
 	fake.specMutex.Lock()
 	ret, specificReturn := fake.specReturnsOnCall[len(fake.specArgsForCall)]
@@ -148,8 +429,16 @@ func (fake *Logging) SpecReturnsOnCall(i int, result1 string) {
 func (fake *Logging) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.activateLevelsMutex.RLock()
+	defer fake.activateLevelsMutex.RUnlock()
 	fake.activateSpecMutex.RLock()
 	defer fake.activateSpecMutex.RUnlock()
+	fake.activateSpecWithTTLMutex.RLock()
+	defer fake.activateSpecWithTTLMutex.RUnlock()
+	fake.encodingMutex.RLock()
+	defer fake.encodingMutex.RUnlock()
+	fake.setJSONOutputMutex.RLock()
+	defer fake.setJSONOutputMutex.RUnlock()
 	fake.specMutex.RLock()
 	defer fake.specMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+//go:generate counterfeiter -o fakes/sinks.go -fake-name Sinks . Sinks
+
+type Sinks interface {
+	AddSink(c flogging.SinkConfig) error
+	RemoveSink(name string) error
+	Sinks() []flogging.SinkStatus
+}
+
+// SinkList is the payload returned when listing the log sinks.
+type SinkList struct {
+	Sinks []flogging.SinkStatus `json:"sinks"`
+}
+
+func NewSinksHandler(allowList flogging.SinkAllowList) *SinksHandler {
+	return &SinksHandler{
+		Sinks:     flogging.Global,
+		AllowList: allowList,
+		Logger:    flogging.MustGetLogger("flogging.httpadmin"),
+	}
+}
+
+// SinksHandler lists, adds and removes the sinks log records are written to.
+// A sink is added with a PUT of its configuration and removed with a DELETE
+// naming it in the name query parameter. Only sinks whose destination is
+// permitted by the allow list may be added.
+type SinksHandler struct {
+	Sinks     Sinks
+	AllowList flogging.SinkAllowList
+	Logger    *flogging.FabricLogger
+}
+
+func (h *SinksHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPut:
+		var sinkConfig flogging.SinkConfig
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&sinkConfig); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		req.Body.Close()
+
+		if err := h.AllowList.Permits(sinkConfig); err != nil {
+			sendResponse(h.Logger, resp, http.StatusForbidden, err)
+			return
+		}
+		if err := h.Sinks.AddSink(sinkConfig); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		name := req.URL.Query().Get("name")
+		if name == "" {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, errors.New("sink name must be provided"))
+			return
+		}
+		if err := h.Sinks.RemoveSink(name); err != nil {
+			sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+			return
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet:
+		sendResponse(h.Logger, resp, http.StatusOK, &SinkList{Sinks: h.Sinks.Sinks()})
+
+	default:
+		err := fmt.Errorf("invalid request method: %s", req.Method)
+		sendResponse(h.Logger, resp, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/flogging/httpadmin"
+	"github.com/hyperledger/fabric/common/flogging/httpadmin/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SinksHandler", func() {
+	var (
+		fakeSinks *fakes.Sinks
+		handler   *httpadmin.SinksHandler
+	)
+
+	BeforeEach(func() {
+		fakeSinks = &fakes.Sinks{}
+		fakeSinks.SinksReturns([]flogging.SinkStatus{
+			{SinkConfig: flogging.SinkConfig{Name: "local", Type: flogging.FileSink, Path: "/var/log/peer.log"}, Dropped: 3},
+		})
+		handler = &httpadmin.SinksHandler{
+			Sinks:     fakeSinks,
+			AllowList: flogging.SinkAllowList{Hosts: []string{"collector"}},
+		}
+	})
+
+	It("responds with the active sinks", func() {
+		req := httptest.NewRequest("GET", "/ignored", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(fakeSinks.SinksCallCount()).To(Equal(1))
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"sinks": [{"name": "local", "type": "file", "path": "/var/log/peer.log", "dropped": 3}]}`))
+		Expect(resp.Result().Header.Get("Content-Type")).To(Equal("application/json"))
+	})
+
+	It("adds a sink", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"name": "collector", "type": "remote", "address": "collector:5170"}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeSinks.AddSinkCallCount()).To(Equal(1))
+		Expect(fakeSinks.AddSinkArgsForCall(0)).To(Equal(flogging.SinkConfig{
+			Name:    "collector",
+			Type:    flogging.RemoteSink,
+			Address: "collector:5170",
+		}))
+	})
+
+	It("removes a sink", func() {
+		req := httptest.NewRequest("DELETE", "/ignored?name=local", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeSinks.RemoveSinkCallCount()).To(Equal(1))
+		Expect(fakeSinks.RemoveSinkArgsForCall(0)).To(Equal("local"))
+	})
+
+	Context("when the sink payload cannot be decoded", func() {
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`goo`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeSinks.AddSinkCallCount()).To(Equal(0))
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid character 'g' looking for beginning of value"}`))
+		})
+	})
+
+	Context("when the destination of the sink is not allowed", func() {
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"name": "collector", "type": "remote", "address": "elsewhere:5170"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeSinks.AddSinkCallCount()).To(Equal(0))
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusForbidden))
+			Expect(resp.Body).To(MatchJSON(`{"error": "host elsewhere of sink collector is not allowed"}`))
+		})
+	})
+
+	Context("when adding the sink fails", func() {
+		BeforeEach(func() {
+			fakeSinks.AddSinkReturns(errors.New("no route to collector"))
+		})
+
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"name": "collector", "type": "remote", "address": "collector:5170"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "no route to collector"}`))
+		})
+	})
+
+	Context("when the sink to remove is not named", func() {
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("DELETE", "/ignored", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(fakeSinks.RemoveSinkCallCount()).To(Equal(0))
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "sink name must be provided"}`))
+		})
+	})
+
+	Context("when removing the sink fails", func() {
+		BeforeEach(func() {
+			fakeSinks.RemoveSinkReturns(errors.New("sink local does not exist"))
+		})
+
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("DELETE", "/ignored?name=local", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "sink local does not exist"}`))
+		})
+	})
+
+	Context("when an unsupported method is used", func() {
+		It("responds with an error", func() {
+			req := httptest.NewRequest("POST", "/ignored", strings.NewReader(`{}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "invalid request method: POST"}`))
+			Expect(fakeSinks.Invocations()).To(BeEmpty())
+		})
+	})
+
+	Describe("NewSinksHandler", func() {
+		It("constructs a handler that modifies the global sinks", func() {
+			allowList := flogging.SinkAllowList{Directories: []string{"/var/log"}}
+			sinksHandler := httpadmin.NewSinksHandler(allowList)
+			Expect(sinksHandler.Sinks).To(Equal(flogging.Global))
+			Expect(sinksHandler.AllowList).To(Equal(allowList))
+			Expect(sinksHandler.Logger).NotTo(BeNil())
+		})
+	})
+})
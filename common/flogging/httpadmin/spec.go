@@ -10,19 +10,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
 )
 
 //go:generate counterfeiter -o fakes/logging.go -fake-name Logging . Logging
 
 type Logging interface {
 	ActivateSpec(spec string) error
+	ActivateSpecWithTTL(spec string, ttl time.Duration) error
+	ActivateLevels(levels map[string]string, ttl time.Duration) error
 	Spec() string
+	SetJSONOutput(enabled bool) error
+	Encoding() flogging.Encoding
 }
 
+// LogSpec is the payload of the logspec endpoint.
+//
+// Spec replaces the whole logging spec while Levels only changes the level
+// of the named loggers. When TTL is set, the previous levels are restored
+// once it has elapsed. JSON toggles structured JSON output.
 type LogSpec struct {
-	Spec string `json:"spec,omitempty"`
+	Spec   string            `json:"spec,omitempty"`
+	Levels map[string]string `json:"levels,omitempty"`
+	TTL    string            `json:"ttl,omitempty"`
+	JSON   *bool             `json:"json,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -52,14 +66,19 @@ func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		}
 		req.Body.Close()
 
-		if err := h.Logging.ActivateSpec(logSpec.Spec); err != nil {
+		if err := h.update(&logSpec); err != nil {
 			h.sendResponse(resp, http.StatusBadRequest, err)
 			return
 		}
 		resp.WriteHeader(http.StatusNoContent)
 
 	case http.MethodGet:
-		h.sendResponse(resp, http.StatusOK, &LogSpec{Spec: h.Logging.Spec()})
+		logSpec := &LogSpec{Spec: h.Logging.Spec()}
+		if h.Logging.Encoding() == flogging.JSON {
+			jsonOutput := true
+			logSpec.JSON = &jsonOutput
+		}
+		h.sendResponse(resp, http.StatusOK, logSpec)
 
 	default:
 		err := fmt.Errorf("invalid request method: %s", req.Method)
@@ -67,7 +86,49 @@ func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (h *SpecHandler) update(logSpec *LogSpec) error {
+	var ttl time.Duration
+	if logSpec.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(logSpec.TTL)
+		if err != nil {
+			return errors.Wrap(err, "invalid ttl")
+		}
+	}
+
+	if logSpec.Spec != "" && len(logSpec.Levels) != 0 {
+		return errors.New("spec and levels cannot both be provided")
+	}
+
+	// a request that only toggles JSON output leaves the levels unchanged
+	if logSpec.JSON != nil {
+		jsonOnly := logSpec.Spec == "" && len(logSpec.Levels) == 0
+		if jsonOnly && ttl != 0 {
+			return errors.New("ttl requires spec or levels")
+		}
+		if err := h.Logging.SetJSONOutput(*logSpec.JSON); err != nil {
+			return err
+		}
+		if jsonOnly {
+			return nil
+		}
+	}
+
+	switch {
+	case len(logSpec.Levels) != 0:
+		return h.Logging.ActivateLevels(logSpec.Levels, ttl)
+	case ttl != 0:
+		return h.Logging.ActivateSpecWithTTL(logSpec.Spec, ttl)
+	default:
+		return h.Logging.ActivateSpec(logSpec.Spec)
+	}
+}
+
 func (h *SpecHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	sendResponse(h.Logger, resp, code, payload)
+}
+
+func sendResponse(logger *flogging.FabricLogger, resp http.ResponseWriter, code int, payload interface{}) {
 	encoder := json.NewEncoder(resp)
 	if err, ok := payload.(error); ok {
 		payload = &ErrorResponse{Error: err.Error()}
@@ -77,6 +138,6 @@ func (h *SpecHandler) sendResponse(resp http.ResponseWriter, code int, payload i
 	resp.WriteHeader(code)
 
 	if err := encoder.Encode(payload); err != nil {
-		h.Logger.Errorw("failed to encode payload", "error", err)
+		logger.Errorw("failed to encode payload", "error", err)
 	}
 }
@@ -8,14 +8,17 @@ package httpadmin_test
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/flogging/httpadmin"
 	"github.com/hyperledger/fabric/common/flogging/httpadmin/fakes"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -81,6 +84,86 @@ var _ = Describe("SpecHandler", func() {
 		})
 	})
 
+	Context("when JSON output is enabled", func() {
+		BeforeEach(func() {
+			fakeLogging.EncodingReturns(flogging.JSON)
+		})
+
+		It("includes it in the current logging spec", func() {
+			req := httptest.NewRequest("GET", "/ignored", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Body).To(MatchJSON(`{"spec": "the-returned-specification", "json": true}`))
+		})
+	})
+
+	It("sets the logging spec for a limited time", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"spec": "updated-spec", "ttl": "5m"}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(0))
+		Expect(fakeLogging.ActivateSpecWithTTLCallCount()).To(Equal(1))
+		spec, ttl := fakeLogging.ActivateSpecWithTTLArgsForCall(0)
+		Expect(spec).To(Equal("updated-spec"))
+		Expect(ttl).To(Equal(5 * time.Minute))
+	})
+
+	It("sets the level of individual loggers", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"levels": {"gossip": "debug"}, "ttl": "1m"}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(0))
+		Expect(fakeLogging.ActivateLevelsCallCount()).To(Equal(1))
+		levels, ttl := fakeLogging.ActivateLevelsArgsForCall(0)
+		Expect(levels).To(Equal(map[string]string{"gossip": "debug"}))
+		Expect(ttl).To(Equal(time.Minute))
+	})
+
+	It("toggles JSON output without changing the spec", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"json": true}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.SetJSONOutputCallCount()).To(Equal(1))
+		Expect(fakeLogging.SetJSONOutputArgsForCall(0)).To(BeTrue())
+		Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(0))
+	})
+
+	It("toggles JSON output and sets the spec", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"json": false, "spec": "updated-spec"}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.SetJSONOutputCallCount()).To(Equal(1))
+		Expect(fakeLogging.SetJSONOutputArgsForCall(0)).To(BeFalse())
+		Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(1))
+		Expect(fakeLogging.ActivateSpecArgsForCall(0)).To(Equal("updated-spec"))
+	})
+
+	DescribeTable("invalid update payloads",
+		func(payload, expectedErr string) {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(payload))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(fmt.Sprintf(`{"error": %q}`, expectedErr)))
+			Expect(fakeLogging.Invocations()).NotTo(HaveKey("ActivateSpec"))
+			Expect(fakeLogging.Invocations()).NotTo(HaveKey("SetJSONOutput"))
+		},
+		Entry("bad ttl", `{"spec": "debug", "ttl": "soon"}`, `invalid ttl: time: invalid duration "soon"`),
+		Entry("spec and levels", `{"spec": "debug", "levels": {"gossip": "info"}}`, "spec and levels cannot both be provided"),
+		Entry("ttl with json only", `{"json": true, "ttl": "1m"}`, "ttl requires spec or levels"),
+	)
+
 	Context("when an unsupported method is used", func() {
 		It("responds with an error", func() {
 			req := httptest.NewRequest("POST", "/ignored", strings.NewReader(`{}`))
@@ -10,7 +10,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging/fabenc"
 	zaplogfmt "github.com/sykesm/zap-logfmt"
@@ -53,6 +56,18 @@ type Logging struct {
 	multiFormatter *fabenc.MultiFormatter
 	writer         zapcore.WriteSyncer
 	observer       Observer
+	format         string
+	consoleFormat  string
+	sinks          []*sink
+	pendingRevert  *specRevert
+}
+
+// specRevert tracks a logging spec that was activated for a limited time
+// and the spec to restore once that time has elapsed.
+type specRevert struct {
+	timer        *time.Timer
+	activeSpec   string
+	previousSpec string
 }
 
 // New creates a new logging system and initializes it with the provided
@@ -116,11 +131,14 @@ func (l *Logging) SetFormat(format string) error {
 
 	if format == "json" {
 		l.encoding = JSON
+		l.format = format
 		return nil
 	}
 
 	if format == "logfmt" {
 		l.encoding = LOGFMT
+		l.format = format
+		l.consoleFormat = format
 		return nil
 	}
 
@@ -130,10 +148,125 @@ func (l *Logging) SetFormat(format string) error {
 	}
 	l.multiFormatter.SetFormatters(formatters)
 	l.encoding = CONSOLE
+	l.format = format
+	l.consoleFormat = format
+
+	return nil
+}
+
+// Format returns the active log record format specification.
+func (l *Logging) Format() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.format
+}
+
+// SetJSONOutput toggles structured JSON output. When JSON output is
+// disabled, the last format that was not JSON is restored.
+func (l *Logging) SetJSONOutput(enabled bool) error {
+	if enabled {
+		return l.SetFormat("json")
+	}
+
+	l.mutex.RLock()
+	format := l.consoleFormat
+	l.mutex.RUnlock()
+	return l.SetFormat(format)
+}
+
+// ActivateSpecWithTTL activates a logging spec for the provided duration.
+// Once the duration has elapsed, the spec that was active before the call is
+// restored unless the spec has been changed again in the meantime. A ttl of
+// zero activates the spec permanently.
+func (l *Logging) ActivateSpecWithTTL(spec string, ttl time.Duration) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.activateSpecWithTTL(spec, ttl)
+}
+
+// ActivateSpec activates a logging spec permanently. It replaces a spec
+// activated for a limited duration, which is then not restored.
+func (l *Logging) ActivateSpec(spec string) error {
+	return l.ActivateSpecWithTTL(spec, 0)
+}
+
+// activateSpecWithTTL must be called with the mutex held
+func (l *Logging) activateSpecWithTTL(spec string, ttl time.Duration) error {
+	previousSpec := l.LoggerLevels.Spec()
+	if l.pendingRevert != nil {
+		l.pendingRevert.timer.Stop()
+		if l.pendingRevert.activeSpec == previousSpec {
+			previousSpec = l.pendingRevert.previousSpec
+		}
+		l.pendingRevert = nil
+	}
+
+	if err := l.LoggerLevels.ActivateSpec(spec); err != nil {
+		return err
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
+	revert := &specRevert{
+		activeSpec:   l.LoggerLevels.Spec(),
+		previousSpec: previousSpec,
+	}
+	revert.timer = time.AfterFunc(ttl, func() { l.revertSpec(revert) })
+	l.pendingRevert = revert
 
 	return nil
 }
 
+func (l *Logging) revertSpec(revert *specRevert) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.pendingRevert != revert {
+		return
+	}
+	l.pendingRevert = nil
+
+	// the spec was replaced by a call to ActivateSpec
+	if l.LoggerLevels.Spec() != revert.activeSpec {
+		return
+	}
+	if err := l.LoggerLevels.ActivateSpec(revert.previousSpec); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to restore logging spec '%s': %s\n", revert.previousSpec, err)
+	}
+}
+
+// ActivateLevels sets the logging level of the named loggers, leaving the
+// level of the other loggers unchanged. When ttl is not zero, the previous
+// levels are restored once it has elapsed, as with ActivateSpecWithTTL.
+func (l *Logging) ActivateLevels(levels map[string]string, ttl time.Duration) error {
+	// the lock is held from reading the active spec to activating the merged
+	// spec, so that concurrent calls don't drop each other's levels
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	fields := strings.Split(l.LoggerLevels.Spec(), ":")
+	defaultLevel := fields[len(fields)-1]
+
+	merged := map[string]string{}
+	for _, field := range fields[:len(fields)-1] {
+		split := strings.SplitN(field, "=", 2)
+		merged[split[0]] = split[1]
+	}
+	for logger, level := range levels {
+		merged[logger] = level
+	}
+
+	var spec []string
+	for logger, level := range merged {
+		spec = append(spec, fmt.Sprintf("%s=%s", logger, level))
+	}
+	sort.Strings(spec)
+	spec = append(spec, defaultLevel)
+
+	return l.activateSpecWithTTL(strings.Join(spec, ":"), ttl)
+}
+
 // SetWriter controls which writer formatted log records are written to.
 // Writers, with the exception of an *os.File, need to be safe for concurrent
 // use by multiple go routines.
@@ -173,8 +306,12 @@ func (l *Logging) SetObserver(observer Observer) Observer {
 func (l *Logging) Write(b []byte) (int, error) {
 	l.mutex.RLock()
 	w := l.writer
+	sinks := l.sinks
 	l.mutex.RUnlock()
 
+	for _, s := range sinks {
+		s.write(b)
+	}
 	return w.Write(b)
 }
 
@@ -11,7 +11,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/flogging/mock"
@@ -156,3 +158,110 @@ func TestLoggerCoreCheck(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, logger.Core().Enabled(zapcore.DebugLevel), "debug should now be enabled at debug level")
 }
+
+func TestActivateSpecWithTTL(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "gossip=warn:info"})
+	assert.NoError(t, err)
+
+	err = logging.ActivateSpecWithTTL("debug", 100*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", logging.Spec())
+	assert.Eventually(t, func() bool { return logging.Spec() == "gossip=warn:info" }, time.Second, 10*time.Millisecond)
+
+	err = logging.ActivateSpecWithTTL("::=borken=::", time.Minute)
+	assert.EqualError(t, err, "invalid logging specification '::=borken=::': bad segment '=borken='")
+	assert.Equal(t, "gossip=warn:info", logging.Spec())
+}
+
+func TestActivateSpecWithTTLStacked(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "info"})
+	assert.NoError(t, err)
+
+	err = logging.ActivateSpecWithTTL("debug", time.Minute)
+	assert.NoError(t, err)
+	err = logging.ActivateSpecWithTTL("warn", 100*time.Millisecond)
+	assert.NoError(t, err)
+
+	// the spec preceding the first temporary spec is restored
+	assert.Eventually(t, func() bool { return logging.Spec() == "info" }, time.Second, 10*time.Millisecond)
+}
+
+func TestActivateSpecWithTTLReplaced(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "info"})
+	assert.NoError(t, err)
+
+	err = logging.ActivateSpecWithTTL("debug", 100*time.Millisecond)
+	assert.NoError(t, err)
+	err = logging.ActivateSpec("error")
+	assert.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, "error", logging.Spec())
+}
+
+func TestActivateLevels(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "gossip=warn:chaincode=error:info"})
+	assert.NoError(t, err)
+
+	err = logging.ActivateLevels(map[string]string{"gossip": "debug", "ledger": "debug"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "chaincode=error:gossip=debug:ledger=debug:info", logging.Spec())
+	assert.Equal(t, zapcore.DebugLevel, logging.Level("gossip.discovery"))
+	assert.Equal(t, zapcore.InfoLevel, logging.Level("peer"))
+
+	err = logging.ActivateLevels(map[string]string{"gossip": "info"}, 100*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, "chaincode=error:gossip=info:ledger=debug:info", logging.Spec())
+	assert.Eventually(t, func() bool { return logging.Spec() == "chaincode=error:gossip=debug:ledger=debug:info" }, time.Second, 10*time.Millisecond)
+
+	err = logging.ActivateLevels(map[string]string{"gossip": "noisy"}, 0)
+	assert.EqualError(t, err, "invalid logging specification 'chaincode=error:gossip=noisy:ledger=debug:info': bad segment 'gossip=noisy'")
+}
+
+func TestActivateLevelsConcurrently(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{LogSpec: "info"})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			err := logging.ActivateLevels(map[string]string{fmt.Sprintf("logger%02d", i): "debug"}, 0)
+			assert.NoError(t, err)
+		}(i)
+		go func() {
+			defer wg.Done()
+			logging.Spec()
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, zapcore.DebugLevel, logging.Level(fmt.Sprintf("logger%02d", i)))
+	}
+}
+
+func TestSetJSONOutput(t *testing.T) {
+	logging, err := flogging.New(flogging.Config{Format: "%{message}"})
+	assert.NoError(t, err)
+	assert.Equal(t, flogging.Encoding(flogging.CONSOLE), logging.Encoding())
+
+	err = logging.SetJSONOutput(true)
+	assert.NoError(t, err)
+	assert.Equal(t, flogging.Encoding(flogging.JSON), logging.Encoding())
+	assert.Equal(t, "json", logging.Format())
+
+	err = logging.SetJSONOutput(false)
+	assert.NoError(t, err)
+	assert.Equal(t, flogging.Encoding(flogging.CONSOLE), logging.Encoding())
+	assert.Equal(t, "%{message}", logging.Format())
+
+	err = logging.SetFormat("logfmt")
+	assert.NoError(t, err)
+	err = logging.SetJSONOutput(true)
+	assert.NoError(t, err)
+	err = logging.SetJSONOutput(false)
+	assert.NoError(t, err)
+	assert.Equal(t, flogging.Encoding(flogging.LOGFMT), logging.Encoding())
+}
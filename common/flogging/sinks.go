@@ -0,0 +1,335 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkType identifies the kind of destination of a log sink.
+type SinkType string
+
+const (
+	// FileSink appends log records to a file.
+	FileSink SinkType = "file"
+	// SyslogSink sends log records to a syslog daemon.
+	SyslogSink SinkType = "syslog"
+	// RemoteSink streams log records to a remote collector over a network
+	// connection.
+	RemoteSink SinkType = "remote"
+)
+
+const (
+	// remoteSinkDialTimeout bounds the time spent connecting to a remote
+	// collector when a remote sink is added.
+	remoteSinkDialTimeout = 5 * time.Second
+
+	// sinkQueueSize is the number of log records buffered for a sink. Records
+	// written while the queue of a sink is full are dropped, so that a slow
+	// sink never blocks the loggers.
+	sinkQueueSize = 1024
+
+	// sinkCloseTimeout bounds the time spent flushing the queue of a sink
+	// when it is removed.
+	sinkCloseTimeout = 5 * time.Second
+)
+
+// SinkConfig describes a destination that log records are written to in
+// addition to the writer of the logging system.
+type SinkConfig struct {
+	// Name uniquely identifies the sink.
+	Name string `json:"name"`
+
+	// Type is the kind of destination of the sink.
+	Type SinkType `json:"type"`
+
+	// Path is the path of the file log records are appended to by a file
+	// sink.
+	Path string `json:"path,omitempty"`
+
+	// Network is the network used to reach a syslog daemon or remote
+	// collector, such as "tcp" or "udp". A syslog sink without network and
+	// address uses the local syslog daemon, and a remote sink defaults to tcp.
+	Network string `json:"network,omitempty"`
+
+	// Address is the address of a syslog daemon or remote collector.
+	Address string `json:"address,omitempty"`
+
+	// Tag is the tag of the records sent by a syslog sink. It defaults to the
+	// name of the process.
+	Tag string `json:"tag,omitempty"`
+}
+
+// SinkStatus is the configuration of an active sink and the number of log
+// records it dropped because its destination could not keep up.
+type SinkStatus struct {
+	SinkConfig
+	Dropped uint64 `json:"dropped"`
+}
+
+// SinkAllowList restricts the destinations of the sinks which may be added.
+// Nothing is allowed by the zero value.
+type SinkAllowList struct {
+	// Directories are the directories, along with their subdirectories, that
+	// file sinks may write to.
+	Directories []string
+
+	// Hosts are the hosts that syslog and remote sinks may connect to.
+	Hosts []string
+}
+
+// Permits returns an error unless the destination of the sink is allowed.
+// The local syslog daemon is always allowed.
+func (a SinkAllowList) Permits(c SinkConfig) error {
+	switch c.Type {
+	case FileSink:
+		return a.permitsFile(c)
+	case SyslogSink:
+		if c.Network == "" && c.Address == "" {
+			return nil
+		}
+		return a.permitsHost(c)
+	case RemoteSink:
+		return a.permitsHost(c)
+	default:
+		return errors.Errorf("sink %s has unknown type '%s'", c.Name, c.Type)
+	}
+}
+
+func (a SinkAllowList) permitsFile(c SinkConfig) error {
+	if !filepath.IsAbs(c.Path) {
+		return errors.Errorf("path of file sink %s must be absolute", c.Name)
+	}
+	// symbolic links are resolved so that they cannot lead out of the
+	// allowed directories
+	dir, err := filepath.EvalSymlinks(filepath.Dir(c.Path))
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve the directory of file sink %s", c.Name)
+	}
+	if fi, err := os.Lstat(c.Path); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		return errors.Errorf("path of file sink %s is a symbolic link", c.Name)
+	}
+
+	for _, allowed := range a.Directories {
+		allowed, err := filepath.EvalSymlinks(allowed)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(allowed, dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return errors.Errorf("file sink %s is not in an allowed directory", c.Name)
+}
+
+func (a SinkAllowList) permitsHost(c SinkConfig) error {
+	host, _, err := net.SplitHostPort(c.Address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid address of sink %s", c.Name)
+	}
+	for _, allowed := range a.Hosts {
+		if strings.EqualFold(host, allowed) {
+			return nil
+		}
+	}
+	return errors.Errorf("host %s of sink %s is not allowed", host, c.Name)
+}
+
+// sink writes log records to its destination from a dedicated goroutine.
+type sink struct {
+	config  SinkConfig
+	writer  zapcore.WriteSyncer
+	closer  io.Closer
+	queue   chan []byte
+	done    chan struct{}
+	dropped uint64
+
+	// closing is read locked while records are enqueued, so that the queue
+	// is not closed under a writer
+	closing sync.RWMutex
+	closed  bool
+}
+
+func newSink(c SinkConfig, writer zapcore.WriteSyncer, closer io.Closer) *sink {
+	s := &sink{
+		config: c,
+		writer: writer,
+		closer: closer,
+		queue:  make(chan []byte, sinkQueueSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *sink) run() {
+	defer close(s.done)
+	for b := range s.queue {
+		s.writer.Write(b)
+	}
+	s.writer.Sync()
+}
+
+// write enqueues a log record, dropping it when the queue is full.
+func (s *sink) write(b []byte) {
+	s.closing.RLock()
+	defer s.closing.RUnlock()
+	if s.closed {
+		return
+	}
+
+	// the buffer is reused by the encoder once the write returns
+	record := make([]byte, len(b))
+	copy(record, b)
+	select {
+	case s.queue <- record:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// close flushes the queued log records and closes the destination.
+func (s *sink) close() error {
+	s.closing.Lock()
+	s.closed = true
+	close(s.queue)
+	s.closing.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(sinkCloseTimeout):
+		// the records still queued are abandoned along with the
+		// destination, which unblocks the goroutine
+	}
+	return s.closer.Close()
+}
+
+func (s *sink) status() SinkStatus {
+	return SinkStatus{SinkConfig: s.config, Dropped: atomic.LoadUint64(&s.dropped)}
+}
+
+func openSink(c SinkConfig) (*sink, error) {
+	var wc io.WriteCloser
+	var err error
+	switch c.Type {
+	case FileSink:
+		if c.Path == "" {
+			return nil, errors.Errorf("file sink %s has no path", c.Name)
+		}
+		var f *os.File
+		f, err = os.OpenFile(c.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open log file of sink %s", c.Name)
+		}
+		return newSink(c, f, f), nil
+
+	case SyslogSink:
+		wc, err = openSyslog(c)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to connect sink %s to syslog", c.Name)
+		}
+
+	case RemoteSink:
+		if c.Address == "" {
+			return nil, errors.Errorf("remote sink %s has no address", c.Name)
+		}
+		network := c.Network
+		if network == "" {
+			network = "tcp"
+		}
+		wc, err = net.DialTimeout(network, c.Address, remoteSinkDialTimeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect sink %s to %s", c.Name, c.Address)
+		}
+
+	default:
+		return nil, errors.Errorf("sink %s has unknown type '%s'", c.Name, c.Type)
+	}
+
+	return newSink(c, zapcore.AddSync(wc), wc), nil
+}
+
+// AddSink opens a sink and starts writing log records to it.
+func (l *Logging) AddSink(c SinkConfig) error {
+	if c.Name == "" {
+		return errors.New("sink name must be provided")
+	}
+
+	l.mutex.RLock()
+	for _, s := range l.sinks {
+		if s.config.Name == c.Name {
+			l.mutex.RUnlock()
+			return errors.Errorf("sink %s already exists", c.Name)
+		}
+	}
+	l.mutex.RUnlock()
+
+	s, err := openSink(c)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, existing := range l.sinks {
+		if existing.config.Name == c.Name {
+			s.close()
+			return errors.Errorf("sink %s already exists", c.Name)
+		}
+	}
+	// the slice is replaced rather than appended to as Write iterates over
+	// it without holding the lock
+	sinks := make([]*sink, 0, len(l.sinks)+1)
+	sinks = append(sinks, l.sinks...)
+	l.sinks = append(sinks, s)
+
+	return nil
+}
+
+// RemoveSink stops writing log records to a sink and closes it.
+func (l *Logging) RemoveSink(name string) error {
+	l.mutex.Lock()
+	var removed *sink
+	sinks := make([]*sink, 0, len(l.sinks))
+	for _, s := range l.sinks {
+		if s.config.Name == name {
+			removed = s
+			continue
+		}
+		sinks = append(sinks, s)
+	}
+	l.sinks = sinks
+	l.mutex.Unlock()
+
+	if removed == nil {
+		return errors.Errorf("sink %s does not exist", name)
+	}
+	return errors.Wrapf(removed.close(), "failed to close sink %s", name)
+}
+
+// Sinks returns the status of the active sinks.
+func (l *Logging) Sinks() []SinkStatus {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	statuses := make([]SinkStatus, 0, len(l.sinks))
+	for _, s := range l.sinks {
+		statuses = append(statuses, s.status())
+	}
+	return statuses
+}
@@ -0,0 +1,18 @@
+// +build !windows
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"io"
+	"log/syslog"
+)
+
+func openSyslog(c SinkConfig) (io.WriteCloser, error) {
+	return syslog.Dial(c.Network, c.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, c.Tag)
+}
@@ -0,0 +1,17 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+func openSyslog(c SinkConfig) (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on windows")
+}
@@ -0,0 +1,260 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sinks")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	logFile := filepath.Join(tempDir, "peer.log")
+
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{Format: "%{message}", Writer: buf})
+	require.NoError(t, err)
+
+	err = logging.AddSink(flogging.SinkConfig{Name: "file", Type: flogging.FileSink, Path: logFile})
+	require.NoError(t, err)
+	assert.Equal(t, []flogging.SinkStatus{{SinkConfig: flogging.SinkConfig{Name: "file", Type: flogging.FileSink, Path: logFile}}}, logging.Sinks())
+
+	logger := logging.Logger("test")
+	logger.Info("to both")
+
+	err = logging.RemoveSink("file")
+	require.NoError(t, err)
+	assert.Empty(t, logging.Sinks())
+	logger.Info("to writer only")
+
+	contents, err := ioutil.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "to both\n", string(contents))
+	assert.Equal(t, "to both\nto writer only\n", buf.String())
+}
+
+func TestRemoteSink(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	logging, err := flogging.New(flogging.Config{Format: "json", Writer: ioutil.Discard})
+	require.NoError(t, err)
+	err = logging.AddSink(flogging.SinkConfig{Name: "collector", Type: flogging.RemoteSink, Address: lis.Addr().String()})
+	require.NoError(t, err)
+	defer logging.RemoveSink("collector")
+
+	logging.Logger("test").Info("remote message")
+	assert.Contains(t, <-received, `"msg":"remote message"`)
+}
+
+func TestAddSinkErrors(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sinks")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	logging, err := flogging.New(flogging.Config{Writer: ioutil.Discard})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		config      flogging.SinkConfig
+		expectedErr string
+	}{
+		{
+			name:        "no name",
+			config:      flogging.SinkConfig{Type: flogging.FileSink, Path: filepath.Join(tempDir, "log")},
+			expectedErr: "sink name must be provided",
+		},
+		{
+			name:        "unknown type",
+			config:      flogging.SinkConfig{Name: "sink", Type: "carrier-pigeon"},
+			expectedErr: "sink sink has unknown type 'carrier-pigeon'",
+		},
+		{
+			name:        "file without path",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.FileSink},
+			expectedErr: "file sink sink has no path",
+		},
+		{
+			name:        "remote without address",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.RemoteSink},
+			expectedErr: "remote sink sink has no address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := logging.AddSink(tt.config)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+
+	err = logging.AddSink(flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(tempDir, "log")})
+	require.NoError(t, err)
+	err = logging.AddSink(flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(tempDir, "other")})
+	assert.EqualError(t, err, "sink sink already exists")
+
+	err = logging.RemoveSink("missing")
+	assert.EqualError(t, err, "sink missing does not exist")
+	require.NoError(t, logging.RemoveSink("sink"))
+}
+
+func TestSinkAllowList(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sinks")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	allowedDir := filepath.Join(tempDir, "allowed")
+	require.NoError(t, os.MkdirAll(filepath.Join(allowedDir, "sub"), 0755))
+	require.NoError(t, os.Symlink(tempDir, filepath.Join(allowedDir, "escape")))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(allowedDir, "link.log")))
+
+	allowList := flogging.SinkAllowList{
+		Directories: []string{allowedDir},
+		Hosts:       []string{"collector.example.com"},
+	}
+
+	tests := []struct {
+		name        string
+		config      flogging.SinkConfig
+		expectedErr string
+	}{
+		{
+			name:   "file in allowed directory",
+			config: flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(allowedDir, "peer.log")},
+		},
+		{
+			name:   "file in allowed subdirectory",
+			config: flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(allowedDir, "sub", "peer.log")},
+		},
+		{
+			name:        "file outside allowed directories",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(tempDir, "peer.log")},
+			expectedErr: "file sink sink is not in an allowed directory",
+		},
+		{
+			name:        "file escaping through parent directory",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(allowedDir, "..", "peer.log")},
+			expectedErr: "file sink sink is not in an allowed directory",
+		},
+		{
+			name:        "file escaping through symbolic link",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(allowedDir, "escape", "peer.log")},
+			expectedErr: "file sink sink is not in an allowed directory",
+		},
+		{
+			name:        "file is symbolic link",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(allowedDir, "link.log")},
+			expectedErr: "path of file sink sink is a symbolic link",
+		},
+		{
+			name:        "relative file",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: "peer.log"},
+			expectedErr: "path of file sink sink must be absolute",
+		},
+		{
+			name:   "allowed remote host",
+			config: flogging.SinkConfig{Name: "sink", Type: flogging.RemoteSink, Address: "collector.example.com:5170"},
+		},
+		{
+			name:        "remote host not allowed",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.RemoteSink, Address: "127.0.0.1:5170"},
+			expectedErr: "host 127.0.0.1 of sink sink is not allowed",
+		},
+		{
+			name:   "local syslog",
+			config: flogging.SinkConfig{Name: "sink", Type: flogging.SyslogSink},
+		},
+		{
+			name:        "remote syslog not allowed",
+			config:      flogging.SinkConfig{Name: "sink", Type: flogging.SyslogSink, Network: "udp", Address: "10.0.0.1:514"},
+			expectedErr: "host 10.0.0.1 of sink sink is not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := allowList.Permits(tt.config)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+
+	err = flogging.SinkAllowList{}.Permits(flogging.SinkConfig{Name: "sink", Type: flogging.FileSink, Path: filepath.Join(allowedDir, "peer.log")})
+	assert.EqualError(t, err, "file sink sink is not in an allowed directory")
+}
+
+func TestSlowSinkDoesNotBlock(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	// the collector accepts the connection but never reads from it
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	logging, err := flogging.New(flogging.Config{Format: "%{message}", Writer: ioutil.Discard})
+	require.NoError(t, err)
+	err = logging.AddSink(flogging.SinkConfig{Name: "collector", Type: flogging.RemoteSink, Address: lis.Addr().String()})
+	require.NoError(t, err)
+	conn := <-accepted
+	defer conn.Close()
+
+	logger := logging.Logger("test")
+	record := strings.Repeat("x", 64*1024)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 4096; i++ {
+			logger.Info(record)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("logging blocked on a slow sink")
+	}
+	sinks := logging.Sinks()
+	require.Len(t, sinks, 1)
+	assert.NotZero(t, sinks[0].Dropped)
+
+	conn.Close()
+	require.NoError(t, logging.RemoveSink("collector"))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// BackupSnapshot is a point-in-time view of the block files and of the block index of
+// a ledger, which can be copied to a backup while blocks are added to the store.
+type BackupSnapshot struct {
+	rootDir      string
+	blkfilesInfo blockfilesInfo
+	index        *leveldbhelper.Snapshot
+}
+
+// BackupSnapshot returns a snapshot of the block store for a backup. The caller is
+// expected to ensure that no blocks are added to the store while the snapshot is
+// taken, and to release the snapshot once it is copied.
+func (store *BlockStore) BackupSnapshot() (*BackupSnapshot, error) {
+	mgr := store.fileMgr
+	mgr.blkfilesInfoCond.L.Lock()
+	blkfilesInfo := *mgr.blockfilesInfo
+	mgr.blkfilesInfoCond.L.Unlock()
+	index, err := mgr.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &BackupSnapshot{
+		rootDir:      mgr.rootDir,
+		blkfilesInfo: blkfilesInfo,
+		index:        index,
+	}, nil
+}
+
+// CopyTo copies the block files and the block index of the snapshot to the block
+// storage directory at the given path, which can later be used as the block storage
+// directory of a peer. The block files are only ever appended to, so the latest block
+// file is copied up to the last block of the snapshot and the earlier ones entirely.
+func (s *BackupSnapshot) CopyTo(blockStorageDir string) error {
+	targetConf := NewConf(blockStorageDir, 0)
+	targetDir := targetConf.getLedgerBlockDir(filepath.Base(s.rootDir))
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return errors.Wrapf(err, "error creating dir [%s]", targetDir)
+	}
+	files, err := ioutil.ReadDir(s.rootDir)
+	if err != nil {
+		return errors.Wrapf(err, "error reading dir [%s]", s.rootDir)
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		src := filepath.Join(s.rootDir, file.Name())
+		dst := filepath.Join(targetDir, file.Name())
+		fileNum := -1
+		if isBlockFileName(file.Name()) {
+			if fileNum, err = strconv.Atoi(strings.TrimPrefix(file.Name(), blockfilePrefix)); err != nil {
+				return errors.Wrapf(err, "error parsing the number of block file [%s]", src)
+			}
+		}
+		switch {
+		case fileNum > s.blkfilesInfo.latestFileNumber:
+			// a block file started after the snapshot, it's not part of the backup
+			continue
+		case fileNum == s.blkfilesInfo.latestFileNumber:
+			err = copyFile(src, dst, int64(s.blkfilesInfo.latestFileSize))
+		default:
+			err = copyFile(src, dst, file.Size())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := syncDir(targetDir); err != nil {
+		return err
+	}
+	return s.index.CopyTo(targetConf.getIndexDir())
+}
+
+// Release releases the snapshot of the block index
+func (s *BackupSnapshot) Release() {
+	s.index.Release()
+}
+
+// copyFile copies the first size bytes of the src file to the dst file
+func copyFile(src, dst string, size int64) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "error while opening file:%s", src)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "error while creating file:%s", dst)
+	}
+	if _, err := io.CopyN(out, in, size); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "error while copying file:%s to file:%s", src, dst)
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "error while synching the file:%s", dst)
+	}
+	if err := out.Close(); err != nil {
+		return errors.Wrapf(err, "error while closing the file:%s", dst)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	// a small max block file size spreads the blocks across several block files
+	env := newTestEnv(t, NewConf(testPath(), 20000))
+	defer env.Cleanup()
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	for _, b := range blocks[:15] {
+		require.NoError(t, store.AddBlock(b))
+	}
+
+	backupDir, err := ioutil.TempDir("", "blkstorage-backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(backupDir)
+	snapshot, err := store.BackupSnapshot()
+	require.NoError(t, err)
+
+	// the blocks added after the snapshot are not part of the backup
+	for _, b := range blocks[15:] {
+		require.NoError(t, store.AddBlock(b))
+	}
+	require.NoError(t, snapshot.CopyTo(backupDir))
+	snapshot.Release()
+
+	backupFiles, err := ioutil.ReadDir(filepath.Join(backupDir, ChainsDir, "testLedger"))
+	require.NoError(t, err)
+	require.True(t, len(backupFiles) > 1)
+
+	backupProvider, err := NewProvider(NewConf(backupDir, 20000), &IndexConfig{AttrsToIndex: attrsToIndex}, &disabled.Provider{})
+	require.NoError(t, err)
+	defer backupProvider.Close()
+	backupStore, err := backupProvider.Open("testLedger")
+	require.NoError(t, err)
+	defer backupStore.Shutdown()
+
+	bcInfo, err := backupStore.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), bcInfo.Height)
+	for _, b := range blocks[:15] {
+		block, err := backupStore.RetrieveBlockByHash(protoutil.BlockHeaderHash(b.Header))
+		require.NoError(t, err)
+		require.Equal(t, b, block)
+	}
+
+	// the backup is a ledger of its own, which can be appended to
+	require.NoError(t, backupStore.AddBlock(blocks[15]))
+	block, err := backupStore.RetrieveBlockByNumber(15)
+	require.NoError(t, err)
+	require.Equal(t, blocks[15], block)
+
+	// a backup cannot overwrite an existing one
+	snapshot, err = store.BackupSnapshot()
+	require.NoError(t, err)
+	defer snapshot.Release()
+	require.Error(t, snapshot.CopyTo(backupDir))
+}
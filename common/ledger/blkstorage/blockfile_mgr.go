@@ -12,6 +12,7 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/golang/protobuf/proto"
@@ -27,6 +28,8 @@ const (
 	blockfilePrefix                   = "blockfile_"
 	bootstrappingSnapshotInfoFile     = "bootstrappingSnapshot.info"
 	bootstrappingSnapshotInfoTempFile = "bootstrappingSnapshotTemp.info"
+	blockfilesFormatFile              = "blockfilesFormat.info"
+	blockfilesFormatTempFile          = "blockfilesFormatTemp.info"
 )
 
 var (
@@ -43,6 +46,8 @@ type blockfileMgr struct {
 	blkfilesInfoCond          *sync.Cond
 	currentFileWriter         *blockfileWriter
 	bcInfo                    atomic.Value
+	pruneInfo                 atomic.Value
+	pruneLock                 sync.Mutex
 }
 
 /*
@@ -89,12 +94,21 @@ At start up a new manager:
 func newBlockfileMgr(id string, conf *Conf, indexConfig *IndexConfig, indexStore *leveldbhelper.DBHandle) (*blockfileMgr, error) {
 	logger.Debugf("newBlockfileMgr() initializing file-based block storage for ledger: %s ", id)
 	rootDir := conf.getLedgerBlockDir(id)
-	_, err := util.CreateDirIfMissing(rootDir)
+	_, err := conf.createLedgerBlockDir(id)
 	if err != nil {
 		panic(fmt.Sprintf("Error creating block storage root dir [%s]: %s", rootDir, err))
 	}
 	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
 
+	if _, err := loadBlockfilesFormat(rootDir); err != nil {
+		return nil, err
+	}
+	if conf.compressBlocks {
+		if err := recordBlockfilesCompression(rootDir); err != nil {
+			return nil, err
+		}
+	}
+
 	blockfilesInfo, err := mgr.loadBlkfilesInfo()
 	if err != nil {
 		panic(fmt.Sprintf("Could not get block file info for current block file from db: %s", err))
@@ -135,6 +149,15 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *IndexConfig, indexStore
 	mgr.currentFileWriter = currentFileWriter
 	mgr.blkfilesInfoCond = sync.NewCond(&sync.Mutex{})
 
+	pi, err := mgr.loadPruneInfo()
+	if err != nil {
+		return nil, err
+	}
+	mgr.pruneInfo.Store(pi)
+
+	if err := mgr.completeBlockHashSM3Index(); err != nil {
+		return nil, err
+	}
 	if err := mgr.syncIndex(); err != nil {
 		return nil, err
 	}
@@ -160,6 +183,10 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *IndexConfig, indexStore
 			PreviousBlockHash: previousBlockHash}
 	}
 	mgr.bcInfo.Store(bcInfo)
+
+	if err := mgr.removePrunedBlockfiles(); err != nil {
+		return nil, err
+	}
 	return mgr, nil
 }
 
@@ -170,7 +197,7 @@ func bootstrapFromSnapshottedTxIDs(
 	indexStore *leveldbhelper.DBHandle,
 ) error {
 	rootDir := conf.getLedgerBlockDir(snapshotInfo.LedgerID)
-	isEmpty, err := util.CreateDirIfMissing(rootDir)
+	isEmpty, err := conf.createLedgerBlockDir(snapshotInfo.LedgerID)
 	if err != nil {
 		return err
 	}
@@ -269,6 +296,11 @@ func (mgr *blockfileMgr) moveToNextFile() {
 }
 
 func (mgr *blockfileMgr) addBlock(block *common.Block) error {
+	return mgr.addBlockWithStageTimes(block, &BlockCommitStageTimes{})
+}
+
+func (mgr *blockfileMgr) addBlockWithStageTimes(block *common.Block, stageTimes *BlockCommitStageTimes) error {
+	startFileAppend := time.Now()
 	bcInfo := mgr.getBlockchainInfo()
 	if block.Header.Number != bcInfo.Height {
 		return errors.Errorf(
@@ -296,6 +328,12 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	txOffsets := info.txOffsets
 	currentOffset := mgr.blockfilesInfo.latestFileSize
 
+	if mgr.conf.compressBlocks {
+		if blockBytes, err = compressBlockBytes(blockBytes); err != nil {
+			return err
+		}
+	}
+
 	blockBytesLen := len(blockBytes)
 	blockBytesEncodedLen := proto.EncodeVarint(uint64(blockBytesLen))
 	totalBytesToAppend := blockBytesLen + len(blockBytesEncodedLen)
@@ -335,20 +373,31 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 		}
 		return errors.WithMessage(err, "error saving blockfiles file info to db")
 	}
+	stageTimes.FileAppend = time.Since(startFileAppend)
 
 	//Index block file location pointer updated with file suffex and offset for the new block
 	blockFLP := &fileLocPointer{fileSuffixNum: newBlkfilesInfo.latestFileNumber}
 	blockFLP.offset = currentOffset
-	// shift the txoffset because we prepend length of bytes before block bytes
-	for _, txOffset := range txOffsets {
-		txOffset.loc.offset += len(blockBytesEncodedLen)
+	// shift the txoffset because we prepend length of bytes before block bytes,
+	// unless the block is compressed and the txoffset relative to the uncompressed block
+	if !mgr.conf.compressBlocks {
+		for _, txOffset := range txOffsets {
+			txOffset.loc.offset += len(blockBytesEncodedLen)
+		}
 	}
 	//save the index in the database
-	if err = mgr.index.indexBlock(&blockIdxInfo{
+	startIndexUpdate := time.Now()
+	idxInfo := &blockIdxInfo{
 		blockNum: block.Header.Number, blockHash: blockHash,
-		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata}); err != nil {
+		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata,
+		compressed: mgr.conf.compressBlocks}
+	if mgr.index.isAttributeIndexed(IndexableAttrBlockHashSM3) {
+		idxInfo.blockHashSM3 = protoutil.BlockHeaderHashSM3(block.Header)
+	}
+	if err = mgr.index.indexBlock(idxInfo); err != nil {
 		return err
 	}
+	stageTimes.IndexUpdate = time.Since(startIndexUpdate)
 
 	//update the blockfilesInfo (for storage) and the blockchain info (for APIs) in the manager
 	mgr.updateBlockfilesInfo(newBlkfilesInfo)
@@ -386,12 +435,12 @@ func (mgr *blockfileMgr) syncIndex() error {
 		return nil
 	}
 
-	startFileNum := 0
+	startFileNum := mgr.getPruneInfo().firstFileNumber
 	startOffset := 0
 	skipFirstBlock := false
 	endFileNum := mgr.blockfilesInfo.latestFileNumber
 
-	firstAvailableBlkNum, err := retrieveFirstBlockNumFromFile(mgr.rootDir, 0)
+	firstAvailableBlkNum, err := retrieveFirstBlockNumFromFile(mgr.rootDir, startFileNum)
 	if err != nil {
 		return err
 	}
@@ -444,19 +493,26 @@ func (mgr *blockfileMgr) syncIndex() error {
 		}
 
 		//The blockStartOffset will get applied to the txOffsets prior to indexing within indexBlock(),
-		//therefore just shift by the difference between blockBytesOffset and blockStartOffset
-		numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
-		for _, offset := range info.txOffsets {
-			offset.loc.offset += numBytesToShift
+		//therefore just shift by the difference between blockBytesOffset and blockStartOffset.
+		//The txOffsets of a compressed block remain relative to the uncompressed block bytes
+		if !blockPlacementInfo.compressed {
+			numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
+			for _, offset := range info.txOffsets {
+				offset.loc.offset += numBytesToShift
+			}
 		}
 
 		//Update the blockIndexInfo with what was actually stored in file system
 		blockIdxInfo.blockHash = protoutil.BlockHeaderHash(info.blockHeader)
+		if mgr.index.isAttributeIndexed(IndexableAttrBlockHashSM3) {
+			blockIdxInfo.blockHashSM3 = protoutil.BlockHeaderHashSM3(info.blockHeader)
+		}
 		blockIdxInfo.blockNum = info.blockHeader.Number
 		blockIdxInfo.flp = &fileLocPointer{fileSuffixNum: blockPlacementInfo.fileNum,
 			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
 		blockIdxInfo.txOffsets = info.txOffsets
 		blockIdxInfo.metadata = info.metadata
+		blockIdxInfo.compressed = blockPlacementInfo.compressed
 
 		logger.Debugf("syncIndex() indexing block [%d]", blockIdxInfo.blockNum)
 		if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
@@ -470,6 +526,69 @@ func (mgr *blockfileMgr) syncIndex() error {
 	return nil
 }
 
+// completeBlockHashSM3Index indexes the SM3 hashes of the headers of the
+// blocks indexed while they were not configured to be, so that the blocks of
+// all the peers can be looked up by their SM3 hash however long the peers
+// have been indexing them.
+func (mgr *blockfileMgr) completeBlockHashSM3Index() error {
+	if !mgr.index.isAttributeIndexed(IndexableAttrBlockHashSM3) {
+		return mgr.index.clearBlockHashSM3Complete()
+	}
+	complete, err := mgr.index.isBlockHashSM3Complete()
+	if err != nil || complete {
+		return err
+	}
+	lastBlockIndexed, err := mgr.index.getLastBlockIndexed()
+	if err == errIndexSavePointKeyNotPresent || (err == nil && mgr.blockfilesInfo.noBlockFiles) {
+		// the blocks are all indexed from now on
+		return mgr.index.indexBlockHashesSM3(nil, nil, true)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Start indexing the SM3 hashes of the block headers up to block [%d]", lastBlockIndexed)
+	stream, err := newBlockStream(mgr.rootDir, mgr.getPruneInfo().firstFileNumber, 0, mgr.blockfilesInfo.latestFileNumber)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	var hashes [][]byte
+	var flps []*fileLocPointer
+	for {
+		blockBytes, blockPlacementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			break
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		if info.blockHeader.Number > lastBlockIndexed {
+			break
+		}
+		hashes = append(hashes, protoutil.BlockHeaderHashSM3(info.blockHeader))
+		flps = append(flps, &fileLocPointer{fileSuffixNum: blockPlacementInfo.fileNum,
+			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}})
+		if len(hashes) == 1000 {
+			if err := mgr.index.indexBlockHashesSM3(hashes, flps, false); err != nil {
+				return err
+			}
+			logger.Infof("Indexed the SM3 hash of the header of block [%d]", info.blockHeader.Number)
+			hashes, flps = nil, nil
+		}
+	}
+	if err := mgr.index.indexBlockHashesSM3(hashes, flps, true); err != nil {
+		return err
+	}
+	logger.Infof("Finished indexing the SM3 hashes of the block headers")
+	return nil
+}
+
 func (mgr *blockfileMgr) getBlockchainInfo() *common.BlockchainInfo {
 	return mgr.bcInfo.Load().(*common.BlockchainInfo)
 }
@@ -514,6 +633,9 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
 		)
 	}
+	if blockNum < mgr.firstRetainedBlockNumber() {
+		return mgr.retrieveRetainedConfigBlock(blockNum)
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -554,6 +676,13 @@ func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.B
 			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
 		)
 	}
+	if blockNum < mgr.firstRetainedBlockNumber() {
+		block, err := mgr.retrieveRetainedConfigBlock(blockNum)
+		if err != nil {
+			return nil, err
+		}
+		return block.Header, nil
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -601,6 +730,13 @@ func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, t
 			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
 		)
 	}
+	if blockNum < mgr.firstRetainedBlockNumber() {
+		block, err := mgr.retrieveRetainedConfigBlock(blockNum)
+		if err != nil {
+			return nil, err
+		}
+		return protoutil.ExtractEnvelope(block, int(tranNum))
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -624,7 +760,12 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 	logger.Debugf("Entering fetchTransactionEnvelope() %v\n", lp)
 	var err error
 	var txEnvelopeBytes []byte
-	if txEnvelopeBytes, err = mgr.fetchRawBytes(lp); err != nil {
+	if lp.inCompressedBlock {
+		txEnvelopeBytes, err = mgr.fetchTxBytesFromCompressedBlock(lp)
+	} else {
+		txEnvelopeBytes, err = mgr.fetchRawBytes(lp)
+	}
+	if err != nil {
 		return nil, err
 	}
 	_, n := proto.DecodeVarint(txEnvelopeBytes)
@@ -632,6 +773,9 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkNotPruned(lp); err != nil {
+		return nil, err
+	}
 	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
 		return nil, err
@@ -644,7 +788,22 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 	return b, nil
 }
 
+func (mgr *blockfileMgr) fetchTxBytesFromCompressedBlock(lp *fileLocPointer) ([]byte, error) {
+	blockBytes, err := mgr.fetchBlockBytes(&fileLocPointer{fileSuffixNum: lp.fileSuffixNum, locPointer: locPointer{offset: lp.offset}})
+	if err != nil {
+		return nil, err
+	}
+	end := lp.blockBytesOffset + lp.bytesLength
+	if end > len(blockBytes) {
+		return nil, errors.Errorf("transaction location [%s] exceeds block of length [%d]", lp, len(blockBytes))
+	}
+	return blockBytes[lp.blockBytesOffset:end], nil
+}
+
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkNotPruned(lp); err != nil {
+		return nil, err
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
@@ -9,6 +9,7 @@ package blkstorage
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"path/filepath"
 	"unicode/utf8"
 
@@ -27,7 +28,9 @@ const (
 	blockHashIdxKeyPrefix       = 'h'
 	txIDIdxKeyPrefix            = 't'
 	blockNumTranNumIdxKeyPrefix = 'a'
+	blockHashSM3IdxKeyPrefix    = 'g'
 	indexSavePointKeyStr        = "indexCheckpointKey"
+	blockHashSM3CompleteKeyStr  = "blockHashSM3CompleteKey"
 
 	snapshotFileFormat       = byte(1)
 	snapshotDataFileName     = "txids.data"
@@ -36,6 +39,7 @@ const (
 
 var (
 	indexSavePointKey              = []byte(indexSavePointKeyStr)
+	blockHashSM3CompleteKey        = []byte(blockHashSM3CompleteKeyStr)
 	errIndexSavePointKeyNotPresent = errors.New("NoBlockIndexed")
 	errNilValue                    = errors.New("")
 	importTxIDsBatchSize           = uint64(1000) // txID is 64 bytes, so batch size roughly translates to 64KB
@@ -44,9 +48,14 @@ var (
 type blockIdxInfo struct {
 	blockNum  uint64
 	blockHash []byte
-	flp       *fileLocPointer
-	txOffsets []*txindexInfo
-	metadata  *common.BlockMetadata
+	// blockHashSM3 is only set if the SM3 hashes of the block headers are indexed
+	blockHashSM3 []byte
+	flp          *fileLocPointer
+	txOffsets    []*txindexInfo
+	metadata     *common.BlockMetadata
+	// compressed indicates that the block is stored compressed, in which
+	// case the txOffsets are relative to the uncompressed block bytes
+	compressed bool
 }
 
 type blockIndex struct {
@@ -101,6 +110,9 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	if index.isAttributeIndexed(IndexableAttrBlockHash) {
 		batch.Put(constructBlockHashKey(blkHash), flpBytes)
 	}
+	if index.isAttributeIndexed(IndexableAttrBlockHashSM3) {
+		batch.Put(constructBlockHashSM3Key(blockIdxInfo.blockHashSM3), flpBytes)
+	}
 
 	//Index2
 	if index.isAttributeIndexed(IndexableAttrBlockNum) {
@@ -110,7 +122,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index3 Used to find a transaction by its transaction id
 	if index.isAttributeIndexed(IndexableAttrTxID) {
 		for i, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := newTxLocationPointer(flp, txoffset.loc, blockIdxInfo.compressed)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to txid-index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -136,7 +148,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index4 - Store BlockNumTranNum will be used to query history data
 	if index.isAttributeIndexed(IndexableAttrBlockNumTranNum) {
 		for i, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := newTxLocationPointer(flp, txoffset.loc, blockIdxInfo.compressed)
 			logger.Debugf("Adding txLoc [%s] for tx number:[%d] ID: [%s] to blockNumTranNum index", txFlp, i, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -159,13 +171,62 @@ func (index *blockIndex) isAttributeIndexed(attribute IndexableAttr) bool {
 	return ok
 }
 
+// isBlockHashSM3Complete returns whether the SM3 hashes of the headers of all
+// the indexed blocks are indexed
+func (index *blockIndex) isBlockHashSM3Complete() (bool, error) {
+	v, err := index.db.Get(blockHashSM3CompleteKey)
+	if err != nil {
+		return false, err
+	}
+	return v != nil, nil
+}
+
+// indexBlockHashesSM3 indexes the SM3 hashes of the headers of blocks indexed
+// without them. Once complete is set, the SM3 hashes of the headers of all
+// the indexed blocks are indexed.
+func (index *blockIndex) indexBlockHashesSM3(hashes [][]byte, flps []*fileLocPointer, complete bool) error {
+	batch := index.db.NewUpdateBatch()
+	for i, hash := range hashes {
+		flpBytes, err := flps[i].marshal()
+		if err != nil {
+			return err
+		}
+		batch.Put(constructBlockHashSM3Key(hash), flpBytes)
+	}
+	if complete {
+		batch.Put(blockHashSM3CompleteKey, []byte{})
+	}
+	return index.db.WriteBatch(batch, true)
+}
+
+// clearBlockHashSM3Complete records that the SM3 hashes of the headers of the
+// blocks indexed from now on are not indexed
+func (index *blockIndex) clearBlockHashSM3Complete() error {
+	return index.db.Delete(blockHashSM3CompleteKey, true)
+}
+
+// getBlockLocByHash returns the location of the block whose header has the
+// given SHA256 hash or, if they are indexed, the given SM3 hash.
 func (index *blockIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
-	if !index.isAttributeIndexed(IndexableAttrBlockHash) {
+	var keys [][]byte
+	if index.isAttributeIndexed(IndexableAttrBlockHash) {
+		keys = append(keys, constructBlockHashKey(blockHash))
+	}
+	if index.isAttributeIndexed(IndexableAttrBlockHashSM3) {
+		keys = append(keys, constructBlockHashSM3Key(blockHash))
+	}
+	if len(keys) == 0 {
 		return nil, ErrAttrNotIndexed
 	}
-	b, err := index.db.Get(constructBlockHashKey(blockHash))
-	if err != nil {
-		return nil, err
+	var b []byte
+	for _, key := range keys {
+		var err error
+		if b, err = index.db.Get(key); err != nil {
+			return nil, err
+		}
+		if b != nil {
+			break
+		}
 	}
 	if b == nil {
 		return nil, ErrNotFoundInIndex
@@ -388,6 +449,10 @@ func constructBlockHashKey(blockHash []byte) []byte {
 	return append([]byte{blockHashIdxKeyPrefix}, blockHash...)
 }
 
+func constructBlockHashSM3Key(blockHash []byte) []byte {
+	return append([]byte{blockHashSM3IdxKeyPrefix}, blockHash...)
+}
+
 func constructTxIDKey(txID string, blkNum, txNum uint64) []byte {
 	k := append(
 		[]byte{txIDIdxKeyPrefix},
@@ -467,6 +532,11 @@ func (lp *locPointer) String() string {
 type fileLocPointer struct {
 	fileSuffixNum int
 	locPointer
+	// inCompressedBlock is set for a transaction of a compressed block. The
+	// offset is then the one of the block in the file, and blockBytesOffset
+	// the one of the transaction in the uncompressed block bytes
+	inCompressedBlock bool
+	blockBytesOffset  int
 }
 
 func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *locPointer) *fileLocPointer {
@@ -476,6 +546,18 @@ func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *
 	return flp
 }
 
+func newTxLocationPointer(blockFLP *fileLocPointer, relativeLP *locPointer, compressedBlock bool) *fileLocPointer {
+	if !compressedBlock {
+		return newFileLocationPointer(blockFLP.fileSuffixNum, blockFLP.offset, relativeLP)
+	}
+	return &fileLocPointer{
+		fileSuffixNum:     blockFLP.fileSuffixNum,
+		locPointer:        locPointer{offset: blockFLP.offset, bytesLength: relativeLP.bytesLength},
+		inCompressedBlock: true,
+		blockBytesOffset:  relativeLP.offset,
+	}
+}
+
 func (flp *fileLocPointer) marshal() ([]byte, error) {
 	buffer := proto.NewBuffer([]byte{})
 	e := buffer.EncodeVarint(uint64(flp.fileSuffixNum))
@@ -490,6 +572,12 @@ func (flp *fileLocPointer) marshal() ([]byte, error) {
 	if e != nil {
 		return nil, errors.Wrapf(e, "unexpected error while marshaling fileLocPointer [%s]", flp)
 	}
+	if flp.inCompressedBlock {
+		e = buffer.EncodeVarint(uint64(flp.blockBytesOffset))
+		if e != nil {
+			return nil, errors.Wrapf(e, "unexpected error while marshaling fileLocPointer [%s]", flp)
+		}
+	}
 	return buffer.Bytes(), nil
 }
 
@@ -511,10 +599,23 @@ func (flp *fileLocPointer) unmarshal(b []byte) error {
 		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
 	}
 	flp.bytesLength = int(i)
+	// a fourth varint is only present for a transaction in a compressed block
+	i, e = buffer.DecodeVarint()
+	if e == io.ErrUnexpectedEOF {
+		return nil
+	}
+	if e != nil {
+		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
+	}
+	flp.inCompressedBlock = true
+	flp.blockBytesOffset = int(i)
 	return nil
 }
 
 func (flp *fileLocPointer) String() string {
+	if flp.inCompressedBlock {
+		return fmt.Sprintf("fileSuffixNum=%d, %s, blockBytesOffset=%d", flp.fileSuffixNum, flp.locPointer.String(), flp.blockBytesOffset)
+	}
 	return fmt.Sprintf("fileSuffixNum=%d, %s", flp.fileSuffixNum, flp.locPointer.String())
 }
 
@@ -197,6 +197,85 @@ func testBlockIndexSelectiveIndexing(t *testing.T, indexItems []IndexableAttr) {
 	})
 }
 
+func TestBlockIndexSM3Hashes(t *testing.T) {
+	t.Run("indexed", func(t *testing.T) {
+		env := newTestEnvSelectiveIndexing(t, NewConf(testPath(), 0), []IndexableAttr{IndexableAttrBlockNum, IndexableAttrBlockHash, IndexableAttrBlockHashSM3}, &disabled.Provider{})
+		defer env.Cleanup()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+		defer blkfileMgrWrapper.close()
+		blkfileMgr := blkfileMgrWrapper.blockfileMgr
+		originalIndexStore := blkfileMgr.index.db
+
+		blocks := testutil.ConstructTestBlocks(t, 4)
+		blkfileMgrWrapper.addBlocks(blocks[:2])
+		// the last blocks are indexed when syncing the index on restart
+		blkfileMgr.index.db = env.provider.leveldbProvider.GetDBHandle("someRandomPlace")
+		blkfileMgrWrapper.addBlocks(blocks[2:])
+		blkfileMgr.index.db = originalIndexStore
+		blkfileMgrWrapper.close()
+		blkfileMgrWrapper = newTestBlockfileWrapper(env, "testledger")
+		blkfileMgr = blkfileMgrWrapper.blockfileMgr
+
+		for _, b := range blocks {
+			block, err := blkfileMgr.retrieveBlockByHash(protoutil.BlockHeaderHash(b.Header))
+			require.NoError(t, err)
+			require.Equal(t, b, block)
+
+			block, err = blkfileMgr.retrieveBlockByHash(protoutil.BlockHeaderHashSM3(b.Header))
+			require.NoError(t, err)
+			require.Equal(t, b, block)
+		}
+	})
+
+	t.Run("enabled on an existing ledger", func(t *testing.T) {
+		conf := NewConf(testPath(), 0)
+		withoutSM3 := []IndexableAttr{IndexableAttrBlockNum, IndexableAttrBlockHash}
+		withSM3 := []IndexableAttr{IndexableAttrBlockNum, IndexableAttrBlockHash, IndexableAttrBlockHashSM3}
+		reopen := func(env *testEnv, attrs []IndexableAttr) (*testEnv, *testBlockfileMgrWrapper) {
+			env.provider.Close()
+			env = newTestEnvSelectiveIndexing(t, conf, attrs, &disabled.Provider{})
+			return env, newTestBlockfileWrapper(env, "testledger")
+		}
+		blocks := testutil.ConstructTestBlocks(t, 5)
+
+		env := newTestEnvSelectiveIndexing(t, conf, withoutSM3, &disabled.Provider{})
+		defer func() { env.Cleanup() }()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+		blkfileMgrWrapper.addBlocks(blocks[:2])
+		blkfileMgrWrapper.close()
+
+		env, blkfileMgrWrapper = reopen(env, withSM3)
+		blkfileMgrWrapper.addBlocks(blocks[2:3])
+		blkfileMgrWrapper.close()
+
+		// the blocks indexed while SM3 is disabled are indexed once it is enabled again
+		env, blkfileMgrWrapper = reopen(env, withoutSM3)
+		blkfileMgrWrapper.addBlocks(blocks[3:])
+		blkfileMgrWrapper.close()
+
+		env, blkfileMgrWrapper = reopen(env, withSM3)
+		defer blkfileMgrWrapper.close()
+		for _, b := range blocks {
+			block, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockByHash(protoutil.BlockHeaderHashSM3(b.Header))
+			require.NoError(t, err)
+			require.Equal(t, b, block)
+		}
+	})
+
+	t.Run("not indexed", func(t *testing.T) {
+		env := newTestEnvSelectiveIndexing(t, NewConf(testPath(), 0), []IndexableAttr{IndexableAttrBlockHash}, &disabled.Provider{})
+		defer env.Cleanup()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+		defer blkfileMgrWrapper.close()
+
+		blocks := testutil.ConstructTestBlocks(t, 2)
+		blkfileMgrWrapper.addBlocks(blocks)
+
+		_, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockByHash(protoutil.BlockHeaderHashSM3(blocks[0].Header))
+		require.Exactly(t, ErrNotFoundInIndex, err)
+	})
+}
+
 func containsAttr(indexItems []IndexableAttr, attr IndexableAttr) bool {
 	for _, element := range indexItems {
 		if element == attr {
@@ -69,6 +69,16 @@ func (itr *blocksItr) Next() (ledger.QueryResult, error) {
 		return nil, nil
 	}
 	if itr.stream == nil {
+		// only the config blocks are retained below the first retained block,
+		// the iterator fails on the other blocks rather than skipping them
+		if itr.blockNumToRetrieve < itr.mgr.firstRetainedBlockNumber() {
+			block, err := itr.mgr.retrieveRetainedConfigBlock(itr.blockNumToRetrieve)
+			if err != nil {
+				return nil, err
+			}
+			itr.blockNumToRetrieve++
+			return block, nil
+		}
 		logger.Debugf("Initializing block stream for iterator. itr.maxBlockNumAvailable=%d", itr.maxBlockNumAvailable)
 		if err := itr.initStream(); err != nil {
 			return nil, err
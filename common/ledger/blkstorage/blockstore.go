@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package blkstorage
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -14,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/snapshot"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
 )
 
 // BlockStore - filesystem based implementation for `BlockStore`
@@ -40,16 +43,32 @@ func newBlockStore(id string, conf *Conf, indexConfig *IndexConfig,
 	return &BlockStore{id, conf, fileMgr, ledgerStats}, nil
 }
 
+// BlockCommitStageTimes breaks down the time taken to add a block to the store
+type BlockCommitStageTimes struct {
+	// FileAppend is the time taken to serialize the block and append it to the block file
+	FileAppend time.Duration
+	// IndexUpdate is the time taken to index the block and its transactions
+	IndexUpdate time.Duration
+}
+
 // AddBlock adds a new block
 func (store *BlockStore) AddBlock(block *common.Block) error {
+	_, err := store.AddBlockWithStageTimes(block)
+	return err
+}
+
+// AddBlockWithStageTimes adds a new block and returns the time taken by each
+// stage of the commit of the block
+func (store *BlockStore) AddBlockWithStageTimes(block *common.Block) (*BlockCommitStageTimes, error) {
 	// track elapsed time to collect block commit time
+	stageTimes := &BlockCommitStageTimes{}
 	startBlockCommit := time.Now()
-	result := store.fileMgr.addBlock(block)
+	result := store.fileMgr.addBlockWithStageTimes(block, stageTimes)
 	elapsedBlockCommit := time.Since(startBlockCommit)
 
 	store.updateBlockStats(block.Header.Number, elapsedBlockCommit)
 
-	return result
+	return stageTimes, result
 }
 
 // GetBlockchainInfo returns the current info about blockchain
@@ -57,6 +76,20 @@ func (store *BlockStore) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	return store.fileMgr.getBlockchainInfo(), nil
 }
 
+// FirstRetainedBlock returns the number of the first block kept by a store
+// which retains only its most recent blocks. The blocks below are pruned but
+// the config blocks.
+func (store *BlockStore) FirstRetainedBlock() uint64 {
+	return store.fileMgr.firstRetainedBlockNumber()
+}
+
+// Prune removes the block files holding only blocks numbered below the given
+// block number, keeping their config blocks. It reads the block files back, so
+// it is better called away from the commit path.
+func (store *BlockStore) Prune(belowBlockNum uint64) error {
+	return store.fileMgr.prune(belowBlockNum)
+}
+
 // RetrieveBlocks returns an iterator that can be used for iterating over a range of blocks
 func (store *BlockStore) RetrieveBlocks(startNum uint64) (ledger.ResultsIterator, error) {
 	return store.fileMgr.retrieveBlocks(startNum)
@@ -100,6 +133,26 @@ func (store *BlockStore) ExportTxIds(dir string, newHashFunc snapshot.NewHashFun
 	return store.fileMgr.index.exportUniqueTxIDs(dir, newHashFunc)
 }
 
+// Size returns the disk space in bytes used by the block files of the ledger,
+// the block index shared by the ledgers being excluded
+func (store *BlockStore) Size() (int64, error) {
+	dir, err := filepath.EvalSymlinks(store.conf.getLedgerBlockDir(store.id))
+	if err != nil {
+		return 0, errors.Wrapf(err, "error resolving the block files directory of ledger [%s]", store.id)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error listing the block files of ledger [%s]", store.id)
+	}
+	var size int64
+	for _, f := range files {
+		if f.Mode().IsRegular() {
+			size += f.Size()
+		}
+	}
+	return size, nil
+}
+
 // Shutdown shuts down the block store
 func (store *BlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
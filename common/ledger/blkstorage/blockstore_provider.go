@@ -29,6 +29,11 @@ const (
 	IndexableAttrBlockHash       = IndexableAttr("BlockHash")
 	IndexableAttrTxID            = IndexableAttr("TxID")
 	IndexableAttrBlockNumTranNum = IndexableAttr("BlockNumTranNum")
+	// IndexableAttrBlockHashSM3 indexes the blocks by the SM3 hash of their
+	// header, so that they may be retrieved by either hash of their header.
+	// The blocks committed before it was configured are indexed when the block
+	// store is opened.
+	IndexableAttrBlockHashSM3 = IndexableAttr("BlockHashSM3")
 )
 
 // IndexConfig - a configuration that includes a list of attributes that should be indexed
@@ -72,12 +77,7 @@ type BlockStoreProvider struct {
 
 // NewProvider constructs a filesystem based block store provider
 func NewProvider(conf *Conf, indexConfig *IndexConfig, metricsProvider metrics.Provider) (*BlockStoreProvider, error) {
-	dbConf := &leveldbhelper.Conf{
-		DBPath:         conf.getIndexDir(),
-		ExpectedFormat: dataFormatVersion(indexConfig),
-	}
-
-	p, err := leveldbhelper.NewProvider(dbConf)
+	p, err := openIndexDB(conf, indexConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +142,14 @@ func (p *BlockStoreProvider) Remove(ledgerid string) error {
 		return err
 	}
 	dbHandle.Close()
-	if err := os.RemoveAll(p.conf.getLedgerBlockDir(ledgerid)); err != nil {
+	ledgerDir := p.conf.getLedgerBlockDir(ledgerid)
+	if shardDir, err := os.Readlink(ledgerDir); err == nil {
+		// the block files of the ledger are in a shard
+		if err := os.RemoveAll(shardDir); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(ledgerDir); err != nil {
 		return err
 	}
 	return syncDir(p.conf.getChainsDir())
@@ -153,6 +160,11 @@ func (p *BlockStoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// CompactIndex compacts the leveldb holding the block indexes of all the ledgers
+func (p *BlockStoreProvider) CompactIndex() error {
+	return p.leveldbProvider.Compact()
+}
+
 // Close closes the BlockStoreProvider
 func (p *BlockStoreProvider) Close() {
 	p.leveldbProvider.Close()
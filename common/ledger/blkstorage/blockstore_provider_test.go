@@ -9,6 +9,7 @@ package blkstorage
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -246,6 +247,83 @@ func TestRemove(t *testing.T) {
 	require.EqualError(t, provider.Remove("ledger2"), "internal leveldb error while obtaining db iterator: leveldb: closed")
 }
 
+func TestShardedBlockStores(t *testing.T) {
+	rootDir := testPath()
+	defer os.RemoveAll(rootDir)
+	shard1 := testPath()
+	defer os.RemoveAll(shard1)
+	shard2 := testPath()
+	defer os.RemoveAll(shard2)
+	mappedShard := testPath()
+	defer os.RemoveAll(mappedShard)
+
+	// a ledger created before sharding remains in the root directory
+	env := newTestEnv(t, NewConf(rootDir, 0))
+	store, err := env.provider.Open("unsharded")
+	require.NoError(t, err)
+	unshardedBlocks := addBlocksToStore(t, store, 3)
+	store.Shutdown()
+	env.provider.Close()
+
+	conf := NewConf(rootDir, 0)
+	conf.SetShards([]string{shard1, shard2}, map[string]string{"mapped": mappedShard})
+	env = newTestEnv(t, conf)
+	provider := env.provider
+
+	ledgerBlocks := map[string][]*common.Block{}
+	for _, ledgerid := range []string{"unsharded", "mapped", "ledger1", "ledger2", "ledger3", "ledger4"} {
+		store, err := provider.Open(ledgerid)
+		require.NoError(t, err)
+		if ledgerid == "unsharded" {
+			checkBlocks(t, unshardedBlocks, store)
+			ledgerBlocks[ledgerid] = unshardedBlocks
+		} else {
+			ledgerBlocks[ledgerid] = addBlocksToStore(t, store, 3)
+		}
+		store.Shutdown()
+	}
+
+	info, err := os.Lstat(conf.getLedgerBlockDir("unsharded"))
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	target, err := os.Readlink(conf.getLedgerBlockDir("mapped"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(mappedShard, ChainsDir, "mapped"), target)
+	shardUsed := map[string]bool{}
+	for _, ledgerid := range []string{"ledger1", "ledger2", "ledger3", "ledger4"} {
+		target, err := os.Readlink(conf.getLedgerBlockDir(ledgerid))
+		require.NoError(t, err)
+		require.Contains(t, []string{shard1, shard2}, filepath.Dir(filepath.Dir(target)))
+		require.FileExists(t, filepath.Join(target, "blockfile_000000"))
+		shardUsed[filepath.Dir(filepath.Dir(target))] = true
+	}
+	require.Len(t, shardUsed, 2)
+
+	storeNames, err := provider.List()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"unsharded", "mapped", "ledger1", "ledger2", "ledger3", "ledger4"}, storeNames)
+	provider.Close()
+
+	// the sharded ledgers are found without the sharding configuration
+	env = newTestEnv(t, NewConf(rootDir, 0))
+	defer env.Cleanup()
+	provider = env.provider
+	for ledgerid, blocks := range ledgerBlocks {
+		store, err := provider.Open(ledgerid)
+		require.NoError(t, err)
+		checkBlocks(t, blocks, store)
+		store.Shutdown()
+	}
+
+	require.NoError(t, provider.Remove("mapped"))
+	exists, err := provider.Exists("mapped")
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.NoDirExists(t, filepath.Join(mappedShard, ChainsDir, "mapped"))
+	_, err = os.Lstat(conf.getLedgerBlockDir("mapped"))
+	require.True(t, os.IsNotExist(err))
+}
+
 func constructLedgerid(id int) string {
 	return fmt.Sprintf("ledger_%d", id)
 }
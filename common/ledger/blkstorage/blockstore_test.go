@@ -8,6 +8,8 @@ package blkstorage
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -31,6 +33,51 @@ func TestWrongBlockNumber(t *testing.T) {
 	require.Error(t, err, "Error shold have been thrown when adding block number 4 while block number 3 is expected")
 }
 
+func TestAddBlockWithStageTimes(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	provider := env.provider
+	store, _ := provider.Open("testLedger")
+	defer store.Shutdown()
+
+	blocks := testutil.ConstructTestBlocks(t, 2)
+	stageTimes, err := store.AddBlockWithStageTimes(blocks[0])
+	require.NoError(t, err)
+	require.NotZero(t, stageTimes.FileAppend)
+	require.NotZero(t, stageTimes.IndexUpdate)
+
+	stageTimes, err = store.AddBlockWithStageTimes(blocks[0])
+	require.Error(t, err)
+	require.Zero(t, stageTimes.IndexUpdate)
+}
+
+func TestBlockStoreSize(t *testing.T) {
+	path := testPath()
+	env := newTestEnv(t, NewConf(path, 0))
+	defer env.Cleanup()
+
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	size, err := store.Size()
+	require.NoError(t, err)
+	require.Zero(t, size)
+
+	blocks := testutil.ConstructTestBlocks(t, 3)
+	for _, b := range blocks {
+		require.NoError(t, store.AddBlock(b))
+	}
+	size, err = store.Size()
+	require.NoError(t, err)
+	require.Greater(t, size, int64(0))
+
+	require.NoError(t, os.RemoveAll(filepath.Join(path, ChainsDir, "testLedger")))
+	_, err = store.Size()
+	require.Error(t, err)
+}
+
 func TestTxIDIndexErrorPropagations(t *testing.T) {
 	env := newTestEnv(t, NewConf(testPath(), 0))
 	defer env.Cleanup()
@@ -0,0 +1,296 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/DataDog/zstd"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// Version 2 of the block file format allows a block to be stored as a zstd
+// frame holding its serialized bytes.  Such a block is recognized by the zstd
+// frame magic number.  A serialized block never starts with these bytes, as
+// they would encode a block with a data hash of 6069 bytes, and therefore
+// compressed and uncompressed blocks can be mixed in the same block file and
+// block files written with version 1 of the format are read unchanged.
+var zstdFrameMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+const (
+	// blockfilesFormatVersion is the version of the block file format which
+	// allows compressed blocks.  It is recorded, along with the compression of
+	// the blocks, in the blockfilesFormat file of a ledger before its first
+	// compressed block is written.
+	blockfilesFormatVersion = 2
+
+	// compressedBlocksFormat is the data format of the block store index once
+	// the block files of a ledger may hold compressed blocks.  The versions of
+	// the peer which cannot read compressed blocks expect the index in
+	// dataformat.CurrentFormat, and therefore refuse to open the block store.
+	compressedBlocksFormat = "2.0-zstd"
+)
+
+func isCompressedBlock(storedBytes []byte) bool {
+	return bytes.HasPrefix(storedBytes, zstdFrameMagic)
+}
+
+// loadBlockfilesFormat returns the format recorded for the block files of a
+// ledger, or nil if the blocks of the ledger are not compressed.  An error is
+// returned if the format is unknown to this version of the peer.
+func loadBlockfilesFormat(ledgerDir string) (*BlockfilesFormat, error) {
+	formatBytes, err := ioutil.ReadFile(filepath.Join(ledgerDir, blockfilesFormatFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading blockfilesFormat file")
+	}
+	format := &BlockfilesFormat{}
+	if err := proto.Unmarshal(formatBytes, format); err != nil {
+		return nil, errors.Wrapf(err, "error while unmarshalling blockfilesFormat")
+	}
+	if format.Version > blockfilesFormatVersion {
+		return nil, errors.Errorf("block files at [%s] have unknown format version %d", ledgerDir, format.Version)
+	}
+	if _, ok := BlockCompression_name[int32(format.Compression)]; !ok {
+		return nil, errors.Errorf("block files at [%s] have unknown compression flag %d", ledgerDir, format.Compression)
+	}
+	return format, nil
+}
+
+// recordBlockfilesCompression records that the block files of a ledger may
+// hold compressed blocks, unless already recorded.
+func recordBlockfilesCompression(ledgerDir string) error {
+	format, err := loadBlockfilesFormat(ledgerDir)
+	if err != nil {
+		return err
+	}
+	if format != nil && format.Compression == BlockCompression_ZSTD {
+		return nil
+	}
+	formatBytes, err := proto.Marshal(&BlockfilesFormat{
+		Version:     blockfilesFormatVersion,
+		Compression: BlockCompression_ZSTD,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error while marshalling blockfilesFormat")
+	}
+	if err := os.RemoveAll(filepath.Join(ledgerDir, blockfilesFormatTempFile)); err != nil {
+		return errors.Wrapf(err, "error removing file [%s]", blockfilesFormatTempFile)
+	}
+	return createAndSyncFileAtomically(ledgerDir, blockfilesFormatTempFile, blockfilesFormatFile, formatBytes)
+}
+
+// hasCompressedLedgers returns true if the block files of any ledger may hold
+// compressed blocks.
+func hasCompressedLedgers(conf *Conf) (bool, error) {
+	chainsDir := conf.getChainsDir()
+	chainsDirExists, err := pathExists(chainsDir)
+	if err != nil || !chainsDirExists {
+		return false, err
+	}
+	ledgerIDs, err := util.ListSubdirs(chainsDir)
+	if err != nil {
+		return false, err
+	}
+	for _, ledgerID := range ledgerIDs {
+		format, err := loadBlockfilesFormat(conf.getLedgerBlockDir(ledgerID))
+		if err != nil {
+			return false, err
+		}
+		if format != nil && format.Compression != BlockCompression_NONE {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// openIndexDB opens the leveldb of the block store index.  Its data format is
+// changed to compressedBlocksFormat before the first block is compressed,
+// whether by a block store which compresses the blocks it appends or by
+// CompressBlockStore.
+func openIndexDB(conf *Conf, indexConfig *IndexConfig) (*leveldbhelper.Provider, error) {
+	dbConf := &leveldbhelper.Conf{
+		DBPath:         conf.getIndexDir(),
+		ExpectedFormat: dataFormatVersion(indexConfig),
+	}
+	if dbConf.ExpectedFormat != dataformat.CurrentFormat {
+		if conf.compressBlocks {
+			return nil, errors.Errorf("blocks can only be compressed in a block store of data format %s", dataformat.CurrentFormat)
+		}
+		return leveldbhelper.NewProvider(dbConf)
+	}
+
+	format, _, err := leveldbhelper.RetrieveDataFormat(dbConf.DBPath)
+	if err != nil {
+		return nil, err
+	}
+	if format == compressedBlocksFormat {
+		dbConf.ExpectedFormat = compressedBlocksFormat
+		return leveldbhelper.NewProvider(dbConf)
+	}
+	compressed := conf.compressBlocks
+	if !compressed {
+		if compressed, err = hasCompressedLedgers(conf); err != nil {
+			return nil, err
+		}
+	}
+	p, err := leveldbhelper.NewProvider(dbConf)
+	if err != nil || !compressed {
+		return p, err
+	}
+	logger.Infof("Setting the data format of the block store index to %s", compressedBlocksFormat)
+	if err := p.SetDataFormat(compressedBlocksFormat); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func compressBlockBytes(blockBytes []byte) ([]byte, error) {
+	compressed, err := zstd.Compress(nil, blockBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "error compressing block")
+	}
+	return compressed, nil
+}
+
+func decompressBlockBytes(storedBytes []byte) ([]byte, error) {
+	blockBytes, err := zstd.Decompress(nil, storedBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decompressing block")
+	}
+	return blockBytes, nil
+}
+
+// CompressBlockStore rewrites the block files of all the ledgers with their
+// blocks compressed.  As the blocks move within the block files, the block
+// storage index is dropped, and it is rebuilt the next time the block store
+// is opened.  It must only be used while the peer is offline.
+func CompressBlockStore(blockStorageDir string) error {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	chainsDir := conf.getChainsDir()
+	chainsDirExists, err := pathExists(chainsDir)
+	if err != nil {
+		return err
+	}
+	if !chainsDirExists {
+		logger.Infof("Dir [%s] missing... exiting", chainsDir)
+		return nil
+	}
+	ledgerIDs, err := util.ListSubdirs(chainsDir)
+	if err != nil {
+		return err
+	}
+	if len(ledgerIDs) == 0 {
+		logger.Info("No ledgers found.. exiting")
+		return nil
+	}
+	logger.Infof("Found ledgers - %s", ledgerIDs)
+
+	// The index of a ledger bootstrapped from a snapshot holds the IDs of the
+	// transactions of the snapshot, so it cannot be rebuilt from the block files
+	for _, ledgerID := range ledgerIDs {
+		bsi, err := loadBootstrappingSnapshotInfo(conf.getLedgerBlockDir(ledgerID))
+		if err != nil {
+			return err
+		}
+		if bsi != nil {
+			return errors.Errorf("cannot compress the block files of ledger [%s] as it was bootstrapped from a snapshot", ledgerID)
+		}
+	}
+
+	// the index is dropped first so that it is never used with moved blocks,
+	// even if the compression is interrupted
+	if err := DeleteBlockStoreIndex(blockStorageDir); err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		ledgerDir := conf.getLedgerBlockDir(ledgerID)
+		if err := recordBlockfilesCompression(ledgerDir); err != nil {
+			return err
+		}
+		if err := compressBlockfiles(ledgerDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compressBlockfiles(ledgerDir string) error {
+	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
+	if err != nil {
+		return err
+	}
+	for fileNum := 0; fileNum <= lastFileNum; fileNum++ {
+		logger.Infof("Compressing file [%s]", deriveBlockfilePath(ledgerDir, fileNum))
+		if err := compressBlockfile(ledgerDir, fileNum); err != nil {
+			return err
+		}
+	}
+	return syncDir(ledgerDir)
+}
+
+// compressBlockfile rewrites a block file with all its blocks compressed.  A
+// partially written block at the end of the file is discarded.
+func compressBlockfile(ledgerDir string, fileNum int) error {
+	filePath := deriveBlockfilePath(ledgerDir, fileNum)
+	tempFilePath := filepath.Join(ledgerDir, fmt.Sprintf("%s.compressing", filepath.Base(filePath)))
+
+	stream, err := newBlockfileStream(ledgerDir, fileNum, 0)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	if err := os.RemoveAll(tempFilePath); err != nil {
+		return errors.Wrapf(err, "error removing file [%s]", tempFilePath)
+	}
+	writer, err := newBlockfileWriter(tempFilePath)
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+
+	for {
+		blockBytes, _, err := stream.nextBlockBytesAndPlacementInfo()
+		if err == ErrUnexpectedEndOfBlockfile {
+			logger.Warningf("Discarding partially written block at the end of file [%s]", filePath)
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			break
+		}
+		compressed, err := compressBlockBytes(blockBytes)
+		if err != nil {
+			return err
+		}
+		if err := writer.append(proto.EncodeVarint(uint64(len(compressed))), false); err != nil {
+			return errors.Wrapf(err, "error writing file [%s]", tempFilePath)
+		}
+		if err := writer.append(compressed, false); err != nil {
+			return errors.Wrapf(err, "error writing file [%s]", tempFilePath)
+		}
+	}
+
+	if err := writer.file.Sync(); err != nil {
+		return errors.Wrapf(err, "error syncing file [%s]", tempFilePath)
+	}
+	return errors.Wrapf(os.Rename(tempFilePath, filePath), "error replacing file [%s]", filePath)
+}
@@ -0,0 +1,342 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressedBlockReadWrite(t *testing.T) {
+	env := newTestEnv(t, NewConfWithCompression(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	require.Equal(t, []bool{true}, storedBlocksCompression(t, blkfileMgrWrapper.blockfileMgr.rootDir, 0)[:1])
+	verifyBlocksAndTransactions(t, blkfileMgrWrapper, blocks)
+}
+
+func TestMixedCompressionBlockfiles(t *testing.T) {
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+
+	env := newTestEnv(t, NewConf(blockStorageDir, 0))
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks[:5])
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	env = newTestEnv(t, NewConfWithCompression(blockStorageDir, 0))
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks[5:])
+	require.Equal(t,
+		[]bool{false, false, false, false, false, true, true, true, true, true},
+		storedBlocksCompression(t, blkfileMgrWrapper.blockfileMgr.rootDir, 0),
+	)
+	verifyBlocksAndTransactions(t, blkfileMgrWrapper, blocks)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	// the index is rebuilt from the block files with both kinds of blocks
+	require.NoError(t, DeleteBlockStoreIndex(blockStorageDir))
+	env = newTestEnv(t, NewConf(blockStorageDir, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	verifyBlocksAndTransactions(t, blkfileMgrWrapper, blocks)
+}
+
+func TestCompressBlockStore(t *testing.T) {
+	blockStorageDir := testPath()
+	blocks := testutil.ConstructTestBlocks(t, 200)
+
+	env := newTestEnv(t, NewConf(blockStorageDir, 64*1024))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	ledgerDir := blkfileMgrWrapper.blockfileMgr.rootDir
+	lastFileNum := blkfileMgrWrapper.blockfileMgr.blockfilesInfo.latestFileNumber
+	require.True(t, lastFileNum > 0)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	require.NoError(t, CompressBlockStore(blockStorageDir))
+	for fileNum := 0; fileNum <= lastFileNum; fileNum++ {
+		for _, compressed := range storedBlocksCompression(t, ledgerDir, fileNum) {
+			require.True(t, compressed)
+		}
+	}
+
+	env = newTestEnv(t, NewConf(blockStorageDir, 64*1024))
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	verifyBlocksAndTransactions(t, blkfileMgrWrapper, blocks)
+}
+
+func TestCompressBlockStoreDiscardsPartialBlock(t *testing.T) {
+	blockStorageDir := testPath()
+	blocks := testutil.ConstructTestBlocks(t, 5)
+
+	env := newTestEnv(t, NewConf(blockStorageDir, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	ledgerDir := blkfileMgrWrapper.blockfileMgr.rootDir
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	file, err := os.OpenFile(deriveBlockfilePath(ledgerDir, 0), os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = file.Write(proto.EncodeVarint(1000))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	require.NoError(t, CompressBlockStore(blockStorageDir))
+	require.Equal(t, []bool{true, true, true, true, true}, storedBlocksCompression(t, ledgerDir, 0))
+
+	env = newTestEnv(t, NewConf(blockStorageDir, 0))
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	verifyBlocksAndTransactions(t, blkfileMgrWrapper, blocks)
+}
+
+func TestCompressBlockStoreBootstrappedFromSnapshot(t *testing.T) {
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+
+	conf := NewConf(blockStorageDir, 0)
+	ledgerDir := conf.getLedgerBlockDir("testLedger")
+	require.NoError(t, os.MkdirAll(ledgerDir, 0755))
+	bsiBytes, err := proto.Marshal(&BootstrappingSnapshotInfo{LastBlockNum: 10})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(ledgerDir, bootstrappingSnapshotInfoFile), bsiBytes, 0644))
+
+	err = CompressBlockStore(blockStorageDir)
+	require.EqualError(t, err, "cannot compress the block files of ledger [testLedger] as it was bootstrapped from a snapshot")
+}
+
+func TestCompressBlockStoreNoLedgers(t *testing.T) {
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+	require.NoError(t, CompressBlockStore(blockStorageDir))
+
+	_, err := NewProvider(NewConf(blockStorageDir, 0), &IndexConfig{AttrsToIndex: attrsToIndex}, &disabled.Provider{})
+	require.NoError(t, err)
+	require.NoError(t, CompressBlockStore(blockStorageDir))
+}
+
+func TestCompressedBlockStoreDataFormat(t *testing.T) {
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+	blocks := testutil.ConstructTestBlocks(t, 5)
+
+	env := newTestEnv(t, NewConf(blockStorageDir, 0))
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	ledgerDir := blkfileMgrWrapper.blockfileMgr.rootDir
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+	assertIndexDataFormat(t, blockStorageDir, dataformat.CurrentFormat)
+	format, err := loadBlockfilesFormat(ledgerDir)
+	require.NoError(t, err)
+	require.Nil(t, format)
+
+	env = newTestEnv(t, NewConfWithCompression(blockStorageDir, 0))
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+	assertIndexDataFormat(t, blockStorageDir, compressedBlocksFormat)
+	format, err = loadBlockfilesFormat(ledgerDir)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(&BlockfilesFormat{Version: blockfilesFormatVersion, Compression: BlockCompression_ZSTD}, format))
+
+	// the versions of the peer which cannot read compressed blocks expect the
+	// current data format
+	_, err = leveldbhelper.NewProvider(&leveldbhelper.Conf{
+		DBPath:         BlockStoreIndexPath(blockStorageDir),
+		ExpectedFormat: dataformat.CurrentFormat,
+	})
+	require.IsType(t, &dataformat.ErrFormatMismatch{}, err)
+
+	// the format is kept once compression is disabled, and when the index is rebuilt
+	env = newTestEnv(t, NewConf(blockStorageDir, 0))
+	env.provider.Close()
+	assertIndexDataFormat(t, blockStorageDir, compressedBlocksFormat)
+	require.NoError(t, DeleteBlockStoreIndex(blockStorageDir))
+	env = newTestEnv(t, NewConf(blockStorageDir, 0))
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	verifyBlocksAndTransactions(t, blkfileMgrWrapper, blocks)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+	assertIndexDataFormat(t, blockStorageDir, compressedBlocksFormat)
+}
+
+func TestCompressBlockStoreDataFormat(t *testing.T) {
+	blockStorageDir := testPath()
+	blocks := testutil.ConstructTestBlocks(t, 5)
+
+	env := newTestEnv(t, NewConf(blockStorageDir, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	ledgerDir := blkfileMgrWrapper.blockfileMgr.rootDir
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	require.NoError(t, CompressBlockStore(blockStorageDir))
+	format, err := loadBlockfilesFormat(ledgerDir)
+	require.NoError(t, err)
+	require.Equal(t, BlockCompression_ZSTD, format.Compression)
+
+	env = newTestEnv(t, NewConf(blockStorageDir, 0))
+	env.provider.Close()
+	assertIndexDataFormat(t, blockStorageDir, compressedBlocksFormat)
+}
+
+func TestUnknownBlockfilesFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        *BlockfilesFormat
+		expectedError string
+	}{
+		{
+			name:          "unknown version",
+			format:        &BlockfilesFormat{Version: blockfilesFormatVersion + 1, Compression: BlockCompression_ZSTD},
+			expectedError: "have unknown format version 3",
+		},
+		{
+			name:          "unknown compression",
+			format:        &BlockfilesFormat{Version: blockfilesFormatVersion, Compression: BlockCompression(7)},
+			expectedError: "have unknown compression flag 7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockStorageDir := testPath()
+			defer os.RemoveAll(blockStorageDir)
+
+			env := newTestEnv(t, NewConf(blockStorageDir, 0))
+			blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+			blkfileMgrWrapper.addBlocks(testutil.ConstructTestBlocks(t, 1))
+			ledgerDir := blkfileMgrWrapper.blockfileMgr.rootDir
+			blkfileMgrWrapper.close()
+
+			formatBytes, err := proto.Marshal(tt.format)
+			require.NoError(t, err)
+			require.NoError(t, ioutil.WriteFile(filepath.Join(ledgerDir, blockfilesFormatFile), formatBytes, 0644))
+
+			_, err = env.provider.Open("testLedger")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedError)
+			env.provider.Close()
+
+			_, err = NewProvider(NewConf(blockStorageDir, 0), &IndexConfig{AttrsToIndex: attrsToIndex}, &disabled.Provider{})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestFileLocPointerInCompressedBlock(t *testing.T) {
+	flp := newTxLocationPointer(
+		&fileLocPointer{fileSuffixNum: 2, locPointer: locPointer{offset: 100}},
+		&locPointer{offset: 30, bytesLength: 50},
+		true,
+	)
+	b, err := flp.marshal()
+	require.NoError(t, err)
+	unmarshaled := &fileLocPointer{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	require.Equal(t, flp, unmarshaled)
+	require.Equal(t, &fileLocPointer{
+		fileSuffixNum:     2,
+		locPointer:        locPointer{offset: 100, bytesLength: 50},
+		inCompressedBlock: true,
+		blockBytesOffset:  30,
+	}, unmarshaled)
+
+	flp = newTxLocationPointer(
+		&fileLocPointer{fileSuffixNum: 2, locPointer: locPointer{offset: 100}},
+		&locPointer{offset: 30, bytesLength: 50},
+		false,
+	)
+	b, err = flp.marshal()
+	require.NoError(t, err)
+	unmarshaled = &fileLocPointer{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	require.Equal(t, &fileLocPointer{fileSuffixNum: 2, locPointer: locPointer{offset: 130, bytesLength: 50}}, unmarshaled)
+}
+
+func TestSerializedBlockIsNeverMistakenForCompressed(t *testing.T) {
+	for _, block := range testutil.ConstructTestBlocks(t, 50) {
+		blockBytes, _, err := serializeBlock(block)
+		require.NoError(t, err)
+		require.False(t, isCompressedBlock(blockBytes))
+	}
+}
+
+func assertIndexDataFormat(t *testing.T, blockStorageDir, expectedFormat string) {
+	format, exists, err := leveldbhelper.RetrieveDataFormat(BlockStoreIndexPath(blockStorageDir))
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, expectedFormat, format)
+}
+
+// storedBlocksCompression returns, for each block stored in a block file,
+// whether it is compressed
+func storedBlocksCompression(t *testing.T, ledgerDir string, fileNum int) []bool {
+	stream, err := newBlockfileStream(ledgerDir, fileNum, 0)
+	require.NoError(t, err)
+	defer stream.close()
+	var compression []bool
+	for {
+		blockBytes, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		require.NoError(t, err)
+		if blockBytes == nil {
+			return compression
+		}
+		compression = append(compression, placementInfo.compressed)
+	}
+}
+
+func verifyBlocksAndTransactions(t *testing.T, w *testBlockfileMgrWrapper, blocks []*common.Block) {
+	w.testGetBlockByHash(blocks, nil)
+	w.testGetBlockByNumber(blocks, 0, nil)
+	w.testGetBlockByTxID(blocks, nil)
+	for blockNum, block := range blocks {
+		for tranNum, txEnvelopeBytes := range block.Data.Data {
+			txEnvelope, err := protoutil.GetEnvelopeFromBlock(txEnvelopeBytes)
+			require.NoError(t, err)
+
+			txID, err := protoutil.GetOrComputeTxIDFromEnvelope(txEnvelopeBytes)
+			require.NoError(t, err)
+			txEnvelopeByID, err := w.blockfileMgr.retrieveTransactionByID(txID)
+			require.NoError(t, err)
+			require.Equal(t, txEnvelope, txEnvelopeByID)
+
+			txEnvelopeByNum, err := w.blockfileMgr.retrieveTransactionByBlockNumTranNum(uint64(blockNum), uint64(tranNum))
+			require.NoError(t, err)
+			require.Equal(t, txEnvelope, txEnvelopeByNum)
+		}
+	}
+}
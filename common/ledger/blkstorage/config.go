@@ -6,7 +6,14 @@ SPDX-License-Identifier: Apache-2.0
 
 package blkstorage
 
-import "path/filepath"
+import (
+	"hash/fnv"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/pkg/errors"
+)
 
 const (
 	// ChainsDir is the name of the directory containing the channel ledgers.
@@ -20,6 +27,9 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	compressBlocks   bool
+	shardDirs        []string
+	shardMapping     map[string]string
 }
 
 // NewConf constructs new `Conf`.
@@ -28,7 +38,27 @@ func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir: blockStorageDir, maxBlockfileSize: maxBlockfileSize}
+}
+
+// NewConfWithCompression constructs new `Conf` for a `BlockStore` which
+// compresses the blocks it appends to the block files.
+func NewConfWithCompression(blockStorageDir string, maxBlockfileSize int) *Conf {
+	conf := NewConf(blockStorageDir, maxBlockfileSize)
+	conf.compressBlocks = true
+	return conf
+}
+
+// SetShards spreads the block files of the ledgers created from now on
+// across the given directories, which are typically on separate volumes. A
+// ledger is placed in the directory it is mapped to, if any, or in a
+// directory chosen by hashing its id otherwise. The block files of a sharded
+// ledger are linked from the chains directory, so that the ledgers created
+// before remain where they are and that the offline commands keep finding all
+// the ledgers.
+func (conf *Conf) SetShards(dirs []string, mapping map[string]string) {
+	conf.shardDirs = dirs
+	conf.shardMapping = mapping
 }
 
 func (conf *Conf) getIndexDir() string {
@@ -42,3 +72,50 @@ func (conf *Conf) getChainsDir() string {
 func (conf *Conf) getLedgerBlockDir(ledgerid string) string {
 	return filepath.Join(conf.getChainsDir(), ledgerid)
 }
+
+// getShardedLedgerBlockDir returns the directory holding the block files of a
+// new ledger, or an empty string if the ledger is not sharded.
+func (conf *Conf) getShardedLedgerBlockDir(ledgerid string) string {
+	if dir, ok := conf.shardMapping[ledgerid]; ok {
+		return filepath.Join(dir, ChainsDir, ledgerid)
+	}
+	if len(conf.shardDirs) == 0 {
+		return ""
+	}
+	h := fnv.New32a()
+	h.Write([]byte(ledgerid))
+	dir := conf.shardDirs[h.Sum32()%uint32(len(conf.shardDirs))]
+	return filepath.Join(dir, ChainsDir, ledgerid)
+}
+
+// createLedgerBlockDir creates the directory of the block files of a ledger if
+// missing, in the shard of the ledger if it is sharded. It returns true if the
+// directory is empty.
+func (conf *Conf) createLedgerBlockDir(ledgerid string) (bool, error) {
+	ledgerDir := conf.getLedgerBlockDir(ledgerid)
+	shardDir := conf.getShardedLedgerBlockDir(ledgerid)
+	if shardDir == "" || filepath.Clean(shardDir) == filepath.Clean(ledgerDir) {
+		return util.CreateDirIfMissing(ledgerDir)
+	}
+	if _, err := os.Lstat(ledgerDir); err == nil || !os.IsNotExist(err) {
+		// the ledger exists, either sharded or not
+		return util.CreateDirIfMissing(ledgerDir)
+	}
+
+	shardDir, err := filepath.Abs(shardDir)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to resolve shard directory %s", shardDir)
+	}
+	isEmpty, err := util.CreateDirIfMissing(shardDir)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to create shard directory %s", shardDir)
+	}
+	if err := os.MkdirAll(conf.getChainsDir(), 0755); err != nil {
+		return false, errors.Wrapf(err, "failed to create ledger directory %s", conf.getChainsDir())
+	}
+	if err := os.Symlink(shardDir, ledgerDir); err != nil {
+		return false, errors.Wrapf(err, "failed to link shard directory %s", shardDir)
+	}
+	logger.Infof("Block files of ledger [%s] are stored in [%s]", ledgerid, shardDir)
+	return isEmpty, syncDir(conf.getChainsDir())
+}
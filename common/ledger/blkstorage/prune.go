@@ -0,0 +1,212 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+const retainedConfigBlockKeyPrefix = 'c'
+
+var blkPruneInfoKey = []byte("blkPruneInfo")
+
+// pruneInfo records the block files removed from a ledger which retains only
+// its most recent blocks. The block files numbered below firstFileNumber are
+// removed, and the blocks numbered below firstBlockNumber are only available
+// if they are config blocks, which are kept in the index database.
+type pruneInfo struct {
+	firstFileNumber  int
+	firstBlockNumber uint64
+}
+
+func (i *pruneInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	if err := buffer.EncodeVarint(uint64(i.firstFileNumber)); err != nil {
+		return nil, errors.Wrapf(err, "error encoding the firstFileNumber [%d]", i.firstFileNumber)
+	}
+	if err := buffer.EncodeVarint(i.firstBlockNumber); err != nil {
+		return nil, errors.Wrapf(err, "error encoding the firstBlockNumber [%d]", i.firstBlockNumber)
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruneInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	val, err := buffer.DecodeVarint()
+	if err != nil {
+		return err
+	}
+	i.firstFileNumber = int(val)
+	if i.firstBlockNumber, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *pruneInfo) String() string {
+	return fmt.Sprintf("firstFileNumber=[%d], firstBlockNumber=[%d]", i.firstFileNumber, i.firstBlockNumber)
+}
+
+func constructRetainedConfigBlockKey(blockNum uint64) []byte {
+	return append([]byte{retainedConfigBlockKeyPrefix}, util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+func (mgr *blockfileMgr) loadPruneInfo() (*pruneInfo, error) {
+	b, err := mgr.db.Get(blkPruneInfoKey)
+	if b == nil || err != nil {
+		return &pruneInfo{}, err
+	}
+	i := &pruneInfo{}
+	if err := i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (mgr *blockfileMgr) getPruneInfo() *pruneInfo {
+	return mgr.pruneInfo.Load().(*pruneInfo)
+}
+
+// firstRetainedBlockNumber returns the number of the first block available in
+// the block files, all the blocks below being pruned but the config blocks.
+func (mgr *blockfileMgr) firstRetainedBlockNumber() uint64 {
+	return mgr.getPruneInfo().firstBlockNumber
+}
+
+// removePrunedBlockfiles removes the pruned block files that a crash
+// prevented from being removed.
+func (mgr *blockfileMgr) removePrunedBlockfiles() error {
+	for fileNum := 0; fileNum < mgr.getPruneInfo().firstFileNumber; fileNum++ {
+		if err := removeBlockfile(mgr.rootDir, fileNum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prune removes the block files holding only blocks numbered below the given
+// block number, after moving their config blocks to the index database. Only
+// whole block files are removed, so the ledger may keep up to a block file worth
+// of blocks below the given block number. The block files being read back, prune
+// is meant to be called away from the commit path.
+func (mgr *blockfileMgr) prune(belowBlockNum uint64) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	// the block files below the latest one are no longer written to
+	mgr.blkfilesInfoCond.L.Lock()
+	blkfilesInfo := mgr.blockfilesInfo
+	mgr.blkfilesInfoCond.L.Unlock()
+	if blkfilesInfo.noBlockFiles {
+		return nil
+	}
+
+	info := mgr.getPruneInfo()
+	for fileNum := info.firstFileNumber; fileNum < blkfilesInfo.latestFileNumber; fileNum++ {
+		if fileNum+1 == blkfilesInfo.latestFileNumber && blkfilesInfo.latestFileSize == 0 {
+			// the latest block file holds no block yet
+			break
+		}
+		nextFileFirstBlock, err := retrieveFirstBlockNumFromFile(mgr.rootDir, fileNum+1)
+		if err != nil {
+			return err
+		}
+		// the file holds the blocks up to nextFileFirstBlock-1
+		if nextFileFirstBlock > belowBlockNum {
+			break
+		}
+		if err := mgr.retainConfigBlocks(fileNum); err != nil {
+			return err
+		}
+		info = &pruneInfo{firstFileNumber: fileNum + 1, firstBlockNumber: nextFileFirstBlock}
+		b, err := info.marshal()
+		if err != nil {
+			return err
+		}
+		if err := mgr.db.Put(blkPruneInfoKey, b, true); err != nil {
+			return err
+		}
+		mgr.pruneInfo.Store(info)
+		if err := removeBlockfile(mgr.rootDir, fileNum); err != nil {
+			return err
+		}
+		logger.Infof("Pruned block file [%d], first retained block = [%d]", fileNum, nextFileFirstBlock)
+	}
+	return nil
+}
+
+// retainConfigBlocks copies the config blocks of a block file to the index
+// database.
+func (mgr *blockfileMgr) retainConfigBlocks(fileNum int) error {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	batch := mgr.db.NewUpdateBatch()
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			break
+		}
+		block, err := deserializeBlock(blockBytes)
+		if err != nil {
+			return err
+		}
+		if protoutil.IsConfigBlock(block) {
+			batch.Put(constructRetainedConfigBlockKey(block.Header.Number), blockBytes)
+		}
+	}
+	return mgr.db.WriteBatch(batch, true)
+}
+
+// retrieveRetainedConfigBlock returns the config block of the given number
+// kept in the index database once its block file is pruned.
+func (mgr *blockfileMgr) retrieveRetainedConfigBlock(blockNum uint64) (*common.Block, error) {
+	blockBytes, err := mgr.db.Get(constructRetainedConfigBlockKey(blockNum))
+	if err != nil {
+		return nil, err
+	}
+	if blockBytes == nil {
+		return nil, errors.Errorf(
+			"cannot serve block [%d]. The block is pruned. First retained block = [%d]",
+			blockNum, mgr.firstRetainedBlockNumber(),
+		)
+	}
+	return deserializeBlock(blockBytes)
+}
+
+func removeBlockfile(rootDir string, fileNum int) error {
+	err := os.Remove(deriveBlockfilePath(rootDir, fileNum))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing block file [%d]", fileNum)
+	}
+	return nil
+}
+
+// checkNotPruned returns an error if the location points to a pruned block
+// file.
+func (mgr *blockfileMgr) checkNotPruned(lp *fileLocPointer) error {
+	if info := mgr.getPruneInfo(); lp.fileSuffixNum < info.firstFileNumber {
+		return errors.Errorf(
+			"cannot serve the pruned block file [%d]. First retained block = [%d]",
+			lp.fileSuffixNum, info.firstBlockNumber,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+// constructTestBlocksWithConfigBlock returns a series of blocks starting with
+// a genesis block, the block configBlockNum being a config block as well.
+func constructTestBlocksWithConfigBlock(t *testing.T, numBlocks int, configBlockNum uint64) []*common.Block {
+	blocks := testutil.ConstructTestBlocks(t, numBlocks)
+	blocks[configBlockNum] = testutil.ConstructBlockWithTxidHeaderType(
+		t, configBlockNum, blocks[configBlockNum].Header.PreviousHash,
+		[][]byte{[]byte("config")}, []string{"configtx"}, false, common.HeaderType_CONFIG,
+	)
+	for i := configBlockNum + 1; i < uint64(numBlocks); i++ {
+		blocks[i].Header.PreviousHash = protoutil.BlockHeaderHash(blocks[i-1].Header)
+	}
+	return blocks
+}
+
+// testBlockfileSize returns the size of a block file holding three test blocks.
+func testBlockfileSize(t *testing.T) int {
+	block := testutil.ConstructTestBlocks(t, 2)[1]
+	return 3 * (len(protoutil.MarshalOrPanic(block)) + 8)
+}
+
+func TestPrune(t *testing.T) {
+	blocks := constructTestBlocksWithConfigBlock(t, 40, 12)
+	blockfileSize := testBlockfileSize(t)
+
+	blockStorageDir := testPath()
+	defer os.RemoveAll(blockStorageDir)
+	conf := NewConf(blockStorageDir, blockfileSize)
+
+	env := newTestEnv(t, conf)
+	w := newTestBlockfileWrapper(env, "testLedger")
+	w.addBlocks(blocks[:30])
+	require.NoError(t, w.blockfileMgr.prune(20))
+
+	first := w.blockfileMgr.firstRetainedBlockNumber()
+	require.True(t, first > 12 && first <= 20, "first retained block = %d", first)
+	verifyPrunedBlocks(t, w, blocks[:30])
+	w.close()
+	env.provider.Close()
+
+	// the pruned ledger restarts and is pruned further
+	env = newTestEnv(t, conf)
+	defer env.Cleanup()
+	w = newTestBlockfileWrapper(env, "testLedger")
+	defer w.close()
+	require.Equal(t, first, w.blockfileMgr.firstRetainedBlockNumber())
+	verifyPrunedBlocks(t, w, blocks[:30])
+
+	w.addBlocks(blocks[30:])
+	require.Equal(t, first, w.blockfileMgr.firstRetainedBlockNumber())
+	require.NoError(t, w.blockfileMgr.prune(30))
+	require.True(t, w.blockfileMgr.firstRetainedBlockNumber() > first)
+	verifyPrunedBlocks(t, w, blocks)
+}
+
+func TestPruneNothing(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	blockfileSize := testBlockfileSize(t)
+
+	env := newTestEnv(t, NewConf(testPath(), blockfileSize))
+	defer env.Cleanup()
+	w := newTestBlockfileWrapper(env, "testLedger")
+	defer w.close()
+	require.NoError(t, w.blockfileMgr.prune(0))
+	w.addBlocks(blocks)
+
+	// no block is numbered below block 0
+	require.NoError(t, w.blockfileMgr.prune(0))
+	require.Equal(t, uint64(0), w.blockfileMgr.firstRetainedBlockNumber())
+	verifyBlocksAndTransactions(t, w, blocks)
+}
+
+func verifyPrunedBlocks(t *testing.T, w *testBlockfileMgrWrapper, blocks []*common.Block) {
+	mgr := w.blockfileMgr
+	first := mgr.firstRetainedBlockNumber()
+	require.Equal(t, uint64(len(blocks)), mgr.getBlockchainInfo().Height)
+
+	for fileNum := 0; fileNum < mgr.getPruneInfo().firstFileNumber; fileNum++ {
+		_, err := os.Stat(deriveBlockfilePath(mgr.rootDir, fileNum))
+		require.True(t, os.IsNotExist(err))
+	}
+
+	for _, block := range blocks {
+		num := block.Header.Number
+		if num >= first || protoutil.IsConfigBlock(block) {
+			b, err := mgr.retrieveBlockByNumber(num)
+			require.NoError(t, err)
+			require.Equal(t, block, b)
+			header, err := mgr.retrieveBlockHeaderByNumber(num)
+			require.NoError(t, err)
+			require.Equal(t, block.Header, header)
+			continue
+		}
+
+		_, err := mgr.retrieveBlockByNumber(num)
+		require.EqualError(t, err, fmt.Sprintf("cannot serve block [%d]. The block is pruned. First retained block = [%d]", num, first))
+		_, err = mgr.retrieveBlockByHash(protoutil.BlockHeaderHash(block.Header))
+		require.Error(t, err)
+		_, err = mgr.retrieveTransactionByBlockNumTranNum(num, 0)
+		require.Error(t, err)
+	}
+
+	// the iterator fails on the pruned blocks, but serves the config blocks
+	itr, err := mgr.retrieveBlocks(12)
+	require.NoError(t, err)
+	b, err := itr.Next()
+	require.NoError(t, err)
+	require.Equal(t, blocks[12], b)
+	_, err = itr.Next()
+	require.EqualError(t, err, fmt.Sprintf("cannot serve block [13]. The block is pruned. First retained block = [%d]", first))
+	itr.Close()
+
+	itr, err = mgr.retrieveBlocks(first)
+	require.NoError(t, err)
+	defer itr.Close()
+	for _, block := range blocks[first:] {
+		b, err := itr.Next()
+		require.NoError(t, err)
+		require.Equal(t, block, b)
+	}
+}
@@ -55,12 +55,17 @@ func ResetBlockStore(blockStorageDir string) error {
 
 // DeleteBlockStoreIndex deletes block store index file
 func DeleteBlockStoreIndex(blockStorageDir string) error {
-	conf := &Conf{blockStorageDir: blockStorageDir}
-	indexDir := conf.getIndexDir()
+	indexDir := BlockStoreIndexPath(blockStorageDir)
 	logger.Infof("Dropping all contents under the index dir [%s]... if present", indexDir)
 	return fileutil.RemoveContents(indexDir)
 }
 
+// BlockStoreIndexPath returns the path of the leveldb holding the block index of all the channels
+func BlockStoreIndexPath(blockStorageDir string) string {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	return conf.getIndexDir()
+}
+
 func resetToGenesisBlk(ledgerDir string) error {
 	logger.Infof("Resetting ledger [%s] to genesis block", ledgerDir)
 	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
@@ -59,12 +59,7 @@ func newRollbackMgr(blockStorageDir, ledgerID string, indexConfig *IndexConfig,
 
 	r.indexDir = conf.getIndexDir()
 	var err error
-	r.dbProvider, err = leveldbhelper.NewProvider(
-		&leveldbhelper.Conf{
-			DBPath:         r.indexDir,
-			ExpectedFormat: dataFormatVersion(indexConfig),
-		},
-	)
+	r.dbProvider, err = openIndexDB(conf, indexConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +142,9 @@ func addIndexEntriesToBeDeleted(batch *leveldbhelper.UpdateBatch, blockInfo *ser
 	if indexStore.isAttributeIndexed(IndexableAttrBlockHash) {
 		batch.Delete(constructBlockHashKey(protoutil.BlockHeaderHash(blockInfo.blockHeader)))
 	}
+	if indexStore.isAttributeIndexed(IndexableAttrBlockHashSM3) {
+		batch.Delete(constructBlockHashSM3Key(protoutil.BlockHeaderHashSM3(blockInfo.blockHeader)))
+	}
 
 	if indexStore.isAttributeIndexed(IndexableAttrBlockNum) {
 		batch.Delete(constructBlockNumKey(blockInfo.blockHeader.Number))
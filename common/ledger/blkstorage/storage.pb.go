@@ -20,6 +20,31 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type BlockCompression int32
+
+const (
+	BlockCompression_NONE BlockCompression = 0
+	BlockCompression_ZSTD BlockCompression = 1
+)
+
+var BlockCompression_name = map[int32]string{
+	0: "NONE",
+	1: "ZSTD",
+}
+
+var BlockCompression_value = map[string]int32{
+	"NONE": 0,
+	"ZSTD": 1,
+}
+
+func (x BlockCompression) String() string {
+	return proto.EnumName(BlockCompression_name, int32(x))
+}
+
+func (BlockCompression) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{0}
+}
+
 type TxIDIndexValue struct {
 	BlkLocation          []byte   `protobuf:"bytes,1,opt,name=blk_location,json=blkLocation,proto3" json:"blk_location,omitempty"`
 	TxLocation           []byte   `protobuf:"bytes,2,opt,name=tx_location,json=txLocation,proto3" json:"tx_location,omitempty"`
@@ -130,30 +155,86 @@ func (m *BootstrappingSnapshotInfo) GetPreviousBlockHash() []byte {
 	return nil
 }
 
+// blockfilesFormat records the format of the block files of a ledger, once its
+// blocks may be stored compressed.
+type BlockfilesFormat struct {
+	Version              uint32           `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Compression          BlockCompression `protobuf:"varint,2,opt,name=compression,proto3,enum=msgs.BlockCompression" json:"compression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *BlockfilesFormat) Reset()         { *m = BlockfilesFormat{} }
+func (m *BlockfilesFormat) String() string { return proto.CompactTextString(m) }
+func (*BlockfilesFormat) ProtoMessage()    {}
+func (*BlockfilesFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{2}
+}
+
+func (m *BlockfilesFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockfilesFormat.Unmarshal(m, b)
+}
+func (m *BlockfilesFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockfilesFormat.Marshal(b, m, deterministic)
+}
+func (m *BlockfilesFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockfilesFormat.Merge(m, src)
+}
+func (m *BlockfilesFormat) XXX_Size() int {
+	return xxx_messageInfo_BlockfilesFormat.Size(m)
+}
+func (m *BlockfilesFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockfilesFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockfilesFormat proto.InternalMessageInfo
+
+func (m *BlockfilesFormat) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *BlockfilesFormat) GetCompression() BlockCompression {
+	if m != nil {
+		return m.Compression
+	}
+	return BlockCompression_NONE
+}
+
 func init() {
+	proto.RegisterEnum("msgs.BlockCompression", BlockCompression_name, BlockCompression_value)
 	proto.RegisterType((*TxIDIndexValue)(nil), "msgs.txIDIndexValue")
 	proto.RegisterType((*BootstrappingSnapshotInfo)(nil), "msgs.bootstrappingSnapshotInfo")
+	proto.RegisterType((*BlockfilesFormat)(nil), "msgs.blockfilesFormat")
 }
 
 func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 271 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0x41, 0x4b, 0xf3, 0x40,
-	0x10, 0x86, 0xc9, 0xd7, 0x7e, 0x1e, 0xa6, 0xa9, 0xe8, 0x9e, 0xea, 0xc9, 0x1a, 0x3c, 0xf4, 0x50,
-	0x9a, 0x83, 0x20, 0x9e, 0xab, 0x07, 0x03, 0xe2, 0x21, 0x82, 0x07, 0x2f, 0x61, 0x77, 0xb3, 0x4d,
-	0x96, 0x6c, 0x76, 0x96, 0xdd, 0x49, 0x89, 0x57, 0xff, 0x82, 0x7f, 0x58, 0x0c, 0xa1, 0xa5, 0x78,
-	0x9c, 0xe7, 0x7d, 0x60, 0x5e, 0x5e, 0x98, 0x07, 0x42, 0xcf, 0x2b, 0xb5, 0x71, 0x1e, 0x09, 0xd9,
-	0xb4, 0x0d, 0x55, 0x48, 0xbe, 0x22, 0x38, 0xa7, 0x3e, 0x7b, 0xca, 0x6c, 0xa9, 0xfa, 0x77, 0x6e,
-	0x3a, 0xc5, 0x6e, 0x20, 0x16, 0xa6, 0x29, 0x0c, 0x4a, 0x4e, 0x1a, 0xed, 0x22, 0x5a, 0x46, 0xab,
-	0x38, 0x9f, 0x09, 0xd3, 0xbc, 0x8c, 0x88, 0x5d, 0xc3, 0x8c, 0xfa, 0xa3, 0xf1, 0x6f, 0x30, 0x80,
-	0xfa, 0x83, 0xb0, 0x06, 0x46, 0x7d, 0xb1, 0xe7, 0x46, 0x97, 0x03, 0x28, 0x24, 0x96, 0x6a, 0x31,
-	0x59, 0x46, 0xab, 0xff, 0xf9, 0x05, 0xfd, 0x3e, 0x1a, 0x83, 0x47, 0x2c, 0x55, 0xf2, 0x1d, 0xc1,
-	0x95, 0x40, 0xa4, 0x40, 0x9e, 0x3b, 0xa7, 0x6d, 0xf5, 0x66, 0xb9, 0x0b, 0x35, 0x52, 0x66, 0x77,
-	0xc8, 0x12, 0x88, 0x0d, 0x0f, 0xb4, 0x35, 0x28, 0x9b, 0xd7, 0xae, 0x1d, 0xfa, 0x4c, 0xf3, 0x13,
-	0xc6, 0x6e, 0x61, 0x7e, 0xb8, 0x9f, 0x79, 0xa8, 0xc7, 0x4a, 0xa7, 0x90, 0xad, 0xe1, 0xd2, 0x79,
-	0xb5, 0xd7, 0xd8, 0x85, 0xa3, 0x39, 0x19, 0xcc, 0xbf, 0xc1, 0xf6, 0xe1, 0xe3, 0xbe, 0xd2, 0x54,
-	0x77, 0x62, 0x23, 0xb1, 0x4d, 0xeb, 0x4f, 0xa7, 0xbc, 0x51, 0x65, 0xa5, 0x7c, 0xba, 0xe3, 0xc2,
-	0x6b, 0x99, 0x4a, 0x6c, 0x5b, 0xb4, 0xe9, 0x08, 0x85, 0x69, 0xc6, 0x81, 0xc5, 0xd9, 0xb0, 0xf0,
-	0xdd, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd8, 0x44, 0x15, 0xf9, 0x72, 0x01, 0x00, 0x00,
+	// 342 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0x4d, 0x4b, 0xeb, 0x40,
+	0x14, 0x86, 0x6f, 0x6e, 0x7b, 0xaf, 0x72, 0xfa, 0x41, 0x9c, 0x85, 0xd4, 0x95, 0x35, 0x88, 0x14,
+	0x29, 0x09, 0x28, 0x48, 0xd7, 0x6d, 0x15, 0x0b, 0x52, 0x21, 0x95, 0x2e, 0xba, 0x29, 0x33, 0xc9,
+	0xe4, 0x83, 0x4c, 0x72, 0xc2, 0xcc, 0xa4, 0xc4, 0xad, 0x7f, 0xc1, 0x3f, 0x2c, 0x0d, 0x69, 0x6b,
+	0x75, 0x37, 0xe7, 0x79, 0x1f, 0x86, 0xc3, 0x7b, 0xa0, 0xa3, 0x34, 0x4a, 0x1a, 0x72, 0x3b, 0x97,
+	0xa8, 0x91, 0x34, 0x53, 0x15, 0x2a, 0xeb, 0xc3, 0x80, 0xae, 0x2e, 0x67, 0xd3, 0x59, 0xe6, 0xf3,
+	0x72, 0x49, 0x45, 0xc1, 0xc9, 0x15, 0xb4, 0x99, 0x48, 0xd6, 0x02, 0x3d, 0xaa, 0x63, 0xcc, 0x7a,
+	0x46, 0xdf, 0x18, 0xb4, 0xdd, 0x16, 0x13, 0xc9, 0x4b, 0x8d, 0xc8, 0x25, 0xb4, 0x74, 0x79, 0x30,
+	0xfe, 0x56, 0x06, 0xe8, 0x72, 0x2f, 0x0c, 0x81, 0xe8, 0x72, 0xbd, 0xa1, 0x22, 0xf6, 0x2b, 0xb0,
+	0xf6, 0xd0, 0xe7, 0xbd, 0x46, 0xdf, 0x18, 0xfc, 0x73, 0x4d, 0x5d, 0x2e, 0xf7, 0xc1, 0x04, 0x7d,
+	0x6e, 0x7d, 0x1a, 0x70, 0xc1, 0x10, 0xb5, 0xd2, 0x92, 0xe6, 0x79, 0x9c, 0x85, 0x8b, 0x8c, 0xe6,
+	0x2a, 0x42, 0x3d, 0xcb, 0x02, 0x24, 0x16, 0xb4, 0x05, 0x55, 0x7a, 0x2c, 0xd0, 0x4b, 0xe6, 0x45,
+	0x5a, 0xed, 0xd3, 0x74, 0x8f, 0x18, 0xb9, 0x86, 0xce, 0x7e, 0x7e, 0xa6, 0x2a, 0xaa, 0x57, 0x3a,
+	0x86, 0x64, 0x08, 0x67, 0xb9, 0xe4, 0x9b, 0x18, 0x0b, 0x75, 0x30, 0x1b, 0x95, 0xf9, 0x3b, 0xb0,
+	0x02, 0x30, 0xd9, 0x76, 0x08, 0x62, 0xc1, 0xd5, 0x13, 0xca, 0x94, 0x6a, 0xd2, 0x83, 0x93, 0x0d,
+	0x97, 0x6a, 0x57, 0x4b, 0xc7, 0xdd, 0x8d, 0x64, 0x04, 0x2d, 0x0f, 0xd3, 0x5c, 0x72, 0xa5, 0x76,
+	0x95, 0x74, 0xef, 0xce, 0xed, 0x6d, 0xc9, 0x76, 0xf5, 0xcd, 0xe4, 0x90, 0xba, 0xdf, 0xd5, 0xdb,
+	0x1b, 0x30, 0x7f, 0x0a, 0xe4, 0x14, 0x9a, 0xf3, 0xd7, 0xf9, 0xa3, 0xf9, 0x67, 0xfb, 0x5a, 0x2d,
+	0xde, 0xa6, 0xa6, 0x31, 0x1e, 0xad, 0x1e, 0xc2, 0x58, 0x47, 0x05, 0xb3, 0x3d, 0x4c, 0x9d, 0xe8,
+	0x3d, 0xe7, 0x52, 0x70, 0x3f, 0xe4, 0xd2, 0x09, 0x28, 0x93, 0xb1, 0xe7, 0x78, 0x98, 0xa6, 0x98,
+	0x39, 0x35, 0x64, 0x22, 0xa9, 0x0f, 0xce, 0xfe, 0x57, 0x17, 0xbf, 0xff, 0x1a, 0x00, 0x17, 0x06,
+	0xf8, 0x3c, 0x02, 0x02, 0x00, 0x00,
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
 )
 
 type blockStoreProvider interface {
@@ -23,6 +24,7 @@ type blockStoreProvider interface {
 type fileLedgerFactory struct {
 	blkstorageProvider blockStoreProvider
 	ledgers            map[string]blockledger.ReadWriter
+	blockStores        map[string]*blkstorage.BlockStore
 	mutex              sync.Mutex
 }
 
@@ -44,9 +46,38 @@ func (flf *fileLedgerFactory) GetOrCreate(chainID string) (blockledger.ReadWrite
 	}
 	ledger = NewFileLedger(blockStore)
 	flf.ledgers[key] = ledger
+	flf.blockStores[key] = blockStore
 	return ledger, nil
 }
 
+// CloseLedger releases the resources of the ledger of a channel, which is opened
+// again by the next call to GetOrCreate. The iterators over the ledger must have
+// been closed beforehand.
+func (flf *fileLedgerFactory) CloseLedger(channelID string) {
+	flf.mutex.Lock()
+	defer flf.mutex.Unlock()
+
+	blockStore, ok := flf.blockStores[channelID]
+	if !ok {
+		return
+	}
+	blockStore.Shutdown()
+	delete(flf.blockStores, channelID)
+	delete(flf.ledgers, channelID)
+}
+
+// LedgerSize returns the disk space in bytes used by the block files of the
+// ledger of a channel, which must be open
+func (flf *fileLedgerFactory) LedgerSize(channelID string) (int64, error) {
+	flf.mutex.Lock()
+	blockStore, ok := flf.blockStores[channelID]
+	flf.mutex.Unlock()
+	if !ok {
+		return 0, errors.Errorf("ledger of channel %s is not open", channelID)
+	}
+	return blockStore.Size()
+}
+
 // ChannelIDs returns the channel IDs the factory is aware of
 func (flf *fileLedgerFactory) ChannelIDs() []string {
 	channelIDs, err := flf.blkstorageProvider.List()
@@ -75,5 +106,6 @@ func New(directory string, metricsProvider metrics.Provider) (blockledger.Factor
 	return &fileLedgerFactory{
 		blkstorageProvider: p,
 		ledgers:            make(map[string]blockledger.ReadWriter),
+		blockStores:        make(map[string]*blkstorage.BlockStore),
 	}, nil
 }
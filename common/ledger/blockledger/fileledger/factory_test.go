@@ -78,3 +78,22 @@ func TestMultiReinitialization(t *testing.T) {
 	assert.Equal(t, 3, len(flf.ChannelIDs()), "Expected channel to be recovered")
 	flf.Close()
 }
+
+func TestLedgerSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.NoError(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(dir)
+
+	flf, err := New(dir, &disabled.Provider{})
+	assert.NoError(t, err)
+	defer flf.Close()
+
+	_, err = flf.(*fileLedgerFactory).LedgerSize("testchannelid")
+	assert.EqualError(t, err, "ledger of channel testchannelid is not open")
+
+	_, err = flf.GetOrCreate("testchannelid")
+	assert.NoError(t, err)
+	size, err := flf.(*fileLedgerFactory).LedgerSize("testchannelid")
+	assert.NoError(t, err)
+	assert.Zero(t, size)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/pkg/errors"
 )
 
 var logger = flogging.MustGetLogger("common.ledger.blockledger.file")
@@ -29,6 +30,7 @@ type FileLedgerBlockStore interface {
 	GetBlockchainInfo() (*cb.BlockchainInfo, error)
 	RetrieveBlocks(startBlockNumber uint64) (ledger.ResultsIterator, error)
 	RetrieveBlockByNumber(blockNum uint64) (*cb.Block, error)
+	RetrieveBlockByTxID(txID string) (*cb.Block, error)
 }
 
 // NewFileLedger creates a new FileLedger for interaction with the ledger
@@ -82,6 +84,15 @@ func (fl *FileLedger) Iterator(startPosition *ab.SeekPosition) (blockledger.Iter
 		if startingBlockNumber > height {
 			return &blockledger.NotFoundErrorIterator{}, 0
 		}
+	case *ab.SeekPosition_Transaction:
+		// the block index resolves the block the transaction was committed in,
+		// which fails when the transaction IDs are not indexed
+		block, err := fl.blockStore.RetrieveBlockByTxID(start.Transaction.TxId)
+		if err != nil {
+			logger.Debugw("Failed to retrieve block by transaction ID", "txID", start.Transaction.TxId, "error", err)
+			return &blockledger.NotFoundErrorIterator{}, 0
+		}
+		startingBlockNumber = block.Header.Number
 	default:
 		return &blockledger.NotFoundErrorIterator{}, 0
 	}
@@ -114,6 +125,25 @@ func (fl *FileLedger) Append(block *cb.Block) error {
 	return err
 }
 
+// FirstRetainedBlock returns the number of the first block kept by a ledger
+// which retains only its most recent blocks and its config blocks, 0 if the
+// ledger retains all its blocks.
+func (fl *FileLedger) FirstRetainedBlock() uint64 {
+	if pruned, ok := fl.blockStore.(blockledger.Pruned); ok {
+		return pruned.FirstRetainedBlock()
+	}
+	return 0
+}
+
+// Prune removes the blocks numbered below the given block number, but the
+// config blocks, if the block store supports it.
+func (fl *FileLedger) Prune(belowBlockNum uint64) error {
+	if pruned, ok := fl.blockStore.(blockledger.Pruned); ok {
+		return pruned.Prune(belowBlockNum)
+	}
+	return errors.New("the block store does not support pruning")
+}
+
 func (fl *FileLedger) RetrieveBlockByNumber(blockNumber uint64) (*cb.Block, error) {
 	return fl.blockStore.RetrieveBlockByNumber(blockNumber)
 }
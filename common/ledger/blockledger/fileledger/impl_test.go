@@ -177,6 +177,32 @@ func TestReinitialization(t *testing.T) {
 	assert.Equal(t, protoutil.BlockHeaderHash(b1.Header), protoutil.BlockHeaderHash(block.Header), "Block hashes did no match")
 }
 
+func TestCloseLedger(t *testing.T) {
+	tev, ledger1 := initialize(t)
+	defer tev.tearDown()
+
+	envelope := getSampleEnvelopeWithSignatureHeader()
+	ledger1.Append(blockledger.CreateNextBlock(ledger1, []*cb.Envelope{envelope}))
+
+	flf := tev.flf.(*fileLedgerFactory)
+	flf.CloseLedger("testchannelid")
+	flf.CloseLedger("testchannelid")
+	flf.CloseLedger("nonexistent")
+	assert.Empty(t, flf.ledgers)
+
+	// the ledger is opened again, with the blocks appended before it was closed
+	fl, err := flf.GetOrCreate("testchannelid")
+	assert.NoError(t, err)
+	assert.NotEqual(t, ledger1, fl)
+	assert.Equal(t, uint64(2), fl.Height())
+
+	b2 := blockledger.CreateNextBlock(fl, []*cb.Envelope{envelope})
+	assert.NoError(t, fl.Append(b2))
+	block := blockledger.GetBlock(fl, 2)
+	assert.NotNil(t, block)
+	assert.Equal(t, protoutil.BlockHeaderHash(b2.Header), protoutil.BlockHeaderHash(block.Header))
+}
+
 func TestAddition(t *testing.T) {
 	tev, fl := initialize(t)
 	defer tev.tearDown()
@@ -245,6 +271,36 @@ func TestBlockedRetrieval(t *testing.T) {
 	assert.Equal(t, uint64(2), block.Header.Number, "Expected to successfully retrieve the third block")
 }
 
+func TestTransactionRetrieval(t *testing.T) {
+	seekTx := &ab.SeekPosition{Type: &ab.SeekPosition_Transaction{Transaction: &ab.SeekTransaction{TxId: "txid"}}}
+
+	resultsIterator := &mockBlockStoreIterator{}
+	resultsIterator.On("Close").Return()
+	fl := &FileLedger{
+		blockStore: &mockBlockStore{
+			blockchainInfo:  &cb.BlockchainInfo{Height: uint64(10)},
+			block:           &cb.Block{Header: &cb.BlockHeader{Number: 5}},
+			resultsIterator: resultsIterator,
+		},
+		signal: make(chan struct{}),
+	}
+	it, num := fl.Iterator(seekTx)
+	defer it.Close()
+	assert.IsType(t, &fileLedgerIterator{}, it)
+	assert.Equal(t, uint64(5), num, "Expected block iterator at the block containing the transaction")
+
+	fl.blockStore.(*mockBlockStore).defaultError = errors.New("no such transaction ID")
+	it, num = fl.Iterator(seekTx)
+	assert.IsType(t, &blockledger.NotFoundErrorIterator{}, it)
+	assert.Zero(t, num)
+
+	// the transaction IDs are not indexed by the orderer ledger
+	tev, fl := initialize(t)
+	defer tev.tearDown()
+	it, _ = fl.Iterator(seekTx)
+	assert.IsType(t, &blockledger.NotFoundErrorIterator{}, it)
+}
+
 func TestBlockstoreError(t *testing.T) {
 	// Since this test only ensures failed GetBlockchainInfo
 	// is properly handled. We don't bother creating fully
@@ -318,3 +374,27 @@ func getSampleEnvelopeWithSignatureHeader() *cb.Envelope {
 	payloadBytes := protoutil.MarshalOrPanic(payload)
 	return &cb.Envelope{Payload: payloadBytes}
 }
+
+func TestPrune(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.NoError(t, err)
+	defer os.RemoveAll(name)
+
+	p, err := New(name, &disabled.Provider{})
+	assert.NoError(t, err)
+	defer p.Close()
+	l, err := p.GetOrCreate("testchannelid")
+	assert.NoError(t, err)
+	fl := l.(*FileLedger)
+	assert.NoError(t, fl.Append(genesisBlock))
+
+	// the ledger holds a single block file, which is never pruned
+	var _ blockledger.Pruned = fl
+	assert.NoError(t, fl.Prune(1))
+	assert.Zero(t, fl.FirstRetainedBlock())
+
+	// a block store which retains all its blocks
+	fl = NewFileLedger(&mockBlockStore{})
+	assert.Zero(t, fl.FirstRetainedBlock())
+	assert.EqualError(t, fl.Prune(1), "the block store does not support pruning")
+}
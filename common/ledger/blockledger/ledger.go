@@ -44,6 +44,19 @@ type Reader interface {
 	RetrieveBlockByNumber(blockNumber uint64) (*cb.Block, error)
 }
 
+// Pruned is implemented by the ledgers which may retain only their most recent
+// blocks, along with their config blocks
+type Pruned interface {
+	// FirstRetainedBlock returns the number of the first block kept by the
+	// ledger, the blocks below being pruned but the config blocks
+	FirstRetainedBlock() uint64
+
+	// Prune removes the blocks numbered below the given block number, but the
+	// config blocks. Whole block files are removed, so some of these blocks
+	// may be kept until a later call
+	Prune(belowBlockNum uint64) error
+}
+
 // Writer allows the caller to modify the ledger
 type Writer interface {
 	// Append a new block to the ledger
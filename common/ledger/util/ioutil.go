@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/hyperledger/fabric/common/flogging"
@@ -74,6 +75,12 @@ func ListSubdirs(dirPath string) ([]string, error) {
 		return nil, errors.Wrapf(err, "error reading dir %s", dirPath)
 	}
 	for _, f := range files {
+		if f.Mode()&os.ModeSymlink != 0 {
+			// follow the links to directories
+			if target, err := os.Stat(filepath.Join(dirPath, f.Name())); err == nil {
+				f = target
+			}
+		}
 		if f.IsDir() {
 			subdirs = append(subdirs, f.Name())
 		}
@@ -158,6 +158,29 @@ func (dbInst *DB) GetIterator(startKey []byte, endKey []byte) iterator.Iterator
 	return dbInst.db.NewIterator(&goleveldbutil.Range{Start: startKey, Limit: endKey}, dbInst.readOpts)
 }
 
+// GetSnapshot returns a point-in-time, read-only view of the key-value store.
+// The snapshot should be released after the use
+func (dbInst *DB) GetSnapshot() (*leveldb.Snapshot, error) {
+	dbInst.mutex.RLock()
+	defer dbInst.mutex.RUnlock()
+	snapshot, err := dbInst.db.GetSnapshot()
+	if err != nil {
+		return nil, errors.Wrap(err, "error obtaining leveldb snapshot")
+	}
+	return snapshot, nil
+}
+
+// Compact compacts the whole key space of the db, which discards the deleted and
+// overwritten entries and rewrites the table files
+func (dbInst *DB) Compact() error {
+	dbInst.mutex.RLock()
+	defer dbInst.mutex.RUnlock()
+	if err := dbInst.db.CompactRange(goleveldbutil.Range{}); err != nil {
+		return errors.Wrapf(err, "error while compacting leveldb [%s]", dbInst.conf.DBPath)
+	}
+	return nil
+}
+
 // WriteBatch writes a batch
 func (dbInst *DB) WriteBatch(batch *leveldb.Batch, sync bool) error {
 	dbInst.mutex.RLock()
@@ -9,12 +9,15 @@ package leveldbhelper
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/ledger/dataformat"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
+	goleveldbutil "github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const (
@@ -120,6 +123,29 @@ func (p *Provider) GetDataFormat() (string, error) {
 	return string(f), err
 }
 
+// SetDataFormat changes the format of the data, once the data is converted to the new format
+func (p *Provider) SetDataFormat(format string) error {
+	return p.GetDBHandle(internalDBName).Put(formatVersionKey, []byte(format), true)
+}
+
+// RetrieveDataFormat returns the format of the data of the leveldb at the given path
+// without checking it against an expected format. The returned bool is false if
+// no leveldb exists at the path, in which case none is created.
+func RetrieveDataFormat(dbPath string) (string, bool, error) {
+	if _, err := os.Stat(filepath.Join(dbPath, "CURRENT")); err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, errors.Wrapf(err, "error while checking for a leveldb at path [%s]", dbPath)
+	}
+	db := CreateDB(&Conf{DBPath: dbPath})
+	db.Open()
+	defer db.Close()
+	internalDB := &DBHandle{db: db, dbName: internalDBName}
+	f, err := internalDB.Get(formatVersionKey)
+	return string(f), true, err
+}
+
 // GetDBHandle returns a handle to a named db
 func (p *Provider) GetDBHandle(dbName string) *DBHandle {
 	p.mux.Lock()
@@ -137,6 +163,11 @@ func (p *Provider) GetDBHandle(dbName string) *DBHandle {
 	return dbHandle
 }
 
+// Compact compacts the underlying leveldb, which is shared by all the named dbs
+func (p *Provider) Compact() error {
+	return p.db.Compact()
+}
+
 // Close closes the underlying leveldb
 func (p *Provider) Close() {
 	p.db.Close()
@@ -204,6 +235,18 @@ func (h *DBHandle) DeleteAll() error {
 	return nil
 }
 
+// CopyTo copies all the keys that belong to the channel (dbName) to the leveldb at the given path,
+// which is created with the data format of this leveldb if missing. The keys are copied from a
+// snapshot of the db, hence the updates applied during the copy are not copied.
+func (h *DBHandle) CopyTo(dbPath string) error {
+	snapshot, err := h.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.Release()
+	return snapshot.CopyTo(dbPath)
+}
+
 // NewUpdateBatch returns a new UpdateBatch that can be used to update the db
 func (h *DBHandle) NewUpdateBatch() *UpdateBatch {
 	return &UpdateBatch{
@@ -242,6 +285,16 @@ func (h *DBHandle) GetIterator(startKey []byte, endKey []byte) (*Iterator, error
 	return &Iterator{h.dbName, itr}, nil
 }
 
+// GetSnapshot returns a point-in-time, read-only view of the named db. The view is not affected
+// by the writes that happen after this call. The snapshot should be released after the use
+func (h *DBHandle) GetSnapshot() (*Snapshot, error) {
+	snapshot, err := h.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{dbName: h.dbName, snapshot: snapshot}, nil
+}
+
 // Close closes the DBHandle after its db data have been deleted
 func (h *DBHandle) Close() {
 	if h.closeFunc != nil {
@@ -287,6 +340,94 @@ func (itr *Iterator) Seek(key []byte) bool {
 	return itr.Iterator.Seek(levelKey)
 }
 
+// Snapshot is a point-in-time, read-only view of a named db
+type Snapshot struct {
+	dbName   string
+	snapshot *leveldb.Snapshot
+}
+
+// Get returns the value for the given key as of the time the snapshot was taken
+func (s *Snapshot) Get(key []byte) ([]byte, error) {
+	value, err := s.snapshot.Get(constructLevelKey(s.dbName, key), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving leveldb key [%#v] from snapshot", key)
+	}
+	return value, nil
+}
+
+// GetIterator gets an handle to iterator over the snapshot. The iterator should be released after the use.
+// The semantics of startKey and endKey are same as of the function DBHandle.GetIterator
+func (s *Snapshot) GetIterator(startKey []byte, endKey []byte) (*Iterator, error) {
+	sKey := constructLevelKey(s.dbName, startKey)
+	eKey := constructLevelKey(s.dbName, endKey)
+	if endKey == nil {
+		eKey[len(eKey)-1] = lastKeyIndicator
+	}
+	itr := s.snapshot.NewIterator(&goleveldbutil.Range{Start: sKey, Limit: eKey}, nil)
+	if err := itr.Error(); err != nil {
+		itr.Release()
+		return nil, errors.Wrapf(err, "internal leveldb error while obtaining snapshot iterator")
+	}
+	return &Iterator{s.dbName, itr}, nil
+}
+
+// CopyTo copies all the keys of the snapshot to the leveldb at the given path, which is created
+// with the data format of the source leveldb if missing
+func (s *Snapshot) CopyTo(dbPath string) error {
+	format, err := s.snapshot.Get(constructLevelKey(internalDBName, formatVersionKey), nil)
+	if err != nil && err != leveldb.ErrNotFound {
+		return errors.Wrapf(err, "error retrieving leveldb key [%#v] from snapshot", formatVersionKey)
+	}
+	target, err := NewProvider(&Conf{DBPath: dbPath, ExpectedFormat: string(format)})
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	iter, err := s.GetIterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	// use leveldb iterator directly to be more efficient, the keys
+	// keep the same dbName prefix in the target leveldb
+	dbIter := iter.Iterator
+	numKeys := 0
+	batchSize := 0
+	batch := &leveldb.Batch{}
+	for dbIter.Next() {
+		key, value := dbIter.Key(), dbIter.Value()
+		numKeys++
+		batchSize = batchSize + len(key) + len(value)
+		batch.Put(key, value)
+		if batchSize >= maxBatchSize {
+			if err := target.db.WriteBatch(batch, false); err != nil {
+				return err
+			}
+			batchSize = 0
+			batch = &leveldb.Batch{}
+		}
+	}
+	if err := dbIter.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while retrieving data from db iterator")
+	}
+	if err := target.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Debugf("Copied %d entries for channel %s from snapshot to leveldb %s", numKeys, s.dbName, dbPath)
+	return nil
+}
+
+// Release releases the snapshot. The iterators obtained from the snapshot
+// remain usable and are expected to be released by the caller
+func (s *Snapshot) Release() {
+	s.snapshot.Release()
+}
+
 func constructLevelKey(dbName string, key []byte) []byte {
 	return append(append([]byte(dbName), dbNameKeySep...), key...)
 }
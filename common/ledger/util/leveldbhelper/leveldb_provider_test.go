@@ -294,6 +294,130 @@ func TestDeleteAll(t *testing.T) {
 	require.EqualError(t, db2.DeleteAll(), "internal leveldb error while obtaining db iterator: leveldb: closed")
 }
 
+func TestCopyTo(t *testing.T) {
+	testPath := testDBPath + "/source"
+	targetPath := testDBPath + "/target"
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+
+	p, err := NewProvider(&Conf{DBPath: testPath, ExpectedFormat: dataformat.CurrentFormat})
+	require.NoError(t, err)
+	defer p.Close()
+	db1 := p.GetDBHandle("db1")
+	db2 := p.GetDBHandle("db2")
+	// db1 is copied in multiple batches (each long key has 125 bytes)
+	for i := 0; i < 10000; i++ {
+		require.NoError(t, db1.Put([]byte(createTestLongKey(i)), []byte(createTestValue("db1", i)), false))
+	}
+	for i := 0; i < 20; i++ {
+		require.NoError(t, db2.Put([]byte(createTestKey(i)), []byte(createTestValue("db2", i)), false))
+	}
+
+	require.NoError(t, db1.CopyTo(targetPath))
+
+	// the target leveldb has the data format of the source and only the keys of the copied db
+	target, err := NewProvider(&Conf{DBPath: targetPath, ExpectedFormat: dataformat.CurrentFormat})
+	require.NoError(t, err)
+	defer target.Close()
+	itr, err := target.GetDBHandle("db1").GetIterator(nil, nil)
+	require.NoError(t, err)
+	checkItrResults(t, itr, createTestLongKeys(0, 9999), createTestValues("db1", 0, 9999))
+	itr.Release()
+	itr, err = target.GetDBHandle("db2").GetIterator(nil, nil)
+	require.NoError(t, err)
+	checkItrResults(t, itr, nil, nil)
+	itr.Release()
+
+	// a snapshot copies the keys as of the time it was taken
+	snapshot, err := db2.GetSnapshot()
+	require.NoError(t, err)
+	require.NoError(t, db2.Put([]byte(createTestKey(20)), []byte(createTestValue("db2", 20)), false))
+	snapshotTargetPath := testDBPath + "/snapshot-target"
+	require.NoError(t, snapshot.CopyTo(snapshotTargetPath))
+	snapshot.Release()
+	snapshotTarget, err := NewProvider(&Conf{DBPath: snapshotTargetPath, ExpectedFormat: dataformat.CurrentFormat})
+	require.NoError(t, err)
+	defer snapshotTarget.Close()
+	itr, err = snapshotTarget.GetDBHandle("db2").GetIterator(nil, nil)
+	require.NoError(t, err)
+	checkItrResults(t, itr, createTestKeys(0, 19), createTestValues("db2", 0, 19))
+	itr.Release()
+
+	// negative test
+	p.Close()
+	require.EqualError(t, db2.CopyTo(targetPath), "error obtaining leveldb snapshot: leveldb: closed")
+}
+
+func TestCompact(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	p := env.provider
+
+	db1 := p.GetDBHandle("db1")
+	db2 := p.GetDBHandle("db2")
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, db1.Put([]byte(createTestLongKey(i)), []byte(createTestValue("db1", i)), false))
+	}
+	for i := 0; i < 20; i++ {
+		require.NoError(t, db2.Put([]byte(createTestKey(i)), []byte(createTestValue("db2", i)), false))
+	}
+	require.NoError(t, db1.DeleteAll())
+
+	require.NoError(t, p.Compact())
+
+	// the compaction keeps the live keys of all the dbs
+	itr, err := db1.GetIterator(nil, nil)
+	require.NoError(t, err)
+	checkItrResults(t, itr, nil, nil)
+	itr.Release()
+	itr, err = db2.GetIterator(nil, nil)
+	require.NoError(t, err)
+	checkItrResults(t, itr, createTestKeys(0, 19), createTestValues("db2", 0, 19))
+	itr.Release()
+
+	// negative test
+	p.Close()
+	require.EqualError(t, p.Compact(), "error while compacting leveldb ["+testDBPath+"]: leveldb: closed")
+}
+
+func TestSnapshot(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	p := env.provider
+
+	db1 := p.GetDBHandle("db1")
+	db2 := p.GetDBHandle("db2")
+	for i := 0; i < 10; i++ {
+		require.NoError(t, db1.Put([]byte(createTestKey(i)), []byte(createTestValue("db1", i)), false))
+		require.NoError(t, db2.Put([]byte(createTestKey(i)), []byte(createTestValue("db2", i)), false))
+	}
+
+	snapshot, err := db1.GetSnapshot()
+	require.NoError(t, err)
+
+	// updates after taking the snapshot should not be visible in the snapshot
+	require.NoError(t, db1.Put([]byte(createTestKey(10)), []byte(createTestValue("db1", 10)), false))
+	require.NoError(t, db1.Put([]byte(createTestKey(2)), []byte("updated-value"), false))
+	require.NoError(t, db1.Delete([]byte(createTestKey(3)), false))
+
+	val, err := snapshot.Get([]byte(createTestKey(2)))
+	require.NoError(t, err)
+	require.Equal(t, createTestValue("db1", 2), string(val))
+	val, err = snapshot.Get([]byte(createTestKey(10)))
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	itr, err := snapshot.GetIterator([]byte(createTestKey(2)), nil)
+	require.NoError(t, err)
+	defer itr.Release()
+	snapshot.Release()
+	checkItrResults(t, itr, createTestKeys(2, 9), createTestValues("db1", 2, 9))
+
+	val, err = db1.Get([]byte(createTestKey(2)))
+	require.NoError(t, err)
+	require.Equal(t, "updated-value", string(val))
+}
+
 func TestFormatCheck(t *testing.T) {
 	testCases := []struct {
 		dataFormat     string
@@ -348,6 +472,45 @@ func TestFormatCheck(t *testing.T) {
 	}
 }
 
+func TestRetrieveDataFormat(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+
+	_, exists, err := RetrieveDataFormat(testDBPath)
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = os.Stat(testDBPath)
+	require.True(t, os.IsNotExist(err), "no leveldb should be created")
+
+	p, err := NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.NoError(t, err)
+	p.Close()
+
+	format, exists, err := RetrieveDataFormat(testDBPath)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "2.0", format)
+}
+
+func TestSetDataFormat(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+
+	p, err := NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.NoError(t, err)
+	require.NoError(t, p.SetDataFormat("2.1"))
+	p.Close()
+
+	_, err = NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.IsType(t, &dataformat.ErrFormatMismatch{}, err)
+	p, err = NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.1"})
+	require.NoError(t, err)
+	defer p.Close()
+	f, err := p.GetDataFormat()
+	require.NoError(t, err)
+	require.Equal(t, "2.1", f)
+}
+
 func TestClose(t *testing.T) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+// An ExemplarHistogram is a Histogram that can retain exemplars of its
+// observations. An exemplar identifies a sample of an observed value, such as
+// the transaction a latency was measured for, and links a bucket of the
+// histogram to it.
+type ExemplarHistogram interface {
+	Histogram
+
+	// ObserveWithExemplar records a value along with the labels of an exemplar
+	// identifying it.
+	ObserveWithExemplar(value float64, exemplarLabels map[string]string)
+}
+
+// ObserveWithExemplar records a value in a histogram along with the labels of
+// an exemplar when the histogram supports exemplars, and only the value
+// otherwise.
+func ObserveWithExemplar(h Histogram, value float64, exemplarLabels map[string]string) {
+	if eh, ok := h.(ExemplarHistogram); ok {
+		eh.ObserveWithExemplar(value, exemplarLabels)
+		return
+	}
+	h.Observe(value)
+}
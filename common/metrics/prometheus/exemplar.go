@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package prometheus
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// An Exemplar is the most recent observation of a bucket of a histogram
+// recorded with the labels identifying it.
+type Exemplar struct {
+	// Metric is the fully qualified name of the histogram.
+	Metric string `json:"metric"`
+	// Labels are the labels of the histogram the observation was recorded with.
+	Labels map[string]string `json:"labels,omitempty"`
+	// UpperBound is the upper bound of the bucket of the observation, formatted
+	// like the le label of the bucket.
+	UpperBound string `json:"le"`
+	// Value is the observed value.
+	Value float64 `json:"value"`
+	// ExemplarLabels identify the observation.
+	ExemplarLabels map[string]string `json:"exemplar"`
+	// Timestamp is the time of the observation.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Exemplars retains the most recent exemplar of each bucket of the histograms
+// of a Provider, and serves them as JSON. As the version of the Prometheus
+// exposition format in use does not support exemplars, they are served apart
+// from the metrics.
+type Exemplars struct {
+	mutex      sync.Mutex
+	histograms []*histogramExemplars
+}
+
+// NewExemplars creates an empty exemplar store.
+func NewExemplars() *Exemplars {
+	return &Exemplars{}
+}
+
+func (e *Exemplars) register(name string, buckets []float64) *histogramExemplars {
+	if len(buckets) == 0 {
+		buckets = prom.DefBuckets
+	}
+	h := &histogramExemplars{
+		name:      name,
+		buckets:   buckets,
+		exemplars: map[exemplarKey]*Exemplar{},
+	}
+
+	e.mutex.Lock()
+	e.histograms = append(e.histograms, h)
+	e.mutex.Unlock()
+
+	return h
+}
+
+// Exemplars returns the retained exemplars, sorted by metric, labels and
+// bucket.
+func (e *Exemplars) Exemplars() []Exemplar {
+	e.mutex.Lock()
+	histograms := append([]*histogramExemplars(nil), e.histograms...)
+	e.mutex.Unlock()
+	sort.SliceStable(histograms, func(i, j int) bool { return histograms[i].name < histograms[j].name })
+
+	var exemplars []Exemplar
+	for _, h := range histograms {
+		exemplars = append(exemplars, h.list()...)
+	}
+	return exemplars
+}
+
+// ServeHTTP serves the retained exemplars as a JSON array.
+func (e *Exemplars) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		resp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	exemplars := e.Exemplars()
+	if exemplars == nil {
+		exemplars = []Exemplar{}
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(resp).Encode(exemplars)
+}
+
+type histogramExemplars struct {
+	name    string
+	buckets []float64
+
+	mutex     sync.Mutex
+	exemplars map[exemplarKey]*Exemplar
+}
+
+type exemplarKey struct {
+	labels string
+	bucket int
+}
+
+func (h *histogramExemplars) record(labelValues []string, value float64, exemplarLabels map[string]string) {
+	labels := labelsToMap(labelValues)
+	bucket := sort.SearchFloat64s(h.buckets, value)
+	upperBound := math.Inf(1)
+	if bucket < len(h.buckets) {
+		upperBound = h.buckets[bucket]
+	}
+
+	exemplar := &Exemplar{
+		Metric:         h.name,
+		Labels:         labels,
+		UpperBound:     strconv.FormatFloat(upperBound, 'g', -1, 64),
+		Value:          value,
+		ExemplarLabels: exemplarLabels,
+		Timestamp:      time.Now(),
+	}
+
+	h.mutex.Lock()
+	h.exemplars[exemplarKey{labels: labelsKey(labels), bucket: bucket}] = exemplar
+	h.mutex.Unlock()
+}
+
+func (h *histogramExemplars) list() []Exemplar {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make([]exemplarKey, 0, len(h.exemplars))
+	for key := range h.exemplars {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].labels != keys[j].labels {
+			return keys[i].labels < keys[j].labels
+		}
+		return keys[i].bucket < keys[j].bucket
+	})
+
+	exemplars := make([]Exemplar, len(keys))
+	for i, key := range keys {
+		exemplars[i] = *h.exemplars[key]
+	}
+	return exemplars
+}
+
+// labelsToMap converts label name and value pairs to a map, using unknown for
+// a missing value like the histograms do.
+func labelsToMap(labelValues []string) map[string]string {
+	if len(labelValues) == 0 {
+		return nil
+	}
+	labels := map[string]string{}
+	for i := 0; i < len(labelValues); i += 2 {
+		value := "unknown"
+		if i+1 < len(labelValues) {
+			value = labelValues[i+1]
+		}
+		labels[labelValues[i]] = value
+	}
+	return labels
+}
+
+func labelsKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(labels[name])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package prometheus_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	commonmetrics "github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/common/metrics/prometheus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var _ = Describe("Exemplars", func() {
+	var (
+		server        *httptest.Server
+		client        *http.Client
+		exemplars     *prometheus.Exemplars
+		p             *prometheus.Provider
+		histogramOpts commonmetrics.HistogramOpts
+	)
+
+	BeforeEach(func() {
+		registry := prom.NewRegistry()
+		prom.DefaultRegisterer = registry
+		prom.DefaultGatherer = registry
+
+		exemplars = prometheus.NewExemplars()
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		mux.Handle("/metrics/exemplars", exemplars)
+		server = httptest.NewServer(mux)
+		client = server.Client()
+
+		p = &prometheus.Provider{Exemplars: exemplars}
+		histogramOpts = commonmetrics.HistogramOpts{
+			Namespace:  "peer",
+			Subsystem:  "playground",
+			Name:       "histogram_name",
+			Help:       "This is some help text for the histogram",
+			Buckets:    []float64{1, 5},
+			LabelNames: []string{"channel"},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	getExemplars := func() []prometheus.Exemplar {
+		resp, err := client.Get(fmt.Sprintf("http://%s/metrics/exemplars", server.Listener.Addr().String()))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+
+		var result []prometheus.Exemplar
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	It("retains the most recent exemplar of each bucket", func() {
+		histogram := p.NewHistogram(histogramOpts)
+		commonmetrics.ObserveWithExemplar(histogram.With("channel", "b"), 0.5, map[string]string{"txid": "tx1"})
+		commonmetrics.ObserveWithExemplar(histogram.With("channel", "a"), 0.7, map[string]string{"txid": "tx2"})
+		commonmetrics.ObserveWithExemplar(histogram.With("channel", "a"), 0.9, map[string]string{"txid": "tx3"})
+		commonmetrics.ObserveWithExemplar(histogram.With("channel", "a"), 7, map[string]string{"txid": "tx4"})
+		histogram.With("channel", "a").Observe(3)
+
+		result := getExemplars()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Metric).To(Equal("peer_playground_histogram_name"))
+		Expect(result[0].Labels).To(Equal(map[string]string{"channel": "a"}))
+		Expect(result[0].UpperBound).To(Equal("1"))
+		Expect(result[0].Value).To(Equal(0.9))
+		Expect(result[0].ExemplarLabels).To(Equal(map[string]string{"txid": "tx3"}))
+		Expect(result[0].Timestamp).NotTo(BeZero())
+		Expect(result[1].Labels).To(Equal(map[string]string{"channel": "a"}))
+		Expect(result[1].UpperBound).To(Equal("+Inf"))
+		Expect(result[1].ExemplarLabels).To(Equal(map[string]string{"txid": "tx4"}))
+		Expect(result[2].Labels).To(Equal(map[string]string{"channel": "b"}))
+		Expect(result[2].UpperBound).To(Equal("1"))
+		Expect(result[2].ExemplarLabels).To(Equal(map[string]string{"txid": "tx1"}))
+
+		resp, err := client.Get(fmt.Sprintf("http://%s/metrics", server.Listener.Addr().String()))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		bytes, err := ioutil.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_bucket{channel="a",le="1"} 2`))
+		Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_bucket{channel="a",le="5"} 3`))
+		Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_count{channel="a"} 4`))
+	})
+
+	It("uses the default buckets when none are provided", func() {
+		histogramOpts.Buckets = nil
+		histogram := p.NewHistogram(histogramOpts)
+		commonmetrics.ObserveWithExemplar(histogram.With("channel", "a"), 0.2, map[string]string{"txid": "tx1"})
+
+		result := getExemplars()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].UpperBound).To(Equal("0.25"))
+	})
+
+	It("serves an empty array without exemplars", func() {
+		p.NewHistogram(histogramOpts)
+		Expect(getExemplars()).To(BeEmpty())
+	})
+
+	It("rejects methods other than GET", func() {
+		resp, err := client.Post(fmt.Sprintf("http://%s/metrics/exemplars", server.Listener.Addr().String()), "application/json", nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	Context("when the provider does not retain exemplars", func() {
+		It("records the observations only", func() {
+			p = &prometheus.Provider{}
+			histogram := p.NewHistogram(histogramOpts)
+			commonmetrics.ObserveWithExemplar(histogram.With("channel", "a"), 0.5, map[string]string{"txid": "tx1"})
+			Expect(getExemplars()).To(BeEmpty())
+		})
+	})
+
+	Context("when the histogram does not support exemplars", func() {
+		It("records the observations only", func() {
+			fakeHistogram := &metricsfakes.Histogram{}
+			commonmetrics.ObserveWithExemplar(fakeHistogram, 0.5, map[string]string{"txid": "tx1"})
+			Expect(fakeHistogram.ObserveCallCount()).To(Equal(1))
+			Expect(fakeHistogram.ObserveArgsForCall(0)).To(Equal(0.5))
+		})
+	})
+})
@@ -13,7 +13,11 @@ import (
 	prom "github.com/prometheus/client_golang/prometheus"
 )
 
-type Provider struct{}
+type Provider struct {
+	// Exemplars, when set, retains the most recent exemplar of each bucket of
+	// the histograms created by the provider.
+	Exemplars *Exemplars
+}
 
 func (p *Provider) NewCounter(o metrics.CounterOpts) metrics.Counter {
 	return &Counter{
@@ -44,7 +48,12 @@ func (p *Provider) NewGauge(o metrics.GaugeOpts) metrics.Gauge {
 }
 
 func (p *Provider) NewHistogram(o metrics.HistogramOpts) metrics.Histogram {
+	var exemplars *histogramExemplars
+	if p.Exemplars != nil {
+		exemplars = p.Exemplars.register(prom.BuildFQName(o.Namespace, o.Subsystem, o.Name), o.Buckets)
+	}
 	return &Histogram{
+		exemplars: exemplars,
 		Histogram: prometheus.NewHistogramFrom(
 			prom.HistogramOpts{
 				Namespace: o.Namespace,
@@ -70,8 +79,23 @@ func (g *Gauge) With(labelValues ...string) metrics.Gauge {
 	return &Gauge{Gauge: g.Gauge.With(labelValues...)}
 }
 
-type Histogram struct{ kitmetrics.Histogram }
+type Histogram struct {
+	kitmetrics.Histogram
+	exemplars   *histogramExemplars
+	labelValues []string
+}
 
 func (h *Histogram) With(labelValues ...string) metrics.Histogram {
-	return &Histogram{Histogram: h.Histogram.With(labelValues...)}
+	return &Histogram{
+		Histogram:   h.Histogram.With(labelValues...),
+		exemplars:   h.exemplars,
+		labelValues: append(append([]string{}, h.labelValues...), labelValues...),
+	}
+}
+
+func (h *Histogram) ObserveWithExemplar(value float64, exemplarLabels map[string]string) {
+	h.Histogram.Observe(value)
+	if h.exemplars != nil {
+		h.exemplars.record(h.labelValues, value, exemplarLabels)
+	}
 }
@@ -9,6 +9,7 @@ package policies
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -24,6 +25,9 @@ type ImplicitMetaPolicy struct {
 	// Only used for logging
 	managers      map[string]*ManagerImpl
 	SubPolicyName string
+
+	// subPolicyPaths holds the fully qualified path of each entry of SubPolicies
+	subPolicyPaths []string
 }
 
 // NewPolicy creates a new policy based on the policy bytes
@@ -33,12 +37,19 @@ func NewImplicitMetaPolicy(data []byte, managers map[string]*ManagerImpl) (*Impl
 		return nil, fmt.Errorf("Error unmarshaling to ImplicitMetaPolicy: %s", err)
 	}
 
+	groupNames := make([]string, 0, len(managers))
+	for groupName := range managers {
+		groupNames = append(groupNames, groupName)
+	}
+	sort.Strings(groupNames)
+
 	subPolicies := make([]Policy, len(managers))
+	subPolicyPaths := make([]string, len(managers))
 
-	i := 0
-	for _, manager := range managers {
+	for i, groupName := range groupNames {
+		manager := managers[groupName]
 		subPolicies[i], _ = manager.GetPolicy(definition.SubPolicy)
-		i++
+		subPolicyPaths[i] = PathSeparator + manager.path + PathSeparator + definition.SubPolicy
 	}
 
 	var threshold int
@@ -58,13 +69,20 @@ func NewImplicitMetaPolicy(data []byte, managers map[string]*ManagerImpl) (*Impl
 	}
 
 	return &ImplicitMetaPolicy{
-		SubPolicies:   subPolicies,
-		Threshold:     threshold,
-		managers:      managers,
-		SubPolicyName: definition.SubPolicy,
+		SubPolicies:    subPolicies,
+		Threshold:      threshold,
+		managers:       managers,
+		SubPolicyName:  definition.SubPolicy,
+		subPolicyPaths: subPolicyPaths,
 	}, nil
 }
 
+// SubPolicyPaths returns the fully qualified paths of the sub-policies
+// evaluated by this policy, in the same order as SubPolicies.
+func (imp *ImplicitMetaPolicy) SubPolicyPaths() []string {
+	return imp.subPolicyPaths
+}
+
 // EvaluateSignedData takes a set of SignedData and evaluates whether this set of signatures satisfies the policy
 func (imp *ImplicitMetaPolicy) EvaluateSignedData(signatureSet []*protoutil.SignedData) error {
 	logger.Debugf("This is an implicit meta policy, it will trigger other policy evaluations, whose failures may be benign")
@@ -115,3 +115,20 @@ func TestImplicitMetaMajority(t *testing.T) {
 	err = runPolicyTest(t, cb.ImplicitMetaPolicy_MAJORITY, 10, 0)
 	assert.EqualError(t, err, "implicit policy evaluation failed - 0 sub-policies were satisfied, but this policy requires 6 of the 'TestPolicyName' sub-policies to be satisfied")
 }
+
+func TestImplicitMetaSubPolicyPaths(t *testing.T) {
+	managers := map[string]*ManagerImpl{
+		"Org2": {path: "Channel/Application/Org2"},
+		"Org1": {path: "Channel/Application/Org1"},
+	}
+	imp, err := NewImplicitMetaPolicy(protoutil.MarshalOrPanic(&cb.ImplicitMetaPolicy{
+		Rule:      cb.ImplicitMetaPolicy_ANY,
+		SubPolicy: TestPolicyName,
+	}), managers)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/Channel/Application/Org1/" + TestPolicyName,
+		"/Channel/Application/Org2/" + TestPolicyName,
+	}, imp.SubPolicyPaths())
+	assert.Len(t, imp.SubPolicies, 2)
+}
@@ -0,0 +1,330 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/policies/inquire"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("policies.simulator")
+
+const (
+	// LifecycleEndorsementPolicy is the channel policy which a chaincode
+	// definition commit must satisfy, both for the endorsement of the commit
+	// transaction and for the set of approving organizations.
+	LifecycleEndorsementPolicy = "/Channel/Application/LifecycleEndorsement"
+
+	// ActionPolicy is the action name used when evaluating an arbitrary policy
+	ActionPolicy = "policy"
+
+	// ActionConfigUpdate is the action name used when evaluating a config update
+	ActionConfigUpdate = "config-update"
+
+	// ActionChaincodeCommit is the action name used when evaluating a chaincode definition commit
+	ActionChaincodeCommit = "chaincode-commit"
+)
+
+// Result is the outcome of evaluating a single policy against a set of signers.
+type Result struct {
+	// Path is the fully qualified path of the policy
+	Path string `json:"path"`
+	// Element is the config element whose modification requires the policy, if any
+	Element string `json:"element,omitempty"`
+	// Satisfied is true when the signers satisfy the policy
+	Satisfied bool `json:"satisfied"`
+	// Error is the reason the policy was not satisfied
+	Error string `json:"error,omitempty"`
+	// MissingPrincipals lists the principals of the satisfying combination
+	// closest to the provided signers which none of the signers satisfy
+	MissingPrincipals []string `json:"missing_principals,omitempty"`
+	// SubPolicies holds the results of the sub-policies of an implicit meta policy
+	SubPolicies []*Result `json:"sub_policies,omitempty"`
+}
+
+// RejectedSigner describes a signer which could not be used for evaluation.
+type RejectedSigner struct {
+	Index  int    `json:"index"`
+	MSPID  string `json:"mspid,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Report is the outcome of simulating an action against the channel policies.
+type Report struct {
+	ChannelID       string            `json:"channel_id"`
+	Action          string            `json:"action"`
+	Satisfied       bool              `json:"satisfied"`
+	Results         []*Result         `json:"results"`
+	RejectedSigners []*RejectedSigner `json:"rejected_signers,omitempty"`
+}
+
+// Simulator evaluates channel policies offline, that is against a set of
+// signer identities rather than against signatures, so that policy failures
+// can be diagnosed before a transaction is submitted.
+type Simulator struct {
+	channelID     string
+	policyManager policies.Manager
+	deserializer  msp.IdentityDeserializer
+	validator     *configtx.ValidatorImpl
+}
+
+// New creates a Simulator for the channel described by the supplied config.
+func New(channelID string, config *cb.Config, bccsp bccsp.BCCSP) (*Simulator, error) {
+	bundle, err := channelconfig.NewBundle(channelID, config, bccsp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel config bundle")
+	}
+
+	validator, err := configtx.NewValidatorImpl(channelID, config, channelconfig.RootGroupKey, bundle.PolicyManager())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create config validator")
+	}
+
+	return &Simulator{
+		channelID:     channelID,
+		policyManager: bundle.PolicyManager(),
+		deserializer:  bundle.MSPManager(),
+		validator:     validator,
+	}, nil
+}
+
+// EvaluatePolicy evaluates the policy at the given path against the signers,
+// which are serialized identities.
+func (s *Simulator) EvaluatePolicy(path string, signers [][]byte) (*Report, error) {
+	report := s.newReport(ActionPolicy)
+	identities := s.deserialize(signers, report)
+
+	policy, ok := s.policyManager.GetPolicy(path)
+	if !ok {
+		return nil, errors.Errorf("policy %s does not exist", path)
+	}
+
+	report.Results = []*Result{evaluate(path, policy, identities)}
+	report.Satisfied = report.Results[0].Satisfied
+	return report, nil
+}
+
+// SimulateConfigUpdate evaluates every mod_policy which the config update
+// must satisfy against the signers, which are serialized identities.
+func (s *Simulator) SimulateConfigUpdate(configUpdate *cb.ConfigUpdate, signers [][]byte) (*Report, error) {
+	requirements, err := s.validator.RequiredPolicies(configUpdate)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to compute policies required by config update")
+	}
+
+	report := s.newReport(ActionConfigUpdate)
+	identities := s.deserialize(signers, report)
+
+	report.Satisfied = true
+	for _, requirement := range requirements {
+		var result *Result
+		if requirement.Policy == nil {
+			result = &Result{
+				Path:  requirement.PolicyPath,
+				Error: fmt.Sprintf("policy %s does not exist", requirement.PolicyPath),
+			}
+		} else {
+			result = evaluate(requirement.PolicyPath, requirement.Policy, identities)
+		}
+		result.Element = requirement.Element
+		report.Satisfied = report.Satisfied && result.Satisfied
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// SimulateChaincodeCommit evaluates the policy governing chaincode definition
+// commits against the signers, which are serialized identities.
+func (s *Simulator) SimulateChaincodeCommit(signers [][]byte) (*Report, error) {
+	report, err := s.EvaluatePolicy(LifecycleEndorsementPolicy, signers)
+	if err != nil {
+		return nil, err
+	}
+	report.Action = ActionChaincodeCommit
+	return report, nil
+}
+
+func (s *Simulator) newReport(action string) *Report {
+	return &Report{
+		ChannelID: s.channelID,
+		Action:    action,
+	}
+}
+
+func (s *Simulator) deserialize(signers [][]byte, report *Report) []msp.Identity {
+	var identities []msp.Identity
+	for i, signer := range signers {
+		sID := &mspproto.SerializedIdentity{}
+		if err := proto.Unmarshal(signer, sID); err != nil {
+			report.RejectedSigners = append(report.RejectedSigners, &RejectedSigner{
+				Index:  i,
+				Reason: fmt.Sprintf("invalid serialized identity: %s", err),
+			})
+			continue
+		}
+
+		identity, err := s.deserializer.DeserializeIdentity(signer)
+		if err != nil {
+			report.RejectedSigners = append(report.RejectedSigners, &RejectedSigner{
+				Index:  i,
+				MSPID:  sID.Mspid,
+				Reason: err.Error(),
+			})
+			continue
+		}
+		if err := identity.Validate(); err != nil {
+			report.RejectedSigners = append(report.RejectedSigners, &RejectedSigner{
+				Index:  i,
+				MSPID:  sID.Mspid,
+				Reason: fmt.Sprintf("identity is not valid: %s", err),
+			})
+			continue
+		}
+
+		identities = append(identities, identity)
+	}
+	return identities
+}
+
+func evaluate(path string, policy policies.Policy, identities []msp.Identity) *Result {
+	result := &Result{Path: path}
+	if err := policy.EvaluateIdentities(identities); err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Satisfied = true
+	}
+
+	if pl, ok := policy.(*policies.PolicyLogger); ok {
+		policy = pl.Policy
+	}
+
+	switch p := policy.(type) {
+	case *policies.ImplicitMetaPolicy:
+		paths := p.SubPolicyPaths()
+		for i, subPolicy := range p.SubPolicies {
+			result.SubPolicies = append(result.SubPolicies, evaluate(paths[i], subPolicy, identities))
+		}
+	case policies.Converter:
+		if result.Satisfied {
+			break
+		}
+		spe, err := p.Convert()
+		if err != nil {
+			logger.Debugf("Policy %s cannot be converted to a signature policy: %s", path, err)
+			break
+		}
+		result.MissingPrincipals = missingPrincipals(spe, identities)
+	}
+
+	return result
+}
+
+// missingPrincipals returns the principals, among the principal set satisfying
+// the policy which is closest to being satisfied by the identities, which none
+// of the identities satisfy.  Each identity is matched to at most one principal,
+// in the same way as during policy evaluation.
+func missingPrincipals(spe *cb.SignaturePolicyEnvelope, identities []msp.Identity) []string {
+	var closest policies.PrincipalSet
+	found := false
+	for _, principalSet := range inquire.NewInquireableSignaturePolicy(spe).SatisfiedBy() {
+		missing := unsatisfied(principalSet, identities)
+		if !found || len(missing) < len(closest) {
+			closest = missing
+			found = true
+		}
+	}
+
+	var res []string
+	for _, principal := range closest {
+		res = append(res, principalString(principal))
+	}
+	return res
+}
+
+func unsatisfied(principalSet policies.PrincipalSet, identities []msp.Identity) policies.PrincipalSet {
+	used := make([]bool, len(identities))
+	var missing policies.PrincipalSet
+	for _, principal := range principalSet {
+		matched := false
+		for i, identity := range identities {
+			if used[i] {
+				continue
+			}
+			if identity.SatisfiesPrincipal(principal) == nil {
+				used[i] = true
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			missing = append(missing, principal)
+		}
+	}
+	return missing
+}
+
+// principalString renders a principal in the notation of the policy DSL,
+// e.g. Org1MSP.admin, where possible.
+func principalString(principal *mspproto.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case mspproto.MSPPrincipal_ROLE:
+		role := &mspproto.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err == nil {
+			return fmt.Sprintf("%s.%s", role.MspIdentifier, strings.ToLower(role.Role.String()))
+		}
+	case mspproto.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mspproto.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err == nil {
+			return fmt.Sprintf("%s.OU(%s)", ou.MspIdentifier, ou.OrganizationalUnitIdentifier)
+		}
+	case mspproto.MSPPrincipal_IDENTITY:
+		sID := &mspproto.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, sID); err == nil {
+			return fmt.Sprintf("%s.identity", sID.Mspid)
+		}
+	}
+	return fmt.Sprintf("%s principal", principal.PrincipalClassification)
+}
+
+// NewFromConfigBlock creates a Simulator for the channel whose config is
+// carried by the supplied config block.
+func NewFromConfigBlock(block *cb.Block, bccsp bccsp.BCCSP) (*Simulator, error) {
+	if !protoutil.IsConfigBlock(block) {
+		return nil, errors.New("block is not a config block")
+	}
+
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed configuration block")
+	}
+
+	configEnv := &cb.ConfigEnvelope{}
+	chdr, err := protoutil.UnmarshalEnvelopeOfType(envelope, cb.HeaderType_CONFIG, configEnv)
+	if err != nil {
+		return nil, errors.WithMessage(err, "malformed configuration envelope")
+	}
+
+	if configEnv.Config == nil {
+		return nil, errors.New("no config found in envelope")
+	}
+
+	return New(chdr.ChannelId, configEnv.Config, bccsp)
+}
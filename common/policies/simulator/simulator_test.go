@@ -0,0 +1,265 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIdentity satisfies role principals of its own MSP, treating the
+// identity bytes of the serialized identity as its role.
+type fakeIdentity struct {
+	mspID string
+	role  mspproto.MSPRole_MSPRoleType
+}
+
+func (id *fakeIdentity) ExpiresAt() time.Time { return time.Time{} }
+
+func (id *fakeIdentity) GetIdentifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{Mspid: id.mspID, Id: id.role.String()}
+}
+
+func (id *fakeIdentity) GetMSPIdentifier() string { return id.mspID }
+
+func (id *fakeIdentity) Validate() error { return nil }
+
+func (id *fakeIdentity) GetOrganizationalUnits() []*msp.OUIdentifier { return nil }
+
+func (id *fakeIdentity) Anonymous() bool { return false }
+
+func (id *fakeIdentity) Verify(msg []byte, sig []byte) error { return nil }
+
+func (id *fakeIdentity) Serialize() ([]byte, error) {
+	return serializedIdentity(id.mspID, id.role), nil
+}
+
+func (id *fakeIdentity) SatisfiesPrincipal(principal *mspproto.MSPPrincipal) error {
+	if principal.PrincipalClassification != mspproto.MSPPrincipal_ROLE {
+		return errors.New("unsupported principal")
+	}
+	role := &mspproto.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return err
+	}
+	if role.MspIdentifier != id.mspID {
+		return errors.Errorf("identity is not a member of %s", role.MspIdentifier)
+	}
+	if role.Role != mspproto.MSPRole_MEMBER && role.Role != id.role {
+		return errors.Errorf("identity is not %s", role.Role)
+	}
+	return nil
+}
+
+type fakeDeserializer struct {
+	mspIDs []string
+}
+
+func (fd *fakeDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	sID := &mspproto.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, sID); err != nil {
+		return nil, err
+	}
+	for _, mspID := range fd.mspIDs {
+		if mspID == sID.Mspid {
+			role := mspproto.MSPRole_MSPRoleType(mspproto.MSPRole_MSPRoleType_value[string(sID.IdBytes)])
+			return &fakeIdentity{mspID: sID.Mspid, role: role}, nil
+		}
+	}
+	return nil, errors.Errorf("MSP %s is unknown", sID.Mspid)
+}
+
+func (fd *fakeDeserializer) IsWellFormed(identity *mspproto.SerializedIdentity) error { return nil }
+
+func serializedIdentity(mspID string, role mspproto.MSPRole_MSPRoleType) []byte {
+	return protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: []byte(role.String())})
+}
+
+func orgGroup(mspID string) *cb.ConfigGroup {
+	group := protoutil.NewConfigGroup()
+	group.ModPolicy = "Admins"
+	group.Policies["Admins"] = &cb.ConfigPolicy{Policy: policies.SignaturePolicy("Admins", policydsl.SignedByMspAdmin(mspID)).Value(), ModPolicy: "Admins"}
+	group.Policies["Readers"] = &cb.ConfigPolicy{Policy: policies.SignaturePolicy("Readers", policydsl.SignedByMspMember(mspID)).Value(), ModPolicy: "Admins"}
+	group.Policies["Endorsement"] = &cb.ConfigPolicy{Policy: policies.SignaturePolicy("Endorsement", policydsl.SignedByMspMember(mspID)).Value(), ModPolicy: "Admins"}
+	return group
+}
+
+func newTestSimulator(t *testing.T) *Simulator {
+	application := protoutil.NewConfigGroup()
+	application.ModPolicy = "Admins"
+	application.Groups["Org1"] = orgGroup("Org1MSP")
+	application.Groups["Org2"] = orgGroup("Org2MSP")
+	application.Policies["Admins"] = policies.ImplicitMetaPolicyWithSubPolicy("Admins", cb.ImplicitMetaPolicy_MAJORITY)
+	application.Policies["Readers"] = policies.ImplicitMetaPolicyWithSubPolicy("Readers", cb.ImplicitMetaPolicy_ANY)
+	application.Policies["LifecycleEndorsement"] = policies.ImplicitMetaPolicyWithSubPolicy("Endorsement", cb.ImplicitMetaPolicy_MAJORITY)
+	for _, policy := range application.Policies {
+		policy.ModPolicy = "Admins"
+	}
+
+	channel := protoutil.NewConfigGroup()
+	channel.ModPolicy = "Admins"
+	channel.Groups["Application"] = application
+	channel.Policies["Admins"] = policies.ImplicitMetaPolicyWithSubPolicy("Admins", cb.ImplicitMetaPolicy_MAJORITY)
+	channel.Policies["Admins"].ModPolicy = "Admins"
+
+	deserializer := &fakeDeserializer{mspIDs: []string{"Org1MSP", "Org2MSP"}}
+	pm, err := policies.NewManagerImpl("Channel", map[int32]policies.Provider{
+		int32(cb.Policy_SIGNATURE): cauthdsl.NewPolicyProvider(deserializer),
+	}, channel)
+	require.NoError(t, err)
+
+	validator, err := configtx.NewValidatorImpl("testchannel", &cb.Config{ChannelGroup: channel}, "Channel", pm)
+	require.NoError(t, err)
+
+	return &Simulator{
+		channelID:     "testchannel",
+		policyManager: pm,
+		deserializer:  deserializer,
+		validator:     validator,
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	sim := newTestSimulator(t)
+	org1Admin := serializedIdentity("Org1MSP", mspproto.MSPRole_ADMIN)
+	org2Admin := serializedIdentity("Org2MSP", mspproto.MSPRole_ADMIN)
+	org2Member := serializedIdentity("Org2MSP", mspproto.MSPRole_MEMBER)
+
+	t.Run("Satisfied", func(t *testing.T) {
+		report, err := sim.EvaluatePolicy("/Channel/Application/Admins", [][]byte{org1Admin, org2Admin})
+		require.NoError(t, err)
+		require.True(t, report.Satisfied)
+		require.Equal(t, ActionPolicy, report.Action)
+		require.Equal(t, "testchannel", report.ChannelID)
+		require.Len(t, report.Results, 1)
+		require.Len(t, report.Results[0].SubPolicies, 2)
+		require.Equal(t, "/Channel/Application/Org1/Admins", report.Results[0].SubPolicies[0].Path)
+		require.Equal(t, "/Channel/Application/Org2/Admins", report.Results[0].SubPolicies[1].Path)
+		require.True(t, report.Results[0].SubPolicies[0].Satisfied)
+		require.True(t, report.Results[0].SubPolicies[1].Satisfied)
+	})
+
+	t.Run("MissingSignature", func(t *testing.T) {
+		report, err := sim.EvaluatePolicy("/Channel/Application/Admins", [][]byte{org1Admin, org2Member})
+		require.NoError(t, err)
+		require.False(t, report.Satisfied)
+		require.NotEmpty(t, report.Results[0].Error)
+		require.True(t, report.Results[0].SubPolicies[0].Satisfied)
+		sub := report.Results[0].SubPolicies[1]
+		require.False(t, sub.Satisfied)
+		require.Equal(t, []string{"Org2MSP.admin"}, sub.MissingPrincipals)
+	})
+
+	t.Run("RejectedSigner", func(t *testing.T) {
+		report, err := sim.EvaluatePolicy("/Channel/Application/Readers", [][]byte{
+			serializedIdentity("UnknownMSP", mspproto.MSPRole_MEMBER),
+			[]byte("garbage"),
+		})
+		require.NoError(t, err)
+		require.False(t, report.Satisfied)
+		require.Len(t, report.RejectedSigners, 2)
+		require.Equal(t, 0, report.RejectedSigners[0].Index)
+		require.Equal(t, "UnknownMSP", report.RejectedSigners[0].MSPID)
+		require.Equal(t, 1, report.RejectedSigners[1].Index)
+	})
+
+	t.Run("NoSuchPolicy", func(t *testing.T) {
+		_, err := sim.EvaluatePolicy("/Channel/Application/Nonexistent", nil)
+		require.EqualError(t, err, "policy /Channel/Application/Nonexistent does not exist")
+	})
+}
+
+func TestSimulateChaincodeCommit(t *testing.T) {
+	sim := newTestSimulator(t)
+
+	report, err := sim.SimulateChaincodeCommit([][]byte{
+		serializedIdentity("Org1MSP", mspproto.MSPRole_MEMBER),
+		serializedIdentity("Org2MSP", mspproto.MSPRole_MEMBER),
+	})
+	require.NoError(t, err)
+	require.Equal(t, ActionChaincodeCommit, report.Action)
+	require.True(t, report.Satisfied)
+	require.Equal(t, LifecycleEndorsementPolicy, report.Results[0].Path)
+
+	report, err = sim.SimulateChaincodeCommit([][]byte{serializedIdentity("Org1MSP", mspproto.MSPRole_MEMBER)})
+	require.NoError(t, err)
+	require.False(t, report.Satisfied)
+	require.Equal(t, []string{"Org2MSP.member"}, report.Results[0].SubPolicies[1].MissingPrincipals)
+}
+
+func TestSimulateConfigUpdate(t *testing.T) {
+	sim := newTestSimulator(t)
+	org1Admin := serializedIdentity("Org1MSP", mspproto.MSPRole_ADMIN)
+	org2Admin := serializedIdentity("Org2MSP", mspproto.MSPRole_ADMIN)
+
+	configUpdate := &cb.ConfigUpdate{
+		ChannelId: "testchannel",
+		ReadSet: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				"Application": {},
+			},
+		},
+		WriteSet: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				"Application": {
+					Version:   1,
+					ModPolicy: "Admins",
+				},
+			},
+		},
+	}
+
+	report, err := sim.SimulateConfigUpdate(configUpdate, [][]byte{org1Admin})
+	require.NoError(t, err)
+	require.Equal(t, ActionConfigUpdate, report.Action)
+	require.False(t, report.Satisfied)
+	require.Len(t, report.Results, 1)
+	require.Equal(t, "[Group]  /Channel/Application", report.Results[0].Element)
+	require.Equal(t, "/Channel/Application/Admins", report.Results[0].Path)
+
+	report, err = sim.SimulateConfigUpdate(configUpdate, [][]byte{org1Admin, org2Admin})
+	require.NoError(t, err)
+	require.True(t, report.Satisfied)
+
+	_, err = sim.SimulateConfigUpdate(&cb.ConfigUpdate{ChannelId: "otherchannel"}, nil)
+	require.EqualError(t, err, "failed to compute policies required by config update: ConfigUpdate for channel 'otherchannel' but validator for channel 'testchannel'")
+}
+
+func TestNewFromConfigBlock(t *testing.T) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	_, err = NewFromConfigBlock(&cb.Block{}, cryptoProvider)
+	require.EqualError(t, err, "block is not a config block")
+
+	env := &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(protoutil.MakeChannelHeader(cb.HeaderType_CONFIG, 0, "testchannel", 0)),
+			},
+			Data: protoutil.MarshalOrPanic(&cb.ConfigEnvelope{}),
+		}),
+	}
+	block := protoutil.NewBlock(0, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(env)}
+
+	_, err = NewFromConfigBlock(block, cryptoProvider)
+	require.EqualError(t, err, "no config found in envelope")
+}
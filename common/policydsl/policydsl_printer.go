@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policydsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+)
+
+// String renders a signature policy envelope in the notation accepted by
+// FromString, e.g. AND('Org1MSP.member', 'Org2MSP.admin'). Principals which
+// cannot be expressed in the notation, i.e. organizational units and
+// identities, are rendered as 'Org1MSP.OU(name)' and 'Org1MSP.identity'.
+// The identical rules of a gate of a weighted (version 1) policy are rendered
+// once, e.g. OutOf(2, Weight(2, 'Org1MSP.member'), 'Org2MSP.member').
+func String(envelope *cb.SignaturePolicyEnvelope) (string, error) {
+	if envelope == nil || envelope.Rule == nil {
+		return "", errors.New("empty signature policy")
+	}
+	return policyString(envelope.Rule, envelope.Identities, envelope.Version == 1)
+}
+
+func policyString(policy *cb.SignaturePolicy, identities []*mb.MSPPrincipal, weighted bool) (string, error) {
+	switch t := policy.Type.(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(identities) {
+			return "", errors.Errorf("identity index %d out of range", t.SignedBy)
+		}
+		principal, err := principalString(identities[t.SignedBy])
+		if err != nil {
+			return "", err
+		}
+		return "'" + principal + "'", nil
+	case *cb.SignaturePolicy_NOutOf_:
+		// in weighted policies, identical rules of a gate are rendered once along with their weight
+		var distinct []*cb.SignaturePolicy
+		var weights []int
+		for _, rule := range t.NOutOf.Rules {
+			found := false
+			for i := range distinct {
+				if weighted && proto.Equal(distinct[i], rule) {
+					weights[i]++
+					found = true
+					break
+				}
+			}
+			if !found {
+				distinct = append(distinct, rule)
+				weights = append(weights, 1)
+			}
+		}
+		rules := make([]string, len(distinct))
+		for i, rule := range distinct {
+			s, err := policyString(rule, identities, weighted)
+			if err != nil {
+				return "", err
+			}
+			if weights[i] > 1 {
+				s = fmt.Sprintf("Weight(%d, %s)", weights[i], s)
+			}
+			rules[i] = s
+		}
+		n := int(t.NOutOf.N)
+		switch {
+		case len(rules) > 1 && n == len(t.NOutOf.Rules):
+			return fmt.Sprintf("AND(%s)", strings.Join(rules, ", ")), nil
+		case len(rules) > 1 && n == 1:
+			return fmt.Sprintf("OR(%s)", strings.Join(rules, ", ")), nil
+		default:
+			return fmt.Sprintf("OutOf(%d, %s)", n, strings.Join(rules, ", ")), nil
+		}
+	default:
+		return "", errors.Errorf("unknown signature policy type %T", policy.Type)
+	}
+}
+
+func principalString(principal *mb.MSPPrincipal) (string, error) {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return "", errors.Wrap(err, "failed unmarshalling role principal")
+		}
+		return fmt.Sprintf("%s.%s", role.MspIdentifier, strings.ToLower(role.Role.String())), nil
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return "", errors.Wrap(err, "failed unmarshalling organizational unit principal")
+		}
+		return fmt.Sprintf("%s.OU(%s)", ou.MspIdentifier, ou.OrganizationalUnitIdentifier), nil
+	case mb.MSPPrincipal_IDENTITY:
+		sID := &mb.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, sID); err != nil {
+			return "", errors.Wrap(err, "failed unmarshalling identity principal")
+		}
+		return fmt.Sprintf("%s.identity", sID.Mspid), nil
+	default:
+		return "", errors.Errorf("unknown principal classification %s", principal.PrincipalClassification)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policydsl
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	for _, tc := range []struct {
+		policy   string
+		expected string
+	}{
+		{"OR('A.member', 'B.admin')", "OR('A.member', 'B.admin')"},
+		{"OutOf(1, 'A.member', 'B.member')", "OR('A.member', 'B.member')"},
+		{"AND('A.peer', 'B.client', 'C.orderer')", "AND('A.peer', 'B.client', 'C.orderer')"},
+		{"OutOf(2, 'A.member', 'B.member', 'C.member')", "OutOf(2, 'A.member', 'B.member', 'C.member')"},
+		{"AND('A.member', OR('B.admin', 'C.admin'))", "AND('A.member', OR('B.admin', 'C.admin'))"},
+		{"OutOf(1, 'A.member')", "OutOf(1, 'A.member')"},
+		{"OutOf(2, Weight(2, 'A.member'), 'B.member', 'C.member')", "OutOf(2, Weight(2, 'A.member'), 'B.member', 'C.member')"},
+		{"AND(Weight(2, 'A.member'), 'B.member')", "AND(Weight(2, 'A.member'), 'B.member')"},
+		{"OR('A.member', Weight(3, AND('B.member', 'C.member')))", "OR('A.member', Weight(3, AND('B.member', 'C.member')))"},
+		{"OutOf(2, Weight(2, 'A.member'))", "OutOf(2, Weight(2, 'A.member'))"},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			envelope, err := FromString(tc.policy)
+			require.NoError(t, err)
+			s, err := String(envelope)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s)
+
+			reparsed, err := FromString(s)
+			require.NoError(t, err)
+			assert.Equal(t, envelope, reparsed)
+		})
+	}
+}
+
+func TestStringNonDSLPrincipals(t *testing.T) {
+	envelope := &common.SignaturePolicyEnvelope{
+		Rule: NOutOf(1, []*common.SignaturePolicy{SignedBy(0), SignedBy(1)}),
+		Identities: []*msp.MSPPrincipal{
+			{
+				PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+				Principal:               protoutil.MarshalOrPanic(&msp.OrganizationUnit{MspIdentifier: "A", OrganizationalUnitIdentifier: "ops"}),
+			},
+			{
+				PrincipalClassification: msp.MSPPrincipal_IDENTITY,
+				Principal:               protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "B", IdBytes: []byte("cert")}),
+			},
+		},
+	}
+	s, err := String(envelope)
+	assert.NoError(t, err)
+	assert.Equal(t, "OR('A.OU(ops)', 'B.identity')", s)
+}
+
+func TestStringErrors(t *testing.T) {
+	_, err := String(nil)
+	assert.EqualError(t, err, "empty signature policy")
+
+	_, err = String(&common.SignaturePolicyEnvelope{Rule: SignedBy(1)})
+	assert.EqualError(t, err, "identity index 1 out of range")
+
+	_, err = String(&common.SignaturePolicyEnvelope{
+		Rule:       SignedBy(0),
+		Identities: []*msp.MSPPrincipal{{PrincipalClassification: msp.MSPPrincipal_ROLE, Principal: []byte("garbage")}},
+	})
+	assert.Error(t, err)
+}
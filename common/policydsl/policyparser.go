@@ -26,6 +26,10 @@ const (
 	GateOutOf = "OutOf"
 )
 
+// GateWeight is the function which, within a gate, makes its argument count
+// the given number of times, e.g. OutOf(2, Weight(2, 'A.member'), 'B.member', 'C.member')
+const GateWeight = "Weight"
+
 // Role values for principals
 const (
 	RoleAdmin   = "admin"
@@ -74,6 +78,8 @@ func outof(args ...interface{}) (interface{}, error) {
 			} else {
 				toret += t
 			}
+		case *weightedArg:
+			toret += t.expr
 		default:
 			return nil, fmt.Errorf("unexpected type %s", reflect.TypeOf(arg))
 		}
@@ -83,7 +89,16 @@ func outof(args ...interface{}) (interface{}, error) {
 }
 
 func and(args ...interface{}) (interface{}, error) {
-	args = append([]interface{}{len(args)}, args...)
+	// all the arguments are required, hence the threshold is the sum of their weights
+	n := 0
+	for _, arg := range args {
+		if w, ok := arg.(*weightedArg); ok {
+			n += w.weight
+		} else {
+			n++
+		}
+	}
+	args = append([]interface{}{n}, args...)
 	return outof(args...)
 }
 
@@ -92,6 +107,76 @@ func or(args ...interface{}) (interface{}, error) {
 	return outof(args...)
 }
 
+// weightedArg is the result of the first evaluation of a weight call,
+// which lets and() account for the weight of its arguments
+type weightedArg struct {
+	weight int
+	expr   string
+}
+
+// weightExpr validates the arguments of a weight call and renders it back, with
+// the principal quoted, so that it survives the subsequent evaluations
+func weightExpr(args ...interface{}) (int, string, error) {
+	if len(args) != 2 {
+		return 0, "", fmt.Errorf("expected two arguments to Weight. Given %d", len(args))
+	}
+
+	weight, ok := args[0].(float64)
+	if !ok || weight < 1 || weight != float64(int(weight)) {
+		return 0, "", fmt.Errorf("weight must be a positive integer, got %v", args[0])
+	}
+
+	toret := "weight(" + strconv.Itoa(int(weight)) + ", "
+	switch t := args[1].(type) {
+	case string:
+		if regex.MatchString(t) {
+			toret += "'" + t + "'"
+		} else {
+			toret += t
+		}
+	default:
+		return 0, "", fmt.Errorf("unexpected type %s", reflect.TypeOf(args[1]))
+	}
+
+	return int(weight), toret + ")", nil
+}
+
+// a stub function which marks its argument as weighted
+func weight(args ...interface{}) (interface{}, error) {
+	w, expr, err := weightExpr(args...)
+	if err != nil {
+		return nil, err
+	}
+	return &weightedArg{weight: w, expr: expr}, nil
+}
+
+// a stub function - it returns the weight call as a string for the next passes
+func weightFirstPass(args ...interface{}) (interface{}, error) {
+	_, expr, err := weightExpr(args...)
+	if err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// weightedPolicy is the result of the last evaluation of a weight call:
+// the enclosing gate repeats the policy weight times
+type weightedPolicy struct {
+	weight int
+	policy interface{}
+}
+
+func weightSecondPass(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expected two arguments to Weight. Given %d", len(args))
+	}
+	weight, ok := args[0].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unrecognized type, expected a number, got %s", reflect.TypeOf(args[0]))
+	}
+	return &weightedPolicy{weight: int(weight), policy: args[1]}, nil
+}
+
 func firstPass(args ...interface{}) (interface{}, error) {
 	toret := "outof(ID"
 	for _, arg := range args {
@@ -140,78 +225,54 @@ func secondPass(args ...interface{}) (interface{}, error) {
 		return nil, fmt.Errorf("unrecognized type, expected a number, got %s", reflect.TypeOf(args[1]))
 	}
 
-	/* get the n in the t out of n */
-	var n int = len(args) - 2
-
-	/* sanity check - t should be positive, permit equal to n+1, but disallow over n+1 */
-	if t < 0 || t > n+1 {
-		return nil, fmt.Errorf("invalid t-out-of-n predicate, t %d, n %d", t, n)
+	/* sanity check - t should be positive */
+	if t < 0 {
+		return nil, fmt.Errorf("invalid t-out-of-n predicate, t %d, n %d", t, len(args)-2)
 	}
 
 	policies := make([]*cb.SignaturePolicy, 0)
 
 	/* handle the rest of the arguments */
 	for _, principal := range args[2:] {
+		/* a weighted argument counts as many times as its weight */
+		weight := 1
+		if w, ok := principal.(*weightedPolicy); ok {
+			weight = w.weight
+			principal = w.policy
+			ctx.weighted = true
+		}
+
+		var dapolicy *cb.SignaturePolicy
 		switch t := principal.(type) {
 		/* if it's a string, we expect it to be formed as
 		   <MSP_ID> . <ROLE>, where MSP_ID is the MSP identifier
 		   and ROLE is either a member, an admin, a client, a peer or an orderer*/
 		case string:
-			/* split the string */
-			subm := regex.FindAllStringSubmatch(t, -1)
-			if subm == nil || len(subm) != 1 || len(subm[0]) != 4 {
-				return nil, fmt.Errorf("error parsing principal %s", t)
-			}
-
-			/* get the right role */
-			var r mb.MSPRole_MSPRoleType
-
-			switch subm[0][3] {
-			case RoleMember:
-				r = mb.MSPRole_MEMBER
-			case RoleAdmin:
-				r = mb.MSPRole_ADMIN
-			case RoleClient:
-				r = mb.MSPRole_CLIENT
-			case RolePeer:
-				r = mb.MSPRole_PEER
-			case RoleOrderer:
-				r = mb.MSPRole_ORDERER
-			default:
-				return nil, fmt.Errorf("error parsing role %s", t)
-			}
-
-			/* build the principal we've been told */
-			mspRole, err := proto.Marshal(&mb.MSPRole{MspIdentifier: subm[0][1], Role: r})
+			p, err := ctx.principalPolicy(t)
 			if err != nil {
-				return nil, fmt.Errorf("error marshalling msp role: %s", err)
-			}
-
-			p := &mb.MSPPrincipal{
-				PrincipalClassification: mb.MSPPrincipal_ROLE,
-				Principal:               mspRole,
+				return nil, err
 			}
-			ctx.principals = append(ctx.principals, p)
-
-			/* create a SignaturePolicy that requires a signature from
-			   the principal we've just built*/
-			dapolicy := SignedBy(int32(ctx.IDNum))
-			policies = append(policies, dapolicy)
-
-			/* increment the identity counter. Note that this is
-			   suboptimal as we are not reusing identities. We
-			   can deduplicate them easily and make this puppy
-			   smaller. For now it's fine though */
-			// TODO: deduplicate principals
-			ctx.IDNum++
+			dapolicy = p
 
 		/* if we've already got a policy we're good, just append it */
 		case *cb.SignaturePolicy:
-			policies = append(policies, t)
+			dapolicy = t
 
 		default:
 			return nil, fmt.Errorf("unrecognized type, expected a principal or a policy, got %s", reflect.TypeOf(principal))
 		}
+
+		for i := 0; i < weight; i++ {
+			policies = append(policies, dapolicy)
+		}
+	}
+
+	/* get the n in the t out of n */
+	var n int = len(policies)
+
+	/* sanity check - permit t equal to n+1, but disallow over n+1 */
+	if t > n+1 {
+		return nil, fmt.Errorf("invalid t-out-of-n predicate, t %d, n %d", t, n)
 	}
 
 	return NOutOf(int32(t), policies), nil
@@ -220,6 +281,60 @@ func secondPass(args ...interface{}) (interface{}, error) {
 type context struct {
 	IDNum      int
 	principals []*mb.MSPPrincipal
+	weighted   bool
+}
+
+// principalPolicy builds the principal <MSP_ID>.<ROLE> and
+// returns a SignaturePolicy requiring a signature from it
+func (ctx *context) principalPolicy(principal string) (*cb.SignaturePolicy, error) {
+	/* split the string */
+	subm := regex.FindAllStringSubmatch(principal, -1)
+	if subm == nil || len(subm) != 1 || len(subm[0]) != 4 {
+		return nil, fmt.Errorf("error parsing principal %s", principal)
+	}
+
+	/* get the right role */
+	var r mb.MSPRole_MSPRoleType
+
+	switch subm[0][3] {
+	case RoleMember:
+		r = mb.MSPRole_MEMBER
+	case RoleAdmin:
+		r = mb.MSPRole_ADMIN
+	case RoleClient:
+		r = mb.MSPRole_CLIENT
+	case RolePeer:
+		r = mb.MSPRole_PEER
+	case RoleOrderer:
+		r = mb.MSPRole_ORDERER
+	default:
+		return nil, fmt.Errorf("error parsing role %s", principal)
+	}
+
+	/* build the principal we've been told */
+	mspRole, err := proto.Marshal(&mb.MSPRole{MspIdentifier: subm[0][1], Role: r})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling msp role: %s", err)
+	}
+
+	p := &mb.MSPPrincipal{
+		PrincipalClassification: mb.MSPPrincipal_ROLE,
+		Principal:               mspRole,
+	}
+	ctx.principals = append(ctx.principals, p)
+
+	/* create a SignaturePolicy that requires a signature from
+	   the principal we've just built*/
+	dapolicy := SignedBy(int32(ctx.IDNum))
+
+	/* increment the identity counter. Note that this is
+	   suboptimal as we are not reusing identities. We
+	   can deduplicate them easily and make this puppy
+	   smaller. For now it's fine though */
+	// TODO: deduplicate principals
+	ctx.IDNum++
+
+	return dapolicy, nil
 }
 
 func newContext() *context {
@@ -236,6 +351,10 @@ func newContext() *context {
 //	- GATE is either "and" or "or"
 //	- P is either a principal or another nested call to GATE
 //
+// Within "OutOf(N, P[, P])", "and" and "or", P may also be weighted as
+// "Weight(W, P)", in which case it counts W times towards the threshold
+// of the gate. Weighted policies are returned with version 1.
+//
 // A principal is defined as:
 //
 // ORG.ROLE
@@ -248,15 +367,18 @@ func FromString(policy string) (*cb.SignaturePolicyEnvelope, error) {
 	// first we translate the and/or business into outof gates
 	intermediate, err := govaluate.NewEvaluableExpressionWithFunctions(
 		policy, map[string]govaluate.ExpressionFunction{
-			GateAnd:                    and,
-			strings.ToLower(GateAnd):   and,
-			strings.ToUpper(GateAnd):   and,
-			GateOr:                     or,
-			strings.ToLower(GateOr):    or,
-			strings.ToUpper(GateOr):    or,
-			GateOutOf:                  outof,
-			strings.ToLower(GateOutOf): outof,
-			strings.ToUpper(GateOutOf): outof,
+			GateAnd:                     and,
+			strings.ToLower(GateAnd):    and,
+			strings.ToUpper(GateAnd):    and,
+			GateOr:                      or,
+			strings.ToLower(GateOr):     or,
+			strings.ToUpper(GateOr):     or,
+			GateOutOf:                   outof,
+			strings.ToLower(GateOutOf):  outof,
+			strings.ToUpper(GateOutOf):  outof,
+			GateWeight:                  weight,
+			strings.ToLower(GateWeight): weight,
+			strings.ToUpper(GateWeight): weight,
 		},
 	)
 	if err != nil {
@@ -289,7 +411,7 @@ func FromString(policy string) (*cb.SignaturePolicyEnvelope, error) {
 	// we put the identities that the policy requires
 	exp, err := govaluate.NewEvaluableExpressionWithFunctions(
 		resStr,
-		map[string]govaluate.ExpressionFunction{"outof": firstPass},
+		map[string]govaluate.ExpressionFunction{"outof": firstPass, "weight": weightFirstPass},
 	)
 	if err != nil {
 		return nil, err
@@ -319,7 +441,7 @@ func FromString(policy string) (*cb.SignaturePolicyEnvelope, error) {
 
 	exp, err = govaluate.NewEvaluableExpressionWithFunctions(
 		resStr,
-		map[string]govaluate.ExpressionFunction{"outof": secondPass},
+		map[string]govaluate.ExpressionFunction{"outof": secondPass, "weight": weightSecondPass},
 	)
 	if err != nil {
 		return nil, err
@@ -348,6 +470,10 @@ func FromString(policy string) (*cb.SignaturePolicyEnvelope, error) {
 		Version:    0,
 		Rule:       rule,
 	}
+	if ctx.weighted {
+		// weighted gates are only understood by policies of version 1
+		p.Version = 1
+	}
 
 	return p, nil
 }
@@ -282,6 +282,69 @@ func TestOutOfNumIsString(t *testing.T) {
 	assert.Equal(t, p1, p2)
 }
 
+func TestWeight(t *testing.T) {
+	principals := make([]*msp.MSPPrincipal, 0)
+	for _, mspID := range []string{"A", "B", "C"} {
+		principals = append(principals, &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_ROLE,
+			Principal:               protoutil.MarshalOrPanic(&msp.MSPRole{Role: msp.MSPRole_MEMBER, MspIdentifier: mspID})})
+	}
+
+	p1, err := FromString("OutOf(2, Weight(2, 'A.member'), 'B.member', 'C.member')")
+	assert.NoError(t, err)
+	p2 := &common.SignaturePolicyEnvelope{
+		Version:    1,
+		Rule:       NOutOf(2, []*common.SignaturePolicy{SignedBy(0), SignedBy(0), SignedBy(1), SignedBy(2)}),
+		Identities: principals,
+	}
+	assert.Equal(t, p2, p1)
+
+	// the threshold of an AND is the sum of the weights of its arguments
+	p1, err = FromString("AND(weight(2, 'A.member'), 'B.member')")
+	assert.NoError(t, err)
+	p2 = &common.SignaturePolicyEnvelope{
+		Version:    1,
+		Rule:       NOutOf(3, []*common.SignaturePolicy{SignedBy(0), SignedBy(0), SignedBy(1)}),
+		Identities: principals[:2],
+	}
+	assert.Equal(t, p2, p1)
+
+	// sub-policies can be weighted, too
+	p1, err = FromString("OutOf(2, 'A.member', WEIGHT(2, OR('B.member', 'C.member')))")
+	assert.NoError(t, err)
+	// (nested gates are evaluated first, hence their principals come first)
+	sub := NOutOf(1, []*common.SignaturePolicy{SignedBy(0), SignedBy(1)})
+	p2 = &common.SignaturePolicyEnvelope{
+		Version:    1,
+		Rule:       NOutOf(2, []*common.SignaturePolicy{SignedBy(2), sub, sub}),
+		Identities: []*msp.MSPPrincipal{principals[1], principals[2], principals[0]},
+	}
+	assert.Equal(t, p2, p1)
+
+	// the weights count towards the upper boundary of the threshold
+	_, err = FromString("OutOf(4, Weight(2, 'A.member'), 'B.member')")
+	assert.NoError(t, err)
+	_, err = FromString("OutOf(5, Weight(2, 'A.member'), 'B.member')")
+	assert.EqualError(t, err, "invalid t-out-of-n predicate, t 5, n 3")
+}
+
+func TestWeightErrorCase(t *testing.T) {
+	_, err := FromString("OutOf(1, Weight(0, 'A.member'))")
+	assert.EqualError(t, err, "weight must be a positive integer, got 0")
+
+	_, err = FromString("OutOf(1, Weight(1.5, 'A.member'))")
+	assert.EqualError(t, err, "weight must be a positive integer, got 1.5")
+
+	_, err = FromString("OutOf(1, Weight(2))")
+	assert.EqualError(t, err, "expected two arguments to Weight. Given 1")
+
+	_, err = FromString("OutOf(1, Weight(2, Weight(2, 'A.member')))")
+	assert.EqualError(t, err, "unexpected type *policydsl.weightedArg")
+
+	_, err = FromString("Weight(2, 'A.member')")
+	assert.EqualError(t, err, "invalid policy string 'Weight(2, 'A.member')'")
+}
+
 func TestOutOfErrorCase(t *testing.T) {
 	p1, err1 := FromString("") // 1st NewEvaluableExpressionWithFunctions() returns an error
 	assert.Nil(t, p1)
@@ -8,43 +8,162 @@ package idemixca
 
 import (
 	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	m "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/idemix"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
 )
 
-// GenerateIssuerKey invokes Idemix library to generate an issuer (CA) signing key pair.
+// attributeNames are the names of the attributes supported by the issuer
+var attributeNames = []string{msp.AttributeNameOU, msp.AttributeNameRole, msp.AttributeNameEnrollmentId, msp.AttributeNameRevocationHandle}
+
+// GenerateIssuerKey generates an issuer (CA) signing key pair with the given idemix BCCSP.
 // Currently four attributes are supported by the issuer:
 // AttributeNameOU is the organization unit name
 // AttributeNameRole is the role (member or admin) name
 // AttributeNameEnrollmentId is the enrollment id
 // AttributeNameRevocationHandle contains the revocation handle, which can be used to revoke this user
-// Generated keys are serialized to bytes.
-func GenerateIssuerKey() ([]byte, []byte, error) {
+func GenerateIssuerKey(csp bccsp.BCCSP) (bccsp.Key, error) {
+	key, err := csp.KeyGen(&bccsp.IdemixIssuerKeyGenOpts{Temporary: true, AttributeNames: attributeNames})
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot generate CA key")
+	}
+	return key, nil
+}
+
+// GenerateRevocationKey generates a long term revocation key with the given idemix BCCSP.
+func GenerateRevocationKey(csp bccsp.BCCSP) (bccsp.Key, error) {
+	key, err := csp.KeyGen(&bccsp.IdemixRevocationKeyGenOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot generate revocation key")
+	}
+	return key, nil
+}
+
+// ExportIssuerKey returns the issuer secret key and the serialized issuer public key
+// of the passed issuer key, which must be exportable.
+func ExportIssuerKey(key bccsp.Key) ([]byte, []byte, error) {
+	raw, err := key.Bytes()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "cannot export CA key")
+	}
+	issuerKey := &idemix.IssuerKey{}
+	if err := proto.Unmarshal(raw, issuerKey); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal CA key")
+	}
+	ipkSerialized, err := proto.Marshal(issuerKey.Ipk)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal CA public key")
+	}
+	return issuerKey.Isk, ipkSerialized, nil
+}
+
+// ImportIssuerKey imports the issuer key made of the passed issuer secret key and
+// serialized issuer public key into the given idemix BCCSP.
+func ImportIssuerKey(csp bccsp.BCCSP, isk, ipkSerialized []byte) (bccsp.Key, error) {
+	ipk := &idemix.IssuerPublicKey{}
+	if err := proto.Unmarshal(ipkSerialized, ipk); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal CA public key")
+	}
+	raw, err := proto.Marshal(&idemix.IssuerKey{Isk: isk, Ipk: ipk})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal CA key")
+	}
+	key, err := csp.KeyImport(raw, &bccsp.IdemixIssuerKeyImportOpts{Temporary: true, AttributeNames: attributeNames})
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot import CA key")
+	}
+	return key, nil
+}
+
+// GenerateAuditEscrowKey generates the key pair of an auditor and requires the
+// identities certified by the serialized issuer public key to escrow their
+// enrollment id for the auditor in their signatures. It returns the serialized
+// audit escrow key and the updated serialized issuer public key, which replaces
+// the issuer public key of the CA and of the MSP.
+func GenerateAuditEscrowKey(ipkSerialized []byte) ([]byte, []byte, error) {
+	ipk := &idemix.IssuerPublicKey{}
+	if err := proto.Unmarshal(ipkSerialized, ipk); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal CA public key")
+	}
 	rng, err := idemix.GetRand()
 	if err != nil {
+		return nil, nil, errors.WithMessage(err, "Error getting PRNG")
+	}
+	escrowKey, err := idemix.NewAuditEscrowKey(rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ipk.SetAuditEscrow(escrowKey.Pk, msp.AttributeNameEnrollmentId); err != nil {
 		return nil, nil, err
 	}
-	AttributeNames := []string{msp.AttributeNameOU, msp.AttributeNameRole, msp.AttributeNameEnrollmentId, msp.AttributeNameRevocationHandle}
-	key, err := idemix.NewIssuerKey(AttributeNames, rng)
+	escrowKeySerialized, err := proto.Marshal(escrowKey)
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "cannot generate CA key")
+		return nil, nil, errors.Wrap(err, "failed to marshal audit escrow key")
 	}
-	ipkSerialized, err := proto.Marshal(key.Ipk)
+	ipkSerialized, err = proto.Marshal(ipk)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal CA public key")
+	}
+	return escrowKeySerialized, ipkSerialized, nil
+}
 
-	return key.Isk, ipkSerialized, err
+// ExportRevocationKey returns the PEM encodings of the passed revocation key, which
+// must be exportable, and of its public key.
+func ExportRevocationKey(key bccsp.Key) ([]byte, []byte, error) {
+	d, err := key.Bytes()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "cannot export revocation key")
+	}
+	pk, err := key.PublicKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	encodedPK, err := pk.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := x509.ParsePKIXPublicKey(encodedPK)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse revocation public key")
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, nil, errors.Errorf("revocation public key is of type %T, not of type ECDSA", pub)
+	}
+	encodedSK, err := x509.MarshalECPrivateKey(&ecdsa.PrivateKey{PublicKey: *ecdsaPub, D: new(big.Int).SetBytes(d)})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal revocation key")
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encodedSK}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedPK}),
+		nil
+}
+
+// ImportRevocationKey imports the PEM encoded revocation key into the given idemix BCCSP.
+func ImportRevocationKey(csp bccsp.BCCSP, pemEncodedSK []byte) (bccsp.Key, error) {
+	key, err := csp.KeyImport(pemEncodedSK, &bccsp.IdemixRevocationKeyImportOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot import revocation key")
+	}
+	return key, nil
 }
 
 // GenerateSignerConfig creates a new signer config.
 // It generates a fresh user secret and issues a credential
 // with four attributes (described above) using the CA's key pair.
-func GenerateSignerConfig(roleMask int, ouString string, enrollmentId string, revocationHandle int, key *idemix.IssuerKey, revKey *ecdsa.PrivateKey) ([]byte, error) {
-	attrs := make([]*FP256BN.BIG, 4)
-
+// The credential and the credential revocation information are
+// signed with the issuer key and the revocation key through the
+// given idemix BCCSP, which must export the user secret keys.
+func GenerateSignerConfig(csp bccsp.BCCSP, roleMask int, ouString string, enrollmentId string, revocationHandle int, issuerKey bccsp.Key, revocationKey bccsp.Key) ([]byte, error) {
 	if ouString == "" {
 		return nil, errors.Errorf("the OU attribute value is empty")
 	}
@@ -53,41 +172,53 @@ func GenerateSignerConfig(roleMask int, ouString string, enrollmentId string, re
 		return nil, errors.Errorf("the enrollment id value is empty")
 	}
 
-	attrs[msp.AttributeIndexOU] = idemix.HashModOrder([]byte(ouString))
-	attrs[msp.AttributeIndexRole] = FP256BN.NewBIGint(roleMask)
-	attrs[msp.AttributeIndexEnrollmentId] = idemix.HashModOrder([]byte(enrollmentId))
-	attrs[msp.AttributeIndexRevocationHandle] = FP256BN.NewBIGint(revocationHandle)
+	ipk, err := issuerKey.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	userKey, err := csp.KeyGen(&bccsp.IdemixUserSecretKeyGenOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate a user secret key")
+	}
+	sk, err := userKey.Bytes()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to export the user secret key")
+	}
 
 	rng, err := idemix.GetRand()
 	if err != nil {
 		return nil, errors.WithMessage(err, "Error getting PRNG")
 	}
-	sk := idemix.RandModOrder(rng)
 	ni := idemix.BigToBytes(idemix.RandModOrder(rng))
-	msg := idemix.NewCredRequest(sk, ni, key.Ipk, rng)
-	cred, err := idemix.NewCredential(key, msg, attrs, rng)
+	credRequest, err := csp.Sign(userKey, nil, &bccsp.IdemixCredentialRequestSignerOpts{IssuerPK: ipk, IssuerNonce: ni})
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to generate a credential")
+		return nil, errors.WithMessage(err, "failed to generate a credential request")
 	}
 
-	credBytes, err := proto.Marshal(cred)
+	attrs := make([]bccsp.IdemixAttribute, 4)
+	attrs[msp.AttributeIndexOU] = bccsp.IdemixAttribute{Type: bccsp.IdemixBytesAttribute, Value: []byte(ouString)}
+	attrs[msp.AttributeIndexRole] = bccsp.IdemixAttribute{Type: bccsp.IdemixIntAttribute, Value: roleMask}
+	attrs[msp.AttributeIndexEnrollmentId] = bccsp.IdemixAttribute{Type: bccsp.IdemixBytesAttribute, Value: []byte(enrollmentId)}
+	attrs[msp.AttributeIndexRevocationHandle] = bccsp.IdemixAttribute{Type: bccsp.IdemixIntAttribute, Value: revocationHandle}
+	credBytes, err := csp.Sign(issuerKey, credRequest, &bccsp.IdemixCredentialSignerOpts{Attributes: attrs, IssuerPK: ipk})
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to marshal credential")
+		return nil, errors.WithMessage(err, "failed to generate a credential")
 	}
 
 	// NOTE currently, idemixca creates CRI's with "ALG_NO_REVOCATION"
-	cri, err := idemix.CreateCRI(revKey, []*FP256BN.BIG{FP256BN.NewBIGint(revocationHandle)}, 0, idemix.ALG_NO_REVOCATION, rng)
-	if err != nil {
-		return nil, err
-	}
-	criBytes, err := proto.Marshal(cri)
+	criBytes, err := csp.Sign(revocationKey, nil, &bccsp.IdemixCRISignerOpts{
+		Epoch:               0,
+		RevocationAlgorithm: bccsp.AlgNoRevocation,
+		UnrevokedHandles:    [][]byte{idemix.BigToBytes(FP256BN.NewBIGint(revocationHandle))},
+	})
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to marshal CRI")
+		return nil, errors.WithMessage(err, "failed to generate a CRI")
 	}
 
 	signer := &m.IdemixMSPSignerConfig{
 		Cred:                            credBytes,
-		Sk:                              idemix.BigToBytes(sk),
+		Sk:                              sk,
 		OrganizationalUnitIdentifier:    ouString,
 		Role:                            int32(roleMask),
 		EnrollmentId:                    enrollmentId,
@@ -7,14 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package idemixca
 
 import (
-	"encoding/pem"
-	"github.com/cetcxinlian/cryptogm/x509"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	idemixcsp "github.com/hyperledger/fabric/bccsp/idemix"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/idemix"
 	m "github.com/hyperledger/fabric/msp"
@@ -27,31 +27,28 @@ var testDir = filepath.Join(os.TempDir(), "idemixca-test")
 func TestIdemixCa(t *testing.T) {
 	cleanup()
 
-	isk, ipkBytes, err := GenerateIssuerKey()
+	csp, err := idemixcsp.NewWithExportableKeys(sw.NewDummyKeyStore())
 	assert.NoError(t, err)
 
-	revocationkey, err := idemix.GenerateLongTermRevocationKey()
+	key, err := GenerateIssuerKey(csp)
 	assert.NoError(t, err)
-
-	ipk := &idemix.IssuerPublicKey{}
-	err = proto.Unmarshal(ipkBytes, ipk)
+	_, ipkBytes, err := ExportIssuerKey(key)
 	assert.NoError(t, err)
 
-	encodedRevocationPK, err := x509.MarshalPKIXPublicKey(revocationkey.Public())
+	revocationkey, err := GenerateRevocationKey(csp)
+	assert.NoError(t, err)
+	_, pemEncodedRevocationPK, err := ExportRevocationKey(revocationkey)
 	assert.NoError(t, err)
-	pemEncodedRevocationPK := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedRevocationPK})
 
 	writeVerifierToFile(ipkBytes, pemEncodedRevocationPK)
 
-	key := &idemix.IssuerKey{Isk: isk, Ipk: ipk}
-
-	conf, err := GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 1, key, revocationkey)
+	conf, err := GenerateSignerConfig(csp, m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 1, key, revocationkey)
 	assert.NoError(t, err)
 	cleanupSigner()
 	assert.NoError(t, writeSignerToFile(conf))
 	assert.NoError(t, setupMSP())
 
-	conf, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "enrollmentid2", 1234, key, revocationkey)
+	conf, err = GenerateSignerConfig(csp, m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "enrollmentid2", 1234, key, revocationkey)
 	assert.NoError(t, err)
 	cleanupSigner()
 	assert.NoError(t, writeSignerToFile(conf))
@@ -61,13 +58,123 @@ func TestIdemixCa(t *testing.T) {
 	cleanupVerifier()
 	assert.Error(t, setupMSP())
 
-	_, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "", "enrollmentid", 1, key, revocationkey)
+	_, err = GenerateSignerConfig(csp, m.GetRoleMaskFromIdemixRole(m.ADMIN), "", "enrollmentid", 1, key, revocationkey)
 	assert.EqualError(t, err, "the OU attribute value is empty")
 
-	_, err = GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "", 1, key, revocationkey)
+	_, err = GenerateSignerConfig(csp, m.GetRoleMaskFromIdemixRole(m.ADMIN), "OU1", "", 1, key, revocationkey)
 	assert.EqualError(t, err, "the enrollment id value is empty")
 }
 
+func TestImportExportKeys(t *testing.T) {
+	cleanup()
+
+	csp, err := idemixcsp.NewWithExportableKeys(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+
+	key, err := GenerateIssuerKey(csp)
+	assert.NoError(t, err)
+	isk, ipkBytes, err := ExportIssuerKey(key)
+	assert.NoError(t, err)
+	revocationkey, err := GenerateRevocationKey(csp)
+	assert.NoError(t, err)
+	pemEncodedRevocationSK, pemEncodedRevocationPK, err := ExportRevocationKey(revocationkey)
+	assert.NoError(t, err)
+	writeVerifierToFile(ipkBytes, pemEncodedRevocationPK)
+
+	// the keys written by idemixgen can be imported into a BCCSP whose keys
+	// cannot be exported, except for the user secret keys
+	nonExportableCSP, err := idemixcsp.New(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	importedKey, err := ImportIssuerKey(nonExportableCSP, isk, ipkBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, key.SKI(), importedKey.SKI())
+	_, _, err = ExportIssuerKey(importedKey)
+	assert.EqualError(t, err, "cannot export CA key: not exportable")
+	importedRevocationKey, err := ImportRevocationKey(nonExportableCSP, pemEncodedRevocationSK)
+	assert.NoError(t, err)
+	assert.Equal(t, revocationkey.SKI(), importedRevocationKey.SKI())
+	_, _, err = ExportRevocationKey(importedRevocationKey)
+	assert.EqualError(t, err, "cannot export revocation key: not exportable")
+
+	conf, err := GenerateSignerConfig(csp, m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 1, importedKey, importedRevocationKey)
+	assert.NoError(t, err)
+	cleanupSigner()
+	assert.NoError(t, writeSignerToFile(conf))
+	assert.NoError(t, setupMSP())
+
+	// the user secret key is part of the signer config
+	_, err = GenerateSignerConfig(nonExportableCSP, m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 1, importedKey, importedRevocationKey)
+	assert.EqualError(t, err, "failed to export the user secret key: not exportable")
+
+	// an issuer secret key which does not match the issuer public key cannot be imported
+	otherKey, err := GenerateIssuerKey(csp)
+	assert.NoError(t, err)
+	otherIsk, _, err := ExportIssuerKey(otherKey)
+	assert.NoError(t, err)
+	_, err = ImportIssuerKey(csp, otherIsk, ipkBytes)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid issuer key, the secret key does not match the public key")
+
+	_, err = ImportRevocationKey(csp, []byte("not a key"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to parse revocation ECDSA private key bytes")
+}
+
+func TestAuditEscrow(t *testing.T) {
+	cleanup()
+
+	csp, err := idemixcsp.NewWithExportableKeys(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+
+	key, err := GenerateIssuerKey(csp)
+	assert.NoError(t, err)
+	isk, ipkBytes, err := ExportIssuerKey(key)
+	assert.NoError(t, err)
+	escrowKeyBytes, ipkBytes, err := GenerateAuditEscrowKey(ipkBytes)
+	assert.NoError(t, err)
+	key, err = ImportIssuerKey(csp, isk, ipkBytes)
+	assert.NoError(t, err)
+	revocationkey, err := GenerateRevocationKey(csp)
+	assert.NoError(t, err)
+	_, pemEncodedRevocationPK, err := ExportRevocationKey(revocationkey)
+	assert.NoError(t, err)
+	writeVerifierToFile(ipkBytes, pemEncodedRevocationPK)
+
+	escrowKey := &idemix.AuditEscrowKey{}
+	assert.NoError(t, proto.Unmarshal(escrowKeyBytes, escrowKey))
+
+	// the signatures of the identities escrow their enrollment id
+	auditTag := func(enrollmentID string) []byte {
+		conf, err := GenerateSignerConfig(csp, m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", enrollmentID, 1, key, revocationkey)
+		assert.NoError(t, err)
+		cleanupSigner()
+		assert.NoError(t, writeSignerToFile(conf))
+		msp, err := newMSP()
+		assert.NoError(t, err)
+		signer, err := msp.GetDefaultSigningIdentity()
+		assert.NoError(t, err)
+		assert.NoError(t, signer.Validate())
+
+		serialized, err := signer.Serialize()
+		assert.NoError(t, err)
+		sid := &mspproto.SerializedIdentity{}
+		assert.NoError(t, proto.Unmarshal(serialized, sid))
+		idemixID := &mspproto.SerializedIdemixIdentity{}
+		assert.NoError(t, proto.Unmarshal(sid.IdBytes, idemixID))
+		sig := &idemix.Signature{}
+		assert.NoError(t, proto.Unmarshal(idemixID.Proof, sig))
+		tag, err := sig.AuditEscrow.Decrypt(escrowKey)
+		assert.NoError(t, err)
+		return tag
+	}
+	tag1 := auditTag("enrollmentid1")
+	assert.Equal(t, tag1, auditTag("enrollmentid1"))
+	assert.NotEqual(t, tag1, auditTag("enrollmentid2"))
+
+	_, _, err = GenerateAuditEscrowKey([]byte("not a key"))
+	assert.Error(t, err)
+}
+
 func cleanup() error {
 	// clean up any previous files
 	err := os.RemoveAll(testDir)
@@ -109,9 +216,14 @@ func writeSignerToFile(signerBytes []byte) error {
 // setupMSP tests whether we can successfully setup an idemix msp
 // with the generated config bytes
 func setupMSP() error {
+	_, err := newMSP()
+	return err
+}
+
+func newMSP() (m.MSP, error) {
 	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// setup an idemix msp from the test directory
 	msp, err := m.New(
@@ -119,13 +231,13 @@ func setupMSP() error {
 		cryptoProvider,
 	)
 	if err != nil {
-		return errors.Wrap(err, "Getting MSP failed")
+		return nil, errors.Wrap(err, "Getting MSP failed")
 	}
 	mspConfig, err := m.GetIdemixMspConfig(testDir, "TestName")
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return msp.Setup(mspConfig)
+	return msp, msp.Setup(mspConfig)
 }
@@ -0,0 +1,283 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("tracing")
+
+const (
+	defaultFlushInterval = 5 * time.Second
+	defaultMaxBatchSize  = 512
+	defaultQueueSize     = 4096
+	defaultTimeout       = 10 * time.Second
+
+	// the OpenTelemetry status codes
+	statusCodeUnset = 0
+	statusCodeError = 2
+)
+
+// OTLPConfig configures an OTLPExporter.
+type OTLPConfig struct {
+	// Endpoint is the URL of the OTLP/HTTP traces endpoint of the collector,
+	// e.g. http://127.0.0.1:4318/v1/traces.
+	Endpoint string
+	// ServiceName and InstanceID identify the node emitting the spans.
+	ServiceName string
+	InstanceID  string
+	// FlushInterval is the maximum time a span waits before it is sent.
+	FlushInterval time.Duration
+	// MaxBatchSize is the maximum number of spans sent in one request.
+	MaxBatchSize int
+	// QueueSize is the number of spans queued for export beyond which the
+	// new spans are dropped.
+	QueueSize int
+	// Timeout bounds each request to the collector.
+	Timeout time.Duration
+}
+
+// OTLPExporter sends the spans in batches to an OpenTelemetry collector
+// with the OTLP/HTTP protocol, in its JSON encoding.
+type OTLPExporter struct {
+	endpoint      string
+	resource      []Attribute
+	flushInterval time.Duration
+	maxBatchSize  int
+	client        *http.Client
+
+	spans    chan *Span
+	stopOnce sync.Once
+	stop     chan struct{}
+	stopped  chan struct{}
+
+	mutex   sync.Mutex
+	dropped int
+}
+
+// NewOTLPExporter creates an OTLPExporter and starts sending the spans.
+func NewOTLPExporter(config OTLPConfig) *OTLPExporter {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultFlushInterval
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = defaultMaxBatchSize
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	resource := []Attribute{{Key: "service.name", Value: config.ServiceName}}
+	if config.InstanceID != "" {
+		resource = append(resource, Attribute{Key: "service.instance.id", Value: config.InstanceID})
+	}
+
+	e := &OTLPExporter{
+		endpoint:      config.Endpoint,
+		resource:      resource,
+		flushInterval: config.FlushInterval,
+		maxBatchSize:  config.MaxBatchSize,
+		client:        &http.Client{Timeout: config.Timeout},
+		spans:         make(chan *Span, config.QueueSize),
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// Export queues the span for export. The span is dropped if the queue is
+// full.
+func (e *OTLPExporter) Export(span *Span) {
+	select {
+	case e.spans <- span:
+	default:
+		e.mutex.Lock()
+		e.dropped++
+		e.mutex.Unlock()
+	}
+}
+
+// Stop sends the queued spans and stops the exporter.
+func (e *OTLPExporter) Stop() {
+	e.stopOnce.Do(func() { close(e.stop) })
+	<-e.stopped
+}
+
+func (e *OTLPExporter) run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	var batch []*Span
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			logger.Warningf("Failed to export %d spans to %s: %s", len(batch), e.endpoint, err)
+		}
+		batch = nil
+		e.logDropped()
+	}
+
+	for {
+		select {
+		case span := <-e.spans:
+			batch = append(batch, span)
+			if len(batch) >= e.maxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			for {
+				select {
+				case span := <-e.spans:
+					batch = append(batch, span)
+					if len(batch) >= e.maxBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *OTLPExporter) logDropped() {
+	e.mutex.Lock()
+	dropped := e.dropped
+	e.dropped = 0
+	e.mutex.Unlock()
+	if dropped > 0 {
+		logger.Warningf("Dropped %d spans as the export queue was full", dropped)
+	}
+}
+
+func (e *OTLPExporter) send(batch []*Span) error {
+	body, err := json.Marshal(e.encode(batch))
+	if err != nil {
+		return errors.Wrap(err, "failed to encode spans")
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+// The OTLP/HTTP JSON encoding of the spans.
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func encodeAttributes(attributes []Attribute) []otlpAttribute {
+	var encoded []otlpAttribute
+	for _, a := range attributes {
+		encoded = append(encoded, otlpAttribute{Key: a.Key, Value: otlpValue{StringValue: a.Value}})
+	}
+	return encoded
+}
+
+func (e *OTLPExporter) encode(batch []*Span) *otlpTraces {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		status := otlpStatus{Code: statusCodeUnset}
+		if s.Error != "" {
+			status = otlpStatus{Code: statusCodeError, Message: s.Error}
+		}
+		spans = append(spans, otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			ParentSpanID:      s.ParentSpanID.String(),
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        encodeAttributes(s.Attributes),
+			Status:            status,
+		})
+	}
+
+	return &otlpTraces{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: encodeAttributes(e.resource)},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/hyperledger/fabric"},
+				Spans: spans,
+			}},
+		}},
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type collector struct {
+	mutex    sync.Mutex
+	requests []*otlpTraces
+	status   int
+}
+
+func (c *collector) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	traces := &otlpTraces{}
+	if err := json.NewDecoder(req.Body).Decode(traces); err == nil && req.Header.Get("Content-Type") == "application/json" {
+		c.requests = append(c.requests, traces)
+	}
+	if c.status != 0 {
+		resp.WriteHeader(c.status)
+	}
+}
+
+func (c *collector) spans() []otlpSpan {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var spans []otlpSpan
+	for _, r := range c.requests {
+		for _, rs := range r.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	return spans
+}
+
+func TestOTLPExporter(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:      server.URL,
+		ServiceName:   "peer",
+		InstanceID:    "peer0",
+		FlushInterval: time.Hour,
+	})
+	tracer := NewTracer(exporter)
+
+	span := tracer.StartSpan("tx1", "endorser.ProcessProposal", SpanKindServer, "fabric.channel", "mychannel")
+	span.StartChild("endorser.SimulateProposal").Finish(errors.New("boom"))
+	span.Finish(nil)
+
+	// stopping the exporter sends the queued spans
+	exporter.Stop()
+	exporter.Stop()
+
+	require.Len(t, c.requests, 1)
+	require.Len(t, c.requests[0].ResourceSpans, 1)
+	rs := c.requests[0].ResourceSpans[0]
+	assert.Equal(t, []otlpAttribute{
+		{Key: "service.name", Value: otlpValue{StringValue: "peer"}},
+		{Key: "service.instance.id", Value: otlpValue{StringValue: "peer0"}},
+	}, rs.Resource.Attributes)
+
+	spans := c.spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "endorser.SimulateProposal", spans[0].Name)
+	assert.Equal(t, span.SpanID.String(), spans[0].ParentSpanID)
+	assert.Equal(t, otlpStatus{Code: statusCodeError, Message: "boom"}, spans[0].Status)
+
+	assert.Equal(t, "endorser.ProcessProposal", spans[1].Name)
+	assert.Equal(t, TraceIDFromTxID("tx1").String(), spans[1].TraceID)
+	assert.Equal(t, span.SpanID.String(), spans[1].SpanID)
+	assert.Empty(t, spans[1].ParentSpanID)
+	assert.Equal(t, SpanKindServer, spans[1].Kind)
+	assert.Equal(t, otlpStatus{Code: statusCodeUnset}, spans[1].Status)
+	assert.Contains(t, spans[1].Attributes, otlpAttribute{Key: "fabric.tx_id", Value: otlpValue{StringValue: "tx1"}})
+	assert.NotEmpty(t, spans[1].StartTimeUnixNano)
+	assert.NotEmpty(t, spans[1].EndTimeUnixNano)
+}
+
+func TestOTLPExporterBatches(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:      server.URL,
+		FlushInterval: 10 * time.Millisecond,
+		MaxBatchSize:  2,
+	})
+	defer exporter.Stop()
+	tracer := NewTracer(exporter)
+
+	for i := 0; i < 3; i++ {
+		tracer.StartSpan("tx1", "operation", SpanKindInternal).Finish(nil)
+	}
+	require.Eventually(t, func() bool { return len(c.spans()) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	assert.Len(t, c.requests, 2)
+}
+
+func TestOTLPExporterFailures(t *testing.T) {
+	c := &collector{status: http.StatusInternalServerError}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{Endpoint: server.URL})
+	err := exporter.send([]*Span{NewTracer(exporter).StartSpan("tx1", "operation", SpanKindInternal)})
+	assert.EqualError(t, err, "unexpected status: 500 Internal Server Error")
+	exporter.Stop()
+
+	exporter = NewOTLPExporter(OTLPConfig{Endpoint: server.URL, QueueSize: 1})
+	exporter.Stop()
+	// the exporter is stopped, the spans exceeding the queue are dropped
+	exporter.Export(&Span{})
+	exporter.Export(&Span{})
+	assert.Equal(t, 1, exporter.dropped)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tracing records the spans of the processing of transactions by the
+// nodes of the network, in the OpenTelemetry data model.
+//
+// The trace of a transaction is identified by a trace ID derived from its
+// transaction ID. As the transaction ID is carried by the channel header of
+// the proposal, of the transaction envelope broadcast to the ordering service
+// and of the transaction in the block delivered to the peers, the spans
+// recorded by the endorsing peers, the orderers and the committing peers
+// join the same trace without further propagation.
+package tracing
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+func (s SpanID) String() string {
+	if s == (SpanID{}) {
+		return ""
+	}
+	return hex.EncodeToString(s[:])
+}
+
+// TraceIDFromTxID returns the ID of the trace of a transaction.
+func TraceIDFromTxID(txID string) TraceID {
+	var traceID TraceID
+	sum := sha256.Sum256([]byte(txID))
+	copy(traceID[:], sum[:])
+	return traceID
+}
+
+func newSpanID() SpanID {
+	var spanID SpanID
+	if _, err := rand.Read(spanID[:]); err != nil {
+		logger.Warningf("Failed to generate span ID: %s", err)
+	}
+	return spanID
+}
+
+// SpanKind is the relationship of a span with the remote parties of the
+// operation it records.
+type SpanKind int
+
+// The span kinds defined by OpenTelemetry.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+	SpanKindProducer SpanKind = 4
+	SpanKindConsumer SpanKind = 5
+)
+
+// Attribute is a key/value pair describing a span.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Span records an operation of the processing of a transaction.
+type Span struct {
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	Name         string
+	Kind         SpanKind
+	Start        time.Time
+	End          time.Time
+	Attributes   []Attribute
+	Error        string
+
+	tracer *Tracer
+}
+
+// Exporter sends the spans to a tracing backend.
+type Exporter interface {
+	// Export queues a finished span for export. It must not block.
+	Export(span *Span)
+}
+
+// Tracer records the spans of a node. A nil Tracer records nothing, hence
+// the code paths can be instrumented unconditionally.
+type Tracer struct {
+	Exporter Exporter
+
+	now func() time.Time
+}
+
+// NewTracer creates a Tracer sending the spans to the exporter.
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{
+		Exporter: exporter,
+		now:      time.Now,
+	}
+}
+
+// StartSpan starts a root span of the trace of the transaction. The
+// attributes are given as alternating keys and values.
+func (t *Tracer) StartSpan(txID, name string, kind SpanKind, keyvals ...string) *Span {
+	if t == nil {
+		return nil
+	}
+	span := &Span{
+		TraceID:    TraceIDFromTxID(txID),
+		SpanID:     newSpanID(),
+		Name:       name,
+		Kind:       kind,
+		Start:      t.now(),
+		Attributes: []Attribute{{Key: "fabric.tx_id", Value: txID}},
+		tracer:     t,
+	}
+	span.SetAttributes(keyvals...)
+	return span
+}
+
+// StartChild starts a span of an operation nested in the operation of this
+// span.
+func (s *Span) StartChild(name string, keyvals ...string) *Span {
+	if s == nil {
+		return nil
+	}
+	child := &Span{
+		TraceID:      s.TraceID,
+		SpanID:       newSpanID(),
+		ParentSpanID: s.SpanID,
+		Name:         name,
+		Kind:         SpanKindInternal,
+		Start:        s.tracer.now(),
+		tracer:       s.tracer,
+	}
+	child.SetAttributes(keyvals...)
+	return child
+}
+
+// SetAttributes adds attributes, given as alternating keys and values, to
+// the span.
+func (s *Span) SetAttributes(keyvals ...string) {
+	if s == nil {
+		return
+	}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		s.Attributes = append(s.Attributes, Attribute{Key: keyvals[i], Value: keyvals[i+1]})
+	}
+}
+
+// Finish ends the span, recording the error which failed the operation, if
+// any, and exports it.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.End = s.tracer.now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	s.tracer.Exporter.Export(s)
+}
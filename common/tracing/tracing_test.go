@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExporter struct {
+	mutex sync.Mutex
+	spans []*Span
+}
+
+func (r *recordingExporter) Export(span *Span) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func TestTraceIDFromTxID(t *testing.T) {
+	assert.Equal(t, TraceIDFromTxID("tx1"), TraceIDFromTxID("tx1"))
+	assert.NotEqual(t, TraceIDFromTxID("tx1"), TraceIDFromTxID("tx2"))
+	assert.Len(t, TraceIDFromTxID("tx1").String(), 32)
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	span := tracer.StartSpan("tx1", "operation", SpanKindServer, "key", "value")
+	assert.Nil(t, span)
+
+	child := span.StartChild("child")
+	assert.Nil(t, child)
+	child.SetAttributes("key", "value")
+	child.Finish(errors.New("boom"))
+	span.Finish(nil)
+}
+
+func TestSpans(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := NewTracer(exporter)
+
+	span := tracer.StartSpan("tx1", "operation", SpanKindServer, "fabric.channel", "mychannel")
+	child := span.StartChild("suboperation", "key")
+	child.Finish(errors.New("boom"))
+	span.SetAttributes("fabric.response_status", "200")
+	span.Finish(nil)
+
+	require.Len(t, exporter.spans, 2)
+	assert.Equal(t, child, exporter.spans[0])
+	assert.Equal(t, span, exporter.spans[1])
+
+	assert.Equal(t, TraceIDFromTxID("tx1"), span.TraceID)
+	assert.Equal(t, SpanID{}, span.ParentSpanID)
+	assert.Equal(t, "", span.ParentSpanID.String())
+	assert.Equal(t, SpanKindServer, span.Kind)
+	assert.Empty(t, span.Error)
+	assert.False(t, span.End.Before(span.Start))
+	assert.Equal(t, []Attribute{
+		{Key: "fabric.tx_id", Value: "tx1"},
+		{Key: "fabric.channel", Value: "mychannel"},
+		{Key: "fabric.response_status", Value: "200"},
+	}, span.Attributes)
+
+	assert.Equal(t, span.TraceID, child.TraceID)
+	assert.Equal(t, span.SpanID, child.ParentSpanID)
+	assert.NotEqual(t, span.SpanID, child.SpanID)
+	assert.Equal(t, SpanKindInternal, child.Kind)
+	assert.Equal(t, "boom", child.Error)
+	assert.Empty(t, child.Attributes, "an attribute without value is ignored")
+}
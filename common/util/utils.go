@@ -12,6 +12,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/cetcxinlian/cryptogm/sm3"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
@@ -35,6 +36,13 @@ func ComputeSHA3256(data []byte) (hash []byte) {
 	return
 }
 
+// ComputeSM3 returns SM3 on data. Unlike the other hash functions, it doesn't
+// depend on the default BCCSP, which may not implement SM3, and it matches
+// protoutil.BlockHeaderHashSM3.
+func ComputeSM3(data []byte) (hash []byte) {
+	return sm3.SumSM3(data)
+}
+
 // GenerateBytesUUID returns a UUID based on RFC 4122 returning the generated bytes
 func GenerateBytesUUID() []byte {
 	uuid := make([]byte, 16)
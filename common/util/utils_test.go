@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"testing"
 	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
 )
 
 func TestComputeSHA256(t *testing.T) {
@@ -40,6 +42,18 @@ func TestComputeSHA3256(t *testing.T) {
 	}
 }
 
+func TestComputeSM3(t *testing.T) {
+	if !bytes.Equal(ComputeSM3([]byte("foobar")), ComputeSM3([]byte("foobar"))) {
+		t.Fatalf("Expected hashes to match, but they did not match")
+	}
+	if bytes.Equal(ComputeSM3([]byte("foobar")), ComputeSHA256([]byte("foobar"))) {
+		t.Fatalf("Expected SM3 and SHA256 hashes to be different, but they match")
+	}
+	if !bytes.Equal(ComputeSM3([]byte("foobar")), sm3.SumSM3([]byte("foobar"))) {
+		t.Fatalf("Expected the SM3 hash of the sm3 package")
+	}
+}
+
 func TestUUIDGeneration(t *testing.T) {
 	uuid := GenerateUUID()
 	if len(uuid) != 36 {
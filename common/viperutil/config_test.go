@@ -205,6 +205,29 @@ func TestByteSizeOverflow(t *testing.T) {
 	}
 }
 
+func TestByteSizeUint64(t *testing.T) {
+	config := viper.New()
+	config.SetConfigType("yaml")
+
+	data := "---\nInner:\n    ByteSize: 500GB"
+	err := config.ReadConfig(bytes.NewReader([]byte(data)))
+	if err != nil {
+		t.Fatalf("Error reading config: %s", err)
+	}
+	var uconf struct {
+		Inner struct {
+			ByteSize uint64
+		}
+	}
+	err = EnhancedExactUnmarshal(config, &uconf)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal with: %s", err)
+	}
+	if uconf.Inner.ByteSize != 500*1024*1024*1024 {
+		t.Fatalf("Did not get back the right byte size, expected: %v got %v", 500*1024*1024*1024, uconf.Inner.ByteSize)
+	}
+}
+
 type stringFromFileConfig struct {
 	Inner struct {
 		Single   string
@@ -137,7 +137,7 @@ func customDecodeHook(f reflect.Type, t reflect.Type, data interface{}) (interfa
 }
 
 func byteSizeDecodeHook(f reflect.Kind, t reflect.Kind, data interface{}) (interface{}, error) {
-	if f != reflect.String || t != reflect.Uint32 {
+	if f != reflect.String || (t != reflect.Uint32 && t != reflect.Uint64) {
 		return data, nil
 	}
 	raw := data.(string)
@@ -161,7 +161,7 @@ func byteSizeDecodeHook(f reflect.Kind, t reflect.Kind, data interface{}) (inter
 		case "k":
 			size = size << 10
 		}
-		if size > math.MaxUint32 {
+		if t == reflect.Uint32 && size > math.MaxUint32 {
 			return size, fmt.Errorf("value '%s' overflows uint32", raw)
 		}
 		return size, nil
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+)
+
+// ChaincodeEventACLProvider enforces the ACLs restricting the delivery of the events of
+// individual chaincodes. The ACL of the events of a chaincode is defined in the ACLs of
+// the application channel config, with the resource name event/ChaincodeEvents/<chaincode name>.
+type ChaincodeEventACLProvider struct {
+	resGetter ResourceGetter
+}
+
+// NewChaincodeEventACLProvider creates a ChaincodeEventACLProvider looking up the ACLs
+// in the channel config returned by the resource getter
+func NewChaincodeEventACLProvider(rg ResourceGetter) *ChaincodeEventACLProvider {
+	return &ChaincodeEventACLProvider{resGetter: rg}
+}
+
+// CheckChaincodeEventPolicies returns, for each chaincode of the channel whose events are
+// restricted by an ACL, whether the envelope satisfies the ACL.
+func (p *ChaincodeEventACLProvider) CheckChaincodeEventPolicies(envelope *common.Envelope, channelID string) (map[string]bool, error) {
+	resCfg := p.resGetter(channelID)
+	if resCfg == nil {
+		return nil, nil
+	}
+	app, exists := resCfg.ApplicationConfig()
+	if !exists || app.APIPolicyMapper() == nil {
+		return nil, nil
+	}
+
+	pp := &aclmgmtPolicyProviderImpl{&policyEvaluatorImpl{resCfg}}
+	return checkChaincodeEventACLs(pp, app.APIPolicyMapper().APIsWithPrefix(resources.ChaincodeEventsPrefix), envelope), nil
+}
+
+func checkChaincodeEventACLs(pp aclmgmtPolicyProvider, resNames []string, idinfo interface{}) map[string]bool {
+	allowed := map[string]bool{}
+	for _, resName := range resNames {
+		chaincodeName := strings.TrimPrefix(resName, resources.ChaincodeEventsPrefix)
+		if chaincodeName == "" {
+			continue
+		}
+		err := pp.CheckACL(pp.GetPolicyName(resName), idinfo)
+		if err != nil {
+			aclLogger.Debugf("access denied for the events of chaincode [%s]: %s", chaincodeName, err)
+		}
+		allowed[chaincodeName] = err == nil
+	}
+	return allowed
+}
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaincodeEventACLs(t *testing.T) {
+	peval := &mockPolicyEvaluatorImpl{
+		pmap: map[string]string{
+			"event/ChaincodeEvents/assets": "/Channel/Application/Readers",
+			"event/ChaincodeEvents/mycc":   "/Channel/Application/Admins",
+		},
+		peval: map[string]error{
+			"/Channel/Application/Readers": nil,
+			"/Channel/Application/Admins":  errors.New("signature set did not satisfy policy"),
+		},
+	}
+	pprov := newPolicyProvider(peval)
+	env, err := protoutil.CreateSignedEnvelope(common.HeaderType_DELIVER_SEEK_INFO, "mychannel", &mocks.SignerSerializer{}, &orderer.SeekInfo{}, 0, 0)
+	require.NoError(t, err)
+
+	allowed := checkChaincodeEventACLs(pprov, []string{
+		"event/ChaincodeEvents/assets",
+		"event/ChaincodeEvents/mycc",
+		"event/ChaincodeEvents/",
+	}, env)
+	assert.Equal(t, map[string]bool{"assets": true, "mycc": false}, allowed)
+}
+
+func TestChaincodeEventACLsNoChannel(t *testing.T) {
+	p := NewChaincodeEventACLProvider(func(string) channelconfig.Resources { return nil })
+	allowed, err := p.CheckChaincodeEventPolicies(&common.Envelope{}, "mychannel")
+	assert.NoError(t, err)
+	assert.Empty(t, allowed)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/pkg/errors"
+)
+
+// ChaincodeFunctionACLProvider enforces the ACLs protecting individual chaincode functions.
+// The ACL of a function is defined in the ACLs of the application channel config, with the
+// resource name <chaincode name>:<function name>. The functions without an ACL are only
+// subject to the peer/Propose ACL.
+type ChaincodeFunctionACLProvider struct {
+	resGetter ResourceGetter
+}
+
+// NewChaincodeFunctionACLProvider creates a ChaincodeFunctionACLProvider looking up the ACLs
+// in the channel config returned by the resource getter
+func NewChaincodeFunctionACLProvider(rg ResourceGetter) *ChaincodeFunctionACLProvider {
+	return &ChaincodeFunctionACLProvider{resGetter: rg}
+}
+
+// CheckACL checks the ACL of the chaincode function for the channel using the idinfo.
+// It succeeds if no ACL is defined for the function.
+func (p *ChaincodeFunctionACLProvider) CheckACL(channelID, chaincodeName, function string, idinfo interface{}) error {
+	resCfg := p.resGetter(channelID)
+	if resCfg == nil {
+		return nil
+	}
+
+	pp := &aclmgmtPolicyProviderImpl{&policyEvaluatorImpl{resCfg}}
+	return checkChaincodeFunctionACL(pp, resources.ChaincodeFunction(chaincodeName, function), idinfo)
+}
+
+func checkChaincodeFunctionACL(pp aclmgmtPolicyProvider, resName string, idinfo interface{}) error {
+	policyName := pp.GetPolicyName(resName)
+	if policyName == "" {
+		return nil
+	}
+
+	aclLogger.Debugf("acl policy %s found in config for chaincode function %s", policyName, resName)
+	if err := pp.CheckACL(policyName, idinfo); err != nil {
+		return errors.WithMessagef(err, "access denied for chaincode function [%s]", resName)
+	}
+	return nil
+}
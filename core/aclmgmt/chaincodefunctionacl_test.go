@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aclmgmt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaincodeFunctionACL(t *testing.T) {
+	peval := &mockPolicyEvaluatorImpl{
+		pmap:  map[string]string{"mycc:transfer": "/Channel/Application/Admins"},
+		peval: map[string]error{"/Channel/Application/Admins": nil},
+	}
+	pprov := newPolicyProvider(peval)
+	sProp, _ := protoutil.MockSignedEndorserProposalOrPanic("A", &peer.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+
+	err := checkChaincodeFunctionACL(pprov, "mycc:transfer", sProp)
+	assert.NoError(t, err)
+
+	// functions without ACL are not restricted
+	err = checkChaincodeFunctionACL(pprov, "mycc:query", sProp)
+	assert.NoError(t, err)
+
+	peval.peval["/Channel/Application/Admins"] = errors.New("signature set did not satisfy policy")
+	err = checkChaincodeFunctionACL(pprov, "mycc:transfer", sProp)
+	assert.EqualError(t, err, "access denied for chaincode function [mycc:transfer]: failed evaluating policy on signed data during check policy [/Channel/Application/Admins]: [signature set did not satisfy policy]")
+}
+
+func TestChaincodeFunctionACLNoChannel(t *testing.T) {
+	p := NewChaincodeFunctionACLProvider(func(string) channelconfig.Resources { return nil })
+	err := p.CheckACL("mychannel", "mycc", "transfer", struct{}{})
+	assert.NoError(t, err)
+}
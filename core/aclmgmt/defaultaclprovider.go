@@ -21,6 +21,10 @@ import (
 const (
 	CHANNELREADERS = policies.ChannelApplicationReaders
 	CHANNELWRITERS = policies.ChannelApplicationWriters
+	// CHANNELAUDITORS is the policy of the auditors of the channel. It is not
+	// part of the default channel config, so the resources it guards are
+	// denied unless the channel defines it or overrides their ACL.
+	CHANNELAUDITORS = policies.PathSeparator + policies.ChannelPrefix + policies.PathSeparator + policies.ApplicationPrefix + policies.PathSeparator + "Auditors"
 )
 
 type defaultACLProvider interface {
@@ -54,6 +58,9 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	d.pResourcePolicyMap[resources.Lifecycle_QueryInstalledChaincodes] = mgmt.Admins
 	d.pResourcePolicyMap[resources.Lifecycle_ApproveChaincodeDefinitionForMyOrg] = mgmt.Admins
 	d.pResourcePolicyMap[resources.Lifecycle_QueryApprovedChaincodeDefinition] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Lifecycle_QueryAllChaincodeDefinitions] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Lifecycle_AnalyzeCollectionConfigUpdate] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Lifecycle_QueryInstalledChaincodeSBOM] = mgmt.Admins
 
 	d.cResourcePolicyMap[resources.Lifecycle_CommitChaincodeDefinition] = CHANNELWRITERS
 	d.cResourcePolicyMap[resources.Lifecycle_QueryChaincodeDefinition] = CHANNELWRITERS
@@ -83,6 +90,11 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByHash] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetTransactionByID] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByTxID] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlocksByRange] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetChannelConfigView] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetChannelMembership] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetBlockHashing] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_AuditIdemixTransactions] = CHANNELAUDITORS
 
 	//--------------- CSCC resources -----------
 	//p resources (implemented by the chaincode currently)
@@ -101,6 +113,9 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	d.cResourcePolicyMap[resources.Event_Block] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Event_FilteredBlock] = CHANNELREADERS
 
+	//Custom transaction resources
+	d.cResourcePolicyMap[resources.Notarization_Notarize] = CHANNELWRITERS
+
 	return d
 }
 
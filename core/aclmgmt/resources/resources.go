@@ -22,6 +22,9 @@ const (
 	Lifecycle_QueryChaincodeDefinition           = "_lifecycle/QueryChaincodeDefinition"
 	Lifecycle_QueryChaincodeDefinitions          = "_lifecycle/QueryChaincodeDefinitions"
 	Lifecycle_CheckCommitReadiness               = "_lifecycle/CheckCommitReadiness"
+	Lifecycle_QueryAllChaincodeDefinitions       = "_lifecycle/QueryAllChaincodeDefinitions"
+	Lifecycle_AnalyzeCollectionConfigUpdate      = "_lifecycle/AnalyzeCollectionConfigUpdate"
+	Lifecycle_QueryInstalledChaincodeSBOM        = "_lifecycle/QueryInstalledChaincodeSBOM"
 
 	//Lscc resources
 	Lscc_Install                   = "lscc/Install"
@@ -35,11 +38,16 @@ const (
 	Lscc_GetCollectionsConfig      = "lscc/GetCollectionsConfig"
 
 	//Qscc resources
-	Qscc_GetChainInfo       = "qscc/GetChainInfo"
-	Qscc_GetBlockByNumber   = "qscc/GetBlockByNumber"
-	Qscc_GetBlockByHash     = "qscc/GetBlockByHash"
-	Qscc_GetTransactionByID = "qscc/GetTransactionByID"
-	Qscc_GetBlockByTxID     = "qscc/GetBlockByTxID"
+	Qscc_GetChainInfo            = "qscc/GetChainInfo"
+	Qscc_GetBlockByNumber        = "qscc/GetBlockByNumber"
+	Qscc_GetBlockByHash          = "qscc/GetBlockByHash"
+	Qscc_GetTransactionByID      = "qscc/GetTransactionByID"
+	Qscc_GetBlockByTxID          = "qscc/GetBlockByTxID"
+	Qscc_GetBlocksByRange        = "qscc/GetBlocksByRange"
+	Qscc_GetChannelConfigView    = "qscc/GetChannelConfigView"
+	Qscc_GetChannelMembership    = "qscc/GetChannelMembership"
+	Qscc_GetBlockHashing         = "qscc/GetBlockHashing"
+	Qscc_AuditIdemixTransactions = "qscc/AuditIdemixTransactions"
 
 	//Cscc resources
 	Cscc_JoinChain      = "cscc/JoinChain"
@@ -53,4 +61,23 @@ const (
 	//Events
 	Event_Block         = "event/Block"
 	Event_FilteredBlock = "event/FilteredBlock"
+
+	//Custom transactions
+	Notarization_Notarize = "notarization/Notarize"
 )
+
+// ChaincodeFunction returns the name of the resource protecting the function of
+// an application chaincode
+func ChaincodeFunction(chaincodeName, function string) string {
+	return chaincodeName + ":" + function
+}
+
+// ChaincodeEventsPrefix prefixes the names of the resources restricting the
+// delivery of the events of application chaincodes
+const ChaincodeEventsPrefix = "event/ChaincodeEvents/"
+
+// ChaincodeEvents returns the name of the resource restricting the delivery of
+// the events of an application chaincode
+func ChaincodeEvents(chaincodeName string) string {
+	return ChaincodeEventsPrefix + chaincodeName
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package accesscontrol
 
 import (
+	"encoding/pem"
 	"errors"
 	"fmt"
 
@@ -14,6 +15,7 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
 	"google.golang.org/grpc"
 )
 
@@ -57,6 +59,32 @@ func (ac *Authenticator) Generate(ccName string) (*CertAndPrivKeyPair, error) {
 	}, nil
 }
 
+// GenerateUntilRevoked returns a pair of certificate and private key, like
+// Generate, but the certificate stays associated with the given chaincode
+// name until it is revoked rather than for a limited time. This lets a
+// chaincode run outside of the peer reconnect with the same certificate.
+func (ac *Authenticator) GenerateUntilRevoked(ccName string) (*CertAndPrivKeyPair, error) {
+	cert, err := ac.mapper.genPinnedCert(ccName)
+	if err != nil {
+		return nil, err
+	}
+	return &CertAndPrivKeyPair{
+		Key:  cert.Key,
+		Cert: cert.Cert,
+	}, nil
+}
+
+// Revoke dissociates the given PEM encoded certificate from the chaincode
+// name it was generated for, so that it can no longer be used to register.
+func (ac *Authenticator) Revoke(cert []byte) error {
+	bl, _ := pem.Decode(cert)
+	if bl == nil {
+		return errors.New("could not decode the PEM structure of the certificate")
+	}
+	ac.mapper.purge(certHash(util.ComputeSHA256(bl.Bytes)))
+	return nil
+}
+
 func (ac *Authenticator) authenticate(msg *pb.ChaincodeMessage, stream grpc.ServerStream) error {
 	if msg.Type != pb.ChaincodeMessage_REGISTER {
 		logger.Warning("Got message", msg, "but expected a ChaincodeMessage_REGISTER message")
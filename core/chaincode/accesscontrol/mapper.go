@@ -51,6 +51,13 @@ func (r *certMapper) register(hash certHash, name string) {
 	})
 }
 
+// pin registers the hash without expiration, until it is purged
+func (r *certMapper) pin(hash certHash, name string) {
+	r.Lock()
+	defer r.Unlock()
+	r.m[hash] = name
+}
+
 func (r *certMapper) purge(hash certHash) {
 	r.Lock()
 	defer r.Unlock()
@@ -67,6 +74,16 @@ func (r *certMapper) genCert(name string) (*tlsgen.CertKeyPair, error) {
 	return keyPair, nil
 }
 
+func (r *certMapper) genPinnedCert(name string) (*tlsgen.CertKeyPair, error) {
+	keyPair, err := r.keyGen()
+	if err != nil {
+		return nil, err
+	}
+	hash := util.ComputeSHA256(keyPair.TLSCert.Raw)
+	r.pin(certHash(hash), name)
+	return keyPair, nil
+}
+
 // ExtractCertificateHash extracts the hash of the certificate from the stream
 func extractCertificateHashFromContext(ctx context.Context) []byte {
 	pr, extracted := peer.FromContext(ctx)
@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package accesscontrol
 
 import (
+	"encoding/pem"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,3 +38,25 @@ func TestPurge(t *testing.T) {
 	time.Sleep(time.Second * 3)
 	assert.Empty(t, m.lookup(certHash(hash)))
 }
+
+func TestPinnedCertNotPurged(t *testing.T) {
+	ca, _ := tlsgen.NewCA()
+	backupTTL := ttl
+	defer func() {
+		ttl = backupTTL
+	}()
+	ttl = time.Second
+	auth := NewAuthenticator(ca)
+	pair, err := auth.GenerateUntilRevoked("A")
+	assert.NoError(t, err)
+
+	bl, _ := pem.Decode(pair.Cert)
+	hash := certHash(util.ComputeSHA256(bl.Bytes))
+	time.Sleep(time.Second * 2)
+	assert.Equal(t, "A", auth.mapper.lookup(hash))
+
+	assert.NoError(t, auth.Revoke(pair.Cert))
+	assert.Empty(t, auth.mapper.lookup(hash))
+
+	assert.EqualError(t, auth.Revoke([]byte("not a certificate")), "could not decode the PEM structure of the certificate")
+}
@@ -0,0 +1,295 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package argschema
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MetadataKey is the key of the chaincode definition metadata which holds
+// the schema of the chaincode arguments.
+const MetadataKey = "argschema"
+
+// the types the arguments of a chaincode function may be declared with
+const (
+	TypeBytes   = "bytes"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeJSON    = "json"
+)
+
+// Schema describes the arguments the functions of a chaincode accept. The
+// first argument of an invocation is the name of the function, the others
+// are validated against the Args of that function.
+type Schema struct {
+	// Functions are the functions of the chaincode, by name.
+	Functions map[string]*Function `json:"functions"`
+
+	// AllowUnknownFunctions accepts the invocations of the functions the
+	// schema does not describe, without validating their arguments.
+	AllowUnknownFunctions bool `json:"allowUnknownFunctions,omitempty"`
+}
+
+// Function describes the arguments of a chaincode function.
+type Function struct {
+	Args []*Arg `json:"args"`
+
+	// AdditionalArgs accepts more arguments than the Args describe.
+	AdditionalArgs bool `json:"additionalArgs,omitempty"`
+}
+
+// Arg describes an argument of a chaincode function. The constraints which
+// are not set are not enforced.
+type Arg struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+
+	// Pattern is a regular expression a string argument must match.
+	Pattern string `json:"pattern,omitempty"`
+	// MinLength and MaxLength bound the length of an argument, in bytes.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Minimum and Maximum bound the value of an integer or number argument.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	// Enum lists the values the argument may take.
+	Enum []string `json:"enum,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// ValidationError reports the reason the arguments of an invocation do not
+// comply with the schema. It is returned to the clients as the JSON payload
+// of the proposal response.
+type ValidationError struct {
+	Function string `json:"function"`
+	// Index is the position of the argument following the function name,
+	// starting at 1, or 0 when the error is not about a single argument.
+	Index  int    `json:"index,omitempty"`
+	Arg    string `json:"arg,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (e *ValidationError) Error() string {
+	if e.Index == 0 {
+		return fmt.Sprintf("invalid arguments for function '%s': %s", e.Function, e.Reason)
+	}
+	return fmt.Sprintf("invalid argument '%s' at position %d for function '%s': %s", e.Arg, e.Index, e.Function, e.Reason)
+}
+
+// Parse unmarshals and checks a schema.
+func Parse(raw []byte) (*Schema, error) {
+	schema := &Schema{}
+	if err := json.Unmarshal(raw, schema); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal argument schema")
+	}
+	for name, fn := range schema.Functions {
+		if fn == nil {
+			return nil, errors.Errorf("function '%s' of the argument schema has no description", name)
+		}
+		if err := fn.check(); err != nil {
+			return nil, errors.WithMessagef(err, "invalid description of function '%s' in the argument schema", name)
+		}
+	}
+	return schema, nil
+}
+
+func (f *Function) check() error {
+	optional := false
+	for i, arg := range f.Args {
+		if arg == nil {
+			return errors.Errorf("argument at position %d has no description", i+1)
+		}
+		if arg.Name == "" {
+			return errors.Errorf("argument at position %d has no name", i+1)
+		}
+		switch arg.Type {
+		case TypeBytes, TypeString, TypeInteger, TypeNumber, TypeBoolean, TypeJSON:
+		default:
+			return errors.Errorf("argument '%s' has unknown type '%s'", arg.Name, arg.Type)
+		}
+		if optional && !arg.Optional {
+			return errors.Errorf("required argument '%s' follows an optional argument", arg.Name)
+		}
+		optional = arg.Optional
+		if arg.Pattern != "" {
+			if arg.Type != TypeString {
+				return errors.Errorf("argument '%s' of type '%s' cannot have a pattern", arg.Name, arg.Type)
+			}
+			pattern, err := regexp.Compile(arg.Pattern)
+			if err != nil {
+				return errors.Wrapf(err, "invalid pattern for argument '%s'", arg.Name)
+			}
+			arg.pattern = pattern
+		}
+		if (arg.Minimum != nil || arg.Maximum != nil) && arg.Type != TypeInteger && arg.Type != TypeNumber {
+			return errors.Errorf("argument '%s' of type '%s' cannot have a minimum or maximum", arg.Name, arg.Type)
+		}
+		if arg.MinLength != nil && arg.MaxLength != nil && *arg.MinLength > *arg.MaxLength {
+			return errors.Errorf("argument '%s' has a minLength greater than its maxLength", arg.Name)
+		}
+		if arg.Minimum != nil && arg.Maximum != nil && *arg.Minimum > *arg.Maximum {
+			return errors.Errorf("argument '%s' has a minimum greater than its maximum", arg.Name)
+		}
+	}
+	return nil
+}
+
+// Validate checks the arguments of an invocation, including the function
+// name, against the schema. The returned error is a *ValidationError.
+func (s *Schema) Validate(args [][]byte) error {
+	if len(args) == 0 {
+		if s.AllowUnknownFunctions {
+			return nil
+		}
+		return &ValidationError{Reason: "missing function name"}
+	}
+
+	name := string(args[0])
+	fn, ok := s.Functions[name]
+	if !ok {
+		if s.AllowUnknownFunctions {
+			return nil
+		}
+		return &ValidationError{Function: name, Reason: "function is not defined by the argument schema"}
+	}
+
+	params := args[1:]
+	required := 0
+	for _, arg := range fn.Args {
+		if !arg.Optional {
+			required++
+		}
+	}
+	if len(params) < required {
+		return &ValidationError{Function: name, Reason: fmt.Sprintf("expected at least %d arguments, got %d", required, len(params))}
+	}
+	if len(params) > len(fn.Args) && !fn.AdditionalArgs {
+		return &ValidationError{Function: name, Reason: fmt.Sprintf("expected at most %d arguments, got %d", len(fn.Args), len(params))}
+	}
+
+	for i, arg := range fn.Args {
+		if i >= len(params) {
+			break
+		}
+		if reason := arg.validate(params[i]); reason != "" {
+			return &ValidationError{Function: name, Index: i + 1, Arg: arg.Name, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// validate returns the reason the value does not comply with the
+// description of the argument, or an empty string
+func (a *Arg) validate(value []byte) string {
+	if a.MinLength != nil && len(value) < *a.MinLength {
+		return fmt.Sprintf("length %d is less than the minimum length %d", len(value), *a.MinLength)
+	}
+	if a.MaxLength != nil && len(value) > *a.MaxLength {
+		return fmt.Sprintf("length %d exceeds the maximum length %d", len(value), *a.MaxLength)
+	}
+
+	switch a.Type {
+	case TypeString:
+		if !utf8.Valid(value) {
+			return "not a valid UTF-8 string"
+		}
+		if a.pattern != nil && !a.pattern.Match(value) {
+			return fmt.Sprintf("does not match the pattern '%s'", a.Pattern)
+		}
+	case TypeInteger:
+		n, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return "not an integer"
+		}
+		if reason := a.validateRange(float64(n)); reason != "" {
+			return reason
+		}
+	case TypeNumber:
+		n, err := strconv.ParseFloat(string(value), 64)
+		if err != nil {
+			return "not a number"
+		}
+		if reason := a.validateRange(n); reason != "" {
+			return reason
+		}
+	case TypeBoolean:
+		if s := string(value); s != "true" && s != "false" {
+			return "not a boolean, expected 'true' or 'false'"
+		}
+	case TypeJSON:
+		if !json.Valid(value) {
+			return "not a valid JSON document"
+		}
+	}
+
+	if len(a.Enum) > 0 {
+		for _, v := range a.Enum {
+			if v == string(value) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("not one of the allowed values %v", a.Enum)
+	}
+	return ""
+}
+
+func (a *Arg) validateRange(n float64) string {
+	if a.Minimum != nil && n < *a.Minimum {
+		return fmt.Sprintf("value is less than the minimum %v", *a.Minimum)
+	}
+	if a.Maximum != nil && n > *a.Maximum {
+		return fmt.Sprintf("value exceeds the maximum %v", *a.Maximum)
+	}
+	return ""
+}
+
+// Cache memoizes the parsed schemas by their content, so that the schema of
+// a chaincode definition is parsed once rather than on every proposal. A nil
+// Cache parses the schemas on every call.
+type Cache struct {
+	mutex   sync.Mutex
+	schemas map[[sha256.Size]byte]*Schema
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{
+		schemas: map[[sha256.Size]byte]*Schema{},
+	}
+}
+
+// Parse returns the parsed schema, from the cache if it was parsed before.
+func (c *Cache) Parse(raw []byte) (*Schema, error) {
+	if c == nil {
+		return Parse(raw)
+	}
+
+	key := sha256.Sum256(raw)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if schema, ok := c.schemas[key]; ok {
+		return schema, nil
+	}
+	schema, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	c.schemas[key] = schema
+	return schema, nil
+}
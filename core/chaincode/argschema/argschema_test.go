@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package argschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const assetSchema = `{
+	"functions": {
+		"CreateAsset": {
+			"args": [
+				{"name": "id", "type": "string", "pattern": "^asset[0-9]+$"},
+				{"name": "color", "type": "string", "enum": ["blue", "red"]},
+				{"name": "size", "type": "integer", "minimum": 1, "maximum": 100},
+				{"name": "appraisal", "type": "number", "optional": true},
+				{"name": "attributes", "type": "json", "optional": true}
+			]
+		},
+		"SetFrozen": {
+			"args": [
+				{"name": "id", "type": "string", "minLength": 1, "maxLength": 16},
+				{"name": "frozen", "type": "boolean"}
+			]
+		},
+		"Log": {
+			"args": [{"name": "entry", "type": "bytes"}],
+			"additionalArgs": true
+		}
+	}
+}`
+
+func toArgs(args ...string) [][]byte {
+	var res [][]byte
+	for _, arg := range args {
+		res = append(res, []byte(arg))
+	}
+	return res
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Parse([]byte(assetSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		args        [][]byte
+		expectedErr string
+	}{
+		{"valid", toArgs("CreateAsset", "asset1", "blue", "5"), ""},
+		{"valid with optional args", toArgs("CreateAsset", "asset1", "red", "100", "10.5", `{"owner":"Tom"}`), ""},
+		{"valid boolean", toArgs("SetFrozen", "asset1", "true"), ""},
+		{"valid additional args", toArgs("Log", "a", "b", "c"), ""},
+		{"no function", nil, "invalid arguments for function '': missing function name"},
+		{"unknown function", toArgs("DeleteAsset", "asset1"), "invalid arguments for function 'DeleteAsset': function is not defined by the argument schema"},
+		{"missing args", toArgs("CreateAsset", "asset1"), "invalid arguments for function 'CreateAsset': expected at least 3 arguments, got 1"},
+		{"too many args", toArgs("SetFrozen", "asset1", "true", "now"), "invalid arguments for function 'SetFrozen': expected at most 2 arguments, got 3"},
+		{"pattern mismatch", toArgs("CreateAsset", "car1", "blue", "5"), "invalid argument 'id' at position 1 for function 'CreateAsset': does not match the pattern '^asset[0-9]+$'"},
+		{"invalid utf8", toArgs("CreateAsset", "asset1\xff", "blue", "5"), "invalid argument 'id' at position 1 for function 'CreateAsset': not a valid UTF-8 string"},
+		{"not in enum", toArgs("CreateAsset", "asset1", "green", "5"), "invalid argument 'color' at position 2 for function 'CreateAsset': not one of the allowed values [blue red]"},
+		{"not an integer", toArgs("CreateAsset", "asset1", "blue", "5.5"), "invalid argument 'size' at position 3 for function 'CreateAsset': not an integer"},
+		{"below minimum", toArgs("CreateAsset", "asset1", "blue", "0"), "invalid argument 'size' at position 3 for function 'CreateAsset': value is less than the minimum 1"},
+		{"above maximum", toArgs("CreateAsset", "asset1", "blue", "101"), "invalid argument 'size' at position 3 for function 'CreateAsset': value exceeds the maximum 100"},
+		{"not a number", toArgs("CreateAsset", "asset1", "blue", "5", "high"), "invalid argument 'appraisal' at position 4 for function 'CreateAsset': not a number"},
+		{"not json", toArgs("CreateAsset", "asset1", "blue", "5", "1", "{"), "invalid argument 'attributes' at position 5 for function 'CreateAsset': not a valid JSON document"},
+		{"not a boolean", toArgs("SetFrozen", "asset1", "yes"), "invalid argument 'frozen' at position 2 for function 'SetFrozen': not a boolean, expected 'true' or 'false'"},
+		{"too short", toArgs("SetFrozen", "", "true"), "invalid argument 'id' at position 1 for function 'SetFrozen': length 0 is less than the minimum length 1"},
+		{"too long", toArgs("SetFrozen", "asset12345678901234", "true"), "invalid argument 'id' at position 1 for function 'SetFrozen': length 19 exceeds the maximum length 16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.args)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedErr)
+			require.IsType(t, &ValidationError{}, err)
+		})
+	}
+}
+
+func TestValidateAllowUnknownFunctions(t *testing.T) {
+	schema, err := Parse([]byte(`{"allowUnknownFunctions": true, "functions": {"Get": {"args": [{"name": "key", "type": "string"}]}}}`))
+	require.NoError(t, err)
+	require.NoError(t, schema.Validate(nil))
+	require.NoError(t, schema.Validate(toArgs("Put", "key", "value")))
+	require.EqualError(t, schema.Validate(toArgs("Get")), "invalid arguments for function 'Get': expected at least 1 arguments, got 0")
+}
+
+func TestValidationErrorJSON(t *testing.T) {
+	schema, err := Parse([]byte(assetSchema))
+	require.NoError(t, err)
+	err = schema.Validate(toArgs("CreateAsset", "asset1", "green", "5"))
+	payload, err := json.Marshal(err)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"function":"CreateAsset","index":2,"arg":"color","reason":"not one of the allowed values [blue red]"}`, string(payload))
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      string
+		expectedErr string
+	}{
+		{"not json", `functions`, "could not unmarshal argument schema: invalid character 'u' in literal false (expecting 'a')"},
+		{"nil function", `{"functions": {"Get": null}}`, "function 'Get' of the argument schema has no description"},
+		{"nil arg", `{"functions": {"Get": {"args": [null]}}}`, "invalid description of function 'Get' in the argument schema: argument at position 1 has no description"},
+		{"no name", `{"functions": {"Get": {"args": [{"type": "string"}]}}}`, "invalid description of function 'Get' in the argument schema: argument at position 1 has no name"},
+		{"unknown type", `{"functions": {"Get": {"args": [{"name": "key", "type": "date"}]}}}`, "invalid description of function 'Get' in the argument schema: argument 'key' has unknown type 'date'"},
+		{"required after optional", `{"functions": {"Get": {"args": [{"name": "key", "type": "string", "optional": true}, {"name": "field", "type": "string"}]}}}`, "invalid description of function 'Get' in the argument schema: required argument 'field' follows an optional argument"},
+		{"pattern on integer", `{"functions": {"Get": {"args": [{"name": "key", "type": "integer", "pattern": "[0-9]"}]}}}`, "invalid description of function 'Get' in the argument schema: argument 'key' of type 'integer' cannot have a pattern"},
+		{"invalid pattern", `{"functions": {"Get": {"args": [{"name": "key", "type": "string", "pattern": "("}]}}}`, "invalid description of function 'Get' in the argument schema: invalid pattern for argument 'key': error parsing regexp: missing closing ): `(`"},
+		{"minimum on string", `{"functions": {"Get": {"args": [{"name": "key", "type": "string", "minimum": 1}]}}}`, "invalid description of function 'Get' in the argument schema: argument 'key' of type 'string' cannot have a minimum or maximum"},
+		{"inverted length", `{"functions": {"Get": {"args": [{"name": "key", "type": "string", "minLength": 5, "maxLength": 1}]}}}`, "invalid description of function 'Get' in the argument schema: argument 'key' has a minLength greater than its maxLength"},
+		{"inverted range", `{"functions": {"Get": {"args": [{"name": "key", "type": "integer", "minimum": 5, "maximum": 1}]}}}`, "invalid description of function 'Get' in the argument schema: argument 'key' has a minimum greater than its maximum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.schema))
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache()
+	s1, err := c.Parse([]byte(assetSchema))
+	require.NoError(t, err)
+	s2, err := c.Parse([]byte(assetSchema))
+	require.NoError(t, err)
+	require.True(t, s1 == s2)
+
+	_, err = c.Parse([]byte("{"))
+	require.Error(t, err)
+	require.Len(t, c.schemas, 1)
+
+	var nilCache *Cache
+	s3, err := nilCache.Parse([]byte(assetSchema))
+	require.NoError(t, err)
+	require.False(t, s1 == s3)
+}
@@ -117,6 +117,11 @@ type registry interface {
 	chaincode.Registry
 }
 
+//go:generate counterfeiter -o fake/warm_pool_launcher.go --fake-name WarmPoolLauncher . warmPoolLauncher
+type warmPoolLauncher interface {
+	chaincode.WarmPoolLauncher
+}
+
 //go:generate counterfeiter -o fake/application_config_retriever.go --fake-name ApplicationConfigRetriever . applicationConfigRetriever
 type applicationConfigRetriever interface {
 	chaincode.ApplicationConfigRetriever
@@ -14,8 +14,10 @@ import (
 	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/debug"
 	"github.com/hyperledger/fabric/core/chaincode/extcc"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -54,13 +56,32 @@ type Lifecycle interface {
 	ChaincodeEndorsementInfo(channelID, chaincodeName string, qe ledger.SimpleQueryExecutor) (*lifecycle.ChaincodeEndorsementInfo, error)
 }
 
+// UpgradeRouter tracks the invocations of chaincodes being upgraded, so that the chaincode
+// package of their previous definition is only stopped once it has no invocation in flight.
+type UpgradeRouter interface {
+	// Route records an invocation executed by the chaincode package of the definition it
+	// was simulated against, and returns a function to be called once it completes.
+	Route(channelID, chaincodeName, ccid string) func()
+}
+
+// DebugRouter routes the invocations of the chaincodes being debugged to the
+// chaincode processes run by their developers.
+type DebugRouter interface {
+	// Route returns the chaincode ID the invocations of a chaincode on a channel
+	// are routed to, if the chaincode is being debugged on the channel.
+	Route(channelID, chaincodeName string) (string, bool)
+}
+
 // ChaincodeSupport responsible for providing interfacing with chaincodes from the Peer.
 type ChaincodeSupport struct {
 	ACLProvider            ACLProvider
 	AppConfig              ApplicationConfigRetriever
 	BuiltinSCCs            scc.BuiltinSCCs
+	DebugRouter            DebugRouter
 	DeployedCCInfoProvider ledger.DeployedChaincodeInfoProvider
 	ExecuteTimeout         time.Duration
+	ExecutionTraces        *txtrace.Recorder
+	GMCrypto               *GMCrypto
 	InstallTimeout         time.Duration
 	HandlerMetrics         *HandlerMetrics
 	HandlerRegistry        *HandlerRegistry
@@ -70,7 +91,9 @@ type ChaincodeSupport struct {
 	Peer                   *peer.Peer
 	Runtime                Runtime
 	TotalQueryLimit        int
+	UpgradeRouter          UpgradeRouter
 	UserRunsCC             bool
+	WarmPool               *WarmPool
 }
 
 // Launch starts executing chaincode if it is not already running. This method
@@ -119,6 +142,8 @@ func (cs *ChaincodeSupport) HandleChaincodeStream(stream ccintf.ChaincodeStream)
 		DeployedCCInfoProvider: cs.DeployedCCInfoProvider,
 		AppConfig:              cs.AppConfig,
 		Metrics:                cs.HandlerMetrics,
+		ExecutionTraces:        cs.ExecutionTraces,
+		GMCrypto:               cs.GMCrypto,
 		TotalQueryLimit:        cs.TotalQueryLimit,
 	}
 
@@ -194,6 +219,29 @@ func (cs *ChaincodeSupport) Invoke(txParams *ccprovider.TransactionParams, chain
 		return nil, errors.WithMessage(err, "invalid invocation")
 	}
 
+	// the chaincode being debugged is run by the developer, not launched
+	if cs.DebugRouter != nil {
+		if debugID, ok := cs.DebugRouter.Route(txParams.ChannelID, chaincodeName); ok {
+			h := cs.HandlerRegistry.Handler(debugID)
+			if h == nil {
+				return nil, errors.Errorf("chaincode %s is being debugged on channel %s but %s is not connected", chaincodeName, txParams.ChannelID, debugID)
+			}
+			// the results of the chaincode process run by the developer must
+			// not be endorsed
+			debug.Mark(txParams.Context)
+			return cs.execute(cctype, txParams, chaincodeName, input, h)
+		}
+	}
+
+	if cs.UpgradeRouter != nil {
+		done := cs.UpgradeRouter.Route(txParams.ChannelID, chaincodeName, ccid)
+		defer done()
+	}
+
+	if !cs.BuiltinSCCs.IsSysCC(chaincodeName) {
+		cs.WarmPool.Record(ccid)
+	}
+
 	h, err := cs.Launch(ccid)
 	if err != nil {
 		return nil, err
@@ -18,19 +18,29 @@ import (
 const (
 	defaultExecutionTimeout = 30 * time.Second
 	minimumStartupTimeout   = 5 * time.Second
+	defaultDrainTimeout     = 30 * time.Second
+
+	defaultMaxExecutionTraces = 100
+	defaultWarmPoolInterval   = time.Minute
 )
 
 type Config struct {
-	TotalQueryLimit int
-	TLSEnabled      bool
-	Keepalive       time.Duration
-	ExecuteTimeout  time.Duration
-	InstallTimeout  time.Duration
-	StartupTimeout  time.Duration
-	LogFormat       string
-	LogLevel        string
-	ShimLogLevel    string
-	SCCAllowlist    map[string]bool
+	TotalQueryLimit     int
+	TLSEnabled          bool
+	Keepalive           time.Duration
+	ExecuteTimeout      time.Duration
+	InstallTimeout      time.Duration
+	StartupTimeout      time.Duration
+	LogFormat           string
+	LogLevel            string
+	ShimLogLevel        string
+	SCCAllowlist        map[string]bool
+	UpgradeEnabled      bool
+	UpgradeDrainTimeout time.Duration
+	DebugEnabled        bool
+	MaxExecutionTraces  int
+	WarmPoolSize        int
+	WarmPoolInterval    time.Duration
 }
 
 func GlobalConfig() *Config {
@@ -58,6 +68,25 @@ func (c *Config) load() {
 		c.StartupTimeout = minimumStartupTimeout
 	}
 
+	c.UpgradeEnabled = viper.GetBool("chaincode.upgrade.enabled")
+	c.UpgradeDrainTimeout = viper.GetDuration("chaincode.upgrade.drainTimeout")
+	if c.UpgradeDrainTimeout <= 0 {
+		c.UpgradeDrainTimeout = defaultDrainTimeout
+	}
+
+	c.DebugEnabled = viper.GetBool("chaincode.debug.enabled")
+
+	c.MaxExecutionTraces = viper.GetInt("chaincode.executionTraces.maxTraces")
+	if c.MaxExecutionTraces <= 0 {
+		c.MaxExecutionTraces = defaultMaxExecutionTraces
+	}
+
+	c.WarmPoolSize = viper.GetInt("chaincode.warmPool.size")
+	c.WarmPoolInterval = viper.GetDuration("chaincode.warmPool.interval")
+	if c.WarmPoolInterval <= 0 {
+		c.WarmPoolInterval = defaultWarmPoolInterval
+	}
+
 	c.SCCAllowlist = map[string]bool{}
 	for k, v := range viper.GetStringMapString("chaincode.system") {
 		c.SCCAllowlist[k] = parseBool(v)
@@ -36,6 +36,12 @@ var _ = Describe("Config", func() {
 			viper.Set("chaincode.logging.format", "test-chaincode-logging-format")
 			viper.Set("chaincode.logging.level", "warning")
 			viper.Set("chaincode.logging.shim", "warning")
+			viper.Set("chaincode.upgrade.enabled", "true")
+			viper.Set("chaincode.upgrade.drainTimeout", "2m")
+			viper.Set("chaincode.debug.enabled", "true")
+			viper.Set("chaincode.executionTraces.maxTraces", "25")
+			viper.Set("chaincode.warmPool.size", "3")
+			viper.Set("chaincode.warmPool.interval", "30s")
 
 			config := chaincode.GlobalConfig()
 			Expect(config.TLSEnabled).To(BeTrue())
@@ -46,6 +52,34 @@ var _ = Describe("Config", func() {
 			Expect(config.LogFormat).To(Equal("test-chaincode-logging-format"))
 			Expect(config.LogLevel).To(Equal("warn"))
 			Expect(config.ShimLogLevel).To(Equal("warn"))
+			Expect(config.UpgradeEnabled).To(BeTrue())
+			Expect(config.UpgradeDrainTimeout).To(Equal(2 * time.Minute))
+			Expect(config.DebugEnabled).To(BeTrue())
+			Expect(config.MaxExecutionTraces).To(Equal(25))
+			Expect(config.WarmPoolSize).To(Equal(3))
+			Expect(config.WarmPoolInterval).To(Equal(30 * time.Second))
+		})
+
+		Context("when no upgrade drain timeout is configured", func() {
+			It("falls back to the default drain timeout", func() {
+				config := chaincode.GlobalConfig()
+				Expect(config.UpgradeDrainTimeout).To(Equal(30 * time.Second))
+			})
+		})
+
+		Context("when no execution trace limit is configured", func() {
+			It("falls back to the default limit", func() {
+				config := chaincode.GlobalConfig()
+				Expect(config.MaxExecutionTraces).To(Equal(100))
+			})
+		})
+
+		Context("when no warm pool interval is configured", func() {
+			It("falls back to the default interval", func() {
+				config := chaincode.GlobalConfig()
+				Expect(config.WarmPoolSize).To(Equal(0))
+				Expect(config.WarmPoolInterval).To(Equal(time.Minute))
+			})
 		})
 
 		Context("when an invalid keepalive is configured", func() {
@@ -114,13 +148,18 @@ func capture() (restore func()) {
 	viper.SetEnvPrefix("CORE")
 	viper.AutomaticEnv()
 	config := map[string]string{
-		"peer.tls.enabled":         viper.GetString("peer.tls.enabled"),
-		"chaincode.keepalive":      viper.GetString("chaincode.keepalive"),
-		"chaincode.executetimeout": viper.GetString("chaincode.executetimeout"),
-		"chaincode.startuptimeout": viper.GetString("chaincode.startuptimeout"),
-		"chaincode.logging.format": viper.GetString("chaincode.logging.format"),
-		"chaincode.logging.level":  viper.GetString("chaincode.logging.level"),
-		"chaincode.logging.shim":   viper.GetString("chaincode.logging.shim"),
+		"peer.tls.enabled":                    viper.GetString("peer.tls.enabled"),
+		"chaincode.keepalive":                 viper.GetString("chaincode.keepalive"),
+		"chaincode.executetimeout":            viper.GetString("chaincode.executetimeout"),
+		"chaincode.startuptimeout":            viper.GetString("chaincode.startuptimeout"),
+		"chaincode.logging.format":            viper.GetString("chaincode.logging.format"),
+		"chaincode.logging.level":             viper.GetString("chaincode.logging.level"),
+		"chaincode.logging.shim":              viper.GetString("chaincode.logging.shim"),
+		"chaincode.upgrade.enabled":           viper.GetString("chaincode.upgrade.enabled"),
+		"chaincode.upgrade.drainTimeout":      viper.GetString("chaincode.upgrade.drainTimeout"),
+		"chaincode.executionTraces.maxTraces": viper.GetString("chaincode.executionTraces.maxTraces"),
+		"chaincode.warmPool.size":             viper.GetString("chaincode.warmPool.size"),
+		"chaincode.warmPool.interval":         viper.GetString("chaincode.warmPool.interval"),
 	}
 
 	return func() {
@@ -0,0 +1,313 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/chaincode/accesscontrol"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("chaincode.debug")
+
+// Session is the debug session of a chaincode on a channel. While the session
+// is open, the invocations of the chaincode on the channel are routed to the
+// chaincode process which registers with the peer under ChaincodeID, and the
+// shim messages of all its transactions are traced. The results of these
+// invocations are never endorsed. As the chaincode ID authenticates the
+// chaincode process when TLS is disabled, it is only returned when the
+// session is opened.
+type Session struct {
+	ChannelID     string    `json:"channel_id"`
+	ChaincodeName string    `json:"chaincode_name"`
+	ChaincodeID   string    `json:"chaincode_id,omitempty"`
+	PeerAddress   string    `json:"peer_address"`
+	Started       time.Time `json:"started"`
+	Connected     bool      `json:"connected"`
+}
+
+// Credentials are the PEM encoded TLS credentials the chaincode process of a
+// debug session connects to the peer with. They are only issued when TLS is
+// enabled for the chaincode connections of the peer.
+type Credentials struct {
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key"`
+	RootCert   string `json:"root_cert"`
+}
+
+// Registry tracks the chaincode processes registered with the peer.
+type Registry interface {
+	// Solicit allows a chaincode process to register with the given ID.
+	Solicit(ccid string)
+	// Unsolicit disallows a chaincode process to register with the given
+	// ID, and deregisters the one already registered.
+	Unsolicit(ccid string)
+	// Running returns the IDs of the registered chaincode processes.
+	Running() []string
+}
+
+// CertIssuer issues the client certificates chaincode processes authenticate
+// to the peer with.
+type CertIssuer interface {
+	// GenerateUntilRevoked issues a client certificate for the chaincode ID.
+	GenerateUntilRevoked(ccid string) (*accesscontrol.CertAndPrivKeyPair, error)
+	// Revoke revokes a client certificate.
+	Revoke(cert []byte) error
+}
+
+// Tracer traces the shim messages of the transactions of chaincodes.
+type Tracer interface {
+	Enable(chaincode string, sampleRate float64) error
+	Disable(chaincode string)
+	Settings() []txtrace.Settings
+}
+
+type key struct {
+	channelID     string
+	chaincodeName string
+}
+
+type session struct {
+	Session
+	cert []byte
+}
+
+// Manager manages the debug sessions of the chaincodes of the peer, replacing
+// the peer wide development mode with sessions scoped to a chaincode
+// definition: the other chaincodes are still launched by the peer, and only
+// the chaincode process a session was opened for may register in its place.
+type Manager struct {
+	registry    Registry
+	certs       CertIssuer
+	tracer      Tracer
+	peerAddress string
+	rootCert    []byte
+
+	mutex    sync.Mutex
+	sessions map[key]*session
+	// sample rates the chaincodes were traced with before their first
+	// session was opened, 0 when they were not traced
+	sampleRates map[string]float64
+}
+
+// NewManager creates a Manager. The chaincode processes of the sessions
+// connect to the peer at peerAddress and, unless certs is nil, authenticate
+// with the certificates it issues, verifying the peer with rootCert.
+func NewManager(registry Registry, certs CertIssuer, tracer Tracer, peerAddress string, rootCert []byte) *Manager {
+	return &Manager{
+		registry:    registry,
+		certs:       certs,
+		tracer:      tracer,
+		peerAddress: peerAddress,
+		rootCert:    rootCert,
+		sessions:    map[key]*session{},
+		sampleRates: map[string]float64{},
+	}
+}
+
+// Start opens a debug session for the chaincode on the channel and returns
+// it, along with the credentials its chaincode process connects with.
+func (m *Manager) Start(channelID, chaincodeName string) (*Session, *Credentials, error) {
+	if channelID == "" || chaincodeName == "" {
+		return nil, nil, errors.New("channel and chaincode name must be specified")
+	}
+	k := key{channelID: channelID, chaincodeName: chaincodeName}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.sessions[k]; ok {
+		return nil, nil, errors.Errorf("chaincode %s is already being debugged on channel %s", chaincodeName, channelID)
+	}
+
+	// the chaincode ID is unguessable, so that only the developer who
+	// opened the session can register its chaincode process
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate chaincode ID")
+	}
+	s := &session{
+		Session: Session{
+			ChannelID:     channelID,
+			ChaincodeName: chaincodeName,
+			ChaincodeID:   chaincodeName + ":debug-" + hex.EncodeToString(nonce),
+			PeerAddress:   m.peerAddress,
+			Started:       time.Now(),
+		},
+	}
+
+	var creds *Credentials
+	if m.certs != nil {
+		pair, err := m.certs.GenerateUntilRevoked(s.ChaincodeID)
+		if err != nil {
+			return nil, nil, errors.WithMessagef(err, "failed to generate TLS certificates for %s", s.ChaincodeID)
+		}
+		s.cert = pair.Cert
+		creds = &Credentials{
+			ClientCert: string(pair.Cert),
+			ClientKey:  string(pair.Key),
+			RootCert:   string(m.rootCert),
+		}
+	}
+
+	if !m.debugging(chaincodeName) {
+		m.sampleRates[chaincodeName] = m.sampleRate(chaincodeName)
+	}
+	if err := m.tracer.Enable(chaincodeName, 1); err != nil {
+		logger.Warningf("Failed to trace the transactions of chaincode %s: %s", chaincodeName, err)
+	}
+
+	m.registry.Solicit(s.ChaincodeID)
+	m.sessions[k] = s
+	logger.Infof("Started debug session of chaincode %s on channel %s, awaiting registration of %s", chaincodeName, channelID, s.ChaincodeID)
+
+	session := s.Session
+	return &session, creds, nil
+}
+
+// Stop closes the debug session of the chaincode on the channel, if any, and
+// returns whether there was one. The chaincode process of the session is
+// deregistered, and the invocations of the chaincode are routed to the
+// chaincode package of its definition again.
+func (m *Manager) Stop(channelID, chaincodeName string) bool {
+	k := key{channelID: channelID, chaincodeName: chaincodeName}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	s, ok := m.sessions[k]
+	if !ok {
+		return false
+	}
+	delete(m.sessions, k)
+
+	m.registry.Unsolicit(s.ChaincodeID)
+	if s.cert != nil {
+		if err := m.certs.Revoke(s.cert); err != nil {
+			logger.Warningf("Failed to revoke the certificate of %s: %s", s.ChaincodeID, err)
+		}
+	}
+
+	if !m.debugging(chaincodeName) {
+		m.restoreTracing(chaincodeName)
+	}
+
+	logger.Infof("Stopped debug session of chaincode %s on channel %s", chaincodeName, channelID)
+	return true
+}
+
+// Route returns the chaincode ID of the chaincode process of the debug
+// session of the chaincode on the channel, if there is one.
+func (m *Manager) Route(channelID, chaincodeName string) (string, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	s, ok := m.sessions[key{channelID: channelID, chaincodeName: chaincodeName}]
+	if !ok {
+		return "", false
+	}
+	return s.ChaincodeID, true
+}
+
+// Sessions returns the open debug sessions, without their chaincode ID,
+// sorted by channel and chaincode.
+func (m *Manager) Sessions() []Session {
+	running := map[string]bool{}
+	for _, ccid := range m.registry.Running() {
+		running[ccid] = true
+	}
+
+	m.mutex.Lock()
+	sessions := make([]Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		session := s.Session
+		session.Connected = running[s.ChaincodeID]
+		session.ChaincodeID = ""
+		sessions = append(sessions, session)
+	}
+	m.mutex.Unlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].ChannelID != sessions[j].ChannelID {
+			return sessions[i].ChannelID < sessions[j].ChannelID
+		}
+		return sessions[i].ChaincodeName < sessions[j].ChaincodeName
+	})
+	return sessions
+}
+
+// debugging returns whether the chaincode is debugged on any channel. It
+// must be called with the mutex held.
+func (m *Manager) debugging(chaincodeName string) bool {
+	for k := range m.sessions {
+		if k.chaincodeName == chaincodeName {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) sampleRate(chaincodeName string) float64 {
+	for _, s := range m.tracer.Settings() {
+		if s.Chaincode == chaincodeName {
+			return s.SampleRate
+		}
+	}
+	return 0
+}
+
+// restoreTracing traces the chaincode as it was before it was debugged. It
+// must be called with the mutex held.
+func (m *Manager) restoreTracing(chaincodeName string) {
+	rate := m.sampleRates[chaincodeName]
+	delete(m.sampleRates, chaincodeName)
+	if rate == 0 {
+		m.tracer.Disable(chaincodeName)
+		return
+	}
+	if err := m.tracer.Enable(chaincodeName, rate); err != nil {
+		logger.Warningf("Failed to restore the tracing of chaincode %s: %s", chaincodeName, err)
+	}
+}
+
+type markKey struct{}
+
+// WithMark returns a context recording whether any chaincode invoked by the
+// transaction it is passed to was executed in a debug session.
+func WithMark(ctx context.Context) context.Context {
+	return context.WithValue(ctx, markKey{}, new(int32))
+}
+
+// Mark records that a chaincode was executed in a debug session, if the
+// context was created by WithMark.
+func Mark(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if mark, ok := ctx.Value(markKey{}).(*int32); ok {
+		atomic.StoreInt32(mark, 1)
+	}
+}
+
+// Marked reports whether a chaincode was executed in a debug session since
+// the context was created by WithMark.
+func Marked(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	mark, ok := ctx.Value(markKey{}).(*int32)
+	return ok && atomic.LoadInt32(mark) == 1
+}
@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/accesscontrol"
+	"github.com/hyperledger/fabric/core/chaincode/debug"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRegistry struct {
+	mutex     sync.Mutex
+	solicited map[string]bool
+	running   map[string]bool
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{solicited: map[string]bool{}, running: map[string]bool{}}
+}
+
+func (r *fakeRegistry) Solicit(ccid string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.solicited[ccid] = true
+}
+
+func (r *fakeRegistry) Unsolicit(ccid string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.solicited, ccid)
+	delete(r.running, ccid)
+}
+
+func (r *fakeRegistry) Running() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var ccids []string
+	for ccid := range r.running {
+		ccids = append(ccids, ccid)
+	}
+	sort.Strings(ccids)
+	return ccids
+}
+
+func (r *fakeRegistry) register(ccid string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.solicited[ccid] {
+		r.running[ccid] = true
+	}
+}
+
+type fakeCertIssuer struct {
+	err     error
+	issued  map[string]string
+	revoked []string
+}
+
+func (c *fakeCertIssuer) GenerateUntilRevoked(ccid string) (*accesscontrol.CertAndPrivKeyPair, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	cert := "cert of " + ccid
+	c.issued[cert] = ccid
+	return &accesscontrol.CertAndPrivKeyPair{Cert: []byte(cert), Key: []byte("key of " + ccid)}, nil
+}
+
+func (c *fakeCertIssuer) Revoke(cert []byte) error {
+	c.revoked = append(c.revoked, string(cert))
+	return nil
+}
+
+func TestSession(t *testing.T) {
+	registry := newFakeRegistry()
+	certs := &fakeCertIssuer{issued: map[string]string{}}
+	tracer := txtrace.NewRecorder(10)
+	m := debug.NewManager(registry, certs, tracer, "peer0:7052", []byte("root cert"))
+
+	_, ok := m.Route("mychannel", "mycc")
+	assert.False(t, ok)
+
+	session, creds, err := m.Start("mychannel", "mycc")
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", session.ChannelID)
+	assert.Equal(t, "mycc", session.ChaincodeName)
+	assert.True(t, strings.HasPrefix(session.ChaincodeID, "mycc:debug-"), session.ChaincodeID)
+	assert.Equal(t, "peer0:7052", session.PeerAddress)
+	assert.False(t, session.Connected)
+	assert.Equal(t, &debug.Credentials{
+		ClientCert: "cert of " + session.ChaincodeID,
+		ClientKey:  "key of " + session.ChaincodeID,
+		RootCert:   "root cert",
+	}, creds)
+
+	// only the chaincode process of the session may register
+	assert.True(t, registry.solicited[session.ChaincodeID])
+	ccid, ok := m.Route("mychannel", "mycc")
+	assert.True(t, ok)
+	assert.Equal(t, session.ChaincodeID, ccid)
+	_, ok = m.Route("otherchannel", "mycc")
+	assert.False(t, ok)
+	_, ok = m.Route("mychannel", "othercc")
+	assert.False(t, ok)
+
+	// all the transactions of the chaincode are traced
+	assert.Equal(t, []txtrace.Settings{{Chaincode: "mycc", SampleRate: 1}}, tracer.Settings())
+
+	registry.register(session.ChaincodeID)
+	sessions := m.Sessions()
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Connected)
+	assert.Empty(t, sessions[0].ChaincodeID)
+
+	_, _, err = m.Start("mychannel", "mycc")
+	assert.EqualError(t, err, "chaincode mycc is already being debugged on channel mychannel")
+
+	assert.True(t, m.Stop("mychannel", "mycc"))
+	assert.False(t, m.Stop("mychannel", "mycc"))
+	_, ok = m.Route("mychannel", "mycc")
+	assert.False(t, ok)
+	assert.Empty(t, registry.solicited)
+	assert.Empty(t, registry.Running())
+	assert.Equal(t, []string{"cert of " + session.ChaincodeID}, certs.revoked)
+	assert.Empty(t, tracer.Settings())
+	assert.Empty(t, m.Sessions())
+}
+
+func TestSessionErrors(t *testing.T) {
+	m := debug.NewManager(newFakeRegistry(), &fakeCertIssuer{err: errors.New("boom")}, txtrace.NewRecorder(10), "peer0:7052", nil)
+
+	_, _, err := m.Start("", "mycc")
+	assert.EqualError(t, err, "channel and chaincode name must be specified")
+
+	_, _, err = m.Start("mychannel", "mycc")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	_, ok := m.Route("mychannel", "mycc")
+	assert.False(t, ok)
+}
+
+func TestSessionWithoutTLS(t *testing.T) {
+	registry := newFakeRegistry()
+	m := debug.NewManager(registry, nil, txtrace.NewRecorder(10), "peer0:7052", nil)
+
+	session, creds, err := m.Start("mychannel", "mycc")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+	assert.True(t, registry.solicited[session.ChaincodeID])
+	assert.True(t, m.Stop("mychannel", "mycc"))
+}
+
+func TestSessionTracing(t *testing.T) {
+	tracer := txtrace.NewRecorder(10)
+	require.NoError(t, tracer.Enable("mycc", 0.25))
+	m := debug.NewManager(newFakeRegistry(), nil, tracer, "peer0:7052", nil)
+
+	_, _, err := m.Start("channel1", "mycc")
+	require.NoError(t, err)
+	_, _, err = m.Start("channel2", "mycc")
+	require.NoError(t, err)
+	assert.Equal(t, []txtrace.Settings{{Chaincode: "mycc", SampleRate: 1}}, tracer.Settings())
+
+	// the chaincode is traced until its last session is stopped, then
+	// with its previous sample rate
+	m.Stop("channel1", "mycc")
+	assert.Equal(t, []txtrace.Settings{{Chaincode: "mycc", SampleRate: 1}}, tracer.Settings())
+	m.Stop("channel2", "mycc")
+	assert.Equal(t, []txtrace.Settings{{Chaincode: "mycc", SampleRate: 0.25}}, tracer.Settings())
+}
+
+func TestMark(t *testing.T) {
+	ctx := debug.WithMark(context.Background())
+	assert.False(t, debug.Marked(ctx))
+
+	// the mark is shared by the contexts derived from the marked context
+	derived, cancel := context.WithCancel(ctx)
+	defer cancel()
+	debug.Mark(derived)
+	assert.True(t, debug.Marked(ctx))
+
+	unmarked := context.Background()
+	debug.Mark(unmarked)
+	assert.False(t, debug.Marked(unmarked))
+}
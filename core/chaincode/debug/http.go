@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type startResponse struct {
+	Session     *Session     `json:"session"`
+	Credentials *Credentials `json:"credentials,omitempty"`
+}
+
+// Handler manages the debug sessions of the chaincodes through the operations
+// endpoint.
+//
+// POST with channel and chaincode query parameters opens a debug session for
+// the chaincode on the channel, returning the session along with the TLS
+// credentials its chaincode process connects with, and DELETE with the same
+// parameters closes it. GET returns the open debug sessions, without their
+// chaincode ID. As the response to a POST authorizes a chaincode process to
+// serve the invocations of the chaincode, the Handler must only be served to
+// authenticated administrators.
+type Handler struct {
+	Manager *Manager
+}
+
+// NewHandler returns a Handler for the given Manager.
+func NewHandler(m *Manager) *Handler {
+	return &Handler{Manager: m}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	channelID, chaincodeName := query.Get("channel"), query.Get("chaincode")
+
+	switch req.Method {
+	case http.MethodGet:
+		h.sendResponse(resp, http.StatusOK, h.Manager.Sessions())
+
+	case http.MethodPost:
+		session, creds, err := h.Manager.Start(channelID, chaincodeName)
+		if err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, &errorResponse{Error: err.Error()})
+			return
+		}
+		h.sendResponse(resp, http.StatusCreated, &startResponse{Session: session, Credentials: creds})
+
+	case http.MethodDelete:
+		if !h.Manager.Stop(channelID, chaincodeName) {
+			h.sendResponse(resp, http.StatusNotFound, &errorResponse{
+				Error: fmt.Sprintf("chaincode %s is not being debugged on channel %s", chaincodeName, channelID),
+			})
+			return
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+	}
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
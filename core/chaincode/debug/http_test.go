@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/debug"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	certs := &fakeCertIssuer{issued: map[string]string{}}
+	m := debug.NewManager(newFakeRegistry(), certs, txtrace.NewRecorder(10), "peer0:7052", []byte("root cert"))
+	h := debug.NewHandler(m)
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(method, target, nil))
+		return resp
+	}
+
+	resp := serve(http.MethodPost, "/chaincode/debug?channel=mychannel&chaincode=mycc")
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	var started struct {
+		Session     debug.Session     `json:"session"`
+		Credentials debug.Credentials `json:"credentials"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &started))
+	assert.Equal(t, "mycc", started.Session.ChaincodeName)
+	assert.Equal(t, "cert of "+started.Session.ChaincodeID, started.Credentials.ClientCert)
+	assert.Equal(t, "root cert", started.Credentials.RootCert)
+
+	resp = serve(http.MethodPost, "/chaincode/debug?channel=mychannel&chaincode=mycc")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"chaincode mycc is already being debugged on channel mychannel"}`, resp.Body.String())
+
+	// the chaincode ID authorizes the chaincode process and is not listed
+	resp = serve(http.MethodGet, "/chaincode/debug")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "chaincode_id")
+	var sessions []debug.Session
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &sessions))
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "mycc", sessions[0].ChaincodeName)
+
+	resp = serve(http.MethodDelete, "/chaincode/debug?channel=mychannel&chaincode=mycc")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = serve(http.MethodDelete, "/chaincode/debug?channel=mychannel&chaincode=mycc")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"error":"chaincode mycc is not being debugged on channel mychannel"}`, resp.Body.String())
+
+	resp = serve(http.MethodPut, "/chaincode/debug")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: PUT"}`, resp.Body.String())
+}
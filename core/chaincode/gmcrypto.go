@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"encoding/pem"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/cetcxinlian/cryptogm/sm4"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/pkg/gmcrypto"
+	"github.com/pkg/errors"
+)
+
+// GMCrypto performs the crypto operations requested by chaincode through
+// the gmcrypto package with the crypto provider of the peer.
+type GMCrypto struct {
+	CSP bccsp.BCCSP
+}
+
+// Perform performs the operation requested by the given transaction.
+func (g *GMCrypto) Perform(txID string, op gmcrypto.Operation, args [][]byte) ([]byte, error) {
+	switch op {
+	case gmcrypto.SM3:
+		return g.CSP.Hash(args[0], &bccsp.SM3Opts{})
+	case gmcrypto.SM4Encrypt:
+		return g.encrypt(txID, args[0], args[1])
+	case gmcrypto.SM4Decrypt:
+		return decrypt(args[0], args[1])
+	case gmcrypto.SM2Verify:
+		return nil, g.verify(args[0], args[1], args[2])
+	default:
+		return nil, errors.Errorf("unknown crypto operation '%s'", op)
+	}
+}
+
+// encrypt encrypts the plaintext in CBC mode with an initialization vector
+// computed as the HMAC-SM3, keyed by the SM4 key, of the transaction ID and
+// the plaintext, so that every endorser of the transaction produces the same
+// ciphertext while the clear initialization vector can't be used to confirm
+// guesses of the plaintext without the key.
+func (g *GMCrypto) encrypt(txID string, key, plaintext []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid SM4 key")
+	}
+	mac := hmac.New(sm3.New, key)
+	mac.Write([]byte(txID))
+	mac.Write([]byte{0})
+	mac.Write(plaintext)
+	iv := mac.Sum(nil)[:block.BlockSize()]
+
+	padding := block.BlockSize() - len(plaintext)%block.BlockSize()
+	padded := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	ciphertext := make([]byte, len(iv)+len(padded))
+	copy(ciphertext, iv)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext[len(iv):], padded)
+	return ciphertext, nil
+}
+
+func decrypt(key, ciphertext []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid SM4 key")
+	}
+	blockSize := block.BlockSize()
+	if len(ciphertext) < 2*blockSize || len(ciphertext)%blockSize != 0 {
+		return nil, errors.New("invalid SM4 ciphertext length")
+	}
+
+	plaintext := make([]byte, len(ciphertext)-blockSize)
+	cipher.NewCBCDecrypter(block, ciphertext[:blockSize]).CryptBlocks(plaintext, ciphertext[blockSize:])
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > blockSize || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("invalid SM4 ciphertext padding, is the key correct?")
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func (g *GMCrypto) verify(pemBytes, signature, msg []byte) error {
+	pemBlock, _ := pem.Decode(pemBytes)
+	if pemBlock == nil {
+		return errors.New("no PEM encoded certificate or public key found")
+	}
+
+	var key bccsp.Key
+	var err error
+	switch pemBlock.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(pemBlock.Bytes)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse certificate")
+		}
+		if _, ok := cert.PublicKey.(*sm2.PublicKey); !ok {
+			return errors.New("the certificate does not hold an SM2 public key")
+		}
+		key, err = g.CSP.KeyImport(cert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+		if err != nil {
+			return errors.WithMessage(err, "failed to import the public key of the certificate")
+		}
+	case "PUBLIC KEY":
+		key, err = g.CSP.KeyImport(pemBlock.Bytes, &bccsp.SM2PKIXPublicKeyImportOpts{Temporary: true})
+		if err != nil {
+			return errors.WithMessage(err, "failed to import the SM2 public key")
+		}
+	default:
+		return errors.Errorf("unexpected PEM block type '%s', expected a certificate or a public key", pemBlock.Type)
+	}
+
+	valid, err := g.CSP.Verify(key, signature, msg, nil)
+	if err != nil {
+		return errors.WithMessage(err, "failed to verify the signature")
+	}
+	if !valid {
+		return errors.New("the signature is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/pkg/gmcrypto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GMCrypto", func() {
+	var gmCrypto *chaincode.GMCrypto
+
+	BeforeEach(func() {
+		cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+		Expect(err).NotTo(HaveOccurred())
+		gmCrypto = &chaincode.GMCrypto{CSP: cryptoProvider}
+	})
+
+	It("hashes with SM3", func() {
+		digest, err := gmCrypto.Perform("tx-id", gmcrypto.SM3, [][]byte{[]byte("msg")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(sm3.SumSM3([]byte("msg"))))
+	})
+
+	Describe("SM4 encryption", func() {
+		key := []byte("0123456789abcdef")
+
+		It("encrypts and decrypts", func() {
+			ciphertext, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Encrypt, [][]byte{key, []byte("plaintext")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ciphertext).To(HaveLen(32))
+
+			plaintext, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Decrypt, [][]byte{key, ciphertext})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plaintext).To(Equal([]byte("plaintext")))
+		})
+
+		It("produces the same ciphertext for the same transaction", func() {
+			ciphertext1, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Encrypt, [][]byte{key, []byte("plaintext")})
+			Expect(err).NotTo(HaveOccurred())
+			ciphertext2, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Encrypt, [][]byte{key, []byte("plaintext")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ciphertext1).To(Equal(ciphertext2))
+
+			ciphertext3, err := gmCrypto.Perform("other-tx-id", gmcrypto.SM4Encrypt, [][]byte{key, []byte("plaintext")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ciphertext3).NotTo(Equal(ciphertext1))
+		})
+
+		It("keys the initialization vector with the SM4 key", func() {
+			ciphertext1, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Encrypt, [][]byte{key, []byte("plaintext")})
+			Expect(err).NotTo(HaveOccurred())
+			ciphertext2, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Encrypt, [][]byte{[]byte("fedcba9876543210"), []byte("plaintext")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ciphertext2[:16]).NotTo(Equal(ciphertext1[:16]))
+			Expect(ciphertext1[:16]).NotTo(Equal(sm3.SumSM3([]byte("tx-id\x00plaintext"))[:16]))
+		})
+
+		It("rejects invalid keys", func() {
+			_, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Encrypt, [][]byte{[]byte("short"), []byte("plaintext")})
+			Expect(err).To(MatchError(ContainSubstring("invalid SM4 key")))
+		})
+
+		It("rejects invalid ciphertexts", func() {
+			_, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Decrypt, [][]byte{key, []byte("short")})
+			Expect(err).To(MatchError("invalid SM4 ciphertext length"))
+
+			ciphertext, err := gmCrypto.Perform("tx-id", gmcrypto.SM4Encrypt, [][]byte{key, []byte("plaintext")})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = gmCrypto.Perform("tx-id", gmcrypto.SM4Decrypt, [][]byte{[]byte("fedcba9876543210"), ciphertext})
+			Expect(err).To(MatchError("invalid SM4 ciphertext padding, is the key correct?"))
+		})
+	})
+
+	Describe("SM2 verification", func() {
+		var (
+			privateKey *sm2.PrivateKey
+			signature  []byte
+			publicPEM  []byte
+			certPEM    []byte
+		)
+
+		BeforeEach(func() {
+			var err error
+			privateKey, err = sm2.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			signature, err = privateKey.Sign(rand.Reader, []byte("document"), nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+			publicPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+			template := &x509.Certificate{
+				SerialNumber:       big.NewInt(1),
+				NotBefore:          time.Now().Add(-time.Hour),
+				NotAfter:           time.Now().Add(time.Hour),
+				SignatureAlgorithm: x509.SM2WithSM3,
+			}
+			der, err = x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+		})
+
+		It("verifies signatures against a public key", func() {
+			_, err := gmCrypto.Perform("tx-id", gmcrypto.SM2Verify, [][]byte{publicPEM, signature, []byte("document")})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gmCrypto.Perform("tx-id", gmcrypto.SM2Verify, [][]byte{publicPEM, signature, []byte("tampered")})
+			Expect(err).To(MatchError("the signature is invalid"))
+		})
+
+		It("verifies signatures against a certificate", func() {
+			_, err := gmCrypto.Perform("tx-id", gmcrypto.SM2Verify, [][]byte{certPEM, signature, []byte("document")})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gmCrypto.Perform("tx-id", gmcrypto.SM2Verify, [][]byte{certPEM, signature, []byte("tampered")})
+			Expect(err).To(MatchError("the signature is invalid"))
+		})
+
+		It("rejects invalid PEM", func() {
+			_, err := gmCrypto.Perform("tx-id", gmcrypto.SM2Verify, [][]byte{[]byte("garbage"), signature, []byte("document")})
+			Expect(err).To(MatchError("no PEM encoded certificate or public key found"))
+
+			privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})
+			_, err = gmCrypto.Perform("tx-id", gmcrypto.SM2Verify, [][]byte{privatePEM, signature, []byte("document")})
+			Expect(err).To(MatchError("unexpected PEM block type 'PRIVATE KEY', expected a certificate or a public key"))
+		})
+	})
+})
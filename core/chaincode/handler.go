@@ -15,17 +15,21 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/pkg/ccmetrics"
+	"github.com/hyperledger/fabric/pkg/gmcrypto"
 	"github.com/pkg/errors"
 )
 
@@ -123,6 +127,11 @@ type Handler struct {
 	AppConfig ApplicationConfigRetriever
 	// Metrics holds chaincode handler metrics
 	Metrics *HandlerMetrics
+	// ExecutionTraces records the shim requests of the sampled transactions
+	// of the chaincodes tracing is enabled for
+	ExecutionTraces *txtrace.Recorder
+	// GMCrypto performs the crypto operations requested by the chaincode
+	GMCrypto *GMCrypto
 
 	// state holds the current handler state. It will be created, established, or
 	// ready.
@@ -200,6 +209,10 @@ func (h *Handler) handleMessageReadyState(msg *pb.ChaincodeMessage) error {
 		go h.HandleTransaction(msg, h.HandleGetStateMetadata)
 	case pb.ChaincodeMessage_PUT_STATE_METADATA:
 		go h.HandleTransaction(msg, h.HandlePutStateMetadata)
+	case pb.ChaincodeMessage_INCREMENT_STATE:
+		go h.HandleTransaction(msg, h.HandleIncrementState)
+	case pb.ChaincodeMessage_GM_CRYPTO:
+		go h.HandleTransaction(msg, h.HandleGMCrypto)
 	default:
 		return fmt.Errorf("[%s] Fabric side handler cannot handle message (%s) while in ready state", msg.Txid, msg.Type)
 	}
@@ -239,6 +252,13 @@ func (h *Handler) HandleTransaction(msg *pb.ChaincodeMessage, delegate handleFun
 	}
 	h.Metrics.ShimRequestsReceived.With(meterLabels...).Add(1)
 
+	if err == nil {
+		// the ledger is not accessed on behalf of abandoned transactions
+		if ctxErr := txContext.Err(); ctxErr != nil {
+			err = errors.Wrap(ctxErr, "transaction cancelled")
+		}
+	}
+
 	var resp *pb.ChaincodeMessage
 	if err == nil {
 		resp, err = delegate(msg, txContext)
@@ -251,6 +271,9 @@ func (h *Handler) HandleTransaction(msg *pb.ChaincodeMessage, delegate handleFun
 	}
 
 	chaincodeLogger.Debugf("[%s] Completed %s. Sending %s", shorttxid(msg.Txid), msg.Type, resp.Type)
+	if txContext != nil {
+		txContext.Trace.Record(msg, resp)
+	}
 	h.ActiveTransactions.Remove(msg.ChannelId, msg.Txid)
 	h.serialSendAsync(resp)
 
@@ -724,6 +747,14 @@ func (h *Handler) HandleGetStateByRange(msg *pb.ChaincodeMessage, txContext *Tra
 		}
 		rangeIter, err = txContext.TXSimulator.GetPrivateDataRangeScanIterator(namespaceID, collection,
 			getStateByRange.StartKey, getStateByRange.EndKey)
+	} else if isRangeScanCursorSet(metadata) {
+		isPaginated = true
+		cursorExecutor, ok := txContext.TXSimulator.(ledger.RangeScanCursorExecutor)
+		if !ok {
+			return nil, errors.New("range scan cursors are not supported by the ledger")
+		}
+		rangeIter, err = cursorExecutor.GetStateRangeScanIteratorWithCursor(namespaceID,
+			getStateByRange.StartKey, getStateByRange.EndKey, metadata.PageSize, metadata.Bookmark)
 	} else if isMetadataSetForPagination(metadata) {
 		isPaginated = true
 		startKey := getStateByRange.StartKey
@@ -922,6 +953,11 @@ func isMetadataSetForPagination(metadata *pb.QueryMetadata) bool {
 	return true
 }
 
+// isRangeScanCursorSet returns whether the bookmark is a resumable range scan cursor
+func isRangeScanCursorSet(metadata *pb.QueryMetadata) bool {
+	return metadata != nil && strings.HasPrefix(metadata.Bookmark, ledger.RangeScanCursorPrefix)
+}
+
 func getQueryMetadataFromBytes(metadataBytes []byte) (*pb.QueryMetadata, error) {
 	if metadataBytes != nil {
 		metadata := &pb.QueryMetadata{}
@@ -1042,6 +1078,46 @@ func (h *Handler) HandlePutStateMetadata(msg *pb.ChaincodeMessage, txContext *Tr
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// Handles requests to increment counter keys of the ledger state
+func (h *Handler) HandleIncrementState(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	incrementState := &pb.IncrementState{}
+	err := proto.Unmarshal(msg.Payload, incrementState)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	err = txContext.TXSimulator.IncrementState(txContext.NamespaceID, incrementState.Key, incrementState.Delta)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// HandleGMCrypto performs a GM crypto operation requested by the chaincode
+// with the crypto provider of the peer.
+func (h *Handler) HandleGMCrypto(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	if h.GMCrypto == nil {
+		return nil, errors.New("GM crypto operations are not available to chaincode")
+	}
+	gmCrypto := &pb.GMCrypto{}
+	err := proto.Unmarshal(msg.Payload, gmCrypto)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	op := gmcrypto.Operation(gmCrypto.Operation)
+	if err := gmcrypto.CheckArgs(op, gmCrypto.Args); err != nil {
+		return nil, err
+	}
+	result, err := h.GMCrypto.Perform(msg.Txid, op, gmCrypto.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: result, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 func (h *Handler) HandleDelState(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
 	delState := &pb.DelState{}
 	err := proto.Unmarshal(msg.Payload, delState)
@@ -1091,6 +1167,10 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 	}
 	chaincodeLogger.Debugf("[%s] C-call-C %s on channel %s", shorttxid(msg.Txid), targetInstance.ChaincodeName, targetInstance.ChannelID)
 
+	if targetInstance.ChaincodeName == ccmetrics.ChaincodeName {
+		return h.handleChaincodeMetric(msg, txContext, chaincodeSpec.Input)
+	}
+
 	err = h.checkACL(txContext.SignedProp, txContext.Proposal, targetInstance)
 	if err != nil {
 		chaincodeLogger.Errorf(
@@ -1113,6 +1193,7 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 		Proposal:             txContext.Proposal,
 		TXSimulator:          txContext.TXSimulator,
 		HistoryQueryExecutor: txContext.HistoryQueryExecutor,
+		Context:              txContext.Context,
 	}
 
 	if targetInstance.ChannelID != txContext.ChannelID {
@@ -1152,6 +1233,45 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// handleChaincodeMetric records a metric emitted by the chaincode through
+// the reserved metrics chaincode name instead of invoking a chaincode.
+func (h *Handler) handleChaincodeMetric(msg *pb.ChaincodeMessage, txContext *TransactionContext, input *pb.ChaincodeInput) (*pb.ChaincodeMessage, error) {
+	m, err := ccmetrics.ParseArgs(input.GetArgs())
+	if err != nil {
+		return nil, err
+	}
+
+	labels := []string{"channel", txContext.ChannelID, "chaincode", txContext.NamespaceID, "name", m.Name}
+	switch m.Kind {
+	case ccmetrics.Counter:
+		h.Metrics.CustomCounter.With(labels...).Add(m.Value)
+	case ccmetrics.Histogram:
+		h.Metrics.CustomHistogram.With(labels...).Observe(m.Value)
+	}
+
+	return interceptedResponse(msg, nil)
+}
+
+// interceptedResponse returns the response to an invocation intercepted by the
+// handler, which mimics the one of an invoked chaincode.
+func interceptedResponse(msg *pb.ChaincodeMessage, result []byte) (*pb.ChaincodeMessage, error) {
+	payload, err := proto.Marshal(&pb.Response{Status: int32(common.Status_SUCCESS), Payload: result})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+	res, err := proto.Marshal(&pb.ChaincodeMessage{
+		Type:      pb.ChaincodeMessage_COMPLETED,
+		Payload:   payload,
+		Txid:      msg.Txid,
+		ChannelId: msg.ChannelId,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 func (h *Handler) Execute(txParams *ccprovider.TransactionParams, namespace string, msg *pb.ChaincodeMessage, timeout time.Duration) (*pb.ChaincodeMessage, error) {
 	chaincodeLogger.Debugf("Entry")
 	defer chaincodeLogger.Debugf("Exit")
@@ -1170,8 +1290,14 @@ func (h *Handler) Execute(txParams *ccprovider.TransactionParams, namespace stri
 		return nil, err
 	}
 
+	txctx.Trace = h.ExecutionTraces.Start(namespace, msg)
 	h.serialSendAsync(msg)
 
+	var cancelled <-chan struct{}
+	if txParams.Context != nil {
+		cancelled = txParams.Context.Done()
+	}
+
 	var ccresp *pb.ChaincodeMessage
 	select {
 	case ccresp = <-txctx.ResponseNotifier:
@@ -1180,9 +1306,15 @@ func (h *Handler) Execute(txParams *ccprovider.TransactionParams, namespace stri
 	case <-time.After(timeout):
 		err = errors.New("timeout expired while executing transaction")
 		h.Metrics.ExecuteTimeouts.With("chaincode", h.chaincodeID).Add(1)
+	case <-cancelled:
+		// the chaincode may still be running; its subsequent requests are
+		// rejected once the transaction context is deleted
+		err = errors.Wrap(txParams.Context.Err(), "transaction cancelled")
+		txctx.CloseQueryIterators()
 	case <-h.streamDone():
 		err = errors.New("chaincode stream terminated")
 	}
+	txctx.Trace.Finish(ccresp, err)
 
 	return ccresp, err
 }
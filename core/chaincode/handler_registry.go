@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/hyperledger/fabric/core/ledger"
@@ -17,9 +18,10 @@ import (
 type HandlerRegistry struct {
 	allowUnsolicitedRegistration bool // from cs.userRunsCC
 
-	mutex     sync.Mutex              // lock covering handlers and launching
+	mutex     sync.Mutex              // lock covering handlers, launching and solicited
 	handlers  map[string]*Handler     // chaincode cname to associated handler
 	launching map[string]*LaunchState // launching chaincodes to LaunchState
+	solicited map[string]struct{}     // chaincodes allowed to register without launch
 }
 
 type LaunchState struct {
@@ -61,6 +63,7 @@ func NewHandlerRegistry(allowUnsolicitedRegistration bool) *HandlerRegistry {
 	return &HandlerRegistry{
 		handlers:                     map[string]*Handler{},
 		launching:                    map[string]*LaunchState{},
+		solicited:                    map[string]struct{}{},
 		allowUnsolicitedRegistration: allowUnsolicitedRegistration,
 	}
 }
@@ -122,6 +125,19 @@ func (r *HandlerRegistry) Handler(ccid string) *Handler {
 	return h
 }
 
+// Running returns the sorted IDs of the chaincodes whose runtime is connected
+// to the peer.
+func (r *HandlerRegistry) Running() []string {
+	r.mutex.Lock()
+	ccids := make([]string, 0, len(r.handlers))
+	for ccid := range r.handlers {
+		ccids = append(ccids, ccid)
+	}
+	r.mutex.Unlock()
+	sort.Strings(ccids)
+	return ccids
+}
+
 // Register adds a chaincode handler to the registry.
 // An error will be returned if a handler is already registered for the
 // chaincode. An error will also be returned if the chaincode has not already
@@ -136,8 +152,9 @@ func (r *HandlerRegistry) Register(h *Handler) error {
 	}
 
 	// This chaincode was not launched by the peer but is attempting
-	// to register. Only allowed in development mode.
-	if r.launching[h.chaincodeID] == nil && !r.allowUnsolicitedRegistration {
+	// to register. Only allowed in development mode, or when solicited.
+	_, solicited := r.solicited[h.chaincodeID]
+	if r.launching[h.chaincodeID] == nil && !solicited && !r.allowUnsolicitedRegistration {
 		return errors.Errorf("peer will not accept external chaincode connection %s (except in dev mode)", h.chaincodeID)
 	}
 
@@ -147,6 +164,29 @@ func (r *HandlerRegistry) Register(h *Handler) error {
 	return nil
 }
 
+// Solicit allows a chaincode which is not launched by the peer, such as a
+// chaincode a developer runs in a debugger, to register with the given ID
+// until it is unsolicited. The chaincode may register again after it
+// disconnects.
+func (r *HandlerRegistry) Solicit(ccid string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.solicited[ccid] = struct{}{}
+}
+
+// Unsolicit withdraws the permission to register granted by Solicit, and
+// deregisters the chaincode if it is registered.
+func (r *HandlerRegistry) Unsolicit(ccid string) {
+	r.mutex.Lock()
+	delete(r.solicited, ccid)
+	_, registered := r.handlers[ccid]
+	r.mutex.Unlock()
+
+	if registered {
+		r.Deregister(ccid)
+	}
+}
+
 // Deregister clears references to state associated specified chaincode.
 // As part of the cleanup, it closes the handler so it can cleanup any state.
 // If the registry does not contain the provided handler, an error is returned.
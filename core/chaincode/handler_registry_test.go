@@ -146,6 +146,23 @@ var _ = Describe("HandlerRegistry", func() {
 		})
 	})
 
+	Describe("Running", func() {
+		It("returns no chaincode when no handler is registered", func() {
+			Expect(hr.Running()).To(BeEmpty())
+		})
+
+		It("returns the chaincodes with a registered handler", func() {
+			handler.TXContexts = chaincode.NewTransactionContexts()
+			err := hr.Register(handler)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hr.Running()).To(Equal([]string{"chaincode-id"}))
+
+			err = hr.Deregister("chaincode-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hr.Running()).To(BeEmpty())
+		})
+	})
+
 	Describe("Register", func() {
 		Context("when unsolicited registration is disallowed", func() {
 			BeforeEach(func() {
@@ -172,6 +189,43 @@ var _ = Describe("HandlerRegistry", func() {
 			})
 		})
 
+		Context("when the registration is solicited", func() {
+			BeforeEach(func() {
+				hr = chaincode.NewHandlerRegistry(false)
+				hr.Solicit("chaincode-id")
+				handler.TXContexts = chaincode.NewTransactionContexts()
+			})
+
+			It("allows direct registration without launching", func() {
+				err := hr.Register(handler)
+				Expect(err).NotTo(HaveOccurred())
+
+				h := hr.Handler("chaincode-id")
+				Expect(h).To(BeIdenticalTo(handler))
+			})
+
+			It("allows registering again after deregistration", func() {
+				err := hr.Register(handler)
+				Expect(err).NotTo(HaveOccurred())
+				err = hr.Deregister("chaincode-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = hr.Register(handler)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("disallows registration once unsolicited", func() {
+				err := hr.Register(handler)
+				Expect(err).NotTo(HaveOccurred())
+
+				hr.Unsolicit("chaincode-id")
+				Expect(hr.Handler("chaincode-id")).To(BeNil())
+
+				err = hr.Register(handler)
+				Expect(err).To(MatchError(`peer will not accept external chaincode connection chaincode-id (except in dev mode)`))
+			})
+		})
+
 		Context("when unsolicited registrations are allowed", func() {
 			BeforeEach(func() {
 				hr = chaincode.NewHandlerRegistry(true)
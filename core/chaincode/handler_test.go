@@ -7,20 +7,28 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode_test
 
 import (
+	"context"
 	"io"
 	"time"
 
+	"github.com/cetcxinlian/cryptogm/sm3"
 	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/common/util"
 	ar "github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/fake"
 	"github.com/hyperledger/fabric/core/chaincode/mock"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/pkg/ccmetrics"
+	"github.com/hyperledger/fabric/pkg/gmcrypto"
+	"github.com/hyperledger/fabric/protoutil"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -46,6 +54,8 @@ var _ = Describe("Handler", func() {
 		fakeShimRequestsCompleted      *metricsfakes.Counter
 		fakeShimRequestDuration        *metricsfakes.Histogram
 		fakeExecuteTimeouts            *metricsfakes.Counter
+		fakeCustomCounter              *metricsfakes.Counter
+		fakeCustomHistogram            *metricsfakes.Histogram
 		fakeCapabilites                *mock.ApplicationCapabilities
 
 		responseNotifier chan *pb.ChaincodeMessage
@@ -100,6 +110,10 @@ var _ = Describe("Handler", func() {
 		fakeShimRequestDuration.WithReturns(fakeShimRequestDuration)
 		fakeExecuteTimeouts = &metricsfakes.Counter{}
 		fakeExecuteTimeouts.WithReturns(fakeExecuteTimeouts)
+		fakeCustomCounter = &metricsfakes.Counter{}
+		fakeCustomCounter.WithReturns(fakeCustomCounter)
+		fakeCustomHistogram = &metricsfakes.Histogram{}
+		fakeCustomHistogram.WithReturns(fakeCustomHistogram)
 
 		builtinSCCs = map[string]struct{}{}
 
@@ -108,6 +122,8 @@ var _ = Describe("Handler", func() {
 			ShimRequestsCompleted: fakeShimRequestsCompleted,
 			ShimRequestDuration:   fakeShimRequestDuration,
 			ExecuteTimeouts:       fakeExecuteTimeouts,
+			CustomCounter:         fakeCustomCounter,
+			CustomHistogram:       fakeCustomHistogram,
 		}
 
 		handler = &chaincode.Handler{
@@ -201,6 +217,31 @@ var _ = Describe("Handler", func() {
 			Expect(transactionID).To(Equal("tx-id"))
 		})
 
+		Context("when the transaction is traced", func() {
+			var recorder *txtrace.Recorder
+
+			BeforeEach(func() {
+				incomingMessage.Payload = protoutil.MarshalOrPanic(&pb.GetState{Key: "key", Collection: "collection"})
+				expectedResponse.Type = pb.ChaincodeMessage_RESPONSE
+
+				recorder = txtrace.NewRecorder(10)
+				Expect(recorder.Enable("chaincode-name", 1)).To(Succeed())
+				txContext.Trace = recorder.Start("chaincode-name", &pb.ChaincodeMessage{Txid: "tx-id", ChannelId: "channel-id"})
+			})
+
+			It("records the request in the trace", func() {
+				handler.HandleTransaction(incomingMessage, fakeMessageHandler.Handle)
+
+				traces := recorder.Traces("tx-id", "chaincode-name")
+				Expect(traces).To(HaveLen(1))
+				Expect(traces[0].Events).To(HaveLen(1))
+				Expect(traces[0].Events[0].Type).To(Equal("GET_STATE"))
+				Expect(traces[0].Events[0].Key).To(Equal("key"))
+				Expect(traces[0].Events[0].Collection).To(Equal("collection"))
+				Expect(traces[0].Events[0].ValueSize).To(Equal(len("handler-response-payload")))
+			})
+		})
+
 		It("records shim requests received before requests completed", func() {
 			fakeShimRequestsReceived.AddStub = func(delta float64) {
 				defer GinkgoRecover()
@@ -370,6 +411,28 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the transaction has been cancelled", func() {
+			BeforeEach(func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				txContext.Context = ctx
+			})
+
+			It("sends an error response without calling the delegate", func() {
+				handler.HandleTransaction(incomingMessage, fakeMessageHandler.Handle)
+
+				Eventually(fakeChatStream.SendCallCount).Should(Equal(1))
+				msg := fakeChatStream.SendArgsForCall(0)
+				Expect(msg).To(Equal(&pb.ChaincodeMessage{
+					Type:      pb.ChaincodeMessage_ERROR,
+					Payload:   []byte("GET_STATE failed: transaction ID: tx-id: transaction cancelled: context canceled"),
+					Txid:      "tx-id",
+					ChannelId: "channel-id",
+				}))
+				Expect(fakeMessageHandler.HandleCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when the incoming message is INVOKE_CHAINCODE", func() {
 			var chaincodeSpec *pb.ChaincodeSpec
 
@@ -777,6 +840,126 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	Describe("HandleIncrementState", func() {
+		var incomingMessage *pb.ChaincodeMessage
+
+		BeforeEach(func() {
+			payload, err := proto.Marshal(&pb.IncrementState{Key: "counter-key", Delta: -3})
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_INCREMENT_STATE,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+		})
+
+		It("calls IncrementState on the transaction simulator", func() {
+			resp, err := handler.HandleIncrementState(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+
+			Expect(fakeTxSimulator.IncrementStateCallCount()).To(Equal(1))
+			ccname, key, delta := fakeTxSimulator.IncrementStateArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(key).To(Equal("counter-key"))
+			Expect(delta).To(Equal(int64(-3)))
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleIncrementState(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when IncrementState returns an error", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.IncrementStateReturns(errors.New("peach"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleIncrementState(incomingMessage, txContext)
+				Expect(err).To(MatchError("peach"))
+			})
+		})
+	})
+
+	Describe("HandleGMCrypto", func() {
+		var incomingMessage *pb.ChaincodeMessage
+
+		BeforeEach(func() {
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			Expect(err).NotTo(HaveOccurred())
+			handler.GMCrypto = &chaincode.GMCrypto{CSP: cryptoProvider}
+
+			payload, err := proto.Marshal(&pb.GMCrypto{Operation: string(gmcrypto.SM3), Args: [][]byte{[]byte("msg")}})
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_GM_CRYPTO,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+		})
+
+		It("performs the operation", func() {
+			resp, err := handler.HandleGMCrypto(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Payload:   sm3.SumSM3([]byte("msg")),
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGMCrypto(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the operation is invalid", func() {
+			BeforeEach(func() {
+				payload, err := proto.Marshal(&pb.GMCrypto{Operation: string(gmcrypto.SM4Encrypt), Args: [][]byte{[]byte("key")}})
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGMCrypto(incomingMessage, txContext)
+				Expect(err).To(MatchError("expected 2 arguments for sm4encrypt but got 1"))
+			})
+		})
+
+		Context("when the peer does not provide GM crypto", func() {
+			BeforeEach(func() {
+				handler.GMCrypto = nil
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGMCrypto(incomingMessage, txContext)
+				Expect(err).To(MatchError("GM crypto operations are not available to chaincode"))
+			})
+		})
+	})
+
 	Describe("HandleDelState", func() {
 		var incomingMessage *pb.ChaincodeMessage
 		var request *pb.DelState
@@ -1530,6 +1713,46 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the bookmark is a range scan cursor", func() {
+			var fakeCursorExecutor *fakeRangeScanCursorExecutor
+
+			BeforeEach(func() {
+				metadata, err := proto.Marshal(&pb.QueryMetadata{PageSize: 10, Bookmark: ledger.RangeScanCursorPrefix})
+				Expect(err).NotTo(HaveOccurred())
+				request.Metadata = metadata
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+
+				fakeCursorExecutor = &fakeRangeScanCursorExecutor{TxSimulator: fakeTxSimulator, iterator: fakeIterator}
+				txContext.TXSimulator = fakeCursorExecutor
+			})
+
+			It("calls GetStateRangeScanIteratorWithCursor on the transaction simulator", func() {
+				resp, err := handler.HandleGetStateByRange(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(Equal(expectedResponse))
+
+				Expect(fakeCursorExecutor.args).To(Equal([]interface{}{
+					"cc-instance-name", "get-state-start-key", "get-state-end-key", int32(10), ledger.RangeScanCursorPrefix,
+				}))
+				Expect(fakeTxSimulator.GetStateRangeScanIteratorWithPaginationCallCount()).To(Equal(0))
+				_, _, _, isPaginated, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+				Expect(isPaginated).To(BeTrue())
+			})
+
+			Context("and the transaction simulator does not support cursors", func() {
+				BeforeEach(func() {
+					txContext.TXSimulator = fakeTxSimulator
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetStateByRange(incomingMessage, txContext)
+					Expect(err).To(MatchError("range scan cursors are not supported by the ledger"))
+				})
+			})
+		})
+
 		Context("when unmarshalling the request fails", func() {
 			BeforeEach(func() {
 				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
@@ -2293,6 +2516,83 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the target is the metrics chaincode", func() {
+			BeforeEach(func() {
+				request = &pb.ChaincodeSpec{
+					ChaincodeId: &pb.ChaincodeID{Name: ccmetrics.ChaincodeName},
+					Input: &pb.ChaincodeInput{
+						Args: ccmetrics.Metric{Kind: ccmetrics.Counter, Name: "orders", Value: 3}.Args(),
+					},
+				}
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("records the counter without invoking a chaincode", func() {
+				resp, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeACLProvider.CheckACLCallCount()).To(Equal(0))
+				Expect(fakeInvoker.InvokeCallCount()).To(Equal(0))
+				Expect(fakeCustomCounter.WithCallCount()).To(Equal(1))
+				Expect(fakeCustomCounter.WithArgsForCall(0)).To(Equal([]string{
+					"channel", "channel-id",
+					"chaincode", "cc-instance-name",
+					"name", "orders",
+				}))
+				Expect(fakeCustomCounter.AddCallCount()).To(Equal(1))
+				Expect(fakeCustomCounter.AddArgsForCall(0)).To(Equal(3.0))
+
+				Expect(resp.Type).To(Equal(pb.ChaincodeMessage_RESPONSE))
+				completed := &pb.ChaincodeMessage{}
+				Expect(proto.Unmarshal(resp.Payload, completed)).To(Succeed())
+				Expect(completed.Type).To(Equal(pb.ChaincodeMessage_COMPLETED))
+				response := &pb.Response{}
+				Expect(proto.Unmarshal(completed.Payload, response)).To(Succeed())
+				Expect(response.Status).To(Equal(int32(200)))
+			})
+
+			Context("when the metric is a histogram", func() {
+				BeforeEach(func() {
+					request.Input.Args = ccmetrics.Metric{Kind: ccmetrics.Histogram, Name: "amount", Value: 1.5}.Args()
+					payload, err := proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+					incomingMessage.Payload = payload
+				})
+
+				It("records the histogram", func() {
+					_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeCustomCounter.AddCallCount()).To(Equal(0))
+					Expect(fakeCustomHistogram.WithCallCount()).To(Equal(1))
+					Expect(fakeCustomHistogram.WithArgsForCall(0)).To(Equal([]string{
+						"channel", "channel-id",
+						"chaincode", "cc-instance-name",
+						"name", "amount",
+					}))
+					Expect(fakeCustomHistogram.ObserveCallCount()).To(Equal(1))
+					Expect(fakeCustomHistogram.ObserveArgsForCall(0)).To(Equal(1.5))
+				})
+			})
+
+			Context("when the metric is invalid", func() {
+				BeforeEach(func() {
+					request.Input.Args = [][]byte{[]byte("counter"), []byte("orders"), []byte("-1")}
+					payload, err := proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+					incomingMessage.Payload = payload
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+					Expect(err).To(MatchError("counter orders can't be decreased"))
+					Expect(fakeCustomCounter.AddCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when the target is a system chaincode", func() {
 			BeforeEach(func() {
 				builtinSCCs["target-chaincode-name"] = struct{}{}
@@ -2459,6 +2759,43 @@ var _ = Describe("Handler", func() {
 			Expect(txid).To(Equal("tx-id"))
 		})
 
+		Context("when execution tracing is enabled for the chaincode", func() {
+			var recorder *txtrace.Recorder
+
+			BeforeEach(func() {
+				recorder = txtrace.NewRecorder(10)
+				Expect(recorder.Enable("chaincode-name", 1)).To(Succeed())
+				handler.ExecutionTraces = recorder
+			})
+
+			It("traces the transaction", func() {
+				responseNotifier <- &pb.ChaincodeMessage{
+					Type:    pb.ChaincodeMessage_COMPLETED,
+					Payload: protoutil.MarshalOrPanic(&pb.Response{Status: 500, Message: "bad-request"}),
+				}
+				_, err := handler.Execute(txParams, "chaincode-name", incomingMessage, time.Second)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(txContext.Trace).NotTo(BeNil())
+				traces := recorder.Traces("tx-id", "")
+				Expect(traces).To(HaveLen(1))
+				Expect(traces[0].ChannelID).To(Equal("channel-id"))
+				Expect(traces[0].Chaincode).To(Equal("chaincode-name"))
+				Expect(traces[0].ArgHashes).To(HaveLen(2))
+				Expect(traces[0].Completed).To(BeTrue())
+				Expect(traces[0].Status).To(Equal(int32(500)))
+				Expect(traces[0].Message).To(Equal("bad-request"))
+			})
+
+			It("does not trace the transactions of other chaincodes", func() {
+				close(responseNotifier)
+				handler.Execute(txParams, "other-chaincode", incomingMessage, time.Second)
+
+				Expect(txContext.Trace).To(BeNil())
+				Expect(recorder.Traces("tx-id", "")).To(BeEmpty())
+			})
+		})
+
 		Context("when the serial send fails", func() {
 			BeforeEach(func() {
 				fakeChatStream.SendReturns(errors.New("where-is-waldo?"))
@@ -2591,6 +2928,40 @@ var _ = Describe("Handler", func() {
 				Expect(txid).To(Equal("tx-id"))
 			})
 		})
+
+		Context("when the deadline of the transaction passes", func() {
+			var (
+				fakeIterator *mock.QueryResultsIterator
+				cancel       context.CancelFunc
+			)
+
+			BeforeEach(func() {
+				fakeIterator = &mock.QueryResultsIterator{}
+				txContext.InitializeQueryContext("query-id", fakeIterator)
+				txParams.Context, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+			})
+
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("returns an error before timing out", func() {
+				_, err := handler.Execute(txParams, "chaincode-name", incomingMessage, time.Hour)
+				Expect(err).To(MatchError("transaction cancelled: context deadline exceeded"))
+				Expect(errors.Cause(err)).To(Equal(context.DeadlineExceeded))
+				Expect(fakeExecuteTimeouts.WithCallCount()).To(Equal(0))
+			})
+
+			It("closes the query iterators and deletes the transaction context", func() {
+				handler.Execute(txParams, "chaincode-name", incomingMessage, time.Hour)
+
+				Expect(fakeIterator.CloseCallCount()).To(Equal(1))
+				Expect(fakeContextRegistry.DeleteCallCount()).Should(Equal(1))
+				channelID, txid := fakeContextRegistry.DeleteArgsForCall(0)
+				Expect(channelID).To(Equal("channel-id"))
+				Expect(txid).To(Equal("tx-id"))
+			})
+		})
 	})
 
 	Describe("HandleRegister", func() {
@@ -2969,3 +3340,15 @@ var _ = Describe("Handler", func() {
 		Entry("unknown", chaincode.State(999), "UNKNOWN"),
 	)
 })
+
+// fakeRangeScanCursorExecutor adds the support of the range scan cursors to a fake transaction simulator
+type fakeRangeScanCursorExecutor struct {
+	*mock.TxSimulator
+	iterator ledger.QueryResultsIterator
+	args     []interface{}
+}
+
+func (f *fakeRangeScanCursorExecutor) GetStateRangeScanIteratorWithCursor(namespace, startKey, endKey string, pageSize int32, cursor string) (ledger.QueryResultsIterator, error) {
+	f.args = []interface{}{namespace, startKey, endKey, pageSize, cursor}
+	return f.iterator, nil
+}
@@ -402,6 +402,24 @@ func (c *Cache) ListInstalledChaincodes() []*chaincode.InstalledChaincode {
 	return installedChaincodes
 }
 
+// ListCommittedChaincodes returns the chaincode definitions committed on
+// each channel the peer has joined, keyed by channel and chaincode name.
+func (c *Cache) ListCommittedChaincodes() map[string]map[string]*ChaincodeDefinition {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	committedChaincodes := map[string]map[string]*ChaincodeDefinition{}
+	for channelID, channelCache := range c.definedChaincodes {
+		definitions := map[string]*ChaincodeDefinition{}
+		for name, cachedChaincode := range channelCache.Chaincodes {
+			definitions[name] = cachedChaincode.Definition
+		}
+		committedChaincodes[channelID] = definitions
+	}
+
+	return committedChaincodes
+}
+
 // GetInstalledChaincode returns all of the information about a specific
 // installed chaincode.
 func (c *Cache) GetInstalledChaincode(packageID string) (*chaincode.InstalledChaincode, error) {
@@ -304,6 +304,24 @@ var _ = Describe("Cache", func() {
 		})
 	})
 
+	Describe("ListCommittedChaincodes", func() {
+		BeforeEach(func() {
+			lifecycle.SetChaincodeMap(c, "empty-channel-id", &lifecycle.ChannelCache{
+				Chaincodes: map[string]*lifecycle.CachedChaincodeDefinition{},
+			})
+		})
+
+		It("returns the committed chaincode definitions of every channel", func() {
+			committedChaincodes := c.ListCommittedChaincodes()
+			Expect(committedChaincodes).To(Equal(map[string]map[string]*lifecycle.ChaincodeDefinition{
+				"channel-id": {
+					"chaincode-name": channelCache.Chaincodes["chaincode-name"].Definition,
+				},
+				"empty-channel-id": {},
+			}))
+		})
+	})
+
 	Describe("GetInstalledChaincode", func() {
 		It("returns the requested installed chaincode", func() {
 			installedChaincode, err := c.GetInstalledChaincode("packageID")
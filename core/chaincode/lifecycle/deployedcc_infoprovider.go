@@ -100,6 +100,7 @@ func (vc *ValidatorCommitter) ChaincodeInfo(channelName, chaincodeName string, q
 		Hash:                        util.ComputeSHA256([]byte(chaincodeName + ":" + definedChaincode.EndorsementInfo.Version)),
 		ExplicitCollectionConfigPkg: definedChaincode.Collections,
 		IsLegacy:                    false,
+		Metadata:                    definedChaincode.EndorsementInfo.Metadata,
 	}, nil
 }
 
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package lifecycle
 
 import (
+	"github.com/hyperledger/fabric/core/chaincode/argschema"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/pkg/errors"
@@ -38,6 +39,10 @@ type ChaincodeEndorsementInfo struct {
 
 	// EndorsementPlugin is the name of the plugin to use when endorsing.
 	EndorsementPlugin string
+
+	// ArgumentSchema is the schema the arguments of the invocations must comply with,
+	// or nil when the definition does not carry one.
+	ArgumentSchema *argschema.Schema
 }
 
 type ChaincodeEndorsementInfoSource struct {
@@ -46,6 +51,8 @@ type ChaincodeEndorsementInfoSource struct {
 	LegacyImpl  Lifecycle
 	BuiltinSCCs scc.BuiltinSCCs
 	UserRunsCC  bool
+	// ArgumentSchemas memoizes the argument schemas of the chaincode definitions.
+	ArgumentSchemas *argschema.Cache
 }
 
 func (cei *ChaincodeEndorsementInfoSource) CachedChaincodeInfo(channelID, chaincodeName string, qe ledger.SimpleQueryExecutor) (*LocalChaincodeInfo, bool, error) {
@@ -140,10 +147,19 @@ func (cei *ChaincodeEndorsementInfoSource) ChaincodeEndorsementInfo(channelID, c
 		chaincodeInfo.InstallInfo = &ChaincodeInstallInfo{}
 	}
 
+	var argumentSchema *argschema.Schema
+	if raw, ok := chaincodeInfo.Definition.EndorsementInfo.Metadata[argschema.MetadataKey]; ok {
+		argumentSchema, err = cei.ArgumentSchemas.Parse(raw)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid argument schema in the definition of chaincode '%s' on channel '%s'", chaincodeName, channelID)
+		}
+	}
+
 	return &ChaincodeEndorsementInfo{
 		Version:           chaincodeInfo.Definition.EndorsementInfo.Version,
 		EnforceInit:       chaincodeInfo.Definition.EndorsementInfo.InitRequired,
 		EndorsementPlugin: chaincodeInfo.Definition.EndorsementInfo.EndorsementPlugin,
 		ChaincodeID:       chaincodeInfo.InstallInfo.PackageID, // Local packages use package ID for ccid
+		ArgumentSchema:    argumentSchema,
 	}, nil
 }
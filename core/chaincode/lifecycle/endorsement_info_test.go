@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/argschema"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
 	"github.com/hyperledger/fabric/core/scc"
@@ -237,6 +238,38 @@ var _ = Describe("ChaincodeEndorsementInfoSource", func() {
 			}))
 		})
 
+		Context("when the definition carries an argument schema", func() {
+			BeforeEach(func() {
+				testInfo.Definition.EndorsementInfo.Metadata = map[string][]byte{
+					"argschema": []byte(`{"functions": {"Get": {"args": [{"name": "key", "type": "string"}]}}}`),
+				}
+				cei.ArgumentSchemas = argschema.NewCache()
+			})
+
+			It("returns the parsed schema", func() {
+				def, err := cei.ChaincodeEndorsementInfo("channel-id", "name", fakeQueryExecutor)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(def.ArgumentSchema).NotTo(BeNil())
+				Expect(def.ArgumentSchema.Functions).To(HaveKey("Get"))
+				Expect(def.ArgumentSchema.Validate([][]byte{[]byte("Get"), []byte("key1")})).To(Succeed())
+
+				again, err := cei.ChaincodeEndorsementInfo("channel-id", "name", fakeQueryExecutor)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(again.ArgumentSchema).To(BeIdenticalTo(def.ArgumentSchema))
+			})
+
+			Context("when the argument schema cannot be parsed", func() {
+				BeforeEach(func() {
+					testInfo.Definition.EndorsementInfo.Metadata["argschema"] = []byte("{")
+				})
+
+				It("wraps and returns the error", func() {
+					_, err := cei.ChaincodeEndorsementInfo("channel-id", "name", fakeQueryExecutor)
+					Expect(err).To(MatchError("invalid argument schema in the definition of chaincode 'name' on channel 'channel-id': could not unmarshal argument schema: unexpected end of JSON input"))
+				})
+			})
+		})
+
 		Context("when the chaincode is a builtin system chaincode", func() {
 			BeforeEach(func() {
 				builtinSCCs["test-syscc-name"] = struct{}{}
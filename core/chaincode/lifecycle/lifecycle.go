@@ -9,6 +9,7 @@ package lifecycle
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"sync"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -19,7 +20,9 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/chaincode/sbom"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protoutil"
 
 	"github.com/golang/protobuf/proto"
@@ -134,11 +137,25 @@ func (cp *ChaincodeParameters) Equal(ocp *ChaincodeParameters) error {
 		return errors.Errorf("expected ValidationParameter '%x' does not match passed ValidationParameter '%x'", cp.ValidationInfo.ValidationParameter, ocp.ValidationInfo.ValidationParameter)
 	case !proto.Equal(cp.Collections, ocp.Collections):
 		return errors.Errorf("Collections do not match")
+	case !metadataEqual(cp.EndorsementInfo.Metadata, ocp.EndorsementInfo.Metadata):
+		return errors.Errorf("Metadata does not match")
 	default:
 	}
 	return nil
 }
 
+func metadataEqual(metadata, other map[string][]byte) bool {
+	if len(metadata) != len(other) {
+		return false
+	}
+	for key, value := range metadata {
+		if otherValue, ok := other[key]; !ok || !bytes.Equal(value, otherValue) {
+			return false
+		}
+	}
+	return true
+}
+
 // ChaincodeDefinition contains the chaincode parameters, as well as the sequence number of the definition.
 // Note, it does not embed ChaincodeParameters so as not to complicate the serialization.  It is expected
 // that any instance will have no nil fields once initialized.
@@ -212,6 +229,16 @@ type PackageParser interface {
 	Parse(data []byte) (*persistence.ChaincodePackage, error)
 }
 
+//go:generate counterfeiter -o mock/sbom_store.go --fake-name SBOMStore . SBOMStore
+
+// SBOMStore persists the software bill of materials of the installed
+// chaincode packages.
+type SBOMStore interface {
+	SaveSBOM(packageID string, sbom []byte) error
+	// LoadSBOM returns nil if no SBOM was saved for the package.
+	LoadSBOM(packageID string) ([]byte, error)
+}
+
 //go:generate counterfeiter -o mock/install_listener.go --fake-name InstallListener . InstallListener
 type InstallListener interface {
 	HandleChaincodeInstalled(md *persistence.ChaincodePackageMetadata, packageID string)
@@ -223,6 +250,20 @@ type InstalledChaincodesLister interface {
 	GetInstalledChaincode(packageID string) (*chaincode.InstalledChaincode, error)
 }
 
+//go:generate counterfeiter -o mock/committed_chaincodes_lister.go --fake-name CommittedChaincodesLister . CommittedChaincodesLister
+type CommittedChaincodesLister interface {
+	// ListCommittedChaincodes returns the chaincode definitions committed on each
+	// channel the peer has joined, keyed by channel and chaincode name.
+	ListCommittedChaincodes() map[string]map[string]*ChaincodeDefinition
+}
+
+//go:generate counterfeiter -o mock/pvt_data_stats_provider.go --fake-name PvtDataStatsProvider . PvtDataStatsProvider
+type PvtDataStatsProvider interface {
+	// CollectionPvtDataStats returns the amount of private data of a collection
+	// held by the peer on the given channel.
+	CollectionPvtDataStats(channelID, namespace, collection string) (*ledger.CollectionPvtDataStats, error)
+}
+
 // Resources stores the common functions needed by all components of the lifecycle
 // by the SCC as well as internally.  It also has some utility methods attached to it
 // for querying the lifecycle definitions.
@@ -306,7 +347,10 @@ func (r *Resources) LifecycleEndorsementPolicyAsBytes(channelID string) ([]byte,
 type ExternalFunctions struct {
 	Resources                 *Resources
 	InstallListener           InstallListener
+	SBOMStore                 SBOMStore
 	InstalledChaincodesLister InstalledChaincodesLister
+	CommittedChaincodesLister CommittedChaincodesLister
+	PvtDataStatsProvider      PvtDataStatsProvider
 	ChaincodeBuilder          ChaincodeBuilder
 	BuildRegistry             *container.BuildRegistry
 	mutex                     sync.Mutex
@@ -699,6 +743,10 @@ func (ef *ExternalFunctions) InstallChaincode(chaincodeInstallPackage []byte) (*
 		return nil, errors.WithMessage(err, "could not build chaincode")
 	}
 
+	if _, err := ef.generateSBOM(packageID, pkg); err != nil {
+		logger.Warningf("Could not generate the SBOM of chaincode with package ID '%s': %s", packageID, err)
+	}
+
 	if ef.InstallListener != nil {
 		ef.InstallListener.HandleChaincodeInstalled(pkg.Metadata, packageID)
 	}
@@ -738,6 +786,52 @@ func (ef *ExternalFunctions) GetInstalledChaincodePackage(packageID string) ([]b
 	return pkgBytes, nil
 }
 
+// QueryInstalledChaincodeSBOM returns the software bill of materials of the
+// installed chaincode with the given package ID. The SBOM of the packages
+// installed before SBOMs were generated is generated on the first query.
+func (ef *ExternalFunctions) QueryInstalledChaincodeSBOM(packageID string) (*sbom.SBOM, error) {
+	if ef.SBOMStore != nil {
+		sbomBytes, err := ef.SBOMStore.LoadSBOM(packageID)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not load SBOM")
+		}
+		if sbomBytes != nil {
+			return sbom.Unmarshal(sbomBytes)
+		}
+	}
+
+	pkgBytes, err := ef.Resources.ChaincodeStore.Load(packageID)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := ef.Resources.PackageParser.Parse(pkgBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not parse as a chaincode install package")
+	}
+	return ef.generateSBOM(packageID, pkg)
+}
+
+// generateSBOM generates the software bill of materials of a chaincode
+// package and saves it to the SBOM store.
+func (ef *ExternalFunctions) generateSBOM(packageID string, pkg *persistence.ChaincodePackage) (*sbom.SBOM, error) {
+	s, err := sbom.Generate(pkg.Metadata.Type, pkg.CodePackage)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not generate SBOM")
+	}
+	if ef.SBOMStore == nil {
+		return s, nil
+	}
+
+	sbomBytes, err := s.Marshal()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal SBOM")
+	}
+	if err := ef.SBOMStore.SaveSBOM(packageID, sbomBytes); err != nil {
+		return nil, errors.WithMessage(err, "could not save SBOM")
+	}
+	return s, nil
+}
+
 // QueryNamespaceDefinitions lists the publicly defined namespaces in a channel.  Today it should only ever
 // find Datatype encodings of 'ChaincodeDefinition'.
 func (ef *ExternalFunctions) QueryNamespaceDefinitions(publicState RangeableState) (map[string]string, error) {
@@ -768,3 +862,113 @@ func (ef *ExternalFunctions) QueryInstalledChaincode(packageID string) (*chainco
 func (ef *ExternalFunctions) QueryInstalledChaincodes() []*chaincode.InstalledChaincode {
 	return ef.InstalledChaincodesLister.ListInstalledChaincodes()
 }
+
+// QueryAllChaincodeDefinitions returns the chaincode definitions committed
+// on each channel the peer has joined
+func (ef *ExternalFunctions) QueryAllChaincodeDefinitions() map[string]map[string]*ChaincodeDefinition {
+	return ef.CommittedChaincodesLister.ListCommittedChaincodes()
+}
+
+// CollectionConfigUpdateImpact describes the effect of a collection
+// configuration update on a collection of a committed chaincode definition.
+type CollectionConfigUpdateImpact struct {
+	Name         string
+	RemovedOrgs  []string
+	PvtDataStats *ledger.CollectionPvtDataStats
+}
+
+// AnalyzeCollectionConfigUpdate compares the collections of the chaincode
+// definition committed on the channel with the supplied collection
+// configuration, and reports for each existing collection the member orgs
+// removed by the update along with the private data of the collection held
+// by the peer, which becomes orphaned if the org of the peer is removed.
+func (ef *ExternalFunctions) AnalyzeCollectionConfigUpdate(chname, ccname string, collections *pb.CollectionConfigPackage, publicState ReadableState) ([]*CollectionConfigUpdateImpact, error) {
+	definedChaincode, err := ef.QueryChaincodeDefinition(ccname, publicState)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedMembers := map[string]map[string]struct{}{}
+	for _, config := range collections.GetConfig() {
+		coll := config.GetStaticCollectionConfig()
+		if coll == nil {
+			continue
+		}
+		members, err := collectionMemberOrgs(coll)
+		if err != nil {
+			return nil, err
+		}
+		updatedMembers[coll.Name] = members
+	}
+
+	var impacts []*CollectionConfigUpdateImpact
+	for _, config := range definedChaincode.Collections.GetConfig() {
+		coll := config.GetStaticCollectionConfig()
+		if coll == nil {
+			continue
+		}
+		newMembers, ok := updatedMembers[coll.Name]
+		if !ok {
+			return nil, errors.Errorf("existing collection '%s' of chaincode '%s' is missing from the collection configuration", coll.Name, ccname)
+		}
+		members, err := collectionMemberOrgs(coll)
+		if err != nil {
+			return nil, err
+		}
+		removedOrgs := []string{}
+		for org := range members {
+			if _, ok := newMembers[org]; !ok {
+				removedOrgs = append(removedOrgs, org)
+			}
+		}
+		sort.Strings(removedOrgs)
+
+		stats, err := ef.PvtDataStatsProvider.CollectionPvtDataStats(chname, ccname, coll.Name)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not get private data statistics of collection '%s'", coll.Name)
+		}
+
+		impacts = append(impacts, &CollectionConfigUpdateImpact{
+			Name:         coll.Name,
+			RemovedOrgs:  removedOrgs,
+			PvtDataStats: stats,
+		})
+	}
+
+	logger.Infof("Successfully analyzed the collection configuration update of chaincode name '%s' on channel '%s'", ccname, chname)
+
+	return impacts, nil
+}
+
+// collectionMemberOrgs returns the MSP IDs of the principals of the member
+// orgs policy of a collection
+func collectionMemberOrgs(coll *pb.StaticCollectionConfig) (map[string]struct{}, error) {
+	members := map[string]struct{}{}
+	for _, principal := range coll.GetMemberOrgsPolicy().GetSignaturePolicy().GetIdentities() {
+		var mspID string
+		switch principal.PrincipalClassification {
+		case msp.MSPPrincipal_ROLE:
+			role := &msp.MSPRole{}
+			if err := proto.Unmarshal(principal.Principal, role); err != nil {
+				return nil, errors.Wrapf(err, "collection-name: %s -- cannot unmarshal identity bytes into MSPRole", coll.Name)
+			}
+			mspID = role.MspIdentifier
+		case msp.MSPPrincipal_ORGANIZATION_UNIT:
+			ou := &msp.OrganizationUnit{}
+			if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+				return nil, errors.Wrapf(err, "collection-name: %s -- cannot unmarshal identity bytes into OrganizationUnit", coll.Name)
+			}
+			mspID = ou.MspIdentifier
+		case msp.MSPPrincipal_IDENTITY:
+			id := &msp.SerializedIdentity{}
+			if err := proto.Unmarshal(principal.Principal, id); err != nil {
+				return nil, errors.Wrapf(err, "collection-name: %s -- cannot unmarshal identity bytes into SerializedIdentity", coll.Name)
+			}
+			mspID = id.Mspid
+		default:
+			return nil, errors.Errorf("collection-name: %s -- principal type %v is not supported", coll.Name, principal.PrincipalClassification)
+		}
+		members[mspID] = struct{}{}
+	}
+	return members, nil
+}
@@ -90,6 +90,16 @@ type channelConfig interface {
 	channelconfig.Resources
 }
 
+//go:generate counterfeiter -o mock/channel.go --fake-name Channel . channel
+type channel interface {
+	channelconfig.Channel
+}
+
+//go:generate counterfeiter -o mock/channel_capabilities.go --fake-name ChannelCapabilities . channelCapabilities
+type channelCapabilities interface {
+	channelconfig.ChannelCapabilities
+}
+
 //go:generate counterfeiter -o mock/application_config.go --fake-name ApplicationConfig . applicationConfig
 type applicationConfig interface {
 	channelconfig.Application
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package lifecycle_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
@@ -15,10 +18,13 @@ import (
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/chaincode/sbom"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 
@@ -107,6 +113,17 @@ var _ = Describe("ChaincodeParameters", func() {
 				Expect(lhs.Equal(rhs)).To(MatchError("Collections do not match"))
 			})
 		})
+
+		Context("when the Metadata differs from the current definition", func() {
+			BeforeEach(func() {
+				lhs.EndorsementInfo.Metadata = map[string][]byte{"source": []byte("repo")}
+				rhs.EndorsementInfo.Metadata = map[string][]byte{"source": []byte("different")}
+			})
+
+			It("returns an error", func() {
+				Expect(lhs.Equal(rhs)).To(MatchError("Metadata does not match"))
+			})
+		})
 	})
 })
 
@@ -266,6 +283,27 @@ var _ = Describe("Resources", func() {
 	})
 })
 
+// goCodePackage returns the code package of a Go chaincode vendoring its
+// single dependency.
+func goCodePackage() []byte {
+	files := map[string]string{
+		"src/go.mod":             "module cc\n\nrequire github.com/hyperledger/fabric-chaincode-go v0.0.0-20200424173110-d7076418f212\n",
+		"src/vendor/modules.txt": "# github.com/hyperledger/fabric-chaincode-go v0.0.0-20200424173110-d7076418f212\n## explicit\n",
+	}
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = tw.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gw.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
 var _ = Describe("ExternalFunctions", func() {
 	var (
 		resources               *lifecycle.Resources
@@ -274,7 +312,10 @@ var _ = Describe("ExternalFunctions", func() {
 		fakeChaincodeBuilder    *mock.ChaincodeBuilder
 		fakeParser              *mock.PackageParser
 		fakeListener            *mock.InstallListener
+		fakeSBOMStore           *mock.SBOMStore
 		fakeLister              *mock.InstalledChaincodesLister
+		fakeCommittedLister     *mock.CommittedChaincodesLister
+		fakeStatsProvider       *mock.PvtDataStatsProvider
 		fakeChannelConfigSource *mock.ChannelConfigSource
 		fakeChannelConfig       *mock.ChannelConfig
 		fakeApplicationConfig   *mock.ApplicationConfig
@@ -287,7 +328,10 @@ var _ = Describe("ExternalFunctions", func() {
 		fakeChaincodeBuilder = &mock.ChaincodeBuilder{}
 		fakeParser = &mock.PackageParser{}
 		fakeListener = &mock.InstallListener{}
+		fakeSBOMStore = &mock.SBOMStore{}
 		fakeLister = &mock.InstalledChaincodesLister{}
+		fakeCommittedLister = &mock.CommittedChaincodesLister{}
+		fakeStatsProvider = &mock.PvtDataStatsProvider{}
 		fakeChannelConfigSource = &mock.ChannelConfigSource{}
 		fakeChannelConfig = &mock.ChannelConfig{}
 		fakeChannelConfigSource.GetStableChannelConfigReturns(fakeChannelConfig)
@@ -315,7 +359,10 @@ var _ = Describe("ExternalFunctions", func() {
 		ef = &lifecycle.ExternalFunctions{
 			Resources:                 resources,
 			InstallListener:           fakeListener,
+			SBOMStore:                 fakeSBOMStore,
 			InstalledChaincodesLister: fakeLister,
+			CommittedChaincodesLister: fakeCommittedLister,
+			PvtDataStatsProvider:      fakeStatsProvider,
 			ChaincodeBuilder:          fakeChaincodeBuilder,
 			BuildRegistry:             &container.BuildRegistry{},
 		}
@@ -329,6 +376,7 @@ var _ = Describe("ExternalFunctions", func() {
 					Path:  "cc-path",
 					Label: "cc-label",
 				},
+				CodePackage: goCodePackage(),
 			}, nil)
 			fakeCCStore.SaveReturns("fake-hash", nil)
 		})
@@ -368,6 +416,31 @@ var _ = Describe("ExternalFunctions", func() {
 			Expect(ccid).To(Equal("fake-hash"))
 		})
 
+		It("saves the SBOM of the chaincode", func() {
+			_, err := ef.InstallChaincode([]byte("cc-package"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSBOMStore.SaveSBOMCallCount()).To(Equal(1))
+			packageID, sbomBytes := fakeSBOMStore.SaveSBOMArgsForCall(0)
+			Expect(packageID).To(Equal("fake-hash"))
+			s, err := sbom.Unmarshal(sbomBytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Type).To(Equal("cc-type"))
+		})
+
+		When("saving the SBOM fails", func() {
+			BeforeEach(func() {
+				fakeSBOMStore.SaveSBOMReturns(fmt.Errorf("fake-sbom-error"))
+			})
+
+			It("installs the chaincode anyway", func() {
+				cc, err := ef.InstallChaincode([]byte("cc-package"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cc.PackageID).To(Equal("fake-hash"))
+				Expect(fakeListener.HandleChaincodeInstalledCallCount()).To(Equal(1))
+			})
+		})
+
 		When("building the chaincode fails", func() {
 			BeforeEach(func() {
 				fakeChaincodeBuilder.BuildReturns(fmt.Errorf("fake-build-error"))
@@ -478,6 +551,99 @@ var _ = Describe("ExternalFunctions", func() {
 		})
 	})
 
+	Describe("QueryInstalledChaincodeSBOM", func() {
+		BeforeEach(func() {
+			fakeCCStore.LoadReturns([]byte("cc-package"), nil)
+			fakeParser.ParseReturns(&persistence.ChaincodePackage{
+				Metadata:    &persistence.ChaincodePackageMetadata{Type: "golang"},
+				CodePackage: goCodePackage(),
+			}, nil)
+		})
+
+		It("generates and saves the SBOM of the chaincode", func() {
+			s, err := ef.QueryInstalledChaincodeSBOM("package-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(Equal(&sbom.SBOM{
+				Type: "golang",
+				Components: []*sbom.Component{
+					{Name: "github.com/hyperledger/fabric-chaincode-go", Version: "v0.0.0-20200424173110-d7076418f212", Direct: true},
+				},
+				Verified: true,
+			}))
+
+			Expect(fakeSBOMStore.LoadSBOMCallCount()).To(Equal(1))
+			Expect(fakeSBOMStore.LoadSBOMArgsForCall(0)).To(Equal("package-id"))
+			Expect(fakeCCStore.LoadArgsForCall(0)).To(Equal("package-id"))
+			Expect(fakeParser.ParseArgsForCall(0)).To(Equal([]byte("cc-package")))
+			Expect(fakeSBOMStore.SaveSBOMCallCount()).To(Equal(1))
+			packageID, sbomBytes := fakeSBOMStore.SaveSBOMArgsForCall(0)
+			Expect(packageID).To(Equal("package-id"))
+			Expect(sbom.Unmarshal(sbomBytes)).To(Equal(s))
+		})
+
+		Context("when the SBOM was saved", func() {
+			BeforeEach(func() {
+				fakeSBOMStore.LoadSBOMReturns([]byte(`{"type":"node","components":[{"name":"fabric-shim","version":"2.1.2","direct":true}],"vendoring_verified":true}`), nil)
+			})
+
+			It("returns the saved SBOM", func() {
+				s, err := ef.QueryInstalledChaincodeSBOM("package-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(s).To(Equal(&sbom.SBOM{
+					Type:       "node",
+					Components: []*sbom.Component{{Name: "fabric-shim", Version: "2.1.2", Direct: true}},
+					Verified:   true,
+				}))
+				Expect(fakeCCStore.LoadCallCount()).To(Equal(0))
+				Expect(fakeSBOMStore.SaveSBOMCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when loading the SBOM fails", func() {
+			BeforeEach(func() {
+				fakeSBOMStore.LoadSBOMReturns(nil, fmt.Errorf("fake-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryInstalledChaincodeSBOM("package-id")
+				Expect(err).To(MatchError("could not load SBOM: fake-error"))
+			})
+		})
+
+		Context("when the chaincode is not installed", func() {
+			BeforeEach(func() {
+				fakeCCStore.LoadReturns(nil, persistence.CodePackageNotFoundErr{PackageID: "package-id"})
+			})
+
+			It("returns the error", func() {
+				_, err := ef.QueryInstalledChaincodeSBOM("package-id")
+				Expect(err).To(Equal(persistence.CodePackageNotFoundErr{PackageID: "package-id"}))
+			})
+		})
+
+		Context("when parsing the chaincode package fails", func() {
+			BeforeEach(func() {
+				fakeParser.ParseReturns(nil, fmt.Errorf("parse-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryInstalledChaincodeSBOM("package-id")
+				Expect(err).To(MatchError("could not parse as a chaincode install package: parse-error"))
+			})
+		})
+
+		Context("when saving the SBOM fails", func() {
+			BeforeEach(func() {
+				fakeSBOMStore.SaveSBOMReturns(fmt.Errorf("fake-error"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := ef.QueryInstalledChaincodeSBOM("package-id")
+				Expect(err).To(MatchError("could not save SBOM: fake-error"))
+			})
+		})
+	})
+
 	Describe("QueryInstalledChaincode", func() {
 		BeforeEach(func() {
 			fakeLister.GetInstalledChaincodeReturns(&chaincode.InstalledChaincode{
@@ -615,6 +781,132 @@ var _ = Describe("ExternalFunctions", func() {
 		})
 	})
 
+	Describe("QueryAllChaincodeDefinitions", func() {
+		It("passes through to the cache", func() {
+			committedChaincodes := map[string]map[string]*lifecycle.ChaincodeDefinition{
+				"test-channel": {
+					"test-chaincode": {Sequence: 2},
+				},
+				"another-channel": {},
+			}
+			fakeCommittedLister.ListCommittedChaincodesReturns(committedChaincodes)
+
+			Expect(ef.QueryAllChaincodeDefinitions()).To(Equal(committedChaincodes))
+			Expect(fakeCommittedLister.ListCommittedChaincodesCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("AnalyzeCollectionConfigUpdate", func() {
+		var (
+			publicKVS       MapLedgerShim
+			fakePublicState *mock.ReadWritableState
+			collection      func(name string, orgs ...string) *pb.CollectionConfig
+		)
+
+		BeforeEach(func() {
+			collection = func(name string, orgs ...string) *pb.CollectionConfig {
+				return &pb.CollectionConfig{
+					Payload: &pb.CollectionConfig_StaticCollectionConfig{
+						StaticCollectionConfig: &pb.StaticCollectionConfig{
+							Name: name,
+							MemberOrgsPolicy: &pb.CollectionPolicyConfig{
+								Payload: &pb.CollectionPolicyConfig_SignaturePolicy{
+									SignaturePolicy: policydsl.SignedByAnyMember(orgs),
+								},
+							},
+						},
+					},
+				}
+			}
+
+			publicKVS = MapLedgerShim(map[string][]byte{})
+			fakePublicState = &mock.ReadWritableState{}
+			fakePublicState.GetStateStub = publicKVS.GetState
+			resources.Serializer.Serialize("namespaces", "cc-name", &lifecycle.ChaincodeDefinition{
+				Sequence:        2,
+				EndorsementInfo: &lb.ChaincodeEndorsementInfo{},
+				ValidationInfo:  &lb.ChaincodeValidationInfo{},
+				Collections: &pb.CollectionConfigPackage{
+					Config: []*pb.CollectionConfig{
+						collection("coll1", "org0", "org1", "org2"),
+						collection("coll2", "org0", "org1"),
+					},
+				},
+			}, publicKVS)
+
+			fakeStatsProvider.CollectionPvtDataStatsStub = func(channelID, namespace, coll string) (*ledger.CollectionPvtDataStats, error) {
+				if coll == "coll1" {
+					return &ledger.CollectionPvtDataStats{Entries: 3, Bytes: 300}, nil
+				}
+				return &ledger.CollectionPvtDataStats{}, nil
+			}
+		})
+
+		It("reports the orgs removed from the existing collections and the private data held", func() {
+			impacts, err := ef.AnalyzeCollectionConfigUpdate("my-channel", "cc-name", &pb.CollectionConfigPackage{
+				Config: []*pb.CollectionConfig{
+					collection("coll1", "org1"),
+					collection("coll2", "org0", "org1", "org2"),
+					collection("coll3", "org0"),
+				},
+			}, fakePublicState)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(impacts).To(Equal([]*lifecycle.CollectionConfigUpdateImpact{
+				{
+					Name:         "coll1",
+					RemovedOrgs:  []string{"org0", "org2"},
+					PvtDataStats: &ledger.CollectionPvtDataStats{Entries: 3, Bytes: 300},
+				},
+				{
+					Name:         "coll2",
+					RemovedOrgs:  []string{},
+					PvtDataStats: &ledger.CollectionPvtDataStats{},
+				},
+			}))
+
+			Expect(fakeStatsProvider.CollectionPvtDataStatsCallCount()).To(Equal(2))
+			channelID, namespace, coll := fakeStatsProvider.CollectionPvtDataStatsArgsForCall(0)
+			Expect(channelID).To(Equal("my-channel"))
+			Expect(namespace).To(Equal("cc-name"))
+			Expect(coll).To(Equal("coll1"))
+		})
+
+		Context("when the chaincode is not defined", func() {
+			It("returns an error", func() {
+				_, err := ef.AnalyzeCollectionConfigUpdate("my-channel", "missing-name", &pb.CollectionConfigPackage{}, fakePublicState)
+				Expect(err).To(MatchError("namespace missing-name is not defined"))
+			})
+		})
+
+		Context("when an existing collection is missing from the update", func() {
+			It("returns an error", func() {
+				_, err := ef.AnalyzeCollectionConfigUpdate("my-channel", "cc-name", &pb.CollectionConfigPackage{
+					Config: []*pb.CollectionConfig{
+						collection("coll1", "org1"),
+					},
+				}, fakePublicState)
+				Expect(err).To(MatchError("existing collection 'coll2' of chaincode 'cc-name' is missing from the collection configuration"))
+			})
+		})
+
+		Context("when the private data statistics cannot be retrieved", func() {
+			BeforeEach(func() {
+				fakeStatsProvider.CollectionPvtDataStatsStub = nil
+				fakeStatsProvider.CollectionPvtDataStatsReturns(nil, errors.New("ledger-error"))
+			})
+
+			It("returns an error", func() {
+				_, err := ef.AnalyzeCollectionConfigUpdate("my-channel", "cc-name", &pb.CollectionConfigPackage{
+					Config: []*pb.CollectionConfig{
+						collection("coll1", "org1"),
+						collection("coll2", "org1"),
+					},
+				}, fakePublicState)
+				Expect(err).To(MatchError("could not get private data statistics of collection 'coll1': ledger-error"))
+			})
+		})
+	})
+
 	Describe("ApproveChaincodeDefinitionForOrg", func() {
 		var (
 			fakePublicState *mock.ReadWritableState
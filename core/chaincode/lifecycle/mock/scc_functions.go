@@ -4,11 +4,29 @@ package mock
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/sbom"
 )
 
 type SCCFunctions struct {
+	AnalyzeCollectionConfigUpdateStub        func(string, string, *peer.CollectionConfigPackage, lifecycle.ReadableState) ([]*lifecycle.CollectionConfigUpdateImpact, error)
+	analyzeCollectionConfigUpdateMutex       sync.RWMutex
+	analyzeCollectionConfigUpdateArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *peer.CollectionConfigPackage
+		arg4 lifecycle.ReadableState
+	}
+	analyzeCollectionConfigUpdateReturns struct {
+		result1 []*lifecycle.CollectionConfigUpdateImpact
+		result2 error
+	}
+	analyzeCollectionConfigUpdateReturnsOnCall map[int]struct {
+		result1 []*lifecycle.CollectionConfigUpdateImpact
+		result2 error
+	}
 	ApproveChaincodeDefinitionForOrgStub        func(string, string, *lifecycle.ChaincodeDefinition, string, lifecycle.ReadableState, lifecycle.ReadWritableState) error
 	approveChaincodeDefinitionForOrgMutex       sync.RWMutex
 	approveChaincodeDefinitionForOrgArgsForCall []struct {
@@ -85,6 +103,16 @@ type SCCFunctions struct {
 		result1 *chaincode.InstalledChaincode
 		result2 error
 	}
+	QueryAllChaincodeDefinitionsStub        func() map[string]map[string]*lifecycle.ChaincodeDefinition
+	queryAllChaincodeDefinitionsMutex       sync.RWMutex
+	queryAllChaincodeDefinitionsArgsForCall []struct {
+	}
+	queryAllChaincodeDefinitionsReturns struct {
+		result1 map[string]map[string]*lifecycle.ChaincodeDefinition
+	}
+	queryAllChaincodeDefinitionsReturnsOnCall map[int]struct {
+		result1 map[string]map[string]*lifecycle.ChaincodeDefinition
+	}
 	QueryApprovedChaincodeDefinitionStub        func(string, string, int64, lifecycle.ReadableState, lifecycle.ReadableState) (*lifecycle.ApprovedChaincodeDefinition, error)
 	queryApprovedChaincodeDefinitionMutex       sync.RWMutex
 	queryApprovedChaincodeDefinitionArgsForCall []struct {
@@ -129,6 +157,19 @@ type SCCFunctions struct {
 		result1 *chaincode.InstalledChaincode
 		result2 error
 	}
+	QueryInstalledChaincodeSBOMStub        func(string) (*sbom.SBOM, error)
+	queryInstalledChaincodeSBOMMutex       sync.RWMutex
+	queryInstalledChaincodeSBOMArgsForCall []struct {
+		arg1 string
+	}
+	queryInstalledChaincodeSBOMReturns struct {
+		result1 *sbom.SBOM
+		result2 error
+	}
+	queryInstalledChaincodeSBOMReturnsOnCall map[int]struct {
+		result1 *sbom.SBOM
+		result2 error
+	}
 	QueryInstalledChaincodesStub        func() []*chaincode.InstalledChaincode
 	queryInstalledChaincodesMutex       sync.RWMutex
 	queryInstalledChaincodesArgsForCall []struct {
@@ -171,6 +212,72 @@ type SCCFunctions struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *SCCFunctions) AnalyzeCollectionConfigUpdate(arg1 string, arg2 string, arg3 *peer.CollectionConfigPackage, arg4 lifecycle.ReadableState) ([]*lifecycle.CollectionConfigUpdateImpact, error) {
+	fake.analyzeCollectionConfigUpdateMutex.Lock()
+	ret, specificReturn := fake.analyzeCollectionConfigUpdateReturnsOnCall[len(fake.analyzeCollectionConfigUpdateArgsForCall)]
+	fake.analyzeCollectionConfigUpdateArgsForCall = append(fake.analyzeCollectionConfigUpdateArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *peer.CollectionConfigPackage
+		arg4 lifecycle.ReadableState
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AnalyzeCollectionConfigUpdate", []interface{}{arg1, arg2, arg3, arg4})
+	fake.analyzeCollectionConfigUpdateMutex.Unlock()
+	if fake.AnalyzeCollectionConfigUpdateStub != nil {
+		return fake.AnalyzeCollectionConfigUpdateStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.analyzeCollectionConfigUpdateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SCCFunctions) AnalyzeCollectionConfigUpdateCallCount() int {
+	fake.analyzeCollectionConfigUpdateMutex.RLock()
+	defer fake.analyzeCollectionConfigUpdateMutex.RUnlock()
+	return len(fake.analyzeCollectionConfigUpdateArgsForCall)
+}
+
+func (fake *SCCFunctions) AnalyzeCollectionConfigUpdateCalls(stub func(string, string, *peer.CollectionConfigPackage, lifecycle.ReadableState) ([]*lifecycle.CollectionConfigUpdateImpact, error)) {
+	fake.analyzeCollectionConfigUpdateMutex.Lock()
+	defer fake.analyzeCollectionConfigUpdateMutex.Unlock()
+	fake.AnalyzeCollectionConfigUpdateStub = stub
+}
+
+func (fake *SCCFunctions) AnalyzeCollectionConfigUpdateArgsForCall(i int) (string, string, *peer.CollectionConfigPackage, lifecycle.ReadableState) {
+	fake.analyzeCollectionConfigUpdateMutex.RLock()
+	defer fake.analyzeCollectionConfigUpdateMutex.RUnlock()
+	argsForCall := fake.analyzeCollectionConfigUpdateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *SCCFunctions) AnalyzeCollectionConfigUpdateReturns(result1 []*lifecycle.CollectionConfigUpdateImpact, result2 error) {
+	fake.analyzeCollectionConfigUpdateMutex.Lock()
+	defer fake.analyzeCollectionConfigUpdateMutex.Unlock()
+	fake.AnalyzeCollectionConfigUpdateStub = nil
+	fake.analyzeCollectionConfigUpdateReturns = struct {
+		result1 []*lifecycle.CollectionConfigUpdateImpact
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) AnalyzeCollectionConfigUpdateReturnsOnCall(i int, result1 []*lifecycle.CollectionConfigUpdateImpact, result2 error) {
+	fake.analyzeCollectionConfigUpdateMutex.Lock()
+	defer fake.analyzeCollectionConfigUpdateMutex.Unlock()
+	fake.AnalyzeCollectionConfigUpdateStub = nil
+	if fake.analyzeCollectionConfigUpdateReturnsOnCall == nil {
+		fake.analyzeCollectionConfigUpdateReturnsOnCall = make(map[int]struct {
+			result1 []*lifecycle.CollectionConfigUpdateImpact
+			result2 error
+		})
+	}
+	fake.analyzeCollectionConfigUpdateReturnsOnCall[i] = struct {
+		result1 []*lifecycle.CollectionConfigUpdateImpact
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *SCCFunctions) ApproveChaincodeDefinitionForOrg(arg1 string, arg2 string, arg3 *lifecycle.ChaincodeDefinition, arg4 string, arg5 lifecycle.ReadableState, arg6 lifecycle.ReadWritableState) error {
 	fake.approveChaincodeDefinitionForOrgMutex.Lock()
 	ret, specificReturn := fake.approveChaincodeDefinitionForOrgReturnsOnCall[len(fake.approveChaincodeDefinitionForOrgArgsForCall)]
@@ -511,6 +618,58 @@ func (fake *SCCFunctions) InstallChaincodeReturnsOnCall(i int, result1 *chaincod
 	}{result1, result2}
 }
 
+func (fake *SCCFunctions) QueryAllChaincodeDefinitions() map[string]map[string]*lifecycle.ChaincodeDefinition {
+	fake.queryAllChaincodeDefinitionsMutex.Lock()
+	ret, specificReturn := fake.queryAllChaincodeDefinitionsReturnsOnCall[len(fake.queryAllChaincodeDefinitionsArgsForCall)]
+	fake.queryAllChaincodeDefinitionsArgsForCall = append(fake.queryAllChaincodeDefinitionsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("QueryAllChaincodeDefinitions", []interface{}{})
+	fake.queryAllChaincodeDefinitionsMutex.Unlock()
+	if fake.QueryAllChaincodeDefinitionsStub != nil {
+		return fake.QueryAllChaincodeDefinitionsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.queryAllChaincodeDefinitionsReturns
+	return fakeReturns.result1
+}
+
+func (fake *SCCFunctions) QueryAllChaincodeDefinitionsCallCount() int {
+	fake.queryAllChaincodeDefinitionsMutex.RLock()
+	defer fake.queryAllChaincodeDefinitionsMutex.RUnlock()
+	return len(fake.queryAllChaincodeDefinitionsArgsForCall)
+}
+
+func (fake *SCCFunctions) QueryAllChaincodeDefinitionsCalls(stub func() map[string]map[string]*lifecycle.ChaincodeDefinition) {
+	fake.queryAllChaincodeDefinitionsMutex.Lock()
+	defer fake.queryAllChaincodeDefinitionsMutex.Unlock()
+	fake.QueryAllChaincodeDefinitionsStub = stub
+}
+
+func (fake *SCCFunctions) QueryAllChaincodeDefinitionsReturns(result1 map[string]map[string]*lifecycle.ChaincodeDefinition) {
+	fake.queryAllChaincodeDefinitionsMutex.Lock()
+	defer fake.queryAllChaincodeDefinitionsMutex.Unlock()
+	fake.QueryAllChaincodeDefinitionsStub = nil
+	fake.queryAllChaincodeDefinitionsReturns = struct {
+		result1 map[string]map[string]*lifecycle.ChaincodeDefinition
+	}{result1}
+}
+
+func (fake *SCCFunctions) QueryAllChaincodeDefinitionsReturnsOnCall(i int, result1 map[string]map[string]*lifecycle.ChaincodeDefinition) {
+	fake.queryAllChaincodeDefinitionsMutex.Lock()
+	defer fake.queryAllChaincodeDefinitionsMutex.Unlock()
+	fake.QueryAllChaincodeDefinitionsStub = nil
+	if fake.queryAllChaincodeDefinitionsReturnsOnCall == nil {
+		fake.queryAllChaincodeDefinitionsReturnsOnCall = make(map[int]struct {
+			result1 map[string]map[string]*lifecycle.ChaincodeDefinition
+		})
+	}
+	fake.queryAllChaincodeDefinitionsReturnsOnCall[i] = struct {
+		result1 map[string]map[string]*lifecycle.ChaincodeDefinition
+	}{result1}
+}
+
 func (fake *SCCFunctions) QueryApprovedChaincodeDefinition(arg1 string, arg2 string, arg3 int64, arg4 lifecycle.ReadableState, arg5 lifecycle.ReadableState) (*lifecycle.ApprovedChaincodeDefinition, error) {
 	fake.queryApprovedChaincodeDefinitionMutex.Lock()
 	ret, specificReturn := fake.queryApprovedChaincodeDefinitionReturnsOnCall[len(fake.queryApprovedChaincodeDefinitionArgsForCall)]
@@ -705,6 +864,69 @@ func (fake *SCCFunctions) QueryInstalledChaincodeReturnsOnCall(i int, result1 *c
 	}{result1, result2}
 }
 
+func (fake *SCCFunctions) QueryInstalledChaincodeSBOM(arg1 string) (*sbom.SBOM, error) {
+	fake.queryInstalledChaincodeSBOMMutex.Lock()
+	ret, specificReturn := fake.queryInstalledChaincodeSBOMReturnsOnCall[len(fake.queryInstalledChaincodeSBOMArgsForCall)]
+	fake.queryInstalledChaincodeSBOMArgsForCall = append(fake.queryInstalledChaincodeSBOMArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("QueryInstalledChaincodeSBOM", []interface{}{arg1})
+	fake.queryInstalledChaincodeSBOMMutex.Unlock()
+	if fake.QueryInstalledChaincodeSBOMStub != nil {
+		return fake.QueryInstalledChaincodeSBOMStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.queryInstalledChaincodeSBOMReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SCCFunctions) QueryInstalledChaincodeSBOMCallCount() int {
+	fake.queryInstalledChaincodeSBOMMutex.RLock()
+	defer fake.queryInstalledChaincodeSBOMMutex.RUnlock()
+	return len(fake.queryInstalledChaincodeSBOMArgsForCall)
+}
+
+func (fake *SCCFunctions) QueryInstalledChaincodeSBOMCalls(stub func(string) (*sbom.SBOM, error)) {
+	fake.queryInstalledChaincodeSBOMMutex.Lock()
+	defer fake.queryInstalledChaincodeSBOMMutex.Unlock()
+	fake.QueryInstalledChaincodeSBOMStub = stub
+}
+
+func (fake *SCCFunctions) QueryInstalledChaincodeSBOMArgsForCall(i int) string {
+	fake.queryInstalledChaincodeSBOMMutex.RLock()
+	defer fake.queryInstalledChaincodeSBOMMutex.RUnlock()
+	argsForCall := fake.queryInstalledChaincodeSBOMArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *SCCFunctions) QueryInstalledChaincodeSBOMReturns(result1 *sbom.SBOM, result2 error) {
+	fake.queryInstalledChaincodeSBOMMutex.Lock()
+	defer fake.queryInstalledChaincodeSBOMMutex.Unlock()
+	fake.QueryInstalledChaincodeSBOMStub = nil
+	fake.queryInstalledChaincodeSBOMReturns = struct {
+		result1 *sbom.SBOM
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SCCFunctions) QueryInstalledChaincodeSBOMReturnsOnCall(i int, result1 *sbom.SBOM, result2 error) {
+	fake.queryInstalledChaincodeSBOMMutex.Lock()
+	defer fake.queryInstalledChaincodeSBOMMutex.Unlock()
+	fake.QueryInstalledChaincodeSBOMStub = nil
+	if fake.queryInstalledChaincodeSBOMReturnsOnCall == nil {
+		fake.queryInstalledChaincodeSBOMReturnsOnCall = make(map[int]struct {
+			result1 *sbom.SBOM
+			result2 error
+		})
+	}
+	fake.queryInstalledChaincodeSBOMReturnsOnCall[i] = struct {
+		result1 *sbom.SBOM
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *SCCFunctions) QueryInstalledChaincodes() []*chaincode.InstalledChaincode {
 	fake.queryInstalledChaincodesMutex.Lock()
 	ret, specificReturn := fake.queryInstalledChaincodesReturnsOnCall[len(fake.queryInstalledChaincodesArgsForCall)]
@@ -893,6 +1115,8 @@ func (fake *SCCFunctions) QueryOrgApprovalsReturnsOnCall(i int, result1 map[stri
 func (fake *SCCFunctions) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.analyzeCollectionConfigUpdateMutex.RLock()
+	defer fake.analyzeCollectionConfigUpdateMutex.RUnlock()
 	fake.approveChaincodeDefinitionForOrgMutex.RLock()
 	defer fake.approveChaincodeDefinitionForOrgMutex.RUnlock()
 	fake.checkCommitReadinessMutex.RLock()
@@ -903,12 +1127,16 @@ func (fake *SCCFunctions) Invocations() map[string][][]interface{} {
 	defer fake.getInstalledChaincodePackageMutex.RUnlock()
 	fake.installChaincodeMutex.RLock()
 	defer fake.installChaincodeMutex.RUnlock()
+	fake.queryAllChaincodeDefinitionsMutex.RLock()
+	defer fake.queryAllChaincodeDefinitionsMutex.RUnlock()
 	fake.queryApprovedChaincodeDefinitionMutex.RLock()
 	defer fake.queryApprovedChaincodeDefinitionMutex.RUnlock()
 	fake.queryChaincodeDefinitionMutex.RLock()
 	defer fake.queryChaincodeDefinitionMutex.RUnlock()
 	fake.queryInstalledChaincodeMutex.RLock()
 	defer fake.queryInstalledChaincodeMutex.RUnlock()
+	fake.queryInstalledChaincodeSBOMMutex.RLock()
+	defer fake.queryInstalledChaincodeSBOMMutex.RUnlock()
 	fake.queryInstalledChaincodesMutex.RLock()
 	defer fake.queryInstalledChaincodesMutex.RUnlock()
 	fake.queryNamespaceDefinitionsMutex.RLock()
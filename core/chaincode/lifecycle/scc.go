@@ -9,17 +9,21 @@ package lifecycle
 import (
 	"fmt"
 	"regexp"
+	"sort"
 
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-protos-go/common"
 	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/core/chaincode/argschema"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/chaincode/sbom"
 	"github.com/hyperledger/fabric/core/dispatcher"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/msp"
@@ -46,6 +50,10 @@ const (
 	// query all installed chaincodes
 	QueryInstalledChaincodesFuncName = "QueryInstalledChaincodes"
 
+	// QueryInstalledChaincodeSBOMFuncName is the chaincode function name used
+	// to query the software bill of materials of an installed chaincode
+	QueryInstalledChaincodeSBOMFuncName = "QueryInstalledChaincodeSBOM"
+
 	// ApproveChaincodeDefinitionForMyOrgFuncName is the chaincode function name
 	// used to approve a chaincode definition for execution by the user's own org
 	ApproveChaincodeDefinitionForMyOrgFuncName = "ApproveChaincodeDefinitionForMyOrg"
@@ -70,6 +78,18 @@ const (
 	// QueryChaincodeDefinitionsFuncName is the chaincode function name used to
 	// query the committed chaincode definitions in a channel.
 	QueryChaincodeDefinitionsFuncName = "QueryChaincodeDefinitions"
+
+	// QueryAllChaincodeDefinitionsFuncName is the chaincode function name used to
+	// query the committed chaincode definitions in all the channels of a peer.
+	QueryAllChaincodeDefinitionsFuncName = "QueryAllChaincodeDefinitions"
+
+	// AnalyzeCollectionConfigUpdateFuncName is the chaincode function name used to
+	// analyze the impact of a collection configuration update on a channel.
+	AnalyzeCollectionConfigUpdateFuncName = "AnalyzeCollectionConfigUpdate"
+
+	// MaxMetadataSize is the maximum size, keys and values included, of the
+	// operator defined metadata of a chaincode definition.
+	MaxMetadataSize = 64 * 1024
 )
 
 // SCCFunctions provides a backing implementation with concrete arguments
@@ -88,6 +108,10 @@ type SCCFunctions interface {
 	// QueryInstalledChaincodes returns the currently installed chaincodes
 	QueryInstalledChaincodes() []*chaincode.InstalledChaincode
 
+	// QueryInstalledChaincodeSBOM returns the software bill of materials of the
+	// chaincode with the supplied package ID.
+	QueryInstalledChaincodeSBOM(packageID string) (*sbom.SBOM, error)
+
 	// ApproveChaincodeDefinitionForOrg records a chaincode definition into this org's implicit collection.
 	ApproveChaincodeDefinitionForOrg(chname, ccname string, cd *ChaincodeDefinition, packageID string, publicState ReadableState, orgState ReadWritableState) error
 
@@ -115,6 +139,15 @@ type SCCFunctions interface {
 
 	// QueryNamespaceDefinitions returns all defined namespaces
 	QueryNamespaceDefinitions(publicState RangeableState) (map[string]string, error)
+
+	// QueryAllChaincodeDefinitions returns the chaincode definitions committed on
+	// each channel the peer has joined, keyed by channel and chaincode name.
+	QueryAllChaincodeDefinitions() map[string]map[string]*ChaincodeDefinition
+
+	// AnalyzeCollectionConfigUpdate reports the member orgs removed from the existing
+	// collections by a collection configuration update, along with the private data
+	// of these collections held by the peer.
+	AnalyzeCollectionConfigUpdate(chname, ccname string, collections *pb.CollectionConfigPackage, publicState ReadableState) ([]*CollectionConfigUpdateImpact, error)
 }
 
 //go:generate counterfeiter -o mock/channel_config_source.go --fake-name ChannelConfigSource . ChannelConfigSource
@@ -357,10 +390,44 @@ func (i *Invocation) QueryInstalledChaincodes(input *lb.QueryInstalledChaincodes
 	return result, nil
 }
 
+// QueryInstalledChaincodeSBOM is a SCC function that may be dispatched to
+// which routes to the underlying lifecycle implementation.
+func (i *Invocation) QueryInstalledChaincodeSBOM(input *lb.QueryInstalledChaincodeSBOMArgs) (proto.Message, error) {
+	logger.Debugf("received invocation of QueryInstalledChaincodeSBOM for install package ID '%s'",
+		input.PackageId,
+	)
+
+	s, err := i.SCC.Functions.QueryInstalledChaincodeSBOM(input.PackageId)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]*lb.QueryInstalledChaincodeSBOMResult_Component, len(s.Components))
+	for i, component := range s.Components {
+		components[i] = &lb.QueryInstalledChaincodeSBOMResult_Component{
+			Name:    component.Name,
+			Version: component.Version,
+			Direct:  component.Direct,
+			Replace: component.Replace,
+		}
+	}
+
+	return &lb.QueryInstalledChaincodeSBOMResult{
+		PackageId:         input.PackageId,
+		Type:              s.Type,
+		Components:        components,
+		VendoringVerified: s.Verified,
+		Issues:            s.Issues,
+	}, nil
+}
+
 // ApproveChaincodeDefinitionForMyOrg is a SCC function that may be dispatched
 // to which routes to the underlying lifecycle implementation.
 func (i *Invocation) ApproveChaincodeDefinitionForMyOrg(input *lb.ApproveChaincodeDefinitionForMyOrgArgs) (proto.Message, error) {
-	if err := i.validateInput(input.Name, input.Version, input.Collections); err != nil {
+	if err := i.validateInput(input.Name, input.Version, input.ValidationParameter, input.Collections); err != nil {
+		return nil, errors.WithMessage(err, "error validating chaincode definition")
+	}
+	if err := i.validateMetadata(input.Metadata); err != nil {
 		return nil, errors.WithMessage(err, "error validating chaincode definition")
 	}
 	collectionName := ImplicitCollectionNameForOrg(i.SCC.OrgMSPID)
@@ -385,6 +452,7 @@ func (i *Invocation) ApproveChaincodeDefinitionForMyOrg(input *lb.ApproveChainco
 			Version:           input.Version,
 			EndorsementPlugin: input.EndorsementPlugin,
 			InitRequired:      input.InitRequired,
+			Metadata:          input.Metadata,
 		},
 		ValidationInfo: &lb.ChaincodeValidationInfo{
 			ValidationPlugin:    input.ValidationPlugin,
@@ -448,6 +516,7 @@ func (i *Invocation) QueryApprovedChaincodeDefinition(input *lb.QueryApprovedCha
 		InitRequired:        ca.EndorsementInfo.InitRequired,
 		Collections:         ca.Collections,
 		Source:              ca.Source,
+		Metadata:            ca.EndorsementInfo.Metadata,
 	}, nil
 }
 
@@ -465,6 +534,7 @@ func (i *Invocation) CheckCommitReadiness(input *lb.CheckCommitReadinessArgs) (p
 			Version:           input.Version,
 			EndorsementPlugin: input.EndorsementPlugin,
 			InitRequired:      input.InitRequired,
+			Metadata:          input.Metadata,
 		},
 		ValidationInfo: &lb.ChaincodeValidationInfo{
 			ValidationPlugin:    input.ValidationPlugin,
@@ -497,7 +567,10 @@ func (i *Invocation) CheckCommitReadiness(input *lb.CheckCommitReadinessArgs) (p
 // CommitChaincodeDefinition is a SCC function that may be dispatched
 // to which routes to the underlying lifecycle implementation.
 func (i *Invocation) CommitChaincodeDefinition(input *lb.CommitChaincodeDefinitionArgs) (proto.Message, error) {
-	if err := i.validateInput(input.Name, input.Version, input.Collections); err != nil {
+	if err := i.validateInput(input.Name, input.Version, input.ValidationParameter, input.Collections); err != nil {
+		return nil, errors.WithMessage(err, "error validating chaincode definition")
+	}
+	if err := i.validateMetadata(input.Metadata); err != nil {
 		return nil, errors.WithMessage(err, "error validating chaincode definition")
 	}
 
@@ -528,6 +601,7 @@ func (i *Invocation) CommitChaincodeDefinition(input *lb.CommitChaincodeDefiniti
 			Version:           input.Version,
 			EndorsementPlugin: input.EndorsementPlugin,
 			InitRequired:      input.InitRequired,
+			Metadata:          input.Metadata,
 		},
 		ValidationInfo: &lb.ChaincodeValidationInfo{
 			ValidationPlugin:    input.ValidationPlugin,
@@ -593,6 +667,7 @@ func (i *Invocation) QueryChaincodeDefinition(input *lb.QueryChaincodeDefinition
 		InitRequired:        definedChaincode.EndorsementInfo.InitRequired,
 		Collections:         definedChaincode.Collections,
 		Approvals:           approvals,
+		Metadata:            definedChaincode.EndorsementInfo.Metadata,
 	}, nil
 }
 
@@ -625,6 +700,7 @@ func (i *Invocation) QueryChaincodeDefinitions(input *lb.QueryChaincodeDefinitio
 				ValidationParameter: definedChaincode.ValidationInfo.ValidationParameter,
 				InitRequired:        definedChaincode.EndorsementInfo.InitRequired,
 				Collections:         definedChaincode.Collections,
+				Metadata:            definedChaincode.EndorsementInfo.Metadata,
 			})
 		}
 	}
@@ -634,6 +710,102 @@ func (i *Invocation) QueryChaincodeDefinitions(input *lb.QueryChaincodeDefinitio
 	}, nil
 }
 
+// QueryAllChaincodeDefinitions is a SCC function that may be dispatched
+// to which routes to the underlying lifecycle implementation. It is a
+// peer-wide query which returns the committed chaincode definitions of
+// every channel the peer has joined, sorted by channel and chaincode name.
+func (i *Invocation) QueryAllChaincodeDefinitions(input *lb.QueryAllChaincodeDefinitionsArgs) (proto.Message, error) {
+	logger.Debugf("received invocation of QueryAllChaincodeDefinitions")
+
+	if i.ChannelID != "" {
+		return nil, errors.Errorf("QueryAllChaincodeDefinitions is a peer-wide query and cannot be invoked on channel '%s'", i.ChannelID)
+	}
+
+	committedChaincodes := i.SCC.Functions.QueryAllChaincodeDefinitions()
+	channelIDs := make([]string, 0, len(committedChaincodes))
+	for channelID := range committedChaincodes {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	channels := []*lb.QueryAllChaincodeDefinitionsResult_Channel{}
+	for _, channelID := range channelIDs {
+		definitions := committedChaincodes[channelID]
+		names := make([]string, 0, len(definitions))
+		for name := range definitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		chaincodeDefinitions := []*lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{}
+		for _, name := range names {
+			definedChaincode := definitions[name]
+			chaincodeDefinitions = append(chaincodeDefinitions, &lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{
+				Name:                name,
+				Sequence:            definedChaincode.Sequence,
+				Version:             definedChaincode.EndorsementInfo.Version,
+				EndorsementPlugin:   definedChaincode.EndorsementInfo.EndorsementPlugin,
+				ValidationPlugin:    definedChaincode.ValidationInfo.ValidationPlugin,
+				ValidationParameter: definedChaincode.ValidationInfo.ValidationParameter,
+				InitRequired:        definedChaincode.EndorsementInfo.InitRequired,
+				Collections:         definedChaincode.Collections,
+				Metadata:            definedChaincode.EndorsementInfo.Metadata,
+			})
+		}
+
+		channels = append(channels, &lb.QueryAllChaincodeDefinitionsResult_Channel{
+			ChannelId:            channelID,
+			ChaincodeDefinitions: chaincodeDefinitions,
+		})
+	}
+
+	return &lb.QueryAllChaincodeDefinitionsResult{
+		Channels: channels,
+	}, nil
+}
+
+// AnalyzeCollectionConfigUpdate is a SCC function that may be dispatched
+// to which routes to the underlying lifecycle implementation. It reports,
+// before the update is committed, the member orgs removed from each existing
+// collection and the private data held by this peer which becomes orphaned
+// if the org of the peer is among them.
+func (i *Invocation) AnalyzeCollectionConfigUpdate(input *lb.AnalyzeCollectionConfigUpdateArgs) (proto.Message, error) {
+	logger.Debugf("received invocation of AnalyzeCollectionConfigUpdate on channel '%s' for chaincode '%s'",
+		i.Stub.GetChannelID(),
+		input.Name,
+	)
+
+	if i.ChannelID == "" {
+		return nil, errors.New("AnalyzeCollectionConfigUpdate must be invoked on a channel")
+	}
+
+	impacts, err := i.SCC.Functions.AnalyzeCollectionConfigUpdate(i.Stub.GetChannelID(), input.Name, input.Collections, i.Stub)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []*lb.AnalyzeCollectionConfigUpdateResult_Collection{}
+	for _, impact := range impacts {
+		peerOrgRemoved := false
+		for _, org := range impact.RemovedOrgs {
+			if org == i.SCC.OrgMSPID {
+				peerOrgRemoved = true
+			}
+		}
+		collections = append(collections, &lb.AnalyzeCollectionConfigUpdateResult_Collection{
+			Name:           impact.Name,
+			RemovedOrgs:    impact.RemovedOrgs,
+			PeerOrgRemoved: peerOrgRemoved,
+			PvtDataEntries: impact.PvtDataStats.Entries,
+			PvtDataBytes:   impact.PvtDataStats.Bytes,
+		})
+	}
+
+	return &lb.AnalyzeCollectionConfigUpdateResult{
+		Collections: collections,
+	}, nil
+}
+
 var (
 	// NOTE the chaincode name/version regular expressions should stay in sync
 	// with those defined in core/scc/lscc/lscc.go until LSCC has been removed.
@@ -653,7 +825,45 @@ var (
 	}
 )
 
-func (i *Invocation) validateInput(name, version string, collections *pb.CollectionConfigPackage) error {
+// validateMetadata checks the operator defined metadata of a chaincode definition, which
+// the definition may only carry if the channel capability enabling it is set, as peers that
+// don't support it would agree on the definition without its metadata.
+func (i *Invocation) validateMetadata(metadata map[string][]byte) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	channelConfig := i.SCC.ChannelConfigSource.GetStableChannelConfig(i.ChannelID)
+	if channelConfig == nil {
+		return errors.Errorf("could not get channelconfig for channel '%s'", i.ChannelID)
+	}
+	if !channelConfig.ChannelConfig().Capabilities().ChaincodeMetadata() {
+		return errors.Errorf("metadata requires the %s channel capability", capabilities.ChannelChaincodeMetadata)
+	}
+
+	size := 0
+	for key, value := range metadata {
+		if key == "" {
+			return errors.New("metadata keys must not be empty")
+		}
+		size += len(key) + len(value)
+	}
+	if size > MaxMetadataSize {
+		return errors.Errorf("metadata of %d bytes exceeds the maximum of %d bytes", size, MaxMetadataSize)
+	}
+	if schema, ok := metadata[argschema.MetadataKey]; ok {
+		if _, err := argschema.Parse(schema); err != nil {
+			return errors.WithMessage(err, "invalid argument schema")
+		}
+	}
+	if history, ok := metadata[ledger.HistoryMetadataKey]; ok {
+		if h := string(history); h != ledger.HistoryEnabled && h != ledger.HistoryDisabled {
+			return errors.Errorf("invalid value '%s' of metadata key '%s', must be '%s' or '%s'", h, ledger.HistoryMetadataKey, ledger.HistoryEnabled, ledger.HistoryDisabled)
+		}
+	}
+	return nil
+}
+
+func (i *Invocation) validateInput(name, version string, validationParameter []byte, collections *pb.CollectionConfigPackage) error {
 	if !ChaincodeNameRegExp.MatchString(name) {
 		return errors.Errorf("invalid chaincode name '%s'. Names can only consist of alphanumerics, '_', and '-' and can only begin with alphanumerics", name)
 	}
@@ -688,6 +898,10 @@ func (i *Invocation) validateInput(name, version string, collections *pb.Collect
 		return err
 	}
 
+	if err := validateWeightedPolicies(validationParameter, collConfigs, channelConfig); err != nil {
+		return err
+	}
+
 	// validate against collection configs in the committed definition
 	qe := i.SCC.QueryExecutorProvider.TxQueryExecutor(i.Stub.GetChannelID(), i.Stub.GetTxID())
 	committedCCDef, err := i.SCC.DeployedCCInfoProvider.ChaincodeInfo(i.ChannelID, name, qe)
@@ -703,6 +917,44 @@ func (i *Invocation) validateInput(name, version string, collections *pb.Collect
 	return nil
 }
 
+// validateWeightedPolicies ensures that the signature policies among the endorsement policy
+// and the collection endorsement policies only carry weighted thresholds (i.e. are of version 1)
+// if the channel capability enabling them is set, as peers that don't support it would evaluate
+// them differently.
+func validateWeightedPolicies(validationParameter []byte, collConfigs []*pb.StaticCollectionConfig, channelConfig channelconfig.Resources) error {
+	type namedPolicy struct {
+		name   string
+		policy *common.SignaturePolicyEnvelope
+	}
+	var weighted []namedPolicy
+
+	// unparsable validation parameters are left to the validation plugin, as they always were
+	ap := &pb.ApplicationPolicy{}
+	if err := proto.Unmarshal(validationParameter, ap); err == nil && ap.GetSignaturePolicy().GetVersion() == cauthdsl.WeightedPolicyVersion {
+		weighted = append(weighted, namedPolicy{name: "endorsement policy", policy: ap.GetSignaturePolicy()})
+	}
+	for _, c := range collConfigs {
+		sp := c.EndorsementPolicy.GetSignaturePolicy()
+		if sp.GetVersion() == cauthdsl.WeightedPolicyVersion {
+			weighted = append(weighted, namedPolicy{name: fmt.Sprintf("endorsement policy of collection '%s'", c.Name), policy: sp})
+		}
+	}
+	if len(weighted) == 0 {
+		return nil
+	}
+
+	if !channelConfig.ChannelConfig().Capabilities().WeightedSignaturePolicies() {
+		return errors.Errorf("%s is a weighted signature policy, which requires the %s channel capability", weighted[0].name, capabilities.ChannelWeightedPolicies)
+	}
+	pp := &cauthdsl.EnvelopeBasedPolicyProvider{Deserializer: channelConfig.MSPManager(), Weighted: true}
+	for _, np := range weighted {
+		if _, err := pp.NewPolicy(np.policy); err != nil {
+			return errors.WithMessagef(err, "invalid %s", np.name)
+		}
+	}
+	return nil
+}
+
 func extractStaticCollectionConfigs(collConfigPkg *pb.CollectionConfigPackage) ([]*pb.StaticCollectionConfig, error) {
 	if collConfigPkg == nil || len(collConfigPkg.Config) == 0 {
 		return nil, nil
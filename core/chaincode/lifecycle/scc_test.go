@@ -15,6 +15,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/common"
 	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
@@ -24,8 +25,10 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/chaincode/sbom"
 	"github.com/hyperledger/fabric/core/dispatcher"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 
 	. "github.com/onsi/ginkgo"
@@ -279,6 +282,78 @@ var _ = Describe("SCC", func() {
 			})
 		})
 
+		Describe("QueryInstalledChaincodeSBOM", func() {
+			var (
+				arg          *lb.QueryInstalledChaincodeSBOMArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.QueryInstalledChaincodeSBOMArgs{
+					PackageId: "awesome_package",
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("QueryInstalledChaincodeSBOM"), marshaledArg})
+
+				fakeSCCFuncs.QueryInstalledChaincodeSBOMReturns(&sbom.SBOM{
+					Type: "golang",
+					Components: []*sbom.Component{
+						{Name: "example.com/dep", Version: "v1.0.0", Direct: true, Replace: "example.com/fork v1.0.1"},
+						{Name: "example.com/transitive", Version: "v0.1.0"},
+					},
+					Issues: []string{"module example.com/transitive is required at v0.1.0 but vendored at v0.2.0"},
+				}, nil)
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.QueryInstalledChaincodeSBOMResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(payload, &lb.QueryInstalledChaincodeSBOMResult{
+					PackageId: "awesome_package",
+					Type:      "golang",
+					Components: []*lb.QueryInstalledChaincodeSBOMResult_Component{
+						{Name: "example.com/dep", Version: "v1.0.0", Direct: true, Replace: "example.com/fork v1.0.1"},
+						{Name: "example.com/transitive", Version: "v0.1.0"},
+					},
+					Issues: []string{"module example.com/transitive is required at v0.1.0 but vendored at v0.2.0"},
+				})).To(BeTrue())
+
+				Expect(fakeSCCFuncs.QueryInstalledChaincodeSBOMCallCount()).To(Equal(1))
+				Expect(fakeSCCFuncs.QueryInstalledChaincodeSBOMArgsForCall(0)).To(Equal("awesome_package"))
+			})
+
+			Context("when the code package cannot be found", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryInstalledChaincodeSBOMReturns(nil, persistence.CodePackageNotFoundErr{PackageID: "less_awesome_package"})
+				})
+
+				It("returns 404 Not Found", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(404)))
+					Expect(res.Message).To(Equal("chaincode install package 'less_awesome_package' not found"))
+				})
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryInstalledChaincodeSBOMReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'QueryInstalledChaincodeSBOM': underlying-error"))
+				})
+			})
+		})
+
 		Describe("GetInstalledChaincodePackage", func() {
 			var (
 				arg          *lb.GetInstalledChaincodePackageArgs
@@ -510,6 +585,151 @@ var _ = Describe("SCC", func() {
 				Expect(privState.(*lifecycle.ChaincodePrivateLedgerShim).Collection).To(Equal("_implicit_org_fake-mspid"))
 			})
 
+			Context("when the definition carries metadata", func() {
+				var fakeChannelCapabilities *mock.ChannelCapabilities
+
+				BeforeEach(func() {
+					arg.Metadata = map[string][]byte{
+						"source":       []byte("https://example.com/cc.git"),
+						"audit-report": []byte("sm3-digest"),
+					}
+
+					fakeChannelCapabilities = &mock.ChannelCapabilities{}
+					fakeChannelCapabilities.ChaincodeMetadataReturns(true)
+					fakeChannel := &mock.Channel{}
+					fakeChannel.CapabilitiesReturns(fakeChannelCapabilities)
+					fakeChannelConfig.ChannelConfigReturns(fakeChannel)
+				})
+
+				It("passes the metadata in the endorsement info", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(200)))
+
+					Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(1))
+					_, _, cd, _, _, _ := fakeSCCFuncs.ApproveChaincodeDefinitionForOrgArgsForCall(0)
+					Expect(cd.EndorsementInfo.Metadata).To(Equal(map[string][]byte{
+						"source":       []byte("https://example.com/cc.git"),
+						"audit-report": []byte("sm3-digest"),
+					}))
+				})
+
+				Context("when the chaincode metadata capability is not enabled", func() {
+					BeforeEach(func() {
+						fakeChannelCapabilities.ChaincodeMetadataReturns(false)
+					})
+
+					It("wraps and returns the error", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(500)))
+						Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: metadata requires the V2_2_CHAINCODE_METADATA channel capability"))
+					})
+				})
+
+				Context("when a metadata key is empty", func() {
+					BeforeEach(func() {
+						arg.Metadata[""] = []byte("value")
+					})
+
+					It("wraps and returns the error", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(500)))
+						Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: metadata keys must not be empty"))
+					})
+				})
+
+				Context("when the metadata is too large", func() {
+					BeforeEach(func() {
+						arg.Metadata["source"] = make([]byte, lifecycle.MaxMetadataSize)
+					})
+
+					It("wraps and returns the error", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(500)))
+						Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: metadata of 65564 bytes exceeds the maximum of 65536 bytes"))
+					})
+				})
+
+				Context("when the argument schema cannot be parsed", func() {
+					BeforeEach(func() {
+						arg.Metadata["argschema"] = []byte(`{"functions": {"Get": {"args": [{"name": "key", "type": "date"}]}}}`)
+					})
+
+					It("wraps and returns the error", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(500)))
+						Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: invalid argument schema: invalid description of function 'Get' in the argument schema: argument 'key' has unknown type 'date'"))
+					})
+				})
+
+				Context("when the history setting is invalid", func() {
+					BeforeEach(func() {
+						arg.Metadata["history"] = []byte("off")
+					})
+
+					It("wraps and returns the error", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(500)))
+						Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: invalid value 'off' of metadata key 'history', must be 'enabled' or 'disabled'"))
+					})
+				})
+			})
+
+			Context("when the endorsement policy is a weighted signature policy", func() {
+				var fakeChannelCapabilities *mock.ChannelCapabilities
+
+				BeforeEach(func() {
+					envelope, err := policydsl.FromString("OutOf(2, Weight(2, 'fakeOrg1.member'), 'fakeOrg2.member')")
+					Expect(err).NotTo(HaveOccurred())
+					arg.ValidationParameter = protoutil.MarshalOrPanic(&pb.ApplicationPolicy{
+						Type: &pb.ApplicationPolicy_SignaturePolicy{
+							SignaturePolicy: envelope,
+						},
+					})
+
+					fakeChannelCapabilities = &mock.ChannelCapabilities{}
+					fakeChannel := &mock.Channel{}
+					fakeChannel.CapabilitiesReturns(fakeChannelCapabilities)
+					fakeChannelConfig.ChannelConfigReturns(fakeChannel)
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: endorsement policy is a weighted signature policy, which requires the V2_2_WEIGHTED_POLICIES channel capability"))
+				})
+
+				Context("when the weighted policies capability is enabled", func() {
+					BeforeEach(func() {
+						fakeChannelCapabilities.WeightedSignaturePoliciesReturns(true)
+					})
+
+					It("approves the definition", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(200)))
+						Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(1))
+					})
+
+					Context("when the weighted policy is invalid", func() {
+						BeforeEach(func() {
+							arg.ValidationParameter = protoutil.MarshalOrPanic(&pb.ApplicationPolicy{
+								Type: &pb.ApplicationPolicy_SignaturePolicy{
+									SignaturePolicy: &common.SignaturePolicyEnvelope{
+										Version: 1,
+										Rule:    policydsl.NOutOf(1, []*common.SignaturePolicy{policydsl.SignedBy(1), policydsl.SignedBy(1)}),
+									},
+								},
+							})
+						})
+
+						It("wraps and returns the error", func() {
+							res := scc.Invoke(fakeStub)
+							Expect(res.Status).To(Equal(int32(500)))
+							Expect(res.Message).To(Equal("failed to invoke backing implementation of 'ApproveChaincodeDefinitionForMyOrg': error validating chaincode definition: invalid endorsement policy: identity index out of range, requested 1, but identities length is 0"))
+						})
+					})
+				})
+			})
+
 			Context("when the chaincode name contains invalid characters", func() {
 				BeforeEach(func() {
 					arg.Name = "!nvalid"
@@ -1442,6 +1662,7 @@ var _ = Describe("SCC", func() {
 						EndorsementInfo: &lb.ChaincodeEndorsementInfo{
 							Version:           "version",
 							EndorsementPlugin: "endorsement-plugin",
+							Metadata:          map[string][]byte{"source": []byte("source-repo")},
 						},
 						ValidationInfo: &lb.ChaincodeValidationInfo{
 							ValidationPlugin:    "validation-plugin",
@@ -1478,6 +1699,7 @@ var _ = Describe("SCC", func() {
 						"fake-mspid":  true,
 						"other-mspid": true,
 					},
+					Metadata: map[string][]byte{"source": []byte("source-repo")},
 				})).To(BeTrue())
 
 				Expect(fakeSCCFuncs.QueryChaincodeDefinitionCallCount()).To(Equal(1))
@@ -1650,6 +1872,189 @@ var _ = Describe("SCC", func() {
 				})
 			})
 		})
+
+		Describe("QueryAllChaincodeDefinitions", func() {
+			var (
+				arg          *lb.QueryAllChaincodeDefinitionsArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.QueryAllChaincodeDefinitionsArgs{}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetChannelIDReturns("")
+				fakeStub.GetArgsReturns([][]byte{[]byte("QueryAllChaincodeDefinitions"), marshaledArg})
+				definition := func(sequence int64, version string) *lifecycle.ChaincodeDefinition {
+					return &lifecycle.ChaincodeDefinition{
+						Sequence: sequence,
+						EndorsementInfo: &lb.ChaincodeEndorsementInfo{
+							Version:           version,
+							EndorsementPlugin: "endorsement-plugin",
+						},
+						ValidationInfo: &lb.ChaincodeValidationInfo{
+							ValidationPlugin:    "validation-plugin",
+							ValidationParameter: []byte("validation-parameter"),
+						},
+						Collections: &pb.CollectionConfigPackage{},
+					}
+				}
+				fakeSCCFuncs.QueryAllChaincodeDefinitionsReturns(map[string]map[string]*lifecycle.ChaincodeDefinition{
+					"channel2": {
+						"woo": definition(5, "2.0"),
+						"foo": definition(2, "1.0"),
+					},
+					"channel1": {
+						"foo": definition(3, "1.1"),
+					},
+					"channel3": {},
+				})
+			})
+
+			It("returns the definitions of all the channels sorted by channel and chaincode name", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.QueryAllChaincodeDefinitionsResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(payload.Channels).To(HaveLen(3))
+				Expect(payload.Channels[0].ChannelId).To(Equal("channel1"))
+				Expect(payload.Channels[1].ChannelId).To(Equal("channel2"))
+				Expect(payload.Channels[2].ChannelId).To(Equal("channel3"))
+				Expect(payload.Channels[0].ChaincodeDefinitions).To(Equal([]*lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{
+					{
+						Name:                "foo",
+						Sequence:            3,
+						Version:             "1.1",
+						EndorsementPlugin:   "endorsement-plugin",
+						ValidationPlugin:    "validation-plugin",
+						ValidationParameter: []byte("validation-parameter"),
+						Collections:         &pb.CollectionConfigPackage{},
+					},
+				}))
+				Expect(payload.Channels[1].ChaincodeDefinitions).To(HaveLen(2))
+				Expect(payload.Channels[1].ChaincodeDefinitions[0].Name).To(Equal("foo"))
+				Expect(payload.Channels[1].ChaincodeDefinitions[0].Sequence).To(Equal(int64(2)))
+				Expect(payload.Channels[1].ChaincodeDefinitions[1].Name).To(Equal("woo"))
+				Expect(payload.Channels[1].ChaincodeDefinitions[1].Sequence).To(Equal(int64(5)))
+				Expect(payload.Channels[1].ChaincodeDefinitions[1].Version).To(Equal("2.0"))
+				Expect(payload.Channels[2].ChaincodeDefinitions).To(BeEmpty())
+
+				Expect(fakeACLProvider.CheckACLCallCount()).To(Equal(1))
+				resource, channelID, _ := fakeACLProvider.CheckACLArgsForCall(0)
+				Expect(resource).To(Equal("_lifecycle/QueryAllChaincodeDefinitions"))
+				Expect(channelID).To(Equal(""))
+			})
+
+			Context("when invoked on a channel", func() {
+				BeforeEach(func() {
+					fakeStub.GetChannelIDReturns("test-channel")
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'QueryAllChaincodeDefinitions': QueryAllChaincodeDefinitions is a peer-wide query and cannot be invoked on channel 'test-channel'"))
+					Expect(fakeSCCFuncs.QueryAllChaincodeDefinitionsCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Describe("AnalyzeCollectionConfigUpdate", func() {
+			var (
+				arg          *lb.AnalyzeCollectionConfigUpdateArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.AnalyzeCollectionConfigUpdateArgs{
+					Name:        "cc-name",
+					Collections: &pb.CollectionConfigPackage{},
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetChannelIDReturns("test-channel")
+				fakeStub.GetArgsReturns([][]byte{[]byte("AnalyzeCollectionConfigUpdate"), marshaledArg})
+				fakeSCCFuncs.AnalyzeCollectionConfigUpdateReturns([]*lifecycle.CollectionConfigUpdateImpact{
+					{
+						Name:         "coll1",
+						RemovedOrgs:  []string{"fake-mspid", "other-mspid"},
+						PvtDataStats: &ledger.CollectionPvtDataStats{Entries: 2, Bytes: 200},
+					},
+					{
+						Name:         "coll2",
+						RemovedOrgs:  []string{"other-mspid"},
+						PvtDataStats: &ledger.CollectionPvtDataStats{Entries: 1, Bytes: 100},
+					},
+				}, nil)
+			})
+
+			It("reports the impact of the update on each collection", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.AnalyzeCollectionConfigUpdateResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(payload, &lb.AnalyzeCollectionConfigUpdateResult{
+					Collections: []*lb.AnalyzeCollectionConfigUpdateResult_Collection{
+						{
+							Name:           "coll1",
+							RemovedOrgs:    []string{"fake-mspid", "other-mspid"},
+							PeerOrgRemoved: true,
+							PvtDataEntries: 2,
+							PvtDataBytes:   200,
+						},
+						{
+							Name:           "coll2",
+							RemovedOrgs:    []string{"other-mspid"},
+							PvtDataEntries: 1,
+							PvtDataBytes:   100,
+						},
+					},
+				})).To(BeTrue())
+
+				Expect(fakeSCCFuncs.AnalyzeCollectionConfigUpdateCallCount()).To(Equal(1))
+				channelID, name, collections, _ := fakeSCCFuncs.AnalyzeCollectionConfigUpdateArgsForCall(0)
+				Expect(channelID).To(Equal("test-channel"))
+				Expect(name).To(Equal("cc-name"))
+				Expect(proto.Equal(collections, &pb.CollectionConfigPackage{})).To(BeTrue())
+
+				resource, _, _ := fakeACLProvider.CheckACLArgsForCall(0)
+				Expect(resource).To(Equal("_lifecycle/AnalyzeCollectionConfigUpdate"))
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.AnalyzeCollectionConfigUpdateReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'AnalyzeCollectionConfigUpdate': underlying-error"))
+				})
+			})
+
+			Context("when invoked without a channel", func() {
+				BeforeEach(func() {
+					fakeStub.GetChannelIDReturns("")
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'AnalyzeCollectionConfigUpdate': AnalyzeCollectionConfigUpdate must be invoked on a channel"))
+					Expect(fakeSCCFuncs.AnalyzeCollectionConfigUpdateCallCount()).To(Equal(0))
+				})
+			})
+		})
 	})
 })
 
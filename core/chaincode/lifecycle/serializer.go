@@ -44,11 +44,19 @@ type RangeableState interface {
 
 type Marshaler func(proto.Message) ([]byte, error)
 
+// Marshal marshals the message with the marshaler if set, or else deterministically
+// with the standard protobuf impl, so that the maps of the chaincode definitions
+// hash the same on every peer.
 func (m Marshaler) Marshal(msg proto.Message) ([]byte, error) {
 	if m != nil {
 		return m(msg)
 	}
-	return proto.Marshal(msg)
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 var ProtoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
@@ -1076,4 +1076,22 @@ var _ = Describe("Serializer", func() {
 			})
 		})
 	})
+
+	Describe("Marshaler", func() {
+		It("marshals the maps deterministically", func() {
+			info := &lb.ChaincodeEndorsementInfo{Metadata: map[string][]byte{}}
+			for i := 0; i < 20; i++ {
+				info.Metadata[fmt.Sprintf("key-%d", i)] = []byte(fmt.Sprintf("value-%d", i))
+			}
+			expected, err := lifecycle.Marshaler(nil).Marshal(info)
+			Expect(err).NotTo(HaveOccurred())
+			for i := 0; i < 10; i++ {
+				Expect(lifecycle.Marshaler(nil).Marshal(info)).To(Equal(expected))
+			}
+
+			unmarshaled := &lb.ChaincodeEndorsementInfo{}
+			Expect(proto.Unmarshal(expected, unmarshaled)).To(Succeed())
+			Expect(proto.Equal(unmarshaled, info)).To(BeTrue())
+		})
+	})
 })
@@ -59,6 +59,21 @@ var (
 		LabelNames:   []string{"chaincode"},
 		StatsdFormat: "%{#fqname}.%{chaincode}",
 	}
+
+	customCounter = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Name:         "custom_counter",
+		Help:         "The counters emitted by chaincodes.",
+		LabelNames:   []string{"channel", "chaincode", "name"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}.%{name}",
+	}
+	customHistogram = metrics.HistogramOpts{
+		Namespace:    "chaincode",
+		Name:         "custom_histogram",
+		Help:         "The histograms emitted by chaincodes.",
+		LabelNames:   []string{"channel", "chaincode", "name"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}.%{name}",
+	}
 )
 
 type HandlerMetrics struct {
@@ -66,6 +81,8 @@ type HandlerMetrics struct {
 	ShimRequestsCompleted metrics.Counter
 	ShimRequestDuration   metrics.Histogram
 	ExecuteTimeouts       metrics.Counter
+	CustomCounter         metrics.Counter
+	CustomHistogram       metrics.Histogram
 }
 
 func NewHandlerMetrics(p metrics.Provider) *HandlerMetrics {
@@ -74,6 +91,8 @@ func NewHandlerMetrics(p metrics.Provider) *HandlerMetrics {
 		ShimRequestsCompleted: p.NewCounter(shimRequestsCompleted),
 		ShimRequestDuration:   p.NewHistogram(shimRequestDuration),
 		ExecuteTimeouts:       p.NewCounter(executeTimeouts),
+		CustomCounter:         p.NewCounter(customCounter),
+		CustomHistogram:       p.NewHistogram(customHistogram),
 	}
 }
 
@@ -105,6 +105,18 @@ type PeerLedger struct {
 		result1 *common.BlockchainInfo
 		result2 error
 	}
+	GetCommittedStateInfoStub        func() (*common.BlockchainInfo, error)
+	getCommittedStateInfoMutex       sync.RWMutex
+	getCommittedStateInfoArgsForCall []struct {
+	}
+	getCommittedStateInfoReturns struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}
+	getCommittedStateInfoReturnsOnCall map[int]struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}
 	GetBlocksIteratorStub        func(uint64) (ledgera.ResultsIterator, error)
 	getBlocksIteratorMutex       sync.RWMutex
 	getBlocksIteratorArgsForCall []struct {
@@ -156,6 +168,18 @@ type PeerLedger struct {
 		result1 *ledger.BlockAndPvtData
 		result2 error
 	}
+	GetPvtDataAntiEntropyStub        func() (ledger.PvtDataAntiEntropy, error)
+	getPvtDataAntiEntropyMutex       sync.RWMutex
+	getPvtDataAntiEntropyArgsForCall []struct {
+	}
+	getPvtDataAntiEntropyReturns struct {
+		result1 ledger.PvtDataAntiEntropy
+		result2 error
+	}
+	getPvtDataAntiEntropyReturnsOnCall map[int]struct {
+		result1 ledger.PvtDataAntiEntropy
+		result2 error
+	}
 	GetPvtDataByNumStub        func(uint64, ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error)
 	getPvtDataByNumMutex       sync.RWMutex
 	getPvtDataByNumArgsForCall []struct {
@@ -702,6 +726,61 @@ func (fake *PeerLedger) GetBlockchainInfoReturnsOnCall(i int, result1 *common.Bl
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetCommittedStateInfo() (*common.BlockchainInfo, error) {
+	fake.getCommittedStateInfoMutex.Lock()
+	ret, specificReturn := fake.getCommittedStateInfoReturnsOnCall[len(fake.getCommittedStateInfoArgsForCall)]
+	fake.getCommittedStateInfoArgsForCall = append(fake.getCommittedStateInfoArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetCommittedStateInfo", []interface{}{})
+	fake.getCommittedStateInfoMutex.Unlock()
+	if fake.GetCommittedStateInfoStub != nil {
+		return fake.GetCommittedStateInfoStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCommittedStateInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) GetCommittedStateInfoCallCount() int {
+	fake.getCommittedStateInfoMutex.RLock()
+	defer fake.getCommittedStateInfoMutex.RUnlock()
+	return len(fake.getCommittedStateInfoArgsForCall)
+}
+
+func (fake *PeerLedger) GetCommittedStateInfoCalls(stub func() (*common.BlockchainInfo, error)) {
+	fake.getCommittedStateInfoMutex.Lock()
+	defer fake.getCommittedStateInfoMutex.Unlock()
+	fake.GetCommittedStateInfoStub = stub
+}
+
+func (fake *PeerLedger) GetCommittedStateInfoReturns(result1 *common.BlockchainInfo, result2 error) {
+	fake.getCommittedStateInfoMutex.Lock()
+	defer fake.getCommittedStateInfoMutex.Unlock()
+	fake.GetCommittedStateInfoStub = nil
+	fake.getCommittedStateInfoReturns = struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetCommittedStateInfoReturnsOnCall(i int, result1 *common.BlockchainInfo, result2 error) {
+	fake.getCommittedStateInfoMutex.Lock()
+	defer fake.getCommittedStateInfoMutex.Unlock()
+	fake.GetCommittedStateInfoStub = nil
+	if fake.getCommittedStateInfoReturnsOnCall == nil {
+		fake.getCommittedStateInfoReturnsOnCall = make(map[int]struct {
+			result1 *common.BlockchainInfo
+			result2 error
+		})
+	}
+	fake.getCommittedStateInfoReturnsOnCall[i] = struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetBlocksIterator(arg1 uint64) (ledgera.ResultsIterator, error) {
 	fake.getBlocksIteratorMutex.Lock()
 	ret, specificReturn := fake.getBlocksIteratorReturnsOnCall[len(fake.getBlocksIteratorArgsForCall)]
@@ -939,6 +1018,61 @@ func (fake *PeerLedger) GetPvtDataAndBlockByNumReturnsOnCall(i int, result1 *led
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error) {
+	fake.getPvtDataAntiEntropyMutex.Lock()
+	ret, specificReturn := fake.getPvtDataAntiEntropyReturnsOnCall[len(fake.getPvtDataAntiEntropyArgsForCall)]
+	fake.getPvtDataAntiEntropyArgsForCall = append(fake.getPvtDataAntiEntropyArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetPvtDataAntiEntropy", []interface{}{})
+	fake.getPvtDataAntiEntropyMutex.Unlock()
+	if fake.GetPvtDataAntiEntropyStub != nil {
+		return fake.GetPvtDataAntiEntropyStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPvtDataAntiEntropyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) GetPvtDataAntiEntropyCallCount() int {
+	fake.getPvtDataAntiEntropyMutex.RLock()
+	defer fake.getPvtDataAntiEntropyMutex.RUnlock()
+	return len(fake.getPvtDataAntiEntropyArgsForCall)
+}
+
+func (fake *PeerLedger) GetPvtDataAntiEntropyCalls(stub func() (ledger.PvtDataAntiEntropy, error)) {
+	fake.getPvtDataAntiEntropyMutex.Lock()
+	defer fake.getPvtDataAntiEntropyMutex.Unlock()
+	fake.GetPvtDataAntiEntropyStub = stub
+}
+
+func (fake *PeerLedger) GetPvtDataAntiEntropyReturns(result1 ledger.PvtDataAntiEntropy, result2 error) {
+	fake.getPvtDataAntiEntropyMutex.Lock()
+	defer fake.getPvtDataAntiEntropyMutex.Unlock()
+	fake.GetPvtDataAntiEntropyStub = nil
+	fake.getPvtDataAntiEntropyReturns = struct {
+		result1 ledger.PvtDataAntiEntropy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetPvtDataAntiEntropyReturnsOnCall(i int, result1 ledger.PvtDataAntiEntropy, result2 error) {
+	fake.getPvtDataAntiEntropyMutex.Lock()
+	defer fake.getPvtDataAntiEntropyMutex.Unlock()
+	fake.GetPvtDataAntiEntropyStub = nil
+	if fake.getPvtDataAntiEntropyReturnsOnCall == nil {
+		fake.getPvtDataAntiEntropyReturnsOnCall = make(map[int]struct {
+			result1 ledger.PvtDataAntiEntropy
+			result2 error
+		})
+	}
+	fake.getPvtDataAntiEntropyReturnsOnCall[i] = struct {
+		result1 ledger.PvtDataAntiEntropy
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetPvtDataByNum(arg1 uint64, arg2 ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
 	fake.getPvtDataByNumMutex.Lock()
 	ret, specificReturn := fake.getPvtDataByNumReturnsOnCall[len(fake.getPvtDataByNumArgsForCall)]
@@ -1321,6 +1455,8 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.getBlockByTxIDMutex.RUnlock()
 	fake.getBlockchainInfoMutex.RLock()
 	defer fake.getBlockchainInfoMutex.RUnlock()
+	fake.getCommittedStateInfoMutex.RLock()
+	defer fake.getCommittedStateInfoMutex.RUnlock()
 	fake.getBlocksIteratorMutex.RLock()
 	defer fake.getBlocksIteratorMutex.RUnlock()
 	fake.getConfigHistoryRetrieverMutex.RLock()
@@ -1329,6 +1465,8 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.getMissingPvtDataTrackerMutex.RUnlock()
 	fake.getPvtDataAndBlockByNumMutex.RLock()
 	defer fake.getPvtDataAndBlockByNumMutex.RUnlock()
+	fake.getPvtDataAntiEntropyMutex.RLock()
+	defer fake.getPvtDataAntiEntropyMutex.RUnlock()
 	fake.getPvtDataByNumMutex.RLock()
 	defer fake.getPvtDataByNumMutex.RUnlock()
 	fake.getTransactionByIDMutex.RLock()
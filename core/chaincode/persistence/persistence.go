@@ -194,7 +194,46 @@ func (s *Store) Load(packageID string) ([]byte, error) {
 // been marked built.
 func (s *Store) Delete(packageID string) error {
 	ccInstallPkgPath := filepath.Join(s.Path, CCFileName(packageID))
-	return s.ReadWriter.Remove(ccInstallPkgPath)
+	if err := s.ReadWriter.Remove(ccInstallPkgPath); err != nil {
+		return err
+	}
+
+	sbomPath := filepath.Join(s.Path, SBOMFileName(packageID))
+	if exists, _ := s.ReadWriter.Exists(sbomPath); exists {
+		return s.ReadWriter.Remove(sbomPath)
+	}
+	return nil
+}
+
+// SaveSBOM persists the software bill of materials of the chaincode
+// install package with the given packageID alongside the package.
+func (s *Store) SaveSBOM(packageID string, sbom []byte) error {
+	sbomFileName := SBOMFileName(packageID)
+	if err := s.ReadWriter.WriteFile(s.Path, sbomFileName, sbom); err != nil {
+		return errors.Wrapf(err, "error writing SBOM to %s", filepath.Join(s.Path, sbomFileName))
+	}
+	return nil
+}
+
+// LoadSBOM loads the persisted software bill of materials of the chaincode
+// install package with the given packageID. It returns nil if none was
+// persisted, as for the packages installed before SBOMs were generated.
+func (s *Store) LoadSBOM(packageID string) ([]byte, error) {
+	sbomPath := filepath.Join(s.Path, SBOMFileName(packageID))
+
+	exists, err := s.ReadWriter.Exists(sbomPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not determine whether the SBOM of '%s' exists", packageID)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	sbom, err := s.ReadWriter.ReadFile(sbomPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading SBOM at %s", sbomPath)
+	}
+	return sbom, nil
 }
 
 // CodePackageNotFoundErr is the error returned when a code package cannot
@@ -238,6 +277,12 @@ func CCFileName(packageID string) string {
 	return strings.Replace(packageID, ":", ".", 1) + ".tar.gz"
 }
 
+// SBOMFileName returns the name of the file holding the software bill of
+// materials of the chaincode install package with the given packageID.
+func SBOMFileName(packageID string) string {
+	return strings.Replace(packageID, ":", ".", 1) + ".sbom.json"
+}
+
 var packageFileMatcher = regexp.MustCompile("^(.+)[.]([0-9a-f]{64})[.]tar[.]gz$")
 
 func installedChaincodeFromFilename(fileName string) (chaincode.InstalledChaincode, bool) {
@@ -285,6 +285,21 @@ var _ = Describe("Persistence", func() {
 			Expect(mockReadWriter.RemoveArgsForCall(0)).To(Equal("foo/hash.tar.gz"))
 		})
 
+		When("the chaincode has an SBOM", func() {
+			BeforeEach(func() {
+				mockReadWriter.ExistsReturns(true, nil)
+			})
+
+			It("removes the SBOM as well", func() {
+				err := store.Delete("hash")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mockReadWriter.RemoveCallCount()).To(Equal(2))
+				Expect(mockReadWriter.ExistsArgsForCall(0)).To(Equal("foo/hash.sbom.json"))
+				Expect(mockReadWriter.RemoveArgsForCall(1)).To(Equal("foo/hash.sbom.json"))
+			})
+		})
+
 		When("remove returns an error", func() {
 			BeforeEach(func() {
 				mockReadWriter.RemoveReturns(fmt.Errorf("fake-remove-error"))
@@ -297,6 +312,99 @@ var _ = Describe("Persistence", func() {
 		})
 	})
 
+	Describe("SaveSBOM", func() {
+		var (
+			mockReadWriter *mock.IOReadWriter
+			store          *persistence.Store
+		)
+
+		BeforeEach(func() {
+			mockReadWriter = &mock.IOReadWriter{}
+			store = &persistence.Store{
+				ReadWriter: mockReadWriter,
+				Path:       "foo",
+			}
+		})
+
+		It("writes the SBOM next to the chaincode install package", func() {
+			err := store.SaveSBOM("testcc:hash", []byte("sbom"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mockReadWriter.WriteFileCallCount()).To(Equal(1))
+			path, name, data := mockReadWriter.WriteFileArgsForCall(0)
+			Expect(path).To(Equal("foo"))
+			Expect(name).To(Equal("testcc.hash.sbom.json"))
+			Expect(data).To(Equal([]byte("sbom")))
+		})
+
+		Context("when writing the SBOM fails", func() {
+			BeforeEach(func() {
+				mockReadWriter.WriteFileReturns(errors.New("offside"))
+			})
+
+			It("returns an error", func() {
+				err := store.SaveSBOM("testcc:hash", []byte("sbom"))
+				Expect(err).To(MatchError("error writing SBOM to foo/testcc.hash.sbom.json: offside"))
+			})
+		})
+	})
+
+	Describe("LoadSBOM", func() {
+		var (
+			mockReadWriter *mock.IOReadWriter
+			store          *persistence.Store
+		)
+
+		BeforeEach(func() {
+			mockReadWriter = &mock.IOReadWriter{}
+			mockReadWriter.ReadFileReturns([]byte("sbom"), nil)
+			mockReadWriter.ExistsReturns(true, nil)
+			store = &persistence.Store{
+				ReadWriter: mockReadWriter,
+			}
+		})
+
+		It("loads the SBOM", func() {
+			sbom, err := store.LoadSBOM("testcc:hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sbom).To(Equal([]byte("sbom")))
+			Expect(mockReadWriter.ReadFileArgsForCall(0)).To(Equal("testcc.hash.sbom.json"))
+		})
+
+		Context("when there is no SBOM", func() {
+			BeforeEach(func() {
+				mockReadWriter.ExistsReturns(false, nil)
+			})
+
+			It("returns nil", func() {
+				sbom, err := store.LoadSBOM("testcc:hash")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sbom).To(BeNil())
+			})
+		})
+
+		Context("when an IO error occurred during stat", func() {
+			BeforeEach(func() {
+				mockReadWriter.ExistsReturns(false, errors.New("handball"))
+			})
+
+			It("returns an error", func() {
+				_, err := store.LoadSBOM("testcc:hash")
+				Expect(err).To(MatchError("could not determine whether the SBOM of 'testcc:hash' exists: handball"))
+			})
+		})
+
+		Context("when reading the SBOM fails", func() {
+			BeforeEach(func() {
+				mockReadWriter.ReadFileReturns(nil, errors.New("yellowcard"))
+			})
+
+			It("returns an error", func() {
+				_, err := store.LoadSBOM("testcc:hash")
+				Expect(err).To(MatchError("error reading SBOM at testcc.hash.sbom.json: yellowcard"))
+			})
+		})
+	})
+
 	Describe("Load", func() {
 		var (
 			mockReadWriter *mock.IOReadWriter
@@ -235,6 +235,7 @@ func (p *Platform) DockerBuildOptions(path string) (util.DockerBuildOptions, err
 			env = append(env, "GOPROXY=https://proxy.golang.org")
 		}
 	}
+	env = append(env, "GOARCH="+util.GetArchitecture())
 	ldFlagOpts := getLDFlagsOpts()
 	return util.DockerBuildOptions{
 		Cmd: fmt.Sprintf(buildScript, ldFlagOpts, path),
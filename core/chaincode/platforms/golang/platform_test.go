@@ -15,6 +15,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"testing"
@@ -383,7 +384,7 @@ else
 fi
 echo Done!
 `,
-			Env: []string{"GOPROXY=https://proxy.golang.org"},
+			Env: []string{"GOPROXY=https://proxy.golang.org", "GOARCH=" + runtime.GOARCH},
 		}
 		assert.Equal(t, expectedOpts, opts)
 	})
@@ -424,7 +425,7 @@ else
 fi
 echo Done!
 `,
-			Env: []string{"GOPROXY=the-goproxy", "GOSUMDB=the-gosumdb"},
+			Env: []string{"GOPROXY=the-goproxy", "GOSUMDB=the-gosumdb", "GOARCH=" + runtime.GOARCH},
 		}
 		assert.Equal(t, expectedOpts, opts)
 	})
@@ -91,6 +91,7 @@ func (r *Registry) GenerateDockerfile(ccType string) (string, error) {
 	}
 	buf = append(buf, base)
 	buf = append(buf, fmt.Sprintf(`LABEL %s.chaincode.type="%s" \`, metadata.BaseDockerLabel, ccType))
+	buf = append(buf, fmt.Sprintf(`      %s.chaincode.architecture="%s" \`, metadata.BaseDockerLabel, util.GetArchitecture()))
 	buf = append(buf, fmt.Sprintf(`      %s.version="%s"`, metadata.BaseDockerLabel, metadata.Version))
 	// ----------------------------------------------------------------------------------------------------
 	// Then augment it with any general options
@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"runtime"
 
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/hyperledger/fabric/common/metadata"
@@ -44,8 +45,9 @@ var _ = Describe("Platforms", func() {
 			Expect(err).NotTo(HaveOccurred())
 			expectedDockerfile := fmt.Sprintf(`docker-header
 LABEL org.hyperledger.fabric.chaincode.type="fakeType" \
+      org.hyperledger.fabric.chaincode.architecture="%s" \
       org.hyperledger.fabric.version="%s"
-ENV CORE_CHAINCODE_BUILDLEVEL=%s`, metadata.Version, metadata.Version)
+ENV CORE_CHAINCODE_BUILDLEVEL=%s`, runtime.GOARCH, metadata.Version, metadata.Version)
 			Expect(df).To(Equal(expectedDockerfile))
 		})
 
@@ -169,10 +169,31 @@ func DockerBuild(opts DockerBuildOptions, client *docker.Client) error {
 	return nil
 }
 
+// archAliases maps the architecture names reported by uname and Docker to the
+// names used by Go and by the image tags
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+}
+
+// GetArchitecture returns the target architecture of the chaincode builds and
+// launches, set by chaincode.architecture and defaulting to the architecture of
+// the peer
+func GetArchitecture() string {
+	arch := strings.ToLower(viper.GetString("chaincode.architecture"))
+	if arch == "" {
+		return runtime.GOARCH
+	}
+	if alias, ok := archAliases[arch]; ok {
+		return alias
+	}
+	return arch
+}
+
 // GetDockerImageFromConfig replaces variables in the config
 func GetDockerImageFromConfig(path string) string {
 	r := strings.NewReplacer(
-		"$(ARCH)", runtime.GOARCH,
+		"$(ARCH)", GetArchitecture(),
 		"$(PROJECT_VERSION)", metadata.Version,
 		"$(TWO_DIGIT_VERSION)", twoDigitVersion(metadata.Version),
 		"$(DOCKER_NS)", metadata.DockerNamespace)
@@ -79,6 +79,28 @@ func TestUtil_GetDockerImageFromConfig(t *testing.T) {
 	actual = GetDockerImageFromConfig(path)
 	assert.Equal(t, expected, actual, `Error parsing Dockerfile Template. Expected "%s", got "%s"`, expected, actual)
 
+	viper.Set("chaincode.architecture", "arm64")
+	defer viper.Set("chaincode.architecture", "")
+	expected = "FROM " + metadata.DockerNamespace + ":arm64-" + metadata.Version
+	viper.Set(path, "FROM $(DOCKER_NS):$(ARCH)-$(PROJECT_VERSION)")
+	actual = GetDockerImageFromConfig(path)
+	assert.Equal(t, expected, actual, `Error parsing Dockerfile Template. Expected "%s", got "%s"`, expected, actual)
+}
+
+func TestUtil_GetArchitecture(t *testing.T) {
+	defer viper.Set("chaincode.architecture", "")
+
+	viper.Set("chaincode.architecture", "")
+	assert.Equal(t, runtime.GOARCH, GetArchitecture())
+
+	viper.Set("chaincode.architecture", "arm64")
+	assert.Equal(t, "arm64", GetArchitecture())
+
+	viper.Set("chaincode.architecture", "aarch64")
+	assert.Equal(t, "arm64", GetArchitecture())
+
+	viper.Set("chaincode.architecture", "X86_64")
+	assert.Equal(t, "amd64", GetArchitecture())
 }
 
 func TestMain(m *testing.M) {
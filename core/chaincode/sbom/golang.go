@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type goRequirement struct {
+	path     string
+	version  string
+	indirect bool
+}
+
+// goComponents returns the modules required by the go.mod of a Go chaincode,
+// along with the modules vendored with it, and checks that the vendored
+// modules match the requirements.
+func goComponents(files map[string][]byte) ([]*Component, []string) {
+	goMod, ok := files[goModFile]
+	if !ok {
+		return nil, []string{"no go.mod found, the dependencies are not versioned"}
+	}
+	requirements, replacements := parseGoMod(goMod)
+
+	var components []*Component
+	byPath := map[string]*Component{}
+	for _, r := range requirements {
+		c := &Component{Name: r.path, Version: r.version, Direct: !r.indirect, Replace: replacements[r.path]}
+		components = append(components, c)
+		byPath[r.path] = c
+	}
+
+	modulesTxt, ok := files[goVendorFile]
+	if !ok {
+		if len(requirements) == 0 {
+			return components, nil
+		}
+		return components, []string{"the dependencies are not vendored"}
+	}
+
+	var issues []string
+	vendored, explicit := parseGoVendor(modulesTxt)
+	for _, r := range requirements {
+		version, ok := vendored[r.path]
+		switch {
+		case !ok && !r.indirect:
+			issues = append(issues, fmt.Sprintf("module %s %s is required but not vendored", r.path, r.version))
+		case ok && version != r.version:
+			issues = append(issues, fmt.Sprintf("module %s is required at %s but vendored at %s", r.path, r.version, version))
+		}
+	}
+	for _, p := range sortedKeys(vendored) {
+		if _, ok := byPath[p]; ok {
+			continue
+		}
+		if explicit[p] {
+			issues = append(issues, fmt.Sprintf("module %s %s is vendored but not required", p, vendored[p]))
+		}
+		components = append(components, &Component{Name: p, Version: vendored[p]})
+	}
+	return components, issues
+}
+
+// parseGoMod returns the requirements and the replacements of a go.mod file.
+func parseGoMod(b []byte) ([]goRequirement, map[string]string) {
+	var requirements []goRequirement
+	replacements := map[string]string{}
+
+	block := ""
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line, comment := scanner.Text(), ""
+		if i := strings.Index(line, "//"); i >= 0 {
+			line, comment = line[:i], strings.TrimSpace(line[i+2:])
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		directive := block
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block == "":
+			directive, fields = fields[0], fields[1:]
+		}
+
+		switch directive {
+		case "require":
+			if len(fields) >= 2 {
+				requirements = append(requirements, goRequirement{
+					path:     unquote(fields[0]),
+					version:  fields[1],
+					indirect: comment == "indirect",
+				})
+			}
+		case "replace":
+			for i, f := range fields {
+				if f == "=>" && i+1 < len(fields) {
+					replacements[unquote(fields[0])] = strings.Join(fields[i+1:], " ")
+				}
+			}
+		}
+	}
+	return requirements, replacements
+}
+
+// parseGoVendor returns the versions of the modules listed in a
+// vendor/modules.txt file, and which of them are marked as explicitly required.
+func parseGoVendor(b []byte) (map[string]string, map[string]bool) {
+	versions := map[string]string{}
+	explicit := map[string]bool{}
+
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "## "):
+			for _, marker := range strings.Split(strings.TrimPrefix(line, "## "), ";") {
+				if strings.TrimSpace(marker) == "explicit" && current != "" {
+					explicit[current] = true
+				}
+			}
+		case strings.HasPrefix(line, "# "):
+			fields := strings.Fields(strings.TrimPrefix(line, "# "))
+			current = ""
+			// replacements of unversioned modules are listed as "# path => dir"
+			if len(fields) >= 2 && fields[1] != "=>" {
+				current = fields[0]
+				versions[current] = fields[1]
+			}
+		}
+	}
+	return versions, explicit
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, "\"`")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
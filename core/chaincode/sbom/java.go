@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sbom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type mavenProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type mavenDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
+
+type mavenProject struct {
+	Properties struct {
+		Entries []mavenProperty `xml:",any"`
+	} `xml:"properties"`
+	Dependencies []mavenDependency `xml:"dependencies>dependency"`
+}
+
+var (
+	mavenPropertyRegexp = regexp.MustCompile(`\$\{([^}]+)\}`)
+	gradleDepRegexp     = regexp.MustCompile(`(?m)^\s*(implementation|api|compile|runtime|runtimeOnly|compileOnly)\s*\(?\s*['"]([^'":]+):([^'":]+)(?::([^'"]*))?['"]`)
+)
+
+// javaComponents returns the dependencies declared by the Maven or Gradle
+// build file of a Java chaincode, and checks that their versions are fixed.
+// The transitive dependencies are resolved at build time and are not listed.
+func javaComponents(files map[string][]byte) ([]*Component, []string) {
+	var components []*Component
+	var err error
+	switch {
+	case files[javaMavenFile] != nil:
+		components, err = mavenComponents(files[javaMavenFile])
+	case files[javaGradleFile] != nil:
+		components = gradleComponents(files[javaGradleFile])
+	case files[javaGradleKotlin] != nil:
+		components = gradleComponents(files[javaGradleKotlin])
+	default:
+		return nil, []string{"no pom.xml or build.gradle found, the dependencies are unknown"}
+	}
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	var issues []string
+	for _, c := range components {
+		if !fixedJavaVersion(c.Version) {
+			issues = append(issues, fmt.Sprintf("dependency %s has no fixed version", c.Name))
+		}
+	}
+	return components, issues
+}
+
+func mavenComponents(pom []byte) ([]*Component, error) {
+	project := &mavenProject{}
+	if err := xml.Unmarshal(pom, project); err != nil {
+		return nil, fmt.Errorf("could not parse pom.xml: %s", err)
+	}
+	properties := map[string]string{}
+	for _, p := range project.Properties.Entries {
+		properties[p.XMLName.Local] = strings.TrimSpace(p.Value)
+	}
+
+	var components []*Component
+	for _, d := range project.Dependencies {
+		if d.Scope == "test" {
+			continue
+		}
+		version := mavenPropertyRegexp.ReplaceAllStringFunc(strings.TrimSpace(d.Version), func(ref string) string {
+			if v, ok := properties[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+		components = append(components, &Component{
+			Name:    strings.TrimSpace(d.GroupID) + ":" + strings.TrimSpace(d.ArtifactID),
+			Version: version,
+			Direct:  true,
+		})
+	}
+	return components, nil
+}
+
+func gradleComponents(build []byte) []*Component {
+	var components []*Component
+	for _, m := range gradleDepRegexp.FindAllStringSubmatch(string(build), -1) {
+		components = append(components, &Component{
+			Name:    m[2] + ":" + m[3],
+			Version: m[4],
+			Direct:  true,
+		})
+	}
+	return components
+}
+
+// fixedJavaVersion returns false for the versions resolved at build time:
+// missing or unresolved versions, dynamic versions, ranges and snapshots.
+func fixedJavaVersion(version string) bool {
+	switch {
+	case version == "",
+		strings.Contains(version, "${"),
+		strings.Contains(version, "+"),
+		strings.HasPrefix(version, "latest."),
+		strings.ContainsAny(version, "[]()"),
+		strings.HasSuffix(version, "-SNAPSHOT"):
+		return false
+	}
+	return true
+}
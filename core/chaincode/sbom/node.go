@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type nodePackage struct {
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+type nodeLockedDependency struct {
+	Version      string                           `json:"version"`
+	Dev          bool                             `json:"dev"`
+	Dependencies map[string]*nodeLockedDependency `json:"dependencies"`
+}
+
+type nodePackageLock struct {
+	// lockfileVersion 2 and above list the installed packages by path
+	Packages map[string]*nodeLockedDependency `json:"packages"`
+	// lockfileVersion 1 nests the dependencies
+	Dependencies map[string]*nodeLockedDependency `json:"dependencies"`
+}
+
+// nodeComponents returns the production dependencies of a Node.js chaincode
+// as locked by its package-lock.json, and checks that the dependencies
+// declared by its package.json are locked.
+func nodeComponents(files map[string][]byte) ([]*Component, []string) {
+	packageJSON, ok := files[nodePackageFile]
+	if !ok {
+		return nil, []string{"no package.json found, the dependencies are unknown"}
+	}
+	pkg := &nodePackage{}
+	if err := json.Unmarshal(packageJSON, pkg); err != nil {
+		return nil, []string{fmt.Sprintf("could not parse package.json: %s", err)}
+	}
+
+	lockJSON, ok := files[nodePackageLockFile]
+	if !ok {
+		var components []*Component
+		for _, name := range sortedKeys(pkg.Dependencies) {
+			components = append(components, &Component{Name: name, Direct: true})
+		}
+		if len(components) == 0 {
+			return nil, nil
+		}
+		return components, []string{"no package-lock.json found, the dependency versions are not locked"}
+	}
+	lock := &nodePackageLock{}
+	if err := json.Unmarshal(lockJSON, lock); err != nil {
+		return nil, []string{fmt.Sprintf("could not parse package-lock.json: %s", err)}
+	}
+
+	// the same package may be installed at several versions
+	byVersion := map[string]*Component{}
+	topLevelNames := map[string]bool{}
+	add := func(name, version string, topLevel bool) {
+		key := name + "@" + version
+		if _, ok := byVersion[key]; !ok {
+			byVersion[key] = &Component{Name: name, Version: version}
+		}
+		if topLevel {
+			topLevelNames[name] = true
+			_, byVersion[key].Direct = pkg.Dependencies[name]
+		}
+	}
+	if lock.Packages != nil {
+		for p, dep := range lock.Packages {
+			i := strings.LastIndex(p, nodeModulesDirName+"/")
+			if i < 0 || dep.Dev {
+				continue
+			}
+			add(p[i+len(nodeModulesDirName)+1:], dep.Version, i == 0)
+		}
+	} else {
+		collectNodeDependencies(lock.Dependencies, true, add)
+	}
+
+	var components []*Component
+	var issues []string
+	for _, name := range sortedKeys(pkg.Dependencies) {
+		if !topLevelNames[name] {
+			issues = append(issues, fmt.Sprintf("dependency %s %s is not locked", name, pkg.Dependencies[name]))
+			components = append(components, &Component{Name: name, Direct: true})
+		}
+	}
+	for _, c := range byVersion {
+		components = append(components, c)
+	}
+	return components, issues
+}
+
+// collectNodeDependencies walks the nested dependencies of a lockfile of
+// version 1.
+func collectNodeDependencies(deps map[string]*nodeLockedDependency, topLevel bool, add func(name, version string, topLevel bool)) {
+	for name, dep := range deps {
+		if dep.Dev {
+			continue
+		}
+		add(name, dep.Version, topLevel)
+		collectNodeDependencies(dep.Dependencies, false, add)
+	}
+}
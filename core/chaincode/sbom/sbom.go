@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sbom generates the software bill of materials of a chaincode from
+// the dependency manifests found in its code package, and verifies that the
+// dependencies are vendored or locked to fixed versions.
+package sbom
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The manifests read from the code package.
+const (
+	goModFile           = "go.mod"
+	goVendorFile        = "vendor/modules.txt"
+	nodePackageFile     = "package.json"
+	nodePackageLockFile = "package-lock.json"
+	javaMavenFile       = "pom.xml"
+	javaGradleFile      = "build.gradle"
+	javaGradleKotlin    = "build.gradle.kts"
+	maxManifestSize     = 16 * 1024 * 1024
+	nodeModulesDirName  = "node_modules"
+)
+
+// Component is a dependency of a chaincode.
+type Component struct {
+	// Name is the module path of a Go dependency, the package name of a Node.js
+	// dependency or the groupId:artifactId of a Java dependency.
+	Name string `json:"name"`
+	// Version is the version the dependency resolves to, empty if unknown.
+	Version string `json:"version,omitempty"`
+	// Direct is true for the dependencies declared by the chaincode, false for
+	// the transitive ones.
+	Direct bool `json:"direct"`
+	// Replace is the module replacing a Go dependency, as "path version".
+	Replace string `json:"replace,omitempty"`
+}
+
+// SBOM is the software bill of materials of a chaincode package.
+type SBOM struct {
+	// Type is the chaincode type of the package.
+	Type string `json:"type"`
+	// Components are the dependencies of the chaincode, sorted by name and
+	// version.
+	Components []*Component `json:"components"`
+	// Verified is true if the dependencies are vendored or locked to fixed
+	// versions consistently with the manifests of the chaincode.
+	Verified bool `json:"vendoring_verified"`
+	// Issues explain why the dependencies could not be verified.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// Marshal returns the JSON encoding of the SBOM.
+func (s *SBOM) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Unmarshal decodes a JSON encoded SBOM.
+func Unmarshal(b []byte) (*SBOM, error) {
+	s := &SBOM{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal SBOM")
+	}
+	return s, nil
+}
+
+// Generate returns the SBOM of a chaincode of the given type from its code
+// package, the code.tar.gz of the chaincode install package.
+func Generate(ccType string, codePackage []byte) (*SBOM, error) {
+	files, err := manifests(codePackage)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SBOM{Type: ccType, Components: []*Component{}}
+	switch strings.ToLower(ccType) {
+	case "golang", "go":
+		s.Components, s.Issues = goComponents(files)
+	case "node":
+		s.Components, s.Issues = nodeComponents(files)
+	case "java":
+		s.Components, s.Issues = javaComponents(files)
+	default:
+		s.Issues = []string{"the dependencies of chaincode type '" + ccType + "' are not inspected"}
+	}
+	if s.Components == nil {
+		s.Components = []*Component{}
+	}
+	sort.Slice(s.Components, func(i, j int) bool {
+		ci, cj := s.Components[i], s.Components[j]
+		if ci.Name != cj.Name {
+			return ci.Name < cj.Name
+		}
+		return ci.Version < cj.Version
+	})
+	s.Verified = len(s.Issues) == 0
+	return s, nil
+}
+
+// manifests returns the dependency manifests of a code package, keyed by
+// their path relative to the root of the chaincode. The root is the directory
+// of the shallowest manifest, which leaves aside the manifests of the
+// dependencies vendored or installed below it.
+func manifests(codePackage []byte) (map[string][]byte, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(codePackage))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading code package as gzip stream")
+	}
+	defer gr.Close()
+
+	found := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading code package")
+		}
+		if header.Typeflag != tar.TypeReg || !isManifest(header.Name) {
+			continue
+		}
+		b, err := ioutil.ReadAll(io.LimitReader(tr, maxManifestSize))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading %s from code package", header.Name)
+		}
+		found[path.Clean(header.Name)] = b
+	}
+
+	root := ""
+	depth := -1
+	for name := range found {
+		if strings.HasSuffix(name, goVendorFile) {
+			continue
+		}
+		dir := path.Dir(name)
+		if d := strings.Count(dir, "/"); depth == -1 || d < depth || (d == depth && dir < root) {
+			root, depth = dir, d
+		}
+	}
+
+	files := map[string][]byte{}
+	for name, b := range found {
+		if rel := strings.TrimPrefix(name, root+"/"); rel != name || root == "." {
+			files[rel] = b
+		}
+	}
+	return files, nil
+}
+
+func isManifest(name string) bool {
+	if strings.HasSuffix(name, "/"+goVendorFile) || name == goVendorFile {
+		return true
+	}
+	for _, segment := range strings.Split(path.Dir(name), "/") {
+		if segment == "vendor" || segment == nodeModulesDirName {
+			return false
+		}
+	}
+	switch path.Base(name) {
+	case goModFile, nodePackageFile, nodePackageLockFile, javaMavenFile, javaGradleFile, javaGradleKotlin:
+		return true
+	}
+	return false
+}
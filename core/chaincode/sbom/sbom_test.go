@@ -0,0 +1,258 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sbom
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func codePackage(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+const goMod = `module example.com/cc
+
+go 1.14
+
+require (
+	github.com/hyperledger/fabric-chaincode-go v0.0.0-20200424173110-d7076418f212
+	github.com/hyperledger/fabric-protos-go v0.0.0-20200424173316-dd554ba3746e
+	golang.org/x/text v0.3.2 // indirect
+)
+
+replace github.com/hyperledger/fabric-protos-go => github.com/example/fabric-protos-go v0.1.0
+`
+
+const goModulesTxt = `# github.com/golang/protobuf v1.3.2
+github.com/golang/protobuf/proto
+# github.com/hyperledger/fabric-chaincode-go v0.0.0-20200424173110-d7076418f212
+## explicit
+github.com/hyperledger/fabric-chaincode-go/shim
+# github.com/hyperledger/fabric-protos-go v0.0.0-20200424173316-dd554ba3746e => github.com/example/fabric-protos-go v0.1.0
+## explicit
+github.com/hyperledger/fabric-protos-go/peer
+`
+
+func TestGenerateGolang(t *testing.T) {
+	s, err := Generate("GOLANG", codePackage(t, map[string]string{
+		"src/go.mod":                    goMod,
+		"src/main.go":                   "package main",
+		"src/vendor/modules.txt":        goModulesTxt,
+		"src/vendor/example.com/go.mod": "module example.com/other\n\nrequire example.com/ignored v1.0.0\n",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, &SBOM{
+		Type: "GOLANG",
+		Components: []*Component{
+			{Name: "github.com/golang/protobuf", Version: "v1.3.2"},
+			{Name: "github.com/hyperledger/fabric-chaincode-go", Version: "v0.0.0-20200424173110-d7076418f212", Direct: true},
+			{Name: "github.com/hyperledger/fabric-protos-go", Version: "v0.0.0-20200424173316-dd554ba3746e", Direct: true, Replace: "github.com/example/fabric-protos-go v0.1.0"},
+			{Name: "golang.org/x/text", Version: "v0.3.2"},
+		},
+		Verified: true,
+	}, s)
+
+	b, err := s.Marshal()
+	require.NoError(t, err)
+	unmarshaled, err := Unmarshal(b)
+	require.NoError(t, err)
+	require.Equal(t, s, unmarshaled)
+}
+
+func TestGenerateGolangIssues(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		issues []string
+	}{
+		{
+			name:   "no go.mod",
+			files:  map[string]string{"src/main.go": "package main"},
+			issues: []string{"no go.mod found, the dependencies are not versioned"},
+		},
+		{
+			name:   "not vendored",
+			files:  map[string]string{"src/go.mod": goMod},
+			issues: []string{"the dependencies are not vendored"},
+		},
+		{
+			name: "vendoring mismatch",
+			files: map[string]string{
+				"src/go.mod": goMod,
+				"src/vendor/modules.txt": `# github.com/hyperledger/fabric-protos-go v0.0.0-20190000000000-000000000000
+## explicit
+# example.com/extra v1.0.0
+## explicit
+`,
+			},
+			issues: []string{
+				"module github.com/hyperledger/fabric-chaincode-go v0.0.0-20200424173110-d7076418f212 is required but not vendored",
+				"module github.com/hyperledger/fabric-protos-go is required at v0.0.0-20200424173316-dd554ba3746e but vendored at v0.0.0-20190000000000-000000000000",
+				"module example.com/extra v1.0.0 is vendored but not required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Generate("golang", codePackage(t, tt.files))
+			require.NoError(t, err)
+			require.False(t, s.Verified)
+			require.Equal(t, tt.issues, s.Issues)
+		})
+	}
+}
+
+const packageJSON = `{
+	"name": "cc",
+	"dependencies": {"fabric-contract-api": "^2.1.0", "fabric-shim": "^2.1.0"},
+	"devDependencies": {"mocha": "^7.0.0"}
+}`
+
+func TestGenerateNode(t *testing.T) {
+	lockV2 := `{
+	"lockfileVersion": 2,
+	"packages": {
+		"": {"name": "cc"},
+		"node_modules/fabric-contract-api": {"version": "2.1.2"},
+		"node_modules/fabric-shim": {"version": "2.1.2"},
+		"node_modules/fabric-shim/node_modules/long": {"version": "4.0.0"},
+		"node_modules/long": {"version": "3.2.0"},
+		"node_modules/mocha": {"version": "7.1.0", "dev": true}
+	}
+}`
+	lockV1 := `{
+	"lockfileVersion": 1,
+	"dependencies": {
+		"fabric-contract-api": {"version": "2.1.2"},
+		"fabric-shim": {"version": "2.1.2", "dependencies": {"long": {"version": "4.0.0"}}},
+		"long": {"version": "3.2.0"},
+		"mocha": {"version": "7.1.0", "dev": true}
+	}
+}`
+	expected := []*Component{
+		{Name: "fabric-contract-api", Version: "2.1.2", Direct: true},
+		{Name: "fabric-shim", Version: "2.1.2", Direct: true},
+		{Name: "long", Version: "3.2.0"},
+		{Name: "long", Version: "4.0.0"},
+	}
+
+	for name, lock := range map[string]string{"lockfileVersion 2": lockV2, "lockfileVersion 1": lockV1} {
+		t.Run(name, func(t *testing.T) {
+			s, err := Generate("NODE", codePackage(t, map[string]string{
+				"src/package.json":                          packageJSON,
+				"src/package-lock.json":                     lock,
+				"src/node_modules/fabric-shim/package.json": `{"dependencies": {"long": "^4.0.0"}}`,
+				"src/node_modules/fabric-shim/lib/index.js": "",
+			}))
+			require.NoError(t, err)
+			require.True(t, s.Verified)
+			require.Empty(t, s.Issues)
+			require.Equal(t, expected, s.Components)
+		})
+	}
+}
+
+func TestGenerateNodeIssues(t *testing.T) {
+	s, err := Generate("NODE", codePackage(t, map[string]string{"src/package.json": packageJSON}))
+	require.NoError(t, err)
+	require.False(t, s.Verified)
+	require.Equal(t, []string{"no package-lock.json found, the dependency versions are not locked"}, s.Issues)
+	require.Equal(t, []*Component{
+		{Name: "fabric-contract-api", Direct: true},
+		{Name: "fabric-shim", Direct: true},
+	}, s.Components)
+
+	s, err = Generate("NODE", codePackage(t, map[string]string{
+		"src/package.json":      packageJSON,
+		"src/package-lock.json": `{"lockfileVersion": 2, "packages": {"node_modules/fabric-shim": {"version": "2.1.2"}}}`,
+	}))
+	require.NoError(t, err)
+	require.False(t, s.Verified)
+	require.Equal(t, []string{"dependency fabric-contract-api ^2.1.0 is not locked"}, s.Issues)
+}
+
+func TestGenerateJava(t *testing.T) {
+	pom := `<project>
+	<properties>
+		<fabric-chaincode-java.version>2.1.1</fabric-chaincode-java.version>
+	</properties>
+	<dependencies>
+		<dependency>
+			<groupId>org.hyperledger.fabric-chaincode-java</groupId>
+			<artifactId>fabric-chaincode-shim</artifactId>
+			<version>${fabric-chaincode-java.version}</version>
+		</dependency>
+		<dependency>
+			<groupId>org.json</groupId>
+			<artifactId>json</artifactId>
+			<version>[20180000,)</version>
+		</dependency>
+		<dependency>
+			<groupId>junit</groupId>
+			<artifactId>junit</artifactId>
+			<version>4.12</version>
+			<scope>test</scope>
+		</dependency>
+	</dependencies>
+</project>`
+	s, err := Generate("JAVA", codePackage(t, map[string]string{"src/pom.xml": pom}))
+	require.NoError(t, err)
+	require.False(t, s.Verified)
+	require.Equal(t, []string{"dependency org.json:json has no fixed version"}, s.Issues)
+	require.Equal(t, []*Component{
+		{Name: "org.hyperledger.fabric-chaincode-java:fabric-chaincode-shim", Version: "2.1.1", Direct: true},
+		{Name: "org.json:json", Version: "[20180000,)", Direct: true},
+	}, s.Components)
+
+	gradle := `dependencies {
+    implementation group: 'ignored', name: 'ignored', version: '1.0'
+    implementation 'org.hyperledger.fabric-chaincode-java:fabric-chaincode-shim:2.1.1'
+    compile("com.google.protobuf:protobuf-java:3.+")
+    testImplementation 'junit:junit:4.12'
+}`
+	s, err = Generate("JAVA", codePackage(t, map[string]string{"src/build.gradle": gradle}))
+	require.NoError(t, err)
+	require.False(t, s.Verified)
+	require.Equal(t, []string{"dependency com.google.protobuf:protobuf-java has no fixed version"}, s.Issues)
+	require.Equal(t, []*Component{
+		{Name: "com.google.protobuf:protobuf-java", Version: "3.+", Direct: true},
+		{Name: "org.hyperledger.fabric-chaincode-java:fabric-chaincode-shim", Version: "2.1.1", Direct: true},
+	}, s.Components)
+
+	s, err = Generate("JAVA", codePackage(t, map[string]string{"src/Main.java": ""}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"no pom.xml or build.gradle found, the dependencies are unknown"}, s.Issues)
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	s, err := Generate("CAR", codePackage(t, map[string]string{}))
+	require.NoError(t, err)
+	require.False(t, s.Verified)
+	require.Equal(t, []*Component{}, s.Components)
+	require.Equal(t, []string{"the dependencies of chaincode type 'CAR' are not inspected"}, s.Issues)
+}
+
+func TestGenerateBadPackage(t *testing.T) {
+	_, err := Generate("golang", []byte("not a tar.gz"))
+	require.EqualError(t, err, "error reading code package as gzip stream: gzip: invalid header")
+}
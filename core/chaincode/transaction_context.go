@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"context"
 	"sync"
 
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
 )
@@ -25,6 +27,9 @@ type TransactionContext struct {
 	HistoryQueryExecutor ledger.HistoryQueryExecutor
 	CollectionStore      privdata.CollectionStore
 	IsInitTransaction    bool
+	Context              context.Context
+	// Trace records the shim requests of the transaction, if it is traced
+	Trace *txtrace.Execution
 
 	// tracks open iterators used for range queries
 	queryMutex          sync.Mutex
@@ -134,3 +139,12 @@ func (t *TransactionContext) CloseQueryIterators() {
 		iter.Close()
 	}
 }
+
+// Err returns a non-nil error once the deadline of the transaction has passed
+// or the transaction has been cancelled.
+func (t *TransactionContext) Err() error {
+	if t == nil || t.Context == nil {
+		return nil
+	}
+	return t.Context.Err()
+}
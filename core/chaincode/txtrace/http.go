@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txtrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type statusResponse struct {
+	Settings []Settings `json:"settings"`
+	Traces   []Summary  `json:"traces"`
+}
+
+// Handler manages the execution tracing of the chaincodes through the
+// operations endpoint.
+//
+// PUT with chaincode and sample_rate query parameters enables the tracing of
+// the chaincode, and DELETE with a chaincode query parameter disables it.
+// GET returns the tracing settings along with the summaries of the traces,
+// optionally of a chaincode only; with a txid query parameter, it downloads
+// the traces of the transaction instead.
+type Handler struct {
+	Recorder *Recorder
+}
+
+// NewHandler returns a Handler for the given Recorder.
+func NewHandler(r *Recorder) *Handler {
+	return &Handler{Recorder: r}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	chaincode := query.Get("chaincode")
+
+	switch req.Method {
+	case http.MethodGet:
+		txID := query.Get("txid")
+		if txID == "" {
+			h.sendResponse(resp, http.StatusOK, &statusResponse{
+				Settings: h.Recorder.Settings(),
+				Traces:   h.Recorder.Summaries(chaincode),
+			})
+			return
+		}
+		traces := h.Recorder.Traces(txID, chaincode)
+		if len(traces) == 0 {
+			h.sendResponse(resp, http.StatusNotFound, &errorResponse{
+				Error: fmt.Sprintf("no trace found for transaction %s", txID),
+			})
+			return
+		}
+		resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "trace-"+txID+".json"))
+		h.sendResponse(resp, http.StatusOK, traces)
+
+	case http.MethodPut:
+		sampleRate, err := strconv.ParseFloat(query.Get("sample_rate"), 64)
+		if err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+				Error: fmt.Sprintf("invalid sample_rate query parameter: %q", query.Get("sample_rate")),
+			})
+			return
+		}
+		if err := h.Recorder.Enable(chaincode, sampleRate); err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, &errorResponse{Error: err.Error()})
+			return
+		}
+		resp.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if chaincode == "" {
+			h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+				Error: "missing chaincode query parameter",
+			})
+			return
+		}
+		h.Recorder.Disable(chaincode)
+		resp.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+	}
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
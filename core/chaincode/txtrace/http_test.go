@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txtrace
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	r := NewRecorder(10)
+	h := NewHandler(r)
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(method, target, nil))
+		return resp
+	}
+
+	resp := serve(http.MethodPut, "/chaincode/traces?chaincode=mycc&sample_rate=1")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, []Settings{{Chaincode: "mycc", SampleRate: 1}}, r.Settings())
+
+	resp = serve(http.MethodPut, "/chaincode/traces?chaincode=mycc&sample_rate=abc")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid sample_rate query parameter: \"abc\""}`, resp.Body.String())
+
+	resp = serve(http.MethodPut, "/chaincode/traces?sample_rate=0.5")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"chaincode name must be specified"}`, resp.Body.String())
+
+	r.Start("mycc", transaction("tx1", "fn"))
+
+	resp = serve(http.MethodGet, "/chaincode/traces")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	status := &statusResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), status))
+	assert.Equal(t, []Settings{{Chaincode: "mycc", SampleRate: 1}}, status.Settings)
+	require.Len(t, status.Traces, 1)
+	assert.Equal(t, "tx1", status.Traces[0].TxID)
+
+	resp = serve(http.MethodGet, "/chaincode/traces?txid=tx1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `attachment; filename="trace-tx1.json"`, resp.Header().Get("Content-Disposition"))
+	var traces []Trace
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &traces))
+	require.Len(t, traces, 1)
+	assert.Equal(t, []string{sm3Hex("fn")}, traces[0].ArgHashes)
+
+	resp = serve(http.MethodGet, "/chaincode/traces?txid=tx2")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"error":"no trace found for transaction tx2"}`, resp.Body.String())
+
+	resp = serve(http.MethodDelete, "/chaincode/traces")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"missing chaincode query parameter"}`, resp.Body.String())
+
+	resp = serve(http.MethodDelete, "/chaincode/traces?chaincode=mycc")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, r.Settings())
+
+	resp = serve(http.MethodPost, "/chaincode/traces")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: POST"}`, resp.Body.String())
+}
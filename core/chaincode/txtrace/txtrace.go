@@ -0,0 +1,386 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txtrace
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("chaincode.txtrace")
+
+// DefaultMaxTraces is the number of traces kept per chaincode when no
+// limit is configured.
+const DefaultMaxTraces = 100
+
+// Settings are the tracing settings of a chaincode.
+type Settings struct {
+	Chaincode  string  `json:"chaincode"`
+	SampleRate float64 `json:"sample_rate"`
+}
+
+// Event is a shim message sent by the chaincode while executing a traced
+// transaction. Values and arguments are recorded as SM3 hashes only, so
+// that the traces can be shared without disclosing the application data.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	Collection string    `json:"collection,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	EndKey     string    `json:"end_key,omitempty"`
+	QueryHash  string    `json:"query_hash,omitempty"`
+	IteratorID string    `json:"iterator_id,omitempty"`
+	ValueHash  string    `json:"value_hash,omitempty"`
+	ValueSize  int       `json:"value_size,omitempty"`
+	Chaincode  string    `json:"chaincode,omitempty"`
+	ArgHashes  []string  `json:"arg_hashes,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Trace is the execution trace of a transaction by a chaincode.
+type Trace struct {
+	ChannelID string    `json:"channel"`
+	TxID      string    `json:"txid"`
+	Chaincode string    `json:"chaincode"`
+	Type      string    `json:"type"`
+	ArgHashes []string  `json:"arg_hashes"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Completed bool      `json:"completed"`
+	Status    int32     `json:"status,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Events    []Event   `json:"events"`
+}
+
+// Summary describes a trace without its events.
+type Summary struct {
+	ChannelID string    `json:"channel"`
+	TxID      string    `json:"txid"`
+	Chaincode string    `json:"chaincode"`
+	StartTime time.Time `json:"start_time"`
+	Completed bool      `json:"completed"`
+	Status    int32     `json:"status,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Events    int       `json:"events"`
+}
+
+// Execution records the trace of a transaction while it is executed.
+// A nil Execution records nothing, so that callers need not check whether
+// the transaction is traced.
+type Execution struct {
+	mutex sync.Mutex
+	trace Trace
+}
+
+// Record adds the given shim request of the chaincode, along with the
+// response of the peer, to the trace.
+func (e *Execution) Record(msg, resp *pb.ChaincodeMessage) {
+	if e == nil {
+		return
+	}
+
+	event := Event{Time: time.Now(), Type: msg.Type.String()}
+	if err := decodeRequest(&event, msg); err != nil {
+		event.Error = err.Error()
+	}
+	if resp != nil {
+		switch resp.Type {
+		case pb.ChaincodeMessage_ERROR:
+			event.Error = string(resp.Payload)
+		case pb.ChaincodeMessage_RESPONSE:
+			if msg.Type == pb.ChaincodeMessage_GET_STATE || msg.Type == pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH {
+				event.ValueHash, event.ValueSize = hashValue(resp.Payload)
+			}
+		}
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.trace.Events = append(e.trace.Events, event)
+}
+
+// Finish completes the trace with the response of the chaincode or the
+// error that ended the execution.
+func (e *Execution) Finish(resp *pb.ChaincodeMessage, err error) {
+	if e == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.trace.EndTime = time.Now()
+	e.trace.Completed = true
+	switch {
+	case err != nil:
+		e.trace.Error = err.Error()
+	case resp == nil:
+		e.trace.Error = "no response from chaincode"
+	case resp.Type == pb.ChaincodeMessage_COMPLETED:
+		res := &pb.Response{}
+		if err := proto.Unmarshal(resp.Payload, res); err != nil {
+			e.trace.Error = errors.Wrap(err, "failed to unmarshal chaincode response").Error()
+			return
+		}
+		e.trace.Status = res.Status
+		e.trace.Message = res.Message
+	default:
+		e.trace.Error = string(resp.Payload)
+	}
+}
+
+func (e *Execution) snapshot() Trace {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	t := e.trace
+	t.Events = append([]Event(nil), e.trace.Events...)
+	return t
+}
+
+// Recorder samples the transactions of the chaincodes tracing is enabled
+// for, and keeps their most recent traces.
+type Recorder struct {
+	maxTraces int
+	random    func() float64
+
+	mutex    sync.Mutex
+	settings map[string]float64
+	traces   map[string][]*Execution
+}
+
+// NewRecorder creates a Recorder which keeps up to maxTraces traces per
+// chaincode.
+func NewRecorder(maxTraces int) *Recorder {
+	if maxTraces <= 0 {
+		maxTraces = DefaultMaxTraces
+	}
+	return &Recorder{
+		maxTraces: maxTraces,
+		random:    rand.Float64,
+		settings:  map[string]float64{},
+		traces:    map[string][]*Execution{},
+	}
+}
+
+// Enable enables the tracing of the given proportion of the transactions
+// executed by the chaincode.
+func (r *Recorder) Enable(chaincode string, sampleRate float64) error {
+	if chaincode == "" {
+		return errors.New("chaincode name must be specified")
+	}
+	if sampleRate <= 0 || sampleRate > 1 {
+		return errors.Errorf("sample rate must be greater than 0 and at most 1, got %v", sampleRate)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.settings[chaincode] = sampleRate
+	logger.Infof("Enabled execution tracing of chaincode %s with sample rate %v", chaincode, sampleRate)
+	return nil
+}
+
+// Disable disables the tracing of the chaincode. The traces recorded so far
+// are kept.
+func (r *Recorder) Disable(chaincode string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.settings[chaincode]; ok {
+		delete(r.settings, chaincode)
+		logger.Infof("Disabled execution tracing of chaincode %s", chaincode)
+	}
+}
+
+// Settings returns the tracing settings of the chaincodes, sorted by name.
+func (r *Recorder) Settings() []Settings {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	settings := []Settings{}
+	for cc, rate := range r.settings {
+		settings = append(settings, Settings{Chaincode: cc, SampleRate: rate})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Chaincode < settings[j].Chaincode })
+	return settings
+}
+
+// Start starts the trace of the transaction or chaincode initialization in
+// msg, if tracing is enabled for the chaincode and the transaction is
+// sampled. Otherwise it returns nil.
+func (r *Recorder) Start(chaincode string, msg *pb.ChaincodeMessage) *Execution {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rate, ok := r.settings[chaincode]
+	if !ok || r.random() >= rate {
+		return nil
+	}
+
+	e := &Execution{
+		trace: Trace{
+			ChannelID: msg.ChannelId,
+			TxID:      msg.Txid,
+			Chaincode: chaincode,
+			Type:      msg.Type.String(),
+			ArgHashes: []string{},
+			StartTime: time.Now(),
+			Events:    []Event{},
+		},
+	}
+	input := &pb.ChaincodeInput{}
+	if err := proto.Unmarshal(msg.Payload, input); err == nil {
+		e.trace.ArgHashes = hashArgs(input.Args)
+	}
+
+	traces := append(r.traces[chaincode], e)
+	if len(traces) > r.maxTraces {
+		traces = append([]*Execution(nil), traces[len(traces)-r.maxTraces:]...)
+	}
+	r.traces[chaincode] = traces
+	return e
+}
+
+// Summaries returns the summaries of the traces kept for the chaincodes,
+// the most recent first. An empty chaincode name selects all chaincodes.
+func (r *Recorder) Summaries(chaincode string) []Summary {
+	summaries := []Summary{}
+	for _, t := range r.snapshots(chaincode) {
+		summaries = append(summaries, Summary{
+			ChannelID: t.ChannelID,
+			TxID:      t.TxID,
+			Chaincode: t.Chaincode,
+			StartTime: t.StartTime,
+			Completed: t.Completed,
+			Status:    t.Status,
+			Error:     t.Error,
+			Events:    len(t.Events),
+		})
+	}
+	return summaries
+}
+
+// Traces returns the traces of the given transaction. As a chaincode may
+// invoke other chaincodes, there may be a trace per chaincode. An empty
+// chaincode name selects all chaincodes.
+func (r *Recorder) Traces(txID, chaincode string) []Trace {
+	traces := []Trace{}
+	for _, t := range r.snapshots(chaincode) {
+		if t.TxID == txID {
+			traces = append(traces, t)
+		}
+	}
+	return traces
+}
+
+func (r *Recorder) snapshots(chaincode string) []Trace {
+	r.mutex.Lock()
+	var executions []*Execution
+	for cc, ccExecutions := range r.traces {
+		if chaincode == "" || cc == chaincode {
+			executions = append(executions, ccExecutions...)
+		}
+	}
+	r.mutex.Unlock()
+
+	traces := make([]Trace, 0, len(executions))
+	for _, e := range executions {
+		traces = append(traces, e.snapshot())
+	}
+	sort.SliceStable(traces, func(i, j int) bool { return traces[i].StartTime.After(traces[j].StartTime) })
+	return traces
+}
+
+func decodeRequest(event *Event, msg *pb.ChaincodeMessage) error {
+	var err error
+	switch msg.Type {
+	case pb.ChaincodeMessage_GET_STATE, pb.ChaincodeMessage_GET_PRIVATE_DATA_HASH:
+		req := &pb.GetState{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Collection, event.Key = req.Collection, req.Key
+		}
+	case pb.ChaincodeMessage_PUT_STATE:
+		req := &pb.PutState{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Collection, event.Key = req.Collection, req.Key
+			event.ValueHash, event.ValueSize = hashValue(req.Value)
+		}
+	case pb.ChaincodeMessage_DEL_STATE:
+		req := &pb.DelState{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Collection, event.Key = req.Collection, req.Key
+		}
+	case pb.ChaincodeMessage_GET_STATE_METADATA:
+		req := &pb.GetStateMetadata{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Collection, event.Key = req.Collection, req.Key
+		}
+	case pb.ChaincodeMessage_PUT_STATE_METADATA:
+		req := &pb.PutStateMetadata{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Collection, event.Key = req.Collection, req.Key
+			if req.Metadata != nil {
+				event.ValueHash, event.ValueSize = hashValue(req.Metadata.Value)
+			}
+		}
+	case pb.ChaincodeMessage_GET_STATE_BY_RANGE:
+		req := &pb.GetStateByRange{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Collection, event.Key, event.EndKey = req.Collection, req.StartKey, req.EndKey
+		}
+	case pb.ChaincodeMessage_GET_QUERY_RESULT:
+		req := &pb.GetQueryResult{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Collection = req.Collection
+			event.QueryHash, _ = hashValue([]byte(req.Query))
+		}
+	case pb.ChaincodeMessage_GET_HISTORY_FOR_KEY:
+		req := &pb.GetHistoryForKey{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Key = req.Key
+		}
+	case pb.ChaincodeMessage_QUERY_STATE_NEXT:
+		req := &pb.QueryStateNext{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.IteratorID = req.Id
+		}
+	case pb.ChaincodeMessage_QUERY_STATE_CLOSE:
+		req := &pb.QueryStateClose{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.IteratorID = req.Id
+		}
+	case pb.ChaincodeMessage_INVOKE_CHAINCODE:
+		req := &pb.ChaincodeSpec{}
+		if err = proto.Unmarshal(msg.Payload, req); err == nil {
+			event.Chaincode = req.GetChaincodeId().GetName()
+			event.ArgHashes = hashArgs(req.GetInput().GetArgs())
+		}
+	}
+	return errors.Wrapf(err, "failed to unmarshal %s request", msg.Type)
+}
+
+func hashArgs(args [][]byte) []string {
+	hashes := make([]string, 0, len(args))
+	for _, arg := range args {
+		hash, _ := hashValue(arg)
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+func hashValue(value []byte) (string, int) {
+	return hex.EncodeToString(sm3.SumSM3(value)), len(value)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txtrace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sm3Hex(value string) string {
+	return hex.EncodeToString(sm3.SumSM3([]byte(value)))
+}
+
+func transaction(txID string, args ...string) *pb.ChaincodeMessage {
+	input := &pb.ChaincodeInput{}
+	for _, arg := range args {
+		input.Args = append(input.Args, []byte(arg))
+	}
+	return &pb.ChaincodeMessage{
+		Type:      pb.ChaincodeMessage_TRANSACTION,
+		Txid:      txID,
+		ChannelId: "mychannel",
+		Payload:   protoutil.MarshalOrPanic(input),
+	}
+}
+
+func TestEnable(t *testing.T) {
+	r := NewRecorder(10)
+
+	require.NoError(t, r.Enable("cc2", 0.5))
+	require.NoError(t, r.Enable("cc1", 1))
+	assert.Equal(t, []Settings{{Chaincode: "cc1", SampleRate: 1}, {Chaincode: "cc2", SampleRate: 0.5}}, r.Settings())
+
+	r.Disable("cc2")
+	r.Disable("cc3")
+	assert.Equal(t, []Settings{{Chaincode: "cc1", SampleRate: 1}}, r.Settings())
+
+	assert.EqualError(t, r.Enable("", 1), "chaincode name must be specified")
+	assert.EqualError(t, r.Enable("cc1", 0), "sample rate must be greater than 0 and at most 1, got 0")
+	assert.EqualError(t, r.Enable("cc1", 1.5), "sample rate must be greater than 0 and at most 1, got 1.5")
+}
+
+func TestStart(t *testing.T) {
+	r := NewRecorder(10)
+	assert.Nil(t, r.Start("cc1", transaction("tx1")), "tracing not enabled")
+
+	require.NoError(t, r.Enable("cc1", 0.5))
+	r.random = func() float64 { return 0.7 }
+	assert.Nil(t, r.Start("cc1", transaction("tx1")), "transaction not sampled")
+	r.random = func() float64 { return 0.2 }
+	assert.NotNil(t, r.Start("cc1", transaction("tx1", "fn", "arg")))
+
+	traces := r.Traces("tx1", "cc1")
+	require.Len(t, traces, 1)
+	assert.Equal(t, "mychannel", traces[0].ChannelID)
+	assert.Equal(t, "TRANSACTION", traces[0].Type)
+	assert.Equal(t, []string{sm3Hex("fn"), sm3Hex("arg")}, traces[0].ArgHashes)
+	assert.False(t, traces[0].Completed)
+
+	r.Disable("cc1")
+	assert.Nil(t, r.Start("cc1", transaction("tx2")))
+	assert.Len(t, r.Traces("tx1", ""), 1, "traces are kept once disabled")
+
+	var nilRecorder *Recorder
+	assert.Nil(t, nilRecorder.Start("cc1", transaction("tx1")))
+}
+
+func TestMaxTraces(t *testing.T) {
+	r := NewRecorder(3)
+	require.NoError(t, r.Enable("cc1", 1))
+	require.NoError(t, r.Enable("cc2", 1))
+	for i := 0; i < 5; i++ {
+		r.Start("cc1", transaction(fmt.Sprintf("tx%d", i)))
+	}
+	r.Start("cc2", transaction("tx0"))
+
+	var txIDs []string
+	for _, s := range r.Summaries("cc1") {
+		txIDs = append(txIDs, s.TxID)
+	}
+	assert.ElementsMatch(t, []string{"tx2", "tx3", "tx4"}, txIDs)
+	assert.Len(t, r.Summaries(""), 4)
+	assert.Len(t, r.Traces("tx0", ""), 1)
+	assert.Empty(t, r.Traces("tx0", "cc1"))
+}
+
+func TestRecord(t *testing.T) {
+	r := NewRecorder(10)
+	require.NoError(t, r.Enable("cc1", 1))
+	e := r.Start("cc1", transaction("tx1"))
+
+	e.Record(
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE, Payload: protoutil.MarshalOrPanic(&pb.GetState{Key: "k1"})},
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: []byte("v1")},
+	)
+	e.Record(
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_PUT_STATE, Payload: protoutil.MarshalOrPanic(&pb.PutState{Key: "k2", Value: []byte("value"), Collection: "coll"})},
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte("put failed")},
+	)
+	e.Record(
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_QUERY_RESULT, Payload: protoutil.MarshalOrPanic(&pb.GetQueryResult{Query: "query"})},
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE},
+	)
+	e.Record(
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_INVOKE_CHAINCODE, Payload: protoutil.MarshalOrPanic(&pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: "cc2"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("fn")}},
+		})},
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE},
+	)
+	e.Record(
+		&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_DEL_STATE, Payload: []byte("garbage")},
+		nil,
+	)
+
+	traces := r.Traces("tx1", "")
+	require.Len(t, traces, 1)
+	events := traces[0].Events
+	require.Len(t, events, 5)
+
+	assert.Equal(t, "GET_STATE", events[0].Type)
+	assert.Equal(t, "k1", events[0].Key)
+	assert.Equal(t, sm3Hex("v1"), events[0].ValueHash)
+	assert.Equal(t, 2, events[0].ValueSize)
+
+	assert.Equal(t, "PUT_STATE", events[1].Type)
+	assert.Equal(t, "k2", events[1].Key)
+	assert.Equal(t, "coll", events[1].Collection)
+	assert.Equal(t, sm3Hex("value"), events[1].ValueHash)
+	assert.Equal(t, 5, events[1].ValueSize)
+	assert.Equal(t, "put failed", events[1].Error)
+
+	assert.Equal(t, sm3Hex("query"), events[2].QueryHash)
+
+	assert.Equal(t, "cc2", events[3].Chaincode)
+	assert.Equal(t, []string{sm3Hex("fn")}, events[3].ArgHashes)
+
+	assert.Equal(t, "DEL_STATE", events[4].Type)
+	assert.Contains(t, events[4].Error, "failed to unmarshal DEL_STATE request")
+
+	var nilExecution *Execution
+	nilExecution.Record(&pb.ChaincodeMessage{}, nil)
+	nilExecution.Finish(nil, nil)
+}
+
+func TestFinish(t *testing.T) {
+	r := NewRecorder(10)
+	require.NoError(t, r.Enable("cc1", 1))
+
+	r.Start("cc1", transaction("tx1")).Finish(&pb.ChaincodeMessage{
+		Type:    pb.ChaincodeMessage_COMPLETED,
+		Payload: protoutil.MarshalOrPanic(&pb.Response{Status: 200, Message: "ok"}),
+	}, nil)
+	r.Start("cc1", transaction("tx2")).Finish(&pb.ChaincodeMessage{
+		Type:    pb.ChaincodeMessage_ERROR,
+		Payload: []byte("chaincode error"),
+	}, nil)
+	r.Start("cc1", transaction("tx3")).Finish(nil, errors.New("timeout expired while executing transaction"))
+
+	tx1 := r.Traces("tx1", "")[0]
+	assert.True(t, tx1.Completed)
+	assert.Equal(t, int32(200), tx1.Status)
+	assert.Equal(t, "ok", tx1.Message)
+	assert.False(t, tx1.EndTime.Before(tx1.StartTime))
+	assert.Equal(t, "chaincode error", r.Traces("tx2", "")[0].Error)
+	assert.Equal(t, "timeout expired while executing transaction", r.Traces("tx3", "")[0].Error)
+}
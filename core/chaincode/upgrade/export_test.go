@@ -0,0 +1,17 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package upgrade
+
+import "time"
+
+// SetInitPollInterval sets the interval at which the initialization of the
+// chaincode definitions is checked, and returns a function restoring it.
+func SetInitPollInterval(interval time.Duration) func() {
+	previous := initPollInterval
+	initPollInterval = interval
+	return func() { initPollInterval = previous }
+}
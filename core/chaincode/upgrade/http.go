@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler reports the chaincode upgrades of the peer through the operations
+// endpoint.
+type Handler struct {
+	Coordinator *Coordinator
+}
+
+// NewHandler returns a Handler for the given Coordinator.
+func NewHandler(c *Coordinator) *Handler {
+	return &Handler{Coordinator: c}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+
+	h.sendResponse(resp, http.StatusOK, h.Coordinator.Statuses())
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package upgrade
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("chaincode.upgrade")
+
+// State is the progress of the upgrade of a chaincode on this peer.
+type State string
+
+const (
+	// Launching is the state of an upgrade whose new chaincode package is
+	// being launched ahead of the invocations of the new definition.
+	Launching State = "launching"
+	// Initializing is the state of an upgrade whose new chaincode package is
+	// running, while the Init transaction the new definition requires has
+	// not been committed yet.
+	Initializing State = "initializing"
+	// Draining is the state of an upgrade whose new chaincode package is
+	// ready, while the in-flight invocations of the previous package
+	// complete.
+	Draining State = "draining"
+	// Completed is the state of an upgrade whose previous chaincode package
+	// has been stopped.
+	Completed State = "completed"
+	// Failed is the state of an upgrade whose new chaincode package could
+	// not be launched ahead of its invocations, which launch it on demand
+	// as they would without coordination.
+	Failed State = "failed"
+)
+
+// initPollInterval is the interval at which the initialization of the
+// definition of a chaincode is checked while its upgrade awaits it.
+var initPollInterval = time.Second
+
+// Status reports the upgrade of a chaincode on a channel of this peer.
+type Status struct {
+	ChannelID         string     `json:"channel_id"`
+	ChaincodeName     string     `json:"chaincode_name"`
+	PreviousPackageID string     `json:"previous_package_id,omitempty"`
+	PackageID         string     `json:"package_id"`
+	State             State      `json:"state"`
+	Error             string     `json:"error,omitempty"`
+	Started           time.Time  `json:"started"`
+	Finished          *time.Time `json:"finished,omitempty"`
+}
+
+//go:generate counterfeiter -o mock/runtime.go -fake-name Runtime . Runtime
+
+// Runtime launches and stops chaincode runtimes.
+type Runtime interface {
+	// Launch starts the chaincode runtime, if it is not already running, and
+	// returns once it has registered with the peer.
+	Launch(ccid string) error
+	// Stop stops the chaincode runtime.
+	Stop(ccid string) error
+	// Running reports whether the chaincode runtime is registered with the
+	// peer and ready to serve invocations.
+	Running(ccid string) bool
+}
+
+//go:generate counterfeiter -o mock/init_state.go -fake-name InitState . InitState
+
+// InitState reports whether chaincode definitions are initialized.
+type InitState interface {
+	// Initialized reports whether the committed definition of the chaincode
+	// on the channel does not require initialization, or its Init
+	// transaction has been committed.
+	Initialized(channelID, chaincodeName string) (bool, error)
+}
+
+type key struct {
+	channelID     string
+	chaincodeName string
+}
+
+// route records the chaincode package of the definition of a chaincode on
+// a channel, and the package of a newly committed definition which is not
+// ready yet.
+type route struct {
+	active  string
+	pending string
+}
+
+// Coordinator orchestrates the switchover of a chaincode to the package of
+// a newly committed definition. The invocations are always executed by the
+// package of the definition they were simulated against, so that all the
+// peers endorse with the same code. The Coordinator launches the new package
+// as soon as its definition is committed, instead of on the first invocation,
+// considers it ready once it is running and the Init transaction its
+// definition requires has been committed, then stops the previous package
+// after its in-flight invocations have drained.
+type Coordinator struct {
+	runtime      Runtime
+	initState    InitState
+	drainTimeout time.Duration
+
+	mutex    sync.Mutex
+	routes   map[key]*route
+	deploys  map[string]map[string]string
+	inFlight map[string]int
+	idle     map[string]chan struct{}
+	statuses map[key]*Status
+}
+
+// NewCoordinator creates a Coordinator launching and stopping chaincodes
+// with the given runtime. The previous package of a chaincode is stopped
+// once its in-flight invocations complete or drainTimeout expires.
+func NewCoordinator(runtime Runtime, initState InitState, drainTimeout time.Duration) *Coordinator {
+	return &Coordinator{
+		runtime:      runtime,
+		initState:    initState,
+		drainTimeout: drainTimeout,
+		routes:       map[key]*route{},
+		deploys:      map[string]map[string]string{},
+		inFlight:     map[string]int{},
+		idle:         map[string]chan struct{}{},
+		statuses:     map[key]*Status{},
+	}
+}
+
+// Route records an invocation of a chaincode on a channel, executed by the
+// package of the definition it was simulated against, and returns a function
+// to be called once the invocation completes.
+func (c *Coordinator) Route(channelID, chaincodeName, ccid string) func() {
+	k := key{channelID: channelID, chaincodeName: chaincodeName}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	r, ok := c.routes[k]
+	if !ok {
+		r = &route{active: ccid}
+		c.routes[k] = r
+	}
+	if r.active != ccid && r.pending != ccid {
+		// the definition changed without this coordinator being notified
+		r.active = ccid
+	}
+
+	c.inFlight[ccid]++
+	return func() { c.release(ccid) }
+}
+
+func (c *Coordinator) release(ccid string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.inFlight[ccid]--
+	if c.inFlight[ccid] > 0 {
+		return
+	}
+	delete(c.inFlight, ccid)
+	if idle, ok := c.idle[ccid]; ok {
+		close(idle)
+		delete(c.idle, ccid)
+	}
+}
+
+// idleWhileLocked returns a channel closed once the package has no
+// invocation in flight.
+func (c *Coordinator) idleWhileLocked(ccid string) <-chan struct{} {
+	if c.inFlight[ccid] == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle
+	}
+	idle, ok := c.idle[ccid]
+	if !ok {
+		idle = make(chan struct{})
+		c.idle[ccid] = idle
+	}
+	return idle
+}
+
+// ChannelListener returns the listener to register for the chaincode
+// lifecycle events of the given channel.
+func (c *Coordinator) ChannelListener(channelID string) ledger.ChaincodeLifecycleEventListener {
+	return &channelListener{coordinator: c, channelID: channelID}
+}
+
+type channelListener struct {
+	coordinator *Coordinator
+	channelID   string
+}
+
+// HandleChaincodeDeploy records the chaincode which became invokable. Its
+// upgrade starts once the event is committed.
+func (l *channelListener) HandleChaincodeDeploy(chaincodeDefinition *ledger.ChaincodeDefinition, dbArtifactsTar []byte) error {
+	c := l.coordinator
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	deploys, ok := c.deploys[l.channelID]
+	if !ok {
+		deploys = map[string]string{}
+		c.deploys[l.channelID] = deploys
+	}
+	deploys[chaincodeDefinition.Name] = string(chaincodeDefinition.Hash)
+	return nil
+}
+
+// ChaincodeDeployDone starts the upgrade of the chaincodes which became
+// invokable.
+func (l *channelListener) ChaincodeDeployDone(succeeded bool) {
+	c := l.coordinator
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	deploys := c.deploys[l.channelID]
+	delete(c.deploys, l.channelID)
+	if !succeeded {
+		return
+	}
+	for chaincodeName, ccid := range deploys {
+		c.startUpgradeWhileLocked(key{channelID: l.channelID, chaincodeName: chaincodeName}, ccid)
+	}
+}
+
+func (c *Coordinator) startUpgradeWhileLocked(k key, ccid string) {
+	r, ok := c.routes[k]
+	if !ok {
+		r = &route{}
+		c.routes[k] = r
+	}
+	if r.active == ccid || r.pending == ccid {
+		return
+	}
+
+	r.pending = ccid
+	c.statuses[k] = &Status{
+		ChannelID:         k.channelID,
+		ChaincodeName:     k.chaincodeName,
+		PreviousPackageID: r.active,
+		PackageID:         ccid,
+		State:             Launching,
+		Started:           time.Now(),
+	}
+
+	logger.Infof("Launching chaincode package %s for chaincode %s on channel %s", ccid, k.chaincodeName, k.channelID)
+	go c.upgrade(k, r.active, ccid)
+}
+
+func (c *Coordinator) upgrade(k key, previous, ccid string) {
+	err := c.runtime.Launch(ccid)
+	if err == nil && !c.runtime.Running(ccid) {
+		err = errors.Errorf("chaincode %s is not ready after launch", ccid)
+	}
+	if err == nil {
+		err = c.awaitInit(k, ccid)
+	}
+
+	c.mutex.Lock()
+	r := c.routes[k]
+	if r.pending != ccid {
+		// superseded by a more recent definition
+		c.mutex.Unlock()
+		return
+	}
+	r.pending = ""
+	r.active = ccid
+	status := c.statuses[k]
+	if err != nil {
+		c.finishWhileLocked(status, Failed, err)
+		c.mutex.Unlock()
+		logger.Errorf("Failed to launch chaincode package %s for chaincode %s on channel %s: %s", ccid, k.chaincodeName, k.channelID, err)
+		return
+	}
+	status.State = Draining
+	idle := c.idleWhileLocked(previous)
+	c.mutex.Unlock()
+
+	logger.Infof("Chaincode package %s of chaincode %s on channel %s is ready", ccid, k.chaincodeName, k.channelID)
+
+	if previous != "" {
+		select {
+		case <-idle:
+		case <-time.After(c.drainTimeout):
+			logger.Warningf("Timed out waiting for the in-flight invocations of chaincode package %s to complete", previous)
+		}
+		if c.inUse(previous) {
+			logger.Infof("Not stopping chaincode package %s, it still serves other chaincodes", previous)
+		} else if err := c.runtime.Stop(previous); err != nil {
+			logger.Warningf("Failed to stop chaincode package %s: %s", previous, err)
+		}
+	}
+
+	c.mutex.Lock()
+	c.finishWhileLocked(status, Completed, nil)
+	c.mutex.Unlock()
+}
+
+// awaitInit waits until the Init transaction the definition of the package
+// requires has been committed, or the upgrade is superseded by a more
+// recent definition.
+func (c *Coordinator) awaitInit(k key, ccid string) error {
+	if c.initState == nil {
+		return nil
+	}
+
+	for {
+		initialized, err := c.initState.Initialized(k.channelID, k.chaincodeName)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to determine whether chaincode %s is initialized", k.chaincodeName)
+		}
+		if initialized {
+			return nil
+		}
+
+		c.mutex.Lock()
+		superseded := c.routes[k].pending != ccid
+		if !superseded && c.statuses[k].State != Initializing {
+			c.statuses[k].State = Initializing
+			logger.Infof("Chaincode package %s of chaincode %s on channel %s awaits the commit of its Init transaction", ccid, k.chaincodeName, k.channelID)
+		}
+		c.mutex.Unlock()
+		if superseded {
+			return nil
+		}
+		time.Sleep(initPollInterval)
+	}
+}
+
+func (c *Coordinator) finishWhileLocked(status *Status, state State, err error) {
+	now := time.Now()
+	status.State = state
+	status.Finished = &now
+	if err != nil {
+		status.Error = err.Error()
+	}
+}
+
+// inUse reports whether the package serves, or is about to serve, a
+// chaincode on any channel.
+func (c *Coordinator) inUse(ccid string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, r := range c.routes {
+		if r.active == ccid || r.pending == ccid {
+			return true
+		}
+	}
+	return false
+}
+
+// Statuses returns the status of the last upgrade of each chaincode, sorted
+// by channel and chaincode name.
+func (c *Coordinator) Statuses() []Status {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	statuses := make([]Status, 0, len(c.statuses))
+	for _, status := range c.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].ChannelID != statuses[j].ChannelID {
+			return statuses[i].ChannelID < statuses[j].ChannelID
+		}
+		return statuses[i].ChaincodeName < statuses[j].ChaincodeName
+	})
+	return statuses
+}
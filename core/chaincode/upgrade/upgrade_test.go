@@ -0,0 +1,263 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package upgrade_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/upgrade"
+	"github.com/hyperledger/fabric/core/chaincode/upgrade/mock"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRuntime tracks the running packages and lets the tests control when a
+// launch completes.
+type fakeRuntime struct {
+	*mock.Runtime
+
+	mutex    sync.Mutex
+	running  map[string]bool
+	launches chan string
+	release  chan error
+}
+
+func newFakeRuntime(running ...string) *fakeRuntime {
+	f := &fakeRuntime{
+		Runtime:  &mock.Runtime{},
+		running:  map[string]bool{},
+		launches: make(chan string, 10),
+		release:  make(chan error),
+	}
+	for _, ccid := range running {
+		f.running[ccid] = true
+	}
+	f.LaunchStub = func(ccid string) error {
+		f.launches <- ccid
+		if err := <-f.release; err != nil {
+			return err
+		}
+		f.mutex.Lock()
+		f.running[ccid] = true
+		f.mutex.Unlock()
+		return nil
+	}
+	f.RunningStub = func(ccid string) bool {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		return f.running[ccid]
+	}
+	f.StopStub = func(ccid string) error {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		delete(f.running, ccid)
+		return nil
+	}
+	return f
+}
+
+func commitDefinition(c *upgrade.Coordinator, channelID, chaincodeName, ccid string) {
+	listener := c.ChannelListener(channelID)
+	listener.HandleChaincodeDeploy(&ledger.ChaincodeDefinition{Name: chaincodeName, Hash: []byte(ccid)}, nil)
+	listener.ChaincodeDeployDone(true)
+}
+
+func waitForState(t *testing.T, c *upgrade.Coordinator, state upgrade.State) upgrade.Status {
+	var status upgrade.Status
+	require.Eventually(t, func() bool {
+		statuses := c.Statuses()
+		if len(statuses) != 1 {
+			return false
+		}
+		status = statuses[0]
+		return status.State == state
+	}, 5*time.Second, 10*time.Millisecond)
+	return status
+}
+
+func TestUpgrade(t *testing.T) {
+	runtime := newFakeRuntime("mycc:v1")
+	c := upgrade.NewCoordinator(runtime, nil, time.Minute)
+
+	done := c.Route("mychannel", "mycc", "mycc:v1")
+	done2 := c.Route("mychannel", "mycc", "mycc:v1")
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v2")
+	assert.Equal(t, "mycc:v2", <-runtime.launches)
+	status := waitForState(t, c, upgrade.Launching)
+	assert.Equal(t, "mycc:v1", status.PreviousPackageID)
+	assert.Equal(t, "mycc:v2", status.PackageID)
+
+	// the invocations of the new definition are executed by the new
+	// package, even while it launches
+	done3 := c.Route("mychannel", "mycc", "mycc:v2")
+	done3()
+
+	runtime.release <- nil
+	waitForState(t, c, upgrade.Draining)
+
+	// the previous package is stopped once its invocations complete
+	done()
+	assert.Equal(t, 0, runtime.StopCallCount())
+	done2()
+	status = waitForState(t, c, upgrade.Completed)
+	assert.NotNil(t, status.Finished)
+	require.Equal(t, 1, runtime.StopCallCount())
+	assert.Equal(t, "mycc:v1", runtime.StopArgsForCall(0))
+}
+
+func TestUpgradeDrainTimeout(t *testing.T) {
+	runtime := newFakeRuntime("mycc:v1")
+	c := upgrade.NewCoordinator(runtime, nil, 10*time.Millisecond)
+
+	done := c.Route("mychannel", "mycc", "mycc:v1")
+	defer done()
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v2")
+	<-runtime.launches
+	runtime.release <- nil
+	waitForState(t, c, upgrade.Completed)
+	require.Equal(t, 1, runtime.StopCallCount())
+	assert.Equal(t, "mycc:v1", runtime.StopArgsForCall(0))
+}
+
+func TestUpgradePreviousPackageInUse(t *testing.T) {
+	runtime := newFakeRuntime("mycc:v1")
+	c := upgrade.NewCoordinator(runtime, nil, time.Minute)
+
+	c.Route("mychannel", "mycc", "mycc:v1")()
+	c.Route("otherchannel", "mycc", "mycc:v1")()
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v2")
+	<-runtime.launches
+	runtime.release <- nil
+	waitForState(t, c, upgrade.Completed)
+	assert.Equal(t, 0, runtime.StopCallCount())
+
+	c.Route("otherchannel", "mycc", "mycc:v1")()
+}
+
+func TestUpgradeLaunchFailure(t *testing.T) {
+	runtime := newFakeRuntime("mycc:v1")
+	c := upgrade.NewCoordinator(runtime, nil, time.Minute)
+
+	c.Route("mychannel", "mycc", "mycc:v1")()
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v2")
+	<-runtime.launches
+	runtime.release <- errors.New("build failed")
+	status := waitForState(t, c, upgrade.Failed)
+	assert.Equal(t, "build failed", status.Error)
+	assert.Equal(t, 0, runtime.StopCallCount())
+}
+
+func TestUpgradeWithoutPreviousPackage(t *testing.T) {
+	runtime := newFakeRuntime()
+	c := upgrade.NewCoordinator(runtime, nil, time.Minute)
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v1")
+	<-runtime.launches
+
+	// nothing is running, the invocation launches the package itself
+	c.Route("mychannel", "mycc", "mycc:v1")()
+
+	runtime.release <- nil
+	status := waitForState(t, c, upgrade.Completed)
+	assert.Empty(t, status.PreviousPackageID)
+	assert.Equal(t, 0, runtime.StopCallCount())
+}
+
+func TestUpgradeAwaitsInit(t *testing.T) {
+	defer upgrade.SetInitPollInterval(time.Millisecond)()
+
+	runtime := newFakeRuntime("mycc:v1")
+	initState := &mock.InitState{}
+	initialized := make(chan bool)
+	initState.InitializedStub = func(channelID, chaincodeName string) (bool, error) {
+		return <-initialized, nil
+	}
+	c := upgrade.NewCoordinator(runtime, initState, time.Minute)
+	c.Route("mychannel", "mycc", "mycc:v1")()
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v2")
+	<-runtime.launches
+	runtime.release <- nil
+
+	// the new package is not ready until the Init transaction its definition
+	// requires has been committed
+	initialized <- false
+	waitForState(t, c, upgrade.Initializing)
+	initialized <- false
+	assert.Equal(t, 0, runtime.StopCallCount())
+	initialized <- true
+
+	waitForState(t, c, upgrade.Completed)
+	require.Equal(t, 1, runtime.StopCallCount())
+	assert.Equal(t, "mycc:v1", runtime.StopArgsForCall(0))
+	channelID, chaincodeName := initState.InitializedArgsForCall(0)
+	assert.Equal(t, "mychannel", channelID)
+	assert.Equal(t, "mycc", chaincodeName)
+}
+
+func TestUpgradeInitStateFailure(t *testing.T) {
+	runtime := newFakeRuntime("mycc:v1")
+	initState := &mock.InitState{}
+	initState.InitializedReturns(false, errors.New("ledger closed"))
+	c := upgrade.NewCoordinator(runtime, initState, time.Minute)
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v2")
+	<-runtime.launches
+	runtime.release <- nil
+
+	status := waitForState(t, c, upgrade.Failed)
+	assert.Equal(t, "failed to determine whether chaincode mycc is initialized: ledger closed", status.Error)
+	assert.Equal(t, 0, runtime.StopCallCount())
+}
+
+func TestDeployNotCommitted(t *testing.T) {
+	runtime := newFakeRuntime()
+	c := upgrade.NewCoordinator(runtime, nil, time.Minute)
+
+	listener := c.ChannelListener("mychannel")
+	listener.HandleChaincodeDeploy(&ledger.ChaincodeDefinition{Name: "mycc", Hash: []byte("mycc:v1")}, nil)
+	listener.ChaincodeDeployDone(false)
+	assert.Empty(t, c.Statuses())
+	assert.Equal(t, 0, runtime.LaunchCallCount())
+}
+
+func TestHandler(t *testing.T) {
+	runtime := newFakeRuntime()
+	c := upgrade.NewCoordinator(runtime, nil, time.Minute)
+	handler := upgrade.NewHandler(c)
+
+	commitDefinition(c, "mychannel", "mycc", "mycc:v1")
+	<-runtime.launches
+	defer func() { runtime.release <- nil }()
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/chaincode/upgrades", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	var statuses []upgrade.Status
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &statuses))
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "mychannel", statuses[0].ChannelID)
+	assert.Equal(t, "mycc", statuses[0].ChaincodeName)
+	assert.Equal(t, upgrade.Launching, statuses[0].State)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/chaincode/upgrades", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: POST"}`, resp.Body.String())
+}
@@ -0,0 +1,200 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// minimumWarmPoolScore is the score below which a chaincode is no longer
+// tracked by the warm pool.
+const minimumWarmPoolScore = 0.01
+
+// WarmPoolLauncher launches the chaincodes kept in the warm pool.
+type WarmPoolLauncher interface {
+	Launch(ccid string) (*Handler, error)
+}
+
+// WarmPool keeps the chaincodes which are invoked most frequently launched,
+// so that their invocations do not wait for the chaincode to start after a
+// peer restart or after the chaincode exited.
+//
+// The invocation frequency of a chaincode is tracked as a score which is
+// halved at every interval before the invocations of the interval are added
+// to it. The scores are persisted so that the pool can be warmed up as soon
+// as the peer starts.
+type WarmPool struct {
+	size     int
+	interval time.Duration
+	path     string
+	launcher WarmPoolLauncher
+
+	mutex       sync.Mutex
+	invocations map[string]uint64
+	scores      map[string]float64
+	stopOnce    sync.Once
+	stop        chan struct{}
+}
+
+// NewWarmPool creates a warm pool keeping the given number of chaincodes
+// launched, whose scores are persisted at path.
+func NewWarmPool(size int, interval time.Duration, path string, launcher WarmPoolLauncher) *WarmPool {
+	return &WarmPool{
+		size:        size,
+		interval:    interval,
+		path:        path,
+		launcher:    launcher,
+		invocations: map[string]uint64{},
+		scores:      map[string]float64{},
+		stop:        make(chan struct{}),
+	}
+}
+
+// Record records an invocation of the chaincode with the given ID.
+func (w *WarmPool) Record(ccid string) {
+	if w == nil {
+		return
+	}
+	w.mutex.Lock()
+	w.invocations[ccid]++
+	w.mutex.Unlock()
+}
+
+// Chaincodes returns the IDs of the chaincodes kept launched, the most
+// frequently invoked first.
+func (w *WarmPool) Chaincodes() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	ccids := make([]string, 0, len(w.scores))
+	for ccid := range w.scores {
+		ccids = append(ccids, ccid)
+	}
+	sort.Slice(ccids, func(i, j int) bool {
+		if w.scores[ccids[i]] != w.scores[ccids[j]] {
+			return w.scores[ccids[i]] > w.scores[ccids[j]]
+		}
+		return ccids[i] < ccids[j]
+	})
+	if len(ccids) > w.size {
+		ccids = ccids[:w.size]
+	}
+	return ccids
+}
+
+// Run launches the chaincodes of the pool persisted by the previous run and
+// then, at every interval, updates the scores and launches the chaincodes of
+// the pool which are not running. It returns once Stop is called.
+func (w *WarmPool) Run() {
+	if err := w.load(); err != nil {
+		chaincodeLogger.Warningf("Failed loading the chaincode warm pool: %s", err)
+	}
+	w.warm()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.update()
+			if err := w.save(); err != nil {
+				chaincodeLogger.Warningf("Failed persisting the chaincode warm pool: %s", err)
+			}
+			w.warm()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the maintenance of the pool.
+func (w *WarmPool) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+func (w *WarmPool) update() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for ccid, score := range w.scores {
+		w.scores[ccid] = score / 2
+	}
+	for ccid, count := range w.invocations {
+		w.scores[ccid] += float64(count)
+	}
+	for ccid, score := range w.scores {
+		if score < minimumWarmPoolScore {
+			delete(w.scores, ccid)
+		}
+	}
+	w.invocations = map[string]uint64{}
+}
+
+func (w *WarmPool) warm() {
+	for _, ccid := range w.Chaincodes() {
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+
+		if _, err := w.launcher.Launch(ccid); err != nil {
+			// the chaincode is most likely no longer installed, stop keeping
+			// it launched until it is invoked again
+			chaincodeLogger.Warningf("Failed launching chaincode %s of the warm pool: %s", ccid, err)
+			w.mutex.Lock()
+			delete(w.scores, ccid)
+			w.mutex.Unlock()
+		}
+	}
+}
+
+func (w *WarmPool) load() error {
+	data, err := ioutil.ReadFile(w.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed reading %s", w.path)
+	}
+
+	scores := map[string]float64{}
+	if err := json.Unmarshal(data, &scores); err != nil {
+		return errors.Wrapf(err, "failed unmarshaling %s", w.path)
+	}
+
+	w.mutex.Lock()
+	w.scores = scores
+	w.mutex.Unlock()
+	return nil
+}
+
+func (w *WarmPool) save() error {
+	w.mutex.Lock()
+	data, err := json.Marshal(w.scores)
+	w.mutex.Unlock()
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling the scores")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return errors.Wrapf(err, "failed creating the directory of %s", w.path)
+	}
+	tmp := w.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed writing %s", tmp)
+	}
+	return errors.Wrapf(os.Rename(tmp, w.path), "failed renaming %s", tmp)
+}
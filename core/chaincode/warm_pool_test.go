@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/fake"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("WarmPool", func() {
+	var (
+		tempDir      string
+		path         string
+		fakeLauncher *fake.WarmPoolLauncher
+		warmPool     *chaincode.WarmPool
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "warmpool")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(tempDir, "warmpool", "invocations.json")
+
+		fakeLauncher = &fake.WarmPoolLauncher{}
+		warmPool = chaincode.NewWarmPool(2, 10*time.Millisecond, path, fakeLauncher)
+	})
+
+	AfterEach(func() {
+		warmPool.Stop()
+		os.RemoveAll(tempDir)
+	})
+
+	persist := func(scores map[string]float64) {
+		data, err := json.Marshal(scores)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, data, 0600)).To(Succeed())
+	}
+
+	launched := func() []string {
+		var ccids []string
+		for i := 0; i < fakeLauncher.LaunchCallCount(); i++ {
+			ccids = append(ccids, fakeLauncher.LaunchArgsForCall(i))
+		}
+		return ccids
+	}
+
+	It("keeps the most frequently invoked chaincodes launched", func() {
+		for i := 0; i < 3; i++ {
+			warmPool.Record("cc1:hash")
+		}
+		warmPool.Record("cc2:hash")
+		warmPool.Record("cc2:hash")
+		warmPool.Record("cc3:hash")
+		go warmPool.Run()
+
+		Eventually(warmPool.Chaincodes).Should(Equal([]string{"cc1:hash", "cc2:hash"}))
+		Eventually(launched).Should(ContainElement("cc2:hash"))
+		Expect(launched()).To(ContainElement("cc1:hash"))
+		Consistently(launched, 50*time.Millisecond).ShouldNot(ContainElement("cc3:hash"))
+	})
+
+	It("persists the invocation scores", func() {
+		warmPool.Record("cc1:hash")
+		go warmPool.Run()
+
+		Eventually(func() map[string]float64 {
+			scores := map[string]float64{}
+			data, _ := ioutil.ReadFile(path)
+			json.Unmarshal(data, &scores)
+			return scores
+		}).Should(HaveKey("cc1:hash"))
+	})
+
+	It("decays the scores of the chaincodes no longer invoked", func() {
+		persist(map[string]float64{"cc1:hash": 10})
+		for i := 0; i < 6; i++ {
+			warmPool.Record("cc2:hash")
+		}
+		go warmPool.Run()
+
+		Eventually(warmPool.Chaincodes).Should(Equal([]string{"cc2:hash", "cc1:hash"}))
+		Eventually(warmPool.Chaincodes, time.Second).Should(BeEmpty())
+	})
+
+	Context("when the peer restarts", func() {
+		BeforeEach(func() {
+			persist(map[string]float64{"cc1:hash": 1, "cc2:hash": 4, "cc3:hash": 2})
+			warmPool = chaincode.NewWarmPool(2, time.Hour, path, fakeLauncher)
+		})
+
+		It("launches the chaincodes of the persisted pool", func() {
+			go warmPool.Run()
+
+			Eventually(launched).Should(Equal([]string{"cc2:hash", "cc3:hash"}))
+		})
+	})
+
+	Context("when the persisted pool is corrupted", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte("garbage"), 0600)).To(Succeed())
+		})
+
+		It("starts from an empty pool", func() {
+			warmPool.Record("cc1:hash")
+			go warmPool.Run()
+
+			Eventually(launched).Should(Equal([]string{"cc1:hash"}))
+		})
+	})
+
+	Context("when a chaincode fails to launch", func() {
+		BeforeEach(func() {
+			persist(map[string]float64{"cc1:hash": 1})
+			fakeLauncher.LaunchReturns(nil, errors.New("chaincode not installed"))
+		})
+
+		It("no longer keeps the chaincode launched", func() {
+			go warmPool.Run()
+
+			Eventually(fakeLauncher.LaunchCallCount).Should(Equal(1))
+			Eventually(warmPool.Chaincodes).Should(BeEmpty())
+			Consistently(fakeLauncher.LaunchCallCount, 50*time.Millisecond).Should(Equal(1))
+		})
+	})
+
+	It("ignores the invocations recorded without a pool", func() {
+		var nilPool *chaincode.WarmPool
+		Expect(func() { nilPool.Record("cc1:hash") }).NotTo(Panic())
+	})
+})
@@ -64,6 +64,9 @@ type Committer interface {
 	// GetMissingPvtDataTracker return the MissingPvtDataTracker
 	GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error)
 
+	// GetPvtDataAntiEntropy return the PvtDataAntiEntropy
+	GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error)
+
 	// Closes committing service
 	Close()
 }
@@ -40,6 +40,8 @@ type PeerLedgerSupport interface {
 
 	GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error)
 
+	GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error)
+
 	Close()
 }
 
@@ -40,6 +40,10 @@ func (m *mockLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	return info, nil
 }
 
+func (m *mockLedger) GetCommittedStateInfo() (*common.BlockchainInfo, error) {
+	return m.GetBlockchainInfo()
+}
+
 func (m *mockLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	args := m.Called()
 	return args.Get(0).(bool), args.Error(1)
@@ -120,6 +124,10 @@ func (m *mockLedger) GetMissingPvtDataTracker() (ledger2.MissingPvtDataTracker,
 	panic("implement me")
 }
 
+func (m *mockLedger) GetPvtDataAntiEntropy() (ledger2.PvtDataAntiEntropy, error) {
+	panic("implement me")
+}
+
 func createLedger(channelID string) (*common.Block, *mockLedger) {
 	gb, _ := test.MakeGenesisBlock(channelID)
 	ledger := &mockLedger{
@@ -1490,6 +1490,11 @@ func (m *mockLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	return args.Get(0).(*common.BlockchainInfo), nil
 }
 
+func (m *mockLedger) GetCommittedStateInfo() (*common.BlockchainInfo, error) {
+	args := m.Called()
+	return args.Get(0).(*common.BlockchainInfo), nil
+}
+
 func (m *mockLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
 	args := m.Called(blockNumber)
 	return args.Get(0).(*common.Block), nil
@@ -1528,6 +1533,11 @@ func (m *mockLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, e
 	return args.Get(0).(ledger.MissingPvtDataTracker), nil
 }
 
+func (m *mockLedger) GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error) {
+	args := m.Called()
+	return args.Get(0).(ledger.PvtDataAntiEntropy), nil
+}
+
 // mockQueryExecutor mock of the query executor,
 // needed to simulate inability to access state db, e.g.
 // the case where due to db failure it's not possible to
@@ -222,6 +222,8 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 				switch err.(type) {
 				case *commonerrors.VSCCEndorsementPolicyError:
 					return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+				case *commonerrors.VSCCNonDeterministicOutputError:
+					return err, validation.NonDeterministicOutputCode
 				default:
 					return err, peer.TxValidationCode_INVALID_OTHER_REASON
 				}
@@ -263,6 +265,8 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 			switch err.(type) {
 			case *commonerrors.VSCCEndorsementPolicyError:
 				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+			case *commonerrors.VSCCNonDeterministicOutputError:
+				return err, validation.NonDeterministicOutputCode
 			default:
 				return err, peer.TxValidationCode_INVALID_OTHER_REASON
 			}
@@ -282,6 +286,10 @@ func (v *VsccValidatorImpl) VSCCValidateTxForCC(ctx *Context) error {
 	if e, isExecutionError := err.(*validation.ExecutionFailureError); isExecutionError {
 		return &commonerrors.VSCCExecutionFailureError{Err: e}
 	}
+	// If the endorsements do not agree on the output of the chaincode, cast it to the common errors NonDeterministicOutputError.
+	if e, isNonDeterministic := err.(*validation.NonDeterministicOutputError); isNonDeterministic {
+		return &commonerrors.VSCCNonDeterministicOutputError{Err: e}
+	}
 	// Else, treat it as an endorsement error.
 	return &commonerrors.VSCCEndorsementPolicyError{Err: err}
 }
@@ -0,0 +1,24 @@
+// Code generated by mockery v1.0.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// ACLProvider is an autogenerated mock type for the ACLProvider type
+type ACLProvider struct {
+	mock.Mock
+}
+
+// CheckACL provides a mock function with given fields: resName, channelID, idinfo
+func (_m *ACLProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	ret := _m.Called(resName, channelID, idinfo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, interface{}) error); ok {
+		r0 = rf(resName, channelID, idinfo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -46,6 +46,22 @@ func (_m *ChannelResources) Capabilities() channelconfig.ApplicationCapabilities
 	return r0
 }
 
+// ChannelCapabilities provides a mock function with given fields:
+func (_m *ChannelResources) ChannelCapabilities() channelconfig.ChannelCapabilities {
+	ret := _m.Called()
+
+	var r0 channelconfig.ChannelCapabilities
+	if rf, ok := ret.Get(0).(func() channelconfig.ChannelCapabilities); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(channelconfig.ChannelCapabilities)
+		}
+	}
+
+	return r0
+}
+
 // GetMSPIDs provides a mock function with given fields:
 func (_m *ChannelResources) GetMSPIDs() []string {
 	ret := _m.Called()
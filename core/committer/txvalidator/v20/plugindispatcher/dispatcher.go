@@ -12,6 +12,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
@@ -28,6 +30,9 @@ type ChannelResources interface {
 	// GetMSPIDs returns the IDs for the application MSPs
 	// that have been defined in the channel
 	GetMSPIDs() []string
+
+	// ChannelCapabilities defines the capabilities for the channel portion of this channel
+	ChannelCapabilities() channelconfig.ChannelCapabilities
 }
 
 // LedgerResources provides access to ledger artefacts or
@@ -178,6 +183,13 @@ func (v *dispatcherImpl) Dispatch(seq int, payload *common.Payload, envBytes []b
 		}
 		namespaces[ns.NameSpace] = struct{}{}
 
+		// counter deltas are only understood by the peers of channels with the capability
+		if rwsetutil.ContainsCounterDeltas(ns) && !v.cr.ChannelCapabilities().CommutativeCounters() {
+			logger.Errorf("txRWSet increments counter keys of namespace '%s' but commutative counters are not enabled", ns.NameSpace)
+			return errors.Errorf("txRWSet increments counter keys of namespace '%s' but commutative counters are not enabled, channel capability %s is required",
+				ns.NameSpace, capabilities.ChannelCommutativeCounters), peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+
 		if v.txWritesToNamespace(ns) {
 			wrNamespace[ns.NameSpace] = true
 		}
@@ -210,6 +222,8 @@ func (v *dispatcherImpl) Dispatch(seq int, payload *common.Payload, envBytes []b
 			switch err.(type) {
 			case *commonerrors.VSCCEndorsementPolicyError:
 				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+			case *commonerrors.VSCCNonDeterministicOutputError:
+				return err, validation.NonDeterministicOutputCode
 			default:
 				return err, peer.TxValidationCode_INVALID_OTHER_REASON
 			}
@@ -230,6 +244,10 @@ func (v *dispatcherImpl) invokeValidationPlugin(ctx *Context) error {
 	if e, isExecutionError := err.(*validation.ExecutionFailureError); isExecutionError {
 		return &commonerrors.VSCCExecutionFailureError{Err: e}
 	}
+	// If the endorsements do not agree on the output of the chaincode, cast it to the common errors NonDeterministicOutputError.
+	if e, isNonDeterministic := err.(*validation.NonDeterministicOutputError); isNonDeterministic {
+		return &commonerrors.VSCCNonDeterministicOutputError{Err: e}
+	}
 	// Else, treat it as an endorsement error.
 	return &commonerrors.VSCCEndorsementPolicyError{Err: err}
 }
@@ -282,6 +300,11 @@ func (v *dispatcherImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 		return true
 	}
 
+	// counter increments write the counter keys
+	if rwsetutil.ContainsCounterDeltas(ns) {
+		return true
+	}
+
 	// check for private writes for all collections
 	for _, c := range ns.CollHashedRwSets {
 		if c.HashedRwSet != nil && len(c.HashedRwSet.HashedWrites) > 0 {
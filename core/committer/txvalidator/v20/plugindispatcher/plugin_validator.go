@@ -198,7 +198,10 @@ func (pbc *pluginsByChannel) createPluginIfAbsent(channel string) (validation.Pl
 }
 
 func (pbc *pluginsByChannel) initPlugin(plugin validation.Plugin, channel string) (validation.Plugin, error) {
-	pp, err := policy.New(pbc.pv.IdentityDeserializer, channel, pbc.pv.ChannelPolicyManagerGetter)
+	// the capabilities of the validation API don't cover weighted signature policies,
+	// hence they are only honored if the supplied capabilities report them
+	capabilities, _ := pbc.pv.capabilities.(policy.Capabilities)
+	pp, err := policy.New(pbc.pv.IdentityDeserializer, channel, pbc.pv.ChannelPolicyManagerGetter, capabilities)
 	if err != nil {
 		return nil, errors.WithMessage(err, "could not obtain a policy evaluator")
 	}
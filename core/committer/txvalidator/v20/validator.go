@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/core/committer/txvalidator/v20/plugindispatcher"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protoutil"
@@ -54,6 +55,9 @@ type ChannelResources interface {
 
 	// Capabilities defines the capabilities for the application portion of this channel
 	Capabilities() channelconfig.ApplicationCapabilities
+
+	// ChannelCapabilities defines the capabilities for the channel portion of this channel
+	ChannelCapabilities() channelconfig.ChannelCapabilities
 }
 
 // LedgerResources provides access to ledger artefacts or
@@ -75,9 +79,18 @@ type Dispatcher interface {
 	Dispatch(seq int, payload *common.Payload, envBytes []byte, block *common.Block) (error, peer.TxValidationCode)
 }
 
+// ACLProvider checks the access of the creators of the custom transactions
+// to the ACL resources of their types
+type ACLProvider interface {
+	// CheckACL checks the access to the resource on the channel of the
+	// identity in idinfo
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
 //go:generate mockery -dir . -name ChannelResources -case underscore -output mocks/
 //go:generate mockery -dir . -name LedgerResources -case underscore -output mocks/
 //go:generate mockery -dir . -name Dispatcher -case underscore -output mocks/
+//go:generate mockery -dir . -name ACLProvider -case underscore -output mocks/
 
 //go:generate mockery -dir . -name QueryExecutor -case underscore -output mocks/
 
@@ -111,6 +124,7 @@ type TxValidator struct {
 	LedgerResources  LedgerResources
 	Dispatcher       Dispatcher
 	CryptoProvider   bccsp.BCCSP
+	ACLProvider      ACLProvider
 }
 
 var logger = flogging.MustGetLogger("committer.txvalidator")
@@ -138,6 +152,7 @@ func NewTxValidator(
 	cor plugindispatcher.CollectionResources,
 	pm plugin.Mapper,
 	channelPolicyManagerGetter policies.ChannelPolicyManagerGetter,
+	aclProvider ACLProvider,
 	cryptoProvider bccsp.BCCSP,
 ) *TxValidator {
 	// Encapsulates interface implementation
@@ -149,6 +164,7 @@ func NewTxValidator(
 		LedgerResources:  ler,
 		Dispatcher:       plugindispatcher.New(channelID, cr, ler, lcr, pluginValidator),
 		CryptoProvider:   cryptoProvider,
+		ACLProvider:      aclProvider,
 	}
 }
 
@@ -416,6 +432,29 @@ func (v *TxValidator) validateTx(req *blockValidationRequest, results chan<- *bl
 				return
 			}
 			logger.Debugf("config transaction received for chain %s", channel)
+		} else if customtx.IsRegistered(common.HeaderType(chdr.Type)) && v.ChannelResources.ChannelCapabilities().CustomTransactions() {
+
+			txID = chdr.TxId
+
+			// Check duplicate transactions, the content of custom
+			// transactions is validated by their processor at commit time
+			erroneousResultEntry := v.checkTxIdDupsLedger(tIdx, chdr, v.LedgerResources)
+			if erroneousResultEntry != nil {
+				results <- erroneousResultEntry
+				return
+			}
+
+			// Custom transactions are not endorsed, instead their creator
+			// must satisfy the policy of the ACL resource of their type
+			if err := v.ACLProvider.CheckACL(customtx.ACLResource(common.HeaderType(chdr.Type)), channel, env); err != nil {
+				logger.Warningf("Creator of custom transaction %s of type [%s] is not authorized on channel %s: %s", txID, common.HeaderType(chdr.Type), channel, err)
+				results <- &blockValidationResult{
+					tIdx:           tIdx,
+					validationCode: peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE,
+				}
+				return
+			}
+			logger.Debugf("custom transaction of type [%s] received for chain %s", common.HeaderType(chdr.Type), channel)
 		} else {
 			logger.Warningf("Unknown transaction type [%s] in block number [%d] transaction index [%d]",
 				common.HeaderType(chdr.Type), block.Header.Number, tIdx)
@@ -551,3 +590,7 @@ func (ds *dynamicCapabilities) V1_3Validation() bool {
 func (ds *dynamicCapabilities) V2_0Validation() bool {
 	return ds.cr.Capabilities().V2_0Validation()
 }
+
+func (ds *dynamicCapabilities) WeightedSignaturePolicies() bool {
+	return ds.cr.ChannelCapabilities().WeightedSignaturePolicies()
+}
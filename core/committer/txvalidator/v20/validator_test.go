@@ -21,9 +21,11 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	protospeer "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/capabilities"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/common/semaphore"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	tmocks "github.com/hyperledger/fabric/core/committer/txvalidator/mocks"
 	txvalidatorplugin "github.com/hyperledger/fabric/core/committer/txvalidator/plugin"
@@ -34,6 +36,8 @@ import (
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/handlers/validation/builtin"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/core/ledger/customtx/notarization"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
 	"github.com/hyperledger/fabric/core/scc/lscc"
@@ -160,6 +164,10 @@ func setupValidator() (*txvalidatorv20.TxValidator, *txvalidatormocks.QueryExecu
 }
 
 func setupValidatorWithMspMgr(mspmgr msp.MSPManager, mockID *supportmocks.Identity) (*txvalidatorv20.TxValidator, *txvalidatormocks.QueryExecutor, *supportmocks.Identity, *txvalidatormocks.CollectionResources) {
+	return setupValidatorWithSupport(&mocktxvalidator.Support{ACVal: v20Capabilities(), MSPManagerVal: mspmgr}, mockID)
+}
+
+func setupValidatorWithSupport(support *mocktxvalidator.Support, mockID *supportmocks.Identity) (*txvalidatorv20.TxValidator, *txvalidatormocks.QueryExecutor, *supportmocks.Identity, *txvalidatormocks.CollectionResources) {
 	pm := &plugindispatchermocks.Mapper{}
 	factory := &plugindispatchermocks.PluginFactory{}
 	pm.On("FactoryByName", txvalidatorplugin.Name("vscc")).Return(factory)
@@ -179,16 +187,20 @@ func setupValidatorWithMspMgr(mspmgr msp.MSPManager, mockID *supportmocks.Identi
 
 	mockCR := &txvalidatormocks.CollectionResources{}
 
+	mockACL := &txvalidatormocks.ACLProvider{}
+	mockACL.On("CheckACL", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
 	cryptoProvider, _ := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
 	v := txvalidatorv20.NewTxValidator(
 		"",
 		semaphore.New(10),
-		&mocktxvalidator.Support{ACVal: v20Capabilities(), MSPManagerVal: mspmgr},
+		support,
 		mockLedger,
 		&lscc.SCC{BCCSP: cryptoProvider},
 		mockCR,
 		pm,
 		mockCpmg,
+		mockACL,
 		cryptoProvider,
 	)
 
@@ -299,6 +311,73 @@ func TestInvokeNOKDuplicateNs(t *testing.T) {
 	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
 }
 
+func TestInvokeCounterDeltas(t *testing.T) {
+	ccID := "mycc"
+
+	txrws := &rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset: []*rwset.NsReadWriteSet{
+			{
+				Namespace: ccID,
+				Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+					CounterDeltas: []*kvrwset.KVCounterDelta{rwsetutil.NewKVCounterDelta("counter", 1)},
+				}),
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name                string
+		commutativeCounters bool
+		unendorsed          bool
+		expectedCode        peer.TxValidationCode
+	}{
+		{name: "capability disabled", expectedCode: peer.TxValidationCode_ILLEGAL_WRITESET},
+		{name: "capability enabled", commutativeCounters: true, expectedCode: peer.TxValidationCode_VALID},
+		{name: "not endorsed", commutativeCounters: true, unendorsed: true, expectedCode: peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mspmgr := &supportmocks.MSPManager{}
+			mockID := &supportmocks.Identity{}
+			mockID.SatisfiesPrincipalReturns(nil)
+			if tc.unendorsed {
+				// the counter increment is checked against the endorsement policy of the namespace
+				mockID.SatisfiesPrincipalReturns(errors.New("principal not satisfied"))
+			}
+			mockID.GetIdentifierReturns(&msp.IdentityIdentifier{})
+			mspmgr.DeserializeIdentityReturns(mockID, nil)
+			channelCapabilities := map[string]*common.Capability{capabilities.ChannelV2_0: {}}
+			if tc.commutativeCounters {
+				channelCapabilities[capabilities.ChannelCommutativeCounters] = &common.Capability{}
+			}
+			v, mockQE, _, _ := setupValidatorWithSupport(&mocktxvalidator.Support{
+				ACVal:         v20Capabilities(),
+				CCVal:         capabilities.NewChannelProvider(channelCapabilities),
+				MSPManagerVal: mspmgr,
+			}, mockID)
+
+			mockQE.On("GetState", "lscc", ccID).Return(protoutil.MarshalOrPanic(&ccp.ChaincodeData{
+				Name:    ccID,
+				Version: ccVersion,
+				Vscc:    "vscc",
+				Policy:  signedByAnyMember([]string{"SampleOrg"}),
+			}), nil)
+			mockQE.On("GetStateMetadata", ccID, "counter").Return(nil, nil)
+
+			tx := getEnv(ccID, nil, protoutil.MarshalOrPanic(txrws), t)
+			b := &common.Block{Data: &common.BlockData{Data: [][]byte{protoutil.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{Number: 2}}
+
+			err := v.Validate(b)
+			assert.NoError(t, err)
+			if tc.expectedCode == peer.TxValidationCode_VALID {
+				assertValid(b, t)
+			} else {
+				assertInvalid(b, t, tc.expectedCode)
+			}
+		})
+	}
+}
+
 func TestInvokeNoRWSet(t *testing.T) {
 	ccID := "mycc"
 
@@ -1098,6 +1177,7 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 		&txvalidatormocks.CollectionResources{},
 		pm,
 		mockCpmg,
+		&txvalidatormocks.ACLProvider{},
 		cryptoProvider,
 	)
 
@@ -1172,6 +1252,7 @@ func TestValidationPluginExecutionError(t *testing.T) {
 		&txvalidatormocks.CollectionResources{},
 		pm,
 		mockCpmg,
+		&txvalidatormocks.ACLProvider{},
 		cryptoProvider,
 	)
 
@@ -1225,6 +1306,7 @@ func TestValidationPluginNotFound(t *testing.T) {
 		&txvalidatormocks.CollectionResources{},
 		pm,
 		mockCpmg,
+		&txvalidatormocks.ACLProvider{},
 		cryptoProvider,
 	)
 
@@ -1270,3 +1352,60 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func TestCustomTx(t *testing.T) {
+	mspmgr := &supportmocks.MSPManager{}
+	mockID := &supportmocks.Identity{}
+	mockID.SatisfiesPrincipalReturns(nil)
+	mockID.GetIdentifierReturns(&msp.IdentityIdentifier{})
+	mspmgr.DeserializeIdentityReturns(mockID, nil)
+	support := &mocktxvalidator.Support{
+		ACVal:         v20Capabilities(),
+		CCVal:         capabilities.NewChannelProvider(map[string]*common.Capability{capabilities.ChannelV2_0: {}}),
+		MSPManagerVal: mspmgr,
+	}
+	v, _, _, _ := setupValidatorWithSupport(support, mockID)
+
+	tx, _, err := notarization.CreateSignedTx("testchannelid", "doc1", []byte("hash"), signer)
+	assert.NoError(t, err)
+	newBlock := func() *common.Block {
+		return &common.Block{
+			Data:   &common.BlockData{Data: [][]byte{protoutil.MarshalOrPanic(tx)}},
+			Header: &common.BlockHeader{Number: 1},
+		}
+	}
+
+	// Transactions of a type without a registered processor are invalid
+	b := newBlock()
+	assert.NoError(t, v.Validate(b))
+	assertInvalid(b, t, peer.TxValidationCode_BAD_COMMON_HEADER)
+
+	// Custom transactions are invalid on the channels without the capability
+	assert.NoError(t, customtx.Register(notarization.TxType, notarization.ACLResource, &notarization.Processor{}))
+	b = newBlock()
+	assert.NoError(t, v.Validate(b))
+	assertInvalid(b, t, peer.TxValidationCode_UNKNOWN_TX_TYPE)
+
+	support.CCVal = capabilities.NewChannelProvider(map[string]*common.Capability{
+		capabilities.ChannelV2_0:               {},
+		capabilities.ChannelCustomTransactions: {},
+	})
+	b = newBlock()
+	assert.NoError(t, v.Validate(b))
+	assertValid(b, t)
+
+	// The creator of the transactions must satisfy the policy of the ACL resource of their type
+	mockACL := &txvalidatormocks.ACLProvider{}
+	mockACL.On("CheckACL", resources.Notarization_Notarize, "testchannelid", mock.Anything).Return(errors.New("access denied"))
+	v.ACLProvider = mockACL
+	b = newBlock()
+	assert.NoError(t, v.Validate(b))
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	mockLedger := &txvalidatormocks.LedgerResources{}
+	v.LedgerResources = mockLedger
+	mockLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, nil)
+	b = newBlock()
+	assert.NoError(t, v.Validate(b))
+	assertInvalid(b, t, peer.TxValidationCode_DUPLICATE_TXID)
+}
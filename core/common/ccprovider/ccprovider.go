@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package ccprovider
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -415,4 +416,8 @@ type TransactionParams struct {
 
 	// this is additional data passed to the chaincode
 	ProposalDecorations map[string][]byte
+
+	// Context carries the deadline of the proposal, if any, and is cancelled
+	// when the client gives up on it. A nil Context never expires.
+	Context context.Context
 }
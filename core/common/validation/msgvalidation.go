@@ -13,6 +13,7 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -96,7 +97,9 @@ func validateChannelHeader(cHdr *common.ChannelHeader) error {
 	case common.HeaderType_CONFIG_UPDATE:
 	case common.HeaderType_CONFIG:
 	default:
-		return errors.Errorf("invalid header type %s", common.HeaderType(cHdr.Type))
+		if !customtx.IsRegistered(common.HeaderType(cHdr.Type)) {
+			return errors.Errorf("invalid header type %s", common.HeaderType(cHdr.Type))
+		}
 	}
 
 	putilsLogger.Debugf("validateChannelHeader info: header type %d", common.HeaderType(cHdr.Type))
@@ -315,6 +318,23 @@ func ValidateTransaction(e *common.Envelope, cryptoProvider bccsp.BCCSP) (*commo
 		}
 		return payload, pb.TxValidationCode_VALID
 	default:
-		return nil, pb.TxValidationCode_UNSUPPORTED_TX_PAYLOAD
+		if !customtx.IsRegistered(common.HeaderType(chdr.Type)) {
+			return nil, pb.TxValidationCode_UNSUPPORTED_TX_PAYLOAD
+		}
+
+		// Custom transactions are not endorsed, their content is validated by
+		// their processor at commit time. As for endorser transactions, the
+		// transaction ID must be computed properly for the lookup into the
+		// ledger to catch duplicates.
+		err = protoutil.CheckTxID(
+			chdr.TxId,
+			shdr.Nonce,
+			shdr.Creator)
+
+		if err != nil {
+			putilsLogger.Errorf("CheckTxID returns err %s", err)
+			return nil, pb.TxValidationCode_BAD_PROPOSAL_TXID
+		}
+		return payload, pb.TxValidationCode_VALID
 	}
 }
@@ -14,6 +14,8 @@ import (
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
@@ -134,3 +136,31 @@ func TestCheckSignatureFromCreator(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "MSP error: channel doesn't exist")
 }
+
+func TestValidateCustomTransaction(t *testing.T) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	txType := customtx.MinTxType + 50
+
+	header, err := createTestHeader(t, txType, "testchannelid", signerSerialized, true)
+	assert.NoError(t, err)
+	env, err := createTestEnvelope(t, []byte("custom"), header, signer)
+	assert.NoError(t, err)
+
+	// Transactions of a type without a registered processor are rejected
+	_, code := ValidateTransaction(env, cryptoProvider)
+	assert.Equal(t, peer.TxValidationCode_BAD_COMMON_HEADER, code)
+
+	err = customtx.Register(txType, "acl", &mock.CustomTxProcessor{})
+	assert.NoError(t, err)
+	payload, code := ValidateTransaction(env, cryptoProvider)
+	assert.Equal(t, peer.TxValidationCode_VALID, code)
+	assert.Equal(t, []byte("custom"), payload.Data)
+
+	header, err = createTestHeader(t, txType, "testchannelid", signerSerialized, false)
+	assert.NoError(t, err)
+	env, err = createTestEnvelope(t, []byte("custom"), header, signer)
+	assert.NoError(t, err)
+	_, code = ValidateTransaction(env, cryptoProvider)
+	assert.Equal(t, peer.TxValidationCode_BAD_PROPOSAL_TXID, code)
+}
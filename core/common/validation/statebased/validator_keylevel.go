@@ -109,6 +109,16 @@ func (p *baseEvaluator) Evaluate(blockNum, txNum uint64, NsRwSets []*rwsetutil.N
 				return err
 			}
 		}
+		// public counter increments
+		// we validate increments, which write the counter keys, against
+		// key-level validation parameters if any are present or the
+		// chaincode-wide endorsement policy
+		for _, counterDelta := range nsRWSet.KvRwSet.CounterDeltas {
+			err := p.checkSBAndCCEP(ns, "", counterDelta.Key, blockNum, txNum, sd)
+			if err != nil {
+				return err
+			}
+		}
 		// writes in collections
 		// we validate writes against key-level validation parameters
 		// if any are present or the chaincode-wide endorsement policy
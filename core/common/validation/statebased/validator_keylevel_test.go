@@ -426,6 +426,52 @@ func TestOnlySBEPChecked(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestKeylevelValidationCounterDelta(t *testing.T) {
+	t.Parallel()
+
+	// Scenario: we validate a transaction that only increments
+	// a counter key that contains key-level validation params.
+	// The increment writes the key, so the state-based policy
+	// is checked, and the validation fails if it is not satisfied
+
+	vpMetadataKey := pb.MetaDataKeys_VALIDATION_PARAMETER.String()
+	mr := &mockState{GetStateMetadataRv: map[string][]byte{vpMetadataKey: []byte("SBEP")}}
+	ms := &mockStateFetcher{FetchStateRv: mr}
+	pm := &KeyLevelValidationParameterManagerImpl{PolicyTranslator: &mockTranslator{}, StateFetcher: ms}
+	pe := &mockPolicyEvaluator{}
+	validator := NewKeyLevelValidator(NewV13Evaluator(pe, pm), pm)
+
+	rwsbu := rwsetutil.NewRWSetBuilder()
+	assert.NoError(t, rwsbu.AddToCounterDeltaSet("cc", "counter", 1))
+	rws := rwsbu.GetTxReadWriteSet()
+	rwsb, err := rws.ToProtoBytes()
+	assert.NoError(t, err)
+	prp := []byte("barf")
+	block := buildBlockWithTxs(buildTXWithRwset(rwsetUpdatingMetadataFor("cc", "counter")), buildTXWithRwset(rwsb))
+
+	validator.PreValidate(1, block)
+
+	go func() {
+		validator.PostValidate("cc", 1, 0, fmt.Errorf(""))
+	}()
+
+	pe.EvaluateResByPolicy = map[string]error{
+		"SBEP": fmt.Errorf("policy evaluation error"),
+	}
+
+	err = validator.Validate("cc", 1, 1, rwsb, prp, []byte("CCEP"), []*pb.Endorsement{})
+	assert.Error(t, err)
+	assert.IsType(t, &errors.VSCCEndorsementPolicyError{}, err)
+
+	pe.EvaluateRV = fmt.Errorf("policy evaluation error")
+	pe.EvaluateResByPolicy = map[string]error{
+		"SBEP": nil,
+	}
+
+	err = validator.Validate("cc", 1, 1, rwsb, prp, []byte("CCEP"), []*pb.Endorsement{})
+	assert.NoError(t, err)
+}
+
 func TestCCEPValidationPvtReads(t *testing.T) {
 	t.Parallel()
 
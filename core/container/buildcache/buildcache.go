@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package buildcache
+
+import (
+	"encoding/hex"
+	"hash"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+)
+
+// The chaincode build caches reuse the outputs of a previous build of the same
+// chaincode package, by the same builder, instead of building it again. The
+// outputs are keyed by the SM3 digest of the package and by the name and the
+// version of the builder, so that upgrading a builder invalidates the outputs
+// it built before.
+
+// NewDigest returns the hash computing the digest of a chaincode package. The
+// package metadata and its code package are written to it, in this order.
+func NewDigest() hash.Hash {
+	return sm3.New()
+}
+
+// Key returns the key of the outputs of a build of the chaincode package with
+// the given digest by the given version of a builder.
+func Key(builder, builderVersion string, packageDigest []byte) string {
+	h := sm3.New()
+	h.Write([]byte(builder))
+	h.Write([]byte{0})
+	h.Write([]byte(builderVersion))
+	h.Write([]byte{0})
+	h.Write(packageDigest)
+	return hex.EncodeToString(h.Sum(nil))
+}
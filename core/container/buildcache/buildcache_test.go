@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package buildcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	digest := func(data string) []byte {
+		h := NewDigest()
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+
+	key := Key("golang", "1.0", digest("package"))
+	require.Len(t, key, 64)
+	require.Equal(t, key, Key("golang", "1.0", digest("package")))
+
+	require.NotEqual(t, key, Key("golang", "1.0", digest("other package")))
+	require.NotEqual(t, key, Key("golang", "1.1", digest("package")))
+	require.NotEqual(t, key, Key("node", "1.0", digest("package")))
+	require.NotEqual(t, Key("a", "b1.0", digest("package")), Key("ab", "1.0", digest("package")))
+}
@@ -152,6 +152,81 @@ var _ = Describe("Router", func() {
 			})
 		})
 
+		Context("when a process builder is provided", func() {
+			var fakeProcessBuilder *mock.ProcessBuilder
+
+			BeforeEach(func() {
+				fakeProcessBuilder = &mock.ProcessBuilder{}
+				fakeProcessBuilder.BuildReturns(fakeInstance, nil)
+				router.ProcessBuilder = fakeProcessBuilder
+				fakeExternalBuilder.BuildReturns(nil, nil)
+			})
+
+			It("uses the process builder before the docker builder", func() {
+				err := router.Build("package-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeProcessBuilder.BuildCallCount()).To(Equal(1))
+				ccid, md, codeStream := fakeProcessBuilder.BuildArgsForCall(0)
+				Expect(ccid).To(Equal("package-id"))
+				Expect(md).To(Equal(&persistence.ChaincodePackageMetadata{
+					Type: "package-type",
+					Path: "package-path",
+				}))
+				codePackage, err := ioutil.ReadAll(codeStream)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(codePackage).To(Equal([]byte("code-bytes")))
+				Expect(fakeDockerBuilder.BuildCallCount()).To(Equal(0))
+			})
+
+			Context("when the external builder returns an instance", func() {
+				BeforeEach(func() {
+					fakeExternalBuilder.BuildReturns(fakeInstance, nil)
+				})
+
+				It("does not call the process builder", func() {
+					err := router.Build("package-id")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakeProcessBuilder.BuildCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the process builder returns a nil instance", func() {
+				BeforeEach(func() {
+					fakeProcessBuilder.BuildReturns(nil, nil)
+					fakeDockerBuilder.BuildReturns(fakeInstance, nil)
+				})
+
+				It("falls back to the docker impl", func() {
+					err := router.Build("package-id")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakeDockerBuilder.BuildCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the process builder returns an error", func() {
+				BeforeEach(func() {
+					fakeProcessBuilder.BuildReturns(nil, errors.New("fake-process-build-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					err := router.Build("package-id")
+					Expect(err).To(MatchError("process build failed: fake-process-build-error"))
+				})
+			})
+
+			Context("when the package provider returns an error before calling the process builder", func() {
+				BeforeEach(func() {
+					fakePackageProvider.GetChaincodePackageReturnsOnCall(1, nil, nil, nil, errors.New("fake-package-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					err := router.Build("package-id")
+					Expect(err).To(MatchError("failed to get chaincode package for process build: fake-package-error"))
+				})
+			})
+		})
+
 		Context("when an external builder is not provided", func() {
 			BeforeEach(func() {
 				router.ExternalBuilder = nil
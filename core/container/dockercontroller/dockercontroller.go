@@ -16,6 +16,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"regexp"
 	"strconv"
 	"strings"
@@ -27,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/buildcache"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/pkg/errors"
 )
@@ -70,6 +72,8 @@ type dockerClient interface {
 	WaitContainer(containerID string) (int, error)
 	// InspectImage returns an image by its name or ID.
 	InspectImage(imageName string) (*docker.Image, error)
+	// TagImage adds a tag to the image identified by the given name.
+	TagImage(name string, opts docker.TagImageOptions) error
 }
 
 type PlatformBuilder interface {
@@ -108,11 +112,26 @@ type DockerVM struct {
 	AttachStdOut    bool
 	ChaincodePull   bool
 	NetworkMode     string
+	Architecture    string
 	PlatformBuilder PlatformBuilder
 	LoggingEnv      []string
 	MSPID           string
+	// Sandbox is the syscall policy of the chaincode containers, the security
+	// options of HostConfig apply alone if it is nil.
+	Sandbox *SandboxPolicy
+	// BuildCacheEnabled tags the chaincode images with the digest of their
+	// package so that the peers sharing the docker daemon, and the peers
+	// recreated on it, reuse them instead of building the package again.
+	BuildCacheEnabled bool
+	// BuilderVersion is the version of the chaincode images builder, the
+	// cached images of another version are not reused.
+	BuilderVersion string
 }
 
+// buildCacheRepository is the repository of the cached chaincode images,
+// tagged with their build cache key.
+const buildCacheRepository = "fabric-buildcache"
+
 // HealthCheck checks if the DockerVM is able to communicate with the Docker
 // daemon.
 func (vm *DockerVM) HealthCheck(ctx context.Context) error {
@@ -122,7 +141,7 @@ func (vm *DockerVM) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
-func (vm *DockerVM) createContainer(imageID, containerID string, args, env []string) error {
+func (vm *DockerVM) createContainer(imageID, containerID string, args, env []string, hostConfig *docker.HostConfig) error {
 	logger := dockerLogger.With("imageID", imageID, "containerID", containerID)
 	logger.Debugw("create container")
 	_, err := vm.Client.CreateContainer(docker.CreateContainerOptions{
@@ -134,7 +153,7 @@ func (vm *DockerVM) createContainer(imageID, containerID string, args, env []str
 			AttachStdout: vm.AttachStdOut,
 			AttachStderr: vm.AttachStdOut,
 		},
-		HostConfig: vm.HostConfig,
+		HostConfig: hostConfig,
 	})
 	if err != nil {
 		return err
@@ -188,9 +207,28 @@ func (vm *DockerVM) Build(ccid string, metadata *persistence.ChaincodePackageMet
 	// lifecycle tools seem to allow type to be set lower case.
 	ccType := strings.ToUpper(metadata.Type)
 
-	_, err = vm.Client.InspectImage(imageName)
+	image, err := vm.Client.InspectImage(imageName)
+	if err == nil && image != nil && vm.Architecture != "" && image.Architecture != vm.Architecture {
+		// images built for another architecture, such as before moving the
+		// peer to an arm64 host, cannot be launched and are rebuilt
+		dockerLogger.Warningf("Image %s was built for architecture %s, rebuilding it for %s", imageName, image.Architecture, vm.Architecture)
+		err = docker.ErrNoSuchImage
+	}
 	switch err {
 	case docker.ErrNoSuchImage:
+		var cacheKey string
+		if vm.BuildCacheEnabled {
+			code, err := ioutil.ReadAll(codePackage)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not read code package")
+			}
+			cacheKey = vm.buildCacheKey(ccType, metadata.Path, code)
+			if vm.restoreCachedImage(cacheKey, imageName) {
+				break
+			}
+			codePackage = bytes.NewReader(code)
+		}
+
 		dockerfileReader, err := vm.PlatformBuilder.GenerateDockerBuild(ccType, metadata.Path, codePackage)
 		if err != nil {
 			return nil, errors.Wrap(err, "platform builder failed")
@@ -199,6 +237,10 @@ func (vm *DockerVM) Build(ccid string, metadata *persistence.ChaincodePackageMet
 		if err != nil {
 			return nil, errors.Wrap(err, "docker image build failed")
 		}
+
+		if cacheKey != "" {
+			vm.storeCachedImage(imageName, cacheKey)
+		}
 	case nil:
 	default:
 		return nil, errors.Wrap(err, "docker image inspection failed")
@@ -211,6 +253,43 @@ func (vm *DockerVM) Build(ccid string, metadata *persistence.ChaincodePackageMet
 	}, nil
 }
 
+// buildCacheKey returns the tag of the cached image of the chaincode package.
+func (vm *DockerVM) buildCacheKey(ccType, path string, code []byte) string {
+	digest := buildcache.NewDigest()
+	digest.Write([]byte(ccType))
+	digest.Write([]byte{0})
+	digest.Write([]byte(path))
+	digest.Write([]byte{0})
+	digest.Write(code)
+	return buildcache.Key("docker/"+vm.Architecture, vm.BuilderVersion, digest.Sum(nil))
+}
+
+// restoreCachedImage tags the cached image with the name of the chaincode
+// image. It returns false if there is no such cached image.
+func (vm *DockerVM) restoreCachedImage(cacheKey, imageName string) bool {
+	cachedImage := buildCacheRepository + ":" + cacheKey
+	if _, err := vm.Client.InspectImage(cachedImage); err != nil {
+		if err != docker.ErrNoSuchImage {
+			dockerLogger.Warningf("Failed to inspect cached image %s: %s", cachedImage, err)
+		}
+		return false
+	}
+	if err := vm.Client.TagImage(cachedImage, docker.TagImageOptions{Repo: imageName, Tag: "latest"}); err != nil {
+		dockerLogger.Warningf("Failed to restore image %s from cached image %s: %s", imageName, cachedImage, err)
+		return false
+	}
+	dockerLogger.Infof("Restored image %s from cached image %s", imageName, cachedImage)
+	return true
+}
+
+// storeCachedImage tags the chaincode image as the cached image of its
+// package. Failing to do so only prevents reusing the image.
+func (vm *DockerVM) storeCachedImage(imageName, cacheKey string) {
+	if err := vm.Client.TagImage(imageName, docker.TagImageOptions{Repo: buildCacheRepository, Tag: cacheKey}); err != nil {
+		dockerLogger.Warningf("Failed to cache image %s as %s:%s: %s", imageName, buildCacheRepository, cacheKey, err)
+	}
+}
+
 // In order to support starting chaincode containers built with Fabric v1.4 and earlier,
 // we must check for the precense of the start.sh script for Node.js chaincode before
 // attempting to call it.
@@ -296,7 +375,7 @@ func (vm *DockerVM) Start(ccid string, ccType string, peerConnection *ccintf.Pee
 	env := vm.GetEnv(ccid, peerConnection.TLSConfig)
 	dockerLogger.Debugf("start container with env:\n\t%s", strings.Join(env, "\n\t"))
 
-	err = vm.createContainer(imageName, containerName, args, env)
+	err = vm.createContainer(imageName, containerName, args, env, vm.hostConfig(ccid))
 	if err != nil {
 		logger.Errorf("create container failed: %s", err)
 		return err
@@ -471,6 +471,32 @@ func TestBuild(t *testing.T) {
 		assert.Equal(t, 0, client.BuildImageCallCount())
 	})
 
+	t.Run("when the image exists for the target architecture", func(t *testing.T) {
+		client := &mock.DockerClient{}
+		client.InspectImageReturns(&docker.Image{Architecture: "arm64"}, nil)
+
+		dvm := &DockerVM{Client: client, BuildMetrics: buildMetrics, Architecture: "arm64"}
+		_, err := dvm.Build("chaincode-name:chaincode-version", md, bytes.NewBuffer([]byte("code-package")))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0, client.BuildImageCallCount())
+	})
+
+	t.Run("when the image exists for another architecture", func(t *testing.T) {
+		client := &mock.DockerClient{}
+		client.InspectImageReturns(&docker.Image{Architecture: "amd64"}, nil)
+
+		fakePlatformBuilder := &mock.PlatformBuilder{}
+		fakePlatformBuilder.GenerateDockerBuildReturns(&bytes.Buffer{}, nil)
+
+		dvm := &DockerVM{Client: client, BuildMetrics: buildMetrics, PlatformBuilder: fakePlatformBuilder, Architecture: "arm64"}
+		_, err := dvm.Build("chaincode-name:chaincode-version", md, bytes.NewBuffer([]byte("code-package")))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, fakePlatformBuilder.GenerateDockerBuildCallCount())
+		assert.Equal(t, 1, client.BuildImageCallCount())
+	})
+
 	t.Run("when the platform builder fails", func(t *testing.T) {
 		client := &mock.DockerClient{}
 		client.InspectImageReturns(nil, docker.ErrNoSuchImage)
@@ -500,6 +526,88 @@ func TestBuild(t *testing.T) {
 		assert.Equal(t, 1, client.BuildImageCallCount())
 		assert.EqualError(t, err, "docker image build failed: no-build-for-you")
 	})
+
+	t.Run("when the build cache has no image of the package", func(t *testing.T) {
+		client := &mock.DockerClient{}
+		client.InspectImageReturns(nil, docker.ErrNoSuchImage)
+
+		fakePlatformBuilder := &mock.PlatformBuilder{}
+		fakePlatformBuilder.GenerateDockerBuildReturns(&bytes.Buffer{}, nil)
+
+		dvm := &DockerVM{Client: client, BuildMetrics: buildMetrics, PlatformBuilder: fakePlatformBuilder, BuildCacheEnabled: true, BuilderVersion: "2.2.0"}
+		_, err := dvm.Build("chaincode-name:chaincode-version", md, bytes.NewBuffer([]byte("code-package")))
+		assert.NoError(t, err)
+
+		require.Equal(t, 2, client.InspectImageCallCount())
+		cachedImage := client.InspectImageArgsForCall(1)
+		assert.Regexp(t, "^fabric-buildcache:[0-9a-f]{64}$", cachedImage)
+
+		require.Equal(t, 1, fakePlatformBuilder.GenerateDockerBuildCallCount())
+		_, _, codePackageStream := fakePlatformBuilder.GenerateDockerBuildArgsForCall(0)
+		codePackage, err := ioutil.ReadAll(codePackageStream)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("code-package"), codePackage)
+		assert.Equal(t, 1, client.BuildImageCallCount())
+
+		require.Equal(t, 1, client.TagImageCallCount())
+		name, opts := client.TagImageArgsForCall(0)
+		assert.Equal(t, client.InspectImageArgsForCall(0), name)
+		assert.Equal(t, cachedImage, opts.Repo+":"+opts.Tag)
+	})
+
+	t.Run("when the build cache has an image of the package", func(t *testing.T) {
+		client := &mock.DockerClient{}
+		client.InspectImageReturnsOnCall(0, nil, docker.ErrNoSuchImage)
+		client.InspectImageReturnsOnCall(1, &docker.Image{}, nil)
+
+		fakePlatformBuilder := &mock.PlatformBuilder{}
+
+		dvm := &DockerVM{Client: client, BuildMetrics: buildMetrics, PlatformBuilder: fakePlatformBuilder, BuildCacheEnabled: true}
+		_, err := dvm.Build("chaincode-name:chaincode-version", md, bytes.NewBuffer([]byte("code-package")))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0, fakePlatformBuilder.GenerateDockerBuildCallCount())
+		assert.Equal(t, 0, client.BuildImageCallCount())
+
+		require.Equal(t, 1, client.TagImageCallCount())
+		name, opts := client.TagImageArgsForCall(0)
+		assert.Equal(t, client.InspectImageArgsForCall(1), name)
+		assert.Equal(t, client.InspectImageArgsForCall(0), opts.Repo)
+		assert.Equal(t, "latest", opts.Tag)
+	})
+
+	t.Run("when the builder version changes", func(t *testing.T) {
+		client := &mock.DockerClient{}
+		client.InspectImageReturns(nil, docker.ErrNoSuchImage)
+
+		fakePlatformBuilder := &mock.PlatformBuilder{}
+		fakePlatformBuilder.GenerateDockerBuildReturns(&bytes.Buffer{}, nil)
+
+		dvm := &DockerVM{Client: client, BuildMetrics: buildMetrics, PlatformBuilder: fakePlatformBuilder, BuildCacheEnabled: true, BuilderVersion: "2.2.0"}
+		_, err := dvm.Build("chaincode-name:chaincode-version", md, bytes.NewBuffer([]byte("code-package")))
+		assert.NoError(t, err)
+		dvm.BuilderVersion = "2.2.1"
+		_, err = dvm.Build("chaincode-name:chaincode-version", md, bytes.NewBuffer([]byte("code-package")))
+		assert.NoError(t, err)
+
+		require.Equal(t, 4, client.InspectImageCallCount())
+		assert.NotEqual(t, client.InspectImageArgsForCall(1), client.InspectImageArgsForCall(3))
+	})
+
+	t.Run("when caching the image fails", func(t *testing.T) {
+		client := &mock.DockerClient{}
+		client.InspectImageReturns(nil, docker.ErrNoSuchImage)
+		client.TagImageReturns(errors.New("no-tag-for-you"))
+
+		fakePlatformBuilder := &mock.PlatformBuilder{}
+		fakePlatformBuilder.GenerateDockerBuildReturns(&bytes.Buffer{}, nil)
+
+		dvm := &DockerVM{Client: client, BuildMetrics: buildMetrics, PlatformBuilder: fakePlatformBuilder, BuildCacheEnabled: true}
+		_, err := dvm.Build("chaincode-name:chaincode-version", md, bytes.NewBuffer([]byte("code-package")))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, client.BuildImageCallCount())
+		assert.Equal(t, 1, client.TagImageCallCount())
+	})
 }
 
 type InMemBuilder struct{}
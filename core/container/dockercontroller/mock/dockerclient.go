@@ -114,6 +114,18 @@ type DockerClient struct {
 	stopContainerReturnsOnCall map[int]struct {
 		result1 error
 	}
+	TagImageStub        func(string, docker.TagImageOptions) error
+	tagImageMutex       sync.RWMutex
+	tagImageArgsForCall []struct {
+		arg1 string
+		arg2 docker.TagImageOptions
+	}
+	tagImageReturns struct {
+		result1 error
+	}
+	tagImageReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UploadToContainerStub        func(string, docker.UploadToContainerOptions) error
 	uploadToContainerMutex       sync.RWMutex
 	uploadToContainerArgsForCall []struct {
@@ -691,6 +703,67 @@ func (fake *DockerClient) StopContainerReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *DockerClient) TagImage(arg1 string, arg2 docker.TagImageOptions) error {
+	fake.tagImageMutex.Lock()
+	ret, specificReturn := fake.tagImageReturnsOnCall[len(fake.tagImageArgsForCall)]
+	fake.tagImageArgsForCall = append(fake.tagImageArgsForCall, struct {
+		arg1 string
+		arg2 docker.TagImageOptions
+	}{arg1, arg2})
+	fake.recordInvocation("TagImage", []interface{}{arg1, arg2})
+	fake.tagImageMutex.Unlock()
+	if fake.TagImageStub != nil {
+		return fake.TagImageStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.tagImageReturns
+	return fakeReturns.result1
+}
+
+func (fake *DockerClient) TagImageCallCount() int {
+	fake.tagImageMutex.RLock()
+	defer fake.tagImageMutex.RUnlock()
+	return len(fake.tagImageArgsForCall)
+}
+
+func (fake *DockerClient) TagImageCalls(stub func(string, docker.TagImageOptions) error) {
+	fake.tagImageMutex.Lock()
+	defer fake.tagImageMutex.Unlock()
+	fake.TagImageStub = stub
+}
+
+func (fake *DockerClient) TagImageArgsForCall(i int) (string, docker.TagImageOptions) {
+	fake.tagImageMutex.RLock()
+	defer fake.tagImageMutex.RUnlock()
+	argsForCall := fake.tagImageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *DockerClient) TagImageReturns(result1 error) {
+	fake.tagImageMutex.Lock()
+	defer fake.tagImageMutex.Unlock()
+	fake.TagImageStub = nil
+	fake.tagImageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DockerClient) TagImageReturnsOnCall(i int, result1 error) {
+	fake.tagImageMutex.Lock()
+	defer fake.tagImageMutex.Unlock()
+	fake.TagImageStub = nil
+	if fake.tagImageReturnsOnCall == nil {
+		fake.tagImageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.tagImageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *DockerClient) UploadToContainer(arg1 string, arg2 docker.UploadToContainerOptions) error {
 	fake.uploadToContainerMutex.Lock()
 	ret, specificReturn := fake.uploadToContainerReturnsOnCall[len(fake.uploadToContainerArgsForCall)]
@@ -836,6 +909,8 @@ func (fake *DockerClient) Invocations() map[string][][]interface{} {
 	defer fake.startContainerMutex.RUnlock()
 	fake.stopContainerMutex.RLock()
 	defer fake.stopContainerMutex.RUnlock()
+	fake.tagImageMutex.RLock()
+	defer fake.tagImageMutex.RUnlock()
 	fake.uploadToContainerMutex.RLock()
 	defer fake.uploadToContainerMutex.RUnlock()
 	fake.waitContainerMutex.RLock()
@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dockercontroller
+
+import (
+	"strings"
+
+	docker "github.com/fsouza/go-dockerclient"
+)
+
+// Unconfined disables the seccomp or AppArmor confinement of a container
+const Unconfined = "unconfined"
+
+// SandboxProfile is the syscall policy applied to a chaincode container.
+type SandboxProfile struct {
+	// Seccomp is the JSON seccomp profile of the container, or Unconfined.
+	// The default seccomp profile of Docker applies if it is empty.
+	Seccomp string
+	// AppArmor is the name of the AppArmor profile of the container, which must
+	// be loaded on the host, or Unconfined. The default AppArmor profile of
+	// Docker applies if it is empty.
+	AppArmor string
+	// NoNewPrivileges prevents the processes of the container from gaining
+	// privileges, e.g. through setuid binaries.
+	NoNewPrivileges bool
+}
+
+// SandboxPolicy selects the syscall policy of the chaincode containers.
+type SandboxPolicy struct {
+	// Default is the profile of the chaincodes without an override.
+	Default SandboxProfile
+	// Overrides are the profiles replacing the default one for the chaincodes
+	// with the given package labels, or names for the chaincodes installed
+	// with the legacy lifecycle.
+	Overrides map[string]SandboxProfile
+}
+
+// profile returns the sandbox profile of the chaincode with the given ID
+func (p *SandboxPolicy) profile(ccid string) SandboxProfile {
+	label := ccid
+	if i := strings.LastIndex(ccid, ":"); i >= 0 {
+		label = ccid[:i]
+	}
+	if profile, ok := p.Overrides[label]; ok {
+		return profile
+	}
+	return p.Default
+}
+
+// securityOpts returns the docker security options applying the profile
+func (sp SandboxProfile) securityOpts() []string {
+	var opts []string
+	if sp.Seccomp != "" {
+		opts = append(opts, "seccomp="+sp.Seccomp)
+	}
+	if sp.AppArmor != "" {
+		opts = append(opts, "apparmor="+sp.AppArmor)
+	}
+	if sp.NoNewPrivileges {
+		opts = append(opts, "no-new-privileges")
+	}
+	return opts
+}
+
+// hostConfig returns the host config of the container of the given chaincode,
+// with the security options of its sandbox profile
+func (vm *DockerVM) hostConfig(ccid string) *docker.HostConfig {
+	if vm.Sandbox == nil {
+		return vm.HostConfig
+	}
+	opts := vm.Sandbox.profile(ccid).securityOpts()
+	if len(opts) == 0 {
+		return vm.HostConfig
+	}
+
+	hostConfig := &docker.HostConfig{}
+	if vm.HostConfig != nil {
+		*hostConfig = *vm.HostConfig
+	}
+	securityOpt := make([]string, 0, len(hostConfig.SecurityOpt)+len(opts))
+	securityOpt = append(securityOpt, hostConfig.SecurityOpt...)
+	hostConfig.SecurityOpt = append(securityOpt, opts...)
+	return hostConfig
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dockercontroller
+
+import (
+	"testing"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/dockercontroller/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxProfile(t *testing.T) {
+	policy := &SandboxPolicy{
+		Default: SandboxProfile{Seccomp: `{"defaultAction":"SCMP_ACT_ERRNO"}`, AppArmor: "fabric-chaincode", NoNewPrivileges: true},
+		Overrides: map[string]SandboxProfile{
+			"legacy":  {AppArmor: Unconfined},
+			"trusted": {},
+		},
+	}
+
+	assert.Equal(t, []string{`seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`, "apparmor=fabric-chaincode", "no-new-privileges"},
+		policy.profile("mycc:a5c0d7").securityOpts())
+	assert.Equal(t, []string{"apparmor=unconfined"}, policy.profile("legacy:1.0").securityOpts())
+	assert.Empty(t, policy.profile("trusted:a5c0d7").securityOpts())
+	assert.Equal(t, policy.Default, policy.profile("nocolon"))
+}
+
+func TestStartWithSandbox(t *testing.T) {
+	dockerClient := &mock.DockerClient{}
+	dockerClient.CreateContainerReturns(&docker.Container{}, nil)
+	hostConfig := &docker.HostConfig{NetworkMode: "host", SecurityOpt: []string{"label=disable"}}
+	dvm := DockerVM{
+		BuildMetrics: NewBuildMetrics(&disabled.Provider{}),
+		Client:       dockerClient,
+		HostConfig:   hostConfig,
+		Sandbox: &SandboxPolicy{
+			Default:   SandboxProfile{AppArmor: "fabric-chaincode"},
+			Overrides: map[string]SandboxProfile{"trusted": {}},
+		},
+	}
+
+	err := dvm.Start("mycc:a5c0d7", "GOLANG", &ccintf.PeerConnection{Address: "peer-address"})
+	require.NoError(t, err)
+	require.Equal(t, 1, dockerClient.CreateContainerCallCount())
+	opts := dockerClient.CreateContainerArgsForCall(0)
+	assert.Equal(t, "host", opts.HostConfig.NetworkMode)
+	assert.Equal(t, []string{"label=disable", "apparmor=fabric-chaincode"}, opts.HostConfig.SecurityOpt)
+	assert.Equal(t, []string{"label=disable"}, hostConfig.SecurityOpt, "the shared host config must not be modified")
+
+	err = dvm.Start("trusted:a5c0d7", "GOLANG", &ccintf.PeerConnection{Address: "peer-address"})
+	require.NoError(t, err)
+	assert.Same(t, hostConfig, dockerClient.CreateContainerArgsForCall(1).HostConfig)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// BuildCache is a persistent cache of the outputs of the external builders.
+// Each entry is a directory named after its key, see buildcache.Key, holding
+// the bld and release directories of a build. The cache may be shared by the
+// peers, e.g. on a network file system, so that a chaincode package installed
+// on many peers is built once, and is not built again when a peer is recreated.
+type BuildCache struct {
+	// Path is the directory of the cache.
+	Path string
+}
+
+// Restore copies the outputs cached under the key to the bld and release
+// directories of the build context. It returns false if the cache has no
+// outputs under the key.
+func (bc *BuildCache) Restore(key string, buildContext *BuildContext) (bool, error) {
+	entry := filepath.Join(bc.Path, key)
+	_, err := os.Stat(entry)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WithMessagef(err, "could not inspect build cache entry '%s'", entry)
+	}
+
+	if err := CopyDir(logger, filepath.Join(entry, "bld"), buildContext.BldDir); err != nil {
+		return false, err
+	}
+	if err := CopyDir(logger, filepath.Join(entry, "release"), buildContext.ReleaseDir); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Store copies the bld and release directories of the build context to the
+// cache under the key. The entry is assembled in a temporary directory and
+// then renamed, so that the peers sharing the cache never restore a partial
+// entry; if another peer stored the entry first, its outputs are kept.
+func (bc *BuildCache) Store(key string, buildContext *BuildContext) error {
+	entry := filepath.Join(bc.Path, key)
+	if _, err := os.Stat(entry); err == nil {
+		return nil
+	}
+
+	staging, err := ioutil.TempDir(bc.Path, ".staging-")
+	if err != nil {
+		return errors.WithMessage(err, "could not create build cache staging dir")
+	}
+	defer os.RemoveAll(staging)
+
+	if err := CopyDir(logger, buildContext.BldDir, filepath.Join(staging, "bld")); err != nil {
+		return err
+	}
+	if err := CopyDir(logger, buildContext.ReleaseDir, filepath.Join(staging, "release")); err != nil {
+		return err
+	}
+
+	if err := os.Rename(staging, entry); err != nil {
+		if _, statErr := os.Stat(entry); statErr == nil {
+			return nil
+		}
+		return errors.WithMessagef(err, "could not create build cache entry '%s'", entry)
+	}
+	return nil
+}
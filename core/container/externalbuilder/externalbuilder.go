@@ -9,6 +9,7 @@ package externalbuilder
 import (
 	"encoding/json"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"os"
@@ -18,6 +19,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/container/buildcache"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/pkg/errors"
@@ -46,6 +48,9 @@ type Detector struct {
 	DurablePath string
 	// Builders are the builders that detect and build processing will use.
 	Builders []*Builder
+	// BuildCache, when set, provides the outputs of a previous build of the
+	// same package by the same builder, so that the package is not built again.
+	BuildCache *BuildCache
 }
 
 // CachedBuild returns a build instance that was already built or nil when no
@@ -92,7 +97,9 @@ func (d *Detector) CachedBuild(ccid string) (*Instance, error) {
 // Before running the detect and build process, the detector first checks the
 // durable path for the results of a previous build for the provided package.
 // If found, the detect and build process is skipped and the existing instance
-// is returned.
+// is returned. Otherwise, when a build cache is set, the build process is
+// skipped if the cache holds the outputs of the detected builder for the
+// package.
 func (d *Detector) Build(ccid string, mdBytes []byte, codeStream io.Reader) (*Instance, error) {
 	// A small optimization: prevent exploding the build package out into the
 	// file system unless there are external builders defined.
@@ -109,6 +116,13 @@ func (d *Detector) Build(ccid string, mdBytes []byte, codeStream io.Reader) (*In
 		return i, nil
 	}
 
+	var digest hash.Hash
+	if d.BuildCache != nil {
+		digest = buildcache.NewDigest()
+		digest.Write(mdBytes)
+		codeStream = io.TeeReader(codeStream, digest)
+	}
+
 	buildContext, err := NewBuildContext(ccid, mdBytes, codeStream)
 	if err != nil {
 		return nil, errors.WithMessage(err, "could not create build context")
@@ -121,12 +135,36 @@ func (d *Detector) Build(ccid string, mdBytes []byte, codeStream io.Reader) (*In
 		return nil, nil
 	}
 
-	if err := builder.Build(buildContext); err != nil {
-		return nil, errors.WithMessage(err, "external builder failed to build")
+	var cacheKey string
+	restored := false
+	if d.BuildCache != nil {
+		// the digest covers the whole package, not just the part read by untar
+		if _, err := io.Copy(ioutil.Discard, codeStream); err != nil {
+			return nil, errors.WithMessage(err, "could not read chaincode package")
+		}
+		cacheKey = buildcache.Key(builder.Name, builder.Version, digest.Sum(nil))
+		restored, err = d.BuildCache.Restore(cacheKey, buildContext)
+		if err != nil {
+			logger.Warningf("could not restore the cached build of %s: %s", ccid, err)
+		}
 	}
 
-	if err := builder.Release(buildContext); err != nil {
-		return nil, errors.WithMessage(err, "external builder failed to release")
+	if restored {
+		logger.Infof("restored the build of %s by builder '%s' from the build cache", ccid, builder.Name)
+	} else {
+		if err := builder.Build(buildContext); err != nil {
+			return nil, errors.WithMessage(err, "external builder failed to build")
+		}
+
+		if err := builder.Release(buildContext); err != nil {
+			return nil, errors.WithMessage(err, "external builder failed to release")
+		}
+
+		if d.BuildCache != nil {
+			if err := d.BuildCache.Store(cacheKey, buildContext); err != nil {
+				logger.Warningf("could not add the build of %s to the build cache: %s", ccid, err)
+			}
+		}
 	}
 
 	durablePath := filepath.Join(d.DurablePath, SanitizeCCIDPath(ccid))
@@ -267,6 +305,7 @@ type Builder struct {
 	Location             string
 	Logger               *flogging.FabricLogger
 	Name                 string
+	Version              string
 	MSPID                string
 }
 
@@ -277,6 +316,7 @@ func CreateBuilders(builderConfs []peer.ExternalBuilder, mspid string) []*Builde
 		builders = append(builders, &Builder{
 			Location:             builderConf.Path,
 			Name:                 builderConf.Name,
+			Version:              builderConf.Version,
 			PropagateEnvironment: builderConf.PropagateEnvironment,
 			Logger:               logger.Named(builderConf.Name),
 			MSPID:                mspid,
@@ -158,6 +158,100 @@ var _ = Describe("externalbuilder", func() {
 			})
 		})
 
+		Describe("Build with a build cache", func() {
+			var cachePath string
+
+			BeforeEach(func() {
+				var err error
+				cachePath, err = ioutil.TempDir("", "build-cache")
+				Expect(err).NotTo(HaveOccurred())
+				detector.BuildCache = &externalbuilder.BuildCache{Path: cachePath}
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(cachePath)
+			})
+
+			// otherPeer returns a detector sharing the build cache, with its
+			// own durable path, building a new copy of the package.
+			otherPeer := func(builderVersion string) *externalbuilder.Detector {
+				otherDurablePath, err := ioutil.TempDir(durablePath, "other-peer")
+				Expect(err).NotTo(HaveOccurred())
+				codePackage, err = os.Open("testdata/normal_archive.tar.gz")
+				Expect(err).NotTo(HaveOccurred())
+
+				return &externalbuilder.Detector{
+					Builders: externalbuilder.CreateBuilders([]peer.ExternalBuilder{
+						{Path: "testdata/goodbuilder", Name: "goodbuilder", Version: builderVersion},
+					}, "mspid"),
+					DurablePath: otherDurablePath,
+					BuildCache:  detector.BuildCache,
+				}
+			}
+
+			cacheEntries := func() []string {
+				entries, err := ioutil.ReadDir(cachePath)
+				Expect(err).NotTo(HaveOccurred())
+				var names []string
+				for _, entry := range entries {
+					names = append(names, entry.Name())
+				}
+				return names
+			}
+
+			It("stores the build output in the cache", func() {
+				_, err := detector.Build("fake-package-id", md, codePackage)
+				Expect(err).NotTo(HaveOccurred())
+
+				entries := cacheEntries()
+				Expect(entries).To(HaveLen(1))
+				Expect(entries[0]).To(HaveLen(64))
+				Expect(filepath.Join(cachePath, entries[0], "bld")).To(BeADirectory())
+				Expect(filepath.Join(cachePath, entries[0], "release")).To(BeADirectory())
+			})
+
+			It("restores the build output of the same package from the cache", func() {
+				_, err := detector.Build("fake-package-id", md, codePackage)
+				Expect(err).NotTo(HaveOccurred())
+				entries := cacheEntries()
+				Expect(entries).To(HaveLen(1))
+				err = ioutil.WriteFile(filepath.Join(cachePath, entries[0], "bld", "cached"), []byte("cached"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				codePackage.Close()
+				instance, err := otherPeer("").Build("fake-package-id", md, codePackage)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(instance.Builder.Name).To(Equal("goodbuilder"))
+				Expect(filepath.Join(instance.BldDir, "cached")).To(BeARegularFile())
+				Expect(filepath.Join(filepath.Dir(instance.BldDir), "build-info.json")).To(BeARegularFile())
+			})
+
+			It("builds the package again with another version of the builder", func() {
+				_, err := detector.Build("fake-package-id", md, codePackage)
+				Expect(err).NotTo(HaveOccurred())
+				entries := cacheEntries()
+				Expect(entries).To(HaveLen(1))
+				err = ioutil.WriteFile(filepath.Join(cachePath, entries[0], "bld", "cached"), []byte("cached"), 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				codePackage.Close()
+				instance, err := otherPeer("2.0").Build("fake-package-id", md, codePackage)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filepath.Join(instance.BldDir, "cached")).NotTo(BeAnExistingFile())
+				Expect(cacheEntries()).To(HaveLen(2))
+			})
+
+			It("builds another package again", func() {
+				_, err := detector.Build("fake-package-id", md, codePackage)
+				Expect(err).NotTo(HaveOccurred())
+
+				codePackage.Close()
+				_, err = otherPeer("").Build("fake-package-id", []byte(`{"some":"fake-metadata","label":"other"}`), codePackage)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cacheEntries()).To(HaveLen(2))
+			})
+		})
+
 		Describe("CachedBuild", func() {
 			var existingInstance *externalbuilder.Instance
 
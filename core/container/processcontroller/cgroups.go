@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processcontroller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultCPUPeriod is the cgroup CPU period, in microseconds, CPUQuota is
+// relative to when no period is configured.
+const DefaultCPUPeriod = 100000
+
+// Cgroups creates a cgroup v2 per chaincode process beneath Root and applies
+// the configured resource limits to it.  Root must be a directory of the
+// unified cgroup hierarchy delegated to the peer.  Limits which are zero are
+// left unset.
+type Cgroups struct {
+	// Root is the parent cgroup of the chaincode cgroups.
+	Root string
+	// MemoryLimit is the memory limit of a chaincode process, in bytes.
+	MemoryLimit int64
+	// CPUQuota is the CPU time a chaincode process may use per CPUPeriod, in microseconds.
+	CPUQuota int64
+	// CPUPeriod is the CPU period, in microseconds.
+	CPUPeriod int64
+	// PidsLimit is the maximum number of processes and threads of a chaincode.
+	PidsLimit int64
+}
+
+// Cgroup is the cgroup of a chaincode process.
+type Cgroup struct {
+	Path string
+}
+
+// Create creates the cgroup with the given name and applies the limits to it.
+func (c *Cgroups) Create(name string) (*Cgroup, error) {
+	var controllers []string
+	var limits [][2]string
+	if c.CPUQuota > 0 {
+		period := c.CPUPeriod
+		if period == 0 {
+			period = DefaultCPUPeriod
+		}
+		controllers = append(controllers, "+cpu")
+		limits = append(limits, [2]string{"cpu.max", strconv.FormatInt(c.CPUQuota, 10) + " " + strconv.FormatInt(period, 10)})
+	}
+	if c.MemoryLimit > 0 {
+		controllers = append(controllers, "+memory")
+		limits = append(limits, [2]string{"memory.max", strconv.FormatInt(c.MemoryLimit, 10)})
+	}
+	if c.PidsLimit > 0 {
+		controllers = append(controllers, "+pids")
+		limits = append(limits, [2]string{"pids.max", strconv.FormatInt(c.PidsLimit, 10)})
+	}
+
+	if len(controllers) > 0 {
+		subtreeControl := filepath.Join(c.Root, "cgroup.subtree_control")
+		if err := ioutil.WriteFile(subtreeControl, []byte(strings.Join(controllers, " ")), 0644); err != nil {
+			return nil, errors.Wrapf(err, "could not enable controllers in %s", c.Root)
+		}
+	}
+
+	cg := &Cgroup{Path: filepath.Join(c.Root, name)}
+	if err := os.Mkdir(cg.Path, 0755); err != nil && !os.IsExist(err) {
+		return nil, errors.Wrapf(err, "could not create cgroup %s", cg.Path)
+	}
+
+	for _, limit := range limits {
+		if err := ioutil.WriteFile(filepath.Join(cg.Path, limit[0]), []byte(limit[1]), 0644); err != nil {
+			cg.Remove()
+			return nil, errors.Wrapf(err, "could not set %s of cgroup %s", limit[0], cg.Path)
+		}
+	}
+
+	return cg, nil
+}
+
+// AddProcess moves the process with the given pid into the cgroup.
+func (cg *Cgroup) AddProcess(pid int) error {
+	procs := filepath.Join(cg.Path, "cgroup.procs")
+	if err := ioutil.WriteFile(procs, []byte(strconv.Itoa(pid)), 0644); err != nil {
+		return errors.Wrapf(err, "could not add process %d to cgroup %s", pid, cg.Path)
+	}
+	return nil
+}
+
+// Remove removes the cgroup, which must no longer contain any process.
+func (cg *Cgroup) Remove() error {
+	if err := os.RemoveAll(cg.Path); err != nil {
+		return errors.Wrapf(err, "could not remove cgroup %s", cg.Path)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processcontroller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroups(t *testing.T) {
+	root, err := ioutil.TempDir("", "cgroups")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	readFile := func(path ...string) string {
+		contents, err := ioutil.ReadFile(filepath.Join(path...))
+		require.NoError(t, err)
+		return string(contents)
+	}
+
+	t.Run("with limits", func(t *testing.T) {
+		cgroups := &Cgroups{Root: root, MemoryLimit: 1 << 20, CPUQuota: 50000, PidsLimit: 100}
+		cg, err := cgroups.Create("cc1")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "cc1"), cg.Path)
+
+		assert.Equal(t, "+cpu +memory +pids", readFile(root, "cgroup.subtree_control"))
+		assert.Equal(t, "50000 100000", readFile(cg.Path, "cpu.max"))
+		assert.Equal(t, "1048576", readFile(cg.Path, "memory.max"))
+		assert.Equal(t, "100", readFile(cg.Path, "pids.max"))
+
+		require.NoError(t, cg.AddProcess(42))
+		assert.Equal(t, "42", readFile(cg.Path, "cgroup.procs"))
+
+		require.NoError(t, cg.Remove())
+		assert.NoDirExists(t, cg.Path)
+	})
+
+	t.Run("without limits", func(t *testing.T) {
+		cgroups := &Cgroups{Root: root, CPUQuota: 0}
+		cg, err := cgroups.Create("cc2")
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(cg.Path, "memory.max"))
+		assert.NoFileExists(t, filepath.Join(cg.Path, "cpu.max"))
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		cgroups := &Cgroups{Root: filepath.Join(root, "missing")}
+		_, err := cgroups.Create("cc3")
+		assert.Contains(t, err.Error(), "could not create cgroup")
+	})
+}
@@ -0,0 +1,495 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processcontroller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("chaincode.processcontroller")
+
+const (
+	// ChaincodeBinary is the name of the executable which a platform build
+	// command must produce in its output directory.
+	ChaincodeBinary = "chaincode"
+
+	// DefaultTermTimeout is the time a chaincode process is given to exit
+	// after SIGTERM before it is killed.
+	DefaultTermTimeout = 5 * time.Second
+)
+
+// Mutual TLS auth client key and cert file names in the launch directory of
+// a chaincode process
+const (
+	TLSClientKeyPath      string = "client.key"
+	TLSClientCertPath     string = "client.crt"
+	TLSClientKeyFile      string = "client_pem.key"
+	TLSClientCertFile     string = "client_pem.crt"
+	TLSClientRootCertFile string = "peer.crt"
+)
+
+// Platform describes how chaincode packages of a chaincode type are built
+// into executables which run on the peer host.
+type Platform struct {
+	// Type is the chaincode type, e.g. GOLANG, built by this platform.
+	Type string
+	// BuildCommand is invoked with the source, metadata and output directories
+	// as arguments and must write an executable named chaincode to the output
+	// directory.
+	BuildCommand string
+	// PropagateEnvironment lists the environment variables of the peer which
+	// are propagated to the build command and to the chaincode process.
+	PropagateEnvironment []string
+}
+
+// CreatePlatforms will construct platforms from the peer configuration.
+func CreatePlatforms(platformConfs []peer.ProcessLauncherPlatform) []*Platform {
+	var platforms []*Platform
+	for _, pc := range platformConfs {
+		platforms = append(platforms, &Platform{
+			Type:                 strings.ToUpper(pc.Type),
+			BuildCommand:         pc.BuildCommand,
+			PropagateEnvironment: pc.PropagateEnvironment,
+		})
+	}
+	return platforms
+}
+
+// User is the user and group a chaincode process runs as.
+type User struct {
+	UID uint32
+	GID uint32
+}
+
+// ProcessVM builds chaincode packages into host executables and launches them
+// as processes of the peer host, each confined to its own cgroup and,
+// optionally, to its own namespaces, so that chaincode can run where the
+// Docker daemon is not reachable by the peer.
+type ProcessVM struct {
+	// DurablePath is the file system location where chaincode executables are persisted.
+	DurablePath string
+	// MSPID is the local MSP ID passed to chaincode processes.
+	MSPID string
+	// Platforms are the chaincode types this launcher builds, other types are
+	// left to the next builder.
+	Platforms []*Platform
+	// User, when not nil, is the user chaincode processes run as instead of
+	// the user of the peer; this is only supported on Linux.
+	User *User
+	// Cgroups, when not nil, creates the cgroup each chaincode process runs in.
+	Cgroups *Cgroups
+	// Namespaces runs chaincode processes in new PID, IPC, UTS and mount
+	// namespaces; this is only supported on Linux.
+	Namespaces bool
+	// LoggingEnv is the logging configuration passed to chaincode processes.
+	LoggingEnv []string
+	// TermTimeout is the time a chaincode process is given to exit when stopped.
+	TermTimeout time.Duration
+}
+
+func (vm *ProcessVM) platform(ccType string) *Platform {
+	for _, platform := range vm.Platforms {
+		if strings.EqualFold(platform.Type, ccType) {
+			return platform
+		}
+	}
+	return nil
+}
+
+// Build builds the chaincode package into an executable, unless an executable
+// for the package was persisted by a previous build.  A nil instance is
+// returned when no platform is configured for the chaincode type.
+func (vm *ProcessVM) Build(ccid string, metadata *persistence.ChaincodePackageMetadata, codePackage io.Reader) (container.Instance, error) {
+	platform := vm.platform(metadata.Type)
+	if platform == nil {
+		logger.Debugf("no process launcher platform for chaincode type %s of %s", metadata.Type, ccid)
+		return nil, nil
+	}
+
+	durablePath := filepath.Join(vm.DurablePath, externalbuilder.SanitizeCCIDPath(ccid))
+	_, err := os.Stat(filepath.Join(durablePath, ChaincodeBinary))
+	if err == nil {
+		return vm.newInstance(ccid, platform, durablePath), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.WithMessage(err, "existing build detected, but something went wrong inspecting it")
+	}
+
+	if err := vm.build(ccid, platform, metadata, codePackage, durablePath); err != nil {
+		return nil, err
+	}
+
+	return vm.newInstance(ccid, platform, durablePath), nil
+}
+
+func (vm *ProcessVM) build(ccid string, platform *Platform, metadata *persistence.ChaincodePackageMetadata, codePackage io.Reader, durablePath string) error {
+	scratchDir, err := ioutil.TempDir("", "fabric-"+externalbuilder.SanitizeCCIDPath(ccid))
+	if err != nil {
+		return errors.Wrap(err, "could not create temp dir")
+	}
+	defer os.RemoveAll(scratchDir)
+
+	sourceDir := filepath.Join(scratchDir, "src")
+	if err := os.Mkdir(sourceDir, 0700); err != nil {
+		return errors.Wrap(err, "could not create source dir")
+	}
+	if err := externalbuilder.Untar(codePackage, sourceDir); err != nil {
+		return errors.WithMessage(err, "could not untar source package")
+	}
+
+	metadataDir := filepath.Join(scratchDir, "metadata")
+	if err := os.Mkdir(metadataDir, 0700); err != nil {
+		return errors.Wrap(err, "could not create metadata dir")
+	}
+	mdBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Join(metadataDir, "metadata.json"), mdBytes, 0600); err != nil {
+		return errors.Wrap(err, "could not write metadata file")
+	}
+
+	// The output is created next to its durable location so that it can be
+	// moved there atomically once the build succeeds.
+	if err := os.MkdirAll(vm.DurablePath, 0711); err != nil {
+		return errors.Wrap(err, "could not create durable path")
+	}
+	outputDir, err := ioutil.TempDir(vm.DurablePath, "build-")
+	if err != nil {
+		return errors.Wrap(err, "could not create output dir")
+	}
+	defer os.RemoveAll(outputDir)
+
+	cmd := newCommand(platform, platform.BuildCommand, sourceDir, metadataDir, outputDir)
+	sess, err := externalbuilder.Start(logger.With("ccid", ccid), cmd)
+	if err != nil {
+		return errors.Wrapf(err, "build command for platform %s failed to start", platform.Type)
+	}
+	if err := sess.Wait(); err != nil {
+		return errors.Wrapf(err, "build command for platform %s failed", platform.Type)
+	}
+
+	fi, err := os.Stat(filepath.Join(outputDir, ChaincodeBinary))
+	if err != nil {
+		return errors.Wrapf(err, "build command for platform %s did not produce an executable", platform.Type)
+	}
+	if !fi.Mode().IsRegular() || fi.Mode().Perm()&0100 == 0 {
+		return errors.Errorf("build output for platform %s is not an executable file", platform.Type)
+	}
+
+	if vm.User != nil {
+		// the chaincode user executes the chaincode, but may not modify it
+		if err := grantExecute(outputDir, vm.User.GID); err != nil {
+			return errors.WithMessagef(err, "could not grant execution of the build output of chaincode %s", ccid)
+		}
+	}
+
+	if err := os.Rename(outputDir, durablePath); err != nil {
+		return errors.Wrap(err, "could not move build output to durable path")
+	}
+
+	return nil
+}
+
+func (vm *ProcessVM) newInstance(ccid string, platform *Platform, durablePath string) *Instance {
+	termTimeout := vm.TermTimeout
+	if termTimeout == 0 {
+		termTimeout = DefaultTermTimeout
+	}
+	return &Instance{
+		CCID:        ccid,
+		Binary:      filepath.Join(durablePath, ChaincodeBinary),
+		Platform:    platform,
+		MSPID:       vm.MSPID,
+		User:        vm.User,
+		Cgroups:     vm.Cgroups,
+		Namespaces:  vm.Namespaces,
+		LoggingEnv:  vm.LoggingEnv,
+		TermTimeout: termTimeout,
+	}
+}
+
+// grantExecute hands the files of the build output directory to the group of
+// the chaincode user, which may read and execute them.
+func grantExecute(outputDir string, gid uint32) error {
+	return filepath.Walk(outputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := os.Chown(path, -1, int(gid)); err != nil {
+			return errors.Wrapf(err, "could not change the group of %s", path)
+		}
+		mode := info.Mode().Perm() | 0040
+		if info.IsDir() || info.Mode().Perm()&0100 != 0 {
+			mode |= 0010
+		}
+		return errors.Wrapf(os.Chmod(path, mode), "could not change the mode of %s", path)
+	})
+}
+
+// newCommand creates an exec.Cmd whose environment is pruned down to the
+// variables the platform propagates and the DefaultPropagateEnvironment.
+func newCommand(platform *Platform, name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Env = []string{}
+	for _, key := range propagateEnvironment(platform) {
+		if val, ok := os.LookupEnv(key); ok {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, val))
+		}
+	}
+	return cmd
+}
+
+func propagateEnvironment(platform *Platform) []string {
+	keys := append([]string{}, platform.PropagateEnvironment...)
+	for _, variable := range externalbuilder.DefaultPropagateEnvironment {
+		found := false
+		for _, key := range keys {
+			if key == variable {
+				found = true
+				break
+			}
+		}
+		if !found {
+			keys = append(keys, variable)
+		}
+	}
+	return keys
+}
+
+// Instance is a chaincode executable built by a ProcessVM.
+type Instance struct {
+	CCID        string
+	Binary      string
+	Platform    *Platform
+	MSPID       string
+	User        *User
+	Cgroups     *Cgroups
+	Namespaces  bool
+	LoggingEnv  []string
+	TermTimeout time.Duration
+
+	mutex   sync.Mutex
+	session *externalbuilder.Session
+}
+
+// ChaincodeServerInfo returns nil as chaincode processes always connect to the peer.
+func (i *Instance) ChaincodeServerInfo() (*ccintf.ChaincodeServerInfo, error) {
+	return nil, nil
+}
+
+// holdScript is run by the shell a chaincode process is started as when it
+// is confined to a cgroup. The shell waits for the line written once the
+// process was moved into its cgroup before it executes the chaincode, so that
+// the chaincode never runs outside of its cgroup. It exits when the pipe is
+// closed without the line, when the peer fails to move it or dies.
+const holdScript = `read ready <&3 && exec "$0" "$@" 3<&-`
+
+// Start launches the chaincode executable in its own cgroup, if configured,
+// as the chaincode user, if configured, with the peer connection details
+// passed through the environment.
+func (i *Instance) Start(peerConnection *ccintf.PeerConnection) error {
+	attr, err := sysProcAttr(i.Namespaces, i.User)
+	if err != nil {
+		return errors.WithMessagef(err, "could not launch chaincode %s", i.CCID)
+	}
+
+	launchDir, err := ioutil.TempDir("", "fabric-run")
+	if err != nil {
+		return errors.WithMessage(err, "could not create temp run dir")
+	}
+
+	env, err := i.env(launchDir, peerConnection.TLSConfig)
+	if err != nil {
+		os.RemoveAll(launchDir)
+		return err
+	}
+	if i.User != nil {
+		if err := chownAll(launchDir, i.User); err != nil {
+			os.RemoveAll(launchDir)
+			return errors.WithMessagef(err, "could not hand the run dir of chaincode %s to its user", i.CCID)
+		}
+	}
+
+	var cg *Cgroup
+	if i.Cgroups != nil {
+		cg, err = i.Cgroups.Create(externalbuilder.SanitizeCCIDPath(i.CCID))
+		if err != nil {
+			os.RemoveAll(launchDir)
+			return errors.WithMessagef(err, "could not create cgroup for chaincode %s", i.CCID)
+		}
+	}
+
+	cleanup := func(error) {
+		os.RemoveAll(launchDir)
+		if cg != nil {
+			if err := cg.Remove(); err != nil {
+				logger.Warningf("Failed removing cgroup of chaincode %s: %s", i.CCID, err)
+			}
+		}
+	}
+
+	args := []string{fmt.Sprintf("-peer.address=%s", peerConnection.Address)}
+	cmd := exec.Command(i.Binary, args...)
+	var hold, release *os.File
+	if cg != nil {
+		hold, release, err = os.Pipe()
+		if err != nil {
+			cleanup(err)
+			return errors.Wrapf(err, "could not create the pipe holding chaincode %s", i.CCID)
+		}
+		defer release.Close()
+		cmd = exec.Command("/bin/sh", append([]string{"-c", holdScript, i.Binary}, args...)...)
+		cmd.ExtraFiles = []*os.File{hold}
+	}
+	cmd.Dir = launchDir
+	cmd.Env = env
+	cmd.SysProcAttr = attr
+
+	sess, err := externalbuilder.Start(logger.With("ccid", i.CCID), cmd, cleanup)
+	if hold != nil {
+		hold.Close()
+	}
+	if err != nil {
+		cleanup(err)
+		return errors.Wrapf(err, "chaincode %s failed to start", i.CCID)
+	}
+
+	if cg != nil {
+		if err := cg.AddProcess(cmd.Process.Pid); err != nil {
+			sess.Signal(syscall.SIGKILL)
+			return errors.WithMessagef(err, "could not move chaincode %s into its cgroup", i.CCID)
+		}
+		if _, err := release.Write([]byte("\n")); err != nil {
+			sess.Signal(syscall.SIGKILL)
+			return errors.Wrapf(err, "could not release chaincode %s", i.CCID)
+		}
+	}
+
+	i.mutex.Lock()
+	i.session = sess
+	i.mutex.Unlock()
+
+	return nil
+}
+
+// chownAll hands the directory and the files within it to the user.
+func chownAll(dir string, user *User) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return errors.Wrapf(os.Chown(path, int(user.UID), int(user.GID)), "could not change the owner of %s", path)
+	})
+}
+
+func (i *Instance) env(launchDir string, tlsConfig *ccintf.TLSConfig) ([]string, error) {
+	env := []string{}
+	for _, key := range propagateEnvironment(i.Platform) {
+		if val, ok := os.LookupEnv(key); ok {
+			env = append(env, fmt.Sprintf("%s=%s", key, val))
+		}
+	}
+	env = append(env, fmt.Sprintf("CORE_CHAINCODE_ID_NAME=%s", i.CCID))
+	env = append(env, i.LoggingEnv...)
+	env = append(env, fmt.Sprintf("CORE_PEER_LOCALMSPID=%s", i.MSPID))
+
+	if tlsConfig == nil {
+		return append(env, "CORE_PEER_TLS_ENABLED=false"), nil
+	}
+
+	// Note, as in the docker controller, 2 of the TLS artifacts are base64 encoded
+	// but not the other for historical reasons
+	files := map[string][]byte{
+		TLSClientKeyPath:      []byte(base64.StdEncoding.EncodeToString(tlsConfig.ClientKey)),
+		TLSClientCertPath:     []byte(base64.StdEncoding.EncodeToString(tlsConfig.ClientCert)),
+		TLSClientKeyFile:      tlsConfig.ClientKey,
+		TLSClientCertFile:     tlsConfig.ClientCert,
+		TLSClientRootCertFile: tlsConfig.RootCert,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(launchDir, name), contents, 0600); err != nil {
+			return nil, errors.Wrapf(err, "could not write %s", name)
+		}
+	}
+
+	return append(env,
+		"CORE_PEER_TLS_ENABLED=true",
+		fmt.Sprintf("CORE_TLS_CLIENT_KEY_PATH=%s", filepath.Join(launchDir, TLSClientKeyPath)),
+		fmt.Sprintf("CORE_TLS_CLIENT_CERT_PATH=%s", filepath.Join(launchDir, TLSClientCertPath)),
+		fmt.Sprintf("CORE_TLS_CLIENT_KEY_FILE=%s", filepath.Join(launchDir, TLSClientKeyFile)),
+		fmt.Sprintf("CORE_TLS_CLIENT_CERT_FILE=%s", filepath.Join(launchDir, TLSClientCertFile)),
+		fmt.Sprintf("CORE_PEER_TLS_ROOTCERT_FILE=%s", filepath.Join(launchDir, TLSClientRootCertFile)),
+	), nil
+}
+
+func (i *Instance) getSession() *externalbuilder.Session {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.session
+}
+
+// Stop signals the process to terminate with SIGTERM. If the process doesn't
+// terminate within TermTimeout, the process is killed with SIGKILL.
+func (i *Instance) Stop() error {
+	sess := i.getSession()
+	if sess == nil {
+		return errors.Errorf("instance has not been started")
+	}
+
+	done := make(chan struct{})
+	go func() { sess.Wait(); close(done) }()
+
+	sess.Signal(syscall.SIGTERM)
+	select {
+	case <-time.After(i.TermTimeout):
+		sess.Signal(syscall.SIGKILL)
+	case <-done:
+		return nil
+	}
+
+	select {
+	case <-time.After(5 * time.Second):
+		return errors.Errorf("failed to stop chaincode %s", i.CCID)
+	case <-done:
+		return nil
+	}
+}
+
+// Wait waits for the chaincode process to exit and returns its exit code.
+func (i *Instance) Wait() (int, error) {
+	sess := i.getSession()
+	if sess == nil {
+		return -1, errors.Errorf("instance was not successfully started")
+	}
+
+	err := sess.Wait()
+	err = errors.Wrapf(err, "chaincode %s exited", i.CCID)
+	if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+		return exitErr.ExitCode(), err
+	}
+	return 0, err
+}
@@ -0,0 +1,289 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processcontroller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChaincode = `#!/bin/sh
+env > "$CHAINCODE_OUTPUT"
+echo "args=$@" >> "$CHAINCODE_OUTPUT"
+if [ -n "$CHAINCODE_SLEEP" ]; then
+    exec sleep "$CHAINCODE_SLEEP"
+fi
+exit 3
+`
+
+func codePackage(t *testing.T, files map[string]string) io.Reader {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, contents := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     int64(len(contents)),
+			Mode:     0755,
+		})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf
+}
+
+func newTestVM(t *testing.T) (*ProcessVM, string, func()) {
+	tempDir, err := ioutil.TempDir("", "processcontroller")
+	require.NoError(t, err)
+
+	buildCommand, err := filepath.Abs("testdata/build")
+	require.NoError(t, err)
+
+	vm := &ProcessVM{
+		DurablePath: filepath.Join(tempDir, "builds"),
+		MSPID:       "test-mspid",
+		Platforms: CreatePlatforms([]peer.ProcessLauncherPlatform{
+			{
+				Type:                 "test",
+				BuildCommand:         buildCommand,
+				PropagateEnvironment: []string{"CHAINCODE_OUTPUT", "CHAINCODE_SLEEP"},
+			},
+		}),
+		LoggingEnv:  []string{"CORE_CHAINCODE_LOGGING_LEVEL=info"},
+		TermTimeout: time.Second,
+	}
+	return vm, tempDir, func() { os.RemoveAll(tempDir) }
+}
+
+var testMetadata = &persistence.ChaincodePackageMetadata{Type: "test", Path: "path", Label: "label"}
+
+func TestBuild(t *testing.T) {
+	vm, _, cleanup := newTestVM(t)
+	defer cleanup()
+
+	t.Run("unknown chaincode type", func(t *testing.T) {
+		instance, err := vm.Build("cc:1", &persistence.ChaincodePackageMetadata{Type: "golang"}, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, instance)
+	})
+
+	t.Run("build and reuse", func(t *testing.T) {
+		instance, err := vm.Build("cc:1", testMetadata, codePackage(t, map[string]string{"chaincode.sh": testChaincode}))
+		require.NoError(t, err)
+		require.NotNil(t, instance)
+		binary := filepath.Join(vm.DurablePath, "cc-1", ChaincodeBinary)
+		assert.Equal(t, binary, instance.(*Instance).Binary)
+		assert.FileExists(t, binary)
+
+		// the code package is not read when a previous build exists
+		instance, err = vm.Build("cc:1", testMetadata, strings.NewReader("garbage"))
+		require.NoError(t, err)
+		assert.Equal(t, binary, instance.(*Instance).Binary)
+	})
+
+	t.Run("build command fails", func(t *testing.T) {
+		_, err := vm.Build("cc:2", testMetadata, codePackage(t, map[string]string{"fail": ""}))
+		assert.EqualError(t, err, "build command for platform TEST failed: exit status 1")
+		assert.NoFileExists(t, filepath.Join(vm.DurablePath, "cc-2", ChaincodeBinary))
+	})
+
+	t.Run("no executable", func(t *testing.T) {
+		_, err := vm.Build("cc:3", testMetadata, codePackage(t, map[string]string{"noop": ""}))
+		assert.Contains(t, err.Error(), "build command for platform TEST did not produce an executable")
+	})
+
+	t.Run("bad code package", func(t *testing.T) {
+		_, err := vm.Build("cc:4", testMetadata, strings.NewReader("garbage"))
+		assert.Contains(t, err.Error(), "could not untar source package")
+	})
+}
+
+func TestInstanceStartAndWait(t *testing.T) {
+	vm, tempDir, cleanup := newTestVM(t)
+	defer cleanup()
+
+	outputFile := filepath.Join(tempDir, "output")
+	os.Setenv("CHAINCODE_OUTPUT", outputFile)
+	defer os.Unsetenv("CHAINCODE_OUTPUT")
+
+	instance, err := vm.Build("cc:1", testMetadata, codePackage(t, map[string]string{"chaincode.sh": testChaincode}))
+	require.NoError(t, err)
+
+	_, err = instance.Wait()
+	assert.EqualError(t, err, "instance was not successfully started")
+	assert.EqualError(t, instance.Stop(), "instance has not been started")
+
+	serverInfo, err := instance.ChaincodeServerInfo()
+	assert.NoError(t, err)
+	assert.Nil(t, serverInfo)
+
+	err = instance.Start(&ccintf.PeerConnection{
+		Address: "peer:7052",
+		TLSConfig: &ccintf.TLSConfig{
+			ClientKey:  []byte("key"),
+			ClientCert: []byte("cert"),
+			RootCert:   []byte("root"),
+		},
+	})
+	require.NoError(t, err)
+
+	code, err := instance.Wait()
+	assert.Equal(t, 3, code)
+	assert.EqualError(t, err, "chaincode cc:1 exited: exit status 3")
+
+	output, err := ioutil.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "args=-peer.address=peer:7052\n")
+	assert.Contains(t, string(output), "CORE_CHAINCODE_ID_NAME=cc:1\n")
+	assert.Contains(t, string(output), "CORE_PEER_LOCALMSPID=test-mspid\n")
+	assert.Contains(t, string(output), "CORE_CHAINCODE_LOGGING_LEVEL=info\n")
+	assert.Contains(t, string(output), "CORE_PEER_TLS_ENABLED=true\n")
+	assert.Contains(t, string(output), "CORE_PEER_TLS_ROOTCERT_FILE=")
+}
+
+func TestInstanceStop(t *testing.T) {
+	vm, tempDir, cleanup := newTestVM(t)
+	defer cleanup()
+
+	os.Setenv("CHAINCODE_OUTPUT", filepath.Join(tempDir, "output"))
+	defer os.Unsetenv("CHAINCODE_OUTPUT")
+	os.Setenv("CHAINCODE_SLEEP", "60")
+	defer os.Unsetenv("CHAINCODE_SLEEP")
+
+	instance, err := vm.Build("cc:1", testMetadata, codePackage(t, map[string]string{"chaincode.sh": testChaincode}))
+	require.NoError(t, err)
+
+	err = instance.Start(&ccintf.PeerConnection{Address: "peer:7052"})
+	require.NoError(t, err)
+
+	assert.NoError(t, instance.Stop())
+	_, err = instance.Wait()
+	assert.Error(t, err)
+}
+
+func TestInstanceCgroup(t *testing.T) {
+	vm, tempDir, cleanup := newTestVM(t)
+	defer cleanup()
+
+	os.Setenv("CHAINCODE_OUTPUT", filepath.Join(tempDir, "output"))
+	defer os.Unsetenv("CHAINCODE_OUTPUT")
+	os.Setenv("CHAINCODE_SLEEP", "60")
+	defer os.Unsetenv("CHAINCODE_SLEEP")
+
+	// a plain directory stands in for the cgroup hierarchy
+	cgroupRoot := filepath.Join(tempDir, "cgroup")
+	require.NoError(t, os.Mkdir(cgroupRoot, 0755))
+	vm.Cgroups = &Cgroups{Root: cgroupRoot, MemoryLimit: 1024, PidsLimit: 10}
+
+	instance, err := vm.Build("cc:1", testMetadata, codePackage(t, map[string]string{"chaincode.sh": testChaincode}))
+	require.NoError(t, err)
+
+	err = instance.Start(&ccintf.PeerConnection{Address: "peer:7052"})
+	require.NoError(t, err)
+
+	procs, err := ioutil.ReadFile(filepath.Join(cgroupRoot, "cc-1", "cgroup.procs"))
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(string(procs))
+	require.NoError(t, err)
+	assert.NotZero(t, pid)
+
+	require.NoError(t, instance.Stop())
+	instance.Wait()
+	assert.NoDirExists(t, filepath.Join(cgroupRoot, "cc-1"))
+}
+
+func TestInstanceCgroupFailure(t *testing.T) {
+	vm, tempDir, cleanup := newTestVM(t)
+	defer cleanup()
+
+	outputFile := filepath.Join(tempDir, "output")
+	os.Setenv("CHAINCODE_OUTPUT", outputFile)
+	defer os.Unsetenv("CHAINCODE_OUTPUT")
+
+	// the process cannot be moved into a cgroup whose procs file is a directory
+	cgroupRoot := filepath.Join(tempDir, "cgroup")
+	require.NoError(t, os.MkdirAll(filepath.Join(cgroupRoot, "cc-1", "cgroup.procs"), 0755))
+	vm.Cgroups = &Cgroups{Root: cgroupRoot}
+
+	instance, err := vm.Build("cc:1", testMetadata, codePackage(t, map[string]string{"chaincode.sh": testChaincode}))
+	require.NoError(t, err)
+
+	err = instance.Start(&ccintf.PeerConnection{Address: "peer:7052"})
+	assert.Contains(t, err.Error(), "could not move chaincode cc:1 into its cgroup")
+
+	// the chaincode never ran outside of its cgroup
+	cgroupRemoved := func() bool {
+		_, err := os.Stat(filepath.Join(cgroupRoot, "cc-1"))
+		return os.IsNotExist(err)
+	}
+	assert.Eventually(t, cgroupRemoved, 5*time.Second, 10*time.Millisecond)
+	assert.NoFileExists(t, outputFile)
+}
+
+const userChaincode = `#!/bin/sh
+echo "uid=$(id -u) gid=$(id -g) groups=$(id -G)" > "$CHAINCODE_OUTPUT"
+cat "$CORE_PEER_TLS_ROOTCERT_FILE" >> "$CHAINCODE_OUTPUT"
+`
+
+func TestInstanceUser(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing the user of a chaincode process requires root")
+	}
+
+	vm, tempDir, cleanup := newTestVM(t)
+	defer cleanup()
+	vm.User = &User{UID: 65534, GID: 65534}
+
+	// the chaincode user can reach its binary and write its output
+	require.NoError(t, os.Chmod(tempDir, 0755))
+	outputDir := filepath.Join(tempDir, "output")
+	require.NoError(t, os.Mkdir(outputDir, 0777))
+	require.NoError(t, os.Chmod(outputDir, 0777))
+	outputFile := filepath.Join(outputDir, "output")
+	os.Setenv("CHAINCODE_OUTPUT", outputFile)
+	defer os.Unsetenv("CHAINCODE_OUTPUT")
+
+	instance, err := vm.Build("cc:1", testMetadata, codePackage(t, map[string]string{"chaincode.sh": userChaincode}))
+	require.NoError(t, err)
+
+	err = instance.Start(&ccintf.PeerConnection{
+		Address: "peer:7052",
+		TLSConfig: &ccintf.TLSConfig{
+			ClientKey:  []byte("key"),
+			ClientCert: []byte("cert"),
+			RootCert:   []byte("root"),
+		},
+	})
+	require.NoError(t, err)
+
+	code, err := instance.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+
+	output, err := ioutil.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "uid=65534 gid=65534 groups=65534\nroot", string(output))
+}
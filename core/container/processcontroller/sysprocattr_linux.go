@@ -0,0 +1,24 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processcontroller
+
+import "syscall"
+
+func sysProcAttr(namespaces bool, user *User) (*syscall.SysProcAttr, error) {
+	attr := &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+	}
+	if namespaces {
+		attr.Cloneflags = syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS | syscall.CLONE_NEWNS
+	}
+	if user != nil {
+		// the supplementary groups of the peer are dropped
+		attr.Credential = &syscall.Credential{Uid: user.UID, Gid: user.GID}
+	}
+	return attr, nil
+}
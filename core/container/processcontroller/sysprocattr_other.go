@@ -0,0 +1,25 @@
+// +build !linux
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processcontroller
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func sysProcAttr(namespaces bool, user *User) (*syscall.SysProcAttr, error) {
+	if user != nil {
+		return nil, errors.New("running chaincode processes as a dedicated user is only supported on Linux")
+	}
+	if namespaces {
+		logger.Warning("Chaincode namespaces are only supported on Linux, ignoring")
+	}
+	return nil, nil
+}
@@ -23,8 +23,15 @@ const (
 	DefaultReConnectBackoffThreshold   = time.Hour * 1
 	DefaultReConnectTotalTimeThreshold = time.Second * 60 * 60
 	DefaultConnectionTimeout           = time.Second * 3
+	DefaultSourceHealthCheckInterval   = time.Second * 10
+	DefaultPeerSourceStallThreshold    = time.Second * 30
 )
 
+// DefaultBlockSources is the block source priority used when
+// peer.deliveryclient.sources is not set: blocks are pulled from the
+// ordering service only.
+var DefaultBlockSources = []string{OrdererBlockSource}
+
 // DeliverServiceConfig is the struct that defines the deliverservice configuration.
 type DeliverServiceConfig struct {
 	// PeerTLSEnabled enables/disables Peer TLS.
@@ -46,6 +53,17 @@ type DeliverServiceConfig struct {
 	// OrdererEndpointOverrides is a map of orderer addresses which should be
 	// re-mapped to a different orderer endpoint.
 	OrdererEndpointOverrides map[string]*orderers.Endpoint
+
+	// BlockSources lists the sources a leader peer pulls blocks from, in
+	// order of preference. Valid entries are "peers" and "orderer".
+	BlockSources []string
+	// SourceHealthCheckInterval sets how often the health of the block
+	// sources is re-evaluated.
+	SourceHealthCheckInterval time.Duration
+	// PeerSourceStallThreshold sets how long the ledger may stay at the same
+	// height while relying on peers of the organization before failing over
+	// to the next block source.
+	PeerSourceStallThreshold time.Duration
 }
 
 type AddressOverride struct {
@@ -121,6 +139,18 @@ func (c *DeliverServiceConfig) loadDeliverServiceConfig() {
 		c.ConnectionTimeout = DefaultConnectionTimeout
 	}
 
+	c.BlockSources = loadBlockSources()
+
+	c.SourceHealthCheckInterval = viper.GetDuration("peer.deliveryclient.sourceHealthCheck.interval")
+	if c.SourceHealthCheckInterval == 0 {
+		c.SourceHealthCheckInterval = DefaultSourceHealthCheckInterval
+	}
+
+	c.PeerSourceStallThreshold = viper.GetDuration("peer.deliveryclient.sourceHealthCheck.peerStallThreshold")
+	if c.PeerSourceStallThreshold == 0 {
+		c.PeerSourceStallThreshold = DefaultPeerSourceStallThreshold
+	}
+
 	c.KeepaliveOptions = comm.DefaultKeepaliveOptions
 	if viper.IsSet("peer.keepalive.deliveryClient.interval") {
 		c.KeepaliveOptions.ClientInterval = viper.GetDuration("peer.keepalive.deliveryClient.interval")
@@ -164,3 +194,34 @@ func (c *DeliverServiceConfig) loadDeliverServiceConfig() {
 
 	c.OrdererEndpointOverrides = overridesMap
 }
+
+func loadBlockSources() []string {
+	sources := viper.GetStringSlice("peer.deliveryclient.sources")
+	if len(sources) == 0 {
+		return DefaultBlockSources
+	}
+
+	seen := map[string]bool{}
+	var result []string
+	for _, source := range sources {
+		switch source {
+		case PeerBlockSource, OrdererBlockSource:
+		default:
+			logger.Warningf("Ignoring unknown block source '%s' in peer.deliveryclient.sources", source)
+			continue
+		}
+		if seen[source] {
+			continue
+		}
+		seen[source] = true
+		result = append(result, source)
+	}
+
+	if !seen[OrdererBlockSource] {
+		// Without the ordering service nobody in the organization would
+		// ever receive new blocks, so it always remains the last resort.
+		logger.Warningf("peer.deliveryclient.sources does not include '%s', appending it as the last resort", OrdererBlockSource)
+		result = append(result, OrdererBlockSource)
+	}
+	return result
+}
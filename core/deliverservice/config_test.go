@@ -89,6 +89,9 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.deliveryclient.connTimeout", "10s")
 	viper.Set("peer.keepalive.deliveryClient.interval", "5s")
 	viper.Set("peer.keepalive.deliveryClient.timeout", "2s")
+	viper.Set("peer.deliveryclient.sources", []string{"peers", "bogus", "orderer", "peers"})
+	viper.Set("peer.deliveryclient.sourceHealthCheck.interval", "4s")
+	viper.Set("peer.deliveryclient.sourceHealthCheck.peerStallThreshold", "12s")
 
 	coreConfig := deliverservice.GlobalConfig()
 
@@ -108,6 +111,9 @@ func TestGlobalConfig(t *testing.T) {
 		SecOpts: comm.SecureOptions{
 			UseTLS: true,
 		},
+		BlockSources:              []string{"peers", "orderer"},
+		SourceHealthCheckInterval: 4 * time.Second,
+		PeerSourceStallThreshold:  12 * time.Second,
 	}
 
 	assert.Equal(t, expectedConfig, coreConfig)
@@ -126,11 +132,24 @@ func TestGlobalConfigDefault(t *testing.T) {
 		ReconnectTotalTimeThreshold: deliverservice.DefaultReConnectTotalTimeThreshold,
 		ConnectionTimeout:           deliverservice.DefaultConnectionTimeout,
 		KeepaliveOptions:            comm.DefaultKeepaliveOptions,
+		BlockSources:                deliverservice.DefaultBlockSources,
+		SourceHealthCheckInterval:   deliverservice.DefaultSourceHealthCheckInterval,
+		PeerSourceStallThreshold:    deliverservice.DefaultPeerSourceStallThreshold,
 	}
 
 	assert.Equal(t, expectedConfig, coreConfig)
 }
 
+func TestGlobalConfigBlockSourcesWithoutOrderer(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("peer.deliveryclient.sources", []string{"peers"})
+
+	coreConfig := deliverservice.GlobalConfig()
+	assert.Equal(t, []string{"peers", "orderer"}, coreConfig.BlockSources)
+}
+
 func TestLoadOverridesMap(t *testing.T) {
 	defer viper.Reset()
 
@@ -48,6 +48,7 @@ type DeliverService interface {
 type deliverServiceImpl struct {
 	conf           *Config
 	blockProviders map[string]*blocksprovider.Deliverer
+	failovers      map[string]*sourceFailover
 	lock           sync.RWMutex
 	stopping       bool
 }
@@ -73,6 +74,9 @@ type Config struct {
 	// Configuration values for deliver service.
 	// TODO: merge 2 Config struct
 	DeliverServiceConfig *DeliverServiceConfig
+	// OrgPeers reports the ledger heights of the peers of the organization,
+	// it is required for the peers to be used as a block source.
+	OrgPeers OrgPeersInfo
 }
 
 // NewDeliverService construction function to create and initialize
@@ -83,6 +87,7 @@ func NewDeliverService(conf *Config) DeliverService {
 	ds := &deliverServiceImpl{
 		conf:           conf,
 		blockProviders: make(map[string]*blocksprovider.Deliverer),
+		failovers:      make(map[string]*sourceFailover),
 	}
 	return ds
 }
@@ -113,13 +118,64 @@ func (d *deliverServiceImpl) StartDeliverForChannel(chainID string, ledgerInfo b
 		logger.Errorf(errMsg)
 		return errors.New(errMsg)
 	}
-	if _, exist := d.blockProviders[chainID]; exist {
+	_, providerExists := d.blockProviders[chainID]
+	_, failoverExists := d.failovers[chainID]
+	if providerExists || failoverExists {
 		errMsg := fmt.Sprintf("Delivery service - block provider already exists for %s found, can't start delivery", chainID)
 		logger.Errorf(errMsg)
 		return errors.New(errMsg)
 	}
+
+	if d.usePeerSource() {
+		logger.Info("This peer will retrieve blocks from peers of its organization or from the ordering service, using sources", d.conf.DeliverServiceConfig.BlockSources, "for channel", chainID)
+		f := &sourceFailover{
+			channelID: chainID,
+			sources:   d.conf.DeliverServiceConfig.BlockSources,
+			ledger:    ledgerInfo,
+			orgPeers:  d.conf.OrgPeers,
+			newDeliverer: func() blocksDeliverer {
+				return d.newDeliverer(chainID, ledgerInfo)
+			},
+			checkInterval:  d.conf.DeliverServiceConfig.SourceHealthCheckInterval,
+			stallThreshold: d.conf.DeliverServiceConfig.PeerSourceStallThreshold,
+			logger:         flogging.MustGetLogger("peer.blocksprovider").With("channel", chainID),
+			doneC:          make(chan struct{}),
+			now:            time.Now,
+		}
+		d.failovers[chainID] = f
+		go func() {
+			f.DeliverBlocks()
+			finalizer()
+		}()
+		return nil
+	}
+
 	logger.Info("This peer will retrieve blocks from ordering service and disseminate to other peers in the organization for channel", chainID)
 
+	dc := d.newDeliverer(chainID, ledgerInfo)
+	d.blockProviders[chainID] = dc
+	go func() {
+		dc.DeliverBlocks()
+		finalizer()
+	}()
+	return nil
+}
+
+// usePeerSource returns whether peers of the organization are configured as a
+// block source and can be queried for their ledger heights
+func (d *deliverServiceImpl) usePeerSource() bool {
+	if d.conf.OrgPeers == nil {
+		return false
+	}
+	for _, source := range d.conf.DeliverServiceConfig.BlockSources {
+		if source == PeerBlockSource {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *deliverServiceImpl) newDeliverer(chainID string, ledgerInfo blocksprovider.LedgerInfo) *blocksprovider.Deliverer {
 	dc := &blocksprovider.Deliverer{
 		ChannelID:     chainID,
 		Gossip:        d.conf.Gossip,
@@ -143,13 +199,7 @@ func (d *deliverServiceImpl) StartDeliverForChannel(chainID string, ledgerInfo b
 	if d.conf.DeliverGRPCClient.MutualTLSRequired() {
 		dc.TLSCertHash = util.ComputeSHA256(d.conf.DeliverGRPCClient.Certificate().Certificate[0])
 	}
-
-	d.blockProviders[chainID] = dc
-	go func() {
-		dc.DeliverBlocks()
-		finalizer()
-	}()
-	return nil
+	return dc
 }
 
 // StopDeliverForChannel stops blocks delivery for channel by stopping channel block provider
@@ -161,6 +211,12 @@ func (d *deliverServiceImpl) StopDeliverForChannel(chainID string) error {
 		logger.Errorf(errMsg)
 		return errors.New(errMsg)
 	}
+	if f, exist := d.failovers[chainID]; exist {
+		f.Stop()
+		delete(d.failovers, chainID)
+		logger.Debug("This peer will stop pass blocks from orderer service to other peers")
+		return nil
+	}
 	client, exist := d.blockProviders[chainID]
 	if !exist {
 		errMsg := fmt.Sprintf("Delivery service - no block provider for %s found, can't stop delivery", chainID)
@@ -183,4 +239,7 @@ func (d *deliverServiceImpl) Stop() {
 	for _, client := range d.blockProviders {
 		client.Stop()
 	}
+	for _, f := range d.failovers {
+		f.Stop()
+	}
 }
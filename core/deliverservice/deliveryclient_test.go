@@ -127,6 +127,42 @@ eUCutqn1KYDMYh54i6p723cXbdDkmvL2UCciHyHdSWS9lmkKVdyNGIJ6
 		assert.EqualError(t, err, "Delivery service - block provider already exists for channel-id found, can't start delivery")
 	})
 
+	t.Run("Peer Source", func(t *testing.T) {
+		ds := NewDeliverService(&Config{
+			DeliverGRPCClient: grpcClient,
+			DeliverServiceConfig: &DeliverServiceConfig{
+				BlockSources:              []string{PeerBlockSource, OrdererBlockSource},
+				SourceHealthCheckInterval: time.Second,
+				PeerSourceStallThreshold:  time.Second,
+			},
+			OrgPeers: &fake.OrgPeersInfo{},
+		}).(*deliverServiceImpl)
+
+		finalized := make(chan struct{})
+		err := ds.StartDeliverForChannel("channel-id", fakeLedgerInfo, func() {
+			close(finalized)
+		})
+		require.NoError(t, err)
+
+		select {
+		case <-finalized:
+		case <-time.After(time.Second):
+			assert.FailNow(t, "finalizer should have executed")
+		}
+
+		f, ok := ds.failovers["channel-id"]
+		require.True(t, ok, "map entry must exist")
+		assert.Equal(t, []string{PeerBlockSource, OrdererBlockSource}, f.sources)
+		assert.Empty(t, ds.blockProviders)
+
+		err = ds.StartDeliverForChannel("channel-id", fakeLedgerInfo, func() {})
+		assert.EqualError(t, err, "Delivery service - block provider already exists for channel-id found, can't start delivery")
+
+		err = ds.StopDeliverForChannel("channel-id")
+		require.NoError(t, err)
+		assert.Empty(t, ds.failovers)
+	})
+
 	t.Run("Stopping", func(t *testing.T) {
 		ds := NewDeliverService(&Config{
 			DeliverGRPCClient:    grpcClient,
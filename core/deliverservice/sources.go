@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverservice
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/internal/pkg/peer/blocksprovider"
+)
+
+const (
+	// PeerBlockSource denotes the peers of the local organization as a block
+	// source. Blocks are then obtained through the gossip state transfer.
+	PeerBlockSource = "peers"
+	// OrdererBlockSource denotes the ordering service as a block source.
+	OrdererBlockSource = "orderer"
+)
+
+// OrgPeersInfo reports the ledger heights advertised by the alive peers of
+// the local organization.
+type OrgPeersInfo interface {
+	// OrgLedgerHeights returns the ledger heights of the peers of the local
+	// organization which are members of the given channel
+	OrgLedgerHeights(channelID string) []uint64
+}
+
+// blocksDeliverer pulls blocks from the ordering service until stopped
+type blocksDeliverer interface {
+	DeliverBlocks()
+	Stop()
+}
+
+// sourceFailover pulls the blocks of a channel from the most preferred healthy
+// block source, and re-evaluates the choice every check interval.
+//
+// The peer source is healthy as long as some peer of the organization is ahead
+// of the local ledger, and the local ledger keeps growing while it is in use.
+// A stalled peer source is suspended for the stall threshold. The orderer
+// source is healthy until its deliverer gives up, at which point the failover
+// terminates so that the leadership can be yielded.
+type sourceFailover struct {
+	channelID      string
+	sources        []string
+	ledger         blocksprovider.LedgerInfo
+	orgPeers       OrgPeersInfo
+	newDeliverer   func() blocksDeliverer
+	checkInterval  time.Duration
+	stallThreshold time.Duration
+	logger         *flogging.FabricLogger
+	doneC          chan struct{}
+	now            func() time.Time
+
+	active              string
+	deliverer           blocksDeliverer
+	delivererDoneC      chan struct{}
+	lastHeight          uint64
+	lastProgress        time.Time
+	peersSuspendedUntil time.Time
+}
+
+// DeliverBlocks runs the failover loop until stopped, the ledger can no
+// longer be queried, or the ordering service deliverer gives up.
+func (f *sourceFailover) DeliverBlocks() {
+	ticker := time.NewTicker(f.checkInterval)
+	defer ticker.Stop()
+	defer f.stopDeliverer()
+
+	if !f.evaluate() {
+		return
+	}
+	for {
+		select {
+		case <-f.doneC:
+			return
+		case <-f.delivererDoneC:
+			f.logger.Warningf("Block delivery from the ordering service has given up")
+			f.deliverer = nil
+			f.delivererDoneC = nil
+			return
+		case <-ticker.C:
+			if !f.evaluate() {
+				return
+			}
+		}
+	}
+}
+
+// Stop stops the failover loop and the active block source
+func (f *sourceFailover) Stop() {
+	select {
+	case <-f.doneC:
+	default:
+		close(f.doneC)
+	}
+}
+
+// evaluate selects the most preferred healthy block source and switches to it
+// if needed. It returns false if the ledger height could not be obtained.
+func (f *sourceFailover) evaluate() bool {
+	height, err := f.ledger.LedgerHeight()
+	if err != nil {
+		f.logger.Errorf("Did not return ledger height, something is critically wrong: %s", err)
+		return false
+	}
+
+	now := f.now()
+	if height > f.lastHeight {
+		f.lastHeight = height
+		f.lastProgress = now
+	}
+
+	selected := ""
+	for _, source := range f.sources {
+		if f.healthy(source, height, now) {
+			selected = source
+			break
+		}
+	}
+	if selected == "" {
+		if f.active != "" {
+			return true
+		}
+		selected = f.sources[len(f.sources)-1]
+	}
+
+	if selected != f.active {
+		f.switchTo(selected, now)
+	}
+	return true
+}
+
+func (f *sourceFailover) healthy(source string, height uint64, now time.Time) bool {
+	switch source {
+	case OrdererBlockSource:
+		return true
+	case PeerBlockSource:
+		if f.orgPeers == nil || now.Before(f.peersSuspendedUntil) {
+			return false
+		}
+		if f.active == PeerBlockSource && now.Sub(f.lastProgress) > f.stallThreshold {
+			f.logger.Warningf("Ledger height stayed at %d for more than %v while relying on peers of the organization, suspending them as a block source", height, f.stallThreshold)
+			f.peersSuspendedUntil = now.Add(f.stallThreshold)
+			return false
+		}
+		for _, peerHeight := range f.orgPeers.OrgLedgerHeights(f.channelID) {
+			if peerHeight > height {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func (f *sourceFailover) switchTo(source string, now time.Time) {
+	f.logger.Infof("Switching block source from '%s' to '%s' at ledger height %d", f.active, source, f.lastHeight)
+	if f.active == OrdererBlockSource {
+		f.stopDeliverer()
+	}
+
+	f.active = source
+	// the stall timer of the new source starts now
+	f.lastProgress = now
+
+	if source != OrdererBlockSource {
+		return
+	}
+	f.deliverer = f.newDeliverer()
+	f.delivererDoneC = make(chan struct{})
+	go func(d blocksDeliverer, doneC chan struct{}) {
+		d.DeliverBlocks()
+		close(doneC)
+	}(f.deliverer, f.delivererDoneC)
+}
+
+func (f *sourceFailover) stopDeliverer() {
+	if f.deliverer == nil {
+		return
+	}
+	f.deliverer.Stop()
+	<-f.delivererDoneC
+	f.deliverer = nil
+	f.delivererDoneC = nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverservice
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/deliverservice/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate counterfeiter -o fake/org_peers_info.go --fake-name OrgPeersInfo . orgPeersInfo
+type orgPeersInfo interface {
+	OrgPeersInfo
+}
+
+type mockDeliverer struct {
+	mutex   sync.Mutex
+	stopped bool
+	doneC   chan struct{}
+}
+
+func newMockDeliverer() *mockDeliverer {
+	return &mockDeliverer{doneC: make(chan struct{})}
+}
+
+func (md *mockDeliverer) DeliverBlocks() {
+	<-md.doneC
+}
+
+func (md *mockDeliverer) Stop() {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+	if !md.stopped {
+		md.stopped = true
+		close(md.doneC)
+	}
+}
+
+func (md *mockDeliverer) isStopped() bool {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+	return md.stopped
+}
+
+type failoverTestSetup struct {
+	failover   *sourceFailover
+	ledger     *fake.LedgerInfo
+	orgPeers   *fake.OrgPeersInfo
+	now        time.Time
+	mutex      sync.Mutex
+	deliverers []*mockDeliverer
+}
+
+func (s *failoverTestSetup) createdDeliverers() []*mockDeliverer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*mockDeliverer(nil), s.deliverers...)
+}
+
+func newFailoverTestSetup(sources ...string) *failoverTestSetup {
+	s := &failoverTestSetup{
+		ledger:   &fake.LedgerInfo{},
+		orgPeers: &fake.OrgPeersInfo{},
+		now:      time.Unix(1000, 0),
+	}
+	s.failover = &sourceFailover{
+		channelID: "channel-id",
+		sources:   sources,
+		ledger:    s.ledger,
+		orgPeers:  s.orgPeers,
+		newDeliverer: func() blocksDeliverer {
+			md := newMockDeliverer()
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.deliverers = append(s.deliverers, md)
+			return md
+		},
+		checkInterval:  time.Hour,
+		stallThreshold: 30 * time.Second,
+		logger:         flogging.MustGetLogger("peer.blocksprovider"),
+		doneC:          make(chan struct{}),
+		now:            func() time.Time { return s.now },
+	}
+	return s
+}
+
+func TestSourceFailoverPrefersPeersAhead(t *testing.T) {
+	s := newFailoverTestSetup(PeerBlockSource, OrdererBlockSource)
+	s.ledger.LedgerHeightReturns(10, nil)
+	s.orgPeers.OrgLedgerHeightsReturns([]uint64{8, 15})
+
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, PeerBlockSource, s.failover.active)
+	assert.Empty(t, s.deliverers)
+	assert.Equal(t, "channel-id", s.orgPeers.OrgLedgerHeightsArgsForCall(0))
+
+	// the peers of the organization are no longer ahead
+	s.ledger.LedgerHeightReturns(15, nil)
+	s.now = s.now.Add(time.Second)
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, OrdererBlockSource, s.failover.active)
+	require.Len(t, s.deliverers, 1)
+	assert.False(t, s.deliverers[0].isStopped())
+
+	// a peer gets ahead again, the orderer deliverer is stopped
+	s.orgPeers.OrgLedgerHeightsReturns([]uint64{20})
+	s.now = s.now.Add(time.Second)
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, PeerBlockSource, s.failover.active)
+	assert.True(t, s.deliverers[0].isStopped())
+	assert.Nil(t, s.failover.deliverer)
+}
+
+func TestSourceFailoverPeersStalled(t *testing.T) {
+	s := newFailoverTestSetup(PeerBlockSource, OrdererBlockSource)
+	s.ledger.LedgerHeightReturns(10, nil)
+	s.orgPeers.OrgLedgerHeightsReturns([]uint64{15})
+
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, PeerBlockSource, s.failover.active)
+
+	// progress within the stall threshold keeps the peers as the source
+	s.now = s.now.Add(20 * time.Second)
+	s.ledger.LedgerHeightReturns(11, nil)
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, PeerBlockSource, s.failover.active)
+
+	// no progress for longer than the stall threshold
+	s.now = s.now.Add(31 * time.Second)
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, OrdererBlockSource, s.failover.active)
+	require.Len(t, s.deliverers, 1)
+
+	// the peers stay suspended for the stall threshold
+	s.now = s.now.Add(10 * time.Second)
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, OrdererBlockSource, s.failover.active)
+
+	s.now = s.now.Add(30 * time.Second)
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, PeerBlockSource, s.failover.active)
+	assert.True(t, s.deliverers[0].isStopped())
+}
+
+func TestSourceFailoverOrdererFirst(t *testing.T) {
+	s := newFailoverTestSetup(OrdererBlockSource, PeerBlockSource)
+	s.ledger.LedgerHeightReturns(10, nil)
+	s.orgPeers.OrgLedgerHeightsReturns([]uint64{15})
+
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, OrdererBlockSource, s.failover.active)
+	assert.Equal(t, 0, s.orgPeers.OrgLedgerHeightsCallCount())
+}
+
+func TestSourceFailoverNoHealthySource(t *testing.T) {
+	s := newFailoverTestSetup(PeerBlockSource)
+	s.ledger.LedgerHeightReturns(10, nil)
+
+	require.True(t, s.failover.evaluate())
+	assert.Equal(t, PeerBlockSource, s.failover.active)
+}
+
+func TestSourceFailoverDeliverBlocks(t *testing.T) {
+	t.Run("ledger error", func(t *testing.T) {
+		s := newFailoverTestSetup(PeerBlockSource, OrdererBlockSource)
+		s.ledger.LedgerHeightReturns(0, fmt.Errorf("fake-ledger-error"))
+
+		done := make(chan struct{})
+		go func() {
+			s.failover.DeliverBlocks()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.FailNow(t, "failover should have returned")
+		}
+	})
+
+	t.Run("orderer gives up", func(t *testing.T) {
+		s := newFailoverTestSetup(PeerBlockSource, OrdererBlockSource)
+		s.ledger.LedgerHeightReturns(10, nil)
+
+		done := make(chan struct{})
+		go func() {
+			s.failover.DeliverBlocks()
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return len(s.createdDeliverers()) == 1
+		}, time.Second, 10*time.Millisecond)
+		s.createdDeliverers()[0].Stop()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.FailNow(t, "failover should have returned")
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		s := newFailoverTestSetup(PeerBlockSource, OrdererBlockSource)
+		s.ledger.LedgerHeightReturns(10, nil)
+		s.failover.checkInterval = 10 * time.Millisecond
+
+		done := make(chan struct{})
+		go func() {
+			s.failover.DeliverBlocks()
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return s.ledger.LedgerHeightCallCount() > 2
+		}, time.Second, 10*time.Millisecond)
+		s.failover.Stop()
+		s.failover.Stop()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.FailNow(t, "failover should have returned")
+		}
+		deliverers := s.createdDeliverers()
+		require.Len(t, deliverers, 1)
+		assert.True(t, deliverers[0].isStopped())
+	})
+}
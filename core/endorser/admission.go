@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// OverloadedStatus is the status of the response to a proposal rejected as
+// the peer is overloaded. The proposal can be retried later or sent to
+// another peer.
+const OverloadedStatus = 503
+
+// The reasons for which a proposal is rejected by the admission controller.
+const (
+	OverloadCommitBacklog = "commit_backlog"
+	OverloadGoroutines    = "goroutines"
+	OverloadMemory        = "memory"
+)
+
+// CommitBacklog reports the number of blocks received but not yet committed.
+type CommitBacklog interface {
+	CommitBacklog() int
+}
+
+// AdmissionThresholds are the resource usage levels above which proposals
+// are rejected. A threshold of zero is not enforced.
+type AdmissionThresholds struct {
+	// CommitBacklog is the number of blocks waiting to be committed.
+	CommitBacklog int
+	// Goroutines is the number of goroutines of the peer.
+	Goroutines int
+	// HeapBytes is the size of the heap of the peer.
+	HeapBytes uint64
+}
+
+// OverloadError is returned by the admission controller when the peer is
+// overloaded.
+type OverloadError struct {
+	// Reason is the resource whose threshold is crossed.
+	Reason    string
+	Usage     uint64
+	Threshold uint64
+}
+
+func (e *OverloadError) Error() string {
+	return fmt.Sprintf("peer is overloaded: %s of %d exceeds the threshold of %d, retry later", e.Reason, e.Usage, e.Threshold)
+}
+
+// AdmissionController rejects new proposals while the commit backlog, the
+// number of goroutines or the heap of the peer is above its threshold, so
+// that endorsements don't starve the commit path during load spikes. The
+// resource usage is sampled at every interval rather than for every
+// proposal, as reading the memory statistics stops the world.
+type AdmissionController struct {
+	thresholds AdmissionThresholds
+	interval   time.Duration
+	backlog    CommitBacklog
+
+	mutex    sync.RWMutex
+	overload *OverloadError
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewAdmissionController creates an admission controller enforcing the given
+// thresholds, sampling the resource usage at every interval.
+func NewAdmissionController(thresholds AdmissionThresholds, interval time.Duration, backlog CommitBacklog) *AdmissionController {
+	return &AdmissionController{
+		thresholds: thresholds,
+		interval:   interval,
+		backlog:    backlog,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Admit returns an *OverloadError if a proposal should be rejected.
+func (a *AdmissionController) Admit() error {
+	if a == nil {
+		return nil
+	}
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.overload == nil {
+		return nil
+	}
+	return a.overload
+}
+
+// Update samples the resource usage and updates the admission state.
+func (a *AdmissionController) Update() {
+	overload := a.sample()
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	switch {
+	case overload != nil && a.overload == nil:
+		endorserLogger.Warningf("Rejecting proposals: %s", overload)
+	case overload == nil && a.overload != nil:
+		endorserLogger.Infof("Admitting proposals again, resource usage is back under the thresholds")
+	}
+	a.overload = overload
+}
+
+func (a *AdmissionController) sample() *OverloadError {
+	if a.thresholds.CommitBacklog > 0 && a.backlog != nil {
+		if backlog := a.backlog.CommitBacklog(); backlog > a.thresholds.CommitBacklog {
+			return &OverloadError{Reason: OverloadCommitBacklog, Usage: uint64(backlog), Threshold: uint64(a.thresholds.CommitBacklog)}
+		}
+	}
+	if a.thresholds.Goroutines > 0 {
+		if goroutines := runtime.NumGoroutine(); goroutines > a.thresholds.Goroutines {
+			return &OverloadError{Reason: OverloadGoroutines, Usage: uint64(goroutines), Threshold: uint64(a.thresholds.Goroutines)}
+		}
+	}
+	if a.thresholds.HeapBytes > 0 {
+		var memStats runtime.MemStats
+		runtime.ReadMemStats(&memStats)
+		if memStats.HeapAlloc > a.thresholds.HeapBytes {
+			return &OverloadError{Reason: OverloadMemory, Usage: memStats.HeapAlloc, Threshold: a.thresholds.HeapBytes}
+		}
+	}
+	return nil
+}
+
+// Run samples the resource usage at every interval. It returns once Stop is
+// called.
+func (a *AdmissionController) Run() {
+	a.Update()
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.Update()
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the sampling of the resource usage.
+func (a *AdmissionController) Stop() {
+	a.stopOnce.Do(func() { close(a.stop) })
+}
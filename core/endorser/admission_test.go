@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type commitBacklog int
+
+func (c *commitBacklog) CommitBacklog() int {
+	return int(*c)
+}
+
+func TestAdmissionControllerCommitBacklog(t *testing.T) {
+	backlog := commitBacklog(0)
+	a := NewAdmissionController(AdmissionThresholds{CommitBacklog: 10}, time.Second, &backlog)
+
+	a.Update()
+	assert.NoError(t, a.Admit())
+
+	backlog = 11
+	a.Update()
+	err := a.Admit()
+	require.Error(t, err)
+	assert.Equal(t, &OverloadError{Reason: OverloadCommitBacklog, Usage: 11, Threshold: 10}, err)
+	assert.EqualError(t, err, "peer is overloaded: commit_backlog of 11 exceeds the threshold of 10, retry later")
+
+	backlog = 10
+	a.Update()
+	assert.NoError(t, a.Admit())
+}
+
+func TestAdmissionControllerGoroutinesAndMemory(t *testing.T) {
+	a := NewAdmissionController(AdmissionThresholds{Goroutines: 1}, time.Second, nil)
+	a.Update()
+	err := a.Admit()
+	require.Error(t, err)
+	assert.Equal(t, OverloadGoroutines, err.(*OverloadError).Reason)
+
+	a = NewAdmissionController(AdmissionThresholds{HeapBytes: 1}, time.Second, nil)
+	a.Update()
+	err = a.Admit()
+	require.Error(t, err)
+	assert.Equal(t, OverloadMemory, err.(*OverloadError).Reason)
+
+	a = NewAdmissionController(AdmissionThresholds{Goroutines: 1 << 30, HeapBytes: 1 << 62}, time.Second, nil)
+	a.Update()
+	assert.NoError(t, a.Admit())
+}
+
+func TestAdmissionControllerNil(t *testing.T) {
+	var a *AdmissionController
+	assert.NoError(t, a.Admit())
+}
+
+func TestAdmissionControllerRun(t *testing.T) {
+	backlog := commitBacklog(5)
+	a := NewAdmissionController(AdmissionThresholds{CommitBacklog: 1}, time.Millisecond, &backlog)
+	done := make(chan struct{})
+	go func() {
+		a.Run()
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return a.Admit() != nil }, time.Second, time.Millisecond)
+	a.Stop()
+	a.Stop()
+	<-done
+}
@@ -8,16 +8,21 @@ package endorser
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
+	cb "github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-protos-go/transientstore"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/debug"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -30,6 +35,22 @@ import (
 
 var endorserLogger = flogging.MustGetLogger("endorser")
 
+// DeadlineExceededStatus is the status of the response to a proposal whose
+// simulation was abandoned as its deadline passed. The deadline of a proposal
+// is the deadline of the gRPC call it is sent with; proposals carry no
+// deadline of their own, so a proposal relayed without the deadline of the
+// call is simulated until the chaincode execution timeout.
+const DeadlineExceededStatus = 408
+
+// CancelledStatus is the status of the response to a proposal whose
+// simulation was abandoned as the client cancelled the gRPC call it was sent
+// with.
+const CancelledStatus = 499
+
+// InvalidArgumentsStatus is the status of the response to a proposal whose
+// arguments do not comply with the argument schema of the chaincode.
+const InvalidArgumentsStatus = 400
+
 // The Jira issue that documents Endorser flow along with its relationship to
 // the lifecycle chaincode - https://jira.hyperledger.org/browse/FAB-181
 
@@ -71,12 +92,24 @@ type Support interface {
 	// SignedProposal from which an id can be extracted for testing against a policy
 	CheckACL(channelID string, signedProp *pb.SignedProposal) error
 
+	// CheckChaincodeFunctionACL checks the ACL defined for the function of the
+	// chaincode, if any, using the SignedProposal
+	CheckChaincodeFunctionACL(channelID, chaincodeName, function string, signedProp *pb.SignedProposal) error
+
 	// EndorseWithPlugin endorses the response with a plugin
 	EndorseWithPlugin(pluginName, channnelID string, prpBytes []byte, signedProposal *pb.SignedProposal) (*pb.Endorsement, []byte, error)
 
 	// GetLedgerHeight returns ledger height for given channelID
 	GetLedgerHeight(channelID string) (uint64, error)
 
+	// GetBlockchainInfo returns the height and the hash of the last block of
+	// the ledger of the given channelID
+	GetBlockchainInfo(channelID string) (*cb.BlockchainInfo, error)
+
+	// GetCommittedStateInfo returns the height and the hash of the last block
+	// committed to the state database of the ledger of the given channelID
+	GetCommittedStateInfo(channelID string) (*cb.BlockchainInfo, error)
+
 	// GetDeployedCCInfoProvider returns ledger.DeployedChaincodeInfoProvider
 	GetDeployedCCInfoProvider() ledger.DeployedChaincodeInfoProvider
 }
@@ -100,6 +133,9 @@ type Endorser struct {
 	Support                Support
 	PvtRWSetAssembler      PvtRWSetAssembler
 	Metrics                *Metrics
+	Tracer                 *tracing.Tracer
+	QueryCache             *QueryCache
+	Admission              *AdmissionController
 }
 
 // call specified chaincode (system or user)
@@ -287,17 +323,30 @@ func (e *Endorser) preProcess(up *UnpackedProposal, channel *Channel) error {
 			e.Metrics.ProposalACLCheckFailed.With(meterLabels...).Add(1)
 			return err
 		}
+
+		// check that the proposal complies with the ACL of the invoked function, if any
+		if len(up.Input.Args) > 0 {
+			if err = e.Support.CheckChaincodeFunctionACL(up.ChannelHeader.ChannelId, up.ChaincodeName, string(up.Input.Args[0]), up.SignedProposal); err != nil {
+				e.Metrics.ProposalACLCheckFailed.With(meterLabels...).Add(1)
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
 // ProcessProposal process the Proposal
-func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (resp *pb.ProposalResponse, err error) {
 	// start time for computing elapsed time metric for successfully endorsed proposals
 	startTime := time.Now()
 	e.Metrics.ProposalsReceived.Add(1)
 
+	if err := e.Admission.Admit(); err != nil {
+		e.Metrics.ProposalsRejected.With("reason", err.(*OverloadError).Reason).Add(1)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: OverloadedStatus, Message: err.Error()}}, nil
+	}
+
 	addr := util.ExtractRemoteAddress(ctx)
 	endorserLogger.Debug("request from", addr)
 
@@ -310,6 +359,12 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
+	span := e.Tracer.StartSpan(up.TxID(), "endorser.ProcessProposal", tracing.SpanKindServer,
+		"fabric.channel", up.ChannelID(),
+		"fabric.chaincode", up.ChaincodeName,
+	)
+	defer func() { finishProposalSpan(span, resp, err) }()
+
 	var channel *Channel
 	if up.ChannelID() != "" {
 		channel = e.ChannelFetcher.Channel(up.ChannelID())
@@ -323,23 +378,42 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	}
 
 	// 0 -- check and validate
+	checkSpan := span.StartChild("endorser.CheckProposal")
 	err = e.preProcess(up, channel)
+	checkSpan.Finish(err)
 	if err != nil {
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
+	// 1 -- check the ledger is as fresh as required by the client
+	if up.ChannelID() != "" {
+		if pResp := e.checkMinStateVersion(ctx, up); pResp != nil {
+			return pResp, nil
+		}
+	}
+
 	defer func() {
 		meterLabels := []string{
 			"channel", up.ChannelHeader.ChannelId,
 			"chaincode", up.ChaincodeName,
 			"success", strconv.FormatBool(success),
 		}
-		e.Metrics.ProposalDuration.With(meterLabels...).Observe(time.Since(startTime).Seconds())
+		metrics.ObserveWithExemplar(
+			e.Metrics.ProposalDuration.With(meterLabels...),
+			time.Since(startTime).Seconds(),
+			map[string]string{"txid": up.TxID()},
+		)
 	}()
 
-	pResp, err := e.ProcessProposalSuccessfullyOrError(up)
+	pResp, err := e.ProcessProposalSuccessfullyOrError(ctx, up, span)
 	if err != nil {
 		endorserLogger.Warnw("Failed to invoke chaincode", "channel", up.ChannelHeader.ChannelId, "chaincode", up.ChaincodeName, "error", err.Error())
+		switch errors.Cause(err) {
+		case context.DeadlineExceeded:
+			return &pb.ProposalResponse{Response: &pb.Response{Status: DeadlineExceededStatus, Message: err.Error()}}, nil
+		case context.Canceled:
+			return &pb.ProposalResponse{Response: &pb.Response{Status: CancelledStatus, Message: err.Error()}}, nil
+		}
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
 	}
 
@@ -355,16 +429,37 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	return pResp, nil
 }
 
-func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb.ProposalResponse, error) {
+// finishProposalSpan records the outcome of the processing of a proposal. A
+// proposal response with an error status fails the span even though no error
+// is returned to the client.
+func finishProposalSpan(span *tracing.Span, resp *pb.ProposalResponse, err error) {
+	if resp != nil && resp.Response != nil {
+		span.SetAttributes("fabric.response_status", strconv.Itoa(int(resp.Response.Status)))
+		if err == nil && resp.Response.Status >= shim.ERRORTHRESHOLD {
+			err = errors.New(resp.Response.Message)
+		}
+	}
+	span.Finish(err)
+}
+
+// ProcessProposalSuccessfullyOrError simulates and endorses the proposal. The
+// simulation is abandoned when the deadline of ctx, that is the deadline of the
+// gRPC call, passes or ctx is cancelled.
+func (e *Endorser) ProcessProposalSuccessfullyOrError(ctx context.Context, up *UnpackedProposal, span *tracing.Span) (*pb.ProposalResponse, error) {
 	txParams := &ccprovider.TransactionParams{
 		ChannelID:  up.ChannelHeader.ChannelId,
 		TxID:       up.ChannelHeader.TxId,
 		SignedProp: up.SignedProposal,
 		Proposal:   up.Proposal,
+		Context:    debug.WithMark(ctx),
 	}
 
 	logger := decorateLogger(endorserLogger, txParams)
 
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "proposal abandoned before simulation")
+	}
+
 	if acquireTxSimulator(up.ChannelHeader.ChannelId, up.ChaincodeName) {
 		txSim, err := e.Support.GetTxSimulator(up.ChannelID(), up.TxID())
 		if err != nil {
@@ -389,15 +484,67 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		txParams.HistoryQueryExecutor = hqe
 	}
 
+	if up.ChannelID() != "" {
+		if err := e.sendStateVersion(ctx, up.ChannelID()); err != nil {
+			return nil, err
+		}
+	}
+
 	cdLedger, err := e.Support.ChaincodeEndorsementInfo(up.ChannelID(), up.ChaincodeName, txParams.TXSimulator)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "make sure the chaincode %s has been successfully defined on channel %s and try again", up.ChaincodeName, up.ChannelID())
 	}
 
-	// 1 -- simulate
-	res, simulationResult, ccevent, err := e.SimulateProposal(txParams, up.ChaincodeName, up.Input)
-	if err != nil {
-		return nil, errors.WithMessage(err, "error in simulation")
+	// reject the proposals whose arguments do not comply with the argument schema
+	// of the chaincode definition before invoking the chaincode
+	if cdLedger.ArgumentSchema != nil {
+		if err := cdLedger.ArgumentSchema.Validate(up.Input.Args); err != nil {
+			logger.Debugf("arguments of proposal to chaincode %s rejected: %s", up.ChaincodeName, err)
+			e.Metrics.ArgumentValidationFailed.With("channel", up.ChannelID(), "chaincode", up.ChaincodeName).Add(1)
+			payload, mErr := json.Marshal(err)
+			if mErr != nil {
+				return nil, errors.Wrap(mErr, "failed to marshal the argument validation error")
+			}
+			return &pb.ProposalResponse{
+				Response: &pb.Response{
+					Status:  InvalidArgumentsStatus,
+					Message: err.Error(),
+					Payload: payload,
+				},
+			}, nil
+		}
+	}
+
+	// 1 -- simulate, unless the result of the query is cached
+	var res *pb.Response
+	var simulationResult []byte
+	var ccevent *pb.ChaincodeEvent
+	var cached bool
+	cacheKey, cacheable := e.QueryCache.Key(up)
+	cacheable = cacheable && !e.Support.IsSysCC(up.ChaincodeName)
+	if cacheable {
+		if res, simulationResult, cached = e.QueryCache.Get(cacheKey); cached {
+			logger.Debugf("serving cached result of query to chaincode %s", up.ChaincodeName)
+			e.Metrics.QueryCacheHits.With("channel", up.ChannelID(), "chaincode", up.ChaincodeName).Add(1)
+			if txParams.TXSimulator != nil {
+				txParams.TXSimulator.Done()
+			}
+		}
+	}
+	if !cached {
+		var cacheSequence uint64
+		if cacheable {
+			cacheSequence = e.QueryCache.Sequence()
+		}
+		simulateSpan := span.StartChild("endorser.SimulateProposal")
+		res, simulationResult, ccevent, err = e.SimulateProposal(txParams, up.ChaincodeName, up.Input)
+		simulateSpan.Finish(err)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error in simulation")
+		}
+		if cacheable && !debug.Marked(txParams.Context) {
+			e.QueryCache.Put(cacheKey, up.ChannelID(), cacheSequence, res, simulationResult, ccevent)
+		}
 	}
 
 	cceventBytes, err := CreateCCEventBytes(ccevent)
@@ -440,6 +587,14 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		return &pb.ProposalResponse{
 			Response: res,
 		}, nil
+	case debug.Marked(txParams.Context):
+		// a chaincode was executed by the process of a debug session, run
+		// by a developer: the results are returned for inspection, unendorsed
+		logger.Warningf("Not endorsing the results of proposal %s, a chaincode was executed in a debug session", up.TxID())
+		return &pb.ProposalResponse{
+			Response: res,
+			Payload:  prpBytes,
+		}, nil
 	}
 
 	escc := cdLedger.EndorsementPlugin
@@ -447,7 +602,9 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 	logger.Debugf("escc for chaincode %s is %s", up.ChaincodeName, escc)
 
 	// Note, mPrpBytes is the same as prpBytes by default endorsement plugin, but others could change it.
+	endorseSpan := span.StartChild("endorser.EndorseWithPlugin", "fabric.endorsement_plugin", escc)
 	endorsement, mPrpBytes, err := e.Support.EndorseWithPlugin(escc, up.ChannelID(), prpBytes, up.SignedProposal)
+	endorseSpan.Finish(err)
 	if err != nil {
 		meterLabels = append(meterLabels, "chaincodeerror", strconv.FormatBool(false))
 		e.Metrics.EndorsementsFailed.With(meterLabels...).Add(1)
@@ -9,6 +9,7 @@ package endorser_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,14 +17,20 @@ import (
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	mspproto "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/common/tracing"
+	"github.com/hyperledger/fabric/core/chaincode/argschema"
+	"github.com/hyperledger/fabric/core/chaincode/debug"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/core/endorser/fake"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protoutil"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/golang/protobuf/proto"
 	ledgermock "github.com/hyperledger/fabric/core/ledger/mock"
@@ -134,6 +141,8 @@ var _ = Describe("Endorser", func() {
 		}, nil)
 
 		fakeSupport.GetLedgerHeightReturns(7, nil)
+		fakeSupport.GetBlockchainInfoReturns(&cb.BlockchainInfo{Height: 7, CurrentBlockHash: []byte{0xca, 0xfe}}, nil)
+		fakeSupport.GetCommittedStateInfoReturns(&cb.BlockchainInfo{Height: 7, CurrentBlockHash: []byte{0xca, 0xfe}}, nil)
 
 		fakeSupport.EndorseWithPluginReturns(
 			&pb.Endorsement{
@@ -267,6 +276,279 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 
+	Context("when tracing is enabled", func() {
+		var spans *spanRecorder
+
+		BeforeEach(func() {
+			spans = &spanRecorder{}
+			e.Tracer = tracing.NewTracer(spans)
+		})
+
+		It("records the spans of the processing of the proposal", func() {
+			_, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(spans.names()).To(Equal([]string{
+				"endorser.CheckProposal",
+				"endorser.SimulateProposal",
+				"endorser.EndorseWithPlugin",
+				"endorser.ProcessProposal",
+			}))
+			root := spans.spans[3]
+			Expect(root.TraceID).To(Equal(tracing.TraceIDFromTxID("6f142589e4ef6a1e62c9c816e2074f70baa9f7cf67c2f0c287d4ef907d6d2015")))
+			Expect(root.Kind).To(Equal(tracing.SpanKindServer))
+			Expect(root.Error).To(BeEmpty())
+			Expect(root.Attributes).To(ContainElement(tracing.Attribute{Key: "fabric.channel", Value: "channel-id"}))
+			Expect(root.Attributes).To(ContainElement(tracing.Attribute{Key: "fabric.response_status", Value: "200"}))
+			for _, span := range spans.spans[:3] {
+				Expect(span.TraceID).To(Equal(root.TraceID))
+				Expect(span.ParentSpanID).To(Equal(root.SpanID))
+			}
+		})
+
+		Context("when the chaincode endorsement fails", func() {
+			BeforeEach(func() {
+				fakeSupport.EndorseWithPluginReturns(nil, nil, fmt.Errorf("fake-endorserment-error"))
+			})
+
+			It("records the failure in the spans", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spans.spans).To(HaveLen(4))
+				Expect(spans.spans[2].Error).To(Equal("fake-endorserment-error"))
+				Expect(spans.spans[3].Error).To(Equal("endorsing with plugin failed: fake-endorserment-error"))
+			})
+		})
+	})
+
+	Context("when the query cache is enabled", func() {
+		var fakeQueryCacheHits *metricsfakes.Counter
+
+		BeforeEach(func() {
+			fakeQueryCacheHits = &metricsfakes.Counter{}
+			fakeQueryCacheHits.WithReturns(fakeQueryCacheHits)
+			e.Metrics.QueryCacheHits = fakeQueryCacheHits
+			e.QueryCache = endorser.NewQueryCache(10, 0)
+
+			fakeSupport.ExecuteReturns(chaincodeResponse, nil, nil)
+			fakeTxSimulator.GetTxSimulationResultsReturns(
+				&ledger.TxSimulationResults{
+					PubSimulationResults: &rwset.TxReadWriteSet{
+						NsRwset: []*rwset.NsReadWriteSet{{
+							Namespace: chaincodeName,
+							Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+								Reads: []*kvrwset.KVRead{{Key: "asset1"}},
+							}),
+						}},
+					},
+				},
+				nil,
+			)
+		})
+
+		It("endorses repeated queries without calling the chaincode again", func() {
+			first, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(1))
+			Expect(fakeSupport.EndorseWithPluginCallCount()).To(Equal(2))
+			_, _, firstPayload, _ := fakeSupport.EndorseWithPluginArgsForCall(0)
+			_, _, secondPayload, _ := fakeSupport.EndorseWithPluginArgsForCall(1)
+			Expect(secondPayload).To(Equal(firstPayload))
+			Expect(proto.Equal(second.Response, first.Response)).To(BeTrue())
+
+			Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(1))
+			Expect(fakeQueryCacheHits.WithArgsForCall(0)).To(Equal([]string{"channel", "channel-id", "chaincode", "chaincode-name"}))
+		})
+
+		Context("when the chaincode writes to the ledger", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetTxSimulationResultsReturns(
+					&ledger.TxSimulationResults{
+						PubSimulationResults: &rwset.TxReadWriteSet{
+							NsRwset: []*rwset.NsReadWriteSet{{
+								Namespace: chaincodeName,
+								Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+									Writes: []*kvrwset.KVWrite{{Key: "asset1", Value: []byte("value")}},
+								}),
+							}},
+						},
+					},
+					nil,
+				)
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.Background(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+				Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the chaincode emits an event", func() {
+			BeforeEach(func() {
+				fakeSupport.ExecuteReturns(chaincodeResponse, chaincodeEvent, nil)
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.Background(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when it's for a system chaincode", func() {
+			BeforeEach(func() {
+				fakeSupport.IsSysCCReturns(true)
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.Background(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when the peer is overloaded", func() {
+		var fakeProposalsRejected *metricsfakes.Counter
+
+		BeforeEach(func() {
+			fakeProposalsRejected = &metricsfakes.Counter{}
+			fakeProposalsRejected.WithReturns(fakeProposalsRejected)
+			e.Metrics.ProposalsRejected = fakeProposalsRejected
+			e.Admission = endorser.NewAdmissionController(endorser.AdmissionThresholds{Goroutines: 1}, time.Second, nil)
+			e.Admission.Update()
+		})
+
+		It("rejects the proposal with a retryable status", func() {
+			proposalResponse, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Response.Status).To(Equal(int32(endorser.OverloadedStatus)))
+			Expect(proposalResponse.Response.Message).To(ContainSubstring("peer is overloaded: goroutines of"))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+
+			Expect(fakeProposalsReceived.AddCallCount()).To(Equal(1))
+			Expect(fakeProposalsRejected.AddCallCount()).To(Equal(1))
+			Expect(fakeProposalsRejected.WithArgsForCall(0)).To(Equal([]string{"reason", "goroutines"}))
+		})
+	})
+
+	Context("when the client requires a state version", func() {
+		var (
+			ctx                     context.Context
+			fakeStaleStateProposals *metricsfakes.Counter
+		)
+
+		BeforeEach(func() {
+			ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(endorser.MinStateVersionHeader, "7:cafe"))
+			fakeStaleStateProposals = &metricsfakes.Counter{}
+			fakeStaleStateProposals.WithReturns(fakeStaleStateProposals)
+			e.Metrics.StaleStateProposals = fakeStaleStateProposals
+		})
+
+		It("processes the proposal when the ledger is as fresh", func() {
+			proposalResponse, err := e.ProcessProposal(ctx, signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Response.Status).To(Equal(int32(200)))
+			Expect(fakeSupport.GetBlockchainInfoCallCount()).To(Equal(1))
+			Expect(fakeSupport.GetBlockchainInfoArgsForCall(0)).To(Equal("channel-id"))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(1))
+		})
+
+		Context("when the ledger is behind", func() {
+			BeforeEach(func() {
+				ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(endorser.MinStateVersionHeader, "8:beef"))
+			})
+
+			It("rejects the proposal with a retryable status", func() {
+				proposalResponse, err := e.ProcessProposal(ctx, signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proposalResponse.Response.Status).To(Equal(int32(endorser.StaleStateStatus)))
+				Expect(proposalResponse.Response.Message).To(Equal("ledger of channel channel-id is at state version 7:cafe, behind the required state version 8:beef, retry later"))
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+
+				Expect(fakeStaleStateProposals.AddCallCount()).To(Equal(1))
+				Expect(fakeStaleStateProposals.WithArgsForCall(0)).To(Equal([]string{"channel", "channel-id"}))
+			})
+		})
+
+		Context("when the ledger diverges at the same height", func() {
+			BeforeEach(func() {
+				ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(endorser.MinStateVersionHeader, "7:beef"))
+			})
+
+			It("rejects the proposal", func() {
+				proposalResponse, err := e.ProcessProposal(ctx, signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proposalResponse.Response.Status).To(Equal(int32(500)))
+				Expect(proposalResponse.Response.Message).To(Equal("ledger of channel channel-id is at state version 7:cafe, which diverges from the required state version 7:beef"))
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the state version is malformed", func() {
+			BeforeEach(func() {
+				ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(endorser.MinStateVersionHeader, "latest"))
+			})
+
+			It("rejects the proposal", func() {
+				proposalResponse, err := e.ProcessProposal(ctx, signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proposalResponse.Response.Status).To(Equal(int32(endorser.InvalidArgumentsStatus)))
+				Expect(proposalResponse.Response.Message).To(Equal("malformed state version 'latest': invalid height"))
+			})
+		})
+
+		Context("when the ledger info can't be retrieved", func() {
+			BeforeEach(func() {
+				fakeSupport.GetBlockchainInfoReturns(nil, fmt.Errorf("fake-info-error"))
+			})
+
+			It("returns an error to the client", func() {
+				proposalResponse, err := e.ProcessProposal(ctx, signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proposalResponse.Response).To(Equal(&pb.Response{Status: 500, Message: "fake-info-error"}))
+			})
+		})
+	})
+
+	It("reads the state version sent to the client once the simulator is acquired", func() {
+		fakeSupport.GetCommittedStateInfoStub = func(string) (*cb.BlockchainInfo, error) {
+			Expect(fakeSupport.GetTxSimulatorCallCount()).To(Equal(1))
+			return &cb.BlockchainInfo{Height: 7, CurrentBlockHash: []byte{0xca, 0xfe}}, nil
+		}
+		proposalResponse, err := e.ProcessProposal(context.Background(), signedProposal)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proposalResponse.Response.Status).To(Equal(int32(200)))
+		Expect(fakeSupport.GetCommittedStateInfoCallCount()).To(Equal(1))
+		Expect(fakeSupport.GetCommittedStateInfoArgsForCall(0)).To(Equal("channel-id"))
+		// the ledger is only checked early when the client requires a state version
+		Expect(fakeSupport.GetBlockchainInfoCallCount()).To(Equal(0))
+	})
+
+	Context("when the committed state can't be retrieved", func() {
+		BeforeEach(func() {
+			fakeSupport.GetCommittedStateInfoReturns(nil, fmt.Errorf("fake-state-info-error"))
+		})
+
+		It("returns a response with the error", func() {
+			proposalResponse, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Response).To(Equal(&pb.Response{Status: 500, Message: "fake-state-info-error"}))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+		})
+	})
+
 	It("checks for duplicate transactions", func() {
 		_, err := e.ProcessProposal(context.Background(), signedProposal)
 		Expect(err).NotTo(HaveOccurred())
@@ -433,6 +715,48 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 
+	It("checks the ACL of the chaincode function", func() {
+		_, err := e.ProcessProposal(context.Background(), signedProposal)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeSupport.CheckChaincodeFunctionACLCallCount()).To(Equal(1))
+		channelID, chaincodeName, function, _ := fakeSupport.CheckChaincodeFunctionACLArgsForCall(0)
+		Expect(channelID).To(Equal("channel-id"))
+		Expect(chaincodeName).To(Equal("chaincode-name"))
+		Expect(function).To(Equal("arg1"))
+	})
+
+	Context("when the chaincode function acl check fails", func() {
+		BeforeEach(func() {
+			fakeSupport.CheckChaincodeFunctionACLReturns(fmt.Errorf("fake-function-acl-error"))
+		})
+
+		It("returns an error and responds to the client before simulating", func() {
+			proposalResponse, err := e.ProcessProposal(context.TODO(), signedProposal)
+			Expect(err).To(MatchError("fake-function-acl-error"))
+			Expect(proposalResponse).To(Equal(&pb.ProposalResponse{
+				Response: &pb.Response{
+					Status:  500,
+					Message: "fake-function-acl-error",
+				},
+			}))
+			Expect(fakeProposalACLCheckFailed.WithCallCount()).To(Equal(1))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+		})
+
+		Context("when it's for a system chaincode", func() {
+			BeforeEach(func() {
+				fakeSupport.IsSysCCReturns(true)
+			})
+
+			It("skips the acl check", func() {
+				proposalResponse, err := e.ProcessProposal(context.TODO(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proposalResponse.Response.Status).To(Equal(int32(200)))
+				Expect(fakeSupport.CheckChaincodeFunctionACLCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	It("gets the chaincode definition", func() {
 		_, err := e.ProcessProposal(context.Background(), signedProposal)
 		Expect(err).NotTo(HaveOccurred())
@@ -458,6 +782,52 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 
+	Context("when the chaincode definition carries an argument schema", func() {
+		var fakeArgumentValidationFailed *metricsfakes.Counter
+
+		BeforeEach(func() {
+			fakeArgumentValidationFailed = &metricsfakes.Counter{}
+			fakeArgumentValidationFailed.WithReturns(fakeArgumentValidationFailed)
+			e.Metrics.ArgumentValidationFailed = fakeArgumentValidationFailed
+
+			schema, err := argschema.Parse([]byte(`{"functions": {"arg1": {"args": [{"name": "key", "type": "string"}, {"name": "amount", "type": "integer"}]}}}`))
+			Expect(err).NotTo(HaveOccurred())
+			fakeSupport.ChaincodeEndorsementInfoReturns(&lifecycle.ChaincodeEndorsementInfo{
+				Version:           "chaincode-definition-version",
+				EndorsementPlugin: "plugin-name",
+				ArgumentSchema:    schema,
+			}, nil)
+		})
+
+		It("rejects the proposal without calling the chaincode", func() {
+			proposalResponse, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Endorsement).To(BeNil())
+			Expect(proposalResponse.Response.Status).To(Equal(int32(400)))
+			Expect(proposalResponse.Response.Message).To(Equal("invalid argument 'amount' at position 2 for function 'arg1': not an integer"))
+			Expect(proposalResponse.Response.Payload).To(MatchJSON(`{"function":"arg1","index":2,"arg":"amount","reason":"not an integer"}`))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+
+			Expect(fakeArgumentValidationFailed.AddCallCount()).To(Equal(1))
+			Expect(fakeArgumentValidationFailed.WithArgsForCall(0)).To(Equal([]string{"channel", "channel-id", "chaincode", "chaincode-name"}))
+			Expect(fakeSuccessfulProposals.AddCallCount()).To(Equal(0))
+		})
+
+		Context("when the arguments comply with the schema", func() {
+			BeforeEach(func() {
+				chaincodeInput.Args = [][]byte{[]byte("arg1"), []byte("asset1"), []byte("10")}
+			})
+
+			It("calls the chaincode", func() {
+				proposalResponse, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proposalResponse.Response.Status).To(Equal(int32(200)))
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(1))
+				Expect(fakeArgumentValidationFailed.AddCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	It("calls the chaincode", func() {
 		_, err := e.ProcessProposal(context.Background(), signedProposal)
 		Expect(err).NotTo(HaveOccurred())
@@ -490,6 +860,66 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 
+	Context("when the deadline of the proposal is exceeded during the simulation", func() {
+		BeforeEach(func() {
+			fakeSupport.ExecuteReturns(nil, nil, errors.Wrap(context.DeadlineExceeded, "transaction cancelled"))
+		})
+
+		It("returns a response with the deadline exceeded status", func() {
+			proposalResponse, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Payload).To(BeNil())
+			Expect(proposalResponse.Response).To(Equal(&pb.Response{
+				Status:  endorser.DeadlineExceededStatus,
+				Message: "error in simulation: transaction cancelled: context deadline exceeded",
+			}))
+		})
+	})
+
+	Context("when the deadline of the proposal has passed", func() {
+		It("does not simulate the proposal", func() {
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			defer cancel()
+
+			proposalResponse, err := e.ProcessProposal(ctx, signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Response).To(Equal(&pb.Response{
+				Status:  endorser.DeadlineExceededStatus,
+				Message: "proposal abandoned before simulation: context deadline exceeded",
+			}))
+			Expect(fakeSupport.GetTxSimulatorCallCount()).To(Equal(0))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the client cancels the proposal", func() {
+		It("returns a response with the cancelled status", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			proposalResponse, err := e.ProcessProposal(ctx, signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Response).To(Equal(&pb.Response{
+				Status:  endorser.CancelledStatus,
+				Message: "proposal abandoned before simulation: context canceled",
+			}))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+		})
+	})
+
+	It("propagates the context of the proposal to the chaincode", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err := e.ProcessProposal(ctx, signedProposal)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeSupport.ExecuteCallCount()).To(Equal(1))
+		txParams, _, _ := fakeSupport.ExecuteArgsForCall(0)
+		Expect(txParams.Context.Err()).NotTo(HaveOccurred())
+		cancel()
+		Expect(txParams.Context.Err()).To(Equal(context.Canceled))
+	})
+
 	It("distributes private data", func() {
 		_, err := e.ProcessProposal(context.Background(), signedProposal)
 		Expect(err).NotTo(HaveOccurred())
@@ -785,6 +1215,24 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 
+	Context("when the chaincode is executed in a debug session", func() {
+		BeforeEach(func() {
+			fakeSupport.ExecuteStub = func(txParams *ccprovider.TransactionParams, name string, input *pb.ChaincodeInput) (*pb.Response, *pb.ChaincodeEvent, error) {
+				debug.Mark(txParams.Context)
+				return chaincodeResponse, nil, nil
+			}
+		})
+
+		It("returns the response without endorsing it", func() {
+			proposalResponse, err := e.ProcessProposal(context.TODO(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Endorsement).To(BeNil())
+			Expect(proposalResponse.Payload).NotTo(BeNil())
+			Expect(proto.Equal(proposalResponse.Response, chaincodeResponse)).To(BeTrue())
+			Expect(fakeSupport.EndorseWithPluginCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("when we're in the degenerate legacy lifecycle case", func() {
 		BeforeEach(func() {
 			chaincodeName = "lscc"
@@ -1021,3 +1469,19 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 })
+
+type spanRecorder struct {
+	spans []*tracing.Span
+}
+
+func (r *spanRecorder) Export(span *tracing.Span) {
+	r.spans = append(r.spans, span)
+}
+
+func (r *spanRecorder) names() []string {
+	var names []string
+	for _, span := range r.spans {
+		names = append(names, span.Name)
+	}
+	return names
+}
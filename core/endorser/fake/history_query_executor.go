@@ -22,6 +22,21 @@ type HistoryQueryExecutor struct {
 		result1 ledger.ResultsIterator
 		result2 error
 	}
+	GetUpdatedKeysBetweenBlocksStub        func(string, uint64, uint64) ([]string, error)
+	getUpdatedKeysBetweenBlocksMutex       sync.RWMutex
+	getUpdatedKeysBetweenBlocksArgsForCall []struct {
+		arg1 string
+		arg2 uint64
+		arg3 uint64
+	}
+	getUpdatedKeysBetweenBlocksReturns struct {
+		result1 []string
+		result2 error
+	}
+	getUpdatedKeysBetweenBlocksReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -90,11 +105,78 @@ func (fake *HistoryQueryExecutor) GetHistoryForKeyReturnsOnCall(i int, result1 l
 	}{result1, result2}
 }
 
+func (fake *HistoryQueryExecutor) GetUpdatedKeysBetweenBlocks(arg1 string, arg2 uint64, arg3 uint64) ([]string, error) {
+	fake.getUpdatedKeysBetweenBlocksMutex.Lock()
+	ret, specificReturn := fake.getUpdatedKeysBetweenBlocksReturnsOnCall[len(fake.getUpdatedKeysBetweenBlocksArgsForCall)]
+	fake.getUpdatedKeysBetweenBlocksArgsForCall = append(fake.getUpdatedKeysBetweenBlocksArgsForCall, struct {
+		arg1 string
+		arg2 uint64
+		arg3 uint64
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetUpdatedKeysBetweenBlocks", []interface{}{arg1, arg2, arg3})
+	fake.getUpdatedKeysBetweenBlocksMutex.Unlock()
+	if fake.GetUpdatedKeysBetweenBlocksStub != nil {
+		return fake.GetUpdatedKeysBetweenBlocksStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getUpdatedKeysBetweenBlocksReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetUpdatedKeysBetweenBlocksCallCount() int {
+	fake.getUpdatedKeysBetweenBlocksMutex.RLock()
+	defer fake.getUpdatedKeysBetweenBlocksMutex.RUnlock()
+	return len(fake.getUpdatedKeysBetweenBlocksArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetUpdatedKeysBetweenBlocksCalls(stub func(string, uint64, uint64) ([]string, error)) {
+	fake.getUpdatedKeysBetweenBlocksMutex.Lock()
+	defer fake.getUpdatedKeysBetweenBlocksMutex.Unlock()
+	fake.GetUpdatedKeysBetweenBlocksStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetUpdatedKeysBetweenBlocksArgsForCall(i int) (string, uint64, uint64) {
+	fake.getUpdatedKeysBetweenBlocksMutex.RLock()
+	defer fake.getUpdatedKeysBetweenBlocksMutex.RUnlock()
+	argsForCall := fake.getUpdatedKeysBetweenBlocksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HistoryQueryExecutor) GetUpdatedKeysBetweenBlocksReturns(result1 []string, result2 error) {
+	fake.getUpdatedKeysBetweenBlocksMutex.Lock()
+	defer fake.getUpdatedKeysBetweenBlocksMutex.Unlock()
+	fake.GetUpdatedKeysBetweenBlocksStub = nil
+	fake.getUpdatedKeysBetweenBlocksReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetUpdatedKeysBetweenBlocksReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.getUpdatedKeysBetweenBlocksMutex.Lock()
+	defer fake.getUpdatedKeysBetweenBlocksMutex.Unlock()
+	fake.GetUpdatedKeysBetweenBlocksStub = nil
+	if fake.getUpdatedKeysBetweenBlocksReturnsOnCall == nil {
+		fake.getUpdatedKeysBetweenBlocksReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.getUpdatedKeysBetweenBlocksReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HistoryQueryExecutor) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getUpdatedKeysBetweenBlocksMutex.RLock()
+	defer fake.getUpdatedKeysBetweenBlocksMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
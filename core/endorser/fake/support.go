@@ -4,6 +4,7 @@ package fake
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
@@ -38,6 +39,20 @@ type Support struct {
 	checkACLReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CheckChaincodeFunctionACLStub        func(string, string, string, *peer.SignedProposal) error
+	checkChaincodeFunctionACLMutex       sync.RWMutex
+	checkChaincodeFunctionACLArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *peer.SignedProposal
+	}
+	checkChaincodeFunctionACLReturns struct {
+		result1 error
+	}
+	checkChaincodeFunctionACLReturnsOnCall map[int]struct {
+		result1 error
+	}
 	EndorseWithPluginStub        func(string, string, []byte, *peer.SignedProposal) (*peer.Endorsement, []byte, error)
 	endorseWithPluginMutex       sync.RWMutex
 	endorseWithPluginArgsForCall []struct {
@@ -91,6 +106,32 @@ type Support struct {
 		result2 *peer.ChaincodeEvent
 		result3 error
 	}
+	GetBlockchainInfoStub        func(string) (*common.BlockchainInfo, error)
+	getBlockchainInfoMutex       sync.RWMutex
+	getBlockchainInfoArgsForCall []struct {
+		arg1 string
+	}
+	getBlockchainInfoReturns struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}
+	getBlockchainInfoReturnsOnCall map[int]struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}
+	GetCommittedStateInfoStub        func(string) (*common.BlockchainInfo, error)
+	getCommittedStateInfoMutex       sync.RWMutex
+	getCommittedStateInfoArgsForCall []struct {
+		arg1 string
+	}
+	getCommittedStateInfoReturns struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}
+	getCommittedStateInfoReturnsOnCall map[int]struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}
 	GetDeployedCCInfoProviderStub        func() ledger.DeployedChaincodeInfoProvider
 	getDeployedCCInfoProviderMutex       sync.RWMutex
 	getDeployedCCInfoProviderArgsForCall []struct {
@@ -321,6 +362,69 @@ func (fake *Support) CheckACLReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Support) CheckChaincodeFunctionACL(arg1 string, arg2 string, arg3 string, arg4 *peer.SignedProposal) error {
+	fake.checkChaincodeFunctionACLMutex.Lock()
+	ret, specificReturn := fake.checkChaincodeFunctionACLReturnsOnCall[len(fake.checkChaincodeFunctionACLArgsForCall)]
+	fake.checkChaincodeFunctionACLArgsForCall = append(fake.checkChaincodeFunctionACLArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *peer.SignedProposal
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CheckChaincodeFunctionACL", []interface{}{arg1, arg2, arg3, arg4})
+	fake.checkChaincodeFunctionACLMutex.Unlock()
+	if fake.CheckChaincodeFunctionACLStub != nil {
+		return fake.CheckChaincodeFunctionACLStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.checkChaincodeFunctionACLReturns
+	return fakeReturns.result1
+}
+
+func (fake *Support) CheckChaincodeFunctionACLCallCount() int {
+	fake.checkChaincodeFunctionACLMutex.RLock()
+	defer fake.checkChaincodeFunctionACLMutex.RUnlock()
+	return len(fake.checkChaincodeFunctionACLArgsForCall)
+}
+
+func (fake *Support) CheckChaincodeFunctionACLCalls(stub func(string, string, string, *peer.SignedProposal) error) {
+	fake.checkChaincodeFunctionACLMutex.Lock()
+	defer fake.checkChaincodeFunctionACLMutex.Unlock()
+	fake.CheckChaincodeFunctionACLStub = stub
+}
+
+func (fake *Support) CheckChaincodeFunctionACLArgsForCall(i int) (string, string, string, *peer.SignedProposal) {
+	fake.checkChaincodeFunctionACLMutex.RLock()
+	defer fake.checkChaincodeFunctionACLMutex.RUnlock()
+	argsForCall := fake.checkChaincodeFunctionACLArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Support) CheckChaincodeFunctionACLReturns(result1 error) {
+	fake.checkChaincodeFunctionACLMutex.Lock()
+	defer fake.checkChaincodeFunctionACLMutex.Unlock()
+	fake.CheckChaincodeFunctionACLStub = nil
+	fake.checkChaincodeFunctionACLReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Support) CheckChaincodeFunctionACLReturnsOnCall(i int, result1 error) {
+	fake.checkChaincodeFunctionACLMutex.Lock()
+	defer fake.checkChaincodeFunctionACLMutex.Unlock()
+	fake.CheckChaincodeFunctionACLStub = nil
+	if fake.checkChaincodeFunctionACLReturnsOnCall == nil {
+		fake.checkChaincodeFunctionACLReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkChaincodeFunctionACLReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Support) EndorseWithPlugin(arg1 string, arg2 string, arg3 []byte, arg4 *peer.SignedProposal) (*peer.Endorsement, []byte, error) {
 	var arg3Copy []byte
 	if arg3 != nil {
@@ -532,6 +636,132 @@ func (fake *Support) ExecuteLegacyInitReturnsOnCall(i int, result1 *peer.Respons
 	}{result1, result2, result3}
 }
 
+func (fake *Support) GetBlockchainInfo(arg1 string) (*common.BlockchainInfo, error) {
+	fake.getBlockchainInfoMutex.Lock()
+	ret, specificReturn := fake.getBlockchainInfoReturnsOnCall[len(fake.getBlockchainInfoArgsForCall)]
+	fake.getBlockchainInfoArgsForCall = append(fake.getBlockchainInfoArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetBlockchainInfo", []interface{}{arg1})
+	fake.getBlockchainInfoMutex.Unlock()
+	if fake.GetBlockchainInfoStub != nil {
+		return fake.GetBlockchainInfoStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getBlockchainInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Support) GetBlockchainInfoCallCount() int {
+	fake.getBlockchainInfoMutex.RLock()
+	defer fake.getBlockchainInfoMutex.RUnlock()
+	return len(fake.getBlockchainInfoArgsForCall)
+}
+
+func (fake *Support) GetBlockchainInfoCalls(stub func(string) (*common.BlockchainInfo, error)) {
+	fake.getBlockchainInfoMutex.Lock()
+	defer fake.getBlockchainInfoMutex.Unlock()
+	fake.GetBlockchainInfoStub = stub
+}
+
+func (fake *Support) GetBlockchainInfoArgsForCall(i int) string {
+	fake.getBlockchainInfoMutex.RLock()
+	defer fake.getBlockchainInfoMutex.RUnlock()
+	argsForCall := fake.getBlockchainInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Support) GetBlockchainInfoReturns(result1 *common.BlockchainInfo, result2 error) {
+	fake.getBlockchainInfoMutex.Lock()
+	defer fake.getBlockchainInfoMutex.Unlock()
+	fake.GetBlockchainInfoStub = nil
+	fake.getBlockchainInfoReturns = struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Support) GetBlockchainInfoReturnsOnCall(i int, result1 *common.BlockchainInfo, result2 error) {
+	fake.getBlockchainInfoMutex.Lock()
+	defer fake.getBlockchainInfoMutex.Unlock()
+	fake.GetBlockchainInfoStub = nil
+	if fake.getBlockchainInfoReturnsOnCall == nil {
+		fake.getBlockchainInfoReturnsOnCall = make(map[int]struct {
+			result1 *common.BlockchainInfo
+			result2 error
+		})
+	}
+	fake.getBlockchainInfoReturnsOnCall[i] = struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Support) GetCommittedStateInfo(arg1 string) (*common.BlockchainInfo, error) {
+	fake.getCommittedStateInfoMutex.Lock()
+	ret, specificReturn := fake.getCommittedStateInfoReturnsOnCall[len(fake.getCommittedStateInfoArgsForCall)]
+	fake.getCommittedStateInfoArgsForCall = append(fake.getCommittedStateInfoArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetCommittedStateInfo", []interface{}{arg1})
+	fake.getCommittedStateInfoMutex.Unlock()
+	if fake.GetCommittedStateInfoStub != nil {
+		return fake.GetCommittedStateInfoStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCommittedStateInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Support) GetCommittedStateInfoCallCount() int {
+	fake.getCommittedStateInfoMutex.RLock()
+	defer fake.getCommittedStateInfoMutex.RUnlock()
+	return len(fake.getCommittedStateInfoArgsForCall)
+}
+
+func (fake *Support) GetCommittedStateInfoCalls(stub func(string) (*common.BlockchainInfo, error)) {
+	fake.getCommittedStateInfoMutex.Lock()
+	defer fake.getCommittedStateInfoMutex.Unlock()
+	fake.GetCommittedStateInfoStub = stub
+}
+
+func (fake *Support) GetCommittedStateInfoArgsForCall(i int) string {
+	fake.getCommittedStateInfoMutex.RLock()
+	defer fake.getCommittedStateInfoMutex.RUnlock()
+	argsForCall := fake.getCommittedStateInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Support) GetCommittedStateInfoReturns(result1 *common.BlockchainInfo, result2 error) {
+	fake.getCommittedStateInfoMutex.Lock()
+	defer fake.getCommittedStateInfoMutex.Unlock()
+	fake.GetCommittedStateInfoStub = nil
+	fake.getCommittedStateInfoReturns = struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Support) GetCommittedStateInfoReturnsOnCall(i int, result1 *common.BlockchainInfo, result2 error) {
+	fake.getCommittedStateInfoMutex.Lock()
+	defer fake.getCommittedStateInfoMutex.Unlock()
+	fake.GetCommittedStateInfoStub = nil
+	if fake.getCommittedStateInfoReturnsOnCall == nil {
+		fake.getCommittedStateInfoReturnsOnCall = make(map[int]struct {
+			result1 *common.BlockchainInfo
+			result2 error
+		})
+	}
+	fake.getCommittedStateInfoReturnsOnCall[i] = struct {
+		result1 *common.BlockchainInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Support) GetDeployedCCInfoProvider() ledger.DeployedChaincodeInfoProvider {
 	fake.getDeployedCCInfoProviderMutex.Lock()
 	ret, specificReturn := fake.getDeployedCCInfoProviderReturnsOnCall[len(fake.getDeployedCCInfoProviderArgsForCall)]
@@ -1028,12 +1258,18 @@ func (fake *Support) Invocations() map[string][][]interface{} {
 	defer fake.chaincodeEndorsementInfoMutex.RUnlock()
 	fake.checkACLMutex.RLock()
 	defer fake.checkACLMutex.RUnlock()
+	fake.checkChaincodeFunctionACLMutex.RLock()
+	defer fake.checkChaincodeFunctionACLMutex.RUnlock()
 	fake.endorseWithPluginMutex.RLock()
 	defer fake.endorseWithPluginMutex.RUnlock()
 	fake.executeMutex.RLock()
 	defer fake.executeMutex.RUnlock()
 	fake.executeLegacyInitMutex.RLock()
 	defer fake.executeLegacyInitMutex.RUnlock()
+	fake.getBlockchainInfoMutex.RLock()
+	defer fake.getBlockchainInfoMutex.RUnlock()
+	fake.getCommittedStateInfoMutex.RLock()
+	defer fake.getCommittedStateInfoMutex.RUnlock()
 	fake.getDeployedCCInfoProviderMutex.RLock()
 	defer fake.getDeployedCCInfoProviderMutex.RUnlock()
 	fake.getHistoryQueryExecutorMutex.RLock()
@@ -74,6 +74,38 @@ var (
 		LabelNames:   []string{"channel", "chaincode"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
 	}
+
+	queryCacheHitsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "query_cache_hits",
+		Help:         "The number of proposals answered from the query cache.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	argumentValidationFailureCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "argument_validation_failures",
+		Help:         "The number of proposals rejected as their arguments do not comply with the argument schema of the chaincode.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	proposalsRejectedCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "proposals_rejected",
+		Help:         "The number of proposals rejected as the peer is overloaded.",
+		LabelNames:   []string{"reason"},
+		StatsdFormat: "%{#fqname}.%{reason}",
+	}
+
+	staleStateProposalsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "stale_state_proposals",
+		Help:         "The number of proposals rejected as the ledger of the peer is behind the state version required by the client.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
 
 type Metrics struct {
@@ -86,6 +118,10 @@ type Metrics struct {
 	EndorsementsFailed       metrics.Counter
 	DuplicateTxsFailure      metrics.Counter
 	SimulationFailure        metrics.Counter
+	QueryCacheHits           metrics.Counter
+	ProposalsRejected        metrics.Counter
+	ArgumentValidationFailed metrics.Counter
+	StaleStateProposals      metrics.Counter
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
@@ -99,5 +135,9 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		EndorsementsFailed:       p.NewCounter(endorsementFailureCounterOpts),
 		DuplicateTxsFailure:      p.NewCounter(duplicateTxsFailureCounterOpts),
 		SimulationFailure:        p.NewCounter(simulationFailureCounterOpts),
+		QueryCacheHits:           p.NewCounter(queryCacheHitsCounterOpts),
+		ProposalsRejected:        p.NewCounter(proposalsRejectedCounterOpts),
+		ArgumentValidationFailed: p.NewCounter(argumentValidationFailureCounterOpts),
+		StaleStateProposals:      p.NewCounter(staleStateProposalsCounterOpts),
 	}
 }
@@ -31,6 +31,10 @@ func TestNewMetrics(t *testing.T) {
 		EndorsementsFailed:       &metricsfakes.Counter{},
 		DuplicateTxsFailure:      &metricsfakes.Counter{},
 		SimulationFailure:        &metricsfakes.Counter{},
+		QueryCacheHits:           &metricsfakes.Counter{},
+		ProposalsRejected:        &metricsfakes.Counter{},
+		ArgumentValidationFailed: &metricsfakes.Counter{},
+		StaleStateProposals:      &metricsfakes.Counter{},
 	}))
 
 	gt.Expect(provider.NewHistogramCallCount()).To(Equal(1))
@@ -38,7 +42,7 @@ func TestNewMetrics(t *testing.T) {
 		{proposalDurationHistogramOpts},
 	}))
 
-	gt.Expect(provider.NewCounterCallCount()).To(Equal(8))
+	gt.Expect(provider.NewCounterCallCount()).To(Equal(12))
 	gt.Expect(provider.Invocations()["NewCounter"]).To(ConsistOf([][]interface{}{
 		{receivedProposalsCounterOpts},
 		{successfulProposalsCounterOpts},
@@ -48,5 +52,9 @@ func TestNewMetrics(t *testing.T) {
 		{endorsementFailureCounterOpts},
 		{duplicateTxsFailureCounterOpts},
 		{simulationFailureCounterOpts},
+		{queryCacheHitsCounterOpts},
+		{proposalsRejectedCounterOpts},
+		{argumentValidationFailureCounterOpts},
+		{staleStateProposalsCounterOpts},
 	}))
 }
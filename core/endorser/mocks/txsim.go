@@ -114,6 +114,10 @@ func (m *MockTxSim) DeleteStateMetadata(namespace, key string) error {
 	return nil
 }
 
+func (m *MockTxSim) IncrementState(namespace, key string, delta int64) error {
+	return nil
+}
+
 func (m *MockTxSim) SetPrivateDataMetadata(namespace, collection, key string, metadata map[string][]byte) error {
 	return nil
 }
@@ -0,0 +1,310 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// QueryCache caches the results of the simulation of read-only proposals, so
+// that hot repeated queries, such as asset lookups from web frontends, are
+// answered without executing the chaincode.  The results are keyed by the
+// channel, the chaincode, the creator and the input of the proposal and are
+// dropped once a block updating one of the namespaces they read is committed,
+// once they expire or when they are evicted to make room for newer results.
+//
+// Only successful simulations which do not write to the ledger, do not touch
+// private data and do not emit a chaincode event are cached.  Proposals
+// carrying transient data are never cached.
+type QueryCache struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// sequence is incremented by each invalidation, and invalidated holds the
+	// sequence of the last invalidation of each namespace of each channel.
+	// The invalidations of a whole channel are recorded under the empty
+	// namespace.
+	sequence    uint64
+	invalidated map[string]map[string]uint64
+}
+
+type cachedQuery struct {
+	key              string
+	channelID        string
+	namespaces       []string
+	response         *pb.Response
+	simulationResult []byte
+	expiry           time.Time
+}
+
+// NewQueryCache creates a query cache holding at most size results for at
+// most ttl.  A zero ttl means the results only expire when invalidated.
+func NewQueryCache(size int, ttl time.Duration) *QueryCache {
+	return &QueryCache{
+		size:        size,
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     map[string]*list.Element{},
+		lru:         list.New(),
+		invalidated: map[string]map[string]uint64{},
+	}
+}
+
+// Key returns the cache key of the proposal, or false if the result of the
+// proposal can't be cached.
+func (c *QueryCache) Key(up *UnpackedProposal) (string, bool) {
+	if c == nil || up.ChannelID() == "" {
+		return "", false
+	}
+	cpp, err := protoutil.UnmarshalChaincodeProposalPayload(up.Proposal.Payload)
+	if err != nil || len(cpp.TransientMap) > 0 {
+		return "", false
+	}
+
+	h := sha256.New()
+	writeField(h, []byte(up.ChannelID()))
+	writeField(h, []byte(up.ChaincodeName))
+	writeField(h, up.SignatureHeader.Creator)
+	for _, arg := range up.Input.Args {
+		writeField(h, arg)
+	}
+	decorations := make([]string, 0, len(up.Input.Decorations))
+	for name := range up.Input.Decorations {
+		decorations = append(decorations, name)
+	}
+	sort.Strings(decorations)
+	for _, name := range decorations {
+		writeField(h, []byte(name))
+		writeField(h, up.Input.Decorations[name])
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func writeField(h hash.Hash, field []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+	h.Write(length[:])
+	h.Write(field)
+}
+
+// Sequence returns the current invalidation sequence, to be passed to Put
+// once the proposal is simulated.
+func (c *QueryCache) Sequence() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.sequence
+}
+
+// Get returns the response and the simulation results cached for the key.
+func (c *QueryCache) Get(key string) (*pb.Response, []byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	entry := element.Value.(*cachedQuery)
+	if !entry.expiry.IsZero() && c.now().After(entry.expiry) {
+		c.remove(element)
+		return nil, nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.response, entry.simulationResult, true
+}
+
+// Put caches the result of the simulation of a proposal, if it is cacheable.
+// The sequence is the one returned by Sequence before the simulation; the
+// result is discarded if one of the namespaces it read was updated since.
+func (c *QueryCache) Put(key, channelID string, sequence uint64, res *pb.Response, simulationResult []byte, ccevent *pb.ChaincodeEvent) {
+	if res.GetStatus() != shim.OK || ccevent != nil {
+		return
+	}
+	namespaces, ok := readNamespaces(simulationResult)
+	if !ok {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	invalidated := c.invalidated[channelID]
+	if invalidated[""] > sequence {
+		return
+	}
+	for _, ns := range namespaces {
+		if invalidated[ns] > sequence {
+			return
+		}
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	entry := &cachedQuery{
+		key:              key,
+		channelID:        channelID,
+		namespaces:       namespaces,
+		response:         res,
+		simulationResult: simulationResult,
+	}
+	if c.ttl > 0 {
+		entry.expiry = c.now().Add(c.ttl)
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// BlockCommitted drops the cached results which read a namespace updated by
+// the valid transactions of the block.  All the results cached for the
+// channel are dropped on config blocks and chaincode definitions.
+func (c *QueryCache) BlockCommitted(channelID string, block *cb.Block) {
+	namespaces, all := updatedNamespaces(block)
+	if len(namespaces) == 0 && !all {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sequence++
+	invalidated, ok := c.invalidated[channelID]
+	if !ok {
+		invalidated = map[string]uint64{}
+		c.invalidated[channelID] = invalidated
+	}
+	if all {
+		invalidated[""] = c.sequence
+	}
+	for ns := range namespaces {
+		invalidated[ns] = c.sequence
+	}
+
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*cachedQuery)
+		if entry.channelID == channelID && (all || readsAny(entry, namespaces)) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *QueryCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cachedQuery).key)
+}
+
+func readsAny(entry *cachedQuery, namespaces map[string]struct{}) bool {
+	for _, ns := range entry.namespaces {
+		if _, ok := namespaces[ns]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readNamespaces returns the namespaces read by a simulation, or false if the
+// simulation wrote to the ledger or touched private data.
+func readNamespaces(simulationResult []byte) ([]string, bool) {
+	txRWSet := &rwset.TxReadWriteSet{}
+	if err := proto.Unmarshal(simulationResult, txRWSet); err != nil {
+		return nil, false
+	}
+	var namespaces []string
+	for _, nsRWSet := range txRWSet.NsRwset {
+		if len(nsRWSet.CollectionHashedRwset) > 0 {
+			return nil, false
+		}
+		kvRWSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+			return nil, false
+		}
+		if len(kvRWSet.Writes) > 0 || len(kvRWSet.MetadataWrites) > 0 {
+			return nil, false
+		}
+		namespaces = append(namespaces, nsRWSet.Namespace)
+	}
+	return namespaces, true
+}
+
+// updatedNamespaces returns the namespaces written by the valid transactions
+// of the block, or true if the whole channel has to be considered updated.
+func updatedNamespaces(block *cb.Block) (map[string]struct{}, bool) {
+	namespaces := map[string]struct{}{}
+	flags := txflags.ValidationFlags(block.Metadata.GetMetadata()[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i, envBytes := range block.Data.GetData() {
+		if len(flags) > i && flags.IsInvalid(i) {
+			continue
+		}
+		env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, true
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return nil, true
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.GetHeader().GetChannelHeader())
+		if err != nil {
+			return nil, true
+		}
+		if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+			return nil, true
+		}
+		tx, err := protoutil.UnmarshalTransaction(payload.Data)
+		if err != nil {
+			return nil, true
+		}
+		for _, action := range tx.Actions {
+			_, ccAction, err := protoutil.GetPayloads(action)
+			if err != nil {
+				return nil, true
+			}
+			txRWSet := &rwset.TxReadWriteSet{}
+			if err := proto.Unmarshal(ccAction.Results, txRWSet); err != nil {
+				return nil, true
+			}
+			for _, nsRWSet := range txRWSet.NsRwset {
+				kvRWSet := &kvrwset.KVRWSet{}
+				if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+					return nil, true
+				}
+				if len(kvRWSet.Writes) == 0 && len(kvRWSet.MetadataWrites) == 0 {
+					continue
+				}
+				if nsRWSet.Namespace == "_lifecycle" || nsRWSet.Namespace == "lscc" {
+					return nil, true
+				}
+				namespaces[nsRWSet.Namespace] = struct{}{}
+			}
+		}
+	}
+	return namespaces, false
+}
@@ -0,0 +1,261 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readSet(namespaces ...string) []byte {
+	txRWSet := &rwset.TxReadWriteSet{}
+	for _, ns := range namespaces {
+		txRWSet.NsRwset = append(txRWSet.NsRwset, &rwset.NsReadWriteSet{
+			Namespace: ns,
+			Rwset:     protoutil.MarshalOrPanic(&kvrwset.KVRWSet{Reads: []*kvrwset.KVRead{{Key: "key"}}}),
+		})
+	}
+	return protoutil.MarshalOrPanic(txRWSet)
+}
+
+func writeSet(namespaces ...string) []byte {
+	txRWSet := &rwset.TxReadWriteSet{}
+	for _, ns := range namespaces {
+		txRWSet.NsRwset = append(txRWSet.NsRwset, &rwset.NsReadWriteSet{
+			Namespace: ns,
+			Rwset:     protoutil.MarshalOrPanic(&kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}}),
+		})
+	}
+	return protoutil.MarshalOrPanic(txRWSet)
+}
+
+func txEnvelope(headerType cb.HeaderType, results []byte) []byte {
+	prp := &pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{Results: results}),
+	}
+	tx := &pb.Transaction{
+		Actions: []*pb.TransactionAction{{
+			Payload: protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+				Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: protoutil.MarshalOrPanic(prp)},
+			}),
+		}},
+	}
+	payload := &cb.Payload{
+		Header: protoutil.MakePayloadHeader(protoutil.MakeChannelHeader(headerType, 0, "ch1", 0), &cb.SignatureHeader{}),
+		Data:   protoutil.MarshalOrPanic(tx),
+	}
+	return protoutil.MarshalOrPanic(&cb.Envelope{Payload: protoutil.MarshalOrPanic(payload)})
+}
+
+func blockOf(envelopes [][]byte, flags txflags.ValidationFlags) *cb.Block {
+	block := protoutil.NewBlock(1, nil)
+	block.Data.Data = envelopes
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	proposal := func(creator string, transient map[string][]byte, args ...string) *UnpackedProposal {
+		input := &pb.ChaincodeInput{Decorations: map[string][]byte{"b": []byte("2"), "a": []byte("1")}}
+		for _, arg := range args {
+			input.Args = append(input.Args, []byte(arg))
+		}
+		return &UnpackedProposal{
+			ChaincodeName:   "cc",
+			ChannelHeader:   &cb.ChannelHeader{ChannelId: "ch1"},
+			SignatureHeader: &cb.SignatureHeader{Creator: []byte(creator)},
+			Input:           input,
+			Proposal: &pb.Proposal{
+				Payload: protoutil.MarshalOrPanic(&pb.ChaincodeProposalPayload{TransientMap: transient}),
+			},
+		}
+	}
+
+	var nilCache *QueryCache
+	_, ok := nilCache.Key(proposal("alice", nil, "get", "asset1"))
+	assert.False(t, ok)
+
+	c := NewQueryCache(10, 0)
+	key, ok := c.Key(proposal("alice", nil, "get", "asset1"))
+	require.True(t, ok)
+	sameKey, _ := c.Key(proposal("alice", nil, "get", "asset1"))
+	assert.Equal(t, key, sameKey)
+
+	otherArgs, _ := c.Key(proposal("alice", nil, "get", "asset2"))
+	assert.NotEqual(t, key, otherArgs)
+	splitArgs, _ := c.Key(proposal("alice", nil, "getasset1"))
+	assert.NotEqual(t, key, splitArgs)
+	otherCreator, _ := c.Key(proposal("bob", nil, "get", "asset1"))
+	assert.NotEqual(t, key, otherCreator)
+
+	_, ok = c.Key(proposal("alice", map[string][]byte{"secret": []byte("value")}, "get", "asset1"))
+	assert.False(t, ok)
+
+	noChannel := proposal("alice", nil, "get", "asset1")
+	noChannel.ChannelHeader.ChannelId = ""
+	_, ok = c.Key(noChannel)
+	assert.False(t, ok)
+}
+
+func TestQueryCachePut(t *testing.T) {
+	res := &pb.Response{Status: 200, Payload: []byte("asset")}
+	c := NewQueryCache(2, 0)
+
+	c.Put("k1", "ch1", c.Sequence(), res, readSet("cc"), nil)
+	cachedRes, cachedResults, ok := c.Get("k1")
+	require.True(t, ok)
+	assert.Equal(t, res, cachedRes)
+	assert.Equal(t, readSet("cc"), cachedResults)
+
+	c.Put("k2", "ch1", c.Sequence(), res, writeSet("cc"), nil)
+	_, _, ok = c.Get("k2")
+	assert.False(t, ok, "writes are not cached")
+
+	c.Put("k2", "ch1", c.Sequence(), res, readSet("cc"), &pb.ChaincodeEvent{EventName: "event"})
+	_, _, ok = c.Get("k2")
+	assert.False(t, ok, "events are not cached")
+
+	c.Put("k2", "ch1", c.Sequence(), &pb.Response{Status: 404}, readSet("cc"), nil)
+	_, _, ok = c.Get("k2")
+	assert.False(t, ok, "failures are not cached")
+
+	private := protoutil.MarshalOrPanic(&rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{{
+			Namespace:             "cc",
+			CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{{CollectionName: "coll"}},
+		}},
+	})
+	c.Put("k2", "ch1", c.Sequence(), res, private, nil)
+	_, _, ok = c.Get("k2")
+	assert.False(t, ok, "private data is not cached")
+
+	// the least recently used result is evicted
+	c.Put("k2", "ch1", c.Sequence(), res, readSet("cc"), nil)
+	_, _, ok = c.Get("k1")
+	require.True(t, ok)
+	c.Put("k3", "ch1", c.Sequence(), res, readSet("cc"), nil)
+	_, _, ok = c.Get("k2")
+	assert.False(t, ok)
+	_, _, ok = c.Get("k1")
+	assert.True(t, ok)
+	_, _, ok = c.Get("k3")
+	assert.True(t, ok)
+}
+
+func TestQueryCacheTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewQueryCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Put("k1", "ch1", c.Sequence(), &pb.Response{Status: 200}, readSet("cc"), nil)
+	now = now.Add(time.Minute)
+	_, _, ok := c.Get("k1")
+	assert.True(t, ok)
+	now = now.Add(time.Second)
+	_, _, ok = c.Get("k1")
+	assert.False(t, ok)
+}
+
+func TestQueryCacheBlockCommitted(t *testing.T) {
+	res := &pb.Response{Status: 200}
+	populate := func() *QueryCache {
+		c := NewQueryCache(10, 0)
+		c.Put("cc1", "ch1", c.Sequence(), res, readSet("cc1"), nil)
+		c.Put("cc1+cc2", "ch1", c.Sequence(), res, readSet("cc1", "cc2"), nil)
+		c.Put("cc2", "ch1", c.Sequence(), res, readSet("cc2"), nil)
+		c.Put("ch2", "ch2", c.Sequence(), res, readSet("cc1"), nil)
+		return c
+	}
+	cached := func(c *QueryCache) []string {
+		var keys []string
+		for _, key := range []string{"cc1", "cc1+cc2", "cc2", "ch2"} {
+			if _, _, ok := c.Get(key); ok {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		block    *cb.Block
+		expected []string
+	}{
+		{
+			name:     "write",
+			block:    blockOf([][]byte{txEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, writeSet("cc2"))}, nil),
+			expected: []string{"cc1", "ch2"},
+		},
+		{
+			name:     "read only transaction",
+			block:    blockOf([][]byte{txEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, readSet("cc2"))}, nil),
+			expected: []string{"cc1", "cc1+cc2", "cc2", "ch2"},
+		},
+		{
+			name: "invalid transaction",
+			block: blockOf(
+				[][]byte{txEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, writeSet("cc2"))},
+				txflags.NewWithValues(1, pb.TxValidationCode_MVCC_READ_CONFLICT),
+			),
+			expected: []string{"cc1", "cc1+cc2", "cc2", "ch2"},
+		},
+		{
+			name:     "chaincode definition",
+			block:    blockOf([][]byte{txEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, writeSet("_lifecycle"))}, nil),
+			expected: []string{"ch2"},
+		},
+		{
+			name:     "config",
+			block:    blockOf([][]byte{txEnvelope(cb.HeaderType_CONFIG, nil)}, nil),
+			expected: []string{"ch2"},
+		},
+		{
+			name:     "garbage",
+			block:    blockOf([][]byte{[]byte("garbage")}, nil),
+			expected: []string{"ch2"},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := populate()
+			c.BlockCommitted("ch1", testCase.block)
+			assert.Equal(t, testCase.expected, cached(c))
+		})
+	}
+}
+
+func TestQueryCacheConcurrentCommit(t *testing.T) {
+	res := &pb.Response{Status: 200}
+	block := blockOf([][]byte{txEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, writeSet("cc1"))}, nil)
+	c := NewQueryCache(10, 0)
+
+	// results simulated before a commit updating the state they read are not
+	// cached once the commit is observed
+	sequence := c.Sequence()
+	c.BlockCommitted("ch1", block)
+	c.Put("cc1", "ch1", sequence, res, readSet("cc1"), nil)
+	c.Put("cc2", "ch1", sequence, res, readSet("cc2"), nil)
+	c.Put("ch2", "ch2", sequence, res, readSet("cc1"), nil)
+	_, _, ok := c.Get("cc1")
+	assert.False(t, ok)
+	_, _, ok = c.Get("cc2")
+	assert.True(t, ok)
+	_, _, ok = c.Get("ch2")
+	assert.True(t, ok)
+
+	c.Put("cc1", "ch1", c.Sequence(), res, readSet("cc1"), nil)
+	_, _, ok = c.Get("cc1")
+	assert.True(t, ok)
+}
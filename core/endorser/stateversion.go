@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// StateVersionHeader is the gRPC header metadata key set on the responses
+	// to the proposals of a channel, holding the state version of the ledger
+	// of the peer the proposal was simulated at.
+	StateVersionHeader = "fabric-state-version"
+
+	// MinStateVersionHeader is the gRPC metadata key clients present a state
+	// version in, to require the ledger of the peer to be at least as fresh
+	// as the state version. Clients of query farms behind load balancers pass
+	// the state version of the previous response to avoid stale reads.
+	MinStateVersionHeader = "fabric-min-state-version"
+)
+
+// StaleStateStatus is the status of the response to a proposal rejected as
+// the ledger of the peer is behind the state version required by the client.
+// The proposal can be retried later or sent to another peer.
+const StaleStateStatus = 425
+
+// StateVersion identifies the state of the ledger of a channel by the height
+// of the ledger and the hash of the last block committed to it. It is
+// encoded as "<height>:<hex encoded block hash>".
+type StateVersion struct {
+	Height    uint64
+	BlockHash []byte
+}
+
+func (v *StateVersion) String() string {
+	return fmt.Sprintf("%d:%x", v.Height, v.BlockHash)
+}
+
+// ParseStateVersion decodes a state version encoded by String.
+func ParseStateVersion(s string) (*StateVersion, error) {
+	parts := strings.SplitN(s, ":", 2)
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("malformed state version '%s': invalid height", s)
+	}
+	v := &StateVersion{Height: height}
+	if len(parts) == 2 {
+		if v.BlockHash, err = hex.DecodeString(parts[1]); err != nil {
+			return nil, errors.Errorf("malformed state version '%s': invalid block hash", s)
+		}
+	}
+	return v, nil
+}
+
+// minStateVersion returns the state version presented by the client in the
+// metadata of the request, if any.
+func minStateVersion(ctx context.Context) (*StateVersion, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	values := md.Get(MinStateVersionHeader)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return ParseStateVersion(values[0])
+}
+
+// checkMinStateVersion returns a response rejecting the proposal if the
+// ledger of the channel of the proposal is behind the state version required
+// by the client. The proposal is rejected early, before a simulator is
+// acquired, and the state version returned to the client is read later by
+// sendStateVersion.
+func (e *Endorser) checkMinStateVersion(ctx context.Context, up *UnpackedProposal) *pb.ProposalResponse {
+	required, err := minStateVersion(ctx)
+	if err != nil {
+		return &pb.ProposalResponse{Response: &pb.Response{Status: InvalidArgumentsStatus, Message: err.Error()}}
+	}
+	if required == nil {
+		return nil
+	}
+
+	info, err := e.Support.GetBlockchainInfo(up.ChannelID())
+	if err != nil {
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}
+	}
+	current := &StateVersion{Height: info.Height, BlockHash: info.CurrentBlockHash}
+
+	switch {
+	case current.Height < required.Height:
+		e.Metrics.StaleStateProposals.With("channel", up.ChannelID()).Add(1)
+		return &pb.ProposalResponse{Response: &pb.Response{
+			Status:  StaleStateStatus,
+			Message: fmt.Sprintf("ledger of channel %s is at state version %s, behind the required state version %s, retry later", up.ChannelID(), current, required),
+		}}
+	case current.Height == required.Height && len(required.BlockHash) != 0 && !bytes.Equal(current.BlockHash, required.BlockHash):
+		return &pb.ProposalResponse{Response: &pb.Response{
+			Status:  500,
+			Message: fmt.Sprintf("ledger of channel %s is at state version %s, which diverges from the required state version %s", up.ChannelID(), current, required),
+		}}
+	}
+	return nil
+}
+
+// sendStateVersion sends the state version of the ledger of the channel to
+// the client. It is called once the simulator of the proposal, if any, is
+// acquired, so that the state version identifies the state the proposal is
+// simulated at: the simulator holds off the commits to the state database.
+func (e *Endorser) sendStateVersion(ctx context.Context, channelID string) error {
+	info, err := e.Support.GetCommittedStateInfo(channelID)
+	if err != nil {
+		return err
+	}
+	current := &StateVersion{Height: info.Height, BlockHash: info.CurrentBlockHash}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(StateVersionHeader, current.String())); err != nil {
+		endorserLogger.Debugf("Failed setting the %s header: %s", StateVersionHeader, err)
+	}
+	return nil
+}
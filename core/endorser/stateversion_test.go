@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParseStateVersion(t *testing.T) {
+	v := &StateVersion{Height: 42, BlockHash: []byte{0xca, 0xfe}}
+	assert.Equal(t, "42:cafe", v.String())
+
+	parsed, err := ParseStateVersion(v.String())
+	assert.NoError(t, err)
+	assert.Equal(t, v, parsed)
+
+	parsed, err = ParseStateVersion("42")
+	assert.NoError(t, err)
+	assert.Equal(t, &StateVersion{Height: 42}, parsed)
+
+	_, err = ParseStateVersion("-1:cafe")
+	assert.EqualError(t, err, "malformed state version '-1:cafe': invalid height")
+
+	_, err = ParseStateVersion("42:coffee")
+	assert.EqualError(t, err, "malformed state version '42:coffee': invalid block hash")
+}
+
+func TestMinStateVersion(t *testing.T) {
+	v, err := minStateVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("other", "value"))
+	v, err = minStateVersion(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(MinStateVersionHeader, "3:beef"))
+	v, err = minStateVersion(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &StateVersion{Height: 3, BlockHash: []byte{0xbe, 0xef}}, v)
+}
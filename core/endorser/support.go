@@ -9,6 +9,7 @@ package endorser
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/core/aclmgmt"
@@ -41,6 +42,8 @@ type SupportImpl struct {
 	ChaincodeSupport *chaincode.ChaincodeSupport
 	ACLProvider      aclmgmt.ACLProvider
 	BuiltinSCCs      scc.BuiltinSCCs
+
+	ChaincodeFunctionACLProvider *aclmgmt.ChaincodeFunctionACLProvider
 }
 
 func (s *SupportImpl) NewQueryCreator(channel string) (QueryCreator, error) {
@@ -104,6 +107,38 @@ func (s *SupportImpl) GetLedgerHeight(channelID string) (uint64, error) {
 	return info.Height, nil
 }
 
+// GetBlockchainInfo returns the height and the hash of the last block of the
+// ledger of the given channelID
+func (s *SupportImpl) GetBlockchainInfo(channelID string) (*common.BlockchainInfo, error) {
+	lgr := s.Peer.GetLedger(channelID)
+	if lgr == nil {
+		return nil, errors.Errorf("failed to look up the ledger for Channel %s", channelID)
+	}
+
+	info, err := lgr.GetBlockchainInfo()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to obtain information for Channel %s", channelID)
+	}
+
+	return info, nil
+}
+
+// GetCommittedStateInfo returns the height and the hash of the last block
+// committed to the state database of the ledger of the given channelID
+func (s *SupportImpl) GetCommittedStateInfo(channelID string) (*common.BlockchainInfo, error) {
+	lgr := s.Peer.GetLedger(channelID)
+	if lgr == nil {
+		return nil, errors.Errorf("failed to look up the ledger for Channel %s", channelID)
+	}
+
+	info, err := lgr.GetCommittedStateInfo()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to obtain the committed state of Channel %s", channelID)
+	}
+
+	return info, nil
+}
+
 // IsSysCC returns true if the name matches a system chaincode's
 // system chaincode names are system, chain wide
 func (s *SupportImpl) IsSysCC(name string) bool {
@@ -137,6 +172,15 @@ func (s *SupportImpl) CheckACL(channelID string, signedProp *pb.SignedProposal)
 	return s.ACLProvider.CheckACL(resources.Peer_Propose, channelID, signedProp)
 }
 
+// CheckChaincodeFunctionACL checks the ACL defined in the channel config for the
+// function of the chaincode, if any, using the SignedProposal
+func (s *SupportImpl) CheckChaincodeFunctionACL(channelID, chaincodeName, function string, signedProp *pb.SignedProposal) error {
+	if s.ChaincodeFunctionACLProvider == nil {
+		return nil
+	}
+	return s.ChaincodeFunctionACLProvider.CheckACL(channelID, chaincodeName, function, signedProp)
+}
+
 // GetApplicationConfig returns the configtxapplication.SharedConfig for the Channel
 // and whether the Application config exists
 func (s *SupportImpl) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
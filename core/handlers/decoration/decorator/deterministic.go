@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package decorator
+
+import (
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/handlers/decoration"
+	"github.com/hyperledger/fabric/pkg/deterministic"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+var logger = flogging.MustGetLogger("decorator")
+
+// NewDeterministicDecorator creates a decorator that adds to the chaincode
+// input a pseudo-random seed derived from the channel and the ID of the
+// transaction, so that it is identical on every endorsing peer.  Chaincode
+// reads it through the pkg/deterministic package.  The seed is public and
+// predictable, and therefore not a source of randomness.
+func NewDeterministicDecorator() decoration.Decorator {
+	return &deterministicDecorator{}
+}
+
+type deterministicDecorator struct {
+}
+
+// Decorate adds the deterministic seed to the chaincode input
+func (d *deterministicDecorator) Decorate(proposal *peer.Proposal, input *peer.ChaincodeInput) *peer.ChaincodeInput {
+	if proposal == nil {
+		return input
+	}
+
+	hdr, err := protoutil.UnmarshalHeader(proposal.Header)
+	if err != nil {
+		logger.Warningf("Could not decorate chaincode input: %s", err)
+		return input
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		logger.Warningf("Could not decorate chaincode input: %s", err)
+		return input
+	}
+	if input.Decorations == nil {
+		input.Decorations = map[string][]byte{}
+	}
+	input.Decorations[deterministic.SeedKey] = deterministic.DeriveSeed(chdr.ChannelId, chdr.TxId)
+	return input
+}
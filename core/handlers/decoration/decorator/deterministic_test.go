@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package decorator
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/pkg/deterministic"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type decoratedInput peer.ChaincodeInput
+
+func (d *decoratedInput) GetDecorations() map[string][]byte {
+	return d.Decorations
+}
+
+func TestDeterministicDecorator(t *testing.T) {
+	proposal := &peer.Proposal{
+		Header: protoutil.MarshalOrPanic(&common.Header{
+			ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+				ChannelId: "mychannel",
+				TxId:      "txid",
+				Timestamp: &timestamp.Timestamp{Seconds: 1500000000, Nanos: 42},
+			}),
+		}),
+	}
+
+	dec := NewDeterministicDecorator()
+	in := &peer.ChaincodeInput{Args: [][]byte{{1, 2, 3}}}
+	out := dec.Decorate(proposal, in)
+	assert.Equal(t, [][]byte{{1, 2, 3}}, out.Args)
+
+	seed, err := deterministic.Seed((*decoratedInput)(out))
+	assert.NoError(t, err)
+	assert.Equal(t, deterministic.DeriveSeed("mychannel", "txid"), seed)
+
+	// Decorating the same proposal on another peer yields the same seed
+	other := dec.Decorate(proposal, &peer.ChaincodeInput{Decorations: map[string][]byte{"other": []byte("value")}})
+	assert.Equal(t, out.Decorations[deterministic.SeedKey], other.Decorations[deterministic.SeedKey])
+	assert.Equal(t, []byte("value"), other.Decorations["other"])
+	assert.NotContains(t, out.Decorations, "fabric.deterministic.timestamp")
+}
+
+func TestDeterministicDecoratorInvalidProposal(t *testing.T) {
+	dec := NewDeterministicDecorator()
+
+	in := &peer.ChaincodeInput{Args: [][]byte{{1, 2, 3}}}
+	assert.Equal(t, in, dec.Decorate(nil, in))
+	assert.Equal(t, in, dec.Decorate(&peer.Proposal{Header: []byte("garbage")}, in))
+	assert.Nil(t, in.Decorations)
+}
@@ -44,6 +44,13 @@ func (r *HandlerLibrary) DefaultDecorator() decoration.Decorator {
 	return decorator.NewDecorator()
 }
 
+// DeterministicDecorator creates a decorator which
+// passes a deterministic, but public and predictable,
+// pseudo-random seed to the chaincode.
+func (r *HandlerLibrary) DeterministicDecorator() decoration.Decorator {
+	return decorator.NewDeterministicDecorator()
+}
+
 func (r *HandlerLibrary) DefaultEndorsement() endorsement.PluginFactory {
 	return &builtin.DefaultEndorsementFactory{}
 }
@@ -51,3 +58,11 @@ func (r *HandlerLibrary) DefaultEndorsement() endorsement.PluginFactory {
 func (r *HandlerLibrary) DefaultValidation() validation.PluginFactory {
 	return &DefaultValidationFactory{}
 }
+
+// DeterministicOutputValidation creates a validation plugin
+// which, on top of the default validation, rejects the
+// transactions whose endorsements do not agree on the output
+// of the chaincode.
+func (r *HandlerLibrary) DeterministicOutputValidation() validation.PluginFactory {
+	return &DeterministicOutputValidationFactory{}
+}
@@ -43,3 +43,9 @@ func TestLoadCompiledInvalid(t *testing.T) {
 	testReg := registry{}
 	testReg.loadCompiled("InvalidFactory", Auth)
 }
+
+func TestLoadCompiledDeterministicDecorator(t *testing.T) {
+	testReg := registry{}
+	testReg.loadCompiled("DeterministicDecorator", Decoration)
+	assert.Len(t, testReg.decorators, 1)
+}
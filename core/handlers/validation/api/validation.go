@@ -6,7 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package validation
 
-import "github.com/hyperledger/fabric-protos-go/common"
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
 
 // Argument defines the argument for validation
 type Argument interface {
@@ -49,3 +52,22 @@ type ExecutionFailureError struct {
 func (e *ExecutionFailureError) Error() string {
 	return e.Reason
 }
+
+// NonDeterministicOutputCode is the validation code of the transactions
+// whose endorsements do not agree on the output of the chaincode. It extends
+// the TxValidationCode enumeration, which leaves the values following
+// INVALID_CHAINCODE unassigned.
+const NonDeterministicOutputCode = peer.TxValidationCode(26)
+
+// NonDeterministicOutputError indicates that the validation
+// failed because the endorsements of the transaction do not
+// agree on the output of the chaincode
+type NonDeterministicOutputError struct {
+	Reason string
+}
+
+// Error conveys this is an error, and also contains
+// the reason for the error
+func (e *NonDeterministicOutputError) Error() string {
+	return e.Reason
+}
@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	. "github.com/hyperledger/fabric/core/handlers/validation/api/identities"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+type DeterministicOutputValidationFactory struct {
+}
+
+func (*DeterministicOutputValidationFactory) New() validation.Plugin {
+	return &DeterministicOutputValidation{}
+}
+
+// DeterministicOutputValidation cross-checks the endorsements of a transaction
+// on top of the default validation. Every endorser signs the proposal response
+// payload, which holds the read-write set it computed, hence an endorsement that
+// does not verify against the payload of the transaction was made over a
+// different read-write set. The default validation ignores such endorsements as
+// long as the remaining ones satisfy the endorsement policy, whereas this plugin
+// marks the transaction with the NonDeterministicOutputCode validation code and
+// logs the write set the endorsers disagree on.
+type DeterministicOutputValidation struct {
+	DefaultValidation
+	IdentityDeserializer IdentityDeserializer
+}
+
+func (v *DeterministicOutputValidation) Validate(block *common.Block, namespace string, txPosition int, actionPosition int, contextData ...validation.ContextDatum) error {
+	if err := v.DefaultValidation.Validate(block, namespace, txPosition, actionPosition, contextData...); err != nil {
+		return err
+	}
+
+	action, err := transactionAction(block, txPosition, actionPosition)
+	if err != nil {
+		return err
+	}
+	ccPayload, ccAction, err := protoutil.GetPayloads(action)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting the chaincode action")
+	}
+
+	prp := ccPayload.Action.ProposalResponsePayload
+	var mismatched []string
+	for _, endorsement := range ccPayload.Action.Endorsements {
+		endorser, err := v.IdentityDeserializer.DeserializeIdentity(endorsement.Endorser)
+		if err != nil {
+			// left to the endorsement policy evaluation
+			logger.Debugf("block %d, tx %d: skipping endorsement of unknown identity: %v", block.Header.Number, txPosition, err)
+			continue
+		}
+		if err := endorser.Verify(append(prp, endorsement.Endorser...), endorsement.Signature); err != nil {
+			id := endorser.GetIdentityIdentifier()
+			mismatched = append(mismatched, fmt.Sprintf("%s(%s)", id.Mspid, id.Id))
+		}
+	}
+	if len(mismatched) == 0 {
+		return nil
+	}
+
+	logger.Warningf("block %d, tx %d, namespace %s: the output of endorsers [%s] differs from the write set of the transaction:\n%s",
+		block.Header.Number, txPosition, namespace, strings.Join(mismatched, ", "), describeWrites(ccAction.Results, namespace))
+	return &validation.NonDeterministicOutputError{
+		Reason: fmt.Sprintf("endorsements of [%s] do not match the output of the transaction", strings.Join(mismatched, ", ")),
+	}
+}
+
+func (v *DeterministicOutputValidation) Init(dependencies ...validation.Dependency) error {
+	for _, dep := range dependencies {
+		if deserializer, isIdentityDeserializer := dep.(IdentityDeserializer); isIdentityDeserializer {
+			v.IdentityDeserializer = deserializer
+		}
+	}
+	return v.DefaultValidation.Init(dependencies...)
+}
+
+func transactionAction(block *common.Block, txPosition int, actionPosition int) (*peer.TransactionAction, error) {
+	env, err := protoutil.GetEnvelopeFromBlock(block.Data.Data[txPosition])
+	if err != nil {
+		return nil, err
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if actionPosition >= len(tx.Actions) {
+		return nil, errors.Errorf("transaction has only %d actions, but requested action at position %d", len(tx.Actions), actionPosition)
+	}
+	return tx.Actions[actionPosition], nil
+}
+
+// describeWrites lists the writes of the given namespace in the read-write set,
+// one per line.
+func describeWrites(results []byte, namespace string) string {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(results); err != nil {
+		return fmt.Sprintf("unreadable read-write set: %v", err)
+	}
+	var lines []string
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.NameSpace != namespace || ns.KvRwSet == nil {
+			continue
+		}
+		for _, write := range ns.KvRwSet.Writes {
+			if write.IsDelete {
+				lines = append(lines, fmt.Sprintf("  %s: deleted", write.Key))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("  %s: %x", write.Key, write.Value))
+		}
+	}
+	if len(lines) == 0 {
+		return "  no writes"
+	}
+	return strings.Join(lines, "\n")
+}
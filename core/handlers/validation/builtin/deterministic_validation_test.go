@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	commonerrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/core/committer/txvalidator/plugin"
+	. "github.com/hyperledger/fabric/core/handlers/validation/api"
+	. "github.com/hyperledger/fabric/core/handlers/validation/api/identities"
+	vmocks "github.com/hyperledger/fabric/core/handlers/validation/builtin/mocks"
+	"github.com/hyperledger/fabric/core/handlers/validation/builtin/v12/mocks"
+	v20mocks "github.com/hyperledger/fabric/core/handlers/validation/builtin/v20/mocks"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// endorser verifies the signatures made of the signed message followed by "-signed"
+type endorser struct {
+	Identity
+	name string
+}
+
+func (e *endorser) Verify(msg []byte, sig []byte) error {
+	if !bytes.Equal(sig, append(msg, []byte("-signed")...)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func (e *endorser) GetIdentityIdentifier() *IdentityIdentifier {
+	return &IdentityIdentifier{Mspid: "Org1MSP", Id: e.name}
+}
+
+func endorserTx(prp []byte, endorsements ...*peer.Endorsement) []byte {
+	ccPayload := &peer.ChaincodeActionPayload{
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: prp,
+			Endorsements:            endorsements,
+		},
+	}
+	tx := &peer.Transaction{
+		Actions: []*peer.TransactionAction{{Payload: protoutil.MarshalOrPanic(ccPayload)}},
+	}
+	env := &common.Envelope{
+		Payload: protoutil.MarshalOrPanic(&common.Payload{Data: protoutil.MarshalOrPanic(tx)}),
+	}
+	return protoutil.MarshalOrPanic(env)
+}
+
+func proposalResponsePayload(t *testing.T, value string) []byte {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("mycc", "key", []byte(value))
+	simRes, err := rwsetBuilder.GetTxSimulationResults()
+	require.NoError(t, err)
+	results, err := simRes.GetPubSimulationBytes()
+	require.NoError(t, err)
+	return protoutil.MarshalOrPanic(&peer.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&peer.ChaincodeAction{Results: results}),
+	})
+}
+
+func endorse(prp []byte, name string) *peer.Endorsement {
+	msg := append(append([]byte{}, prp...), []byte(name)...)
+	return &peer.Endorsement{
+		Endorser:  []byte(name),
+		Signature: append(msg, []byte("-signed")...),
+	}
+}
+
+func TestDeterministicOutputValidationInit(t *testing.T) {
+	v := (&DeterministicOutputValidationFactory{}).New()
+
+	identityDeserializer := &mocks.IdentityDeserializer{}
+	deps := []Dependency{identityDeserializer, &mocks.Capabilities{}, &mocks.StateFetcher{}, &mocks.PolicyEvaluator{}, &v20mocks.CollectionResources{}}
+	assert.NoError(t, v.Init(deps...))
+	assert.Equal(t, identityDeserializer, v.(*DeterministicOutputValidation).IdentityDeserializer)
+
+	assert.EqualError(t, v.Init(deps[1:]...), "identityDeserializer not passed in init")
+}
+
+func TestDeterministicOutputValidation(t *testing.T) {
+	validator := &vmocks.TransactionValidator{}
+	capabilities := &mocks.Capabilities{}
+	capabilities.On("V2_0Validation").Return(true)
+	identityDeserializer := &mocks.IdentityDeserializer{}
+	identityDeserializer.On("DeserializeIdentity", mock.Anything).Return(
+		func(serializedIdentity []byte) Identity {
+			return &endorser{name: string(serializedIdentity)}
+		},
+		func(serializedIdentity []byte) error {
+			if string(serializedIdentity) == "unknown" {
+				return errors.New("unknown identity")
+			}
+			return nil
+		},
+	)
+	v := &DeterministicOutputValidation{
+		DefaultValidation: DefaultValidation{
+			Capabilities:    capabilities,
+			TxValidatorV2_0: validator,
+		},
+		IdentityDeserializer: identityDeserializer,
+	}
+
+	prp := proposalResponsePayload(t, "value")
+	otherPrp := proposalResponsePayload(t, "other value")
+	block := &common.Block{
+		Header: &common.BlockHeader{Number: 5},
+		Data: &common.BlockData{
+			Data: [][]byte{
+				endorserTx(prp, endorse(prp, "peer0"), endorse(prp, "peer1"), endorse(otherPrp, "unknown")),
+				endorserTx(prp, endorse(prp, "peer0"), endorse(otherPrp, "peer1"), endorse(otherPrp, "peer2")),
+			},
+		},
+	}
+
+	t.Run("matching endorsements", func(t *testing.T) {
+		validator.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		assert.NoError(t, v.Validate(block, "mycc", 0, 0, plugin.SerializedPolicy("policy")))
+	})
+
+	t.Run("mismatching endorsements", func(t *testing.T) {
+		validator.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		err := v.Validate(block, "mycc", 1, 0, plugin.SerializedPolicy("policy"))
+		assert.Equal(t, &NonDeterministicOutputError{
+			Reason: "endorsements of [Org1MSP(peer1), Org1MSP(peer2)] do not match the output of the transaction",
+		}, err)
+	})
+
+	t.Run("default validation failure", func(t *testing.T) {
+		validator.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&commonerrors.VSCCEndorsementPolicyError{Err: errors.New("foo")}).Once()
+		err := v.Validate(block, "mycc", 1, 0, plugin.SerializedPolicy("policy"))
+		assert.EqualError(t, err, "foo")
+	})
+
+	t.Run("missing action", func(t *testing.T) {
+		validator.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		err := v.Validate(block, "mycc", 0, 1, plugin.SerializedPolicy("policy"))
+		assert.EqualError(t, err, "transaction has only 1 actions, but requested action at position 1")
+	})
+}
+
+func TestDescribeWrites(t *testing.T) {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("mycc", "a", []byte{0xca, 0xfe})
+	rwsetBuilder.AddToWriteSet("mycc", "b", nil)
+	rwsetBuilder.AddToWriteSet("othercc", "c", []byte("c"))
+	simRes, err := rwsetBuilder.GetTxSimulationResults()
+	require.NoError(t, err)
+	results, err := simRes.GetPubSimulationBytes()
+	require.NoError(t, err)
+
+	assert.Equal(t, "  a: cafe\n  b: deleted", describeWrites(results, "mycc"))
+	assert.Equal(t, "  no writes", describeWrites(results, "lscc"))
+	assert.Contains(t, describeWrites([]byte("garbage"), "mycc"), "unreadable read-write set")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitlistener
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("commitlistener")
+
+// Registry holds the listeners that are notified of the blocks committed to
+// the ledgers of the peer. It allows integrations, such as off-chain indexers,
+// to be compiled into a custom peer build by registering a listener from an
+// init function, without patching the ledger.
+//
+// Listeners are registered before the ledgers are created. The registry is
+// sealed when the listeners are handed to the ledger manager, after which no
+// more listeners can be registered. The listeners are notified in the order
+// of their registration; see ledger.CommitListener for the guarantees.
+type Registry struct {
+	mutex     sync.Mutex
+	listeners []ledger.CommitListener
+	sealed    bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers the given listener, whose name must be unique
+func (r *Registry) Register(listener ledger.CommitListener) error {
+	if listener == nil {
+		return errors.New("nil commit listener")
+	}
+	name := listener.Name()
+	if name == "" {
+		return errors.New("commit listener name cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.sealed {
+		return errors.Errorf("cannot register commit listener [%s], the commit listeners are already in use", name)
+	}
+	for _, l := range r.listeners {
+		if l.Name() == name {
+			return errors.Errorf("commit listener [%s] is already registered", name)
+		}
+	}
+	r.listeners = append(r.listeners, listener)
+	logger.Infof("Registered commit listener [%s]", name)
+	return nil
+}
+
+// Listeners seals the registry and returns the registered listeners in the order of their registration
+func (r *Registry) Listeners() []ledger.CommitListener {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.sealed = true
+	listeners := make([]ledger.CommitListener, len(r.listeners))
+	copy(listeners, r.listeners)
+	return listeners
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register registers the given listener with the registry of the peer
+func Register(listener ledger.CommitListener) error {
+	return defaultRegistry.Register(listener)
+}
+
+// Listeners seals the registry of the peer and returns the registered listeners
+func Listeners() []ledger.CommitListener {
+	return defaultRegistry.Listeners()
+}
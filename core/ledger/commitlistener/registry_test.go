@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitlistener
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	indexer := &mock.CommitListener{}
+	indexer.NameReturns("indexer")
+	auditor := &mock.CommitListener{}
+	auditor.NameReturns("auditor")
+	unnamed := &mock.CommitListener{}
+
+	assert.EqualError(t, r.Register(nil), "nil commit listener")
+	assert.EqualError(t, r.Register(unnamed), "commit listener name cannot be empty")
+
+	assert.NoError(t, r.Register(indexer))
+	assert.NoError(t, r.Register(auditor))
+	assert.EqualError(t, r.Register(indexer), "commit listener [indexer] is already registered")
+
+	listeners := r.Listeners()
+	assert.Equal(t, []ledger.CommitListener{indexer, auditor}, listeners)
+
+	// The returned slice may be modified by the caller without affecting the registry
+	listeners[0] = auditor
+	assert.Equal(t, []ledger.CommitListener{indexer, auditor}, r.Listeners())
+
+	// Once the listeners are in use, no more listeners can be registered
+	other := &mock.CommitListener{}
+	other.NameReturns("other")
+	assert.EqualError(t, r.Register(other), "cannot register commit listener [other], the commit listeners are already in use")
+	assert.Len(t, r.Listeners(), 2)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/snapshot"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/pkg/errors"
 )
@@ -285,6 +286,12 @@ func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHash
 	}, nil
 }
 
+// BackupSnapshot returns a point-in-time view of the config history of the ledger for a backup.
+// The caller is expected to ensure that no block is committed while the snapshot is taken.
+func (r *Retriever) BackupSnapshot() (*leveldbhelper.Snapshot, error) {
+	return r.dbHandle.GetSnapshot()
+}
+
 func (r *Retriever) getImplicitCollection(chaincodeName string) ([]*peer.StaticCollectionConfig, error) {
 	qe, err := r.ledgerInfoRetriever.NewQueryExecutor()
 	if err != nil {
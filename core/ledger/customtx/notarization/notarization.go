@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package notarization is a sample processor of custom transactions which
+// notarize documents: each transaction records the hash of a document under
+// its ID, once and for all, without executing any chaincode.
+package notarization
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TxType is the transaction type of the notarization transactions
+	TxType = customtx.MinTxType
+
+	// Namespace is the namespace of the state holding the notarization records.
+	// It starts with an underscore so that it cannot be the name of a chaincode.
+	Namespace = "_notarization"
+
+	// ACLResource is the ACL resource whose policy the creators of the
+	// notarization transactions must satisfy
+	ACLResource = resources.Notarization_Notarize
+)
+
+// Record is the notarization of a document. It is the data of the payload
+// of a notarization transaction, and the value of the state under the ID of
+// the document once committed. Its wire format is given by its field tags.
+type Record struct {
+	// DocumentId is the ID of the document
+	DocumentId string `protobuf:"bytes,1,opt,name=document_id,json=documentId,proto3"`
+	// Hash is the hash of the document
+	Hash []byte `protobuf:"bytes,2,opt,name=hash,proto3"`
+	// Creator is the serialized identity which notarized the document, set at commit time
+	Creator []byte `protobuf:"bytes,3,opt,name=creator,proto3"`
+	// TxId is the ID of the transaction which notarized the document, set at commit time
+	TxId string `protobuf:"bytes,4,opt,name=tx_id,json=txId,proto3"`
+}
+
+// Reset resets
+func (r *Record) Reset() { *r = Record{} }
+
+// String converts to string
+func (r *Record) String() string { return proto.CompactTextString(r) }
+
+// ProtoMessage just exists to make proto happy
+func (*Record) ProtoMessage() {}
+
+// Processor processes the notarization transactions
+type Processor struct{}
+
+// GenerateSimulationResults implements function in the interface 'github.com/hyperledger/fabric/core/ledger/CustomTxProcessor'.
+// A notarization transaction is invalid if its record is malformed or if the document is already notarized.
+func (p *Processor) GenerateSimulationResults(txEnv *common.Envelope, simulator ledger.TxSimulator, initializingLedger bool) error {
+	payload, err := protoutil.UnmarshalPayload(txEnv.Payload)
+	if err != nil {
+		return &ledger.InvalidTxError{Msg: err.Error()}
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return &ledger.InvalidTxError{Msg: err.Error()}
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return &ledger.InvalidTxError{Msg: err.Error()}
+	}
+
+	record := &Record{}
+	if err := proto.Unmarshal(payload.Data, record); err != nil {
+		return &ledger.InvalidTxError{Msg: "malformed notarization record: " + err.Error()}
+	}
+	if record.DocumentId == "" || len(record.Hash) == 0 {
+		return &ledger.InvalidTxError{Msg: "notarization record must have a document ID and a hash"}
+	}
+
+	// When initializing the ledger, only valid transactions are processed
+	// and the record may already be in the state being rebuilt
+	if !initializingLedger {
+		existing, err := simulator.GetState(Namespace, record.DocumentId)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return &ledger.InvalidTxError{Msg: "document " + record.DocumentId + " is already notarized"}
+		}
+	}
+
+	record.Creator = shdr.Creator
+	record.TxId = chdr.TxId
+	value, err := proto.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notarization record")
+	}
+	return simulator.SetState(Namespace, record.DocumentId, value)
+}
+
+// CreateSignedTx creates a transaction which notarizes the given document hash.
+// It returns the transaction and its ID.
+func CreateSignedTx(channelID, documentID string, hash []byte, signer protoutil.Signer) (*common.Envelope, string, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to serialize signer")
+	}
+	nonce, err := protoutil.CreateNonce()
+	if err != nil {
+		return nil, "", err
+	}
+	txID := protoutil.ComputeTxID(nonce, creator)
+
+	chdr := protoutil.MakeChannelHeader(TxType, 0, channelID, 0)
+	chdr.TxId = txID
+	data, err := proto.Marshal(&Record{DocumentId: documentID, Hash: hash})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to marshal notarization record")
+	}
+	payloadBytes, err := proto.Marshal(&common.Payload{
+		Header: protoutil.MakePayloadHeader(chdr, protoutil.MakeSignatureHeader(creator, nonce)),
+		Data:   data,
+	})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to marshal payload")
+	}
+	signature, err := signer.Sign(payloadBytes)
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to sign transaction")
+	}
+	return &common.Envelope{Payload: payloadBytes, Signature: signature}, txID, nil
+}
+
+// GetRecord returns the notarization record of the given document, or nil if
+// the document isn't notarized
+func GetRecord(queryExecutor ledger.SimpleQueryExecutor, documentID string) (*Record, error) {
+	value, err := queryExecutor.GetState(Namespace, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	record := &Record{}
+	if err := proto.Unmarshal(value, record); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal notarization record of document %s", documentID)
+	}
+	return record, nil
+}
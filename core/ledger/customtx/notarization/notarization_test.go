@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package notarization
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSigner struct{}
+
+func (testSigner) Sign(msg []byte) ([]byte, error) { return []byte("signature"), nil }
+
+func (testSigner) Serialize() ([]byte, error) { return []byte("creator"), nil }
+
+func TestCreateSignedTx(t *testing.T) {
+	env, txID, err := CreateSignedTx("testchannelid", "doc1", []byte("hash"), testSigner{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("signature"), env.Signature)
+
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, int32(TxType), chdr.Type)
+	assert.Equal(t, "testchannelid", chdr.ChannelId)
+	assert.Equal(t, txID, chdr.TxId)
+	shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	require.NoError(t, err)
+	assert.NoError(t, protoutil.CheckTxID(txID, shdr.Nonce, shdr.Creator))
+
+	record := &Record{}
+	require.NoError(t, proto.Unmarshal(payload.Data, record))
+	assert.True(t, proto.Equal(&Record{DocumentId: "doc1", Hash: []byte("hash")}, record))
+}
+
+func TestProcessor(t *testing.T) {
+	env, txID, err := CreateSignedTx("testchannelid", "doc1", []byte("hash"), testSigner{})
+	require.NoError(t, err)
+	p := &Processor{}
+
+	simulator := &mock.TxSimulator{}
+	require.NoError(t, p.GenerateSimulationResults(env, simulator, false))
+	ns, key := simulator.GetStateArgsForCall(0)
+	assert.Equal(t, Namespace, ns)
+	assert.Equal(t, "doc1", key)
+	require.Equal(t, 1, simulator.SetStateCallCount())
+	ns, key, value := simulator.SetStateArgsForCall(0)
+	assert.Equal(t, Namespace, ns)
+	assert.Equal(t, "doc1", key)
+	record := &Record{}
+	require.NoError(t, proto.Unmarshal(value, record))
+	assert.True(t, proto.Equal(&Record{DocumentId: "doc1", Hash: []byte("hash"), Creator: []byte("creator"), TxId: txID}, record))
+
+	// A document can only be notarized once
+	simulator = &mock.TxSimulator{}
+	simulator.GetStateReturns(value, nil)
+	err = p.GenerateSimulationResults(env, simulator, false)
+	assert.Equal(t, &ledger.InvalidTxError{Msg: "document doc1 is already notarized"}, err)
+	assert.Equal(t, 0, simulator.SetStateCallCount())
+
+	// When initializing the ledger, the state may already hold the record
+	simulator = &mock.TxSimulator{}
+	simulator.GetStateReturns(value, nil)
+	require.NoError(t, p.GenerateSimulationResults(env, simulator, true))
+	assert.Equal(t, 0, simulator.GetStateCallCount())
+	assert.Equal(t, 1, simulator.SetStateCallCount())
+}
+
+func TestProcessorInvalidRecord(t *testing.T) {
+	p := &Processor{}
+
+	env, _, err := CreateSignedTx("testchannelid", "", []byte("hash"), testSigner{})
+	require.NoError(t, err)
+	err = p.GenerateSimulationResults(env, &mock.TxSimulator{}, false)
+	assert.Equal(t, &ledger.InvalidTxError{Msg: "notarization record must have a document ID and a hash"}, err)
+
+	payload := protoutil.UnmarshalPayloadOrPanic(env.Payload)
+	payload.Data = []byte("garbage")
+	env.Payload = protoutil.MarshalOrPanic(payload)
+	err = p.GenerateSimulationResults(env, &mock.TxSimulator{}, false)
+	assert.IsType(t, &ledger.InvalidTxError{}, err)
+	assert.Contains(t, err.Error(), "malformed notarization record")
+
+	err = p.GenerateSimulationResults(&common.Envelope{Payload: []byte("garbage")}, &mock.TxSimulator{}, false)
+	assert.IsType(t, &ledger.InvalidTxError{}, err)
+}
+
+func TestGetRecord(t *testing.T) {
+	queryExecutor := &mock.QueryExecutor{}
+	record, err := GetRecord(queryExecutor, "doc1")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	value, err := proto.Marshal(&Record{DocumentId: "doc1", Hash: []byte("hash")})
+	require.NoError(t, err)
+	queryExecutor.GetStateReturns(value, nil)
+	record, err = GetRecord(queryExecutor, "doc1")
+	assert.NoError(t, err)
+	assert.Equal(t, "doc1", record.DocumentId)
+	assert.Equal(t, []byte("hash"), record.Hash)
+
+	queryExecutor.GetStateReturns([]byte("garbage"), nil)
+	_, err = GetRecord(queryExecutor, "doc1")
+	assert.Error(t, err)
+}
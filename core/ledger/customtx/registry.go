@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package customtx
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("customtx")
+
+// MinTxType is the lowest custom transaction type. The lower types are
+// reserved for the transaction types defined by Fabric.
+const MinTxType common.HeaderType = 100
+
+// Registry holds the processors of the custom transaction types. A custom
+// transaction is signed by its creator and ordered like any other transaction,
+// but it isn't endorsed: at commit time, its creator must satisfy the policy of
+// the ACL resource of its type, and its processor translates it into the writes
+// to apply to the state, without executing any chaincode.
+//
+// Processors are registered before the ledgers are created. The registry is
+// sealed when the processors are handed to the ledger manager, after which
+// no more processors can be registered. The custom transactions are only valid
+// on the channels with the custom transactions capability, which must not be
+// enabled before all the peers of the channel register the same processors,
+// otherwise their states diverge.
+type Registry struct {
+	mutex        sync.RWMutex
+	processors   map[common.HeaderType]ledger.CustomTxProcessor
+	aclResources map[common.HeaderType]string
+	sealed       bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		processors:   map[common.HeaderType]ledger.CustomTxProcessor{},
+		aclResources: map[common.HeaderType]string{},
+	}
+}
+
+// Register registers the processor of the given custom transaction type, which
+// must not be lower than MinTxType, and the ACL resource whose policy the
+// creators of the transactions of the type must satisfy.
+func (r *Registry) Register(txType common.HeaderType, aclResource string, processor ledger.CustomTxProcessor) error {
+	if txType < MinTxType {
+		return errors.Errorf("transaction type %d is reserved, custom transaction types start at %d", txType, MinTxType)
+	}
+	if processor == nil {
+		return errors.Errorf("nil processor for transaction type %d", txType)
+	}
+	if aclResource == "" {
+		return errors.Errorf("no ACL resource for transaction type %d", txType)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.sealed {
+		return errors.Errorf("cannot register transaction type %d, the custom transaction processors are already in use", txType)
+	}
+	if _, exists := r.processors[txType]; exists {
+		return errors.Errorf("transaction type %d is already registered", txType)
+	}
+	r.processors[txType] = processor
+	r.aclResources[txType] = aclResource
+	logger.Infof("Registered processor for custom transaction type %d", txType)
+	return nil
+}
+
+// ACLResource returns the ACL resource of the given transaction type, or the
+// empty string if no processor is registered for the type
+func (r *Registry) ACLResource(txType common.HeaderType) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.aclResources[txType]
+}
+
+// IsRegistered returns whether a processor is registered for the given transaction type
+func (r *Registry) IsRegistered(txType common.HeaderType) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	_, exists := r.processors[txType]
+	return exists
+}
+
+// Processors seals the registry and returns the registered processors
+func (r *Registry) Processors() map[common.HeaderType]ledger.CustomTxProcessor {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.sealed = true
+	processors := make(map[common.HeaderType]ledger.CustomTxProcessor, len(r.processors))
+	for txType, processor := range r.processors {
+		processors[txType] = processor
+	}
+	return processors
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register registers the processor of the given custom transaction type, and
+// the ACL resource of the type, with the registry of the peer
+func Register(txType common.HeaderType, aclResource string, processor ledger.CustomTxProcessor) error {
+	return defaultRegistry.Register(txType, aclResource, processor)
+}
+
+// IsRegistered returns whether a processor of the given transaction type is
+// registered with the registry of the peer
+func IsRegistered(txType common.HeaderType) bool {
+	return defaultRegistry.IsRegistered(txType)
+}
+
+// ACLResource returns the ACL resource of the given transaction type with the
+// registry of the peer
+func ACLResource(txType common.HeaderType) string {
+	return defaultRegistry.ACLResource(txType)
+}
+
+// Processors seals the registry of the peer and returns the registered processors
+func Processors() map[common.HeaderType]ledger.CustomTxProcessor {
+	return defaultRegistry.Processors()
+}
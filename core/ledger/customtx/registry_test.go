@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package customtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	processor := &mock.CustomTxProcessor{}
+
+	assert.EqualError(t, r.Register(common.HeaderType_ENDORSER_TRANSACTION, "acl", processor), "transaction type 3 is reserved, custom transaction types start at 100")
+	assert.EqualError(t, r.Register(MinTxType-1, "acl", processor), "transaction type 99 is reserved, custom transaction types start at 100")
+	assert.EqualError(t, r.Register(MinTxType, "acl", nil), "nil processor for transaction type 100")
+	assert.EqualError(t, r.Register(MinTxType, "", processor), "no ACL resource for transaction type 100")
+	assert.False(t, r.IsRegistered(MinTxType))
+	assert.Empty(t, r.ACLResource(MinTxType))
+
+	assert.NoError(t, r.Register(MinTxType, "acl", processor))
+	assert.True(t, r.IsRegistered(MinTxType))
+	assert.Equal(t, "acl", r.ACLResource(MinTxType))
+	assert.EqualError(t, r.Register(MinTxType, "acl", processor), "transaction type 100 is already registered")
+
+	processors := r.Processors()
+	assert.Equal(t, map[common.HeaderType]ledger.CustomTxProcessor{MinTxType: processor}, processors)
+
+	// The returned map may be extended by the caller without affecting the registry
+	processors[common.HeaderType_CONFIG] = processor
+	assert.False(t, r.IsRegistered(common.HeaderType_CONFIG))
+
+	// Once the processors are in use, no more processors can be registered
+	assert.EqualError(t, r.Register(MinTxType+1, "acl", processor), "cannot register transaction type 101, the custom transaction processors are already in use")
+	assert.False(t, r.IsRegistered(MinTxType+1))
+	assert.True(t, r.IsRegistered(MinTxType))
+}
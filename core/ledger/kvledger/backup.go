@@ -0,0 +1,334 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/pkg/errors"
+)
+
+const backupManifestFileName = "_backup_manifest.json"
+
+var backupHashOpts = &bccsp.SM3Opts{}
+
+// BackupManifest describes a backup of a ledger. The backup dir has the layout of the
+// ledgersData dir of a peer which holds this ledger alone, and it can be restored as such.
+type BackupManifest struct {
+	LedgerID           string `json:"ledger_id"`
+	Height             uint64 `json:"height"`
+	LastBlockHashInHex string `json:"last_block_hash"`
+	StateDatabase      string `json:"state_database"`
+	// CouchDBDatabases are the CouchDB state databases replicated to the
+	// CouchDB replication target, if any
+	CouchDBDatabases []string  `json:"couchdb_databases,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	// FilesAndHashes are the SM3 hashes of the files of the backup, keyed by
+	// their path relative to the backup dir
+	FilesAndHashes map[string]string `json:"backup_files_raw_hashes"`
+	// Dir is the dir holding the backup
+	Dir string `json:"-"`
+}
+
+// stateLevelDBBackup is implemented by the goleveldb state database
+type stateLevelDBBackup interface {
+	BackupSnapshot() (*leveldbhelper.Snapshot, error)
+}
+
+// stateCouchDBReplicator is implemented by the CouchDB state database
+type stateCouchDBReplicator interface {
+	Replicate(targetURL string) ([]string, error)
+}
+
+// storeSnapshot is a point-in-time view of a leveldb store of the ledger
+// along with the path of the store in a backup dir
+type storeSnapshot struct {
+	name     string
+	snapshot *leveldbhelper.Snapshot
+	path     func(backupDir string) string
+}
+
+// backupSnapshots are the snapshots of the stores of a ledger taken for a backup
+type backupSnapshots struct {
+	bcInfo     *common.BlockchainInfo
+	blockStore *blkstorage.BackupSnapshot
+	stores     []*storeSnapshot
+}
+
+func (s *backupSnapshots) release() {
+	if s.blockStore != nil {
+		s.blockStore.Release()
+	}
+	for _, store := range s.stores {
+		store.snapshot.Release()
+	}
+}
+
+// Backup takes a backup of the ledger while the peer is running. The commits of blocks
+// to the ledger only pause while a point-in-time snapshot of each store is taken, so
+// that the backup is consistent at a block boundary, and the snapshots are then copied
+// to the backup while the blocks are committed.
+//
+// A CouchDB state database cannot be snapshotted. Instead, its databases are replicated
+// to the configured CouchDB replication target before the snapshots are taken, starting
+// with the database which holds the savepoint. The replicated state is hence at least as
+// recent as its savepoint and not more recent than the snapshot of the block store, and
+// restoring the backup brings it to the height of the backup by committing the blocks
+// after the savepoint again, like after a crash.
+//
+// Once the snapshots are copied, the backup files are hashed and a manifest is written
+// along with them.
+func (l *kvLedger) Backup() (*BackupManifest, error) {
+	if l.backupsConfig == nil || l.backupsConfig.RootDir == "" {
+		return nil, errors.New("no root dir is configured for the backups")
+	}
+	backupsRootDir := l.backupsConfig.RootDir
+	if !filepath.IsAbs(backupsRootDir) {
+		return nil, errors.Errorf("invalid path: %s. The path for the backups dir is expected to be an absolute path", backupsRootDir)
+	}
+	if err := os.MkdirAll(InProgressBackupsPath(backupsRootDir), 0755); err != nil {
+		return nil, errors.Wrapf(err, "error while creating dir [%s]", InProgressBackupsPath(backupsRootDir))
+	}
+
+	manifest := &BackupManifest{
+		LedgerID:  l.ledgerID,
+		CreatedAt: time.Now().UTC(),
+	}
+	switch vdb := l.stateDB.VersionedDB.(type) {
+	case stateLevelDBBackup:
+		manifest.StateDatabase = "goleveldb"
+	case stateCouchDBReplicator:
+		manifest.StateDatabase = "CouchDB"
+		if l.backupsConfig.CouchDBReplicationTarget == "" {
+			logger.Warnf("No CouchDB replication target is configured, the state of ledger [%s] will be rebuilt from the blocks when the backup is restored", l.ledgerID)
+			break
+		}
+		dbNames, err := vdb.Replicate(l.backupsConfig.CouchDBReplicationTarget)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error while replicating CouchDB state databases")
+		}
+		manifest.CouchDBDatabases = dbNames
+	default:
+		return nil, errors.Errorf("backup is not supported by the state database of ledger [%s]", l.ledgerID)
+	}
+
+	snapshots, err := l.snapshotStores()
+	if err != nil {
+		return nil, err
+	}
+	defer snapshots.release()
+	manifest.Height = snapshots.bcInfo.Height
+	manifest.LastBlockHashInHex = hex.EncodeToString(snapshots.bcInfo.CurrentBlockHash)
+
+	backupTempDir, err := l.copySnapshots(snapshots)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(backupTempDir)
+
+	if manifest.FilesAndHashes, err = l.hashBackupFiles(backupTempDir); err != nil {
+		return nil, err
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", jsonFileIndent)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while marshalling backup manifest to JSON")
+	}
+	if err := createAndSyncFile(filepath.Join(backupTempDir, backupManifestFileName), manifestBytes); err != nil {
+		return nil, err
+	}
+	if err := syncDir(backupTempDir); err != nil {
+		return nil, err
+	}
+
+	manifest.Dir = BackupDirForLedgerHeight(backupsRootDir, l.ledgerID, manifest.Height)
+	if err := os.MkdirAll(filepath.Dir(manifest.Dir), 0755); err != nil {
+		return nil, errors.Wrapf(err, "error while creating final dir for backup:%s", filepath.Dir(manifest.Dir))
+	}
+	if err := os.Rename(backupTempDir, manifest.Dir); err != nil {
+		return nil, errors.Wrapf(err, "error while renaming dir [%s] to [%s]", backupTempDir, manifest.Dir)
+	}
+	if err := syncParentDir(manifest.Dir); err != nil {
+		return nil, err
+	}
+	logger.Infof("Backup of ledger [%s] at height [%d] completed at [%s]", l.ledgerID, manifest.Height, manifest.Dir)
+	return manifest, nil
+}
+
+// snapshotStores takes the snapshots of the stores of the ledger while holding the lock
+// which pauses the commits of blocks. The private data of old blocks may be committed
+// meanwhile, in which case it will be applied to the state again when the backup is
+// restored, like after a crash.
+func (l *kvLedger) snapshotStores() (snapshots *backupSnapshots, e error) {
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if bcInfo.Height == 0 {
+		return nil, errors.Errorf("ledger [%s] has no blocks to back up", l.ledgerID)
+	}
+	backupDir := BackupDirForLedgerHeight(l.backupsConfig.RootDir, l.ledgerID, bcInfo.Height)
+	if _, err := os.Stat(backupDir); err == nil {
+		return nil, errors.Errorf("a backup of ledger [%s] at height [%d] already exists at [%s]", l.ledgerID, bcInfo.Height, backupDir)
+	}
+
+	snapshots = &backupSnapshots{bcInfo: bcInfo}
+	defer func() {
+		if e != nil {
+			snapshots.release()
+		}
+	}()
+	if snapshots.blockStore, err = l.blockStore.BackupSnapshot(); err != nil {
+		return nil, errors.WithMessage(err, "error while taking snapshot of block store")
+	}
+	addSnapshot := func(name string, path func(string) string, snapshot *leveldbhelper.Snapshot, err error) error {
+		if err != nil {
+			return errors.WithMessagef(err, "error while taking snapshot of %s", name)
+		}
+		snapshots.stores = append(snapshots.stores, &storeSnapshot{name: name, snapshot: snapshot, path: path})
+		return nil
+	}
+
+	snapshot, err := l.pvtdataStore.BackupSnapshot()
+	if err := addSnapshot("private data store", PvtDataStorePath, snapshot, err); err != nil {
+		return nil, err
+	}
+	if l.historyDB != nil {
+		snapshot, err := l.historyDB.BackupSnapshot()
+		if err := addSnapshot("history database", HistoryDBPath, snapshot, err); err != nil {
+			return nil, err
+		}
+	}
+	snapshot, err = l.configHistoryRetriever.BackupSnapshot()
+	if err := addSnapshot("config history database", ConfigHistoryDBPath, snapshot, err); err != nil {
+		return nil, err
+	}
+	for _, cat := range []bookkeeping.Category{
+		bookkeeping.PvtdataExpiry,
+		bookkeeping.MetadataPresenceIndicator,
+		bookkeeping.RebuildProgress,
+		bookkeeping.CommitJournal,
+	} {
+		snapshot, err := l.bookkeeperProvider.GetDBHandle(l.ledgerID, cat).GetSnapshot()
+		if err := addSnapshot("bookkeeping database", BookkeeperDBPath, snapshot, err); err != nil {
+			return nil, err
+		}
+	}
+	if vdb, ok := l.stateDB.VersionedDB.(stateLevelDBBackup); ok {
+		snapshot, err := vdb.BackupSnapshot()
+		if err := addSnapshot("state database", StateDBPath, snapshot, err); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+// copySnapshots copies the snapshots of the stores of the ledger to a new temp dir,
+// while the commits of blocks go on. The files are copied rather than hard linked,
+// so that the backup does not share the files of the live ledger.
+func (l *kvLedger) copySnapshots(snapshots *backupSnapshots) (backupTempDir string, e error) {
+	height := snapshots.bcInfo.Height
+	backupTempDir, err := ioutil.TempDir(
+		InProgressBackupsPath(l.backupsConfig.RootDir),
+		fmt.Sprintf("%s-%d-", l.ledgerID, height),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "error while creating temp dir for backup of ledger [%s]", l.ledgerID)
+	}
+	defer func() {
+		if e != nil {
+			os.RemoveAll(backupTempDir)
+		}
+	}()
+
+	logger.Infof("Starting backup of ledger [%s] at height [%d]", l.ledgerID, height)
+	genesisBlock, err := l.blockStore.RetrieveBlockByNumber(0)
+	if err != nil {
+		return "", err
+	}
+	idStore, err := openIDStore(LedgerProviderPath(backupTempDir))
+	if err != nil {
+		return "", err
+	}
+	err = idStore.createLedgerID(l.ledgerID, genesisBlock)
+	idStore.close()
+	if err != nil {
+		return "", err
+	}
+
+	if err := snapshots.blockStore.CopyTo(BlockStorePath(backupTempDir)); err != nil {
+		return "", errors.WithMessage(err, "error while backing up block store")
+	}
+	for _, store := range snapshots.stores {
+		if err := store.snapshot.CopyTo(store.path(backupTempDir)); err != nil {
+			return "", errors.WithMessagef(err, "error while backing up %s", store.name)
+		}
+	}
+	return backupTempDir, nil
+}
+
+func (l *kvLedger) hashBackupFiles(dir string) (map[string]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while listing backup files in dir [%s]", dir)
+	}
+	sort.Strings(files)
+
+	filesAndHashes := map[string]string{}
+	for _, path := range files {
+		hashsum, err := l.hashFile(path)
+		if err != nil {
+			return nil, err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error while computing relative path of file:%s", path)
+		}
+		filesAndHashes[filepath.ToSlash(relPath)] = hex.EncodeToString(hashsum)
+	}
+	return filesAndHashes, nil
+}
+
+func (l *kvLedger) hashFile(path string) ([]byte, error) {
+	hash, err := l.hashProvider.GetHash(backupHashOpts)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while opening file:%s", path)
+	}
+	defer file.Close()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, errors.Wrapf(err, "error while hashing file:%s", path)
+	}
+	return hash.Sum(nil), nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProviderWithCollectionConfig(
+		t,
+		[]*nsCollBtlConfig{{namespace: "ns", btlConfig: map[string]uint64{"coll": 0}}},
+		conf,
+	)
+	defer provider.Close()
+
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+
+	blockAndPvtdata1 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1", "key2": "value2.1"},
+		map[string]string{"key1": "pvtValue1.1"},
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata1, &ledger.CommitOptions{}))
+	blockAndPvtdata2 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2"},
+		nil,
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata2, &ledger.CommitOptions{}))
+
+	manifest, err := kvlgr.Backup()
+	require.NoError(t, err)
+	require.Equal(t, "testLedgerid", manifest.LedgerID)
+	require.Equal(t, uint64(3), manifest.Height)
+	require.Equal(t, hex.EncodeToString(protoutil.BlockHeaderHash(blockAndPvtdata2.Block.Header)), manifest.LastBlockHashInHex)
+	require.Equal(t, "goleveldb", manifest.StateDatabase)
+	require.Equal(t, BackupDirForLedgerHeight(conf.BackupsConfig.RootDir, "testLedgerid", 3), manifest.Dir)
+
+	// the manifest lists the SM3 hashes of all the files of the backup
+	manifestBytes, err := ioutil.ReadFile(filepath.Join(manifest.Dir, backupManifestFileName))
+	require.NoError(t, err)
+	writtenManifest := &BackupManifest{}
+	require.NoError(t, json.Unmarshal(manifestBytes, writtenManifest))
+	require.Equal(t, manifest.FilesAndHashes, writtenManifest.FilesAndHashes)
+	require.Contains(t, writtenManifest.FilesAndHashes, "chains/chains/testLedgerid/blockfile_000000")
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	for file, hashInHex := range writtenManifest.FilesAndHashes {
+		content, err := ioutil.ReadFile(filepath.Join(manifest.Dir, file))
+		require.NoError(t, err)
+		hash, err := cryptoProvider.Hash(content, &bccsp.SM3Opts{})
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(hash), hashInHex, "hash of file %s", file)
+	}
+	inProgress, err := ioutil.ReadDir(InProgressBackupsPath(conf.BackupsConfig.RootDir))
+	require.NoError(t, err)
+	require.Empty(t, inProgress)
+	// the block files are copied rather than shared with the ledger
+	liveBlockFile, err := os.Stat(filepath.Join(BlockStorePath(conf.RootFSPath), "chains", "testLedgerid", "blockfile_000000"))
+	require.NoError(t, err)
+	backupBlockFile, err := os.Stat(filepath.Join(manifest.Dir, "chains", "chains", "testLedgerid", "blockfile_000000"))
+	require.NoError(t, err)
+	require.False(t, os.SameFile(liveBlockFile, backupBlockFile))
+	commitHashAtBackup := kvlgr.commitHash
+
+	// the blocks committed after the backup are not part of it
+	blockAndPvtdata3 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk3",
+		map[string]string{"key1": "value1.3"},
+		nil,
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata3, &ledger.CommitOptions{}))
+
+	// the backup dir is the ledgersData dir of a peer holding the ledger
+	restoredConf, restoredCleanup := testConfig(t)
+	defer restoredCleanup()
+	restoredConf.RootFSPath = manifest.Dir
+	restoredProvider := testutilNewProviderWithCollectionConfig(
+		t,
+		[]*nsCollBtlConfig{{namespace: "ns", btlConfig: map[string]uint64{"coll": 0}}},
+		restoredConf,
+	)
+	defer restoredProvider.Close()
+	restoredLgr, err := restoredProvider.Open("testLedgerid")
+	require.NoError(t, err)
+	defer restoredLgr.Close()
+
+	bcInfo, err := restoredLgr.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), bcInfo.Height)
+	require.Equal(t, commitHashAtBackup, restoredLgr.(*kvLedger).commitHash)
+	qe, err := restoredLgr.NewQueryExecutor()
+	require.NoError(t, err)
+	defer qe.Done()
+	val, err := qe.GetState("ns", "key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1.2"), val)
+	pvtVal, err := qe.GetPrivateData("ns", "coll", "key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("pvtValue1.1"), pvtVal)
+	hqe, err := restoredLgr.NewHistoryQueryExecutor()
+	require.NoError(t, err)
+	itr, err := hqe.GetHistoryForKey("ns", "key1")
+	require.NoError(t, err)
+	defer itr.Close()
+	numHistoryEntries := 0
+	for {
+		res, err := itr.Next()
+		require.NoError(t, err)
+		if res == nil {
+			break
+		}
+		numHistoryEntries++
+	}
+	require.Equal(t, 2, numHistoryEntries)
+}
+
+func TestBackupErrors(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	_, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+
+	manifest, err := kvlgr.Backup()
+	require.NoError(t, err)
+	_, err = kvlgr.Backup()
+	require.EqualError(t, err, "a backup of ledger [testLedgerid] at height [1] already exists at ["+manifest.Dir+"]")
+
+	kvlgr.backupsConfig = &ledger.BackupsConfig{RootDir: "relative/path"}
+	_, err = kvlgr.Backup()
+	require.EqualError(t, err, "invalid path: relative/path. The path for the backups dir is expected to be an absolute path")
+
+	kvlgr.backupsConfig = nil
+	_, err = kvlgr.Backup()
+	require.EqualError(t, err, "no root dir is configured for the backups")
+
+	// the temp dir of a failed backup is removed
+	kvlgr.backupsConfig = conf.BackupsConfig
+	ledgerBackupsDir := filepath.Dir(manifest.Dir)
+	require.NoError(t, os.RemoveAll(ledgerBackupsDir))
+	require.NoError(t, ioutil.WriteFile(ledgerBackupsDir, []byte("not a dir"), 0644))
+	_, err = kvlgr.Backup()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error while creating final dir for backup")
+	inProgress, err := ioutil.ReadDir(InProgressBackupsPath(conf.BackupsConfig.RootDir))
+	require.NoError(t, err)
+	require.Empty(t, inProgress)
+}
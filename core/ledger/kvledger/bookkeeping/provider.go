@@ -20,6 +20,10 @@ const (
 	PvtdataExpiry Category = iota
 	// MetadataPresenceIndicator maintains the bookkeeping about whether metadata is ever set for a namespace
 	MetadataPresenceIndicator
+	// RebuildProgress maintains the checkpoint of the rebuild of the ledger databases from the block store
+	RebuildProgress
+	// CommitJournal maintains the progress of the commit of the last block to the ledger stores
+	CommitJournal
 )
 
 // Provider provides handle to different bookkeepers for the given ledger
@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// CheckDataFormat checks that the ledger data under the given root path is in the
+// current data format, so that the ledger provider can open it. It returns an
+// ErrFormatMismatch otherwise, and nil if no ledger data exists yet. The peer must
+// be offline while the check is performed.
+func CheckDataFormat(rootFSPath string) error {
+	fileLock := leveldbhelper.NewFileLock(fileLockPath(rootFSPath))
+	if err := fileLock.Lock(); err != nil {
+		return errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	dbPath := LedgerProviderPath(rootFSPath)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil
+	}
+	db := leveldbhelper.CreateDB(&leveldbhelper.Conf{DBPath: dbPath})
+	db.Open()
+	defer db.Close()
+
+	emptyDB, err := db.IsEmpty()
+	if err != nil || emptyDB {
+		return err
+	}
+	format, err := db.Get(formatKey)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(format, []byte(dataformat.CurrentFormat)) {
+		return &dataformat.ErrFormatMismatch{
+			ExpectedFormat: dataformat.CurrentFormat,
+			Format:         string(format),
+			DBInfo:         fmt.Sprintf("leveldb for channel-IDs at [%s]", dbPath),
+		}
+	}
+	return nil
+}
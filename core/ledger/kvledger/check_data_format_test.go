@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDataFormat(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	conf.HistoryDBConfig.Enabled = false
+	defer cleanup()
+
+	// no ledger data yet
+	require.NoError(t, CheckDataFormat(conf.RootFSPath))
+
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	provider.Close()
+	require.NoError(t, CheckDataFormat(conf.RootFSPath))
+
+	// the check fails while the ledger data is in use
+	fileLock := leveldbhelper.NewFileLock(fileLockPath(conf.RootFSPath))
+	require.NoError(t, fileLock.Lock())
+	require.Contains(t, CheckDataFormat(conf.RootFSPath).Error(), "as another peer node command is executing")
+	fileLock.Unlock()
+
+	provider = testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	err := provider.idStore.db.Put(formatKey, []byte(dataformat.PreviousFormat), true)
+	provider.Close()
+	require.NoError(t, err)
+
+	err = CheckDataFormat(conf.RootFSPath)
+	expectedErr := &dataformat.ErrFormatMismatch{
+		ExpectedFormat: dataformat.CurrentFormat,
+		Format:         dataformat.PreviousFormat,
+		DBInfo:         fmt.Sprintf("leveldb for channel-IDs at [%s]", LedgerProviderPath(conf.RootFSPath)),
+	}
+	require.EqualError(t, err, expectedErr.Error())
+}
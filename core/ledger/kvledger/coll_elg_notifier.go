@@ -12,8 +12,8 @@ import (
 	"github.com/hyperledger/fabric/core/ledger"
 )
 
-// collElgNotifier listens for the chaincode events and determines whether the peer has become eligible for one or more existing
-// private data collections and notifies the registered listener
+// collElgNotifier listens for the chaincode events and determines whether the peer has become eligible, or is no longer
+// eligible, for one or more existing private data collections and notifies the registered listener
 type collElgNotifier struct {
 	deployedChaincodeInfoProvider ledger.DeployedChaincodeInfoProvider
 	membershipInfoProvider        ledger.MembershipInfoProvider
@@ -41,10 +41,14 @@ func (n *collElgNotifier) InterestedInNamespaces() []string {
 // 1) Retrieves the existing collection configurations and new collection configurations
 // 2) Computes the collections for which the peer is not eligible as per the existing collection configuration
 //    but is eligible as per the new collection configuration
+// 3) Computes the collections for which the peer is eligible as per the existing collection configuration
+//    but is not eligible as per the new collection configuration
 // Finally, it causes an invocation to function 'ProcessCollsEligibilityEnabled' on ledger store with a map {ns:colls}
-// that contains the details of <ns, coll> combination for which the eligibility of the peer is switched on.
+// that contains the details of <ns, coll> combination for which the eligibility of the peer is switched on, and
+// to function 'ProcessCollsEligibilityDisabled' with the <ns, coll> combination for which it is switched off.
 func (n *collElgNotifier) HandleStateUpdates(trigger *ledger.StateUpdateTrigger) error {
 	nsCollMap := map[string][]string{}
+	disabledNsCollMap := map[string][]string{}
 	qe := trigger.CommittedStateQueryExecutor
 	postCommitQE := trigger.PostCommitQueryExecutor
 
@@ -80,10 +84,28 @@ func (n *collElgNotifier) HandleStateUpdates(trigger *ledger.StateUpdateTrigger)
 		if len(elgEnabledCollNames) > 0 {
 			nsCollMap[ccName] = elgEnabledCollNames
 		}
+
+		elgDisabledCollNames, err := n.elgDisabledCollNames(
+			ledgerid,
+			existingCCInfo.ExplicitCollectionConfigPkg,
+			postCommitCCInfo.ExplicitCollectionConfigPkg,
+		)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("[%s] collections of chaincode [%s] for which peer was eligible before and now the eligiblity is disabled - [%s]",
+			ledgerid, ccName, elgDisabledCollNames,
+		)
+		if len(elgDisabledCollNames) > 0 {
+			disabledNsCollMap[ccName] = elgDisabledCollNames
+		}
 	}
 	if len(nsCollMap) > 0 {
 		n.invokeLedgerSpecificNotifier(trigger.LedgerID, trigger.CommittingBlockNum, nsCollMap)
 	}
+	if len(disabledNsCollMap) > 0 {
+		return n.listeners[trigger.LedgerID].ProcessCollsEligibilityDisabled(trigger.CommittingBlockNum, disabledNsCollMap)
+	}
 	return nil
 }
 
@@ -136,6 +158,40 @@ func (n *collElgNotifier) elgEnabled(ledgerID string, existingPolicy, postCommit
 	return n.membershipInfoProvider.AmMemberOf(ledgerID, postCommitPolicy)
 }
 
+// elgDisabledCollNames returns the names of the collections for which the peer is eligible as per 'existingPkg' and is not eligible as per 'postCommitPkg'
+func (n *collElgNotifier) elgDisabledCollNames(ledgerID string,
+	existingPkg, postCommitPkg *peer.CollectionConfigPackage) ([]string, error) {
+
+	collectionNames := []string{}
+	existingConfMap := map[string]*peer.StaticCollectionConfig{}
+	for _, existingConf := range retrieveCollConfs(existingPkg) {
+		existingConfMap[existingConf.Name] = existingConf
+	}
+
+	for _, postCommitConf := range retrieveCollConfs(postCommitPkg) {
+		existingConf, ok := existingConfMap[postCommitConf.Name]
+		if !ok { // brand new collection
+			continue
+		}
+		existingMember, err := n.membershipInfoProvider.AmMemberOf(ledgerID, existingConf.MemberOrgsPolicy)
+		if err != nil {
+			return nil, err
+		}
+		if !existingMember {
+			continue
+		}
+		postCommitMember, err := n.membershipInfoProvider.AmMemberOf(ledgerID, postCommitConf.MemberOrgsPolicy)
+		if err != nil {
+			return nil, err
+		}
+		if !postCommitMember {
+			// an existing member and removed now
+			collectionNames = append(collectionNames, postCommitConf.Name)
+		}
+	}
+	return collectionNames, nil
+}
+
 func extractPublicUpdates(stateUpdates ledger.StateUpdates) map[string][]*kvrwset.KVWrite {
 	m := map[string][]*kvrwset.KVWrite{}
 	for ns, updates := range stateUpdates {
@@ -151,6 +207,7 @@ func (n *collElgNotifier) StateCommitDone(ledgerID string) {
 
 type collElgListener interface {
 	ProcessCollsEligibilityEnabled(commitingBlk uint64, nsCollMap map[string][]string) error
+	ProcessCollsEligibilityDisabled(commitingBlk uint64, nsCollMap map[string][]string) error
 }
 
 func retrieveCollConfs(collConfPkg *peer.CollectionConfigPackage) []*peer.StaticCollectionConfig {
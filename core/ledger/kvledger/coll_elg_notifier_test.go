@@ -73,11 +73,24 @@ func TestCollElgNotifier(t *testing.T) {
 		},
 		mockCollElgListener.receivedNsCollMap,
 	)
+	// and the peer is no longer eligible for "coll1"
+	require.Equal(t,
+		map[string][]string{
+			"cc1": {"coll1"},
+		},
+		mockCollElgListener.receivedDisabledNsCollMap,
+	)
 }
 
 type mockCollElgListener struct {
-	receivedCommittingBlk uint64
-	receivedNsCollMap     map[string][]string
+	receivedCommittingBlk     uint64
+	receivedNsCollMap         map[string][]string
+	receivedDisabledNsCollMap map[string][]string
+}
+
+func (m *mockCollElgListener) ProcessCollsEligibilityDisabled(commitingBlk uint64, nsCollMap map[string][]string) error {
+	m.receivedDisabledNsCollMap = nsCollMap
+	return nil
 }
 
 func (m *mockCollElgListener) ProcessCollsEligibilityEnabled(commitingBlk uint64, nsCollMap map[string][]string) error {
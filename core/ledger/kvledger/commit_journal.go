@@ -0,0 +1,152 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+var commitJournalKey = []byte("commitJournal")
+
+// commitStage is a step of the commit of a block, after which the block is durable
+// in one or more of the ledger stores
+type commitStage uint64
+
+const (
+	// stageBlockStore is reached once the block is in the pvtdata and block stores
+	stageBlockStore commitStage = 1 << iota
+	// stageStateDB is reached once the block is committed to the state database
+	stageStateDB
+	// stageHistoryDB is reached once the block is committed to the history database
+	stageHistoryDB
+)
+
+var commitStageNames = []struct {
+	stage commitStage
+	name  string
+}{
+	{stageBlockStore, "block store"},
+	{stageStateDB, "state"},
+	{stageHistoryDB, "history"},
+}
+
+func (s commitStage) String() string {
+	var names []string
+	for _, n := range commitStageNames {
+		if s&n.stage != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// commitJournalEntry records the stages reached by the commit of a block. The entry
+// of a block is written before any store is updated, so that a crash in the middle of
+// a commit leaves an entry telling which stores may lack the block.
+type commitJournalEntry struct {
+	blockNum uint64
+	expected commitStage
+	reached  commitStage
+}
+
+func (e *commitJournalEntry) complete() bool {
+	return e.reached&e.expected == e.expected
+}
+
+func (e *commitJournalEntry) toBytes() []byte {
+	buf := proto.NewBuffer(nil)
+	buf.EncodeVarint(e.blockNum)
+	buf.EncodeVarint(uint64(e.expected))
+	buf.EncodeVarint(uint64(e.reached))
+	return buf.Bytes()
+}
+
+func commitJournalEntryFromBytes(b []byte) (*commitJournalEntry, error) {
+	buf := proto.NewBuffer(b)
+	var fields [3]uint64
+	for i := range fields {
+		v, err := buf.DecodeVarint()
+		if err != nil {
+			return nil, errors.Wrap(err, "error decoding commit journal entry")
+		}
+		fields[i] = v
+	}
+	return &commitJournalEntry{
+		blockNum: fields[0],
+		expected: commitStage(fields[1]),
+		reached:  commitStage(fields[2]),
+	}, nil
+}
+
+// commitJournal is a write-ahead journal of the commit of the blocks to the stores
+// of a ledger. Only the entry of the last block is kept. The entry is synced to disk
+// when the commit of a block begins, the stages reached are recorded without syncing
+// as the stores are the reference for what is durable, the journal telling which of
+// them to check upon recovery.
+type commitJournal struct {
+	db    *leveldbhelper.DBHandle
+	entry *commitJournalEntry
+}
+
+func newCommitJournal(db *leveldbhelper.DBHandle) *commitJournal {
+	return &commitJournal{db: db}
+}
+
+func (j *commitJournal) retrieve() (*commitJournalEntry, error) {
+	b, err := j.db.Get(commitJournalKey)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return commitJournalEntryFromBytes(b)
+}
+
+// begin records that the commit of a block to the given stores is starting
+func (j *commitJournal) begin(blockNum uint64, expected commitStage) error {
+	j.entry = &commitJournalEntry{blockNum: blockNum, expected: expected}
+	return j.db.Put(commitJournalKey, j.entry.toBytes(), true)
+}
+
+// reached records that the block being committed is durable in a store
+func (j *commitJournal) reached(stage commitStage) error {
+	j.entry.reached |= stage
+	return j.db.Put(commitJournalKey, j.entry.toBytes(), false)
+}
+
+// resolve records that an interrupted commit has been recovered
+func (j *commitJournal) resolve(entry *commitJournalEntry) error {
+	entry.reached = entry.expected
+	j.entry = entry
+	return j.db.Put(commitJournalKey, entry.toBytes(), true)
+}
+
+// commitRecoveryReport describes the recovery of a commit interrupted by a crash
+type commitRecoveryReport struct {
+	ledgerID    string
+	blockNum    uint64
+	reached     commitStage
+	recommitted commitStage
+	// refetch is set when the block never reached the block store and is to be
+	// delivered again to the peer
+	refetch bool
+}
+
+func (r *commitRecoveryReport) String() string {
+	s := fmt.Sprintf("Recovered the interrupted commit of block [%d] of ledger [%s]: stores reached [%s]",
+		r.blockNum, r.ledgerID, r.reached)
+	if r.refetch {
+		return s + ", the block is not in the block store and will be fetched again"
+	}
+	return s + fmt.Sprintf(", recommitted to [%s]", r.recommitted)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitJournalEntry(t *testing.T) {
+	entry := &commitJournalEntry{
+		blockNum: 10,
+		expected: stageBlockStore | stageStateDB | stageHistoryDB,
+		reached:  stageBlockStore,
+	}
+	decoded, err := commitJournalEntryFromBytes(entry.toBytes())
+	require.NoError(t, err)
+	require.Equal(t, entry, decoded)
+	require.False(t, decoded.complete())
+	decoded.reached |= stageStateDB | stageHistoryDB
+	require.True(t, decoded.complete())
+
+	_, err = commitJournalEntryFromBytes([]byte{0xff})
+	require.EqualError(t, err, "error decoding commit journal entry: unexpected EOF")
+
+	require.Equal(t, "none", commitStage(0).String())
+	require.Equal(t, "block store, history", (stageBlockStore | stageHistoryDB).String())
+
+	report := &commitRecoveryReport{ledgerID: "ledger1", blockNum: 10, reached: stageBlockStore, recommitted: stageStateDB}
+	require.Equal(t, "Recovered the interrupted commit of block [10] of ledger [ledger1]: stores reached [block store], recommitted to [state]", report.String())
+	report = &commitRecoveryReport{ledgerID: "ledger1", blockNum: 10, refetch: true}
+	require.Equal(t, "Recovered the interrupted commit of block [10] of ledger [ledger1]: stores reached [none], the block is not in the block store and will be fetched again", report.String())
+}
+
+func TestCommitJournalRecovery(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	nsCollBtlConfs := []*nsCollBtlConfig{
+		{
+			namespace: "ns",
+			btlConfig: map[string]uint64{"coll": 0},
+		},
+	}
+	provider1 := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, conf)
+	defer provider1.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger1, err := provider1.Create(gb)
+	require.NoError(t, err)
+	defer ledger1.Close()
+	kvledger1 := ledger1.(*kvLedger)
+
+	blockAndPvtdata1 := prepareNextBlockForTest(t, ledger1, bg, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1"}, map[string]string{"key1": "pvtValue1.1"})
+	require.NoError(t, ledger1.CommitLegacy(blockAndPvtdata1, &lgr.CommitOptions{}))
+	entry, err := kvledger1.commitJournal.retrieve()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), entry.blockNum)
+	require.True(t, entry.complete())
+
+	// the peer fails after committing the block to the block store and the state database,
+	// before the commit to the state database is recorded in the journal
+	blockAndPvtdata2 := prepareNextBlockForTest(t, ledger1, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2"}, map[string]string{"key1": "pvtValue1.2"})
+	_, _, err = kvledger1.txmgr.ValidateAndPrepare(blockAndPvtdata2, true)
+	require.NoError(t, err)
+	require.NoError(t, kvledger1.commitJournal.begin(2, stageBlockStore|stageStateDB|stageHistoryDB))
+	require.NoError(t, kvledger1.commitToPvtAndBlockStore(blockAndPvtdata2))
+	kvledger1.recordCommitStage(stageBlockStore)
+	require.NoError(t, kvledger1.txmgr.Commit())
+	ledger1.Close()
+	provider1.Close()
+
+	// the journal drives the recommit of the block to the history database only
+	provider2 := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, conf)
+	defer provider2.Close()
+	ledger2, err := provider2.Open("testLedger")
+	require.NoError(t, err)
+	defer ledger2.Close()
+	kvledger2 := ledger2.(*kvLedger)
+	checkBCSummaryForTest(t, ledger2,
+		&bcSummary{
+			stateDBSavePoint:   uint64(2),
+			stateDBKVs:         map[string]string{"key1": "value1.2"},
+			stateDBPvtKVs:      map[string]string{"key1": "pvtValue1.2"},
+			historyDBSavePoint: uint64(2),
+			historyKey:         "key1",
+			historyVals:        []string{"value1.2", "value1.1"},
+		},
+	)
+	entry, err = kvledger2.commitJournal.retrieve()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), entry.blockNum)
+	require.True(t, entry.complete())
+
+	// the peer fails before the block reaches the block store
+	require.NoError(t, kvledger2.commitJournal.begin(3, stageBlockStore|stageStateDB|stageHistoryDB))
+	ledger2.Close()
+	provider2.Close()
+
+	provider3 := testutilNewProviderWithCollectionConfig(t, nsCollBtlConfs, conf)
+	defer provider3.Close()
+	ledger3, err := provider3.Open("testLedger")
+	require.NoError(t, err)
+	defer ledger3.Close()
+	bcInfo, err := ledger3.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), bcInfo.Height)
+	entry, err = ledger3.(*kvLedger).commitJournal.retrieve()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), entry.blockNum)
+	require.True(t, entry.complete())
+}
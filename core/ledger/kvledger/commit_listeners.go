@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"runtime/debug"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+// notifyCommitListeners notifies the commit listeners, in the order of their registration,
+// of the block that was just committed along with the private data the peer is eligible for
+func (l *kvLedger) notifyCommitListeners(blockAndPvtdata *ledger.BlockAndPvtData) {
+	if len(l.commitListeners) == 0 {
+		return
+	}
+	event := &ledger.CommitEvent{
+		LedgerID: l.ledgerID,
+		Block:    blockAndPvtdata.Block,
+		PvtData:  l.eligiblePvtData(blockAndPvtdata.PvtData),
+	}
+	for _, listener := range l.commitListeners {
+		l.notifyCommitListener(listener, event)
+	}
+}
+
+// notifyCommitListener isolates the ledger and the other listeners from
+// the errors returned and the panics raised by the given listener
+func (l *kvLedger) notifyCommitListener(listener ledger.CommitListener, event *ledger.CommitEvent) {
+	blockNum := event.Block.Header.Number
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("[%s] Commit listener [%s] panicked while handling block [%d]: %v\n%s",
+				l.ledgerID, listener.Name(), blockNum, r, debug.Stack())
+		}
+	}()
+	if err := listener.HandleCommittedBlock(event); err != nil {
+		logger.Errorf("[%s] Commit listener [%s] failed handling block [%d]: %+v", l.ledgerID, listener.Name(), blockNum, err)
+	}
+}
+
+// eligiblePvtData returns the private data of the collections the peer is a member of,
+// as per the collection configurations that are in effect after the commit of the block.
+// The private data of a collection whose eligibility cannot be determined is left out.
+func (l *kvLedger) eligiblePvtData(pvtData ledger.TxPvtDataMap) ledger.TxPvtDataMap {
+	if len(pvtData) == 0 {
+		return nil
+	}
+
+	collInfoRetriever := &collectionInfoRetriever{l.ledgerID, l, l.ccInfoProvider}
+	eligibility := map[[2]string]bool{}
+	isEligible := func(ns, coll string) bool {
+		if eligible, ok := eligibility[[2]string{ns, coll}]; ok {
+			return eligible
+		}
+		eligible, err := l.isEligibleForCollection(collInfoRetriever, ns, coll)
+		if err != nil {
+			logger.Warningf("[%s] Leaving out the private data of collection [%s:%s] for the commit listeners, failed determining eligibility: %s",
+				l.ledgerID, ns, coll, err)
+		}
+		eligibility[[2]string{ns, coll}] = eligible
+		return eligible
+	}
+
+	eligiblePvtData := ledger.TxPvtDataMap{}
+	for txNum, txPvtData := range pvtData {
+		if txPvtData == nil || txPvtData.WriteSet == nil {
+			continue
+		}
+		writeSet := &rwset.TxPvtReadWriteSet{DataModel: txPvtData.WriteSet.DataModel}
+		for _, nsPvtRwset := range txPvtData.WriteSet.NsPvtRwset {
+			var collPvtRwsets []*rwset.CollectionPvtReadWriteSet
+			for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+				if isEligible(nsPvtRwset.Namespace, collPvtRwset.CollectionName) {
+					collPvtRwsets = append(collPvtRwsets, collPvtRwset)
+				}
+			}
+			if len(collPvtRwsets) == 0 {
+				continue
+			}
+			writeSet.NsPvtRwset = append(writeSet.NsPvtRwset, &rwset.NsPvtReadWriteSet{
+				Namespace:          nsPvtRwset.Namespace,
+				CollectionPvtRwset: collPvtRwsets,
+			})
+		}
+		if len(writeSet.NsPvtRwset) == 0 {
+			continue
+		}
+		eligiblePvtData[txNum] = &ledger.TxPvtData{SeqInBlock: txPvtData.SeqInBlock, WriteSet: writeSet}
+	}
+	return eligiblePvtData
+}
+
+func (l *kvLedger) isEligibleForCollection(collInfoRetriever *collectionInfoRetriever, ns, coll string) (bool, error) {
+	if l.membershipInfoProvider == nil {
+		return false, nil
+	}
+	collConf, err := collInfoRetriever.CollectionInfo(ns, coll)
+	if err != nil || collConf == nil {
+		return false, err
+	}
+	return l.membershipInfoProvider.AmMemberOf(l.ledgerID, collConf.MemberOrgsPolicy)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitListeners(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+
+	memberPolicy := &peer.CollectionPolicyConfig{}
+	nonMemberPolicy := &peer.CollectionPolicyConfig{}
+	unknownPolicy := &peer.CollectionPolicyConfig{}
+	collConfigs := map[string]*peer.StaticCollectionConfig{
+		"coll1": {Name: "coll1", MemberOrgsPolicy: memberPolicy},
+		"coll2": {Name: "coll2", MemberOrgsPolicy: nonMemberPolicy},
+		"coll3": {Name: "coll3", MemberOrgsPolicy: unknownPolicy},
+	}
+	collConfigPkg := &peer.CollectionConfigPackage{}
+	for _, collName := range []string{"coll1", "coll2", "coll3"} {
+		collConfigPkg.Config = append(collConfigPkg.Config, &peer.CollectionConfig{
+			Payload: &peer.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: collConfigs[collName]},
+		})
+	}
+	ccInfoProvider := &mock.DeployedChaincodeInfoProvider{}
+	ccInfoProvider.AllCollectionsConfigPkgReturns(collConfigPkg, nil)
+	ccInfoProvider.CollectionInfoStub = func(channelName, ccName, collName string, qe lgr.SimpleQueryExecutor) (*peer.StaticCollectionConfig, error) {
+		return collConfigs[collName], nil
+	}
+	membershipInfoProvider := &mock.MembershipInfoProvider{}
+	membershipInfoProvider.AmMemberOfStub = func(channelName string, policy *peer.CollectionPolicyConfig) (bool, error) {
+		if policy == unknownPolicy {
+			return false, errors.New("membership unknown")
+		}
+		return policy == memberPolicy, nil
+	}
+
+	var notified []string
+	var events []*lgr.CommitEvent
+	var heights []uint64
+	var l lgr.PeerLedger
+	panicking := &mock.CommitListener{}
+	panicking.NameReturns("panicking")
+	panicking.HandleCommittedBlockStub = func(event *lgr.CommitEvent) error {
+		notified = append(notified, "panicking")
+		panic("listener failure")
+	}
+	failing := &mock.CommitListener{}
+	failing.NameReturns("failing")
+	failing.HandleCommittedBlockStub = func(event *lgr.CommitEvent) error {
+		notified = append(notified, "failing")
+		return errors.New("listener failure")
+	}
+	indexer := &mock.CommitListener{}
+	indexer.NameReturns("indexer")
+	indexer.HandleCommittedBlockStub = func(event *lgr.CommitEvent) error {
+		notified = append(notified, "indexer")
+		events = append(events, event)
+		// the listener can query the ledger about the block just committed,
+		// once the ledger being created is returned by the provider
+		if l != nil {
+			bcInfo, err := l.GetBlockchainInfo()
+			require.NoError(t, err)
+			heights = append(heights, bcInfo.Height)
+		}
+		return nil
+	}
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	provider, err := NewProvider(
+		&lgr.Initializer{
+			DeployedChaincodeInfoProvider: ccInfoProvider,
+			MembershipInfoProvider:        membershipInfoProvider,
+			MetricsProvider:               &disabled.Provider{},
+			Config:                        conf,
+			HashProvider:                  cryptoProvider,
+			CommitListeners:               []lgr.CommitListener{panicking, failing, indexer},
+		},
+	)
+	require.NoError(t, err)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err = provider.Create(gb)
+	require.NoError(t, err)
+	defer l.Close()
+	kvl := l.(*kvLedger)
+	// the listeners are notified of the genesis block as well
+	require.Equal(t, []string{"panicking", "failing", "indexer"}, notified)
+	notified = nil
+
+	simulator, err := l.NewTxSimulator("txid1")
+	require.NoError(t, err)
+	require.NoError(t, simulator.SetState("ns", "key1", []byte("value1")))
+	require.NoError(t, simulator.SetPrivateData("ns", "coll1", "key1", []byte("value1")))
+	require.NoError(t, simulator.SetPrivateData("ns", "coll2", "key1", []byte("value1")))
+	require.NoError(t, simulator.SetPrivateData("ns", "coll3", "key1", []byte("value1")))
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	require.NoError(t, err)
+	pubSimBytes, err := simRes.GetPubSimulationBytes()
+	require.NoError(t, err)
+	block1 := bg.NextBlock([][]byte{pubSimBytes})
+	require.NoError(t, l.CommitLegacy(&lgr.BlockAndPvtData{
+		Block:   block1,
+		PvtData: lgr.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}},
+	}, &lgr.CommitOptions{}))
+
+	block2 := bg.NextBlock([][]byte{pubSimBytes})
+	require.NoError(t, l.CommitLegacy(&lgr.BlockAndPvtData{Block: block2}, &lgr.CommitOptions{}))
+
+	// the listeners are notified in the order of their registration, block after block,
+	// and neither the panicking listener nor the failing one prevent the others from being notified
+	require.Equal(t, []string{"panicking", "failing", "indexer", "panicking", "failing", "indexer"}, notified)
+	require.Equal(t, []uint64{2, 3}, heights)
+
+	require.Len(t, events, 3)
+	require.Equal(t, "testLedger", events[1].LedgerID)
+	require.Equal(t, gb, events[0].Block)
+	require.Equal(t, block1, events[1].Block)
+	require.Equal(t, block2, events[2].Block)
+	require.Empty(t, events[2].PvtData)
+
+	// only the private data of the collections the peer is eligible for is delivered
+	require.Len(t, events[1].PvtData, 1)
+	writeSet := events[1].PvtData[0].WriteSet
+	require.Len(t, writeSet.NsPvtRwset, 1)
+	require.Equal(t, "ns", writeSet.NsPvtRwset[0].Namespace)
+	require.Len(t, writeSet.NsPvtRwset[0].CollectionPvtRwset, 1)
+	require.Equal(t, "coll1", writeSet.NsPvtRwset[0].CollectionPvtRwset[0].CollectionName)
+
+	// the private data committed to the ledger is left untouched
+	require.Len(t, simRes.PvtSimulationResults.NsPvtRwset[0].CollectionPvtRwset, 3)
+	pvtData, err := kvl.pvtdataStore.GetPvtDataByBlockNum(1, nil)
+	require.NoError(t, err)
+	require.Len(t, pvtData[0].WriteSet.NsPvtRwset[0].CollectionPvtRwset, 3)
+}
+
+func TestEligiblePvtDataWithoutMembershipInfo(t *testing.T) {
+	l := &kvLedger{ledgerID: "testLedger"}
+	require.Empty(t, l.eligiblePvtData(samplePvtData(t, []uint64{0, 1})))
+	require.Nil(t, l.eligiblePvtData(nil))
+}
@@ -0,0 +1,292 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// names of the leveldb based stores which are compacted
+const (
+	StateDBStore    = "statedb"
+	HistoryDBStore  = "historydb"
+	BlockIndexStore = "blockindex"
+)
+
+const (
+	defaultCompactionInterval = 24 * time.Hour
+	// compactionCheckInterval is the period at which the scheduler checks whether
+	// the stores are due for a compaction
+	compactionCheckInterval = time.Minute
+)
+
+// CompactionResult reports the compaction of a leveldb based store
+type CompactionResult struct {
+	Store           string  `json:"store"`
+	SizeBeforeBytes int64   `json:"size_before_bytes"`
+	SizeAfterBytes  int64   `json:"size_after_bytes"`
+	ReclaimedBytes  int64   `json:"reclaimed_bytes"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// compactableStore is a leveldb based store along with the dir holding its files
+type compactableStore struct {
+	name    string
+	path    string
+	compact func() error
+}
+
+// compactionWindow is the time of day window the scheduled compactions start in,
+// expressed as offsets from midnight
+type compactionWindow struct {
+	start time.Duration
+	end   time.Duration
+}
+
+func parseCompactionWindow(start, end string) (*compactionWindow, error) {
+	if start == "" && end == "" {
+		return nil, nil
+	}
+	startOffset, err := parseTimeOfDay(start)
+	if err != nil {
+		return nil, err
+	}
+	endOffset, err := parseTimeOfDay(end)
+	if err != nil {
+		return nil, err
+	}
+	return &compactionWindow{start: startOffset, end: endOffset}, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.Errorf("invalid time of day [%s] for the compaction window, expected the HH:MM format", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// contains returns whether the given time falls in the window
+func (w *compactionWindow) contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if w.start <= w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+// compactionScheduler compacts the leveldb based stores, either in the off-peak window
+// at the configured interval or on demand. The compactions run one at a time.
+type compactionScheduler struct {
+	stores   []*compactableStore
+	window   *compactionWindow
+	interval time.Duration
+	stats    *stats
+	now      func() time.Time
+
+	mutex          sync.Mutex
+	lastCompaction map[string]time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (p *Provider) initCompactionScheduler() error {
+	rootFSPath := p.initializer.Config.RootFSPath
+	stores := []*compactableStore{
+		{
+			name:    BlockIndexStore,
+			path:    filepath.Join(BlockStorePath(rootFSPath), blkstorage.IndexDir),
+			compact: p.blkStoreProvider.CompactIndex,
+		},
+	}
+	if vdbProvider, ok := p.dbProvider.VersionedDBProvider.(interface{ Compact() error }); ok {
+		stores = append(stores, &compactableStore{
+			name:    StateDBStore,
+			path:    StateDBPath(rootFSPath),
+			compact: vdbProvider.Compact,
+		})
+	}
+	if p.historydbProvider != nil {
+		stores = append(stores, &compactableStore{
+			name:    HistoryDBStore,
+			path:    HistoryDBPath(rootFSPath),
+			compact: p.historydbProvider.Compact,
+		})
+	}
+
+	conf := p.initializer.Config.CompactionConfig
+	if conf == nil {
+		conf = &ledger.CompactionConfig{}
+	}
+	window, err := parseCompactionWindow(conf.WindowStart, conf.WindowEnd)
+	if err != nil {
+		return err
+	}
+	interval := conf.Interval
+	if interval <= 0 {
+		interval = defaultCompactionInterval
+	}
+
+	p.compactionScheduler = &compactionScheduler{
+		stores:         stores,
+		window:         window,
+		interval:       interval,
+		stats:          p.stats,
+		now:            time.Now,
+		lastCompaction: map[string]time.Time{},
+	}
+	if conf.Enabled {
+		p.compactionScheduler.start()
+	}
+	return nil
+}
+
+// CompactStores compacts the given leveldb based stores, or all of them when none is given,
+// and reports the space reclaimed by the compactions
+func (p *Provider) CompactStores(storeNames ...string) ([]*CompactionResult, error) {
+	return p.compactionScheduler.compact(storeNames...)
+}
+
+func (s *compactionScheduler) start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+}
+
+func (s *compactionScheduler) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(compactionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.compactDueStores()
+		}
+	}
+}
+
+// compactDueStores compacts the stores whose last compaction is older than the interval,
+// when the current time falls in the compaction window
+func (s *compactionScheduler) compactDueStores() {
+	if !s.window.contains(s.now()) {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, store := range s.stores {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		if last, ok := s.lastCompaction[store.name]; ok && s.now().Sub(last) < s.interval {
+			continue
+		}
+		result, err := s.compactStore(store)
+		if err != nil {
+			logger.Errorf("Scheduled compaction of store [%s] failed: %+v", store.name, err)
+			continue
+		}
+		logger.Infof("Scheduled compaction of store [%s] reclaimed %d bytes in %.1f seconds", store.name, result.ReclaimedBytes, result.DurationSeconds)
+	}
+}
+
+func (s *compactionScheduler) compact(storeNames ...string) ([]*CompactionResult, error) {
+	stores := s.stores
+	if len(storeNames) > 0 {
+		stores = nil
+		for _, name := range storeNames {
+			store := s.store(name)
+			if store == nil {
+				return nil, errors.Errorf("unknown store [%s], the stores which can be compacted are %v", name, s.storeNames())
+			}
+			stores = append(stores, store)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var results []*CompactionResult
+	for _, store := range stores {
+		result, err := s.compactStore(store)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// compactStore compacts a store and is expected to be called with the mutex held
+func (s *compactionScheduler) compactStore(store *compactableStore) (*CompactionResult, error) {
+	sizeBefore, err := dirSize(store.path)
+	if err != nil {
+		return nil, err
+	}
+	startTime := time.Now()
+	if err := store.compact(); err != nil {
+		return nil, errors.WithMessagef(err, "failed to compact store [%s]", store.name)
+	}
+	elapsed := time.Since(startTime)
+	s.lastCompaction[store.name] = s.now()
+	sizeAfter, err := dirSize(store.path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompactionResult{
+		Store:           store.name,
+		SizeBeforeBytes: sizeBefore,
+		SizeAfterBytes:  sizeAfter,
+		DurationSeconds: elapsed.Seconds(),
+	}
+	if sizeAfter < sizeBefore {
+		result.ReclaimedBytes = sizeBefore - sizeAfter
+	}
+	s.stats.updateCompaction(result)
+	return result, nil
+}
+
+func (s *compactionScheduler) store(name string) *compactableStore {
+	for _, store := range s.stores {
+		if store.name == name {
+			return store
+		}
+	}
+	return nil
+}
+
+func (s *compactionScheduler) storeNames() []string {
+	var names []string
+	for _, store := range s.stores {
+		names = append(names, store.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shutdown stops the scheduled compactions and waits for a running compaction to complete
+func (s *compactionScheduler) shutdown() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+}
@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactionWindow(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2020, 6, 1, hour, min, 0, 0, time.Local)
+	}
+
+	w, err := parseCompactionWindow("", "")
+	require.NoError(t, err)
+	require.Nil(t, w)
+	require.True(t, w.contains(at(12, 0)))
+
+	w, err = parseCompactionWindow("01:30", "04:00")
+	require.NoError(t, err)
+	require.False(t, w.contains(at(1, 29)))
+	require.True(t, w.contains(at(1, 30)))
+	require.True(t, w.contains(at(3, 59)))
+	require.False(t, w.contains(at(4, 0)))
+
+	// the window spans midnight
+	w, err = parseCompactionWindow("23:00", "02:00")
+	require.NoError(t, err)
+	require.True(t, w.contains(at(23, 30)))
+	require.True(t, w.contains(at(0, 30)))
+	require.False(t, w.contains(at(2, 0)))
+	require.False(t, w.contains(at(22, 59)))
+
+	_, err = parseCompactionWindow("01:00", "")
+	require.EqualError(t, err, "invalid time of day [] for the compaction window, expected the HH:MM format")
+	_, err = parseCompactionWindow("25:00", "02:00")
+	require.EqualError(t, err, "invalid time of day [25:00] for the compaction window, expected the HH:MM format")
+}
+
+func TestCompactStores(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.Create(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+	for i := 0; i < 3; i++ {
+		blockAndPvtdata := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk",
+			map[string]string{"key1": "value1", "key2": "value2"},
+			nil,
+		)
+		require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata, &ledger.CommitOptions{}))
+	}
+
+	results, err := provider.CompactStores()
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for i, store := range []string{BlockIndexStore, StateDBStore, HistoryDBStore} {
+		require.Equal(t, store, results[i].Store)
+		require.NotZero(t, results[i].SizeBeforeBytes)
+		require.NotZero(t, results[i].SizeAfterBytes)
+		if results[i].SizeAfterBytes < results[i].SizeBeforeBytes {
+			require.Equal(t, results[i].SizeBeforeBytes-results[i].SizeAfterBytes, results[i].ReclaimedBytes)
+		} else {
+			require.Zero(t, results[i].ReclaimedBytes)
+		}
+	}
+
+	results, err = provider.CompactStores(HistoryDBStore)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, HistoryDBStore, results[0].Store)
+
+	_, err = provider.CompactStores("pvtdatastore")
+	require.EqualError(t, err, "unknown store [pvtdatastore], the stores which can be compacted are [blockindex historydb statedb]")
+
+	// the queries still succeed after the compactions
+	qe, err := kvlgr.NewQueryExecutor()
+	require.NoError(t, err)
+	defer qe.Done()
+	val, err := qe.GetState("ns", "key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), val)
+}
+
+func TestCompactStoresWithInvalidWindow(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	conf.CompactionConfig = &ledger.CompactionConfig{
+		Enabled:     true,
+		WindowStart: "1am",
+		WindowEnd:   "04:00",
+	}
+	_, err := NewProvider(&ledger.Initializer{
+		DeployedChaincodeInfoProvider: &mock.DeployedChaincodeInfoProvider{},
+		MetricsProvider:               &metricsfakes.Provider{},
+		Config:                        conf,
+	})
+	require.EqualError(t, err, "invalid time of day [1am] for the compaction window, expected the HH:MM format")
+}
+
+func TestScheduledCompactions(t *testing.T) {
+	storeDir, err := ioutil.TempDir("", "compaction")
+	require.NoError(t, err)
+	defer os.RemoveAll(storeDir)
+	require.NoError(t, ioutil.WriteFile(storeDir+"/000001.ldb", make([]byte, 100), 0644))
+
+	var compactions []string
+	var compactionErr error
+	newStore := func(name string) *compactableStore {
+		return &compactableStore{
+			name: name,
+			path: storeDir,
+			compact: func() error {
+				compactions = append(compactions, name)
+				if compactionErr != nil {
+					return compactionErr
+				}
+				return ioutil.WriteFile(storeDir+"/000001.ldb", make([]byte, 40), 0644)
+			},
+		}
+	}
+
+	fakeHist := testutilConstructHist()
+	fakeCounter := testutilConstructCounter()
+	fakeGauge := testutilConstructGauge()
+	fakeProvider := &metricsfakes.Provider{}
+	fakeProvider.NewHistogramReturns(fakeHist)
+	fakeProvider.NewCounterReturns(fakeCounter)
+	fakeProvider.NewGaugeReturns(fakeGauge)
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.Local)
+	window, err := parseCompactionWindow("02:00", "04:00")
+	require.NoError(t, err)
+	s := &compactionScheduler{
+		stores:         []*compactableStore{newStore(StateDBStore), newStore(HistoryDBStore)},
+		window:         window,
+		interval:       24 * time.Hour,
+		stats:          newStats(fakeProvider),
+		now:            func() time.Time { return now },
+		lastCompaction: map[string]time.Time{},
+	}
+
+	// no compaction outside of the window
+	s.compactDueStores()
+	require.Empty(t, compactions)
+
+	now = time.Date(2020, 6, 2, 2, 30, 0, 0, time.Local)
+	s.compactDueStores()
+	require.Equal(t, []string{StateDBStore, HistoryDBStore}, compactions)
+	require.Equal(t, []string{"store", StateDBStore}, fakeCounter.WithArgsForCall(0))
+	require.Equal(t, float64(60), fakeCounter.AddArgsForCall(0))
+	require.Equal(t, float64(40), fakeGauge.SetArgsForCall(0))
+	require.Equal(t, 2, fakeHist.ObserveCallCount())
+
+	// the stores compacted less than the interval ago are skipped
+	now = now.Add(time.Hour)
+	compactions = nil
+	s.compactDueStores()
+	require.Empty(t, compactions)
+
+	// a failed compaction does not prevent the compaction of the other stores
+	now = time.Date(2020, 6, 3, 2, 30, 0, 0, time.Local)
+	compactionErr = errors.New("leveldb: closed")
+	s.compactDueStores()
+	require.Equal(t, []string{StateDBStore, HistoryDBStore}, compactions)
+
+	_, err = s.compact(StateDBStore)
+	require.EqualError(t, err, "failed to compact store [statedb]: leveldb: closed")
+
+	// the scheduler stops on shutdown
+	s.start()
+	s.shutdown()
+}
@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// CompressBlockFiles compresses the block files of all the channels.
+// The block storage index is dropped and rebuilt upon server restart.
+func CompressBlockFiles(rootFSPath string) error {
+	fileLockPath := fileLockPath(rootFSPath)
+	fileLock := leveldbhelper.NewFileLock(fileLockPath)
+	if err := fileLock.Lock(); err != nil {
+		return errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	blockstorePath := BlockStorePath(rootFSPath)
+	logger.Infof("Compressing the block files at location [%s]", blockstorePath)
+	if err := blkstorage.CompressBlockStore(blockstorePath); err != nil {
+		return err
+	}
+	logger.Info("The block files of all channels have been successfully compressed")
+	return nil
+}
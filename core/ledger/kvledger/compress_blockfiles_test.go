@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressBlockFiles(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+
+	genesisBlock, err := configtxtest.MakeGenesisBlock(constructTestLedgerID(0))
+	require.NoError(t, err)
+	_, err = provider.Create(genesisBlock)
+	require.NoError(t, err)
+
+	// compression should fail when provider is still open
+	err = CompressBlockFiles(conf.RootFSPath)
+	require.Error(t, err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying")
+	provider.Close()
+
+	require.NoError(t, CompressBlockFiles(conf.RootFSPath))
+
+	provider = testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+	lgr, err := provider.Open(constructTestLedgerID(0))
+	require.NoError(t, err)
+	defer lgr.Close()
+	block, err := lgr.GetBlockByNumber(0)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(genesisBlock, block))
+}
@@ -0,0 +1,25 @@
+// +build !windows
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// availableDiskSpace returns the number of bytes available to the peer on the
+// file system holding the given path
+func availableDiskSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, errors.Wrapf(err, "failed to retrieve the free space of the file system at [%s]", path)
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
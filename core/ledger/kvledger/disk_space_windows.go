@@ -0,0 +1,18 @@
+// +build windows
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/pkg/errors"
+)
+
+// availableDiskSpace is not supported on windows
+func availableDiskSpace(path string) (uint64, error) {
+	return 0, errors.Errorf("retrieving the free space of the file system at [%s] is not supported on windows", path)
+}
@@ -11,12 +11,14 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
 	protoutil "github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 )
 
 var logger = flogging.MustGetLogger("history")
@@ -52,15 +54,36 @@ func (p *DBProvider) GetDBHandle(name string) (*DB, error) {
 		nil
 }
 
+// Compact compacts the underlying db, which holds the history of all the channels
+func (p *DBProvider) Compact() error {
+	return p.leveldbProvider.Compact()
+}
+
 // Close closes the underlying db
 func (p *DBProvider) Close() {
 	p.leveldbProvider.Close()
 }
 
+// NamespaceFilter selects the namespaces the history of the keys is
+// indexed for.
+type NamespaceFilter interface {
+	// Indexed returns whether the history of the keys of the namespace is
+	// indexed.
+	Indexed(namespace string) (bool, error)
+}
+
 // DB maintains and provides access to history data for a particular channel
 type DB struct {
 	levelDB *leveldbhelper.DBHandle
 	name    string
+	filter  NamespaceFilter
+}
+
+// SetNamespaceFilter restricts the namespaces the history of the keys is
+// indexed for in the blocks committed from now on. Without a filter, the
+// history of the keys of all the namespaces is indexed.
+func (d *DB) SetNamespaceFilter(filter NamespaceFilter) {
+	d.filter = filter
 }
 
 // Commit implements method in HistoryDB interface
@@ -72,12 +95,39 @@ func (d *DB) Commit(block *common.Block) error {
 
 	dbBatch := d.levelDB.NewUpdateBatch()
 
+	// the keys updated in the blocks are indexed from the first block committed by a
+	// version of the history database maintaining the index
+	indexStart, err := d.levelDB.Get(updatedKeysIndexStartKey)
+	if err != nil {
+		return err
+	}
+	if indexStart == nil {
+		dbBatch.Put(updatedKeysIndexStartKey, util.EncodeOrderPreservingVarUint64(blockNo))
+	}
+
 	logger.Debugf("Channel [%s]: Updating history database for blockNo [%v] with [%d] transactions",
 		d.name, blockNo, len(block.Data.Data))
 
 	// Get the invalidation byte array for the block
 	txsFilter := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 
+	// the namespaces are looked up once per block
+	indexed := map[string]bool{}
+	isIndexed := func(ns string) (bool, error) {
+		if d.filter == nil {
+			return true, nil
+		}
+		if ok, seen := indexed[ns]; seen {
+			return ok, nil
+		}
+		ok, err := d.filter.Indexed(ns)
+		if err != nil {
+			return false, errors.WithMessagef(err, "could not determine whether the history of namespace %s is indexed", ns)
+		}
+		indexed[ns] = ok
+		return ok, nil
+	}
+
 	// write each tran's write set to history db
 	for _, envBytes := range block.Data.Data {
 
@@ -117,11 +167,19 @@ func (d *DB) Commit(block *common.Block) error {
 			// add a history record for each write
 			for _, nsRWSet := range txRWSet.NsRwSets {
 				ns := nsRWSet.NameSpace
+				ok, err := isIndexed(ns)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
 
 				for _, kvWrite := range nsRWSet.KvRwSet.Writes {
 					dataKey := constructDataKey(ns, kvWrite.Key, blockNo, tranNo)
 					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
 					dbBatch.Put(dataKey, emptyValue)
+					dbBatch.Put(constructUpdatedKey(ns, blockNo, kvWrite.Key), emptyValue)
 				}
 			}
 
@@ -180,6 +238,12 @@ func (d *DB) Name() string {
 	return "history"
 }
 
+// BackupSnapshot returns a point-in-time view of the history of the channel for a backup.
+// The caller is expected to ensure that no block is committed while the snapshot is taken.
+func (d *DB) BackupSnapshot() (*leveldbhelper.Snapshot, error) {
+	return d.levelDB.GetSnapshot()
+}
+
 // CommitLostBlock implements method in interface kvledger.Recoverer
 func (d *DB) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error {
 	block := blockAndPvtdata.Block
@@ -8,6 +8,7 @@ package history
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -22,6 +23,7 @@ import (
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
@@ -210,6 +212,132 @@ func TestHistory(t *testing.T) {
 	})
 }
 
+type namespaceFilter struct {
+	indexed map[string]bool
+	err     error
+	lookups []string
+}
+
+func (f *namespaceFilter) Indexed(namespace string) (bool, error) {
+	f.lookups = append(f.lookups, namespace)
+	return f.indexed[namespace], f.err
+}
+
+func TestHistoryNamespaceFilter(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	store, err := env.testBlockStorageEnv.provider.Open("ledger1")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	require.NoError(t, store.AddBlock(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb))
+
+	simulationResults := [][]byte{}
+	for _, ns := range []string{"ns1", "ns2", "ns1"} {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		simulator.SetState(ns, "key1", []byte(ns+"-value"))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		simulationResults = append(simulationResults, pubSimResBytes)
+	}
+	block1 := bg.NextBlock(simulationResults)
+	require.NoError(t, store.AddBlock(block1))
+
+	filter := &namespaceFilter{indexed: map[string]bool{"ns1": true}}
+	env.testHistoryDB.SetNamespaceFilter(filter)
+	require.NoError(t, env.testHistoryDB.Commit(block1))
+	// the namespaces are looked up once per block
+	assert.Equal(t, []string{"ns1", "ns2"}, filter.lookups)
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store)
+	require.NoError(t, err)
+	testutilVerifyResults(t, qhistory, "ns1", "key1", []string{"ns1-value", "ns1-value"})
+	testutilVerifyResults(t, qhistory, "ns2", "key1", []string{})
+	savepoint, err := env.testHistoryDB.GetLastSavepoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), savepoint.BlockNum)
+
+	env.testHistoryDB.SetNamespaceFilter(&namespaceFilter{err: errors.New("boom")})
+	err = env.testHistoryDB.Commit(bg.NextBlock(simulationResults))
+	assert.EqualError(t, err, "could not determine whether the history of namespace ns1 is indexed: boom")
+}
+
+func TestGetUpdatedKeysBetweenBlocks(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	store, err := env.testBlockStorageEnv.provider.Open("ledger1")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store)
+	require.NoError(t, err)
+	keys, err := qhistory.GetUpdatedKeysBetweenBlocks("ns1", 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	require.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// each transaction writes the keys given as namespace and key pairs
+	commitWrites := func(txs ...[]string) {
+		var simulationResults [][]byte
+		for _, tx := range txs {
+			simulator, err := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+			require.NoError(t, err)
+			for i := 0; i < len(tx); i += 2 {
+				require.NoError(t, simulator.SetState(tx[i], tx[i+1], []byte("value")))
+			}
+			simulator.Done()
+			simRes, err := simulator.GetTxSimulationResults()
+			require.NoError(t, err)
+			pubSimResBytes, err := simRes.GetPubSimulationBytes()
+			require.NoError(t, err)
+			simulationResults = append(simulationResults, pubSimResBytes)
+		}
+		require.NoError(t, env.testHistoryDB.Commit(bg.NextBlock(simulationResults)))
+	}
+	commitWrites([]string{"ns1", "key1"}, []string{"ns1", "key2"})                // block 1
+	commitWrites([]string{"ns1", "key3", "ns1", "key2"}, []string{"ns2", "key4"}) // block 2
+	commitWrites([]string{"ns1", "key\x00with-nil"})                              // block 3
+
+	tests := []struct {
+		namespace            string
+		startBlock, endBlock uint64
+		expectedKeys         []string
+	}{
+		{"ns1", 0, 4, []string{"key\x00with-nil", "key1", "key2", "key3"}},
+		{"ns1", 1, 2, []string{"key1", "key2"}},
+		{"ns1", 2, 3, []string{"key2", "key3"}},
+		{"ns1", 2, 2, nil},
+		{"ns2", 0, 100, []string{"key4"}},
+		{"ns1", 4, 100, nil},
+		{"ns3", 0, 4, nil},
+	}
+	for _, test := range tests {
+		keys, err := qhistory.GetUpdatedKeysBetweenBlocks(test.namespace, test.startBlock, test.endBlock)
+		require.NoError(t, err)
+		if test.expectedKeys == nil {
+			require.Empty(t, keys, "%s [%d, %d)", test.namespace, test.startBlock, test.endBlock)
+		} else {
+			require.Equal(t, test.expectedKeys, keys, "%s [%d, %d)", test.namespace, test.startBlock, test.endBlock)
+		}
+	}
+
+	_, err = qhistory.GetUpdatedKeysBetweenBlocks("ns1", 3, 2)
+	require.EqualError(t, err, "start block [3] is greater than end block [2]")
+
+	// the keys updated before the index was introduced cannot be retrieved
+	require.NoError(t, env.testHistoryDB.levelDB.Put(updatedKeysIndexStartKey, util.EncodeOrderPreservingVarUint64(2), true))
+	_, err = qhistory.GetUpdatedKeysBetweenBlocks("ns1", 1, 3)
+	require.EqualError(t, err, "the keys updated before block [2] are not indexed, the history database must be rebuilt to retrieve them")
+	keys, err = qhistory.GetUpdatedKeysBetweenBlocks("ns1", 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key2", "key3"}, keys)
+}
+
 func TestHistoryForInvalidTran(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
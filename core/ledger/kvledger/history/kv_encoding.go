@@ -23,6 +23,11 @@ var (
 	dataKeyPrefix   = []byte{'d'}  // prefix added to dataKeys
 	savePointKey    = []byte{'s'}  // a single key in db for persisting savepoint
 	emptyValue      = []byte{}     // used to store as value for keys where only key needs to be stored (e.g., dataKeys)
+	// prefix added to the keys of the index of the keys updated in each block, which
+	// cannot start a namespace
+	updatedKeyPrefix = []byte{0x01}
+	// a single key in db for persisting the number of the first block of the index of updated keys
+	updatedKeysIndexStartKey = []byte{'u'}
 )
 
 // constructDataKey builds the key of the format namespace~len(key)~key~blocknum~trannum
@@ -74,3 +79,39 @@ func (r *rangeScan) decodeBlockNumTranNum(dataKey dataKey) (uint64, uint64, erro
 	}
 	return blockNum, tranNum, nil
 }
+
+// constructUpdatedKey builds the key of the format 0x01~namespace~blocknum~key recording
+// that the key is updated in the block, so that the keys updated in a range of blocks
+// are found with a range scan
+func constructUpdatedKey(ns string, blocknum uint64, key string) []byte {
+	k := append(updatedKeysNsPrefix(ns), util.EncodeOrderPreservingVarUint64(blocknum)...)
+	return append(k, []byte(key)...)
+}
+
+// constructUpdatedKeysRangeScan returns the start and end keys for performing a range
+// scan that covers the keys of the namespace updated in the blocks [startBlock, endBlock).
+// startKey = 0x01~namespace~startBlock
+// endKey = 0x01~namespace~endBlock
+func constructUpdatedKeysRangeScan(ns string, startBlock, endBlock uint64) *rangeScan {
+	prefix := updatedKeysNsPrefix(ns)
+	return &rangeScan{
+		startKey: append(append([]byte{}, prefix...), util.EncodeOrderPreservingVarUint64(startBlock)...),
+		endKey:   append(prefix, util.EncodeOrderPreservingVarUint64(endBlock)...),
+	}
+}
+
+// decodeUpdatedKey returns the key recorded by an updated key of the namespace
+func decodeUpdatedKey(ns string, updatedKey []byte) (string, error) {
+	blockNumKey := bytes.TrimPrefix(updatedKey, updatedKeysNsPrefix(ns))
+	_, blockBytesConsumed, err := util.DecodeOrderPreservingVarUint64(blockNumKey)
+	if err != nil {
+		return "", err
+	}
+	return string(blockNumKey[blockBytesConsumed:]), nil
+}
+
+func updatedKeysNsPrefix(ns string) []byte {
+	k := append([]byte{}, updatedKeyPrefix...)
+	k = append(k, []byte(ns)...)
+	return append(k, compositeKeySep...)
+}
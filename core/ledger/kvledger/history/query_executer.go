@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package history
 
 import (
+	"sort"
+
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	protoutil "github.com/hyperledger/fabric/protoutil"
@@ -41,6 +44,55 @@ func (q *QueryExecutor) GetHistoryForKey(namespace string, key string) (commonle
 	return &historyScanner{rangeScan, namespace, key, dbItr, q.blockStore}, nil
 }
 
+// GetUpdatedKeysBetweenBlocks implements method in interface `ledger.HistoryQueryExecutor`
+func (q *QueryExecutor) GetUpdatedKeysBetweenBlocks(namespace string, startBlock, endBlock uint64) ([]string, error) {
+	if startBlock > endBlock {
+		return nil, errors.Errorf("start block [%d] is greater than end block [%d]", startBlock, endBlock)
+	}
+	indexStartBytes, err := q.levelDB.Get(updatedKeysIndexStartKey)
+	if err != nil {
+		return nil, err
+	}
+	if indexStartBytes == nil || startBlock == endBlock {
+		// no block is committed yet
+		return nil, nil
+	}
+	indexStart, _, err := util.DecodeOrderPreservingVarUint64(indexStartBytes)
+	if err != nil {
+		return nil, err
+	}
+	if startBlock < indexStart {
+		return nil, errors.Errorf("the keys updated before block [%d] are not indexed, the history database must be rebuilt to retrieve them", indexStart)
+	}
+
+	rangeScan := constructUpdatedKeysRangeScan(namespace, startBlock, endBlock)
+	dbItr, err := q.levelDB.GetIterator(rangeScan.startKey, rangeScan.endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer dbItr.Release()
+
+	updated := map[string]struct{}{}
+	for dbItr.Next() {
+		key, err := decodeUpdatedKey(namespace, dbItr.Key())
+		if err != nil {
+			return nil, err
+		}
+		updated[key] = struct{}{}
+	}
+	if err := dbItr.Error(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan the keys of namespace %s updated in blocks [%d, %d)", namespace, startBlock, endBlock)
+	}
+
+	keys := make([]string, 0, len(updated))
+	for key := range updated {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	logger.Debugf("Found %d keys of namespace %s updated in blocks [%d, %d)", len(keys), namespace, startBlock, endBlock)
+	return keys, nil
+}
+
 //historyScanner implements ResultsIterator for iterating through history results
 type historyScanner struct {
 	rangeScan  *rangeScan
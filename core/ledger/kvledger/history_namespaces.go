@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+// historyNamespaceFilter selects the namespaces of a channel the history
+// database indexes. The namespaces listed in the configuration of the peer
+// are enabled or disabled as listed. Otherwise, the history of a chaincode
+// namespace is enabled or disabled by the metadata of the chaincode
+// definition, and defaults to the configuration of the peer.
+type historyNamespaceFilter struct {
+	ledgerID     string
+	config       *ledger.HistoryDBConfig
+	infoProvider ledger.DeployedChaincodeInfoProvider
+	// newQueryExecutor returns a query executor on the committed state
+	newQueryExecutor func() (ledger.QueryExecutor, error)
+}
+
+func (f *historyNamespaceFilter) Indexed(namespace string) (bool, error) {
+	for _, ns := range f.config.EnabledNamespaces {
+		if ns == namespace {
+			return true, nil
+		}
+	}
+	for _, ns := range f.config.DisabledNamespaces {
+		if ns == namespace {
+			return false, nil
+		}
+	}
+
+	if f.infoProvider != nil {
+		qe, err := f.newQueryExecutor()
+		if err != nil {
+			return false, err
+		}
+		defer qe.Done()
+		info, err := f.infoProvider.ChaincodeInfo(f.ledgerID, namespace, qe)
+		if err != nil {
+			return false, err
+		}
+		if info != nil {
+			switch string(info.Metadata[ledger.HistoryMetadataKey]) {
+			case ledger.HistoryEnabled:
+				return true, nil
+			case ledger.HistoryDisabled:
+				return false, nil
+			}
+		}
+	}
+
+	return !f.config.DisabledByDefault, nil
+}
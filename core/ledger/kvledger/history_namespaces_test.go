@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryNamespaceFilter(t *testing.T) {
+	infoProvider := &mock.DeployedChaincodeInfoProvider{}
+	infoProvider.ChaincodeInfoStub = func(channelName, chaincodeName string, qe ledger.SimpleQueryExecutor) (*ledger.DeployedChaincodeInfo, error) {
+		switch chaincodeName {
+		case "enabled-cc", "listed-disabled-cc":
+			return &ledger.DeployedChaincodeInfo{Name: chaincodeName, Metadata: map[string][]byte{"history": []byte("enabled")}}, nil
+		case "disabled-cc", "listed-enabled-cc":
+			return &ledger.DeployedChaincodeInfo{Name: chaincodeName, Metadata: map[string][]byte{"history": []byte("disabled")}}, nil
+		case "plain-cc":
+			return &ledger.DeployedChaincodeInfo{Name: chaincodeName}, nil
+		case "broken-cc":
+			return nil, errors.New("boom")
+		}
+		return nil, nil
+	}
+	queryExecutor := &mock.QueryExecutor{}
+
+	filter := &historyNamespaceFilter{
+		ledgerID: "mychannel",
+		config: &ledger.HistoryDBConfig{
+			Enabled:            true,
+			EnabledNamespaces:  []string{"listed-enabled-cc"},
+			DisabledNamespaces: []string{"listed-disabled-cc"},
+		},
+		infoProvider: infoProvider,
+		newQueryExecutor: func() (ledger.QueryExecutor, error) {
+			return queryExecutor, nil
+		},
+	}
+
+	for _, tc := range []struct {
+		namespace         string
+		indexed           bool
+		disabledByDefault bool
+	}{
+		{namespace: "listed-enabled-cc", indexed: true},
+		{namespace: "listed-disabled-cc", indexed: false},
+		{namespace: "enabled-cc", indexed: true},
+		{namespace: "enabled-cc", indexed: true, disabledByDefault: true},
+		{namespace: "disabled-cc", indexed: false},
+		{namespace: "plain-cc", indexed: true},
+		{namespace: "plain-cc", indexed: false, disabledByDefault: true},
+		{namespace: "_lifecycle", indexed: true},
+		{namespace: "_lifecycle", indexed: false, disabledByDefault: true},
+	} {
+		filter.config.DisabledByDefault = tc.disabledByDefault
+		indexed, err := filter.Indexed(tc.namespace)
+		require.NoError(t, err)
+		require.Equal(t, tc.indexed, indexed, "namespace %s, disabled by default %t", tc.namespace, tc.disabledByDefault)
+	}
+
+	channelName, _, qe := infoProvider.ChaincodeInfoArgsForCall(0)
+	require.Equal(t, "mychannel", channelName)
+	require.Equal(t, queryExecutor, qe)
+	require.Equal(t, infoProvider.ChaincodeInfoCallCount(), queryExecutor.DoneCallCount())
+
+	_, err := filter.Indexed("broken-cc")
+	require.EqualError(t, err, "boom")
+
+	filter.newQueryExecutor = func() (ledger.QueryExecutor, error) {
+		return nil, errors.New("closed")
+	}
+	_, err = filter.Indexed("plain-cc")
+	require.EqualError(t, err, "closed")
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
@@ -56,6 +57,23 @@ type kvLedger struct {
 	commitHash             []byte
 	hashProvider           ledger.HashProvider
 	snapshotsConfig        *ledger.SnapshotsConfig
+	// stateDB, bookkeeperProvider and backupsConfig are used for the
+	// online backups of the ledger
+	stateDB            *privacyenabledstate.DB
+	bookkeeperProvider bookkeeping.Provider
+	backupsConfig      *ledger.BackupsConfig
+	// rebuildProgressDB and rebuildProgressListener track the rebuild of the
+	// state and history databases from the block store
+	rebuildProgressDB       *leveldbhelper.DBHandle
+	rebuildProgressListener ledger.RebuildProgressListener
+	// commitJournal records the progress of the commit of the last block to
+	// the ledger stores and drives the recovery of an interrupted commit
+	commitJournal *commitJournal
+	// commitListeners are notified of the committed blocks, and the private data
+	// delivered to them is filtered as per the eligibility of the peer
+	commitListeners        []ledger.CommitListener
+	ccInfoProvider         ledger.DeployedChaincodeInfoProvider
+	membershipInfoProvider ledger.MembershipInfoProvider
 	// isPvtDataStoreAheadOfBlockStore is read during missing pvtData
 	// reconciliation and may be updated during a regular block commit.
 	// Hence, we use atomic value to ensure consistent read.
@@ -68,6 +86,7 @@ type lgrInitializer struct {
 	pvtdataStore             *pvtdatastorage.Store
 	stateDB                  *privacyenabledstate.DB
 	historyDB                *history.DB
+	historyDBConfig          *ledger.HistoryDBConfig
 	configHistoryMgr         *confighistory.Mgr
 	stateListeners           []ledger.StateListener
 	bookkeeperProvider       bookkeeping.Provider
@@ -75,8 +94,14 @@ type lgrInitializer struct {
 	ccLifecycleEventProvider ledger.ChaincodeLifecycleEventProvider
 	stats                    *ledgerStats
 	customTxProcessors       map[common.HeaderType]ledger.CustomTxProcessor
+	counterCapability        ledger.CounterCapabilityProvider
 	hashProvider             ledger.HashProvider
 	snapshotsConfig          *ledger.SnapshotsConfig
+	backupsConfig            *ledger.BackupsConfig
+	rangeScanCursorsConfig   *ledger.RangeScanCursorsConfig
+	rebuildProgressListener  ledger.RebuildProgressListener
+	commitListeners          []ledger.CommitListener
+	membershipInfoProvider   ledger.MembershipInfoProvider
 }
 
 func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
@@ -90,6 +115,19 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		hashProvider:    initializer.hashProvider,
 		snapshotsConfig: initializer.snapshotsConfig,
 		blockAPIsRWLock: &sync.RWMutex{},
+		stats:           initializer.stats,
+
+		stateDB:            initializer.stateDB,
+		bookkeeperProvider: initializer.bookkeeperProvider,
+		backupsConfig:      initializer.backupsConfig,
+
+		rebuildProgressDB:       initializer.bookkeeperProvider.GetDBHandle(ledgerID, bookkeeping.RebuildProgress),
+		rebuildProgressListener: initializer.rebuildProgressListener,
+		commitJournal:           newCommitJournal(initializer.bookkeeperProvider.GetDBHandle(ledgerID, bookkeeping.CommitJournal)),
+
+		commitListeners:        initializer.commitListeners,
+		ccInfoProvider:         initializer.ccInfoProvider,
+		membershipInfoProvider: initializer.membershipInfoProvider,
 	}
 
 	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(&collectionInfoRetriever{ledgerID, l, initializer.ccInfoProvider})
@@ -113,12 +151,23 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		BookkeepingProvider: initializer.bookkeeperProvider,
 		CCInfoProvider:      initializer.ccInfoProvider,
 		CustomTxProcessors:  initializer.customTxProcessors,
+		CounterCapability:   initializer.counterCapability,
 		HashFunc:            rwsetHashFunc,
+		RangeScanCursors:    initializer.rangeScanCursorsConfig,
 	}
 	if err := l.initTxMgr(txmgrInitializer); err != nil {
 		return nil, err
 	}
 
+	if l.historyDB != nil && initializer.historyDBConfig != nil {
+		l.historyDB.SetNamespaceFilter(&historyNamespaceFilter{
+			ledgerID:         ledgerID,
+			config:           initializer.historyDBConfig,
+			infoProvider:     initializer.ccInfoProvider,
+			newQueryExecutor: l.txmgr.NewQueryExecutorNoCollChecks,
+		})
+	}
+
 	// btlPolicy internally uses queryexecuter and indirectly ends up using txmgr.
 	// Hence, we need to init the pvtdataStore once the txmgr is initiated.
 	l.pvtdataStore.Init(btlPolicy)
@@ -141,8 +190,14 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 	ccEventListener := initializer.stateDB.GetChaincodeEventListener()
 	logger.Debugf("Register state db for chaincode lifecycle events: %t", ccEventListener != nil)
 	if ccEventListener != nil {
-		cceventmgmt.GetMgr().Register(ledgerID, ccEventListener)
-		initializer.ccLifecycleEventProvider.RegisterListener(ledgerID, &ccEventListenerAdaptor{ccEventListener})
+		// the leveldb state db listens for the composite key families of the chaincodes,
+		// even where the ledger is opened without chaincode lifecycle events
+		if ccEventMgr := cceventmgmt.GetMgr(); ccEventMgr != nil {
+			ccEventMgr.Register(ledgerID, ccEventListener)
+		}
+		if initializer.ccLifecycleEventProvider != nil {
+			initializer.ccLifecycleEventProvider.RegisterListener(ledgerID, &ccEventListenerAdaptor{ccEventListener})
+		}
 	}
 
 	//Recover both state DB and history DB if they are out of sync with block storage
@@ -150,8 +205,7 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		return nil, err
 	}
 	l.configHistoryRetriever = initializer.configHistoryMgr.GetRetriever(ledgerID, l)
-
-	l.stats = initializer.stats
+	l.stats.updateHydrationStatus(l.pvtdataStore.GetHydrationStatus())
 	return l, nil
 }
 
@@ -220,6 +274,9 @@ func (l *kvLedger) isPvtDataStoreAheadOfBlockStore() (bool, error) {
 
 func (l *kvLedger) recoverDBs() error {
 	logger.Debugf("Entering recoverDB()")
+	if err := l.recoverFromCommitJournal(); err != nil {
+		return err
+	}
 	if err := l.syncStateAndHistoryDBWithBlockstore(); err != nil {
 		return err
 	}
@@ -229,6 +286,59 @@ func (l *kvLedger) recoverDBs() error {
 	return nil
 }
 
+// recoverFromCommitJournal completes the commit of the last block when it was interrupted
+// by a crash, recommitting the block to the databases which lack it according to the
+// commit journal. Larger gaps, such as databases dropped for a rebuild, are left to
+// syncStateAndHistoryDBWithBlockstore.
+func (l *kvLedger) recoverFromCommitJournal() error {
+	entry, err := l.commitJournal.retrieve()
+	if err != nil {
+		return err
+	}
+	if entry == nil || entry.complete() {
+		logger.Debugf("No interrupted commit in the commit journal of ledger [%s]", l.ledgerID)
+		return nil
+	}
+
+	report := &commitRecoveryReport{ledgerID: l.ledgerID, blockNum: entry.blockNum, reached: entry.reached}
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if info.Height <= entry.blockNum {
+		report.refetch = true
+	} else {
+		stages := map[commitStage]recoverable{stageStateDB: l.txmgr}
+		if l.historyDB != nil {
+			stages[stageHistoryDB] = l.historyDB
+		}
+		recoverables := []recoverable{}
+		for _, stage := range []commitStage{stageStateDB, stageHistoryDB} {
+			r, ok := stages[stage]
+			if !ok || entry.reached&stage != 0 {
+				continue
+			}
+			recoverFlag, firstBlockNum, err := r.ShouldRecover(entry.blockNum)
+			if err != nil {
+				return err
+			}
+			// a database lagging by more than the journaled block is not the result of
+			// the interrupted commit and is synced with the block store afterwards
+			if recoverFlag && firstBlockNum == entry.blockNum {
+				recoverables = append(recoverables, r)
+				report.recommitted |= stage
+			}
+		}
+		if len(recoverables) > 0 {
+			if err := l.recommitLostBlocks(nil, entry.blockNum, entry.blockNum, recoverables...); err != nil {
+				return err
+			}
+		}
+	}
+	logger.Info(report)
+	return l.commitJournal.resolve(entry)
+}
+
 func (l *kvLedger) syncStateAndHistoryDBWithBlockstore() error {
 	//If there is no block in blockstorage, nothing to recover.
 	info, _ := l.blockStore.GetBlockchainInfo()
@@ -269,24 +379,61 @@ func (l *kvLedger) syncStateAndHistoryDBWithBlockstore() error {
 	if len(recoverers) == 0 {
 		return nil
 	}
+	if len(recoverers) == 2 && recoverers[0].firstBlockNum > recoverers[1].firstBlockNum {
+		// swap (put the lagger db at 0 index)
+		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
+	}
+
+	tracker, err := newRebuildTracker(l.ledgerID, l.rebuildProgressDB, l.stats, l.rebuildProgressListener,
+		recoverers[0].firstBlockNum, lastAvailableBlockNum)
+	if err != nil {
+		return err
+	}
+	bulkLoad, err := l.startStateBulkLoad(recoverers)
+	if err != nil {
+		return err
+	}
+	err = l.recommitRecoverers(recoverers, lastAvailableBlockNum, tracker)
+	if bulkLoad {
+		if endErr := l.txmgr.EndBulkLoad(); err == nil {
+			err = endErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if tracker != nil {
+		return tracker.done()
+	}
+	return nil
+}
+
+// startStateBulkLoad starts a bulk load of the state database when it is
+// rebuilt from the genesis block, as after a rebuild or an upgrade of the dbs
+func (l *kvLedger) startStateBulkLoad(recoverers []*recoverer) (bool, error) {
+	for _, r := range recoverers {
+		if r.recoverable == recoverable(l.txmgr) && r.firstBlockNum == 0 {
+			return l.txmgr.StartBulkLoad()
+		}
+	}
+	return false, nil
+}
+
+func (l *kvLedger) recommitRecoverers(recoverers []*recoverer, lastAvailableBlockNum uint64, tracker *rebuildTracker) error {
 	if len(recoverers) == 1 {
-		return l.recommitLostBlocks(recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
+		return l.recommitLostBlocks(tracker, recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
 	}
 
 	// both dbs need to be recovered
-	if recoverers[0].firstBlockNum > recoverers[1].firstBlockNum {
-		// swap (put the lagger db at 0 index)
-		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
-	}
 	if recoverers[0].firstBlockNum != recoverers[1].firstBlockNum {
 		// bring the lagger db equal to the other db
-		if err := l.recommitLostBlocks(recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
+		if err := l.recommitLostBlocks(tracker, recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
 			recoverers[0].recoverable); err != nil {
 			return err
 		}
 	}
 	// get both the db upto block storage
-	return l.recommitLostBlocks(recoverers[1].firstBlockNum, lastAvailableBlockNum,
+	return l.recommitLostBlocks(tracker, recoverers[1].firstBlockNum, lastAvailableBlockNum,
 		recoverers[0].recoverable, recoverers[1].recoverable)
 }
 
@@ -332,8 +479,8 @@ func (l *kvLedger) filterYetToCommitBlocks(blocksPvtData map[uint64][]*ledger.Tx
 }
 
 //recommitLostBlocks retrieves blocks in specified range and commit the write set to either
-//state DB or history DB or both
-func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
+//state DB or history DB or both. The progress is recorded by the tracker, if any.
+func (l *kvLedger) recommitLostBlocks(tracker *rebuildTracker, firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
 	logger.Infof("Recommitting lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
 	var err error
 	var blockAndPvtdata *ledger.BlockAndPvtData
@@ -346,6 +493,11 @@ func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64,
 				return err
 			}
 		}
+		if tracker != nil {
+			if err := tracker.blockRecommitted(blockNumber); err != nil {
+				return err
+			}
+		}
 	}
 	logger.Infof("Recommitted lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
 	return nil
@@ -375,6 +527,43 @@ func (l *kvLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	return bcInfo, err
 }
 
+// GetCommittedStateInfo returns the height and the hash of the last block
+// committed to the state database. Unlike GetBlockchainInfo, it doesn't wait
+// for the commit in progress, so it can be called while a TxSimulator or a
+// QueryExecutor is held, in which case it identifies the state they see.
+func (l *kvLedger) GetCommittedStateInfo() (*common.BlockchainInfo, error) {
+	savepoint, err := l.txmgr.GetLastSavepoint()
+	if err != nil {
+		return nil, err
+	}
+	if savepoint == nil {
+		return &common.BlockchainInfo{}, nil
+	}
+	// the block store is committed ahead of the state database
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	switch bcInfo.Height {
+	case savepoint.BlockNum + 1:
+		return bcInfo, nil
+	case savepoint.BlockNum + 2:
+		return &common.BlockchainInfo{
+			Height:           savepoint.BlockNum + 1,
+			CurrentBlockHash: bcInfo.PreviousBlockHash,
+		}, nil
+	}
+	block, err := l.blockStore.RetrieveBlockByNumber(savepoint.BlockNum)
+	if err != nil {
+		return nil, err
+	}
+	return &common.BlockchainInfo{
+		Height:            savepoint.BlockNum + 1,
+		CurrentBlockHash:  protoutil.BlockHeaderHash(block.Header),
+		PreviousBlockHash: block.Header.PreviousHash,
+	}, nil
+}
+
 // GetBlockByNumber returns block at a given height
 // blockNumber of  math.MaxUint64 will return last block
 func (l *kvLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
@@ -442,7 +631,18 @@ func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error
 }
 
 // CommitLegacy commits the block and the corresponding pvt data in an atomic operation
+// and then notifies the commit listeners
 func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
+	if err := l.commit(pvtdataAndBlock, commitOpts); err != nil {
+		return err
+	}
+	// the listeners are notified once the block APIs are unlocked, so
+	// that they can query the ledger about the block just committed
+	l.notifyCommitListeners(pvtdataAndBlock)
+	return nil
+}
+
+func (l *kvLedger) commit(pvtdataAndBlock *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
 	var err error
 	block := pvtdataAndBlock.Block
 	blockNo := pvtdataAndBlock.Block.Header.Number
@@ -474,6 +674,7 @@ func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitO
 		return err
 	}
 	elapsedBlockProcessing := time.Since(startBlockProcessing)
+	stageTimes := &commitStageTimes{}
 
 	startBlockstorageAndPvtdataCommit := time.Now()
 	logger.Debugf("[%s] Adding CommitHash to the block [%d]", l.ledgerID, blockNo)
@@ -484,12 +685,23 @@ func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitO
 		l.addBlockCommitHash(pvtdataAndBlock.Block, updateBatchBytes)
 	}
 
+	expectedStages := stageBlockStore | stageStateDB
+	if l.historyDB != nil {
+		expectedStages |= stageHistoryDB
+	}
+
 	logger.Debugf("[%s] Committing pvtdata and block [%d] to storage", l.ledgerID, blockNo)
 	l.blockAPIsRWLock.Lock()
 	defer l.blockAPIsRWLock.Unlock()
-	if err = l.commitToPvtAndBlockStore(pvtdataAndBlock); err != nil {
+	// the journal is updated under the lock, so that a snapshot of the stores taken
+	// under the lock never has a commit in progress
+	if err = l.commitJournal.begin(blockNo, expectedStages); err != nil {
+		return err
+	}
+	if err = l.commitToPvtAndBlockStoreWithStageTimes(pvtdataAndBlock, stageTimes); err != nil {
 		return err
 	}
+	l.recordCommitStage(stageBlockStore)
 	elapsedBlockstorageAndPvtdataCommit := time.Since(startBlockstorageAndPvtdataCommit)
 
 	startCommitState := time.Now()
@@ -497,17 +709,26 @@ func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitO
 	if err = l.txmgr.Commit(); err != nil {
 		panic(errors.WithMessage(err, "error during commit to txmgr"))
 	}
+	l.recordCommitStage(stageStateDB)
 	elapsedCommitState := time.Since(startCommitState)
 
 	// History database could be written in parallel with state and/or async as a future optimization,
 	// although it has not been a bottleneck...no need to clutter the log with elapsed duration.
 	if l.historyDB != nil {
 		logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, blockNo)
+		startHistoryCommit := time.Now()
 		if err := l.historyDB.Commit(block); err != nil {
 			panic(errors.WithMessage(err, "Error during commit to history db"))
 		}
+		l.recordCommitStage(stageHistoryDB)
+		stageTimes.historyCommit = time.Since(startHistoryCommit)
 	}
 
+	txmgrStageTimes := l.txmgr.LastCommitStageTimes()
+	stageTimes.blockValidation = txmgrStageTimes.Validation
+	stageTimes.statedbPrep = txmgrStageTimes.StatedbPrep
+	stageTimes.statedbCommit = txmgrStageTimes.StatedbCommit
+
 	logger.Infof("[%s] Committed block [%d] with %d transaction(s) in %dms (state_validation=%dms block_and_pvtdata_commit=%dms state_commit=%dms)"+
 		" commitHash=[%x]",
 		l.ledgerID, block.Header.Number, len(block.Data.Data),
@@ -523,10 +744,23 @@ func (l *kvLedger) CommitLegacy(pvtdataAndBlock *ledger.BlockAndPvtData, commitO
 		elapsedCommitState,
 		txstatsInfo,
 	)
+	l.stats.updateCommitStageTimes(stageTimes)
 	return nil
 }
 
+// recordCommitStage records in the commit journal that the block being committed is
+// durable in a store. As the block is already in the block store, a failure is fatal.
+func (l *kvLedger) recordCommitStage(stage commitStage) {
+	if err := l.commitJournal.reached(stage); err != nil {
+		panic(errors.WithMessagef(err, "error recording the commit of block to the %s in the commit journal", stage))
+	}
+}
+
 func (l *kvLedger) commitToPvtAndBlockStore(blockAndPvtdata *ledger.BlockAndPvtData) error {
+	return l.commitToPvtAndBlockStoreWithStageTimes(blockAndPvtdata, &commitStageTimes{})
+}
+
+func (l *kvLedger) commitToPvtAndBlockStoreWithStageTimes(blockAndPvtdata *ledger.BlockAndPvtData, stageTimes *commitStageTimes) error {
 	pvtdataStoreHt, err := l.pvtdataStore.LastCommittedBlockHeight()
 	if err != nil {
 		return err
@@ -544,17 +778,22 @@ func (l *kvLedger) commitToPvtAndBlockStore(blockAndPvtdata *ledger.BlockAndPvtD
 		// transaction to become valid, we store the pvtdata of invalid transactions
 		// too in the pvtdataStore as we do for the publicdata in the case of blockStore.
 		// Hence, we pass all pvtData present in the block to the pvtdataStore committer.
+		startPvtdataCommit := time.Now()
 		pvtData, missingPvtData := constructPvtDataAndMissingData(blockAndPvtdata)
 		if err := l.pvtdataStore.Commit(blockNum, pvtData, missingPvtData); err != nil {
 			return err
 		}
+		stageTimes.pvtdataCommit = time.Since(startPvtdataCommit)
 	} else {
 		logger.Debugf("Skipping writing pvtData to pvt block store as it ahead of the block store")
 	}
 
-	if err := l.blockStore.AddBlock(blockAndPvtdata.Block); err != nil {
+	blockStageTimes, err := l.blockStore.AddBlockWithStageTimes(blockAndPvtdata.Block)
+	if err != nil {
 		return err
 	}
+	stageTimes.blockFileAppend = blockStageTimes.FileAppend
+	stageTimes.indexUpdate = blockStageTimes.IndexUpdate
 
 	if pvtdataStoreHt == blockNum+1 {
 		// Only when the pvtdataStore was ahead of blockStore
@@ -689,6 +928,7 @@ func (l *kvLedger) CommitPvtDataOfOldBlocks(reconciledPvtdata []*ledger.Reconcil
 	if err != nil {
 		return nil, err
 	}
+	l.stats.updateHydrationStatus(l.pvtdataStore.GetHydrationStatus())
 
 	return hashMismatches, nil
 }
@@ -717,6 +957,17 @@ func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, err
 	return l, nil
 }
 
+// GetPvtDataAntiEntropy returns the PvtDataAntiEntropy of the pvtdata store, which checks the
+// private data to reconcile against the blocks of the block store
+func (l *kvLedger) GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error) {
+	return &pvtDataAntiEntropy{Store: l.pvtdataStore, blockStore: l.blockStore}, nil
+}
+
+// CollectionPvtDataStats returns the amount of private data of a collection held by the pvtdata store
+func (l *kvLedger) CollectionPvtDataStats(ns, coll string) (*ledger.CollectionPvtDataStats, error) {
+	return l.pvtdataStore.CollectionPvtDataStats(ns, coll)
+}
+
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
 	l.blockStore.Shutdown()
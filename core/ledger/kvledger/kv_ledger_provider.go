@@ -57,6 +57,7 @@ var (
 		blkstorage.IndexableAttrBlockNum,
 		blkstorage.IndexableAttrTxID,
 		blkstorage.IndexableAttrBlockNumTranNum,
+		blkstorage.IndexableAttrBlockHashSM3,
 	}
 )
 
@@ -76,6 +77,7 @@ type Provider struct {
 	collElgNotifier      *collElgNotifier
 	stats                *stats
 	fileLock             *leveldbhelper.FileLock
+	compactionScheduler  *compactionScheduler
 }
 
 // NewProvider instantiates a new Provider.
@@ -128,6 +130,9 @@ func NewProvider(initializer *ledger.Initializer) (pr *Provider, e error) {
 		return nil, err
 	}
 	p.initLedgerStatistics()
+	if err := p.initCompactionScheduler(); err != nil {
+		return nil, err
+	}
 	p.recoverUnderConstructionLedger()
 	if err := p.initSnapshotDir(); err != nil {
 		return nil, err
@@ -146,11 +151,22 @@ func (p *Provider) initLedgerIDInventory() error {
 
 func (p *Provider) initBlockStoreProvider() error {
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
-	blkStoreProvider, err := blkstorage.NewProvider(
-		blkstorage.NewConf(
+	blkStoreConf := blkstorage.NewConf(
+		BlockStorePath(p.initializer.Config.RootFSPath),
+		maxBlockFileSize,
+	)
+	blockStoreConfig := p.initializer.Config.BlockStoreConfig
+	if blockStoreConfig != nil && blockStoreConfig.Compression {
+		blkStoreConf = blkstorage.NewConfWithCompression(
 			BlockStorePath(p.initializer.Config.RootFSPath),
 			maxBlockFileSize,
-		),
+		)
+	}
+	if blockStoreConfig != nil {
+		blkStoreConf.SetShards(blockStoreConfig.ShardPaths, blockStoreConfig.ShardChannels)
+	}
+	blkStoreProvider, err := blkstorage.NewProvider(
+		blkStoreConf,
 		indexConfig,
 		p.initializer.MetricsProvider,
 	)
@@ -354,6 +370,7 @@ func (p *Provider) open(ledgerID string) (ledger.PeerLedger, error) {
 		pvtdataStore:             pvtdataStore,
 		stateDB:                  db,
 		historyDB:                historyDB,
+		historyDBConfig:          p.initializer.Config.HistoryDBConfig,
 		configHistoryMgr:         p.configHistoryMgr,
 		stateListeners:           p.stateListeners,
 		bookkeeperProvider:       p.bookkeepingProvider,
@@ -361,8 +378,14 @@ func (p *Provider) open(ledgerID string) (ledger.PeerLedger, error) {
 		ccLifecycleEventProvider: p.initializer.ChaincodeLifecycleEventProvider,
 		stats:                    p.stats.ledgerStats(ledgerID),
 		customTxProcessors:       p.initializer.CustomTxProcessors,
+		counterCapability:        p.initializer.CounterCapabilityProvider,
 		hashProvider:             p.initializer.HashProvider,
 		snapshotsConfig:          p.initializer.Config.SnapshotsConfig,
+		backupsConfig:            p.initializer.Config.BackupsConfig,
+		rangeScanCursorsConfig:   p.initializer.Config.StateDBConfig.RangeScanCursors,
+		rebuildProgressListener:  p.initializer.RebuildProgressListener,
+		commitListeners:          p.initializer.CommitListeners,
+		membershipInfoProvider:   p.initializer.MembershipInfoProvider,
 	}
 
 	l, err := newKVLedger(initializer)
@@ -384,6 +407,9 @@ func (p *Provider) List() ([]string, error) {
 
 // Close implements the corresponding method from interface ledger.PeerLedgerProvider
 func (p *Provider) Close() {
+	if p.compactionScheduler != nil {
+		p.compactionScheduler.shutdown()
+	}
 	if p.idStore != nil {
 		p.idStore.close()
 	}
@@ -603,6 +603,9 @@ func testConfig(t *testing.T) (conf *lgr.Config, cleanup func()) {
 		SnapshotsConfig: &lgr.SnapshotsConfig{
 			RootDir: filepath.Join(path, "snapshots"),
 		},
+		BackupsConfig: &lgr.BackupsConfig{
+			RootDir: filepath.Join(path, "backups"),
+		},
 	}
 	cleanup = func() {
 		os.RemoveAll(path)
@@ -9,6 +9,7 @@ package kvledger
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -188,6 +189,55 @@ func TestAddCommitHash(t *testing.T) {
 
 }
 
+func TestGetCommittedStateInfo(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	gbHash := protoutil.BlockHeaderHash(gb.Header)
+	ledger, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer ledger.Close()
+	kvl := ledger.(*kvLedger)
+
+	stateInfo, err := kvl.GetCommittedStateInfo()
+	require.NoError(t, err)
+	require.Equal(t, &common.BlockchainInfo{Height: 1, CurrentBlockHash: gbHash}, stateInfo)
+
+	block1 := bg.NextBlock([][]byte{})
+	require.NoError(t, ledger.CommitLegacy(&lgr.BlockAndPvtData{Block: block1}, &lgr.CommitOptions{}))
+	block1Hash := protoutil.BlockHeaderHash(block1.Header)
+	stateInfo, err = kvl.GetCommittedStateInfo()
+	require.NoError(t, err)
+	require.Equal(t, &common.BlockchainInfo{Height: 2, CurrentBlockHash: block1Hash, PreviousBlockHash: gbHash}, stateInfo)
+
+	// the commit of the next block waits for the simulator to be done once
+	// the block is added to the block store
+	simulator, err := ledger.NewTxSimulator(util.GenerateUUID())
+	require.NoError(t, err)
+	block2 := bg.NextBlock([][]byte{})
+	committed := make(chan error, 1)
+	go func() {
+		committed <- ledger.CommitLegacy(&lgr.BlockAndPvtData{Block: block2}, &lgr.CommitOptions{})
+	}()
+	require.Eventually(t, func() bool {
+		bcInfo, err := kvl.blockStore.GetBlockchainInfo()
+		return err == nil && bcInfo.Height == 3
+	}, 10*time.Second, 10*time.Millisecond)
+
+	stateInfo, err = kvl.GetCommittedStateInfo()
+	require.NoError(t, err)
+	require.Equal(t, &common.BlockchainInfo{Height: 2, CurrentBlockHash: block1Hash}, stateInfo)
+
+	simulator.Done()
+	require.NoError(t, <-committed)
+	stateInfo, err = kvl.GetCommittedStateInfo()
+	require.NoError(t, err)
+	require.Equal(t, &common.BlockchainInfo{Height: 3, CurrentBlockHash: protoutil.BlockHeaderHash(block2.Header), PreviousBlockHash: block1Hash}, stateInfo)
+}
+
 func TestKVLedgerBlockStorageWithPvtdata(t *testing.T) {
 	t.Skip()
 	conf, cleanup := testConfig(t)
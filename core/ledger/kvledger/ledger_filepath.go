@@ -69,3 +69,13 @@ func SnapshotsDirForLedger(snapshotRootDir, ledgerID string) string {
 func SnapshotDirForLedgerHeight(snapshotRootDir, ledgerID string, snapshotHeight uint64) string {
 	return filepath.Join(SnapshotsDirForLedger(snapshotRootDir, ledgerID), strconv.FormatUint(snapshotHeight, 10))
 }
+
+// InProgressBackupsPath returns the dir path that is used temporarily during the backups of the ledgers
+func InProgressBackupsPath(backupsRootDir string) string {
+	return filepath.Join(backupsRootDir, "underConstruction")
+}
+
+// BackupDirForLedgerHeight returns the absolute path for the backup of a ledger at a particular height
+func BackupDirForLedgerHeight(backupsRootDir, ledgerID string, height uint64) string {
+	return filepath.Join(backupsRootDir, "completed", ledgerID, strconv.FormatUint(height, 10))
+}
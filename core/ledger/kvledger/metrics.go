@@ -10,14 +10,46 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
 )
 
 type stats struct {
 	blockProcessingTime            metrics.Histogram
 	blockAndPvtdataStoreCommitTime metrics.Histogram
 	statedbCommitTime              metrics.Histogram
+	commitStageTime                metrics.Histogram
 	transactionsCount              metrics.Counter
+	rebuildBlocksProcessed         metrics.Gauge
+	rebuildBlocksTotal             metrics.Gauge
+	rebuildETA                     metrics.Gauge
+	pvtdataHydrationPercent        metrics.Gauge
+	compactionTime                 metrics.Histogram
+	compactionReclaimedBytes       metrics.Counter
+	storeSizeBytes                 metrics.Gauge
+}
+
+// stages of the commit of a block reported by the commit_stage_time metric
+const (
+	blockValidationStage = "block_validation"
+	statedbPrepStage     = "statedb_prep"
+	statedbCommitStage   = "statedb_commit"
+	historyCommitStage   = "history_commit"
+	pvtdataCommitStage   = "pvtdata_commit"
+	blockFileAppendStage = "block_file_append"
+	indexUpdateStage     = "index_update"
+)
+
+// commitStageTimes breaks down the time taken to commit a block
+type commitStageTimes struct {
+	blockValidation time.Duration
+	statedbPrep     time.Duration
+	statedbCommit   time.Duration
+	historyCommit   time.Duration
+	pvtdataCommit   time.Duration
+	blockFileAppend time.Duration
+	indexUpdate     time.Duration
 }
 
 func newStats(metricsProvider metrics.Provider) *stats {
@@ -25,18 +57,35 @@ func newStats(metricsProvider metrics.Provider) *stats {
 	stats.blockProcessingTime = metricsProvider.NewHistogram(blockProcessingTimeOpts)
 	stats.blockAndPvtdataStoreCommitTime = metricsProvider.NewHistogram(blockAndPvtdataStoreCommitTimeOpts)
 	stats.statedbCommitTime = metricsProvider.NewHistogram(statedbCommitTimeOpts)
+	stats.commitStageTime = metricsProvider.NewHistogram(commitStageTimeOpts)
 	stats.transactionsCount = metricsProvider.NewCounter(transactionCountOpts)
+	stats.rebuildBlocksProcessed = metricsProvider.NewGauge(rebuildBlocksProcessedOpts)
+	stats.rebuildBlocksTotal = metricsProvider.NewGauge(rebuildBlocksTotalOpts)
+	stats.rebuildETA = metricsProvider.NewGauge(rebuildETAOpts)
+	stats.pvtdataHydrationPercent = metricsProvider.NewGauge(pvtdataHydrationPercentOpts)
+	stats.compactionTime = metricsProvider.NewHistogram(compactionTimeOpts)
+	stats.compactionReclaimedBytes = metricsProvider.NewCounter(compactionReclaimedBytesOpts)
+	stats.storeSizeBytes = metricsProvider.NewGauge(storeSizeBytesOpts)
 	return stats
 }
 
+func (s *stats) updateCompaction(result *CompactionResult) {
+	s.compactionTime.With("store", result.Store).Observe(result.DurationSeconds)
+	s.compactionReclaimedBytes.With("store", result.Store).Add(float64(result.ReclaimedBytes))
+	s.storeSizeBytes.With("store", result.Store).Set(float64(result.SizeAfterBytes))
+}
+
 type ledgerStats struct {
 	stats    *stats
 	ledgerid string
+	// hydratingColls are the collections reported as being lazily hydrated
+	hydratingColls map[[2]string]struct{}
 }
 
 func (s *stats) ledgerStats(ledgerid string) *ledgerStats {
 	return &ledgerStats{
-		s, ledgerid,
+		stats:    s,
+		ledgerid: ledgerid,
 	}
 }
 
@@ -52,6 +101,52 @@ func (s *ledgerStats) updateStatedbCommitTime(timeTaken time.Duration) {
 	s.stats.statedbCommitTime.With("channel", s.ledgerid).Observe(timeTaken.Seconds())
 }
 
+func (s *ledgerStats) updateCommitStageTimes(stageTimes *commitStageTimes) {
+	for _, stage := range []struct {
+		name      string
+		timeTaken time.Duration
+	}{
+		{blockValidationStage, stageTimes.blockValidation},
+		{statedbPrepStage, stageTimes.statedbPrep},
+		{statedbCommitStage, stageTimes.statedbCommit},
+		{historyCommitStage, stageTimes.historyCommit},
+		{pvtdataCommitStage, stageTimes.pvtdataCommit},
+		{blockFileAppendStage, stageTimes.blockFileAppend},
+		{indexUpdateStage, stageTimes.indexUpdate},
+	} {
+		s.stats.commitStageTime.With("channel", s.ledgerid, "stage", stage.name).Observe(stage.timeTaken.Seconds())
+	}
+}
+
+func (s *ledgerStats) updateRebuildProgress(progress *ledger.RebuildProgress) {
+	s.stats.rebuildBlocksProcessed.With("channel", s.ledgerid).Set(float64(progress.BlocksProcessed))
+	s.stats.rebuildBlocksTotal.With("channel", s.ledgerid).Set(float64(progress.BlocksTotal))
+	s.stats.rebuildETA.With("channel", s.ledgerid).Set(progress.ETA.Seconds())
+}
+
+func (s *ledgerStats) updateHydrationStatus(status []*pvtdatastorage.HydrationStatus) {
+	hydratingColls := make(map[[2]string]struct{})
+	for _, h := range status {
+		s.stats.pvtdataHydrationPercent.With(
+			"channel", s.ledgerid,
+			"namespace", h.Namespace,
+			"collection", h.Collection,
+		).Set(h.Percentage())
+		hydratingColls[[2]string{h.Namespace, h.Collection}] = struct{}{}
+	}
+	// the collections that are no longer reported are completely hydrated
+	for c := range s.hydratingColls {
+		if _, ok := hydratingColls[c]; !ok {
+			s.stats.pvtdataHydrationPercent.With(
+				"channel", s.ledgerid,
+				"namespace", c[0],
+				"collection", c[1],
+			).Set(100)
+		}
+	}
+	s.hydratingColls = hydratingColls
+}
+
 func (s *ledgerStats) updateTransactionsStats(
 	txstatsInfo []*validation.TxStatInfo,
 ) {
@@ -106,6 +201,16 @@ var (
 		Buckets:      []float64{0.005, 0.01, 0.015, 0.05, 0.1, 1, 10},
 	}
 
+	commitStageTimeOpts = metrics.HistogramOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "commit_stage_time",
+		Help:         "Time taken in seconds by each stage of the commit of a block.",
+		LabelNames:   []string{"channel", "stage"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{stage}",
+		Buckets:      []float64{0.005, 0.01, 0.015, 0.05, 0.1, 1, 10},
+	}
+
 	transactionCountOpts = metrics.CounterOpts{
 		Namespace:    "ledger",
 		Subsystem:    "",
@@ -114,4 +219,68 @@ var (
 		LabelNames:   []string{"channel", "transaction_type", "chaincode", "validation_code"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{transaction_type}.%{chaincode}.%{validation_code}",
 	}
+
+	rebuildBlocksProcessedOpts = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "rebuild_blocks_processed",
+		Help:         "Number of blocks recommitted by the rebuild of the ledger databases.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	rebuildBlocksTotalOpts = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "rebuild_blocks_total",
+		Help:         "Number of blocks to recommit to complete the rebuild of the ledger databases.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	rebuildETAOpts = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "rebuild_eta_seconds",
+		Help:         "Estimated time in seconds remaining to complete the rebuild of the ledger databases.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	pvtdataHydrationPercentOpts = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "pvtdata_hydration_percent",
+		Help:         "Percentage of the private data committed before the peer became eligible for a collection that has been fetched.",
+		LabelNames:   []string{"channel", "namespace", "collection"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{namespace}.%{collection}",
+	}
+
+	compactionTimeOpts = metrics.HistogramOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "compaction_time",
+		Help:         "Time taken in seconds by the compaction of a leveldb based store.",
+		LabelNames:   []string{"store"},
+		StatsdFormat: "%{#fqname}.%{store}",
+		Buckets:      []float64{1, 10, 60, 300, 900, 3600},
+	}
+
+	compactionReclaimedBytesOpts = metrics.CounterOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "compaction_reclaimed_bytes",
+		Help:         "Disk space in bytes reclaimed by the compactions of a leveldb based store.",
+		LabelNames:   []string{"store"},
+		StatsdFormat: "%{#fqname}.%{store}",
+	}
+
+	storeSizeBytesOpts = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Subsystem:    "",
+		Name:         "store_size_bytes",
+		Help:         "Disk space in bytes used by a leveldb based store after its last compaction.",
+		LabelNames:   []string{"store"},
+		StatsdFormat: "%{#fqname}.%{store}",
+	}
 )
@@ -19,6 +19,7 @@ import (
 	lgr "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
 	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
 	"github.com/stretchr/testify/require"
 )
 
@@ -142,11 +143,88 @@ func TestStatsBlockCommit(t *testing.T) {
 	)
 }
 
+func TestStatsCommitStageTimes(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	testMetricProvider := testutilConstructMetricProvider()
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	provider, err := NewProvider(
+		&lgr.Initializer{
+			DeployedChaincodeInfoProvider: &mock.DeployedChaincodeInfoProvider{},
+			MetricsProvider:               testMetricProvider.fakeProvider,
+			Config:                        conf,
+			HashProvider:                  cryptoProvider,
+		},
+	)
+	require.NoError(t, err)
+	defer provider.Close()
+
+	ledgerid := "ledger1"
+	_, gb := testutil.NewBlockGenerator(t, ledgerid, false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	ledger := l.(*kvLedger)
+	defer ledger.Close()
+
+	// one observation per stage while committing the genesis block
+	fakeHist := testMetricProvider.fakeCommitStageTimeHist
+	expectedStages := []string{
+		"block_validation",
+		"statedb_prep",
+		"statedb_commit",
+		"history_commit",
+		"pvtdata_commit",
+		"block_file_append",
+		"index_update",
+	}
+	require.Equal(t, len(expectedStages), fakeHist.ObserveCallCount())
+	for i, stage := range expectedStages {
+		require.Equal(t, []string{"channel", ledgerid, "stage", stage}, fakeHist.WithArgsForCall(i))
+	}
+
+	ledger.stats.updateCommitStageTimes(&commitStageTimes{
+		blockValidation: 1 * time.Second,
+		indexUpdate:     7 * time.Second,
+	})
+	require.Equal(t, []string{"channel", ledgerid, "stage", "block_validation"}, fakeHist.WithArgsForCall(7))
+	require.Equal(t, float64(1), fakeHist.ObserveArgsForCall(7))
+	require.Equal(t, []string{"channel", ledgerid, "stage", "index_update"}, fakeHist.WithArgsForCall(13))
+	require.Equal(t, float64(7), fakeHist.ObserveArgsForCall(13))
+}
+
+func TestStatsHydrationStatus(t *testing.T) {
+	fakeGauge := testutilConstructGauge()
+	fakeProvider := &metricsfakes.Provider{}
+	fakeProvider.NewGaugeReturns(fakeGauge)
+	fakeProvider.NewHistogramReturns(testutilConstructHist())
+	fakeProvider.NewCounterReturns(testutilConstructCounter())
+	stats := newStats(fakeProvider).ledgerStats("ledger1")
+
+	stats.updateHydrationStatus([]*pvtdatastorage.HydrationStatus{
+		{Namespace: "ns-1", Collection: "coll-1", Total: 4, Hydrated: 1},
+	})
+	require.Equal(t, 1, fakeGauge.SetCallCount())
+	require.Equal(t, []string{"channel", "ledger1", "namespace", "ns-1", "collection", "coll-1"}, fakeGauge.WithArgsForCall(0))
+	require.Equal(t, float64(25), fakeGauge.SetArgsForCall(0))
+
+	// a collection no longer reported is completely hydrated
+	stats.updateHydrationStatus(nil)
+	require.Equal(t, 2, fakeGauge.SetCallCount())
+	require.Equal(t, []string{"channel", "ledger1", "namespace", "ns-1", "collection", "coll-1"}, fakeGauge.WithArgsForCall(1))
+	require.Equal(t, float64(100), fakeGauge.SetArgsForCall(1))
+
+	stats.updateHydrationStatus(nil)
+	require.Equal(t, 2, fakeGauge.SetCallCount())
+}
+
 type testMetricProvider struct {
 	fakeProvider                              *metricsfakes.Provider
 	fakeBlockProcessingTimeHist               *metricsfakes.Histogram
 	fakeBlockstorageCommitWithPvtDataTimeHist *metricsfakes.Histogram
 	fakeStatedbCommitTimeHist                 *metricsfakes.Histogram
+	fakeCommitStageTimeHist                   *metricsfakes.Histogram
 	fakeTransactionsCount                     *metricsfakes.Counter
 }
 
@@ -155,6 +233,7 @@ func testutilConstructMetricProvider() *testMetricProvider {
 	fakeBlockProcessingTimeHist := testutilConstructHist()
 	fakeBlockstorageCommitWithPvtDataTimeHist := testutilConstructHist()
 	fakeStatedbCommitTimeHist := testutilConstructHist()
+	fakeCommitStageTimeHist := testutilConstructHist()
 	fakeTransactionsCount := testutilConstructCounter()
 	fakeProvider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
 		// return a gauge for metrics in common/ledger
@@ -168,6 +247,8 @@ func testutilConstructMetricProvider() *testMetricProvider {
 			return fakeBlockstorageCommitWithPvtDataTimeHist
 		case statedbCommitTimeOpts.Name:
 			return fakeStatedbCommitTimeHist
+		case commitStageTimeOpts.Name:
+			return fakeCommitStageTimeHist
 		default:
 			// return a histogram for metrics in common/ledger
 			return testutilConstructHist()
@@ -186,6 +267,7 @@ func testutilConstructMetricProvider() *testMetricProvider {
 		fakeBlockProcessingTimeHist,
 		fakeBlockstorageCommitWithPvtDataTimeHist,
 		fakeStatedbCommitTimeHist,
+		fakeCommitStageTimeHist,
 		fakeTransactionsCount,
 	}
 }
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// pvtDataAntiEntropy checks the private data that other peers claim to hold against
+// the hashed rwsets of the committed blocks before scheduling its reconciliation
+type pvtDataAntiEntropy struct {
+	*pvtdatastorage.Store
+	blockStore *blkstorage.BlockStore
+}
+
+// ScheduleReconciliation tracks as missing the given private data that was written by a
+// valid transaction of a committed block, as recorded by the hashed rwset of the transaction
+func (a *pvtDataAntiEntropy) ScheduleReconciliation(lost ledger.MissingPvtDataInfo) (int, error) {
+	verified, err := verifyLostPvtData(lost, a.blockStore)
+	if err != nil {
+		return 0, err
+	}
+	return a.Store.ScheduleReconciliation(verified)
+}
+
+func verifyLostPvtData(lost ledger.MissingPvtDataInfo, blockStore *blkstorage.BlockStore) (ledger.MissingPvtDataInfo, error) {
+	info, err := blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	verified := make(ledger.MissingPvtDataInfo)
+	for blkNum, blkLost := range lost {
+		if blkNum >= info.Height {
+			logger.Debugf("Skipping the lost private data of block [%d] which is not committed yet", blkNum)
+			continue
+		}
+		block, err := blockStore.RetrieveBlockByNumber(blkNum)
+		if err != nil {
+			return nil, err
+		}
+		validationFlags := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		for txNum, txLost := range blkLost {
+			if txNum >= uint64(len(block.Data.Data)) || !validationFlags.IsValid(int(txNum)) {
+				logger.Warningf("Skipping the lost private data of blockNum:[%d], txNum:[%d] which is not a valid transaction", blkNum, txNum)
+				continue
+			}
+			txRWSet, err := rwsetOfTx(block, txNum)
+			if err != nil {
+				logger.Warningf("Skipping the lost private data of blockNum:[%d], txNum:[%d]: %s", blkNum, txNum, err)
+				continue
+			}
+			for _, nsColl := range txLost {
+				if txRWSet.GetPvtDataHash(nsColl.Namespace, nsColl.Collection) == nil {
+					logger.Warningf("Skipping the lost private data of namespace: %s collection: %s which was not written by txNum %d in BlkNum %d",
+						nsColl.Namespace, nsColl.Collection, txNum, blkNum)
+					continue
+				}
+				verified.Add(blkNum, txNum, nsColl.Namespace, nsColl.Collection)
+			}
+		}
+	}
+	return verified, nil
+}
+
+func rwsetOfTx(block *common.Block, txNum uint64) (*rwsetutil.TxRwSet, error) {
+	txEnvelope, err := protoutil.GetEnvelopeFromBlock(block.Data.Data[txNum])
+	if err != nil {
+		return nil, err
+	}
+	responsePayload, err := protoutil.GetActionFromEnvelopeMsg(txEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(responsePayload.Results); err != nil {
+		return nil, err
+	}
+	return txRWSet, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPvtDataAntiEntropyScheduleReconciliation(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProviderWithCollectionConfig(
+		t,
+		[]*nsCollBtlConfig{
+			{
+				namespace: "ns-1",
+				btlConfig: map[string]uint64{
+					"coll-1": 0,
+					"coll-2": 0,
+				},
+			},
+		},
+		conf,
+	)
+	defer provider.Close()
+
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	lg, _ := provider.Create(gb)
+	defer lg.Close()
+
+	// block1 writes the private data of ns-1:coll-1 in its only transaction,
+	// which the peer silently lost
+	_, pubSimResBytes := produceSamplePvtdata(t, 0, []string{"ns-1:coll-1"}, [][]byte{{0}})
+	blk1 := testutil.ConstructBlock(t, 1, protoutil.BlockHeaderHash(gb.Header), [][]byte{pubSimResBytes}, false)
+	require.NoError(t, lg.(*kvLedger).commitToPvtAndBlockStore(&ledger.BlockAndPvtData{Block: blk1}))
+
+	antiEntropy, err := lg.(*kvLedger).GetPvtDataAntiEntropy()
+	require.NoError(t, err)
+
+	lost := make(ledger.MissingPvtDataInfo)
+	lost.Add(1, 0, "ns-1", "coll-1")
+	// not written by the transaction
+	lost.Add(1, 0, "ns-1", "coll-2")
+	// no such transaction
+	lost.Add(1, 5, "ns-1", "coll-1")
+	// no such block
+	lost.Add(7, 0, "ns-1", "coll-1")
+	scheduled, err := antiEntropy.ScheduleReconciliation(lost)
+	require.NoError(t, err)
+	require.Equal(t, 1, scheduled)
+
+	missingPvtDataTracker, err := lg.GetMissingPvtDataTracker()
+	require.NoError(t, err)
+	missing, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	require.NoError(t, err)
+	expected := make(ledger.MissingPvtDataInfo)
+	expected.Add(1, 0, "ns-1", "coll-1")
+	require.Equal(t, expected, missing)
+}
@@ -7,15 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
+	"bytes"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
 	"github.com/pkg/errors"
 )
 
 // RebuildDBs drops existing ledger databases.
-// Dropped database will be rebuilt upon server restart
+// Dropped database will be rebuilt upon server restart.
+// If a previous rebuild is yet to complete, the databases are not dropped
+// again and the rebuild resumes from its checkpoint upon server restart.
 func RebuildDBs(config *ledger.Config) error {
 	rootFSPath := config.RootFSPath
 	fileLockPath := fileLockPath(rootFSPath)
@@ -26,6 +32,10 @@ func RebuildDBs(config *ledger.Config) error {
 	}
 	defer fileLock.Unlock()
 
+	if resume, err := resumeRebuild(rootFSPath); err != nil || resume {
+		return err
+	}
+
 	if config.StateDBConfig.StateDatabase == "CouchDB" {
 		if err := statecouchdb.DropApplicationDBs(config.StateDBConfig.CouchDB); err != nil {
 			return err
@@ -38,3 +48,46 @@ func RebuildDBs(config *ledger.Config) error {
 	blockstorePath := BlockStorePath(rootFSPath)
 	return blkstorage.DeleteBlockStoreIndex(blockstorePath)
 }
+
+// resumeRebuild returns true if the rebuild of the databases of a ledger is yet to complete.
+// As the databases of all the ledgers are dropped together, none of them needs to be dropped
+// again in that case.
+func resumeRebuild(rootFSPath string) (bool, error) {
+	pending, err := pendingRebuilds(rootFSPath)
+	if err != nil || len(pending) == 0 {
+		return false, err
+	}
+
+	logger.Infof("The rebuild of the databases of ledgers %s is yet to complete, "+
+		"it will resume from its checkpoint upon server restart", pending)
+	return true, nil
+}
+
+// pendingRebuilds returns the IDs of the ledgers whose databases are yet to be rebuilt.
+func pendingRebuilds(rootFSPath string) ([]string, error) {
+	dbPath := LedgerProviderPath(rootFSPath)
+	db := leveldbhelper.CreateDB(&leveldbhelper.Conf{DBPath: dbPath})
+	db.Open()
+	defer db.Close()
+
+	// the ledgers created before the current data format are listed in a different way,
+	// none of them can be under rebuild as the rebuild checkpoints came with the current format
+	format, err := db.Get(formatKey)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(format, []byte(dataformat.CurrentFormat)) {
+		return nil, nil
+	}
+	ledgerIDs, err := (&idStore{db, dbPath}).getActiveLedgerIDs()
+	if err != nil || len(ledgerIDs) == 0 {
+		return nil, err
+	}
+
+	bookkeeperProvider, err := bookkeeping.NewProvider(BookkeeperDBPath(rootFSPath))
+	if err != nil {
+		return nil, err
+	}
+	defer bookkeeperProvider.Close()
+	return rebuildPending(bookkeeperProvider, ledgerIDs)
+}
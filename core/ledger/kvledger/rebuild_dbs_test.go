@@ -11,7 +11,11 @@ import (
 	"testing"
 
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/ledger/util"
+	commonutil "github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -57,3 +61,60 @@ func TestRebuildDBs(t *testing.T) {
 	err = RebuildDBs(conf)
 	require.NoError(t, err)
 }
+
+func TestRebuildDBsResume(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+
+	bg, gb := testutil.NewBlockGenerator(t, "testledger", false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		simulator, err := l.NewTxSimulator(commonutil.GenerateUUID())
+		require.NoError(t, err)
+		require.NoError(t, simulator.SetState("ns1", "key1", []byte("value1")))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		require.NoError(t, l.CommitLegacy(&lgr.BlockAndPvtData{Block: bg.NextBlock([][]byte{pubSimBytes})}, &lgr.CommitOptions{}))
+	}
+	l.Close()
+	provider.Close()
+
+	require.NoError(t, RebuildDBs(conf))
+
+	// the rebuild progress is reported while the ledger is opened
+	var reported []*lgr.RebuildProgress
+	provider = testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	provider.initializer.RebuildProgressListener = func(progress *lgr.RebuildProgress) {
+		reported = append(reported, progress)
+	}
+	l, err = provider.Open("testledger")
+	require.NoError(t, err)
+	l.Close()
+	require.Len(t, reported, 2)
+	require.Equal(t, uint64(0), reported[0].BlocksProcessed)
+	require.Equal(t, uint64(3), reported[0].BlocksTotal)
+	require.Equal(t, uint64(3), reported[1].BlocksProcessed)
+	require.Equal(t, uint64(3), reported[1].BlocksTotal)
+	checkpoint, err := retrieveRebuildCheckpoint(provider.bookkeepingProvider.GetDBHandle("testledger", bookkeeping.RebuildProgress))
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+
+	// simulate a rebuild which did not complete
+	require.NoError(t, provider.bookkeepingProvider.GetDBHandle("testledger", bookkeeping.RebuildProgress).Put(
+		rebuildCheckpointKey, (&rebuildCheckpoint{lastBlockNum: 2, nextBlockNum: 1}).toBytes(), true))
+	provider.Close()
+
+	// the databases are not dropped again
+	require.NoError(t, RebuildDBs(conf))
+	empty, err := util.DirEmpty(StateDBPath(conf.RootFSPath))
+	require.NoError(t, err)
+	require.False(t, empty)
+	empty, err = util.DirEmpty(BookkeeperDBPath(conf.RootFSPath))
+	require.NoError(t, err)
+	require.False(t, empty)
+}
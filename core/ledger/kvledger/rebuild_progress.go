@@ -0,0 +1,209 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/pkg/errors"
+)
+
+var rebuildCheckpointKey = []byte("rebuildCheckpoint")
+
+const (
+	// rebuildCheckpointInterval is the number of recommitted blocks between two checkpoints
+	rebuildCheckpointInterval = 1000
+	// rebuildReportInterval is the interval between two progress reports
+	rebuildReportInterval = 30 * time.Second
+)
+
+// rebuildCheckpoint records the progress of the rebuild of the state and history databases
+// of a ledger. The databases maintain their own savepoints, hence the rebuild always resumes
+// from the block following the savepoints. The checkpoint keeps track of where the rebuild
+// started so that the progress reported after a restart covers the whole rebuild, and its
+// presence tells RebuildDBs and UpgradeDBs that a rebuild is yet to complete.
+type rebuildCheckpoint struct {
+	firstBlockNum uint64
+	lastBlockNum  uint64
+	nextBlockNum  uint64
+	startTime     time.Time
+}
+
+func (c *rebuildCheckpoint) toBytes() []byte {
+	buf := proto.NewBuffer(nil)
+	buf.EncodeVarint(c.firstBlockNum)
+	buf.EncodeVarint(c.lastBlockNum)
+	buf.EncodeVarint(c.nextBlockNum)
+	buf.EncodeVarint(uint64(c.startTime.UnixNano()))
+	return buf.Bytes()
+}
+
+func rebuildCheckpointFromBytes(b []byte) (*rebuildCheckpoint, error) {
+	buf := proto.NewBuffer(b)
+	var fields [4]uint64
+	for i := range fields {
+		v, err := buf.DecodeVarint()
+		if err != nil {
+			return nil, errors.Wrap(err, "error decoding rebuild checkpoint")
+		}
+		fields[i] = v
+	}
+	return &rebuildCheckpoint{
+		firstBlockNum: fields[0],
+		lastBlockNum:  fields[1],
+		nextBlockNum:  fields[2],
+		startTime:     time.Unix(0, int64(fields[3])),
+	}, nil
+}
+
+func retrieveRebuildCheckpoint(db *leveldbhelper.DBHandle) (*rebuildCheckpoint, error) {
+	b, err := db.Get(rebuildCheckpointKey)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return rebuildCheckpointFromBytes(b)
+}
+
+// rebuildTracker checkpoints and reports the progress of the recommit of the blocks
+// to the state and history databases of a ledger
+type rebuildTracker struct {
+	ledgerID   string
+	db         *leveldbhelper.DBHandle
+	stats      *ledgerStats
+	listener   ledger.RebuildProgressListener
+	checkpoint *rebuildCheckpoint
+
+	// resumeBlockNum and resumeTime are the first block recommitted and the time at which
+	// the recommit resumed since the peer started. The ETA is computed from the rate since then.
+	resumeBlockNum uint64
+	resumeTime     time.Time
+	lastReportTime time.Time
+	now            func() time.Time
+}
+
+// newRebuildTracker returns a rebuildTracker if the databases are being rebuilt, that is
+// if they are empty or a checkpoint of a previous rebuild exists, and nil otherwise
+func newRebuildTracker(
+	ledgerID string,
+	db *leveldbhelper.DBHandle,
+	stats *ledgerStats,
+	listener ledger.RebuildProgressListener,
+	firstBlockNum, lastBlockNum uint64,
+) (*rebuildTracker, error) {
+	checkpoint, err := retrieveRebuildCheckpoint(db)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case checkpoint == nil && firstBlockNum != 0:
+		// the databases are catching up with a few blocks after a crash, not being rebuilt
+		return nil, nil
+	case checkpoint == nil || checkpoint.firstBlockNum > firstBlockNum:
+		checkpoint = &rebuildCheckpoint{
+			firstBlockNum: firstBlockNum,
+			startTime:     now,
+		}
+	default:
+		logger.Infof("Resuming the rebuild of the databases of ledger [%s] started at %s: [%d] blocks recommitted so far",
+			ledgerID, checkpoint.startTime.Format(time.RFC3339), firstBlockNum-checkpoint.firstBlockNum)
+	}
+	checkpoint.lastBlockNum = lastBlockNum
+	checkpoint.nextBlockNum = firstBlockNum
+
+	t := &rebuildTracker{
+		ledgerID:       ledgerID,
+		db:             db,
+		stats:          stats,
+		listener:       listener,
+		checkpoint:     checkpoint,
+		resumeBlockNum: firstBlockNum,
+		resumeTime:     now,
+		lastReportTime: now,
+		now:            time.Now,
+	}
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+	t.report()
+	return t, nil
+}
+
+func (t *rebuildTracker) save() error {
+	return t.db.Put(rebuildCheckpointKey, t.checkpoint.toBytes(), true)
+}
+
+// blockRecommitted records that the given block has been recommitted
+func (t *rebuildTracker) blockRecommitted(blockNum uint64) error {
+	t.checkpoint.nextBlockNum = blockNum + 1
+	if (t.checkpoint.nextBlockNum-t.checkpoint.firstBlockNum)%rebuildCheckpointInterval == 0 {
+		if err := t.save(); err != nil {
+			return err
+		}
+	}
+	if t.now().Sub(t.lastReportTime) >= rebuildReportInterval {
+		t.report()
+	}
+	return nil
+}
+
+// done removes the checkpoint once all the databases are in sync with the block store
+func (t *rebuildTracker) done() error {
+	t.checkpoint.nextBlockNum = t.checkpoint.lastBlockNum + 1
+	t.report()
+	logger.Infof("Completed the rebuild of the databases of ledger [%s] in %s",
+		t.ledgerID, t.now().Sub(t.checkpoint.startTime).Round(time.Second))
+	return t.db.Delete(rebuildCheckpointKey, true)
+}
+
+func (t *rebuildTracker) progress() *ledger.RebuildProgress {
+	c := t.checkpoint
+	progress := &ledger.RebuildProgress{
+		LedgerID:        t.ledgerID,
+		BlocksProcessed: c.nextBlockNum - c.firstBlockNum,
+		BlocksTotal:     c.lastBlockNum + 1 - c.firstBlockNum,
+	}
+	if recommitted := c.nextBlockNum - t.resumeBlockNum; recommitted > 0 {
+		elapsed := t.now().Sub(t.resumeTime)
+		remaining := c.lastBlockNum + 1 - c.nextBlockNum
+		progress.ETA = time.Duration(float64(elapsed) / float64(recommitted) * float64(remaining))
+	}
+	return progress
+}
+
+func (t *rebuildTracker) report() {
+	t.lastReportTime = t.now()
+	progress := t.progress()
+	logger.Infof("Rebuilding the databases of ledger [%s]: [%d] of [%d] blocks recommitted, ETA %s",
+		t.ledgerID, progress.BlocksProcessed, progress.BlocksTotal, progress.ETA.Round(time.Second))
+	if t.stats != nil {
+		t.stats.updateRebuildProgress(progress)
+	}
+	if t.listener != nil {
+		t.listener(progress)
+	}
+}
+
+// rebuildPending returns the IDs of the ledgers whose databases are being rebuilt
+func rebuildPending(bookkeeperProvider bookkeeping.Provider, ledgerIDs []string) ([]string, error) {
+	var pending []string
+	for _, ledgerID := range ledgerIDs {
+		checkpoint, err := retrieveRebuildCheckpoint(bookkeeperProvider.GetDBHandle(ledgerID, bookkeeping.RebuildProgress))
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil {
+			pending = append(pending, ledgerID)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildCheckpointEncoding(t *testing.T) {
+	checkpoint := &rebuildCheckpoint{
+		firstBlockNum: 0,
+		lastBlockNum:  100000,
+		nextBlockNum:  2000,
+		startTime:     time.Unix(1600000000, 5),
+	}
+	decoded, err := rebuildCheckpointFromBytes(checkpoint.toBytes())
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.firstBlockNum, decoded.firstBlockNum)
+	require.Equal(t, checkpoint.lastBlockNum, decoded.lastBlockNum)
+	require.Equal(t, checkpoint.nextBlockNum, decoded.nextBlockNum)
+	require.True(t, checkpoint.startTime.Equal(decoded.startTime))
+
+	_, err = rebuildCheckpointFromBytes([]byte{0x01})
+	require.EqualError(t, err, "error decoding rebuild checkpoint: unexpected EOF")
+}
+
+func TestRebuildTracker(t *testing.T) {
+	env := bookkeeping.NewTestEnv(t)
+	defer env.Cleanup()
+	db := env.TestProvider.GetDBHandle("testledger", bookkeeping.RebuildProgress)
+
+	t.Run("catching up after a crash", func(t *testing.T) {
+		tracker, err := newRebuildTracker("testledger", db, nil, nil, 10, 20)
+		require.NoError(t, err)
+		require.Nil(t, tracker)
+	})
+
+	var reported []*ledger.RebuildProgress
+	listener := func(progress *ledger.RebuildProgress) {
+		reported = append(reported, progress)
+	}
+
+	tracker, err := newRebuildTracker("testledger", db, nil, listener, 0, 2999)
+	require.NoError(t, err)
+	require.NotNil(t, tracker)
+	require.Equal(t, &ledger.RebuildProgress{LedgerID: "testledger", BlocksTotal: 3000}, reported[0])
+
+	start := tracker.resumeTime
+	tracker.now = func() time.Time { return start.Add(10 * time.Second) }
+	for blockNum := uint64(0); blockNum < 1000; blockNum++ {
+		require.NoError(t, tracker.blockRecommitted(blockNum))
+	}
+	require.Len(t, reported, 1)
+	require.Equal(t, &ledger.RebuildProgress{
+		LedgerID:        "testledger",
+		BlocksProcessed: 1000,
+		BlocksTotal:     3000,
+		ETA:             20 * time.Second,
+	}, tracker.progress())
+
+	tracker.now = func() time.Time { return start.Add(2 * rebuildReportInterval) }
+	require.NoError(t, tracker.blockRecommitted(1000))
+	require.Len(t, reported, 2)
+	require.Equal(t, uint64(1001), reported[1].BlocksProcessed)
+
+	// the checkpoint is persisted every rebuildCheckpointInterval blocks
+	checkpoint, err := retrieveRebuildCheckpoint(db)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), checkpoint.nextBlockNum)
+
+	// the rebuild resumes from the savepoints of the databases, after the checkpoint
+	tracker, err = newRebuildTracker("testledger", db, nil, listener, 1500, 2999)
+	require.NoError(t, err)
+	require.NotNil(t, tracker)
+	require.Equal(t, &ledger.RebuildProgress{LedgerID: "testledger", BlocksProcessed: 1500, BlocksTotal: 3000}, reported[2])
+	require.True(t, checkpoint.startTime.Equal(tracker.checkpoint.startTime))
+
+	pending, err := rebuildPending(env.TestProvider, []string{"testledger", "otherledger"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"testledger"}, pending)
+
+	require.NoError(t, tracker.done())
+	require.Equal(t, uint64(3000), reported[3].BlocksProcessed)
+	checkpoint, err = retrieveRebuildCheckpoint(db)
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+}
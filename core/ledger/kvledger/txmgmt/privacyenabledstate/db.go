@@ -131,6 +131,34 @@ func (s *DB) IsBulkOptimizable() bool {
 	return ok
 }
 
+// StartBulkLoad starts a bulk load of the underlying statedb, if it implements statedb.BulkLoadCapable.
+// It returns whether the bulk load was started.
+func (s *DB) StartBulkLoad() (bool, error) {
+	bulkLoadCapable, ok := s.VersionedDB.(statedb.BulkLoadCapable)
+	if !ok {
+		return false, nil
+	}
+	return bulkLoadCapable.StartBulkLoad()
+}
+
+// EndBulkLoad ends the bulk load of the underlying statedb, if it implements statedb.BulkLoadCapable
+func (s *DB) EndBulkLoad() error {
+	bulkLoadCapable, ok := s.VersionedDB.(statedb.BulkLoadCapable)
+	if !ok {
+		return nil
+	}
+	return bulkLoadCapable.EndBulkLoad()
+}
+
+// PinView pins a read-only view of the public state, if the underlying statedb implements statedb.ViewPinnable
+func (s *DB) PinView() (statedb.PinnedView, error) {
+	viewPinnable, ok := s.VersionedDB.(statedb.ViewPinnable)
+	if !ok {
+		return nil, errors.New("pinning a view of the state is not supported by the statedb in use")
+	}
+	return viewPinnable.PinView()
+}
+
 // LoadCommittedVersionsOfPubAndHashedKeys loads committed version of given public and hashed states
 func (s *DB) LoadCommittedVersionsOfPubAndHashedKeys(pubKeys []*statedb.CompositeKey,
 	hashedKeys []*HashedCompositeKey) error {
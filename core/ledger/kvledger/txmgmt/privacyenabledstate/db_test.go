@@ -155,6 +155,43 @@ func testDB(t *testing.T, env TestEnv) {
 	require.Nil(t, vv)
 }
 
+func TestBulkLoad(t *testing.T) {
+	for _, env := range testEnvs {
+		t.Run(env.GetName(), func(t *testing.T) {
+			testBulkLoad(t, env)
+		})
+	}
+}
+
+func testBulkLoad(t *testing.T, env TestEnv) {
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle(generateLedgerID(t))
+
+	_, bulkLoadCapable := db.VersionedDB.(statedb.BulkLoadCapable)
+	started, err := db.StartBulkLoad()
+	require.NoError(t, err)
+	require.Equal(t, bulkLoadCapable, started)
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(1, 2))
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 2)))
+	require.NoError(t, db.EndBulkLoad())
+
+	vv, err := db.GetState("ns1", "key1")
+	require.NoError(t, err)
+	require.Equal(t, &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, vv)
+	vv, err = db.GetPrivateData("ns1", "coll1", "key1")
+	require.NoError(t, err)
+	require.Equal(t, &statedb.VersionedValue{Value: []byte("pvt_value1"), Version: version.NewHeight(1, 2)}, vv)
+
+	// the database is no longer empty
+	started, err = db.StartBulkLoad()
+	require.NoError(t, err)
+	require.False(t, started)
+}
+
 func TestGetStateMultipleKeys(t *testing.T) {
 	for _, env := range testEnvs {
 		t.Run(env.GetName(), func(t *testing.T) {
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwsetutil
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/pkg/errors"
+)
+
+// NewKVCounterDelta returns the increment of the counter key by delta. The
+// increments of counter keys are recorded in the CounterDeltas of the public
+// write-set of a namespace, and their deltas are added to the values of the
+// keys at commit time. Because an increment carries no read of the key,
+// concurrent increments of a counter do not conflict with each other.
+//
+// The value of a counter key is a signed 64-bit integer encoded in decimal;
+// a missing key is a counter of value zero.
+func NewKVCounterDelta(key string, delta int64) *kvrwset.KVCounterDelta {
+	return &kvrwset.KVCounterDelta{Key: key, Delta: delta}
+}
+
+// ContainsCounterDeltas returns true if the public write-set of the namespace
+// increments a counter key.
+func ContainsCounterDeltas(nsRwSet *NsRwSet) bool {
+	return len(nsRwSet.KvRwSet.GetCounterDeltas()) > 0
+}
+
+// AddCounterDelta adds delta to the encoded value of a counter, and returns
+// the encoded result. A nil value is a counter of value zero.
+func AddCounterDelta(value []byte, delta int64) ([]byte, error) {
+	var current int64
+	if value != nil {
+		var err error
+		if current, err = DecodeCounterValue(value); err != nil {
+			return nil, err
+		}
+	}
+	sum, err := addInt64(current, delta)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeCounterValue(sum), nil
+}
+
+// EncodeCounterValue encodes the value of a counter.
+func EncodeCounterValue(v int64) []byte {
+	return []byte(strconv.FormatInt(v, 10))
+}
+
+// DecodeCounterValue decodes the value of a counter.
+func DecodeCounterValue(b []byte) (int64, error) {
+	v, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("value [%q] is not a counter", b)
+	}
+	return v, nil
+}
+
+func addInt64(a, b int64) (int64, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, errors.Errorf("counter overflow adding %d to %d", b, a)
+	}
+	return a + b, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwsetutil
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCounterDelta(t *testing.T) {
+	value, err := AddCounterDelta(nil, 3)
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), value)
+
+	value, err = AddCounterDelta([]byte("3"), -10)
+	require.NoError(t, err)
+	require.Equal(t, []byte("-7"), value)
+
+	_, err = AddCounterDelta(EncodeCounterValue(math.MaxInt64), 1)
+	require.EqualError(t, err, "counter overflow adding 1 to 9223372036854775807")
+
+	_, err = AddCounterDelta(EncodeCounterValue(math.MinInt64), -1)
+	require.EqualError(t, err, "counter overflow adding -1 to -9223372036854775808")
+
+	_, err = AddCounterDelta([]byte(`{"asset":"car"}`), 1)
+	require.EqualError(t, err, `value ["{\"asset\":\"car\"}"] is not a counter`)
+}
+
+func TestTxSimulationResultWithCounterDeltas(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	require.NoError(t, rwSetBuilder.AddToCounterDeltaSet("ns1", "key1", 2))
+	require.NoError(t, rwSetBuilder.AddToCounterDeltaSet("ns1", "key1", 3))
+	require.True(t, rwSetBuilder.HasCounterDelta("ns1", "key1"))
+	require.False(t, rwSetBuilder.HasCounterDelta("ns1", "key2"))
+	require.False(t, rwSetBuilder.HasCounterDelta("ns2", "key1"))
+
+	rwSetBuilder.AddToWriteSet("ns1", "key2", []byte("value2"))
+	require.EqualError(t, rwSetBuilder.AddToCounterDeltaSet("ns1", "key2", 1),
+		"key [key2] of namespace [ns1] is both written and incremented")
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key3", map[string][]byte{"metadata1": []byte("metadata1")})
+	require.EqualError(t, rwSetBuilder.AddToCounterDeltaSet("ns1", "key3", 1),
+		"key [key3] of namespace [ns1] has both its metadata updated and is incremented")
+	require.NoError(t, rwSetBuilder.AddToCounterDeltaSet("ns1", "key4", math.MaxInt64))
+	require.EqualError(t, rwSetBuilder.AddToCounterDeltaSet("ns1", "key4", 1),
+		"counter overflow adding 1 to 9223372036854775807")
+
+	txRwSet := rwSetBuilder.GetTxReadWriteSet()
+	require.Len(t, txRwSet.NsRwSets, 1)
+	require.True(t, ContainsCounterDeltas(txRwSet.NsRwSets[0]))
+	require.Equal(t, []*kvrwset.KVCounterDelta{
+		{Key: "key1", Delta: 5},
+		{Key: "key4", Delta: math.MaxInt64},
+	}, txRwSet.NsRwSets[0].KvRwSet.CounterDeltas)
+	require.Equal(t, []*kvrwset.KVMetadataWrite{
+		{Key: "key3", Entries: []*kvrwset.KVMetadataEntry{{Name: "metadata1", Value: []byte("metadata1")}}},
+	}, txRwSet.NsRwSets[0].KvRwSet.MetadataWrites)
+
+	// the counter deltas survive the round trip through the proto messages
+	protoBytes, err := txRwSet.ToProtoBytes()
+	require.NoError(t, err)
+	unmarshalled := &TxRwSet{}
+	require.NoError(t, unmarshalled.FromProtoBytes(protoBytes))
+	require.True(t, ContainsCounterDeltas(unmarshalled.NsRwSets[0]))
+	require.Equal(t, int64(5), unmarshalled.NsRwSets[0].KvRwSet.CounterDeltas[0].Delta)
+}
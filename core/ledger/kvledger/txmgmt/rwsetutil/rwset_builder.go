@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/pkg/errors"
 )
 
 var logger = flogging.MustGetLogger("rwsetutil")
@@ -38,6 +39,7 @@ type nsPubRwBuilder struct {
 	readMap           map[string]*kvrwset.KVRead //for mvcc validation
 	writeMap          map[string]*kvrwset.KVWrite
 	metadataWriteMap  map[string]*kvrwset.KVMetadataWrite
+	counterDeltaMap   map[string]*kvrwset.KVCounterDelta
 	rangeQueriesMap   map[rangeQueryKey]*kvrwset.RangeQueryInfo //for phantom read validation
 	rangeQueriesKeys  []rangeQueryKey
 	collHashRwBuilder map[string]*collHashRwBuilder
@@ -92,6 +94,38 @@ func (b *RWSetBuilder) AddToMetadataWriteSet(ns, key string, metadata map[string
 		metadataWriteMap[key] = mapToMetadataWrite(key, metadata)
 }
 
+// AddToCounterDeltaSet adds an increment of a counter key to the write-set.
+// The increments of a key within a transaction are summed into a single
+// counter delta. An error is returned if the key is also written, or has
+// its metadata updated, in the write-set, or if the sum overflows.
+func (b *RWSetBuilder) AddToCounterDeltaSet(ns, key string, delta int64) error {
+	nsPubRwBuilder := b.getOrCreateNsPubRwBuilder(ns)
+	if _, ok := nsPubRwBuilder.writeMap[key]; ok {
+		return errors.Errorf("key [%s] of namespace [%s] is both written and incremented", key, ns)
+	}
+	if _, ok := nsPubRwBuilder.metadataWriteMap[key]; ok {
+		return errors.Errorf("key [%s] of namespace [%s] has both its metadata updated and is incremented", key, ns)
+	}
+	if counterDelta, ok := nsPubRwBuilder.counterDeltaMap[key]; ok {
+		var err error
+		if delta, err = addInt64(counterDelta.Delta, delta); err != nil {
+			return err
+		}
+	}
+	nsPubRwBuilder.counterDeltaMap[key] = NewKVCounterDelta(key, delta)
+	return nil
+}
+
+// HasCounterDelta returns true if the key is incremented in the write-set
+func (b *RWSetBuilder) HasCounterDelta(ns, key string) bool {
+	nsPubRwBuilder, ok := b.pubRwBuilderMap[ns]
+	if !ok {
+		return false
+	}
+	_, ok = nsPubRwBuilder.counterDeltaMap[key]
+	return ok
+}
+
 // AddToRangeQuerySet adds a range query info for performing phantom read validation
 func (b *RWSetBuilder) AddToRangeQuerySet(ns string, rqi *kvrwset.RangeQueryInfo) {
 	nsPubRwBuilder := b.getOrCreateNsPubRwBuilder(ns)
@@ -197,6 +231,7 @@ func (b *nsPubRwBuilder) build() *NsRwSet {
 	var readSet []*kvrwset.KVRead
 	var writeSet []*kvrwset.KVWrite
 	var metadataWriteSet []*kvrwset.KVMetadataWrite
+	var counterDeltaSet []*kvrwset.KVCounterDelta
 	var rangeQueriesInfo []*kvrwset.RangeQueryInfo
 	var collHashedRwSet []*CollHashedRwSet
 	//add read set
@@ -204,6 +239,7 @@ func (b *nsPubRwBuilder) build() *NsRwSet {
 	//add write set
 	util.GetValuesBySortedKeys(&(b.writeMap), &writeSet)
 	util.GetValuesBySortedKeys(&(b.metadataWriteMap), &metadataWriteSet)
+	util.GetValuesBySortedKeys(&(b.counterDeltaMap), &counterDeltaSet)
 	//add range query info
 	for _, key := range b.rangeQueriesKeys {
 		rangeQueriesInfo = append(rangeQueriesInfo, b.rangeQueriesMap[key])
@@ -220,6 +256,7 @@ func (b *nsPubRwBuilder) build() *NsRwSet {
 			Reads:            readSet,
 			Writes:           writeSet,
 			MetadataWrites:   metadataWriteSet,
+			CounterDeltas:    counterDeltaSet,
 			RangeQueriesInfo: rangeQueriesInfo,
 		},
 		CollHashedRwSets: collHashedRwSet,
@@ -314,6 +351,7 @@ func newNsPubRwBuilder(namespace string) *nsPubRwBuilder {
 		make(map[string]*kvrwset.KVRead),
 		make(map[string]*kvrwset.KVWrite),
 		make(map[string]*kvrwset.KVMetadataWrite),
+		make(map[string]*kvrwset.KVCounterDelta),
 		make(map[rangeQueryKey]*kvrwset.RangeQueryInfo),
 		nil,
 		make(map[string]*collHashRwBuilder),
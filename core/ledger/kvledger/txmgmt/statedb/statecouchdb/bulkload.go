@@ -0,0 +1,247 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// maxBulkLoadRevisions bounds the number of revisions of documents a bulk load keeps in memory
+var maxBulkLoadRevisions = 1000000
+
+// bulkLoad holds the state of a bulk load of the state database, i.e., of a
+// rebuild from the blocks. As the database is empty at the start of the load,
+// the revisions of the documents written by the load are the only ones that can
+// be found in the database, which saves the lookups of the revisions of the
+// keys missing from the read sets. Once the load has written more documents than
+// maxBulkLoadRevisions, the revisions are dropped and looked up in the database
+// again. The creation of the indexes is deferred to the end of the load so that
+// CouchDB does not update them on every batch.
+type bulkLoad struct {
+	mutex        sync.Mutex
+	revisions    map[string]map[string]string
+	numRevisions int
+	overflowed   bool
+	doc          *bulkLoadDoc
+}
+
+// bulkLoadDoc is persisted in the metadata database while a bulk load is in
+// progress. It holds the index files of the chaincodes deployed during the load,
+// so that they are created even if the peer stops before the end of the load.
+type bulkLoadDoc struct {
+	Indexes []*deferredIndexes `json:"indexes"`
+}
+
+// deferredIndexes holds the index files of a chaincode deployed during a bulk load
+type deferredIndexes struct {
+	Namespace      string            `json:"namespace"`
+	IndexFilesData map[string][]byte `json:"index_files_data"`
+}
+
+func newBulkLoad() *bulkLoad {
+	return &bulkLoad{
+		revisions: make(map[string]map[string]string),
+		doc:       &bulkLoadDoc{},
+	}
+}
+
+// setRevision records the revision of a document written during the load. An
+// empty revision denotes a deleted document.
+func (b *bulkLoad) setRevision(ns, key, rev string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.overflowed {
+		return
+	}
+	nsRevs, ok := b.revisions[ns]
+	if !ok {
+		if rev == "" {
+			return
+		}
+		nsRevs = make(map[string]string)
+		b.revisions[ns] = nsRevs
+	}
+	_, exists := nsRevs[key]
+	if rev == "" {
+		if exists {
+			delete(nsRevs, key)
+			b.numRevisions--
+		}
+		return
+	}
+	if !exists {
+		if b.numRevisions >= maxBulkLoadRevisions {
+			logger.Infof("The bulk load has written more than %d documents, the revisions are looked up in the database from now on", maxBulkLoadRevisions)
+			b.overflowed = true
+			b.revisions = nil
+			return
+		}
+		b.numRevisions++
+	}
+	nsRevs[key] = rev
+}
+
+// mayExist returns whether a document may exist in the database, i.e., whether it
+// was written, and not deleted since, during the load, or whether the load no longer
+// keeps track of the written documents
+func (b *bulkLoad) mayExist(ns, key string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.overflowed {
+		return true
+	}
+	_, ok := b.revisions[ns][key]
+	return ok
+}
+
+// addRevisions adds the revisions of the given keys to revs. The keys that
+// were not written during the load do not exist in the database. It returns
+// the keys whose revisions are to be looked up in the database, i.e., all of
+// them once the load no longer keeps track of the written documents.
+func (b *bulkLoad) addRevisions(ns string, keys []string, revs map[string]string) []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.overflowed {
+		return keys
+	}
+	nsRevs := b.revisions[ns]
+	for _, k := range keys {
+		if rev, ok := nsRevs[k]; ok {
+			revs[k] = rev
+		}
+	}
+	return nil
+}
+
+// deferIndexes records the index files of a chaincode deployed during the load
+// in the bulk load document of the metadata database
+func (vdb *VersionedDB) deferIndexes(b *bulkLoad, namespace string, indexFilesData map[string][]byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.doc.Indexes = append(b.doc.Indexes, &deferredIndexes{Namespace: namespace, IndexFilesData: indexFilesData})
+	return vdb.writeBulkLoadDoc(b.doc)
+}
+
+func (vdb *VersionedDB) writeBulkLoadDoc(doc *bulkLoadDoc) error {
+	jsonValue, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal bulk load document")
+	}
+	_, err = vdb.metadataDB.saveDoc(bulkLoadDocID, "", &couchDoc{jsonValue: jsonValue})
+	return err
+}
+
+func (vdb *VersionedDB) readBulkLoadDoc() (*bulkLoadDoc, error) {
+	couchDoc, _, err := vdb.metadataDB.readDoc(bulkLoadDocID)
+	if err != nil {
+		return nil, err
+	}
+	if couchDoc == nil || couchDoc.jsonValue == nil {
+		return nil, nil
+	}
+	doc := &bulkLoadDoc{}
+	if err := json.Unmarshal(couchDoc.jsonValue, doc); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bulk load document")
+	}
+	return doc, nil
+}
+
+// createIndexesOfInterruptedBulkLoad creates the indexes deferred by a bulk load
+// which did not reach its end, as the rest of the rebuild is not a bulk load
+func (vdb *VersionedDB) createIndexesOfInterruptedBulkLoad() error {
+	doc, err := vdb.readBulkLoadDoc()
+	if err != nil || doc == nil {
+		return err
+	}
+	logger.Infof("[%s] Creating the indexes of %d chaincode deployments deferred by an interrupted bulk load",
+		vdb.chainName, len(doc.Indexes))
+	for _, idx := range doc.Indexes {
+		if err := vdb.ProcessIndexesForChaincodeDeploy(idx.Namespace, idx.IndexFilesData); err != nil {
+			return err
+		}
+	}
+	return vdb.metadataDB.deleteDoc(bulkLoadDocID, "")
+}
+
+// StartBulkLoad implements method in statedb.BulkLoadCapable interface. During
+// the load, the revisions of the documents are not looked up in CouchDB, the
+// updates are sent in batches of BulkLoadMaxBatchUpdateSize documents and the
+// indexes of the chaincodes are neither created nor warmed.
+func (vdb *VersionedDB) StartBulkLoad() (bool, error) {
+	savepoint, err := vdb.GetLatestSavePoint()
+	if err != nil {
+		return false, err
+	}
+	vdb.mux.RLock()
+	namespaces := len(vdb.channelMetadata.NamespaceDBsInfo)
+	vdb.mux.RUnlock()
+	if savepoint != nil || namespaces != 0 {
+		logger.Infof("[%s] Not starting a bulk load as the state database is not empty", vdb.chainName)
+		return false, nil
+	}
+
+	vdb.bulkLoadLock.Lock()
+	defer vdb.bulkLoadLock.Unlock()
+	if vdb.bulkLoad == nil {
+		logger.Infof("[%s] Starting a bulk load of the state database", vdb.chainName)
+		b := newBulkLoad()
+		if err := vdb.writeBulkLoadDoc(b.doc); err != nil {
+			return false, err
+		}
+		vdb.bulkLoad = b
+	}
+	return true, nil
+}
+
+// EndBulkLoad implements method in statedb.BulkLoadCapable interface. It creates
+// the indexes of the chaincodes deployed during the load, in the order of their
+// deployment, and warms them in the background.
+func (vdb *VersionedDB) EndBulkLoad() error {
+	vdb.bulkLoadLock.Lock()
+	b := vdb.bulkLoad
+	vdb.bulkLoad = nil
+	vdb.bulkLoadLock.Unlock()
+	if b == nil {
+		return nil
+	}
+
+	logger.Infof("[%s] Ending the bulk load of the state database, creating the indexes of %d chaincode deployments",
+		vdb.chainName, len(b.doc.Indexes))
+	var warmUp []*couchDatabase
+	warming := map[string]bool{}
+	for _, idx := range b.doc.Indexes {
+		if err := vdb.ProcessIndexesForChaincodeDeploy(idx.Namespace, idx.IndexFilesData); err != nil {
+			return err
+		}
+		if warming[idx.Namespace] {
+			continue
+		}
+		db, err := vdb.getNamespaceDBHandle(idx.Namespace)
+		if err != nil {
+			return err
+		}
+		warming[idx.Namespace] = true
+		warmUp = append(warmUp, db)
+	}
+	if err := vdb.metadataDB.deleteDoc(bulkLoadDocID, ""); err != nil {
+		return err
+	}
+	for _, db := range warmUp {
+		go db.runWarmIndexAllIndexes()
+	}
+	return nil
+}
+
+// getBulkLoad returns the bulk load in progress, if any
+func (vdb *VersionedDB) getBulkLoad() *bulkLoad {
+	vdb.bulkLoadLock.RLock()
+	defer vdb.bulkLoadLock.RUnlock()
+	return vdb.bulkLoad
+}
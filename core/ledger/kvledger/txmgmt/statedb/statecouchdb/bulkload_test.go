@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkLoadRevisions(t *testing.T) {
+	b := newBulkLoad()
+	b.setRevision("ns", "key1", "1-a")
+	b.setRevision("ns", "key2", "1-b")
+	b.setRevision("ns", "key2", "")
+	b.setRevision("ns2", "key3", "")
+
+	require.True(t, b.mayExist("ns", "key1"))
+	require.False(t, b.mayExist("ns", "key2"))
+	require.False(t, b.mayExist("ns2", "key3"))
+
+	revs := map[string]string{}
+	require.Empty(t, b.addRevisions("ns", []string{"key1", "key2", "key4"}, revs))
+	require.Equal(t, map[string]string{"key1": "1-a"}, revs)
+}
+
+func TestBulkLoadRevisionsOverflow(t *testing.T) {
+	defer func(max int) { maxBulkLoadRevisions = max }(maxBulkLoadRevisions)
+	maxBulkLoadRevisions = 2
+
+	b := newBulkLoad()
+	b.setRevision("ns", "key1", "1-a")
+	b.setRevision("ns", "key2", "1-b")
+	b.setRevision("ns", "key1", "2-a")
+	require.False(t, b.overflowed)
+	require.False(t, b.mayExist("ns", "key3"))
+
+	// the revisions are dropped once more documents than the bound are written
+	b.setRevision("ns", "key3", "1-c")
+	require.True(t, b.overflowed)
+	require.Nil(t, b.revisions)
+	require.True(t, b.mayExist("ns", "key4"))
+	revs := map[string]string{}
+	require.Equal(t, []string{"key1", "key4"}, b.addRevisions("ns", []string{"key1", "key4"}, revs))
+	require.Empty(t, revs)
+}
+
+func TestBulkLoad(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+
+	versionedDB, err := vdbEnv.DBProvider.GetDBHandle("test-bulk-load", nil)
+	require.NoError(t, err)
+	db := versionedDB.(*VersionedDB)
+
+	started, err := db.StartBulkLoad()
+	require.NoError(t, err)
+	require.True(t, started)
+
+	indexData := map[string][]byte{
+		"META-INF/statedb/couchdb/indexes/indexSizeSortName.json": []byte(`{"index":{"fields":[{"size":"desc"}]},"ddoc":"indexSizeSortName","name":"indexSizeSortName","type":"json"}`),
+	}
+	require.NoError(t, db.ProcessIndexesForChaincodeDeploy("ns1", indexData))
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"size":1}`), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte(`{"size":2}`), version.NewHeight(1, 2))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 2)))
+
+	nsDB, err := db.getNamespaceDBHandle("ns1")
+	require.NoError(t, err)
+	indexes, err := nsDB.listIndex()
+	require.NoError(t, err)
+	require.Empty(t, indexes)
+
+	// the updates of the keys written during the load use the recorded revisions,
+	// the other keys are known to be missing
+	require.NoError(t, db.LoadCommittedVersions([]*statedb.CompositeKey{{Namespace: "ns1", Key: "key1"}, {Namespace: "ns1", Key: "key3"}}))
+	ver, ok := db.GetCachedVersion("ns1", "key1")
+	require.True(t, ok)
+	require.Equal(t, version.NewHeight(1, 1), ver)
+	ver, ok = db.GetCachedVersion("ns1", "key3")
+	require.True(t, ok)
+	require.Nil(t, ver)
+
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key2", []byte(`{"size":3}`), version.NewHeight(2, 1))
+	batch.Delete("ns1", "key1", version.NewHeight(2, 2))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 2)))
+	require.False(t, db.getBulkLoad().mayExist("ns1", "key1"))
+
+	committers, err := db.buildCommittersForNs("ns1", map[string]*statedb.VersionedValue{
+		"key2": {Value: []byte(`{"size":4}`), Version: version.NewHeight(3, 1)},
+	})
+	require.NoError(t, err)
+	require.Len(t, committers, 1)
+	require.Contains(t, string(committers[0].batchUpdateMap["key2"].CouchDoc.jsonValue), revField)
+
+	// the deferred indexes are persisted until the end of the load
+	doc, err := db.readBulkLoadDoc()
+	require.NoError(t, err)
+	require.Equal(t, &bulkLoadDoc{Indexes: []*deferredIndexes{{Namespace: "ns1", IndexFilesData: indexData}}}, doc)
+
+	require.NoError(t, db.EndBulkLoad())
+	require.Nil(t, db.getBulkLoad())
+	doc, err = db.readBulkLoadDoc()
+	require.NoError(t, err)
+	require.Nil(t, doc)
+	indexes, err = nsDB.listIndex()
+	require.NoError(t, err)
+	require.Len(t, indexes, 1)
+
+	vv, err := db.GetState("ns1", "key2")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"size":3}`, string(vv.Value))
+	vv, err = db.GetState("ns1", "key1")
+	require.NoError(t, err)
+	require.Nil(t, vv)
+
+	// a bulk load is not started on a database that is not empty
+	started, err = db.StartBulkLoad()
+	require.NoError(t, err)
+	require.False(t, started)
+}
+
+func TestBulkLoadInterrupted(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+
+	versionedDB, err := vdbEnv.DBProvider.GetDBHandle("test-bulk-load-interrupted", nil)
+	require.NoError(t, err)
+	db := versionedDB.(*VersionedDB)
+
+	started, err := db.StartBulkLoad()
+	require.NoError(t, err)
+	require.True(t, started)
+	indexData := map[string][]byte{
+		"META-INF/statedb/couchdb/indexes/indexSizeSortName.json": []byte(`{"index":{"fields":[{"size":"desc"}]},"ddoc":"indexSizeSortName","name":"indexSizeSortName","type":"json"}`),
+	}
+	require.NoError(t, db.ProcessIndexesForChaincodeDeploy("ns1", indexData))
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"size":1}`), version.NewHeight(1, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+
+	// the peer stops before the end of the load, the deferred indexes are
+	// created when the database is opened again
+	vdbEnv.closeAndReopen()
+	versionedDB, err = vdbEnv.DBProvider.GetDBHandle("test-bulk-load-interrupted", nil)
+	require.NoError(t, err)
+	db = versionedDB.(*VersionedDB)
+	nsDB, err := db.getNamespaceDBHandle("ns1")
+	require.NoError(t, err)
+	indexes, err := nsDB.listIndex()
+	require.NoError(t, err)
+	require.Len(t, indexes, 1)
+	doc, err := db.readBulkLoadDoc()
+	require.NoError(t, err)
+	require.Nil(t, doc)
+
+	started, err = db.StartBulkLoad()
+	require.NoError(t, err)
+	require.False(t, started)
+}
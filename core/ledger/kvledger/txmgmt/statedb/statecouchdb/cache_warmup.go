@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// warmCache loads the states of the given namespaces into the state cache, so that
+// the reads following the start of the peer do not all miss the cache. The conflicts
+// of the documents are repaired along the way. As a failure only leaves the cache
+// cold, it is logged rather than returned.
+func (vdb *VersionedDB) warmCache(namespaces []string) {
+	for _, ns := range namespaces {
+		if !vdb.cache.enabled(ns) {
+			continue
+		}
+		if _, ok := vdb.channelMetadata.NamespaceDBsInfo[ns]; !ok {
+			logger.Debugf("[%s] skipping the cache warm-up of namespace [%s], no database exists for it", vdb.chainName, ns)
+			continue
+		}
+		startTime := time.Now()
+		count, err := vdb.warmNamespaceCache(ns)
+		if err != nil {
+			logger.Warningf("[%s] Failed to warm the state cache of namespace [%s] after loading %d keys: %s", vdb.chainName, ns, count, err)
+			continue
+		}
+		logger.Infof("[%s] Warmed the state cache of namespace [%s] with %d keys in %s", vdb.chainName, ns, count, time.Since(startTime))
+	}
+}
+
+// warmNamespaceCache loads all the states of a namespace into the state cache,
+// fetching the documents in bulk, and returns the number of states loaded.
+func (vdb *VersionedDB) warmNamespaceCache(namespace string) (int, error) {
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	startKey := ""
+	for {
+		results, nextStartKey, err := db.readDocRangeWithOptions(startKey, "", db.couchInstance.internalQueryLimit(), conflictsOption)
+		if err != nil {
+			return count, err
+		}
+		for _, result := range results {
+			if isCouchInternalKey(result.id) {
+				continue
+			}
+			doc, err := db.repairConflicts(&couchDoc{jsonValue: result.value, attachments: result.attachments})
+			if err != nil {
+				return count, err
+			}
+			kv, err := couchDocToKeyValue(doc)
+			if err != nil {
+				return count, errors.WithMessagef(err, "failed to decode document %s", result.id)
+			}
+			if err := vdb.cache.putState(vdb.chainName, namespace, kv.key, constructCacheValue(kv.VersionedValue, kv.revision)); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(results) == 0 || nextStartKey == "" {
+			return count, nil
+		}
+		startKey = nextStartKey
+	}
+}
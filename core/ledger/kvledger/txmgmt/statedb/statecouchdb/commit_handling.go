@@ -21,6 +21,11 @@ type committer struct {
 	namespace      string
 	cacheKVs       cacheKVs
 	cacheEnabled   bool
+	// largeDocs holds the documents with chunked binary values, which are
+	// saved individually rather than inlined in the bulk update
+	largeDocs map[string]*couchDoc
+	// bulkLoad records the revisions of the saved documents during a bulk load
+	bulkLoad *bulkLoad
 }
 
 func (c *committer) addToCacheUpdate(kv *keyValue) {
@@ -43,6 +48,13 @@ func (c *committer) addToCacheUpdate(kv *keyValue) {
 }
 
 func (c *committer) updateRevisionInCacheUpdate(key, rev string) {
+	if c.bulkLoad != nil {
+		if doc, ok := c.batchUpdateMap[key]; ok && doc.Deleted {
+			// a deleted document is created anew, without a revision
+			rev = ""
+		}
+		c.bulkLoad.setRevision(c.namespace, key, rev)
+	}
 	if !c.cacheEnabled {
 		return
 	}
@@ -105,6 +117,11 @@ func (vdb *VersionedDB) buildCommittersForNs(ns string, nsUpdates map[string]*st
 	}
 	// for each namespace, build mutiple committers based on the maxBatchSize
 	maxBatchSize := db.couchInstance.maxBatchUpdateSize()
+	bulkLoad := vdb.getBulkLoad()
+	if bulkLoad != nil {
+		maxBatchSize = db.couchInstance.bulkLoadMaxBatchUpdateSize()
+	}
+	attachmentThreshold := db.couchInstance.attachmentThreshold()
 	numCommitters := 1
 	if maxBatchSize > 0 {
 		numCommitters = int(math.Ceil(float64(len(nsUpdates)) / float64(maxBatchSize)))
@@ -117,9 +134,11 @@ func (vdb *VersionedDB) buildCommittersForNs(ns string, nsUpdates map[string]*st
 		committers[i] = &committer{
 			db:             db,
 			batchUpdateMap: make(map[string]*batchableDocument),
+			largeDocs:      make(map[string]*couchDoc),
 			namespace:      ns,
 			cacheKVs:       make(cacheKVs),
 			cacheEnabled:   cacheEnabled,
+			bulkLoad:       bulkLoad,
 		}
 	}
 
@@ -137,6 +156,11 @@ func (vdb *VersionedDB) buildCommittersForNs(ns string, nsUpdates map[string]*st
 		if err != nil {
 			return nil, err
 		}
+		if splitValueAttachment(couchDoc, attachmentThreshold) {
+			committers[i].largeDocs[key] = couchDoc
+			committers[i].addToCacheUpdate(kv)
+			continue
+		}
 		committers[i].batchUpdateMap[key] = &batchableDocument{CouchDoc: *couchDoc, Deleted: vv.Value == nil}
 		committers[i].addToCacheUpdate(kv)
 		if maxBatchSize > 0 && len(committers[i].batchUpdateMap) == maxBatchSize {
@@ -177,6 +201,13 @@ func (c *committer) commitUpdates() error {
 		docs = append(docs, &update.CouchDoc)
 	}
 
+	if err := c.commitLargeDocs(); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
 	// Do the bulk update into couchdb. Note that this will do retries if the entire bulk update fails or times out
 	responses, err := c.db.batchUpdateDocuments(docs)
 	if err != nil {
@@ -210,6 +241,7 @@ func (c *committer) commitUpdates() error {
 			// If the delete fails due to a document not being found (404 error),
 			// the document has already been deleted and the DeleteDoc will not return an error
 			err = c.db.deleteDoc(resp.ID, "")
+			c.updateRevisionInCacheUpdate(resp.ID, "")
 		} else {
 			logger.Warningf("CouchDB batch document update encountered an problem. Reason:%s, Retrying update for document ID:%s", resp.Reason, resp.ID)
 			// Save the individual document to couchdb
@@ -231,6 +263,25 @@ func (c *committer) commitUpdates() error {
 	return nil
 }
 
+// commitLargeDocs saves the documents with chunked binary values one by one.
+// The attachments are sent as raw multipart data rather than base64 encoded
+// in the bulk update.
+func (c *committer) commitLargeDocs() error {
+	for id, doc := range c.largeDocs {
+		// the revision is looked up by saveDoc, as on the retry of the
+		// documents failing the bulk update
+		if err := removeJSONRevision(&doc.jsonValue); err != nil {
+			return err
+		}
+		revision, err := c.db.saveDoc(id, "", doc)
+		if err != nil {
+			return errors.WithMessagef(err, "error saving document ID: %s with a large value", id)
+		}
+		c.updateRevisionInCacheUpdate(id, revision)
+	}
+	return nil
+}
+
 func (vdb *VersionedDB) getRevisions(ns string, nsUpdates map[string]*statedb.VersionedValue) (map[string]string, error) {
 	revisions := make(map[string]string)
 	nsRevs := vdb.committedDataCache.revs[ns]
@@ -248,6 +299,13 @@ func (vdb *VersionedDB) getRevisions(ns string, nsUpdates map[string]*statedb.Ve
 		return revisions, nil
 	}
 
+	if b := vdb.getBulkLoad(); b != nil {
+		// the database holds only the documents written during the load
+		if missingKeys = b.addRevisions(ns, missingKeys, revisions); len(missingKeys) == 0 {
+			return revisions, nil
+		}
+	}
+
 	missingKeys, err := vdb.addMissingRevisionsFromCache(ns, missingKeys, revisions)
 	if err != nil {
 		return nil, err
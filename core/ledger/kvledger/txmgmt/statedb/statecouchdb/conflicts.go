@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/pkg/errors"
+)
+
+const conflictsField = "_conflicts"
+
+// conflictsOption asks CouchDB to report the conflicting revisions of the
+// documents it returns.
+var conflictsOption = url.Values{"conflicts": []string{"true"}}
+
+// conflictsInfo holds the revision and the conflicting revisions of a document.
+type conflictsInfo struct {
+	ID        string   `json:"_id"`
+	Rev       string   `json:"_rev"`
+	Conflicts []string `json:"_conflicts"`
+}
+
+// conflicts returns the revision and the conflicting revisions of a document
+// read with the conflicts option. A document only has conflicting revisions when
+// it was edited outside of the peer, such as by a manual edit or a replication.
+func (d *couchDoc) conflicts() (*conflictsInfo, error) {
+	info := &conflictsInfo{}
+	if err := json.Unmarshal(d.jsonValue, info); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling json data")
+	}
+	return info, nil
+}
+
+// ledgerVersion returns the ledger version stored in a document, or nil when
+// the document does not carry a valid one.
+func (d *couchDoc) ledgerVersion() *version.Height {
+	fields := struct {
+		Version string `json:"~version"`
+	}{}
+	if err := json.Unmarshal(d.jsonValue, &fields); err != nil || fields.Version == "" {
+		return nil
+	}
+	ver, _, err := decodeVersionAndMetadata(fields.Version)
+	if err != nil {
+		return nil
+	}
+	return ver
+}
+
+type docRevision struct {
+	rev string
+	doc *couchDoc
+}
+
+// repairConflicts removes the conflicting revisions of a document read with the
+// conflicts option, so that reads cannot silently return a stale value picked by
+// CouchDB as the winning revision. The revision carrying the highest ledger version
+// is kept, the winning revision being kept on a tie. It returns the kept document.
+func (dbclient *couchDatabase) repairConflicts(doc *couchDoc) (*couchDoc, error) {
+	info, err := doc.conflicts()
+	if err != nil || len(info.Conflicts) == 0 {
+		return doc, err
+	}
+	id := info.ID
+
+	revisions := []*docRevision{{rev: info.Rev, doc: doc}}
+	for _, conflict := range info.Conflicts {
+		conflictDoc, _, err := dbclient.readDocWithOptions(id, url.Values{"rev": []string{conflict}})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to read revision %s of document %s", conflict, id)
+		}
+		if conflictDoc == nil {
+			continue
+		}
+		revisions = append(revisions, &docRevision{rev: conflict, doc: conflictDoc})
+	}
+
+	kept := selectRevision(revisions)
+	var deletions []*couchDoc
+	for _, r := range revisions {
+		if r == kept {
+			continue
+		}
+		deletion, err := json.Marshal(map[string]interface{}{idField: id, revField: r.rev, deletedField: true})
+		if err != nil {
+			return nil, errors.Wrap(err, "error marshalling json data")
+		}
+		deletions = append(deletions, &couchDoc{jsonValue: deletion})
+	}
+	if len(deletions) == 0 {
+		return kept.doc, nil
+	}
+
+	responses, err := dbclient.batchUpdateDocuments(deletions)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to remove the conflicting revisions of document %s", id)
+	}
+	for _, resp := range responses {
+		if !resp.Ok {
+			return nil, errors.Errorf("failed to remove a conflicting revision of document %s: %s %s", id, resp.Error, resp.Reason)
+		}
+	}
+
+	couchdbLogger.Warningf("[%s] Repaired document [%s]: kept revision %s, removed %d conflicting revisions", dbclient.dbName, id, kept.rev, len(deletions))
+	dbclient.couchInstance.stats.recordConflictRepaired(dbclient.dbName)
+	return kept.doc, nil
+}
+
+// selectRevision returns the revision with the highest ledger version,
+// preferring the first one on a tie.
+func selectRevision(revisions []*docRevision) *docRevision {
+	selected := revisions[0]
+	selectedVersion := selected.doc.ledgerVersion()
+	for _, r := range revisions[1:] {
+		ver := r.doc.ledgerVersion()
+		if ver == nil {
+			continue
+		}
+		if selectedVersion == nil || ver.Compare(selectedVersion) > 0 {
+			selected, selectedVersion = r, ver
+		}
+	}
+	return selected
+}
@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCouchDB serves the documents of a single database, keyed by id and revision,
+// and records the revisions deleted through _bulk_docs.
+type fakeCouchDB struct {
+	t       *testing.T
+	mutex   sync.Mutex
+	docs    map[string]map[string]string // id -> rev -> json
+	winners map[string]string            // id -> winning rev
+	deleted []string
+}
+
+func (f *fakeCouchDB) addDoc(id, rev string, ver *version.Height, value string, winner bool) {
+	doc := map[string]interface{}{idField: id, revField: rev, "asset": value}
+	if ver != nil {
+		encodedVersion, err := encodeVersionAndMetadata(ver, nil)
+		require.NoError(f.t, err)
+		doc[versionField] = encodedVersion
+	}
+	docJSON, err := json.Marshal(doc)
+	require.NoError(f.t, err)
+	if f.docs[id] == nil {
+		f.docs[id] = map[string]string{}
+	}
+	f.docs[id][rev] = string(docJSON)
+	if winner {
+		f.winners[id] = rev
+	}
+}
+
+// winningDoc returns the winning revision of a document along with its conflicts.
+func (f *fakeCouchDB) winningDoc(id string) (string, string) {
+	rev := f.winners[id]
+	doc := map[string]interface{}{}
+	require.NoError(f.t, json.Unmarshal([]byte(f.docs[id][rev]), &doc))
+	var conflicts []string
+	for r := range f.docs[id] {
+		if r != rev {
+			conflicts = append(conflicts, r)
+		}
+	}
+	if len(conflicts) > 0 {
+		doc[conflictsField] = conflicts
+	}
+	docJSON, err := json.Marshal(doc)
+	require.NoError(f.t, err)
+	return rev, string(docJSON)
+}
+
+func (f *fakeCouchDB) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	path := strings.TrimPrefix(req.URL.Path, "/testdb/")
+	switch {
+	case req.Method == http.MethodPost && path == "_bulk_docs":
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(f.t, err)
+		bulk := struct {
+			Docs []map[string]interface{} `json:"docs"`
+		}{}
+		require.NoError(f.t, json.Unmarshal(body, &bulk))
+		var responses []string
+		for _, doc := range bulk.Docs {
+			id, rev := doc[idField].(string), doc[revField].(string)
+			assert.Equal(f.t, true, doc[deletedField])
+			delete(f.docs[id], rev)
+			f.deleted = append(f.deleted, rev)
+			if f.winners[id] == rev {
+				for r := range f.docs[id] {
+					f.winners[id] = r
+				}
+			}
+			responses = append(responses, fmt.Sprintf(`{"id":%q,"ok":true}`, id))
+		}
+		resp.Write([]byte("[" + strings.Join(responses, ",") + "]"))
+	case req.Method == http.MethodGet && path == "_all_docs":
+		assert.Equal(f.t, "true", req.URL.Query().Get("conflicts"))
+		var rows []string
+		for _, id := range []string{"_design/index", "key1", "key2"} {
+			if f.docs[id] == nil {
+				continue
+			}
+			_, doc := f.winningDoc(id)
+			rows = append(rows, fmt.Sprintf(`{"id":%q,"key":%q,"doc":%s}`, id, id, doc))
+		}
+		fmt.Fprintf(resp, `{"total_rows":%d,"rows":[%s]}`, len(rows), strings.Join(rows, ","))
+	case req.Method == http.MethodGet:
+		if f.docs[path] == nil {
+			resp.WriteHeader(http.StatusNotFound)
+			resp.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		rev, doc := f.winningDoc(path)
+		if r := req.URL.Query().Get("rev"); r != "" {
+			rev, doc = r, f.docs[path][r]
+		} else {
+			assert.Equal(f.t, "true", req.URL.Query().Get("conflicts"))
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		resp.Header().Set("Etag", fmt.Sprintf("%q", rev))
+		resp.Write([]byte(doc))
+	default:
+		resp.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newFakeCouchDB(t *testing.T) (*fakeCouchDB, *couchDatabase, *metricsfakes.Counter) {
+	fake := &fakeCouchDB{
+		t:       t,
+		docs:    map[string]map[string]string{},
+		winners: map[string]string{},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	fakeHistogram := &metricsfakes.Histogram{}
+	fakeHistogram.WithReturns(fakeHistogram)
+	fakeCounter := &metricsfakes.Counter{}
+	fakeCounter.WithReturns(fakeCounter)
+	couchInstance := &couchInstance{
+		conf: &ledger.CouchDBConfig{
+			Address:            strings.TrimPrefix(server.URL, "http://"),
+			InternalQueryLimit: 1000,
+		},
+		client: server.Client(),
+		stats: &stats{
+			apiProcessingTime: fakeHistogram,
+			conflictsRepaired: fakeCounter,
+		},
+	}
+	return fake, &couchDatabase{couchInstance: couchInstance, dbName: "testdb"}, fakeCounter
+}
+
+func TestRepairConflicts(t *testing.T) {
+	fake, db, fakeCounter := newFakeCouchDB(t)
+	// a replication made a stale revision win over the one committed by the peer,
+	// and a manual edit removed the ledger version of another one
+	fake.addDoc("key1", "2-stale", version.NewHeight(1, 0), "stale", true)
+	fake.addDoc("key1", "2-committed", version.NewHeight(3, 1), "committed", false)
+	fake.addDoc("key1", "3-edited", nil, "edited", false)
+
+	doc, _, err := db.readDocWithOptions("key1", conflictsOption)
+	require.NoError(t, err)
+	doc, err = db.repairConflicts(doc)
+	require.NoError(t, err)
+
+	kv, err := couchDocToKeyValue(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "2-committed", kv.revision)
+	assert.Equal(t, version.NewHeight(3, 1), kv.Version)
+	assert.JSONEq(t, `{"asset":"committed"}`, string(kv.Value))
+	assert.ElementsMatch(t, []string{"2-stale", "3-edited"}, fake.deleted)
+
+	require.Equal(t, 1, fakeCounter.AddCallCount())
+	assert.Equal(t, float64(1), fakeCounter.AddArgsForCall(0))
+	assert.Equal(t, []string{"database", "testdb"}, fakeCounter.WithArgsForCall(0))
+
+	// once repaired, the document has no conflict left
+	doc, _, err = db.readDocWithOptions("key1", conflictsOption)
+	require.NoError(t, err)
+	_, err = db.repairConflicts(doc)
+	require.NoError(t, err)
+	assert.Len(t, fake.deleted, 2)
+	assert.Equal(t, 1, fakeCounter.AddCallCount())
+}
+
+func TestRepairConflictsKeepsWinnerOnTie(t *testing.T) {
+	fake, db, _ := newFakeCouchDB(t)
+	fake.addDoc("key1", "2-a", version.NewHeight(2, 0), "a", false)
+	fake.addDoc("key1", "2-b", version.NewHeight(2, 0), "b", true)
+
+	doc, _, err := db.readDocWithOptions("key1", conflictsOption)
+	require.NoError(t, err)
+	doc, err = db.repairConflicts(doc)
+	require.NoError(t, err)
+
+	kv, err := couchDocToKeyValue(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", kv.revision)
+	assert.Equal(t, []string{"2-a"}, fake.deleted)
+}
+
+func TestReadFromDBRepairsConflicts(t *testing.T) {
+	fake, db, _ := newFakeCouchDB(t)
+	fake.addDoc("key1", "2-stale", version.NewHeight(1, 0), "stale", true)
+	fake.addDoc("key1", "2-committed", version.NewHeight(3, 1), "committed", false)
+
+	vdb := &VersionedDB{
+		chainName:    "testchannel",
+		namespaceDBs: map[string]*couchDatabase{"ns": db},
+		cache:        newCache(32, nil),
+	}
+	vv, err := vdb.GetState("ns", "key1")
+	require.NoError(t, err)
+	assert.Equal(t, version.NewHeight(3, 1), vv.Version)
+	assert.Equal(t, []string{"2-stale"}, fake.deleted)
+
+	cv, err := vdb.cache.getState("testchannel", "ns", "key1")
+	require.NoError(t, err)
+	require.NotNil(t, cv)
+	assert.Equal(t, "2-committed", string(cv.AdditionalInfo))
+}
+
+func TestWarmCache(t *testing.T) {
+	fake, db, fakeCounter := newFakeCouchDB(t)
+	fake.addDoc("_design/index", "1-a", nil, "index", true)
+	fake.addDoc("key1", "1-a", version.NewHeight(1, 0), "value1", true)
+	fake.addDoc("key2", "2-stale", version.NewHeight(1, 1), "stale", true)
+	fake.addDoc("key2", "2-committed", version.NewHeight(2, 0), "value2", false)
+
+	vdb := &VersionedDB{
+		chainName:    "testchannel",
+		namespaceDBs: map[string]*couchDatabase{"ns": db},
+		channelMetadata: &channelMetadata{
+			ChannelName: "testchannel",
+			NamespaceDBsInfo: map[string]*namespaceDBInfo{
+				"ns": {Namespace: "ns", DBName: "testdb"},
+			},
+		},
+		cache: newCache(32, nil),
+	}
+	vdb.warmCache([]string{"ns", "missing"})
+
+	cv, err := vdb.cache.getState("testchannel", "ns", "key1")
+	require.NoError(t, err)
+	require.NotNil(t, cv)
+	assert.Equal(t, "1-a", string(cv.AdditionalInfo))
+
+	cv, err = vdb.cache.getState("testchannel", "ns", "key2")
+	require.NoError(t, err)
+	require.NotNil(t, cv)
+	assert.Equal(t, "2-committed", string(cv.AdditionalInfo))
+	vv, err := constructVersionedValue(cv)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"asset":"value2"}`, string(vv.Value))
+	assert.Equal(t, []string{"2-stale"}, fake.deleted)
+	assert.Equal(t, 1, fakeCounter.AddCallCount())
+
+	cv, err = vdb.cache.getState("testchannel", "ns", "_design/index")
+	require.NoError(t, err)
+	assert.Nil(t, cv)
+}
+
+func TestWarmCacheDisabled(t *testing.T) {
+	_, db, _ := newFakeCouchDB(t)
+	vdb := &VersionedDB{
+		chainName:    "testchannel",
+		namespaceDBs: map[string]*couchDatabase{"ns": db},
+		channelMetadata: &channelMetadata{
+			NamespaceDBsInfo: map[string]*namespaceDBInfo{"ns": {Namespace: "ns"}},
+		},
+		cache: newCache(0, nil),
+	}
+	// without user cache, no request is sent to CouchDB
+	db.couchInstance.conf.Address = "127.0.0.1:0"
+	vdb.warmCache([]string{"ns"})
+}
@@ -464,6 +464,21 @@ func (couchInstance *couchInstance) maxBatchUpdateSize() int {
 	return couchInstance.conf.MaxBatchUpdateSize
 }
 
+// bulkLoadMaxBatchUpdateSize returns the maximum number of records to include
+// in a bulk update operation during a bulk load.
+func (couchInstance *couchInstance) bulkLoadMaxBatchUpdateSize() int {
+	if couchInstance.conf.BulkLoadMaxBatchUpdateSize <= 0 {
+		return couchInstance.conf.MaxBatchUpdateSize
+	}
+	return couchInstance.conf.BulkLoadMaxBatchUpdateSize
+}
+
+// attachmentThreshold returns the size above which binary values are stored
+// as chunked attachments written outside of bulk update operations.
+func (couchInstance *couchInstance) attachmentThreshold() int {
+	return couchInstance.conf.AttachmentThreshold
+}
+
 // url returns the URL for the CouchDB instance.
 func (couchInstance *couchInstance) url() string {
 	URL := &url.URL{
@@ -710,6 +725,12 @@ func getRevisionHeader(resp *http.Response) (string, error) {
 //readDoc method provides function to retrieve a document and its revision
 //from the database by id
 func (dbclient *couchDatabase) readDoc(id string) (*couchDoc, string, error) {
+	return dbclient.readDocWithOptions(id, nil)
+}
+
+//readDocWithOptions retrieves a document and its revision, passing the additional
+//query options, such as conflicts or rev, to CouchDB
+func (dbclient *couchDatabase) readDocWithOptions(id string, options url.Values) (*couchDoc, string, error) {
 	var couchDoc couchDoc
 	attachments := []*attachmentInfo{}
 	dbName := dbclient.dbName
@@ -727,6 +748,7 @@ func (dbclient *couchDatabase) readDoc(id string) (*couchDoc, string, error) {
 
 	query := readURL.Query()
 	query.Add("attachments", "true")
+	addQueryOptions(query, options)
 
 	//get the number of retries
 	maxRetries := dbclient.couchInstance.conf.MaxRetries
@@ -844,6 +866,12 @@ func (dbclient *couchDatabase) readDoc(id string) (*couchDoc, string, error) {
 //This function provides a limit option to specify the max number of entries and is supplied by config.
 //Skip is reserved for possible future future use.
 func (dbclient *couchDatabase) readDocRange(startKey, endKey string, limit int32) ([]*queryResult, string, error) {
+	return dbclient.readDocRangeWithOptions(startKey, endKey, limit, nil)
+}
+
+//readDocRangeWithOptions retrieves a range of documents, passing the additional
+//query options, such as conflicts, to CouchDB
+func (dbclient *couchDatabase) readDocRangeWithOptions(startKey, endKey string, limit int32, options url.Values) ([]*queryResult, string, error) {
 	dbName := dbclient.dbName
 	couchdbLogger.Debugf("[%s] Entering ReadDocRange()  startKey=%s, endKey=%s", dbName, startKey, endKey)
 
@@ -861,6 +889,7 @@ func (dbclient *couchDatabase) readDocRange(startKey, endKey string, limit int32
 	queryParms.Add("include_docs", "true")
 	queryParms.Add("inclusive_end", "false") // endkey should be exclusive to be consistent with goleveldb
 	queryParms.Add("attachments", "true")    // get the attachments as well
+	addQueryOptions(queryParms, options)
 
 	//Append the startKey if provided
 	if startKey != "" {
@@ -966,6 +995,15 @@ func (dbclient *couchDatabase) readDocRange(startKey, endKey string, limit int32
 
 }
 
+//addQueryOptions adds the additional options to the query parameters of a request
+func addQueryOptions(query url.Values, options url.Values) {
+	for name, values := range options {
+		for _, value := range values {
+			query.Add(name, value)
+		}
+	}
+}
+
 //deleteDoc method provides function to delete a document from the database by id
 func (dbclient *couchDatabase) deleteDoc(id, rev string) error {
 	dbName := dbclient.dbName
@@ -62,6 +62,19 @@ func TestDBBadConnectionDef(t *testing.T) {
 	require.Error(t, err, "Did not receive error when trying to create database connection definition with a bad hostname")
 }
 
+func TestVerifyCouchDBBadConnection(t *testing.T) {
+	config := &ledger.CouchDBConfig{
+		Address:             badParseConnectURL,
+		Username:            "admin",
+		Password:            "adminpw",
+		MaxRetries:          3,
+		MaxRetriesOnStartup: 3,
+		RequestTimeout:      35 * time.Second,
+	}
+	err := VerifyCouchDB(config)
+	require.Error(t, err, "Did not receive error when verifying a database connection definition with a bad hostname")
+}
+
 func TestEncodePathElement(t *testing.T) {
 	encodedString := encodePathElement("testelement")
 	require.Equal(t, "testelement", encodedString)
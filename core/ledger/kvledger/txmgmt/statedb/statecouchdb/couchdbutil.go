@@ -303,6 +303,13 @@ func escapeUpperCase(dbName string) string {
 	return strings.ToLower(dbName)
 }
 
+// VerifyCouchDB checks that CouchDB is reachable at the configured address, that
+// the configured admin credentials are valid and that its version is supported.
+func VerifyCouchDB(config *ledger.CouchDBConfig) error {
+	_, err := createCouchInstance(config, &disabled.Provider{})
+	return err
+}
+
 // DropApplicationDBs drops all application databases.
 func DropApplicationDBs(config *ledger.CouchDBConfig) error {
 	couchdbLogger.Info("Dropping CouchDB application databases ...")
@@ -323,6 +330,33 @@ func DropApplicationDBs(config *ledger.CouchDBConfig) error {
 	return nil
 }
 
+// InspectApplicationDBs returns the data format recorded in CouchDB and the names of
+// the application databases, which are dropped by DropApplicationDBs. Unlike opening
+// the state database, it does not create any database.
+func InspectApplicationDBs(config *ledger.CouchDBConfig) (string, []string, error) {
+	couchInstance, err := createCouchInstance(config, &disabled.Provider{})
+	if err != nil {
+		return "", nil, err
+	}
+	dbNames, err := couchInstance.retrieveApplicationDBNames()
+	if err != nil {
+		return "", nil, err
+	}
+	internalDB := &couchDatabase{
+		couchInstance: couchInstance,
+		dbName:        fabricInternalDBName,
+	}
+	doc, _, err := internalDB.readDoc(dataformatVersionDocID)
+	if err != nil || doc == nil {
+		return "", dbNames, err
+	}
+	format, err := decodeDataformatInfo(doc)
+	if err != nil {
+		return "", nil, err
+	}
+	return format, dbNames, nil
+}
+
 func dropDB(couchInstance *couchInstance, dbName string) (*dbOperationResponse, error) {
 	db := &couchDatabase{
 		couchInstance: couchInstance,
@@ -11,6 +11,7 @@ import (
 	fmt "fmt"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/stretchr/testify/require"
@@ -214,6 +215,30 @@ func TestDropApplicationDBs(t *testing.T) {
 	require.Equal(t, 0, len(dbs), "Databases should be dropped")
 }
 
+func TestInspectApplicationDBs(t *testing.T) {
+	config := testConfig()
+	couchDBEnv.startCouchDB(t)
+	config.Address = couchDBEnv.couchAddress
+	defer couchDBEnv.cleanup(config)
+
+	couchInstance, err := createCouchInstance(config, &disabled.Provider{})
+	require.NoError(t, err)
+
+	format, dbNames, err := InspectApplicationDBs(config)
+	require.NoError(t, err)
+	require.Equal(t, "", format)
+	require.Empty(t, dbNames)
+
+	_, err = createCouchDatabase(couchInstance, "testinspectapplicationdbs")
+	require.NoError(t, err)
+	require.NoError(t, writeDataFormatVersion(couchInstance, dataformat.CurrentFormat))
+
+	format, dbNames, err = InspectApplicationDBs(config)
+	require.NoError(t, err)
+	require.Equal(t, dataformat.CurrentFormat, format)
+	require.ElementsMatch(t, []string{fabricInternalDBName, "testinspectapplicationdbs"}, dbNames)
+}
+
 func TestDropApplicationDBsWhenDBNotStarted(t *testing.T) {
 	config := testConfig()
 	config.MaxRetriesOnStartup = 1
@@ -221,3 +246,11 @@ func TestDropApplicationDBsWhenDBNotStarted(t *testing.T) {
 	err := DropApplicationDBs(config)
 	require.EqualError(t, err, `unable to connect to CouchDB, check the hostname and port: http error calling couchdb: Get "http://127.0.0.1:5984/": dial tcp 127.0.0.1:5984: connect: connection refused`)
 }
+
+func TestInspectApplicationDBsWhenDBNotStarted(t *testing.T) {
+	config := testConfig()
+	config.MaxRetriesOnStartup = 1
+	config.Address = "127.0.0.1:5984"
+	_, _, err := InspectApplicationDBs(config)
+	require.EqualError(t, err, `unable to connect to CouchDB, check the hostname and port: http error calling couchdb: Get "http://127.0.0.1:5984/": dial tcp 127.0.0.1:5984: connect: connection refused`)
+}
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -26,6 +27,10 @@ const (
 	deletedField  = "_deleted"
 )
 
+// binaryWrapperChunkPrefix prefixes the names of the attachments holding the
+// chunks of a large binary value.
+const binaryWrapperChunkPrefix = binaryWrapper + "."
+
 type keyValue struct {
 	key      string
 	revision string
@@ -113,20 +118,79 @@ func validateAndRetrieveFields(doc *couchDoc) (*couchDocFields, error) {
 	delete(jsonDoc, idField)
 	delete(jsonDoc, revField)
 	delete(jsonDoc, versionField)
+	delete(jsonDoc, conflictsField)
 
 	var err error
 	if doc.attachments == nil {
 		docFields.value, err = json.Marshal(jsonDoc)
 		return docFields, err
 	}
+	chunks := map[int][]byte{}
 	for _, attachment := range doc.attachments {
-		if attachment.Name == binaryWrapper {
+		switch {
+		case attachment.Name == binaryWrapper:
 			docFields.value = attachment.AttachmentBytes
+		case strings.HasPrefix(attachment.Name, binaryWrapperChunkPrefix):
+			index, err := strconv.Atoi(strings.TrimPrefix(attachment.Name, binaryWrapperChunkPrefix))
+			if err != nil {
+				return nil, errors.Errorf("invalid value chunk attachment [%s]", attachment.Name)
+			}
+			chunks[index] = attachment.AttachmentBytes
 		}
 	}
+	if len(chunks) > 0 {
+		docFields.value, err = joinValueChunks(chunks)
+	}
 	return docFields, err
 }
 
+// joinValueChunks reassembles a value stored as chunked attachments.
+func joinValueChunks(chunks map[int][]byte) ([]byte, error) {
+	var size int
+	for i := 0; i < len(chunks); i++ {
+		chunk, ok := chunks[i]
+		if !ok {
+			return nil, errors.Errorf("value chunk attachment [%s%d] is missing", binaryWrapperChunkPrefix, i)
+		}
+		size += len(chunk)
+	}
+	value := make([]byte, 0, size)
+	for i := 0; i < len(chunks); i++ {
+		value = append(value, chunks[i]...)
+	}
+	return value, nil
+}
+
+// splitValueAttachment splits the binary value attachment of the document
+// into chunks of at most chunkSize bytes, so that large values do not hit the
+// size limits of CouchDB. It reports whether the document carries a binary
+// value larger than chunkSize.
+func splitValueAttachment(doc *couchDoc, chunkSize int) bool {
+	if chunkSize <= 0 || len(doc.attachments) != 1 || doc.attachments[0].Name != binaryWrapper {
+		return false
+	}
+	value := doc.attachments[0].AttachmentBytes
+	if len(value) <= chunkSize {
+		return false
+	}
+
+	var chunks []*attachmentInfo
+	for i := 0; len(value) > 0; i++ {
+		n := chunkSize
+		if n > len(value) {
+			n = len(value)
+		}
+		chunks = append(chunks, &attachmentInfo{
+			Name:            fmt.Sprintf("%s%d", binaryWrapperChunkPrefix, i),
+			ContentType:     "application/octet-stream",
+			AttachmentBytes: value[:n],
+		})
+		value = value[n:]
+	}
+	doc.attachments = chunks
+	return true
+}
+
 func keyValToCouchDoc(kv *keyValue) (*couchDoc, error) {
 	type kvType int32
 	const (
@@ -57,6 +57,43 @@ func testKVAndDocConversion(t *testing.T, kv *keyValue) {
 	require.Equal(t, kv, actualKV)
 }
 
+func TestValueChunking(t *testing.T) {
+	kv := &keyValue{
+		"key1", "rev1",
+		&statedb.VersionedValue{
+			Value:    []byte("0123456789abcdefghij"),
+			Version:  version.NewHeight(1, 1),
+			Metadata: []byte("metadata1"),
+		},
+	}
+	doc, err := keyValToCouchDoc(kv)
+	require.NoError(t, err)
+	require.False(t, splitValueAttachment(doc, 0))
+	require.False(t, splitValueAttachment(doc, 20))
+	require.True(t, splitValueAttachment(doc, 8))
+	require.Len(t, doc.attachments, 3)
+	require.Equal(t, "valueBytes.2", doc.attachments[2].Name)
+	require.Equal(t, []byte("ghij"), doc.attachments[2].AttachmentBytes)
+
+	// the order of the attachments read from CouchDB is not relevant
+	doc.attachments[0], doc.attachments[2] = doc.attachments[2], doc.attachments[0]
+	actualKV, err := couchDocToKeyValue(doc)
+	require.NoError(t, err)
+	require.Equal(t, kv, actualKV)
+
+	doc.attachments = doc.attachments[:2]
+	_, err = couchDocToKeyValue(doc)
+	require.EqualError(t, err, "value chunk attachment [valueBytes.0] is missing")
+
+	jsonKV := &keyValue{"key2", "", &statedb.VersionedValue{
+		Value:   []byte(`{"asset":"0123456789abcdefghij"}`),
+		Version: version.NewHeight(1, 2),
+	}}
+	doc, err = keyValToCouchDoc(jsonKV)
+	require.NoError(t, err)
+	require.False(t, splitValueAttachment(doc, 8))
+}
+
 func TestSortJSON(t *testing.T) {
 	for i := 3; i <= 3; i++ {
 		t.Run(
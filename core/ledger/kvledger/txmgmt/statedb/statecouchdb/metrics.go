@@ -21,15 +21,26 @@ var (
 		LabelNames:   []string{"database", "function_name", "result"},
 		StatsdFormat: "%{#fqname}.%{database}.%{function_name}.%{result}",
 	}
+
+	conflictsRepairedOpts = metrics.CounterOpts{
+		Namespace:    "couchdb",
+		Subsystem:    "",
+		Name:         "conflicts_repaired",
+		Help:         "The number of documents whose conflicting revisions were removed.",
+		LabelNames:   []string{"database"},
+		StatsdFormat: "%{#fqname}.%{database}",
+	}
 )
 
 type stats struct {
 	apiProcessingTime metrics.Histogram
+	conflictsRepaired metrics.Counter
 }
 
 func newStats(metricsProvider metrics.Provider) *stats {
 	return &stats{
 		apiProcessingTime: metricsProvider.NewHistogram(apiProcessingTimeOpts),
+		conflictsRepaired: metricsProvider.NewCounter(conflictsRepairedOpts),
 	}
 }
 
@@ -40,3 +51,7 @@ func (s *stats) observeProcessingTime(startTime time.Time, dbName, functionName,
 		"result", result,
 	).Observe(time.Since(startTime).Seconds())
 }
+
+func (s *stats) recordConflictRepaired(dbName string) {
+	s.conflictsRepaired.With("database", dbName).Add(1)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type replicationRequest struct {
+	Source       string `json:"source"`
+	Target       string `json:"target"`
+	CreateTarget bool   `json:"create_target"`
+}
+
+type replicationResponse struct {
+	Ok     bool   `json:"ok"`
+	Error  string `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// Replicate replicates the databases of the channel, i.e., the metadata database
+// and the databases of the namespaces, to the CouchDB instance at the given URL
+// with the CouchDB `_replicate` API. The target URL carries the credentials for the
+// target instance, if any. Each replication runs to completion before the next one
+// starts, and the metadata database, which holds the savepoint, is replicated first.
+// Hence, while updates may be applied during the replication, the replicated databases
+// are never behind the replicated savepoint. It returns the names of the replicated databases.
+func (vdb *VersionedDB) Replicate(targetURL string) ([]string, error) {
+	target, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing CouchDB replication target URL")
+	}
+	source := &url.URL{Scheme: "http", Host: vdb.couchInstance.conf.Address}
+	if vdb.couchInstance.conf.Username != "" && vdb.couchInstance.conf.Password != "" {
+		source.User = url.UserPassword(vdb.couchInstance.conf.Username, vdb.couchInstance.conf.Password)
+	}
+
+	vdb.mux.RLock()
+	dbNames := []string{vdb.metadataDB.dbName}
+	for _, dbInfo := range vdb.channelMetadata.NamespaceDBsInfo {
+		dbNames = append(dbNames, dbInfo.DBName)
+	}
+	vdb.mux.RUnlock()
+	sort.Strings(dbNames[1:])
+
+	for _, dbName := range dbNames {
+		if err := vdb.couchInstance.replicate(source, target, dbName); err != nil {
+			return nil, err
+		}
+		logger.Debugf("[%s] Replicated database %s", vdb.chainName, dbName)
+	}
+	return dbNames, nil
+}
+
+// replicate replicates the database from the source CouchDB instance to the target
+// CouchDB instance, creating the target database if needed. The request is sent without
+// the request timeout of the instance since a replication may take long.
+func (couchInstance *couchInstance) replicate(source, target *url.URL, dbName string) error {
+	data, err := json.Marshal(&replicationRequest{
+		Source:       strings.TrimSuffix(source.String(), "/") + "/" + dbName,
+		Target:       strings.TrimSuffix(target.String(), "/") + "/" + dbName,
+		CreateTarget: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error marshalling replication request")
+	}
+	req, err := http.NewRequest(http.MethodPost, couchInstance.url()+"/_replicate", bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "error creating replication request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if couchInstance.conf.Username != "" && couchInstance.conf.Password != "" {
+		req.SetBasicAuth(couchInstance.conf.Username, couchInstance.conf.Password)
+	}
+
+	client := &http.Client{Transport: couchInstance.client.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error replicating database %s", dbName)
+	}
+	defer closeResponseBody(resp)
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "error reading replication response")
+	}
+	replicationResp := &replicationResponse{}
+	if err := json.Unmarshal(body, replicationResp); err != nil {
+		return errors.Wrapf(err, "error decoding replication response of database %s: %s", dbName, body)
+	}
+	if resp.StatusCode >= 400 || !replicationResp.Ok {
+		return errors.Errorf("error replicating database %s: status code %d, error %s, reason %s",
+			dbName, resp.StatusCode, replicationResp.Error, replicationResp.Reason)
+	}
+	return nil
+}
@@ -31,6 +31,9 @@ const (
 	// Due to CouchDB's length restriction on db names, channel names and namepsaces may be truncated in db names.
 	// The metadata is used for dropping channel-specific databases and snapshot support.
 	channelMetadataDocID = "channel_metadata"
+	// bulkLoadDocID is used as a key to store the indexes deferred by the bulk load in progress, if any
+	// (maintained in the channel's metadatadb).
+	bulkLoadDocID = "bulk_load"
 	// fabricInternalDBName is used to create a db in couch that would be used for internal data such as the version of the data format
 	// a double underscore ensures that the dbname does not clash with the dbnames created for the chaincodes
 	fabricInternalDBName = "fabric__internal"
@@ -47,6 +50,7 @@ type VersionedDBProvider struct {
 	openCounts         uint64
 	redoLoggerProvider *redoLoggerProvider
 	cache              *cache
+	warmupNamespaces   []string
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
@@ -72,6 +76,7 @@ func NewVersionedDBProvider(config *ledger.CouchDBConfig, metricsProvider metric
 			openCounts:         0,
 			redoLoggerProvider: p,
 			cache:              cache,
+			warmupNamespaces:   config.CacheWarmupNamespaces,
 		},
 		nil
 }
@@ -143,6 +148,7 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string, nsProvider state
 		if err != nil {
 			return nil, err
 		}
+		vdb.warmCache(provider.warmupNamespaces)
 		provider.databases[dbName] = vdb
 	}
 	return vdb, nil
@@ -171,6 +177,8 @@ type VersionedDB struct {
 	mux                sync.RWMutex
 	redoLogger         *redoLogger
 	cache              *cache
+	bulkLoad           *bulkLoad // The bulk load in progress, if any.
+	bulkLoadLock       sync.RWMutex
 }
 
 // newVersionedDB constructs an instance of VersionedDB
@@ -208,6 +216,9 @@ func newVersionedDB(couchInstance *couchInstance, redoLogger *redoLogger, dbName
 	if err = vdb.initChannelMetadata(isNewDB, nsProvider); err != nil {
 		return nil, err
 	}
+	if err = vdb.createIndexesOfInterruptedBulkLoad(); err != nil {
+		return nil, err
+	}
 
 	// in normal circumstances, redolog is expected to be either equal to the last block
 	// committed to the statedb or one ahead (in the event of a crash). However, either of
@@ -264,6 +275,11 @@ func (vdb *VersionedDB) getNamespaceDBHandle(namespace string) (*couchDatabase,
 
 // ProcessIndexesForChaincodeDeploy creates indexes for a specified namespace
 func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, indexFilesData map[string][]byte) error {
+	if b := vdb.getBulkLoad(); b != nil {
+		logger.Infof("deferring the creation of the indexes of chaincode [%s] on channel [%s] to the end of the bulk load",
+			namespace, vdb.chainName)
+		return vdb.deferIndexes(b, namespace, indexFilesData)
+	}
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return err
@@ -309,11 +325,17 @@ func (vdb *VersionedDB) GetDBType() string {
 func (vdb *VersionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) error {
 	missingKeys := map[string][]string{}
 	committedDataCache := newVersionCache()
+	bulkLoad := vdb.getBulkLoad()
 	for _, compositeKey := range keys {
 		ns, key := compositeKey.Namespace, compositeKey.Key
 		committedDataCache.setVerAndRev(ns, key, nil, "")
 		logger.Debugf("Load into version cache: %s~%s", ns, key)
 
+		if bulkLoad != nil && !bulkLoad.mayExist(ns, key) {
+			// the key does not exist in the database
+			continue
+		}
+
 		if !vdb.cache.enabled(ns) {
 			missingKeys[ns] = append(missingKeys[ns], key)
 			continue
@@ -443,13 +465,16 @@ func (vdb *VersionedDB) readFromDB(namespace, key string) (*keyValue, error) {
 	if err := validateKey(key); err != nil {
 		return nil, err
 	}
-	couchDoc, _, err := db.readDoc(key)
+	couchDoc, _, err := db.readDocWithOptions(key, conflictsOption)
 	if err != nil {
 		return nil, err
 	}
 	if couchDoc == nil {
 		return nil, nil
 	}
+	if couchDoc, err = db.repairConflicts(couchDoc); err != nil {
+		return nil, err
+	}
 	kv, err := couchDocToKeyValue(couchDoc)
 	if err != nil {
 		return nil, err
@@ -682,7 +707,7 @@ func (vdb *VersionedDB) postCommitProcessing(committers []*committer, namespaces
 		if err != nil {
 			return err
 		}
-		if db.couchInstance.conf.WarmIndexesAfterNBlocks > 0 {
+		if db.couchInstance.conf.WarmIndexesAfterNBlocks > 0 && vdb.getBulkLoad() == nil {
 			if db.indexWarmCounter >= db.couchInstance.conf.WarmIndexesAfterNBlocks {
 				go db.runWarmIndexAllIndexes()
 				db.indexWarmCounter = 0
@@ -868,6 +893,7 @@ func (vdb *VersionedDB) GetFullScanIterator(skipNamespace func(string) bool) (st
 	toSkipKeysFromEmptyNs := map[string]bool{
 		savepointDocID:       true,
 		channelMetadataDocID: true,
+		bulkLoadDocID:        true,
 	}
 	return newDBsScanner(dbsToScan, vdb.couchInstance.internalQueryLimit(), toSkipKeysFromEmptyNs)
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package statecouchdb
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -1323,6 +1324,57 @@ func TestMissingRevisionRetrievalFromDB(t *testing.T) {
 	require.Equal(t, revisions["key3"], newRevisions["key3"])
 }
 
+func TestLargeBinaryValues(t *testing.T) {
+	vdbEnv.init(t, nil)
+	defer vdbEnv.cleanup()
+	vdbEnv.config.AttachmentThreshold = 16
+	vdbEnv.closeAndReopen()
+
+	db, err := vdbEnv.DBProvider.GetDBHandle("testlargebinaryvalues", nil)
+	require.NoError(t, err)
+
+	largeValue := bytes.Repeat([]byte("0123456789"), 10)
+	largeJSONValue := []byte(`{"asset":"` + strings.Repeat("a", 100) + `"}`)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "large", largeValue, version.NewHeight(1, 1))
+	batch.Put("ns1", "small", []byte("value"), version.NewHeight(1, 2))
+	batch.Put("ns1", "json", largeJSONValue, version.NewHeight(1, 3))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)))
+
+	vv, err := db.GetState("ns1", "large")
+	require.NoError(t, err)
+	require.Equal(t, largeValue, vv.Value)
+	vv, err = db.GetState("ns1", "small")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), vv.Value)
+	vv, err = db.GetState("ns1", "json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(largeJSONValue), string(vv.Value))
+
+	// the large value is stored as chunks
+	nsDB, err := db.(*VersionedDB).getNamespaceDBHandle("ns1")
+	require.NoError(t, err)
+	doc, _, err := nsDB.readDoc("large")
+	require.NoError(t, err)
+	require.Len(t, doc.attachments, 7)
+
+	// update and delete the large value
+	updatedValue := bytes.Repeat([]byte("9876543210"), 5)
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "large", updatedValue, version.NewHeight(2, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+	vv, err = db.GetState("ns1", "large")
+	require.NoError(t, err)
+	require.Equal(t, updatedValue, vv.Value)
+
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns1", "large", version.NewHeight(3, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(3, 1)))
+	vv, err = db.GetState("ns1", "large")
+	require.NoError(t, err)
+	require.Nil(t, vv)
+}
+
 func TestMissingRevisionRetrievalFromCache(t *testing.T) {
 	vdbEnv.init(t, []string{"lscc", "_lifecycle"})
 	defer vdbEnv.cleanup()
@@ -103,6 +103,37 @@ type IndexCapable interface {
 	ProcessIndexesForChaincodeDeploy(namespace string, indexFilesData map[string][]byte) error
 }
 
+// BulkLoadCapable interface provides additional functions for databases
+// that can be filled faster when they are rebuilt from scratch
+type BulkLoadCapable interface {
+	// StartBulkLoad switches the database to the bulk load mode, in which it assumes that
+	// it holds no state other than the one committed since the start of the load. It returns
+	// false, leaving the mode unchanged, if the database is not empty.
+	StartBulkLoad() (bool, error)
+	// EndBulkLoad leaves the bulk load mode and catches up with the work deferred during the load
+	EndBulkLoad() error
+}
+
+// ViewPinnable interface provides additional functions for databases that can pin
+// a read-only view of the state, so that the range scans can be resumed against the
+// same state in the later transactions
+type ViewPinnable interface {
+	// PinView returns a view of the state as of the latest savepoint. The view is not affected
+	// by the updates applied afterwards
+	PinView() (PinnedView, error)
+}
+
+// PinnedView is a read-only, point-in-time view of the statedb
+type PinnedView interface {
+	// SavePoint returns the savepoint of the state the view is pinned to
+	SavePoint() (*version.Height, error)
+	// GetStateRangeScanIteratorWithPagination returns an iterator over the pinned state, with the same
+	// semantics as the function VersionedDB.GetStateRangeScanIteratorWithPagination
+	GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (QueryResultsIterator, error)
+	// Release releases the view. The iterators obtained from the view should be closed by the caller
+	Release()
+}
+
 // FullScanIterator provides a mean to iterate over entire statedb. The intended use of this iterator
 // is to generate the snapshot files for the statedb
 type FullScanIterator interface {
@@ -0,0 +1,407 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+const (
+	compositeKeyNamespace = "\x00"
+	minUnicodeRuneValue   = 0 // U+0000
+	maxUnicodeRune        = string(utf8.MaxRune)
+
+	// bloomBitsPerPrefix and bloomHashes give a false positive rate of about 1%
+	bloomBitsPerPrefix = 10
+	bloomHashes        = 7
+	bloomMinPrefixes   = 1024
+)
+
+var compositeKeyFamiliesKeyPrefix = []byte{'c'}
+
+// compositeKeyFamily is a family of composite keys sharing an object type,
+// declared by a chaincode in a file of its META-INF/statedb/leveldb/indexes
+// directory, such as
+//
+//	{"compositeKeyFamilies":[{"objectType":"color~name","prefixAttributes":1}]}
+//
+// The state database maintains a bloom filter of the prefixes of the keys of
+// the family, made of the object type and up to PrefixAttributes of their
+// leading attributes, so that partial composite key queries on a prefix that
+// no key starts with are answered without scanning the database.
+type compositeKeyFamily struct {
+	ObjectType       string `json:"objectType"`
+	PrefixAttributes int    `json:"prefixAttributes"`
+}
+
+type compositeKeyFamilies struct {
+	CompositeKeyFamilies []*compositeKeyFamily `json:"compositeKeyFamilies"`
+}
+
+// parseCompositeKeyFamilies parses the index files of a namespace into the
+// composite key families they declare, sorted by object type. A family
+// declared twice keeps its largest number of prefix attributes.
+func parseCompositeKeyFamilies(indexFilesData map[string][]byte) ([]*compositeKeyFamily, error) {
+	byObjectType := map[string]*compositeKeyFamily{}
+	for fileName, data := range indexFilesData {
+		declared := &compositeKeyFamilies{}
+		if err := json.Unmarshal(data, declared); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling composite key families of index file [%s]", fileName)
+		}
+		for _, f := range declared.CompositeKeyFamilies {
+			if err := validateCompositeKeyFamily(f); err != nil {
+				return nil, errors.WithMessagef(err, "invalid composite key family in index file [%s]", fileName)
+			}
+			if existing, ok := byObjectType[f.ObjectType]; ok && existing.PrefixAttributes >= f.PrefixAttributes {
+				continue
+			}
+			byObjectType[f.ObjectType] = f
+		}
+	}
+
+	families := make([]*compositeKeyFamily, 0, len(byObjectType))
+	for _, f := range byObjectType {
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].ObjectType < families[j].ObjectType })
+	return families, nil
+}
+
+func validateCompositeKeyFamily(f *compositeKeyFamily) error {
+	if f == nil || f.ObjectType == "" {
+		return errors.New("object type must not be empty")
+	}
+	if strings.ContainsRune(f.ObjectType, minUnicodeRuneValue) || strings.Contains(f.ObjectType, maxUnicodeRune) {
+		return errors.Errorf("object type [%s] contains a reserved character", f.ObjectType)
+	}
+	if f.PrefixAttributes < 0 {
+		return errors.Errorf("prefix attributes of object type [%s] must not be negative", f.ObjectType)
+	}
+	return nil
+}
+
+// splitCompositeKey returns the object type and the attributes of a
+// composite key, or false if the key is not a composite key. The attributes
+// of the prefix of a partial composite key end with an empty attribute.
+func splitCompositeKey(key string) (string, []string, bool) {
+	if !strings.HasPrefix(key, compositeKeyNamespace) {
+		return "", nil, false
+	}
+	parts := strings.Split(key[len(compositeKeyNamespace):], string(rune(minUnicodeRuneValue)))
+	if len(parts) < 2 {
+		return "", nil, false
+	}
+	return parts[0], parts[1:], true
+}
+
+// compositeKeyPrefix builds the prefix of the composite keys of an object
+// type starting with the given attributes.
+func compositeKeyPrefix(objectType string, attributes []string) string {
+	var b strings.Builder
+	b.WriteString(compositeKeyNamespace)
+	b.WriteString(objectType)
+	b.WriteRune(minUnicodeRuneValue)
+	for _, a := range attributes {
+		b.WriteString(a)
+		b.WriteRune(minUnicodeRuneValue)
+	}
+	return b.String()
+}
+
+// compositeKeyFilters holds the composite key families declared for the
+// namespaces of a channel and the bloom filters of their prefixes. Filters are
+// built in the background by scanning the keys of a family, as the database is
+// opened or the family declared, and kept up to date as updates are applied.
+// Until its filter is built, the queries on a family scan the database. Deleted
+// keys are not removed from the filters, which only leads to a scan as if there
+// were no filter.
+type compositeKeyFilters struct {
+	db *leveldbhelper.DBHandle
+
+	mutex   sync.Mutex
+	filters map[string]map[string]*familyFilter // namespace -> object type -> filter
+}
+
+type familyFilter struct {
+	family *compositeKeyFamily
+
+	mutex    sync.Mutex
+	bloom    *bloomFilter // nil until built
+	building bool
+	pending  []string // keys written while the filter is being built
+}
+
+func newCompositeKeyFilters(db *leveldbhelper.DBHandle) (*compositeKeyFilters, error) {
+	c := &compositeKeyFilters{
+		db:      db,
+		filters: map[string]map[string]*familyFilter{},
+	}
+
+	itr, err := db.GetIterator(compositeKeyFamiliesKeyPrefix, []byte{compositeKeyFamiliesKeyPrefix[0] + 1})
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	for itr.Next() {
+		ns := string(itr.Key()[len(compositeKeyFamiliesKeyPrefix):])
+		declared := &compositeKeyFamilies{}
+		if err := json.Unmarshal(itr.Value(), declared); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling composite key families of namespace [%s]", ns)
+		}
+		c.setFamilies(ns, declared.CompositeKeyFamilies)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrap(err, "internal leveldb error while loading composite key families")
+	}
+	return c, nil
+}
+
+// declare persists the composite key families of a namespace, replacing the
+// families declared earlier for it.
+func (c *compositeKeyFilters) declare(namespace string, families []*compositeKeyFamily) error {
+	key := append(append([]byte{}, compositeKeyFamiliesKeyPrefix...), namespace...)
+	if len(families) == 0 {
+		if err := c.db.Delete(key, true); err != nil {
+			return err
+		}
+	} else {
+		value, err := json.Marshal(&compositeKeyFamilies{CompositeKeyFamilies: families})
+		if err != nil {
+			return errors.Wrap(err, "error marshaling composite key families")
+		}
+		if err := c.db.Put(key, value, true); err != nil {
+			return err
+		}
+	}
+	c.setFamilies(namespace, families)
+	return nil
+}
+
+// setFamilies replaces the filters of a namespace and starts building them.
+func (c *compositeKeyFilters) setFamilies(namespace string, families []*compositeKeyFamily) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(families) == 0 {
+		delete(c.filters, namespace)
+		return
+	}
+	filters := map[string]*familyFilter{}
+	for _, f := range families {
+		filter := &familyFilter{family: f, building: true}
+		filters[f.ObjectType] = filter
+		go filter.build(c.db, namespace)
+	}
+	c.filters[namespace] = filters
+}
+
+func (c *compositeKeyFilters) filter(namespace, objectType string) *familyFilter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.filters[namespace][objectType]
+}
+
+// mayContain returns false if no key of a namespace falls in the range of a
+// partial composite key query, which starts at the prefix of the query and
+// ends at the prefix followed by the maximum unicode rune. It returns true if
+// the range is not exactly the one of a query on a declared family, if the
+// filter of the family is not built yet, or if some key may fall in the range.
+func (c *compositeKeyFilters) mayContain(namespace, startKey, endKey string) bool {
+	if !strings.HasSuffix(endKey, maxUnicodeRune) || startKey != strings.TrimSuffix(endKey, maxUnicodeRune) {
+		return true
+	}
+	objectType, attributes, ok := splitCompositeKey(startKey)
+	if !ok || attributes[len(attributes)-1] != "" {
+		return true
+	}
+	f := c.filter(namespace, objectType)
+	if f == nil {
+		return true
+	}
+
+	attributes = attributes[:len(attributes)-1]
+	if len(attributes) > f.family.PrefixAttributes {
+		attributes = attributes[:f.family.PrefixAttributes]
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.bloom == nil {
+		return true
+	}
+	return f.bloom.mayContain(compositeKeyPrefix(objectType, attributes))
+}
+
+// update adds the prefixes of the keys written by a batch to the filters of
+// their families. It is called before the batch is written, so that a filter
+// never misses a key of the database.
+func (c *compositeKeyFilters) update(batch *statedb.UpdateBatch) {
+	c.mutex.Lock()
+	namespaces := make(map[string]map[string]*familyFilter, len(c.filters))
+	for ns, filters := range c.filters {
+		namespaces[ns] = filters
+	}
+	c.mutex.Unlock()
+
+	for ns, filters := range namespaces {
+		keys := map[*familyFilter][]string{}
+		for key, vv := range batch.GetUpdates(ns) {
+			if vv.Value == nil {
+				continue
+			}
+			objectType, _, ok := splitCompositeKey(key)
+			if !ok {
+				continue
+			}
+			if f, ok := filters[objectType]; ok {
+				keys[f] = append(keys[f], key)
+			}
+		}
+		for f, keys := range keys {
+			f.update(c.db, ns, keys)
+		}
+	}
+}
+
+func (f *familyFilter) update(db *leveldbhelper.DBHandle, namespace string, keys []string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch {
+	case f.building:
+		f.pending = append(f.pending, keys...)
+	case f.bloom != nil:
+		for _, key := range keys {
+			f.add(f.bloom, key)
+		}
+		if f.bloom.full() {
+			// rebuilt sized after the keys of the family, the keys of the
+			// batch are not written yet and may be missed by the scan
+			f.bloom = nil
+			f.building = true
+			f.pending = append([]string{}, keys...)
+			go f.build(db, namespace)
+		}
+	}
+}
+
+// build scans the keys of the family, along with the keys written meanwhile,
+// into a new bloom filter. The filter must be marked as building beforehand.
+func (f *familyFilter) build(db *leveldbhelper.DBHandle, namespace string) {
+	keys, err := f.scan(db, namespace)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.building = false
+	pending := f.pending
+	f.pending = nil
+	if err != nil {
+		logger.Errorf("Failed building the filter of composite key family [%s] of namespace [%s], its queries scan the database: %s", f.family.ObjectType, namespace, err)
+		return
+	}
+
+	keys = append(keys, pending...)
+	capacity := 2 * len(keys) * (f.family.PrefixAttributes + 1)
+	if capacity < bloomMinPrefixes {
+		capacity = bloomMinPrefixes
+	}
+	bloom := newBloomFilter(capacity)
+	for _, key := range keys {
+		f.add(bloom, key)
+	}
+	f.bloom = bloom
+	logger.Debugf("Built the filter of composite key family [%s] of namespace [%s] over %d keys", f.family.ObjectType, namespace, len(keys))
+}
+
+func (f *familyFilter) scan(db *leveldbhelper.DBHandle, namespace string) ([]string, error) {
+	prefix := compositeKeyPrefix(f.family.ObjectType, nil)
+	startKey := encodeDataKey(namespace, prefix)
+	endKey := encodeDataKey(namespace, prefix+maxUnicodeRune)
+
+	var keys []string
+	itr, err := db.GetIterator(startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	for itr.Next() {
+		_, key := decodeDataKey(itr.Key())
+		keys = append(keys, key)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrap(err, "internal leveldb error")
+	}
+	return keys, nil
+}
+
+func (f *familyFilter) add(bloom *bloomFilter, key string) {
+	objectType, attributes, _ := splitCompositeKey(key)
+	// the last attribute of a full composite key is empty
+	attributes = attributes[:len(attributes)-1]
+	if len(attributes) > f.family.PrefixAttributes {
+		attributes = attributes[:f.family.PrefixAttributes]
+	}
+	for i := 0; i <= len(attributes); i++ {
+		bloom.add(compositeKeyPrefix(objectType, attributes[:i]))
+	}
+}
+
+// bloomFilter is a bloom filter of strings sized for a number of entries.
+type bloomFilter struct {
+	bits     []uint64
+	capacity int
+	entries  int
+}
+
+func newBloomFilter(capacity int) *bloomFilter {
+	return &bloomFilter{
+		bits:     make([]uint64, (capacity*bloomBitsPerPrefix+63)/64),
+		capacity: capacity,
+	}
+}
+
+func (b *bloomFilter) locations(s string) (uint64, uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	sum := h.Sum64()
+	return sum & 0xffffffff, sum>>32 | 1, uint64(len(b.bits) * 64)
+}
+
+func (b *bloomFilter) add(s string) {
+	h1, h2, n := b.locations(s)
+	for i := uint64(0); i < bloomHashes; i++ {
+		bit := (h1 + i*h2) % n
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+	b.entries++
+}
+
+func (b *bloomFilter) mayContain(s string) bool {
+	h1, h2, n := b.locations(s)
+	for i := uint64(0); i < bloomHashes; i++ {
+		bit := (h1 + i*h2) % n
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// full returns true once more entries were added than the filter was sized
+// for, counting the entries of a key added more than once.
+func (b *bloomFilter) full() bool {
+	return b.entries > b.capacity
+}
@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompositeKeyFamilies(t *testing.T) {
+	families, err := parseCompositeKeyFamilies(map[string][]byte{
+		"META-INF/statedb/leveldb/indexes/color.json": []byte(`{"compositeKeyFamilies":[{"objectType":"color~name","prefixAttributes":1}]}`),
+		"META-INF/statedb/leveldb/indexes/owner.json": []byte(`{"compositeKeyFamilies":[{"objectType":"owner~name"},{"objectType":"color~name","prefixAttributes":2}]}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*compositeKeyFamily{
+		{ObjectType: "color~name", PrefixAttributes: 2},
+		{ObjectType: "owner~name", PrefixAttributes: 0},
+	}, families)
+
+	_, err = parseCompositeKeyFamilies(map[string][]byte{"bad.json": []byte(`not json`)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error unmarshaling composite key families of index file [bad.json]")
+
+	_, err = parseCompositeKeyFamilies(map[string][]byte{"empty.json": []byte(`{"compositeKeyFamilies":[{"prefixAttributes":1}]}`)})
+	require.EqualError(t, err, "invalid composite key family in index file [empty.json]: object type must not be empty")
+
+	_, err = parseCompositeKeyFamilies(map[string][]byte{"negative.json": []byte(`{"compositeKeyFamilies":[{"objectType":"color","prefixAttributes":-1}]}`)})
+	require.EqualError(t, err, "invalid composite key family in index file [negative.json]: prefix attributes of object type [color] must not be negative")
+
+	_, err = parseCompositeKeyFamilies(map[string][]byte{"reserved.json": []byte(`{"compositeKeyFamilies":[{"objectType":"col\u0000or"}]}`)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "contains a reserved character")
+}
+
+func TestCompositeKeyFilter(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testcompositekeyfilter", nil)
+	require.NoError(t, err)
+
+	indexCapable, ok := db.(statedb.IndexCapable)
+	require.True(t, ok)
+	require.Equal(t, "leveldb", indexCapable.GetDBType())
+	err = indexCapable.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"color.json": []byte(`{"compositeKeyFamilies":[{"objectType":"color~name","prefixAttributes":1}]}`),
+	})
+	require.NoError(t, err)
+	waitForFilters(t, db.(*versionedDB), "ns1", "color~name")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", compositeKeyPrefix("color~name", []string{"blue", "marble1"}), []byte("v1"), version.NewHeight(1, 1))
+	batch.Put("ns1", compositeKeyPrefix("color~name", []string{"red", "marble2"}), []byte("v2"), version.NewHeight(1, 2))
+	batch.Put("ns2", compositeKeyPrefix("color~name", []string{"green", "marble3"}), []byte("v3"), version.NewHeight(1, 3))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)))
+
+	queryKeys := func(ns string, attributes ...string) []string {
+		prefix := compositeKeyPrefix("color~name", attributes)
+		itr, err := db.GetStateRangeScanIterator(ns, prefix, prefix+maxUnicodeRune)
+		require.NoError(t, err)
+		defer itr.Close()
+		var keys []string
+		for {
+			res, err := itr.Next()
+			require.NoError(t, err)
+			if res == nil {
+				return keys
+			}
+			keys = append(keys, res.(*statedb.VersionedKV).Key)
+		}
+	}
+	filtered := func(ns string, attributes ...string) bool {
+		prefix := compositeKeyPrefix("color~name", attributes)
+		_, ok := mustRangeScan(t, db, ns, prefix, prefix+maxUnicodeRune).(*emptyScanner)
+		return ok
+	}
+
+	require.Equal(t, []string{compositeKeyPrefix("color~name", []string{"blue", "marble1"})}, queryKeys("ns1", "blue"))
+	require.Len(t, queryKeys("ns1"), 2)
+	require.True(t, filtered("ns1", "green"))
+	require.Empty(t, queryKeys("ns1", "green"))
+	// attributes beyond the declared prefix attributes are checked by the scan
+	require.False(t, filtered("ns1", "blue", "marble9"))
+	require.Empty(t, queryKeys("ns1", "blue", "marble9"))
+	// the family is not declared in ns2
+	require.False(t, filtered("ns2", "yellow"))
+	require.Len(t, queryKeys("ns2", "green"), 1)
+	// only the range of a partial composite key query is filtered
+	prefix := compositeKeyPrefix("color~name", []string{"green"})
+	_, ok = mustRangeScan(t, db, "ns1", prefix+"a", prefix+maxUnicodeRune).(*emptyScanner)
+	require.False(t, ok)
+	_, ok = mustRangeScan(t, db, "ns1", prefix, prefix+"z").(*emptyScanner)
+	require.False(t, ok)
+
+	// keys written after the filter is built are added to it
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", compositeKeyPrefix("color~name", []string{"green", "marble4"}), []byte("v4"), version.NewHeight(2, 1))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+	require.False(t, filtered("ns1", "green"))
+	require.Len(t, queryKeys("ns1", "green"), 1)
+
+	// the declared families survive reopening the database
+	env.DBProvider.Close()
+	env.DBProvider, err = NewVersionedDBProvider(env.dbPath)
+	require.NoError(t, err)
+	db, err = env.DBProvider.GetDBHandle("testcompositekeyfilter", nil)
+	require.NoError(t, err)
+	waitForFilters(t, db.(*versionedDB), "ns1", "color~name")
+	require.True(t, filtered("ns1", "purple"))
+	require.Len(t, queryKeys("ns1", "green"), 1)
+
+	// redeclaring no family removes the filters of the namespace
+	err = db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"color.json": []byte(`{"compositeKeyFamilies":[]}`),
+	})
+	require.NoError(t, err)
+	require.False(t, filtered("ns1", "purple"))
+}
+
+func TestCompositeKeyFilterRebuild(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testcompositekeyfilterrebuild", nil)
+	require.NoError(t, err)
+	vdb := db.(*versionedDB)
+	require.NoError(t, vdb.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"owner.json": []byte(`{"compositeKeyFamilies":[{"objectType":"owner"}]}`),
+	}))
+
+	waitForFilters(t, vdb, "ns1", "owner")
+	prefix := compositeKeyPrefix("owner", nil)
+	require.False(t, vdb.compositeKeyFilters.mayContain("ns1", prefix, prefix+maxUnicodeRune))
+
+	batch := statedb.NewUpdateBatch()
+	for i := 0; i <= bloomMinPrefixes; i++ {
+		batch.Put("ns1", compositeKeyPrefix("owner", []string{fmt.Sprintf("owner%d", i)}), []byte("v"), version.NewHeight(1, uint64(i)))
+	}
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, bloomMinPrefixes)))
+
+	// the full filter is rebuilt in the background, queries scan meanwhile
+	waitForFilters(t, vdb, "ns1", "owner")
+	require.True(t, vdb.compositeKeyFilters.mayContain("ns1", prefix, prefix+maxUnicodeRune))
+	f := vdb.compositeKeyFilters.filter("ns1", "owner")
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	require.True(t, f.bloom.capacity >= 2*(bloomMinPrefixes+1))
+	require.False(t, f.bloom.full())
+}
+
+func mustRangeScan(t *testing.T, db statedb.VersionedDB, ns, startKey, endKey string) statedb.ResultsIterator {
+	itr, err := db.GetStateRangeScanIterator(ns, startKey, endKey)
+	require.NoError(t, err)
+	itr.Close()
+	return itr
+}
+
+func waitForFilters(t *testing.T, vdb *versionedDB, ns string, objectTypes ...string) {
+	for _, objectType := range objectTypes {
+		f := vdb.compositeKeyFilters.filter(ns, objectType)
+		require.NotNil(t, f)
+		require.Eventually(t, func() bool {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+			return f.bloom != nil
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	b := newBloomFilter(1000)
+	for i := 0; i < 1000; i++ {
+		b.add(fmt.Sprintf("present%d", i))
+	}
+	for i := 0; i < 1000; i++ {
+		require.True(t, b.mayContain(fmt.Sprintf("present%d", i)))
+	}
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if b.mayContain(fmt.Sprintf("absent%d", i)) {
+			falsePositives++
+		}
+	}
+	require.True(t, falsePositives < 300, "false positives: %d", falsePositives)
+	require.False(t, b.full())
+	b.add("one more")
+	require.True(t, b.full())
+}
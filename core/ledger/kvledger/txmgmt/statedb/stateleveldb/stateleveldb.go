@@ -50,7 +50,16 @@ func NewVersionedDBProvider(dbPath string) (*VersionedDBProvider, error) {
 
 // GetDBHandle gets the handle to a named database
 func (provider *VersionedDBProvider) GetDBHandle(dbName string, namespaceProvider statedb.NamespaceProvider) (statedb.VersionedDB, error) {
-	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	vdb, err := newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName)
+	if err != nil {
+		return nil, err
+	}
+	return vdb, nil
+}
+
+// Compact compacts the underlying db, which holds the state of all the channels
+func (provider *VersionedDBProvider) Compact() error {
+	return provider.dbProvider.Compact()
 }
 
 // Close closes the underlying db
@@ -60,13 +69,18 @@ func (provider *VersionedDBProvider) Close() {
 
 // VersionedDB implements VersionedDB interface
 type versionedDB struct {
-	db     *leveldbhelper.DBHandle
-	dbName string
+	db                  *leveldbhelper.DBHandle
+	dbName              string
+	compositeKeyFilters *compositeKeyFilters
 }
 
 // newVersionedDB constructs an instance of VersionedDB
-func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) *versionedDB {
-	return &versionedDB{db, dbName}
+func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) (*versionedDB, error) {
+	compositeKeyFilters, err := newCompositeKeyFilters(db)
+	if err != nil {
+		return nil, err
+	}
+	return &versionedDB{db, dbName, compositeKeyFilters}, nil
 }
 
 // Open implements method in VersionedDB interface
@@ -80,6 +94,12 @@ func (vdb *versionedDB) Close() {
 	// do nothing because shared db is used
 }
 
+// BackupSnapshot returns a point-in-time view of the state of the channel for a backup.
+// The caller is expected to ensure that no updates are applied while the snapshot is taken.
+func (vdb *versionedDB) BackupSnapshot() (*leveldbhelper.Snapshot, error) {
+	return vdb.db.GetSnapshot()
+}
+
 // ValidateKeyValue implements method in VersionedDB interface
 func (vdb *versionedDB) ValidateKeyValue(key string, value []byte) error {
 	return nil
@@ -138,6 +158,10 @@ func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey str
 
 // GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
 func (vdb *versionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	if !vdb.compositeKeyFilters.mayContain(namespace, startKey, endKey) {
+		logger.Debugf("Skipping the scan of range [%q, %q) of namespace [%s], no key of the composite key family falls in it", startKey, endKey, namespace)
+		return &emptyScanner{}, nil
+	}
 	dataStartKey := encodeDataKey(namespace, startKey)
 	dataEndKey := encodeDataKey(namespace, endKey)
 	if endKey == "" {
@@ -189,12 +213,29 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 		dbBatch.Put(savePointKey, height.ToBytes())
 	}
 	// Setting snyc to true as a precaution, false may be an ok optimization after further testing.
+	vdb.compositeKeyFilters.update(batch)
 	if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
 		return err
 	}
 	return nil
 }
 
+// GetDBType returns the hosted stateDB
+func (vdb *versionedDB) GetDBType() string {
+	return "leveldb"
+}
+
+// ProcessIndexesForChaincodeDeploy declares the composite key families of a
+// namespace, for which the prefixes of the keys are kept in bloom filters
+func (vdb *versionedDB) ProcessIndexesForChaincodeDeploy(namespace string, indexFilesData map[string][]byte) error {
+	families, err := parseCompositeKeyFamilies(indexFilesData)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Channel [%s]: Declaring %d composite key families for namespace [%s]", vdb.dbName, len(families), namespace)
+	return vdb.compositeKeyFilters.declare(namespace, families)
+}
+
 // GetLatestSavePoint implements method in VersionedDB interface
 func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
 	versionBytes, err := vdb.db.Get(savePointKey)
@@ -220,6 +261,52 @@ func (vdb *versionedDB) GetFullScanIterator(skipNamespace func(string) bool) (st
 	return newFullDBScanner(vdb.db, skipNamespace)
 }
 
+// PinView implements method in ViewPinnable interface
+func (vdb *versionedDB) PinView() (statedb.PinnedView, error) {
+	snapshot, err := vdb.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &pinnedView{snapshot: snapshot}, nil
+}
+
+// pinnedView implements interface statedb.PinnedView over a leveldb snapshot
+type pinnedView struct {
+	snapshot *leveldbhelper.Snapshot
+}
+
+// SavePoint implements method in PinnedView interface
+func (v *pinnedView) SavePoint() (*version.Height, error) {
+	versionBytes, err := v.snapshot.Get(savePointKey)
+	if err != nil || versionBytes == nil {
+		return nil, err
+	}
+	height, _, err := version.NewHeightFromBytes(versionBytes)
+	if err != nil {
+		return nil, err
+	}
+	return height, nil
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in PinnedView interface
+func (v *pinnedView) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	dataStartKey := encodeDataKey(namespace, startKey)
+	dataEndKey := encodeDataKey(namespace, endKey)
+	if endKey == "" {
+		dataEndKey[len(dataEndKey)-1] = lastKeyIndicator
+	}
+	dbItr, err := v.snapshot.GetIterator(dataStartKey, dataEndKey)
+	if err != nil {
+		return nil, err
+	}
+	return newKVScanner(namespace, dbItr, pageSize), nil
+}
+
+// Release implements method in PinnedView interface
+func (v *pinnedView) Release() {
+	v.snapshot.Release()
+}
+
 func encodeDataKey(ns, key string) []byte {
 	k := append(dataKeyPrefix, []byte(ns)...)
 	k = append(k, nsKeySep...)
@@ -288,6 +375,19 @@ func (scanner *kvScanner) GetBookmarkAndClose() string {
 	return retval
 }
 
+// emptyScanner is returned for the ranges known to hold no key
+type emptyScanner struct{}
+
+func (scanner *emptyScanner) Next() (statedb.QueryResult, error) {
+	return nil, nil
+}
+
+func (scanner *emptyScanner) Close() {}
+
+func (scanner *emptyScanner) GetBookmarkAndClose() string {
+	return ""
+}
+
 type fullDBScanner struct {
 	db     *leveldbhelper.DBHandle
 	dbItr  iterator.Iterator
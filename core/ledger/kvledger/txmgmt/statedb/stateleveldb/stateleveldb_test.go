@@ -193,3 +193,46 @@ func TestFullScanIteratorErrorPropagation(t *testing.T) {
 	_, _, err = itr.Next()
 	require.Contains(t, err.Error(), "internal leveldb error while retrieving data from db iterator:")
 }
+
+func TestPinView(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testpinview", nil)
+	require.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 3))
+	batch.Put("ns2", "key4", []byte("value4"), version.NewHeight(1, 4))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)))
+
+	view, err := db.(statedb.ViewPinnable).PinView()
+	require.NoError(t, err)
+	defer view.Release()
+
+	// updates applied after pinning the view should not be visible in the view
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns1", "key2", version.NewHeight(2, 1))
+	batch.Put("ns1", "key5", []byte("value5"), version.NewHeight(2, 2))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 2)))
+
+	savePoint, err := view.SavePoint()
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(1, 4), savePoint)
+
+	itr, err := view.GetStateRangeScanIteratorWithPagination("ns1", "", "", 2)
+	require.NoError(t, err)
+	commontests.TestItrWithoutClose(t, itr, []string{"key1", "key2"})
+	require.Equal(t, "key3", itr.GetBookmarkAndClose())
+
+	itr, err = view.GetStateRangeScanIteratorWithPagination("ns1", "key3", "", 2)
+	require.NoError(t, err)
+	commontests.TestItrWithoutClose(t, itr, []string{"key3"})
+	require.Equal(t, "", itr.GetBookmarkAndClose())
+
+	itr, err = db.GetStateRangeScanIteratorWithPagination("ns1", "", "", 0)
+	require.NoError(t, err)
+	commontests.TestItrWithoutClose(t, itr, []string{"key1", "key3", "key5"})
+	itr.Close()
+}
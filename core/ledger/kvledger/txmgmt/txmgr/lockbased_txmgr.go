@@ -9,6 +9,7 @@ package txmgr
 import (
 	"bytes"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -44,6 +45,19 @@ type LockBasedTxMgr struct {
 	oldBlockCommit      sync.Mutex
 	current             *current
 	hashFunc            rwsetutil.HashFunc
+	stageTimes          CommitStageTimes
+	pinnedViews         *pinnedViews
+}
+
+// CommitStageTimes breaks down the time taken by the transaction manager to
+// process the last block
+type CommitStageTimes struct {
+	// Validation is the time taken to validate the transactions of the block
+	Validation time.Duration
+	// StatedbPrep is the time taken to prepare the state updates of the block
+	StatedbPrep time.Duration
+	// StatedbCommit is the time taken to apply the updates to the state database
+	StatedbCommit time.Duration
 }
 
 // pvtdataPurgeMgr wraps the actual purge manager and an additional flag 'usedOnce'
@@ -96,7 +110,9 @@ type Initializer struct {
 	BookkeepingProvider bookkeeping.Provider
 	CCInfoProvider      ledger.DeployedChaincodeInfoProvider
 	CustomTxProcessors  map[common.HeaderType]ledger.CustomTxProcessor
+	CounterCapability   ledger.CounterCapabilityProvider
 	HashFunc            rwsetutil.HashFunc
+	RangeScanCursors    *ledger.RangeScanCursorsConfig
 }
 
 // NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
@@ -115,6 +131,7 @@ func NewLockBasedTxMgr(initializer *Initializer) (*LockBasedTxMgr, error) {
 		stateListeners: initializer.StateListeners,
 		ccInfoProvider: initializer.CCInfoProvider,
 		hashFunc:       initializer.HashFunc,
+		pinnedViews:    newPinnedViews(initializer.DB, initializer.RangeScanCursors),
 	}
 	pvtstatePurgeMgr, err := pvtstatepurgemgmt.InstantiatePurgeMgr(
 		initializer.LedgerID,
@@ -129,6 +146,7 @@ func NewLockBasedTxMgr(initializer *Initializer) (*LockBasedTxMgr, error) {
 		txmgr,
 		initializer.DB,
 		initializer.CustomTxProcessors,
+		initializer.CounterCapability,
 		initializer.HashFunc)
 	return txmgr, nil
 }
@@ -195,11 +213,16 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 
 	block := blockAndPvtdata.Block
 	logger.Debugf("Validating new block with num trans = [%d]", len(block.Data.Data))
+	txmgr.stageTimes = CommitStageTimes{}
+	startValidation := time.Now()
 	batch, txstatsInfo, err := txmgr.commitBatchPreparer.ValidateAndPrepareBatch(blockAndPvtdata, doMVCCValidation)
 	if err != nil {
 		txmgr.reset()
 		return nil, nil, err
 	}
+	txmgr.stageTimes.Validation = time.Since(startValidation)
+
+	startStatedbPrep := time.Now()
 	txmgr.current = &current{block: block, batch: batch}
 	if err := txmgr.invokeNamespaceListeners(); err != nil {
 		txmgr.reset()
@@ -207,6 +230,7 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 	}
 
 	updateBytes, err := deterministicBytesForPubAndHashUpdates(batch)
+	txmgr.stageTimes.StatedbPrep = time.Since(startStatedbPrep)
 	return txstatsInfo, updateBytes, err
 }
 
@@ -504,6 +528,7 @@ func (txmgr *LockBasedTxMgr) Shutdown() {
 	// wait for background go routine to finish else the timing issue causes a nil pointer inside goleveldb code
 	// see FAB-11974
 	txmgr.pvtdataPurgeMgr.WaitForPrepareToFinish()
+	txmgr.pinnedViews.close()
 	txmgr.db.Close()
 }
 
@@ -538,6 +563,7 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 		panic("validateAndPrepare() method should have been called before calling commit()")
 	}
 
+	startStatedbPrep := time.Now()
 	if err := txmgr.pvtdataPurgeMgr.UpdateExpiryInfo(
 		txmgr.current.batch.PvtUpdates, txmgr.current.batch.HashUpdates); err != nil {
 		return err
@@ -547,7 +573,9 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 		txmgr.current.batch.PvtUpdates, txmgr.current.batch.HashUpdates); err != nil {
 		return err
 	}
+	txmgr.stageTimes.StatedbPrep += time.Since(startStatedbPrep)
 
+	startStatedbCommit := time.Now()
 	commitHeight := version.NewHeight(txmgr.current.blockNum(), txmgr.current.maxTxNumber())
 	txmgr.commitRWLock.Lock()
 	logger.Debugf("Write lock acquired for committing updates to state database")
@@ -556,6 +584,7 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 		return err
 	}
 	txmgr.commitRWLock.Unlock()
+	txmgr.stageTimes.StatedbCommit = time.Since(startStatedbCommit)
 	// only while holding a lock on oldBlockCommit, we should clear the cache as the
 	// cache is being used by the old pvtData committer to load the version of
 	// hashedKeys. Also, note that the PrepareForExpiringKeys uses the cache.
@@ -572,6 +601,13 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	return nil
 }
 
+// LastCommitStageTimes returns the time taken by each stage of the processing
+// of the last block. It is not safe to call concurrently with the processing of
+// a block.
+func (txmgr *LockBasedTxMgr) LastCommitStageTimes() CommitStageTimes {
+	return txmgr.stageTimes
+}
+
 // Rollback implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Rollback() {
 	txmgr.reset()
@@ -596,6 +632,17 @@ func (txmgr *LockBasedTxMgr) ShouldRecover(lastAvailableBlock uint64) (bool, uin
 	return savepoint.BlockNum != lastAvailableBlock, savepoint.BlockNum + 1, nil
 }
 
+// StartBulkLoad switches the state database to a bulk load mode, if it supports one, for
+// the recommit of the blocks to an empty state database. It returns whether it did so.
+func (txmgr *LockBasedTxMgr) StartBulkLoad() (bool, error) {
+	return txmgr.db.StartBulkLoad()
+}
+
+// EndBulkLoad ends the bulk load mode of the state database
+func (txmgr *LockBasedTxMgr) EndBulkLoad() error {
+	return txmgr.db.EndBulkLoad()
+}
+
 // Name returns the name of the database that manages all active states.
 func (txmgr *LockBasedTxMgr) Name() string {
 	return "state"
@@ -166,6 +166,65 @@ func (q *queryExecutor) GetStateRangeScanIteratorWithPagination(namespace string
 	return itr, nil
 }
 
+// GetStateRangeScanIteratorWithCursor implements method in interface `ledger.RangeScanCursorExecutor`
+func (q *queryExecutor) GetStateRangeScanIteratorWithCursor(namespace, startKey, endKey string, pageSize int32, cursor string) (ledger.QueryResultsIterator, error) {
+	if err := q.checkDone(); err != nil {
+		return nil, err
+	}
+	savepoint, err := q.txmgr.db.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	if savepoint == nil {
+		return nil, errors.New("cannot open a range scan cursor on an empty state")
+	}
+	var view statedb.PinnedView
+	blockNum := savepoint.BlockNum
+	if cursor == ledger.RangeScanCursorPrefix {
+		view = q.txmgr.pinnedViews.pin(namespace, blockNum)
+	} else {
+		c, err := decodeRangeScanCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Namespace != namespace {
+			return nil, errors.Errorf("the range scan cursor belongs to namespace [%s], not to namespace [%s]", c.Namespace, namespace)
+		}
+		if c.BlockNum > blockNum {
+			return nil, errors.Errorf("the range scan cursor was opened at block [%d], beyond the state at block [%d]", c.BlockNum, blockNum)
+		}
+		startKey = string(c.NextKey)
+		blockNum = c.BlockNum
+		view = q.txmgr.pinnedViews.get(namespace, blockNum)
+	}
+
+	var dbItr statedb.ResultsIterator
+	if view != nil {
+		dbItr, err = view.GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0)
+	} else {
+		dbItr, err = q.txmgr.db.GetStateRangeScanIterator(namespace, startKey, endKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	itr, err := newResultsItrWithDBItr(
+		namespace,
+		startKey,
+		endKey,
+		dbItr,
+		q.rwsetBuilder,
+		queryReadsHashingEnabled,
+		maxDegreeQueryReadsHashing,
+		q.hasher,
+	)
+	if err != nil {
+		dbItr.Close()
+		return nil, err
+	}
+	q.itrs = append(q.itrs, itr)
+	return &cursorResultsItr{resultsItr: itr, blockNum: blockNum, pageSize: pageSize}, nil
+}
+
 // ExecuteQuery implements method in interface `ledger.QueryExecutor`
 func (q *queryExecutor) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
 	if err := q.checkDone(); err != nil {
@@ -409,6 +468,12 @@ func newResultsItr(ns string, startKey string, endKey string, pageSize int32,
 	if err != nil {
 		return nil, err
 	}
+	return newResultsItrWithDBItr(ns, startKey, endKey, dbItr, rwsetBuilder, enableHashing, maxDegree, hashFunc)
+}
+
+// newResultsItrWithDBItr wraps a db iterator over the range between startKey and endKey
+func newResultsItrWithDBItr(ns string, startKey string, endKey string, dbItr statedb.ResultsIterator,
+	rwsetBuilder *rwsetutil.RWSetBuilder, enableHashing bool, maxDegree uint32, hashFunc rwsetutil.HashFunc) (*resultsItr, error) {
 	itr := &resultsItr{ns: ns, dbItr: dbItr}
 	// it's a simulation request so, enable capture of range query info
 	if rwsetBuilder != nil {
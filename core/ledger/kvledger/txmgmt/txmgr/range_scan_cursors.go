@@ -0,0 +1,202 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txmgr
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRangeScanCursorLifetime    = time.Minute
+	defaultMaxPinnedViewsPerChaincode = 2
+)
+
+// rangeScanCursor is the content of the bookmarks that resume a range scan. The cursor carries all it takes to resume
+// the scan, so that any peer can resume it, against the view of the state pinned at its block if the peer holds one
+// and against the current state otherwise
+type rangeScanCursor struct {
+	BlockNum  uint64 `json:"block_num"`
+	Namespace string `json:"namespace"`
+	NextKey   []byte `json:"next_key"`
+}
+
+func (c *rangeScanCursor) encode() string {
+	// the marshaling of the struct cannot fail
+	cursorBytes, _ := json.Marshal(c)
+	return ledger.RangeScanCursorPrefix + base64.RawURLEncoding.EncodeToString(cursorBytes)
+}
+
+func decodeRangeScanCursor(bookmark string) (*rangeScanCursor, error) {
+	if !strings.HasPrefix(bookmark, ledger.RangeScanCursorPrefix) {
+		return nil, errors.New("the bookmark is not a range scan cursor")
+	}
+	cursorBytes, err := base64.RawURLEncoding.DecodeString(bookmark[len(ledger.RangeScanCursorPrefix):])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed range scan cursor")
+	}
+	c := &rangeScanCursor{}
+	if err := json.Unmarshal(cursorBytes, c); err != nil {
+		return nil, errors.Wrap(err, "malformed range scan cursor")
+	}
+	return c, nil
+}
+
+// pinnedViews keeps the views of the state pinned for the range scan cursors of each chaincode, by the block number
+// of their savepoint. A view is shared by all the cursors a chaincode opens while the state is at the same block, so
+// that the pages of a scan are read from the same state. Each chaincode may pin a limited number of views, beyond
+// which its cursors read the current state. A view is released once its lifetime, counted from the opening of its
+// first cursor, expires
+type pinnedViews struct {
+	db                   *privacyenabledstate.DB
+	lifetime             time.Duration
+	maxViewsPerNamespace int
+
+	lock  sync.Mutex
+	views map[pinnedViewKey]*expiringView
+}
+
+type pinnedViewKey struct {
+	namespace string
+	blockNum  uint64
+}
+
+type expiringView struct {
+	statedb.PinnedView
+	expiry time.Time
+}
+
+func newPinnedViews(db *privacyenabledstate.DB, conf *ledger.RangeScanCursorsConfig) *pinnedViews {
+	p := &pinnedViews{
+		db:                   db,
+		lifetime:             defaultRangeScanCursorLifetime,
+		maxViewsPerNamespace: defaultMaxPinnedViewsPerChaincode,
+		views:                map[pinnedViewKey]*expiringView{},
+	}
+	if conf != nil && conf.Lifetime > 0 {
+		p.lifetime = conf.Lifetime
+	}
+	if conf != nil && conf.MaxPinnedViewsPerChaincode > 0 {
+		p.maxViewsPerNamespace = conf.MaxPinnedViewsPerChaincode
+	}
+	return p
+}
+
+// pin returns the view of the state at the given block pinned for the namespace, pinning the current state if no view
+// is pinned at the block yet. It returns nil if the namespace has used up its budget of pinned views or if the statedb
+// cannot pin its views. The caller is expected to hold the commit lock, so that the state is at the given block
+func (p *pinnedViews) pin(namespace string, blockNum uint64) statedb.PinnedView {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.releaseExpired()
+
+	key := pinnedViewKey{namespace: namespace, blockNum: blockNum}
+	if view, ok := p.views[key]; ok {
+		return view
+	}
+	pinned := 0
+	for k := range p.views {
+		if k.namespace == namespace {
+			pinned++
+		}
+	}
+	if pinned >= p.maxViewsPerNamespace {
+		logger.Debugf("Namespace [%s] has [%d] views of the state pinned, the range scan cursor reads the current state", namespace, pinned)
+		return nil
+	}
+	pinnedView, err := p.db.PinView()
+	if err != nil {
+		logger.Debugf("Could not pin a view of the state, the range scan cursor reads the current state: %s", err)
+		return nil
+	}
+	logger.Debugf("Pinning the view of the state at block [%d] for the range scan cursors of namespace [%s]", blockNum, namespace)
+	view := &expiringView{PinnedView: pinnedView, expiry: time.Now().Add(p.lifetime)}
+	p.views[key] = view
+	return view
+}
+
+// get returns the view of the state at the given block pinned for the namespace, or nil if there is none
+func (p *pinnedViews) get(namespace string, blockNum uint64) statedb.PinnedView {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.releaseExpired()
+
+	view, ok := p.views[pinnedViewKey{namespace: namespace, blockNum: blockNum}]
+	if !ok {
+		return nil
+	}
+	return view
+}
+
+func (p *pinnedViews) releaseExpired() {
+	now := time.Now()
+	for key, view := range p.views {
+		if now.After(view.expiry) {
+			logger.Debugf("Releasing the view of the state at block [%d] pinned for the range scan cursors of namespace [%s]", key.blockNum, key.namespace)
+			view.Release()
+			delete(p.views, key)
+		}
+	}
+}
+
+// close releases all the pinned views. The iterators already obtained from the views remain usable
+func (p *pinnedViews) close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for key, view := range p.views {
+		view.Release()
+		delete(p.views, key)
+	}
+}
+
+// cursorResultsItr implements interface ledger.QueryResultsIterator over a page of a range scan cursor. The
+// underlying resultsItr records the range read in the read set, so that the page is validated against the committed
+// state like any range query, whether it is read from a pinned view or from the current state
+type cursorResultsItr struct {
+	*resultsItr
+	blockNum uint64
+	pageSize int32
+	returned int32
+	lastKey  string
+}
+
+// Next implements method in interface ledger.ResultsIterator
+func (itr *cursorResultsItr) Next() (commonledger.QueryResult, error) {
+	if itr.pageSize > 0 && itr.returned >= itr.pageSize {
+		// the page is full, the range info ends with the last key of the page
+		return nil, nil
+	}
+	queryResult, err := itr.resultsItr.Next()
+	if err != nil || queryResult == nil {
+		return nil, err
+	}
+	itr.returned++
+	itr.lastKey = queryResult.(*queryresult.KV).Key
+	return queryResult, nil
+}
+
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator.
+// It returns the cursor resuming the scan after the page, or an empty string when the scan is complete
+func (itr *cursorResultsItr) GetBookmarkAndClose() string {
+	defer itr.Close()
+	if itr.pageSize <= 0 || itr.returned < itr.pageSize {
+		return ""
+	}
+	// the scan resumes right after the last key of the page, which is the key followed by the smallest byte
+	c := &rangeScanCursor{BlockNum: itr.blockNum, Namespace: itr.ns, NextKey: []byte(itr.lastKey + "\x00")}
+	return c.encode()
+}
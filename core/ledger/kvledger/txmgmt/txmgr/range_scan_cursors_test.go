@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeScanCursorEncoding(t *testing.T) {
+	c := &rangeScanCursor{BlockNum: 5, Namespace: "ns1", NextKey: []byte("key\x00with\xffbytes")}
+	bookmark := c.encode()
+	require.Contains(t, bookmark, ledger.RangeScanCursorPrefix)
+
+	decoded, err := decodeRangeScanCursor(bookmark)
+	require.NoError(t, err)
+	require.Equal(t, c, decoded)
+
+	_, err = decodeRangeScanCursor("key_001")
+	require.EqualError(t, err, "the bookmark is not a range scan cursor")
+	_, err = decodeRangeScanCursor(ledger.RangeScanCursorPrefix + "!!!")
+	require.Contains(t, err.Error(), "malformed range scan cursor")
+}
+
+func TestRangeScanCursor(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testrangescancursor", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	s, _ := txMgr.NewTxSimulator("test_tx1")
+	for i := 1; i <= 6; i++ {
+		require.NoError(t, s.SetState("cid", createTestKey(i), createTestValue(i)))
+	}
+	s.Done()
+	txRWSet, _ := s.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+	// scan returns the keys of the page read through the cursor, the cursor resuming the scan
+	// and the range query info recorded in the read set
+	scan := func(namespace, startKey, cursor string) ([]string, string, *kvrwset.RangeQueryInfo, error) {
+		s, err := txMgr.NewTxSimulator("test_tx")
+		require.NoError(t, err)
+		itr, err := s.(ledger.RangeScanCursorExecutor).GetStateRangeScanIteratorWithCursor(namespace, startKey, "", 2, cursor)
+		if err != nil {
+			s.Done()
+			return nil, "", nil, err
+		}
+		var keys []string
+		for {
+			queryResult, err := itr.Next()
+			require.NoError(t, err)
+			if queryResult == nil {
+				break
+			}
+			keys = append(keys, queryResult.(*queryresult.KV).Key)
+		}
+		bookmark := itr.GetBookmarkAndClose()
+		s.Done()
+		simRes, err := s.GetTxSimulationResults()
+		require.NoError(t, err)
+		rwset, err := rwsetutil.TxRwSetFromProtoMsg(simRes.PubSimulationResults)
+		require.NoError(t, err)
+		require.Len(t, rwset.NsRwSets, 1)
+		require.Len(t, rwset.NsRwSets[0].KvRwSet.RangeQueriesInfo, 1)
+		return keys, bookmark, rwset.NsRwSets[0].KvRwSet.RangeQueriesInfo[0], nil
+	}
+
+	keys, cursor, rqi, err := scan("cid", "key_002", ledger.RangeScanCursorPrefix)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key_002", "key_003"}, keys)
+	require.Equal(t, "key_002", rqi.StartKey)
+	require.Equal(t, "key_003", rqi.EndKey)
+	require.False(t, rqi.ItrExhausted)
+
+	// the updates committed after opening the cursor are not visible to the scan while the view is pinned
+	s, _ = txMgr.NewTxSimulator("test_tx2")
+	require.NoError(t, s.DeleteState("cid", "key_004"))
+	require.NoError(t, s.SetState("cid", "key_007", createTestValue(7)))
+	s.Done()
+	txRWSet, _ = s.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+	keys, nextCursor, rqi, err := scan("cid", "", cursor)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key_004", "key_005"}, keys)
+	require.Equal(t, "key_003\x00", rqi.StartKey)
+
+	keys, lastCursor, rqi, err := scan("cid", "", nextCursor)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key_006"}, keys)
+	require.Equal(t, "", lastCursor)
+	require.True(t, rqi.ItrExhausted)
+
+	t.Run("namespace-mismatch", func(t *testing.T) {
+		_, _, _, err := scan("another-cid", "", cursor)
+		require.EqualError(t, err, "the range scan cursor belongs to namespace [cid], not to namespace [another-cid]")
+	})
+
+	t.Run("cursor-beyond-state", func(t *testing.T) {
+		c, err := decodeRangeScanCursor(cursor)
+		require.NoError(t, err)
+		c.BlockNum = 100
+		_, _, _, err = scan("cid", "", c.encode())
+		require.EqualError(t, err, "the range scan cursor was opened at block [100], beyond the state at block [2]")
+	})
+
+	t.Run("no-pinned-view", func(t *testing.T) {
+		// without the view, as on another peer or after a restart, the scan resumes against the current state
+		txMgr.pinnedViews.close()
+		keys, _, _, err := scan("cid", "", cursor)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key_005", "key_006"}, keys)
+	})
+
+	t.Run("expired-view", func(t *testing.T) {
+		txMgr.pinnedViews.lifetime = time.Nanosecond
+		defer func() { txMgr.pinnedViews.lifetime = defaultRangeScanCursorLifetime }()
+		_, _, _, err := scan("cid", "", ledger.RangeScanCursorPrefix)
+		require.NoError(t, err)
+
+		time.Sleep(time.Millisecond)
+		require.Nil(t, txMgr.pinnedViews.get("cid", 2))
+	})
+
+	t.Run("pinned-views-per-chaincode", func(t *testing.T) {
+		txMgr.pinnedViews.close()
+		txMgr.pinnedViews.maxViewsPerNamespace = 1
+		defer func() { txMgr.pinnedViews.maxViewsPerNamespace = defaultMaxPinnedViewsPerChaincode }()
+		require.NotNil(t, txMgr.pinnedViews.pin("cid", 2))
+		require.NotNil(t, txMgr.pinnedViews.pin("cid", 2))
+		require.Nil(t, txMgr.pinnedViews.pin("cid", 3))
+		require.NotNil(t, txMgr.pinnedViews.pin("another-cid", 3))
+
+		// the cursors of a chaincode over its budget read the current state
+		txMgr.pinnedViews.close()
+		require.NotNil(t, txMgr.pinnedViews.pin("cid", 1))
+		keys, _, _, err := scan("cid", "", ledger.RangeScanCursorPrefix)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key_001", "key_002"}, keys)
+		require.Nil(t, txMgr.pinnedViews.get("cid", 2))
+	})
+}
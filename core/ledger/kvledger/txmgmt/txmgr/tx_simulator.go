@@ -37,6 +37,9 @@ func (s *txSimulator) SetState(ns string, key string, value []byte) error {
 	if err := s.checkWritePrecondition(key, value); err != nil {
 		return err
 	}
+	if s.rwsetBuilder.HasCounterDelta(ns, key) {
+		return errors.Errorf("key [%s] of namespace [%s] is incremented in the transaction and cannot be written", key, ns)
+	}
 	s.rwsetBuilder.AddToWriteSet(ns, key, value)
 	return nil
 }
@@ -61,6 +64,9 @@ func (s *txSimulator) SetStateMetadata(namespace, key string, metadata map[strin
 	if err := s.checkWritePrecondition(key, nil); err != nil {
 		return err
 	}
+	if s.rwsetBuilder.HasCounterDelta(namespace, key) {
+		return errors.Errorf("key [%s] of namespace [%s] is incremented in the transaction and its metadata cannot be updated", key, namespace)
+	}
 	s.rwsetBuilder.AddToMetadataWriteSet(namespace, key, metadata)
 	return nil
 }
@@ -70,6 +76,14 @@ func (s *txSimulator) DeleteStateMetadata(namespace, key string) error {
 	return s.SetStateMetadata(namespace, key, nil)
 }
 
+// IncrementState implements method in interface `ledger.TxSimulator`
+func (s *txSimulator) IncrementState(namespace, key string, delta int64) error {
+	if err := s.checkWritePrecondition(key, nil); err != nil {
+		return err
+	}
+	return s.rwsetBuilder.AddToCounterDeltaSet(namespace, key, delta)
+}
+
 // SetPrivateData implements method in interface `ledger.TxSimulator`
 func (s *txSimulator) SetPrivateData(ns, coll, key string, value []byte) error {
 	if err := s.queryExecutor.validateCollName(ns, coll); err != nil {
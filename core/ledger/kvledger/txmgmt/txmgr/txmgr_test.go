@@ -23,6 +23,8 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
+	"github.com/hyperledger/fabric/core/ledger/mock"
 	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/stretchr/testify/require"
@@ -1612,3 +1614,63 @@ func TestName(t *testing.T) {
 	txMgr := testEnv.getTxMgr()
 	require.Equal(t, "state", txMgr.Name())
 }
+
+func TestTxWithCounterDeltas(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testLedgerID := "testtxwithcounterdeltas"
+		testEnv.init(t, testLedgerID, nil)
+		testTxWithCounterDeltas(t, testEnv)
+		testEnv.cleanup()
+	}
+}
+
+func testTxWithCounterDeltas(t *testing.T, env testEnv) {
+	namespace := "testns"
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	counterCapability := &mock.CounterCapabilityProvider{}
+	txMgr.commitBatchPreparer = validation.NewCommitBatchPreparer(txMgr, env.getVDB(), nil, counterCapability, testHashFunc)
+
+	// Simulate and commit tx1 - set val and metadata for key1
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	metadata1 := map[string][]byte{"entry1": []byte("meatadata1-entry1")}
+	require.NoError(t, s1.SetState(namespace, "key1", []byte("10")))
+	require.NoError(t, s1.SetStateMetadata(namespace, "key1", metadata1))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	// Simulate tx2 - increment key1 twice and key2, which does not exist
+	s2, _ := txMgr.NewTxSimulator("test_tx2")
+	require.NoError(t, s2.IncrementState(namespace, "key1", 5))
+	require.NoError(t, s2.IncrementState(namespace, "key1", 2))
+	require.NoError(t, s2.IncrementState(namespace, "key2", -3))
+	require.EqualError(t, s2.SetState(namespace, "key1", []byte("1")),
+		"key [key1] of namespace [testns] is incremented in the transaction and cannot be written")
+	require.EqualError(t, s2.SetStateMetadata(namespace, "key2", metadata1),
+		"key [key2] of namespace [testns] is incremented in the transaction and its metadata cannot be updated")
+	require.NoError(t, s2.SetState(namespace, "key3", []byte("value3")))
+	require.EqualError(t, s2.IncrementState(namespace, "key3", 1),
+		"key [key3] of namespace [testns] is both written and incremented")
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+
+	// Commit tx2 while the channel does not enable the counters - the increments are ignored
+	txMgrHelper.validateAndCommitRWSet(txRWSet2.PubSimulationResults)
+	qe, _ := txMgr.NewQueryExecutor("test_tx3")
+	checkTestQueryResults(t, qe, namespace, "key1", []byte("10"), metadata1)
+	checkTestQueryResults(t, qe, namespace, "key2", nil, nil)
+	checkTestQueryResults(t, qe, namespace, "key3", []byte("value3"), nil)
+	qe.Done()
+
+	// Commit tx2 again once the channel enables the counters
+	counterCapability.CommutativeCountersEnabledReturns(true, nil)
+	txMgrHelper.validateAndCommitRWSet(txRWSet2.PubSimulationResults)
+
+	// Run query - key1 should return the incremented value and its metadata, key2 the delta
+	qe, _ = txMgr.NewQueryExecutor("test_tx4")
+	checkTestQueryResults(t, qe, namespace, "key1", []byte("17"), metadata1)
+	checkTestQueryResults(t, qe, namespace, "key2", []byte("-3"), nil)
+	qe.Done()
+}
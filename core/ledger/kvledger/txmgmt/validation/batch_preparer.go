@@ -37,6 +37,7 @@ type CommitBatchPreparer struct {
 	db                         *privacyenabledstate.DB
 	validator                  *validator
 	customTxProcessors         map[common.HeaderType]ledger.CustomTxProcessor
+	counterCapabilityProvider  ledger.CounterCapabilityProvider
 }
 
 // TxStatInfo encapsulates information about a transaction
@@ -53,17 +54,33 @@ func NewCommitBatchPreparer(
 	postOrderSimulatorProvider PostOrderSimulatorProvider,
 	db *privacyenabledstate.DB,
 	customTxProcessors map[common.HeaderType]ledger.CustomTxProcessor,
+	counterCapabilityProvider ledger.CounterCapabilityProvider,
 	hashFunc rwsetutil.HashFunc,
 ) *CommitBatchPreparer {
-	return &CommitBatchPreparer{
-		postOrderSimulatorProvider,
-		db,
-		&validator{
+	p := &CommitBatchPreparer{
+		postOrderSimulatorProvider: postOrderSimulatorProvider,
+		db:                         db,
+		validator: &validator{
 			db:       db,
 			hashFunc: hashFunc,
 		},
-		customTxProcessors,
+		customTxProcessors:        customTxProcessors,
+		counterCapabilityProvider: counterCapabilityProvider,
 	}
+	if counterCapabilityProvider != nil {
+		p.validator.countersEnabled = p.commutativeCountersEnabled
+	}
+	return p
+}
+
+// commutativeCountersEnabled reads the capability from the state committed before the block being validated
+func (p *CommitBatchPreparer) commutativeCountersEnabled() (bool, error) {
+	sim, err := p.postOrderSimulatorProvider.NewTxSimulator("")
+	if err != nil {
+		return false, err
+	}
+	defer sim.Done()
+	return p.counterCapabilityProvider.CommutativeCountersEnabled(sim)
 }
 
 // ValidateAndPrepareBatch performs validation of transactions in the block and prepares the batch of final writes
@@ -16,9 +16,9 @@ import (
 )
 
 func prepareTxOps(rwset *rwsetutil.TxRwSet, txht *version.Height,
-	precedingUpdates *publicAndHashUpdates, db *privacyenabledstate.DB) (txOps, error) {
+	precedingUpdates *publicAndHashUpdates, db *privacyenabledstate.DB, applyCounterDeltas bool) (txOps, error) {
 	txops := txOps{}
-	if err := txops.applyTxRwset(rwset); err != nil {
+	if err := txops.applyTxRwset(rwset, applyCounterDeltas); err != nil {
 		return nil, err
 	}
 	for ck, keyop := range txops {
@@ -28,6 +28,24 @@ func prepareTxOps(rwset *rwsetutil.TxRwSet, txht *version.Height,
 			continue
 		}
 
+		// a counter key is incremented in the current transaction. Add the delta to the value
+		// from the last committed state and keep the metadata of the last committed state
+		if keyop.isCounterDelta() {
+			latestVal, err := retrieveLatestState(ck.ns, ck.coll, ck.key, precedingUpdates, db)
+			if err != nil {
+				return nil, err
+			}
+			var value, metadata []byte
+			if latestVal != nil {
+				value, metadata = latestVal.Value, latestVal.Metadata
+			}
+			if keyop.value, err = rwsetutil.AddCounterDelta(value, keyop.delta); err != nil {
+				return nil, err
+			}
+			keyop.metadata = metadata
+			continue
+		}
+
 		// check if only value is updated in the current transaction then merge the metadata from last committed state
 		if keyop.isOnlyUpsert() {
 			latestMetadata, err := retrieveLatestMetadata(ck.ns, ck.coll, ck.key, precedingUpdates, db)
@@ -55,8 +73,9 @@ func prepareTxOps(rwset *rwsetutil.TxRwSet, txht *version.Height,
 }
 
 // applyTxRwset records the upsertion/deletion of a kv and updatation/deletion
-// of associated metadata present in a txrwset
-func (txops txOps) applyTxRwset(rwset *rwsetutil.TxRwSet) error {
+// of associated metadata present in a txrwset. The counter deltas are recorded
+// only if applyCounterDeltas is set, that is if the channel enables them
+func (txops txOps) applyTxRwset(rwset *rwsetutil.TxRwSet, applyCounterDeltas bool) error {
 	for _, nsRWSet := range rwset.NsRwSets {
 		ns := nsRWSet.NameSpace
 		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
@@ -67,6 +86,11 @@ func (txops txOps) applyTxRwset(rwset *rwsetutil.TxRwSet) error {
 				return err
 			}
 		}
+		if applyCounterDeltas {
+			for _, kvCounterDelta := range nsRWSet.KvRwSet.CounterDeltas {
+				txops.counterDelta(compositeKey{ns, "", kvCounterDelta.Key}, kvCounterDelta.Delta)
+			}
+		}
 
 		// apply collection level kvwrite and kvMetadataWrite
 		for _, collHashRWset := range nsRWSet.CollHashedRwSets {
@@ -166,6 +190,7 @@ const (
 	metadataUpdate
 	metadataDelete
 	keyDelete
+	counterDelta
 )
 
 type compositeKey struct {
@@ -178,6 +203,7 @@ type keyOps struct {
 	flag     keyOpsFlag
 	value    []byte
 	metadata []byte
+	delta    int64
 }
 
 ////////////////// txOps functions
@@ -204,6 +230,12 @@ func (txops txOps) metadataDelete(k compositeKey) {
 	keyops.flag += metadataDelete
 }
 
+func (txops txOps) counterDelta(k compositeKey, delta int64) {
+	keyops := txops.getOrCreateKeyEntry(k)
+	keyops.flag += counterDelta
+	keyops.delta = delta
+}
+
 func (txops txOps) getOrCreateKeyEntry(k compositeKey) *keyOps {
 	keyops, ok := txops[k]
 	if !ok {
@@ -230,3 +262,7 @@ func (keyops keyOps) isUpsertAndMetadataUpdate() bool {
 func (keyops keyOps) isOnlyUpsert() bool {
 	return keyops.flag|upsertVal == upsertVal
 }
+
+func (keyops keyOps) isCounterDelta() bool {
+	return keyops.flag == counterDelta
+}
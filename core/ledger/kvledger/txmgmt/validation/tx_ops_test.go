@@ -78,7 +78,7 @@ func TestTxOpsPreparationValueUpdate(t *testing.T) {
 		nil,
 	)
 
-	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db)
+	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db, false)
 	require.NoError(t, err)
 	require.Len(t, txOps, 3)
 
@@ -135,7 +135,7 @@ func TestTxOpsPreparationMetadataUpdates(t *testing.T) {
 		},
 	)
 
-	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db)
+	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db, false)
 	require.NoError(t, err)
 	require.Len(t, txOps, 2) // key3 should have been removed from the txOps because, the key3 does not exist and only metadata is being updated
 
@@ -155,6 +155,60 @@ func TestTxOpsPreparationMetadataUpdates(t *testing.T) {
 	require.Equal(t, ck2ExpectedKeyOps, txOps[ck2])
 }
 
+func TestTxOpsPreparationCounterDeltas(t *testing.T) {
+	testDBEnv := privacyenabledstate.LevelDBTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	ck1, ck2, ck3 :=
+		compositeKey{ns: "ns1", key: "key1"},
+		compositeKey{ns: "ns1", key: "key2"},
+		compositeKey{ns: "ns1", key: "key3"}
+
+	updateBatch := privacyenabledstate.NewUpdateBatch()
+	updateBatch.PubUpdates.PutValAndMetadata( // write key1 with a counter value and metadata
+		ck1.ns, ck1.key,
+		[]byte("10"),
+		testutilSerializedMetadata(t, map[string][]byte{"metadata1": []byte("metadata1")}),
+		version.NewHeight(1, 1))
+	db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(1, 1)) //write the above initial state to db
+
+	precedingUpdates := newPubAndHashUpdates() // key2 is written by a preceding transaction of the block
+	precedingUpdates.publicUpdates.Put(ck2.ns, ck2.key, []byte("-3"), version.NewHeight(2, 0))
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	require.NoError(t, rwsetBuilder.AddToCounterDeltaSet(ck1.ns, ck1.key, 5))
+	require.NoError(t, rwsetBuilder.AddToCounterDeltaSet(ck2.ns, ck2.key, 4))
+	require.NoError(t, rwsetBuilder.AddToCounterDeltaSet(ck3.ns, ck3.key, -7))
+
+	// the counter deltas are ignored unless the channel enables them
+	txOps, err := prepareTxOps(rwsetBuilder.GetTxReadWriteSet(), version.NewHeight(2, 1), precedingUpdates, db, false)
+	require.NoError(t, err)
+	require.Len(t, txOps, 0)
+
+	txOps, err = prepareTxOps(rwsetBuilder.GetTxReadWriteSet(), version.NewHeight(2, 1), precedingUpdates, db, true)
+	require.NoError(t, err)
+	require.Len(t, txOps, 3)
+
+	require.Equal(t, &keyOps{ // key1 should have the incremented value and the existing metadata
+		flag:     counterDelta,
+		value:    []byte("15"),
+		metadata: testutilSerializedMetadata(t, map[string][]byte{"metadata1": []byte("metadata1")}),
+		delta:    5,
+	}, txOps[ck1])
+	require.Equal(t, &keyOps{ // key2 should be incremented from the value written by the preceding transaction
+		flag:  counterDelta,
+		value: []byte("1"),
+		delta: 4,
+	}, txOps[ck2])
+	require.Equal(t, &keyOps{ // key3 does not exist and is incremented from zero
+		flag:  counterDelta,
+		value: []byte("-7"),
+		delta: -7,
+	}, txOps[ck3])
+}
+
 func TestTxOpsPreparationMetadataDelete(t *testing.T) {
 	testDBEnv := privacyenabledstate.LevelDBTestEnv{}
 	testDBEnv.Init(t)
@@ -187,7 +241,7 @@ func TestTxOpsPreparationMetadataDelete(t *testing.T) {
 		},
 	)
 
-	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db)
+	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db, false)
 	require.NoError(t, err)
 	require.Len(t, txOps, 2) // key3 should have been removed from the txOps because, the key3 does not exist and only metadata is being updated
 
@@ -248,7 +302,7 @@ func TestTxOpsPreparationMixedUpdates(t *testing.T) {
 		},
 	)
 
-	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db)
+	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db, false)
 	require.NoError(t, err)
 	require.Len(t, txOps, 4)
 
@@ -335,7 +389,7 @@ func TestTxOpsPreparationPvtdataHashes(t *testing.T) {
 		},
 	)
 
-	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db)
+	txOps, err := prepareTxOps(rwset, version.NewHeight(1, 2), precedingUpdates, db, false)
 	require.NoError(t, err)
 	require.Len(t, txOps, 4)
 
@@ -445,7 +499,7 @@ func TestInterpretNilValueKVWritesAsDelete(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			txOps := txOps{}
-			err := txOps.applyTxRwset(tc.rwset)
+			err := txOps.applyTxRwset(tc.rwset, false)
 			require.NoError(t, err)
 
 			for _, keyToVerify := range tc.compositeKeysToVerify {
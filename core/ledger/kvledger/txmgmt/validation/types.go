@@ -83,10 +83,11 @@ func (u *publicAndHashUpdates) applyWriteSet(
 	txHeight *version.Height,
 	db *privacyenabledstate.DB,
 	containsPostOrderWrites bool,
+	applyCounterDeltas bool,
 ) error {
 	u.publicUpdates.ContainsPostOrderWrites =
 		u.publicUpdates.ContainsPostOrderWrites || containsPostOrderWrites
-	txops, err := prepareTxOps(txRWSet, txHeight, u, db)
+	txops, err := prepareTxOps(txRWSet, txHeight, u, db, applyCounterDeltas)
 	logger.Debugf("txops=%#v", txops)
 	if err != nil {
 		return err
@@ -20,6 +20,9 @@ import (
 type validator struct {
 	db       *privacyenabledstate.DB
 	hashFunc rwsetutil.HashFunc
+	// countersEnabled tells whether the channel config committed to the state
+	// enables the increments of counter keys. A nil func disables them
+	countersEnabled func() (bool, error)
 }
 
 // preLoadCommittedVersionOfRSet loads committed version of all keys in each
@@ -90,19 +93,28 @@ func (v *validator) validateAndPrepareBatch(blk *block, doMVCCValidation bool) (
 		}
 	}
 
+	countersEnabled, err := v.commutativeCountersEnabled(blk)
+	if err != nil {
+		return nil, err
+	}
+
 	updates := newPubAndHashUpdates()
 	for _, tx := range blk.txs {
 		var validationCode peer.TxValidationCode
 		var err error
-		if validationCode, err = v.validateEndorserTX(tx.rwset, doMVCCValidation, updates); err != nil {
+		if validationCode, err = v.validateEndorserTX(tx.rwset, doMVCCValidation, updates, countersEnabled); err != nil {
 			return nil, err
 		}
 
 		tx.validationCode = validationCode
 		if validationCode == peer.TxValidationCode_VALID {
 			logger.Debugf("Block [%d] Transaction index [%d] TxId [%s] marked as valid by state validator. ContainsPostOrderWrites [%t]", blk.num, tx.indexInBlock, tx.id, tx.containsPostOrderWrites)
+			if !countersEnabled && containsCounterDeltas(tx.rwset) {
+				logger.Warningf("Block [%d] Transaction index [%d] TxId [%s] increments counter keys but the channel does not enable commutative counters, the increments are ignored",
+					blk.num, tx.indexInBlock, tx.id)
+			}
 			committingTxHeight := version.NewHeight(blk.num, uint64(tx.indexInBlock))
-			if err := updates.applyWriteSet(tx.rwset, committingTxHeight, v.db, tx.containsPostOrderWrites); err != nil {
+			if err := updates.applyWriteSet(tx.rwset, committingTxHeight, v.db, tx.containsPostOrderWrites, countersEnabled); err != nil {
 				return nil, err
 			}
 		} else {
@@ -113,11 +125,38 @@ func (v *validator) validateAndPrepareBatch(blk *block, doMVCCValidation bool) (
 	return updates, nil
 }
 
+// commutativeCountersEnabled returns true if the block increments counter keys and
+// the channel config committed before the block enables commutative counters. The
+// capability is read from the state rather than from the current channel config so
+// that the blocks committed before the capability was enabled, which the peers that
+// committed them did not increment, are never reinterpreted on recovery or rebuild
+func (v *validator) commutativeCountersEnabled(blk *block) (bool, error) {
+	if v.countersEnabled == nil {
+		return false, nil
+	}
+	for _, tx := range blk.txs {
+		if containsCounterDeltas(tx.rwset) {
+			return v.countersEnabled()
+		}
+	}
+	return false, nil
+}
+
+func containsCounterDeltas(txRWSet *rwsetutil.TxRwSet) bool {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if rwsetutil.ContainsCounterDeltas(nsRWSet) {
+			return true
+		}
+	}
+	return false
+}
+
 // validateEndorserTX validates endorser transaction
 func (v *validator) validateEndorserTX(
 	txRWSet *rwsetutil.TxRwSet,
 	doMVCCValidation bool,
-	updates *publicAndHashUpdates) (peer.TxValidationCode, error) {
+	updates *publicAndHashUpdates,
+	countersEnabled bool) (peer.TxValidationCode, error) {
 
 	var validationCode = peer.TxValidationCode_VALID
 	var err error
@@ -125,7 +164,14 @@ func (v *validator) validateEndorserTX(
 	if doMVCCValidation {
 		validationCode, err = v.validateTx(txRWSet, updates)
 	}
-	return validationCode, err
+	if validationCode != peer.TxValidationCode_VALID || err != nil || !countersEnabled {
+		return validationCode, err
+	}
+	// counter deltas are applied to the latest state at commit time and are
+	// validated irrespective of the mvcc validation, so that a delta that
+	// cannot be applied invalidates the transaction rather than failing the
+	// commit of the block
+	return v.validateCounterDeltas(txRWSet, updates)
 }
 
 func (v *validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *publicAndHashUpdates) (peer.TxValidationCode, error) {
@@ -158,6 +204,60 @@ func (v *validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *publicAndHas
 	return peer.TxValidationCode_VALID, nil
 }
 
+////////////////////////////////////////////////////////////////////////////////
+/////                 Validation of counter deltas
+////////////////////////////////////////////////////////////////////////////////
+
+// validateCounterDeltas checks that the counter keys incremented by the transaction
+// are not otherwise written by the transaction, and that the deltas can be added to
+// the latest values of the keys, which must be counters
+func (v *validator) validateCounterDeltas(txRWSet *rwsetutil.TxRwSet, updates *publicAndHashUpdates) (peer.TxValidationCode, error) {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		for _, counterDelta := range nsRWSet.KvRwSet.CounterDeltas {
+			if isWrittenInTx(nsRWSet.KvRwSet, counterDelta.Key) {
+				logger.Warningf("Counter key [%s:%s] is incremented more than once, or is also written, by the transaction", ns, counterDelta.Key)
+				return peer.TxValidationCode_INVALID_WRITESET, nil
+			}
+			latestVal, err := retrieveLatestState(ns, "", counterDelta.Key, updates, v.db)
+			if err != nil {
+				return peer.TxValidationCode(-1), err
+			}
+			var value []byte
+			if latestVal != nil {
+				value = latestVal.Value
+			}
+			if _, err := rwsetutil.AddCounterDelta(value, counterDelta.Delta); err != nil {
+				logger.Warningf("Cannot increment counter key [%s:%s]: %s", ns, counterDelta.Key, err)
+				return peer.TxValidationCode_INVALID_WRITESET, nil
+			}
+		}
+	}
+	return peer.TxValidationCode_VALID, nil
+}
+
+// isWrittenInTx returns true if the key is written, or has its metadata updated, in the
+// public write-set, or is incremented more than once
+func isWrittenInTx(kvRWSet *kvrwset.KVRWSet, key string) bool {
+	for _, kvWrite := range kvRWSet.Writes {
+		if kvWrite.Key == key {
+			return true
+		}
+	}
+	for _, metadataWrite := range kvRWSet.MetadataWrites {
+		if metadataWrite.Key == key {
+			return true
+		}
+	}
+	increments := 0
+	for _, counterDelta := range kvRWSet.CounterDeltas {
+		if counterDelta.Key == key {
+			increments++
+		}
+	}
+	return increments > 1
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 /////                 Validation of public read-set
 ////////////////////////////////////////////////////////////////////////////////
@@ -9,6 +9,7 @@ package validation
 import (
 	"crypto/sha256"
 	"fmt"
+	"math"
 	"os"
 	"testing"
 
@@ -233,6 +234,64 @@ func TestValidator(t *testing.T) {
 	checkValidation(t, testValidator, getTestPubSimulationRWSet(t, rwsetBuilder4, rwsetBuilder5), []int{1})
 }
 
+func TestCounterDeltaValidation(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	//populate db with initial data
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "counter1", []byte("1"), version.NewHeight(1, 0))
+	batch.PubUpdates.Put("ns1", "counter2", []byte(fmt.Sprintf("%d", math.MaxInt64)), version.NewHeight(1, 1))
+	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 2))
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 2))
+
+	countersEnabled := false
+	testValidator := &validator{
+		db:              db,
+		hashFunc:        testHashFunc,
+		countersEnabled: func() (bool, error) { return countersEnabled, nil },
+	}
+
+	// the increments are neither validated nor applied if the channel does not enable counters
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	require.NoError(t, rwsetBuilder.AddToCounterDeltaSet("ns1", "key1", 1))
+	checkValidation(t, testValidator, getTestPubSimulationRWSet(t, rwsetBuilder), []int{})
+	countersEnabled = true
+
+	// concurrent increments of counter1 should all be valid and should not conflict
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	require.NoError(t, rwsetBuilder1.AddToCounterDeltaSet("ns1", "counter1", 2))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	require.NoError(t, rwsetBuilder2.AddToCounterDeltaSet("ns1", "counter1", -1))
+	// rwset3 reads counter1 and conflicts with the preceding increments
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToReadSet("ns1", "counter1", version.NewHeight(1, 0))
+	// rwset4 overflows counter2
+	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
+	require.NoError(t, rwsetBuilder4.AddToCounterDeltaSet("ns1", "counter2", 1))
+	// rwset5 increments a key that is not a counter
+	rwsetBuilder5 := rwsetutil.NewRWSetBuilder()
+	require.NoError(t, rwsetBuilder5.AddToCounterDeltaSet("ns1", "key1", 1))
+	checkValidation(t, testValidator,
+		getTestPubSimulationRWSet(t, rwsetBuilder1, rwsetBuilder2, rwsetBuilder3, rwsetBuilder4, rwsetBuilder5),
+		[]int{2, 3, 4})
+
+	// a write-set that both writes and increments a key is invalid
+	txRWSet := getTestPubSimulationRWSet(t, rwsetBuilder1)[0]
+	txRWSet.NsRwSets[0].KvRwSet.Writes = []*kvrwset.KVWrite{{Key: "counter1", Value: []byte("5")}}
+	checkValidation(t, testValidator, []*rwsetutil.TxRwSet{txRWSet}, []int{0})
+
+	// a write-set that increments a key twice is invalid
+	txRWSet = getTestPubSimulationRWSet(t, rwsetBuilder1)[0]
+	txRWSet.NsRwSets[0].KvRwSet.CounterDeltas = append(
+		txRWSet.NsRwSets[0].KvRwSet.CounterDeltas,
+		rwsetutil.NewKVCounterDelta("counter1", 1),
+	)
+	checkValidation(t, testValidator, []*rwsetutil.TxRwSet{txRWSet}, []int{0})
+}
+
 func TestPhantomValidation(t *testing.T) {
 	testDBEnv := testEnvs[levelDBtestEnvName]
 	testDBEnv.Init(t)
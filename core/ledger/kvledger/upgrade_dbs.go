@@ -7,7 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
@@ -18,6 +24,8 @@ import (
 // It checks the format of idStore and does not drop any databases
 // if the format is already the latest version. Otherwise, it drops
 // ledger databases and upgrades the idStore format.
+// If the rebuild of the databases following a previous upgrade is yet
+// to complete, it resumes upon server restart.
 func UpgradeDBs(config *ledger.Config) error {
 	rootFSPath := config.RootFSPath
 	fileLockPath := fileLockPath(rootFSPath)
@@ -30,6 +38,10 @@ func UpgradeDBs(config *ledger.Config) error {
 
 	logger.Infof("Ledger data folder from config = [%s]", rootFSPath)
 
+	if resume, err := resumeRebuild(rootFSPath); err != nil || resume {
+		return err
+	}
+
 	if config.StateDBConfig.StateDatabase == "CouchDB" {
 		if err := statecouchdb.DropApplicationDBs(config.StateDBConfig.CouchDB); err != nil {
 			return err
@@ -49,3 +61,202 @@ func UpgradeDBs(config *ledger.Config) error {
 	idStore := &idStore{db, dbPath}
 	return idStore.upgradeFormat()
 }
+
+const (
+	// UpgradeActionNone means that UpgradeDBs leaves the database untouched
+	UpgradeActionNone = "none"
+	// UpgradeActionUpgradeFormat means that UpgradeDBs updates the format of the database in place
+	UpgradeActionUpgradeFormat = "upgrade format"
+	// UpgradeActionDropAndRebuild means that UpgradeDBs drops the database, which is
+	// rebuilt with the current format from the block files upon peer restart
+	UpgradeActionDropAndRebuild = "drop and rebuild"
+
+	// estimatedRebuildRate is a conservative estimate of the number of bytes of block
+	// files per second processed by the peer while rebuilding the dropped databases
+	estimatedRebuildRate = 4 << 20
+)
+
+// DBUpgrade describes a ledger database and what UpgradeDBs does to it.
+type DBUpgrade struct {
+	Name      string
+	Location  string
+	Exists    bool
+	Format    string
+	SizeBytes int64
+	Action    string
+}
+
+// UpgradePlan is the outcome of a dry run of UpgradeDBs.
+type UpgradePlan struct {
+	DBs []*DBUpgrade
+	// PendingRebuilds lists the ledgers whose databases are still to be rebuilt after a
+	// previous upgrade, in which case UpgradeDBs does not drop the databases again
+	PendingRebuilds []string
+	// BlockFilesBytes is the size of the block files the dropped databases are rebuilt from
+	BlockFilesBytes int64
+	// EstimatedRebuildDuration is a rough estimate of the time the peer takes upon restart
+	// to rebuild the dropped databases
+	EstimatedRebuildDuration time.Duration
+	// RequiredDiskBytes is the disk space needed to rebuild the dropped databases, including
+	// the headroom needed by the compactions of leveldb
+	RequiredDiskBytes int64
+	// AvailableDiskBytes is the free disk space of the ledger file system once the databases are
+	// dropped, or -1 if it cannot be retrieved
+	AvailableDiskBytes int64
+	// Blockers lists the issues that would make UpgradeDBs or the rebuild of the databases fail
+	Blockers []string
+}
+
+// UpgradeDBsDryRun inspects the formats of the ledger databases and reports what UpgradeDBs
+// would do to each of them, the time and disk space needed to rebuild the dropped databases,
+// and any issue preventing the upgrade, without modifying any database. As UpgradeDBs, it
+// requires the peer to be offline.
+func UpgradeDBsDryRun(config *ledger.Config) (*UpgradePlan, error) {
+	rootFSPath := config.RootFSPath
+	plan := &UpgradePlan{AvailableDiskBytes: -1}
+	if _, err := os.Stat(rootFSPath); os.IsNotExist(err) {
+		return plan, nil
+	}
+
+	fileLock := leveldbhelper.NewFileLock(fileLockPath(rootFSPath))
+	if err := fileLock.Lock(); err != nil {
+		return nil, errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	idStoreUpgrade, err := inspectIDStore(rootFSPath)
+	if err != nil {
+		if _, ok := err.(*dataformat.ErrFormatMismatch); !ok {
+			return nil, err
+		}
+		plan.Blockers = append(plan.Blockers, err.Error())
+	}
+	plan.DBs = append(plan.DBs, idStoreUpgrade)
+
+	if idStoreUpgrade.Format == dataformat.CurrentFormat {
+		if _, exists, err := leveldbhelper.RetrieveDataFormat(BookkeeperDBPath(rootFSPath)); err != nil {
+			return nil, err
+		} else if exists {
+			if plan.PendingRebuilds, err = pendingRebuilds(rootFSPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if config.StateDBConfig.StateDatabase == "CouchDB" {
+		couchDBUpgrade := &DBUpgrade{Name: "state (CouchDB)", Location: config.StateDBConfig.CouchDB.Address}
+		format, dbNames, err := statecouchdb.InspectApplicationDBs(config.StateDBConfig.CouchDB)
+		if err != nil {
+			plan.Blockers = append(plan.Blockers, fmt.Sprintf("the CouchDB state database cannot be inspected: %s", err))
+		}
+		couchDBUpgrade.Exists = len(dbNames) > 0
+		couchDBUpgrade.Format = format
+		plan.DBs = append(plan.DBs, couchDBUpgrade)
+	}
+	leveldbs := []struct {
+		name string
+		path string
+	}{
+		{"state", StateDBPath(rootFSPath)},
+		{"config history", ConfigHistoryDBPath(rootFSPath)},
+		{"bookkeeper", BookkeeperDBPath(rootFSPath)},
+		{"history", HistoryDBPath(rootFSPath)},
+		{"block index", blkstorage.BlockStoreIndexPath(BlockStorePath(rootFSPath))},
+	}
+	for _, l := range leveldbs {
+		format, exists, err := leveldbhelper.RetrieveDataFormat(l.path)
+		if err != nil {
+			return nil, err
+		}
+		size, err := dirSize(l.path)
+		if err != nil {
+			return nil, err
+		}
+		plan.DBs = append(plan.DBs, &DBUpgrade{Name: l.name, Location: l.path, Exists: exists, Format: format, SizeBytes: size})
+	}
+
+	var droppedBytes int64
+	for _, db := range plan.DBs[1:] {
+		db.Action = UpgradeActionNone
+		if db.Exists && len(plan.PendingRebuilds) == 0 {
+			db.Action = UpgradeActionDropAndRebuild
+			droppedBytes += db.SizeBytes
+		}
+	}
+	if droppedBytes == 0 && len(plan.PendingRebuilds) == 0 {
+		return plan, nil
+	}
+
+	blockStoreBytes, err := dirSize(BlockStorePath(rootFSPath))
+	if err != nil {
+		return nil, err
+	}
+	plan.BlockFilesBytes = blockStoreBytes - plan.DBs[len(plan.DBs)-1].SizeBytes
+	plan.EstimatedRebuildDuration = time.Duration(plan.BlockFilesBytes/estimatedRebuildRate+1) * time.Second
+	// the rebuilt databases are assumed to reach the size of the dropped ones, which
+	// leveldb may temporarily double while compacting
+	plan.RequiredDiskBytes = 2 * droppedBytes
+	available, err := availableDiskSpace(rootFSPath)
+	if err != nil {
+		logger.Warningf("Unable to check the disk space needed to rebuild the databases: %s", err)
+		return plan, nil
+	}
+	plan.AvailableDiskBytes = int64(available) + droppedBytes
+	if plan.RequiredDiskBytes > plan.AvailableDiskBytes {
+		plan.Blockers = append(plan.Blockers, fmt.Sprintf("rebuilding the databases needs %d bytes of disk space but only %d bytes are available at [%s]",
+			plan.RequiredDiskBytes, plan.AvailableDiskBytes, rootFSPath))
+	}
+	return plan, nil
+}
+
+// inspectIDStore returns what UpgradeDBs does to the idStore. The error is an
+// ErrFormatMismatch if the format of the idStore cannot be upgraded.
+func inspectIDStore(rootFSPath string) (*DBUpgrade, error) {
+	dbPath := LedgerProviderPath(rootFSPath)
+	idStoreUpgrade := &DBUpgrade{Name: "idStore", Location: dbPath, Action: UpgradeActionNone}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return idStoreUpgrade, nil
+	}
+	db := leveldbhelper.CreateDB(&leveldbhelper.Conf{DBPath: dbPath})
+	db.Open()
+	defer db.Close()
+	idStore := &idStore{db, dbPath}
+
+	emptyDB, err := db.IsEmpty()
+	if err != nil || emptyDB {
+		return idStoreUpgrade, err
+	}
+	idStoreUpgrade.Exists = true
+	if idStoreUpgrade.SizeBytes, err = dirSize(dbPath); err != nil {
+		return idStoreUpgrade, err
+	}
+	format, err := db.Get(formatKey)
+	if err != nil {
+		return idStoreUpgrade, err
+	}
+	idStoreUpgrade.Format = string(format)
+	eligible, err := idStore.checkUpgradeEligibility()
+	if eligible {
+		idStoreUpgrade.Action = UpgradeActionUpgradeFormat
+	}
+	return idStoreUpgrade, err
+}
+
+// dirSize returns the total size of the files under the given dir, or 0 if the dir does not exist
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, errors.Wrapf(err, "failed to compute the size of [%s]", dir)
+}
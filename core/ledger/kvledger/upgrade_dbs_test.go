@@ -8,9 +8,12 @@ package kvledger
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -34,3 +37,104 @@ func TestUpgradeWrongFormat(t *testing.T) {
 	}
 	require.EqualError(t, err, expectedErr.Error())
 }
+
+func TestUpgradeDBsDryRun(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	for i := 0; i < 2; i++ {
+		genesisBlock, _ := configtxtest.MakeGenesisBlock(constructTestLedgerID(i))
+		_, err := provider.Create(genesisBlock)
+		require.NoError(t, err)
+	}
+
+	// the dry run should fail when provider is still open
+	_, err := UpgradeDBsDryRun(conf)
+	require.Error(t, err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying")
+	provider.Close()
+
+	plan, err := UpgradeDBsDryRun(conf)
+	require.NoError(t, err)
+	require.Empty(t, plan.Blockers)
+	require.Empty(t, plan.PendingRebuilds)
+
+	rootFSPath := conf.RootFSPath
+	require.Equal(t, &DBUpgrade{
+		Name:      "idStore",
+		Location:  LedgerProviderPath(rootFSPath),
+		Exists:    true,
+		Format:    dataformat.CurrentFormat,
+		SizeBytes: plan.DBs[0].SizeBytes,
+		Action:    UpgradeActionNone,
+	}, plan.DBs[0])
+	expectedDBs := map[string]string{
+		"state":          StateDBPath(rootFSPath),
+		"config history": ConfigHistoryDBPath(rootFSPath),
+		"bookkeeper":     BookkeeperDBPath(rootFSPath),
+		"history":        HistoryDBPath(rootFSPath),
+		"block index":    filepath.Join(BlockStorePath(rootFSPath), "index"),
+	}
+	require.Len(t, plan.DBs, len(expectedDBs)+1)
+	var droppedBytes int64
+	for _, db := range plan.DBs[1:] {
+		require.Equal(t, expectedDBs[db.Name], db.Location)
+		require.True(t, db.Exists, db.Name)
+		require.Equal(t, UpgradeActionDropAndRebuild, db.Action)
+		require.NotZero(t, db.SizeBytes, db.Name)
+		droppedBytes += db.SizeBytes
+	}
+	require.Equal(t, dataformat.CurrentFormat, plan.DBs[1].Format)
+	require.NotZero(t, plan.BlockFilesBytes)
+	require.NotZero(t, plan.EstimatedRebuildDuration)
+	require.Equal(t, 2*droppedBytes, plan.RequiredDiskBytes)
+	require.True(t, plan.AvailableDiskBytes >= droppedBytes)
+
+	// nothing is dropped
+	empty, err := util.DirEmpty(StateDBPath(rootFSPath))
+	require.NoError(t, err)
+	require.False(t, empty)
+	provider = testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	l, err := provider.Open(constructTestLedgerID(0))
+	require.NoError(t, err)
+	l.Close()
+
+	// the idStore in the previous format is upgraded in place
+	require.NoError(t, provider.idStore.db.Put(formatKey, []byte(dataformat.PreviousFormat), true))
+	provider.Close()
+	plan, err = UpgradeDBsDryRun(conf)
+	require.NoError(t, err)
+	require.Empty(t, plan.Blockers)
+	require.Equal(t, dataformat.PreviousFormat, plan.DBs[0].Format)
+	require.Equal(t, UpgradeActionUpgradeFormat, plan.DBs[0].Action)
+}
+
+func TestUpgradeDBsDryRunWrongFormat(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	err := provider.idStore.db.Put(formatKey, []byte("x.0"), true)
+	provider.Close()
+	require.NoError(t, err)
+
+	plan, err := UpgradeDBsDryRun(conf)
+	require.NoError(t, err)
+	expectedErr := &dataformat.ErrFormatMismatch{
+		ExpectedFormat: dataformat.PreviousFormat,
+		Format:         "x.0",
+		DBInfo:         fmt.Sprintf("leveldb for channel-IDs at [%s]", LedgerProviderPath(conf.RootFSPath)),
+	}
+	require.Equal(t, []string{expectedErr.Error()}, plan.Blockers)
+	require.Equal(t, "x.0", plan.DBs[0].Format)
+}
+
+func TestUpgradeDBsDryRunNoLedgerData(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	conf.RootFSPath = filepath.Join(conf.RootFSPath, "missing")
+
+	plan, err := UpgradeDBsDryRun(conf)
+	require.NoError(t, err)
+	require.Empty(t, plan.DBs)
+	require.Empty(t, plan.Blockers)
+	require.NoDirExists(t, conf.RootFSPath)
+}
@@ -32,9 +32,29 @@ type Initializer struct {
 	HealthCheckRegistry             HealthCheckRegistry
 	Config                          *Config
 	CustomTxProcessors              map[common.HeaderType]CustomTxProcessor
+	CounterCapabilityProvider       CounterCapabilityProvider
 	HashProvider                    HashProvider
+	RebuildProgressListener         RebuildProgressListener
+	CommitListeners                 []CommitListener
 }
 
+// RebuildProgress reports the progress of the rebuild of the state and history
+// databases of a ledger from its block store
+type RebuildProgress struct {
+	LedgerID string
+	// BlocksProcessed is the number of blocks recommitted since the rebuild started,
+	// including the blocks recommitted before the peer restarted
+	BlocksProcessed uint64
+	// BlocksTotal is the number of blocks to recommit to complete the rebuild
+	BlocksTotal uint64
+	// ETA is the estimated time remaining to complete the rebuild
+	ETA time.Duration
+}
+
+// RebuildProgressListener is invoked periodically while the databases of a ledger are
+// rebuilt from its block store, and once the rebuild completes
+type RebuildProgressListener func(progress *RebuildProgress)
+
 // Config is a structure used to configure a ledger provider.
 type Config struct {
 	// RootFSPath is the top-level directory where ledger files are stored.
@@ -47,6 +67,13 @@ type Config struct {
 	HistoryDBConfig *HistoryDBConfig
 	// SnapshotsConfig holds the configuration parameters for the snapshots.
 	SnapshotsConfig *SnapshotsConfig
+	// BlockStoreConfig holds the configuration parameters for the block store.
+	BlockStoreConfig *BlockStoreConfig
+	// BackupsConfig holds the configuration parameters for the online backups of the ledgers.
+	BackupsConfig *BackupsConfig
+	// CompactionConfig holds the configuration parameters for the scheduled compactions of the
+	// leveldb based stores.
+	CompactionConfig *CompactionConfig
 }
 
 // StateDBConfig is a structure used to configure the state parameters for the ledger.
@@ -57,6 +84,19 @@ type StateDBConfig struct {
 	// CouchDB is the configuration for CouchDB.  It is used when StateDatabase
 	// is set to "CouchDB".
 	CouchDB *CouchDBConfig
+	// RangeScanCursors is the configuration for the resumable range scan cursors.
+	RangeScanCursors *RangeScanCursorsConfig
+}
+
+// RangeScanCursorsConfig is a structure used to configure the resumable range scan cursors.
+type RangeScanCursorsConfig struct {
+	// Lifetime is the duration for which a view of the state is kept pinned
+	// for the range scan cursors opened at its height. Zero means one minute.
+	Lifetime time.Duration
+	// MaxPinnedViewsPerChaincode is the maximum number of views of the state
+	// a channel keeps pinned at the same time for the cursors of a chaincode.
+	// Zero means two.
+	MaxPinnedViewsPerChaincode int
 }
 
 // CouchDBConfig is a structure used to configure a CouchInstance.
@@ -82,6 +122,14 @@ type CouchDBConfig struct {
 	// MaxBatchUpdateSize is the maximum number of records to included in CouchDB
 	// bulk update operations.
 	MaxBatchUpdateSize int
+	// BulkLoadMaxBatchUpdateSize is the maximum number of records to include in
+	// CouchDB bulk update operations while the state database is rebuilt from
+	// the blocks. Zero falls back to MaxBatchUpdateSize.
+	BulkLoadMaxBatchUpdateSize int
+	// AttachmentThreshold is the size in bytes above which binary values are
+	// split into attachments of at most that size, saved individually rather
+	// than base64 encoded in bulk update operations. Zero disables it.
+	AttachmentThreshold int
 	// WarmIndexesAfterNBlocks is the number of blocks after which to warm any
 	// CouchDB indexes.
 	WarmIndexesAfterNBlocks int
@@ -95,6 +143,9 @@ type CouchDBConfig struct {
 	// UserCacheSizeMBs needs to be a multiple of 32 MB. If it is not a multiple of 32 MB,
 	// the peer would round the size to the next multiple of 32 MB.
 	UserCacheSizeMBs int
+	// CacheWarmupNamespaces lists the namespaces whose states are loaded into the
+	// state cache when the state database of a channel is opened.
+	CacheWarmupNamespaces []string
 }
 
 // PrivateDataConfig is a structure used to configure a private data storage provider.
@@ -116,12 +167,47 @@ type PrivateDataConfig struct {
 	// from other peers. A chance for eligible deprioritized missing data
 	// would be given after every DeprioritizedDataReconcilerInterval
 	DeprioritizedDataReconcilerInterval time.Duration
+	// LazyHydration, when set, places the historical missing data of a collection
+	// for which the peer becomes eligible in the eligible deprioritized category,
+	// so that fetching it does not delay the reconciliation of recent blocks
+	LazyHydration bool
+	// PurgeIneligibleData, when set, purges the private data of a collection held by
+	// the peer once a collection config update makes the peer ineligible for it. The
+	// purged data is tracked as ineligible missing data, so that it is fetched again
+	// if the peer becomes eligible for the collection again
+	PurgeIneligibleData bool
+}
+
+// CollectionPvtDataStats reports the private data of a collection held by the
+// private data store of a peer
+type CollectionPvtDataStats struct {
+	// Entries is the number of transactions whose private data of the collection is held
+	Entries uint64
+	// Bytes is the size of the private data of the collection held
+	Bytes uint64
 }
 
 // HistoryDBConfig is a structure used to configure the transaction history database.
 type HistoryDBConfig struct {
 	Enabled bool
-}
+	// DisabledByDefault disables the history of the namespaces which neither this
+	// configuration nor their chaincode definition enable it for.
+	DisabledByDefault bool
+	// EnabledNamespaces and DisabledNamespaces are the namespaces the history is
+	// enabled, respectively disabled, for, regardless of their chaincode definition.
+	EnabledNamespaces  []string
+	DisabledNamespaces []string
+}
+
+const (
+	// HistoryMetadataKey is the key of the metadata entry of a chaincode definition
+	// enabling or disabling the history database for the namespace of the chaincode.
+	HistoryMetadataKey = "history"
+	// HistoryEnabled is the value of the metadata entry enabling the history.
+	HistoryEnabled = "enabled"
+	// HistoryDisabled is the value of the metadata entry disabling the history.
+	HistoryDisabled = "disabled"
+)
 
 // SnapshotsConfig is a structure used to configure snapshot function
 type SnapshotsConfig struct {
@@ -129,6 +215,48 @@ type SnapshotsConfig struct {
 	RootDir string
 }
 
+// BackupsConfig is a structure used to configure the online backups of the ledgers
+type BackupsConfig struct {
+	// RootDir is the top-level directory for the backups.
+	RootDir string
+	// CouchDBReplicationTarget is the URL of the CouchDB instance the CouchDB
+	// state databases are replicated to during a backup. When it is empty, the
+	// backup of a ledger which uses CouchDB has no state database, and the state
+	// is rebuilt from the blocks when the backup is restored.
+	CouchDBReplicationTarget string
+}
+
+// CompactionConfig is a structure used to configure the scheduled compactions of the leveldb
+// based stores of the peer, that is the state database, the history database and the block index
+type CompactionConfig struct {
+	// Enabled enables the scheduled compactions. The compactions triggered through the
+	// operations endpoint are available regardless.
+	Enabled bool
+	// WindowStart and WindowEnd are the times of day, in the HH:MM format and the local time
+	// zone of the peer, which delimit the off-peak window the scheduled compactions start in.
+	// When WindowEnd is before WindowStart, the window spans midnight. When both are empty,
+	// the scheduled compactions may start at any time.
+	WindowStart string
+	WindowEnd   string
+	// Interval is the minimum time between two scheduled compactions of a store.
+	Interval time.Duration
+}
+
+// BlockStoreConfig is a structure used to configure the block store.
+type BlockStoreConfig struct {
+	// Compression enables the compression of the blocks appended to the
+	// block files. Block files written without compression remain readable.
+	Compression bool
+	// ShardPaths are the directories, typically on separate volumes, across
+	// which the block files of the channels created from now on are spread by
+	// hashing the channel ID. The block files are kept under RootFSPath when
+	// it is empty.
+	ShardPaths []string
+	// ShardChannels maps channel IDs to the directory holding their block
+	// files, overriding the placement by hash.
+	ShardChannels map[string]string
+}
+
 // PeerLedgerProvider provides handle to ledger instances
 type PeerLedgerProvider interface {
 	// Create creates a new ledger with the given genesis block.
@@ -151,6 +279,10 @@ type PeerLedger interface {
 	commonledger.Ledger
 	// GetTransactionByID retrieves a transaction by id
 	GetTransactionByID(txID string) (*peer.ProcessedTransaction, error)
+	// GetCommittedStateInfo returns the height and the hash of the last block committed
+	// to the state database. It doesn't wait for the commit in progress, if any, so it
+	// identifies the state seen by a TxSimulator or QueryExecutor held while calling it
+	GetCommittedStateInfo() (*common.BlockchainInfo, error)
 	// GetBlockByHash returns a block given it's hash
 	GetBlockByHash(blockHash []byte) (*common.Block, error)
 	// GetBlockByTxID returns a block which contains a transaction
@@ -189,6 +321,8 @@ type PeerLedger interface {
 	CommitPvtDataOfOldBlocks(reconciledPvtdata []*ReconciledPvtdata, unreconciled MissingPvtDataInfo) ([]*PvtdataHashMismatch, error)
 	// GetMissingPvtDataTracker return the MissingPvtDataTracker
 	GetMissingPvtDataTracker() (MissingPvtDataTracker, error)
+	// GetPvtDataAntiEntropy returns the PvtDataAntiEntropy
+	GetPvtDataAntiEntropy() (PvtDataAntiEntropy, error)
 	// DoesPvtDataInfoExist returns true when
 	// (1) the ledger has pvtdata associated with the given block number (or)
 	// (2) a few or all pvtdata associated with the given block number is missing but the
@@ -266,11 +400,35 @@ type QueryExecutor interface {
 	Done()
 }
 
+// RangeScanCursorPrefix is the prefix of the bookmarks that are resumable range scan cursors. A paginated
+// range query started with this prefix as the bookmark opens a cursor over the state as of the current height,
+// and the bookmark returned with each page resumes the scan after the page, in a later transaction.
+// Since simple keys cannot start with 0x00 and composite key attributes cannot hold U+10FFFF, such bookmarks
+// never collide with the regular ones, which are keys
+const RangeScanCursorPrefix = "\x00\U0010FFFFcursor:"
+
+// RangeScanCursorExecutor is implemented by the QueryExecutors and TxSimulators that support the resumable range scan cursors
+type RangeScanCursorExecutor interface {
+	// GetStateRangeScanIteratorWithCursor returns an iterator over a page of the keys between startKey and endKey. The
+	// cursor is either RangeScanCursorPrefix, to open a new cursor, or a bookmark returned by an earlier iterator, in which
+	// case startKey is ignored. The bookmark holds the block the cursor was opened at and the key the scan resumes from,
+	// so any peer can resume the scan: the page is read from the view of the state pinned at that block when the peer
+	// holds one, and from the current state otherwise. The iterator returns the bookmark resuming the scan after the
+	// page, or an empty bookmark at the end of the range. The range read is added to the read set of the transaction,
+	// so that the page is validated against the committed state like any range query
+	GetStateRangeScanIteratorWithCursor(namespace, startKey, endKey string, pageSize int32, cursor string) (QueryResultsIterator, error)
+}
+
 // HistoryQueryExecutor executes the history queries
 type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in fabric-protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetUpdatedKeysBetweenBlocks retrieves the keys of the namespace written by the valid transactions
+	// of the blocks with a number in the range [startBlock, endBlock), in lexical order.
+	// An error is returned if some blocks of the range were committed before the keys were indexed by the
+	// history database, which then needs to be rebuilt.
+	GetUpdatedKeysBetweenBlocks(namespace string, startBlock, endBlock uint64) ([]string, error)
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
@@ -287,6 +445,9 @@ type TxSimulator interface {
 	SetStateMetadata(namespace, key string, metadata map[string][]byte) error
 	// DeleteStateMetadata deletes the metadata (if any) associated with an existing key-tuple <namespace, key>
 	DeleteStateMetadata(namespace, key string) error
+	// IncrementState adds delta to the counter key-tuple <namespace, key> at commit time.
+	// The increments of a counter key by concurrent transactions do not conflict
+	IncrementState(namespace, key string, delta int64) error
 	// ExecuteUpdate for supporting rich data model (see comments on QueryExecutor above)
 	ExecuteUpdate(query string) error
 	// SetPrivateData sets the given value to a key in the private data state represented by the tuple <namespace, collection, key>
@@ -505,6 +666,29 @@ type KVStateUpdates struct {
 	CollHashUpdates map[string][]*kvrwset.KVWriteHash
 }
 
+// CommitListener allows an in-process component, such as an off-chain indexer compiled into a
+// custom peer build, to be notified of the blocks committed to the ledgers of the peer.
+// Function `HandleCommittedBlock` is invoked for every block, including the genesis block, after
+// the block, its private data, and the resulting state updates are committed. The listeners are
+// notified synchronously, one block at a time in the order in which the blocks are committed and,
+// for every block, in the order in which the listeners are registered. Hence, the commit of the
+// next block waits for the listeners and a listener is expected to return promptly. An error
+// returned, or a panic raised, by a listener is logged and affects neither the ledger nor the
+// other listeners. The CommitEvent is shared among the listeners and must not be modified.
+type CommitListener interface {
+	Name() string
+	HandleCommittedBlock(event *CommitEvent) error
+}
+
+// CommitEvent encapsulates a block committed to a ledger along with its private data.
+// PvtData holds only the private data of the collections the peer is eligible for
+// and that was available at the time of commit.
+type CommitEvent struct {
+	LedgerID string
+	Block    *common.Block
+	PvtData  TxPvtDataMap
+}
+
 // ConfigHistoryRetriever allow retrieving history of collection configs
 type ConfigHistoryRetriever interface {
 	MostRecentCollectionConfigBelow(blockNum uint64, chaincodeName string) (*CollectionConfigInfo, error)
@@ -515,6 +699,31 @@ type MissingPvtDataTracker interface {
 	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (MissingPvtDataInfo, error)
 }
 
+// PvtDataAntiEntropy allows comparing the private data held by the peer with the private data held
+// by other peers, to detect and recover the private data that is silently lost, i.e., that is neither
+// held by the peer nor tracked as missing
+type PvtDataAntiEntropy interface {
+	// PvtDataDigests returns the digests of the private data of each collection held by the peer
+	// for the blocks from startBlk to endBlk
+	PvtDataDigests(startBlk, endBlk uint64) ([]*CollectionPvtDataDigest, error)
+	// PvtDataEntries returns the private data of a collection held by the peer for the blocks from
+	// startBlk to endBlk, as a map of block number to the numbers of the transactions
+	PvtDataEntries(ns, coll string, startBlk, endBlk uint64) (map[uint64][]uint64, error)
+	// ScheduleReconciliation tracks as missing the given private data, which other peers hold, so
+	// that the reconciler fetches it. The private data that was not written by a valid transaction
+	// of a committed block, that the peer holds, that is already tracked as missing or that is
+	// expired is skipped. It returns the number of entries tracked as missing
+	ScheduleReconciliation(lost MissingPvtDataInfo) (int, error)
+}
+
+// CollectionPvtDataDigest is the digest of the private data of a collection held by a peer for a
+// range of blocks
+type CollectionPvtDataDigest struct {
+	Namespace, Collection string
+	// Digest is the SM3 digest of the private data of the collection
+	Digest []byte
+}
+
 // MissingPvtDataInfo is a map of block number to MissingBlockPvtdataInfo
 type MissingPvtDataInfo map[uint64]MissingBlockPvtdataInfo
 
@@ -626,6 +835,8 @@ type DeployedChaincodeInfo struct {
 	Version                     string
 	ExplicitCollectionConfigPkg *peer.CollectionConfigPackage
 	IsLegacy                    bool
+	// Metadata is the operator defined metadata of the chaincode definition
+	Metadata map[string][]byte
 }
 
 // ChaincodeLifecycleInfo captures the update info of a chaincode
@@ -701,6 +912,13 @@ type CustomTxProcessor interface {
 	GenerateSimulationResults(txEnvelop *common.Envelope, simulator TxSimulator, initializingLedger bool) error
 }
 
+// CounterCapabilityProvider tells whether the channel config committed to the state enables the
+// increments of counter keys. The ledger applies the counter deltas of the transactions of a block
+// only if the channel config committed before the block enables them
+type CounterCapabilityProvider interface {
+	CommutativeCountersEnabled(qe SimpleQueryExecutor) (bool, error)
+}
+
 // InvalidTxError is expected to be thrown by a custom transaction processor
 // if it wants the ledger to record a particular transaction as invalid
 type InvalidTxError struct {
@@ -725,4 +943,6 @@ type HashProvider interface {
 //go:generate counterfeiter -o mock/health_check_registry.go -fake-name HealthCheckRegistry . HealthCheckRegistry
 //go:generate counterfeiter -o mock/cc_event_listener.go -fake-name ChaincodeLifecycleEventListener . ChaincodeLifecycleEventListener
 //go:generate counterfeiter -o mock/custom_tx_processor.go -fake-name CustomTxProcessor . CustomTxProcessor
+//go:generate counterfeiter -o mock/counter_capability_provider.go -fake-name CounterCapabilityProvider . CounterCapabilityProvider
+//go:generate counterfeiter -o mock/commit_listener.go -fake-name CommitListener . CommitListener
 //go:generate counterfeiter -o mock/cc_event_provider.go -fake-name ChaincodeLifecycleEventProvider . ChaincodeLifecycleEventProvider
@@ -44,6 +44,7 @@ type MetadataProvider interface {
 // Initializer encapsulates all the external dependencies for the ledger module
 type Initializer struct {
 	CustomTxProcessors              map[common.HeaderType]ledger.CustomTxProcessor
+	CounterCapabilityProvider       ledger.CounterCapabilityProvider
 	StateListeners                  []ledger.StateListener
 	DeployedChaincodeInfoProvider   ledger.DeployedChaincodeInfoProvider
 	MembershipInfoProvider          ledger.MembershipInfoProvider
@@ -53,6 +54,7 @@ type Initializer struct {
 	Config                          *ledger.Config
 	HashProvider                    ledger.HashProvider
 	EbMetadataProvider              MetadataProvider
+	CommitListeners                 []ledger.CommitListener
 }
 
 // NewLedgerMgr creates a new LedgerMgr
@@ -72,7 +74,9 @@ func NewLedgerMgr(initializer *Initializer) *LedgerMgr {
 			HealthCheckRegistry:             initializer.HealthCheckRegistry,
 			Config:                          initializer.Config,
 			CustomTxProcessors:              initializer.CustomTxProcessors,
+			CounterCapabilityProvider:       initializer.CounterCapabilityProvider,
 			HashProvider:                    initializer.HashProvider,
+			CommitListeners:                 initializer.CommitListeners,
 		},
 	)
 	if err != nil {
@@ -154,6 +158,47 @@ func (m *LedgerMgr) Close() {
 	logger.Infof("ledger mgmt closed")
 }
 
+// BackupLedger takes an online backup of the opened ledger with the given id
+func (m *LedgerMgr) BackupLedger(ledgerID string) (*kvledger.BackupManifest, error) {
+	l, err := m.getOpenedLedger(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	backuper, ok := l.(interface {
+		Backup() (*kvledger.BackupManifest, error)
+	})
+	if !ok {
+		return nil, errors.Errorf("ledger [%s] does not support online backups", ledgerID)
+	}
+	return backuper.Backup()
+}
+
+// CollectionPvtDataStats returns the amount of private data of a collection held by the opened ledger with the given id
+func (m *LedgerMgr) CollectionPvtDataStats(ledgerID, ns, coll string) (*ledger.CollectionPvtDataStats, error) {
+	l, err := m.getOpenedLedger(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	statsProvider, ok := l.(interface {
+		CollectionPvtDataStats(ns, coll string) (*ledger.CollectionPvtDataStats, error)
+	})
+	if !ok {
+		return nil, errors.Errorf("ledger [%s] does not report private data statistics", ledgerID)
+	}
+	return statsProvider.CollectionPvtDataStats(ns, coll)
+}
+
+// CompactStores compacts the given leveldb based stores of the ledgers, or all of them when none is given
+func (m *LedgerMgr) CompactStores(stores ...string) ([]*kvledger.CompactionResult, error) {
+	compactor, ok := m.ledgerProvider.(interface {
+		CompactStores(stores ...string) ([]*kvledger.CompactionResult, error)
+	})
+	if !ok {
+		return nil, errors.New("the ledger provider does not support the compaction of the stores")
+	}
+	return compactor.CompactStores(stores...)
+}
+
 func (m *LedgerMgr) getOpenedLedger(ledgerID string) (ledger.PeerLedger, error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
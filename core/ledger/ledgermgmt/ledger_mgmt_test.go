@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
 	"github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -78,6 +79,46 @@ func TestLedgerMgmt(t *testing.T) {
 	ledgerMgr.Close()
 }
 
+func TestBackupLedger(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "ledgermgmt")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	initializer, err := constructDefaultInitializer(testDir)
+	require.NoError(t, err)
+	ledgerMgr := NewLedgerMgr(initializer)
+	defer ledgerMgr.Close()
+
+	gb, _ := test.MakeGenesisBlock("ledger1")
+	l, err := ledgerMgr.CreateLedger("ledger1", gb)
+	require.NoError(t, err)
+
+	manifest, err := ledgerMgr.BackupLedger("ledger1")
+	require.NoError(t, err)
+	require.Equal(t, "ledger1", manifest.LedgerID)
+	require.Equal(t, uint64(1), manifest.Height)
+	require.DirExists(t, manifest.Dir)
+
+	l.Close()
+	_, err = ledgerMgr.BackupLedger("ledger1")
+	require.EqualError(t, err, "Ledger not opened [ledger1]")
+}
+
+func TestCompactStores(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "ledgermgmt")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	initializer, err := constructDefaultInitializer(testDir)
+	require.NoError(t, err)
+	ledgerMgr := NewLedgerMgr(initializer)
+	defer ledgerMgr.Close()
+
+	results, err := ledgerMgr.CompactStores(kvledger.BlockIndexStore, kvledger.StateDBStore)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, kvledger.BlockIndexStore, results[0].Store)
+	require.Equal(t, kvledger.StateDBStore, results[1].Store)
+}
+
 func TestChaincodeInfoProvider(t *testing.T) {
 	testDir, err := ioutil.TempDir("", "ledgermgmt")
 	if err != nil {
@@ -143,6 +184,9 @@ func constructDefaultInitializer(testDir string) (*Initializer, error) {
 			SnapshotsConfig: &ledger.SnapshotsConfig{
 				RootDir: filepath.Join(testDir, "snapshots"),
 			},
+			BackupsConfig: &ledger.BackupsConfig{
+				RootDir: filepath.Join(testDir, "backups"),
+			},
 		},
 
 		MetricsProvider:               &disabled.Provider{},
@@ -295,6 +295,19 @@ type TxSimulator struct {
 		result1 *ledger.TxSimulationResults
 		result2 error
 	}
+	IncrementStateStub        func(string, string, int64) error
+	incrementStateMutex       sync.RWMutex
+	incrementStateArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int64
+	}
+	incrementStateReturns struct {
+		result1 error
+	}
+	incrementStateReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPrivateDataStub        func(string, string, string, []byte) error
 	setPrivateDataMutex       sync.RWMutex
 	setPrivateDataArgsForCall []struct {
@@ -1686,6 +1699,68 @@ func (fake *TxSimulator) GetTxSimulationResultsReturnsOnCall(i int, result1 *led
 	}{result1, result2}
 }
 
+func (fake *TxSimulator) IncrementState(arg1 string, arg2 string, arg3 int64) error {
+	fake.incrementStateMutex.Lock()
+	ret, specificReturn := fake.incrementStateReturnsOnCall[len(fake.incrementStateArgsForCall)]
+	fake.incrementStateArgsForCall = append(fake.incrementStateArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int64
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("IncrementState", []interface{}{arg1, arg2, arg3})
+	fake.incrementStateMutex.Unlock()
+	if fake.IncrementStateStub != nil {
+		return fake.IncrementStateStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.incrementStateReturns
+	return fakeReturns.result1
+}
+
+func (fake *TxSimulator) IncrementStateCallCount() int {
+	fake.incrementStateMutex.RLock()
+	defer fake.incrementStateMutex.RUnlock()
+	return len(fake.incrementStateArgsForCall)
+}
+
+func (fake *TxSimulator) IncrementStateCalls(stub func(string, string, int64) error) {
+	fake.incrementStateMutex.Lock()
+	defer fake.incrementStateMutex.Unlock()
+	fake.IncrementStateStub = stub
+}
+
+func (fake *TxSimulator) IncrementStateArgsForCall(i int) (string, string, int64) {
+	fake.incrementStateMutex.RLock()
+	defer fake.incrementStateMutex.RUnlock()
+	argsForCall := fake.incrementStateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *TxSimulator) IncrementStateReturns(result1 error) {
+	fake.incrementStateMutex.Lock()
+	defer fake.incrementStateMutex.Unlock()
+	fake.IncrementStateStub = nil
+	fake.incrementStateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *TxSimulator) IncrementStateReturnsOnCall(i int, result1 error) {
+	fake.incrementStateMutex.Lock()
+	defer fake.incrementStateMutex.Unlock()
+	fake.IncrementStateStub = nil
+	if fake.incrementStateReturnsOnCall == nil {
+		fake.incrementStateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.incrementStateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *TxSimulator) SetPrivateData(arg1 string, arg2 string, arg3 string, arg4 []byte) error {
 	var arg4Copy []byte
 	if arg4 != nil {
@@ -2114,6 +2189,8 @@ func (fake *TxSimulator) Invocations() map[string][][]interface{} {
 	defer fake.getStateRangeScanIteratorWithPaginationMutex.RUnlock()
 	fake.getTxSimulationResultsMutex.RLock()
 	defer fake.getTxSimulationResultsMutex.RUnlock()
+	fake.incrementStateMutex.RLock()
+	defer fake.incrementStateMutex.RUnlock()
 	fake.setPrivateDataMutex.RLock()
 	defer fake.setPrivateDataMutex.RUnlock()
 	fake.setPrivateDataMetadataMutex.RLock()
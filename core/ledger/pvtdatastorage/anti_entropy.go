@@ -0,0 +1,232 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"hash"
+	"sort"
+	"sync/atomic"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/willf/bitset"
+)
+
+// PvtDataDigests returns the digest of the private data of each collection held by the store for the
+// blocks from 'startBlk' to 'endBlk'. The digest of a collection is the SM3 digest of the block and
+// transaction numbers of its entries, each followed by the SM3 digest of the entry, in order. The entries
+// that are expired but not purged yet are left out, so that the digests do not depend on the progress
+// of the purge
+func (s *Store) PvtDataDigests(startBlk, endBlk uint64) ([]*ledger.CollectionPvtDataDigest, error) {
+	hashes := map[nsColl]hash.Hash{}
+	err := s.iteratePvtData(startBlk, endBlk, func(key *dataKey, value []byte) {
+		nsColl := nsColl{ns: key.ns, coll: key.coll}
+		h, ok := hashes[nsColl]
+		if !ok {
+			h = sm3.New()
+			hashes[nsColl] = h
+		}
+		h.Write(version.NewHeight(key.blkNum, key.txNum).ToBytes())
+		h.Write(sm3.SumSM3(value))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var digests []*ledger.CollectionPvtDataDigest
+	for nsColl, h := range hashes {
+		digests = append(digests, &ledger.CollectionPvtDataDigest{
+			Namespace:  nsColl.ns,
+			Collection: nsColl.coll,
+			Digest:     h.Sum(nil),
+		})
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		if digests[i].Namespace != digests[j].Namespace {
+			return digests[i].Namespace < digests[j].Namespace
+		}
+		return digests[i].Collection < digests[j].Collection
+	})
+	return digests, nil
+}
+
+// PvtDataEntries returns the transactions whose private data of the collection is held by the store for
+// the blocks from 'startBlk' to 'endBlk', leaving out the expired entries as PvtDataDigests does
+func (s *Store) PvtDataEntries(ns, coll string, startBlk, endBlk uint64) (map[uint64][]uint64, error) {
+	entries := map[uint64][]uint64{}
+	err := s.iteratePvtData(startBlk, endBlk, func(key *dataKey, _ []byte) {
+		if key.ns == ns && key.coll == coll {
+			entries[key.blkNum] = append(entries[key.blkNum], key.txNum)
+		}
+	})
+	return entries, err
+}
+
+func (s *Store) iteratePvtData(startBlk, endBlk uint64, f func(key *dataKey, value []byte)) error {
+	if startBlk > endBlk {
+		return nil
+	}
+	lastCommittedBlock := atomic.LoadUint64(&s.lastCommittedBlock)
+	startKey, endKey := getDataKeysForRangeScan(startBlk, endBlk)
+	itr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+
+	for itr.Next() {
+		keyBytes := itr.Key()
+		v11Fmt, err := v11Format(keyBytes)
+		if err != nil {
+			return err
+		}
+		if v11Fmt {
+			// the data committed by a v1.1 peer isn't split by collection
+			continue
+		}
+		key, err := decodeDatakey(keyBytes)
+		if err != nil {
+			return err
+		}
+		expired, err := isExpired(key.nsCollBlk, s.btlPolicy, lastCommittedBlock)
+		if err != nil {
+			return err
+		}
+		if expired {
+			continue
+		}
+		f(key, itr.Value())
+	}
+	return nil
+}
+
+// ScheduleReconciliation tracks the given private data, which other peers hold, as eligible prioritized
+// missing data so that the reconciler fetches it. The private data that the store holds, that is already
+// tracked as missing (eligible or not) or that is expired is skipped, as well as the private data of the
+// blocks that are not committed yet. It returns the number of entries tracked as missing
+func (s *Store) ScheduleReconciliation(lost ledger.MissingPvtDataInfo) (int, error) {
+	s.purgerLock.Lock()
+	defer s.purgerLock.Unlock()
+
+	if s.isEmpty {
+		return 0, nil
+	}
+	lastCommittedBlock := atomic.LoadUint64(&s.lastCommittedBlock)
+
+	p := &oldBlockDataProcessor{
+		Store: s,
+		entries: &entriesForPvtDataOfOldBlocks{
+			dataEntries:                     make(map[dataKey]*rwset.CollectionPvtReadWriteSet),
+			expiryEntries:                   make(map[expiryKey]*ExpiryData),
+			prioritizedMissingDataEntries:   make(map[nsCollBlk]*bitset.BitSet),
+			deprioritizedMissingDataEntries: make(map[nsCollBlk]*bitset.BitSet),
+		},
+	}
+
+	scheduled := 0
+	for blkNum, blkLost := range lost {
+		if blkNum > lastCommittedBlock {
+			continue
+		}
+		for txNum, txLost := range blkLost {
+			for _, nsColl := range txLost {
+				key := nsCollBlk{ns: nsColl.Namespace, coll: nsColl.Collection, blkNum: blkNum}
+				tracked, err := p.isPresentOrTrackedAsMissing(key, txNum)
+				if err != nil {
+					return 0, err
+				}
+				if tracked {
+					continue
+				}
+
+				expiringBlk, err := s.btlPolicy.GetExpiringBlock(key.ns, key.coll, key.blkNum)
+				if err != nil {
+					return 0, err
+				}
+				if lastCommittedBlock >= expiringBlk {
+					continue
+				}
+				if !neverExpires(expiringBlk) {
+					expKey := expiryKey{expiringBlk: expiringBlk, committingBlk: blkNum}
+					expData, err := p.getExpiryDataFromEntriesOrStore(expKey)
+					if err != nil {
+						return 0, err
+					}
+					if expData == nil {
+						// the purge scheduler already removed the entries of the block
+						continue
+					}
+					expData.addMissingData(key.ns, key.coll)
+					p.entries.expiryEntries[expKey] = expData
+				}
+
+				prioMissingData, err := p.getPrioMissingDataFromEntriesOrStore(key)
+				if err != nil {
+					return 0, err
+				}
+				if prioMissingData == nil {
+					prioMissingData = &bitset.BitSet{}
+				}
+				p.entries.prioritizedMissingDataEntries[key] = prioMissingData.Set(uint(txNum))
+				scheduled++
+			}
+		}
+	}
+	if scheduled == 0 {
+		return 0, nil
+	}
+
+	batch, err := p.constructDBUpdateBatch()
+	if err != nil {
+		return 0, err
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return 0, err
+	}
+	if err := s.updateHydrationStatus(); err != nil {
+		logger.Warningf("[%s] Failed updating the hydration status of collections: %s", s.ledgerid, err)
+	}
+	return scheduled, nil
+}
+
+func (p *oldBlockDataProcessor) isPresentOrTrackedAsMissing(key nsCollBlk, txNum uint64) (bool, error) {
+	data, err := p.db.Get(encodeDataKey(&dataKey{nsCollBlk: key, txNum: txNum}))
+	if err != nil {
+		return false, err
+	}
+	if data != nil {
+		return true, nil
+	}
+
+	prioMissingData, err := p.getPrioMissingDataFromEntriesOrStore(key)
+	if err != nil {
+		return false, err
+	}
+	deprioMissingData, err := p.getDeprioMissingDataFromEntriesOrStore(key)
+	if err != nil {
+		return false, err
+	}
+	var inelgMissingData *bitset.BitSet
+	encInelgMissingData, err := p.db.Get(encodeInelgMissingDataKey(&missingDataKey{nsCollBlk: key}))
+	if err != nil {
+		return false, err
+	}
+	if encInelgMissingData != nil {
+		if inelgMissingData, err = decodeMissingDataValue(encInelgMissingData); err != nil {
+			return false, err
+		}
+	}
+
+	for _, missingData := range []*bitset.BitSet{prioMissingData, deprioMissingData, inelgMissingData} {
+		if missingData != nil && missingData.Test(uint(txNum)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
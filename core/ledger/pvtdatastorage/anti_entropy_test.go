@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPvtDataDigests(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 1,
+		},
+	)
+	commitBlocks := func(store *Store) {
+		require.NoError(t, store.Commit(0, nil, nil))
+		require.NoError(t, store.Commit(1, []*ledger.TxPvtData{
+			produceSamplePvtdata(t, 1, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+			produceSamplePvtdata(t, 3, []string{"ns-1:coll-1"}),
+		}, nil))
+		require.NoError(t, store.Commit(2, []*ledger.TxPvtData{
+			produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+		}, nil))
+		require.NoError(t, store.Commit(3, nil, nil))
+	}
+
+	env1 := NewTestStoreEnv(t, "TestPvtDataDigests", btlPolicy, pvtDataConf())
+	defer env1.Cleanup()
+	store1 := env1.TestStore
+	commitBlocks(store1)
+	env2 := NewTestStoreEnv(t, "TestPvtDataDigests", btlPolicy, pvtDataConf())
+	defer env2.Cleanup()
+	store2 := env2.TestStore
+	commitBlocks(store2)
+
+	digests1, err := store1.PvtDataDigests(1, 2)
+	require.NoError(t, err)
+	require.Len(t, digests1, 2)
+	require.Equal(t, "coll-1", digests1[0].Collection)
+	require.Equal(t, "coll-2", digests1[1].Collection)
+	digests2, err := store2.PvtDataDigests(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, digests1, digests2)
+
+	// the data of {ns-1:coll-2} at block 1 has expired, as block 3 is committed
+	entries, err := store1.PvtDataEntries("ns-1", "coll-2", 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, map[uint64][]uint64{2: {2}}, entries)
+
+	// the data of {ns-1:coll-1} at block 1, tx 3 is silently lost by the second store
+	batch := store2.db.NewUpdateBatch()
+	batch.Delete(encodeDataKey(&dataKey{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, 3}))
+	require.NoError(t, store2.db.WriteBatch(batch, true))
+	digests2, err = store2.PvtDataDigests(1, 2)
+	require.NoError(t, err)
+	require.NotEqual(t, digests1[0].Digest, digests2[0].Digest)
+	require.Equal(t, digests1[1], digests2[1])
+
+	entries, err = store2.PvtDataEntries("ns-1", "coll-1", 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, map[uint64][]uint64{1: {1}, 2: {2}}, entries)
+
+	digests, err := store1.PvtDataDigests(3, 10)
+	require.NoError(t, err)
+	require.Empty(t, digests)
+}
+
+func TestScheduleReconciliation(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+			{"ns-1", "coll-3"}: 1,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestScheduleReconciliation", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	blk1MissingData := make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(2, "ns-1", "coll-1", true)
+	blk1MissingData.Add(2, "ns-1", "coll-2", false)
+	require.NoError(t, store.Commit(1, []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 1, []string{"ns-1:coll-1"}),
+	}, blk1MissingData))
+	require.NoError(t, store.Commit(2, []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 1, []string{"ns-1:coll-3"}),
+	}, nil))
+
+	count, err := store.ScheduleReconciliation(nil)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	lost := make(ledger.MissingPvtDataInfo)
+	lost.Add(1, 1, "ns-1", "coll-1") // present
+	lost.Add(1, 2, "ns-1", "coll-1") // tracked as eligible missing data
+	lost.Add(1, 2, "ns-1", "coll-2") // tracked as ineligible missing data
+	lost.Add(1, 3, "ns-1", "coll-1") // lost
+	lost.Add(1, 3, "ns-1", "coll-3") // expired
+	lost.Add(2, 2, "ns-1", "coll-3") // lost
+	lost.Add(3, 1, "ns-1", "coll-1") // not committed yet
+	count, err = store.ScheduleReconciliation(lost)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	expectedMissingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	expectedMissingPvtDataInfo.Add(1, 2, "ns-1", "coll-1")
+	expectedMissingPvtDataInfo.Add(1, 3, "ns-1", "coll-1")
+	expectedMissingPvtDataInfo.Add(2, 2, "ns-1", "coll-3")
+	missingPvtDataInfo, err := store.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	require.NoError(t, err)
+	require.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+
+	// scheduling the same data again is a no-op
+	count, err = store.ScheduleReconciliation(lost)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	// the missing data of an expiring collection is purged with the expired data
+	require.NoError(t, store.Commit(3, nil, nil))
+	require.NoError(t, store.Commit(4, nil, nil))
+	testWaitForPurgerRoutineToFinish(store)
+	require.False(t, testElgPrioMissingDataKeyExists(t, store, &missingDataKey{nsCollBlk{ns: "ns-1", coll: "coll-3", blkNum: 2}}))
+	require.True(t, testElgPrioMissingDataKeyExists(t, store, &missingDataKey{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}}))
+}
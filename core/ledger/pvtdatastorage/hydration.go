@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"sort"
+)
+
+// HydrationStatus reports the progress of the lazy hydration of a collection, i.e., of the
+// background fetching of the private data committed before the peer became eligible for it
+type HydrationStatus struct {
+	Namespace  string
+	Collection string
+	// Total is the number of transactions whose private data of the collection was
+	// missing when the peer became eligible for the collection
+	Total uint64
+	// Hydrated is the number of these transactions whose private data is no longer missing,
+	// either because it has been fetched or because it has expired
+	Hydrated uint64
+}
+
+// Percentage returns the hydrated share of the historical private data of the collection
+func (h *HydrationStatus) Percentage() float64 {
+	if h.Total == 0 {
+		return 100
+	}
+	return float64(h.Hydrated) * 100 / float64(h.Total)
+}
+
+// hydrationInfo is persisted for each collection being lazily hydrated. blkNum is the
+// block in which the peer became eligible for the collection, and total is the number
+// of transactions up to that block whose private data of the collection is missing
+type hydrationInfo struct {
+	blkNum uint64
+	total  uint64
+}
+
+type nsColl struct {
+	ns, coll string
+}
+
+// GetHydrationStatus returns the hydration progress of the collections that are being
+// lazily hydrated, as computed after the last commit of the private data of old blocks.
+// Once completely hydrated, a collection is no longer reported
+func (s *Store) GetHydrationStatus() []*HydrationStatus {
+	s.hydrationLock.RLock()
+	defer s.hydrationLock.RUnlock()
+	status := make([]*HydrationStatus, len(s.hydrationStatus))
+	for i, h := range s.hydrationStatus {
+		hCopy := *h
+		status[i] = &hCopy
+	}
+	return status
+}
+
+func (s *Store) getHydrationInfo(ns, coll string) (*hydrationInfo, error) {
+	v, err := s.db.Get(encodeHydrationKey(ns, coll))
+	if err != nil || v == nil {
+		return &hydrationInfo{}, err
+	}
+	return decodeHydrationVal(v)
+}
+
+// updateHydrationStatus recomputes the hydration progress of the collections that are being
+// lazily hydrated, from the eligible missing data entries that remain, and stops tracking
+// the collections that are completely hydrated
+func (s *Store) updateHydrationStatus() error {
+	startKey, endKey := createRangeScanKeysForHydration()
+	itr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	hydrations := make(map[nsColl]*hydrationInfo)
+	maxBlkNum := uint64(0)
+	for itr.Next() {
+		ns, coll := decodeHydrationKey(itr.Key())
+		h, err := decodeHydrationVal(itr.Value())
+		if err != nil {
+			itr.Release()
+			return err
+		}
+		hydrations[nsColl{ns, coll}] = h
+		if h.blkNum > maxBlkNum {
+			maxBlkNum = h.blkNum
+		}
+	}
+	itr.Release()
+
+	remaining := make(map[nsColl]uint64)
+	if len(hydrations) > 0 {
+		for _, group := range [][]byte{elgPrioritizedMissingDataGroup, elgDeprioritizedMissingDataGroup} {
+			if err := s.countMissingTxs(group, maxBlkNum, hydrations, remaining); err != nil {
+				return err
+			}
+		}
+	}
+
+	var status []*HydrationStatus
+	batch := s.db.NewUpdateBatch()
+	for c, h := range hydrations {
+		hydrated := uint64(0)
+		if remaining[c] < h.total {
+			hydrated = h.total - remaining[c]
+		}
+		if hydrated == h.total {
+			logger.Infof("[%s] Hydration of the private data of [ns=%s, coll=%s] completed, [%d] transactions hydrated",
+				s.ledgerid, c.ns, c.coll, h.total)
+			batch.Delete(encodeHydrationKey(c.ns, c.coll))
+			continue
+		}
+		status = append(status, &HydrationStatus{
+			Namespace:  c.ns,
+			Collection: c.coll,
+			Total:      h.total,
+			Hydrated:   hydrated,
+		})
+	}
+	if batch.Len() > 0 {
+		if err := s.db.WriteBatch(batch, true); err != nil {
+			return err
+		}
+	}
+
+	sort.Slice(status, func(i, j int) bool {
+		if status[i].Namespace != status[j].Namespace {
+			return status[i].Namespace < status[j].Namespace
+		}
+		return status[i].Collection < status[j].Collection
+	})
+	for _, h := range status {
+		logger.Infof("[%s] Hydration of the private data of [ns=%s, coll=%s] at %.1f%%, [%d] out of [%d] transactions hydrated",
+			s.ledgerid, h.Namespace, h.Collection, h.Percentage(), h.Hydrated, h.Total)
+	}
+
+	s.hydrationLock.Lock()
+	s.hydrationStatus = status
+	s.hydrationLock.Unlock()
+	return nil
+}
+
+// countMissingTxs adds to remaining the number of transactions of the given eligible missing
+// data group whose private data of a collection being hydrated is missing, up to the block
+// in which the peer became eligible for the collection
+func (s *Store) countMissingTxs(group []byte, maxBlkNum uint64, hydrations map[nsColl]*hydrationInfo, remaining map[nsColl]uint64) error {
+	startKey, endKey := createRangeScanKeysForElgMissingData(maxBlkNum, group)
+	itr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	defer itr.Release()
+
+	for itr.Next() {
+		key := decodeElgMissingDataKey(itr.Key())
+		c := nsColl{key.ns, key.coll}
+		h, ok := hydrations[c]
+		if !ok || key.blkNum > h.blkNum {
+			continue
+		}
+		bitmap, err := decodeMissingDataValue(itr.Value())
+		if err != nil {
+			return err
+		}
+		remaining[c] += uint64(bitmap.Count())
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyHydration(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	conf := pvtDataConf()
+	conf.LazyHydration = true
+	env := NewTestStoreEnv(t, "TestLazyHydration", btlPolicy, conf)
+	defer env.Cleanup()
+	store := env.TestStore
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	blk1MissingData := make(ledger.TxMissingPvtDataMap)
+	blk1MissingData.Add(1, "ns-1", "coll-1", true)
+	blk1MissingData.Add(4, "ns-1", "coll-2", false)
+	require.NoError(t, store.Commit(1, nil, blk1MissingData))
+	blk2MissingData := make(ledger.TxMissingPvtDataMap)
+	blk2MissingData.Add(1, "ns-1", "coll-2", false)
+	blk2MissingData.Add(2, "ns-1", "coll-2", false)
+	require.NoError(t, store.Commit(2, nil, blk2MissingData))
+	require.NoError(t, store.Commit(3, nil, nil))
+	require.Empty(t, store.GetHydrationStatus())
+
+	// the peer becomes eligible for {ns-1:coll-2} in block 3
+	require.NoError(t, store.ProcessCollsEligibilityEnabled(3, map[string][]string{"ns-1": {"coll-2"}}))
+	testutilWaitForCollElgProcToFinish(store)
+
+	// the historical missing data is deprioritized and does not delay the reconciliation of recent blocks
+	for _, key := range []*missingDataKey{
+		{nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}},
+		{nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 2}},
+	} {
+		require.True(t, testElgDeprioMissingDataKeyExists(t, store, key))
+		require.False(t, testElgPrioMissingDataKeyExists(t, store, key))
+		require.False(t, testInelgMissingDataKeyExists(t, store, key))
+	}
+	expectedMissingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	expectedMissingPvtDataInfo.Add(1, 1, "ns-1", "coll-1")
+	missingPvtDataInfo, err := store.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	require.NoError(t, err)
+	require.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+
+	require.Equal(t,
+		[]*HydrationStatus{{Namespace: "ns-1", Collection: "coll-2", Total: 3, Hydrated: 0}},
+		store.GetHydrationStatus(),
+	)
+
+	// the data missing in blocks committed after the peer became eligible is not accounted
+	blk4MissingData := make(ledger.TxMissingPvtDataMap)
+	blk4MissingData.Add(1, "ns-1", "coll-2", true)
+	require.NoError(t, store.Commit(4, nil, blk4MissingData))
+
+	// hydrate block 1
+	require.NoError(t, store.CommitPvtDataOfOldBlocks(
+		map[uint64][]*ledger.TxPvtData{
+			1: {produceSamplePvtdata(t, 4, []string{"ns-1:coll-2"})},
+		},
+		nil,
+	))
+	status := store.GetHydrationStatus()
+	require.Equal(t,
+		[]*HydrationStatus{{Namespace: "ns-1", Collection: "coll-2", Total: 3, Hydrated: 1}},
+		status,
+	)
+	require.InDelta(t, 33.3, status[0].Percentage(), 0.1)
+
+	// the hydration progress survives a restart
+	env.CloseAndReopen()
+	store = env.TestStore
+	require.Equal(t, status, store.GetHydrationStatus())
+
+	// hydrate block 2, which completes the hydration of the collection
+	require.NoError(t, store.CommitPvtDataOfOldBlocks(
+		map[uint64][]*ledger.TxPvtData{
+			2: {
+				produceSamplePvtdata(t, 1, []string{"ns-1:coll-2"}),
+				produceSamplePvtdata(t, 2, []string{"ns-1:coll-2"}),
+			},
+		},
+		nil,
+	))
+	require.Empty(t, store.GetHydrationStatus())
+	v, err := store.db.Get(encodeHydrationKey("ns-1", "coll-2"))
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
+
+func TestHydrationValEncoding(t *testing.T) {
+	h := &hydrationInfo{blkNum: 300, total: 70000}
+	decoded, err := decodeHydrationVal(encodeHydrationVal(h))
+	require.NoError(t, err)
+	require.Equal(t, h, decoded)
+
+	_, err = decodeHydrationVal(nil)
+	require.EqualError(t, err, "invalid hydration value: missing block number")
+	_, err = decodeHydrationVal([]byte{1})
+	require.EqualError(t, err, "invalid hydration value: missing total")
+
+	ns, coll := decodeHydrationKey(encodeHydrationKey("ns-1", "coll-1"))
+	require.Equal(t, "ns-1", ns)
+	require.Equal(t, "coll-1", coll)
+
+	require.Equal(t, float64(100), (&HydrationStatus{}).Percentage())
+}
@@ -0,0 +1,207 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/willf/bitset"
+)
+
+// CollectionPvtDataStats returns the amount of private data of a collection held by the store
+func (s *Store) CollectionPvtDataStats(ns, coll string) (*ledger.CollectionPvtDataStats, error) {
+	itr, err := s.db.GetIterator(pvtDataKeyPrefix, expiryKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+
+	stats := &ledger.CollectionPvtDataStats{}
+	for itr.Next() {
+		dataKey, err := decodeDatakey(itr.Key())
+		if err != nil {
+			return nil, err
+		}
+		if dataKey.ns != ns || dataKey.coll != coll {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += uint64(len(itr.Value()))
+	}
+	return stats, nil
+}
+
+// ProcessCollsEligibilityDisabled notifies the store when the peer is no longer eligible to receive data
+// for existing collections. Parameter 'committingBlk' refers to the block number that contains the
+// corresponding collection upgrade transaction and the parameter 'nsCollMap' contains the collections for
+// which the peer is no longer eligible. If the purge of the ineligible data is enabled, the private data of
+// these collections committed up to 'committingBlk' is purged in the background, and tracked as ineligible
+// missing data. Otherwise, the private data that is kept is only reported
+func (s *Store) ProcessCollsEligibilityDisabled(committingBlk uint64, nsCollMap map[string][]string) error {
+	if !s.purgeIneligibleData {
+		for ns, colls := range nsCollMap {
+			for _, coll := range colls {
+				stats, err := s.CollectionPvtDataStats(ns, coll)
+				if err != nil {
+					return err
+				}
+				logger.Warningf("[%s] The peer is no longer eligible for [ns=%s, coll=%s] as of block [%d], its [%d] private data entries ([%d] bytes) are kept as the purge of ineligible data is disabled",
+					s.ledgerid, ns, coll, committingBlk, stats.Entries, stats.Bytes)
+			}
+		}
+		return nil
+	}
+
+	val, err := encodeCollElgVal(newCollElgInfo(nsCollMap))
+	if err != nil {
+		return err
+	}
+	batch := s.db.NewUpdateBatch()
+	batch.Put(encodeCollInelgKey(committingBlk), val)
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	s.collElgProcSync.notify()
+	return nil
+}
+
+type collInelgEvent struct {
+	key    []byte
+	blkNum uint64
+	info   *CollElgInfo
+}
+
+func (s *Store) processCollInelgEvents() error {
+	logger.Debugf("Starting to process collection ineligibility events")
+	s.purgerLock.Lock()
+	defer s.purgerLock.Unlock()
+
+	startKey, endKey := createRangeScanKeysForCollInelg()
+	eventItr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	var events []*collInelgEvent
+	for eventItr.Next() {
+		key := append([]byte{}, eventItr.Key()...)
+		info, err := decodeCollElgVal(eventItr.Value())
+		if err != nil {
+			logger.Errorf("This error is not expected %s", err)
+			continue
+		}
+		events = append(events, &collInelgEvent{key: key, blkNum: decodeCollInelgKey(key), info: info})
+	}
+	eventItr.Release()
+
+	purged := false
+	for _, event := range events {
+		for ns, colls := range event.info.NsCollMap {
+			for _, coll := range colls.Entries {
+				numPurged, err := s.purgeCollIneligibleData(event.blkNum, ns, coll)
+				if err != nil {
+					return err
+				}
+				logger.Infof("[%s] Purged [%d] private data entries of [ns=%s, coll=%s] for which the peer is no longer eligible as of block [%d]",
+					s.ledgerid, numPurged, ns, coll, event.blkNum)
+				purged = true
+			}
+		}
+		batch := s.db.NewUpdateBatch()
+		batch.Delete(event.key)
+		if err := s.db.WriteBatch(batch, true); err != nil {
+			return err
+		}
+	}
+
+	if s.lazyHydration && purged {
+		return s.updateHydrationStatus()
+	}
+	return nil
+}
+
+// purgeCollIneligibleData deletes the private data of a collection committed up to block 'maxBlkNum'
+// and turns it, along with the eligible missing data of the collection, into ineligible missing data
+func (s *Store) purgeCollIneligibleData(maxBlkNum uint64, ns, coll string) (int, error) {
+	inelgEntries := map[missingDataKey]*bitset.BitSet{}
+	addInelgEntry := func(blkNum uint64, bitmap *bitset.BitSet) {
+		key := missingDataKey{nsCollBlk{ns: ns, coll: coll, blkNum: blkNum}}
+		if existing, ok := inelgEntries[key]; ok {
+			existing.InPlaceUnion(bitmap)
+			return
+		}
+		inelgEntries[key] = bitmap
+	}
+	batch := s.db.NewUpdateBatch()
+
+	startKey, endKey := createRangeScanKeysForData(maxBlkNum)
+	dataItr, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return 0, err
+	}
+	numPurged := 0
+	for dataItr.Next() {
+		dataKey, err := decodeDatakey(dataItr.Key())
+		if err != nil {
+			dataItr.Release()
+			return 0, err
+		}
+		if dataKey.ns != ns || dataKey.coll != coll {
+			continue
+		}
+		batch.Delete(append([]byte{}, dataItr.Key()...))
+		addInelgEntry(dataKey.blkNum, bitset.New(uint(dataKey.txNum+1)).Set(uint(dataKey.txNum)))
+		numPurged++
+	}
+	dataItr.Release()
+
+	for _, group := range [][]byte{elgPrioritizedMissingDataGroup, elgDeprioritizedMissingDataGroup} {
+		startKey, endKey := createRangeScanKeysForElgMissingData(maxBlkNum, group)
+		missingItr, err := s.db.GetIterator(startKey, endKey)
+		if err != nil {
+			return 0, err
+		}
+		for missingItr.Next() {
+			key := decodeElgMissingDataKey(missingItr.Key())
+			if key.ns != ns || key.coll != coll {
+				continue
+			}
+			bitmap, err := decodeMissingDataValue(missingItr.Value())
+			if err != nil {
+				missingItr.Release()
+				return 0, err
+			}
+			batch.Delete(append([]byte{}, missingItr.Key()...))
+			addInelgEntry(key.blkNum, bitmap)
+		}
+		missingItr.Release()
+	}
+
+	for key, bitmap := range inelgEntries {
+		encKey := encodeInelgMissingDataKey(&key)
+		existing, err := s.db.Get(encKey)
+		if err != nil {
+			return 0, err
+		}
+		if existing != nil {
+			existingBitmap, err := decodeMissingDataValue(existing)
+			if err != nil {
+				return 0, err
+			}
+			bitmap.InPlaceUnion(existingBitmap)
+		}
+		val, err := encodeMissingDataValue(bitmap)
+		if err != nil {
+			return 0, err
+		}
+		batch.Put(encKey, val)
+	}
+	batch.Delete(encodeHydrationKey(ns, coll))
+
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return 0, err
+	}
+	return numPurged, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionPvtDataStats(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestCollectionPvtDataStats", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	require.NoError(t, store.Commit(0, nil, nil))
+	require.NoError(t, store.Commit(1, []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 1, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+		produceSamplePvtdata(t, 3, []string{"ns-1:coll-1"}),
+	}, nil))
+
+	stats, err := store.CollectionPvtDataStats("ns-1", "coll-1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), stats.Entries)
+	require.NotZero(t, stats.Bytes)
+
+	stats2, err := store.CollectionPvtDataStats("ns-1", "coll-2")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), stats2.Entries)
+	require.True(t, stats2.Bytes < stats.Bytes)
+
+	stats, err = store.CollectionPvtDataStats("ns-2", "coll-1")
+	require.NoError(t, err)
+	require.Equal(t, &ledger.CollectionPvtDataStats{}, stats)
+}
+
+func TestCollsEligibilityDisabled(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	commitBlocks := func(store *Store) {
+		require.NoError(t, store.Commit(0, nil, nil))
+		blk1MissingData := make(ledger.TxMissingPvtDataMap)
+		blk1MissingData.Add(2, "ns-1", "coll-1", true)
+		blk1MissingData.Add(2, "ns-1", "coll-2", true)
+		require.NoError(t, store.Commit(1, []*ledger.TxPvtData{
+			produceSamplePvtdata(t, 1, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+			produceSamplePvtdata(t, 3, []string{"ns-1:coll-1"}),
+		}, blk1MissingData))
+		require.NoError(t, store.Commit(2, []*ledger.TxPvtData{
+			produceSamplePvtdata(t, 1, []string{"ns-1:coll-1"}),
+		}, nil))
+	}
+
+	t.Run("purge disabled", func(t *testing.T) {
+		env := NewTestStoreEnv(t, "TestCollsEligibilityDisabled", btlPolicy, pvtDataConf())
+		defer env.Cleanup()
+		store := env.TestStore
+		commitBlocks(store)
+
+		require.NoError(t, store.ProcessCollsEligibilityDisabled(2, map[string][]string{"ns-1": {"coll-1"}}))
+		require.True(t, testDataKeyExists(t, store, &dataKey{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, 1}))
+		require.True(t, testElgPrioMissingDataKeyExists(t, store, &missingDataKey{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}}))
+	})
+
+	t.Run("purge enabled", func(t *testing.T) {
+		conf := pvtDataConf()
+		conf.PurgeIneligibleData = true
+		env := NewTestStoreEnv(t, "TestCollsEligibilityDisabled", btlPolicy, conf)
+		defer env.Cleanup()
+		store := env.TestStore
+		commitBlocks(store)
+
+		// the peer is no longer eligible for {ns-1:coll-1} as of block 2
+		require.NoError(t, store.ProcessCollsEligibilityDisabled(2, map[string][]string{"ns-1": {"coll-1"}}))
+		testutilWaitForCollElgProcToFinish(store)
+
+		for _, key := range []*dataKey{
+			{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, 1},
+			{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, 3},
+			{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 2}, 1},
+		} {
+			require.False(t, testDataKeyExists(t, store, key))
+		}
+		require.True(t, testDataKeyExists(t, store, &dataKey{nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}, 1}))
+		stats, err := store.CollectionPvtDataStats("ns-1", "coll-1")
+		require.NoError(t, err)
+		require.Zero(t, stats.Entries)
+
+		// the purged and the missing data is no longer fetched by the reconciler
+		expectedMissingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+		expectedMissingPvtDataInfo.Add(1, 2, "ns-1", "coll-2")
+		missingPvtDataInfo, err := store.GetMissingPvtDataInfoForMostRecentBlocks(10)
+		require.NoError(t, err)
+		require.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+		require.True(t, testInelgMissingDataKeyExists(t, store, &missingDataKey{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}}))
+		require.True(t, testInelgMissingDataKeyExists(t, store, &missingDataKey{nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 2}}))
+
+		// the data is fetched again once the peer becomes eligible again
+		require.NoError(t, store.Commit(3, nil, nil))
+		require.NoError(t, store.ProcessCollsEligibilityEnabled(3, map[string][]string{"ns-1": {"coll-1"}}))
+		testutilWaitForCollElgProcToFinish(store)
+		expectedMissingPvtDataInfo = make(ledger.MissingPvtDataInfo)
+		expectedMissingPvtDataInfo.Add(2, 1, "ns-1", "coll-1")
+		expectedMissingPvtDataInfo.Add(1, 1, "ns-1", "coll-1")
+		expectedMissingPvtDataInfo.Add(1, 2, "ns-1", "coll-1")
+		expectedMissingPvtDataInfo.Add(1, 2, "ns-1", "coll-2")
+		expectedMissingPvtDataInfo.Add(1, 3, "ns-1", "coll-1")
+		missingPvtDataInfo, err = store.GetMissingPvtDataInfoForMostRecentBlocks(10)
+		require.NoError(t, err)
+		require.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+	})
+}
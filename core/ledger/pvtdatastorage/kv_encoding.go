@@ -28,6 +28,8 @@ var (
 	collElgKeyPrefix                 = []byte{6}
 	lastUpdatedOldBlocksKey          = []byte{7}
 	elgDeprioritizedMissingDataGroup = []byte{8}
+	hydrationKeyPrefix               = []byte{9}
+	collInelgKeyPrefix               = []byte{10}
 
 	nilByte    = byte(0)
 	emptyValue = []byte{}
@@ -39,6 +41,12 @@ func getDataKeysForRangeScanByBlockNum(blockNum uint64) ([]byte, []byte) {
 	return startKey, endKey
 }
 
+func getDataKeysForRangeScan(minBlkNum, maxBlkNum uint64) ([]byte, []byte) {
+	startKey := append(pvtDataKeyPrefix, version.NewHeight(minBlkNum, 0).ToBytes()...)
+	endKey := append(pvtDataKeyPrefix, version.NewHeight(maxBlkNum+1, 0).ToBytes()...)
+	return startKey, endKey
+}
+
 func getExpiryKeysForRangeScan(minBlkNum, maxBlkNum uint64) ([]byte, []byte) {
 	startKey := append(expiryKeyPrefix, version.NewHeight(minBlkNum, 0).ToBytes()...)
 	endKey := append(expiryKeyPrefix, version.NewHeight(maxBlkNum+1, 0).ToBytes()...)
@@ -190,6 +198,15 @@ func decodeCollElgVal(b []byte) (*CollElgInfo, error) {
 	return m, nil
 }
 
+func encodeCollInelgKey(blkNum uint64) []byte {
+	return append(collInelgKeyPrefix, encodeReverseOrderVarUint64(blkNum)...)
+}
+
+func decodeCollInelgKey(b []byte) uint64 {
+	blkNum, _ := decodeReverseOrderVarUint64(b[1:])
+	return blkNum
+}
+
 func createRangeScanKeysForElgMissingData(blkNum uint64, group []byte) ([]byte, []byte) {
 	startKey := append(group, encodeReverseOrderVarUint64(blkNum)...)
 	endKey := append(group, encodeReverseOrderVarUint64(0)...)
@@ -220,11 +237,53 @@ func createRangeScanKeysForInelgMissingData(maxBlkNum uint64, ns, coll string) (
 	return startKey, endKey
 }
 
+func encodeHydrationKey(ns, coll string) []byte {
+	encKey := append(hydrationKeyPrefix, []byte(ns)...)
+	encKey = append(encKey, nilByte)
+	return append(encKey, []byte(coll)...)
+}
+
+func decodeHydrationKey(keyBytes []byte) (string, string) {
+	splittedKey := bytes.SplitN(keyBytes[1:], []byte{nilByte}, 2)
+	return string(splittedKey[0]), string(splittedKey[1])
+}
+
+func encodeHydrationVal(h *hydrationInfo) []byte {
+	return append(proto.EncodeVarint(h.blkNum), proto.EncodeVarint(h.total)...)
+}
+
+func decodeHydrationVal(b []byte) (*hydrationInfo, error) {
+	blkNum, n := proto.DecodeVarint(b)
+	if n == 0 {
+		return nil, errors.New("invalid hydration value: missing block number")
+	}
+	total, m := proto.DecodeVarint(b[n:])
+	if m == 0 {
+		return nil, errors.New("invalid hydration value: missing total")
+	}
+	return &hydrationInfo{blkNum: blkNum, total: total}, nil
+}
+
+func createRangeScanKeysForHydration() ([]byte, []byte) {
+	return hydrationKeyPrefix, []byte{hydrationKeyPrefix[0] + 1}
+}
+
 func createRangeScanKeysForCollElg() (startKey, endKey []byte) {
 	return encodeCollElgKey(math.MaxUint64),
 		encodeCollElgKey(0)
 }
 
+func createRangeScanKeysForCollInelg() (startKey, endKey []byte) {
+	return encodeCollInelgKey(math.MaxUint64),
+		encodeCollInelgKey(0)
+}
+
+func createRangeScanKeysForData(maxBlkNum uint64) ([]byte, []byte) {
+	startKey := append(pvtDataKeyPrefix, version.NewHeight(0, 0).ToBytes()...)
+	endKey := append(pvtDataKeyPrefix, version.NewHeight(maxBlkNum+1, 0).ToBytes()...)
+	return startKey, endKey
+}
+
 func datakeyRange(blockNum uint64) ([]byte, []byte) {
 	startKey := append(pvtDataKeyPrefix, version.NewHeight(blockNum, 0).ToBytes()...)
 	endKey := append(pvtDataKeyPrefix, version.NewHeight(blockNum, math.MaxUint64).ToBytes()...)
@@ -62,7 +62,14 @@ func (s *Store) CommitPvtDataOfOldBlocks(
 	if err != nil {
 		return err
 	}
-	return s.db.WriteBatch(batch, true)
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	if err := s.updateHydrationStatus(); err != nil {
+		// the pvtData has been committed, only the reporting of the hydration progress is stale
+		logger.Warningf("[%s] Failed updating the hydration status of collections: %s", s.ledgerid, err)
+	}
+	return nil
 }
 
 type oldBlockDataProcessor struct {
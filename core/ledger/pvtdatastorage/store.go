@@ -68,6 +68,10 @@ type Store struct {
 
 	deprioritizedDataReconcilerInterval time.Duration
 	accessDeprioMissingDataAfter        time.Time
+	lazyHydration                       bool
+	purgeIneligibleData                 bool
+	hydrationLock                       sync.RWMutex
+	hydrationStatus                     []*HydrationStatus
 }
 
 type blkTranNumKey []byte
@@ -138,6 +142,8 @@ func (p *Provider) OpenStore(ledgerid string) (*Store, error) {
 		purgeInterval:                       uint64(p.pvtData.PurgeInterval),
 		deprioritizedDataReconcilerInterval: p.pvtData.DeprioritizedDataReconcilerInterval,
 		accessDeprioMissingDataAfter:        time.Now().Add(p.pvtData.DeprioritizedDataReconcilerInterval),
+		lazyHydration:                       p.pvtData.LazyHydration,
+		purgeIneligibleData:                 p.pvtData.PurgeIneligibleData,
 		collElgProcSync: &collElgProcSync{
 			notification: make(chan bool, 1),
 			procComplete: make(chan bool, 1),
@@ -146,6 +152,9 @@ func (p *Provider) OpenStore(ledgerid string) (*Store, error) {
 	if err := s.initState(); err != nil {
 		return nil, err
 	}
+	if err := s.updateHydrationStatus(); err != nil {
+		return nil, err
+	}
 	s.launchCollElgProc()
 	logger.Debugf("Pvtdata store opened. Initial state: isEmpty [%t], lastCommittedBlock [%d]",
 		s.isEmpty, s.lastCommittedBlock)
@@ -202,6 +211,12 @@ func (s *Store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
 	s.btlPolicy = btlPolicy
 }
 
+// BackupSnapshot returns a point-in-time view of the private data of the ledger for a backup.
+// The caller is expected to ensure that no block is committed to the store while the snapshot is taken.
+func (s *Store) BackupSnapshot() (*leveldbhelper.Snapshot, error) {
+	return s.db.GetSnapshot()
+}
+
 // Commit commits the pvt data as well as both the eligible and ineligible
 // missing private data --- `eligible` denotes that the missing private data belongs to a collection
 // for which this peer is a member; `ineligible` denotes that the missing private data belong to a
@@ -600,6 +615,11 @@ func (s *Store) retrieveExpiryEntries(minBlkNum, maxBlkNum uint64) ([]*expiryEnt
 
 func (s *Store) launchCollElgProc() {
 	go func() {
+		// the ineligibility events are processed first, as the peer can only become eligible
+		// again for a collection by a later collection config update
+		if err := s.processCollInelgEvents(); err != nil {
+			logger.Errorw("failed to process collection ineligibility events", "err", err)
+		}
 		if err := s.processCollElgEvents(); err != nil {
 			// process collection eligibility events when store is opened -
 			// in case there is an unprocessed events from previous run
@@ -608,6 +628,9 @@ func (s *Store) launchCollElgProc() {
 		for {
 			logger.Debugf("Waiting for collection eligibility event")
 			s.collElgProcSync.waitForNotification()
+			if err := s.processCollInelgEvents(); err != nil {
+				logger.Errorw("failed to process collection ineligibility events", "err", err)
+			}
 			if err := s.processCollElgEvents(); err != nil {
 				logger.Errorw("failed to process collection eligibility events", "err", err)
 			}
@@ -648,6 +671,16 @@ func (s *Store) processCollElgEvents() error {
 					return err
 				}
 				collEntriesConverted := 0
+				var hydration *hydrationInfo
+				if s.lazyHydration {
+					if hydration, err = s.getHydrationInfo(ns, coll); err != nil {
+						collItr.Release()
+						return err
+					}
+					if hydration.blkNum < blkNum {
+						hydration.blkNum = blkNum
+					}
+				}
 
 				for collItr.Next() { // each entry
 					originalKey, originalVal := collItr.Key(), collItr.Value()
@@ -655,10 +688,26 @@ func (s *Store) processCollElgEvents() error {
 					batch.Delete(originalKey)
 					copyVal := make([]byte, len(originalVal))
 					copy(copyVal, originalVal)
-					batch.Put(
-						encodeElgPrioMissingDataKey(modifiedKey),
-						copyVal,
-					)
+					if hydration == nil {
+						batch.Put(
+							encodeElgPrioMissingDataKey(modifiedKey),
+							copyVal,
+						)
+					} else {
+						// the historical missing data is fetched at low priority and accounted
+						// for the hydration progress of the collection
+						bitmap, err := decodeMissingDataValue(copyVal)
+						if err != nil {
+							collItr.Release()
+							return err
+						}
+						hydration.total += uint64(bitmap.Count())
+						batch.Put(
+							encodeElgDeprioMissingDataKey(modifiedKey),
+							copyVal,
+						)
+						batch.Put(encodeHydrationKey(ns, coll), encodeHydrationVal(hydration))
+					}
 					collEntriesConverted++
 					if batch.Len() > s.maxBatchSize {
 						s.db.WriteBatch(batch, true)
@@ -682,6 +731,9 @@ func (s *Store) processCollElgEvents() error {
 
 	s.db.WriteBatch(batch, true)
 	logger.Debugf("Converted [%d] ineligible missing data entries to eligible", totalEntriesConverted)
+	if s.lazyHydration && totalEntriesConverted > 0 {
+		return s.updateHydrationStatus()
+	}
 	return nil
 }
 
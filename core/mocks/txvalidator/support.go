@@ -30,6 +30,7 @@ type Support struct {
 	MSPManagerVal msp.MSPManager
 	ApplyVal      error
 	ACVal         channelconfig.ApplicationCapabilities
+	CCVal         channelconfig.ChannelCapabilities
 
 	sync.Mutex
 	capabilitiesInvokeCount int
@@ -43,6 +44,11 @@ func (ms *Support) Capabilities() channelconfig.ApplicationCapabilities {
 	return ms.ACVal
 }
 
+// ChannelCapabilities returns CCVal
+func (ms *Support) ChannelCapabilities() channelconfig.ChannelCapabilities {
+	return ms.CCVal
+}
+
 // Ledger returns LedgerVal
 func (ms *Support) Ledger() ledger.PeerLedger {
 	return ms.LedgerVal
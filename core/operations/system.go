@@ -55,6 +55,9 @@ type Options struct {
 	Metrics       MetricsOptions
 	TLS           TLS
 	Version       string
+	// LogSinks restricts the destinations of the log sinks which may be
+	// added through the operations endpoint.
+	LogSinks flogging.SinkAllowList
 }
 
 type System struct {
@@ -176,9 +179,11 @@ func (s *System) initializeMetricsProvider() error {
 		return nil
 
 	case "prometheus":
-		s.Provider = &prometheus.Provider{}
+		exemplars := prometheus.NewExemplars()
+		s.Provider = &prometheus.Provider{Exemplars: exemplars}
 		s.versionGauge = versionGauge(s.Provider)
 		s.mux.Handle("/metrics", s.handlerChain(promhttp.Handler(), s.options.TLS.Enabled))
+		s.mux.Handle("/metrics/exemplars", s.handlerChain(exemplars, s.options.TLS.Enabled))
 		return nil
 
 	default:
@@ -194,6 +199,7 @@ func (s *System) initializeMetricsProvider() error {
 
 func (s *System) initializeLoggingHandler() {
 	s.mux.Handle("/logspec", s.handlerChain(httpadmin.NewSpecHandler(), s.options.TLS.Enabled))
+	s.RegisterAdminHandler("/logspec/sinks", httpadmin.NewSinksHandler(s.options.LogSinks))
 }
 
 func (s *System) initializeHealthCheckHandler() {
@@ -222,6 +228,16 @@ func (s *System) RegisterHandler(pattern string, handler http.Handler) {
 	)
 }
 
+// RegisterAdminHandler registers into the ServeMux a handler chain that only serves requests authenticated
+// with a verified TLS client certificate, whether or not client authentication is required by the other
+// resources of the operations.System. When TLS is disabled, all requests to the handler are refused.
+func (s *System) RegisterAdminHandler(pattern string, handler http.Handler) {
+	if !s.options.TLS.Enabled {
+		s.logger.Warnf("TLS is disabled for the operations endpoint, requests to %s will be refused", pattern)
+	}
+	s.mux.Handle(pattern, s.handlerChain(handler, true))
+}
+
 func (s *System) startMetricsTickers() error {
 	m := s.options.Metrics
 	if s.statsd != nil {
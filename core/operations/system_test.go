@@ -104,6 +104,16 @@ var _ = Describe("System", func() {
 		resp, err = unauthClient.Get(logspecURL)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+
+		sinksURL := fmt.Sprintf("https://%s/logspec/sinks", system.Addr())
+		resp, err = client.Get(sinksURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp.Body.Close()
+
+		resp, err = unauthClient.Get(sinksURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 	})
 
 	It("does not host a secure endpoint for additional APIs by default", func() {
@@ -157,6 +167,31 @@ var _ = Describe("System", func() {
 			resp.Body.Close()
 		})
 
+		It("refuses requests to the log sinks", func() {
+			err := system.Start()
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := client.Get(fmt.Sprintf("http://%s/logspec/sinks", system.Addr()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+			resp.Body.Close()
+
+			Expect(fakeLogger.WarnfCallCount()).To(Equal(1))
+			format, args := fakeLogger.WarnfArgsForCall(0)
+			Expect(fmt.Sprintf(format, args...)).To(Equal("TLS is disabled for the operations endpoint, requests to /logspec/sinks will be refused"))
+		})
+
+		It("refuses requests to admin APIs", func() {
+			system.RegisterAdminHandler(AdditionalTestApiPath, &fakes.Handler{Code: http.StatusOK, Text: "admin"})
+			err := system.Start()
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := client.Get(fmt.Sprintf("http://%s%s", system.Addr(), AdditionalTestApiPath))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+			resp.Body.Close()
+		})
+
 		It("does not host an insecure endpoint for additional APIs by default", func() {
 			err := system.Start()
 			Expect(err).NotTo(HaveOccurred())
@@ -185,6 +220,22 @@ var _ = Describe("System", func() {
 		})
 	})
 
+	It("hosts a secure endpoint for admin APIs when added", func() {
+		system.RegisterAdminHandler(AdditionalTestApiPath, &fakes.Handler{Code: http.StatusOK, Text: "admin"})
+		err := system.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		addApiURL := fmt.Sprintf("https://%s%s", system.Addr(), AdditionalTestApiPath)
+		resp, err := client.Get(addApiURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp.Body.Close()
+
+		resp, err = unauthClient.Get(addApiURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
 	Context("when ClientCertRequired is true", func() {
 		BeforeEach(func() {
 			options.TLS.ClientCertRequired = true
@@ -310,7 +361,8 @@ var _ = Describe("System", func() {
 		})
 
 		It("sets up prometheus as a provider", func() {
-			Expect(system.Provider).To(Equal(&prometheus.Provider{}))
+			Expect(system.Provider).To(BeAssignableToTypeOf(&prometheus.Provider{}))
+			Expect(system.Provider.(*prometheus.Provider).Exemplars).NotTo(BeNil())
 		})
 
 		It("hosts a secure endpoint for metrics", func() {
@@ -331,6 +383,24 @@ var _ = Describe("System", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 		})
 
+		It("hosts a secure endpoint for exemplars", func() {
+			err := system.Start()
+			Expect(err).NotTo(HaveOccurred())
+
+			exemplarsURL := fmt.Sprintf("https://%s/metrics/exemplars", system.Addr())
+			resp, err := client.Get(exemplarsURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(MatchJSON("[]"))
+
+			resp, err = unauthClient.Get(exemplarsURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+
 		It("records the fabric version", func() {
 			err := system.Start()
 			Expect(err).NotTo(HaveOccurred())
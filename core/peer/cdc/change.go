@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdc
+
+import (
+	"path"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+const (
+	// OperationPut sets the value of a key.
+	OperationPut = "put"
+	// OperationDelete deletes a key.
+	OperationDelete = "delete"
+	// OperationIncrement adds the delta to the value of a counter key.
+	OperationIncrement = "increment"
+)
+
+// Version is the version of a key, the height of the transaction that last
+// changed it.
+type Version struct {
+	BlockNumber uint64 `json:"block_num"`
+	TxNumber    uint64 `json:"tx_num"`
+}
+
+// Change is a change of the world state committed by a valid transaction.
+type Change struct {
+	ChannelID string  `json:"channel_id"`
+	Namespace string  `json:"namespace"`
+	Key       string  `json:"key"`
+	Operation string  `json:"operation"`
+	Value     []byte  `json:"value,omitempty"`
+	Delta     int64   `json:"delta,omitempty"`
+	Version   Version `json:"version"`
+	TxID      string  `json:"tx_id"`
+}
+
+// Batch holds the changes committed by a block, in the order of the
+// transactions and of their writes. A block without changes, such as a config
+// block, yields an empty batch, which is not delivered to the sink.
+type Batch struct {
+	ChannelID   string    `json:"channel_id"`
+	BlockNumber uint64    `json:"block_number"`
+	Changes     []*Change `json:"changes"`
+}
+
+// Filter selects the changes exported. An empty list of channels or of
+// namespace patterns selects all of them.
+type Filter struct {
+	Channels   []string
+	Namespaces []string
+}
+
+func (f *Filter) channel(channelID string) bool {
+	if len(f.Channels) == 0 {
+		return true
+	}
+	for _, c := range f.Channels {
+		if c == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) namespace(namespace string) bool {
+	if len(f.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range f.Namespaces {
+		if ok, _ := path.Match(pattern, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// blockBatch returns the public state changes of the valid endorser
+// transactions of the block that pass the filter.
+func blockBatch(channelID string, block *common.Block, filter *Filter) (*Batch, error) {
+	batch := &Batch{
+		ChannelID:   channelID,
+		BlockNumber: block.Header.Number,
+	}
+	txsFilter := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for txNum, envBytes := range block.Data.Data {
+		if txsFilter.IsInvalid(txNum) {
+			continue
+		}
+		env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+		if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		respPayload, err := protoutil.GetActionFromEnvelope(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			return nil, err
+		}
+		version := Version{BlockNumber: block.Header.Number, TxNumber: uint64(txNum)}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			if nsRWSet.KvRwSet == nil || !filter.namespace(nsRWSet.NameSpace) {
+				continue
+			}
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				change := &Change{
+					ChannelID: channelID,
+					Namespace: nsRWSet.NameSpace,
+					Key:       kvWrite.Key,
+					Operation: OperationPut,
+					Value:     kvWrite.Value,
+					Version:   version,
+					TxID:      chdr.TxId,
+				}
+				if kvWrite.IsDelete {
+					change.Operation = OperationDelete
+					change.Value = nil
+				}
+				batch.Changes = append(batch.Changes, change)
+			}
+			for _, counterDelta := range nsRWSet.KvRwSet.CounterDeltas {
+				batch.Changes = append(batch.Changes, &Change{
+					ChannelID: channelID,
+					Namespace: nsRWSet.NameSpace,
+					Key:       counterDelta.Key,
+					Operation: OperationIncrement,
+					Delta:     counterDelta.Delta,
+					Version:   version,
+					TxID:      chdr.TxId,
+				})
+			}
+		}
+	}
+	return batch, nil
+}
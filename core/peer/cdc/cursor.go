@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Cursors stores, for every channel, the number of the last block whose
+// changes were delivered to the sink, from which the export resumes.
+type Cursors struct {
+	dir string
+}
+
+// NewCursors creates the cursor store in the given directory.
+func NewCursors(dir string) (*Cursors, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "could not create cursor directory %s", dir)
+	}
+	return &Cursors{dir: dir}, nil
+}
+
+// Get returns the number of the last block of the channel delivered to the
+// sink; the boolean is false if no block was delivered yet.
+func (c *Cursors) Get(channelID string) (uint64, bool, error) {
+	raw, err := ioutil.ReadFile(c.path(channelID))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "could not read cursor of channel [%s]", channelID)
+	}
+	blockNumber, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "invalid cursor of channel [%s]", channelID)
+	}
+	return blockNumber, true, nil
+}
+
+// Set durably records the last block of the channel delivered to the sink.
+func (c *Cursors) Set(channelID string, blockNumber uint64) error {
+	tmp, err := ioutil.TempFile(c.dir, channelID+".tmp")
+	if err != nil {
+		return errors.Wrapf(err, "could not create cursor of channel [%s]", channelID)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(strconv.FormatUint(blockNumber, 10))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(channelID))
+	}
+	return errors.Wrapf(err, "could not write cursor of channel [%s]", channelID)
+}
+
+func (c *Cursors) path(channelID string) string {
+	return filepath.Join(c.dir, channelID+".cursor")
+}
@@ -0,0 +1,311 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("peer.cdc")
+
+const (
+	// DefaultRetryInterval is the time waited before a failed delivery is
+	// retried when no retry interval is configured.
+	DefaultRetryInterval = 5 * time.Second
+
+	// maxPendingBlocks bounds the committed blocks held in memory for a
+	// channel whose export lags behind; the blocks beyond are read back from
+	// the block store once the export catches up.
+	maxPendingBlocks = 100
+)
+
+// BlockStore provides the blocks of the ledger of a channel.
+type BlockStore interface {
+	GetBlockchainInfo() (*common.BlockchainInfo, error)
+	GetBlockByNumber(blockNumber uint64) (*common.Block, error)
+}
+
+// BlockStoreGetter returns the block store of a channel, nil if the ledger of
+// the channel is not open.
+type BlockStoreGetter func(channelID string) BlockStore
+
+// Exporter exports the state changes committed to the ledgers of the peer to
+// a sink. It is registered as a ledger.CommitListener, but delivers the
+// changes asynchronously so that a slow or unavailable sink never holds up
+// the commit of the blocks.
+//
+// The changes of a channel are delivered one block at a time, in the order
+// of the blocks. A failed delivery is retried until it succeeds, and the
+// number of the last delivered block is then recorded by the cursor of the
+// channel. After a restart, the export resumes from the block following the
+// cursor, reading the blocks committed meanwhile from the block store, so
+// every change is delivered at least once.
+type Exporter struct {
+	sink          Sink
+	cursors       *Cursors
+	filter        Filter
+	blockStores   BlockStoreGetter
+	retryInterval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mutex    sync.Mutex
+	channels map[string]*channelExporter
+}
+
+// NewExporter creates an Exporter delivering the changes selected by the
+// filter to the sink.
+func NewExporter(sink Sink, cursors *Cursors, filter Filter, blockStores BlockStoreGetter, retryInterval time.Duration) *Exporter {
+	if retryInterval <= 0 {
+		retryInterval = DefaultRetryInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Exporter{
+		sink:          sink,
+		cursors:       cursors,
+		filter:        filter,
+		blockStores:   blockStores,
+		retryInterval: retryInterval,
+		ctx:           ctx,
+		cancel:        cancel,
+		channels:      map[string]*channelExporter{},
+	}
+}
+
+// Name implements ledger.CommitListener
+func (e *Exporter) Name() string {
+	return "cdc"
+}
+
+// HandleCommittedBlock implements ledger.CommitListener. It hands the block
+// over to the export of its channel.
+func (e *Exporter) HandleCommittedBlock(event *ledger.CommitEvent) error {
+	if !e.filter.channel(event.LedgerID) {
+		return nil
+	}
+	c, err := e.channel(event.LedgerID)
+	if err != nil {
+		return err
+	}
+	c.committed(event.Block)
+	return nil
+}
+
+// Resume resumes the export of the channel up to the height of its ledger.
+// It is called once the ledger of the channel is open, so that the blocks
+// committed while the peer was down are exported without waiting for the
+// next commit.
+func (e *Exporter) Resume(channelID string) error {
+	if !e.filter.channel(channelID) {
+		return nil
+	}
+	c, err := e.channel(channelID)
+	if err != nil {
+		return err
+	}
+	store := e.blockStores(channelID)
+	if store == nil {
+		return errors.Errorf("ledger of channel [%s] is not open", channelID)
+	}
+	info, err := store.GetBlockchainInfo()
+	if err != nil {
+		return errors.WithMessagef(err, "could not get the height of channel [%s]", channelID)
+	}
+	c.reached(info.Height)
+	return nil
+}
+
+// Close stops the export and closes the sink. The blocks not delivered yet
+// are exported after the peer restarts.
+func (e *Exporter) Close() {
+	e.cancel()
+	e.wg.Wait()
+	if err := e.sink.Close(); err != nil {
+		logger.Warningf("Failed closing the change sink: %s", err)
+	}
+}
+
+func (e *Exporter) channel(channelID string) (*channelExporter, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if c, ok := e.channels[channelID]; ok {
+		return c, nil
+	}
+
+	var next uint64
+	delivered, ok, err := e.cursors.Get(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		next = delivered + 1
+	}
+	c := &channelExporter{
+		exporter:  e,
+		channelID: channelID,
+		next:      next,
+		height:    next,
+		pending:   map[uint64]*common.Block{},
+		signal:    make(chan struct{}, 1),
+	}
+	e.channels[channelID] = c
+	e.wg.Add(1)
+	go c.run()
+	logger.Infof("Exporting the changes of channel [%s] from block [%d]", channelID, next)
+	return c, nil
+}
+
+// channelExporter exports the changes of a channel.
+type channelExporter struct {
+	exporter  *Exporter
+	channelID string
+	signal    chan struct{}
+
+	mutex sync.Mutex
+	// next is the number of the next block to deliver.
+	next uint64
+	// height is the height of the ledger known to the export.
+	height  uint64
+	pending map[uint64]*common.Block
+}
+
+func (c *channelExporter) committed(block *common.Block) {
+	c.mutex.Lock()
+	number := block.Header.Number
+	if number+1 > c.height {
+		c.height = number + 1
+	}
+	if number >= c.next && len(c.pending) < maxPendingBlocks {
+		c.pending[number] = block
+	}
+	c.mutex.Unlock()
+	c.notify()
+}
+
+func (c *channelExporter) reached(height uint64) {
+	c.mutex.Lock()
+	if height > c.height {
+		c.height = height
+	}
+	c.mutex.Unlock()
+	c.notify()
+}
+
+func (c *channelExporter) notify() {
+	select {
+	case c.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (c *channelExporter) run() {
+	defer c.exporter.wg.Done()
+	for {
+		select {
+		case <-c.signal:
+		case <-c.exporter.ctx.Done():
+			return
+		}
+		if !c.export() {
+			return
+		}
+	}
+}
+
+// export delivers the blocks up to the known height. It returns false when
+// the exporter is closed.
+func (c *channelExporter) export() bool {
+	for {
+		c.mutex.Lock()
+		if c.next >= c.height {
+			c.mutex.Unlock()
+			return true
+		}
+		number := c.next
+		block := c.pending[number]
+		c.mutex.Unlock()
+
+		if block == nil {
+			var err error
+			if block, err = c.fetch(number); err != nil {
+				logger.Warningf("Failed reading block [%d] of channel [%s], retrying in %s: %s", number, c.channelID, c.exporter.retryInterval, err)
+				if !c.wait() {
+					return false
+				}
+				continue
+			}
+		}
+		if !c.deliver(block) {
+			return false
+		}
+
+		c.mutex.Lock()
+		delete(c.pending, number)
+		c.next = number + 1
+		c.mutex.Unlock()
+	}
+}
+
+// deliver sends the changes of the block to the sink and records the cursor,
+// retrying until both succeed. It returns false when the exporter is closed.
+func (c *channelExporter) deliver(block *common.Block) bool {
+	e := c.exporter
+	batch, err := blockBatch(c.channelID, block, &e.filter)
+	if err != nil {
+		// the block was validated and committed: it cannot be exported and
+		// retrying would stall the export of the channel forever
+		logger.Errorf("Skipping the changes of block [%d] of channel [%s]: %s", block.Header.Number, c.channelID, err)
+		batch = &Batch{ChannelID: c.channelID, BlockNumber: block.Header.Number}
+	}
+	for len(batch.Changes) > 0 {
+		err := e.sink.Send(e.ctx, batch)
+		if err == nil {
+			logger.Debugf("Delivered %d changes of block [%d] of channel [%s]", len(batch.Changes), batch.BlockNumber, c.channelID)
+			break
+		}
+		logger.Warningf("Failed delivering the changes of block [%d] of channel [%s], retrying in %s: %s", batch.BlockNumber, c.channelID, e.retryInterval, err)
+		if !c.wait() {
+			return false
+		}
+	}
+	for {
+		err := e.cursors.Set(c.channelID, batch.BlockNumber)
+		if err == nil {
+			return true
+		}
+		logger.Errorf("Failed recording the cursor of channel [%s], retrying in %s: %s", c.channelID, e.retryInterval, err)
+		if !c.wait() {
+			return false
+		}
+	}
+}
+
+func (c *channelExporter) fetch(number uint64) (*common.Block, error) {
+	store := c.exporter.blockStores(c.channelID)
+	if store == nil {
+		return nil, errors.Errorf("ledger of channel [%s] is not open", c.channelID)
+	}
+	return store.GetBlockByNumber(number)
+}
+
+func (c *channelExporter) wait() bool {
+	select {
+	case <-time.After(c.exporter.retryInterval):
+		return true
+	case <-c.exporter.ctx.Done():
+		return false
+	}
+}
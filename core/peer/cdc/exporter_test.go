@@ -0,0 +1,229 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdc
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type write struct {
+	ns, key, value string
+	delta          int64
+}
+
+func endorserTx(t *testing.T, txID string, writes ...write) []byte {
+	txRWSet := &rwsetutil.TxRwSet{}
+	for _, w := range writes {
+		kvRWSet := &kvrwset.KVRWSet{}
+		if w.delta != 0 {
+			kvRWSet.CounterDeltas = []*kvrwset.KVCounterDelta{rwsetutil.NewKVCounterDelta(w.key, w.delta)}
+		} else {
+			kvRWSet.Writes = []*kvrwset.KVWrite{{Key: w.key, Value: []byte(w.value), IsDelete: w.value == ""}}
+		}
+		txRWSet.NsRwSets = append(txRWSet.NsRwSets, &rwsetutil.NsRwSet{NameSpace: w.ns, KvRwSet: kvRWSet})
+	}
+	results, err := txRWSet.ToProtoBytes()
+	require.NoError(t, err)
+
+	prp := protoutil.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{Results: results}),
+	})
+	cap := protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp},
+	})
+	tx := protoutil.MarshalOrPanic(&pb.Transaction{
+		Actions: []*pb.TransactionAction{{Payload: cap}},
+	})
+	chdr := protoutil.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0)
+	chdr.TxId = txID
+	payload := &common.Payload{
+		Header: protoutil.MakePayloadHeader(chdr, protoutil.MakeSignatureHeader(nil, nil)),
+		Data:   tx,
+	}
+	return protoutil.MarshalOrPanic(&common.Envelope{Payload: protoutil.MarshalOrPanic(payload)})
+}
+
+func newBlock(blockNum uint64, flags []pb.TxValidationCode, txs ...[]byte) *common.Block {
+	block := protoutil.NewBlock(blockNum, nil)
+	block.Data.Data = txs
+	txsFilter := txflags.NewWithValues(len(txs), pb.TxValidationCode_VALID)
+	for i, flag := range flags {
+		txsFilter.SetFlag(i, flag)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	return block
+}
+
+type fakeSink struct {
+	mutex    sync.Mutex
+	batches  []*Batch
+	failures int
+	closed   bool
+}
+
+func (s *fakeSink) Send(ctx context.Context, batch *Batch) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) delivered() []uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var blocks []uint64
+	for _, b := range s.batches {
+		blocks = append(blocks, b.BlockNumber)
+	}
+	return blocks
+}
+
+type fakeBlockStore []*common.Block
+
+func (f fakeBlockStore) GetBlockchainInfo() (*common.BlockchainInfo, error) {
+	return &common.BlockchainInfo{Height: uint64(len(f))}, nil
+}
+
+func (f fakeBlockStore) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
+	if blockNumber >= uint64(len(f)) {
+		return nil, errors.Errorf("no block [%d]", blockNumber)
+	}
+	return f[blockNumber], nil
+}
+
+func TestBlockBatch(t *testing.T) {
+	block := newBlock(5, []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT},
+		endorserTx(t, "tx1", write{ns: "assetcc", key: "car1", value: "red"}, write{ns: "assetcc", key: "car2"}, write{ns: "lscc", key: "assetcc", value: "def"}),
+		endorserTx(t, "tx2", write{ns: "assetcc", key: "car3", value: "blue"}),
+		endorserTx(t, "tx3", write{ns: "assetcc", key: "visits", delta: 3}),
+	)
+
+	batch, err := blockBatch("mychannel", block, &Filter{Namespaces: []string{"asset*"}})
+	require.NoError(t, err)
+	assert.Equal(t, &Batch{
+		ChannelID:   "mychannel",
+		BlockNumber: 5,
+		Changes: []*Change{
+			{ChannelID: "mychannel", Namespace: "assetcc", Key: "car1", Operation: OperationPut, Value: []byte("red"), Version: Version{BlockNumber: 5, TxNumber: 0}, TxID: "tx1"},
+			{ChannelID: "mychannel", Namespace: "assetcc", Key: "car2", Operation: OperationDelete, Version: Version{BlockNumber: 5, TxNumber: 0}, TxID: "tx1"},
+			{ChannelID: "mychannel", Namespace: "assetcc", Key: "visits", Operation: OperationIncrement, Delta: 3, Version: Version{BlockNumber: 5, TxNumber: 2}, TxID: "tx3"},
+		},
+	}, batch)
+
+	batch, err = blockBatch("mychannel", block, &Filter{})
+	require.NoError(t, err)
+	assert.Len(t, batch.Changes, 4)
+}
+
+func TestExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cdc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cursors, err := NewCursors(dir)
+	require.NoError(t, err)
+
+	blocks := fakeBlockStore{
+		newBlock(0, nil),
+		newBlock(1, nil, endorserTx(t, "tx1", write{ns: "assetcc", key: "car1", value: "red"})),
+		newBlock(2, nil, endorserTx(t, "tx2", write{ns: "assetcc", key: "car2", value: "blue"})),
+		newBlock(3, nil, endorserTx(t, "tx3", write{ns: "assetcc", key: "car3", value: "green"})),
+	}
+	var mutex sync.Mutex
+	height := 2
+	blockStores := func(channelID string) BlockStore {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return blocks[:height]
+	}
+
+	sink := &fakeSink{failures: 2}
+	exporter := NewExporter(sink, cursors, Filter{Channels: []string{"mychannel"}}, blockStores, 10*time.Millisecond)
+	assert.Equal(t, "cdc", exporter.Name())
+
+	// the export resumes up to the height of the ledger, retrying the sink
+	require.NoError(t, exporter.Resume("mychannel"))
+	require.Eventually(t, func() bool { return len(sink.delivered()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{1}, sink.delivered(), "the empty batch of the genesis block is not delivered")
+
+	// the committed blocks are exported, the other channels are ignored
+	require.NoError(t, exporter.HandleCommittedBlock(&ledger.CommitEvent{LedgerID: "otherchannel", Block: blocks[2]}))
+	require.NoError(t, exporter.HandleCommittedBlock(&ledger.CommitEvent{LedgerID: "mychannel", Block: blocks[2]}))
+	require.Eventually(t, func() bool { return len(sink.delivered()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		delivered, ok, err := cursors.Get("mychannel")
+		return err == nil && ok && delivered == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	_, ok, err := cursors.Get("otherchannel")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	exporter.Close()
+	assert.True(t, sink.closed)
+
+	// after a restart, the export resumes from the cursor, reading the
+	// blocks committed meanwhile from the block store
+	mutex.Lock()
+	height = 4
+	mutex.Unlock()
+	sink = &fakeSink{}
+	exporter = NewExporter(sink, cursors, Filter{}, blockStores, 10*time.Millisecond)
+	defer exporter.Close()
+	require.NoError(t, exporter.Resume("mychannel"))
+	require.Eventually(t, func() bool { return len(sink.delivered()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []uint64{3}, sink.delivered())
+	assert.Equal(t, "car3", sink.batches[0].Changes[0].Key)
+}
+
+func TestCursors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cdc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cursors, err := NewCursors(dir)
+	require.NoError(t, err)
+
+	_, ok, err := cursors.Get("mychannel")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, cursors.Set("mychannel", 7))
+	require.NoError(t, cursors.Set("mychannel", 8))
+	delivered, ok, err := cursors.Get("mychannel")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(8), delivered)
+
+	require.NoError(t, ioutil.WriteFile(cursors.path("mychannel"), []byte("eight"), 0644))
+	_, _, err = cursors.Get("mychannel")
+	assert.Contains(t, err.Error(), "invalid cursor of channel [mychannel]")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Shopify/sarama"
+	"github.com/pkg/errors"
+)
+
+// KafkaSink publishes the changes to a Kafka topic, one JSON encoded change
+// per message. The messages are keyed by channel, namespace and key, so that
+// the changes of a key land on the same partition, in order.
+type KafkaSink struct {
+	Topic    string
+	Producer sarama.SyncProducer
+}
+
+// NewKafkaSink creates a KafkaSink publishing to the topic through the given
+// brokers. A batch is only acknowledged once all the in-sync replicas have
+// received its messages.
+func NewKafkaSink(brokers []string, topic string) (*KafkaSink, error) {
+	config := sarama.NewConfig()
+	config.ClientID = "fabric-peer-cdc"
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Retry.Max = 0
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create Kafka producer for brokers %v", brokers)
+	}
+	return &KafkaSink{
+		Topic:    topic,
+		Producer: producer,
+	}, nil
+}
+
+// Send publishes the changes of the batch and waits for their acknowledgment.
+func (s *KafkaSink) Send(ctx context.Context, batch *Batch) error {
+	if len(batch.Changes) == 0 {
+		return nil
+	}
+	msgs := make([]*sarama.ProducerMessage, 0, len(batch.Changes))
+	for _, change := range batch.Changes {
+		value, err := json.Marshal(change)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal change")
+		}
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: s.Topic,
+			Key:   sarama.StringEncoder(change.ChannelID + "/" + change.Namespace + "/" + change.Key),
+			Value: sarama.ByteEncoder(value),
+		})
+	}
+	if err := s.Producer.SendMessages(msgs); err != nil {
+		return errors.Wrapf(err, "could not publish changes of block [%d] of channel [%s] to topic %s", batch.BlockNumber, batch.ChannelID, s.Topic)
+	}
+	return nil
+}
+
+// Close closes the producer.
+func (s *KafkaSink) Close() error {
+	return s.Producer.Close()
+}
@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// ExportMethod is the full name of the gRPC method invoked by the gRPC sink
+// with every batch. The request and response messages are JSON encoded, with
+// the gRPC content subtype "json".
+const ExportMethod = "/cdc.ChangeDataCapture/Export"
+
+// Sink receives the batches of changes. A batch is delivered again until Send
+// succeeds, and may also be delivered again after the peer restarts, so a
+// sink must tolerate duplicate batches: the version of a change identifies it.
+type Sink interface {
+	Send(ctx context.Context, batch *Batch) error
+	Close() error
+}
+
+// NewSink creates the sink of the peer configuration.
+func NewSink(conf *peer.Config) (Sink, error) {
+	switch conf.CDCSink {
+	case "file":
+		return NewFileSink(conf.CDCFile)
+	case "kafka":
+		return NewKafkaSink(conf.CDCKafkaBrokers, conf.CDCKafkaTopic)
+	case "grpc":
+		clientConfig := comm.ClientConfig{
+			Timeout: 30 * time.Second,
+			KaOpts:  comm.DefaultKeepaliveOptions,
+		}
+		if conf.CDCGRPCRootCert != "" {
+			rootCert, err := ioutil.ReadFile(conf.CDCGRPCRootCert)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not read root certificate of the sink server")
+			}
+			clientConfig.SecOpts = comm.SecureOptions{
+				UseTLS:        true,
+				ServerRootCAs: [][]byte{rootCert},
+			}
+		}
+		client, err := comm.NewGRPCClient(clientConfig)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not create gRPC client for the sink server")
+		}
+		return &GRPCSink{
+			Address: conf.CDCGRPCAddress,
+			Client:  client,
+		}, nil
+	default:
+		return nil, errors.Errorf("change data capture sink of unknown type [%s]", conf.CDCSink)
+	}
+}
+
+// FileSink appends the changes to a file, one JSON encoded change per line.
+type FileSink struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// NewFileSink opens the file the changes are appended to, creating it and its
+// directory if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Wrapf(err, "could not create directory of change file %s", path)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open change file %s", path)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Send appends the changes of the batch and syncs the file.
+func (s *FileSink) Send(ctx context.Context, batch *Batch) error {
+	var lines []byte
+	for _, change := range batch.Changes {
+		line, err := json.Marshal(change)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal change")
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := s.file.Write(lines); err != nil {
+		return errors.Wrapf(err, "could not write change file %s", s.file.Name())
+	}
+	return errors.Wrapf(s.file.Sync(), "could not sync change file %s", s.file.Name())
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// Ack is the response of the gRPC sink server.
+type Ack struct{}
+
+// GRPCSink invokes the ExportMethod of a gRPC server with every batch.
+type GRPCSink struct {
+	Address string
+	Client  *comm.GRPCClient
+}
+
+// Send invokes the sink server and waits for its response.
+func (s *GRPCSink) Send(ctx context.Context, batch *Batch) error {
+	conn, err := s.Client.NewConnection(s.Address)
+	if err != nil {
+		return errors.WithMessagef(err, "could not connect to sink server %s", s.Address)
+	}
+	defer conn.Close()
+
+	err = conn.Invoke(ctx, ExportMethod, batch, &Ack{}, grpc.CallContentSubtype(jsonCodecName))
+	if err != nil {
+		return errors.Wrapf(err, "sink server %s failed", s.Address)
+	}
+	return nil
+}
+
+// Close does nothing, the connections are closed after every batch.
+func (s *GRPCSink) Close() error {
+	return nil
+}
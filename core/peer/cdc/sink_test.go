@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cdc
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBatch = &Batch{
+	ChannelID:   "mychannel",
+	BlockNumber: 3,
+	Changes: []*Change{
+		{ChannelID: "mychannel", Namespace: "assetcc", Key: "car1", Operation: OperationPut, Value: []byte("red"), Version: Version{BlockNumber: 3}, TxID: "tx1"},
+		{ChannelID: "mychannel", Namespace: "assetcc", Key: "car2", Operation: OperationDelete, Version: Version{BlockNumber: 3, TxNumber: 1}, TxID: "tx2"},
+	},
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cdc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "export", "changes.log")
+	sink, err := NewSink(&peer.Config{CDCSink: "file", CDCFile: path})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), testBatch))
+	require.NoError(t, sink.Close())
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"channel_id":"mychannel","namespace":"assetcc","key":"car1","operation":"put","value":"cmVk","version":{"block_num":3,"tx_num":0},"tx_id":"tx1"}
+{"channel_id":"mychannel","namespace":"assetcc","key":"car2","operation":"delete","version":{"block_num":3,"tx_num":1},"tx_id":"tx2"}
+`, string(raw))
+}
+
+func TestKafkaSink(t *testing.T) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	producer := mocks.NewSyncProducer(t, config)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		assert.Contains(t, string(val), `"key":"car1"`)
+		return nil
+	})
+	producer.ExpectSendMessageAndSucceed()
+	sink := &KafkaSink{Topic: "state-changes", Producer: producer}
+	require.NoError(t, sink.Send(context.Background(), testBatch))
+	require.NoError(t, sink.Send(context.Background(), &Batch{ChannelID: "mychannel", BlockNumber: 4}))
+
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndFail(sarama.ErrNotEnoughReplicas)
+	err := sink.Send(context.Background(), testBatch)
+	assert.Contains(t, err.Error(), "could not publish changes of block [3] of channel [mychannel] to topic state-changes")
+	require.NoError(t, sink.Close())
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink(&peer.Config{CDCSink: "grpc", CDCGRPCAddress: "localhost:7070"})
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:7070", sink.(*GRPCSink).Address)
+
+	_, err = NewSink(&peer.Config{CDCSink: "grpc", CDCGRPCAddress: "localhost:7070", CDCGRPCRootCert: "/nonexistent/ca.pem"})
+	assert.Contains(t, err.Error(), "could not read root certificate of the sink server")
+
+	_, err = NewSink(&peer.Config{CDCSink: "s3"})
+	assert.EqualError(t, err, "change data capture sink of unknown type [s3]")
+}
@@ -104,6 +104,12 @@ func (c *Channel) Capabilities() channelconfig.ApplicationCapabilities {
 	return ac.Capabilities()
 }
 
+// ChannelCapabilities gets the channel capabilities for the current channel
+// configuration.
+func (c *Channel) ChannelCapabilities() channelconfig.ChannelCapabilities {
+	return c.Resources().ChannelConfig().Capabilities()
+}
+
 // GetMSPIDs retrieves the MSP IDs of the organizations in the current channel
 // configuration.
 func (c *Channel) GetMSPIDs() []string {
@@ -0,0 +1,272 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+var logger = flogging.MustGetLogger("peer.channelhooks")
+
+// DefaultTimeout is the time a hook is given to handle an event when no
+// timeout is configured.
+const DefaultTimeout = 30 * time.Second
+
+// NotifyMethod is the full name of the gRPC method invoked by gRPC hooks.
+// The request and response messages are JSON encoded, with the gRPC content
+// subtype "json".
+const NotifyMethod = "/channelhooks.ChannelHooks/Notify"
+
+// EventType is the type of a channel lifecycle event.
+type EventType string
+
+const (
+	// ChannelJoined is dispatched once the peer has joined a channel.
+	ChannelJoined EventType = "channel-joined"
+	// FirstBlockCommitted is dispatched once the first block following the
+	// genesis block of a channel has been committed.
+	FirstBlockCommitted EventType = "first-block-committed"
+)
+
+// Event is a channel lifecycle event passed to hooks.
+type Event struct {
+	Type        EventType `json:"type"`
+	ChannelID   string    `json:"channel_id"`
+	BlockNumber uint64    `json:"block_number"`
+	PeerID      string    `json:"peer_id"`
+	MSPID       string    `json:"mspid"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Ack is the response of a gRPC hook.
+type Ack struct{}
+
+// Hook handles channel lifecycle events.
+type Hook interface {
+	Notify(ctx context.Context, event *Event) error
+}
+
+// ExecHook runs an executable for each event.  The event is written as JSON
+// to the standard input of the executable, and its type and channel are also
+// passed in the HOOK_EVENT and HOOK_CHANNEL_ID environment variables.
+type ExecHook struct {
+	Path                 string
+	PropagateEnvironment []string
+}
+
+// Notify runs the executable and waits for it to complete.
+func (h *ExecHook) Notify(ctx context.Context, event *Event) error {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal event")
+	}
+
+	cmd := exec.CommandContext(ctx, h.Path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = []string{
+		fmt.Sprintf("HOOK_EVENT=%s", event.Type),
+		fmt.Sprintf("HOOK_CHANNEL_ID=%s", event.ChannelID),
+		fmt.Sprintf("HOOK_BLOCK_NUMBER=%d", event.BlockNumber),
+	}
+	for _, key := range h.PropagateEnvironment {
+		if val, ok := os.LookupEnv(key); ok {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, val))
+		}
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "hook %s failed with output: %s", h.Path, output)
+	}
+	return nil
+}
+
+// GRPCHook invokes the NotifyMethod of a gRPC server for each event.
+type GRPCHook struct {
+	Address string
+	Client  *comm.GRPCClient
+}
+
+// Notify invokes the hook server and waits for its response.
+func (h *GRPCHook) Notify(ctx context.Context, event *Event) error {
+	conn, err := h.Client.NewConnection(h.Address)
+	if err != nil {
+		return errors.WithMessagef(err, "could not connect to hook server %s", h.Address)
+	}
+	defer conn.Close()
+
+	err = conn.Invoke(ctx, NotifyMethod, event, &Ack{}, grpc.CallContentSubtype(comm.JSONCodecName))
+	if err != nil {
+		return errors.Wrapf(err, "hook server %s failed", h.Address)
+	}
+	return nil
+}
+
+type registeredHook struct {
+	name    string
+	hook    Hook
+	events  map[EventType]struct{}
+	timeout time.Duration
+}
+
+// Dispatcher dispatches channel lifecycle events to the hooks interested in
+// them.  Hooks run asynchronously so that they never hold up the peer, and
+// their failures are logged.
+type Dispatcher struct {
+	PeerID string
+	MSPID  string
+
+	hooks []*registeredHook
+}
+
+// NewDispatcher creates a Dispatcher for the hooks of the peer configuration.
+func NewDispatcher(peerID, mspID string, hookConfs []peer.ChannelHook) (*Dispatcher, error) {
+	d := &Dispatcher{
+		PeerID: peerID,
+		MSPID:  mspID,
+	}
+
+	for _, hc := range hookConfs {
+		timeout := DefaultTimeout
+		if hc.Timeout != "" {
+			var err error
+			timeout, err = time.ParseDuration(hc.Timeout)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid timeout for channel hook %s", hc.Name)
+			}
+		}
+
+		var hook Hook
+		switch {
+		case hc.Exec != "":
+			hook = &ExecHook{
+				Path:                 hc.Exec,
+				PropagateEnvironment: hc.PropagateEnvironment,
+			}
+		case hc.GRPCAddress != "":
+			clientConfig := comm.ClientConfig{
+				Timeout: timeout,
+				KaOpts:  comm.DefaultKeepaliveOptions,
+			}
+			if hc.GRPCRootCert != "" {
+				rootCert, err := ioutil.ReadFile(hc.GRPCRootCert)
+				if err != nil {
+					return nil, errors.Wrapf(err, "could not read root certificate of channel hook %s", hc.Name)
+				}
+				clientConfig.SecOpts = comm.SecureOptions{
+					UseTLS:        true,
+					ServerRootCAs: [][]byte{rootCert},
+				}
+			}
+			client, err := comm.NewGRPCClient(clientConfig)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "could not create gRPC client for channel hook %s", hc.Name)
+			}
+			hook = &GRPCHook{
+				Address: hc.GRPCAddress,
+				Client:  client,
+			}
+		default:
+			return nil, errors.Errorf("channel hook %s has neither exec nor grpcAddress", hc.Name)
+		}
+
+		d.Register(hc.Name, hook, timeout, hc.Events...)
+	}
+
+	return d, nil
+}
+
+// Register adds a hook which is notified of the given event types, or of all
+// event types when none is given.
+func (d *Dispatcher) Register(name string, hook Hook, timeout time.Duration, events ...string) {
+	rh := &registeredHook{
+		name:    name,
+		hook:    hook,
+		timeout: timeout,
+	}
+	if len(events) > 0 {
+		rh.events = map[EventType]struct{}{}
+		for _, event := range events {
+			rh.events[EventType(event)] = struct{}{}
+		}
+	}
+	d.hooks = append(d.hooks, rh)
+}
+
+// ChannelJoined dispatches a ChannelJoined event.
+func (d *Dispatcher) ChannelJoined(channelID string) {
+	d.Dispatch(ChannelJoined, channelID, 0)
+}
+
+// BlockCommitted dispatches a FirstBlockCommitted event when the committed
+// block is the first one following the genesis block.
+func (d *Dispatcher) BlockCommitted(channelID string, blockNumber uint64) {
+	if blockNumber == 1 {
+		d.Dispatch(FirstBlockCommitted, channelID, blockNumber)
+	}
+}
+
+// Dispatch notifies the hooks interested in the event.  The returned channel
+// is closed once all of them have completed.  Dispatch may be called on a nil
+// Dispatcher, in which case it does nothing.
+func (d *Dispatcher) Dispatch(eventType EventType, channelID string, blockNumber uint64) <-chan struct{} {
+	done := make(chan struct{})
+	if d == nil {
+		close(done)
+		return done
+	}
+
+	event := &Event{
+		Type:        eventType,
+		ChannelID:   channelID,
+		BlockNumber: blockNumber,
+		PeerID:      d.PeerID,
+		MSPID:       d.MSPID,
+		Timestamp:   time.Now(),
+	}
+
+	var interested []*registeredHook
+	for _, rh := range d.hooks {
+		if _, ok := rh.events[eventType]; ok || rh.events == nil {
+			interested = append(interested, rh)
+		}
+	}
+
+	go func() {
+		defer close(done)
+		for _, rh := range interested {
+			d.notify(rh, event)
+		}
+	}()
+
+	return done
+}
+
+func (d *Dispatcher) notify(rh *registeredHook, event *Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), rh.timeout)
+	defer cancel()
+
+	logger.Debugf("Notifying channel hook %s of %s on channel %s", rh.name, event.Type, event.ChannelID)
+	if err := rh.hook.Notify(ctx, event); err != nil {
+		logger.Errorf("Channel hook %s failed handling %s on channel %s: %s", rh.name, event.Type, event.ChannelID, err)
+		return
+	}
+	logger.Infof("Channel hook %s handled %s on channel %s", rh.name, event.Type, event.ChannelID)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type recordingHook struct {
+	mutex  sync.Mutex
+	events []*Event
+	err    error
+}
+
+func (r *recordingHook) Notify(ctx context.Context, event *Event) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func (r *recordingHook) Events() []*Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.events
+}
+
+func TestExecHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "channelhooks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "output")
+	script := filepath.Join(dir, "hook")
+	err = ioutil.WriteFile(script, []byte("#!/bin/sh\nset -e\necho \"$HOOK_EVENT $HOOK_CHANNEL_ID $HOOK_BLOCK_NUMBER $PROPAGATED\" > "+output+"\ncat >> "+output+"\n"), 0755)
+	require.NoError(t, err)
+
+	os.Setenv("PROPAGATED", "propagated")
+	defer os.Unsetenv("PROPAGATED")
+
+	hook := &ExecHook{Path: script, PropagateEnvironment: []string{"PROPAGATED"}}
+	err = hook.Notify(context.Background(), &Event{
+		Type:        FirstBlockCommitted,
+		ChannelID:   "mychannel",
+		BlockNumber: 1,
+		PeerID:      "peer0",
+	})
+	require.NoError(t, err)
+
+	contents, err := ioutil.ReadFile(output)
+	require.NoError(t, err)
+	lines := string(contents)
+	assert.Contains(t, lines, "first-block-committed mychannel 1 propagated\n")
+	assert.Contains(t, lines, `"channel_id":"mychannel"`)
+	assert.Contains(t, lines, `"peer_id":"peer0"`)
+}
+
+func TestExecHookFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "channelhooks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "hook")
+	err = ioutil.WriteFile(script, []byte("#!/bin/sh\necho boom\nexit 1\n"), 0755)
+	require.NoError(t, err)
+
+	hook := &ExecHook{Path: script}
+	err = hook.Notify(context.Background(), &Event{Type: ChannelJoined, ChannelID: "mychannel"})
+	assert.EqualError(t, err, "hook "+script+" failed with output: boom\n: exit status 1")
+}
+
+type hookServer struct {
+	events chan *Event
+}
+
+func (h *hookServer) notify(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	event := &Event{}
+	if err := dec(event); err != nil {
+		return nil, err
+	}
+	h.events <- event
+	return &Ack{}, nil
+}
+
+func TestGRPCHook(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	hs := &hookServer{events: make(chan *Event, 1)}
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "channelhooks.ChannelHooks",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "Notify", Handler: hs.notify},
+		},
+	}, hs)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	client, err := comm.NewGRPCClient(comm.ClientConfig{Timeout: time.Second})
+	require.NoError(t, err)
+
+	hook := &GRPCHook{Address: lis.Addr().String(), Client: client}
+	err = hook.Notify(context.Background(), &Event{Type: ChannelJoined, ChannelID: "mychannel", MSPID: "Org1MSP"})
+	require.NoError(t, err)
+
+	event := <-hs.events
+	assert.Equal(t, ChannelJoined, event.Type)
+	assert.Equal(t, "mychannel", event.ChannelID)
+	assert.Equal(t, "Org1MSP", event.MSPID)
+
+	hook = &GRPCHook{Address: "127.0.0.1:1", Client: client}
+	err = hook.Notify(context.Background(), &Event{Type: ChannelJoined, ChannelID: "mychannel"})
+	assert.Error(t, err)
+}
+
+func TestDispatcher(t *testing.T) {
+	all := &recordingHook{}
+	joinOnly := &recordingHook{}
+	failing := &recordingHook{err: errors.New("boom")}
+
+	d := &Dispatcher{PeerID: "peer0", MSPID: "Org1MSP"}
+	d.Register("failing", failing, time.Second)
+	d.Register("all", all, time.Second)
+	d.Register("join-only", joinOnly, time.Second, string(ChannelJoined))
+
+	<-d.Dispatch(ChannelJoined, "mychannel", 0)
+	<-d.Dispatch(FirstBlockCommitted, "mychannel", 1)
+
+	require.Len(t, failing.Events(), 2)
+	require.Len(t, all.Events(), 2)
+	assert.Equal(t, ChannelJoined, all.Events()[0].Type)
+	assert.Equal(t, FirstBlockCommitted, all.Events()[1].Type)
+	assert.Equal(t, uint64(1), all.Events()[1].BlockNumber)
+	assert.Equal(t, "peer0", all.Events()[1].PeerID)
+	assert.Equal(t, "Org1MSP", all.Events()[1].MSPID)
+	require.Len(t, joinOnly.Events(), 1)
+	assert.Equal(t, ChannelJoined, joinOnly.Events()[0].Type)
+}
+
+func TestDispatcherBlockCommitted(t *testing.T) {
+	hook := &recordingHook{}
+	d := &Dispatcher{}
+	d.Register("hook", hook, time.Second)
+
+	d.BlockCommitted("mychannel", 2)
+	<-d.Dispatch(ChannelJoined, "sentinel", 0)
+	require.Len(t, hook.Events(), 1)
+
+	d.BlockCommitted("mychannel", 1)
+	assert.Eventually(t, func() bool { return len(hook.Events()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, FirstBlockCommitted, hook.Events()[1].Type)
+}
+
+func TestNilDispatcher(t *testing.T) {
+	var d *Dispatcher
+	select {
+	case <-d.Dispatch(ChannelJoined, "mychannel", 0):
+	default:
+		t.Fatal("nil dispatcher should complete immediately")
+	}
+}
+
+func TestNewDispatcher(t *testing.T) {
+	d, err := NewDispatcher("peer0", "Org1MSP", []peer.ChannelHook{
+		{Name: "exec", Exec: "/path/to/hook", Events: []string{"channel-joined"}, Timeout: "5s"},
+		{Name: "grpc", GRPCAddress: "127.0.0.1:7070"},
+	})
+	require.NoError(t, err)
+	require.Len(t, d.hooks, 2)
+	assert.Equal(t, &ExecHook{Path: "/path/to/hook"}, d.hooks[0].hook)
+	assert.Equal(t, 5*time.Second, d.hooks[0].timeout)
+	assert.Contains(t, d.hooks[0].events, ChannelJoined)
+	assert.Equal(t, DefaultTimeout, d.hooks[1].timeout)
+	assert.Nil(t, d.hooks[1].events)
+
+	_, err = NewDispatcher("peer0", "Org1MSP", []peer.ChannelHook{{Name: "bad", Exec: "/path", Timeout: "soon"}})
+	assert.EqualError(t, err, `invalid timeout for channel hook bad: time: invalid duration "soon"`)
+
+	_, err = NewDispatcher("peer0", "Org1MSP", []peer.ChannelHook{{Name: "empty"}})
+	assert.EqualError(t, err, "channel hook empty has neither exec nor grpcAddress")
+
+	_, err = NewDispatcher("peer0", "Org1MSP", []peer.ChannelHook{{Name: "tls", GRPCAddress: "127.0.0.1:7070", GRPCRootCert: "/nonexistent"}})
+	assert.EqualError(t, err, "could not read root certificate of channel hook tls: open /nonexistent: no such file or directory")
+}
+
+func TestEventJSON(t *testing.T) {
+	ts := time.Unix(0, 0).UTC()
+	b, err := json.Marshal(&Event{Type: ChannelJoined, ChannelID: "mychannel", Timestamp: ts})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"channel-joined","channel_id":"mychannel","block_number":0,"peer_id":"","mspid":"","timestamp":"1970-01-01T00:00:00Z"}`, string(b))
+}
@@ -24,6 +24,9 @@ import (
 	"github.com/cetcxinlian/cryptogm/tls"
 	"io/ioutil"
 	"net"
+	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"time"
@@ -44,6 +47,50 @@ type ExternalBuilder struct {
 	PropagateEnvironment []string `yaml:"propagateEnvironment"`
 	Name                 string   `yaml:"name"`
 	Path                 string   `yaml:"path"`
+	// Version identifies the outputs of the builder in the chaincode build
+	// cache and must change when the builder produces different outputs.
+	Version string `yaml:"version"`
+}
+
+// ProcessLauncherPlatform represents the configuration structure of a
+// chaincode type built and launched by the native process launcher
+type ProcessLauncherPlatform struct {
+	Type                 string   `yaml:"type"`
+	BuildCommand         string   `yaml:"buildCommand"`
+	PropagateEnvironment []string `yaml:"propagateEnvironment"`
+}
+
+// ChannelHook represents the configuration structure of a hook notified of
+// channel lifecycle events
+type ChannelHook struct {
+	Name                 string   `yaml:"name"`
+	Events               []string `yaml:"events"`
+	Exec                 string   `yaml:"exec"`
+	GRPCAddress          string   `yaml:"grpcAddress"`
+	GRPCRootCert         string   `yaml:"grpcRootCert"`
+	Timeout              string   `yaml:"timeout"`
+	PropagateEnvironment []string `yaml:"propagateEnvironment"`
+}
+
+// Trigger represents the configuration structure of a commit-time state
+// trigger, whose actions run for the writes of the valid transactions to the
+// keys of a namespace matching its patterns
+type Trigger struct {
+	Name      string          `yaml:"name"`
+	Channel   string          `yaml:"channel"`
+	Namespace string          `yaml:"namespace"`
+	Key       string          `yaml:"key"`
+	Actions   []TriggerAction `yaml:"actions"`
+}
+
+// TriggerAction represents the configuration structure of an action of a
+// trigger, of type event, derivedKey or webhook
+type TriggerAction struct {
+	Type       string `yaml:"type"`
+	DerivedKey string `yaml:"derivedKey"`
+	URL        string `yaml:"url"`
+	RootCert   string `yaml:"rootCert"`
+	Timeout    string `yaml:"timeout"`
 }
 
 // Config is the struct that defines the Peer configurations.
@@ -123,6 +170,39 @@ type Config struct {
 	// registered to deliver service for blocks and transaction events.
 	LimitsConcurrencyDeliverService int
 
+	// ----- Endorser streaming -----
+
+	// EndorserStreamingEnabled enables the streaming endorser service, which
+	// sends proposal responses to clients in chunks.
+	EndorserStreamingEnabled bool
+	// EndorserStreamingChunkSize is the size, in bytes, of the chunks of a
+	// streamed proposal response. A default size is used when it is zero.
+	EndorserStreamingChunkSize int
+
+	// ----- Query cache -----
+
+	// QueryCacheSize is the number of results of read-only proposals cached
+	// by the endorser. The cache is disabled when it is zero.
+	QueryCacheSize int
+	// QueryCacheTTL is the time after which a cached result expires even if
+	// the state it read was not updated. Results don't expire when it is zero.
+	QueryCacheTTL time.Duration
+
+	// ----- Admission control -----
+
+	// AdmissionCommitBacklog is the number of blocks waiting to be committed
+	// above which new proposals are rejected. It is not enforced when zero.
+	AdmissionCommitBacklog int
+	// AdmissionGoroutines is the number of goroutines above which new
+	// proposals are rejected. It is not enforced when zero.
+	AdmissionGoroutines int
+	// AdmissionMaxHeapSize is the heap size in bytes above which new
+	// proposals are rejected. It is not enforced when zero.
+	AdmissionMaxHeapSize uint64
+	// AdmissionInterval is the interval at which the resource usage of the
+	// peer is sampled.
+	AdmissionInterval time.Duration
+
 	// ----- TLS -----
 	// Require server-side TLS.
 	// TODO: create separate sub-struct for PeerTLS config.
@@ -139,6 +219,15 @@ type Config struct {
 	// server time and client's time as specified in a client request message.
 	AuthenticationTimeWindow time.Duration
 
+	// DelegationTokensEnabled accepts the deliver requests of the clients
+	// presenting a delegation token in place of their own identity.
+	DelegationTokensEnabled bool
+	// DelegationTokenIssuerPolicy is the channel policy the issuers of the
+	// delegation tokens must satisfy.
+	DelegationTokenIssuerPolicy string
+	// DelegationTokenMaxLifetime bounds the lifetime of the delegation tokens.
+	DelegationTokenMaxLifetime time.Duration
+
 	// Endpoint of the vm management system. For docker can be one of the following in general
 	// unix:///var/run/docker.sock
 	// http://localhost:2375
@@ -160,6 +249,81 @@ type Config struct {
 	// chaincode. The external builder detection processing will iterate over the
 	// builders in the order specified below.
 	ExternalBuilders []ExternalBuilder
+	// ChaincodeBuildCacheEnabled enables reusing the outputs of the builds of
+	// the same chaincode package by the same version of a builder.
+	ChaincodeBuildCacheEnabled bool
+	// ChaincodeBuildCachePath is the directory caching the outputs of the
+	// external builders. It may be shared by several peers. It defaults to the
+	// buildcache directory of the peer file system path.
+	ChaincodeBuildCachePath string
+
+	// ----- Process launcher config -----
+
+	// ProcessLauncherEnabled enables building and launching chaincode as
+	// processes of the peer host instead of docker containers.
+	ProcessLauncherEnabled bool
+	// ProcessLauncherPlatforms represents the chaincode types built by the
+	// process launcher, along with their build commands.
+	ProcessLauncherPlatforms []ProcessLauncherPlatform
+	// ProcessLauncherUID and ProcessLauncherGID are the user and group
+	// chaincode processes run as, which must differ from the peer's.
+	ProcessLauncherUID uint32
+	ProcessLauncherGID uint32
+	// ProcessLauncherNamespaces runs chaincode processes in their own PID,
+	// IPC, UTS and mount namespaces.
+	ProcessLauncherNamespaces bool
+	// ProcessLauncherCgroupRoot is the cgroup v2 directory beneath which a
+	// cgroup is created for each chaincode process.
+	ProcessLauncherCgroupRoot string
+	// ProcessLauncherMemoryLimit is the memory limit of a chaincode process, in bytes.
+	// It may be configured with a size suffix, e.g. 512mb.
+	ProcessLauncherMemoryLimit int64
+	// ProcessLauncherCPUQuota is the CPU time, in microseconds, a chaincode
+	// process may use per ProcessLauncherCPUPeriod.
+	ProcessLauncherCPUQuota int64
+	// ProcessLauncherCPUPeriod is the CPU period, in microseconds.
+	ProcessLauncherCPUPeriod int64
+	// ProcessLauncherPidsLimit is the maximum number of processes and threads
+	// of a chaincode.
+	ProcessLauncherPidsLimit int64
+
+	// ChannelHooks represents the hooks notified when the peer joins a
+	// channel and when the first block of a channel is committed.
+	ChannelHooks []ChannelHook
+	// Triggers represents the commit-time state triggers evaluated on the
+	// blocks committed to the ledgers of the peer.
+	Triggers []Trigger
+	// IdemixAuditEnabled enables the audit of the transactions of idemix
+	// identities: the peer returns the audit escrows of their creators to the
+	// auditors authorized by the channel config, recording the requests on
+	// its audit trail.
+	IdemixAuditEnabled bool
+
+	// CDCEnabled enables the export of the state changes committed to the
+	// ledgers of the peer to the change data capture sink.
+	CDCEnabled bool
+	// CDCSink is the type of the sink the changes are exported to: file,
+	// kafka or grpc.
+	CDCSink string
+	// CDCChannels restricts the export to the given channels; all the
+	// channels are exported when empty.
+	CDCChannels []string
+	// CDCNamespaces restricts the export to the namespaces matching the given
+	// patterns; all the namespaces are exported when empty.
+	CDCNamespaces []string
+	// CDCRetryInterval is the time waited before a failed delivery is retried.
+	CDCRetryInterval time.Duration
+	// CDCFile is the file the file sink appends the changes to.
+	CDCFile string
+	// CDCKafkaBrokers are the brokers of the Kafka sink.
+	CDCKafkaBrokers []string
+	// CDCKafkaTopic is the topic the Kafka sink publishes the changes to.
+	CDCKafkaTopic string
+	// CDCGRPCAddress is the address of the server of the gRPC sink.
+	CDCGRPCAddress string
+	// CDCGRPCRootCert is the root certificate of the server of the gRPC sink,
+	// which is reached over TLS when it is set.
+	CDCGRPCRootCert string
 
 	// ----- Operations config -----
 	// TODO: create separate sub-struct for Operations config.
@@ -180,6 +344,12 @@ type Config struct {
 	// OperationsTLSClientRootCAs provides the path to PEM encoded ca certiricates to
 	// trust for client authentication.
 	OperationsTLSClientRootCAs []string
+	// OperationsLogSinkDirectories are the directories the file log sinks
+	// added through the operations endpoint may write to.
+	OperationsLogSinkDirectories []string
+	// OperationsLogSinkHosts are the hosts the syslog and remote log sinks
+	// added through the operations endpoint may connect to.
+	OperationsLogSinkHosts []string
 
 	// ----- Metrics config -----
 	// TODO: create separate sub-struct for Metrics config.
@@ -197,6 +367,17 @@ type Config struct {
 	// StatsdPrefix provides the prefix that prepended to all emitted statsd metrics.
 	StatsdPrefix string
 
+	// ----- Tracing config -----
+
+	// TracingEnabled enables the export of the spans recording the processing of
+	// transactions.
+	TracingEnabled bool
+	// TracingEndpoint provides the URL of the OTLP/HTTP traces endpoint of the
+	// OpenTelemetry collector.
+	TracingEndpoint string
+	// TracingFlushInterval sets the maximum time a span waits before it is exported.
+	TracingFlushInterval time.Duration
+
 	// ----- Docker config ------
 
 	// DockerCert is the path to the PEM encoded TLS client certificate required to access
@@ -238,10 +419,33 @@ func (c *Config) load() error {
 		c.AuthenticationTimeWindow = defaultTimeWindow
 	}
 
+	c.DelegationTokensEnabled = viper.GetBool("peer.authentication.delegationTokens.enabled")
+	if c.DelegationTokensEnabled {
+		c.DelegationTokenIssuerPolicy = viper.GetString("peer.authentication.delegationTokens.issuerPolicy")
+		if c.DelegationTokenIssuerPolicy == "" {
+			c.DelegationTokenIssuerPolicy = "/Channel/Application/Admins"
+		}
+		c.DelegationTokenMaxLifetime = viper.GetDuration("peer.authentication.delegationTokens.maxLifetime")
+		if c.DelegationTokenMaxLifetime == 0 {
+			c.DelegationTokenMaxLifetime = time.Hour
+		}
+	}
+
 	c.PeerTLSEnabled = viper.GetBool("peer.tls.enabled")
 	c.NetworkID = viper.GetString("peer.networkId")
 	c.LimitsConcurrencyEndorserService = viper.GetInt("peer.limits.concurrency.endorserService")
 	c.LimitsConcurrencyDeliverService = viper.GetInt("peer.limits.concurrency.deliverService")
+	c.EndorserStreamingEnabled = viper.GetBool("peer.endorserStreaming.enabled")
+	c.EndorserStreamingChunkSize = int(viper.GetSizeInBytes("peer.endorserStreaming.chunkSize"))
+	c.QueryCacheSize = viper.GetInt("peer.queryCache.size")
+	c.QueryCacheTTL = viper.GetDuration("peer.queryCache.ttl")
+	c.AdmissionCommitBacklog = viper.GetInt("peer.admission.commitBacklog")
+	c.AdmissionGoroutines = viper.GetInt("peer.admission.goroutines")
+	c.AdmissionMaxHeapSize = uint64(viper.GetSizeInBytes("peer.admission.maxHeapSize"))
+	c.AdmissionInterval = viper.GetDuration("peer.admission.interval")
+	if c.AdmissionInterval <= 0 {
+		c.AdmissionInterval = time.Second
+	}
 	c.DiscoveryEnabled = viper.GetBool("peer.discovery.enabled")
 	c.ProfileEnabled = viper.GetBool("peer.profile.enabled")
 	c.ProfileListenAddress = viper.GetString("peer.profile.listenAddress")
@@ -293,6 +497,140 @@ func (c *Config) load() error {
 		}
 	}
 
+	c.ChaincodeBuildCacheEnabled = viper.GetBool("chaincode.buildCache.enabled")
+	if c.ChaincodeBuildCacheEnabled {
+		c.ChaincodeBuildCachePath = config.GetPath("chaincode.buildCache.path")
+		if c.ChaincodeBuildCachePath == "" {
+			c.ChaincodeBuildCachePath = filepath.Join(config.GetPath("peer.fileSystemPath"), "buildcache")
+		}
+	}
+
+	c.ProcessLauncherEnabled = viper.GetBool("chaincode.processLauncher.enabled")
+	if c.ProcessLauncherEnabled {
+		var platforms []ProcessLauncherPlatform
+		err = viper.UnmarshalKey("chaincode.processLauncher.platforms", &platforms)
+		if err != nil {
+			return err
+		}
+		for _, platform := range platforms {
+			if platform.Type == "" {
+				return fmt.Errorf("invalid process launcher configuration, type attribute missing in one or more platforms")
+			}
+			if platform.BuildCommand == "" {
+				return fmt.Errorf("process launcher platform %s has no buildCommand attribute", platform.Type)
+			}
+		}
+		c.ProcessLauncherPlatforms = platforms
+		if !viper.IsSet("chaincode.processLauncher.user.uid") || !viper.IsSet("chaincode.processLauncher.user.gid") {
+			return fmt.Errorf("process launcher requires the uid and gid of the user chaincode processes run as")
+		}
+		c.ProcessLauncherUID = uint32(viper.GetInt("chaincode.processLauncher.user.uid"))
+		c.ProcessLauncherGID = uint32(viper.GetInt("chaincode.processLauncher.user.gid"))
+		if int(c.ProcessLauncherUID) == os.Getuid() {
+			return fmt.Errorf("process launcher user %d must not be the user of the peer", c.ProcessLauncherUID)
+		}
+		c.ProcessLauncherNamespaces = viper.GetBool("chaincode.processLauncher.namespaces")
+		c.ProcessLauncherCgroupRoot = viper.GetString("chaincode.processLauncher.cgroup.root")
+		c.ProcessLauncherMemoryLimit = int64(viper.GetSizeInBytes("chaincode.processLauncher.cgroup.memoryLimit"))
+		c.ProcessLauncherCPUQuota = int64(viper.GetInt("chaincode.processLauncher.cgroup.cpuQuota"))
+		c.ProcessLauncherCPUPeriod = int64(viper.GetInt("chaincode.processLauncher.cgroup.cpuPeriod"))
+		c.ProcessLauncherPidsLimit = int64(viper.GetInt("chaincode.processLauncher.cgroup.pidsLimit"))
+	}
+
+	var channelHooks []ChannelHook
+	err = viper.UnmarshalKey("peer.channelHooks", &channelHooks)
+	if err != nil {
+		return err
+	}
+	for _, hook := range channelHooks {
+		if hook.Name == "" {
+			return fmt.Errorf("invalid channel hook configuration, name attribute missing in one or more hooks")
+		}
+		if hook.Exec == "" && hook.GRPCAddress == "" {
+			return fmt.Errorf("channel hook %s has neither exec nor grpcAddress attribute", hook.Name)
+		}
+		if hook.Exec != "" && hook.GRPCAddress != "" {
+			return fmt.Errorf("channel hook %s has both exec and grpcAddress attributes", hook.Name)
+		}
+	}
+	c.ChannelHooks = channelHooks
+
+	var triggers []Trigger
+	err = viper.UnmarshalKey("peer.triggers", &triggers)
+	if err != nil {
+		return err
+	}
+	for i, trigger := range triggers {
+		if trigger.Name == "" {
+			return fmt.Errorf("invalid trigger configuration, name attribute missing in one or more triggers")
+		}
+		if trigger.Namespace == "" {
+			return fmt.Errorf("trigger %s has no namespace attribute", trigger.Name)
+		}
+		for _, pattern := range []string{trigger.Namespace, trigger.Key} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("trigger %s has an invalid pattern '%s'", trigger.Name, pattern)
+			}
+		}
+		if len(trigger.Actions) == 0 {
+			return fmt.Errorf("trigger %s has no actions", trigger.Name)
+		}
+		for j, action := range trigger.Actions {
+			switch action.Type {
+			case "event", "derivedKey":
+			case "webhook":
+				if action.URL == "" {
+					return fmt.Errorf("webhook action of trigger %s has no url attribute", trigger.Name)
+				}
+				if u, err := url.Parse(action.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+					return fmt.Errorf("webhook action of trigger %s has an invalid url '%s', an https url is required", trigger.Name, action.URL)
+				}
+				if action.RootCert != "" {
+					triggers[i].Actions[j].RootCert = config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), action.RootCert)
+				}
+			default:
+				return fmt.Errorf("trigger %s has an action of unknown type '%s'", trigger.Name, action.Type)
+			}
+		}
+	}
+	c.Triggers = triggers
+
+	c.IdemixAuditEnabled = viper.GetBool("peer.idemixAudit.enabled")
+
+	c.CDCEnabled = viper.GetBool("peer.cdc.enabled")
+	if c.CDCEnabled {
+		c.CDCSink = viper.GetString("peer.cdc.sink")
+		c.CDCChannels = viper.GetStringSlice("peer.cdc.channels")
+		c.CDCNamespaces = viper.GetStringSlice("peer.cdc.namespaces")
+		for _, pattern := range c.CDCNamespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid change data capture namespace pattern '%s'", pattern)
+			}
+		}
+		c.CDCRetryInterval = viper.GetDuration("peer.cdc.retryInterval")
+		switch c.CDCSink {
+		case "file":
+			c.CDCFile = config.GetPath("peer.cdc.file.path")
+			if c.CDCFile == "" {
+				return fmt.Errorf("change data capture file sink has no path attribute")
+			}
+		case "kafka":
+			c.CDCKafkaBrokers = viper.GetStringSlice("peer.cdc.kafka.brokers")
+			c.CDCKafkaTopic = viper.GetString("peer.cdc.kafka.topic")
+			if len(c.CDCKafkaBrokers) == 0 || c.CDCKafkaTopic == "" {
+				return fmt.Errorf("change data capture kafka sink requires brokers and topic attributes")
+			}
+		case "grpc":
+			c.CDCGRPCAddress = viper.GetString("peer.cdc.grpc.address")
+			c.CDCGRPCRootCert = config.GetPath("peer.cdc.grpc.rootCert")
+			if c.CDCGRPCAddress == "" {
+				return fmt.Errorf("change data capture grpc sink has no address attribute")
+			}
+		default:
+			return fmt.Errorf("change data capture sink of unknown type '%s'", c.CDCSink)
+		}
+	}
+
 	c.OperationsListenAddress = viper.GetString("operations.listenAddress")
 	c.OperationsTLSEnabled = viper.GetBool("operations.tls.enabled")
 	c.OperationsTLSCertFile = config.GetPath("operations.tls.cert.file")
@@ -302,6 +640,10 @@ func (c *Config) load() error {
 	for _, rca := range viper.GetStringSlice("operations.tls.clientRootCAs.files") {
 		c.OperationsTLSClientRootCAs = append(c.OperationsTLSClientRootCAs, config.TranslatePath(configDir, rca))
 	}
+	for _, dir := range viper.GetStringSlice("operations.logSinks.allowedDirectories") {
+		c.OperationsLogSinkDirectories = append(c.OperationsLogSinkDirectories, config.TranslatePath(configDir, dir))
+	}
+	c.OperationsLogSinkHosts = viper.GetStringSlice("operations.logSinks.allowedHosts")
 
 	c.MetricsProvider = viper.GetString("metrics.provider")
 	c.StatsdNetwork = viper.GetString("metrics.statsd.network")
@@ -309,6 +651,10 @@ func (c *Config) load() error {
 	c.StatsdWriteInterval = viper.GetDuration("metrics.statsd.writeInterval")
 	c.StatsdPrefix = viper.GetString("metrics.statsd.prefix")
 
+	c.TracingEnabled = viper.GetBool("tracing.enabled")
+	c.TracingEndpoint = viper.GetString("tracing.endpoint")
+	c.TracingFlushInterval = viper.GetDuration("tracing.flushInterval")
+
 	c.DockerCert = config.GetPath("vm.docker.tls.cert.file")
 	c.DockerKey = config.GetPath("vm.docker.tls.key.file")
 	c.DockerCA = config.GetPath("vm.docker.tls.ca.file")
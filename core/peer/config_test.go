@@ -7,6 +7,7 @@ package peer
 
 import (
 	"crypto/tls"
+	"fmt"
 	"io/ioutil"
 	"net"
 	"os"
@@ -307,6 +308,10 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("metrics.statsd.writeInterval", "10s")
 	viper.Set("metrics.statsd.prefix", "testPrefix")
 
+	viper.Set("tracing.enabled", true)
+	viper.Set("tracing.endpoint", "http://127.0.0.1:4318/v1/traces")
+	viper.Set("tracing.flushInterval", "5s")
+
 	viper.Set("chaincode.pull", false)
 	viper.Set("chaincode.externalBuilders", &[]ExternalBuilder{
 		{
@@ -339,6 +344,7 @@ func TestGlobalConfig(t *testing.T) {
 		DiscoveryAuthCacheEnabled:             true,
 		DiscoveryAuthCacheMaxSize:             1000,
 		DiscoveryAuthCachePurgeRetentionRatio: 0.75,
+		AdmissionInterval:                     time.Second,
 		ChaincodeListenAddress:                "0.0.0.0:7052",
 		ChaincodeAddress:                      "0.0.0.0:7052",
 		ValidatorPoolSize:                     1,
@@ -376,6 +382,10 @@ func TestGlobalConfig(t *testing.T) {
 		StatsdWriteInterval: 10 * time.Second,
 		StatsdPrefix:        "testPrefix",
 
+		TracingEnabled:       true,
+		TracingEndpoint:      "http://127.0.0.1:4318/v1/traces",
+		TracingFlushInterval: 5 * time.Second,
+
 		DockerCert: filepath.Join(cwd, "test/vm/tls/cert/file"),
 		DockerKey:  filepath.Join(cwd, "test/vm/tls/key/file"),
 		DockerCA:   filepath.Join(cwd, "test/vm/tls/ca/file"),
@@ -397,6 +407,7 @@ func TestGlobalConfigDefault(t *testing.T) {
 		ValidatorPoolSize:             runtime.NumCPU(),
 		VMNetworkMode:                 "host",
 		DeliverClientKeepaliveOptions: comm.DefaultKeepaliveOptions,
+		AdmissionInterval:             time.Second,
 	}
 
 	assert.Equal(t, expectedConfig, coreConfig)
@@ -432,6 +443,7 @@ func TestPropagateEnvironment(t *testing.T) {
 		ValidatorPoolSize:             runtime.NumCPU(),
 		VMNetworkMode:                 "host",
 		DeliverClientKeepaliveOptions: comm.DefaultKeepaliveOptions,
+		AdmissionInterval:             time.Second,
 		ExternalBuilders: []ExternalBuilder{
 			{
 				Name:                 "testName",
@@ -478,3 +490,302 @@ func TestMissingExternalBuilderName(t *testing.T) {
 	_, err := GlobalConfig()
 	assert.EqualError(t, err, "external builder at path relative/plugin_dir has no name attribute")
 }
+
+func TestProcessLauncherConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("chaincode.processLauncher.enabled", true)
+	viper.Set("chaincode.processLauncher.user.uid", 10001)
+	viper.Set("chaincode.processLauncher.user.gid", 10002)
+	viper.Set("chaincode.processLauncher.namespaces", true)
+	viper.Set("chaincode.processLauncher.cgroup.root", "/sys/fs/cgroup/fabric")
+	viper.Set("chaincode.processLauncher.cgroup.memoryLimit", "512mb")
+	viper.Set("chaincode.processLauncher.cgroup.cpuQuota", 50000)
+	viper.Set("chaincode.processLauncher.cgroup.cpuPeriod", 100000)
+	viper.Set("chaincode.processLauncher.cgroup.pidsLimit", 64)
+	viper.Set("chaincode.processLauncher.platforms", &[]ProcessLauncherPlatform{
+		{
+			Type:                 "golang",
+			BuildCommand:         "/path/to/build",
+			PropagateEnvironment: []string{"GOPATH"},
+		},
+	})
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+
+	expectedConfig := &Config{
+		AuthenticationTimeWindow:      15 * time.Minute,
+		PeerAddress:                   "localhost:8080",
+		ValidatorPoolSize:             runtime.NumCPU(),
+		VMNetworkMode:                 "host",
+		DeliverClientKeepaliveOptions: comm.DefaultKeepaliveOptions,
+		AdmissionInterval:             time.Second,
+		ProcessLauncherEnabled:        true,
+		ProcessLauncherPlatforms: []ProcessLauncherPlatform{
+			{
+				Type:                 "golang",
+				BuildCommand:         "/path/to/build",
+				PropagateEnvironment: []string{"GOPATH"},
+			},
+		},
+		ProcessLauncherUID:         10001,
+		ProcessLauncherGID:         10002,
+		ProcessLauncherNamespaces:  true,
+		ProcessLauncherCgroupRoot:  "/sys/fs/cgroup/fabric",
+		ProcessLauncherMemoryLimit: 512 * 1024 * 1024,
+		ProcessLauncherCPUQuota:    50000,
+		ProcessLauncherCPUPeriod:   100000,
+		ProcessLauncherPidsLimit:   64,
+	}
+	assert.Equal(t, expectedConfig, coreConfig)
+}
+
+func TestChaincodeBuildCacheConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("peer.fileSystemPath", "/var/hyperledger/production")
+	viper.Set("chaincode.buildCache.path", "/shared/buildcache")
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.False(t, coreConfig.ChaincodeBuildCacheEnabled)
+	assert.Empty(t, coreConfig.ChaincodeBuildCachePath)
+
+	viper.Set("chaincode.buildCache.enabled", true)
+	coreConfig, err = GlobalConfig()
+	assert.NoError(t, err)
+	assert.True(t, coreConfig.ChaincodeBuildCacheEnabled)
+	assert.Equal(t, "/shared/buildcache", coreConfig.ChaincodeBuildCachePath)
+
+	viper.Set("chaincode.buildCache.path", "")
+	coreConfig, err = GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/hyperledger/production/buildcache", coreConfig.ChaincodeBuildCachePath)
+}
+
+func TestMissingProcessLauncherBuildCommand(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("chaincode.processLauncher.enabled", true)
+	viper.Set("chaincode.processLauncher.platforms", &[]ProcessLauncherPlatform{
+		{
+			Type: "golang",
+		},
+	})
+	_, err := GlobalConfig()
+	assert.EqualError(t, err, "process launcher platform golang has no buildCommand attribute")
+
+	viper.Set("chaincode.processLauncher.platforms", &[]ProcessLauncherPlatform{
+		{
+			BuildCommand: "/path/to/build",
+		},
+	})
+	_, err = GlobalConfig()
+	assert.EqualError(t, err, "invalid process launcher configuration, type attribute missing in one or more platforms")
+}
+
+func TestEndorserStreamingConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("peer.endorserStreaming.enabled", true)
+	viper.Set("peer.endorserStreaming.chunkSize", "64kb")
+
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.True(t, coreConfig.EndorserStreamingEnabled)
+	assert.Equal(t, 64*1024, coreConfig.EndorserStreamingChunkSize)
+}
+
+func TestQueryCacheConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, coreConfig.QueryCacheSize)
+
+	viper.Set("peer.queryCache.size", 1000)
+	viper.Set("peer.queryCache.ttl", "30s")
+	coreConfig, err = GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, coreConfig.QueryCacheSize)
+	assert.Equal(t, 30*time.Second, coreConfig.QueryCacheTTL)
+}
+
+func TestAdmissionConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, coreConfig.AdmissionCommitBacklog)
+	assert.Equal(t, 0, coreConfig.AdmissionGoroutines)
+	assert.Equal(t, uint64(0), coreConfig.AdmissionMaxHeapSize)
+	assert.Equal(t, time.Second, coreConfig.AdmissionInterval)
+
+	viper.Set("peer.admission.commitBacklog", 50)
+	viper.Set("peer.admission.goroutines", 20000)
+	viper.Set("peer.admission.maxHeapSize", "2GB")
+	viper.Set("peer.admission.interval", "500ms")
+	coreConfig, err = GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 50, coreConfig.AdmissionCommitBacklog)
+	assert.Equal(t, 20000, coreConfig.AdmissionGoroutines)
+	assert.Equal(t, uint64(2<<30), coreConfig.AdmissionMaxHeapSize)
+	assert.Equal(t, 500*time.Millisecond, coreConfig.AdmissionInterval)
+}
+
+func TestDelegationTokensConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.False(t, coreConfig.DelegationTokensEnabled)
+	assert.Empty(t, coreConfig.DelegationTokenIssuerPolicy)
+
+	viper.Set("peer.authentication.delegationTokens.enabled", true)
+	coreConfig, err = GlobalConfig()
+	assert.NoError(t, err)
+	assert.True(t, coreConfig.DelegationTokensEnabled)
+	assert.Equal(t, "/Channel/Application/Admins", coreConfig.DelegationTokenIssuerPolicy)
+	assert.Equal(t, time.Hour, coreConfig.DelegationTokenMaxLifetime)
+
+	viper.Set("peer.authentication.delegationTokens.issuerPolicy", "/Channel/Application/Writers")
+	viper.Set("peer.authentication.delegationTokens.maxLifetime", "10m")
+	coreConfig, err = GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "/Channel/Application/Writers", coreConfig.DelegationTokenIssuerPolicy)
+	assert.Equal(t, 10*time.Minute, coreConfig.DelegationTokenMaxLifetime)
+}
+
+func TestChannelHooksConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("peer.channelHooks", &[]ChannelHook{
+		{
+			Name:   "couchdb",
+			Events: []string{"channel-joined"},
+			Exec:   "/path/to/hook",
+		},
+		{
+			Name:        "monitoring",
+			GRPCAddress: "monitoring:7070",
+			Timeout:     "5s",
+		},
+	})
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []ChannelHook{
+		{
+			Name:   "couchdb",
+			Events: []string{"channel-joined"},
+			Exec:   "/path/to/hook",
+		},
+		{
+			Name:        "monitoring",
+			GRPCAddress: "monitoring:7070",
+			Timeout:     "5s",
+		},
+	}, coreConfig.ChannelHooks)
+}
+
+func TestInvalidChannelHooks(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("peer.channelHooks", &[]ChannelHook{
+		{
+			Exec: "/path/to/hook",
+		},
+	})
+	_, err := GlobalConfig()
+	assert.EqualError(t, err, "invalid channel hook configuration, name attribute missing in one or more hooks")
+
+	viper.Set("peer.channelHooks", &[]ChannelHook{
+		{
+			Name: "couchdb",
+		},
+	})
+	_, err = GlobalConfig()
+	assert.EqualError(t, err, "channel hook couchdb has neither exec nor grpcAddress attribute")
+
+	viper.Set("peer.channelHooks", &[]ChannelHook{
+		{
+			Name:        "couchdb",
+			Exec:        "/path/to/hook",
+			GRPCAddress: "couchdb:7070",
+		},
+	})
+	_, err = GlobalConfig()
+	assert.EqualError(t, err, "channel hook couchdb has both exec and grpcAddress attributes")
+}
+
+func TestTriggersConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	triggers := []Trigger{
+		{
+			Name:      "transfers",
+			Channel:   "mychannel",
+			Namespace: "asset*",
+			Key:       "transfer/*",
+			Actions: []TriggerAction{
+				{Type: "event"},
+				{Type: "derivedKey", DerivedKey: "latest/{key}"},
+				{Type: "webhook", URL: "https://notifier:8443/hook", RootCert: "/etc/hyperledger/notifier/ca.pem", Timeout: "3s"},
+			},
+		},
+	}
+	viper.Set("peer.triggers", &triggers)
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, triggers, coreConfig.Triggers)
+}
+
+func TestInvalidTriggers(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+
+	tests := []struct {
+		trigger     Trigger
+		expectedErr string
+	}{
+		{Trigger{Namespace: "cc"}, "invalid trigger configuration, name attribute missing in one or more triggers"},
+		{Trigger{Name: "t"}, "trigger t has no namespace attribute"},
+		{Trigger{Name: "t", Namespace: "cc", Key: "[a"}, "trigger t has an invalid pattern '[a'"},
+		{Trigger{Name: "t", Namespace: "cc"}, "trigger t has no actions"},
+		{Trigger{Name: "t", Namespace: "cc", Actions: []TriggerAction{{Type: "email"}}}, "trigger t has an action of unknown type 'email'"},
+		{Trigger{Name: "t", Namespace: "cc", Actions: []TriggerAction{{Type: "webhook"}}}, "webhook action of trigger t has no url attribute"},
+		{Trigger{Name: "t", Namespace: "cc", Actions: []TriggerAction{{Type: "webhook", URL: "http://notifier:8080/hook"}}}, "webhook action of trigger t has an invalid url 'http://notifier:8080/hook', an https url is required"},
+	}
+	for _, tt := range tests {
+		viper.Set("peer.triggers", &[]Trigger{tt.trigger})
+		_, err := GlobalConfig()
+		assert.EqualError(t, err, tt.expectedErr)
+	}
+}
+
+func TestIdemixAuditConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	coreConfig, err := GlobalConfig()
+	assert.NoError(t, err)
+	assert.False(t, coreConfig.IdemixAuditEnabled)
+
+	viper.Set("peer.idemixAudit.enabled", true)
+	coreConfig, err = GlobalConfig()
+	assert.NoError(t, err)
+	assert.True(t, coreConfig.IdemixAuditEnabled)
+}
+
+func TestProcessLauncherUser(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("chaincode.processLauncher.enabled", true)
+	_, err := GlobalConfig()
+	assert.EqualError(t, err, "process launcher requires the uid and gid of the user chaincode processes run as")
+
+	viper.Set("chaincode.processLauncher.user.uid", os.Getuid())
+	viper.Set("chaincode.processLauncher.user.gid", os.Getgid())
+	_, err = GlobalConfig()
+	assert.EqualError(t, err, fmt.Sprintf("process launcher user %d must not be the user of the peer", os.Getuid()))
+}
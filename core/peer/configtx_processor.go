@@ -11,6 +11,8 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protoutil"
 )
@@ -42,7 +44,29 @@ func (tp *ConfigTxProcessor) GenerateSimulationResults(txEnv *common.Envelope, s
 	}
 }
 
-func retrieveChannelConfig(queryExecuter ledger.QueryExecutor) (*common.Config, error) {
+// CommutativeCountersEnabled implements function in the interface 'github.com/hyperledger/fabric/core/ledger/CounterCapabilityProvider'
+// This implementation looks up the channel capability in the config-envelope-bytes stored by the CONFIG transactions
+func (tp *ConfigTxProcessor) CommutativeCountersEnabled(qe ledger.SimpleQueryExecutor) (bool, error) {
+	conf, err := retrieveChannelConfig(qe)
+	if err != nil {
+		return false, err
+	}
+	if conf == nil || conf.ChannelGroup == nil {
+		return false, nil
+	}
+	capabilitiesValue, ok := conf.ChannelGroup.Values[channelconfig.CapabilitiesKey]
+	if !ok {
+		return false, nil
+	}
+	channelCapabilities := &common.Capabilities{}
+	if err := proto.Unmarshal(capabilitiesValue.Value, channelCapabilities); err != nil {
+		return false, err
+	}
+	_, ok = channelCapabilities.Capabilities[capabilities.ChannelCommutativeCounters]
+	return ok, nil
+}
+
+func retrieveChannelConfig(queryExecuter ledger.SimpleQueryExecutor) (*common.Config, error) {
 	configBytes, err := queryExecuter.GetState(peerNamespace, channelConfigKey)
 	if err != nil {
 		return nil, err
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -20,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/core/ledger"
+	ledgermocks "github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
 	"github.com/hyperledger/fabric/protoutil"
@@ -70,6 +72,49 @@ func TestConfigTxErrorScenarios(t *testing.T) {
 	require.EqualError(t, err, "channel config found nil")
 }
 
+func TestConfigTxCommutativeCountersEnabled(t *testing.T) {
+	configTxProcessor := &ConfigTxProcessor{}
+	qe := &ledgermocks.QueryExecutor{}
+
+	// no channel config committed yet
+	enabled, err := configTxProcessor.CommutativeCountersEnabled(qe)
+	require.NoError(t, err)
+	require.False(t, enabled)
+
+	channelConfig := func(capabilityNames ...string) []byte {
+		caps := &common.Capabilities{Capabilities: map[string]*common.Capability{}}
+		for _, name := range capabilityNames {
+			caps.Capabilities[name] = &common.Capability{}
+		}
+		return protoutil.MarshalOrPanic(&common.ConfigEnvelope{
+			Config: &common.Config{
+				ChannelGroup: &common.ConfigGroup{
+					Values: map[string]*common.ConfigValue{
+						channelconfig.CapabilitiesKey: {Value: protoutil.MarshalOrPanic(caps)},
+					},
+				},
+			},
+		})
+	}
+
+	qe.GetStateReturns(channelConfig(capabilities.ChannelV2_0), nil)
+	enabled, err = configTxProcessor.CommutativeCountersEnabled(qe)
+	require.NoError(t, err)
+	require.False(t, enabled)
+	ns, key := qe.GetStateArgsForCall(1)
+	require.Equal(t, peerNamespace, ns)
+	require.Equal(t, channelConfigKey, key)
+
+	qe.GetStateReturns(channelConfig(capabilities.ChannelV2_0, capabilities.ChannelCommutativeCounters), nil)
+	enabled, err = configTxProcessor.CommutativeCountersEnabled(qe)
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	qe.GetStateReturns(nil, errors.New("leveldb is closed"))
+	_, err = configTxProcessor.CommutativeCountersEnabled(qe)
+	require.EqualError(t, err, "leveldb is closed")
+}
+
 func TestConfigTxUpdateChanConfig(t *testing.T) {
 	helper := newTestHelper(t)
 	channelID := "testchain1"
@@ -36,6 +36,14 @@ type DeliverServer struct {
 	PolicyCheckerProvider   PolicyCheckerProvider
 	CollectionPolicyChecker CollectionPolicyChecker
 	IdentityDeserializerMgr IdentityDeserializerManager
+	// ChaincodeEventPolicyChecker restricts the delivery of the events of the
+	// chaincodes with an event/ChaincodeEvents/<chaincode name> ACL in filtered
+	// blocks. It may be nil.
+	ChaincodeEventPolicyChecker deliver.ChaincodeEventPolicyChecker
+	// DelegationTokens authenticates the clients of the Deliver and
+	// DeliverFiltered services presenting a delegation token. It may be nil,
+	// and is not used for the delivery of private data.
+	DelegationTokens *deliver.TokenAuthenticator
 }
 
 // Chain adds Ledger() to deliver.Chain
@@ -126,6 +134,28 @@ func (fbrs *filteredBlockResponseSender) SendBlockResponse(
 	return fbrs.Send(response)
 }
 
+// SendRestrictedBlockResponse generates deliver response with a filtered block
+// holding the chaincode events allowed by the access. The transactions without
+// any allowed chaincode event are left out of the filtered block when the
+// access is restricted to the events of some chaincodes.
+func (fbrs *filteredBlockResponseSender) SendRestrictedBlockResponse(
+	block *common.Block,
+	channelID string,
+	access *deliver.ChaincodeEventAccess,
+) error {
+	b := blockEvent(*block)
+	filteredBlock, err := b.toFilteredBlock()
+	if err != nil {
+		logger.Warningf("Failed to generate filtered block due to: %s", err)
+		return fbrs.SendStatusResponse(common.Status_BAD_REQUEST)
+	}
+	restrictChaincodeEvents(filteredBlock, access)
+	response := &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock},
+	}
+	return fbrs.Send(response)
+}
+
 func (fbrs *filteredBlockResponseSender) DataType() string {
 	return "filtered_block"
 }
@@ -247,6 +277,8 @@ func (s *DeliverServer) DeliverFiltered(srv peer.Deliver_DeliverFilteredServer)
 		ResponseSender: &filteredBlockResponseSender{
 			Deliver_DeliverFilteredServer: srv,
 		},
+		ChaincodeEventPolicyChecker: s.ChaincodeEventPolicyChecker,
+		TokenAuthenticator:          s.DelegationTokens,
 	}
 	return s.DeliverHandler.Handle(srv.Context(), deliverServer)
 }
@@ -262,6 +294,7 @@ func (s *DeliverServer) Deliver(srv peer.Deliver_DeliverServer) (err error) {
 		ResponseSender: &blockResponseSender{
 			Deliver_DeliverServer: srv,
 		},
+		TokenAuthenticator: s.DelegationTokens,
 	}
 	return s.DeliverHandler.Handle(srv.Context(), deliverServer)
 }
@@ -353,6 +386,29 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	return filteredBlock, nil
 }
 
+// restrictChaincodeEvents removes the chaincode events which are not allowed
+// from the filtered block.
+func restrictChaincodeEvents(filteredBlock *peer.FilteredBlock, access *deliver.ChaincodeEventAccess) {
+	var filteredTransactions []*peer.FilteredTransaction
+	for _, filteredTransaction := range filteredBlock.FilteredTransactions {
+		eventAllowed := false
+		if actions := filteredTransaction.GetTransactionActions(); actions != nil {
+			var chaincodeActions []*peer.FilteredChaincodeAction
+			for _, action := range actions.ChaincodeActions {
+				if access.Allowed(action.ChaincodeEvent.GetChaincodeId()) {
+					chaincodeActions = append(chaincodeActions, action)
+				}
+			}
+			eventAllowed = len(chaincodeActions) > 0
+			actions.ChaincodeActions = chaincodeActions
+		}
+		if access.Unrestricted || eventAllowed {
+			filteredTransactions = append(filteredTransactions, filteredTransaction)
+		}
+	}
+	filteredBlock.FilteredTransactions = filteredTransactions
+}
+
 func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_TransactionActions, error) {
 	transactionActions := &peer.FilteredTransactionActions{}
 	for _, action := range ta {
@@ -199,6 +199,65 @@ func TestFilteredBlockResponseSenderIsFiltered(t *testing.T) {
 	assert.True(t, filtered.IsFiltered(), "should return true from IsFiltered")
 }
 
+func TestRestrictChaincodeEvents(t *testing.T) {
+	filteredTx := func(txID string, chaincodeNames ...string) *peer.FilteredTransaction {
+		actions := &peer.FilteredTransactionActions{}
+		for _, chaincodeName := range chaincodeNames {
+			actions.ChaincodeActions = append(actions.ChaincodeActions, &peer.FilteredChaincodeAction{
+				ChaincodeEvent: &peer.ChaincodeEvent{TxId: txID, ChaincodeId: chaincodeName, EventName: "event"},
+			})
+		}
+		return &peer.FilteredTransaction{
+			Txid: txID,
+			Type: common.HeaderType_ENDORSER_TRANSACTION,
+			Data: &peer.FilteredTransaction_TransactionActions{TransactionActions: actions},
+		}
+	}
+	filteredBlock := func() *peer.FilteredBlock {
+		return &peer.FilteredBlock{
+			ChannelId: "testchannelid",
+			Number:    5,
+			FilteredTransactions: []*peer.FilteredTransaction{
+				filteredTx("tx1", "mycc"),
+				filteredTx("tx2", "othercc"),
+				filteredTx("tx3", "mycc", "othercc", "publiccc"),
+				filteredTx("tx4"),
+				{Txid: "tx5", Type: common.HeaderType_CONFIG},
+			},
+		}
+	}
+	policies := map[string]bool{"mycc": true, "othercc": false}
+
+	t.Run("Unrestricted", func(t *testing.T) {
+		fb := filteredBlock()
+		restrictChaincodeEvents(fb, deliver.NewChaincodeEventAccess(true, policies))
+		assert.True(t, proto.Equal(&peer.FilteredBlock{
+			ChannelId: "testchannelid",
+			Number:    5,
+			FilteredTransactions: []*peer.FilteredTransaction{
+				filteredTx("tx1", "mycc"),
+				filteredTx("tx2"),
+				filteredTx("tx3", "mycc", "publiccc"),
+				filteredTx("tx4"),
+				{Txid: "tx5", Type: common.HeaderType_CONFIG},
+			},
+		}, fb), "unexpected filtered block %v", fb)
+	})
+
+	t.Run("Restricted", func(t *testing.T) {
+		fb := filteredBlock()
+		restrictChaincodeEvents(fb, deliver.NewChaincodeEventAccess(false, policies))
+		assert.True(t, proto.Equal(&peer.FilteredBlock{
+			ChannelId: "testchannelid",
+			Number:    5,
+			FilteredTransactions: []*peer.FilteredTransaction{
+				filteredTx("tx1", "mycc"),
+				filteredTx("tx3", "mycc"),
+			},
+		}, fb), "unexpected filtered block %v", fb)
+	})
+}
+
 func TestEventsServer_DeliverFiltered(t *testing.T) {
 	tests := []testCase{
 		{
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+// ChannelHooks is notified of the lifecycle events of the channels of the
+// peer.
+type ChannelHooks interface {
+	// ChannelJoined is called once the peer has joined a channel.
+	ChannelJoined(channelID string)
+	// BlockCommitted is called after each block committed to a channel.
+	BlockCommitted(channelID string, blockNumber uint64)
+}
+
+// hookedCommitter notifies the channel hooks of each committed block.
+type hookedCommitter struct {
+	committer.Committer
+	channelID string
+	hooks     ChannelHooks
+}
+
+func (h *hookedCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
+	if err := h.Committer.CommitLegacy(blockAndPvtData, commitOpts); err != nil {
+		return err
+	}
+	h.hooks.BlockCommitted(h.channelID, blockAndPvtData.Block.Header.Number)
+	return nil
+}
+
+// BlockObserver is notified of the content of each block committed to the
+// channels of the peer.
+type BlockObserver interface {
+	// BlockCommitted is called after each block committed to a channel.
+	BlockCommitted(channelID string, block *cb.Block)
+}
+
+// observedCommitter passes each committed block to the block observer.
+type observedCommitter struct {
+	committer.Committer
+	channelID string
+	observer  BlockObserver
+}
+
+func (o *observedCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
+	if err := o.Committer.CommitLegacy(blockAndPvtData, commitOpts); err != nil {
+		return err
+	}
+	o.observer.BlockCommitted(o.channelID, blockAndPvtData.Block)
+	return nil
+}
+
+// CommitGate suspends the commit of blocks, for instance while the peer
+// drains.
+type CommitGate interface {
+	// EnterCommit blocks while commits are suspended.
+	EnterCommit()
+	// ExitCommit signals the completion of a commit started with EnterCommit.
+	ExitCommit()
+}
+
+// gatedCommitter waits for the commit gate before each commit.
+type gatedCommitter struct {
+	committer.Committer
+	gate CommitGate
+}
+
+func (g *gatedCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
+	g.gate.EnterCommit()
+	defer g.gate.ExitCommit()
+	return g.Committer.CommitLegacy(blockAndPvtData, commitOpts)
+}
+
+func (g *gatedCommitter) CommitPvtDataOfOldBlocks(reconciledPvtdata []*ledger.ReconciledPvtdata, unreconciled ledger.MissingPvtDataInfo) ([]*ledger.PvtdataHashMismatch, error) {
+	g.gate.EnterCommit()
+	defer g.gate.ExitCommit()
+	return g.Committer.CommitPvtDataOfOldBlocks(reconciledPvtdata, unreconciled)
+}
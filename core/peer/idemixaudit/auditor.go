@@ -0,0 +1,222 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemixaudit
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/golang/protobuf/proto"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("peer.idemixaudit")
+
+// TransactionGetter gets the transactions of the ledger of a channel.
+type TransactionGetter interface {
+	GetTransactionByID(txID string) (*pb.ProcessedTransaction, error)
+}
+
+// Identity describes the identity that requested an audit.
+type Identity struct {
+	MSPID string `json:"msp_id"`
+	// Subject is the subject of the certificate of X.509 identities.
+	Subject string `json:"subject,omitempty"`
+	// Fingerprint is the SM3 hash of the serialized identity, in hex.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Request is a request of an auditor for the audit escrows of the creators of
+// transactions of a channel.
+type Request struct {
+	ChannelID string
+	// Requester is the serialized identity of the auditor.
+	Requester []byte
+	TxIDs     []string
+}
+
+// Report holds the audit escrows of the creators of the audited transactions.
+type Report struct {
+	ChannelID    string         `json:"channel_id"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// Transaction describes the creator of an audited transaction. The peer only
+// returns the audit escrow of the creator, encrypted for the auditor of its
+// MSP; the auditor decrypts it into the link of the creator with Link.
+type Transaction struct {
+	TxID  string `json:"tx_id"`
+	MSPID string `json:"msp_id,omitempty"`
+	// AuditEscrow is the serialized idemix.AuditEscrow of the creator.
+	AuditEscrow []byte `json:"audit_escrow,omitempty"`
+	// Link is set by the auditor: the transactions created with credentials
+	// of the same enrollment ID, issued by the same idemix MSP, have the same
+	// link, which reveals neither the enrollment ID nor anything else about
+	// the creator.
+	Link  string `json:"link,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Auditor returns the audit escrows of the transactions created by idemix
+// identities to the auditors authorized by the channel config, recording
+// every audit request on the audit trail. It holds no audit escrow key, so
+// the peer cannot link the transactions itself.
+type Auditor struct {
+	trail *Trail
+}
+
+// New returns an auditor recording the audit requests on the trail.
+func New(trail *Trail) *Auditor {
+	return &Auditor{
+		trail: trail,
+	}
+}
+
+// Audit returns the audit escrows of the creators of the transactions of the
+// request once authorize grants the access to the requester. The request is
+// recorded on the audit trail whether it is granted or not; the access is
+// denied if it cannot be recorded.
+func (a *Auditor) Audit(req *Request, ledger TransactionGetter, authorize func() error) (*Report, error) {
+	access := Access{
+		ChannelID: req.ChannelID,
+		Requester: describeIdentity(req.Requester),
+		TxIDs:     req.TxIDs,
+	}
+	authErr := authorize()
+	if authErr != nil {
+		access.Error = authErr.Error()
+	} else {
+		access.Granted = true
+	}
+
+	recorded, err := a.trail.Append(access)
+	if err != nil {
+		logger.Errorf("Denying audit of channel %s by %s: %s", req.ChannelID, access.Requester.MSPID, err)
+		return nil, errors.WithMessage(err, "failed recording the audit access")
+	}
+	if authErr != nil {
+		logger.Warningf("Denied audit access %d of channel %s by %s: %s", recorded.Sequence, req.ChannelID, access.Requester.MSPID, authErr)
+		return nil, authErr
+	}
+	logger.Infof("Granted audit access %d of channel %s by %s to %d transactions", recorded.Sequence, req.ChannelID, access.Requester.MSPID, len(req.TxIDs))
+
+	report := &Report{ChannelID: req.ChannelID}
+	for _, txID := range req.TxIDs {
+		tx := &Transaction{TxID: txID}
+		if err := escrow(tx, ledger); err != nil {
+			tx.Error = err.Error()
+		}
+		report.Transactions = append(report.Transactions, tx)
+	}
+	return report, nil
+}
+
+// Trail returns the audit trail of the auditor.
+func (a *Auditor) Trail() *Trail {
+	return a.trail
+}
+
+func escrow(tx *Transaction, ledger TransactionGetter) error {
+	processed, err := ledger.GetTransactionByID(tx.TxID)
+	if err != nil {
+		return errors.WithMessage(err, "failed getting the transaction")
+	}
+	// The proof of the creator, which binds the audit escrow to its
+	// credential, is only verified for the transactions found valid.
+	if code := pb.TxValidationCode(processed.ValidationCode); code != pb.TxValidationCode_VALID {
+		return errors.Errorf("transaction is invalid with code %s", code)
+	}
+	payload, err := protoutil.UnmarshalPayload(processed.GetTransactionEnvelope().GetPayload())
+	if err != nil {
+		return err
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(payload.GetHeader().GetSignatureHeader())
+	if err != nil {
+		return err
+	}
+	creator, err := protoutil.UnmarshalSerializedIdentity(shdr.Creator)
+	if err != nil {
+		return err
+	}
+	tx.MSPID = creator.Mspid
+
+	serialized := &mspproto.SerializedIdemixIdentity{}
+	if err := proto.Unmarshal(creator.IdBytes, serialized); err != nil {
+		return errors.Wrap(err, "creator is not an idemix identity")
+	}
+	sig := &idemix.Signature{}
+	if err := proto.Unmarshal(serialized.Proof, sig); err != nil {
+		return errors.Wrap(err, "failed unmarshaling the proof of the creator")
+	}
+	if sig.AuditEscrow == nil {
+		return errors.Errorf("creator has no audit escrow")
+	}
+	tx.AuditEscrow, err = proto.Marshal(sig.AuditEscrow)
+	return errors.Wrap(err, "failed marshaling the audit escrow of the creator")
+}
+
+// LoadEscrowKey reads the audit escrow key of an auditor.
+func LoadEscrowKey(file string) (*idemix.AuditEscrowKey, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading audit escrow key")
+	}
+	key := &idemix.AuditEscrowKey{}
+	if err := proto.Unmarshal(raw, key); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling audit escrow key")
+	}
+	if len(key.Sk) == 0 {
+		return nil, errors.Errorf("audit escrow key has no secret key")
+	}
+	return key, nil
+}
+
+// Link is run by the auditor of the idemix MSP mspID: it decrypts the audit
+// escrows of the transactions of the report created by the identities of the
+// MSP with the audit escrow key of the MSP, and sets their links. The other
+// transactions of the report are left as is.
+func Link(report *Report, mspID string, key *idemix.AuditEscrowKey) {
+	for _, tx := range report.Transactions {
+		if tx.MSPID != mspID || len(tx.AuditEscrow) == 0 {
+			continue
+		}
+		escrow := &idemix.AuditEscrow{}
+		if err := proto.Unmarshal(tx.AuditEscrow, escrow); err != nil {
+			tx.Error = errors.Wrap(err, "failed unmarshaling the audit escrow").Error()
+			continue
+		}
+		tag, err := escrow.Decrypt(key)
+		if err != nil {
+			tx.Error = err.Error()
+			continue
+		}
+		tx.Link = hex.EncodeToString(sm3.SumSM3(append([]byte(mspID), tag...)))
+	}
+}
+
+func describeIdentity(serializedIdentity []byte) Identity {
+	id := Identity{Fingerprint: hex.EncodeToString(sm3.SumSM3(serializedIdentity))}
+	sid, err := protoutil.UnmarshalSerializedIdentity(serializedIdentity)
+	if err != nil {
+		return id
+	}
+	id.MSPID = sid.Mspid
+	if bl, _ := pem.Decode(sid.IdBytes); bl != nil {
+		if cert, err := x509.ParseCertificate(bl.Bytes); err == nil {
+			id.Subject = cert.Subject.String()
+		}
+	}
+	return id
+}
@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemixaudit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedger map[string]*pb.ProcessedTransaction
+
+func (f fakeLedger) GetTransactionByID(txID string) (*pb.ProcessedTransaction, error) {
+	tx, ok := f[txID]
+	if !ok {
+		return nil, errors.Errorf("no such transaction ID [%s] in index", txID)
+	}
+	return tx, nil
+}
+
+// escrowedTransaction returns a transaction of an idemix identity of the MSP
+// whose audit escrow encrypts the base of the attribute to the value eid.
+func escrowedTransaction(t *testing.T, mspID string, key *idemix.AuditEscrowKey, eid string, code pb.TxValidationCode) *pb.ProcessedTransaction {
+	rng, err := idemix.GetRand()
+	require.NoError(t, err)
+	k := idemix.RandModOrder(rng)
+	c2 := idemix.GenG1.Mul(idemix.HashModOrder([]byte(eid)))
+	c2.Add(idemix.EcpFromProto(key.Pk).Mul(k))
+	sig := &idemix.Signature{AuditEscrow: &idemix.AuditEscrow{
+		C1: idemix.EcpToProto(idemix.GenG1.Mul(k)),
+		C2: idemix.EcpToProto(c2),
+	}}
+	proof, err := proto.Marshal(sig)
+	require.NoError(t, err)
+	idBytes, err := proto.Marshal(&mspproto.SerializedIdemixIdentity{Proof: proof})
+	require.NoError(t, err)
+	return processedTransaction(t, mspID, idBytes, code)
+}
+
+func processedTransaction(t *testing.T, mspID string, idBytes []byte, code pb.TxValidationCode) *pb.ProcessedTransaction {
+	creator, err := proto.Marshal(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: idBytes})
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&cb.Payload{
+		Header: &cb.Header{SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: creator})},
+	})
+	require.NoError(t, err)
+	return &pb.ProcessedTransaction{
+		TransactionEnvelope: &cb.Envelope{Payload: payload},
+		ValidationCode:      int32(code),
+	}
+}
+
+func TestLoadEscrowKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idemixaudit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rng, err := idemix.GetRand()
+	require.NoError(t, err)
+	key, err := idemix.NewAuditEscrowKey(rng)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "AuditEscrowKey"), protoutil.MarshalOrPanic(key), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "empty"), nil, 0600))
+
+	loaded, err := LoadEscrowKey(filepath.Join(dir, "AuditEscrowKey"))
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(key, loaded))
+
+	_, err = LoadEscrowKey(filepath.Join(dir, "empty"))
+	assert.EqualError(t, err, "audit escrow key has no secret key")
+	_, err = LoadEscrowKey(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestAudit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idemixaudit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	trail, err := NewTrail(dir, fakeSigner{})
+	require.NoError(t, err)
+	defer trail.Close()
+
+	rng, err := idemix.GetRand()
+	require.NoError(t, err)
+	key, err := idemix.NewAuditEscrowKey(rng)
+	require.NoError(t, err)
+	otherKey, err := idemix.NewAuditEscrowKey(rng)
+	require.NoError(t, err)
+	auditor := New(trail)
+
+	ledger := fakeLedger{
+		"alice1":  escrowedTransaction(t, "IdemixOrg", key, "alice", pb.TxValidationCode_VALID),
+		"alice2":  escrowedTransaction(t, "IdemixOrg", key, "alice", pb.TxValidationCode_VALID),
+		"bob":     escrowedTransaction(t, "IdemixOrg", key, "bob", pb.TxValidationCode_VALID),
+		"invalid": escrowedTransaction(t, "IdemixOrg", key, "alice", pb.TxValidationCode_BAD_CREATOR_SIGNATURE),
+		"other":   escrowedTransaction(t, "OtherOrg", otherKey, "alice", pb.TxValidationCode_VALID),
+		"x509":    processedTransaction(t, "IdemixOrg", []byte("certificate"), pb.TxValidationCode_VALID),
+	}
+	requester := protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: "AuditorOrg", IdBytes: []byte("auditor")})
+
+	report, err := auditor.Audit(&Request{
+		ChannelID: "mychannel",
+		Requester: requester,
+		TxIDs:     []string{"alice1", "alice2", "bob", "invalid", "other", "x509", "missing"},
+	}, ledger, func() error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", report.ChannelID)
+	txs := report.Transactions
+	require.Len(t, txs, 7)
+	for _, tx := range txs[:3] {
+		assert.Equal(t, "IdemixOrg", tx.MSPID)
+		assert.NotEmpty(t, tx.AuditEscrow)
+	}
+	assert.NotEqual(t, txs[0].AuditEscrow, txs[1].AuditEscrow, "the escrows of the same enrollment ID are unlinkable without the key")
+	assert.Equal(t, "transaction is invalid with code BAD_CREATOR_SIGNATURE", txs[3].Error)
+	assert.Equal(t, "OtherOrg", txs[4].MSPID)
+	assert.NotEmpty(t, txs[4].AuditEscrow)
+	assert.Contains(t, txs[5].Error, "creator is not an idemix identity")
+	assert.Equal(t, "failed getting the transaction: no such transaction ID [missing] in index", txs[6].Error)
+	for _, tx := range txs {
+		assert.Empty(t, tx.Link, "the peer cannot link the transactions")
+	}
+
+	// the auditor of the MSP links the transactions of its identities
+	Link(report, "IdemixOrg", key)
+	assert.NotEmpty(t, txs[0].Link)
+	assert.Equal(t, txs[0].Link, txs[1].Link)
+	assert.NotEmpty(t, txs[2].Link)
+	assert.NotEqual(t, txs[0].Link, txs[2].Link)
+	for _, tx := range txs[3:] {
+		assert.Empty(t, tx.Link)
+	}
+	Link(report, "OtherOrg", otherKey)
+	assert.NotEmpty(t, txs[4].Link)
+	assert.NotEqual(t, txs[0].Link, txs[4].Link)
+
+	_, err = auditor.Audit(&Request{
+		ChannelID: "mychannel",
+		Requester: requester,
+		TxIDs:     []string{"alice1"},
+	}, ledger, func() error { return errors.New("access denied") })
+	assert.EqualError(t, err, "access denied")
+
+	accesses, err := auditor.Trail().Accesses("mychannel", 0)
+	require.NoError(t, err)
+	require.Len(t, accesses, 2)
+	assert.True(t, accesses[0].Granted)
+	assert.Equal(t, "AuditorOrg", accesses[0].Requester.MSPID)
+	assert.Len(t, accesses[0].TxIDs, 7)
+	assert.False(t, accesses[1].Granted)
+	assert.Equal(t, "access denied", accesses[1].Error)
+
+	// the access is denied when it cannot be recorded
+	require.NoError(t, trail.Close())
+	_, err = auditor.Audit(&Request{ChannelID: "mychannel", Requester: requester}, ledger, func() error { return nil })
+	assert.Contains(t, err.Error(), "failed recording the audit access")
+}
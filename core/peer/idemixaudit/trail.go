@@ -0,0 +1,212 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemixaudit
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/pkg/errors"
+)
+
+const trailFileName = "trail.log"
+
+// Signer signs the accesses of the audit trail.
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
+	Serialize() ([]byte, error)
+}
+
+// Access is a record of the audit trail, describing an audit request and
+// whether it was granted. Each record holds the SM3 hash of the previous one,
+// so that altering or removing a record breaks the chain of hashes of all the
+// records following it. Each record is signed by the peer over its hash, so
+// that the trails collected from the peers of a channel can be checked
+// against the MSPs of the channel away from the peers.
+type Access struct {
+	Sequence     uint64    `json:"sequence"`
+	Timestamp    time.Time `json:"timestamp"`
+	ChannelID    string    `json:"channel_id"`
+	Requester    Identity  `json:"requester"`
+	TxIDs        []string  `json:"tx_ids"`
+	Granted      bool      `json:"granted"`
+	Error        string    `json:"error,omitempty"`
+	PreviousHash string    `json:"previous_hash"`
+	Hash         string    `json:"hash"`
+	// Signer is the serialized identity of the peer.
+	Signer []byte `json:"signer"`
+	// Signature is the signature of the peer over the hash.
+	Signature []byte `json:"signature"`
+}
+
+// computeHash returns the hash of the access, which covers all of its fields
+// but the hash and the signature.
+func (a Access) computeHash() (string, error) {
+	a.Hash = ""
+	a.Signature = nil
+	raw, err := json.Marshal(a)
+	if err != nil {
+		return "", errors.Wrap(err, "failed marshaling audit access")
+	}
+	return hex.EncodeToString(sm3.SumSM3(raw)), nil
+}
+
+// Trail is the append only, hash chained and signed trail of the audit
+// accesses served by a peer, kept in a file of the peer.
+type Trail struct {
+	path   string
+	signer Signer
+
+	mutex    sync.Mutex
+	file     *os.File
+	next     uint64
+	lastHash string
+}
+
+// NewTrail opens the audit trail stored in the given directory, creating it
+// if needed, whose new accesses are signed by signer. An error is returned if
+// the hash chain of the existing accesses is broken.
+func NewTrail(dir string, signer Signer) (*Trail, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, errors.Wrapf(err, "failed creating audit trail directory %s", dir)
+	}
+	t := &Trail{
+		path:   filepath.Join(dir, trailFileName),
+		signer: signer,
+	}
+
+	var last *Access
+	err := t.scan(func(a Access) {
+		last = &a
+	})
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		t.next = last.Sequence + 1
+		t.lastHash = last.Hash
+	}
+
+	t.file, err = os.OpenFile(t.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening audit trail %s", t.path)
+	}
+	return t, nil
+}
+
+// Append completes the access with its sequence, timestamp, hashes and
+// signature and durably appends it to the trail.
+func (t *Trail) Append(a Access) (Access, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	a.Sequence = t.next
+	if a.Timestamp.IsZero() {
+		a.Timestamp = time.Now().UTC()
+	}
+	a.PreviousHash = t.lastHash
+	signer, err := t.signer.Serialize()
+	if err != nil {
+		return Access{}, errors.Wrap(err, "failed serializing the signer of the audit trail")
+	}
+	a.Signer = signer
+	hash, err := a.computeHash()
+	if err != nil {
+		return Access{}, err
+	}
+	a.Hash = hash
+	if a.Signature, err = t.signer.Sign([]byte(a.Hash)); err != nil {
+		return Access{}, errors.Wrap(err, "failed signing audit access")
+	}
+
+	line, err := json.Marshal(a)
+	if err != nil {
+		return Access{}, errors.Wrap(err, "failed marshaling audit access")
+	}
+	if _, err := t.file.Write(append(line, '\n')); err != nil {
+		return Access{}, errors.Wrapf(err, "failed writing audit trail %s", t.path)
+	}
+	if err := t.file.Sync(); err != nil {
+		return Access{}, errors.Wrapf(err, "failed syncing audit trail %s", t.path)
+	}
+
+	t.next++
+	t.lastHash = a.Hash
+	return a, nil
+}
+
+// Accesses returns the accesses of the channel, or of all the channels if
+// channelID is empty, whose sequence is greater than or equal to since, after
+// verifying the hash chain of the whole trail.
+func (t *Trail) Accesses(channelID string, since uint64) ([]Access, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	accesses := []Access{}
+	err := t.scan(func(a Access) {
+		if a.Sequence >= since && (channelID == "" || a.ChannelID == channelID) {
+			accesses = append(accesses, a)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accesses, nil
+}
+
+// Close closes the file of the trail.
+func (t *Trail) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.file.Close()
+}
+
+// scan reads the accesses of the trail in order, verifying their hash chain.
+func (t *Trail) scan(f func(Access)) error {
+	file, err := os.Open(t.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed opening audit trail %s", t.path)
+	}
+	defer file.Close()
+
+	var expectedSequence uint64
+	var previousHash string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var a Access
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			return errors.Wrapf(err, "audit trail %s is corrupted at access %d", t.path, expectedSequence)
+		}
+		if a.Sequence != expectedSequence {
+			return errors.Errorf("audit trail %s is tampered: expected access %d but found access %d", t.path, expectedSequence, a.Sequence)
+		}
+		hash, err := a.computeHash()
+		if err != nil {
+			return err
+		}
+		if a.PreviousHash != previousHash || a.Hash != hash {
+			return errors.Errorf("audit trail %s is tampered: hash chain is broken at access %d", t.path, a.Sequence)
+		}
+		f(a)
+		expectedSequence++
+		previousHash = a.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "failed reading audit trail %s", t.path)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemixaudit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(message []byte) ([]byte, error) {
+	return append([]byte("signed:"), message...), nil
+}
+
+func (fakeSigner) Serialize() ([]byte, error) {
+	return []byte("peer"), nil
+}
+
+func TestTrail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idemixaudit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	trail, err := NewTrail(dir, fakeSigner{})
+	require.NoError(t, err)
+	first, err := trail.Append(Access{ChannelID: "ch1", TxIDs: []string{"tx1"}, Granted: true})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), first.Sequence)
+	assert.Empty(t, first.PreviousHash)
+	assert.NotEmpty(t, first.Hash)
+	assert.Equal(t, []byte("peer"), first.Signer)
+	assert.Equal(t, []byte("signed:"+first.Hash), first.Signature)
+	second, err := trail.Append(Access{ChannelID: "ch2", TxIDs: []string{"tx2"}, Error: "access denied"})
+	require.NoError(t, err)
+	assert.Equal(t, first.Hash, second.PreviousHash)
+	require.NoError(t, trail.Close())
+
+	// the trail carries on after a restart
+	trail, err = NewTrail(dir, fakeSigner{})
+	require.NoError(t, err)
+	third, err := trail.Append(Access{ChannelID: "ch1", TxIDs: []string{"tx3"}, Granted: true})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), third.Sequence)
+	assert.Equal(t, second.Hash, third.PreviousHash)
+
+	accesses, err := trail.Accesses("", 0)
+	require.NoError(t, err)
+	assert.Len(t, accesses, 3)
+	accesses, err = trail.Accesses("ch1", 1)
+	require.NoError(t, err)
+	require.Len(t, accesses, 1)
+	assert.Equal(t, []string{"tx3"}, accesses[0].TxIDs)
+	require.NoError(t, trail.Close())
+
+	// altering an access breaks the hash chain
+	path := filepath.Join(dir, trailFileName)
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, []byte(strings.Replace(string(raw), `"access denied"`, `"granted"`, 1)), 0640))
+	_, err = NewTrail(dir, fakeSigner{})
+	assert.EqualError(t, err, "audit trail "+path+" is tampered: hash chain is broken at access 1")
+
+	// removing an access breaks the sequence
+	lines := strings.SplitAfter(string(raw), "\n")
+	require.NoError(t, ioutil.WriteFile(path, []byte(lines[0]+lines[2]), 0640))
+	_, err = NewTrail(dir, fakeSigner{})
+	assert.EqualError(t, err, "audit trail "+path+" is tampered: expected access 1 but found access 2")
+}
@@ -21,6 +21,7 @@ import (
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/semaphore"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/committer/txvalidator/plugin"
@@ -181,6 +182,12 @@ type Peer struct {
 	LedgerMgr                *ledgermgmt.LedgerMgr
 	OrdererEndpointOverrides map[string]*orderers.Endpoint
 	CryptoProvider           bccsp.BCCSP
+	ChannelHooks             ChannelHooks
+	BlockObserver            BlockObserver
+	CommitGate               CommitGate
+	Tracer                   *tracing.Tracer
+	// ACLProvider checks the access of the creators of the custom transactions
+	ACLProvider validatorv20.ACLProvider
 
 	// validationWorkersSemaphore is used to limit the number of concurrent validation
 	// go routines.
@@ -221,6 +228,9 @@ func (p *Peer) CreateChannel(
 	}
 
 	p.initChannel(cid)
+	if p.ChannelHooks != nil {
+		p.ChannelHooks.ChannelJoined(cid)
+	}
 	return nil
 }
 
@@ -324,8 +334,35 @@ func (p *Peer) createChannel(
 		channel.bundleUpdate,
 	)
 
-	committer := committer.NewLedgerCommitter(l)
-	validator := &txvalidator.ValidationRouter{
+	var committer committer.Committer = committer.NewLedgerCommitter(l)
+	if p.Tracer != nil {
+		committer = &tracedCommitter{
+			Committer: committer,
+			channelID: cid,
+			tracer:    p.Tracer,
+		}
+	}
+	if p.ChannelHooks != nil {
+		committer = &hookedCommitter{
+			Committer: committer,
+			channelID: cid,
+			hooks:     p.ChannelHooks,
+		}
+	}
+	if p.BlockObserver != nil {
+		committer = &observedCommitter{
+			Committer: committer,
+			channelID: cid,
+			observer:  p.BlockObserver,
+		}
+	}
+	if p.CommitGate != nil {
+		committer = &gatedCommitter{
+			Committer: committer,
+			gate:      p.CommitGate,
+		}
+	}
+	var validator txvalidator.Validator = &txvalidator.ValidationRouter{
 		CapabilityProvider: channel,
 		V14Validator: validatorv14.NewTxValidator(
 			cid,
@@ -349,9 +386,17 @@ func (p *Peer) createChannel(
 			},
 			p.pluginMapper,
 			policies.PolicyManagerGetterFunc(p.GetPolicyManager),
+			p.ACLProvider,
 			p.CryptoProvider,
 		),
 	}
+	if p.Tracer != nil {
+		validator = &tracedValidator{
+			Validator: validator,
+			channelID: cid,
+			tracer:    p.Tracer,
+		}
+	}
 
 	// TODO: does someone need to call Close() on the transientStoreFactory at shutdown of the peer?
 	store, err := p.openStore(bundle.ConfigtxValidator().ChannelID())
@@ -519,3 +564,7 @@ func (p *Peer) Initialize(
 func (flbs fileLedgerBlockStore) RetrieveBlockByNumber(blockNum uint64) (*common.Block, error) {
 	return flbs.GetBlockByNumber(blockNum)
 }
+
+func (flbs fileLedgerBlockStore) RetrieveBlockByTxID(txID string) (*common.Block, error) {
+	return flbs.GetBlockByTxID(txID)
+}
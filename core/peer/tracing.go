@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/tracing"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// tracedValidator records a span for the validation of each transaction of a
+// block.
+type tracedValidator struct {
+	txvalidator.Validator
+	channelID string
+	tracer    *tracing.Tracer
+}
+
+func (t *tracedValidator) Validate(block *common.Block) error {
+	spans := startTxSpans(t.tracer, block, "peer.Validate", t.channelID)
+	err := t.Validator.Validate(block)
+	finishTxSpans(spans, block, err)
+	return err
+}
+
+// tracedCommitter records a span for the commit of each transaction of a
+// block.
+type tracedCommitter struct {
+	committer.Committer
+	channelID string
+	tracer    *tracing.Tracer
+}
+
+func (t *tracedCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
+	spans := startTxSpans(t.tracer, blockAndPvtData.Block, "peer.Commit", t.channelID)
+	err := t.Committer.CommitLegacy(blockAndPvtData, commitOpts)
+	finishTxSpans(spans, blockAndPvtData.Block, err)
+	return err
+}
+
+// startTxSpans starts a span in the trace of each transaction of the block,
+// indexed by the position of the transaction in the block.
+func startTxSpans(tracer *tracing.Tracer, block *common.Block, name, channelID string) map[int]*tracing.Span {
+	spans := map[int]*tracing.Span{}
+	if block == nil || block.Data == nil || block.Header == nil {
+		return spans
+	}
+	blockNumber := strconv.FormatUint(block.Header.Number, 10)
+	for i, envBytes := range block.Data.Data {
+		env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			continue
+		}
+		chdr, err := protoutil.ChannelHeader(env)
+		if err != nil || chdr.TxId == "" {
+			continue
+		}
+		spans[i] = tracer.StartSpan(chdr.TxId, name, tracing.SpanKindConsumer,
+			"fabric.channel", channelID,
+			"fabric.block_number", blockNumber,
+			"fabric.tx_index", strconv.Itoa(i),
+		)
+	}
+	return spans
+}
+
+// finishTxSpans finishes the spans of the transactions of the block, recording
+// the validation code of each transaction.
+func finishTxSpans(spans map[int]*tracing.Span, block *common.Block, err error) {
+	var flags txflags.ValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		flags = txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+	for i, span := range spans {
+		if i < len(flags) {
+			span.SetAttributes("fabric.validation_code", flags.Flag(i).String())
+		}
+		span.Finish(err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/tracing"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanRecorder struct {
+	spans []*tracing.Span
+}
+
+func (r *spanRecorder) Export(span *tracing.Span) {
+	r.spans = append(r.spans, span)
+}
+
+type validatorFunc func(block *common.Block) error
+
+func (v validatorFunc) Validate(block *common.Block) error {
+	return v(block)
+}
+
+type commitLegacyFunc struct {
+	committer.Committer
+	commit func(*ledger.BlockAndPvtData) error
+}
+
+func (c *commitLegacyFunc) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
+	return c.commit(blockAndPvtData)
+}
+
+func tracedBlock(t *testing.T, txIDs ...string) *common.Block {
+	block := protoutil.NewBlock(7, nil)
+	for _, txID := range txIDs {
+		env := &common.Envelope{
+			Payload: protoutil.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{
+					ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+						Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+						ChannelId: "mychannel",
+						TxId:      txID,
+					}),
+				},
+			}),
+		}
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+	}
+	block.Data.Data = append(block.Data.Data, []byte("garbage"))
+	return block
+}
+
+func TestTracedValidator(t *testing.T) {
+	spans := &spanRecorder{}
+	block := tracedBlock(t, "tx1", "tx2")
+	v := &tracedValidator{
+		Validator: validatorFunc(func(block *common.Block) error {
+			flags := txflags.New(len(block.Data.Data))
+			flags.SetFlag(0, pb.TxValidationCode_VALID)
+			flags.SetFlag(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+			block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+			return nil
+		}),
+		channelID: "mychannel",
+		tracer:    tracing.NewTracer(spans),
+	}
+
+	require.NoError(t, v.Validate(block))
+	require.Len(t, spans.spans, 2, "a span is recorded for each transaction with a transaction ID")
+	codes := map[tracing.TraceID]string{}
+	for _, span := range spans.spans {
+		assert.Equal(t, "peer.Validate", span.Name)
+		assert.Equal(t, tracing.SpanKindConsumer, span.Kind)
+		assert.Contains(t, span.Attributes, tracing.Attribute{Key: "fabric.block_number", Value: "7"})
+		assert.Contains(t, span.Attributes, tracing.Attribute{Key: "fabric.channel", Value: "mychannel"})
+		for _, a := range span.Attributes {
+			if a.Key == "fabric.validation_code" {
+				codes[span.TraceID] = a.Value
+			}
+		}
+	}
+	assert.Equal(t, map[tracing.TraceID]string{
+		tracing.TraceIDFromTxID("tx1"): "VALID",
+		tracing.TraceIDFromTxID("tx2"): "MVCC_READ_CONFLICT",
+	}, codes)
+}
+
+func TestTracedCommitter(t *testing.T) {
+	spans := &spanRecorder{}
+	c := &tracedCommitter{
+		Committer: &commitLegacyFunc{
+			commit: func(*ledger.BlockAndPvtData) error { return errors.New("disk full") },
+		},
+		channelID: "mychannel",
+		tracer:    tracing.NewTracer(spans),
+	}
+
+	err := c.CommitLegacy(&ledger.BlockAndPvtData{Block: tracedBlock(t, "tx1")}, &ledger.CommitOptions{})
+	assert.EqualError(t, err, "disk full")
+	require.Len(t, spans.spans, 1)
+	assert.Equal(t, "peer.Commit", spans.spans[0].Name)
+	assert.Equal(t, tracing.TraceIDFromTxID("tx1"), spans.spans[0].TraceID)
+	assert.Equal(t, "disk full", spans.spans[0].Error)
+}
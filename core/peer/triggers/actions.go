@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package triggers
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// DefaultEventFeedSize is the number of firings retained by the event feed.
+const DefaultEventFeedSize = 1000
+
+// webhookQueueSize bounds the blocks of firings waiting to be posted to a
+// webhook; the firings of the blocks committed while the queue is full are
+// dropped so that a slow webhook never delays the commit.
+const webhookQueueSize = 100
+
+// EventFeed retains the most recent firings of the triggers with an event
+// action, which clients poll by sequence number.
+type EventFeed struct {
+	mutex    sync.Mutex
+	size     int
+	firings  []*Firing
+	sequence uint64
+}
+
+// NewEventFeed creates an EventFeed retaining up to size firings.
+func NewEventFeed(size int) *EventFeed {
+	return &EventFeed{size: size}
+}
+
+// Append assigns the next sequence numbers to the firings and appends them to
+// the feed, evicting the oldest firings beyond the size of the feed.
+func (f *EventFeed) Append(firings ...*Firing) {
+	if len(firings) == 0 {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, firing := range firings {
+		f.sequence++
+		firing.Sequence = f.sequence
+		f.firings = append(f.firings, firing)
+	}
+	if excess := len(f.firings) - f.size; excess > 0 {
+		f.firings = append([]*Firing(nil), f.firings[excess:]...)
+	}
+}
+
+// Since returns the firings of the channel, or of all the channels if
+// channelID is empty, whose sequence is greater than since.
+func (f *EventFeed) Since(channelID string, since uint64) []*Firing {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	res := []*Firing{}
+	for _, firing := range f.firings {
+		if firing.Sequence <= since {
+			continue
+		}
+		if channelID != "" && firing.ChannelID != channelID {
+			continue
+		}
+		res = append(res, firing)
+	}
+	return res
+}
+
+type derivedWrite struct {
+	key    string
+	firing *Firing
+}
+
+// DerivedStore holds the derived keys written by the triggers, in a leveldb
+// database local to the peer with a handle per channel. The value of a
+// derived key is the JSON encoded firing that last wrote it.
+type DerivedStore struct {
+	provider *leveldbhelper.Provider
+}
+
+// NewDerivedStore opens the derived store at the given path.
+func NewDerivedStore(path string) (*DerivedStore, error) {
+	provider, err := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: path})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to open the derived store of the triggers")
+	}
+	return &DerivedStore{provider: provider}, nil
+}
+
+// Write writes the derived keys of a block of the channel in a single batch.
+func (s *DerivedStore) Write(channelID string, writes []*derivedWrite) error {
+	db := s.provider.GetDBHandle(channelID)
+	batch := db.NewUpdateBatch()
+	for _, w := range writes {
+		value, err := json.Marshal(w.firing)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal the firing of trigger [%s]", w.firing.Trigger)
+		}
+		batch.Put([]byte(w.key), value)
+	}
+	if err := db.WriteBatch(batch, true); err != nil {
+		return errors.WithMessagef(err, "failed to write the derived keys of channel [%s]", channelID)
+	}
+	return nil
+}
+
+// Get returns the value of a derived key of the channel, nil if the key does
+// not exist.
+func (s *DerivedStore) Get(channelID, key string) ([]byte, error) {
+	return s.provider.GetDBHandle(channelID).Get([]byte(key))
+}
+
+// Close closes the derived store.
+func (s *DerivedStore) Close() {
+	s.provider.Close()
+}
+
+type webhookRequest struct {
+	ChannelID   string    `json:"channel_id"`
+	BlockNumber uint64    `json:"block_number"`
+	Firings     []*Firing `json:"firings"`
+}
+
+type webhook struct {
+	trigger string
+	url     string
+	client  *http.Client
+	queue   chan *webhookRequest
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newWebhook creates a webhook posting to an https URL. The certificate of
+// the server is verified against the PEM encoded root certificates of the
+// rootCert file, or against the root certificates of the host when none is
+// given.
+func newWebhook(trigger, webhookURL, rootCert string, timeout time.Duration) (*webhook, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" {
+		return nil, errors.Errorf("webhook of trigger [%s] has an invalid url [%s], an https url is required", trigger, webhookURL)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if rootCert != "" {
+		pemBytes, err := ioutil.ReadFile(rootCert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the root certificate of the webhook of trigger [%s]", trigger)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pemBytes) {
+			return nil, errors.Errorf("no certificate found in the root certificate file [%s] of the webhook of trigger [%s]", rootCert, trigger)
+		}
+	}
+	return &webhook{
+		trigger: trigger,
+		url:     webhookURL,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		queue: make(chan *webhookRequest, webhookQueueSize),
+		done:  make(chan struct{}),
+	}, nil
+}
+
+func (w *webhook) enqueue(channelID string, blockNumber uint64, firings []*Firing) {
+	select {
+	case w.queue <- &webhookRequest{ChannelID: channelID, BlockNumber: blockNumber, Firings: firings}:
+	default:
+		logger.Warnf("Webhook queue of trigger [%s] is full, dropping %d firings of block [%d] of channel [%s]",
+			w.trigger, len(firings), blockNumber, channelID)
+	}
+}
+
+func (w *webhook) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case req := <-w.queue:
+			if err := w.post(req); err != nil {
+				logger.Warnf("Failed to post %d firings of trigger [%s] for block [%d] of channel [%s]: %s",
+					len(req.Firings), w.trigger, req.BlockNumber, req.ChannelID, err)
+			}
+		}
+	}
+}
+
+func (w *webhook) post(req *webhookRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the firings")
+	}
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *webhook) stop() {
+	w.once.Do(func() { close(w.done) })
+}
@@ -0,0 +1,339 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package triggers
+
+import (
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("peer.triggers")
+
+const (
+	// ActionEvent appends the firings of a trigger to the event feed.
+	ActionEvent = "event"
+	// ActionDerivedKey writes the firings of a trigger to the derived store
+	// of the peer, under a key derived from the written key.
+	ActionDerivedKey = "derivedKey"
+	// ActionWebhook posts the firings of a trigger to a URL.
+	ActionWebhook = "webhook"
+
+	defaultDerivedKey     = "{trigger}/{namespace}/{key}"
+	defaultWebhookTimeout = 3 * time.Second
+
+	// blockQueueSize bounds the committed blocks waiting to be evaluated;
+	// the commit only waits for the triggers when the queue is full.
+	blockQueueSize = 100
+)
+
+// Firing records a write of a valid transaction that matched a trigger.
+type Firing struct {
+	// Sequence orders the firings in the event feed; it is only set for
+	// the firings of the triggers with an event action.
+	Sequence    uint64 `json:"sequence,omitempty"`
+	Trigger     string `json:"trigger"`
+	ChannelID   string `json:"channel_id"`
+	BlockNumber uint64 `json:"block_number"`
+	TxNumber    uint64 `json:"tx_number"`
+	TxID        string `json:"tx_id"`
+	Namespace   string `json:"namespace"`
+	Key         string `json:"key"`
+	Value       []byte `json:"value,omitempty"`
+	IsDelete    bool   `json:"is_delete,omitempty"`
+}
+
+type trigger struct {
+	name        string
+	channel     string
+	namespace   string
+	key         string
+	event       bool
+	derivedKeys []string
+	webhooks    []*webhook
+}
+
+// matches reports whether the write matches the patterns of the trigger,
+// which New checked to be well formed: path.Match can then only fail on a
+// malformed pattern and never returns an error.
+func (t *trigger) matches(channelID, namespace, key string) bool {
+	if t.channel != "" && t.channel != channelID {
+		return false
+	}
+	if ok, _ := path.Match(t.namespace, namespace); !ok {
+		return false
+	}
+	if t.key == "" {
+		return true
+	}
+	ok, _ := path.Match(t.key, key)
+	return ok
+}
+
+// Engine evaluates the triggers on the blocks committed to the ledgers of the
+// peer and runs their actions. It is registered as a ledger.CommitListener.
+//
+// The evaluation is deterministic and read-only: the triggers only inspect the
+// public writes of the valid endorser transactions of a block, in the order of
+// the transactions and of their writes, and never modify the state of the
+// ledger. Derived keys are therefore written to a store local to the peer
+// rather than to the world state. The blocks are evaluated by a worker of the
+// engine, in the order in which they are committed, so that neither parsing
+// their transactions nor writing the derived keys delays the commit.
+type Engine struct {
+	triggers []*trigger
+	events   *EventFeed
+	derived  *DerivedStore
+	webhooks []*webhook
+	blocks   chan *ledger.CommitEvent
+	done     chan struct{}
+	running  sync.WaitGroup
+}
+
+// New creates an Engine for the triggers of the peer configuration. The
+// derived store is created under the given directory when a trigger has a
+// derivedKey action.
+func New(triggers []peer.Trigger, derivedStorePath string) (*Engine, error) {
+	e := &Engine{
+		events: NewEventFeed(DefaultEventFeedSize),
+		blocks: make(chan *ledger.CommitEvent, blockQueueSize),
+		done:   make(chan struct{}),
+	}
+	for _, t := range triggers {
+		for _, pattern := range []string{t.Namespace, t.Key} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				e.Close()
+				return nil, errors.Wrapf(err, "invalid pattern [%s] of trigger [%s]", pattern, t.Name)
+			}
+		}
+		trig := &trigger{
+			name:      t.Name,
+			channel:   t.Channel,
+			namespace: t.Namespace,
+			key:       t.Key,
+		}
+		for _, action := range t.Actions {
+			switch action.Type {
+			case ActionEvent:
+				trig.event = true
+			case ActionDerivedKey:
+				template := action.DerivedKey
+				if template == "" {
+					template = defaultDerivedKey
+				}
+				trig.derivedKeys = append(trig.derivedKeys, template)
+			case ActionWebhook:
+				timeout := defaultWebhookTimeout
+				if action.Timeout != "" {
+					var err error
+					if timeout, err = time.ParseDuration(action.Timeout); err != nil {
+						e.Close()
+						return nil, errors.Wrapf(err, "invalid timeout for webhook action of trigger [%s]", t.Name)
+					}
+				}
+				wh, err := newWebhook(t.Name, action.URL, action.RootCert, timeout)
+				if err != nil {
+					e.Close()
+					return nil, err
+				}
+				trig.webhooks = append(trig.webhooks, wh)
+				e.webhooks = append(e.webhooks, wh)
+			default:
+				e.Close()
+				return nil, errors.Errorf("trigger [%s] has an action of unknown type [%s]", t.Name, action.Type)
+			}
+		}
+		if len(trig.derivedKeys) > 0 && e.derived == nil {
+			derived, err := NewDerivedStore(derivedStorePath)
+			if err != nil {
+				e.Close()
+				return nil, err
+			}
+			e.derived = derived
+		}
+		e.triggers = append(e.triggers, trig)
+	}
+	for _, wh := range e.webhooks {
+		go wh.run()
+	}
+	e.running.Add(1)
+	go e.run()
+	return e, nil
+}
+
+// Name implements ledger.CommitListener
+func (e *Engine) Name() string {
+	return "triggers"
+}
+
+// HandleCommittedBlock implements ledger.CommitListener. It queues the block
+// for the worker of the engine, and only blocks while the queue is full.
+func (e *Engine) HandleCommittedBlock(event *ledger.CommitEvent) error {
+	select {
+	case e.blocks <- event:
+		return nil
+	case <-e.done:
+		return errors.New("triggers engine is closed")
+	}
+}
+
+func (e *Engine) run() {
+	defer e.running.Done()
+	for {
+		select {
+		case <-e.done:
+			return
+		case event := <-e.blocks:
+			if err := e.evaluate(event); err != nil {
+				logger.Errorf("Failed evaluating the triggers on block [%d] of channel [%s]: %+v",
+					event.Block.Header.Number, event.LedgerID, err)
+			}
+		}
+	}
+}
+
+// evaluate evaluates the triggers on the writes of the block, appends the
+// firings of the event actions to the feed, writes the derived keys in a
+// single batch, and queues the firings of the webhook actions, which are
+// posted asynchronously.
+func (e *Engine) evaluate(event *ledger.CommitEvent) error {
+	writes, err := blockWrites(event.LedgerID, event.Block)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to extract the writes of block [%d] of channel [%s]", event.Block.Header.Number, event.LedgerID)
+	}
+
+	var events []*Firing
+	var derived []*derivedWrite
+	webhookFirings := map[*webhook][]*Firing{}
+	for _, w := range writes {
+		for _, t := range e.triggers {
+			if !t.matches(w.ChannelID, w.Namespace, w.Key) {
+				continue
+			}
+			f := *w
+			f.Trigger = t.name
+			if t.event {
+				events = append(events, &f)
+			}
+			for _, template := range t.derivedKeys {
+				derived = append(derived, &derivedWrite{key: deriveKey(template, &f), firing: &f})
+			}
+			for _, wh := range t.webhooks {
+				webhookFirings[wh] = append(webhookFirings[wh], &f)
+			}
+		}
+	}
+
+	e.events.Append(events...)
+	if len(derived) > 0 {
+		if err := e.derived.Write(event.LedgerID, derived); err != nil {
+			return err
+		}
+	}
+	for _, wh := range e.webhooks {
+		if firings := webhookFirings[wh]; len(firings) > 0 {
+			wh.enqueue(event.LedgerID, event.Block.Header.Number, firings)
+		}
+	}
+	return nil
+}
+
+// Events returns the firings of the event feed for the channel, or for all
+// the channels if channelID is empty, whose sequence is greater than since.
+func (e *Engine) Events(channelID string, since uint64) []*Firing {
+	return e.events.Since(channelID, since)
+}
+
+// DerivedValue returns the value of a derived key of the channel, nil if the
+// key does not exist.
+func (e *Engine) DerivedValue(channelID, key string) ([]byte, error) {
+	if e.derived == nil {
+		return nil, nil
+	}
+	return e.derived.Get(channelID, key)
+}
+
+// Close stops the workers of the engine and of the webhooks and closes the
+// derived store. The blocks still queued are not evaluated.
+func (e *Engine) Close() {
+	close(e.done)
+	e.running.Wait()
+	for _, wh := range e.webhooks {
+		wh.stop()
+	}
+	if e.derived != nil {
+		e.derived.Close()
+	}
+}
+
+func deriveKey(template string, f *Firing) string {
+	return strings.NewReplacer(
+		"{trigger}", f.Trigger,
+		"{namespace}", f.Namespace,
+		"{key}", f.Key,
+	).Replace(template)
+}
+
+// blockWrites returns the public writes of the valid endorser transactions
+// of the block, in the order of the transactions and of their writes.
+func blockWrites(channelID string, block *common.Block) ([]*Firing, error) {
+	var writes []*Firing
+	txsFilter := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for txNum, envBytes := range block.Data.Data {
+		if txsFilter.IsInvalid(txNum) {
+			continue
+		}
+		env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+		if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		respPayload, err := protoutil.GetActionFromEnvelope(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			return nil, err
+		}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				writes = append(writes, &Firing{
+					ChannelID:   channelID,
+					BlockNumber: block.Header.Number,
+					TxNumber:    uint64(txNum),
+					TxID:        chdr.TxId,
+					Namespace:   nsRWSet.NameSpace,
+					Key:         kvWrite.Key,
+					Value:       kvWrite.Value,
+					IsDelete:    kvWrite.IsDelete,
+				})
+			}
+		}
+	}
+	return writes, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package triggers
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+type write struct {
+	ns, key, value string
+}
+
+func endorserTx(t *testing.T, txID string, writes ...write) []byte {
+	txRWSet := &rwsetutil.TxRwSet{}
+	for _, w := range writes {
+		kvWrite := &kvrwset.KVWrite{Key: w.key, Value: []byte(w.value), IsDelete: w.value == ""}
+		txRWSet.NsRwSets = append(txRWSet.NsRwSets, &rwsetutil.NsRwSet{
+			NameSpace: w.ns,
+			KvRwSet:   &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{kvWrite}},
+		})
+	}
+	results, err := txRWSet.ToProtoBytes()
+	require.NoError(t, err)
+
+	prp := protoutil.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{Results: results}),
+	})
+	cap := protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp},
+	})
+	tx := protoutil.MarshalOrPanic(&pb.Transaction{
+		Actions: []*pb.TransactionAction{{Payload: cap}},
+	})
+	chdr := protoutil.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0)
+	chdr.TxId = txID
+	payload := &common.Payload{
+		Header: protoutil.MakePayloadHeader(chdr, protoutil.MakeSignatureHeader(nil, nil)),
+		Data:   tx,
+	}
+	return protoutil.MarshalOrPanic(&common.Envelope{Payload: protoutil.MarshalOrPanic(payload)})
+}
+
+func commitEvent(blockNum uint64, flags []pb.TxValidationCode, txs ...[]byte) *ledger.CommitEvent {
+	block := protoutil.NewBlock(blockNum, nil)
+	block.Data.Data = txs
+	txsFilter := txflags.NewWithValues(len(txs), pb.TxValidationCode_VALID)
+	for i, flag := range flags {
+		txsFilter.SetFlag(i, flag)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	return &ledger.CommitEvent{LedgerID: "mychannel", Block: block}
+}
+
+func TestEngineEvents(t *testing.T) {
+	engine, err := New([]peer.Trigger{
+		{
+			Name:      "assets",
+			Namespace: "asset*",
+			Key:       "car*",
+			Actions:   []peer.TriggerAction{{Type: ActionEvent}},
+		},
+		{
+			Name:      "other-channel",
+			Channel:   "otherchannel",
+			Namespace: "*",
+			Actions:   []peer.TriggerAction{{Type: ActionEvent}},
+		},
+	}, "")
+	require.NoError(t, err)
+	defer engine.Close()
+	require.Equal(t, "triggers", engine.Name())
+
+	err = engine.HandleCommittedBlock(commitEvent(
+		5,
+		[]pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_VALID},
+		endorserTx(t, "tx1", write{"assetcc", "car1", "red"}, write{"assetcc", "bike1", "blue"}),
+		endorserTx(t, "tx2", write{"assetcc", "car2", "green"}),
+		endorserTx(t, "tx3", write{"othercc", "car3", "red"}, write{"assetcc", "car1", ""}),
+	))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(engine.Events("", 0)) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []*Firing{
+		{Sequence: 1, Trigger: "assets", ChannelID: "mychannel", BlockNumber: 5, TxNumber: 0, TxID: "tx1", Namespace: "assetcc", Key: "car1", Value: []byte("red")},
+		{Sequence: 2, Trigger: "assets", ChannelID: "mychannel", BlockNumber: 5, TxNumber: 2, TxID: "tx3", Namespace: "assetcc", Key: "car1", IsDelete: true},
+	}, engine.Events("", 0))
+	require.Len(t, engine.Events("mychannel", 1), 1)
+	require.Empty(t, engine.Events("otherchannel", 0))
+}
+
+func TestEngineDerivedKeys(t *testing.T) {
+	path, err := ioutil.TempDir("", "triggers")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	engine, err := New([]peer.Trigger{
+		{
+			Name:      "latest",
+			Namespace: "assetcc",
+			Actions: []peer.TriggerAction{
+				{Type: ActionDerivedKey},
+				{Type: ActionDerivedKey, DerivedKey: "last-write/{key}"},
+			},
+		},
+	}, path)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	err = engine.HandleCommittedBlock(commitEvent(
+		1, nil,
+		endorserTx(t, "tx1", write{"assetcc", "car1", "red"}),
+		endorserTx(t, "tx2", write{"assetcc", "car1", "blue"}),
+	))
+	require.NoError(t, err)
+
+	var value []byte
+	require.Eventually(t, func() bool {
+		value, err = engine.DerivedValue("mychannel", "last-write/car1")
+		return err == nil && value != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	value, err = engine.DerivedValue("mychannel", "latest/assetcc/car1")
+	require.NoError(t, err)
+	firing := &Firing{}
+	require.NoError(t, json.Unmarshal(value, firing))
+	require.Equal(t, "tx2", firing.TxID)
+	require.Equal(t, []byte("blue"), firing.Value)
+
+	value, err = engine.DerivedValue("otherchannel", "last-write/car1")
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestEngineWebhook(t *testing.T) {
+	requests := make(chan *webhookRequest, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &webhookRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		requests <- req
+	}))
+	defer server.Close()
+
+	rootCert, err := ioutil.TempFile("", "webhook-ca")
+	require.NoError(t, err)
+	defer os.Remove(rootCert.Name())
+	err = pem.Encode(rootCert, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, err)
+	require.NoError(t, rootCert.Close())
+
+	engine, err := New([]peer.Trigger{
+		{
+			Name:      "notify",
+			Namespace: "assetcc",
+			Actions:   []peer.TriggerAction{{Type: ActionWebhook, URL: server.URL, RootCert: rootCert.Name(), Timeout: "1s"}},
+		},
+	}, "")
+	require.NoError(t, err)
+	defer engine.Close()
+
+	err = engine.HandleCommittedBlock(commitEvent(3, nil, endorserTx(t, "tx1", write{"assetcc", "car1", "red"})))
+	require.NoError(t, err)
+
+	select {
+	case req := <-requests:
+		require.Equal(t, "mychannel", req.ChannelID)
+		require.Equal(t, uint64(3), req.BlockNumber)
+		require.Len(t, req.Firings, 1)
+		require.Equal(t, "car1", req.Firings[0].Key)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := New([]peer.Trigger{
+		{Name: "t", Namespace: "cc", Actions: []peer.TriggerAction{{Type: ActionWebhook, URL: "https://localhost", Timeout: "soon"}}},
+	}, "")
+	require.EqualError(t, err, `invalid timeout for webhook action of trigger [t]: time: invalid duration "soon"`)
+
+	_, err = New([]peer.Trigger{
+		{Name: "t", Namespace: "cc", Actions: []peer.TriggerAction{{Type: ActionWebhook, URL: "http://localhost"}}},
+	}, "")
+	require.EqualError(t, err, "webhook of trigger [t] has an invalid url [http://localhost], an https url is required")
+
+	_, err = New([]peer.Trigger{
+		{Name: "t", Namespace: "cc", Key: "[a", Actions: []peer.TriggerAction{{Type: ActionEvent}}},
+	}, "")
+	require.EqualError(t, err, "invalid pattern [[a] of trigger [t]: syntax error in pattern")
+
+	_, err = New([]peer.Trigger{
+		{Name: "t", Namespace: "cc", Actions: []peer.TriggerAction{{Type: "email"}}},
+	}, "")
+	require.EqualError(t, err, "trigger [t] has an action of unknown type [email]")
+}
+
+func TestEventFeed(t *testing.T) {
+	feed := NewEventFeed(2)
+	feed.Append(&Firing{ChannelID: "a"}, &Firing{ChannelID: "b"}, &Firing{ChannelID: "a"})
+	firings := feed.Since("", 0)
+	require.Len(t, firings, 2)
+	require.Equal(t, uint64(2), firings[0].Sequence)
+	require.Equal(t, uint64(3), firings[1].Sequence)
+	require.Len(t, feed.Since("a", 0), 1)
+	require.Empty(t, feed.Since("", 3))
+}
@@ -67,6 +67,28 @@ type ChannelPolicyReferenceProvider interface {
 	NewPolicy(channelConfigPolicyReference string) (policies.Policy, error)
 }
 
+// Capabilities reports which channel capabilities affect the evaluation of application policies.
+type Capabilities interface {
+	// WeightedSignaturePolicies returns true if version 1 signature policies
+	// are evaluated with weighted semantics.
+	WeightedSignaturePolicies() bool
+}
+
+// weightedPolicyProvider creates signature policies whose weighted semantics
+// follow the channel capabilities at the time the policy is created
+type weightedPolicyProvider struct {
+	deserializer msp.IdentityDeserializer
+	capabilities Capabilities
+}
+
+func (w *weightedPolicyProvider) NewPolicy(signaturePolicy *common.SignaturePolicyEnvelope) (policies.Policy, error) {
+	pp := &cauthdsl.EnvelopeBasedPolicyProvider{Deserializer: w.deserializer}
+	if signaturePolicy != nil && signaturePolicy.Version == cauthdsl.WeightedPolicyVersion {
+		pp.Weighted = w.capabilities.WeightedSignaturePolicies()
+	}
+	return pp.NewPolicy(signaturePolicy)
+}
+
 type ApplicationPolicyEvaluator struct {
 	signaturePolicyProvider        SignaturePolicyProvider
 	channelPolicyReferenceProvider ChannelPolicyReferenceProvider
@@ -111,15 +133,21 @@ func (d *dynamicPolicyManager) GetPolicy(id string) (policies.Policy, bool) {
 	return mgr.GetPolicy(id)
 }
 
-// New returns an evaluator for application policies
-func New(deserializer msp.IdentityDeserializer, channel string, channelPolicyManagerGetter policies.ChannelPolicyManagerGetter) (*ApplicationPolicyEvaluator, error) {
+// New returns an evaluator for application policies. Version 1 signature policies are
+// evaluated with weighted semantics only if capabilities is not nil and enables them.
+func New(deserializer msp.IdentityDeserializer, channel string, channelPolicyManagerGetter policies.ChannelPolicyManagerGetter, capabilities Capabilities) (*ApplicationPolicyEvaluator, error) {
 	mgr := channelPolicyManagerGetter.Manager(channel)
 	if mgr == nil {
 		return nil, errors.Errorf("failed to retrieve policy manager for channel %s", channel)
 	}
 
+	var spp SignaturePolicyProvider = &cauthdsl.EnvelopeBasedPolicyProvider{Deserializer: deserializer}
+	if capabilities != nil {
+		spp = &weightedPolicyProvider{deserializer: deserializer, capabilities: capabilities}
+	}
+
 	return &ApplicationPolicyEvaluator{
-		signaturePolicyProvider: &cauthdsl.EnvelopeBasedPolicyProvider{Deserializer: deserializer},
+		signaturePolicyProvider: spp,
 		channelPolicyReferenceProvider: &ChannelPolicyReferenceProviderImpl{Manager: &dynamicPolicyManager{
 			channelID:                  channel,
 			channelPolicyManagerGetter: channelPolicyManagerGetter,
@@ -51,6 +51,50 @@ func TestComponentIntegrationSignaturePolicyEnv(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+type weightedCapabilities bool
+
+func (w weightedCapabilities) WeightedSignaturePolicies() bool {
+	return bool(w)
+}
+
+func TestWeightedSignaturePolicyEnv(t *testing.T) {
+	idds := &mocks.IdentityDeserializer{}
+	id := &mocks.Identity{}
+	mcpmg := &mocks.ChannelPolicyManagerGetter{}
+	mcpmg.On("Manager", "channel").Return(&mocks.PolicyManager{}, true)
+
+	// the member of msp counts twice
+	spenv := policydsl.SignedByMspMember("msp")
+	spenv.Rule = policydsl.NOutOf(2, []*common.SignaturePolicy{policydsl.SignedBy(0), policydsl.SignedBy(0)})
+	spenv.Version = cauthdsl.WeightedPolicyVersion
+	mspenv := protoutil.MarshalOrPanic(&peer.ApplicationPolicy{
+		Type: &peer.ApplicationPolicy_SignaturePolicy{
+			SignaturePolicy: spenv,
+		},
+	})
+	signatureSet := []*protoutil.SignedData{{
+		Identity:  []byte("guess who"),
+		Data:      []byte("batti"),
+		Signature: []byte("lei"),
+	}}
+
+	idds.On("DeserializeIdentity", []byte("guess who")).Return(id, nil)
+	id.On("GetIdentifier").Return(&msp.IdentityIdentifier{Id: "id", Mspid: "msp"})
+	id.On("SatisfiesPrincipal", mock.Anything).Return(nil)
+	id.On("Verify", []byte("batti"), []byte("lei")).Return(nil)
+
+	for _, capabilities := range []Capabilities{nil, weightedCapabilities(false)} {
+		ev, err := New(idds, "channel", mcpmg, capabilities)
+		assert.NoError(t, err)
+		err = ev.Evaluate(mspenv, signatureSet)
+		assert.EqualError(t, err, "signature set did not satisfy policy")
+	}
+
+	ev, err := New(idds, "channel", mcpmg, weightedCapabilities(true))
+	assert.NoError(t, err)
+	assert.NoError(t, ev.Evaluate(mspenv, signatureSet))
+}
+
 func TestEvaluator(t *testing.T) {
 	okEval := &mocks.Policy{}
 	nokEval := &mocks.Policy{}
@@ -121,14 +165,14 @@ func TestEvaluator(t *testing.T) {
 func TestChannelPolicyReference(t *testing.T) {
 	mcpmg := &mocks.ChannelPolicyManagerGetter{}
 	mcpmg.On("Manager", "channel").Return(nil, false).Once()
-	ape, err := New(nil, "channel", mcpmg)
+	ape, err := New(nil, "channel", mcpmg, nil)
 	assert.Error(t, err)
 	assert.Nil(t, ape)
 	assert.Contains(t, err.Error(), "failed to retrieve policy manager for channel")
 
 	mm := &mocks.PolicyManager{}
 	mcpmg.On("Manager", "channel").Return(mm, true).Once()
-	ape, err = New(nil, "channel", mcpmg)
+	ape, err = New(nil, "channel", mcpmg, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, ape)
 
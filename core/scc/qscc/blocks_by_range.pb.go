@@ -0,0 +1,95 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: blocks_by_range.proto
+
+package qscc
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/hyperledger/fabric-protos-go/common"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// BlockRange is returned by GetBlocksByRange, with the blocks of the requested
+// range which fit within the size cap of a response
+type BlockRange struct {
+	Blocks []*common.Block `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	// continuation_token is passed as start of the next query to get the rest of
+	// the range, and is empty once the whole range was returned
+	ContinuationToken    string   `protobuf:"bytes,2,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockRange) Reset()         { *m = BlockRange{} }
+func (m *BlockRange) String() string { return proto.CompactTextString(m) }
+func (*BlockRange) ProtoMessage()    {}
+func (*BlockRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7383b73f77d455a1, []int{0}
+}
+
+func (m *BlockRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockRange.Unmarshal(m, b)
+}
+func (m *BlockRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockRange.Marshal(b, m, deterministic)
+}
+func (m *BlockRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockRange.Merge(m, src)
+}
+func (m *BlockRange) XXX_Size() int {
+	return xxx_messageInfo_BlockRange.Size(m)
+}
+func (m *BlockRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockRange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockRange proto.InternalMessageInfo
+
+func (m *BlockRange) GetBlocks() []*common.Block {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+func (m *BlockRange) GetContinuationToken() string {
+	if m != nil {
+		return m.ContinuationToken
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*BlockRange)(nil), "qscc.BlockRange")
+}
+
+func init() { proto.RegisterFile("blocks_by_range.proto", fileDescriptor_7383b73f77d455a1) }
+
+var fileDescriptor_7383b73f77d455a1 = []byte{
+	// 177 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4d, 0xca, 0xc9, 0x4f,
+	0xce, 0x2e, 0x8e, 0x4f, 0xaa, 0x8c, 0x2f, 0x4a, 0xcc, 0x4b, 0x4f, 0xd5, 0x2b, 0x28, 0xca, 0x2f,
+	0xc9, 0x17, 0x62, 0x29, 0x2c, 0x4e, 0x4e, 0x96, 0x12, 0x4e, 0xce, 0xcf, 0xcd, 0xcd, 0xcf, 0xd3,
+	0x87, 0x50, 0x10, 0x29, 0xa5, 0x24, 0x2e, 0x2e, 0x27, 0x90, 0x9e, 0x20, 0x90, 0x72, 0x21, 0x55,
+	0x2e, 0x36, 0x88, 0x09, 0x12, 0x8c, 0x0a, 0xcc, 0x1a, 0xdc, 0x46, 0xbc, 0x7a, 0x50, 0xc5, 0x10,
+	0x35, 0x50, 0x49, 0x21, 0x5d, 0x2e, 0xa1, 0xe4, 0xfc, 0xbc, 0x92, 0xcc, 0xbc, 0xd2, 0xc4, 0x92,
+	0xcc, 0xfc, 0xbc, 0xf8, 0x92, 0xfc, 0xec, 0xd4, 0x3c, 0x09, 0x26, 0x05, 0x46, 0x0d, 0xce, 0x20,
+	0x41, 0x64, 0x99, 0x10, 0x90, 0x84, 0x93, 0x6e, 0x94, 0x76, 0x7a, 0x66, 0x49, 0x46, 0x69, 0x12,
+	0xc8, 0x34, 0xfd, 0x8c, 0xca, 0x82, 0xd4, 0xa2, 0x9c, 0xd4, 0x94, 0xf4, 0xd4, 0x22, 0xfd, 0xb4,
+	0xc4, 0xa4, 0xa2, 0xcc, 0x64, 0xfd, 0xe4, 0xfc, 0xa2, 0x54, 0xfd, 0xe2, 0xe4, 0x64, 0x7d, 0x90,
+	0x3b, 0x93, 0xd8, 0xc0, 0x2e, 0x33, 0x06, 0x0c, 0x00, 0x9f, 0x0a, 0xb4, 0xb5, 0xcd, 0x00, 0x00,
+	0x00,
+}
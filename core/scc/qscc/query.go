@@ -7,14 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package qscc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"strconv"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/peer/idemixaudit"
+	"github.com/hyperledger/fabric/internal/pkg/configview"
 	"github.com/hyperledger/fabric/protoutil"
 )
 
@@ -23,12 +32,22 @@ type LedgerGetter interface {
 	GetLedger(cid string) ledger.PeerLedger
 }
 
-// New returns an instance of QSCC.
+// IdemixAuditor returns the audit escrows of the creators of transactions to
+// the auditors of a channel, recording the audit accesses.
+type IdemixAuditor interface {
+	Audit(req *idemixaudit.Request, ledger idemixaudit.TransactionGetter, authorize func() error) (*idemixaudit.Report, error)
+}
+
+// New returns an instance of QSCC. The idemix auditor is nil when the idemix
+// audit is not enabled on the peer.
 // Typically this is called once per peer.
-func New(aclProvider aclmgmt.ACLProvider, ledgers LedgerGetter) *LedgerQuerier {
+func New(aclProvider aclmgmt.ACLProvider, ledgers LedgerGetter, idemixAuditor IdemixAuditor) *LedgerQuerier {
 	return &LedgerQuerier{
-		aclProvider: aclProvider,
-		ledgers:     ledgers,
+		aclProvider:   aclProvider,
+		ledgers:       ledgers,
+		idemixAuditor: idemixAuditor,
+		maxRangeSize:  defaultMaxRangeSize,
+		maxRangeCount: defaultMaxRangeCount,
 	}
 }
 
@@ -40,20 +59,42 @@ func (e *LedgerQuerier) Chaincode() shim.Chaincode { return e }
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetBlocksByRange returns a range of blocks
+// - GetChannelConfigView returns the decoded channel config
+// - GetChannelMembership returns the membership of the channel at a block
+// - GetBlockHashing returns the hash algorithms of the block headers
+// - AuditIdemixTransactions returns the audit escrows of the creators of idemix transactions
 type LedgerQuerier struct {
-	aclProvider aclmgmt.ACLProvider
-	ledgers     LedgerGetter
+	aclProvider   aclmgmt.ACLProvider
+	ledgers       LedgerGetter
+	idemixAuditor IdemixAuditor
+	maxRangeSize  int
+	maxRangeCount int
 }
 
 var qscclogger = flogging.MustGetLogger("qscc")
 
 // These are function names from Invoke first parameter
 const (
-	GetChainInfo       string = "GetChainInfo"
-	GetBlockByNumber   string = "GetBlockByNumber"
-	GetBlockByHash     string = "GetBlockByHash"
-	GetTransactionByID string = "GetTransactionByID"
-	GetBlockByTxID     string = "GetBlockByTxID"
+	GetChainInfo            string = "GetChainInfo"
+	GetBlockByNumber        string = "GetBlockByNumber"
+	GetBlockByHash          string = "GetBlockByHash"
+	GetTransactionByID      string = "GetTransactionByID"
+	GetBlockByTxID          string = "GetBlockByTxID"
+	GetBlocksByRange        string = "GetBlocksByRange"
+	GetChannelConfigView    string = "GetChannelConfigView"
+	GetChannelMembership    string = "GetChannelMembership"
+	GetBlockHashing         string = "GetBlockHashing"
+	AuditIdemixTransactions string = "AuditIdemixTransactions"
+)
+
+const (
+	// defaultMaxRangeSize caps the size in bytes of the blocks returned by a
+	// single GetBlocksByRange invocation, well below the gRPC message size limit
+	defaultMaxRangeSize = 10 * 1024 * 1024
+	// defaultMaxRangeCount caps the number of blocks returned by a single
+	// GetBlocksByRange invocation
+	defaultMaxRangeCount = 100
 )
 
 // Init is called once per chain when the chain is created.
@@ -72,6 +113,19 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetBlocksByRange: Return a BlockRange object marshalled in bytes, with the
+// blocks from the number or continuation token in args[2] to the number in args[3]
+// included. When args[4] is "true", only the headers and metadata are returned
+// # GetChannelConfigView: Return the current channel config as JSON, with its
+// policies, MSP certificates and capabilities rendered in readable form
+// # GetChannelMembership: Return as JSON the organizations of the channel, with
+// their root certificates and endpoints, as of the block number in args[2]
+// # GetBlockHashing: Return as JSON a BlockHashing object describing the hash
+// algorithms GetBlockByHash looks blocks up by
+// # AuditIdemixTransactions: Return as JSON a Report holding the audit escrows
+// of the idemix creators of the transactions whose IDs are in args[2:], which
+// only the auditors can decrypt. Every call is recorded on the idemix audit
+// trail of the peer, whether access is granted or not
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -96,7 +150,7 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return shim.Error(fmt.Sprintf("Rejecting invoke of QSCC from another chaincode because of potential for deadlocks, original invocation for '%s'", name))
 	}
 
-	if fname != GetChainInfo && len(args) < 3 {
+	if fname != GetChainInfo && fname != GetChannelConfigView && fname != GetBlockHashing && len(args) < 3 {
 		return shim.Error(fmt.Sprintf("missing 3rd argument for %s", fname))
 	}
 
@@ -107,6 +161,11 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 
 	qscclogger.Debugf("Invoke function: %s on chain: %s", fname, cid)
 
+	// The audit checks its ACL itself, to record the denied accesses too
+	if fname == AuditIdemixTransactions {
+		return e.auditIdemixTransactions(targetLedger, cid, sp, args[2:])
+	}
+
 	// Handle ACL:
 	res := getACLResource(fname)
 	if err = e.aclProvider.CheckACL(res, cid, sp); err != nil {
@@ -124,6 +183,14 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
 		return getBlockByTxID(targetLedger, args[2])
+	case GetBlocksByRange:
+		return e.getBlocksByRange(targetLedger, args[2:])
+	case GetChannelConfigView:
+		return getChannelConfigView(targetLedger)
+	case GetChannelMembership:
+		return getChannelMembership(targetLedger, cid, args[2])
+	case GetBlockHashing:
+		return getBlockHashing(targetLedger, cid)
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
@@ -223,6 +290,267 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+func (e *LedgerQuerier) getBlocksByRange(vledger ledger.PeerLedger, args [][]byte) pb.Response {
+	if len(args) < 2 {
+		return shim.Error(fmt.Sprintf("missing 4th argument for %s", GetBlocksByRange))
+	}
+	start, err := strconv.ParseUint(string(args[0]), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse start block number with error %s", err))
+	}
+	end, err := strconv.ParseUint(string(args[1]), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse end block number with error %s", err))
+	}
+	headersOnly := false
+	if len(args) > 2 {
+		if headersOnly, err = strconv.ParseBool(string(args[2])); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to parse headers only flag with error %s", err))
+		}
+	}
+	if start > end {
+		return shim.Error(fmt.Sprintf("Invalid block range, start %d is greater than end %d", start, end))
+	}
+
+	binfo, err := vledger.GetBlockchainInfo()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block info with error %s", err))
+	}
+	if start >= binfo.Height {
+		return shim.Error(fmt.Sprintf("Failed to get block number %d, the ledger height is %d", start, binfo.Height))
+	}
+	if end >= binfo.Height {
+		end = binfo.Height - 1
+	}
+
+	blockRange := &BlockRange{}
+	size := 0
+	for bnum := start; bnum <= end; bnum++ {
+		if len(blockRange.Blocks) == e.maxRangeCount {
+			blockRange.ContinuationToken = strconv.FormatUint(bnum, 10)
+			break
+		}
+		block, err := vledger.GetBlockByNumber(bnum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+		}
+		if headersOnly {
+			block = &common.Block{Header: block.Header, Metadata: block.Metadata}
+		}
+		// at least one block is always returned, whatever its size
+		size += proto.Size(block)
+		if len(blockRange.Blocks) > 0 && size > e.maxRangeSize {
+			blockRange.ContinuationToken = strconv.FormatUint(bnum, 10)
+			break
+		}
+		blockRange.Blocks = append(blockRange.Blocks, block)
+	}
+
+	bytes, err := protoutil.Marshal(blockRange)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getChannelConfigView(vledger ledger.PeerLedger) pb.Response {
+	configBlock, err := peer.ConfigBlockFromLedger(vledger)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get the config block, error %s", err))
+	}
+	config, err := configview.ConfigFromBlock(configBlock)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to extract the config, error %s", err))
+	}
+
+	bytes, err := configview.Marshal(config)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to render the channel config, error %s", err))
+	}
+
+	return shim.Success(bytes)
+}
+
+func getChannelMembership(vledger ledger.PeerLedger, cid string, number []byte) pb.Response {
+	bnum, err := strconv.ParseUint(string(number), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse block number with error %s", err))
+	}
+	block, err := vledger.GetBlockByNumber(bnum)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+	}
+	configBlock := block
+	if !protoutil.IsConfigBlock(block) {
+		lastConfig, err := protoutil.GetLastConfigIndexFromBlock(block)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get the last config index of block %d, error %s", bnum, err))
+		}
+		if configBlock, err = vledger.GetBlockByNumber(lastConfig); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get config block number %d, error %s", lastConfig, err))
+		}
+	}
+	config, err := configview.ConfigFromBlock(configBlock)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to extract the config, error %s", err))
+	}
+
+	membership, err := configview.MembershipFromConfig(config)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to extract the channel membership, error %s", err))
+	}
+	membership.ChannelID = cid
+	membership.BlockNumber = bnum
+	membership.ConfigBlockNumber = configBlock.Header.Number
+
+	bytes, err := json.MarshalIndent(membership, "", "\t")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+// BlockHashing describes the hashes of the block headers of a channel, as
+// returned by GetBlockHashing. The algorithms are found out from the ledger of
+// the peer rather than assumed.
+type BlockHashing struct {
+	ChannelID string `json:"channel_id"`
+	// HashingAlgorithm is the hashing algorithm of the channel config.
+	HashingAlgorithm string `json:"hashing_algorithm"`
+	// HeaderHashAlgorithm is the algorithm of the previous hashes of the
+	// block headers, unknown until the channel has two blocks.
+	HeaderHashAlgorithm string `json:"header_hash_algorithm,omitempty"`
+	// LookupHashAlgorithm is the algorithm clients should compute the header
+	// hashes with for GetBlockByHash: the hashing algorithm of the channel
+	// config if the blocks are looked up by it.
+	LookupHashAlgorithm string `json:"lookup_hash_algorithm"`
+	// LookupHashAlgorithms are all the algorithms of the header hashes
+	// GetBlockByHash looks blocks up by.
+	LookupHashAlgorithms []string `json:"lookup_hash_algorithms"`
+}
+
+// headerHashes are the functions the block headers may be hashed with
+var headerHashes = []struct {
+	algorithm string
+	hash      func(*common.BlockHeader) []byte
+}{
+	{bccsp.SHA256, protoutil.BlockHeaderHash},
+	{bccsp.SM3, protoutil.BlockHeaderHashSM3},
+}
+
+func getBlockHashing(vledger ledger.PeerLedger, cid string) pb.Response {
+	configBlock, err := peer.ConfigBlockFromLedger(vledger)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get the config block, error %s", err))
+	}
+	config, err := configview.ConfigFromBlock(configBlock)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to extract the config, error %s", err))
+	}
+	hashingAlgorithm := &common.HashingAlgorithm{}
+	if value, ok := config.GetChannelGroup().GetValues()[channelconfig.HashingAlgorithmKey]; ok {
+		if err := proto.Unmarshal(value.Value, hashingAlgorithm); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to unmarshal the hashing algorithm, error %s", err))
+		}
+	}
+
+	info, err := vledger.GetBlockchainInfo()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block info, error %s", err))
+	}
+	lastBlock, err := vledger.GetBlockByNumber(info.Height - 1)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", info.Height-1, err))
+	}
+	var previousHeader *common.BlockHeader
+	if info.Height > 1 {
+		previousBlock, err := vledger.GetBlockByNumber(info.Height - 2)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", info.Height-2, err))
+		}
+		previousHeader = previousBlock.Header
+	}
+
+	hashing := &BlockHashing{
+		ChannelID:            cid,
+		HashingAlgorithm:     hashingAlgorithm.Name,
+		LookupHashAlgorithms: []string{},
+	}
+	for _, headerHash := range headerHashes {
+		if previousHeader != nil && bytes.Equal(lastBlock.Header.PreviousHash, headerHash.hash(previousHeader)) {
+			hashing.HeaderHashAlgorithm = headerHash.algorithm
+		}
+		if block, err := vledger.GetBlockByHash(headerHash.hash(lastBlock.Header)); err == nil && block.Header.Number == lastBlock.Header.Number {
+			hashing.LookupHashAlgorithms = append(hashing.LookupHashAlgorithms, headerHash.algorithm)
+		}
+	}
+	for _, algorithm := range hashing.LookupHashAlgorithms {
+		if hashing.LookupHashAlgorithm == "" || algorithm == hashingAlgorithm.Name {
+			hashing.LookupHashAlgorithm = algorithm
+		}
+	}
+
+	payload, err := json.MarshalIndent(hashing, "", "\t")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(payload)
+}
+
+func (e *LedgerQuerier) auditIdemixTransactions(vledger ledger.PeerLedger, cid string, sp *pb.SignedProposal, rawTxIDs [][]byte) pb.Response {
+	if e.idemixAuditor == nil {
+		return shim.Error("idemix audit is not enabled")
+	}
+	requester, err := proposalCreator(sp)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get the creator of the proposal, error %s", err))
+	}
+	req := &idemixaudit.Request{
+		ChannelID: cid,
+		Requester: requester,
+	}
+	for _, txID := range rawTxIDs {
+		req.TxIDs = append(req.TxIDs, string(txID))
+	}
+
+	report, err := e.idemixAuditor.Audit(req, vledger, func() error {
+		res := getACLResource(AuditIdemixTransactions)
+		if err := e.aclProvider.CheckACL(res, cid, sp); err != nil {
+			return fmt.Errorf("access denied for [%s][%s]: [%s]", AuditIdemixTransactions, cid, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	bytes, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func proposalCreator(sp *pb.SignedProposal) ([]byte, error) {
+	prop, err := protoutil.UnmarshalProposal(sp.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	hdr, err := protoutil.UnmarshalHeader(prop.Header)
+	if err != nil {
+		return nil, err
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	return shdr.Creator, nil
+}
+
 func getACLResource(fname string) string {
 	return "qscc/" + fname
 }
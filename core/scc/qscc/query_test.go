@@ -7,11 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package qscc
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-chaincode-go/shimtest"
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -25,6 +28,8 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt/ledgermgmttest"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/peer/idemixaudit"
+	"github.com/hyperledger/fabric/internal/pkg/configview"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -196,6 +201,70 @@ func TestQueryGetBlockByHash(t *testing.T) {
 	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByHash should have failed with nil hash")
 }
 
+func TestQueryGetBlockByHashSM3(t *testing.T) {
+	chainid := "mytestchainid12"
+	path := tempDir(t, "test12")
+	defer os.RemoveAll(path)
+
+	stub, p, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+	block := addBlockForTesting(t, chainid, p)
+
+	// the block is looked up by either hash of its header
+	for i, hash := range [][]byte{protoutil.BlockHeaderHash(block.Header), protoutil.BlockHeaderHashSM3(block.Header)} {
+		args := [][]byte{[]byte(GetBlockByHash), []byte(chainid), hash}
+		prop := resetProvider(resources.Qscc_GetBlockByHash, chainid, nil, nil)
+		res := stub.MockInvokeWithSignedProposal(strconv.Itoa(i), args, prop)
+		require.Equal(t, int32(shim.OK), res.Status, res.Message)
+		retrieved, err := protoutil.UnmarshalBlock(res.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, block.Header.Number, retrieved.Header.Number)
+	}
+}
+
+func TestQueryGetBlockHashing(t *testing.T) {
+	chainid := "mytestchainid13"
+	path := tempDir(t, "test13")
+	defer os.RemoveAll(path)
+
+	stub, p, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+
+	args := [][]byte{[]byte(GetBlockHashing), []byte(chainid)}
+	prop := resetProvider(resources.Qscc_GetBlockHashing, chainid, nil, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	// the header hash algorithm is unknown until the channel has two blocks
+	hashing := &BlockHashing{}
+	require.NoError(t, json.Unmarshal(res.Payload, hashing))
+	assert.Equal(t, &BlockHashing{
+		ChannelID:            chainid,
+		HashingAlgorithm:     "SHA256",
+		LookupHashAlgorithm:  "SHA256",
+		LookupHashAlgorithms: []string{"SHA256", "SM3"},
+	}, hashing)
+
+	addBlockForTesting(t, chainid, p)
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	hashing = &BlockHashing{}
+	require.NoError(t, json.Unmarshal(res.Payload, hashing))
+	assert.Equal(t, "SHA256", hashing.HeaderHashAlgorithm)
+
+	// the ACL is checked
+	prop = resetProvider(resources.Qscc_GetBlockHashing, chainid, nil, errors.New("Failed access control"))
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Failed access control")
+}
+
 func TestQueryGetBlockByTxID(t *testing.T) {
 	chainid := "mytestchainid5"
 	path := tempDir(t, "test5")
@@ -446,6 +515,175 @@ func TestQueryGeneratedBlock(t *testing.T) {
 	}
 }
 
+func TestQueryGetBlocksByRange(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := tempDir(t, "test9")
+	defer os.RemoveAll(path)
+
+	_, p, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+	block1 := addBlockForTesting(t, chainid, p)
+
+	e := New(mockAclProvider, p, nil)
+	stub := shimtest.NewMockStub("LedgerQuerier", e)
+	invoke := func(args ...string) (*BlockRange, peer2.Response) {
+		fargs := [][]byte{[]byte(GetBlocksByRange), []byte(chainid)}
+		for _, arg := range args {
+			fargs = append(fargs, []byte(arg))
+		}
+		prop := resetProvider(resources.Qscc_GetBlocksByRange, chainid, nil, nil)
+		res := stub.MockInvokeWithSignedProposal("1", fargs, prop)
+		blockRange := &BlockRange{}
+		if res.Status == shim.OK {
+			require.NoError(t, proto.Unmarshal(res.Payload, blockRange))
+		}
+		return blockRange, res
+	}
+
+	// the end of the range is capped to the ledger height
+	blockRange, res := invoke("0", "10")
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	require.Len(t, blockRange.Blocks, 2)
+	assert.Equal(t, uint64(0), blockRange.Blocks[0].Header.Number)
+	assert.True(t, proto.Equal(block1, blockRange.Blocks[1]))
+	assert.Empty(t, blockRange.ContinuationToken)
+
+	// only the headers and metadata are returned
+	blockRange, res = invoke("1", "1", "true")
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	require.Len(t, blockRange.Blocks, 1)
+	assert.True(t, proto.Equal(block1.Header, blockRange.Blocks[0].Header))
+	assert.True(t, proto.Equal(block1.Metadata, blockRange.Blocks[0].Metadata))
+	assert.Nil(t, blockRange.Blocks[0].Data)
+
+	// a response beyond the count cap carries a continuation token
+	e.maxRangeCount = 1
+	blockRange, res = invoke("0", "1")
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	require.Len(t, blockRange.Blocks, 1)
+	assert.Equal(t, "1", blockRange.ContinuationToken)
+	blockRange, res = invoke(blockRange.ContinuationToken, "1")
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	require.Len(t, blockRange.Blocks, 1)
+	assert.Equal(t, uint64(1), blockRange.Blocks[0].Header.Number)
+	assert.Empty(t, blockRange.ContinuationToken)
+
+	// a response beyond the size cap holds at least one block
+	e.maxRangeCount = defaultMaxRangeCount
+	e.maxRangeSize = 1
+	blockRange, res = invoke("0", "1")
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	require.Len(t, blockRange.Blocks, 1)
+	assert.Equal(t, "1", blockRange.ContinuationToken)
+
+	for _, tc := range []struct {
+		args   []string
+		errMsg string
+	}{
+		{args: []string{"0"}, errMsg: "missing 4th argument for GetBlocksByRange"},
+		{args: []string{"a", "1"}, errMsg: "Failed to parse start block number"},
+		{args: []string{"0", "b"}, errMsg: "Failed to parse end block number"},
+		{args: []string{"0", "1", "c"}, errMsg: "Failed to parse headers only flag"},
+		{args: []string{"1", "0"}, errMsg: "Invalid block range, start 1 is greater than end 0"},
+		{args: []string{"2", "3"}, errMsg: "Failed to get block number 2, the ledger height is 2"},
+	} {
+		_, res = invoke(tc.args...)
+		assert.Equal(t, int32(shim.ERROR), res.Status)
+		assert.Contains(t, res.Message, tc.errMsg)
+	}
+}
+
+func TestQueryGetChannelConfigView(t *testing.T) {
+	chainid := "mytestchainid10"
+	path := tempDir(t, "test10")
+	defer os.RemoveAll(path)
+
+	stub, _, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+
+	args := [][]byte{[]byte(GetChannelConfigView), []byte(chainid)}
+	prop := resetProvider(resources.Qscc_GetChannelConfigView, chainid, nil, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	view := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(res.Payload, &view))
+	channelGroup, ok := view["channel_group"].(map[string]interface{})
+	require.True(t, ok)
+	assert.NotEmpty(t, channelGroup["capabilities"])
+	assert.Contains(t, channelGroup["groups"], "Application")
+
+	// the ACL is checked
+	prop = resetProvider(resources.Qscc_GetChannelConfigView, chainid, nil, errors.New("Failed access control"))
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Failed access control")
+}
+
+func TestQueryGetChannelMembership(t *testing.T) {
+	chainid := "mytestchainid11"
+	path := tempDir(t, "test11")
+	defer os.RemoveAll(path)
+
+	stub, p, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+	addBlockForTesting(t, chainid, p)
+
+	invoke := func(number string) (*configview.Membership, peer2.Response) {
+		args := [][]byte{[]byte(GetChannelMembership), []byte(chainid), []byte(number)}
+		prop := resetProvider(resources.Qscc_GetChannelMembership, chainid, nil, nil)
+		res := stub.MockInvokeWithSignedProposal("1", args, prop)
+		if res.Status != shim.OK {
+			return nil, res
+		}
+		membership := &configview.Membership{}
+		require.NoError(t, json.Unmarshal(res.Payload, membership))
+		return membership, res
+	}
+
+	// the membership at a block is defined by the last config block
+	for _, number := range []string{"0", "1"} {
+		membership, res := invoke(number)
+		require.Equal(t, int32(shim.OK), res.Status, res.Message)
+		assert.Equal(t, chainid, membership.ChannelID)
+		assert.Equal(t, number, strconv.FormatUint(membership.BlockNumber, 10))
+		assert.Equal(t, uint64(0), membership.ConfigBlockNumber)
+		require.NotEmpty(t, membership.Organizations)
+		for _, org := range membership.Organizations {
+			assert.NotEmpty(t, org.MSPID)
+			assert.NotEmpty(t, org.RootCerts)
+		}
+	}
+
+	_, res := invoke("a")
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Failed to parse block number")
+	_, res = invoke("2")
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Failed to get block number 2")
+
+	args := [][]byte{[]byte(GetChannelMembership), []byte(chainid)}
+	res = stub.MockInvokeWithSignedProposal("2", args, resetProvider(resources.Qscc_GetChannelMembership, chainid, nil, nil))
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "missing 3rd argument for GetChannelMembership", res.Message)
+
+	// the ACL is checked
+	args = [][]byte{[]byte(GetChannelMembership), []byte(chainid), []byte("0")}
+	prop := resetProvider(resources.Qscc_GetChannelMembership, chainid, nil, errors.New("Failed access control"))
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Failed access control")
+}
+
 func addBlockForTesting(t *testing.T, chainid string, p *peer.Peer) *common.Block {
 	ledger := p.GetLedger(chainid)
 	defer ledger.Close()
@@ -477,6 +715,60 @@ func addBlockForTesting(t *testing.T, chainid string, p *peer.Peer) *common.Bloc
 
 var mockAclProvider *mocks.MockACLProvider
 
+type fakeIdemixAuditor struct {
+	req     *idemixaudit.Request
+	authErr error
+}
+
+func (f *fakeIdemixAuditor) Audit(req *idemixaudit.Request, ledger idemixaudit.TransactionGetter, authorize func() error) (*idemixaudit.Report, error) {
+	f.req = req
+	if f.authErr = authorize(); f.authErr != nil {
+		return nil, f.authErr
+	}
+	report := &idemixaudit.Report{ChannelID: req.ChannelID}
+	for _, txID := range req.TxIDs {
+		report.Transactions = append(report.Transactions, &idemixaudit.Transaction{TxID: txID, MSPID: "IdemixOrg", AuditEscrow: []byte("escrow")})
+	}
+	return report, nil
+}
+
+func TestQueryAuditIdemixTransactions(t *testing.T) {
+	chainid := "mytestchainid14"
+	path := tempDir(t, "test14")
+	defer os.RemoveAll(path)
+
+	stub, p, cleanup, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+
+	args := [][]byte{[]byte(AuditIdemixTransactions), []byte(chainid), []byte("tx1"), []byte("tx2")}
+	prop := resetProvider(resources.Qscc_AuditIdemixTransactions, chainid, nil, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "idemix audit is not enabled", res.Message)
+
+	auditor := &fakeIdemixAuditor{}
+	stub = shimtest.NewMockStub("LedgerQuerier", New(mockAclProvider, p, auditor))
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, chainid, auditor.req.ChannelID)
+	assert.Equal(t, []string{"tx1", "tx2"}, auditor.req.TxIDs)
+	assert.Equal(t, []byte("Alice"), auditor.req.Requester)
+	report := &idemixaudit.Report{}
+	require.NoError(t, json.Unmarshal(res.Payload, report))
+	require.Len(t, report.Transactions, 2)
+	assert.Equal(t, "tx2", report.Transactions[1].TxID)
+
+	// the auditor checks the ACL, so that denied accesses are recorded
+	prop = resetProvider(resources.Qscc_AuditIdemixTransactions, chainid, nil, errors.New("Failed access control"))
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Failed access control")
+	assert.Contains(t, auditor.authErr.Error(), "Failed access control")
+}
+
 func TestMain(m *testing.M) {
 	mockAclProvider = &mocks.MockACLProvider{}
 	mockAclProvider.Reset()
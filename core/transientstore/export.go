@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/pkg/errors"
+)
+
+// ExportedEntry is a private write set exported from the transient store.
+// Entries are written as one JSON document per line.
+type ExportedEntry struct {
+	TxID                  string `json:"txID"`
+	ReceivedAtBlockHeight uint64 `json:"receivedAtBlockHeight"`
+	// PvtSimulationResults is the marshaled TxPvtReadWriteSetWithConfigInfo.
+	PvtSimulationResults []byte `json:"pvtSimulationResults"`
+}
+
+// Export writes the private write sets held in the store to w, and returns
+// the number of exported entries. When namespaces are given, only the write
+// sets and the collection configs of these namespaces are exported, and the
+// entries holding none of them are skipped.
+func (s *Store) Export(w io.Writer, namespaces ...string) (int, error) {
+	startKey := []byte{prwsetPrefix, compositeKeySep}
+	endKey := []byte{prwsetPrefix, compositeKeySep + 1}
+	iter, err := s.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Release()
+
+	encoder := json.NewEncoder(w)
+	count := 0
+	for iter.Next() {
+		dbKey := iter.Key()
+		txid := string(dbKey[2 : 2+bytes.IndexByte(dbKey[2:], compositeKeySep)])
+		_, blockHeight, err := splitCompositeKeyOfPvtRWSet(dbKey)
+		if err != nil {
+			return count, err
+		}
+		results, err := unmarshalPvtSimulationResults(iter.Value())
+		if err != nil {
+			return count, errors.WithMessagef(err, "failed unmarshaling private write set of transaction %s", txid)
+		}
+		if len(namespaces) > 0 && !filterNamespaces(results, namespaces) {
+			continue
+		}
+		resultsBytes, err := proto.Marshal(results)
+		if err != nil {
+			return count, err
+		}
+		entry := &ExportedEntry{
+			TxID:                  txid,
+			ReceivedAtBlockHeight: blockHeight,
+			PvtSimulationResults:  resultsBytes,
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return count, errors.Wrap(err, "failed writing exported entry")
+		}
+		count++
+	}
+	if err := iter.Error(); err != nil {
+		return count, errors.Wrap(err, "failed iterating over the transient store")
+	}
+	return count, nil
+}
+
+// Import persists the private write sets exported by Export and read from r,
+// and returns the number of imported entries. Importing an entry already held
+// by the store adds a duplicate of it, which is harmless as it is purged along
+// with the original.
+func (s *Store) Import(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 256*1024*1024)
+	count := 0
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		entry := &ExportedEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return count, errors.Wrapf(err, "failed decoding entry %d", count)
+		}
+		if entry.TxID == "" {
+			return count, errors.Errorf("entry %d has no transaction ID", count)
+		}
+		results := &transientstore.TxPvtReadWriteSetWithConfigInfo{}
+		if err := proto.Unmarshal(entry.PvtSimulationResults, results); err != nil {
+			return count, errors.Wrapf(err, "failed unmarshaling private write set of transaction %s", entry.TxID)
+		}
+		if err := s.Persist(entry.TxID, entry.ReceivedAtBlockHeight, results); err != nil {
+			return count, errors.WithMessagef(err, "failed persisting private write set of transaction %s", entry.TxID)
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, errors.Wrap(err, "failed reading exported entries")
+	}
+	return count, nil
+}
+
+// unmarshalPvtSimulationResults unmarshals a value of the store, converting the
+// write sets persisted with the old proto.
+func unmarshalPvtSimulationResults(dbVal []byte) (*transientstore.TxPvtReadWriteSetWithConfigInfo, error) {
+	results := &transientstore.TxPvtReadWriteSetWithConfigInfo{}
+	if len(dbVal) > 0 && dbVal[0] == nilByte {
+		if err := proto.Unmarshal(dbVal[1:], results); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
+	if err := proto.Unmarshal(dbVal, txPvtRWSet); err != nil {
+		return nil, err
+	}
+	results.PvtRwset = txPvtRWSet
+	return results, nil
+}
+
+// filterNamespaces removes from the results the write sets and the collection
+// configs of the namespaces not given, and returns false if none is left.
+func filterNamespaces(results *transientstore.TxPvtReadWriteSetWithConfigInfo, namespaces []string) bool {
+	keep := map[string]bool{}
+	for _, ns := range namespaces {
+		keep[ns] = true
+	}
+
+	if pvtRWSet := results.GetPvtRwset(); pvtRWSet != nil {
+		var nsPvtRWSets []*rwset.NsPvtReadWriteSet
+		for _, nsPvtRWSet := range pvtRWSet.NsPvtRwset {
+			if keep[nsPvtRWSet.Namespace] {
+				nsPvtRWSets = append(nsPvtRWSets, nsPvtRWSet)
+			}
+		}
+		pvtRWSet.NsPvtRwset = nsPvtRWSets
+	}
+	for ns := range results.CollectionConfigs {
+		if !keep[ns] {
+			delete(results.CollectionConfigs, ns)
+		}
+	}
+	return len(results.GetPvtRwset().GetNsPvtRwset()) > 0
+}
@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	env.initTestEnv(t)
+	defer env.cleanup()
+	source := env.store
+	other, err := env.storeProvider.OpenStore("OtherStore")
+	require.NoError(t, err)
+
+	require.NoError(t, source.Persist("txid-1", 10, samplePvtDataWithConfigInfo(t)))
+	require.NoError(t, source.Persist("txid-1", 11, samplePvtDataWithConfigInfo(t)))
+	require.NoError(t, source.Persist("txid-2", 12, samplePvtDataWithConfigInfo(t)))
+	require.NoError(t, source.persistOldProto("txid-3", 13, samplePvtData(t)))
+	require.NoError(t, other.Persist("txid-4", 14, samplePvtDataWithConfigInfo(t)))
+
+	buf := &bytes.Buffer{}
+	count, err := source.Export(buf)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+	require.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 4)
+
+	dir, err := ioutil.TempDir("", "ts-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	targetProvider, err := NewStoreProvider(dir)
+	require.NoError(t, err)
+	defer targetProvider.Close()
+	target, err := targetProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+
+	count, err = target.Import(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+
+	retrieve := func(store *Store, txid string) []*EndorserPvtSimulationResults {
+		iter, err := store.GetTxPvtRWSetByTxid(txid, nil)
+		require.NoError(t, err)
+		defer iter.Close()
+		var results []*EndorserPvtSimulationResults
+		for {
+			result, err := iter.Next()
+			require.NoError(t, err)
+			if result == nil {
+				return results
+			}
+			results = append(results, result)
+		}
+	}
+	for _, txid := range []string{"txid-1", "txid-2", "txid-3"} {
+		expected := retrieve(source, txid)
+		actual := retrieve(target, txid)
+		sortResults(expected)
+		sortResults(actual)
+		require.Len(t, actual, len(expected))
+		for i := range expected {
+			require.Equal(t, expected[i].ReceivedAtBlockHeight, actual[i].ReceivedAtBlockHeight)
+			require.True(t, proto.Equal(expected[i].PvtSimulationResultsWithConfig, actual[i].PvtSimulationResultsWithConfig))
+		}
+	}
+	require.Empty(t, retrieve(target, "txid-4"))
+	minHeight, err := target.GetMinTransientBlkHt()
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), minHeight)
+
+	// the imported data is purged as usual
+	require.NoError(t, target.PurgeByTxids([]string{"txid-1"}))
+	require.Empty(t, retrieve(target, "txid-1"))
+}
+
+func TestExportNamespaces(t *testing.T) {
+	env.initTestEnv(t)
+	defer env.cleanup()
+	store := env.store
+
+	require.NoError(t, store.Persist("txid-1", 10, samplePvtDataWithConfigInfo(t)))
+	onlyNs2 := samplePvtDataWithConfigInfo(t)
+	onlyNs2.PvtRwset.NsPvtRwset = onlyNs2.PvtRwset.NsPvtRwset[1:]
+	delete(onlyNs2.CollectionConfigs, "ns-1")
+	require.NoError(t, store.Persist("txid-2", 10, onlyNs2))
+
+	buf := &bytes.Buffer{}
+	count, err := store.Export(buf, "ns-1")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	dir, err := ioutil.TempDir("", "ts-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	targetProvider, err := NewStoreProvider(dir)
+	require.NoError(t, err)
+	defer targetProvider.Close()
+	target, err := targetProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	count, err = target.Import(buf)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	iter, err := target.GetTxPvtRWSetByTxid("txid-1", nil)
+	require.NoError(t, err)
+	defer iter.Close()
+	result, err := iter.Next()
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	nsPvtRWSets := result.PvtSimulationResultsWithConfig.PvtRwset.NsPvtRwset
+	require.Len(t, nsPvtRWSets, 1)
+	require.Equal(t, "ns-1", nsPvtRWSets[0].Namespace)
+	require.Equal(t, rwset.TxReadWriteSet_KV, result.PvtSimulationResultsWithConfig.PvtRwset.DataModel)
+	require.Len(t, result.PvtSimulationResultsWithConfig.CollectionConfigs, 1)
+	require.Contains(t, result.PvtSimulationResultsWithConfig.CollectionConfigs, "ns-1")
+}
+
+func TestImportErrors(t *testing.T) {
+	env.initTestEnv(t)
+	defer env.cleanup()
+	store := env.store
+
+	_, err := store.Import(strings.NewReader("garbage\n"))
+	require.EqualError(t, err, "failed decoding entry 0: invalid character 'g' looking for beginning of value")
+
+	_, err = store.Import(strings.NewReader(`{"receivedAtBlockHeight":1}` + "\n"))
+	require.EqualError(t, err, "entry 0 has no transaction ID")
+
+	count, err := store.Import(strings.NewReader(`{"txID":"txid-1","receivedAtBlockHeight":1}` + "\n\n" + `{"txID":"txid-2","pvtSimulationResults":"/w=="}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed unmarshaling private write set of transaction txid-2")
+	require.Equal(t, 1, count)
+}
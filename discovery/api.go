@@ -55,6 +55,10 @@ type EndorsementSupport interface {
 	// taking in account whether the peers are part of the collections of the chaincodes.
 	// If a nil interest, or an empty interest is passed - no filtering is done.
 	PeersAuthorizedByCriteria(chainID common.ChannelID, interest *discprotos.ChaincodeInterest) (discovery.Members, error)
+
+	// CollectionsOfChaincode returns the collections of the given chaincode, along with the member orgs
+	// of each collection and the peers of the channel that are eligible to access its private data
+	CollectionsOfChaincode(channel common.ChannelID, chaincode string) (*discprotos.ChaincodeCollections, error)
 }
 
 // ConfigSupport provides access to channel configuration
@@ -51,6 +51,10 @@ type ChannelResponse interface {
 	// The given InvocationChain specifies the chaincode calls (along with collections)
 	// that the client passed during the construction of the request
 	Endorsers(invocationChain InvocationChain, f Filter) (Endorsers, error)
+
+	// Collections returns the response for a collections query for a given
+	// chaincode in a given channel context, or error if something went wrong.
+	Collections(chaincode string) ([]*Collection, error)
 }
 
 // LocalResponse aggregates responses for a channel-less scope
@@ -63,6 +67,15 @@ type LocalResponse interface {
 // for satisfying some chaincode's endorsement policy
 type Endorsers []*Peer
 
+// Collection describes a private data collection of a chaincode,
+// the organizations that are members of it and the peers that are eligible
+// to receive its private data.
+type Collection struct {
+	Name       string
+	MemberOrgs []string
+	Peers      []*Peer
+}
+
 // Peer aggregates identity, membership and channel-scoped information
 // of a certain peer.
 type Peer struct {
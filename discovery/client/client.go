@@ -26,6 +26,7 @@ var configTypes = []protoext.QueryType{
 	protoext.PeerMembershipQueryType,
 	protoext.ChaincodeQueryType,
 	protoext.LocalMembershipQueryType,
+	protoext.ChaincodeCollectionsQueryType,
 }
 
 // Client interacts with the discovery server
@@ -99,6 +100,31 @@ func (req *Request) AddEndorsersQuery(interests ...*discovery.ChaincodeInterest)
 	return req, nil
 }
 
+// AddCollectionsQuery adds to the request a query for the collections
+// of the given chaincodes, along with the peers eligible to each collection.
+func (req *Request) AddCollectionsQuery(chaincodes ...string) (*Request, error) {
+	if err := validateChaincodeNames(chaincodes...); err != nil {
+		return nil, err
+	}
+	ch := req.lastChannel
+	q := &discovery.Query_CcCollectionsQuery{
+		CcCollectionsQuery: &discovery.ChaincodeCollectionsQuery{
+			Chaincodes: chaincodes,
+		},
+	}
+	req.Queries = append(req.Queries, &discovery.Query{
+		Channel: ch,
+		Query:   q,
+	})
+	var invocationChains []InvocationChain
+	for _, cc := range chaincodes {
+		invocationChains = append(invocationChains, InvocationChain{{Name: cc}})
+	}
+	req.addChaincodeQueryMapping(invocationChains)
+	req.addQueryMapping(protoext.ChaincodeCollectionsQueryType, ch)
+	return req, nil
+}
+
 // AddLocalPeersQuery adds to the request a local peer query
 func (req *Request) AddLocalPeersQuery() *Request {
 	q := &discovery.Query_LocalPeers{
@@ -278,6 +304,29 @@ func (cr *channelResponse) Endorsers(invocationChain InvocationChain, f Filter)
 	return nil, errors.New("no endorsement combination can be satisfied")
 }
 
+func (cr *channelResponse) Collections(chaincode string) ([]*Collection, error) {
+	// If we have a key that has no chaincode field,
+	// it means it's an error returned from the service
+	if err, exists := cr.response[key{
+		queryType: protoext.ChaincodeCollectionsQueryType,
+		k:         cr.channel,
+	}]; exists {
+		return nil, err.(error)
+	}
+
+	res, exists := cr.response[key{
+		queryType:       protoext.ChaincodeCollectionsQueryType,
+		k:               cr.channel,
+		invocationChain: chaincode,
+	}]
+
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	return res.([]*Collection), nil
+}
+
 type filter struct {
 	ef ExclusionFilter
 	ps PrioritySelector
@@ -350,6 +399,8 @@ func (req *Request) computeResponse(r *discovery.Response) (response, error) {
 			err = resp.mapPeerMembership(channel2index, r, protoext.PeerMembershipQueryType)
 		case protoext.LocalMembershipQueryType:
 			err = resp.mapPeerMembership(channel2index, r, protoext.LocalMembershipQueryType)
+		case protoext.ChaincodeCollectionsQueryType:
+			err = resp.mapCollections(channel2index, r, req.invocationChainMapping)
 		}
 		if err != nil {
 			return nil, err
@@ -525,6 +576,70 @@ func (resp response) createEndorsementDescriptor(desc *discovery.EndorsementDesc
 	return descriptor, nil
 }
 
+func (resp response) mapCollections(
+	channel2index map[string]int,
+	r *discovery.Response,
+	chaincodeQueryMapping map[int][]InvocationChain) error {
+	for ch, index := range channel2index {
+		collectionsRes, err := protoext.ResponseCollectionsAt(r, index)
+		if collectionsRes == nil && err == nil {
+			return errors.Errorf("expected QueryResult of either ChaincodeCollectionsQueryResult or Error but got %v instead", r.Results[index])
+		}
+
+		if err != nil {
+			key := key{
+				queryType: protoext.ChaincodeCollectionsQueryType,
+				k:         ch,
+			}
+			resp[key] = errors.New(err.Content)
+			continue
+		}
+
+		if err := resp.mapCollectionsOfChannel(collectionsRes, ch, chaincodeQueryMapping[index]); err != nil {
+			return errors.Wrapf(err, "failed assembling collections of channel %s", ch)
+		}
+	}
+	return nil
+}
+
+func (resp response) mapCollectionsOfChannel(res *discovery.ChaincodeCollectionsQueryResult, channel string, chaincodes []InvocationChain) error {
+	if len(res.Content) < len(chaincodes) {
+		return errors.Errorf("expected collections of %d chaincodes but got only %d", len(chaincodes), len(res.Content))
+	}
+	for i, ccCollections := range res.Content {
+		expectedCCName := chaincodes[i][0].Name
+		if ccCollections.Chaincode != expectedCCName {
+			return errors.Errorf("expected chaincode %s but got collections of %s", expectedCCName, ccCollections.Chaincode)
+		}
+
+		var collections []*Collection
+		for _, c := range ccCollections.Collections {
+			collection := &Collection{
+				Name:       c.Name,
+				MemberOrgs: c.MemberOrgs,
+			}
+			for _, peers := range c.PeersByOrg {
+				for _, p := range peers.Peers {
+					peer, err := endorser(p, ccCollections.Chaincode, channel)
+					if err != nil {
+						return errors.Wrapf(err, "failed creating peer object of collection %s", c.Name)
+					}
+					collection.Peers = append(collection.Peers, peer)
+				}
+			}
+			collections = append(collections, collection)
+		}
+
+		resp[key{
+			queryType:       protoext.ChaincodeCollectionsQueryType,
+			k:               channel,
+			invocationChain: expectedCCName,
+		}] = collections
+	}
+
+	return nil
+}
+
 func endorser(peer *discovery.Peer, chaincode, channel string) (*Peer, error) {
 	if peer.MembershipInfo == nil || peer.StateInfo == nil {
 		return nil, errors.Errorf("received empty envelope(s) for endorsers for chaincode %s, channel %s", chaincode, channel)
@@ -612,6 +727,18 @@ func validateInterests(interests ...*discovery.ChaincodeInterest) error {
 	return nil
 }
 
+func validateChaincodeNames(chaincodes ...string) error {
+	if len(chaincodes) == 0 {
+		return errors.New("no chaincodes given")
+	}
+	for _, cc := range chaincodes {
+		if cc == "" {
+			return errors.New("chaincode name should not be empty")
+		}
+	}
+	return nil
+}
+
 // InvocationChain aggregates ChaincodeCalls
 type InvocationChain []*discovery.ChaincodeCall
 
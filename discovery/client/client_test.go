@@ -712,6 +712,18 @@ func TestAddEndorsersQueryInvalidInput(t *testing.T) {
 	assert.Contains(t, err.Error(), "chaincode name should not be empty")
 }
 
+func TestAddCollectionsQueryInvalidInput(t *testing.T) {
+	_, err := NewRequest().AddCollectionsQuery()
+	assert.Contains(t, err.Error(), "no chaincodes given")
+
+	_, err = NewRequest().AddCollectionsQuery("mycc", "")
+	assert.Contains(t, err.Error(), "chaincode name should not be empty")
+
+	req, err := NewRequest().OfChannel("mychannel").AddCollectionsQuery("mycc")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mycc"}, req.Queries[0].GetCcCollectionsQuery().Chaincodes)
+}
+
 func TestValidateAliveMessage(t *testing.T) {
 	am := aliveMessage(1)
 	msg, _ := protoext.EnvelopeToGossipMessage(am)
@@ -828,6 +840,8 @@ type endorsementAnalyzer interface {
 	PeersForEndorsement(chainID gossipcommon.ChannelID, interest *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error)
 
 	PeersAuthorizedByCriteria(chainID gossipcommon.ChannelID, interest *discovery.ChaincodeInterest) (gdisc.Members, error)
+
+	CollectionsOfChaincode(chainID gossipcommon.ChannelID, chaincode string) (*discovery.ChaincodeCollections, error)
 }
 
 type inquireablePolicy struct {
@@ -965,6 +979,10 @@ func (ms *mockSupport) PeersAuthorizedByCriteria(channel gossipcommon.ChannelID,
 	return ms.endorsementAnalyzer.PeersAuthorizedByCriteria(channel, interest)
 }
 
+func (ms *mockSupport) CollectionsOfChaincode(channel gossipcommon.ChannelID, chaincode string) (*discovery.ChaincodeCollections, error) {
+	return ms.endorsementAnalyzer.CollectionsOfChaincode(channel, chaincode)
+}
+
 func (*mockSupport) EligibleForService(channel string, data protoutil.SignedData) error {
 	return nil
 }
@@ -12,6 +12,29 @@ type ChannelResponse struct {
 	mock.Mock
 }
 
+// Collections provides a mock function with given fields: chaincode
+func (_m *ChannelResponse) Collections(chaincode string) ([]*client.Collection, error) {
+	ret := _m.Called(chaincode)
+
+	var r0 []*client.Collection
+	if rf, ok := ret.Get(0).(func(string) []*client.Collection); ok {
+		r0 = rf(chaincode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*client.Collection)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(chaincode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Config provides a mock function with given fields:
 func (_m *ChannelResponse) Config() (*discovery.ConfigResult, error) {
 	ret := _m.Called()
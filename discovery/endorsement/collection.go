@@ -7,13 +7,97 @@ SPDX-License-Identifier: Apache-2.0
 package endorsement
 
 import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
 	. "github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/common"
 	"github.com/pkg/errors"
 )
 
+// CollectionsOfChaincode returns the collections of the given chaincode in the given channel,
+// along with the member orgs of each collection and the peers of the channel which have the
+// chaincode installed and are eligible to access the private data of the collection.
+func (ea *endorsementAnalyzer) CollectionsOfChaincode(channelID common.ChannelID, chaincode string) (*ChaincodeCollections, error) {
+	// The collection config of chaincodes defined with the legacy lifecycle
+	// is only fetched when collections are passed.
+	md := ea.Metadata(string(channelID), chaincode, "")
+	if md == nil {
+		return nil, errors.Errorf("No metadata was found for chaincode %s in channel %s", chaincode, string(channelID))
+	}
+	principalSetsByCollections, err := principalsFromCollectionConfig(md.CollectionsConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	channelMembers := ea.PeersOfChannel(channelID).Filter(peersWithChaincode(md))
+	channelMembersByID := channelMembers.ByID()
+	aliveMembership := ea.Peers().Intersect(channelMembers)
+	identitiesByID := ea.IdentityInfo().ByID()
+
+	res := &ChaincodeCollections{Chaincode: chaincode}
+	for _, colConfig := range md.CollectionsConfig.GetConfig() {
+		name := colConfig.GetStaticCollectionConfig().Name
+		principalSet := principalSetsByCollections[name]
+		collection := &CollectionInfo{
+			Name:       name,
+			MemberOrgs: mspIDsOfPrincipalSet(principalSet),
+			PeersByOrg: make(map[string]*Peers),
+		}
+		for _, member := range aliveMembership {
+			identity, exists := identitiesByID[string(member.PKIid)]
+			if !exists || !isIdentityAuthorizedByPrincipalSet(string(channelID), ea, principalSet, identity.Identity) {
+				continue
+			}
+			org := string(identity.Organization)
+			if _, exists := collection.PeersByOrg[org]; !exists {
+				collection.PeersByOrg[org] = &Peers{}
+			}
+			collection.PeersByOrg[org].Peers = append(collection.PeersByOrg[org].Peers, &Peer{
+				Identity:       identity.Identity,
+				StateInfo:      channelMembersByID[string(member.PKIid)].Envelope,
+				MembershipInfo: member.Envelope,
+			})
+		}
+		res.Collections = append(res.Collections, collection)
+	}
+	return res, nil
+}
+
+// mspIDsOfPrincipalSet returns the sorted MSP IDs of the role and organizational
+// unit principals of the given PrincipalSet
+func mspIDsOfPrincipalSet(principalSet policies.PrincipalSet) []string {
+	mspIDs := make(map[string]struct{})
+	for _, principal := range principalSet {
+		switch principal.PrincipalClassification {
+		case msp.MSPPrincipal_ROLE:
+			role := &msp.MSPRole{}
+			if err := proto.Unmarshal(principal.Principal, role); err != nil {
+				logger.Warningf("Failed unmarshalling role principal: %v", err)
+				continue
+			}
+			mspIDs[role.MspIdentifier] = struct{}{}
+		case msp.MSPPrincipal_ORGANIZATION_UNIT:
+			ou := &msp.OrganizationUnit{}
+			if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+				logger.Warningf("Failed unmarshalling organizational unit principal: %v", err)
+				continue
+			}
+			mspIDs[ou.MspIdentifier] = struct{}{}
+		}
+	}
+	var res []string
+	for mspID := range mspIDs {
+		res = append(res, mspID)
+	}
+	sort.Strings(res)
+	return res
+}
+
 func principalsFromCollectionConfig(ccp *peer.CollectionConfigPackage) (principalSetsByCollectionName, error) {
 	principalSetsByCollections := make(principalSetsByCollectionName)
 	if ccp == nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/discovery"
 	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/gossip/api"
 	gcommon "github.com/hyperledger/fabric/gossip/common"
@@ -215,6 +216,120 @@ func TestFilterForPrincipalSets(t *testing.T) {
 	})
 }
 
+func TestCollectionsOfChaincode(t *testing.T) {
+	cc := "cc1"
+	chanPeers := peerSet{
+		newPeer(0).withChaincode(cc, "1.0"),
+		newPeer(3).withChaincode(cc, "1.0"),
+		newPeer(6).withChaincode(cc, "1.0"),
+		newPeer(9),
+	}.toMembers()
+	alivePeers := peerSet{
+		newPeer(0),
+		newPeer(3),
+		newPeer(9),
+	}.toMembers()
+
+	collectionsConfig := buildCollectionConfig(map[string][]*msp.MSPPrincipal{
+		"col1": {orgPrincipal("Org3MSP"), orgPrincipal("Org0MSP")},
+	})
+	collectionsConfig.Config = append(collectionsConfig.Config, buildCollectionConfig(map[string][]*msp.MSPPrincipal{
+		"col2": {orgPrincipal("Org6MSP"), orgPrincipal("Org9MSP")},
+	}).Config...)
+
+	g := &gossipMock{}
+	g.On("Peers").Return(alivePeers)
+	g.On("IdentityInfo").Return(identitySet(pkiID2MSPID))
+	g.On("PeersOfChannel").Return(chanPeers)
+	mf := &metadataFetcher{}
+	mf.On("Metadata").Return(&chaincode.Metadata{
+		Name:              cc,
+		Version:           "1.0",
+		CollectionsConfig: collectionsConfig,
+	}).Once()
+	analyzer := NewEndorsementAnalyzer(g, &policyFetcherMock{}, &principalEvaluatorMock{}, mf)
+
+	eligiblePeer := func(i int) *discovery.Peer {
+		p := newPeer(i)
+		return &discovery.Peer{
+			Identity:       p.identity,
+			StateInfo:      p.Envelope,
+			MembershipInfo: p.Envelope,
+		}
+	}
+
+	// Peer 6 isn't alive and peer 9 doesn't have the chaincode installed,
+	// hence no peer is eligible for the second collection
+	collections, err := analyzer.CollectionsOfChaincode(gcommon.ChannelID("mychannel"), cc)
+	assert.NoError(t, err)
+	assert.Equal(t, &discovery.ChaincodeCollections{
+		Chaincode: cc,
+		Collections: []*discovery.CollectionInfo{
+			{
+				Name:       "col1",
+				MemberOrgs: []string{"Org0MSP", "Org3MSP"},
+				PeersByOrg: map[string]*discovery.Peers{
+					"Org0MSP": {Peers: []*discovery.Peer{eligiblePeer(0)}},
+					"Org3MSP": {Peers: []*discovery.Peer{eligiblePeer(3)}},
+				},
+			},
+			{
+				Name:       "col2",
+				MemberOrgs: []string{"Org6MSP", "Org9MSP"},
+				PeersByOrg: map[string]*discovery.Peers{},
+			},
+		},
+	}, collections)
+
+	// A chaincode without collections
+	mf.On("Metadata").Return(&chaincode.Metadata{Name: cc, Version: "1.0"}).Once()
+	collections, err = analyzer.CollectionsOfChaincode(gcommon.ChannelID("mychannel"), cc)
+	assert.NoError(t, err)
+	assert.Equal(t, &discovery.ChaincodeCollections{Chaincode: cc}, collections)
+
+	// A chaincode which isn't defined
+	mf.On("Metadata").Return(nil).Once()
+	collections, err = analyzer.CollectionsOfChaincode(gcommon.ChannelID("mychannel"), cc)
+	assert.EqualError(t, err, "No metadata was found for chaincode cc1 in channel mychannel")
+	assert.Nil(t, collections)
+
+	// An invalid collection config
+	mf.On("Metadata").Return(&chaincode.Metadata{
+		Name:              cc,
+		Version:           "1.0",
+		CollectionsConfig: &peer.CollectionConfigPackage{Config: []*peer.CollectionConfig{{}}},
+	}).Once()
+	collections, err = analyzer.CollectionsOfChaincode(gcommon.ChannelID("mychannel"), cc)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected a static collection")
+	assert.Nil(t, collections)
+}
+
+func TestMSPIDsOfPrincipalSet(t *testing.T) {
+	principalSet := policies.PrincipalSet{
+		orgPrincipal("Org2MSP"),
+		orgPrincipal("Org1MSP"),
+		orgPrincipal("Org2MSP"),
+		{
+			PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+			Principal: protoutil.MarshalOrPanic(&msp.OrganizationUnit{
+				MspIdentifier:                "Org3MSP",
+				OrganizationalUnitIdentifier: "ou",
+			}),
+		},
+		{
+			PrincipalClassification: msp.MSPPrincipal_IDENTITY,
+			Principal:               []byte("identity"),
+		},
+		{
+			PrincipalClassification: msp.MSPPrincipal_ROLE,
+			Principal:               []byte{1, 2, 3},
+		},
+	}
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP", "Org3MSP"}, mspIDsOfPrincipalSet(principalSet))
+	assert.Nil(t, mspIDsOfPrincipalSet(nil))
+}
+
 func buildCollectionConfig(col2principals map[string][]*msp.MSPPrincipal) *peer.CollectionConfigPackage {
 	collections := &peer.CollectionConfigPackage{}
 	for col, principals := range col2principals {
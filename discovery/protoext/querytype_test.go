@@ -24,6 +24,7 @@ func TestGetQueryType(t *testing.T) {
 		{q: &discovery.Query{Query: &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}}}, expected: protoext.ConfigQueryType},
 		{q: &discovery.Query{Query: &discovery.Query_CcQuery{CcQuery: &discovery.ChaincodeQuery{}}}, expected: protoext.ChaincodeQueryType},
 		{q: &discovery.Query{Query: &discovery.Query_LocalPeers{LocalPeers: &discovery.LocalPeerQuery{}}}, expected: protoext.LocalMembershipQueryType},
+		{q: &discovery.Query{Query: &discovery.Query_CcCollectionsQuery{CcCollectionsQuery: &discovery.ChaincodeCollectionsQuery{}}}, expected: protoext.ChaincodeCollectionsQueryType},
 		{q: &discovery.Query{Query: &discovery.Query_CcQuery{}}, expected: protoext.InvalidQueryType},
 		{q: nil, expected: protoext.InvalidQueryType},
 	}
@@ -28,3 +28,10 @@ func ResponseEndorsersAt(m *discovery.Response, i int) (*discovery.ChaincodeQuer
 	r := m.Results[i]
 	return r.GetCcQueryRes(), r.GetError()
 }
+
+// ResponseCollectionsAt returns the ChaincodeCollectionsQueryResult at a given index in the Response,
+// or an Error if present.
+func ResponseCollectionsAt(m *discovery.Response, i int) (*discovery.ChaincodeCollectionsQueryResult, *discovery.Error) {
+	r := m.Results[i]
+	return r.GetCcCollections(), r.GetError()
+}
@@ -74,9 +74,10 @@ func NewService(config Config, sup Support) *service {
 		Support: sup,
 	}
 	s.channelDispatchers = map[protoext.QueryType]dispatcher{
-		protoext.ConfigQueryType:         s.configQuery,
-		protoext.ChaincodeQueryType:      s.chaincodeQuery,
-		protoext.PeerMembershipQueryType: s.channelMembershipResponse,
+		protoext.ConfigQueryType:               s.configQuery,
+		protoext.ChaincodeQueryType:            s.chaincodeQuery,
+		protoext.PeerMembershipQueryType:       s.channelMembershipResponse,
+		protoext.ChaincodeCollectionsQueryType: s.chaincodeCollectionsQuery,
 	}
 	s.localDispatchers = map[protoext.QueryType]dispatcher{
 		protoext.LocalMembershipQueryType: s.localMembershipResponse,
@@ -155,6 +156,29 @@ func (s *service) chaincodeQuery(q *discovery.Query) *discovery.QueryResult {
 	}
 }
 
+func (s *service) chaincodeCollectionsQuery(q *discovery.Query) *discovery.QueryResult {
+	if err := validateCCCollectionsQuery(q.GetCcCollectionsQuery()); err != nil {
+		return wrapError(err)
+	}
+	var content []*discovery.ChaincodeCollections
+	for _, cc := range q.GetCcCollectionsQuery().Chaincodes {
+		collections, err := s.CollectionsOfChaincode(common2.ChannelID(q.Channel), cc)
+		if err != nil {
+			logger.Errorf("Failed computing collections of chaincode %s: %v", cc, err)
+			return wrapError(errors.Errorf("failed computing collections of chaincode %s", cc))
+		}
+		content = append(content, collections)
+	}
+
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_CcCollections{
+			CcCollections: &discovery.ChaincodeCollectionsQueryResult{
+				Content: content,
+			},
+		},
+	}
+}
+
 func (s *service) configQuery(q *discovery.Query) *discovery.QueryResult {
 	conf, err := s.Config(q.Channel)
 	if err != nil {
@@ -283,6 +307,18 @@ func validateCCQuery(ccQuery *discovery.ChaincodeQuery) error {
 	return nil
 }
 
+func validateCCCollectionsQuery(query *discovery.ChaincodeCollectionsQuery) error {
+	if len(query.Chaincodes) == 0 {
+		return errors.New("chaincode collections query must contain at least one chaincode")
+	}
+	for _, cc := range query.Chaincodes {
+		if cc == "" {
+			return errors.New("chaincode name in query cannot be empty")
+		}
+	}
+	return nil
+}
+
 func wrapError(err error) *discovery.QueryResult {
 	return &discovery.QueryResult{
 		Result: &discovery.QueryResult_Error{
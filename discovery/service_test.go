@@ -39,6 +39,76 @@ func TestConfig(t *testing.T) {
 	}
 }
 
+func TestServiceChaincodeCollectionsQuery(t *testing.T) {
+	ctx := context.Background()
+	mockSup := &mockSupport{}
+	mockSup.On("ChannelExists", "mychannel").Return(true)
+	mockSup.On("EligibleForService", "mychannel", mock.Anything).Return(nil)
+	cc1Collections := &discovery.ChaincodeCollections{
+		Chaincode: "cc1",
+		Collections: []*discovery.CollectionInfo{
+			{
+				Name:       "col1",
+				MemberOrgs: []string{"Org1MSP", "Org2MSP"},
+				PeersByOrg: map[string]*discovery.Peers{
+					"Org1MSP": {Peers: []*discovery.Peer{{Identity: []byte("p0")}}},
+				},
+			},
+		},
+	}
+	cc2Collections := &discovery.ChaincodeCollections{Chaincode: "cc2"}
+	mockSup.On("CollectionsOfChaincode", "cc1").Return(cc1Collections, nil)
+	mockSup.On("CollectionsOfChaincode", "cc2").Return(cc2Collections, nil)
+	mockSup.On("CollectionsOfChaincode", "unknownCC").Return(nil, errors.New("unknown chaincode"))
+	service := NewService(Config{}, mockSup)
+
+	req := &discovery.Request{
+		Authentication: &discovery.AuthInfo{
+			ClientIdentity: []byte{1, 2, 3},
+		},
+		Queries: []*discovery.Query{
+			{
+				Channel: "mychannel",
+				Query: &discovery.Query_CcCollectionsQuery{
+					CcCollectionsQuery: &discovery.ChaincodeCollectionsQuery{},
+				},
+			},
+		},
+	}
+
+	// Scenario I: no chaincodes in the query
+	resp, err := service.Discover(ctx, toSignedRequest(req))
+	assert.NoError(t, err)
+	assert.Equal(t, "chaincode collections query must contain at least one chaincode", resp.Results[0].GetError().Content)
+
+	// Scenario II: an empty chaincode name in the query
+	req.Queries[0].GetCcCollectionsQuery().Chaincodes = []string{"cc1", ""}
+	resp, err = service.Discover(ctx, toSignedRequest(req))
+	assert.NoError(t, err)
+	assert.Equal(t, "chaincode name in query cannot be empty", resp.Results[0].GetError().Content)
+
+	// Scenario III: one of the chaincodes is unknown
+	req.Queries[0].GetCcCollectionsQuery().Chaincodes = []string{"cc1", "unknownCC"}
+	resp, err = service.Discover(ctx, toSignedRequest(req))
+	assert.NoError(t, err)
+	assert.Equal(t, "failed computing collections of chaincode unknownCC", resp.Results[0].GetError().Content)
+
+	// Scenario IV: the collections of all chaincodes are returned in the order of the query
+	req.Queries[0].GetCcCollectionsQuery().Chaincodes = []string{"cc2", "cc1"}
+	resp, err = service.Discover(ctx, toSignedRequest(req))
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(wrapResult(&discovery.ChaincodeCollectionsQueryResult{
+		Content: []*discovery.ChaincodeCollections{cc2Collections, cc1Collections},
+	}), resp))
+
+	// Scenario V: the query is local
+	req.Queries[0].Channel = ""
+	mockSup.On("EligibleForService", "", mock.Anything).Return(nil)
+	resp, err = service.Discover(ctx, toSignedRequest(req))
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown or missing request type", resp.Results[0].GetError().Content)
+}
+
 func TestService(t *testing.T) {
 	conf := Config{
 		AuthCacheEnabled: true,
@@ -461,6 +531,13 @@ func wrapQueryResult(res interface{}) *discovery.QueryResult {
 			},
 		}
 	}
+	if collectionsRes, isCollectionsQuery := res.(*discovery.ChaincodeCollectionsQueryResult); isCollectionsQuery {
+		return &discovery.QueryResult{
+			Result: &discovery.QueryResult_CcCollections{
+				CcCollections: collectionsRes,
+			},
+		}
+	}
 	if confRes, isConfQuery := res.(*discovery.ConfigResult); isConfQuery {
 		return &discovery.QueryResult{
 			Result: &discovery.QueryResult_ConfigResult{
@@ -519,6 +596,14 @@ func (ms *mockSupport) PeersAuthorizedByCriteria(chainID gcommon.ChannelID, inte
 	return args.Get(0).(gdisc.Members), args.Error(1)
 }
 
+func (ms *mockSupport) CollectionsOfChaincode(channel gcommon.ChannelID, chaincode string) (*discovery.ChaincodeCollections, error) {
+	args := ms.Called(chaincode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*discovery.ChaincodeCollections), args.Error(1)
+}
+
 func (*mockSupport) Chaincodes(id gcommon.ChannelID) []*gossip.Chaincode {
 	panic("implement me")
 }
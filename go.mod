@@ -7,7 +7,7 @@ replace golang.org/x/sys => golang.org/x/sys v0.0.0-20190920190810-ef0ce1748380
 
 require (
 	code.cloudfoundry.org/clock v1.0.0
-	github.com/DataDog/zstd v1.4.0 // indirect
+	github.com/DataDog/zstd v1.4.0
 	github.com/Knetic/govaluate v3.0.0+incompatible
 	github.com/Microsoft/hcsshim v0.8.6 // indirect
 	github.com/Shopify/sarama v1.20.1
@@ -74,3 +74,11 @@ require (
 )
 
 replace github.com/onsi/gomega => github.com/onsi/gomega v1.9.0
+
+// The bindings carry the messages added to the Fabric protos by this
+// repository. The fork keeps their .proto sources, compiled by `make protos`.
+replace github.com/hyperledger/fabric-protos-go => ./third_party/fabric-protos-go
+
+// The fork of the shim connects with GM TLS and requests the GM crypto
+// operations of the peer.
+replace github.com/hyperledger/fabric-chaincode-go => ./third_party/fabric-chaincode-go
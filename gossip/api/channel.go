@@ -23,6 +23,15 @@ type SecurityAdvisor interface {
 	OrgByPeerIdentity(PeerIdentityType) OrgIdentityType
 }
 
+// TLSPinner checks the TLS certificates of remote peers against the
+// certificates pinned for the organization of the peers
+type TLSPinner interface {
+	// VerifyTLSPins returns an error if the TLS certificate chain presented
+	// by a peer of the given organization, DER encoded and leaf first,
+	// doesn't match the pins of the organization.
+	VerifyTLSPins(org OrgIdentityType, rawCerts [][]byte) error
+}
+
 // ChannelNotifier is implemented by the gossip component and is used for the peer
 // layer to notify the gossip component of a JoinChannel event
 type ChannelNotifier interface {
@@ -83,11 +83,14 @@ func NewCommInstance(s *grpc.Server, certs *common.TLSCertificates, idStore iden
 		connTimeout:     config.ConnTimeout,
 		recvBuffSize:    config.RecvBuffSize,
 		sendBuffSize:    config.SendBuffSize,
+		tlsPinner:       config.TLSPinner,
+		compression:     config.Compression,
 	}
 
 	connConfig := ConnConfig{
-		RecvBuffSize: config.RecvBuffSize,
-		SendBuffSize: config.SendBuffSize,
+		RecvBuffSize:         config.RecvBuffSize,
+		SendBuffSize:         config.SendBuffSize,
+		CompressionThreshold: config.Compression.Threshold,
 	}
 
 	commInst.connStore = newConnStore(commInst, commInst.logger, connConfig)
@@ -99,10 +102,12 @@ func NewCommInstance(s *grpc.Server, certs *common.TLSCertificates, idStore iden
 
 // CommConfig is the configuration required to initialize a new comm
 type CommConfig struct {
-	DialTimeout  time.Duration // Dial timeout
-	ConnTimeout  time.Duration // Connection timeout
-	RecvBuffSize int           // Buffer size of received messages
-	SendBuffSize int           // Buffer size of sending messages
+	DialTimeout  time.Duration     // Dial timeout
+	ConnTimeout  time.Duration     // Connection timeout
+	RecvBuffSize int               // Buffer size of received messages
+	SendBuffSize int               // Buffer size of sending messages
+	TLSPinner    api.TLSPinner     // Verifies TLS certificates of remote peers against pins, if not nil
+	Compression  CompressionConfig // Compression of the payloads sent to remote peers
 }
 
 type commImpl struct {
@@ -129,6 +134,8 @@ type commImpl struct {
 	connTimeout     time.Duration
 	recvBuffSize    int
 	sendBuffSize    int
+	tlsPinner       api.TLSPinner
+	compression     CompressionConfig
 }
 
 func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidType) (*connection, error) {
@@ -137,6 +144,7 @@ func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidT
 	var stream proto.Gossip_GossipStreamClient
 	var pkiID common.PKIidType
 	var connInfo *protoext.ConnectionInfo
+	var codec string
 	var dialOpts []grpc.DialOption
 
 	c.logger.Debug("Entering", endpoint, expectedPKIID)
@@ -167,7 +175,7 @@ func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidT
 
 	ctx, cancel = context.WithCancel(context.Background())
 	if stream, err = cl.GossipStream(ctx); err == nil {
-		connInfo, err = c.authenticateRemotePeer(stream, true, false)
+		connInfo, codec, err = c.authenticateRemotePeer(stream, true, false)
 		if err == nil {
 			pkiID = connInfo.ID
 			// PKIID is nil when we don't know the remote PKI id's
@@ -188,11 +196,13 @@ func (c *commImpl) createConnection(endpoint string, expectedPKIID common.PKIidT
 				}
 			}
 			connConfig := ConnConfig{
-				RecvBuffSize: c.recvBuffSize,
-				SendBuffSize: c.sendBuffSize,
+				RecvBuffSize:         c.recvBuffSize,
+				SendBuffSize:         c.sendBuffSize,
+				CompressionThreshold: c.compression.Threshold,
 			}
 			conn := newConnection(cl, cc, stream, c.metrics, connConfig)
 			conn.pkiID = pkiID
+			conn.codec = codec
 			conn.info = connInfo
 			conn.logger = c.logger
 			conn.cancel = cancel
@@ -309,7 +319,7 @@ func (c *commImpl) Handshake(remotePeer *RemotePeer) (api.PeerIdentityType, erro
 	if err != nil {
 		return nil, err
 	}
-	connInfo, err := c.authenticateRemotePeer(stream, true, true)
+	connInfo, _, err := c.authenticateRemotePeer(stream, true, true)
 	if err != nil {
 		c.logger.Warningf("Authentication failed: %v", err)
 		return nil, err
@@ -408,7 +418,10 @@ func extractRemoteAddress(stream stream) string {
 	return remoteAddress
 }
 
-func (c *commImpl) authenticateRemotePeer(stream stream, initiator, isProbe bool) (*protoext.ConnectionInfo, error) {
+// authenticateRemotePeer exchanges connection messages with the remote peer and
+// returns its connection info, along with the codec used to compress the payloads
+// sent to it, if any.
+func (c *commImpl) authenticateRemotePeer(stream stream, initiator, isProbe bool) (*protoext.ConnectionInfo, string, error) {
 	ctx := stream.Context()
 	remoteAddress := extractRemoteAddress(stream)
 	remoteCertHash := extractCertificateHashFromContext(ctx)
@@ -432,12 +445,12 @@ func (c *commImpl) authenticateRemotePeer(stream stream, initiator, isProbe bool
 	// TLS enabled but not detected on other side
 	if useTLS && len(remoteCertHash) == 0 {
 		c.logger.Warningf("%s didn't send TLS certificate", remoteAddress)
-		return nil, fmt.Errorf("No TLS certificate")
+		return nil, "", fmt.Errorf("No TLS certificate")
 	}
 
 	cMsg, err = c.createConnectionMsg(c.PKIID, selfCertHash, c.peerIdentity, signer, isProbe)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	c.logger.Debug("Sending", cMsg, "to", remoteAddress)
@@ -445,24 +458,24 @@ func (c *commImpl) authenticateRemotePeer(stream stream, initiator, isProbe bool
 	m, err := readWithTimeout(stream, c.connTimeout, remoteAddress)
 	if err != nil {
 		c.logger.Warningf("Failed reading messge from %s, reason: %v", remoteAddress, err)
-		return nil, err
+		return nil, "", err
 	}
 	receivedMsg := m.GetConn()
 	if receivedMsg == nil {
 		c.logger.Warning("Expected connection message from", remoteAddress, "but got", receivedMsg)
-		return nil, fmt.Errorf("Wrong type")
+		return nil, "", fmt.Errorf("Wrong type")
 	}
 
 	if receivedMsg.PkiId == nil {
 		c.logger.Warningf("%s didn't send a pkiID", remoteAddress)
-		return nil, fmt.Errorf("No PKI-ID")
+		return nil, "", fmt.Errorf("No PKI-ID")
 	}
 
 	c.logger.Debug("Received", receivedMsg, "from", remoteAddress)
 	err = c.idMapper.Put(receivedMsg.PkiId, receivedMsg.Identity)
 	if err != nil {
 		c.logger.Warningf("Identity store rejected %s : %v", remoteAddress, err)
-		return nil, err
+		return nil, "", err
 	}
 
 	connInfo := &protoext.ConnectionInfo{
@@ -480,7 +493,7 @@ func (c *commImpl) authenticateRemotePeer(stream stream, initiator, isProbe bool
 		// If the remote peer sent its TLS certificate, make sure it actually matches the TLS cert
 		// that the peer used.
 		if !bytes.Equal(remoteCertHash, receivedMsg.TlsCertHash) {
-			return nil, errors.Errorf("Expected %v in remote hash of TLS cert, but got %v", remoteCertHash, receivedMsg.TlsCertHash)
+			return nil, "", errors.Errorf("Expected %v in remote hash of TLS cert, but got %v", remoteCertHash, receivedMsg.TlsCertHash)
 		}
 	}
 	// Final step - verify the signature on the connection message itself
@@ -491,16 +504,26 @@ func (c *commImpl) authenticateRemotePeer(stream stream, initiator, isProbe bool
 	err = m.Verify(receivedMsg.Identity, verifier)
 	if err != nil {
 		c.logger.Errorf("Failed verifying signature from %s : %v", remoteAddress, err)
-		return nil, err
+		return nil, "", err
+	}
+
+	// if TLS certificates are pinned, make sure the TLS certificate of the remote
+	// peer matches the pins of its organization
+	if useTLS && c.tlsPinner != nil {
+		org := c.sa.OrgByPeerIdentity(receivedMsg.Identity)
+		if err := c.tlsPinner.VerifyTLSPins(org, extractCertificatesFromContext(ctx)); err != nil {
+			c.logger.Warningf("TLS certificate of %s doesn't match the pins of organization %s: %v", remoteAddress, string(org), err)
+			return nil, "", err
+		}
 	}
 
 	c.logger.Debug("Authenticated", remoteAddress)
 
 	if receivedMsg.Probe {
-		return connInfo, errProbe
+		return connInfo, "", errProbe
 	}
 
-	return connInfo, nil
+	return connInfo, c.compression.negotiate(receivedMsg.Compression), nil
 }
 
 // SendWithAck sends a message to remote peers, waiting for acknowledgement from minAck of them, or until a certain timeout expires
@@ -564,7 +587,7 @@ func (c *commImpl) GossipStream(stream proto.Gossip_GossipStreamServer) error {
 	if c.isStopping() {
 		return fmt.Errorf("Shutting down")
 	}
-	connInfo, err := c.authenticateRemotePeer(stream, false, false)
+	connInfo, codec, err := c.authenticateRemotePeer(stream, false, false)
 
 	if err == errProbe {
 		c.logger.Infof("Peer %s (%s) probed us", connInfo.ID, connInfo.Endpoint)
@@ -577,7 +600,7 @@ func (c *commImpl) GossipStream(stream proto.Gossip_GossipStreamServer) error {
 	}
 	c.logger.Debug("Servicing", extractRemoteAddress(stream))
 
-	conn := c.connStore.onConnected(stream, connInfo, c.metrics)
+	conn := c.connStore.onConnected(stream, connInfo, codec, c.metrics)
 
 	h := func(m *protoext.SignedGossipMessage) {
 		c.msgPublisher.DeMultiplex(&ReceivedMessageImpl{
@@ -644,6 +667,7 @@ func (c *commImpl) createConnectionMsg(pkiID common.PKIidType, certHash []byte,
 				Identity:    cert,
 				PkiId:       pkiID,
 				Probe:       isProbe,
+				Compression: c.compression.advertisedCodecs(),
 			},
 		},
 	}
@@ -885,6 +885,69 @@ func TestProbe(t *testing.T) {
 	assert.Equal(t, api.PeerIdentityType(endpoint), id)
 }
 
+type tlsPinner func(org api.OrgIdentityType, rawCerts [][]byte) error
+
+func (p tlsPinner) VerifyTLSPins(org api.OrgIdentityType, rawCerts [][]byte) error {
+	return p(org, rawCerts)
+}
+
+func newPinningCommInstance(t *testing.T, pinner api.TLSPinner) (Comm, int) {
+	port, gRPCServer, certs, secureDialOpts, dialOpts := util.CreateGRPCLayer()
+	endpoint := fmt.Sprintf("127.0.0.1:%d", port)
+	id := []byte(endpoint)
+	identityMapper := identity.NewIdentityMapper(naiveSec, id, noopPurgeIdentity, naiveSec)
+
+	conf := testCommConfig
+	conf.TLSPinner = pinner
+	commInst, err := NewCommInstance(gRPCServer.Server(), certs, identityMapper, id, secureDialOpts,
+		naiveSec, disabledMetrics, conf, dialOpts...)
+	assert.NoError(t, err)
+
+	go func() {
+		err := gRPCServer.Start()
+		assert.NoError(t, err)
+	}()
+
+	return &commGRPC{commInst.(*commImpl), gRPCServer}, port
+}
+
+func TestTLSPinning(t *testing.T) {
+	var pinned int32
+	var verified int32
+	pinner := tlsPinner(func(org api.OrgIdentityType, rawCerts [][]byte) error {
+		atomic.AddInt32(&verified, 1)
+		if len(rawCerts) == 0 {
+			return errors.New("no TLS certificate")
+		}
+		if atomic.LoadInt32(&pinned) == 0 {
+			return errors.New("TLS certificate doesn't match the pins")
+		}
+		return nil
+	})
+
+	comm1, port1 := newPinningCommInstance(t, pinner)
+	defer comm1.Stop()
+	comm2, port2 := newCommInstance(t, naiveSec)
+	defer comm2.Stop()
+	time.Sleep(time.Duration(1) * time.Second)
+
+	// comm1 refuses comm2 as a client
+	_, err := comm1.Handshake(remotePeer(port2))
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&verified))
+	// and as a server
+	comm2.Handshake(remotePeer(port1))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&verified) == 2
+	}, 5*time.Second, 100*time.Millisecond)
+
+	atomic.StoreInt32(&pinned, 1)
+	_, err = comm1.Handshake(remotePeer(port2))
+	assert.NoError(t, err)
+	_, err = comm2.Handshake(remotePeer(port1))
+	assert.NoError(t, err)
+}
+
 func TestPresumedDead(t *testing.T) {
 	comm1, _ := newCommInstance(t, naiveSec)
 	comm2, port2 := newCommInstance(t, naiveSec)
@@ -931,7 +994,7 @@ func TestReadFromStream(t *testing.T) {
 	stream.On("Recv").Return(&proto.Envelope{Payload: []byte{1}}, nil).Once()
 	stream.On("Recv").Return(nil, errors.New("stream closed")).Once()
 
-	conn := newConnection(nil, nil, stream, disabledMetrics, ConnConfig{1, 1})
+	conn := newConnection(nil, nil, stream, disabledMetrics, ConnConfig{RecvBuffSize: 1, SendBuffSize: 1})
 	conn.logger = flogging.MustGetLogger("test")
 
 	errChan := make(chan error, 2)
@@ -0,0 +1,181 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+
+	"github.com/DataDog/zstd"
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CompressionCodecZstd compresses payloads with zstd
+	CompressionCodecZstd = "zstd"
+	// CompressionCodecGzip compresses payloads with gzip
+	CompressionCodecGzip = "gzip"
+
+	DefCompressionThreshold = 4096
+
+	// maxDecompressedSize bounds the size of a decompressed payload to the
+	// default maximum size of a gRPC message received by the peer, so that a
+	// small compressed payload cannot exhaust the memory of the peer
+	maxDecompressedSize = 100 * 1024 * 1024
+)
+
+// DefCompressionCodecs are the compression codecs used by default, in preference order
+var DefCompressionCodecs = []string{CompressionCodecZstd, CompressionCodecGzip}
+
+// CompressionConfig is the configuration of the compression of the payloads of
+// the blocks, state transfer and private data messages sent to remote peers.
+// Each peer advertises the codecs it accepts when connecting, and the payloads
+// sent to a remote peer are compressed with the first of the configured codecs
+// that the remote peer accepts. Remote peers which don't advertise any codec
+// are never sent compressed payloads.
+type CompressionConfig struct {
+	Enabled   bool     // Whether payloads are compressed and compressed payloads are accepted
+	Codecs    []string // Codecs used to compress payloads, in preference order
+	Threshold int      // Minimum size in bytes of a payload to be compressed
+}
+
+// ValidateCompressionCodecs returns an error if any of the given codecs is not supported
+func ValidateCompressionCodecs(codecs []string) error {
+	for _, codec := range codecs {
+		switch codec {
+		case CompressionCodecZstd, CompressionCodecGzip:
+		default:
+			return errors.Errorf("unsupported compression codec %s", codec)
+		}
+	}
+	return nil
+}
+
+// advertisedCodecs returns the codecs to advertise to remote peers when connecting
+func (cc CompressionConfig) advertisedCodecs() []string {
+	if !cc.Enabled {
+		return nil
+	}
+	return cc.Codecs
+}
+
+// negotiate returns the first of the configured codecs accepted by the remote
+// peer, or an empty string if the payloads sent to it are not to be compressed
+func (cc CompressionConfig) negotiate(remoteCodecs []string) string {
+	if !cc.Enabled {
+		return ""
+	}
+	for _, codec := range cc.Codecs {
+		for _, remoteCodec := range remoteCodecs {
+			if codec == remoteCodec {
+				return codec
+			}
+		}
+	}
+	return ""
+}
+
+// isCompressible returns whether the payload of the message may be compressed,
+// which is the case for the messages carrying blocks, state transfer responses
+// and private data
+func isCompressible(msg *protoext.SignedGossipMessage) bool {
+	if msg.GossipMessage == nil {
+		return false
+	}
+	switch msg.Content.(type) {
+	case *proto.GossipMessage_DataMsg,
+		*proto.GossipMessage_DataUpdate,
+		*proto.GossipMessage_StateResponse,
+		*proto.GossipMessage_PrivateData,
+		*proto.GossipMessage_PrivateRes:
+		return true
+	}
+	return false
+}
+
+// compressEnvelope returns a copy of the envelope with its payload compressed
+// with the given codec
+func compressEnvelope(envelope *proto.Envelope, codec string) (*proto.Envelope, error) {
+	payload, err := compressPayload(codec, envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.Envelope{
+		Payload:        payload,
+		Signature:      envelope.Signature,
+		SecretEnvelope: envelope.SecretEnvelope,
+		Compression:    codec,
+	}, nil
+}
+
+// decompressEnvelope restores in place the payload of an envelope received
+// compressed, so that its signature can be verified
+func decompressEnvelope(envelope *proto.Envelope) error {
+	if envelope.Compression == "" {
+		return nil
+	}
+	payload, err := decompressPayload(envelope.Compression, envelope.Payload)
+	if err != nil {
+		return err
+	}
+	envelope.Payload = payload
+	envelope.Compression = ""
+	return nil
+}
+
+func compressPayload(codec string, payload []byte) ([]byte, error) {
+	switch codec {
+	case CompressionCodecZstd:
+		compressed, err := zstd.Compress(nil, payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error compressing payload with zstd")
+		}
+		return compressed, nil
+	case CompressionCodecGzip:
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		if _, err := w.Write(payload); err != nil {
+			return nil, errors.Wrap(err, "error compressing payload with gzip")
+		}
+		if err := w.Close(); err != nil {
+			return nil, errors.Wrap(err, "error compressing payload with gzip")
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Errorf("unsupported compression codec %s", codec)
+	}
+}
+
+func decompressPayload(codec string, payload []byte) ([]byte, error) {
+	var r io.ReadCloser
+	switch codec {
+	case CompressionCodecZstd:
+		r = zstd.NewReader(bytes.NewReader(payload))
+	case CompressionCodecGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, errors.Wrap(err, "error decompressing payload with gzip")
+		}
+		r = gr
+	default:
+		return nil, errors.Errorf("unsupported compression codec %s", codec)
+	}
+	defer r.Close()
+
+	decompressed, err := ioutil.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decompressing payload with %s", codec)
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, errors.Errorf("decompressed payload exceeds %d bytes", maxDecompressedSize)
+	}
+	return decompressed, nil
+}
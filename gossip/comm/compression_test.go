@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/gossip/metrics"
+	"github.com/hyperledger/fabric/gossip/metrics/mocks"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturingStream struct {
+	sent chan *proto.Envelope
+}
+
+func (s *capturingStream) Send(envelope *proto.Envelope) error {
+	s.sent <- envelope
+	return nil
+}
+
+func (s *capturingStream) Recv() (*proto.Envelope, error) {
+	select {}
+}
+
+func (s *capturingStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestCompressionNegotiation(t *testing.T) {
+	conf := CompressionConfig{Enabled: true, Codecs: []string{CompressionCodecZstd, CompressionCodecGzip}}
+
+	assert.Equal(t, []string{CompressionCodecZstd, CompressionCodecGzip}, conf.advertisedCodecs())
+	assert.Equal(t, CompressionCodecZstd, conf.negotiate([]string{CompressionCodecGzip, CompressionCodecZstd}))
+	assert.Equal(t, CompressionCodecGzip, conf.negotiate([]string{CompressionCodecGzip}))
+	assert.Equal(t, "", conf.negotiate([]string{"lz4"}))
+	// Peers which don't advertise codecs are never sent compressed payloads
+	assert.Equal(t, "", conf.negotiate(nil))
+
+	conf.Enabled = false
+	assert.Nil(t, conf.advertisedCodecs())
+	assert.Equal(t, "", conf.negotiate([]string{CompressionCodecZstd}))
+}
+
+func TestValidateCompressionCodecs(t *testing.T) {
+	assert.NoError(t, ValidateCompressionCodecs(DefCompressionCodecs))
+	assert.EqualError(t, ValidateCompressionCodecs([]string{CompressionCodecGzip, "lz4"}), "unsupported compression codec lz4")
+}
+
+func TestCompressPayload(t *testing.T) {
+	payload := bytes.Repeat([]byte("block"), 1000)
+	for _, codec := range DefCompressionCodecs {
+		t.Run(codec, func(t *testing.T) {
+			compressed, err := compressPayload(codec, payload)
+			require.NoError(t, err)
+			assert.True(t, len(compressed) < len(payload))
+
+			decompressed, err := decompressPayload(codec, compressed)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decompressed)
+
+			_, err = decompressPayload(codec, []byte("garbage"))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := compressPayload("lz4", payload)
+	assert.EqualError(t, err, "unsupported compression codec lz4")
+	_, err = decompressPayload("lz4", payload)
+	assert.EqualError(t, err, "unsupported compression codec lz4")
+}
+
+func TestIsCompressible(t *testing.T) {
+	block, err := protoext.NoopSign(&proto.GossipMessage{
+		Content: &proto.GossipMessage_DataMsg{DataMsg: &proto.DataMessage{Payload: &proto.Payload{}}},
+	})
+	require.NoError(t, err)
+	assert.True(t, isCompressible(block))
+
+	pvtData, err := protoext.NoopSign(&proto.GossipMessage{
+		Content: &proto.GossipMessage_PrivateRes{PrivateRes: &proto.RemotePvtDataResponse{}},
+	})
+	require.NoError(t, err)
+	assert.True(t, isCompressible(pvtData))
+
+	assert.False(t, isCompressible(createEmptyMsg(t)))
+}
+
+func createEmptyMsg(t *testing.T) *protoext.SignedGossipMessage {
+	msg, err := protoext.NoopSign(&proto.GossipMessage{
+		Content: &proto.GossipMessage_Empty{Empty: &proto.Empty{}},
+	})
+	require.NoError(t, err)
+	return msg
+}
+
+func TestConnectionCompression(t *testing.T) {
+	testMetricProvider := mocks.TestUtilConstructMetricProvider()
+	commMetrics := metrics.NewGossipMetrics(testMetricProvider.FakeProvider).CommMetrics
+
+	stream := &capturingStream{sent: make(chan *proto.Envelope, 3)}
+	conn := newConnection(nil, nil, stream, commMetrics, ConnConfig{RecvBuffSize: 1, SendBuffSize: 3, CompressionThreshold: 100})
+	conn.logger = flogging.MustGetLogger("test")
+	conn.info = &protoext.ConnectionInfo{Endpoint: "peer0"}
+	conn.codec = CompressionCodecGzip
+	go conn.writeToStream()
+	defer conn.close()
+
+	dataMsg := func(size int) *protoext.SignedGossipMessage {
+		msg, err := protoext.NoopSign(&proto.GossipMessage{
+			Channel: []byte("testchannel"),
+			Content: &proto.GossipMessage_DataMsg{
+				DataMsg: &proto.DataMessage{Payload: &proto.Payload{SeqNum: 1, Data: bytes.Repeat([]byte{1}, size)}},
+			},
+		})
+		require.NoError(t, err)
+		return msg
+	}
+	large, small, empty := dataMsg(1000), dataMsg(10), createEmptyMsg(t)
+	empty.Envelope.Payload = append(empty.Envelope.Payload, make([]byte, 1000)...)
+
+	for _, msg := range []*protoext.SignedGossipMessage{large, small, empty} {
+		conn.send(msg, func(error) {}, blockingSend)
+	}
+	receive := func() *proto.Envelope {
+		select {
+		case envelope := <-stream.sent:
+			return envelope
+		case <-time.After(5 * time.Second):
+			t.Fatal("message wasn't sent")
+		}
+		return nil
+	}
+
+	// Only the large enough payloads of the eligible messages are compressed
+	compressed := receive()
+	assert.Equal(t, CompressionCodecGzip, compressed.Compression)
+	assert.True(t, len(compressed.Payload) < len(large.Envelope.Payload))
+	assert.Equal(t, "", large.Envelope.Compression)
+	assert.Equal(t, small.Envelope, receive())
+	assert.Equal(t, empty.Envelope, receive())
+
+	require.Equal(t, 1, testMetricProvider.FakeCompressionRatio.ObserveCallCount())
+	assert.Equal(t, []string{"channel", "testchannel", "codec", "gzip"}, testMetricProvider.FakeCompressionRatio.WithArgsForCall(0))
+	assert.True(t, testMetricProvider.FakeCompressionRatio.ObserveArgsForCall(0) > 1)
+
+	// The receiver restores the original payload, which the signature covers
+	require.NoError(t, decompressEnvelope(compressed))
+	assert.Equal(t, "", compressed.Compression)
+	assert.Equal(t, large.Envelope.Payload, compressed.Payload)
+	received, err := protoext.EnvelopeToGossipMessage(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, large.GetDataMsg().Payload.Data, received.GetDataMsg().Payload.Data)
+}
@@ -161,7 +161,7 @@ func (cs *connectionStore) shutdown() {
 // onConnected closes any connection to the remote peer and creates a new connection object to it in order to have only
 // one single bi-directional connection between a pair of peers
 func (cs *connectionStore) onConnected(serverStream proto.Gossip_GossipStreamServer,
-	connInfo *protoext.ConnectionInfo, metrics *metrics.CommMetrics) *connection {
+	connInfo *protoext.ConnectionInfo, codec string, metrics *metrics.CommMetrics) *connection {
 	cs.Lock()
 	defer cs.Unlock()
 
@@ -171,6 +171,7 @@ func (cs *connectionStore) onConnected(serverStream proto.Gossip_GossipStreamSer
 
 	conn := newConnection(nil, nil, serverStream, metrics, cs.config)
 	conn.pkiID = connInfo.ID
+	conn.codec = codec
 	conn.info = connInfo
 	conn.logger = cs.logger
 	cs.pki2Conn[string(connInfo.ID)] = conn
@@ -188,37 +189,41 @@ func (cs *connectionStore) closeConnByPKIid(pkiID common.PKIidType) {
 
 func newConnection(cl proto.GossipClient, c *grpc.ClientConn, s stream, metrics *metrics.CommMetrics, config ConnConfig) *connection {
 	connection := &connection{
-		metrics:      metrics,
-		outBuff:      make(chan *msgSending, config.SendBuffSize),
-		cl:           cl,
-		conn:         c,
-		gossipStream: s,
-		stopChan:     make(chan struct{}, 1),
-		recvBuffSize: config.RecvBuffSize,
+		metrics:              metrics,
+		outBuff:              make(chan *msgSending, config.SendBuffSize),
+		cl:                   cl,
+		conn:                 c,
+		gossipStream:         s,
+		stopChan:             make(chan struct{}, 1),
+		recvBuffSize:         config.RecvBuffSize,
+		compressionThreshold: config.CompressionThreshold,
 	}
 	return connection
 }
 
 // ConnConfig is the configuration required to initialize a new conn
 type ConnConfig struct {
-	RecvBuffSize int
-	SendBuffSize int
+	RecvBuffSize         int
+	SendBuffSize         int
+	CompressionThreshold int
 }
 
 type connection struct {
-	recvBuffSize int
-	metrics      *metrics.CommMetrics
-	cancel       context.CancelFunc
-	info         *protoext.ConnectionInfo
-	outBuff      chan *msgSending
-	logger       util.Logger        // logger
-	pkiID        common.PKIidType   // pkiID of the remote endpoint
-	handler      handler            // function to invoke upon a message reception
-	conn         *grpc.ClientConn   // gRPC connection to remote endpoint
-	cl           proto.GossipClient // gRPC stub of remote endpoint
-	gossipStream stream             // there can only be one
-	stopChan     chan struct{}      // a method to stop the server-side gRPC call from a different go-routine
-	stopOnce     sync.Once          // once to ensure close is called only once
+	recvBuffSize         int
+	metrics              *metrics.CommMetrics
+	codec                string // codec used to compress payloads sent to the remote endpoint, if any
+	compressionThreshold int    // minimum size of a payload to be compressed
+	cancel               context.CancelFunc
+	info                 *protoext.ConnectionInfo
+	outBuff              chan *msgSending
+	logger               util.Logger        // logger
+	pkiID                common.PKIidType   // pkiID of the remote endpoint
+	handler              handler            // function to invoke upon a message reception
+	conn                 *grpc.ClientConn   // gRPC connection to remote endpoint
+	cl                   proto.GossipClient // gRPC stub of remote endpoint
+	gossipStream         stream             // there can only be one
+	stopChan             chan struct{}      // a method to stop the server-side gRPC call from a different go-routine
+	stopOnce             sync.Once          // once to ensure close is called only once
 }
 
 func (conn *connection) close() {
@@ -238,6 +243,8 @@ func (conn *connection) close() {
 func (conn *connection) send(msg *protoext.SignedGossipMessage, onErr func(error), shouldBlock blockingBehavior) {
 	m := &msgSending{
 		envelope: msg.Envelope,
+		channel:  string(msg.Channel),
+		compress: conn.codec != "" && isCompressible(msg),
 		onErr:    onErr,
 	}
 
@@ -253,7 +260,7 @@ func (conn *connection) send(msg *protoext.SignedGossipMessage, onErr func(error
 			case <-conn.stopChan: //stop blocking if the connection is closing
 			}
 		} else {
-			conn.metrics.BufferOverflow.Add(1)
+			conn.metrics.BufferOverflow.With("channel", m.channel).Add(1)
 			conn.logger.Debugf("Buffer to %s overflowed, dropping message %s", conn.info.Endpoint, msg)
 		}
 	}
@@ -289,12 +296,12 @@ func (conn *connection) writeToStream() {
 	for {
 		select {
 		case m := <-conn.outBuff:
-			err := stream.Send(m.envelope)
+			err := stream.Send(conn.compress(m))
 			if err != nil {
 				go m.onErr(err)
 				return
 			}
-			conn.metrics.SentMessages.Add(1)
+			conn.metrics.SentMessages.With("channel", m.channel).Add(1)
 		case <-conn.stopChan:
 			conn.logger.Debug("Closing writing to stream")
 			return
@@ -315,13 +322,20 @@ func (conn *connection) readFromStream(errChan chan error, msgChan chan *protoex
 				conn.logger.Debugf("Got error, aborting: %v", err)
 				return
 			}
-			conn.metrics.ReceivedMessages.Add(1)
+			if err := decompressEnvelope(envelope); err != nil {
+				conn.metrics.ReceivedMessages.With("channel", "").Add(1)
+				errChan <- err
+				conn.logger.Warningf("Got error, aborting: %v", err)
+				return
+			}
 			msg, err := protoext.EnvelopeToGossipMessage(envelope)
 			if err != nil {
+				conn.metrics.ReceivedMessages.With("channel", "").Add(1)
 				errChan <- err
 				conn.logger.Warningf("Got error, aborting: %v", err)
 				return
 			}
+			conn.metrics.ReceivedMessages.With("channel", string(msg.Channel)).Add(1)
 			select {
 			case <-conn.stopChan:
 			case msgChan <- msg:
@@ -330,8 +344,29 @@ func (conn *connection) readFromStream(errChan chan error, msgChan chan *protoex
 	}
 }
 
+// compress returns the envelope of the message to send, with its payload
+// compressed if it is eligible and large enough
+func (conn *connection) compress(m *msgSending) *proto.Envelope {
+	if !m.compress || len(m.envelope.Payload) < conn.compressionThreshold {
+		return m.envelope
+	}
+	envelope, err := compressEnvelope(m.envelope, conn.codec)
+	if err != nil {
+		conn.logger.Warningf("Failed compressing message to %s, sending it uncompressed: %v", conn.info.Endpoint, err)
+		return m.envelope
+	}
+	if len(envelope.Payload) == 0 {
+		return m.envelope
+	}
+	ratio := float64(len(m.envelope.Payload)) / float64(len(envelope.Payload))
+	conn.metrics.CompressionRatio.With("channel", m.channel, "codec", conn.codec).Observe(ratio)
+	return envelope
+}
+
 type msgSending struct {
 	envelope *proto.Envelope
+	channel  string
+	compress bool
 	onErr    func(error)
 }
 
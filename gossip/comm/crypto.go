@@ -23,6 +23,16 @@ func certHashFromRawCert(rawCert []byte) []byte {
 
 // ExtractCertificateHash extracts the hash of the certificate from the stream
 func extractCertificateHashFromContext(ctx context.Context) []byte {
+	certs := extractCertificatesFromContext(ctx)
+	if len(certs) == 0 {
+		return nil
+	}
+	return certHashFromRawCert(certs[0])
+}
+
+// extractCertificatesFromContext extracts the raw certificates the remote
+// peer presented during the TLS handshake, leaf first
+func extractCertificatesFromContext(ctx context.Context) [][]byte {
 	pr, extracted := peer.FromContext(ctx)
 	if !extracted {
 		return nil
@@ -37,10 +47,9 @@ func extractCertificateHashFromContext(ctx context.Context) []byte {
 	if !isTLSConn {
 		return nil
 	}
-	certs := tlsInfo.State.PeerCertificates
-	if len(certs) == 0 {
-		return nil
+	var rawCerts [][]byte
+	for _, cert := range tlsInfo.State.PeerCertificates {
+		rawCerts = append(rawCerts, cert.Raw)
 	}
-	raw := certs[0].Raw
-	return certHashFromRawCert(raw)
+	return rawCerts
 }
@@ -7,12 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package comm
 
 import (
+	"math/rand"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
 	"github.com/hyperledger/fabric/gossip/metrics"
 	"github.com/hyperledger/fabric/gossip/metrics/mocks"
+	"github.com/hyperledger/fabric/gossip/protoext"
 	"github.com/hyperledger/fabric/gossip/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -69,6 +72,10 @@ func TestMetrics(t *testing.T) {
 		1,
 		testMetricProvider.FakeSentMessages.AddArgsForCall(0),
 	)
+	assert.Equal(t,
+		[]string{"channel", ""},
+		testMetricProvider.FakeSentMessages.WithArgsForCall(0),
+	)
 
 	assert.EqualValues(t,
 		1,
@@ -82,3 +89,38 @@ func TestMetrics(t *testing.T) {
 
 	assert.Equal(t, uint32(1), atomic.LoadUint32(&overflown))
 }
+
+func TestMetricsChannelLabel(t *testing.T) {
+	testMetricProvider := mocks.TestUtilConstructMetricProvider()
+	fakeCommMetrics := metrics.NewGossipMetrics(testMetricProvider.FakeProvider).CommMetrics
+
+	comm1, _ := newCommInstanceWithMetrics(t, naiveSec, fakeCommMetrics)
+	comm2, port2 := newCommInstanceWithMetrics(t, naiveSec, fakeCommMetrics)
+	defer comm1.Stop()
+	defer comm2.Stop()
+
+	msg, _ := protoext.NoopSign(&proto.GossipMessage{
+		Tag:     proto.GossipMessage_CHAN_ONLY,
+		Channel: []byte("testchannel"),
+		Nonce:   uint64(rand.Int()),
+		Content: &proto.GossipMessage_DataMsg{
+			DataMsg: &proto.DataMessage{},
+		},
+	})
+	fromComm1 := comm2.Accept(acceptAll)
+	comm1.Send(msg, remotePeer(port2))
+	<-fromComm1
+
+	assert.Eventually(t, func() bool {
+		return testMetricProvider.FakeSentMessages.AddCallCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t,
+		[]string{"channel", "testchannel"},
+		testMetricProvider.FakeSentMessages.WithArgsForCall(0),
+	)
+	assert.Equal(t, 1, testMetricProvider.FakeReceivedMessages.AddCallCount())
+	assert.Equal(t,
+		[]string{"channel", "testchannel"},
+		testMetricProvider.FakeReceivedMessages.WithArgsForCall(0),
+	)
+}
@@ -44,6 +44,10 @@ type Sieve func(message *protoext.SignedGossipMessage) bool
 //    part of the Envelope the SignedGossipMessage originates from
 type DisclosurePolicy func(remotePeer *NetworkMember) (Sieve, EnvelopeFilter)
 
+// EndpointPolicy returns the endpoint that this peer advertises to the given
+// remote peer, or an empty string to advertise its own endpoint.
+type EndpointPolicy func(remotePeer *NetworkMember) string
+
 // CommService is an interface that the discovery expects to be implemented and passed on creation
 type CommService interface {
 	// Gossip gossips a message
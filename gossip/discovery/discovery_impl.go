@@ -10,11 +10,12 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"net"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
+	protolib "github.com/golang/protobuf/proto"
 	proto "github.com/hyperledger/fabric-protos-go/gossip"
 	"github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/gossip/msgstore"
@@ -72,6 +73,12 @@ type gossipDiscoveryImpl struct {
 
 	bootstrapPeers    []string
 	anchorPeerTracker AnchorPeerTracker
+	replayWindow      ReplayWindow
+	answeredMemReqs   *seenWindow
+	pendingMemReqs    *seenWindow
+
+	endpointPolicy      EndpointPolicy
+	advertisedEndpoints map[string]struct{}
 }
 
 type DiscoveryConfig struct {
@@ -82,6 +89,15 @@ type DiscoveryConfig struct {
 	MaxConnectionAttempts        int
 	MsgExpirationFactor          int
 	BootstrapPeers               []string
+	// ReplayWindowDir is the directory in which the alive message timestamps
+	// of remote peers are persisted, in order to reject replayed alive messages
+	// across restarts. Replayed membership requests and responses are rejected
+	// as well. Replay protection is disabled if it is empty.
+	ReplayWindowDir string
+	// EndpointPolicy selects the endpoint advertised to each remote peer. When
+	// it is set, the alive messages of this peer are sent directly to each alive
+	// member instead of being gossiped, each with the endpoint selected for it.
+	EndpointPolicy EndpointPolicy
 }
 
 // NewDiscoveryService returns a new discovery service with the comm module passed and the crypto service passed
@@ -113,11 +129,24 @@ func NewDiscoveryService(self NetworkMember, comm CommService, crypt CryptoServi
 
 		bootstrapPeers:    config.BootstrapPeers,
 		anchorPeerTracker: anchorPeerTracker,
+
+		endpointPolicy:      config.EndpointPolicy,
+		advertisedEndpoints: make(map[string]struct{}),
 	}
 
 	d.validateSelfConfig()
 	d.msgStore = newAliveMsgStore(d)
 
+	if config.ReplayWindowDir != "" {
+		replayWindow, err := NewReplayWindow(config.ReplayWindowDir, DefReplayWindowFlushInterval, config.AliveExpirationTimeout, logger)
+		if err != nil {
+			d.logger.Panicf("Failed initializing replay window: %+v", err)
+		}
+		d.replayWindow = replayWindow
+		d.answeredMemReqs = newSeenWindow(config.AliveExpirationTimeout)
+		d.pendingMemReqs = newSeenWindow(config.AliveExpirationTimeout)
+	}
+
 	go d.periodicalSendAlive()
 	go d.periodicalCheckAlive()
 	go d.handleMessages()
@@ -163,23 +192,7 @@ func (d *gossipDiscoveryImpl) Connect(member NetworkMember, id identifier) {
 				Endpoint:         member.Endpoint,
 				PKIid:            id.ID,
 			}
-			m, err := d.createMembershipRequest(id.SelfOrg)
-			if err != nil {
-				d.logger.Warningf("Failed creating membership request: %+v", errors.WithStack(err))
-				continue
-			}
-			req, err := protoext.NoopSign(m)
-			if err != nil {
-				d.logger.Warningf("Failed creating SignedGossipMessage: %+v", errors.WithStack(err))
-				continue
-			}
-			req.Nonce = util.RandomUInt64()
-			req, err = protoext.NoopSign(req.GossipMessage)
-			if err != nil {
-				d.logger.Warningf("Failed adding NONCE to SignedGossipMessage %+v", errors.WithStack(err))
-				continue
-			}
-			go d.sendUntilAcked(peer, req)
+			go d.sendUntilAcked(peer, id.SelfOrg)
 			return
 		}
 
@@ -187,7 +200,9 @@ func (d *gossipDiscoveryImpl) Connect(member NetworkMember, id identifier) {
 }
 
 func (d *gossipDiscoveryImpl) isMyOwnEndpoint(endpoint string) bool {
-	return endpoint == fmt.Sprintf("127.0.0.1:%d", d.port) || endpoint == fmt.Sprintf("localhost:%d", d.port) ||
+	port := fmt.Sprintf("%d", d.port)
+	return endpoint == net.JoinHostPort("127.0.0.1", port) || endpoint == net.JoinHostPort("::1", port) ||
+		endpoint == net.JoinHostPort("localhost", port) ||
 		endpoint == d.self.InternalEndpoint || endpoint == d.self.Endpoint
 }
 
@@ -197,11 +212,11 @@ func (d *gossipDiscoveryImpl) validateSelfConfig() {
 		d.logger.Panic("Internal endpoint is empty:", endpoint)
 	}
 
-	internalEndpointSplit := strings.Split(endpoint, ":")
-	if len(internalEndpointSplit) != 2 {
+	_, portString, err := net.SplitHostPort(endpoint)
+	if err != nil {
 		d.logger.Panicf("Self endpoint %s isn't formatted as 'host:port'", endpoint)
 	}
-	myPort, err := strconv.ParseInt(internalEndpointSplit[1], 10, 64)
+	myPort, err := strconv.ParseInt(portString, 10, 64)
 	if err != nil {
 		d.logger.Panicf("Self endpoint %s has not valid port, %+v", endpoint, errors.WithStack(err))
 	}
@@ -213,10 +228,23 @@ func (d *gossipDiscoveryImpl) validateSelfConfig() {
 	d.port = int(myPort)
 }
 
-func (d *gossipDiscoveryImpl) sendUntilAcked(peer *NetworkMember, message *protoext.SignedGossipMessage) {
-	nonce := message.Nonce
+// sendUntilAcked sends membership requests to the given peer until it
+// responds.  Each attempt sends a new request, as the peer only answers a
+// request once.
+func (d *gossipDiscoveryImpl) sendUntilAcked(peer *NetworkMember, includeInternalEndpoint bool) {
 	for i := 0; i < d.maxConnectionAttempts && !d.toDie(); i++ {
-		sub := d.pubsub.Subscribe(fmt.Sprintf("%d", nonce), time.Second*5)
+		m, err := d.createMembershipRequestFor(includeInternalEndpoint, peer)
+		if err != nil {
+			d.logger.Warningf("Failed creating membership request: %+v", errors.WithStack(err))
+			return
+		}
+		message, err := protoext.NoopSign(m)
+		if err != nil {
+			d.logger.Warningf("Failed creating SignedGossipMessage: %+v", errors.WithStack(err))
+			return
+		}
+		sub := d.pubsub.Subscribe(fmt.Sprintf("%d", message.Nonce), time.Second*5)
+		d.expectMemResp(peer.PKIid, message.Nonce)
 		d.comm.SendToPeer(peer, message)
 		if _, timeoutErr := sub.Listen(); timeoutErr == nil {
 			return
@@ -274,6 +302,7 @@ func (d *gossipDiscoveryImpl) InitiateSync(peerNum int) {
 	}
 
 	for _, netMember := range peers2SendTo {
+		d.expectMemResp(netMember.PKIid, memReq.Nonce)
 		d.comm.SendToPeer(netMember, memReq)
 	}
 }
@@ -337,7 +366,7 @@ func (d *gossipDiscoveryImpl) handleMsgFromComm(msg protoext.ReceivedMessage) {
 			return
 		}
 
-		if !d.crypt.ValidateAliveMsg(selfInfoGossipMsg) {
+		if !d.crypt.ValidateAliveMsg(selfInfoGossipMsg) || d.isReplayed(selfInfoGossipMsg) || d.isReplayedMemReq(selfInfoGossipMsg) {
 			return
 		}
 
@@ -358,7 +387,7 @@ func (d *gossipDiscoveryImpl) handleMsgFromComm(msg protoext.ReceivedMessage) {
 	}
 
 	if protoext.IsAliveMsg(m.GossipMessage) {
-		if !d.msgStore.CheckValid(m) || !d.crypt.ValidateAliveMsg(m) {
+		if !d.msgStore.CheckValid(m) || !d.crypt.ValidateAliveMsg(m) || d.isReplayed(m) {
 			return
 		}
 		// If the message was sent by me, ignore it and don't forward it further
@@ -373,6 +402,9 @@ func (d *gossipDiscoveryImpl) handleMsgFromComm(msg protoext.ReceivedMessage) {
 	}
 
 	if memResp := m.GetMemRes(); memResp != nil {
+		if d.isReplayedMemResp(msg.GetConnectionInfo().ID, m.Nonce) {
+			return
+		}
 		d.pubsub.Publish(fmt.Sprintf("%d", m.Nonce), m.Nonce)
 		for _, env := range memResp.Alive {
 			am, err := protoext.EnvelopeToGossipMessage(env)
@@ -385,7 +417,7 @@ func (d *gossipDiscoveryImpl) handleMsgFromComm(msg protoext.ReceivedMessage) {
 				return
 			}
 
-			if d.msgStore.CheckValid(am) && d.crypt.ValidateAliveMsg(am) {
+			if d.msgStore.CheckValid(am) && d.crypt.ValidateAliveMsg(am) && !d.isReplayed(am) {
 				d.handleAliveMessage(am)
 			}
 		}
@@ -398,7 +430,7 @@ func (d *gossipDiscoveryImpl) handleMsgFromComm(msg protoext.ReceivedMessage) {
 			}
 
 			// Newer alive message exists or the message isn't authentic
-			if !d.msgStore.CheckValid(dm) || !d.crypt.ValidateAliveMsg(dm) {
+			if !d.msgStore.CheckValid(dm) || !d.crypt.ValidateAliveMsg(dm) || d.isReplayed(dm) {
 				continue
 			}
 
@@ -435,7 +467,7 @@ func (d *gossipDiscoveryImpl) sendMemResponse(targetMember *proto.Member, intern
 			return
 		}
 	}
-	memResp := d.createMembershipResponse(aliveMsg, targetPeer)
+	memResp := d.createMembershipResponse(d.aliveMsgFor(aliveMsg, targetPeer), targetPeer)
 	if memResp == nil {
 		errMsg := `Got a membership request from a peer that shouldn't have sent one: %v, closing connection to the peer as a result.`
 		d.logger.Warningf(errMsg, targetMember)
@@ -494,6 +526,70 @@ func (d *gossipDiscoveryImpl) createMembershipResponse(aliveMsg *protoext.Signed
 	}
 }
 
+// isReplayed returns whether the given authenticated alive message precedes
+// an alive message of the same peer that was already accepted, possibly
+// before this peer was restarted.
+func (d *gossipDiscoveryImpl) isReplayed(m *protoext.SignedGossipMessage) bool {
+	if d.replayWindow == nil {
+		return false
+	}
+
+	aliveMsg := m.GetAliveMsg()
+	if equalPKIid(aliveMsg.Membership.PkiId, d.self.PKIid) {
+		return false
+	}
+	if d.replayWindow.Accept(aliveMsg.Membership.PkiId, aliveMsg.Timestamp) {
+		return false
+	}
+
+	d.logger.Warningf("Rejecting replayed alive message of %s with timestamp %v", protoext.MemberToString(aliveMsg.Membership), aliveMsg.Timestamp)
+	return true
+}
+
+// isReplayedMemReq returns whether the given authenticated alive message was
+// already received as the self information of a membership request, which
+// was therefore already answered.  Every membership request carries a new
+// alive message of its sender.
+func (d *gossipDiscoveryImpl) isReplayedMemReq(selfInfo *protoext.SignedGossipMessage) bool {
+	if d.answeredMemReqs == nil {
+		return false
+	}
+
+	aliveMsg := selfInfo.GetAliveMsg()
+	key := fmt.Sprintf("%x/%d/%d", aliveMsg.Membership.PkiId, aliveMsg.Timestamp.IncNum, aliveMsg.Timestamp.SeqNum)
+	if d.answeredMemReqs.add(key) {
+		return false
+	}
+
+	d.logger.Warningf("Rejecting replayed membership request of %s with timestamp %v", protoext.MemberToString(aliveMsg.Membership), aliveMsg.Timestamp)
+	return true
+}
+
+// expectMemResp records that a membership request with the given nonce was
+// sent to the given peer, so that its response is accepted once
+func (d *gossipDiscoveryImpl) expectMemResp(pkiID common.PKIidType, nonce uint64) {
+	if d.pendingMemReqs == nil {
+		return
+	}
+	d.pendingMemReqs.add(fmt.Sprintf("%x/%d", pkiID, nonce))
+}
+
+// isReplayedMemResp returns whether the membership response of the given
+// peer with the given nonce doesn't answer a pending membership request
+// sent to that peer, either because it answers a request that was already
+// answered or because it answers no request at all.
+func (d *gossipDiscoveryImpl) isReplayedMemResp(pkiID common.PKIidType, nonce uint64) bool {
+	if d.pendingMemReqs == nil {
+		return false
+	}
+	if d.pendingMemReqs.remove(fmt.Sprintf("%x/%d", pkiID, nonce)) {
+		return false
+	}
+
+	d.logger.Warningf("Rejecting membership response of %s with nonce %d which answers no pending membership request", pkiID, nonce)
+	return true
+}
+
 func (d *gossipDiscoveryImpl) handleAliveMessage(m *protoext.SignedGossipMessage) {
 	d.logger.Debug("Entering", m)
 	defer d.logger.Debug("Exiting")
@@ -573,7 +669,8 @@ func (d *gossipDiscoveryImpl) isSentByMe(m *protoext.SignedGossipMessage) bool {
 	}
 	d.logger.Debug("Got alive message about ourselves,", m)
 	d.lock.RLock()
-	diffExternalEndpoint := d.self.Endpoint != m.GetAliveMsg().Membership.Endpoint
+	_, advertised := d.advertisedEndpoints[m.GetAliveMsg().Membership.Endpoint]
+	diffExternalEndpoint := d.self.Endpoint != m.GetAliveMsg().Membership.Endpoint && !advertised
 	d.lock.RUnlock()
 	var diffInternalEndpoint bool
 	secretEnvelope := m.GetSecretEnvelope()
@@ -649,7 +746,7 @@ func (d *gossipDiscoveryImpl) periodicalReconnectToDead() {
 }
 
 func (d *gossipDiscoveryImpl) sendMembershipRequest(member *NetworkMember, includeInternalEndpoint bool) {
-	m, err := d.createMembershipRequest(includeInternalEndpoint)
+	m, err := d.createMembershipRequestFor(includeInternalEndpoint, member)
 	if err != nil {
 		d.logger.Warningf("Failed creating membership request: %+v", errors.WithStack(err))
 		return
@@ -659,14 +756,24 @@ func (d *gossipDiscoveryImpl) sendMembershipRequest(member *NetworkMember, inclu
 		d.logger.Errorf("Failed creating SignedGossipMessage: %+v", errors.WithStack(err))
 		return
 	}
+	d.expectMemResp(member.PKIid, req.Nonce)
 	d.comm.SendToPeer(member, req)
 }
 
 func (d *gossipDiscoveryImpl) createMembershipRequest(includeInternalEndpoint bool) (*proto.GossipMessage, error) {
+	return d.createMembershipRequestFor(includeInternalEndpoint, nil)
+}
+
+// createMembershipRequestFor creates a membership request to the given remote
+// peer, which advertises the endpoint selected for it by the endpoint policy
+func (d *gossipDiscoveryImpl) createMembershipRequestFor(includeInternalEndpoint bool, remotePeer *NetworkMember) (*proto.GossipMessage, error) {
 	am, err := d.createSignedAliveMessage(includeInternalEndpoint)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if remotePeer != nil {
+		am = d.aliveMsgFor(am, remotePeer)
+	}
 	req := &proto.MembershipRequest{
 		SelfInformation: am.Envelope,
 		// TODO: sending the known peers is not secure because the remote peer might shouldn't know
@@ -676,7 +783,7 @@ func (d *gossipDiscoveryImpl) createMembershipRequest(includeInternalEndpoint bo
 	}
 	return &proto.GossipMessage{
 		Tag:   proto.GossipMessage_EMPTY,
-		Nonce: uint64(0),
+		Nonce: util.RandomUInt64(),
 		Content: &proto.GossipMessage_MemReq{
 			MemReq: req,
 		},
@@ -774,10 +881,85 @@ func (d *gossipDiscoveryImpl) periodicalSendAlive() {
 		d.lock.Lock()
 		d.selfAliveMessage = msg
 		d.lock.Unlock()
+		if d.endpointPolicy != nil {
+			d.sendAliveToMembers(msg)
+			continue
+		}
 		d.comm.Gossip(msg)
 	}
 }
 
+// sendAliveToMembers sends the given alive message of this peer to each alive
+// member, with the endpoint that the endpoint policy selects for the member.
+// All the messages have the same timestamp, hence the first one received by a
+// peer prevails over the ones forwarded to it by other peers.
+func (d *gossipDiscoveryImpl) sendAliveToMembers(msg *protoext.SignedGossipMessage) {
+	variants := map[string]*protoext.SignedGossipMessage{}
+	for _, member := range d.GetMembership() {
+		member := member
+		endpoint := d.endpointPolicy(&member)
+		if endpoint == "" {
+			endpoint = msg.GetAliveMsg().Membership.Endpoint
+		}
+		variant, exists := variants[endpoint]
+		if !exists {
+			variant = d.aliveMsgWithEndpoint(msg, endpoint)
+			variants[endpoint] = variant
+		}
+		shouldBeDisclosed, omitConcealedFields := d.disclosurePolicy(&member)
+		if !shouldBeDisclosed(variant) {
+			continue
+		}
+		m := &protoext.SignedGossipMessage{
+			GossipMessage: variant.GossipMessage,
+			Envelope:      omitConcealedFields(variant),
+		}
+		d.comm.SendToPeer(&member, m)
+	}
+}
+
+// aliveMsgFor returns the given alive message of this peer, with the endpoint
+// that the endpoint policy selects for the given remote peer
+func (d *gossipDiscoveryImpl) aliveMsgFor(msg *protoext.SignedGossipMessage, remotePeer *NetworkMember) *protoext.SignedGossipMessage {
+	if d.endpointPolicy == nil {
+		return msg
+	}
+	endpoint := d.endpointPolicy(remotePeer)
+	if endpoint == "" {
+		return msg
+	}
+	return d.aliveMsgWithEndpoint(msg, endpoint)
+}
+
+// aliveMsgWithEndpoint signs a copy of the given alive message of this peer,
+// with the same timestamp and the given endpoint. The message itself is
+// returned if the copy cannot be signed.
+func (d *gossipDiscoveryImpl) aliveMsgWithEndpoint(msg *protoext.SignedGossipMessage, endpoint string) *protoext.SignedGossipMessage {
+	if msg.GetAliveMsg().Membership.Endpoint == endpoint {
+		return msg
+	}
+	gossipMsg := protolib.Clone(msg.GossipMessage).(*proto.GossipMessage)
+	gossipMsg.GetAliveMsg().Membership.Endpoint = endpoint
+
+	d.lock.Lock()
+	d.advertisedEndpoints[endpoint] = struct{}{}
+	internalEndpoint := d.self.InternalEndpoint
+	d.lock.Unlock()
+
+	envp := d.crypt.SignMessage(gossipMsg, internalEndpoint)
+	if envp == nil {
+		d.logger.Warningf("Failed signing alive message with endpoint %s", endpoint)
+		return msg
+	}
+	if msg.Envelope.SecretEnvelope == nil {
+		envp.SecretEnvelope = nil
+	}
+	return &protoext.SignedGossipMessage{
+		GossipMessage: gossipMsg,
+		Envelope:      envp,
+	}
+}
+
 func (d *gossipDiscoveryImpl) aliveMsgAndInternalEndpoint() (*proto.GossipMessage, string) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
@@ -1015,6 +1197,11 @@ func (d *gossipDiscoveryImpl) Stop() {
 		defer d.logger.Info("Stopped")
 		d.logger.Info("Stopping")
 		d.msgStore.Stop()
+		if d.replayWindow != nil {
+			if err := d.replayWindow.Close(); err != nil {
+				d.logger.Warningf("Failed persisting replay window: %+v", err)
+			}
+		}
 	}
 }
 
@@ -1624,6 +1624,63 @@ func TestMemRespDisclosurePol(t *testing.T) {
 	assert.NotZero(t, d2.sentMsgCount())
 }
 
+func TestEndpointPolicy(t *testing.T) {
+	config := defaultTestConfig
+	config.EndpointPolicy = func(remotePeer *NetworkMember) string {
+		if remotePeer.Endpoint == "localhost:7882" {
+			return "127.0.0.1:7881"
+		}
+		return ""
+	}
+	d1 := createDiscoveryInstanceCustomConfig(7881, "d1", []string{}, config)
+	defer d1.Stop()
+	d2 := createDiscoveryInstanceWithNoGossip(7882, "d2", []string{"localhost:7881"})
+	defer d2.Stop()
+	d3 := createDiscoveryInstanceWithNoGossip(7883, "d3", []string{"localhost:7881"})
+	defer d3.Stop()
+
+	assertMembership(t, []*gossipInstance{d1, d2, d3}, 2)
+
+	endpointOfD1 := func(d *gossipInstance) string {
+		member := d.Lookup(common.PKIidType("localhost:7881"))
+		if member == nil {
+			return ""
+		}
+		return member.Endpoint
+	}
+	// d1 advertises its overridden endpoint to d2 and its own endpoint to d3
+	assert.Eventually(t, func() bool { return endpointOfD1(d2) == "127.0.0.1:7881" }, timeout, 100*time.Millisecond)
+	assert.Equal(t, "localhost:7881", endpointOfD1(d3))
+
+	// the overridden endpoint of d1 is not mistaken for a misconfiguration
+	aliveMsg, err := d1.discoveryImpl().createSignedAliveMessage(true)
+	assert.NoError(t, err)
+	msg := d1.discoveryImpl().aliveMsgFor(aliveMsg, &NetworkMember{Endpoint: "localhost:7882"})
+	assert.Equal(t, "127.0.0.1:7881", msg.GetAliveMsg().Membership.Endpoint)
+	assert.True(t, d1.discoveryImpl().isSentByMe(msg))
+}
+
+func TestValidateSelfConfig(t *testing.T) {
+	for _, endpoint := range []string{"peer0:7051", "10.0.0.1:7051", "[2001:db8::1]:7051"} {
+		d := &gossipDiscoveryImpl{
+			self:   NetworkMember{InternalEndpoint: endpoint},
+			logger: util.GetLogger(util.DiscoveryLogger, ""),
+		}
+		d.validateSelfConfig()
+		assert.Equal(t, 7051, d.port)
+		assert.True(t, d.isMyOwnEndpoint(endpoint))
+		assert.True(t, d.isMyOwnEndpoint("[::1]:7051"))
+		assert.True(t, d.isMyOwnEndpoint("127.0.0.1:7051"))
+		assert.False(t, d.isMyOwnEndpoint("[::1]:7052"))
+	}
+
+	d := &gossipDiscoveryImpl{
+		self:   NetworkMember{InternalEndpoint: "2001:db8::1:7051"},
+		logger: util.GetLogger(util.DiscoveryLogger, ""),
+	}
+	assert.Panics(t, d.validateSelfConfig)
+}
+
 func TestMembersByID(t *testing.T) {
 	members := Members{
 		{PKIid: common.PKIidType("p0"), Endpoint: "p0"},
@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/pkg/errors"
+)
+
+const replayWindowFileName = "replaywindow.json"
+
+// DefReplayWindowFlushInterval is the default interval at which the replay
+// window is pruned and persisted
+const DefReplayWindowFlushInterval = 10 * time.Second
+
+// ReplayWindow tracks, per remote peer, the most recent alive message
+// timestamp that was authenticated, and rejects signed alive messages
+// that precede it.  The tracked timestamps are persisted so that alive
+// messages which were superseded before a restart can't be replayed
+// after the restart.  The peers which weren't seen for longer than the
+// alive expiration timeout are forgotten, as discovery does.
+type ReplayWindow interface {
+	// Accept returns whether a message from the given peer bearing the
+	// given timestamp is not a replay, and if so, records the timestamp.
+	Accept(pkiID common.PKIidType, ts *proto.PeerTime) bool
+
+	// Close persists the tracked timestamps and stops the persistence
+	// in the background
+	Close() error
+}
+
+type peerTime struct {
+	IncNum uint64 `json:"inc_num"`
+	SeqNum uint64 `json:"seq_num"`
+	// Seen is the time, in nanoseconds since the epoch, at which the
+	// timestamp was last accepted
+	Seen int64 `json:"seen"`
+}
+
+type fileReplayWindow struct {
+	sync.Mutex
+	path          string
+	logger        util.Logger
+	flushInterval time.Duration
+	expiration    time.Duration
+	dirty         bool
+	peers         map[string]peerTime
+	flushReq      chan struct{}
+	stopChan      chan struct{}
+	stopped       sync.WaitGroup
+	stopOnce      sync.Once
+}
+
+// NewReplayWindow creates a ReplayWindow persisted under the given directory,
+// loading the timestamps persisted by a previous incarnation of this peer, if any.
+// The window is persisted in the background: new incarnations right away, and
+// timestamps that only advance the sequence number of a known incarnation at
+// most once every flushInterval, at which time the peers not seen for longer
+// than expiration are pruned.
+func NewReplayWindow(dir string, flushInterval, expiration time.Duration, logger util.Logger) (ReplayWindow, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed creating replay window directory %s", dir)
+	}
+
+	rw := &fileReplayWindow{
+		path:          filepath.Join(dir, replayWindowFileName),
+		logger:        logger,
+		flushInterval: flushInterval,
+		expiration:    expiration,
+		peers:         make(map[string]peerTime),
+		flushReq:      make(chan struct{}, 1),
+		stopChan:      make(chan struct{}),
+	}
+
+	if err := rw.load(); err != nil {
+		return nil, err
+	}
+
+	rw.stopped.Add(1)
+	go rw.persist()
+	return rw, nil
+}
+
+func (rw *fileReplayWindow) load() error {
+	raw, err := ioutil.ReadFile(rw.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed reading replay window from %s", rw.path)
+	}
+
+	persisted := make(map[string]peerTime)
+	if err := json.Unmarshal(raw, &persisted); err != nil {
+		return errors.Wrapf(err, "failed unmarshaling replay window from %s", rw.path)
+	}
+	now := time.Now().UnixNano()
+	for id, ts := range persisted {
+		if _, err := hex.DecodeString(id); err != nil {
+			return errors.Errorf("invalid PKI-ID %s in replay window %s", id, rw.path)
+		}
+		// the peers persisted without the time they were seen at are kept
+		// for a full expiration period
+		if ts.Seen == 0 {
+			ts.Seen = now
+		}
+		rw.peers[id] = ts
+	}
+	return nil
+}
+
+func (rw *fileReplayWindow) Accept(pkiID common.PKIidType, ts *proto.PeerTime) bool {
+	if ts == nil {
+		return false
+	}
+
+	rw.Lock()
+	defer rw.Unlock()
+
+	id := hex.EncodeToString(pkiID)
+	last, exists := rw.peers[id]
+	if exists {
+		if ts.IncNum < last.IncNum || (ts.IncNum == last.IncNum && ts.SeqNum < last.SeqNum) {
+			return false
+		}
+	}
+
+	rw.peers[id] = peerTime{IncNum: ts.IncNum, SeqNum: ts.SeqNum, Seen: time.Now().UnixNano()}
+	if exists && ts.IncNum == last.IncNum && ts.SeqNum == last.SeqNum {
+		return true
+	}
+	rw.dirty = true
+
+	if !exists || ts.IncNum != last.IncNum {
+		select {
+		case rw.flushReq <- struct{}{}:
+		default:
+		}
+	}
+
+	return true
+}
+
+func (rw *fileReplayWindow) Close() error {
+	rw.stopOnce.Do(func() {
+		close(rw.stopChan)
+	})
+	rw.stopped.Wait()
+	return rw.flush()
+}
+
+// persist flushes the window, off the path of the gossip messages, when a
+// new incarnation is accepted and periodically
+func (rw *fileReplayWindow) persist() {
+	defer rw.stopped.Done()
+
+	ticker := time.NewTicker(rw.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rw.stopChan:
+			return
+		case <-rw.flushReq:
+		case <-ticker.C:
+			rw.prune()
+		}
+		if err := rw.flush(); err != nil {
+			rw.logger.Warningf("Failed persisting gossip replay window: %+v", err)
+		}
+	}
+}
+
+// prune forgets the peers that weren't seen for longer than the expiration
+func (rw *fileReplayWindow) prune() {
+	rw.Lock()
+	defer rw.Unlock()
+
+	expired := time.Now().Add(-rw.expiration).UnixNano()
+	for id, ts := range rw.peers {
+		if ts.Seen < expired {
+			delete(rw.peers, id)
+			rw.dirty = true
+		}
+	}
+}
+
+func (rw *fileReplayWindow) flush() error {
+	rw.Lock()
+	if !rw.dirty {
+		rw.Unlock()
+		return nil
+	}
+	raw, err := json.Marshal(rw.peers)
+	rw.dirty = false
+	rw.Unlock()
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling replay window")
+	}
+
+	tmpPath := rw.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, raw, 0600); err != nil {
+		rw.markDirty()
+		return errors.Wrapf(err, "failed writing replay window to %s", tmpPath)
+	}
+	if err := os.Rename(tmpPath, rw.path); err != nil {
+		rw.markDirty()
+		return errors.Wrapf(err, "failed renaming replay window to %s", rw.path)
+	}
+	return nil
+}
+
+func (rw *fileReplayWindow) markDirty() {
+	rw.Lock()
+	defer rw.Unlock()
+	rw.dirty = true
+}
+
+// seenWindow remembers keys until they expire, in order to detect the
+// membership requests and responses which are replayed
+type seenWindow struct {
+	sync.Mutex
+	expiration time.Duration
+	seen       map[string]time.Time
+}
+
+func newSeenWindow(expiration time.Duration) *seenWindow {
+	return &seenWindow{
+		expiration: expiration,
+		seen:       make(map[string]time.Time),
+	}
+}
+
+// add adds the given key, and returns false if it was already added and
+// hasn't expired yet
+func (w *seenWindow) add(key string) bool {
+	w.Lock()
+	defer w.Unlock()
+	w.prune()
+	if _, exists := w.seen[key]; exists {
+		return false
+	}
+	w.seen[key] = time.Now()
+	return true
+}
+
+// remove removes the given key, and returns false if it wasn't added or
+// has expired
+func (w *seenWindow) remove(key string) bool {
+	w.Lock()
+	defer w.Unlock()
+	w.prune()
+	if _, exists := w.seen[key]; !exists {
+		return false
+	}
+	delete(w.seen, key)
+	return true
+}
+
+func (w *seenWindow) prune() {
+	expired := time.Now().Add(-w.expiration)
+	for key, added := range w.seen {
+		if added.Before(expired) {
+			delete(w.seen, key)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	proto "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayWindow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replaywindow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := util.GetLogger(util.DiscoveryLogger, "")
+	p1 := common.PKIidType("p1")
+	p2 := common.PKIidType("p2")
+	p3 := common.PKIidType("p3")
+
+	rw, err := NewReplayWindow(dir, time.Hour, time.Hour, logger)
+	require.NoError(t, err)
+
+	assert.False(t, rw.Accept(p1, nil))
+	assert.True(t, rw.Accept(p1, &proto.PeerTime{IncNum: 10, SeqNum: 5}))
+	assert.True(t, rw.Accept(p2, &proto.PeerTime{IncNum: 1, SeqNum: 1}))
+	assert.True(t, rw.Accept(p1, &proto.PeerTime{IncNum: 10, SeqNum: 5}))
+	assert.True(t, rw.Accept(p1, &proto.PeerTime{IncNum: 10, SeqNum: 6}))
+	assert.False(t, rw.Accept(p1, &proto.PeerTime{IncNum: 10, SeqNum: 4}))
+	assert.False(t, rw.Accept(p1, &proto.PeerTime{IncNum: 9, SeqNum: 100}))
+	require.NoError(t, rw.Close())
+
+	// After the window is closed, the sequence number advance survives a restart
+	rw, err = NewReplayWindow(dir, time.Hour, time.Hour, logger)
+	require.NoError(t, err)
+	assert.False(t, rw.Accept(p1, &proto.PeerTime{IncNum: 10, SeqNum: 5}))
+	assert.True(t, rw.Accept(p1, &proto.PeerTime{IncNum: 11, SeqNum: 0}))
+	assert.False(t, rw.Accept(p2, &proto.PeerTime{IncNum: 0, SeqNum: 2}))
+
+	// A new incarnation is persisted in the background, without waiting for
+	// the window to be closed
+	assert.True(t, rw.Accept(p3, &proto.PeerTime{IncNum: 1, SeqNum: 1}))
+	assert.Eventually(t, func() bool {
+		raw, err := ioutil.ReadFile(filepath.Join(dir, replayWindowFileName))
+		return err == nil && strings.Contains(string(raw), hex.EncodeToString(p3))
+	}, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, rw.Close())
+}
+
+func TestReplayWindowPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replaywindow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := util.GetLogger(util.DiscoveryLogger, "")
+	p1 := common.PKIidType("p1")
+	p2 := common.PKIidType("p2")
+
+	rw, err := NewReplayWindow(dir, time.Hour, time.Minute, logger)
+	require.NoError(t, err)
+	assert.True(t, rw.Accept(p1, &proto.PeerTime{IncNum: 10, SeqNum: 5}))
+	assert.True(t, rw.Accept(p2, &proto.PeerTime{IncNum: 10, SeqNum: 5}))
+
+	frw := rw.(*fileReplayWindow)
+	frw.Lock()
+	expired := frw.peers[hex.EncodeToString(p1)]
+	expired.Seen = time.Now().Add(-2 * time.Minute).UnixNano()
+	frw.peers[hex.EncodeToString(p1)] = expired
+	frw.Unlock()
+	frw.prune()
+	require.NoError(t, rw.Close())
+
+	// The peer which wasn't seen for longer than the expiration is forgotten
+	rw, err = NewReplayWindow(dir, time.Hour, time.Minute, logger)
+	require.NoError(t, err)
+	defer rw.Close()
+	assert.True(t, rw.Accept(p1, &proto.PeerTime{IncNum: 1, SeqNum: 1}))
+	assert.False(t, rw.Accept(p2, &proto.PeerTime{IncNum: 1, SeqNum: 1}))
+}
+
+func TestReplayWindowWithoutSeenTime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replaywindow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := util.GetLogger(util.DiscoveryLogger, "")
+	p1 := common.PKIidType("p1")
+
+	persisted := fmt.Sprintf(`{"%s":{"inc_num":10,"seq_num":5}}`, hex.EncodeToString(p1))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, replayWindowFileName), []byte(persisted), 0600))
+	rw, err := NewReplayWindow(dir, time.Hour, time.Minute, logger)
+	require.NoError(t, err)
+	defer rw.Close()
+
+	// The peers persisted without the time they were seen at aren't pruned right away
+	rw.(*fileReplayWindow).prune()
+	assert.False(t, rw.Accept(p1, &proto.PeerTime{IncNum: 10, SeqNum: 4}))
+}
+
+func TestSeenWindow(t *testing.T) {
+	w := newSeenWindow(time.Minute)
+	assert.True(t, w.add("a"))
+	assert.False(t, w.add("a"))
+	assert.True(t, w.remove("a"))
+	assert.False(t, w.remove("a"))
+	assert.False(t, w.remove("b"))
+
+	w = newSeenWindow(time.Millisecond)
+	assert.True(t, w.add("a"))
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, w.add("a"))
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, w.remove("a"))
+}
+
+func TestReplayWindowCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replaywindow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := util.GetLogger(util.DiscoveryLogger, "")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, replayWindowFileName), []byte("{"), 0600))
+	_, err = NewReplayWindow(dir, time.Hour, time.Hour, logger)
+	assert.Contains(t, err.Error(), "failed unmarshaling replay window")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, replayWindowFileName), []byte(`{"zz":{"inc_num":1,"seq_num":1}}`), 0600))
+	_, err = NewReplayWindow(dir, time.Hour, time.Hour, logger)
+	assert.Contains(t, err.Error(), "invalid PKI-ID zz")
+}
+
+func TestDiscoveryRejectsReplayedAliveMessages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replaywindow")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := util.GetLogger(util.DiscoveryLogger, "")
+	rw, err := NewReplayWindow(dir, time.Hour, time.Hour, logger)
+	require.NoError(t, err)
+	defer rw.Close()
+
+	d := &gossipDiscoveryImpl{
+		self:         NetworkMember{PKIid: common.PKIidType("self")},
+		logger:       logger,
+		replayWindow: rw,
+	}
+
+	aliveMsg := func(pkiID string, inc, seq uint64) *proto.GossipMessage {
+		return &proto.GossipMessage{
+			Content: &proto.GossipMessage_AliveMsg{
+				AliveMsg: &proto.AliveMessage{
+					Membership: &proto.Member{PkiId: []byte(pkiID)},
+					Timestamp:  &proto.PeerTime{IncNum: inc, SeqNum: seq},
+				},
+			},
+		}
+	}
+
+	msg, err := protoext.NoopSign(aliveMsg("p1", 2, 2))
+	require.NoError(t, err)
+	assert.False(t, d.isReplayed(msg))
+
+	msg, err = protoext.NoopSign(aliveMsg("p1", 1, 3))
+	require.NoError(t, err)
+	assert.True(t, d.isReplayed(msg))
+
+	msg, err = protoext.NoopSign(aliveMsg("self", 0, 0))
+	require.NoError(t, err)
+	assert.False(t, d.isReplayed(msg))
+}
+
+func TestDiscoveryRejectsReplayedMembershipMessages(t *testing.T) {
+	d := &gossipDiscoveryImpl{
+		self:            NetworkMember{PKIid: common.PKIidType("self")},
+		logger:          util.GetLogger(util.DiscoveryLogger, ""),
+		answeredMemReqs: newSeenWindow(time.Minute),
+		pendingMemReqs:  newSeenWindow(time.Minute),
+	}
+
+	selfInfo := func(inc, seq uint64) *protoext.SignedGossipMessage {
+		msg, err := protoext.NoopSign(&proto.GossipMessage{
+			Content: &proto.GossipMessage_AliveMsg{
+				AliveMsg: &proto.AliveMessage{
+					Membership: &proto.Member{PkiId: []byte("p1")},
+					Timestamp:  &proto.PeerTime{IncNum: inc, SeqNum: seq},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return msg
+	}
+
+	// A membership request is only answered once
+	assert.False(t, d.isReplayedMemReq(selfInfo(1, 1)))
+	assert.True(t, d.isReplayedMemReq(selfInfo(1, 1)))
+	assert.False(t, d.isReplayedMemReq(selfInfo(1, 2)))
+
+	// A membership response is only accepted once, from the peer the request was sent to
+	d.expectMemResp(common.PKIidType("p1"), 42)
+	assert.True(t, d.isReplayedMemResp(common.PKIidType("p2"), 42))
+	assert.True(t, d.isReplayedMemResp(common.PKIidType("p1"), 43))
+	assert.False(t, d.isReplayedMemResp(common.PKIidType("p1"), 42))
+	assert.True(t, d.isReplayedMemResp(common.PKIidType("p1"), 42))
+
+	// Replay protection is disabled without a replay window
+	d = &gossipDiscoveryImpl{logger: d.logger}
+	d.expectMemResp(common.PKIidType("p1"), 42)
+	assert.False(t, d.isReplayedMemReq(selfInfo(1, 1)))
+	assert.False(t, d.isReplayedMemReq(selfInfo(1, 1)))
+	assert.False(t, d.isReplayedMemResp(common.PKIidType("p1"), 43))
+}
@@ -72,6 +72,7 @@ type PullAdapter interface {
 // with the help of the PullAdapter
 type PullEngine struct {
 	PullAdapter
+	sleepTime          int64
 	stopFlag           int32
 	state              *util.Set
 	item2owners        map[string][]string
@@ -103,6 +104,7 @@ func NewPullEngineWithFilter(participant PullAdapter, sleepTime time.Duration, d
 	engine := &PullEngine{
 		PullAdapter:        participant,
 		stopFlag:           int32(0),
+		sleepTime:          int64(sleepTime),
 		state:              util.NewSet(),
 		item2owners:        make(map[string][]string),
 		peers2nonces:       make(map[string]uint64),
@@ -119,7 +121,7 @@ func NewPullEngineWithFilter(participant PullAdapter, sleepTime time.Duration, d
 
 	go func() {
 		for !engine.toDie() {
-			time.Sleep(sleepTime)
+			time.Sleep(engine.getSleepTime())
 			if engine.toDie() {
 				return
 			}
@@ -141,6 +143,16 @@ func NewPullEngine(participant PullAdapter, sleepTime time.Duration, config Pull
 	return NewPullEngineWithFilter(participant, sleepTime, acceptAllFilter, config)
 }
 
+// SetSleepTime changes the sleep time between pull initiations,
+// starting from the next pull initiation
+func (engine *PullEngine) SetSleepTime(sleepTime time.Duration) {
+	atomic.StoreInt64(&(engine.sleepTime), int64(sleepTime))
+}
+
+func (engine *PullEngine) getSleepTime() time.Duration {
+	return time.Duration(atomic.LoadInt64(&(engine.sleepTime)))
+}
+
 func (engine *PullEngine) toDie() bool {
 	return atomic.LoadInt32(&(engine.stopFlag)) == int32(1)
 }
@@ -200,6 +200,31 @@ func TestPullEngine_Stop(t *testing.T) {
 	assert.Equal(t, len1, len2, "PullEngine was still active after Stop() was invoked!")
 }
 
+func TestPullEngine_SetSleepTime(t *testing.T) {
+	// Scenario: inst2 pulls from inst1, and its sleep time between pull
+	// initiations is shortened from 500ms to 50ms.
+	// Expected outcome: inst1 receives many more hello messages than
+	// the initial sleep time would yield.
+	peers := make(map[string]*pullTestInstance)
+	inst1 := newPushPullTestInstance("p1", peers)
+	inst2 := newPushPullTestInstance("p2", peers)
+	defer inst1.stop()
+	defer inst2.stop()
+
+	var hellos int32
+	inst1.hook(func(m interface{}) {
+		if _, isHello := m.(*helloMsg); isHello {
+			atomic.AddInt32(&hellos, 1)
+		}
+	})
+	inst2.setNextPeerSelection([]string{"p1"})
+	inst2.SetSleepTime(time.Duration(50) * time.Millisecond)
+	assert.Equal(t, time.Duration(50)*time.Millisecond, inst2.getSleepTime())
+
+	time.Sleep(time.Duration(1500) * time.Millisecond)
+	assert.True(t, atomic.LoadInt32(&hellos) > 5, "expected more than 5 hello messages, got %d", atomic.LoadInt32(&hellos))
+}
+
 func TestPullEngineAll2AllWithIncrementalSpawning(t *testing.T) {
 	// Scenario: spawn 10 nodes, each 50 ms after the other
 	// and have them transfer data between themselves.
@@ -42,6 +42,7 @@ type Config struct {
 	MaxBlockCountToStore        int
 	PullPeerNum                 int
 	PullInterval                time.Duration
+	PropagatePeerNum            int
 	RequestStateInfoInterval    time.Duration
 	BlockExpirationInterval     time.Duration
 	StateInfoCacheSweepInterval time.Duration
@@ -50,6 +51,23 @@ type Config struct {
 	RequestWaitTime             time.Duration
 	ResponseWaitTime            time.Duration
 	MsgExpirationTimeout        time.Duration
+
+	// AdaptiveTuning makes the number of peers blocks are pushed to and the
+	// interval between pull invocations adapt to the loss of pushed blocks
+	// and to the number of peers in the channel, within the bounds below
+	AdaptiveTuning      bool
+	MinPropagatePeerNum int
+	MaxPropagatePeerNum int
+	MinPullInterval     time.Duration
+	MaxPullInterval     time.Duration
+
+	// PartitionDetection makes the channel periodically look for peers it
+	// hasn't exchanged channel messages with for PartitionSilenceThreshold,
+	// and try to heal the partition by probing them and reconnecting to the
+	// anchor peers of the channel
+	PartitionDetection        bool
+	PartitionCheckInterval    time.Duration
+	PartitionSilenceThreshold time.Duration
 }
 
 // GossipChannel defines an object that deals with all channel-related messages
@@ -82,6 +100,14 @@ type GossipChannel interface {
 	// for this channel
 	EligibleForChannel(member discovery.NetworkMember) bool
 
+	// PropagatePeerNum returns the number of peers blocks of this channel
+	// are pushed to
+	PropagatePeerNum() int
+
+	// UnreachablePeers returns the number of peers of the channel that
+	// remained partitioned despite the last attempt to heal the partition
+	UnreachablePeers() int
+
 	// HandleMessage processes a message sent by a remote peer
 	HandleMessage(protoext.ReceivedMessage)
 
@@ -135,6 +161,10 @@ type Adapter interface {
 	// GetIdentityByPKIID returns an identity of a peer with a certain
 	// pkiID, or nil if not found
 	GetIdentityByPKIID(pkiID common.PKIidType) api.PeerIdentityType
+
+	// ConnectToAnchorPeers connects to the given anchor peers of an
+	// organization in the channel
+	ConnectToAnchorPeers(channel common.ChannelID, org api.OrgIdentityType, anchorPeers []api.AnchorPeer)
 }
 
 type gossipChannel struct {
@@ -157,11 +187,15 @@ type gossipChannel struct {
 	logger                    util.Logger
 	stateInfoPublishScheduler *time.Ticker
 	stateInfoRequestScheduler *time.Ticker
+	partitionCheckScheduler   *time.Ticker
 	memFilter                 *membershipFilter
 	ledgerHeight              uint64
 	incTime                   uint64
 	leftChannel               int32
 	membershipTracker         *membershipTracker
+	tuner                     *adaptiveTuner
+	partitions                *partitionDetector
+	metrics                   *metrics.MembershipMetrics
 }
 
 type membershipFilter struct {
@@ -200,6 +234,7 @@ func NewGossipChannel(pkiID common.PKIidType, org api.OrgIdentityType, mcs api.M
 		stateInfoRequestScheduler: time.NewTicker(adapter.GetConf().RequestStateInfoInterval),
 		orgs:                      []api.OrgIdentityType{},
 		chainID:                   channelID,
+		metrics:                   metrics,
 	}
 
 	if logger == nil {
@@ -213,6 +248,10 @@ func NewGossipChannel(pkiID common.PKIidType, org api.OrgIdentityType, mcs api.M
 	comparator := protoext.NewGossipMessageComparator(adapter.GetConf().MaxBlockCountToStore)
 
 	gc.blocksPuller = gc.createBlockPuller()
+	if adapter.GetConf().AdaptiveTuning {
+		gc.tuner = newAdaptiveTuner(adapter.GetConf())
+		gc.blocksPuller.SetPullInterval(gc.tuner.PullInterval())
+	}
 
 	seqNumFromMsg := func(m interface{}) string {
 		return fmt.Sprintf("%d", m.(*protoext.SignedGossipMessage).GetDataMsg().Payload.SeqNum)
@@ -301,9 +340,96 @@ func NewGossipChannel(pkiID common.PKIidType, org api.OrgIdentityType, mcs api.M
 	}
 
 	go gc.membershipTracker.trackMembershipChanges()
+
+	if gc.tuner != nil {
+		go gc.adaptPropagation()
+	}
+	if adapter.GetConf().PartitionDetection {
+		gc.partitions = newPartitionDetector(adapter.GetConf())
+		gc.partitionCheckScheduler = time.NewTicker(adapter.GetConf().PartitionCheckInterval)
+		go gc.periodicalInvocation(gc.checkPartition, gc.partitionCheckScheduler.C)
+	}
 	return gc
 }
 
+// adaptPropagation periodically adjusts the number of peers blocks are pushed to
+// and the interval between pull invocations, according to the blocks received
+// since the previous adjustment and to the number of peers in the channel
+func (gc *gossipChannel) adaptPropagation() {
+	timer := time.NewTimer(gc.tuner.PullInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			peerCount := len(gc.memFilter.GetMembership())
+			fanout, interval := gc.tuner.adjust(peerCount)
+			gc.blocksPuller.SetPullInterval(interval)
+			gc.logger.Debugf("[%s] Channel has %d peers, pushing blocks to %d peers and pulling every %v",
+				string(gc.chainID), peerCount, fanout, interval)
+			timer.Reset(interval)
+		case <-gc.stopChan:
+			return
+		}
+	}
+}
+
+// PropagatePeerNum returns the number of peers blocks of this channel
+// are pushed to
+func (gc *gossipChannel) PropagatePeerNum() int {
+	if gc.tuner == nil {
+		return gc.GetConf().PropagatePeerNum
+	}
+	return gc.tuner.PropagatePeerNum()
+}
+
+// checkPartition looks for peers of the channel that no channel message was
+// exchanged with for the silence threshold, and tries to heal the partition by
+// probing them directly and by reconnecting to the anchor peers of the channel
+func (gc *gossipChannel) checkPartition() {
+	silent, unhealed := gc.partitions.detect(gc.GetPeers())
+	gc.metrics.PartitionedPeers.With("channel", string(gc.chainID)).Set(float64(len(silent)))
+	if len(unhealed) > 0 {
+		gc.metrics.HealingFailures.With("channel", string(gc.chainID)).Add(1)
+		gc.logger.Warningf("[%s] Failed healing the partition of the channel, %d peers are still unreachable: %v",
+			string(gc.chainID), len(unhealed), endpointsOf(unhealed))
+	}
+	if len(silent) == 0 {
+		return
+	}
+	gc.logger.Warningf("[%s] No channel message was exchanged with %d peers for %v, trying to reconnect to them: %v",
+		string(gc.chainID), len(silent), gc.GetConf().PartitionSilenceThreshold, endpointsOf(silent))
+
+	req, err := gc.createStateInfoRequest()
+	if err != nil {
+		gc.logger.Warningf("Failed creating SignedGossipMessage: %+v", err)
+	} else {
+		peers := make([]*comm.RemotePeer, 0, len(silent))
+		for _, member := range silent {
+			peers = append(peers, &comm.RemotePeer{PKIID: member.PKIid, Endpoint: member.PreferredEndpoint()})
+		}
+		gc.Send(req, peers...)
+	}
+
+	gc.RLock()
+	joinMsg := gc.joinMsg
+	gc.RUnlock()
+	if joinMsg == nil {
+		return
+	}
+	for _, org := range joinMsg.Members() {
+		gc.ConnectToAnchorPeers(gc.chainID, org, joinMsg.AnchorPeersOf(org))
+	}
+}
+
+// UnreachablePeers returns the number of peers of the channel that
+// remained partitioned despite the last attempt to heal the partition
+func (gc *gossipChannel) UnreachablePeers() int {
+	if gc.partitions == nil {
+		return 0
+	}
+	return gc.partitions.Unhealed()
+}
+
 func (gc *gossipChannel) reportMembershipChanges(input ...interface{}) {
 	args := []interface{}{fmt.Sprintf("[%s]", string(gc.chainID))}
 	args = append(args, input...)
@@ -317,6 +443,9 @@ func (gc *gossipChannel) Stop() {
 	gc.blocksPuller.Stop()
 	gc.stateInfoPublishScheduler.Stop()
 	gc.stateInfoRequestScheduler.Stop()
+	if gc.partitionCheckScheduler != nil {
+		gc.partitionCheckScheduler.Stop()
+	}
 	gc.leaderMsgStore.Stop()
 	gc.stateInfoMsgStore.Stop()
 	gc.blockMsgStore.Stop()
@@ -619,6 +748,10 @@ func (gc *gossipChannel) HandleMessage(msg protoext.ReceivedMessage) {
 		return
 	}
 
+	if gc.partitions != nil {
+		gc.partitions.onContact(msg.GetConnectionInfo().ID)
+	}
+
 	if protoext.IsStateInfoPullRequestMsg(m.GossipMessage) {
 		msg.Respond(gc.createStateInfoSnapshot(orgID))
 		return
@@ -650,6 +783,9 @@ func (gc *gossipChannel) HandleMessage(msg protoext.ReceivedMessage) {
 			if added {
 				gc.logger.Debugf("Adding %v to the block puller", msg.GetGossipMessage())
 				gc.blocksPuller.Add(msg.GetGossipMessage())
+				if gc.tuner != nil {
+					gc.tuner.onPushed()
+				}
 			}
 			gc.Unlock()
 		} else { // StateInfoMsg verification should be handled in a layer above
@@ -720,6 +856,9 @@ func (gc *gossipChannel) HandleMessage(msg protoext.ReceivedMessage) {
 					// exists in memory or that it is too far in the past
 					continue
 				}
+				if gc.tuner != nil {
+					gc.tuner.onPulled()
+				}
 				filteredEnvelopes = append(filteredEnvelopes, item)
 			}
 
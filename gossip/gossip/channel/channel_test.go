@@ -276,6 +276,14 @@ func (ga *gossipAdapterMock) GetIdentityByPKIID(pkiID common.PKIidType) api.Peer
 	return api.PeerIdentityType(pkiID)
 }
 
+func (ga *gossipAdapterMock) ConnectToAnchorPeers(channel common.ChannelID, org api.OrgIdentityType, anchorPeers []api.AnchorPeer) {
+	// Ensure we have configured ConnectToAnchorPeers prior
+	if !ga.wasMocked("ConnectToAnchorPeers") {
+		return
+	}
+	ga.Called(channel, org, anchorPeers)
+}
+
 func (ga *gossipAdapterMock) wasMocked(methodName string) bool {
 	ga.RLock()
 	defer ga.RUnlock()
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+)
+
+// partitionDetector detects partitions of a channel: peers of the channel
+// that are known to be alive, through messages relayed by other peers, but
+// that this peer doesn't exchange channel messages such as block pull digests
+// and state info snapshots with. Such peers are in another clique of the
+// channel membership, which exchanges digests only within itself.
+type partitionDetector struct {
+	silenceThreshold time.Duration
+	now              func() time.Time
+
+	lock        sync.Mutex
+	lastContact map[string]time.Time
+	suspected   map[string]struct{}
+	unhealed    int
+}
+
+func newPartitionDetector(conf Config) *partitionDetector {
+	return &partitionDetector{
+		silenceThreshold: conf.PartitionSilenceThreshold,
+		now:              time.Now,
+		lastContact:      make(map[string]time.Time),
+		suspected:        make(map[string]struct{}),
+	}
+}
+
+// onContact records a channel message received directly from a peer
+func (d *partitionDetector) onContact(pkiID common.PKIidType) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.lastContact[string(pkiID)] = d.now()
+}
+
+// detect returns the given peers of the channel that no channel message was
+// received from for the silence threshold, and among them the peers that
+// were already silent at the previous detection, i.e. that the attempt to
+// heal the partition failed to reconnect to.
+// A peer is given the silence threshold to make contact from the first
+// detection it is seen in.
+func (d *partitionDetector) detect(peers []discovery.NetworkMember) (silent, unhealed []discovery.NetworkMember) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := d.now()
+	current := make(map[string]struct{}, len(peers))
+	suspected := make(map[string]struct{})
+	for _, peer := range peers {
+		id := string(peer.PKIid)
+		current[id] = struct{}{}
+		last, exists := d.lastContact[id]
+		if !exists {
+			d.lastContact[id] = now
+			continue
+		}
+		if now.Sub(last) < d.silenceThreshold {
+			continue
+		}
+		silent = append(silent, peer)
+		suspected[id] = struct{}{}
+		if _, wasSuspected := d.suspected[id]; wasSuspected {
+			unhealed = append(unhealed, peer)
+		}
+	}
+
+	// Forget peers that left the channel or are no longer alive
+	for id := range d.lastContact {
+		if _, exists := current[id]; !exists {
+			delete(d.lastContact, id)
+		}
+	}
+	d.suspected = suspected
+	d.unhealed = len(unhealed)
+	return silent, unhealed
+}
+
+// Unhealed returns the number of peers that remained partitioned
+// at the last detection despite the attempt to heal the partition
+func (d *partitionDetector) Unhealed() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.unhealed
+}
+
+func endpointsOf(peers []discovery.NetworkMember) []string {
+	endpoints := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		endpoints = append(endpoints, peer.PreferredEndpoint())
+	}
+	return endpoints
+}
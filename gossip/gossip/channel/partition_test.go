@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/metrics"
+	"github.com/hyperledger/fabric/gossip/metrics/mocks"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestPartitionDetector(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	d := newPartitionDetector(Config{PartitionSilenceThreshold: time.Minute})
+	d.now = clock.Now
+
+	p1 := discovery.NetworkMember{PKIid: common.PKIidType("p1"), Endpoint: "p1:7051"}
+	p2 := discovery.NetworkMember{PKIid: common.PKIidType("p2"), Endpoint: "p2:7051"}
+	p3 := discovery.NetworkMember{PKIid: common.PKIidType("p3"), Endpoint: "p3:7051"}
+	peers := []discovery.NetworkMember{p1, p2, p3}
+
+	// p1 makes contact, the others are given the silence threshold
+	// from the first detection they are seen in
+	d.onContact(p1.PKIid)
+	silent, unhealed := d.detect(peers)
+	assert.Empty(t, silent)
+	assert.Empty(t, unhealed)
+
+	clock.advance(30 * time.Second)
+	d.onContact(p2.PKIid)
+	clock.advance(30 * time.Second)
+	silent, unhealed = d.detect(peers)
+	assert.Equal(t, []discovery.NetworkMember{p1, p3}, silent)
+	assert.Empty(t, unhealed)
+	assert.Equal(t, 0, d.Unhealed())
+
+	// p1 makes contact again after the healing attempt, p3 doesn't
+	d.onContact(p1.PKIid)
+	clock.advance(30 * time.Second)
+	silent, unhealed = d.detect(peers)
+	assert.Equal(t, []discovery.NetworkMember{p2, p3}, silent)
+	assert.Equal(t, []discovery.NetworkMember{p3}, unhealed)
+	assert.Equal(t, 1, d.Unhealed())
+
+	// Peers that are gone are forgotten
+	silent, unhealed = d.detect([]discovery.NetworkMember{p1})
+	assert.Empty(t, silent)
+	assert.Empty(t, unhealed)
+	assert.Equal(t, 0, d.Unhealed())
+	silent, _ = d.detect(peers)
+	assert.Empty(t, silent)
+	assert.Equal(t, []string{"p1:7051", "p2:7051", "p3:7051"}, endpointsOf(peers))
+}
+
+func TestChannelPartitionHealing(t *testing.T) {
+	anchorPeer := api.AnchorPeer{Host: "anchor", Port: 7051}
+	jcm := &joinChanMsg{
+		members2AnchorPeers: map[string][]api.AnchorPeer{
+			string(orgInChannelA): {anchorPeer},
+		},
+	}
+	member := discovery.NetworkMember{PKIid: pkiIDInOrg1, Endpoint: "p1:7051"}
+
+	partitionConf := conf
+	partitionConf.PartitionDetection = true
+	partitionConf.PartitionCheckInterval = time.Hour
+	partitionConf.PartitionSilenceThreshold = time.Minute
+	// Don't let the periodical state info requests interfere
+	partitionConf.RequestStateInfoInterval = time.Hour
+
+	cs := &cryptoService{}
+	adapter := new(gossipAdapterMock)
+	adapter.On("GetConf").Return(partitionConf)
+	adapter.On("GetMembership").Return([]discovery.NetworkMember{member})
+	adapter.On("GetOrgOfPeer", pkiIDInOrg1).Return(orgInChannelA)
+	adapter.On("GetOrgOfPeer", mock.Anything).Return(api.OrgIdentityType(nil))
+	adapter.On("Gossip", mock.Anything)
+	adapter.On("Forward", mock.Anything)
+	adapter.On("DeMultiplex", mock.Anything)
+	adapter.On("ValidateStateInfoMessage", mock.Anything).Return(nil)
+	adapter.On("ConnectToAnchorPeers", channelA, orgInChannelA, []api.AnchorPeer{anchorPeer})
+	probed := make(chan []*comm.RemotePeer, 10)
+	adapter.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		if protoext.IsStateInfoPullRequestMsg(args.Get(0).(*protoext.SignedGossipMessage).GossipMessage) {
+			probed <- args.Get(1).([]*comm.RemotePeer)
+		}
+	})
+
+	testMetricProvider := mocks.TestUtilConstructMetricProvider()
+	membershipMetrics := metrics.NewGossipMetrics(testMetricProvider.FakeProvider).MembershipMetrics
+	gc := NewGossipChannel(common.PKIidType("p0"), orgInChannelA, cs, channelA, adapter, jcm, membershipMetrics, nil).(*gossipChannel)
+	defer gc.Stop()
+	clock := &fakeClock{now: time.Now()}
+	gc.partitions.now = clock.Now
+
+	gc.HandleMessage(&receivedMsg{PKIID: pkiIDInOrg1, msg: createStateInfoMsg(1, pkiIDInOrg1, channelA)})
+	assert.Len(t, gc.GetPeers(), 1)
+
+	// Contact was made recently, there is no partition
+	gc.checkPartition()
+	assert.Equal(t, 0, gc.UnreachablePeers())
+	adapter.AssertNotCalled(t, "ConnectToAnchorPeers", mock.Anything, mock.Anything, mock.Anything)
+
+	// After the silence threshold the peer is probed and the anchor peers are reconnected to
+	clock.advance(time.Minute)
+	gc.checkPartition()
+	assert.Equal(t, []*comm.RemotePeer{{PKIID: pkiIDInOrg1, Endpoint: "p1:7051"}}, <-probed)
+	adapter.AssertNumberOfCalls(t, "ConnectToAnchorPeers", 1)
+	assert.Equal(t, 0, gc.UnreachablePeers())
+	assert.Equal(t, 0, testMetricProvider.FakeHealingFailures.AddCallCount())
+	assert.EqualValues(t, 1, testMetricProvider.FakePartitionedPeersGauge.SetArgsForCall(1))
+
+	// The peer remains silent, healing failed
+	clock.advance(time.Minute)
+	gc.checkPartition()
+	assert.Equal(t, 1, gc.UnreachablePeers())
+	assert.Equal(t, 1, testMetricProvider.FakeHealingFailures.AddCallCount())
+	assert.Equal(t, []string{"channel", string(channelA)}, testMetricProvider.FakeHealingFailures.WithArgsForCall(0))
+	adapter.AssertNumberOfCalls(t, "ConnectToAnchorPeers", 2)
+
+	// The peer responds to the probe, the partition is healed
+	gc.HandleMessage(&receivedMsg{PKIID: pkiIDInOrg1, msg: stateInfoSnapshotForChannel(channelA, createStateInfoMsg(1, pkiIDInOrg1, channelA))})
+	gc.checkPartition()
+	assert.Equal(t, 0, gc.UnreachablePeers())
+	assert.EqualValues(t, 0, testMetricProvider.FakePartitionedPeersGauge.SetArgsForCall(3))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// lossThreshold is the ratio of blocks obtained by pull, out of all
+	// blocks received in an adjustment round, above which the push
+	// propagation is considered lossy
+	lossThreshold = 0.1
+)
+
+// adaptiveTuner adapts the number of peers blocks are pushed to and the
+// interval between pull invocations of a channel to the loss of pushed
+// blocks and to the number of peers in the channel.
+// A block that was obtained by pull rather than by push is accounted as
+// lost by the push propagation.
+type adaptiveTuner struct {
+	pushed uint64
+	pulled uint64
+
+	lock        sync.RWMutex
+	minFanout   int
+	maxFanout   int
+	minInterval time.Duration
+	maxInterval time.Duration
+	fanout      int
+	interval    time.Duration
+	boost       int
+}
+
+func newAdaptiveTuner(conf Config) *adaptiveTuner {
+	t := &adaptiveTuner{
+		minFanout:   conf.MinPropagatePeerNum,
+		maxFanout:   conf.MaxPropagatePeerNum,
+		minInterval: conf.MinPullInterval,
+		maxInterval: conf.MaxPullInterval,
+	}
+	if t.minFanout < 1 {
+		t.minFanout = 1
+	}
+	if t.maxFanout < t.minFanout {
+		t.maxFanout = t.minFanout
+	}
+	if t.maxInterval < t.minInterval {
+		t.maxInterval = t.minInterval
+	}
+	t.fanout = clampInt(conf.PropagatePeerNum, t.minFanout, t.maxFanout)
+	t.interval = clampDuration(conf.PullInterval, t.minInterval, t.maxInterval)
+	return t
+}
+
+// onPushed records a block received by push
+func (t *adaptiveTuner) onPushed() {
+	atomic.AddUint64(&t.pushed, 1)
+}
+
+// onPulled records a block received by pull
+func (t *adaptiveTuner) onPulled() {
+	atomic.AddUint64(&t.pulled, 1)
+}
+
+// PropagatePeerNum returns the number of peers blocks are currently pushed to
+func (t *adaptiveTuner) PropagatePeerNum() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.fanout
+}
+
+// PullInterval returns the current duration between pull invocations
+func (t *adaptiveTuner) PullInterval() time.Duration {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.interval
+}
+
+// adjust ends an adjustment round given the number of peers in the channel,
+// and returns the push fanout and the pull interval for the next round.
+// When the push propagation was lossy the fanout grows and the pull interval
+// shrinks, otherwise the fanout decays back towards what the peer count
+// requires and the pull interval grows, up to their floors and ceilings.
+func (t *adaptiveTuner) adjust(peerCount int) (int, time.Duration) {
+	pushed := atomic.SwapUint64(&t.pushed, 0)
+	pulled := atomic.SwapUint64(&t.pulled, 0)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// A message pushed to log2(n) peers, and forwarded by each of them,
+	// reaches all n peers with high probability
+	base := 1
+	if peerCount > 1 {
+		base = int(math.Ceil(math.Log2(float64(peerCount + 1))))
+	}
+
+	lossy := pushed+pulled > 0 && float64(pulled)/float64(pushed+pulled) > lossThreshold
+	if lossy {
+		if base+t.boost < t.maxFanout {
+			t.boost++
+		}
+		t.interval = clampDuration(t.interval/2, t.minInterval, t.maxInterval)
+	} else {
+		if t.boost > 0 {
+			t.boost--
+		}
+		t.interval = clampDuration(t.interval+t.interval/2, t.minInterval, t.maxInterval)
+	}
+	t.fanout = clampInt(base+t.boost, t.minFanout, t.maxFanout)
+
+	return t.fanout, t.interval
+}
+
+func clampInt(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+func clampDuration(d, min, max time.Duration) time.Duration {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func tunerConf() Config {
+	return Config{
+		PropagatePeerNum:    3,
+		PullInterval:        4 * time.Second,
+		AdaptiveTuning:      true,
+		MinPropagatePeerNum: 2,
+		MaxPropagatePeerNum: 6,
+		MinPullInterval:     2 * time.Second,
+		MaxPullInterval:     8 * time.Second,
+	}
+}
+
+func TestAdaptiveTunerInitialValues(t *testing.T) {
+	tuner := newAdaptiveTuner(tunerConf())
+	assert.Equal(t, 3, tuner.PropagatePeerNum())
+	assert.Equal(t, 4*time.Second, tuner.PullInterval())
+
+	// The configured values are clamped into their bounds
+	conf := tunerConf()
+	conf.PropagatePeerNum = 10
+	conf.PullInterval = time.Second
+	tuner = newAdaptiveTuner(conf)
+	assert.Equal(t, 6, tuner.PropagatePeerNum())
+	assert.Equal(t, 2*time.Second, tuner.PullInterval())
+
+	// Inconsistent bounds are fixed
+	conf = tunerConf()
+	conf.MinPropagatePeerNum = 0
+	conf.MaxPropagatePeerNum = 0
+	conf.MaxPullInterval = time.Second
+	tuner = newAdaptiveTuner(conf)
+	assert.Equal(t, 1, tuner.PropagatePeerNum())
+	assert.Equal(t, 2*time.Second, tuner.PullInterval())
+}
+
+func TestAdaptiveTunerPeerCount(t *testing.T) {
+	tuner := newAdaptiveTuner(tunerConf())
+
+	for _, tc := range []struct {
+		peerCount      int
+		expectedFanout int
+	}{
+		{peerCount: 0, expectedFanout: 2},
+		{peerCount: 1, expectedFanout: 2},
+		{peerCount: 3, expectedFanout: 2},
+		{peerCount: 7, expectedFanout: 3},
+		{peerCount: 15, expectedFanout: 4},
+		{peerCount: 30, expectedFanout: 5},
+		{peerCount: 500, expectedFanout: 6},
+	} {
+		fanout, _ := tuner.adjust(tc.peerCount)
+		assert.Equal(t, tc.expectedFanout, fanout, "peer count %d", tc.peerCount)
+		assert.Equal(t, tc.expectedFanout, tuner.PropagatePeerNum())
+	}
+}
+
+func TestAdaptiveTunerLoss(t *testing.T) {
+	tuner := newAdaptiveTuner(tunerConf())
+
+	// Without any loss the fanout is what the peer count requires,
+	// and pull phases become less frequent up to the ceiling
+	for i := 0; i < 10; i++ {
+		tuner.onPushed()
+	}
+	fanout, interval := tuner.adjust(7)
+	assert.Equal(t, 3, fanout)
+	assert.Equal(t, 6*time.Second, interval)
+	fanout, interval = tuner.adjust(7)
+	assert.Equal(t, 3, fanout)
+	assert.Equal(t, 8*time.Second, interval)
+
+	// A loss under the threshold changes nothing
+	for i := 0; i < 19; i++ {
+		tuner.onPushed()
+	}
+	tuner.onPulled()
+	fanout, interval = tuner.adjust(7)
+	assert.Equal(t, 3, fanout)
+	assert.Equal(t, 8*time.Second, interval)
+
+	// Lossy rounds grow the fanout and make pull phases more frequent,
+	// down to the floors and up to the ceilings
+	expected := []struct {
+		fanout   int
+		interval time.Duration
+	}{
+		{4, 4 * time.Second},
+		{5, 2 * time.Second},
+		{6, 2 * time.Second},
+		{6, 2 * time.Second},
+	}
+	for _, e := range expected {
+		tuner.onPushed()
+		tuner.onPulled()
+		fanout, interval = tuner.adjust(7)
+		assert.Equal(t, e.fanout, fanout)
+		assert.Equal(t, e.interval, interval)
+	}
+
+	// Once the loss is gone, the fanout decays back
+	for _, e := range []int{5, 4, 3, 3} {
+		tuner.onPushed()
+		fanout, _ = tuner.adjust(7)
+		assert.Equal(t, e, fanout)
+	}
+}
+
+func TestAdaptiveTunerIdle(t *testing.T) {
+	// A round in which all blocks were pulled is lossy,
+	// while a round without any block received is not
+	tuner := newAdaptiveTuner(tunerConf())
+	tuner.onPulled()
+	fanout, interval := tuner.adjust(7)
+	assert.Equal(t, 4, fanout)
+	assert.Equal(t, 2*time.Second, interval)
+
+	fanout, interval = tuner.adjust(7)
+	assert.Equal(t, 3, fanout)
+	assert.Equal(t, 3*time.Second, interval)
+}
+
+func TestChannelPropagatePeerNum(t *testing.T) {
+	newChannel := func(conf Config) GossipChannel {
+		adapter := new(gossipAdapterMock)
+		adapter.On("GetConf").Return(conf)
+		adapter.On("GetMembership").Return([]discovery.NetworkMember{})
+		adapter.On("Gossip", mock.Anything)
+		adapter.On("Send", mock.Anything, mock.Anything)
+		return NewGossipChannel(pkiIDInOrg1, orgInChannelA, &cryptoService{}, channelA, adapter, &joinChanMsg{}, disabledMetrics, nil)
+	}
+
+	staticConf := conf
+	staticConf.PropagatePeerNum = 5
+	gc := newChannel(staticConf)
+	defer gc.Stop()
+	assert.Equal(t, 5, gc.PropagatePeerNum())
+
+	// With adaptive tuning, the fanout starts from the configured one
+	// and then adapts to the (empty) channel
+	adaptiveConf := conf
+	adaptiveConf.PropagatePeerNum = 5
+	adaptiveConf.PullInterval = 100 * time.Millisecond
+	adaptiveConf.AdaptiveTuning = true
+	adaptiveConf.MinPropagatePeerNum = 2
+	adaptiveConf.MaxPropagatePeerNum = 6
+	adaptiveConf.MinPullInterval = 50 * time.Millisecond
+	adaptiveConf.MaxPullInterval = 200 * time.Millisecond
+	gc = newChannel(adaptiveConf)
+	defer gc.Stop()
+	assert.Equal(t, 5, gc.PropagatePeerNum())
+	assert.Eventually(t, func() bool {
+		return gc.PropagatePeerNum() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -124,6 +124,7 @@ func (ga *gossipAdapterImpl) GetConf() channel.Config {
 		PublishStateInfoInterval:    ga.conf.PublishStateInfoInterval,
 		PullInterval:                ga.conf.PullInterval,
 		PullPeerNum:                 ga.conf.PullPeerNum,
+		PropagatePeerNum:            ga.conf.PropagatePeerNum,
 		RequestStateInfoInterval:    ga.conf.RequestStateInfoInterval,
 		BlockExpirationInterval:     ga.conf.PullInterval * 100,
 		StateInfoCacheSweepInterval: ga.conf.PullInterval * 5,
@@ -132,6 +133,14 @@ func (ga *gossipAdapterImpl) GetConf() channel.Config {
 		RequestWaitTime:             ga.conf.RequestWaitTime,
 		ResponseWaitTime:            ga.conf.ResponseWaitTime,
 		MsgExpirationTimeout:        ga.conf.MsgExpirationTimeout,
+		AdaptiveTuning:              ga.conf.AdaptiveTuning,
+		MinPropagatePeerNum:         ga.conf.MinPropagatePeerNum,
+		MaxPropagatePeerNum:         ga.conf.MaxPropagatePeerNum,
+		MinPullInterval:             ga.conf.MinPullInterval,
+		MaxPullInterval:             ga.conf.MaxPullInterval,
+		PartitionDetection:          ga.conf.PartitionDetection,
+		PartitionCheckInterval:      ga.conf.PartitionCheckInterval,
+		PartitionSilenceThreshold:   ga.conf.PartitionSilenceThreshold,
 	}
 }
 
@@ -194,3 +203,9 @@ func (ga *gossipAdapterImpl) GetIdentityByPKIID(pkiID common.PKIidType) api.Peer
 	}
 	return identity
 }
+
+// ConnectToAnchorPeers connects to the given anchor peers of an
+// organization in the channel
+func (ga *gossipAdapterImpl) ConnectToAnchorPeers(channel common.ChannelID, org api.OrgIdentityType, anchorPeers []api.AnchorPeer) {
+	ga.Node.learnAnchorPeers(string(channel), org, anchorPeers)
+}
@@ -11,12 +11,14 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	"github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/discovery"
 	"github.com/hyperledger/fabric/gossip/election"
 	"github.com/hyperledger/fabric/gossip/gossip/algo"
 	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
@@ -46,6 +48,27 @@ type Config struct {
 	// PullPeerNum is the number of peers to pull from.
 	PullPeerNum int
 
+	// AdaptiveTuning makes the number of peers blocks are pushed to and the frequency of block pull phases
+	// of each channel adapt to the loss of pushed blocks and to the number of peers in the channel.
+	AdaptiveTuning bool
+	// MinPropagatePeerNum is the minimum number of peers selected to push blocks to when tuning is adaptive.
+	MinPropagatePeerNum int
+	// MaxPropagatePeerNum is the maximum number of peers selected to push blocks to when tuning is adaptive.
+	MaxPropagatePeerNum int
+	// MinPullInterval is the minimum duration between block pull phases when tuning is adaptive.
+	MinPullInterval time.Duration
+	// MaxPullInterval is the maximum duration between block pull phases when tuning is adaptive.
+	MaxPullInterval time.Duration
+
+	// PartitionDetection makes each channel detect peers it no longer exchanges channel messages with,
+	// and try to heal the partition by probing them and reconnecting to the anchor peers of the channel.
+	PartitionDetection bool
+	// PartitionCheckInterval is the duration between partition checks of a channel.
+	PartitionCheckInterval time.Duration
+	// PartitionSilenceThreshold is the duration without channel messages after which a peer of a channel
+	// is considered partitioned.
+	PartitionSilenceThreshold time.Duration
+
 	// SkipBlockVerification controls either we skip verifying block message or not.
 	SkipBlockVerification bool
 
@@ -58,11 +81,17 @@ type Config struct {
 
 	// TLSCerts is the TLS certificates of the peer.
 	TLSCerts *common.TLSCertificates
+	// TLSPinner verifies the TLS certificates of remote peers against the pins of their organization,
+	// TLS certificates aren't pinned if nil.
+	TLSPinner api.TLSPinner
 
 	// InternalEndpoint is the endpoint we publish to peers in our organization.
 	InternalEndpoint string
 	// ExternalEndpoint is the peer publishes this endpoint instead of selfEndpoint to foreign organizations.
 	ExternalEndpoint string
+	// ExternalEndpointOverrides are the endpoints the peer publishes instead of ExternalEndpoint
+	// to the peers of some foreign organizations or networks.
+	ExternalEndpointOverrides []EndpointOverride
 	// TimeForMembershipTracker determines time for polloing with membershipTracker.
 	TimeForMembershipTracker time.Duration
 
@@ -81,6 +110,9 @@ type Config struct {
 	RecvBuffSize int
 	// SendBuffSize is the buffer size of sending message.
 	SendBuffSize int
+	// Compression configures the compression of the payloads of the blocks, state transfer
+	// and private data messages sent to peers which support it.
+	Compression comm.CompressionConfig
 
 	// MsgExpirationTimeout indicate leadership message expiration timeout.
 	MsgExpirationTimeout time.Duration
@@ -97,6 +129,9 @@ type Config struct {
 	MsgExpirationFactor int
 	// MaxConnectionAttempts is the max number of attempts to connect to a peer (wait for alive ack)
 	MaxConnectionAttempts int
+	// ReplayWindowDir is the directory where alive message timestamps of remote peers are persisted
+	// to reject replayed alive messages across restarts, replay protection is disabled if empty
+	ReplayWindowDir string
 }
 
 // GlobalConfig builds a Config from the given endpoint, certificate and bootstrap peers.
@@ -128,6 +163,12 @@ func (c *Config) loadConfig(endpoint string, certs *common.TLSCertificates, boot
 	c.PullPeerNum = util.GetIntOrDefault("peer.gossip.pullPeerNum", 3)
 	c.InternalEndpoint = endpoint
 	c.ExternalEndpoint = viper.GetString("peer.gossip.externalEndpoint")
+	if err := viper.UnmarshalKey("peer.gossip.externalEndpointOverrides", &c.ExternalEndpointOverrides); err != nil {
+		return errors.Wrap(err, "could not unmarshal peer.gossip.externalEndpointOverrides")
+	}
+	if _, err := parseEndpointOverrides(c.ExternalEndpointOverrides); err != nil {
+		return err
+	}
 	c.PublishCertPeriod = util.GetDurationOrDefault("peer.gossip.publishCertPeriod", 10*time.Second)
 	c.RequestStateInfoInterval = util.GetDurationOrDefault("peer.gossip.requestStateInfoInterval", 4*time.Second)
 	c.PublishStateInfoInterval = util.GetDurationOrDefault("peer.gossip.publishStateInfoInterval", 4*time.Second)
@@ -141,6 +182,15 @@ func (c *Config) loadConfig(endpoint string, certs *common.TLSCertificates, boot
 	c.ConnTimeout = util.GetDurationOrDefault("peer.gossip.connTimeout", comm.DefConnTimeout)
 	c.RecvBuffSize = util.GetIntOrDefault("peer.gossip.recvBuffSize", comm.DefRecvBuffSize)
 	c.SendBuffSize = util.GetIntOrDefault("peer.gossip.sendBuffSize", comm.DefSendBuffSize)
+	c.Compression.Enabled = viper.GetBool("peer.gossip.compression.enabled")
+	c.Compression.Codecs = viper.GetStringSlice("peer.gossip.compression.codecs")
+	if len(c.Compression.Codecs) == 0 {
+		c.Compression.Codecs = comm.DefCompressionCodecs
+	}
+	if err := comm.ValidateCompressionCodecs(c.Compression.Codecs); err != nil {
+		return errors.WithMessage(err, "invalid peer.gossip.compression.codecs")
+	}
+	c.Compression.Threshold = util.GetIntOrDefault("peer.gossip.compression.threshold", comm.DefCompressionThreshold)
 	c.MsgExpirationTimeout = util.GetDurationOrDefault("peer.gossip.election.leaderAliveThreshold", election.DefLeaderAliveThreshold) * 10
 	c.AliveTimeInterval = util.GetDurationOrDefault("peer.gossip.aliveTimeInterval", discovery.DefAliveTimeInterval)
 	c.AliveExpirationTimeout = util.GetDurationOrDefault("peer.gossip.aliveExpirationTimeout", 5*c.AliveTimeInterval)
@@ -148,6 +198,15 @@ func (c *Config) loadConfig(endpoint string, certs *common.TLSCertificates, boot
 	c.ReconnectInterval = util.GetDurationOrDefault("peer.gossip.reconnectInterval", c.AliveExpirationTimeout)
 	c.MaxConnectionAttempts = util.GetIntOrDefault("peer.gossip.maxConnectionAttempts", discovery.DefMaxConnectionAttempts)
 	c.MsgExpirationFactor = util.GetIntOrDefault("peer.gossip.msgExpirationFactor", discovery.DefMsgExpirationFactor)
+	c.ReplayWindowDir = viper.GetString("peer.gossip.replayWindowDir")
+	c.AdaptiveTuning = viper.GetBool("peer.gossip.adaptive.enabled")
+	c.MinPropagatePeerNum = util.GetIntOrDefault("peer.gossip.adaptive.minPropagatePeerNum", 1)
+	c.MaxPropagatePeerNum = util.GetIntOrDefault("peer.gossip.adaptive.maxPropagatePeerNum", 3*c.PropagatePeerNum)
+	c.MinPullInterval = util.GetDurationOrDefault("peer.gossip.adaptive.minPullInterval", c.DigestWaitTime+c.ResponseWaitTime)
+	c.MaxPullInterval = util.GetDurationOrDefault("peer.gossip.adaptive.maxPullInterval", 4*c.PullInterval)
+	c.PartitionDetection = viper.GetBool("peer.gossip.partitionDetection.enabled")
+	c.PartitionCheckInterval = util.GetDurationOrDefault("peer.gossip.partitionDetection.checkInterval", 30*time.Second)
+	c.PartitionSilenceThreshold = util.GetDurationOrDefault("peer.gossip.partitionDetection.silenceThreshold", 2*time.Minute)
 
 	return nil
 }
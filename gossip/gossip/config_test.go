@@ -39,6 +39,9 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.gossip.pullPeerNum", 7)
 	viper.Set("peer.gossip.endpoint", endpoint)
 	viper.Set("peer.gossip.externalEndpoint", externalEndpoint)
+	viper.Set("peer.gossip.externalEndpointOverrides", []map[string]interface{}{
+		{"endpoint": "10.0.0.5:7052", "mspIDs": []string{"Org2MSP"}, "cidrs": []string{"10.0.0.0/8"}},
+	})
 	viper.Set("peer.gossip.publishCertPeriod", "8s")
 	viper.Set("peer.gossip.requestStateInfoInterval", "9s")
 	viper.Set("peer.gossip.publishStateInfoInterval", "10s")
@@ -57,6 +60,18 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.gossip.reconnectInterval", "22s")
 	viper.Set("peer.gossip.maxConnectionAttempts", "100")
 	viper.Set("peer.gossip.msgExpirationFactor", "10")
+	viper.Set("peer.gossip.replayWindowDir", "/var/hyperledger/production/gossip")
+	viper.Set("peer.gossip.adaptive.enabled", true)
+	viper.Set("peer.gossip.adaptive.minPropagatePeerNum", 23)
+	viper.Set("peer.gossip.adaptive.maxPropagatePeerNum", 24)
+	viper.Set("peer.gossip.adaptive.minPullInterval", "25s")
+	viper.Set("peer.gossip.adaptive.maxPullInterval", "26s")
+	viper.Set("peer.gossip.partitionDetection.enabled", true)
+	viper.Set("peer.gossip.partitionDetection.checkInterval", "28s")
+	viper.Set("peer.gossip.partitionDetection.silenceThreshold", "29s")
+	viper.Set("peer.gossip.compression.enabled", true)
+	viper.Set("peer.gossip.compression.codecs", []string{"gzip"})
+	viper.Set("peer.gossip.compression.threshold", 27)
 
 	coreConfig, err := gossip.GlobalConfig(endpoint, nil, bootstrap...)
 	assert.NoError(t, err)
@@ -67,18 +82,21 @@ func TestGlobalConfig(t *testing.T) {
 	assert.NoError(t, err)
 
 	expectedConfig := &gossip.Config{
-		BindPort:                     int(port),
-		BootstrapPeers:               []string{"bootstrap1", "bootstrap2", "bootstrap3"},
-		ID:                           endpoint,
-		MaxBlockCountToStore:         1,
-		MaxPropagationBurstLatency:   2 * time.Second,
-		MaxPropagationBurstSize:      3,
-		PropagateIterations:          4,
-		PropagatePeerNum:             5,
-		PullInterval:                 6 * time.Second,
-		PullPeerNum:                  7,
-		InternalEndpoint:             endpoint,
-		ExternalEndpoint:             externalEndpoint,
+		BindPort:                   int(port),
+		BootstrapPeers:             []string{"bootstrap1", "bootstrap2", "bootstrap3"},
+		ID:                         endpoint,
+		MaxBlockCountToStore:       1,
+		MaxPropagationBurstLatency: 2 * time.Second,
+		MaxPropagationBurstSize:    3,
+		PropagateIterations:        4,
+		PropagatePeerNum:           5,
+		PullInterval:               6 * time.Second,
+		PullPeerNum:                7,
+		InternalEndpoint:           endpoint,
+		ExternalEndpoint:           externalEndpoint,
+		ExternalEndpointOverrides: []gossip.EndpointOverride{
+			{Endpoint: "10.0.0.5:7052", MSPIDs: []string{"Org2MSP"}, CIDRs: []string{"10.0.0.0/8"}},
+		},
 		PublishCertPeriod:            8 * time.Second,
 		RequestStateInfoInterval:     9 * time.Second,
 		PublishStateInfoInterval:     10 * time.Second,
@@ -92,6 +110,7 @@ func TestGlobalConfig(t *testing.T) {
 		ConnTimeout:                  16 * time.Second,
 		RecvBuffSize:                 17,
 		SendBuffSize:                 18,
+		Compression:                  comm.CompressionConfig{Enabled: true, Codecs: []string{"gzip"}, Threshold: 27},
 		MsgExpirationTimeout:         19 * time.Second * 10, // LeaderAliveThreshold * 10
 		AliveTimeInterval:            20 * time.Second,
 		AliveExpirationTimeout:       21 * time.Second,
@@ -99,6 +118,15 @@ func TestGlobalConfig(t *testing.T) {
 		ReconnectInterval:            22 * time.Second,
 		MaxConnectionAttempts:        100,
 		MsgExpirationFactor:          10,
+		ReplayWindowDir:              "/var/hyperledger/production/gossip",
+		AdaptiveTuning:               true,
+		MinPropagatePeerNum:          23,
+		MaxPropagatePeerNum:          24,
+		MinPullInterval:              25 * time.Second,
+		MaxPullInterval:              26 * time.Second,
+		PartitionDetection:           true,
+		PartitionCheckInterval:       28 * time.Second,
+		PartitionSilenceThreshold:    29 * time.Second,
 	}
 
 	assert.Equal(t, expectedConfig, coreConfig)
@@ -147,6 +175,7 @@ func TestGlobalConfigDefaults(t *testing.T) {
 		ConnTimeout:                  comm.DefConnTimeout,
 		RecvBuffSize:                 comm.DefRecvBuffSize,
 		SendBuffSize:                 comm.DefSendBuffSize,
+		Compression:                  comm.CompressionConfig{Codecs: comm.DefCompressionCodecs, Threshold: comm.DefCompressionThreshold},
 		MsgExpirationTimeout:         election.DefLeaderAliveThreshold * 10,
 		AliveTimeInterval:            discovery.DefAliveTimeInterval,
 		AliveExpirationTimeout:       5 * discovery.DefAliveTimeInterval,
@@ -154,7 +183,33 @@ func TestGlobalConfigDefaults(t *testing.T) {
 		ReconnectInterval:            5 * discovery.DefAliveTimeInterval,
 		MaxConnectionAttempts:        120,
 		MsgExpirationFactor:          20,
+		AdaptiveTuning:               false,
+		MinPropagatePeerNum:          1,
+		MaxPropagatePeerNum:          9,
+		MinPullInterval:              algo.DefDigestWaitTime + algo.DefResponseWaitTime,
+		MaxPullInterval:              16 * time.Second,
+		PartitionDetection:           false,
+		PartitionCheckInterval:       30 * time.Second,
+		PartitionSilenceThreshold:    2 * time.Minute,
 	}
 
 	assert.Equal(t, expectedConfig, coreConfig)
 }
+
+func TestGlobalConfigInvalidCompressionCodecs(t *testing.T) {
+	viper.Reset()
+	viper.Set("peer.gossip.compression.codecs", []string{"zstd", "lz4"})
+
+	_, err := gossip.GlobalConfig("0.0.0.0:7051", nil)
+	assert.EqualError(t, err, "invalid peer.gossip.compression.codecs: unsupported compression codec lz4")
+}
+
+func TestGlobalConfigInvalidEndpointOverrides(t *testing.T) {
+	viper.Reset()
+	viper.Set("peer.gossip.externalEndpointOverrides", []map[string]interface{}{
+		{"endpoint": "10.0.0.5:7052", "cidrs": []string{"10.0.0.0"}},
+	})
+
+	_, err := gossip.GlobalConfig("0.0.0.0:7051", nil)
+	assert.EqualError(t, err, "invalid CIDR of endpoint override 10.0.0.5:7052: invalid CIDR address: 10.0.0.0")
+}
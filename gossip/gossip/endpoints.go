@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gossip
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// EndpointOverride is an endpoint that the peer advertises instead of its
+// external endpoint to the peers of some organizations, or to the peers whose
+// endpoints are in some networks.
+type EndpointOverride struct {
+	// Endpoint is the endpoint advertised to the matching peers.
+	Endpoint string `mapstructure:"endpoint"`
+	// MSPIDs are the MSP IDs of the organizations of the matching peers.
+	MSPIDs []string `mapstructure:"mspIDs"`
+	// CIDRs are the networks of the addresses of the matching peers.
+	CIDRs []string `mapstructure:"cidrs"`
+}
+
+type endpointOverride struct {
+	endpoint string
+	mspIDs   map[string]struct{}
+	networks []*net.IPNet
+}
+
+// endpointOverrides selects the endpoints advertised to remote peers, the
+// first override matching a peer applies.
+type endpointOverrides []*endpointOverride
+
+func parseEndpointOverrides(overrides []EndpointOverride) (endpointOverrides, error) {
+	var parsed endpointOverrides
+	for _, o := range overrides {
+		if _, _, err := net.SplitHostPort(o.Endpoint); err != nil {
+			return nil, errors.Wrapf(err, "invalid endpoint override %s", o.Endpoint)
+		}
+		if len(o.MSPIDs) == 0 && len(o.CIDRs) == 0 {
+			return nil, errors.Errorf("endpoint override %s has neither MSP IDs nor CIDRs", o.Endpoint)
+		}
+		eo := &endpointOverride{
+			endpoint: o.Endpoint,
+			mspIDs:   make(map[string]struct{}),
+		}
+		for _, mspID := range o.MSPIDs {
+			eo.mspIDs[mspID] = struct{}{}
+		}
+		for _, cidr := range o.CIDRs {
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid CIDR of endpoint override %s", o.Endpoint)
+			}
+			eo.networks = append(eo.networks, network)
+		}
+		parsed = append(parsed, eo)
+	}
+	return parsed, nil
+}
+
+// endpointFor returns the endpoint to advertise to a peer of the given
+// organization, with the given endpoint, or an empty string if no override
+// matches the peer. The host of the endpoint is resolved if it is not an IP
+// address and some override has CIDRs.
+func (eos endpointOverrides) endpointFor(mspID, endpoint string) string {
+	var ips []net.IP
+	resolved := false
+	for _, eo := range eos {
+		if _, ok := eo.mspIDs[mspID]; ok && mspID != "" {
+			return eo.endpoint
+		}
+		if len(eo.networks) == 0 {
+			continue
+		}
+		if !resolved {
+			ips = resolveHost(endpoint)
+			resolved = true
+		}
+		for _, network := range eo.networks {
+			for _, ip := range ips {
+				if network.Contains(ip) {
+					return eo.endpoint
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func resolveHost(endpoint string) []net.IP {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil
+	}
+	return ips
+}
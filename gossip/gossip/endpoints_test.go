@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointOverrides(t *testing.T) {
+	overrides, err := parseEndpointOverrides([]EndpointOverride{
+		{Endpoint: "peer0.org1.internal:7051", MSPIDs: []string{"Org2MSP"}},
+		{Endpoint: "10.0.0.5:7051", CIDRs: []string{"10.0.0.0/8", "fd00::/8"}},
+		{Endpoint: "peer0.org1.example.com:7051", MSPIDs: []string{"Org3MSP"}, CIDRs: []string{"192.168.0.0/16"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "peer0.org1.internal:7051", overrides.endpointFor("Org2MSP", "192.168.1.1:7051"))
+	assert.Equal(t, "10.0.0.5:7051", overrides.endpointFor("Org4MSP", "10.1.2.3:7051"))
+	assert.Equal(t, "10.0.0.5:7051", overrides.endpointFor("", "[fd00::1]:7051"))
+	assert.Equal(t, "peer0.org1.example.com:7051", overrides.endpointFor("Org3MSP", "172.16.0.1:7051"))
+	assert.Equal(t, "peer0.org1.example.com:7051", overrides.endpointFor("Org4MSP", "192.168.1.1:7051"))
+	assert.Equal(t, "", overrides.endpointFor("Org4MSP", "172.16.0.1:7051"))
+	assert.Equal(t, "", overrides.endpointFor("Org4MSP", "malformed"))
+}
+
+func TestParseEndpointOverridesErrors(t *testing.T) {
+	_, err := parseEndpointOverrides([]EndpointOverride{{Endpoint: "peer0", MSPIDs: []string{"Org2MSP"}}})
+	assert.EqualError(t, err, "invalid endpoint override peer0: address peer0: missing port in address")
+
+	_, err = parseEndpointOverrides([]EndpointOverride{{Endpoint: "peer0:7051"}})
+	assert.EqualError(t, err, "endpoint override peer0:7051 has neither MSP IDs nor CIDRs")
+
+	_, err = parseEndpointOverrides([]EndpointOverride{{Endpoint: "peer0:7051", CIDRs: []string{"10.0.0.0"}}})
+	assert.EqualError(t, err, "invalid CIDR of endpoint override peer0:7051: invalid CIDR address: 10.0.0.0")
+}
@@ -8,9 +8,12 @@ package gossip
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -105,6 +108,8 @@ func New(conf *Config, s *grpc.Server, sa api.SecurityAdvisor,
 		ConnTimeout:  conf.ConnTimeout,
 		RecvBuffSize: conf.RecvBuffSize,
 		SendBuffSize: conf.SendBuffSize,
+		TLSPinner:    conf.TLSPinner,
+		Compression:  conf.Compression,
 	}
 	g.comm, err = comm.NewCommInstance(s, conf.TLSCerts, g.idMapper, selfIdentity, secureDialOpts, sa,
 		gossipMetrics.CommMetrics, commConfig)
@@ -130,6 +135,15 @@ func New(conf *Config, s *grpc.Server, sa api.SecurityAdvisor,
 		MaxConnectionAttempts:        conf.MaxConnectionAttempts,
 		MsgExpirationFactor:          conf.MsgExpirationFactor,
 		BootstrapPeers:               conf.BootstrapPeers,
+		ReplayWindowDir:              conf.ReplayWindowDir,
+	}
+	if len(conf.ExternalEndpointOverrides) > 0 {
+		overrides, err := parseEndpointOverrides(conf.ExternalEndpointOverrides)
+		if err != nil {
+			lgr.Error("Failed parsing the external endpoint overrides:", err)
+			return nil
+		}
+		discoveryConfig.EndpointPolicy = g.endpointPolicy(overrides)
 	}
 	self := g.selfNetworkMember()
 	logger := util.GetLogger(util.DiscoveryLogger, self.InternalEndpoint)
@@ -578,7 +592,7 @@ func (g *Node) gossipInChan(messages []*emittedGossipMessage, chanRoutingFactory
 		if protoext.IsLeadershipMsg(messagesOfChannel[0].GossipMessage) {
 			peers2Send = filter.SelectPeers(len(membership), membership, chanRoutingFactory(gc))
 		} else {
-			peers2Send = filter.SelectPeers(g.conf.PropagatePeerNum, membership, chanRoutingFactory(gc))
+			peers2Send = filter.SelectPeers(gc.PropagatePeerNum(), membership, chanRoutingFactory(gc))
 		}
 
 		// Send the messages to the remote peers
@@ -723,6 +737,24 @@ func (g *Node) PeersOfChannel(channel common.ChannelID) []discovery.NetworkMembe
 	return gc.GetPeers()
 }
 
+// HealthCheck returns an error if the partition of a channel
+// couldn't be healed, nil otherwise
+func (g *Node) HealthCheck(ctx context.Context) error {
+	var partitioned []string
+	g.chanState.RLock()
+	for channel, gc := range g.chanState.channels {
+		if n := gc.UnreachablePeers(); n > 0 {
+			partitioned = append(partitioned, fmt.Sprintf("%s (%d unreachable peers)", channel, n))
+		}
+	}
+	g.chanState.RUnlock()
+	if len(partitioned) == 0 {
+		return nil
+	}
+	sort.Strings(partitioned)
+	return errors.Errorf("failed healing gossip partition of channels: %s", strings.Join(partitioned, ", "))
+}
+
 // SelfMembershipInfo returns the peer's membership information
 func (g *Node) SelfMembershipInfo() discovery.NetworkMember {
 	return g.disc.Self()
@@ -1280,6 +1312,22 @@ func (g *Node) disclosurePolicy(remotePeer *discovery.NetworkMember) (discovery.
 		}
 }
 
+// endpointPolicy returns the policy that advertises the endpoints of the given
+// overrides to the peers of foreign organizations
+func (g *Node) endpointPolicy(overrides endpointOverrides) discovery.EndpointPolicy {
+	return func(remotePeer *discovery.NetworkMember) string {
+		org := g.getOrgOfPeer(remotePeer.PKIid)
+		if bytes.Equal(g.selfOrg, org) {
+			return ""
+		}
+		endpoint := remotePeer.Endpoint
+		if endpoint == "" {
+			endpoint = remotePeer.InternalEndpoint
+		}
+		return overrides.endpointFor(string(org), endpoint)
+	}
+}
+
 func (g *Node) peersByOriginOrgPolicy(peer discovery.NetworkMember) filter.RoutingFilter {
 	peersOrg := g.getOrgOfPeer(peer.PKIid)
 	if len(peersOrg) == 0 {
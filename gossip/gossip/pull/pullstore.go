@@ -115,6 +115,9 @@ type Mediator interface {
 
 	// HandleMessage handles a message from some remote peer
 	HandleMessage(msg protoext.ReceivedMessage)
+
+	// SetPullInterval changes the duration between pull invocations
+	SetPullInterval(interval time.Duration)
 }
 
 // pullMediatorImpl is an implementation of Mediator
@@ -221,6 +224,11 @@ func (p *pullMediatorImpl) Stop() {
 	p.engine.Stop()
 }
 
+// SetPullInterval changes the duration between pull invocations
+func (p *pullMediatorImpl) SetPullInterval(interval time.Duration) {
+	p.engine.SetSleepTime(interval)
+}
+
 // RegisterMsgHook registers a message hook to a specific type of pull message
 func (p *pullMediatorImpl) RegisterMsgHook(pullMsgType MsgType, hook MessageHook) {
 	p.Lock()
@@ -98,6 +98,7 @@ type CommMetrics struct {
 	SentMessages     metrics.Counter
 	BufferOverflow   metrics.Counter
 	ReceivedMessages metrics.Counter
+	CompressionRatio metrics.Histogram
 }
 
 func newCommMetrics(p metrics.Provider) *CommMetrics {
@@ -105,6 +106,7 @@ func newCommMetrics(p metrics.Provider) *CommMetrics {
 		SentMessages:     p.NewCounter(SentMessagesOpts),
 		BufferOverflow:   p.NewCounter(BufferOverflowOpts),
 		ReceivedMessages: p.NewCounter(ReceivedMessagesOpts),
+		CompressionRatio: p.NewHistogram(CompressionRatioOpts),
 	}
 }
 
@@ -114,7 +116,8 @@ var (
 		Subsystem:    "comm",
 		Name:         "messages_sent",
 		Help:         "Number of messages sent",
-		StatsdFormat: "%{#fqname}",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
 	}
 
 	BufferOverflowOpts = metrics.CounterOpts{
@@ -122,7 +125,8 @@ var (
 		Subsystem:    "comm",
 		Name:         "overflow_count",
 		Help:         "Number of outgoing queue buffer overflows",
-		StatsdFormat: "%{#fqname}",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
 	}
 
 	ReceivedMessagesOpts = metrics.CounterOpts{
@@ -130,18 +134,33 @@ var (
 		Subsystem:    "comm",
 		Name:         "messages_received",
 		Help:         "Number of messages received",
-		StatsdFormat: "%{#fqname}",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	CompressionRatioOpts = metrics.HistogramOpts{
+		Namespace:    "gossip",
+		Subsystem:    "comm",
+		Name:         "compression_ratio",
+		Help:         "Ratio of the original to the compressed size of compressed message payloads",
+		LabelNames:   []string{"channel", "codec"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{codec}",
+		Buckets:      []float64{1, 1.5, 2, 3, 4, 6, 8, 12, 16},
 	}
 )
 
 // MembershipMetrics encapsulates gossip channel membership related metrics
 type MembershipMetrics struct {
-	Total metrics.Gauge
+	Total            metrics.Gauge
+	PartitionedPeers metrics.Gauge
+	HealingFailures  metrics.Counter
 }
 
 func newMembershipMetrics(p metrics.Provider) *MembershipMetrics {
 	return &MembershipMetrics{
-		Total: p.NewGauge(TotalOpts),
+		Total:            p.NewGauge(TotalOpts),
+		PartitionedPeers: p.NewGauge(PartitionedPeersOpts),
+		HealingFailures:  p.NewCounter(HealingFailuresOpts),
 	}
 }
 
@@ -154,6 +173,24 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+
+	PartitionedPeersOpts = metrics.GaugeOpts{
+		Namespace:    "gossip",
+		Subsystem:    "membership",
+		Name:         "partitioned_peers",
+		Help:         "Number of channel peers suspected to be partitioned",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	HealingFailuresOpts = metrics.CounterOpts{
+		Namespace:    "gossip",
+		Subsystem:    "membership",
+		Name:         "partition_healing_failures",
+		Help:         "Number of failed attempts to heal a channel partition",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
 
 // PrivdataMetrics encapsulates gossip private data related metrics
@@ -167,6 +204,8 @@ type PrivdataMetrics struct {
 	ReconciliationDuration         metrics.Histogram
 	PullDuration                   metrics.Histogram
 	RetrieveDuration               metrics.Histogram
+	AntiEntropyDuration            metrics.Histogram
+	AntiEntropyScheduled           metrics.Counter
 }
 
 func newPrivdataMetrics(p metrics.Provider) *PrivdataMetrics {
@@ -180,6 +219,8 @@ func newPrivdataMetrics(p metrics.Provider) *PrivdataMetrics {
 		ReconciliationDuration:         p.NewHistogram(ReconciliationDurationOpts),
 		PullDuration:                   p.NewHistogram(PullDurationOpts),
 		RetrieveDuration:               p.NewHistogram(RetrieveDurationOpts),
+		AntiEntropyDuration:            p.NewHistogram(AntiEntropyDurationOpts),
+		AntiEntropyScheduled:           p.NewCounter(AntiEntropyScheduledOpts),
 	}
 }
 
@@ -264,4 +305,22 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+
+	AntiEntropyDurationOpts = metrics.HistogramOpts{
+		Namespace:    "gossip",
+		Subsystem:    "privdata",
+		Name:         "anti_entropy_duration",
+		Help:         "Time it takes for an anti-entropy round to complete (in seconds)",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	AntiEntropyScheduledOpts = metrics.CounterOpts{
+		Namespace:    "gossip",
+		Subsystem:    "privdata",
+		Name:         "anti_entropy_scheduled",
+		Help:         "Number of private data elements found missing by anti-entropy and scheduled for reconciliation",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
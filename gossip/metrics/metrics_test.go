@@ -42,9 +42,12 @@ func TestMetrics(t *testing.T) {
 	assert.NotNil(t, gossipMetrics.CommMetrics.SentMessages)
 	assert.NotNil(t, gossipMetrics.CommMetrics.ReceivedMessages)
 	assert.NotNil(t, gossipMetrics.CommMetrics.BufferOverflow)
+	assert.NotNil(t, gossipMetrics.CommMetrics.CompressionRatio)
 
 	assert.NotNil(t, gossipMetrics.MembershipMetrics)
 	assert.NotNil(t, gossipMetrics.MembershipMetrics.Total)
+	assert.NotNil(t, gossipMetrics.MembershipMetrics.PartitionedPeers)
+	assert.NotNil(t, gossipMetrics.MembershipMetrics.HealingFailures)
 
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics)
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics.CommitPrivateDataDuration)
@@ -56,4 +59,6 @@ func TestMetrics(t *testing.T) {
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics.ReconciliationDuration)
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics.PullDuration)
 	assert.NotNil(t, gossipMetrics.PrivdataMetrics.RetrieveDuration)
+	assert.NotNil(t, gossipMetrics.PrivdataMetrics.AntiEntropyDuration)
+	assert.NotNil(t, gossipMetrics.PrivdataMetrics.AntiEntropyScheduled)
 }
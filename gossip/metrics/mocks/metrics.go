@@ -24,8 +24,11 @@ type TestMetricProvider struct {
 	FakeSentMessages     *metricsfakes.Counter
 	FakeBufferOverflow   *metricsfakes.Counter
 	FakeReceivedMessages *metricsfakes.Counter
+	FakeCompressionRatio *metricsfakes.Histogram
 
-	FakeTotalGauge *metricsfakes.Gauge
+	FakeTotalGauge            *metricsfakes.Gauge
+	FakePartitionedPeersGauge *metricsfakes.Gauge
+	FakeHealingFailures       *metricsfakes.Counter
 
 	FakeValidationDuration             *metricsfakes.Histogram
 	FakeListMissingPrivateDataDuration *metricsfakes.Histogram
@@ -36,6 +39,8 @@ type TestMetricProvider struct {
 	FakeReconciliationDuration         *metricsfakes.Histogram
 	FakePullDuration                   *metricsfakes.Histogram
 	FakeRetrieveDuration               *metricsfakes.Histogram
+	FakeAntiEntropyDuration            *metricsfakes.Histogram
+	FakeAntiEntropyScheduled           *metricsfakes.Counter
 }
 
 func TestUtilConstructMetricProvider() *TestMetricProvider {
@@ -50,8 +55,11 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 	fakeSentMessages := testUtilConstructCounter()
 	fakeBufferOverflow := testUtilConstructCounter()
 	fakeReceivedMessages := testUtilConstructCounter()
+	fakeCompressionRatio := testUtilConstructHist()
 
 	fakeTotalGauge := testUtilConstructGauge()
+	fakePartitionedPeersGauge := testUtilConstructGauge()
+	fakeHealingFailures := testUtilConstructCounter()
 
 	fakeValidationDuration := testUtilConstructHist()
 	fakeListMissingPrivateDataDuration := testUtilConstructHist()
@@ -62,6 +70,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 	fakeReconciliationDuration := testUtilConstructHist()
 	fakePullDuration := testUtilConstructHist()
 	fakeRetrieveDuration := testUtilConstructHist()
+	fakeAntiEntropyDuration := testUtilConstructHist()
+	fakeAntiEntropyScheduled := testUtilConstructCounter()
 
 	fakeProvider.NewCounterStub = func(opts metrics.CounterOpts) metrics.Counter {
 		switch opts.Name {
@@ -71,6 +81,10 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 			return fakeSentMessages
 		case gmetrics.ReceivedMessagesOpts.Name:
 			return fakeReceivedMessages
+		case gmetrics.AntiEntropyScheduledOpts.Name:
+			return fakeAntiEntropyScheduled
+		case gmetrics.HealingFailuresOpts.Name:
+			return fakeHealingFailures
 		}
 		return nil
 	}
@@ -78,6 +92,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 		switch opts.Name {
 		case gmetrics.CommitDurationOpts.Name:
 			return fakeCommitDurationHist
+		case gmetrics.CompressionRatioOpts.Name:
+			return fakeCompressionRatio
 		case gmetrics.ValidationDurationOpts.Name:
 			return fakeValidationDuration
 		case gmetrics.ListMissingPrivateDataDurationOpts.Name:
@@ -96,6 +112,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 			return fakePullDuration
 		case gmetrics.RetrieveDurationOpts.Name:
 			return fakeRetrieveDuration
+		case gmetrics.AntiEntropyDurationOpts.Name:
+			return fakeAntiEntropyDuration
 		}
 		return nil
 	}
@@ -109,6 +127,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 			return fakeDeclarationGauge
 		case gmetrics.TotalOpts.Name:
 			return fakeTotalGauge
+		case gmetrics.PartitionedPeersOpts.Name:
+			return fakePartitionedPeersGauge
 		}
 		return nil
 	}
@@ -122,7 +142,10 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 		fakeSentMessages,
 		fakeBufferOverflow,
 		fakeReceivedMessages,
+		fakeCompressionRatio,
 		fakeTotalGauge,
+		fakePartitionedPeersGauge,
+		fakeHealingFailures,
 		fakeValidationDuration,
 		fakeListMissingPrivateDataDuration,
 		fakeFetchDuration,
@@ -132,6 +155,8 @@ func TestUtilConstructMetricProvider() *TestMetricProvider {
 		fakeReconciliationDuration,
 		fakePullDuration,
 		fakeRetrieveDuration,
+		fakeAntiEntropyDuration,
+		fakeAntiEntropyScheduled,
 	}
 }
 
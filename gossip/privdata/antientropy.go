@@ -0,0 +1,436 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	protosgossip "github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/gossip/comm"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/metrics"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// maxPendingRangeRequests is the number of digest requests of the other peers that
+// may wait to be answered, the requests received beyond it are dropped
+const maxPendingRangeRequests = 10
+
+//go:generate mockery -dir . -name PvtDataAntiEntropy -case underscore -output mocks/
+
+// PvtDataAntiEntropy is the local interface used to generate mocks for foreign interface.
+type PvtDataAntiEntropy interface {
+	ledger.PvtDataAntiEntropy
+}
+
+// AntiEntropy periodically compares the digests of the private data the peer holds for a range of blocks
+// with the digests of a random peer of the channel, and schedules the reconciliation of the private data
+// the peer lost silently, which the reconciler would never notice otherwise. It also answers the digest
+// requests of the other peers of the channel, even if its own rounds are disabled.
+type AntiEntropy struct {
+	channel        string
+	logger         util.Logger
+	metrics        *metrics.PrivdataMetrics
+	interval       time.Duration
+	batchSize      uint64
+	enabled        bool
+	cursor         uint64
+	selfSignedData protoutil.SignedData
+	pubSub         *util.PubSub
+	msgChan        <-chan protoext.ReceivedMessage
+	requests       chan protoext.ReceivedMessage
+	stopChan       chan struct{}
+	startOnce      sync.Once
+	stopOnce       sync.Once
+	cs             privdata.CollectionStore
+	gossip
+	committer.Committer
+}
+
+// NewAntiEntropy creates a new instance of the private data anti-entropy
+func NewAntiEntropy(channel string, metrics *metrics.PrivdataMetrics, c committer.Committer, cs privdata.CollectionStore,
+	g gossip, selfSignedData protoutil.SignedData, config *PrivdataConfig) *AntiEntropy {
+	ae := &AntiEntropy{
+		channel:        channel,
+		logger:         logger.With("channel", channel),
+		metrics:        metrics,
+		interval:       config.AntiEntropyInterval,
+		batchSize:      uint64(config.AntiEntropyBatchSize),
+		enabled:        config.AntiEntropyEnabled,
+		selfSignedData: selfSignedData,
+		pubSub:         util.NewPubSub(),
+		requests:       make(chan protoext.ReceivedMessage, maxPendingRangeRequests),
+		stopChan:       make(chan struct{}),
+		cs:             cs,
+		gossip:         g,
+		Committer:      c,
+	}
+	_, ae.msgChan = ae.Accept(func(o interface{}) bool {
+		msg := o.(protoext.ReceivedMessage).GetGossipMessage()
+		if !bytes.Equal(msg.Channel, []byte(ae.channel)) {
+			return false
+		}
+		return msg.GetPvtRangeReq() != nil || msg.GetPvtRangeRes() != nil
+	}, true)
+	return ae
+}
+
+// Start starts answering the digest requests of the other peers and, if enabled, the periodic
+// anti-entropy rounds
+func (ae *AntiEntropy) Start() {
+	ae.startOnce.Do(func() {
+		go ae.listen()
+		go ae.serve()
+		if ae.enabled {
+			ae.logger.Debug("Private data anti-entropy is enabled")
+			go ae.run()
+		}
+	})
+}
+
+// Stop stops the anti-entropy
+func (ae *AntiEntropy) Stop() {
+	ae.stopOnce.Do(func() {
+		close(ae.stopChan)
+	})
+}
+
+func (ae *AntiEntropy) listen() {
+	for {
+		select {
+		case <-ae.stopChan:
+			return
+		case msg := <-ae.msgChan:
+			if msg == nil {
+				// comm module stopped, hence this channel
+				// closed
+				return
+			}
+			if msg.GetGossipMessage().GetPvtRangeReq() != nil {
+				select {
+				case ae.requests <- msg:
+				default:
+					ae.logger.Debug("Dropping the private data request of", msg.GetConnectionInfo().Endpoint, ", too many requests are pending")
+				}
+			}
+			if msg.GetGossipMessage().GetPvtRangeRes() != nil {
+				ae.pubSub.Publish(nonceTopic(msg.GetGossipMessage().Nonce), msg)
+			}
+		}
+	}
+}
+
+// serve answers the digest requests of the other peers, off the listen loop so that
+// the responses to the requests of the peer are not held up by the store scans
+func (ae *AntiEntropy) serve() {
+	for {
+		select {
+		case <-ae.stopChan:
+			return
+		case msg := <-ae.requests:
+			ae.handleRequest(msg)
+		}
+	}
+}
+
+func (ae *AntiEntropy) run() {
+	for {
+		select {
+		case <-ae.stopChan:
+			return
+		case <-time.After(ae.interval):
+			if err := ae.round(); err != nil {
+				ae.logger.Warningf("Private data anti-entropy round failed: %s", err)
+			}
+		}
+	}
+}
+
+func (ae *AntiEntropy) handleRequest(message protoext.ReceivedMessage) {
+	req := message.GetGossipMessage().GetPvtRangeReq()
+	connInfo := message.GetConnectionInfo()
+	signedData := protoutil.SignedData{
+		Identity:  connInfo.Identity,
+		Data:      connInfo.Auth.SignedData,
+		Signature: connInfo.Auth.Signature,
+	}
+
+	if req.EndBlock < req.StartBlock {
+		ae.logger.Debugf("Peer %s requested the private data of an invalid range of blocks [%d - %d]", connInfo.Endpoint, req.StartBlock, req.EndBlock)
+		return
+	}
+	// the range is capped so that a peer cannot make the store scan the whole ledger,
+	// the response carries the range the digests are computed for
+	res := &protosgossip.PvtDataRangeResponse{
+		StartBlock: req.StartBlock,
+		EndBlock:   req.EndBlock,
+	}
+	if req.EndBlock-req.StartBlock >= ae.batchSize {
+		res.EndBlock = req.StartBlock + ae.batchSize - 1
+	}
+	var err error
+	if req.Namespace == "" && req.Collection == "" {
+		res.Digests, err = ae.localDigests(res.StartBlock, res.EndBlock, signedData)
+	} else if ae.isEligible(req.Namespace, req.Collection, signedData) {
+		res.Entries, err = ae.localEntries(req.Namespace, req.Collection, res.StartBlock, res.EndBlock)
+	} else {
+		ae.logger.Debug("Peer", connInfo.Endpoint, "isn't eligible for collection", req.Collection, "of", req.Namespace)
+	}
+	if err != nil {
+		ae.logger.Warningf("Failed computing private data digests of blocks [%d - %d] requested by %s: %s",
+			res.StartBlock, res.EndBlock, connInfo.Endpoint, err)
+		return
+	}
+
+	message.Respond(&protosgossip.GossipMessage{
+		Channel: []byte(ae.channel),
+		Tag:     protosgossip.GossipMessage_CHAN_ONLY,
+		Nonce:   message.GetGossipMessage().Nonce,
+		Content: &protosgossip.GossipMessage_PvtRangeRes{
+			PvtRangeRes: res,
+		},
+	})
+}
+
+// localDigests returns the digests of the collections the peer holds private data of in the given
+// blocks, and which the peer with the given signed data is eligible for
+func (ae *AntiEntropy) localDigests(startBlk, endBlk uint64, signedData protoutil.SignedData) ([]*protosgossip.PvtDataRangeDigest, error) {
+	antiEntropy, err := ae.GetPvtDataAntiEntropy()
+	if err != nil {
+		return nil, err
+	}
+	digests, err := antiEntropy.PvtDataDigests(startBlk, endBlk)
+	if err != nil {
+		return nil, err
+	}
+	var res []*protosgossip.PvtDataRangeDigest
+	for _, d := range digests {
+		if !ae.isEligible(d.Namespace, d.Collection, signedData) {
+			continue
+		}
+		res = append(res, &protosgossip.PvtDataRangeDigest{
+			Namespace:  d.Namespace,
+			Collection: d.Collection,
+			Digest:     d.Digest,
+		})
+	}
+	return res, nil
+}
+
+func (ae *AntiEntropy) localEntries(ns, coll string, startBlk, endBlk uint64) ([]*protosgossip.PvtDataDigest, error) {
+	antiEntropy, err := ae.GetPvtDataAntiEntropy()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := antiEntropy.PvtDataEntries(ns, coll, startBlk, endBlk)
+	if err != nil {
+		return nil, err
+	}
+	var res []*protosgossip.PvtDataDigest
+	for blkNum, txNums := range entries {
+		for _, txNum := range txNums {
+			res = append(res, &protosgossip.PvtDataDigest{
+				Namespace:  ns,
+				Collection: coll,
+				BlockSeq:   blkNum,
+				SeqInBlock: txNum,
+			})
+		}
+	}
+	return res, nil
+}
+
+func (ae *AntiEntropy) isEligible(ns, coll string, signedData protoutil.SignedData) bool {
+	ap, err := ae.cs.RetrieveCollectionAccessPolicy(privdata.CollectionCriteria{
+		Channel:    ae.channel,
+		Collection: coll,
+		Namespace:  ns,
+	})
+	if err != nil {
+		return false
+	}
+	filter := ap.AccessFilter()
+	return filter != nil && filter(signedData)
+}
+
+// round compares the private data of the next range of blocks with a random peer of the channel,
+// and schedules the reconciliation of the private data the peer is missing
+func (ae *AntiEntropy) round() error {
+	defer ae.reportDuration(time.Now())
+
+	height, err := ae.LedgerHeight()
+	if err != nil {
+		return errors.WithMessage(err, "failed getting the ledger height")
+	}
+	startBlk, endBlk, ok := ae.nextRange(height)
+	if !ok {
+		return nil
+	}
+
+	peers := ae.PeersOfChannel(common.ChannelID(ae.channel))
+	if len(peers) == 0 {
+		ae.logger.Debug("No peer to compare the private data with")
+		return nil
+	}
+	peer := peers[util.RandomInt(len(peers))]
+
+	res, err := ae.request(peer, &protosgossip.PvtDataRangeRequest{StartBlock: startBlk, EndBlock: endBlk})
+	if err != nil {
+		return err
+	}
+	if res.StartBlock != startBlk || res.EndBlock < startBlk || res.EndBlock > endBlk {
+		return errors.Errorf("%s answered the private data request of blocks [%d - %d] for blocks [%d - %d]",
+			peer.PreferredEndpoint(), startBlk, endBlk, res.StartBlock, res.EndBlock)
+	}
+	if res.EndBlock < endBlk {
+		// the peer caps the range to a smaller batch size, the next round resumes after it
+		endBlk = res.EndBlock
+		ae.cursor = endBlk + 1
+	}
+	localDigests, err := ae.localDigests(startBlk, endBlk, ae.selfSignedData)
+	if err != nil {
+		return err
+	}
+
+	lost := make(ledger.MissingPvtDataInfo)
+	for _, remote := range divergentDigests(localDigests, res.Digests) {
+		if !ae.isEligible(remote.Namespace, remote.Collection, ae.selfSignedData) {
+			continue
+		}
+		if err := ae.addLostEntries(lost, peer, remote.Namespace, remote.Collection, startBlk, endBlk); err != nil {
+			return err
+		}
+	}
+	if len(lost) == 0 {
+		ae.logger.Debugf("Private data of blocks [%d - %d] is in sync with %s", startBlk, endBlk, peer.PreferredEndpoint())
+		return nil
+	}
+
+	antiEntropy, err := ae.GetPvtDataAntiEntropy()
+	if err != nil {
+		return err
+	}
+	scheduled, err := antiEntropy.ScheduleReconciliation(lost)
+	if err != nil {
+		return errors.WithMessage(err, "failed scheduling the reconciliation of the lost private data")
+	}
+	if scheduled > 0 {
+		ae.logger.Warningf("Found %d private data elements of blocks [%d - %d] missing compared to %s, scheduled their reconciliation",
+			scheduled, startBlk, endBlk, peer.PreferredEndpoint())
+		ae.metrics.AntiEntropyScheduled.With("channel", ae.channel).Add(float64(scheduled))
+	}
+	return nil
+}
+
+// nextRange returns the next range of blocks to compare, going through the blocks
+// of the ledger in a round-robin fashion
+func (ae *AntiEntropy) nextRange(height uint64) (uint64, uint64, bool) {
+	// the genesis block holds no private data
+	if height <= 1 {
+		return 0, 0, false
+	}
+	if ae.cursor == 0 || ae.cursor >= height {
+		ae.cursor = 1
+	}
+	startBlk := ae.cursor
+	endBlk := startBlk + ae.batchSize - 1
+	if endBlk >= height {
+		endBlk = height - 1
+	}
+	ae.cursor = endBlk + 1
+	return startBlk, endBlk, true
+}
+
+func (ae *AntiEntropy) addLostEntries(lost ledger.MissingPvtDataInfo, peer discovery.NetworkMember, ns, coll string, startBlk, endBlk uint64) error {
+	res, err := ae.request(peer, &protosgossip.PvtDataRangeRequest{
+		StartBlock: startBlk,
+		EndBlock:   endBlk,
+		Namespace:  ns,
+		Collection: coll,
+	})
+	if err != nil {
+		return err
+	}
+	antiEntropy, err := ae.GetPvtDataAntiEntropy()
+	if err != nil {
+		return err
+	}
+	localEntries, err := antiEntropy.PvtDataEntries(ns, coll, startBlk, endBlk)
+	if err != nil {
+		return err
+	}
+	held := map[[2]uint64]struct{}{}
+	for blkNum, txNums := range localEntries {
+		for _, txNum := range txNums {
+			held[[2]uint64{blkNum, txNum}] = struct{}{}
+		}
+	}
+	for _, entry := range res.Entries {
+		if entry.Namespace != ns || entry.Collection != coll || entry.BlockSeq < startBlk || entry.BlockSeq > endBlk {
+			continue
+		}
+		if _, ok := held[[2]uint64{entry.BlockSeq, entry.SeqInBlock}]; ok {
+			continue
+		}
+		lost.Add(entry.BlockSeq, entry.SeqInBlock, ns, coll)
+	}
+	return nil
+}
+
+func (ae *AntiEntropy) request(peer discovery.NetworkMember, req *protosgossip.PvtDataRangeRequest) (*protosgossip.PvtDataRangeResponse, error) {
+	msg := &protosgossip.GossipMessage{
+		Tag:     protosgossip.GossipMessage_CHAN_ONLY,
+		Channel: []byte(ae.channel),
+		Nonce:   util.RandomUInt64(),
+		Content: &protosgossip.GossipMessage_PvtRangeReq{
+			PvtRangeReq: req,
+		},
+	}
+	sub := ae.pubSub.Subscribe(nonceTopic(msg.Nonce), responseWaitTime)
+	ae.Send(msg, &comm.RemotePeer{Endpoint: peer.PreferredEndpoint(), PKIID: peer.PKIid})
+
+	item, err := sub.Listen()
+	if err != nil {
+		return nil, errors.Errorf("no response from %s to the private data request of blocks [%d - %d]",
+			peer.PreferredEndpoint(), req.StartBlock, req.EndBlock)
+	}
+	return item.(protoext.ReceivedMessage).GetGossipMessage().GetPvtRangeRes(), nil
+}
+
+func (ae *AntiEntropy) reportDuration(startTime time.Time) {
+	ae.metrics.AntiEntropyDuration.With("channel", ae.channel).Observe(time.Since(startTime).Seconds())
+}
+
+// divergentDigests returns the remote digests that differ from the local ones
+func divergentDigests(local, remote []*protosgossip.PvtDataRangeDigest) []*protosgossip.PvtDataRangeDigest {
+	localDigests := map[[2]string][]byte{}
+	for _, d := range local {
+		localDigests[[2]string{d.Namespace, d.Collection}] = d.Digest
+	}
+	var res []*protosgossip.PvtDataRangeDigest
+	for _, d := range remote {
+		if bytes.Equal(localDigests[[2]string{d.Namespace, d.Collection}], d.Digest) {
+			continue
+		}
+		res = append(res, d)
+	}
+	return res
+}
+
+func nonceTopic(nonce uint64) string {
+	return fmt.Sprintf("%d", nonce)
+}
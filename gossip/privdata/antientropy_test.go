@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/gossip/comm"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/metrics"
+	gmetricsmocks "github.com/hyperledger/fabric/gossip/metrics/mocks"
+	"github.com/hyperledger/fabric/gossip/privdata/mocks"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (gn *gossipNetwork) newAntiEntropy(id string, m *metrics.PrivdataMetrics, cs privdata.CollectionStore,
+	antiEntropy *mocks.PvtDataAntiEntropy, height uint64, knownMembers ...discovery.NetworkMember) *AntiEntropy {
+	g := newMockGossip(&comm.RemotePeer{PKIID: common.PKIidType(id), Endpoint: id})
+	g.network = gn
+	g.On("PeersOfChannel", mock.Anything).Return(knownMembers)
+	gn.peers = append(gn.peers, g)
+
+	committer := &mocks.Committer{}
+	committer.On("GetPvtDataAntiEntropy").Return(antiEntropy, nil)
+	committer.On("LedgerHeight").Return(height, nil)
+
+	ae := NewAntiEntropy("A", m, committer, cs, g, protoutil.SignedData{Identity: []byte(id)}, &PrivdataConfig{
+		AntiEntropyInterval:  time.Hour,
+		AntiEntropyBatchSize: 10,
+	})
+	ae.Start()
+	return ae
+}
+
+func TestAntiEntropyRound(t *testing.T) {
+	// Scenario: p1 lost the private data of col1 at block 2, tx 1, which p2 holds.
+	// p2 also holds private data of col2, which p1 isn't eligible for.
+	// The anti-entropy round of p1 should schedule the reconciliation of the lost private data only.
+	gn := &gossipNetwork{}
+	cs := newCollectionStore().withPolicy("col1", 100).thatMapsTo("p1", "p2").
+		withPolicy("col2", 100).thatMapsTo("p2")
+	testMetricProvider := gmetricsmocks.TestUtilConstructMetricProvider()
+	m := metrics.NewGossipMetrics(testMetricProvider.FakeProvider).PrivdataMetrics
+
+	p1Store := &mocks.PvtDataAntiEntropy{}
+	p1Store.On("PvtDataDigests", uint64(1), uint64(5)).Return([]*ledger.CollectionPvtDataDigest{
+		{Namespace: "ns", Collection: "col1", Digest: []byte("d1")},
+	}, nil)
+	p1Store.On("PvtDataEntries", "ns", "col1", uint64(1), uint64(5)).Return(map[uint64][]uint64{1: {0}}, nil)
+	expectedLost := make(ledger.MissingPvtDataInfo)
+	expectedLost.Add(2, 1, "ns", "col1")
+	p1Store.On("ScheduleReconciliation", expectedLost).Return(1, nil)
+
+	p2Store := &mocks.PvtDataAntiEntropy{}
+	p2Store.On("PvtDataDigests", uint64(1), uint64(5)).Return([]*ledger.CollectionPvtDataDigest{
+		{Namespace: "ns", Collection: "col1", Digest: []byte("d2")},
+		{Namespace: "ns", Collection: "col2", Digest: []byte("d3")},
+	}, nil)
+	p2Store.On("PvtDataEntries", "ns", "col1", uint64(1), uint64(5)).Return(map[uint64][]uint64{1: {0}, 2: {1}}, nil)
+
+	p1 := gn.newAntiEntropy("p1", m, cs, p1Store, 6, membership(peerData{"p2", 6})...)
+	defer p1.Stop()
+	p2 := gn.newAntiEntropy("p2", m, cs, p2Store, 6, membership(peerData{"p1", 6})...)
+	defer p2.Stop()
+
+	require.NoError(t, p1.round())
+	p1Store.AssertCalled(t, "ScheduleReconciliation", expectedLost)
+	p2Store.AssertNotCalled(t, "PvtDataEntries", "ns", "col2", mock.Anything, mock.Anything)
+	assert.Equal(t, 1, testMetricProvider.FakeAntiEntropyScheduled.AddCallCount())
+	assert.Equal(t, float64(1), testMetricProvider.FakeAntiEntropyScheduled.AddArgsForCall(0))
+	assert.Equal(t, 1, testMetricProvider.FakeAntiEntropyDuration.ObserveCallCount())
+}
+
+func TestAntiEntropyInSync(t *testing.T) {
+	// Scenario: p1 and p2 hold the same private data, hence no entries are requested
+	// and no reconciliation is scheduled
+	gn := &gossipNetwork{}
+	cs := newCollectionStore().withPolicy("col1", 100).thatMapsTo("p1", "p2")
+	m := metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics
+
+	digests := []*ledger.CollectionPvtDataDigest{{Namespace: "ns", Collection: "col1", Digest: []byte("d1")}}
+	p1Store := &mocks.PvtDataAntiEntropy{}
+	p1Store.On("PvtDataDigests", uint64(1), uint64(3)).Return(digests, nil)
+	p2Store := &mocks.PvtDataAntiEntropy{}
+	p2Store.On("PvtDataDigests", uint64(1), uint64(3)).Return(digests, nil)
+
+	p1 := gn.newAntiEntropy("p1", m, cs, p1Store, 4, membership(peerData{"p2", 4})...)
+	defer p1.Stop()
+	p2 := gn.newAntiEntropy("p2", m, cs, p2Store, 4, membership(peerData{"p1", 4})...)
+	defer p2.Stop()
+
+	require.NoError(t, p1.round())
+	p1Store.AssertNotCalled(t, "ScheduleReconciliation", mock.Anything)
+	p2Store.AssertNotCalled(t, "PvtDataEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAntiEntropyCappedRange(t *testing.T) {
+	// Scenario: p2 compares smaller batches of blocks than p1, hence it answers the digests
+	// of the first blocks of the range requested by p1 only, which p1 compares and resumes after
+	gn := &gossipNetwork{}
+	cs := newCollectionStore().withPolicy("col1", 100).thatMapsTo("p1", "p2")
+	m := metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics
+
+	digests := []*ledger.CollectionPvtDataDigest{{Namespace: "ns", Collection: "col1", Digest: []byte("d1")}}
+	p1Store := &mocks.PvtDataAntiEntropy{}
+	p1Store.On("PvtDataDigests", uint64(1), uint64(2)).Return(digests, nil)
+	p2Store := &mocks.PvtDataAntiEntropy{}
+	p2Store.On("PvtDataDigests", uint64(1), uint64(2)).Return(digests, nil)
+
+	p1 := gn.newAntiEntropy("p1", m, cs, p1Store, 6, membership(peerData{"p2", 6})...)
+	defer p1.Stop()
+	p2 := gn.newAntiEntropy("p2", m, cs, p2Store, 6, membership(peerData{"p1", 6})...)
+	defer p2.Stop()
+	p2.batchSize = 2
+
+	require.NoError(t, p1.round())
+	p1Store.AssertNotCalled(t, "ScheduleReconciliation", mock.Anything)
+	p2Store.AssertNotCalled(t, "PvtDataDigests", uint64(1), uint64(5))
+	assert.Equal(t, uint64(3), p1.cursor)
+}
+
+func TestAntiEntropyNoResponse(t *testing.T) {
+	// Scenario: p1 knows p2, which doesn't respond
+	gn := &gossipNetwork{}
+	cs := newCollectionStore().withPolicy("col1", 100).thatMapsTo("p1")
+	m := metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics
+
+	p1Store := &mocks.PvtDataAntiEntropy{}
+	p1Store.On("PvtDataDigests", uint64(1), uint64(1)).Return(nil, nil)
+	p1 := gn.newAntiEntropy("p1", m, cs, p1Store, 2, membership(peerData{"p2", 2})...)
+	defer p1.Stop()
+
+	err := p1.round()
+	require.EqualError(t, err, "no response from p2 to the private data request of blocks [1 - 1]")
+}
+
+func TestAntiEntropyNextRange(t *testing.T) {
+	ae := &AntiEntropy{batchSize: 10}
+
+	_, _, ok := ae.nextRange(1)
+	assert.False(t, ok)
+
+	for _, expected := range [][2]uint64{{1, 10}, {11, 20}, {21, 24}, {1, 10}} {
+		startBlk, endBlk, ok := ae.nextRange(25)
+		assert.True(t, ok)
+		assert.Equal(t, expected, [2]uint64{startBlk, endBlk})
+	}
+
+	// the ledger height is lower than the cursor, e.g. after the ledger was rebuilt
+	ae.cursor = 30
+	startBlk, endBlk, ok := ae.nextRange(25)
+	assert.True(t, ok)
+	assert.Equal(t, [2]uint64{1, 10}, [2]uint64{startBlk, endBlk})
+}
@@ -17,6 +17,8 @@ const (
 	reconcileSleepIntervalDefault         = time.Minute
 	reconcileBatchSizeDefault             = 10
 	implicitCollectionMaxPeerCountDefault = 1
+	antiEntropyIntervalDefault            = 10 * time.Minute
+	antiEntropyBatchSizeDefault           = 100
 )
 
 // PrivdataConfig is the struct that defines the Gossip Privdata configurations.
@@ -30,6 +32,12 @@ type PrivdataConfig struct {
 	ReconciliationEnabled bool
 	// ImplicitCollectionDisseminationPolicy specifies the dissemination  policy for the peer's own implicit collection.
 	ImplicitCollDisseminationPolicy ImplicitCollectionDisseminationPolicy
+	// AntiEntropyEnabled is a flag that indicates whether private data anti-entropy is enabled or not.
+	AntiEntropyEnabled bool
+	// AntiEntropyInterval determines the time between two anti-entropy rounds.
+	AntiEntropyInterval time.Duration
+	// AntiEntropyBatchSize determines the number of blocks whose private data is compared in a single anti-entropy round.
+	AntiEntropyBatchSize int
 }
 
 // ImplicitCollectionDisseminationPolicy specifies the dissemination  policy for the peer's own implicit collection.
@@ -85,4 +93,16 @@ func (c *PrivdataConfig) loadPrivDataConfig() {
 
 	c.ImplicitCollDisseminationPolicy.RequiredPeerCount = requiredPeerCount
 	c.ImplicitCollDisseminationPolicy.MaxPeerCount = maxPeerCount
+
+	c.AntiEntropyEnabled = viper.GetBool("peer.gossip.pvtData.antiEntropyEnabled")
+
+	c.AntiEntropyInterval = viper.GetDuration("peer.gossip.pvtData.antiEntropyInterval")
+	if c.AntiEntropyInterval == 0 {
+		c.AntiEntropyInterval = antiEntropyIntervalDefault
+	}
+
+	c.AntiEntropyBatchSize = viper.GetInt("peer.gossip.pvtData.antiEntropyBatchSize")
+	if c.AntiEntropyBatchSize <= 0 {
+		c.AntiEntropyBatchSize = antiEntropyBatchSizeDefault
+	}
 }
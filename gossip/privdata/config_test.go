@@ -23,6 +23,9 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.gossip.pvtData.reconciliationEnabled", true)
 	viper.Set("peer.gossip.pvtData.implicitCollectionDisseminationPolicy.requiredPeerCount", 2)
 	viper.Set("peer.gossip.pvtData.implicitCollectionDisseminationPolicy.maxPeerCount", 3)
+	viper.Set("peer.gossip.pvtData.antiEntropyEnabled", true)
+	viper.Set("peer.gossip.pvtData.antiEntropyInterval", "5m")
+	viper.Set("peer.gossip.pvtData.antiEntropyBatchSize", 50)
 
 	coreConfig := privdata.GlobalConfig()
 
@@ -34,6 +37,9 @@ func TestGlobalConfig(t *testing.T) {
 			RequiredPeerCount: 2,
 			MaxPeerCount:      3,
 		},
+		AntiEntropyEnabled:   true,
+		AntiEntropyInterval:  5 * time.Minute,
+		AntiEntropyBatchSize: 50,
 	}
 
 	assert.Equal(t, coreConfig, expectedConfig)
@@ -52,6 +58,9 @@ func TestGlobalConfigDefaults(t *testing.T) {
 			RequiredPeerCount: 0,
 			MaxPeerCount:      1,
 		},
+		AntiEntropyEnabled:   false,
+		AntiEntropyInterval:  10 * time.Minute,
+		AntiEntropyBatchSize: 100,
 	}
 
 	assert.Equal(t, coreConfig, expectedConfig)
@@ -136,6 +136,29 @@ func (_m *Committer) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, e
 	return r0, r1
 }
 
+// GetPvtDataAntiEntropy provides a mock function with given fields:
+func (_m *Committer) GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error) {
+	ret := _m.Called()
+
+	var r0 ledger.PvtDataAntiEntropy
+	if rf, ok := ret.Get(0).(func() ledger.PvtDataAntiEntropy); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ledger.PvtDataAntiEntropy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPvtDataAndBlockByNum provides a mock function with given fields: seqNum
 func (_m *Committer) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.BlockAndPvtData, error) {
 	ret := _m.Called(seqNum)
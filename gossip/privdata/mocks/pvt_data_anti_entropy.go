@@ -0,0 +1,78 @@
+// Code generated by mockery v1.0.0. DO NOT EDIT.
+
+package mocks
+
+import ledger "github.com/hyperledger/fabric/core/ledger"
+import mock "github.com/stretchr/testify/mock"
+
+// PvtDataAntiEntropy is an autogenerated mock type for the PvtDataAntiEntropy type
+type PvtDataAntiEntropy struct {
+	mock.Mock
+}
+
+// PvtDataDigests provides a mock function with given fields: startBlk, endBlk
+func (_m *PvtDataAntiEntropy) PvtDataDigests(startBlk uint64, endBlk uint64) ([]*ledger.CollectionPvtDataDigest, error) {
+	ret := _m.Called(startBlk, endBlk)
+
+	var r0 []*ledger.CollectionPvtDataDigest
+	if rf, ok := ret.Get(0).(func(uint64, uint64) []*ledger.CollectionPvtDataDigest); ok {
+		r0 = rf(startBlk, endBlk)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ledger.CollectionPvtDataDigest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, uint64) error); ok {
+		r1 = rf(startBlk, endBlk)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PvtDataEntries provides a mock function with given fields: ns, coll, startBlk, endBlk
+func (_m *PvtDataAntiEntropy) PvtDataEntries(ns string, coll string, startBlk uint64, endBlk uint64) (map[uint64][]uint64, error) {
+	ret := _m.Called(ns, coll, startBlk, endBlk)
+
+	var r0 map[uint64][]uint64
+	if rf, ok := ret.Get(0).(func(string, string, uint64, uint64) map[uint64][]uint64); ok {
+		r0 = rf(ns, coll, startBlk, endBlk)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint64][]uint64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, uint64, uint64) error); ok {
+		r1 = rf(ns, coll, startBlk, endBlk)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScheduleReconciliation provides a mock function with given fields: lost
+func (_m *PvtDataAntiEntropy) ScheduleReconciliation(lost ledger.MissingPvtDataInfo) (int, error) {
+	ret := _m.Called(lost)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(ledger.MissingPvtDataInfo) int); ok {
+		r0 = rf(lost)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ledger.MissingPvtDataInfo) error); ok {
+		r1 = rf(lost)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
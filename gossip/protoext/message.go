@@ -96,7 +96,8 @@ func IsDataReq(m *gossip.GossipMessage) bool {
 
 // IsPrivateDataMsg returns whether this message is related to private data
 func IsPrivateDataMsg(m *gossip.GossipMessage) bool {
-	return m.GetPrivateReq() != nil || m.GetPrivateRes() != nil || m.GetPrivateData() != nil
+	return m.GetPrivateReq() != nil || m.GetPrivateRes() != nil || m.GetPrivateData() != nil ||
+		m.GetPvtRangeReq() != nil || m.GetPvtRangeRes() != nil
 }
 
 // IsAck returns whether this GossipMessage is an acknowledgement
@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package service
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net"
 	"sync"
 
 	gproto "github.com/hyperledger/fabric-protos-go/gossip"
@@ -105,6 +108,10 @@ type gossipSvc interface {
 	// IsInMyOrg checks whether a network member is in this peer's org
 	IsInMyOrg(member discovery.NetworkMember) bool
 
+	// HealthCheck returns an error if the partition of a channel
+	// couldn't be healed
+	HealthCheck(ctx context.Context) error
+
 	// Stop stops the gossip component
 	Stop()
 }
@@ -137,6 +144,9 @@ type deliveryFactoryImpl struct {
 
 // Returns an instance of delivery client
 func (df *deliveryFactoryImpl) Service(g GossipServiceAdapter, ordererSource *orderers.ConnectionSource, mcs api.MessageCryptoService, isStaticLeader bool) deliverservice.DeliverService {
+	// The peers of the organization can only be used as a block source if
+	// the gossip adapter is able to report their ledger heights
+	orgPeers, _ := g.(deliverservice.OrgPeersInfo)
 	return deliverservice.NewDeliverService(&deliverservice.Config{
 		IsStaticLeader:       isStaticLeader,
 		CryptoSvc:            mcs,
@@ -145,6 +155,7 @@ func (df *deliveryFactoryImpl) Service(g GossipServiceAdapter, ordererSource *or
 		DeliverGRPCClient:    df.deliverGRPCClient,
 		DeliverServiceConfig: df.deliverServiceConfig,
 		OrdererSource:        ordererSource,
+		OrgPeers:             orgPeers,
 	})
 }
 
@@ -153,11 +164,13 @@ type privateHandler struct {
 	coordinator gossipprivdata.Coordinator
 	distributor gossipprivdata.PvtDataDistributor
 	reconciler  gossipprivdata.PvtDataReconciler
+	antiEntropy *gossipprivdata.AntiEntropy
 }
 
 func (p privateHandler) close() {
 	p.coordinator.Close()
 	p.reconciler.Stop()
+	p.antiEntropy.Stop()
 }
 
 // GossipService handles the interaction between gossip service and peer
@@ -372,8 +385,11 @@ func (g *GossipService) InitializeChannel(channelID string, ordererSource *order
 		coordinator: coordinator,
 		distributor: gossipprivdata.NewDistributor(channelID, g, collectionAccessFactory, g.metrics.PrivdataMetrics, pushAckTimeout),
 		reconciler:  reconciler,
+		antiEntropy: gossipprivdata.NewAntiEntropy(channelID, g.metrics.PrivdataMetrics, support.Committer,
+			support.CollectionStore, g.gossipSvc, selfSignedData, g.privdataConfig),
 	}
 	g.privateHandlers[channelID].reconciler.Start()
+	g.privateHandlers[channelID].antiEntropy.Start()
 
 	blockingMode := !g.serviceConfig.NonBlockingCommitMode
 	stateConfig := state.GlobalConfig()
@@ -453,7 +469,7 @@ func (g *GossipService) updateAnchors(config Config) {
 				Port: int(ap.Port),
 			}
 			jcm.members2AnchorPeers[appOrg.MSPID()] = append(jcm.members2AnchorPeers[appOrg.MSPID()], anchorPeer)
-			anchorPeerEndpoints[fmt.Sprintf("%s:%d", ap.Host, ap.Port)] = struct{}{}
+			anchorPeerEndpoints[net.JoinHostPort(ap.Host, fmt.Sprintf("%d", ap.Port))] = struct{}{}
 		}
 	}
 	g.anchorPeerTracker.update(config.ChannelID(), anchorPeerEndpoints)
@@ -470,6 +486,36 @@ func (g *GossipService) AddPayload(channelID string, payload *gproto.Payload) er
 	return g.chains[channelID].AddPayload(payload)
 }
 
+// CommitBacklog returns the number of blocks received but not yet committed
+// across all channels
+func (g *GossipService) CommitBacklog() int {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	backlog := 0
+	for _, chain := range g.chains {
+		backlog += chain.BacklogSize()
+	}
+	return backlog
+}
+
+// OrgLedgerHeights returns the ledger heights advertised by the alive peers
+// of this peer's organization in the given channel
+func (g *GossipService) OrgLedgerHeights(channelID string) []uint64 {
+	myOrg := g.secAdv.OrgByPeerIdentity(api.PeerIdentityType(g.peerIdentity))
+	identities := g.IdentityInfo().ByID()
+
+	var heights []uint64
+	for _, member := range g.PeersOfChannel(gossipcommon.ChannelID(channelID)) {
+		identity, exists := identities[string(member.PKIid)]
+		if !exists || !bytes.Equal(identity.Organization, myOrg) || member.Properties == nil {
+			continue
+		}
+		heights = append(heights, member.Properties.LedgerHeight)
+	}
+	return heights
+}
+
 // Stop stops the gossip component
 func (g *GossipService) Stop() {
 	g.lock.Lock()
@@ -412,6 +412,10 @@ func (li *mockLedgerInfo) GetMissingPvtDataTracker() (ledger.MissingPvtDataTrack
 	panic("implement me")
 }
 
+func (li *mockLedgerInfo) GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error) {
+	panic("implement me")
+}
+
 func (li *mockLedgerInfo) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
 	panic("implement me")
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package service
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -66,8 +67,8 @@ func (*gossipMock) Peers() []discovery.NetworkMember {
 	panic("implement me")
 }
 
-func (*gossipMock) PeersOfChannel(common.ChannelID) []discovery.NetworkMember {
-	panic("implement me")
+func (g *gossipMock) PeersOfChannel(channelID common.ChannelID) []discovery.NetworkMember {
+	return g.Called(channelID).Get(0).([]discovery.NetworkMember)
 }
 
 func (*gossipMock) UpdateMetadata(metadata []byte) {
@@ -103,13 +104,17 @@ func (g *gossipMock) LeaveChan(channelID common.ChannelID) {
 }
 
 func (g *gossipMock) IdentityInfo() api.PeerIdentitySet {
-	panic("implement me")
+	return g.Called().Get(0).(api.PeerIdentitySet)
 }
 
 func (*gossipMock) IsInMyOrg(member discovery.NetworkMember) bool {
 	panic("implement me")
 }
 
+func (*gossipMock) HealthCheck(ctx context.Context) error {
+	panic("implement me")
+}
+
 func (*gossipMock) Stop() {
 	panic("implement me")
 }
@@ -236,3 +241,23 @@ func TestJoinChannelNoAnchorPeers(t *testing.T) {
 	})
 	joinChanCalled.Wait()
 }
+
+func TestOrgLedgerHeights(t *testing.T) {
+	gMock := &gossipMock{}
+	gMock.On("IdentityInfo").Return(api.PeerIdentitySet{
+		{PKIId: common.PKIidType("p1"), Organization: api.OrgIdentityType("Org0")},
+		{PKIId: common.PKIidType("p2"), Organization: api.OrgIdentityType("Org1")},
+		{PKIId: common.PKIidType("p3"), Organization: api.OrgIdentityType("Org0")},
+		{PKIId: common.PKIidType("p4"), Organization: api.OrgIdentityType("Org0")},
+	})
+	gMock.On("PeersOfChannel", common.ChannelID("A")).Return([]discovery.NetworkMember{
+		{PKIid: common.PKIidType("p1"), Properties: &proto.Properties{LedgerHeight: 10}},
+		{PKIid: common.PKIidType("p2"), Properties: &proto.Properties{LedgerHeight: 20}},
+		{PKIid: common.PKIidType("p3"), Properties: &proto.Properties{LedgerHeight: 5}},
+		{PKIid: common.PKIidType("p4")},
+		{PKIid: common.PKIidType("p5"), Properties: &proto.Properties{LedgerHeight: 30}},
+	})
+
+	g := &GossipService{secAdv: &secAdvMock{}, peerIdentity: api.PeerIdentityType("Org0"), gossipSvc: gMock}
+	assert.Equal(t, []uint64{10, 5}, g.OrgLedgerHeights("A"))
+}
@@ -36,6 +36,9 @@ import (
 type GossipStateProvider interface {
 	AddPayload(payload *proto.Payload) error
 
+	// BacklogSize returns the number of blocks received but not yet committed
+	BacklogSize() int
+
 	// Stop terminates state transfer object
 	Stop()
 }
@@ -747,6 +750,12 @@ func (s *GossipStateProviderImpl) hasRequiredHeight(height uint64) func(peer dis
 	}
 }
 
+// BacklogSize returns the number of blocks in the payload buffer, that is
+// blocks received but not yet committed.
+func (s *GossipStateProviderImpl) BacklogSize() int {
+	return s.payloads.Size()
+}
+
 // AddPayload adds new payload into state.
 func (s *GossipStateProviderImpl) AddPayload(payload *proto.Payload) error {
 	return s.addPayload(payload, s.blockingMode)
@@ -253,6 +253,10 @@ func (*mockCommitter) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker,
 	panic("implement me")
 }
 
+func (*mockCommitter) GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error) {
+	panic("implement me")
+}
+
 func (*mockCommitter) CommitPvtDataOfOldBlocks(
 	reconciledPvtdata []*ledger.ReconciledPvtdata,
 	unreconciled ledger.MissingPvtDataInfo,
@@ -272,6 +276,10 @@ func (mock *ramLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker,
 	panic("implement me")
 }
 
+func (mock *ramLedger) GetPvtDataAntiEntropy() (ledger.PvtDataAntiEntropy, error) {
+	panic("implement me")
+}
+
 func (mock *ramLedger) CommitPvtDataOfOldBlocks(
 	reconciledPvtdata []*ledger.ReconciledPvtdata,
 	unreconciled ledger.MissingPvtDataInfo,
@@ -321,6 +329,10 @@ func (mock *ramLedger) GetBlockchainInfo() (*pcomm.BlockchainInfo, error) {
 	}, nil
 }
 
+func (mock *ramLedger) GetCommittedStateInfo() (*pcomm.BlockchainInfo, error) {
+	return mock.GetBlockchainInfo()
+}
+
 func (mock *ramLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	return false, nil
 }
@@ -595,6 +607,29 @@ func TestAddPayloadLedgerUnavailable(t *testing.T) {
 	assert.Contains(t, err.Error(), "cannot query ledger")
 }
 
+func TestBacklogSize(t *testing.T) {
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	g := &mocks.GossipMock{}
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, make(chan protoext.ReceivedMessage))
+	p := newPeerNodeWithGossip(0, mc, noopPeerIdentityAcceptor, g)
+	defer p.shutdown()
+	assert.Equal(t, 0, p.s.BacklogSize())
+
+	// Blocks after a gap can't be committed and wait in the buffer
+	for seq := uint64(5); seq <= 6; seq++ {
+		rawblock := protoutil.NewBlock(seq, []byte{})
+		b, _ := pb.Marshal(rawblock)
+		err := p.s.AddPayload(&proto.Payload{
+			SeqNum: seq,
+			Data:   b,
+		})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, p.s.BacklogSize())
+}
+
 func TestLargeBlockGap(t *testing.T) {
 	// Scenario: the peer knows of a peer who has a ledger height much higher
 	// than itself (500 blocks higher).
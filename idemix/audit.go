@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"github.com/hyperledger/fabric-amcl/amcl"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/pkg/errors"
+)
+
+// The audit escrow lets an auditor link the signatures produced with credentials
+// holding the same value of an undisclosed attribute (e.g., the enrollment ID),
+// without the attribute being disclosed to anyone else.
+// The issuer public key names the escrowed attribute and holds the public key of the auditor;
+// every signature then carries an ElGamal encryption of h_attr^attr under that key
+// together with a proof, part of the zero-knowledge proof of the signature, that the
+// encrypted attribute is the one signed in the credential.
+
+// NewAuditEscrowKey creates a new audit escrow key pair for an auditor
+func NewAuditEscrowKey(rng *amcl.RAND) (*AuditEscrowKey, error) {
+	if rng == nil {
+		return nil, errors.Errorf("cannot create audit escrow key: received nil input")
+	}
+	sk := RandModOrder(rng)
+	return &AuditEscrowKey{
+		Sk: BigToBytes(sk),
+		Pk: EcpToProto(GenG1.Mul(sk)),
+	}, nil
+}
+
+// SetAuditEscrow requires the signatures under the issuer public key to escrow the named
+// attribute for the auditor holding the secret key of pk, and updates the hash of the key
+func (IPk *IssuerPublicKey) SetAuditEscrow(pk *ECP, attribute string) error {
+	if pk == nil {
+		return errors.Errorf("cannot set audit escrow: received nil public key")
+	}
+	IPk.AuditEscrowPk = pk
+	IPk.AuditEscrowAttribute = attribute
+	if _, err := auditEscrowIndex(IPk); err != nil {
+		IPk.AuditEscrowPk = nil
+		IPk.AuditEscrowAttribute = ""
+		return errors.WithMessage(err, "cannot set audit escrow")
+	}
+	return IPk.SetHash()
+}
+
+// Decrypt decrypts the audit escrow with the secret key of the auditor, returning the audit tag
+// of the escrowed attribute value: escrows of the same attribute value have the same audit tag.
+// The escrow is bound to the credential by the zero-knowledge proof of the signature holding it,
+// which must have been verified against the issuer public key.
+func (e *AuditEscrow) Decrypt(key *AuditEscrowKey) ([]byte, error) {
+	if key == nil {
+		return nil, errors.Errorf("cannot decrypt audit escrow: received nil input")
+	}
+	if e == nil || e.C1 == nil || e.C2 == nil {
+		return nil, errors.Errorf("cannot decrypt audit escrow: audit escrow is incomplete")
+	}
+
+	// h_attr^attr = C2 / C1^sk
+	tag := EcpFromProto(e.C2)
+	tag.Sub(EcpFromProto(e.C1).Mul(FP256BN.FromBytes(key.Sk)))
+	return EcpToBytes(tag), nil
+}
+
+// AuditTagOf returns the audit tag of the signatures escrowing the given attribute value,
+// which lets the auditor check whether signatures escrow a known value
+func AuditTagOf(ipk *IssuerPublicKey, attributeValue *FP256BN.BIG) ([]byte, error) {
+	if ipk == nil || attributeValue == nil {
+		return nil, errors.Errorf("cannot compute audit tag: received nil input")
+	}
+	index, err := auditEscrowIndex(ipk)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot compute audit tag")
+	}
+	if index < 0 {
+		return nil, errors.Errorf("cannot compute audit tag: issuer public key has no audit escrow")
+	}
+	return EcpToBytes(EcpFromProto(ipk.HAttrs[index]).Mul(attributeValue)), nil
+}
+
+// auditEscrowIndex returns the index of the attribute escrowed for the auditor,
+// or -1 if the issuer public key has no audit escrow
+func auditEscrowIndex(ipk *IssuerPublicKey) (int, error) {
+	if ipk.AuditEscrowPk == nil {
+		return -1, nil
+	}
+	for i, name := range ipk.AttributeNames {
+		if name == ipk.AuditEscrowAttribute {
+			if i >= len(ipk.HAttrs) {
+				return -1, errors.Errorf("issuer public key has no base for attribute %s", name)
+			}
+			return i, nil
+		}
+	}
+	return -1, errors.Errorf("audit escrow attribute %s is not an attribute of the issuer public key", ipk.AuditEscrowAttribute)
+}
+
+// auditEscrow holds the values of the audit escrow of a signature used in its zero-knowledge proof.
+// A nil auditEscrow adds nothing to the proof.
+type auditEscrow struct {
+	C1, C2 *FP256BN.ECP
+	t4, t5 *FP256BN.ECP
+	// k and its randomness rK are only known to the signer
+	k, rK *FP256BN.BIG
+}
+
+// numElements returns the number of elements of G1 the escrow adds to the proof data
+func (e *auditEscrow) numElements() int {
+	if e == nil {
+		return 0
+	}
+	return 4
+}
+
+// appendBytes appends the t-values and the encryption of the escrow to the proof data
+func (e *auditEscrow) appendBytes(data []byte, index int) int {
+	if e == nil {
+		return index
+	}
+	index = appendBytesG1(data, index, e.t4)
+	index = appendBytesG1(data, index, e.t5)
+	index = appendBytesG1(data, index, e.C1)
+	return appendBytesG1(data, index, e.C2)
+}
+
+// toProto returns the audit escrow of the signature, given the challenge of the zero-knowledge proof
+func (e *auditEscrow) toProto(ProofC *FP256BN.BIG) *AuditEscrow {
+	if e == nil {
+		return nil
+	}
+	return &AuditEscrow{
+		C1: EcpToProto(e.C1),
+		C2: EcpToProto(e.C2),
+		// s_k = r_k + C \cdot k
+		ProofSK: BigToBytes(Modadd(e.rK, FP256BN.Modmul(ProofC, e.k, GroupOrder), GroupOrder)),
+	}
+}
@@ -139,6 +139,8 @@ func (m *ECP2) GetYb() []byte {
 // h_sk, h_rand, h_attrs, w, bar_g1, bar_g2 - group elements corresponding to the signing key, randomness, and attributes
 // proof_c, proof_s compose a zero-knowledge proof of knowledge of the secret key
 // hash is a hash of the public key appended to it
+// audit_escrow_pk, audit_escrow_attribute optionally require the signatures to escrow
+// the named attribute under the public key of an auditor, see AuditEscrow
 type IssuerPublicKey struct {
 	AttributeNames       []string `protobuf:"bytes,1,rep,name=attribute_names,json=attributeNames,proto3" json:"attribute_names,omitempty"`
 	HSk                  *ECP     `protobuf:"bytes,2,opt,name=h_sk,json=hSk,proto3" json:"h_sk,omitempty"`
@@ -150,6 +152,8 @@ type IssuerPublicKey struct {
 	ProofC               []byte   `protobuf:"bytes,8,opt,name=proof_c,json=proofC,proto3" json:"proof_c,omitempty"`
 	ProofS               []byte   `protobuf:"bytes,9,opt,name=proof_s,json=proofS,proto3" json:"proof_s,omitempty"`
 	Hash                 []byte   `protobuf:"bytes,10,opt,name=hash,proto3" json:"hash,omitempty"`
+	AuditEscrowPk        *ECP     `protobuf:"bytes,11,opt,name=audit_escrow_pk,json=auditEscrowPk,proto3" json:"audit_escrow_pk,omitempty"`
+	AuditEscrowAttribute string   `protobuf:"bytes,12,opt,name=audit_escrow_attribute,json=auditEscrowAttribute,proto3" json:"audit_escrow_attribute,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -250,6 +254,20 @@ func (m *IssuerPublicKey) GetHash() []byte {
 	return nil
 }
 
+func (m *IssuerPublicKey) GetAuditEscrowPk() *ECP {
+	if m != nil {
+		return m.AuditEscrowPk
+	}
+	return nil
+}
+
+func (m *IssuerPublicKey) GetAuditEscrowAttribute() string {
+	if m != nil {
+		return m.AuditEscrowAttribute
+	}
+	return ""
+}
+
 // IssuerKey specifies an issuer key pair that consists of
 // ISk - the issuer secret key and
 // IssuerPublicKey - the issuer public key
@@ -448,6 +466,7 @@ func (m *CredRequest) GetProofS() []byte {
 // and the corresponding user secret together with the attribute values
 // nonce - a fresh nonce used for the signature
 // nym - a fresh pseudonym (a commitment to to the user secret)
+// audit_escrow - the escrow of an attribute required by the issuer public key
 type Signature struct {
 	APrime               *ECP                `protobuf:"bytes,1,opt,name=a_prime,json=aPrime,proto3" json:"a_prime,omitempty"`
 	ABar                 *ECP                `protobuf:"bytes,2,opt,name=a_bar,json=aBar,proto3" json:"a_bar,omitempty"`
@@ -466,6 +485,7 @@ type Signature struct {
 	RevocationPkSig      []byte              `protobuf:"bytes,15,opt,name=revocation_pk_sig,json=revocationPkSig,proto3" json:"revocation_pk_sig,omitempty"`
 	Epoch                int64               `protobuf:"varint,16,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	NonRevocationProof   *NonRevocationProof `protobuf:"bytes,17,opt,name=non_revocation_proof,json=nonRevocationProof,proto3" json:"non_revocation_proof,omitempty"`
+	AuditEscrow          *AuditEscrow        `protobuf:"bytes,18,opt,name=audit_escrow,json=auditEscrow,proto3" json:"audit_escrow,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
 	XXX_sizecache        int32               `json:"-"`
@@ -615,6 +635,13 @@ func (m *Signature) GetNonRevocationProof() *NonRevocationProof {
 	return nil
 }
 
+func (m *Signature) GetAuditEscrow() *AuditEscrow {
+	if m != nil {
+		return m.AuditEscrow
+	}
+	return nil
+}
+
 // NonRevocationProof contains proof that the credential is not revoked
 type NonRevocationProof struct {
 	RevocationAlg        int32    `protobuf:"varint,1,opt,name=revocation_alg,json=revocationAlg,proto3" json:"revocation_alg,omitempty"`
@@ -811,6 +838,119 @@ func (m *CredentialRevocationInformation) GetRevocationData() []byte {
 	return nil
 }
 
+// AuditEscrow is a verifiable ElGamal encryption of an undisclosed attribute of a
+// signature under the public key of an auditor:
+// c1 = g^k and c2 = h_attr^attr * pk^k, where h_attr is the base of the attribute
+// in the issuer public key.
+// proof_s_k is the s-value proving knowledge of k, the encryption is bound to the
+// attribute signed in the credential by the zero-knowledge proof of the signature.
+// The auditor decrypts c2 / c1^sk = h_attr^attr, a tag that links the signatures
+// escrowing the same attribute value without disclosing it.
+type AuditEscrow struct {
+	C1                   *ECP     `protobuf:"bytes,1,opt,name=c1,proto3" json:"c1,omitempty"`
+	C2                   *ECP     `protobuf:"bytes,2,opt,name=c2,proto3" json:"c2,omitempty"`
+	ProofSK              []byte   `protobuf:"bytes,3,opt,name=proof_s_k,json=proofSK,proto3" json:"proof_s_k,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEscrow) Reset()         { *m = AuditEscrow{} }
+func (m *AuditEscrow) String() string { return proto.CompactTextString(m) }
+func (*AuditEscrow) ProtoMessage()    {}
+func (*AuditEscrow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d23908e9a304c6, []int{10}
+}
+
+func (m *AuditEscrow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEscrow.Unmarshal(m, b)
+}
+func (m *AuditEscrow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEscrow.Marshal(b, m, deterministic)
+}
+func (m *AuditEscrow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEscrow.Merge(m, src)
+}
+func (m *AuditEscrow) XXX_Size() int {
+	return xxx_messageInfo_AuditEscrow.Size(m)
+}
+func (m *AuditEscrow) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEscrow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEscrow proto.InternalMessageInfo
+
+func (m *AuditEscrow) GetC1() *ECP {
+	if m != nil {
+		return m.C1
+	}
+	return nil
+}
+
+func (m *AuditEscrow) GetC2() *ECP {
+	if m != nil {
+		return m.C2
+	}
+	return nil
+}
+
+func (m *AuditEscrow) GetProofSK() []byte {
+	if m != nil {
+		return m.ProofSK
+	}
+	return nil
+}
+
+// AuditEscrowKey specifies the key pair of an auditor that consists of
+// sk - the secret key and
+// pk - the public key, pk = g^sk
+type AuditEscrowKey struct {
+	Sk                   []byte   `protobuf:"bytes,1,opt,name=sk,proto3" json:"sk,omitempty"`
+	Pk                   *ECP     `protobuf:"bytes,2,opt,name=pk,proto3" json:"pk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEscrowKey) Reset()         { *m = AuditEscrowKey{} }
+func (m *AuditEscrowKey) String() string { return proto.CompactTextString(m) }
+func (*AuditEscrowKey) ProtoMessage()    {}
+func (*AuditEscrowKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d23908e9a304c6, []int{11}
+}
+
+func (m *AuditEscrowKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEscrowKey.Unmarshal(m, b)
+}
+func (m *AuditEscrowKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEscrowKey.Marshal(b, m, deterministic)
+}
+func (m *AuditEscrowKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEscrowKey.Merge(m, src)
+}
+func (m *AuditEscrowKey) XXX_Size() int {
+	return xxx_messageInfo_AuditEscrowKey.Size(m)
+}
+func (m *AuditEscrowKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEscrowKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEscrowKey proto.InternalMessageInfo
+
+func (m *AuditEscrowKey) GetSk() []byte {
+	if m != nil {
+		return m.Sk
+	}
+	return nil
+}
+
+func (m *AuditEscrowKey) GetPk() *ECP {
+	if m != nil {
+		return m.Pk
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ECP)(nil), "ECP")
 	proto.RegisterType((*ECP2)(nil), "ECP2")
@@ -822,61 +962,70 @@ func init() {
 	proto.RegisterType((*NonRevocationProof)(nil), "NonRevocationProof")
 	proto.RegisterType((*NymSignature)(nil), "NymSignature")
 	proto.RegisterType((*CredentialRevocationInformation)(nil), "CredentialRevocationInformation")
+	proto.RegisterType((*AuditEscrow)(nil), "AuditEscrow")
+	proto.RegisterType((*AuditEscrowKey)(nil), "AuditEscrowKey")
 }
 
 func init() { proto.RegisterFile("idemix.proto", fileDescriptor_28d23908e9a304c6) }
 
 var fileDescriptor_28d23908e9a304c6 = []byte{
-	// 814 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xcb, 0x8e, 0xe2, 0x46,
-	0x14, 0x55, 0x61, 0x9b, 0x6e, 0x2e, 0xee, 0xa6, 0xa7, 0xba, 0x95, 0xa9, 0xbc, 0x14, 0xc6, 0xca,
-	0x64, 0x5a, 0x59, 0xd0, 0x19, 0x5a, 0xf9, 0x80, 0x1e, 0x42, 0xa2, 0x51, 0x24, 0x84, 0xcc, 0x2e,
-	0x9b, 0x52, 0x15, 0x14, 0xd8, 0x02, 0xdb, 0xa4, 0x6c, 0x32, 0x38, 0x8b, 0x7c, 0x4d, 0xfe, 0x26,
-	0x8b, 0xfc, 0x52, 0x54, 0x0f, 0x70, 0xd1, 0xcc, 0x64, 0xe7, 0x7b, 0xcf, 0x7d, 0x71, 0xce, 0x31,
-	0x86, 0x30, 0x5d, 0x88, 0x2c, 0xdd, 0x0f, 0xb6, 0xb2, 0xa8, 0x8a, 0xe8, 0x15, 0x78, 0xe3, 0xd1,
-	0x14, 0x87, 0x80, 0xf6, 0x04, 0xf5, 0xd1, 0x7d, 0x18, 0xa3, 0xbd, 0x8a, 0x6a, 0xd2, 0x32, 0x51,
-	0x1d, 0xfd, 0x0c, 0xfe, 0x78, 0x34, 0x1d, 0xe2, 0x6b, 0x68, 0xed, 0x99, 0x2d, 0x6a, 0xed, 0x99,
-	0x8e, 0xb9, 0x2d, 0x6b, 0xed, 0xb9, 0x8a, 0x6b, 0x46, 0x3c, 0x13, 0xd7, 0x1a, 0xaf, 0x39, 0xf1,
-	0x6d, 0xcc, 0xa3, 0xbf, 0x5b, 0xd0, 0x7b, 0x5f, 0x96, 0x3b, 0x21, 0xa7, 0x3b, 0xbe, 0x49, 0xe7,
-	0xbf, 0x8a, 0x1a, 0xbf, 0x81, 0x1e, 0xab, 0x2a, 0x99, 0xf2, 0x5d, 0x25, 0x68, 0xce, 0x32, 0x51,
-	0x12, 0xd4, 0xf7, 0xee, 0x3b, 0xf1, 0xf5, 0x31, 0x3d, 0x51, 0x59, 0xfc, 0x12, 0xfc, 0x84, 0x96,
-	0x6b, 0xbd, 0xae, 0x3b, 0xf4, 0x07, 0xe3, 0xd1, 0x34, 0xf6, 0x92, 0xd9, 0x1a, 0x7f, 0x09, 0xed,
-	0x84, 0x4a, 0x96, 0x2f, 0xf4, 0xe6, 0x03, 0x14, 0x24, 0x31, 0xcb, 0x17, 0xf8, 0x6b, 0xb8, 0x48,
-	0xa8, 0x9a, 0x54, 0x12, 0xbf, 0xef, 0x1d, 0xd1, 0x76, 0xf2, 0xa4, 0x72, 0xf8, 0x16, 0xd0, 0x07,
-	0x12, 0xe8, 0xb6, 0x40, 0x01, 0xc3, 0x18, 0x7d, 0x50, 0x03, 0x39, 0x93, 0x74, 0xf5, 0x96, 0xb4,
-	0xdd, 0x81, 0x9c, 0xc9, 0x5f, 0xde, 0x1e, 0xc1, 0x21, 0xb9, 0x78, 0x0e, 0x0e, 0xf1, 0x4b, 0xb8,
-	0xd8, 0xca, 0xa2, 0x58, 0xd2, 0x39, 0xb9, 0xd4, 0xbf, 0xba, 0xad, 0xc3, 0x51, 0x03, 0x94, 0xa4,
-	0xe3, 0x00, 0x33, 0x8c, 0xc1, 0x4f, 0x58, 0x99, 0x10, 0xd0, 0x59, 0xfd, 0x1c, 0x3d, 0x41, 0xc7,
-	0xb0, 0xa4, 0xf8, 0xb9, 0x01, 0x2f, 0x2d, 0xd7, 0x96, 0x74, 0xf5, 0x88, 0x23, 0xf0, 0xd2, 0xed,
-	0x81, 0x87, 0x9b, 0xc1, 0x33, 0x42, 0x63, 0x05, 0x46, 0x4b, 0x80, 0x91, 0x14, 0x0b, 0x91, 0x57,
-	0x29, 0xdb, 0x60, 0x0c, 0xc8, 0xc8, 0x76, 0x38, 0x17, 0x31, 0x95, 0xe3, 0x27, 0x5c, 0x22, 0xae,
-	0x54, 0x17, 0x56, 0x3e, 0x24, 0x54, 0x54, 0x5a, 0xf1, 0x50, 0x89, 0xef, 0x20, 0x30, 0x34, 0x06,
-	0x7d, 0xef, 0x3e, 0x8c, 0x4d, 0x10, 0xfd, 0x09, 0x5d, 0xb5, 0x27, 0x16, 0xbf, 0xef, 0x44, 0x59,
-	0xe1, 0xcf, 0xc0, 0xcb, 0xeb, 0xec, 0x64, 0x95, 0x4a, 0xe0, 0x57, 0x10, 0xa6, 0xfa, 0x4c, 0x9a,
-	0x17, 0xf9, 0x5c, 0x58, 0xcb, 0x74, 0x4d, 0x6e, 0xa2, 0x52, 0x2e, 0x75, 0xde, 0xa7, 0xa8, 0xf3,
-	0x5d, 0xea, 0xa2, 0x7f, 0x7d, 0xe8, 0xcc, 0xd2, 0x55, 0xce, 0xaa, 0x9d, 0x14, 0x4a, 0x68, 0x46,
-	0xb7, 0x32, 0xcd, 0xc4, 0xc9, 0xfa, 0x36, 0x9b, 0xaa, 0x1c, 0xfe, 0x1c, 0x02, 0x46, 0x39, 0x93,
-	0x27, 0x3f, 0xd9, 0x67, 0xef, 0x98, 0x54, 0x9d, 0xdc, 0x76, 0xba, 0x06, 0x6a, 0x73, 0xd3, 0xe9,
-	0x1c, 0xe6, 0x9f, 0x1c, 0xf6, 0x15, 0x80, 0x3d, 0x4c, 0xd9, 0x32, 0xd0, 0xd8, 0xa5, 0xb9, 0x6d,
-	0xb6, 0xc6, 0x5f, 0x40, 0xe7, 0x80, 0x0a, 0xed, 0xa3, 0x30, 0x36, 0x73, 0x66, 0x63, 0xb7, 0x53,
-	0x1a, 0x1f, 0x1d, 0x3b, 0xe3, 0xe1, 0x09, 0xfa, 0x68, 0x7d, 0x74, 0x40, 0x1f, 0xf1, 0x6b, 0xe8,
-	0x1d, 0xb7, 0xda, 0xab, 0x8d, 0xa3, 0x42, 0xbb, 0xda, 0x5c, 0x1d, 0xc1, 0xd5, 0xa1, 0xcc, 0xc8,
-	0x06, 0x5a, 0xb6, 0xae, 0x29, 0x32, 0xe6, 0xbf, 0x83, 0xc0, 0xc8, 0xd1, 0xd5, 0x03, 0x4c, 0x70,
-	0xd0, 0x30, 0x3c, 0xd7, 0xf0, 0x38, 0x51, 0x52, 0x55, 0x71, 0xa5, 0xbb, 0xc0, 0x5e, 0x36, 0xa9,
-	0x33, 0xfc, 0x23, 0xdc, 0x4a, 0xf1, 0x47, 0x31, 0x67, 0x55, 0x5a, 0xe4, 0x54, 0x6c, 0x8b, 0x79,
-	0x42, 0xb7, 0x6b, 0x72, 0xed, 0xbe, 0x5f, 0x2f, 0x9a, 0x8a, 0xb1, 0x2a, 0x98, 0xae, 0xf1, 0xf7,
-	0xe0, 0x24, 0xe9, 0x76, 0x4d, 0xcb, 0x74, 0x45, 0x7a, 0x7a, 0x7a, 0xaf, 0x01, 0xa6, 0xeb, 0x59,
-	0xba, 0x52, 0x37, 0xeb, 0xb9, 0xe4, 0xa6, 0x8f, 0xee, 0xbd, 0xd8, 0x04, 0x78, 0x0c, 0x77, 0x79,
-	0x91, 0x53, 0x77, 0x8a, 0xba, 0x8a, 0xbc, 0xd0, 0x9b, 0x6f, 0x07, 0x93, 0x22, 0x8f, 0x9b, 0x41,
-	0x0a, 0x8a, 0x71, 0x7e, 0x96, 0x8b, 0x32, 0xc0, 0xe7, 0x95, 0xf8, 0x35, 0x5c, 0x3b, 0x83, 0xd9,
-	0x66, 0xa5, 0x0d, 0x16, 0xc4, 0x57, 0x4d, 0xf6, 0x69, 0xb3, 0xc2, 0x3f, 0x7c, 0xe2, 0x06, 0xe3,
-	0xf5, 0x8f, 0xad, 0xfb, 0x0b, 0xc2, 0x49, 0x9d, 0x35, 0x16, 0x76, 0x9c, 0x86, 0xfe, 0xc7, 0x69,
-	0xad, 0x67, 0x4e, 0x3b, 0x13, 0xc6, 0x3b, 0x13, 0xe6, 0xa8, 0xb4, 0xef, 0x28, 0x1d, 0xfd, 0x83,
-	0xe0, 0x9b, 0xe6, 0x5f, 0xa2, 0xb9, 0xee, 0x7d, 0xbe, 0x2c, 0x64, 0xa6, 0x1f, 0x1b, 0xbe, 0x91,
-	0xcb, 0x77, 0x1f, 0x2e, 0x8f, 0xea, 0xb6, 0x5c, 0x75, 0x2f, 0x84, 0xd5, 0xb4, 0x0f, 0xe1, 0xa1,
-	0x42, 0xcb, 0x69, 0x6f, 0xb2, 0xb0, 0x52, 0xf2, 0x9c, 0x56, 0xff, 0x63, 0xb4, 0xbe, 0x01, 0xc7,
-	0x03, 0x74, 0xc1, 0x2a, 0x66, 0x5f, 0x35, 0xa7, 0xfb, 0x27, 0x56, 0xb1, 0x77, 0xdf, 0xfd, 0xf6,
-	0xed, 0x2a, 0xad, 0x92, 0x1d, 0x1f, 0xcc, 0x8b, 0xec, 0x21, 0xa9, 0xb7, 0x42, 0x6e, 0xc4, 0x62,
-	0x25, 0xe4, 0xc3, 0x92, 0x71, 0x99, 0xce, 0x1f, 0xcc, 0x57, 0x8f, 0xb7, 0xf5, 0x67, 0xef, 0xf1,
-	0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1c, 0xca, 0x29, 0x38, 0x06, 0x07, 0x00, 0x00,
+	// 925 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x56, 0x4d, 0x8f, 0xe2, 0x46,
+	0x10, 0x95, 0xbf, 0x98, 0xa1, 0xf0, 0xc0, 0x6c, 0x0f, 0xda, 0xed, 0x7c, 0x29, 0xac, 0x95, 0xcd,
+	0xa2, 0x28, 0x62, 0x32, 0x4c, 0x92, 0x3b, 0x4b, 0x48, 0xb4, 0x5a, 0x09, 0x21, 0xa3, 0x5c, 0x72,
+	0xb1, 0xda, 0xa6, 0x07, 0x5b, 0x06, 0xdb, 0x69, 0x9b, 0x0c, 0xce, 0x21, 0xca, 0x31, 0xff, 0x2d,
+	0x7f, 0x2a, 0xea, 0x6e, 0x63, 0xb7, 0x87, 0x9d, 0xdc, 0x5c, 0xf5, 0xaa, 0xaa, 0x1f, 0x7e, 0xaf,
+	0x1a, 0x83, 0x1d, 0x6d, 0xe8, 0x3e, 0x3a, 0x4e, 0x32, 0x96, 0x16, 0xa9, 0xf3, 0x1a, 0x8c, 0xc5,
+	0x7c, 0x85, 0x6c, 0xd0, 0x8e, 0x58, 0x1b, 0x69, 0x63, 0xdb, 0xd5, 0x8e, 0x3c, 0x2a, 0xb1, 0x2e,
+	0xa3, 0xd2, 0xf9, 0x19, 0xcc, 0xc5, 0x7c, 0x35, 0x45, 0x7d, 0xd0, 0x8f, 0xa4, 0x2a, 0xd2, 0x8f,
+	0x44, 0xc4, 0x7e, 0x55, 0xa6, 0x1f, 0x7d, 0x1e, 0x97, 0x04, 0x1b, 0x32, 0x2e, 0x05, 0x5e, 0xfa,
+	0xd8, 0xac, 0x62, 0xdf, 0xf9, 0xc7, 0x80, 0xc1, 0xfb, 0x3c, 0x3f, 0x50, 0xb6, 0x3a, 0xf8, 0xbb,
+	0x28, 0xf8, 0x40, 0x4b, 0xf4, 0x16, 0x06, 0xa4, 0x28, 0x58, 0xe4, 0x1f, 0x0a, 0xea, 0x25, 0x64,
+	0x4f, 0x73, 0xac, 0x8d, 0x8c, 0x71, 0xd7, 0xed, 0xd7, 0xe9, 0x25, 0xcf, 0xa2, 0x57, 0x60, 0x86,
+	0x5e, 0x1e, 0x8b, 0xe3, 0x7a, 0x53, 0x73, 0xb2, 0x98, 0xaf, 0x5c, 0x23, 0x5c, 0xc7, 0xe8, 0x33,
+	0xe8, 0x84, 0x1e, 0x23, 0xc9, 0x06, 0x1b, 0x0a, 0x64, 0x85, 0x2e, 0x49, 0x36, 0xe8, 0x0b, 0xb8,
+	0x08, 0x3d, 0x3e, 0x29, 0xc7, 0xe6, 0xc8, 0xa8, 0xd1, 0x4e, 0x38, 0xe3, 0x39, 0x74, 0x03, 0xda,
+	0x23, 0xb6, 0x44, 0x9b, 0xc5, 0x81, 0xa9, 0xab, 0x3d, 0xf2, 0x81, 0x3e, 0x61, 0xde, 0xf6, 0x0e,
+	0x77, 0xd4, 0x81, 0x3e, 0x61, 0xbf, 0xdc, 0xd5, 0xe0, 0x14, 0x5f, 0x3c, 0x05, 0xa7, 0xe8, 0x15,
+	0x5c, 0x64, 0x2c, 0x4d, 0x1f, 0xbc, 0x00, 0x5f, 0x8a, 0x5f, 0xdd, 0x11, 0xe1, 0xbc, 0x01, 0x72,
+	0xdc, 0x55, 0x80, 0x35, 0x42, 0x60, 0x86, 0x24, 0x0f, 0x31, 0x88, 0xac, 0x78, 0x46, 0xdf, 0xc2,
+	0x80, 0x1c, 0x36, 0x51, 0xe1, 0xd1, 0x3c, 0x60, 0xe9, 0xa3, 0x97, 0xc5, 0xb8, 0xa7, 0x9c, 0x75,
+	0x25, 0xc0, 0x85, 0xc0, 0x56, 0x31, 0xfa, 0x1e, 0x5e, 0xb6, 0xaa, 0xeb, 0xd7, 0x86, 0xed, 0x91,
+	0x36, 0xee, 0xba, 0x43, 0xa5, 0x7c, 0x76, 0xc2, 0x9c, 0x19, 0x74, 0xa5, 0x12, 0x5c, 0x83, 0x6b,
+	0x30, 0xa2, 0x3c, 0xae, 0x84, 0xe5, 0x8f, 0xc8, 0x01, 0x23, 0xca, 0x4e, 0xef, 0xfa, 0x7a, 0xf2,
+	0x44, 0x34, 0x97, 0x83, 0xce, 0x03, 0xc0, 0x9c, 0xd1, 0x0d, 0x4d, 0x8a, 0x88, 0xec, 0x10, 0x02,
+	0x4d, 0x5a, 0xe3, 0x44, 0x53, 0x23, 0x3c, 0xe7, 0xb7, 0xf4, 0xd2, 0x7c, 0xee, 0x2c, 0x5a, 0x59,
+	0x44, 0xa3, 0x3c, 0xca, 0x2b, 0x83, 0x68, 0x39, 0x1a, 0x82, 0x25, 0xa5, 0xb2, 0x46, 0xc6, 0xd8,
+	0x76, 0x65, 0xe0, 0xfc, 0x09, 0x3d, 0x7e, 0x8e, 0x4b, 0x7f, 0x3f, 0xd0, 0xbc, 0x40, 0x2f, 0xc1,
+	0x48, 0xca, 0x7d, 0xeb, 0x28, 0x9e, 0x40, 0xaf, 0xc1, 0x8e, 0x04, 0x4d, 0x2f, 0x49, 0x93, 0x80,
+	0x56, 0xb6, 0xec, 0xc9, 0xdc, 0x92, 0xa7, 0x54, 0x79, 0x8c, 0xe7, 0xe4, 0x31, 0x55, 0x79, 0x9c,
+	0xbf, 0x2d, 0xe8, 0xae, 0xa3, 0x6d, 0x42, 0x8a, 0x03, 0xa3, 0xdc, 0x4c, 0xc4, 0xcb, 0x58, 0xb4,
+	0xa7, 0xad, 0xe3, 0x3b, 0x64, 0xc5, 0x73, 0xe8, 0x13, 0xb0, 0x88, 0xe7, 0x13, 0xd6, 0xfa, 0xc9,
+	0x26, 0x79, 0x47, 0x18, 0xef, 0xf4, 0xab, 0x4e, 0xd5, 0xa4, 0x1d, 0x5f, 0x76, 0x2a, 0xc4, 0xcc,
+	0x16, 0xb1, 0xcf, 0x01, 0x2a, 0x62, 0xdc, 0xfa, 0x96, 0xc0, 0x2e, 0x25, 0xb7, 0x75, 0x8c, 0x3e,
+	0x85, 0xee, 0x09, 0xa5, 0xc2, 0xab, 0xb6, 0x2b, 0xe7, 0xac, 0x17, 0x6a, 0x27, 0x93, 0x5e, 0xad,
+	0x3b, 0xdd, 0x69, 0x0b, 0xbd, 0xc7, 0x97, 0x2d, 0xf4, 0x1e, 0xbd, 0x81, 0x41, 0x7d, 0x6a, 0xc5,
+	0x5a, 0xba, 0xd6, 0xae, 0x8e, 0x96, 0xac, 0x1d, 0xb8, 0x3a, 0x95, 0x49, 0xd9, 0x40, 0xc8, 0xd6,
+	0x93, 0x45, 0x72, 0xc1, 0x86, 0x60, 0x49, 0x39, 0x7a, 0x62, 0x80, 0x0c, 0x4e, 0x1a, 0xda, 0xe7,
+	0x1a, 0xd6, 0x13, 0x99, 0xc7, 0x2b, 0xae, 0x44, 0x17, 0x54, 0xcc, 0x96, 0xe5, 0x1e, 0xfd, 0x00,
+	0x37, 0x8c, 0xfe, 0x91, 0x06, 0xa4, 0x88, 0xd2, 0xc4, 0xa3, 0x59, 0x1a, 0x84, 0x7c, 0x41, 0xfa,
+	0xea, 0x0e, 0xbf, 0x68, 0x2a, 0x16, 0xbc, 0x60, 0x15, 0xa3, 0x6f, 0x40, 0x49, 0x7a, 0x59, 0xec,
+	0xe5, 0xd1, 0x16, 0x0f, 0xc4, 0xf4, 0x41, 0x03, 0xac, 0xe2, 0x75, 0xb4, 0xe5, 0x9c, 0xc5, 0x5c,
+	0x7c, 0x3d, 0xd2, 0xc6, 0x86, 0x2b, 0x03, 0xb4, 0x80, 0x61, 0x92, 0x26, 0x9e, 0x3a, 0x85, 0xb3,
+	0xc2, 0x2f, 0xc4, 0xc9, 0x37, 0x93, 0x65, 0x9a, 0xb8, 0xcd, 0x20, 0x0e, 0xb9, 0x28, 0x39, 0xcb,
+	0xa1, 0x5b, 0xb0, 0xd5, 0x75, 0xc5, 0x48, 0xb4, 0xdb, 0x93, 0x59, 0xb3, 0xa5, 0x6e, 0x4f, 0x59,
+	0x59, 0x67, 0x0f, 0xe8, 0x7c, 0x34, 0x7a, 0x03, 0x7d, 0x85, 0x09, 0xd9, 0x6d, 0x85, 0x23, 0x2d,
+	0xf7, 0xaa, 0xc9, 0xce, 0x76, 0x5b, 0xf4, 0xdd, 0x33, 0xa4, 0xe5, 0x72, 0x7c, 0x84, 0x9f, 0xf3,
+	0x17, 0xd8, 0xcb, 0x72, 0xdf, 0x78, 0x5e, 0xb1, 0xa6, 0xf6, 0x3f, 0xd6, 0xd4, 0x9f, 0x58, 0xf3,
+	0x4c, 0x49, 0xe3, 0x4c, 0xc9, 0xda, 0x1a, 0xa6, 0x62, 0x0d, 0xe7, 0x5f, 0x0d, 0xbe, 0x6c, 0xae,
+	0x95, 0x86, 0xdd, 0xfb, 0xe4, 0x21, 0x65, 0x7b, 0xf1, 0xd8, 0x08, 0xa4, 0xa9, 0x02, 0x8d, 0xe0,
+	0xb2, 0xb6, 0x83, 0xae, 0xda, 0xe1, 0x82, 0x56, 0x26, 0x18, 0x81, 0x7d, 0xaa, 0x10, 0xfa, 0x57,
+	0x9c, 0x2a, 0x98, 0x4b, 0x7f, 0xfe, 0x5a, 0xcd, 0x8f, 0xbd, 0xd6, 0xb7, 0xa0, 0x98, 0xc6, 0xdb,
+	0x90, 0x82, 0x54, 0xbb, 0xa9, 0x74, 0xff, 0x44, 0x0a, 0xe2, 0xfc, 0x0a, 0x3d, 0x45, 0x58, 0x34,
+	0x04, 0x3d, 0xb8, 0x6b, 0xdd, 0x1d, 0x7a, 0x70, 0x27, 0xb2, 0xd3, 0xd6, 0xa5, 0xa1, 0x07, 0x53,
+	0x75, 0xb9, 0xe3, 0x8a, 0x69, 0xb5, 0xdc, 0x1f, 0x9c, 0x1f, 0xa1, 0xaf, 0x8c, 0xe5, 0x57, 0x78,
+	0x1f, 0xf4, 0xfa, 0x06, 0xd7, 0xf3, 0x98, 0xcf, 0xcc, 0xda, 0xff, 0x95, 0x7a, 0x16, 0xbf, 0xfb,
+	0xfa, 0xb7, 0xaf, 0xb6, 0x51, 0x11, 0x1e, 0xfc, 0x49, 0x90, 0xee, 0x6f, 0xc3, 0x32, 0xa3, 0x6c,
+	0x47, 0x37, 0x5b, 0xca, 0x6e, 0x1f, 0x88, 0xcf, 0xa2, 0xe0, 0x56, 0x7e, 0x19, 0xf8, 0x1d, 0xf1,
+	0x69, 0x70, 0xff, 0xdf, 0x00, 0x20, 0x92, 0x7b, 0xab, 0x2a, 0x08, 0x00, 0x00,
 }
@@ -162,3 +162,87 @@ func TestIdemix(t *testing.T) {
 		return
 	}
 }
+
+func TestAuditEscrow(t *testing.T) {
+	rng, err := GetRand()
+	assert.NoError(t, err)
+
+	AttributeNames := []string{"OU", "EnrollmentID", "RevocationHandle"}
+	key, err := NewIssuerKey(AttributeNames, rng)
+	assert.NoError(t, err)
+
+	escrowKey, err := NewAuditEscrowKey(rng)
+	assert.NoError(t, err)
+	assert.Error(t, key.Ipk.SetAuditEscrow(escrowKey.Pk, "Role"), "escrowing an unknown attribute should fail")
+	assert.Nil(t, key.Ipk.AuditEscrowPk)
+	assert.NoError(t, key.Ipk.SetAuditEscrow(escrowKey.Pk, "EnrollmentID"))
+	assert.NoError(t, key.Ipk.Check())
+
+	revocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	cri, err := CreateCRI(revocationKey, []*FP256BN.BIG{}, 0, ALG_NO_REVOCATION, rng)
+	assert.NoError(t, err)
+
+	newCred := func(eid *FP256BN.BIG) (*Credential, *FP256BN.BIG) {
+		sk := RandModOrder(rng)
+		m := NewCredRequest(sk, BigToBytes(RandModOrder(rng)), key.Ipk, rng)
+		cred, err := NewCredential(key, m, []*FP256BN.BIG{FP256BN.NewBIGint(1), eid, RandModOrder(rng)}, rng)
+		assert.NoError(t, err)
+		return cred, sk
+	}
+	sign := func(cred *Credential, sk *FP256BN.BIG, disclosure []byte) (*Signature, error) {
+		Nym, RandNym := MakeNym(sk, key.Ipk, rng)
+		return NewSignature(cred, sk, Nym, RandNym, key.Ipk, disclosure, []byte("msg"), 2, cri, rng)
+	}
+
+	alice := HashModOrder([]byte("alice"))
+	cred1, sk1 := newCred(alice)
+	cred2, sk2 := newCred(alice)
+	cred3, sk3 := newCred(HashModOrder([]byte("bob")))
+
+	disclosure := []byte{1, 0, 0}
+	attrs := []*FP256BN.BIG{FP256BN.NewBIGint(1), nil, nil}
+	sig1, err := sign(cred1, sk1, disclosure)
+	assert.NoError(t, err)
+	sig2, err := sign(cred2, sk2, disclosure)
+	assert.NoError(t, err)
+	sig3, err := sign(cred3, sk3, disclosure)
+	assert.NoError(t, err)
+	for _, sig := range []*Signature{sig1, sig2, sig3} {
+		assert.NotNil(t, sig.AuditEscrow)
+		assert.NoError(t, sig.Ver(disclosure, key.Ipk, []byte("msg"), attrs, 2, &revocationKey.PublicKey, 0))
+	}
+
+	// The escrowed attribute must remain hidden
+	_, err = sign(cred1, sk1, []byte{1, 1, 0})
+	assert.EqualError(t, err, "Attribute 1 is disclosed but also escrowed for the auditor, which should remain hidden.")
+
+	// The auditor links the signatures of the same enrollment ID
+	tag1, err := sig1.AuditEscrow.Decrypt(escrowKey)
+	assert.NoError(t, err)
+	tag2, err := sig2.AuditEscrow.Decrypt(escrowKey)
+	assert.NoError(t, err)
+	tag3, err := sig3.AuditEscrow.Decrypt(escrowKey)
+	assert.NoError(t, err)
+	assert.Equal(t, tag1, tag2)
+	assert.NotEqual(t, tag1, tag3)
+	aliceTag, err := AuditTagOf(key.Ipk, alice)
+	assert.NoError(t, err)
+	assert.Equal(t, aliceTag, tag1)
+
+	otherKey, err := NewAuditEscrowKey(rng)
+	assert.NoError(t, err)
+	otherTag, err := sig1.AuditEscrow.Decrypt(otherKey)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tag1, otherTag, "another key should not decrypt the escrow")
+
+	// Tampering with or stripping the escrow invalidates the signature
+	c2 := sig1.AuditEscrow.C2
+	sig1.AuditEscrow.C2 = sig3.AuditEscrow.C2
+	assert.Error(t, sig1.Ver(disclosure, key.Ipk, []byte("msg"), attrs, 2, &revocationKey.PublicKey, 0))
+	sig1.AuditEscrow.C2 = c2
+	sig1.AuditEscrow = nil
+	assert.EqualError(t, sig1.Ver(disclosure, key.Ipk, []byte("msg"), attrs, 2, &revocationKey.PublicKey, 0), "signature invalid: no audit escrow of attribute EnrollmentID")
+	_, err = sig1.AuditEscrow.Decrypt(escrowKey)
+	assert.EqualError(t, err, "cannot decrypt audit escrow: audit escrow is incomplete")
+}
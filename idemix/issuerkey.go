@@ -142,6 +142,9 @@ func (IPk *IssuerPublicKey) Check() error {
 			return errors.Errorf("some part of the public key is undefined")
 		}
 	}
+	if _, err := auditEscrowIndex(IPk); err != nil {
+		return err
+	}
 
 	// Verify Proof
 
@@ -46,6 +46,8 @@ func hiddenIndices(Disclosure []byte) []int {
 // The []byte Disclosure steers which attributes are disclosed:
 // if Disclosure[i] == 0 then attribute i remains hidden and otherwise it is disclosed.
 // We require the revocation handle to remain undisclosed (i.e., Disclosure[rhIndex] == 0).
+// If the issuer public key has an audit escrow key, the attribute it names must remain undisclosed
+// and is escrowed for the auditor in the signature.
 // We use the zero-knowledge proof by http://eprint.iacr.org/2016/663.pdf, Sec. 4.5 to prove knowledge of a BBS+ signature
 func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP256BN.BIG, ipk *IssuerPublicKey, Disclosure []byte, msg []byte, rhIndex int, cri *CredentialRevocationInformation, rng *amcl.RAND) (*Signature, error) {
 	// Validate inputs
@@ -61,6 +63,14 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 		return nil, errors.Errorf("Attribute %d is disclosed but also used as revocation handle attribute, which should remain hidden.", rhIndex)
 	}
 
+	escrowIndex, err := auditEscrowIndex(ipk)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create idemix signature")
+	}
+	if escrowIndex >= 0 && Disclosure[escrowIndex] == 1 {
+		return nil, errors.Errorf("Attribute %d is disclosed but also escrowed for the auditor, which should remain hidden.", escrowIndex)
+	}
+
 	// locate the indices of the attributes to hide and sample randomness for them
 	HiddenIndices := hiddenIndices(Disclosure)
 
@@ -158,17 +168,32 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 	// t3 is related to the knowledge of the secrets behind the pseudonym, which is also signed in (A,B,S,E)
 	t3 := HSk.Mul2(rSk, HRand, rRNym) // h_{sk}^{r_{sk}} \cdot h_r^{r_{rnym}}
 
+	// The audit escrow (C1, C2) encrypts the escrowed attribute under the public key Y of the auditor,
+	// t4 and t5 are related to the knowledge of the randomness k of the encryption and of the attribute,
+	// which is also signed in (A,B,S,E)
+	var escrow *auditEscrow
+	if escrowIndex >= 0 {
+		escrow = &auditEscrow{k: RandModOrder(rng), rK: RandModOrder(rng)}
+		HEscrow := EcpFromProto(ipk.HAttrs[escrowIndex])
+		Y := EcpFromProto(ipk.AuditEscrowPk)
+		escrow.C1 = FP256BN.G1mul(GenG1, escrow.k)                                                  // g^k
+		escrow.C2 = HEscrow.Mul2(FP256BN.FromBytes(cred.Attrs[escrowIndex]), Y, escrow.k)           // h_{escrow}^{attr} \cdot Y^k
+		escrow.t4 = FP256BN.G1mul(GenG1, escrow.rK)                                                 // g^{r_k}
+		escrow.t5 = HEscrow.Mul2(rAttrs[sort.SearchInts(HiddenIndices, escrowIndex)], Y, escrow.rK) // h_{escrow}^{r_{attr}} \cdot Y^{r_k}
+	}
+
 	// Step 2: Compute the Fiat-Shamir hash, forming the challenge of the ZKP.
 
 	// Compute the Fiat-Shamir hash, forming the challenge of the ZKP.
 	// proofData is the data being hashed, it consists of:
 	// the signature label
 	// 7 elements of G1 each taking 2*FieldBytes+1 bytes
+	// 4 more elements of G1 if the signature has an audit escrow
 	// one bigint (hash of the issuer public key) of length FieldBytes
 	// disclosed attributes
 	// message being signed
 	// the amount of bytes needed for the nonrevocation proof
-	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+ProofBytes[RevocationAlgorithm(cri.RevocationAlg)])
+	proofData := make([]byte, len([]byte(signLabel))+(7+escrow.numElements())*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+ProofBytes[RevocationAlgorithm(cri.RevocationAlg)])
 	index := 0
 	index = appendBytesString(proofData, index, signLabel)
 	index = appendBytesG1(proofData, index, t1)
@@ -178,6 +203,7 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 	index = appendBytesG1(proofData, index, ABar)
 	index = appendBytesG1(proofData, index, BPrime)
 	index = appendBytesG1(proofData, index, Nym)
+	index = escrow.appendBytes(proofData, index)
 	index = appendBytes(proofData, index, nonRevokedProofHashData)
 	copy(proofData[index:], ipk.Hash)
 	index = index + FieldBytes
@@ -216,6 +242,7 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 
 	// We are done. Return signature
 	return &Signature{
+			AuditEscrow:        escrow.toProto(ProofC),
 			APrime:             EcpToProto(APrime),
 			ABar:               EcpToProto(ABar),
 			BPrime:             EcpToProto(BPrime),
@@ -254,6 +281,21 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 		return errors.Errorf("Attribute %d is disclosed but is also used as revocation handle, which should remain hidden.", rhIndex)
 	}
 
+	escrowIndex, err := auditEscrowIndex(ipk)
+	if err != nil {
+		return errors.WithMessage(err, "cannot verify idemix signature")
+	}
+	if escrowIndex >= 0 {
+		if Disclosure[escrowIndex] == 1 {
+			return errors.Errorf("Attribute %d is disclosed but is also escrowed for the auditor, which should remain hidden.", escrowIndex)
+		}
+		if sig.AuditEscrow == nil || sig.AuditEscrow.C1 == nil || sig.AuditEscrow.C2 == nil {
+			return errors.Errorf("signature invalid: no audit escrow of attribute %s", ipk.AuditEscrowAttribute)
+		}
+	} else if sig.AuditEscrow != nil {
+		return errors.Errorf("signature invalid: audit escrow not required by the issuer public key")
+	}
+
 	HiddenIndices := hiddenIndices(Disclosure)
 
 	// Parse signature
@@ -328,6 +370,19 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	t3 := HSk.Mul2(ProofSSk, HRand, ProofSRNym)
 	t3.Sub(Nym.Mul(ProofC))
 
+	// Recompute t4 and t5 of the audit escrow
+	var escrow *auditEscrow
+	if escrowIndex >= 0 {
+		escrow = &auditEscrow{C1: EcpFromProto(sig.AuditEscrow.C1), C2: EcpFromProto(sig.AuditEscrow.C2)}
+		ProofSK := FP256BN.FromBytes(sig.AuditEscrow.ProofSK)
+		HEscrow := EcpFromProto(ipk.HAttrs[escrowIndex])
+		Y := EcpFromProto(ipk.AuditEscrowPk)
+		escrow.t4 = FP256BN.G1mul(GenG1, ProofSK)
+		escrow.t4.Sub(FP256BN.G1mul(escrow.C1, ProofC))
+		escrow.t5 = HEscrow.Mul2(ProofSAttrs[sort.SearchInts(HiddenIndices, escrowIndex)], Y, ProofSK)
+		escrow.t5.Sub(FP256BN.G1mul(escrow.C2, ProofC))
+	}
+
 	// add contribution from the non-revocation proof
 	nonRevokedVer, err := getNonRevocationVerifier(RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg))
 	if err != nil {
@@ -345,10 +400,11 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	// proofData is the data being hashed, it consists of:
 	// the signature label
 	// 7 elements of G1 each taking 2*FieldBytes+1 bytes
+	// 4 more elements of G1 if the signature has an audit escrow
 	// one bigint (hash of the issuer public key) of length FieldBytes
 	// disclosed attributes
 	// message that was signed
-	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+ProofBytes[RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg)])
+	proofData := make([]byte, len([]byte(signLabel))+(7+escrow.numElements())*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+ProofBytes[RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg)])
 	index := 0
 	index = appendBytesString(proofData, index, signLabel)
 	index = appendBytesG1(proofData, index, t1)
@@ -358,6 +414,7 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	index = appendBytesG1(proofData, index, ABar)
 	index = appendBytesG1(proofData, index, BPrime)
 	index = appendBytesG1(proofData, index, Nym)
+	index = escrow.appendBytes(proofData, index)
 	index = appendBytes(proofData, index, nonRevokedProofBytes)
 	copy(proofData[index:], ipk.Hash)
 	index = index + FieldBytes
@@ -117,6 +117,9 @@ type GossipPvtData struct {
 	ReconcileSleepInterval                     time.Duration                   `yaml:"reconcileSleepInterval,omitempty"`
 	ReconciliationEnabled                      bool                            `yaml:"reconciliationEnabled"`
 	SkipPullingInvalidTransactionsDuringCommit bool                            `yaml:"skipPullingInvalidTransactionsDuringCommit"`
+	AntiEntropyEnabled                         bool                            `yaml:"antiEntropyEnabled"`
+	AntiEntropyInterval                        time.Duration                   `yaml:"antiEntropyInterval,omitempty"`
+	AntiEntropyBatchSize                       int                             `yaml:"antiEntropyBatchSize,omitempty"`
 	ImplicitCollDisseminationPolicy            ImplicitCollDisseminationPolicy `yaml:"implicitCollectionDisseminationPolicy"`
 }
 
@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package ccmetadata
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/hyperledger/fabric/common/flogging"
 )
@@ -25,17 +27,20 @@ type fileValidator func(fileName string, fileBytes []byte) error
 // AllowedCharsCollectionName captures the regex pattern for a valid collection name
 const AllowedCharsCollectionName = "[A-Za-z0-9_-]+"
 
-// Currently, the only metadata expected and allowed is for META-INF/statedb/couchdb/indexes.
+// Currently, the only metadata expected and allowed is for META-INF/statedb/couchdb/indexes
+// and META-INF/statedb/leveldb/indexes.
 var fileValidators = map[*regexp.Regexp]fileValidator{
 	regexp.MustCompile("^META-INF/statedb/couchdb/indexes/.*[.]json"):                                                couchdbIndexFileValidator,
 	regexp.MustCompile("^META-INF/statedb/couchdb/collections/" + AllowedCharsCollectionName + "/indexes/.*[.]json"): couchdbIndexFileValidator,
+	regexp.MustCompile("^META-INF/statedb/leveldb/indexes/.*[.]json"):                                                leveldbIndexFileValidator,
+	regexp.MustCompile("^META-INF/statedb/leveldb/collections/" + AllowedCharsCollectionName + "/indexes/.*[.]json"): leveldbIndexFileValidator,
 }
 
 var collectionNameValid = regexp.MustCompile("^" + AllowedCharsCollectionName)
 
 var fileNameValid = regexp.MustCompile("^.*[.]json")
 
-var validDatabases = []string{"couchdb"}
+var validDatabases = []string{"couchdb", "leveldb"}
 
 // UnhandledDirectoryError is returned for metadata files in unhandled directories
 type UnhandledDirectoryError struct {
@@ -156,6 +161,40 @@ func couchdbIndexFileValidator(fileName string, fileBytes []byte) error {
 
 }
 
+// leveldbIndexFileValidator implements fileValidator for the composite key
+// families declared for leveldb, such as
+// {"compositeKeyFamilies":[{"objectType":"color~name","prefixAttributes":1}]}
+func leveldbIndexFileValidator(fileName string, fileBytes []byte) error {
+	declaration := &struct {
+		CompositeKeyFamilies []*struct {
+			ObjectType       *string `json:"objectType"`
+			PrefixAttributes int     `json:"prefixAttributes"`
+		} `json:"compositeKeyFamilies"`
+	}{}
+	decoder := json.NewDecoder(bytes.NewReader(fileBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(declaration); err != nil {
+		return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] is not a valid composite key families definition: %s", fileName, err)}
+	}
+
+	if len(declaration.CompositeKeyFamilies) == 0 {
+		return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] must declare at least one entry in \"compositeKeyFamilies\"", fileName)}
+	}
+	for _, family := range declaration.CompositeKeyFamilies {
+		if family == nil || family.ObjectType == nil || *family.ObjectType == "" {
+			return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] declares a composite key family without \"objectType\"", fileName)}
+		}
+		if strings.ContainsRune(*family.ObjectType, 0) || strings.ContainsRune(*family.ObjectType, utf8.MaxRune) {
+			return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] declares object type [%s] containing a reserved character", fileName, *family.ObjectType)}
+		}
+		if family.PrefixAttributes < 0 {
+			return &InvalidIndexContentError{fmt.Sprintf("Index metadata file [%s] declares negative \"prefixAttributes\" for object type [%s]", fileName, *family.ObjectType)}
+		}
+	}
+
+	return nil
+}
+
 // isJSON tests a string to determine if it can be parsed as valid JSON
 func isJSON(s []byte) (bool, map[string]interface{}) {
 	var js map[string]interface{}
@@ -48,6 +48,31 @@ func TestBadIndexJSON(t *testing.T) {
 	t.Log("SAMPLE ERROR STRING:", err.Error())
 }
 
+func TestLeveldbIndexJSON(t *testing.T) {
+	fileName := "META-INF/statedb/leveldb/indexes/colorIndex.json"
+	fileBytes := []byte(`{"compositeKeyFamilies":[{"objectType":"color~name","prefixAttributes":1},{"objectType":"owner~name"}]}`)
+	err := ValidateMetadataFile(fileName, fileBytes)
+	assert.NoError(t, err, "Error validating a good leveldb index")
+
+	fileName = "META-INF/statedb/leveldb/collections/testcoll/indexes/colorIndex.json"
+	err = ValidateMetadataFile(fileName, fileBytes)
+	assert.NoError(t, err, "Error validating a good leveldb collection index")
+
+	fileName = "META-INF/statedb/leveldb/indexes/colorIndex.json"
+	for _, badIndex := range []string{
+		`invalid json`,
+		`{"index":{"fields":["data.docType","data.owner"]},"name":"indexOwner","type":"json"}`,
+		`{"compositeKeyFamilies":[]}`,
+		`{"compositeKeyFamilies":[{"prefixAttributes":1}]}`,
+		`{"compositeKeyFamilies":[{"objectType":"col\u0000or"}]}`,
+		`{"compositeKeyFamilies":[{"objectType":"color","prefixAttributes":-1}]}`,
+	} {
+		err = ValidateMetadataFile(fileName, []byte(badIndex))
+		_, ok := err.(*InvalidIndexContentError)
+		assert.True(t, ok, "Should have received an InvalidIndexContentError for %s", badIndex)
+	}
+}
+
 func TestIndexWrongLocation(t *testing.T) {
 	testDir := filepath.Join(packageTestDir, "IndexWrongLocation")
 	cleanupDir(testDir)
@@ -378,10 +378,47 @@ func NewConsortiumGroup(conf *genesisconfig.Consortium) (*cb.ConfigGroup, error)
 
 	addValue(consortiumGroup, channelconfig.ChannelCreationPolicyValue(policies.ImplicitMetaAnyPolicy(channelconfig.AdminsPolicyKey).Value()), ordererAdminsPolicyName)
 
+	if len(conf.ChannelTemplates) > 0 {
+		templates := map[string]*cb.ConfigGroup{}
+		for templateName, template := range conf.ChannelTemplates {
+			var err error
+			templates[templateName], err = NewChannelTemplateGroup(template)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create channel template %s", templateName)
+			}
+		}
+		addValue(consortiumGroup, channelconfig.ChannelTemplatesValue(templates), ordererAdminsPolicyName)
+	}
+
 	consortiumGroup.ModPolicy = ordererAdminsPolicyName
 	return consortiumGroup, nil
 }
 
+// NewChannelTemplateGroup returns the template of the application group of the channels created from a channel template.
+// The organizations are only referenced by name, the orderer copies their definitions from the consortium when it creates
+// a channel from the template.  It sets the mod_policy of all elements to "Admins".
+func NewChannelTemplateGroup(conf *genesisconfig.ChannelTemplate) (*cb.ConfigGroup, error) {
+	templateGroup := protoutil.NewConfigGroup()
+	if err := AddPolicies(templateGroup, conf.Policies, channelconfig.AdminsPolicyKey); err != nil {
+		return nil, errors.Wrapf(err, "error adding policies to channel template group")
+	}
+
+	if len(conf.ACLs) > 0 {
+		addValue(templateGroup, channelconfig.ACLValues(conf.ACLs), channelconfig.AdminsPolicyKey)
+	}
+
+	if len(conf.Capabilities) > 0 {
+		addValue(templateGroup, channelconfig.CapabilitiesValue(conf.Capabilities), channelconfig.AdminsPolicyKey)
+	}
+
+	for _, org := range conf.Organizations {
+		templateGroup.Groups[org.Name] = protoutil.NewConfigGroup()
+	}
+
+	templateGroup.ModPolicy = channelconfig.AdminsPolicyKey
+	return templateGroup, nil
+}
+
 // NewChannelCreateConfigUpdate generates a ConfigUpdate which can be sent to the orderer to create a new channel.  Optionally, the channel group of the
 // ordering system channel may be passed in, and the resulting ConfigUpdate will extract the appropriate versions from this file.
 func NewChannelCreateConfigUpdate(channelID string, conf *genesisconfig.Profile, templateConfig *cb.ConfigGroup) (*cb.ConfigUpdate, error) {
@@ -416,6 +453,35 @@ func NewChannelCreateConfigUpdate(channelID string, conf *genesisconfig.Profile,
 	return updt, nil
 }
 
+// NewTemplatedChannelCreateConfigUpdate generates a ConfigUpdate which can be sent to the orderer to create a new channel
+// for the given consortium from one of its channel templates, referenced in the isolated data.  The orderer creates the
+// application group of the channel from the template, so the ConfigUpdate leaves it untouched and only sets the
+// consortium value, whose modification is governed by the channel creation policy of the consortium.
+func NewTemplatedChannelCreateConfigUpdate(channelID, consortium, templateName string) *cb.ConfigUpdate {
+	return &cb.ConfigUpdate{
+		ChannelId: channelID,
+		ReadSet: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				channelconfig.ConsortiumKey: {},
+			},
+		},
+		WriteSet: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				channelconfig.ConsortiumKey: {
+					Version:   1,
+					ModPolicy: channelconfig.AdminsPolicyKey,
+					Value: protoutil.MarshalOrPanic(&cb.Consortium{
+						Name: consortium,
+					}),
+				},
+			},
+		},
+		IsolatedData: map[string][]byte{
+			channelconfig.ChannelTemplateIsolatedDataKey: []byte(templateName),
+		},
+	}
+}
+
 // DefaultConfigTemplate generates a config template based on the assumption that
 // the input profile is a channel creation template and no system channel context
 // is available.
@@ -530,6 +596,28 @@ func MakeChannelCreationTransactionFromTemplate(
 		return nil, errors.Wrap(err, "config update generation failure")
 	}
 
+	return makeChannelCreationTransaction(channelID, signer, newChannelConfigUpdate)
+}
+
+// MakeTemplatedChannelCreationTransaction creates a transaction for creating a channel for the given consortium
+// from one of its channel templates, which are defined in the ordering system channel.
+func MakeTemplatedChannelCreationTransaction(
+	channelID string,
+	signer identity.SignerSerializer,
+	consortium string,
+	templateName string,
+) (*cb.Envelope, error) {
+	if consortium == "" {
+		return nil, errors.New("cannot define a new channel with no Consortium value")
+	}
+	if templateName == "" {
+		return nil, errors.New("cannot define a new channel from an empty channel template name")
+	}
+
+	return makeChannelCreationTransaction(channelID, signer, NewTemplatedChannelCreateConfigUpdate(channelID, consortium, templateName))
+}
+
+func makeChannelCreationTransaction(channelID string, signer identity.SignerSerializer, newChannelConfigUpdate *cb.ConfigUpdate) (*cb.Envelope, error) {
 	newConfigUpdateEnv := &cb.ConfigUpdateEnvelope{
 		ConfigUpdate: protoutil.MarshalOrPanic(newChannelConfigUpdate),
 	}
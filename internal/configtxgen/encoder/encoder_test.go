@@ -533,6 +533,53 @@ var _ = Describe("Encoder", func() {
 		})
 	})
 
+	Describe("NewChannelTemplateGroup", func() {
+		var (
+			conf *genesisconfig.ChannelTemplate
+		)
+
+		BeforeEach(func() {
+			conf = &genesisconfig.ChannelTemplate{
+				Organizations: []*genesisconfig.Organization{
+					{
+						Name: "SampleOrg",
+					},
+				},
+				ACLs: map[string]string{
+					"SomeACL": "SomePolicy",
+				},
+				Policies: CreateStandardPolicies(),
+				Capabilities: map[string]bool{
+					"FakeCapability": true,
+				},
+			}
+		})
+
+		It("translates the config into a config group referencing the orgs by name", func() {
+			cg, err := encoder.NewChannelTemplateGroup(conf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(cg.Policies)).To(Equal(3))
+			Expect(cg.Policies["Admins"]).NotTo(BeNil())
+			Expect(len(cg.Groups)).To(Equal(1))
+			Expect(proto.Equal(cg.Groups["SampleOrg"], protoutil.NewConfigGroup())).To(BeTrue())
+			Expect(len(cg.Values)).To(Equal(2))
+			Expect(cg.Values["ACLs"]).NotTo(BeNil())
+			Expect(cg.Values["Capabilities"]).NotTo(BeNil())
+			Expect(cg.ModPolicy).To(Equal("Admins"))
+		})
+
+		Context("when the policy definition is bad", func() {
+			BeforeEach(func() {
+				conf.Policies["Admins"].Rule = "garbage"
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := encoder.NewChannelTemplateGroup(conf)
+				Expect(err).To(MatchError("error adding policies to channel template group: invalid implicit meta policy rule 'garbage': expected two space separated tokens, but got 1"))
+			})
+		})
+	})
+
 	Describe("NewConsortiumOrgGroup", func() {
 		var (
 			conf *genesisconfig.Organization
@@ -1167,6 +1214,44 @@ var _ = Describe("Encoder", func() {
 			})
 		})
 
+		Describe("MakeTemplatedChannelCreationTransaction", func() {
+			It("returns a tx referencing the channel template", func() {
+				env, err := encoder.MakeTemplatedChannelCreationTransaction("channel-id", nil, "SampleConsortium", "gold")
+				Expect(err).NotTo(HaveOccurred())
+				payload := &cb.Payload{}
+				err = proto.Unmarshal(env.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+				err = proto.Unmarshal(payload.Data, configUpdateEnv)
+				Expect(err).NotTo(HaveOccurred())
+				configUpdate := &cb.ConfigUpdate{}
+				err = proto.Unmarshal(configUpdateEnv.ConfigUpdate, configUpdate)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(configUpdate.ChannelId).To(Equal("channel-id"))
+				Expect(configUpdate.IsolatedData).To(Equal(map[string][]byte{"channel_template": []byte("gold")}))
+				Expect(configUpdate.WriteSet.Groups).To(BeEmpty())
+				Expect(configUpdate.WriteSet.Values["Consortium"].Version).To(Equal(uint64(1)))
+				consortium := &cb.Consortium{}
+				err = proto.Unmarshal(configUpdate.WriteSet.Values["Consortium"].Value, consortium)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(consortium.Name).To(Equal("SampleConsortium"))
+			})
+
+			Context("when the template name is empty", func() {
+				It("returns an error", func() {
+					_, err := encoder.MakeTemplatedChannelCreationTransaction("channel-id", nil, "SampleConsortium", "")
+					Expect(err).To(MatchError("cannot define a new channel from an empty channel template name"))
+				})
+			})
+
+			Context("when the consortium is empty", func() {
+				It("returns an error", func() {
+					_, err := encoder.MakeTemplatedChannelCreationTransaction("channel-id", nil, "", "gold")
+					Expect(err).To(MatchError("cannot define a new channel with no Consortium value"))
+				})
+			})
+		})
+
 		Describe("DefaultConfigTemplate", func() {
 			var (
 				conf *genesisconfig.Profile
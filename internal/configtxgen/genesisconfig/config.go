@@ -98,7 +98,19 @@ type Policy struct {
 // Consortium represents a group of organizations which may create channels
 // with each other
 type Consortium struct {
-	Organizations []*Organization `yaml:"Organizations"`
+	Organizations    []*Organization             `yaml:"Organizations"`
+	ChannelTemplates map[string]*ChannelTemplate `yaml:"ChannelTemplates"`
+}
+
+// ChannelTemplate encodes a named template of the application-level configuration
+// of the channels created by a consortium. A channel creation request referencing
+// the template by name has the orderer materialize the application configuration,
+// with the definitions of the organizations taken from the consortium.
+type ChannelTemplate struct {
+	Organizations []*Organization    `yaml:"Organizations"`
+	Capabilities  map[string]bool    `yaml:"Capabilities"`
+	Policies      map[string]*Policy `yaml:"Policies"`
+	ACLs          map[string]string  `yaml:"ACLs"`
 }
 
 // Application encodes the application-level configuration needed in config
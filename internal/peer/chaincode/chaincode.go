@@ -20,7 +20,7 @@ import (
 
 const (
 	chainFuncName = "chaincode"
-	chainCmdDes   = "Operate a chaincode: install|instantiate|invoke|package|query|signpackage|upgrade|list."
+	chainCmdDes   = "Operate a chaincode: install|instantiate|invoke|package|query|signpackage|upgrade|list|build-proposal|build-transaction|finalize-transaction."
 )
 
 var logger = flogging.MustGetLogger("chaincodeCmd")
@@ -49,6 +49,9 @@ func Cmd(cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) *cobra.Command {
 	chaincodeCmd.AddCommand(signpackageCmd(cf, cryptoProvider))
 	chaincodeCmd.AddCommand(upgradeCmd(cf, cryptoProvider))
 	chaincodeCmd.AddCommand(listCmd(cf, cryptoProvider))
+	chaincodeCmd.AddCommand(buildProposalCmd(cf, cryptoProvider))
+	chaincodeCmd.AddCommand(buildTransactionCmd(cf, cryptoProvider))
+	chaincodeCmd.AddCommand(finalizeTransactionCmd(cf, cryptoProvider))
 
 	return chaincodeCmd
 }
@@ -77,6 +80,10 @@ var (
 	connectionProfile     string
 	waitForEvent          bool
 	waitForEventTimeout   time.Duration
+	creatorCertFile       string
+	creatorMSPID          string
+	minStateVersion       string
+	idempotencyKey        string
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -143,6 +150,10 @@ func resetFlags() {
 		"if creating CC deployment spec package for owner endorsements, also sign it with local MSP")
 	flags.StringVarP(&instantiationPolicy, "instantiate-policy", "i", "",
 		"instantiation policy for the chaincode")
+	flags.StringVarP(&creatorCertFile, "creator", "", "",
+		"The path to the PEM certificate of the identity that signs the proposal offline. Defaults to the local MSP identity")
+	flags.StringVarP(&creatorMSPID, "creatorMSPID", "", "",
+		"The MSP ID of the identity that signs the proposal offline. Defaults to the local MSP ID")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
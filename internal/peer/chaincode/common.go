@@ -24,12 +24,15 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/common/util"
+	pendorser "github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/internal/peer/common"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 // checkSpec to see if chaincode resides within current package capture for language.
@@ -494,12 +497,11 @@ func InitCmdFactory(cmdName string, isEndorserRequired, isOrdererRequired bool,
 			if len(orderingEndpoints) == 0 {
 				return nil, errors.Errorf("no orderer endpoints retrieved for channel %s, pass orderer endpoint with -o flag instead", channelID)
 			}
-			logger.Infof("Retrieved channel (%s) orderer endpoint: %s", channelID, orderingEndpoints[0])
-			// override viper env
-			viper.Set("orderer.address", orderingEndpoints[0])
+			logger.Infof("Retrieved channel (%s) orderer endpoints: %s", channelID, orderingEndpoints)
+			broadcastClient, err = common.GetBroadcastClientForEndpoints(orderingEndpoints)
+		} else {
+			broadcastClient, err = common.GetBroadcastClientFnc()
 		}
-
-		broadcastClient, err = common.GetBroadcastClientFnc()
 		if err != nil {
 			return nil, errors.WithMessage(err, "error getting broadcast client")
 		}
@@ -522,11 +524,19 @@ func processProposals(endorserClients []pb.EndorserClient, signedProposal *pb.Si
 		wg.Add(1)
 		go func(endorser pb.EndorserClient) {
 			defer wg.Done()
-			proposalResp, err := endorser.ProcessProposal(context.Background(), signedProposal)
+			ctx := context.Background()
+			if minStateVersion != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, pendorser.MinStateVersionHeader, minStateVersion)
+			}
+			var header metadata.MD
+			proposalResp, err := endorser.ProcessProposal(ctx, signedProposal, grpc.Header(&header))
 			if err != nil {
 				errorCh <- err
 				return
 			}
+			if stateVersion := header.Get(pendorser.StateVersionHeader); len(stateVersion) > 0 {
+				logger.Infof("Proposal simulated at state version %s", stateVersion[0])
+			}
 			responsesCh <- proposalResp
 		}(endorser)
 	}
@@ -589,6 +599,12 @@ func ChaincodeInvokeOrQuery(
 		return nil, errors.WithMessagef(err, "error creating proposal for %s", funcName)
 	}
 
+	if invoke && idempotencyKey != "" {
+		if err := setIdempotencyKey(prop, idempotencyKey); err != nil {
+			return nil, errors.WithMessagef(err, "error creating proposal for %s", funcName)
+		}
+	}
+
 	signedProp, err := protoutil.GetSignedProposal(prop, signer)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "error creating signed proposal for %s", funcName)
@@ -657,6 +673,27 @@ func ChaincodeInvokeOrQuery(
 	return proposalResp, nil
 }
 
+// setIdempotencyKey sets the idempotency key of the channel header of the
+// proposal, which is carried over to the transaction assembled from it.
+func setIdempotencyKey(prop *pb.Proposal, key string) error {
+	hdr, err := protoutil.UnmarshalHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	chdr.IdempotencyKey = key
+	if hdr.ChannelHeader, err = proto.Marshal(chdr); err != nil {
+		return errors.Wrap(err, "error marshaling channel header")
+	}
+	if prop.Header, err = proto.Marshal(hdr); err != nil {
+		return errors.Wrap(err, "error marshaling header")
+	}
+	return nil
+}
+
 // DeliverGroup holds all of the information needed to connect
 // to a set of peers to wait for the interested txid to be
 // committed to the ledgers of all peers. This functionality
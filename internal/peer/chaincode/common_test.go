@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
 	"github.com/hyperledger/fabric/internal/peer/chaincode/mock"
@@ -35,6 +36,8 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 //go:generate counterfeiter -o mock/signer_serializer.go --fake-name SignerSerializer . signerSerializer
@@ -887,4 +890,40 @@ func TestProcessProposals(t *testing.T) {
 		assert.EqualError(t, err, "failed to call endorser")
 		assert.Nil(t, responses)
 	})
+	t.Run("should require the state version of a previous query", func(t *testing.T) {
+		minStateVersion = "7:cafe"
+		defer func() { minStateVersion = "" }()
+		client := &outgoingMetadataEndorserClient{}
+		_, err := processProposals([]pb.EndorserClient{client}, signedProposal)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"7:cafe"}, client.md.Get(endorser.MinStateVersionHeader))
+	})
+}
+
+type outgoingMetadataEndorserClient struct {
+	md metadata.MD
+}
+
+func (c *outgoingMetadataEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	c.md, _ = metadata.FromOutgoingContext(ctx)
+	return &pb.ProposalResponse{Response: &pb.Response{Status: 200}}, nil
+}
+
+func TestSetIdempotencyKey(t *testing.T) {
+	prop, _, err := protoutil.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}},
+	}, []byte("creator"))
+	require.NoError(t, err)
+
+	err = setIdempotencyKey(prop, "key")
+	require.NoError(t, err)
+	hdr, err := protoutil.UnmarshalHeader(prop.Header)
+	require.NoError(t, err)
+	chdr, err := protoutil.UnmarshalChannelHeader(hdr.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "key", chdr.IdempotencyKey)
+	assert.Equal(t, "mychannel", chdr.ChannelId)
+
+	err = setIdempotencyKey(&pb.Proposal{Header: []byte("garbage")}, "key")
+	assert.Error(t, err)
 }
@@ -40,6 +40,9 @@ func invokeCmd(cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) *cobra.Comma
 	}
 	attachFlags(chaincodeInvokeCmd, flagList)
 
+	chaincodeInvokeCmd.Flags().StringVar(&idempotencyKey, "idempotencyKey", "",
+		"A key identifying the transaction across retries of the invocation. Orderers deduplicating broadcasts by idempotency key order the transaction only once")
+
 	return chaincodeInvokeCmd
 }
 
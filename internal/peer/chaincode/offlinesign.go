@@ -0,0 +1,271 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pcommon "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The offline signing workflow splits an invocation in three steps so that
+// the proposal and the transaction can be signed outside of the peer CLI,
+// such as with a hardware token on an air-gapped machine:
+//
+//   1. build-proposal writes the unsigned proposal
+//   2. build-transaction takes the proposal and its signature, collects the
+//      endorsements and writes the unsigned transaction
+//   3. finalize-transaction takes the transaction and its signature and sends
+//      it for ordering
+//
+// The files written are the exact bytes to sign, and the signatures read are
+// the raw ASN.1 DER signatures of these bytes.
+
+// buildProposalCmd returns the cobra command for building an unsigned proposal
+func buildProposalCmd(cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build-proposal <outputfile>",
+		Short: fmt.Sprintf("Build an unsigned proposal invoking the specified %s.", chainFuncName),
+		Long: fmt.Sprintf("Build an unsigned proposal invoking the specified %s and write it to a file. "+
+			"The content of the file is to be signed offline by the creator of the proposal.", chainFuncName),
+		ValidArgs: []string{"1"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("peer chaincode build-proposal <outputfile>")
+			}
+			return buildProposal(cmd, args[0], cf, cryptoProvider)
+		},
+	}
+	flagList := []string{
+		"name",
+		"ctor",
+		"isInit",
+		"channelID",
+		"creator",
+		"creatorMSPID",
+	}
+	attachFlags(cmd, flagList)
+
+	return cmd
+}
+
+func buildProposal(cmd *cobra.Command, outputFile string, cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) error {
+	if channelID == "" {
+		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+	spec, err := getChaincodeSpec(cmd)
+	if err != nil {
+		return err
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var creator []byte
+	if creatorCertFile != "" {
+		creator, err = readCreator(creatorCertFile)
+		if err != nil {
+			return err
+		}
+	} else {
+		if cf == nil {
+			cf, err = InitCmdFactory(cmd.Name(), false, false, cryptoProvider)
+			if err != nil {
+				return err
+			}
+		}
+		creator, err = cf.Signer.Serialize()
+		if err != nil {
+			return errors.WithMessage(err, "error serializing identity")
+		}
+	}
+
+	var tMap map[string][]byte
+	if transient != "" {
+		if err := json.Unmarshal([]byte(transient), &tMap); err != nil {
+			return errors.Wrap(err, "error parsing transient string")
+		}
+	}
+
+	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
+	prop, txID, err := protoutil.CreateChaincodeProposalWithTxIDAndTransient(pcommon.HeaderType_ENDORSER_TRANSACTION, channelID, invocation, creator, "", tMap)
+	if err != nil {
+		return errors.WithMessage(err, "error creating proposal")
+	}
+
+	if err := ioutil.WriteFile(outputFile, protoutil.MarshalOrPanic(prop), 0644); err != nil {
+		return errors.Wrapf(err, "error writing proposal to %s", outputFile)
+	}
+
+	fmt.Printf("Wrote unsigned proposal for transaction %s to %s successfully\n", txID, outputFile)
+
+	return nil
+}
+
+// readCreator returns the serialized identity of the creator of an offline
+// signed proposal from its certificate.
+func readCreator(certFile string) ([]byte, error) {
+	cert, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading creator certificate %s", certFile)
+	}
+	mspID := creatorMSPID
+	if mspID == "" {
+		mspID = viper.GetString("peer.localMspId")
+	}
+	if mspID == "" {
+		return nil, errors.New("the MSP ID of the creator is empty. Rerun the command with the --creatorMSPID flag")
+	}
+	return protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: cert}), nil
+}
+
+// buildTransactionCmd returns the cobra command for endorsing an offline
+// signed proposal and building the unsigned transaction
+func buildTransactionCmd(cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build-transaction <proposalfile> <signaturefile> <outputfile>",
+		Short: "Endorse an offline signed proposal and build the unsigned transaction.",
+		Long: "Send a proposal built by build-proposal along with its signature to the endorsing peers, " +
+			"and write the unsigned transaction assembled from the endorsements to a file. " +
+			"The content of the file is to be signed offline by the creator of the proposal.",
+		ValidArgs: []string{"3"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 3 {
+				return errors.New("peer chaincode build-transaction <proposalfile> <signaturefile> <outputfile>")
+			}
+			return buildTransaction(cmd, args[0], args[1], args[2], cf, cryptoProvider)
+		},
+	}
+	flagList := []string{
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(cmd, flagList)
+
+	return cmd
+}
+
+func buildTransaction(cmd *cobra.Command, proposalFile, signatureFile, outputFile string, cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) error {
+	propBytes, err := ioutil.ReadFile(proposalFile)
+	if err != nil {
+		return errors.Wrapf(err, "error reading proposal %s", proposalFile)
+	}
+	prop := &pb.Proposal{}
+	if err := proto.Unmarshal(propBytes, prop); err != nil {
+		return errors.Wrapf(err, "error unmarshaling proposal %s", proposalFile)
+	}
+	signature, err := ioutil.ReadFile(signatureFile)
+	if err != nil {
+		return errors.Wrapf(err, "error reading signature %s", signatureFile)
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, false, cryptoProvider)
+		if err != nil {
+			return err
+		}
+	}
+
+	signedProp := &pb.SignedProposal{ProposalBytes: propBytes, Signature: signature}
+	responses, err := processProposals(cf.EndorserClients, signedProp)
+	if err != nil {
+		return errors.WithMessage(err, "error endorsing proposal")
+	}
+	if len(responses) == 0 {
+		// this should only happen if some new code has introduced a bug
+		return errors.New("no proposal responses received - this might indicate a bug")
+	}
+	for _, resp := range responses {
+		if resp.Response.Status >= shim.ERRORTHRESHOLD || resp.Endorsement == nil {
+			return errors.Errorf("endorsement failure during build-transaction. response: %v", resp.Response)
+		}
+	}
+
+	payl, err := protoutil.CreateUnsignedTx(prop, responses...)
+	if err != nil {
+		return errors.WithMessage(err, "could not assemble transaction")
+	}
+
+	if err := ioutil.WriteFile(outputFile, protoutil.MarshalOrPanic(payl), 0644); err != nil {
+		return errors.Wrapf(err, "error writing transaction to %s", outputFile)
+	}
+
+	fmt.Printf("Wrote unsigned transaction to %s successfully\n", outputFile)
+
+	return nil
+}
+
+// finalizeTransactionCmd returns the cobra command for sending an offline
+// signed transaction for ordering
+func finalizeTransactionCmd(cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "finalize-transaction <transactionfile> <signaturefile>",
+		Short: "Send an offline signed transaction for ordering.",
+		Long: "Combine a transaction built by build-transaction with its signature " +
+			"and send the resulting envelope to the orderer passed with the -o flag.",
+		ValidArgs: []string{"2"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("peer chaincode finalize-transaction <transactionfile> <signaturefile>")
+			}
+			return finalizeTransaction(cmd, args[0], args[1], cf, cryptoProvider)
+		},
+	}
+
+	return cmd
+}
+
+func finalizeTransaction(cmd *cobra.Command, transactionFile, signatureFile string, cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) error {
+	paylBytes, err := ioutil.ReadFile(transactionFile)
+	if err != nil {
+		return errors.Wrapf(err, "error reading transaction %s", transactionFile)
+	}
+	payl, err := protoutil.UnmarshalPayload(paylBytes)
+	if err != nil {
+		return errors.WithMessagef(err, "error unmarshaling transaction %s", transactionFile)
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payl.Header.GetChannelHeader())
+	if err != nil {
+		return errors.WithMessagef(err, "error unmarshaling channel header of transaction %s", transactionFile)
+	}
+	signature, err := ioutil.ReadFile(signatureFile)
+	if err != nil {
+		return errors.Wrapf(err, "error reading signature %s", signatureFile)
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), false, true, cryptoProvider)
+		if err != nil {
+			return err
+		}
+	}
+	defer cf.BroadcastClient.Close()
+
+	env := &pcommon.Envelope{Payload: paylBytes, Signature: signature}
+	if err := cf.BroadcastClient.Send(env); err != nil {
+		return errors.WithMessagef(err, "error sending transaction %s", chdr.TxId)
+	}
+
+	logger.Infof("Transaction %s sent for ordering on channel %s", chdr.TxId, chdr.ChannelId)
+
+	return nil
+}
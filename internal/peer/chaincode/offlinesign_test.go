@@ -0,0 +1,271 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestOfflineSigning(t *testing.T) {
+	defer resetFlags()
+	resetFlags()
+
+	dir, err := ioutil.TempDir("", "offlinesign")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	mockCF, err := getMockChaincodeCmdFactory()
+	require.NoError(t, err)
+	// the signer of the mock factory stands for the offline signer
+	signer := mockCF.Signer
+
+	proposalFile := filepath.Join(dir, "proposal")
+	cmd := buildProposalCmd(mockCF, cryptoProvider)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-c", "{\"Args\": [\"invoke\",\"a\",\"b\",\"10\"]}", "-C", "mychannel", proposalFile})
+	require.NoError(t, cmd.Execute())
+
+	propBytes, err := ioutil.ReadFile(proposalFile)
+	require.NoError(t, err)
+	prop := &pb.Proposal{}
+	require.NoError(t, proto.Unmarshal(propBytes, prop))
+	hdr, err := protoutil.UnmarshalHeader(prop.Header)
+	require.NoError(t, err)
+	shdr, err := protoutil.UnmarshalSignatureHeader(hdr.SignatureHeader)
+	require.NoError(t, err)
+	creator, err := signer.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, creator, shdr.Creator)
+
+	propSignatureFile := filepath.Join(dir, "proposal.sig")
+	propSignature, err := signer.Sign(propBytes)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(propSignatureFile, propSignature, 0644))
+
+	var endorsed []*pb.SignedProposal
+	mockCF.EndorserClients = []pb.EndorserClient{
+		&recordingEndorser{EndorserClient: mockCF.EndorserClients[0], signedProposals: &endorsed},
+	}
+	transactionFile := filepath.Join(dir, "transaction")
+	cmd = buildTransactionCmd(mockCF, cryptoProvider)
+	addFlags(cmd)
+	cmd.SetArgs([]string{proposalFile, propSignatureFile, transactionFile})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, []*pb.SignedProposal{{ProposalBytes: propBytes, Signature: propSignature}}, endorsed)
+
+	paylBytes, err := ioutil.ReadFile(transactionFile)
+	require.NoError(t, err)
+	payl, err := protoutil.UnmarshalPayload(paylBytes)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(hdr, payl.Header))
+
+	txSignatureFile := filepath.Join(dir, "transaction.sig")
+	txSignature, err := signer.Sign(paylBytes)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(txSignatureFile, txSignature, 0644))
+
+	var sent *cb.Envelope
+	mockCF.BroadcastClient = &recordingBroadcastClient{sent: &sent}
+	cmd = finalizeTransactionCmd(mockCF, cryptoProvider)
+	addFlags(cmd)
+	cmd.SetArgs([]string{transactionFile, txSignatureFile})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, &cb.Envelope{Payload: paylBytes, Signature: txSignature}, sent)
+
+	// the envelope is the one the signer would have produced online
+	env, err := protoutil.CreateSignedTx(prop, signer, &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Endorsement: &pb.Endorsement{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, env.Payload, sent.Payload)
+}
+
+func TestBuildProposalWithCreator(t *testing.T) {
+	defer resetFlags()
+	resetFlags()
+
+	dir, err := ioutil.TempDir("", "offlinesign")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, []byte("certificate"), 0644))
+	proposalFile := filepath.Join(dir, "proposal")
+
+	// no factory is needed when the creator is passed
+	cmd := buildProposalCmd(nil, cryptoProvider)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-c", "{\"Args\": [\"query\",\"a\"]}", "-C", "mychannel", "--creator", certFile, "--creatorMSPID", "Org1MSP", proposalFile})
+	require.NoError(t, cmd.Execute())
+
+	propBytes, err := ioutil.ReadFile(proposalFile)
+	require.NoError(t, err)
+	prop := &pb.Proposal{}
+	require.NoError(t, proto.Unmarshal(propBytes, prop))
+	hdr, err := protoutil.UnmarshalHeader(prop.Header)
+	require.NoError(t, err)
+	shdr, err := protoutil.UnmarshalSignatureHeader(hdr.SignatureHeader)
+	require.NoError(t, err)
+	id := &mspproto.SerializedIdentity{}
+	require.NoError(t, proto.Unmarshal(shdr.Creator, id))
+	require.Equal(t, "Org1MSP", id.Mspid)
+	require.Equal(t, []byte("certificate"), id.IdBytes)
+
+	resetFlags()
+	cmd = buildProposalCmd(nil, cryptoProvider)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "--creator", certFile, proposalFile})
+	require.EqualError(t, cmd.Execute(), "The required parameter 'channelID' is empty. Rerun the command with -C flag")
+
+	cmd = buildProposalCmd(nil, cryptoProvider)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-c", "{\"Args\": [\"query\",\"a\"]}", "-C", "mychannel", "--creator", filepath.Join(dir, "missing.pem"), proposalFile})
+	err = cmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error reading creator certificate")
+}
+
+func TestBuildTransactionFailures(t *testing.T) {
+	defer resetFlags()
+	resetFlags()
+
+	dir, err := ioutil.TempDir("", "offlinesign")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	proposalFile := filepath.Join(dir, "proposal")
+	signatureFile := filepath.Join(dir, "proposal.sig")
+	transactionFile := filepath.Join(dir, "transaction")
+	require.NoError(t, ioutil.WriteFile(signatureFile, []byte("signature"), 0644))
+
+	buildTransaction := func(cf *ChaincodeCmdFactory) error {
+		cmd := buildTransactionCmd(cf, cryptoProvider)
+		addFlags(cmd)
+		cmd.SetArgs([]string{proposalFile, signatureFile, transactionFile})
+		return cmd.Execute()
+	}
+
+	err = buildTransaction(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error reading proposal")
+
+	require.NoError(t, ioutil.WriteFile(proposalFile, []byte("garbage"), 0644))
+	err = buildTransaction(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error unmarshaling proposal")
+
+	prop, _, err := protoutil.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", createCIS(), []byte("creator"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(proposalFile, protoutil.MarshalOrPanic(prop), 0644))
+
+	mockCF, err := getMockChaincodeCmdFactoryEndorsementFailure(500, []byte("failed"))
+	require.NoError(t, err)
+	err = buildTransaction(mockCF)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "endorsement failure during build-transaction")
+
+	mockCF, err = getMockChaincodeCmdFactoryWithErr()
+	require.NoError(t, err)
+	err = buildTransaction(mockCF)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error endorsing proposal")
+
+	_, err = os.Stat(transactionFile)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFinalizeTransactionFailures(t *testing.T) {
+	defer resetFlags()
+	resetFlags()
+
+	dir, err := ioutil.TempDir("", "offlinesign")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	transactionFile := filepath.Join(dir, "transaction")
+	signatureFile := filepath.Join(dir, "transaction.sig")
+	require.NoError(t, ioutil.WriteFile(transactionFile, []byte("garbage"), 0644))
+
+	finalizeTransaction := func(cf *ChaincodeCmdFactory) error {
+		cmd := finalizeTransactionCmd(cf, cryptoProvider)
+		addFlags(cmd)
+		cmd.SetArgs([]string{transactionFile, signatureFile})
+		return cmd.Execute()
+	}
+
+	err = finalizeTransaction(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error unmarshaling transaction")
+
+	payl := &cb.Payload{
+		Header: protoutil.MakePayloadHeader(
+			protoutil.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0),
+			protoutil.MakeSignatureHeader([]byte("creator"), []byte("nonce")),
+		),
+	}
+	require.NoError(t, ioutil.WriteFile(transactionFile, protoutil.MarshalOrPanic(payl), 0644))
+	err = finalizeTransaction(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error reading signature")
+
+	require.NoError(t, ioutil.WriteFile(signatureFile, []byte("signature"), 0644))
+	mockCF, err := getMockChaincodeCmdFactory()
+	require.NoError(t, err)
+	mockCF.BroadcastClient = common.GetMockBroadcastClient(errors.New("orderer unavailable"))
+	err = finalizeTransaction(mockCF)
+	require.EqualError(t, err, "error sending transaction : orderer unavailable")
+}
+
+type recordingEndorser struct {
+	pb.EndorserClient
+	signedProposals *[]*pb.SignedProposal
+}
+
+func (r *recordingEndorser) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	*r.signedProposals = append(*r.signedProposals, in)
+	return r.EndorserClient.ProcessProposal(ctx, in, opts...)
+}
+
+type recordingBroadcastClient struct {
+	sent **cb.Envelope
+}
+
+func (r *recordingBroadcastClient) Send(env *cb.Envelope) error {
+	*r.sent = env
+	return nil
+}
+
+func (r *recordingBroadcastClient) Close() error {
+	return nil
+}
@@ -41,6 +41,8 @@ func queryCmd(cf *ChaincodeCmdFactory, cryptoProvider bccsp.BCCSP) *cobra.Comman
 		"If true, output the query value as raw bytes, otherwise format as a printable string")
 	chaincodeQueryCmd.Flags().BoolVarP(&chaincodeQueryHex, "hex", "x", false,
 		"If true, output the query value byte array in hexadecimal. Incompatible with --raw")
+	chaincodeQueryCmd.Flags().StringVar(&minStateVersion, "minStateVersion", "",
+		"The state version, as logged by a previous query, the ledger of the peers must be at least as fresh as. Peers behind it reject the query")
 
 	return chaincodeQueryCmd
 }
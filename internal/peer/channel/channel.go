@@ -7,7 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
-	"strings"
+	"net"
 	"time"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -36,13 +36,27 @@ var (
 	genesisBlockPath string
 
 	// create related variables
-	channelID     string
-	channelTxFile string
-	outputBlock   string
-	timeout       time.Duration
+	channelID       string
+	channelTxFile   string
+	outputBlock     string
+	timeout         time.Duration
+	consortium      string
+	channelTemplate string
 
 	// fetch related variables
 	bestEffort bool
+
+	// simulatepolicy related variables
+	simulateAction     string
+	simulatePolicyPath string
+	simulateSigners    []string
+
+	// membership related variables
+	outputFormat string
+
+	// delegatetoken related variables
+	tokenSubject string
+	tokenTTL     time.Duration
 )
 
 // Cmd returns the cobra command for Node
@@ -56,6 +70,10 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 	channelCmd.AddCommand(updateCmd(cf))
 	channelCmd.AddCommand(signconfigtxCmd(cf))
 	channelCmd.AddCommand(getinfoCmd(cf))
+	channelCmd.AddCommand(simulatepolicyCmd(cf))
+	channelCmd.AddCommand(viewconfigCmd(cf))
+	channelCmd.AddCommand(membershipCmd(cf))
+	channelCmd.AddCommand(delegatetokenCmd(cf))
 
 	return channelCmd
 }
@@ -80,7 +98,15 @@ func resetFlags() {
 	flags.StringVarP(&channelTxFile, "file", "f", "", "Configuration transaction file generated by a tool such as configtxgen for submitting to orderer")
 	flags.StringVarP(&outputBlock, "outputBlock", "", common.UndefinedParamValue, `The path to write the genesis block for the channel. (default ./<channelID>.block)`)
 	flags.DurationVarP(&timeout, "timeout", "t", 10*time.Second, "Channel creation timeout")
+	flags.StringVarP(&consortium, "consortium", "", "", "The consortium to create the channel for, when creating it from a channel template")
+	flags.StringVarP(&channelTemplate, "template", "", "", "The name of the channel template of the consortium, defined in the ordering system channel, to create the channel from")
 	flags.BoolVarP(&bestEffort, "bestEffort", "", false, "Whether fetch requests should ignore errors and return blocks on a best effort basis")
+	flags.StringVarP(&simulateAction, "action", "", "policy", "The action to simulate: config-update, chaincode-commit or policy")
+	flags.StringVarP(&simulatePolicyPath, "policy", "", "", "The fully qualified path of the policy to evaluate, e.g. /Channel/Application/Admins")
+	flags.StringArrayVarP(&simulateSigners, "signer", "", nil, "A signer of the form MSPID:path/to/cert.pem; can be repeated")
+	flags.StringVarP(&outputFormat, "outputFormat", "", "text", "The output format of the membership: text or json")
+	flags.StringVarP(&tokenSubject, "subject", "", "", "The name of the bearer the delegation token is issued to, recorded for auditing")
+	flags.DurationVarP(&tokenTTL, "ttl", "", time.Hour, "The lifetime of the delegation token")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
@@ -96,8 +122,8 @@ func attachFlags(cmd *cobra.Command, names []string) {
 
 var channelCmd = &cobra.Command{
 	Use:   "channel",
-	Short: "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
-	Long:  "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
+	Short: "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo|simulatepolicy|viewconfig|membership|delegatetoken.",
+	Long:  "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo|simulatepolicy|viewconfig|membership|delegatetoken.",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		common.InitCmd(cmd, args)
 		common.SetOrdererEnv(cmd, args)
@@ -162,7 +188,7 @@ func InitCmdFactory(isEndorserRequired, isPeerDeliverRequired, isOrdererRequired
 
 	// for create and fetch, we need the orderer as well
 	if isOrdererRequired {
-		if len(strings.Split(common.OrderingEndpoint, ":")) != 2 {
+		if !isHostPort(common.OrderingEndpoint) {
 			return nil, errors.Errorf("ordering service endpoint %s is not valid or missing", common.OrderingEndpoint)
 		}
 		cf.DeliverClient, err = common.NewDeliverClientForOrderer(channelID, cf.Signer, bestEffort)
@@ -174,3 +200,10 @@ func InitCmdFactory(isEndorserRequired, isPeerDeliverRequired, isOrdererRequired
 	logger.Infof("Endorser and orderer connections initialized")
 	return cf, nil
 }
+
+// isHostPort returns whether the endpoint is in host:port format, with the
+// host of IPv6 addresses enclosed in square brackets.
+func isHostPort(endpoint string) bool {
+	_, port, err := net.SplitHostPort(endpoint)
+	return err == nil && port != ""
+}
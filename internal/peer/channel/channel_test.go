@@ -20,3 +20,13 @@ func TestInitCmdFactory(t *testing.T) {
 		assert.Contains(t, err.Error(), "ERROR - only a single deliver source is currently supported")
 	})
 }
+
+func TestIsHostPort(t *testing.T) {
+	assert.True(t, isHostPort("orderer.example.com:7050"))
+	assert.True(t, isHostPort("10.0.0.1:7050"))
+	assert.True(t, isHostPort("[2001:db8::1]:7050"))
+	assert.False(t, isHostPort(""))
+	assert.False(t, isHostPort("orderer.example.com"))
+	assert.False(t, isHostPort("orderer.example.com:"))
+	assert.False(t, isHostPort("2001:db8::1:7050"))
+}
@@ -52,6 +52,8 @@ func createCmd(cf *ChannelCmdFactory) *cobra.Command {
 		"file",
 		"outputBlock",
 		"timeout",
+		"consortium",
+		"template",
 	}
 	attachFlags(createCmd, flagList)
 
@@ -71,6 +73,11 @@ func createChannelFromDefaults(cf *ChannelCmdFactory) (*cb.Envelope, error) {
 	return chCrtEnv, nil
 }
 
+func createChannelFromTemplate() (*cb.Envelope, error) {
+	// the transaction is signed along with the sanity check
+	return encoder.MakeTemplatedChannelCreationTransaction(channelID, nil, consortium, channelTemplate)
+}
+
 func createChannelFromConfigTx(configTxFileName string) (*cb.Envelope, error) {
 	cftx, err := ioutil.ReadFile(configTxFileName)
 	if err != nil {
@@ -145,6 +152,10 @@ func sendCreateChainTransaction(cf *ChannelCmdFactory) error {
 		if chCrtEnv, err = createChannelFromConfigTx(channelTxFile); err != nil {
 			return err
 		}
+	} else if channelTemplate != "" {
+		if chCrtEnv, err = createChannelFromTemplate(); err != nil {
+			return err
+		}
 	} else {
 		if chCrtEnv, err = createChannelFromDefaults(cf); err != nil {
 			return err
@@ -226,6 +237,10 @@ func create(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
 		return errors.New("must supply channel ID")
 	}
 
+	if channelTemplate != "" && channelTxFile != "" {
+		return errors.New("a channel creation transaction file and a channel template cannot both be supplied")
+	}
+
 	// Parsing of the command line is done so silence cmd usage
 	cmd.SilenceUsage = true
 
@@ -22,6 +22,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/internal/peer/common"
 	"github.com/hyperledger/fabric/internal/peer/common/mock"
@@ -510,6 +512,66 @@ func TestCreateChainFromTx(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+type capturingBroadcastClient struct {
+	envelopes []*cb.Envelope
+}
+
+func (c *capturingBroadcastClient) Send(env *cb.Envelope) error {
+	c.envelopes = append(c.envelopes, env)
+	return nil
+}
+
+func (c *capturingBroadcastClient) Close() error {
+	return nil
+}
+
+func TestCreateChainFromTemplate(t *testing.T) {
+	defer resetFlags()
+	InitMSP()
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+
+	mockchannel := "mockchannel"
+	defer os.Remove(mockchannel + ".block")
+
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		t.Fatalf("Get default signer error: %v", err)
+	}
+	broadcastClient := &capturingBroadcastClient{}
+	mockCF := &ChannelCmdFactory{
+		BroadcastFactory: func() (common.BroadcastClient, error) {
+			return broadcastClient, nil
+		},
+		Signer:        signer,
+		DeliverClient: &mockDeliverClient{},
+	}
+
+	cmd := createCmd(mockCF)
+	AddFlags(cmd)
+
+	args := []string{"-c", mockchannel, "--consortium", "SampleConsortium", "--template", "gold", "-f", "channel.tx", "-o", "localhost:7050"}
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	assert.EqualError(t, err, "a channel creation transaction file and a channel template cannot both be supplied")
+
+	args = []string{"-c", mockchannel, "--consortium", "SampleConsortium", "--template", "gold", "-f", "", "-o", "localhost:7050"}
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	assert.NoError(t, err)
+
+	assert.Len(t, broadcastClient.envelopes, 1)
+	payload, err := protoutil.UnmarshalPayload(broadcastClient.envelopes[0].Payload)
+	assert.NoError(t, err)
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	assert.NoError(t, err)
+	assert.Len(t, configUpdateEnv.Signatures, 1)
+	configUpdate, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	assert.NoError(t, err)
+	assert.Equal(t, mockchannel, configUpdate.ChannelId)
+	assert.Equal(t, []byte("gold"), configUpdate.IsolatedData[channelconfig.ChannelTemplateIsolatedDataKey])
+}
+
 func TestCreateChainInvalidTx(t *testing.T) {
 	defer resetFlags()
 
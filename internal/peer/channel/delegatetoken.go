@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/common/deliver"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func delegatetokenCmd(cf *ChannelCmdFactory) *cobra.Command {
+	delegatetokenCmd := &cobra.Command{
+		Use:   "delegatetoken",
+		Short: "Issues a delegation token for the deliver service of a channel.",
+		Long: "Issues a short-lived delegation token, signed by the local identity, which grants its bearer the access of the local identity " +
+			"to the deliver service of the channel until it expires. The token is printed to stdout, to be presented by the deliver clients " +
+			"as the 'authorization: Bearer <token>' gRPC metadata. Requires '-c'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return delegateToken(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"subject",
+		"ttl",
+	}
+	attachFlags(delegatetokenCmd, flagList)
+
+	return delegatetokenCmd
+}
+
+func delegateToken(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("must supply channel ID")
+	}
+	if tokenTTL <= 0 {
+		return errors.Errorf("invalid token lifetime %s", tokenTTL)
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserNotRequired, PeerDeliverNotRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	token, err := deliver.CreateDelegationToken(cf.Signer, channelID, &deliver.DelegationClaims{
+		Subject:   tokenSubject,
+		ExpiresAt: time.Now().Add(tokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
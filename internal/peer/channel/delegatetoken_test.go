@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegateToken(t *testing.T) {
+	InitMSP()
+
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+	mockCF := &ChannelCmdFactory{
+		Signer: signer,
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name: "success",
+			args: []string{"-c", mockChannel, "--subject", "gateway", "--ttl", "10m"},
+		},
+		{
+			name:        "missing channel",
+			args:        []string{"--subject", "gateway"},
+			expectedErr: "must supply channel ID",
+		},
+		{
+			name:        "invalid lifetime",
+			args:        []string{"-c", mockChannel, "--ttl", "0s"},
+			expectedErr: "invalid token lifetime 0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+
+			cmd := delegatetokenCmd(mockCF)
+			AddFlags(cmd)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strconv"
-	"strings"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -50,7 +49,7 @@ func fetch(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
 	// default to fetching from orderer
 	ordererRequired := OrdererRequired
 	peerDeliverRequired := PeerDeliverNotRequired
-	if len(strings.Split(common.OrderingEndpoint, ":")) != 2 {
+	if !isHostPort(common.OrderingEndpoint) {
 		// if no orderer endpoint supplied, connect to peer's deliver service
 		ordererRequired = OrdererNotRequired
 		peerDeliverRequired = PeerDeliverRequired
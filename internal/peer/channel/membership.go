@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/scc/qscc"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/configview"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func membershipCmd(cf *ChannelCmdFactory) *cobra.Command {
+	membershipCmd := &cobra.Command{
+		Use:   "membership [blockNumber]",
+		Short: "Prints the membership of a channel at a block.",
+		Long: "Prints the organizations of a channel, with their MSP IDs, root certificates and anchor peer and orderer " +
+			"endpoints, as defined by the channel config in effect at the given block. Requires '-c' and a block number " +
+			"to query the peer, or '-b' with a config block to read it offline.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return membership(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"blockpath",
+		"outputFormat",
+	}
+	attachFlags(membershipCmd, flagList)
+
+	return membershipCmd
+}
+
+func (cc *endorserClient) getChannelMembership(blockNumber uint64) ([]byte, error) {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "qscc"},
+			Input: &pb.ChaincodeInput{Args: [][]byte{
+				[]byte(qscc.GetChannelMembership),
+				[]byte(channelID),
+				[]byte(strconv.FormatUint(blockNumber, 10)),
+			}},
+		},
+	}
+
+	c, _ := cc.cf.Signer.Serialize()
+	prop, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", invocation, c)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create proposal")
+	}
+
+	signedProp, err := protoutil.GetSignedProposal(prop, cc.cf.Signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create signed proposal")
+	}
+
+	proposalResp, err := cc.cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed sending proposal")
+	}
+
+	if proposalResp.Response == nil || proposalResp.Response.Status != 200 {
+		return nil, errors.Errorf("received bad response, status %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	return proposalResp.Response.Payload, nil
+}
+
+func membership(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue && genesisBlockPath == common.UndefinedParamValue {
+		return errors.New("must supply channel ID or config block path")
+	}
+	if outputFormat != "text" && outputFormat != "json" {
+		return errors.Errorf("unknown output format %s", outputFormat)
+	}
+
+	m := &configview.Membership{}
+	if genesisBlockPath != common.UndefinedParamValue {
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		blockBytes, err := ioutil.ReadFile(genesisBlockPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to read config block")
+		}
+		block, err := protoutil.UnmarshalBlock(blockBytes)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal config block")
+		}
+		config, err := configview.ConfigFromBlock(block)
+		if err != nil {
+			return err
+		}
+		if m, err = configview.MembershipFromConfig(config); err != nil {
+			return err
+		}
+		m.BlockNumber = block.Header.Number
+		m.ConfigBlockNumber = block.Header.Number
+	} else {
+		if len(args) != 1 {
+			return errors.New("must supply the block number")
+		}
+		blockNumber, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return errors.Errorf("invalid block number %s", args[0])
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+
+		if cf == nil {
+			cf, err = InitCmdFactory(EndorserRequired, PeerDeliverNotRequired, OrdererNotRequired)
+			if err != nil {
+				return err
+			}
+		}
+		client := &endorserClient{cf}
+		payload, err := client.getChannelMembership(blockNumber)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(payload, m); err != nil {
+			return errors.Wrap(err, "failed to decode the channel membership")
+		}
+	}
+
+	if outputFormat == "json" {
+		out, err := json.MarshalIndent(m, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	printMembership(os.Stdout, m)
+	return nil
+}
+
+func printMembership(w io.Writer, m *configview.Membership) {
+	if m.ChannelID != "" {
+		fmt.Fprintf(w, "Channel: %s\n", m.ChannelID)
+	}
+	fmt.Fprintf(w, "Block: %d\n", m.BlockNumber)
+	fmt.Fprintf(w, "Config block: %d (sequence %d)\n", m.ConfigBlockNumber, m.ConfigSequence)
+	if len(m.OrdererAddresses) > 0 {
+		fmt.Fprintf(w, "Orderer addresses: %s\n", strings.Join(m.OrdererAddresses, ", "))
+	}
+	for _, org := range m.Organizations {
+		fmt.Fprintf(w, "\nOrganization %s (%s)\n", org.Name, org.Group)
+		fmt.Fprintf(w, "  MSP ID: %s\n", org.MSPID)
+		for _, cert := range org.RootCerts {
+			fmt.Fprintf(w, "  Root certificate: %s, serial %s, valid until %s\n", cert.Subject, cert.SerialNumber, cert.NotAfter.Format("2006-01-02"))
+		}
+		for _, cert := range org.TLSRootCerts {
+			fmt.Fprintf(w, "  TLS root certificate: %s, serial %s, valid until %s\n", cert.Subject, cert.SerialNumber, cert.NotAfter.Format("2006-01-02"))
+		}
+		if len(org.AnchorPeers) > 0 {
+			fmt.Fprintf(w, "  Anchor peers: %s\n", strings.Join(org.AnchorPeers, ", "))
+		}
+		if len(org.OrdererEndpoints) > 0 {
+			fmt.Fprintf(w, "  Orderer endpoints: %s\n", strings.Join(org.OrdererEndpoints, ", "))
+		}
+	}
+}
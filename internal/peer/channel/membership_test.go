@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/configview"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMembership(t *testing.T) {
+	InitMSP()
+
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	payload := []byte(`{"channel_id": "mockchannel", "block_number": 12, "config_block_number": 10, "organizations": [{"name": "Org1", "group": "Application", "msp_id": "Org1MSP"}]}`)
+	mockCF := &ChannelCmdFactory{
+		EndorserClient: common.GetMockEndorserClient(&pb.ProposalResponse{
+			Response:    &pb.Response{Status: 200, Payload: payload},
+			Endorsement: &pb.Endorsement{},
+		}, nil),
+		Signer: signer,
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing channel and block",
+			args:        []string{},
+			expectedErr: "must supply channel ID or config block path",
+		},
+		{
+			name:        "missing block number",
+			args:        []string{"-c", mockChannel},
+			expectedErr: "must supply the block number",
+		},
+		{
+			name:        "invalid block number",
+			args:        []string{"-c", mockChannel, "newest"},
+			expectedErr: "invalid block number newest",
+		},
+		{
+			name:        "unknown output format",
+			args:        []string{"-c", mockChannel, "12", "--outputFormat", "yaml"},
+			expectedErr: "unknown output format yaml",
+		},
+		{
+			name: "text output",
+			args: []string{"-c", mockChannel, "12"},
+		},
+		{
+			name: "json output",
+			args: []string{"-c", mockChannel, "12", "--outputFormat", "json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+
+			cmd := membershipCmd(mockCF)
+			AddFlags(cmd)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+
+	resetFlags()
+	mockCF.EndorserClient = common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response:    &pb.Response{Status: 500, Message: "access denied"},
+		Endorsement: &pb.Endorsement{},
+	}, nil)
+	cmd := membershipCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel, "12"})
+	assert.EqualError(t, cmd.Execute(), "received bad response, status 500: access denied")
+}
+
+func TestMembershipOffline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "membershiptest-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configEnv := &cb.ConfigEnvelope{
+		Config: &cb.Config{Sequence: 1, ChannelGroup: protoutil.NewConfigGroup()},
+	}
+	payload := &cb.Payload{
+		Header: protoutil.MakePayloadHeader(protoutil.MakeChannelHeader(cb.HeaderType_CONFIG, 0, "mychannel", 0), &cb.SignatureHeader{}),
+		Data:   protoutil.MarshalOrPanic(configEnv),
+	}
+	block := protoutil.NewBlock(3, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(&cb.Envelope{Payload: protoutil.MarshalOrPanic(payload)})}
+	configBlockPath := filepath.Join(dir, "config.block")
+	require.NoError(t, ioutil.WriteFile(configBlockPath, protoutil.MarshalOrPanic(block), 0644))
+
+	resetFlags()
+	cmd := membershipCmd(nil)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-b", configBlockPath})
+	assert.NoError(t, cmd.Execute())
+
+	resetFlags()
+	cmd = membershipCmd(nil)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-b", filepath.Join(dir, "missing.block")})
+	assert.EqualError(t, cmd.Execute(), "failed to read config block: open "+filepath.Join(dir, "missing.block")+": no such file or directory")
+}
+
+func TestPrintMembership(t *testing.T) {
+	m := &configview.Membership{
+		ChannelID:         "mychannel",
+		BlockNumber:       12,
+		ConfigBlockNumber: 10,
+		ConfigSequence:    4,
+		OrdererAddresses:  []string{"orderer0:7050"},
+		Organizations: []*configview.Organization{
+			{
+				Name:  "Org1",
+				Group: "Application",
+				MSPID: "Org1MSP",
+				RootCerts: []*configview.Certificate{{
+					Subject:      "CN=ca.org1.example.com",
+					SerialNumber: "42",
+					NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+				}},
+				AnchorPeers: []string{"peer0.org1:7051"},
+			},
+			{
+				Name:             "OrdererOrg",
+				Group:            "Orderer",
+				MSPID:            "OrdererMSP",
+				OrdererEndpoints: []string{"orderer1:7050", "orderer2:7050"},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	printMembership(buf, m)
+	assert.Equal(t, `Channel: mychannel
+Block: 12
+Config block: 10 (sequence 4)
+Orderer addresses: orderer0:7050
+
+Organization Org1 (Application)
+  MSP ID: Org1MSP
+  Root certificate: CN=ca.org1.example.com, serial 42, valid until 2030-01-01
+  Anchor peers: peer0.org1:7051
+
+Organization OrdererOrg (Orderer)
+  MSP ID: OrdererMSP
+  Orderer endpoints: orderer1:7050, orderer2:7050
+`, buf.String())
+}
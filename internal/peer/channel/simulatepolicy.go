@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/policies/simulator"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func simulatepolicyCmd(cf *ChannelCmdFactory) *cobra.Command {
+	simulatepolicyCmd := &cobra.Command{
+		Use:   "simulatepolicy",
+		Short: "Evaluates channel policies offline against a set of signers.",
+		Long: "Evaluates the channel policies required by an action against a set of signer certificates, without submitting anything, " +
+			"and reports which policies pass or fail and which signatures are missing. Requires '-b' with a config block of the channel. " +
+			"The action is one of 'config-update' (requires '-f'), 'chaincode-commit' or 'policy' (requires '--policy').",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return simulatePolicy(cmd, args)
+		},
+	}
+	flagList := []string{
+		"blockpath",
+		"file",
+		"action",
+		"policy",
+		"signer",
+	}
+	attachFlags(simulatepolicyCmd, flagList)
+
+	return simulatepolicyCmd
+}
+
+func simulatePolicy(cmd *cobra.Command, args []string) error {
+	if genesisBlockPath == common.UndefinedParamValue {
+		return errors.New("must supply config block path")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	blockBytes, err := ioutil.ReadFile(genesisBlockPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read config block")
+	}
+	block, err := protoutil.UnmarshalBlock(blockBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed to unmarshal config block")
+	}
+
+	sim, err := simulator.NewFromConfigBlock(block, factory.GetDefault())
+	if err != nil {
+		return err
+	}
+
+	signers, err := loadSigners(simulateSigners)
+	if err != nil {
+		return err
+	}
+
+	var report *simulator.Report
+	switch simulateAction {
+	case simulator.ActionConfigUpdate:
+		if channelTxFile == "" {
+			return errors.New("must supply config update file for action config-update")
+		}
+		configUpdate, updateSigners, err := loadConfigUpdate(channelTxFile)
+		if err != nil {
+			return err
+		}
+		report, err = sim.SimulateConfigUpdate(configUpdate, append(updateSigners, signers...))
+		if err != nil {
+			return err
+		}
+	case simulator.ActionChaincodeCommit:
+		report, err = sim.SimulateChaincodeCommit(signers)
+		if err != nil {
+			return err
+		}
+	case simulator.ActionPolicy:
+		if simulatePolicyPath == "" {
+			return errors.New("must supply policy path for action policy")
+		}
+		report, err = sim.EvaluatePolicy(simulatePolicyPath, signers)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unknown action '%s'", simulateAction)
+	}
+
+	output, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal simulation report")
+	}
+	fmt.Println(string(output))
+	return nil
+}
+
+// loadSigners reads signer specifications of the form MSPID:path/to/cert.pem
+// and returns the corresponding serialized identities.
+func loadSigners(specs []string) ([][]byte, error) {
+	var signers [][]byte
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid signer '%s', expected MSPID:path/to/cert.pem", spec)
+		}
+		cert, err := ioutil.ReadFile(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read certificate for signer '%s'", spec)
+		}
+		signers = append(signers, protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{
+			Mspid:   parts[0],
+			IdBytes: cert,
+		}))
+	}
+	return signers, nil
+}
+
+// loadConfigUpdate reads a config update envelope, as produced by configtxlator
+// or signed by 'peer channel signconfigtx', and returns the config update along
+// with the identities of the signatures already attached to it.
+func loadConfigUpdate(path string) (*cb.ConfigUpdate, [][]byte, error) {
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, ConfigTxFileNotFound(err.Error())
+	}
+
+	env, err := protoutil.UnmarshalEnvelope(fileData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, nil, InvalidCreateTx("bad payload")
+	}
+
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	if err != nil {
+		return nil, nil, InvalidCreateTx("Bad config update env")
+	}
+
+	configUpdate, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	if err != nil {
+		return nil, nil, InvalidCreateTx("Bad config update")
+	}
+
+	var signers [][]byte
+	for _, configSig := range configUpdateEnv.Signatures {
+		sigHeader := &cb.SignatureHeader{}
+		if err := proto.Unmarshal(configSig.SignatureHeader, sigHeader); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to unmarshal signature header")
+		}
+		signers = append(signers, sigHeader.Creator)
+	}
+
+	return configUpdate, signers, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatePolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simulatepolicytest-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	blockPath := filepath.Join(dir, "normal.block")
+	require.NoError(t, ioutil.WriteFile(blockPath, protoutil.MarshalOrPanic(&cb.Block{}), 0644))
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing block",
+			args:        []string{"--policy", "/Channel/Application/Readers"},
+			expectedErr: "must supply config block path",
+		},
+		{
+			name:        "nonexistent block",
+			args:        []string{"-b", filepath.Join(dir, "missing.block"), "--policy", "/Channel/Application/Readers"},
+			expectedErr: "failed to read config block: open " + filepath.Join(dir, "missing.block") + ": no such file or directory",
+		},
+		{
+			name:        "not a config block",
+			args:        []string{"-b", blockPath, "--policy", "/Channel/Application/Readers"},
+			expectedErr: "block is not a config block",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+
+			cmd := simulatepolicyCmd(nil)
+			AddFlags(cmd)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestLoadSigners(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simulatepolicytest-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certPath := filepath.Join(dir, "cert.pem")
+	require.NoError(t, ioutil.WriteFile(certPath, []byte("cert"), 0644))
+
+	signers, err := loadSigners([]string{"Org1MSP:" + certPath})
+	require.NoError(t, err)
+	require.Len(t, signers, 1)
+
+	_, err = loadSigners([]string{"Org1MSP"})
+	assert.EqualError(t, err, "invalid signer 'Org1MSP', expected MSPID:path/to/cert.pem")
+
+	_, err = loadSigners([]string{"Org1MSP:" + filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err)
+}
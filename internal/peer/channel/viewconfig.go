@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/scc/qscc"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/configview"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func viewconfigCmd(cf *ChannelCmdFactory) *cobra.Command {
+	viewconfigCmd := &cobra.Command{
+		Use:   "viewconfig",
+		Short: "Prints the channel config decoded to JSON.",
+		Long: "Prints the current config of a channel decoded to JSON, with its policies, the certificates of its MSPs and " +
+			"its capabilities rendered in readable form. Requires '-c' to query the peer, or '-b' with a config block " +
+			"to decode it offline.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return viewConfig(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"blockpath",
+	}
+	attachFlags(viewconfigCmd, flagList)
+
+	return viewconfigCmd
+}
+
+func (cc *endorserClient) getChannelConfigView() ([]byte, error) {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "qscc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(qscc.GetChannelConfigView), []byte(channelID)}},
+		},
+	}
+
+	c, _ := cc.cf.Signer.Serialize()
+	prop, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", invocation, c)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create proposal")
+	}
+
+	signedProp, err := protoutil.GetSignedProposal(prop, cc.cf.Signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot create signed proposal")
+	}
+
+	proposalResp, err := cc.cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed sending proposal")
+	}
+
+	if proposalResp.Response == nil || proposalResp.Response.Status != 200 {
+		return nil, errors.Errorf("received bad response, status %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	return proposalResp.Response.Payload, nil
+}
+
+func viewConfig(cmd *cobra.Command, cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue && genesisBlockPath == common.UndefinedParamValue {
+		return errors.New("must supply channel ID or config block path")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var view []byte
+	if genesisBlockPath != common.UndefinedParamValue {
+		blockBytes, err := ioutil.ReadFile(genesisBlockPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to read config block")
+		}
+		block, err := protoutil.UnmarshalBlock(blockBytes)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal config block")
+		}
+		config, err := configview.ConfigFromBlock(block)
+		if err != nil {
+			return err
+		}
+		if view, err = configview.Marshal(config); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if cf == nil {
+			cf, err = InitCmdFactory(EndorserRequired, PeerDeliverNotRequired, OrdererNotRequired)
+			if err != nil {
+				return err
+			}
+		}
+		client := &endorserClient{cf}
+		if view, err = client.getChannelConfigView(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println(string(view))
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewConfig(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	mockCF := &ChannelCmdFactory{
+		EndorserClient: common.GetMockEndorserClient(&pb.ProposalResponse{
+			Response:    &pb.Response{Status: 200, Payload: []byte(`{"sequence": "1"}`)},
+			Endorsement: &pb.Endorsement{},
+		}, nil),
+		Signer: signer,
+	}
+
+	cmd := viewconfigCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel})
+	assert.NoError(t, cmd.Execute())
+
+	resetFlags()
+	mockCF.EndorserClient = common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response:    &pb.Response{Status: 500, Message: "access denied"},
+		Endorsement: &pb.Endorsement{},
+	}, nil)
+	cmd = viewconfigCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel})
+	assert.EqualError(t, cmd.Execute(), "received bad response, status 500: access denied")
+}
+
+func TestViewConfigOffline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "viewconfigtest-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configEnv := &cb.ConfigEnvelope{
+		Config: &cb.Config{Sequence: 1, ChannelGroup: protoutil.NewConfigGroup()},
+	}
+	payload := &cb.Payload{
+		Header: protoutil.MakePayloadHeader(protoutil.MakeChannelHeader(cb.HeaderType_CONFIG, 0, "mychannel", 0), &cb.SignatureHeader{}),
+		Data:   protoutil.MarshalOrPanic(configEnv),
+	}
+	block := protoutil.NewBlock(0, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(&cb.Envelope{Payload: protoutil.MarshalOrPanic(payload)})}
+	configBlockPath := filepath.Join(dir, "config.block")
+	require.NoError(t, ioutil.WriteFile(configBlockPath, protoutil.MarshalOrPanic(block), 0644))
+
+	normalBlockPath := filepath.Join(dir, "normal.block")
+	require.NoError(t, ioutil.WriteFile(normalBlockPath, protoutil.MarshalOrPanic(&cb.Block{}), 0644))
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing channel and block",
+			args:        []string{},
+			expectedErr: "must supply channel ID or config block path",
+		},
+		{
+			name:        "nonexistent block",
+			args:        []string{"-b", filepath.Join(dir, "missing.block")},
+			expectedErr: "failed to read config block: open " + filepath.Join(dir, "missing.block") + ": no such file or directory",
+		},
+		{
+			name:        "not a config block",
+			args:        []string{"-b", normalBlockPath},
+			expectedErr: "block is not a config block",
+		},
+		{
+			name: "config block",
+			args: []string{"-b", configBlockPath},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+
+			cmd := viewconfigCmd(nil)
+			AddFlags(cmd)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -7,9 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package common
 
 import (
+	"math/rand"
+	"strings"
+
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 type BroadcastClient interface {
@@ -36,6 +40,32 @@ func GetBroadcastClient() (BroadcastClient, error) {
 	return &BroadcastGRPCClient{Client: bc}, nil
 }
 
+// GetBroadcastClientForEndpoints returns a BroadcastClient connected to one
+// of the given orderer endpoints. The endpoints are tried in random order, so
+// that the clients are spread across the ordering service nodes, and the ones
+// which cannot be reached are skipped.
+func GetBroadcastClientForEndpoints(endpoints []string) (BroadcastClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no orderer endpoints supplied")
+	}
+
+	var failures []string
+	for _, i := range rand.Perm(len(endpoints)) {
+		endpoint := endpoints[i]
+		// override viper env
+		viper.Set("orderer.address", endpoint)
+		bc, err := GetBroadcastClientFnc()
+		if err == nil {
+			logger.Infof("Connected to orderer endpoint: %s", endpoint)
+			return bc, nil
+		}
+		logger.Warningf("Failed to connect to orderer endpoint %s: %s", endpoint, err)
+		failures = append(failures, endpoint+": "+err.Error())
+	}
+
+	return nil, errors.Errorf("failed to connect to any of the orderer endpoints: %s", strings.Join(failures, "; "))
+}
+
 func (s *BroadcastGRPCClient) getAck() error {
 	msg, err := s.Client.Recv()
 	if err != nil {
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBroadcastClientForEndpoints(t *testing.T) {
+	defer viper.Reset()
+	defer func(f func() (common.BroadcastClient, error)) { common.GetBroadcastClientFnc = f }(common.GetBroadcastClientFnc)
+
+	unreachable := map[string]bool{"orderer1:7050": true, "orderer2:7050": true}
+	var attempts []string
+	common.GetBroadcastClientFnc = func() (common.BroadcastClient, error) {
+		endpoint := viper.GetString("orderer.address")
+		attempts = append(attempts, endpoint)
+		if unreachable[endpoint] {
+			return nil, errors.New("connection refused")
+		}
+		return common.GetMockBroadcastClient(nil), nil
+	}
+
+	endpoints := []string{"orderer1:7050", "orderer2:7050", "orderer3:7050"}
+	bc, err := common.GetBroadcastClientForEndpoints(endpoints)
+	require.NoError(t, err)
+	assert.NotNil(t, bc)
+	assert.Equal(t, "orderer3:7050", viper.GetString("orderer.address"))
+	assert.Equal(t, "orderer3:7050", attempts[len(attempts)-1])
+
+	attempts = nil
+	unreachable["orderer3:7050"] = true
+	_, err = common.GetBroadcastClientForEndpoints(endpoints)
+	assert.ElementsMatch(t, endpoints, attempts)
+	assert.Contains(t, err.Error(), "failed to connect to any of the orderer endpoints")
+	assert.Contains(t, err.Error(), "orderer1:7050: connection refused")
+
+	_, err = common.GetBroadcastClientForEndpoints(nil)
+	assert.EqualError(t, err, "no orderer endpoints supplied")
+}
@@ -12,10 +12,12 @@ import (
 	"github.com/cetcxinlian/cryptogm/tls"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	pcommon "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
@@ -24,6 +26,8 @@ import (
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/enrollment"
+	"github.com/hyperledger/fabric/internal/pkg/remotesigner"
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protoutil"
@@ -40,6 +44,18 @@ const CmdRoot = "core"
 var mainLogger = flogging.MustGetLogger("main")
 var logOutput = os.Stderr
 
+// contextKept is set when the configuration and the local MSP loaded by
+// InitCmd must be reused by the following invocations of InitCmd
+var contextKept bool
+
+// KeepContext makes the following invocations of InitCmd reuse the
+// configuration and the local MSP already loaded instead of loading them
+// again. It is used by the peer shell, which runs several commands in the
+// same process.
+func KeepContext() {
+	contextKept = true
+}
+
 var (
 	defaultConnTimeout = 3 * time.Second
 	// These function variables (xyzFnc) can be used to invoke corresponding xyz function
@@ -133,12 +149,22 @@ func InitCrypto(mspMgrConfigDir, localMSPID, localMSPType string) error {
 
 	// Init the BCCSP
 	SetBCCSPKeystorePath()
-	bccspConfig := factory.GetDefaultOpts()
-	if config := viper.Get("peer.BCCSP"); config != nil {
-		err = mapstructure.WeakDecode(config, bccspConfig)
+	bccspConfig, err := getBCCSPConfig()
+	if err != nil {
+		return err
+	}
+
+	if viper.GetBool("peer.remoteSigner.enabled") {
+		// the signing key is held by the remote signing service, hence the
+		// local MSP is set up without its signing identity
+		conf, _, err := localPublicMspConfig(mspMgrConfigDir, bccspConfig, localMSPID, localMSPType)
+		if err == nil {
+			err = mspmgmt.GetLocalMSP(factory.GetDefault()).Setup(conf)
+		}
 		if err != nil {
-			return errors.WithMessage(err, "could not decode peer BCCSP configuration")
+			return errors.WithMessagef(err, "error when setting up MSP of type %s from directory %s", localMSPType, mspMgrConfigDir)
 		}
+		return nil
 	}
 
 	err = mspmgmt.LoadLocalMspWithType(mspMgrConfigDir, bccspConfig, localMSPID, localMSPType)
@@ -149,6 +175,123 @@ func InitCrypto(mspMgrConfigDir, localMSPID, localMSPType string) error {
 	return nil
 }
 
+// ReloadCrypto reloads the local MSP of this peer from its directory
+func ReloadCrypto(mspMgrConfigDir, localMSPID, localMSPType string) error {
+	bccspConfig, err := getBCCSPConfig()
+	if err != nil {
+		return err
+	}
+
+	var conf *mspproto.MSPConfig
+	if viper.GetBool("peer.remoteSigner.enabled") {
+		conf, _, err = localPublicMspConfig(mspMgrConfigDir, bccspConfig, localMSPID, localMSPType)
+	} else {
+		conf, err = msp.GetLocalMspConfigWithType(mspMgrConfigDir, bccspConfig, localMSPID, localMSPType)
+	}
+	if err == nil {
+		err = mspmgmt.ReloadLocalMsp(conf)
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "error when reloading MSP of type %s from directory %s", localMSPType, mspMgrConfigDir)
+	}
+	return nil
+}
+
+func getBCCSPConfig() (*factory.FactoryOpts, error) {
+	bccspConfig := factory.GetDefaultOpts()
+	if config := viper.Get("peer.BCCSP"); config != nil {
+		err := mapstructure.WeakDecode(config, bccspConfig)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not decode peer BCCSP configuration")
+		}
+	}
+	return bccspConfig, nil
+}
+
+// localPublicMspConfig returns the configuration of the local MSP without its
+// signing identity, along with the certificate of the signing identity.
+func localPublicMspConfig(dir string, bccspConfig *factory.FactoryOpts, mspID, mspType string) (*mspproto.MSPConfig, []byte, error) {
+	conf, err := msp.GetLocalMspConfigWithType(dir, bccspConfig, mspID, mspType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return remotesigner.PublicMSPConfig(conf)
+}
+
+// GetRemoteSignerConfig returns the configuration of the connection to the
+// remote signing service.
+func GetRemoteSignerConfig() (remotesigner.Config, error) {
+	conf := remotesigner.Config{
+		Address: viper.GetString("peer.remoteSigner.address"),
+		KeyID:   viper.GetString("peer.remoteSigner.keyID"),
+		Timeout: viper.GetDuration("peer.remoteSigner.timeout"),
+		ClientConfig: comm.ClientConfig{
+			Timeout: viper.GetDuration("peer.remoteSigner.timeout"),
+			KaOpts:  comm.DefaultKeepaliveOptions,
+			SecOpts: comm.SecureOptions{
+				UseTLS: viper.GetBool("peer.remoteSigner.tls.enabled"),
+			},
+		},
+	}
+	if !conf.ClientConfig.SecOpts.UseTLS {
+		return conf, nil
+	}
+
+	rootCert, err := ioutil.ReadFile(config.GetPath("peer.remoteSigner.tls.rootcert.file"))
+	if err != nil {
+		return conf, errors.Wrap(err, "error loading the TLS root certificate of the remote signer")
+	}
+	conf.ClientConfig.SecOpts.ServerRootCAs = [][]byte{rootCert}
+
+	if viper.GetString("peer.remoteSigner.tls.clientCert.file") != "" {
+		cert, err := ioutil.ReadFile(config.GetPath("peer.remoteSigner.tls.clientCert.file"))
+		if err != nil {
+			return conf, errors.Wrap(err, "error loading the TLS client certificate of the remote signer")
+		}
+		key, err := ioutil.ReadFile(config.GetPath("peer.remoteSigner.tls.clientKey.file"))
+		if err != nil {
+			return conf, errors.Wrap(err, "error loading the TLS client key of the remote signer")
+		}
+		conf.ClientConfig.SecOpts.RequireClientCert = true
+		conf.ClientConfig.SecOpts.Certificate = cert
+		conf.ClientConfig.SecOpts.Key = key
+	}
+	return conf, nil
+}
+
+// GetSigningIdentity returns the signing identity of the local MSP. When
+// peer.remoteSigner.enabled is set, the identity signs with the remote signing
+// service.
+func GetSigningIdentity() (msp.SigningIdentity, error) {
+	localMSP := mspmgmt.GetLocalMSP(factory.GetDefault())
+	if !viper.GetBool("peer.remoteSigner.enabled") {
+		return localMSP.GetDefaultSigningIdentity()
+	}
+
+	bccspConfig, err := getBCCSPConfig()
+	if err != nil {
+		return nil, err
+	}
+	mspType := viper.GetString("peer.localMspType")
+	if mspType == "" {
+		mspType = msp.ProviderTypeToString(msp.FABRIC)
+	}
+	_, signCert, err := localPublicMspConfig(config.GetPath("peer.mspConfigPath"), bccspConfig, viper.GetString("peer.localMspId"), mspType)
+	if err != nil {
+		return nil, err
+	}
+	id, err := remotesigner.PublicIdentity(localMSP, signCert)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := GetRemoteSignerConfig()
+	if err != nil {
+		return nil, err
+	}
+	return remotesigner.New(conf, id)
+}
+
 // SetBCCSPKeystorePath sets the file keystore path for the SW BCCSP provider
 // to an absolute path relative to the config file
 func SetBCCSPKeystorePath() {
@@ -156,9 +299,48 @@ func SetBCCSPKeystorePath() {
 		config.GetPath("peer.BCCSP.SW.FileKeyStore.KeyStore"))
 }
 
+// GetEnrollmentConfig returns the configuration of the enrollment of the peer
+// identities against a CA.
+func GetEnrollmentConfig() enrollment.Config {
+	conf := enrollment.Config{
+		URL:           viper.GetString("peer.enrollment.url"),
+		CAName:        viper.GetString("peer.enrollment.caName"),
+		EnrollmentID:  viper.GetString("peer.enrollment.id"),
+		Secret:        viper.GetString("peer.enrollment.secret"),
+		TLSProfile:    viper.GetString("peer.enrollment.tlsProfile"),
+		CSRHosts:      viper.GetStringSlice("peer.enrollment.csr.hosts"),
+		KeyType:       viper.GetString("peer.enrollment.keyType"),
+		MSPDir:        config.GetPath("peer.mspConfigPath"),
+		RenewBefore:   viper.GetDuration("peer.enrollment.renewBefore"),
+		CheckInterval: viper.GetDuration("peer.enrollment.checkInterval"),
+		Timeout:       viper.GetDuration("peer.enrollment.timeout"),
+	}
+	for _, file := range viper.GetStringSlice("peer.enrollment.tls.rootcert.files") {
+		conf.TLSRootCerts = append(conf.TLSRootCerts, config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), file))
+	}
+	if viper.GetBool("peer.tls.enabled") {
+		conf.TLSCertFile = config.GetPath("peer.tls.cert.file")
+		conf.TLSKeyFile = config.GetPath("peer.tls.key.file")
+	}
+	return conf
+}
+
+// Enroll enrolls the peer identities missing a certificate and renews the
+// certificates about to expire.
+func Enroll() error {
+	enroller, err := enrollment.New(GetEnrollmentConfig())
+	if err != nil {
+		return errors.WithMessage(err, "invalid enrollment configuration")
+	}
+	if _, err := enroller.Enroll(); err != nil {
+		return errors.WithMessage(err, "enrollment failed")
+	}
+	return nil
+}
+
 // GetDefaultSigner return a default Signer(Default/PEER) for cli
 func GetDefaultSigner() (msp.SigningIdentity, error) {
-	signer, err := mspmgmt.GetLocalMSP(factory.GetDefault()).GetDefaultSigningIdentity()
+	signer, err := GetSigningIdentity()
 	if err != nil {
 		return nil, errors.WithMessage(err, "error obtaining the default signing identity")
 	}
@@ -289,6 +471,11 @@ func configFromEnv(prefix string) (address, override string, clientConfig comm.C
 }
 
 func InitCmd(cmd *cobra.Command, args []string) {
+	if contextKept {
+		mainLogger.Debugf("Reusing the loaded context for %s", cmd.CommandPath())
+		return
+	}
+
 	err := InitConfig(CmdRoot)
 	if err != nil { // Handle errors reading the config file
 		mainLogger.Errorf("Fatal error when initializing %s config : %s", CmdRoot, err)
@@ -329,6 +516,15 @@ func InitCmd(cmd *cobra.Command, args []string) {
 	if mspType == "" {
 		mspType = msp.ProviderTypeToString(msp.FABRIC)
 	}
+
+	// enroll the peer identities before the local MSP is loaded
+	if cmd.CommandPath() == "peer node start" && viper.GetBool("peer.enrollment.enabled") {
+		if err = Enroll(); err != nil {
+			mainLogger.Errorf("Cannot run peer because %s", err.Error())
+			os.Exit(1)
+		}
+	}
+
 	err = InitCrypto(mspMgrConfigDir, mspID, mspType)
 	if err != nil { // Handle errors reading the config file
 		mainLogger.Errorf("Cannot run peer because %s", err.Error())
@@ -15,6 +15,7 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/peer/proposalstream"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
@@ -111,6 +112,10 @@ func (pc *PeerClient) Endorser() (pb.EndorserClient, error) {
 	if err != nil {
 		return nil, errors.WithMessagef(err, "endorser client failed to connect to %s", pc.Address)
 	}
+	if viper.GetBool("peer.client.proposalStreaming") {
+		maxSize := int(viper.GetSizeInBytes("peer.client.proposalStreamingMaxSize"))
+		return proposalstream.NewEndorserClient(conn, maxSize), nil
+	}
 	return pb.NewEndorserClient(conn), nil
 }
 
@@ -189,7 +189,33 @@ func (s *MSPMessageCryptoService) VerifyBlock(chainID common.ChannelID, seqNum u
 	}
 
 	// - Evaluate policy
-	return policy.EvaluateSignedData(signatureSet)
+	if err := policy.EvaluateSignedData(signatureSet); err != nil {
+		return err
+	}
+
+	// - Verify the time attestation of the orderer, if any, against the same policy
+	_, timestampSignatures, err := protoutil.GetBlockTimestamp(block)
+	if err != nil {
+		return fmt.Errorf("Invalid timestamp for block with id [%d] on channel [%s]: [%s]", block.Header.Number, chainID, err)
+	}
+	if timestampSignatures != nil {
+		if err := policy.EvaluateSignedData(timestampSignatures); err != nil {
+			return fmt.Errorf("Timestamp of block with id [%d] on channel [%s] is not attested by the orderers: [%s]", block.Header.Number, chainID, err)
+		}
+	}
+
+	// - Verify the range attestation of the orderer, if any, against the same policy
+	_, rangeSignatures, err := protoutil.GetBlockRangeAttestation(block)
+	if err != nil {
+		return fmt.Errorf("Invalid range attestation for block with id [%d] on channel [%s]: [%s]", block.Header.Number, chainID, err)
+	}
+	if rangeSignatures == nil {
+		return nil
+	}
+	if err := policy.EvaluateSignedData(rangeSignatures); err != nil {
+		return fmt.Errorf("Range attestation of block with id [%d] on channel [%s] is not signed by the orderers: [%s]", block.Header.Number, chainID, err)
+	}
+	return nil
 }
 
 // Sign signs msg with this peer's signing key and outputs
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package gossip
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
 	"reflect"
@@ -275,6 +276,94 @@ func TestVerifyBlock(t *testing.T) {
 	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), 42, &common.Block{}))
 }
 
+// signedDataPolicy is satisfied by signatures equal to their signed data
+type signedDataPolicy struct{}
+
+func (signedDataPolicy) EvaluateSignedData(signatureSet []*protoutil.SignedData) error {
+	for _, sd := range signatureSet {
+		if !bytes.Equal(sd.Data, sd.Signature) {
+			return errors.New("Invalid Signature")
+		}
+	}
+	return nil
+}
+
+func (signedDataPolicy) EvaluateIdentities(identities []msp.Identity) error {
+	panic("Implement me")
+}
+
+func TestVerifyBlockTimestamp(t *testing.T) {
+	signer := &mocks.SignerSerializer{}
+	signer.SerializeReturns([]byte("Orderer"), nil)
+	policyManagerGetter := &mocks.ChannelPolicyManagerGetterWithManager{
+		Managers: map[string]policies.Manager{
+			"C": &mocks.ChannelPolicyManager{Policy: signedDataPolicy{}},
+		},
+	}
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	msgCryptoService := NewMCS(policyManagerGetter, signer, &mocks.DeserializersManager{}, cryptoProvider)
+
+	block, _ := mockBlock(t, "C", 42, signer, nil)
+	signer.SignStub = func(msg []byte) ([]byte, error) { return msg, nil }
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), 42, block))
+
+	assert.NoError(t, protoutil.SetBlockTimestamp(block, time.Now()))
+	assert.NoError(t, protoutil.SignBlockTimestamp(block, signer))
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), 42, block))
+
+	// An attestation of another block is rejected
+	otherBlock, _ := mockBlock(t, "C", 43, signer, nil)
+	assert.NoError(t, protoutil.SetBlockTimestamp(otherBlock, time.Now()))
+	assert.NoError(t, protoutil.SignBlockTimestamp(otherBlock, signer))
+	block.Metadata.Metadata[protoutil.BlockMetadataIndexTimestamp] = otherBlock.Metadata.Metadata[protoutil.BlockMetadataIndexTimestamp]
+	err = msgCryptoService.VerifyBlock([]byte("C"), 42, block)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid timestamp for block with id [42]")
+
+	// A forged attestation is rejected
+	signer.SignStub = func(msg []byte) ([]byte, error) { return []byte("forged"), nil }
+	assert.NoError(t, protoutil.SetBlockTimestamp(block, time.Now()))
+	assert.NoError(t, protoutil.SignBlockTimestamp(block, signer))
+	err = msgCryptoService.VerifyBlock([]byte("C"), 42, block)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Timestamp of block with id [42] on channel [C] is not attested by the orderers")
+}
+
+func TestVerifyBlockRangeAttestation(t *testing.T) {
+	signer := &mocks.SignerSerializer{}
+	signer.SerializeReturns([]byte("Orderer"), nil)
+	policyManagerGetter := &mocks.ChannelPolicyManagerGetterWithManager{
+		Managers: map[string]policies.Manager{
+			"C": &mocks.ChannelPolicyManager{Policy: signedDataPolicy{}},
+		},
+	}
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	assert.NoError(t, err)
+	msgCryptoService := NewMCS(policyManagerGetter, signer, &mocks.DeserializersManager{}, cryptoProvider)
+
+	block, _ := mockBlock(t, "C", 42, signer, nil)
+	signer.SignStub = func(msg []byte) ([]byte, error) { return msg, nil }
+	hashes := [][]byte{[]byte("hash of block 41"), protoutil.BlockHeaderHash(block.Header)}
+	assert.NoError(t, protoutil.AddBlockRangeAttestation(block, signer, 41, hashes))
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), 42, block))
+
+	// An attestation of another range is rejected
+	otherBlock, _ := mockBlock(t, "C", 43, signer, nil)
+	assert.NoError(t, protoutil.AddBlockRangeAttestation(otherBlock, signer, 43, [][]byte{protoutil.BlockHeaderHash(otherBlock.Header)}))
+	block.Metadata.Metadata[protoutil.BlockMetadataIndexRangeAttestation] = otherBlock.Metadata.Metadata[protoutil.BlockMetadataIndexRangeAttestation]
+	err = msgCryptoService.VerifyBlock([]byte("C"), 42, block)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid range attestation for block with id [42]")
+
+	// A forged attestation is rejected
+	signer.SignStub = func(msg []byte) ([]byte, error) { return []byte("forged"), nil }
+	assert.NoError(t, protoutil.AddBlockRangeAttestation(block, signer, 41, hashes))
+	err = msgCryptoService.VerifyBlock([]byte("C"), 42, block)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Range attestation of block with id [42] on channel [C] is not signed by the orderers")
+}
+
 func mockBlock(t *testing.T, channel string, seqNum uint64, localSigner *mocks.SignerSerializer, dataHash []byte) (*common.Block, []byte) {
 	block := protoutil.NewBlock(seqNum, nil)
 
@@ -0,0 +1,221 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gossip
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/pkg/errors"
+)
+
+var pinnerLogger = flogging.MustGetLogger("peer.gossip.pinner")
+
+// TLSPin holds the TLS certificates pinned for the peers of an organization.
+type TLSPin struct {
+	// MSPID is the MSP ID of the organization.
+	MSPID string
+	// Fingerprints are the hex encoded SM3 fingerprints of the pinned certificates.
+	Fingerprints []string
+	// CertFiles are the paths to the PEM files of the pinned certificates.
+	CertFiles []string
+}
+
+// mspTLSPinner implements the TLSPinner interface using peer's MSPs.
+//
+// The TLS certificate of a peer of an organization with pinned certificates
+// must either be one of them or be issued, possibly through the intermediate
+// certificates presented alongside it, by one of them. The TLS certificate of a peer of any
+// other organization must be issued by one of the TLS CAs of its organization,
+// as found in the configuration of the channels. The latter prevents a peer of
+// an organization from using a TLS certificate issued by the TLS CA of another
+// organization, which the TLS handshake alone accepts.
+type mspTLSPinner struct {
+	deserializer mgmt.DeserializersManager
+	pins         map[string]map[string]struct{}
+}
+
+// NewTLSPinner creates a new instance of mspTLSPinner
+// that implements TLSPinner
+func NewTLSPinner(deserializer mgmt.DeserializersManager, pins []TLSPin) (api.TLSPinner, error) {
+	pinner := &mspTLSPinner{
+		deserializer: deserializer,
+		pins:         map[string]map[string]struct{}{},
+	}
+	for _, pin := range pins {
+		if pin.MSPID == "" {
+			return nil, errors.New("TLS certificates are pinned without MSP ID")
+		}
+		fingerprints, exists := pinner.pins[pin.MSPID]
+		if !exists {
+			fingerprints = map[string]struct{}{}
+			pinner.pins[pin.MSPID] = fingerprints
+		}
+		for _, fp := range pin.Fingerprints {
+			raw, err := hex.DecodeString(fp)
+			if err != nil || len(raw) != sm3.Size {
+				return nil, errors.Errorf("invalid SM3 fingerprint %s pinned for %s", fp, pin.MSPID)
+			}
+			fingerprints[hex.EncodeToString(raw)] = struct{}{}
+		}
+		for _, file := range pin.CertFiles {
+			pemBytes, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed reading certificate pinned for %s", pin.MSPID)
+			}
+			block, _ := pem.Decode(pemBytes)
+			if block == nil || block.Type != "CERTIFICATE" {
+				return nil, errors.Errorf("file %s pinned for %s doesn't hold a PEM encoded certificate", file, pin.MSPID)
+			}
+			fingerprints[certFingerprint(block.Bytes)] = struct{}{}
+		}
+		if len(fingerprints) == 0 {
+			return nil, errors.Errorf("no TLS certificate is pinned for %s", pin.MSPID)
+		}
+	}
+	return pinner, nil
+}
+
+// VerifyTLSPins returns an error if the TLS certificate chain presented
+// by a peer of the given organization doesn't match the pins of the organization.
+func (p *mspTLSPinner) VerifyTLSPins(org api.OrgIdentityType, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no TLS certificate")
+	}
+	mspID := string(org)
+	if mspID == "" {
+		return errors.New("unknown organization")
+	}
+
+	if fingerprints, pinned := p.pins[mspID]; pinned {
+		if _, exists := fingerprints[certFingerprint(rawCerts[0])]; exists {
+			return nil
+		}
+		if issuedByPin(fingerprints, rawCerts) {
+			return nil
+		}
+		return errors.Errorf("TLS certificate doesn't match the certificates pinned for %s", mspID)
+	}
+
+	return p.verifyIssuedByOrg(mspID, rawCerts)
+}
+
+// issuedByPin returns whether the leaf of the chain is issued by one of the
+// pinned certificates presented in the chain. A pinned certificate merely
+// appended to the chain doesn't match.
+func issuedByPin(fingerprints map[string]struct{}, rawCerts [][]byte) bool {
+	leaf, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return false
+	}
+	for i, rawCert := range rawCerts[1:] {
+		if _, exists := fingerprints[certFingerprint(rawCert)]; !exists {
+			continue
+		}
+		pinnedCert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			continue
+		}
+		roots := x509.NewCertPool()
+		roots.AddCert(pinnedCert)
+		intermediates := x509.NewCertPool()
+		for j, rawCert := range rawCerts[1:] {
+			if j == i {
+				continue
+			}
+			if cert, err := x509.ParseCertificate(rawCert); err == nil {
+				intermediates.AddCert(cert)
+			}
+		}
+		_, err = leaf.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err == nil {
+			return true
+		}
+		pinnerLogger.Debugf("TLS certificate isn't issued by the pinned certificate %s: %s", certFingerprint(rawCert), err)
+	}
+	return false
+}
+
+func (p *mspTLSPinner) verifyIssuedByOrg(mspID string, rawCerts [][]byte) error {
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	var found bool
+	for _, orgMSP := range p.orgMSPs(mspID) {
+		for _, cert := range orgMSP.GetTLSRootCerts() {
+			found = roots.AppendCertsFromPEM(cert) || found
+		}
+		for _, cert := range orgMSP.GetTLSIntermediateCerts() {
+			intermediates.AppendCertsFromPEM(cert)
+		}
+	}
+	if !found {
+		return errors.Errorf("no TLS CA certificate of %s found", mspID)
+	}
+
+	leaf, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return errors.Wrap(err, "failed parsing TLS certificate")
+	}
+	for _, rawCert := range rawCerts[1:] {
+		cert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return errors.Wrap(err, "failed parsing TLS intermediate certificate")
+		}
+		intermediates.AddCert(cert)
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "TLS certificate isn't issued by a TLS CA of %s", mspID)
+	}
+	return nil
+}
+
+// orgMSPs returns the MSPs of an organization, the local one and those found
+// in the configuration of the channels.
+func (p *mspTLSPinner) orgMSPs(mspID string) []msp.MSP {
+	var msps []msp.MSP
+	if localMSP, ok := p.deserializer.GetLocalDeserializer().(msp.MSP); ok {
+		if id, err := localMSP.GetIdentifier(); err == nil && id == mspID {
+			msps = append(msps, localMSP)
+		}
+	}
+	for channelID, deserializer := range p.deserializer.GetChannelDeserializers() {
+		mspManager, ok := deserializer.(msp.MSPManager)
+		if !ok {
+			continue
+		}
+		channelMSPs, err := mspManager.GetMSPs()
+		if err != nil {
+			pinnerLogger.Warningf("Failed getting MSPs of channel [%s]: %s", channelID, err)
+			continue
+		}
+		if orgMSP, exists := channelMSPs[mspID]; exists {
+			msps = append(msps, orgMSP)
+		}
+	}
+	return msps
+}
+
+func certFingerprint(rawCert []byte) string {
+	return hex.EncodeToString(sm3.SumSM3(rawCert))
+}
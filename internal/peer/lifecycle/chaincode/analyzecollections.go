@@ -0,0 +1,244 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// CollectionsAnalyzer holds the dependencies needed to analyze the
+// impact of a collection configuration update on the collections of
+// a committed chaincode definition before the update is committed.
+type CollectionsAnalyzer struct {
+	Command        *cobra.Command
+	EndorserClient pb.EndorserClient
+	Input          *AnalyzeCollectionsInput
+	Signer         identity.SignerSerializer
+	Writer         io.Writer
+}
+
+// AnalyzeCollectionsInput holds all of the input parameters for analyzing
+// a collection configuration update.
+type AnalyzeCollectionsInput struct {
+	ChannelID               string
+	Name                    string
+	CollectionConfigPackage *pb.CollectionConfigPackage
+	PeerAddresses           []string
+	TxID                    string
+	OutputFormat            string
+}
+
+// Validate the input for an AnalyzeCollectionConfigUpdate proposal
+func (a *AnalyzeCollectionsInput) Validate() error {
+	if a.ChannelID == "" {
+		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	if a.Name == "" {
+		return errors.New("The required parameter 'name' is empty. Rerun the command with -n flag")
+	}
+
+	if a.CollectionConfigPackage == nil {
+		return errors.New("The required parameter 'collections-config' is empty. Rerun the command with --collections-config flag")
+	}
+
+	return nil
+}
+
+// AnalyzeCollectionsCmd returns the cobra command for the
+// AnalyzeCollectionConfigUpdate lifecycle operation
+func AnalyzeCollectionsCmd(a *CollectionsAnalyzer, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	chaincodeAnalyzeCollectionsCmd := &cobra.Command{
+		Use:   "analyzecollections",
+		Short: "Analyze the impact of a collection configuration update on a peer.",
+		Long:  "Report the orgs removed from the existing collections of a committed chaincode definition by a collection configuration update, and the private data held by the peer which becomes orphaned when its org is removed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a := a
+			if a == nil {
+				// set input from CLI flags
+				input, err := a.createInput()
+				if err != nil {
+					return err
+				}
+
+				ccInput := &ClientConnectionsInput{
+					CommandName:           cmd.Name(),
+					EndorserRequired:      true,
+					ChannelID:             channelID,
+					PeerAddresses:         peerAddresses,
+					TLSRootCertFiles:      tlsRootCertFiles,
+					ConnectionProfilePath: connectionProfilePath,
+					TLSEnabled:            viper.GetBool("peer.tls.enabled"),
+				}
+
+				cc, err := NewClientConnections(ccInput, cryptoProvider)
+				if err != nil {
+					return err
+				}
+
+				a = &CollectionsAnalyzer{
+					Command:        cmd,
+					Input:          input,
+					EndorserClient: cc.EndorserClients[0],
+					Signer:         cc.Signer,
+					Writer:         os.Stdout,
+				}
+			}
+
+			return a.Analyze()
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"name",
+		"collections-config",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+		"output",
+	}
+	attachFlags(chaincodeAnalyzeCollectionsCmd, flagList)
+
+	return chaincodeAnalyzeCollectionsCmd
+}
+
+// Analyze submits an AnalyzeCollectionConfigUpdate proposal
+// and prints the result.
+func (a *CollectionsAnalyzer) Analyze() error {
+	err := a.Input.Validate()
+	if err != nil {
+		return err
+	}
+
+	if a.Command != nil {
+		// Parsing of the command line is done so silence cmd usage
+		a.Command.SilenceUsage = true
+	}
+
+	proposal, err := a.createProposal(a.Input.TxID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create proposal")
+	}
+
+	signedProposal, err := signProposal(proposal, a.Signer)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create signed proposal")
+	}
+
+	// the private data held is specific to each peer, so only a single peer is queried
+	proposalResponse, err := a.EndorserClient.ProcessProposal(context.Background(), signedProposal)
+	if err != nil {
+		return errors.WithMessage(err, "failed to endorse proposal")
+	}
+
+	if proposalResponse == nil {
+		return errors.New("received nil proposal response")
+	}
+
+	if proposalResponse.Response == nil {
+		return errors.New("received proposal response with nil response")
+	}
+
+	if proposalResponse.Response.Status != int32(cb.Status_SUCCESS) {
+		return errors.Errorf("query failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+	}
+
+	if strings.ToLower(a.Input.OutputFormat) == "json" {
+		return printResponseAsJSON(proposalResponse, &lb.AnalyzeCollectionConfigUpdateResult{}, a.Writer)
+	}
+	return a.printResponse(proposalResponse)
+}
+
+// printResponse prints the information included in the response
+// from the server as human readable plain-text.
+func (a *CollectionsAnalyzer) printResponse(proposalResponse *pb.ProposalResponse) error {
+	result := &lb.AnalyzeCollectionConfigUpdateResult{}
+	err := proto.Unmarshal(proposalResponse.Response.Payload, result)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal proposal response's response payload")
+	}
+
+	fmt.Fprintf(a.Writer, "Impact of the collection configuration update of chaincode '%s' on channel '%s':\n", a.Input.Name, a.Input.ChannelID)
+	for _, coll := range result.Collections {
+		removedOrgs := "none"
+		if len(coll.RemovedOrgs) > 0 {
+			removedOrgs = strings.Join(coll.RemovedOrgs, ", ")
+		}
+		fmt.Fprintf(a.Writer, "Collection: %s, Removed orgs: %s, Private data held: %d entries (%d bytes)", coll.Name, removedOrgs, coll.PvtDataEntries, coll.PvtDataBytes)
+		if coll.PeerOrgRemoved {
+			fmt.Fprint(a.Writer, ", orphaned as the org of the peer is removed")
+		}
+		fmt.Fprintln(a.Writer)
+	}
+
+	return nil
+}
+
+// createInput creates the input struct based on the CLI flags
+func (a *CollectionsAnalyzer) createInput() (*AnalyzeCollectionsInput, error) {
+	ccp, err := createCollectionConfigPackage(collectionsConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &AnalyzeCollectionsInput{
+		ChannelID:               channelID,
+		Name:                    chaincodeName,
+		CollectionConfigPackage: ccp,
+		PeerAddresses:           peerAddresses,
+		OutputFormat:            output,
+	}
+
+	return input, nil
+}
+
+func (a *CollectionsAnalyzer) createProposal(inputTxID string) (*pb.Proposal, error) {
+	args := &lb.AnalyzeCollectionConfigUpdateArgs{
+		Name:        a.Input.Name,
+		Collections: a.Input.CollectionConfigPackage,
+	}
+
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	ccInput := &pb.ChaincodeInput{Args: [][]byte{[]byte(analyzeCollectionsFuncName), argsBytes}}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: lifecycleName},
+			Input:       ccInput,
+		},
+	}
+
+	creatorBytes, err := a.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	proposal, _, err := protoutil.CreateChaincodeProposalWithTxIDAndTransient(cb.HeaderType_ENDORSER_TRANSACTION, a.Input.ChannelID, cis, creatorBytes, inputTxID, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create ChaincodeInvocationSpec proposal")
+	}
+
+	return proposal, nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode/mock"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("AnalyzeCollections", func() {
+	Describe("CollectionsAnalyzer", func() {
+		var (
+			mockProposalResponse *pb.ProposalResponse
+			mockEndorserClient   *mock.EndorserClient
+			mockSigner           *mock.Signer
+			input                *chaincode.AnalyzeCollectionsInput
+			collectionsAnalyzer  *chaincode.CollectionsAnalyzer
+		)
+
+		BeforeEach(func() {
+			mockEndorserClient = &mock.EndorserClient{}
+			mockResult := &lb.AnalyzeCollectionConfigUpdateResult{
+				Collections: []*lb.AnalyzeCollectionConfigUpdateResult_Collection{
+					{
+						Name:           "coll1",
+						RemovedOrgs:    []string{"Org1MSP", "Org3MSP"},
+						PeerOrgRemoved: true,
+						PvtDataEntries: 10,
+						PvtDataBytes:   2048,
+					},
+					{
+						Name:           "coll2",
+						PvtDataEntries: 3,
+						PvtDataBytes:   512,
+					},
+				},
+			}
+			mockResultBytes, err := proto.Marshal(mockResult)
+			Expect(err).NotTo(HaveOccurred())
+			mockProposalResponse = &pb.ProposalResponse{
+				Response: &pb.Response{
+					Status:  200,
+					Payload: mockResultBytes,
+				},
+				Endorsement: &pb.Endorsement{},
+			}
+			mockEndorserClient.ProcessProposalReturns(mockProposalResponse, nil)
+
+			input = &chaincode.AnalyzeCollectionsInput{
+				ChannelID:               "testchannel",
+				Name:                    "testcc",
+				CollectionConfigPackage: &pb.CollectionConfigPackage{},
+			}
+
+			mockSigner = &mock.Signer{}
+			buffer := gbytes.NewBuffer()
+
+			collectionsAnalyzer = &chaincode.CollectionsAnalyzer{
+				Input:          input,
+				EndorserClient: mockEndorserClient,
+				Signer:         mockSigner,
+				Writer:         buffer,
+			}
+		})
+
+		It("analyzes the collection configuration update and writes the output as human readable plain-text", func() {
+			err := collectionsAnalyzer.Analyze()
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(collectionsAnalyzer.Writer).Should(gbytes.Say("Impact of the collection configuration update of chaincode 'testcc' on channel 'testchannel':"))
+			Eventually(collectionsAnalyzer.Writer).Should(gbytes.Say(`Collection: coll1, Removed orgs: Org1MSP, Org3MSP, Private data held: 10 entries \(2048 bytes\), orphaned as the org of the peer is removed`))
+			Eventually(collectionsAnalyzer.Writer).Should(gbytes.Say(`Collection: coll2, Removed orgs: none, Private data held: 3 entries \(512 bytes\)\n`))
+		})
+
+		Context("when JSON-formatted output is requested", func() {
+			BeforeEach(func() {
+				collectionsAnalyzer.Input.OutputFormat = "json"
+			})
+
+			It("analyzes the collection configuration update and writes the output as JSON", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).NotTo(HaveOccurred())
+				expectedOutput := &lb.AnalyzeCollectionConfigUpdateResult{
+					Collections: []*lb.AnalyzeCollectionConfigUpdateResult_Collection{
+						{
+							Name:           "coll1",
+							RemovedOrgs:    []string{"Org1MSP", "Org3MSP"},
+							PeerOrgRemoved: true,
+							PvtDataEntries: 10,
+							PvtDataBytes:   2048,
+						},
+						{
+							Name:           "coll2",
+							PvtDataEntries: 3,
+							PvtDataBytes:   512,
+						},
+					},
+				}
+				json, err := json.MarshalIndent(expectedOutput, "", "\t")
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(collectionsAnalyzer.Writer).Should(gbytes.Say(string(json)))
+			})
+		})
+
+		Context("when the channel name is not provided", func() {
+			BeforeEach(func() {
+				collectionsAnalyzer.Input.ChannelID = ""
+			})
+
+			It("returns an error", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).To(MatchError("The required parameter 'channelID' is empty. Rerun the command with -C flag"))
+			})
+		})
+
+		Context("when the chaincode name is not provided", func() {
+			BeforeEach(func() {
+				collectionsAnalyzer.Input.Name = ""
+			})
+
+			It("returns an error", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).To(MatchError("The required parameter 'name' is empty. Rerun the command with -n flag"))
+			})
+		})
+
+		Context("when the collection configuration is not provided", func() {
+			BeforeEach(func() {
+				collectionsAnalyzer.Input.CollectionConfigPackage = nil
+			})
+
+			It("returns an error", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).To(MatchError("The required parameter 'collections-config' is empty. Rerun the command with --collections-config flag"))
+			})
+		})
+
+		Context("when the signer cannot be serialized", func() {
+			BeforeEach(func() {
+				mockSigner.SerializeReturns(nil, errors.New("cafe"))
+			})
+
+			It("returns an error", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).To(MatchError("failed to create proposal: failed to serialize identity: cafe"))
+			})
+		})
+
+		Context("when the endorser fails to endorse the proposal", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturns(nil, errors.New("latte"))
+			})
+
+			It("returns an error", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).To(MatchError("failed to endorse proposal: latte"))
+			})
+		})
+
+		Context("when the endorser returns a non-success status", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = &pb.Response{
+					Status:  500,
+					Message: "capuccino",
+				}
+				mockEndorserClient.ProcessProposalReturns(mockProposalResponse, nil)
+			})
+
+			It("returns an error", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).To(MatchError("query failed with status: 500 - capuccino"))
+			})
+		})
+
+		Context("when the endorser returns an unexpected result", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = &pb.Response{
+					Status:  200,
+					Payload: []byte("jibberish"),
+				}
+				mockEndorserClient.ProcessProposalReturns(mockProposalResponse, nil)
+			})
+
+			It("returns an error", func() {
+				err := collectionsAnalyzer.Analyze()
+				Expect(err).To(MatchError(ContainSubstring("failed to unmarshal proposal response's response payload")))
+			})
+		})
+	})
+
+	Describe("AnalyzeCollectionsCmd", func() {
+		var analyzeCollectionsCmd *cobra.Command
+
+		BeforeEach(func() {
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			Expect(err).To(BeNil())
+			analyzeCollectionsCmd = chaincode.AnalyzeCollectionsCmd(nil, cryptoProvider)
+			analyzeCollectionsCmd.SilenceErrors = true
+			analyzeCollectionsCmd.SilenceUsage = true
+			analyzeCollectionsCmd.SetArgs([]string{
+				"--channelID=testchannel",
+				"--name=testcc",
+				"--peerAddresses=querypeer1",
+				"--tlsRootCertFiles=tls1",
+			})
+		})
+
+		AfterEach(func() {
+			chaincode.ResetFlags()
+		})
+
+		It("sets up the collections analyzer and analyzes the update", func() {
+			err := analyzeCollectionsCmd.Execute()
+			Expect(err).To(MatchError(ContainSubstring("failed to retrieve endorser client")))
+		})
+
+		Context("when the collections config is invalid", func() {
+			BeforeEach(func() {
+				analyzeCollectionsCmd.SetArgs([]string{
+					"--collections-config=idontexist.json",
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			It("returns an error", func() {
+				err := analyzeCollectionsCmd.Execute()
+				Expect(err).To(MatchError("invalid collection configuration in file idontexist.json: could not read file 'idontexist.json': open idontexist.json: no such file or directory"))
+			})
+		})
+	})
+})
@@ -51,6 +51,7 @@ type ApproveForMyOrgInput struct {
 	ValidationParameterBytes []byte
 	CollectionConfigPackage  *pb.CollectionConfigPackage
 	InitRequired             bool
+	Metadata                 map[string][]byte
 	PeerAddresses            []string
 	WaitForEvent             bool
 	WaitForEventTimeout      time.Duration
@@ -85,6 +86,7 @@ func ApproveForMyOrgCmd(a *ApproverForMyOrg, cryptoProvider bccsp.BCCSP) *cobra.
 		Short: "Approve the chaincode definition for my org.",
 		Long:  "Approve the chaincode definition for my organization.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			a := a
 			if a == nil {
 				// set input from CLI flags
 				input, err := a.createInput()
@@ -138,6 +140,8 @@ func ApproveForMyOrgCmd(a *ApproverForMyOrg, cryptoProvider bccsp.BCCSP) *cobra.
 		"channel-config-policy",
 		"init-required",
 		"collections-config",
+		"metadata",
+		"arg-schema",
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
@@ -255,6 +259,11 @@ func (a *ApproverForMyOrg) createInput() (*ApproveForMyOrgInput, error) {
 		return nil, err
 	}
 
+	metadata, err := createDefinitionMetadata(definitionMetadata, argumentSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &ApproveForMyOrgInput{
 		ChannelID:                channelID,
 		Name:                     chaincodeName,
@@ -266,6 +275,7 @@ func (a *ApproverForMyOrg) createInput() (*ApproveForMyOrgInput, error) {
 		ValidationParameterBytes: policyBytes,
 		InitRequired:             initRequired,
 		CollectionConfigPackage:  ccp,
+		Metadata:                 metadata,
 		PeerAddresses:            peerAddresses,
 		WaitForEvent:             waitForEvent,
 		WaitForEventTimeout:      waitForEventTimeout,
@@ -305,6 +315,7 @@ func (a *ApproverForMyOrg) createProposal(inputTxID string) (proposal *pb.Propos
 		ValidationParameter: a.Input.ValidationParameterBytes,
 		InitRequired:        a.Input.InitRequired,
 		Collections:         a.Input.CollectionConfigPackage,
+		Metadata:            a.Input.Metadata,
 		Source:              ccsrc,
 	}
 
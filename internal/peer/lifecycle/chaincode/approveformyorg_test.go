@@ -406,6 +406,87 @@ var _ = Describe("ApproverForMyOrg", func() {
 				Expect(err).To(MatchError("invalid collection configuration in file idontexist.json: could not read file 'idontexist.json': open idontexist.json: no such file or directory"))
 			})
 		})
+
+		Context("when the argument schema is specified", func() {
+			BeforeEach(func() {
+				approveForMyOrgCmd.SetArgs([]string{
+					"--arg-schema=testdata/argschema.json",
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--version=testversion",
+					"--package-id=testpackageid",
+					"--sequence=1",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			It("sets up the approver for my org and attempts to approve the chaincode definition", func() {
+				err := approveForMyOrgCmd.Execute()
+				Expect(err).To(MatchError(ContainSubstring("failed to retrieve endorser client")))
+			})
+		})
+
+		Context("when the argument schema file does not exist", func() {
+			BeforeEach(func() {
+				approveForMyOrgCmd.SetArgs([]string{
+					"--arg-schema=idontexist.json",
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--version=testversion",
+					"--package-id=testpackageid",
+					"--sequence=1",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			It("returns an error", func() {
+				err := approveForMyOrgCmd.Execute()
+				Expect(err).To(MatchError("could not read argument schema file 'idontexist.json': open idontexist.json: no such file or directory"))
+			})
+		})
+
+		Context("when the argument schema is invalid", func() {
+			BeforeEach(func() {
+				approveForMyOrgCmd.SetArgs([]string{
+					"--arg-schema=testdata/connectionprofile.yaml",
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--version=testversion",
+					"--package-id=testpackageid",
+					"--sequence=1",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			It("returns an error", func() {
+				err := approveForMyOrgCmd.Execute()
+				Expect(err).To(MatchError(ContainSubstring("invalid argument schema in file testdata/connectionprofile.yaml: could not unmarshal argument schema")))
+			})
+		})
+
+		Context("when the argument schema is also given as metadata", func() {
+			BeforeEach(func() {
+				approveForMyOrgCmd.SetArgs([]string{
+					"--arg-schema=testdata/argschema.json",
+					"--metadata=argschema={}",
+					"--channelID=testchannel",
+					"--name=testcc",
+					"--version=testversion",
+					"--package-id=testpackageid",
+					"--sequence=1",
+					"--peerAddresses=querypeer1",
+					"--tlsRootCertFiles=tls1",
+				})
+			})
+
+			It("returns an error", func() {
+				err := approveForMyOrgCmd.Execute()
+				Expect(err).To(MatchError("cannot specify both \"--arg-schema\" and the metadata key 'argschema'"))
+			})
+		})
 	})
 })
 
@@ -21,6 +21,7 @@ const (
 	approveFuncName              = "ApproveChaincodeDefinitionForMyOrg"
 	commitFuncName               = "CommitChaincodeDefinition"
 	checkCommitReadinessFuncName = "CheckCommitReadiness"
+	analyzeCollectionsFuncName   = "AnalyzeCollectionConfigUpdate"
 )
 
 var logger = flogging.MustGetLogger("cli.lifecycle.chaincode")
@@ -37,11 +38,14 @@ func Cmd(cryptoProvider bccsp.BCCSP) *cobra.Command {
 	chaincodeCmd.AddCommand(InstallCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(QueryInstalledCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(GetInstalledPackageCmd(nil, cryptoProvider))
+	chaincodeCmd.AddCommand(QuerySBOMCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(ApproveForMyOrgCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(QueryApprovedCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(CheckCommitReadinessCmd(nil, cryptoProvider))
+	chaincodeCmd.AddCommand(AnalyzeCollectionsCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(CommitCmd(nil, cryptoProvider))
 	chaincodeCmd.AddCommand(QueryCommittedCmd(nil, cryptoProvider))
+	chaincodeCmd.AddCommand(MigrateCmd(nil, cryptoProvider))
 
 	return chaincodeCmd
 }
@@ -69,12 +73,16 @@ var (
 	initRequired          bool
 	output                string
 	outputDirectory       string
+	migrateCommit         bool
+	definitionMetadata    []string
+	argumentSchemaFile    string
+	allChannels           bool
 )
 
 var chaincodeCmd = &cobra.Command{
 	Use:   "chaincode",
-	Short: "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|approveformyorg|queryapproved|checkcommitreadiness|commit|querycommitted",
-	Long:  "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|approveformyorg|queryapproved|checkcommitreadiness|commit|querycommitted",
+	Short: "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|querysbom|approveformyorg|queryapproved|checkcommitreadiness|analyzecollections|commit|querycommitted|migrate",
+	Long:  "Perform chaincode operations: package|install|queryinstalled|getinstalledpackage|querysbom|approveformyorg|queryapproved|checkcommitreadiness|analyzecollections|commit|querycommitted|migrate",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		common.InitCmd(cmd, args)
 		common.SetOrdererEnv(cmd, args)
@@ -116,6 +124,10 @@ func ResetFlags() {
 	flags.BoolVarP(&initRequired, "init-required", "", false, "Whether the chaincode requires invoking 'init'")
 	flags.StringVarP(&output, "output", "O", "", "The output format for query results. Default is human-readable plain-text. json is currently the only supported format.")
 	flags.StringVarP(&outputDirectory, "output-directory", "", "", "The output directory to use when writing a chaincode install package to disk. Default is the current working directory.")
+	flags.BoolVarP(&migrateCommit, "commit", "", false, "Whether to commit the migrated chaincode definition on the channel once approved for my org")
+	flags.BoolVarP(&allChannels, "all-channels", "", false, "Whether to query the committed chaincode definitions of all the channels the peer has joined")
+	flags.StringArrayVarP(&definitionMetadata, "metadata", "", []string{}, "Metadata of the chaincode definition as key=value, agreed across the organizations like the other parameters. Requires the V2_2_CHAINCODE_METADATA channel capability. May be repeated")
+	flags.StringVarP(&argumentSchemaFile, "arg-schema", "", "", "The path to the JSON file holding the schema the peers validate the arguments of the chaincode invocations against, stored in the metadata of the chaincode definition")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
@@ -54,6 +54,7 @@ type CommitReadinessCheckInput struct {
 	ValidationParameterBytes []byte
 	CollectionConfigPackage  *pb.CollectionConfigPackage
 	InitRequired             bool
+	Metadata                 map[string][]byte
 	PeerAddresses            []string
 	TxID                     string
 	OutputFormat             string
@@ -88,6 +89,7 @@ func CheckCommitReadinessCmd(c *CommitReadinessChecker, cryptoProvider bccsp.BCC
 		Short: "Check whether a chaincode definition is ready to be committed on a channel.",
 		Long:  "Check whether a chaincode definition is ready to be committed on a channel.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			c := c
 			if c == nil {
 				// set input from CLI flags
 				input, err := c.createInput()
@@ -133,6 +135,8 @@ func CheckCommitReadinessCmd(c *CommitReadinessChecker, cryptoProvider bccsp.BCC
 		"channel-config-policy",
 		"init-required",
 		"collections-config",
+		"metadata",
+		"arg-schema",
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
@@ -225,6 +229,11 @@ func (c *CommitReadinessChecker) createInput() (*CommitReadinessCheckInput, erro
 		return nil, err
 	}
 
+	metadata, err := createDefinitionMetadata(definitionMetadata, argumentSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &CommitReadinessCheckInput{
 		ChannelID:                channelID,
 		Name:                     chaincodeName,
@@ -236,6 +245,7 @@ func (c *CommitReadinessChecker) createInput() (*CommitReadinessCheckInput, erro
 		ValidationParameterBytes: policyBytes,
 		InitRequired:             initRequired,
 		CollectionConfigPackage:  ccp,
+		Metadata:                 metadata,
 		PeerAddresses:            peerAddresses,
 		OutputFormat:             output,
 	}
@@ -253,6 +263,7 @@ func (c *CommitReadinessChecker) createProposal(inputTxID string) (*pb.Proposal,
 		ValidationParameter: c.Input.ValidationParameterBytes,
 		InitRequired:        c.Input.InitRequired,
 		Collections:         c.Input.CollectionConfigPackage,
+		Metadata:            c.Input.Metadata,
 	}
 
 	argsBytes, err := proto.Marshal(args)
@@ -187,9 +187,13 @@ func (c *ClientConnections) setOrdererClient() error {
 			return errors.Errorf("no orderer endpoints retrieved for channel %s, pass orderer endpoint with -o flag instead", channelID)
 		}
 
-		logger.Infof("Retrieved channel (%s) orderer endpoint: %s", channelID, orderingEndpoints[0])
-		// override viper env
-		viper.Set("orderer.address", orderingEndpoints[0])
+		logger.Infof("Retrieved channel (%s) orderer endpoints: %s", channelID, orderingEndpoints)
+		broadcastClient, err := common.GetBroadcastClientForEndpoints(orderingEndpoints)
+		if err != nil {
+			return errors.WithMessage(err, "failed to retrieve broadcast client")
+		}
+		c.BroadcastClient = broadcastClient
+		return nil
 	}
 
 	broadcastClient, err := common.GetBroadcastClient()
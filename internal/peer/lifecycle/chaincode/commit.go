@@ -51,6 +51,7 @@ type CommitInput struct {
 	ValidationParameterBytes []byte
 	CollectionConfigPackage  *pb.CollectionConfigPackage
 	InitRequired             bool
+	Metadata                 map[string][]byte
 	PeerAddresses            []string
 	WaitForEvent             bool
 	WaitForEventTimeout      time.Duration
@@ -85,6 +86,7 @@ func CommitCmd(c *Committer, cryptoProvider bccsp.BCCSP) *cobra.Command {
 		Short: "Commit the chaincode definition on the channel.",
 		Long:  "Commit the chaincode definition on the channel.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			c := c
 			if c == nil {
 				// set input from CLI flags
 				input, err := c.createInput()
@@ -137,6 +139,8 @@ func CommitCmd(c *Committer, cryptoProvider bccsp.BCCSP) *cobra.Command {
 		"channel-config-policy",
 		"init-required",
 		"collections-config",
+		"metadata",
+		"arg-schema",
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
@@ -253,6 +257,11 @@ func (c *Committer) createInput() (*CommitInput, error) {
 		return nil, err
 	}
 
+	metadata, err := createDefinitionMetadata(definitionMetadata, argumentSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &CommitInput{
 		ChannelID:                channelID,
 		Name:                     chaincodeName,
@@ -263,6 +272,7 @@ func (c *Committer) createInput() (*CommitInput, error) {
 		ValidationParameterBytes: policyBytes,
 		InitRequired:             initRequired,
 		CollectionConfigPackage:  ccp,
+		Metadata:                 metadata,
 		PeerAddresses:            peerAddresses,
 		WaitForEvent:             waitForEvent,
 		WaitForEventTimeout:      waitForEventTimeout,
@@ -281,6 +291,7 @@ func (c *Committer) createProposal(inputTxID string) (proposal *pb.Proposal, txI
 		ValidationParameter: c.Input.ValidationParameterBytes,
 		InitRequired:        c.Input.InitRequired,
 		Collections:         c.Input.CollectionConfigPackage,
+		Metadata:            c.Input.Metadata,
 	}
 
 	argsBytes, err := proto.Marshal(args)
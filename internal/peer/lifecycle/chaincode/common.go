@@ -11,10 +11,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/core/chaincode/argschema"
 	"github.com/hyperledger/fabric/internal/peer/chaincode"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -107,6 +111,58 @@ func createPolicyBytes(signaturePolicy, channelConfigPolicy string) ([]byte, err
 	return policyBytes, nil
 }
 
+// createDefinitionMetadata parses the key=value entries of the metadata of a
+// chaincode definition, and adds the argument schema read from
+// argumentSchemaFile, if any.
+func createDefinitionMetadata(entries []string, argumentSchemaFile string) (map[string][]byte, error) {
+	if len(entries) == 0 && argumentSchemaFile == "" {
+		return nil, nil
+	}
+
+	metadata := map[string][]byte{}
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid metadata '%s', expected key=value", entry)
+		}
+		if _, ok := metadata[kv[0]]; ok {
+			return nil, errors.Errorf("duplicate metadata key '%s'", kv[0])
+		}
+		metadata[kv[0]] = []byte(kv[1])
+	}
+
+	if argumentSchemaFile != "" {
+		if _, ok := metadata[argschema.MetadataKey]; ok {
+			return nil, errors.Errorf("cannot specify both \"--arg-schema\" and the metadata key '%s'", argschema.MetadataKey)
+		}
+		schema, err := ioutil.ReadFile(argumentSchemaFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read argument schema file '%s'", argumentSchemaFile)
+		}
+		if _, err := argschema.Parse(schema); err != nil {
+			return nil, errors.WithMessagef(err, "invalid argument schema in file %s", argumentSchemaFile)
+		}
+		metadata[argschema.MetadataKey] = schema
+	}
+	return metadata, nil
+}
+
+// formatDefinitionMetadata prints the metadata of a chaincode definition
+// sorted by key.
+func formatDefinitionMetadata(metadata map[string][]byte) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, fmt.Sprintf("%s: %s", key, metadata[key]))
+	}
+	return strings.Join(entries, ", ")
+}
+
 func createCollectionConfigPackage(collectionsConfigFile string) (*pb.CollectionConfigPackage, error) {
 	var ccp *pb.CollectionConfigPackage
 	if collectionsConfigFile != "" {
@@ -56,6 +56,7 @@ func GetInstalledPackageCmd(i *InstalledPackageGetter, cryptoProvider bccsp.BCCS
 		Short: "Get an installed chaincode package from a peer.",
 		Long:  "Get an installed chaincode package from a peer.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			i := i
 			if i == nil {
 				ccInput := &ClientConnectionsInput{
 					CommandName:           cmd.Name(),
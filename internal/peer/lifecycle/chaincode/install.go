@@ -60,6 +60,7 @@ func InstallCmd(i *Installer, cryptoProvider bccsp.BCCSP) *cobra.Command {
 		Long:      "Install a chaincode on a peer.",
 		ValidArgs: []string{"1"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			i := i
 			if i == nil {
 				ccInput := &ClientConnectionsInput{
 					CommandName:           cmd.Name(),
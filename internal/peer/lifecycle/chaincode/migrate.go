@@ -0,0 +1,373 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/tls"
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	lsccName                    = "lscc"
+	lsccGetCCDataFuncName       = "getccdata"
+	lsccGetCollectionsFuncName  = "GetCollectionsConfig"
+	lsccCollectionsNotDefMsgFmt = "collections config not defined for chaincode %s"
+)
+
+// LegacyDefinition holds the parts of the definition of a chaincode
+// instantiated with the legacy lifecycle (LSCC) which are carried over
+// to the new chaincode lifecycle
+type LegacyDefinition struct {
+	Version                 string
+	EndorsementPlugin       string
+	ValidationPlugin        string
+	EndorsementPolicy       *cb.SignaturePolicyEnvelope
+	CollectionConfigPackage *pb.CollectionConfigPackage
+}
+
+// Migrator holds the dependencies needed to migrate a chaincode
+// instantiated with the legacy lifecycle to the new chaincode
+// lifecycle
+type Migrator struct {
+	Certificate     tls.Certificate
+	Command         *cobra.Command
+	BroadcastClient common.BroadcastClient
+	DeliverClients  []pb.DeliverClient
+	EndorserClients []EndorserClient
+	Input           *MigrateInput
+	Signer          Signer
+	Writer          io.Writer
+}
+
+// MigrateInput holds all of the input parameters for migrating a
+// legacy chaincode. When the version is empty, the version the
+// chaincode was instantiated with is kept. When Commit is set, the
+// chaincode definition is committed once approved for the organization
+type MigrateInput struct {
+	ChannelID           string
+	Name                string
+	Version             string
+	PackageID           string
+	Sequence            int64
+	Commit              bool
+	PeerAddresses       []string
+	WaitForEvent        bool
+	WaitForEventTimeout time.Duration
+}
+
+// Validate the input for a migration
+func (m *MigrateInput) Validate() error {
+	if m.ChannelID == "" {
+		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	if m.Name == "" {
+		return errors.New("The required parameter 'name' is empty. Rerun the command with -n flag")
+	}
+
+	if m.PackageID == "" {
+		return errors.New("The required parameter 'package-id' is empty. Rerun the command with --package-id flag")
+	}
+
+	return nil
+}
+
+// MigrateCmd returns the cobra command for chaincode Migrate
+func MigrateCmd(m *Migrator, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	chaincodeMigrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate a chaincode instantiated with the legacy lifecycle to the new chaincode lifecycle.",
+		Long: "Migrate a chaincode instantiated with the legacy lifecycle to the new chaincode lifecycle. " +
+			"The endorsement policy and the collections config of the legacy chaincode are approved for my organization " +
+			"in a single chaincode definition and, optionally, committed on the channel.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m := m
+			if m == nil {
+				input := &MigrateInput{
+					ChannelID:           channelID,
+					Name:                chaincodeName,
+					Version:             chaincodeVersion,
+					PackageID:           packageID,
+					Sequence:            int64(sequence),
+					Commit:              migrateCommit,
+					PeerAddresses:       peerAddresses,
+					WaitForEvent:        waitForEvent,
+					WaitForEventTimeout: waitForEventTimeout,
+				}
+
+				ccInput := &ClientConnectionsInput{
+					CommandName:           cmd.Name(),
+					EndorserRequired:      true,
+					OrdererRequired:       true,
+					ChannelID:             channelID,
+					PeerAddresses:         peerAddresses,
+					TLSRootCertFiles:      tlsRootCertFiles,
+					ConnectionProfilePath: connectionProfilePath,
+					TLSEnabled:            viper.GetBool("peer.tls.enabled"),
+				}
+
+				cc, err := NewClientConnections(ccInput, cryptoProvider)
+				if err != nil {
+					return err
+				}
+
+				endorserClients := make([]EndorserClient, len(cc.EndorserClients))
+				for i, e := range cc.EndorserClients {
+					endorserClients[i] = e
+				}
+
+				m = &Migrator{
+					Command:         cmd,
+					Input:           input,
+					Certificate:     cc.Certificate,
+					BroadcastClient: cc.BroadcastClient,
+					DeliverClients:  cc.DeliverClients,
+					EndorserClients: endorserClients,
+					Signer:          cc.Signer,
+					Writer:          os.Stdout,
+				}
+			}
+			return m.Migrate()
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"name",
+		"version",
+		"package-id",
+		"sequence",
+		"commit",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+		"waitForEvent",
+		"waitForEventTimeout",
+	}
+	attachFlags(chaincodeMigrateCmd, flagList)
+
+	return chaincodeMigrateCmd
+}
+
+// Migrate reads the definition of a legacy chaincode from LSCC and
+// approves, and optionally commits, an equivalent chaincode definition
+// for the new chaincode lifecycle. The endorsement policy and the
+// collections config are part of the same chaincode definition, hence
+// they are carried over together on the channel
+func (m *Migrator) Migrate() error {
+	err := m.Input.Validate()
+	if err != nil {
+		return err
+	}
+
+	if m.Command != nil {
+		// Parsing of the command line is done so silence cmd usage
+		m.Command.SilenceUsage = true
+	}
+
+	if len(m.EndorserClients) == 0 {
+		// this should only be empty due to a programming bug
+		return errors.New("no endorser clients provided")
+	}
+
+	legacy, err := m.LegacyDefinition()
+	if err != nil {
+		return errors.WithMessagef(err, "failed to retrieve the legacy definition of chaincode '%s'", m.Input.Name)
+	}
+
+	policyBytes, err := proto.Marshal(&pb.ApplicationPolicy{
+		Type: &pb.ApplicationPolicy_SignaturePolicy{
+			SignaturePolicy: legacy.EndorsementPolicy,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal endorsement policy")
+	}
+
+	version := m.Input.Version
+	if version == "" {
+		version = legacy.Version
+	}
+	sequence := m.Input.Sequence
+	if sequence == 0 {
+		// the chaincode is not yet defined in the new lifecycle
+		sequence = 1
+	}
+
+	// the chaincode was initialized when it was instantiated,
+	// hence the migrated definition does not require init
+	approver := &ApproverForMyOrg{
+		Certificate:     m.Certificate,
+		BroadcastClient: m.BroadcastClient,
+		DeliverClients:  m.DeliverClients,
+		EndorserClients: m.EndorserClients,
+		Signer:          m.Signer,
+		Input: &ApproveForMyOrgInput{
+			ChannelID:                m.Input.ChannelID,
+			Name:                     m.Input.Name,
+			Version:                  version,
+			PackageID:                m.Input.PackageID,
+			Sequence:                 sequence,
+			EndorsementPlugin:        legacy.EndorsementPlugin,
+			ValidationPlugin:         legacy.ValidationPlugin,
+			ValidationParameterBytes: policyBytes,
+			CollectionConfigPackage:  legacy.CollectionConfigPackage,
+			PeerAddresses:            m.Input.PeerAddresses,
+			WaitForEvent:             m.Input.WaitForEvent,
+			WaitForEventTimeout:      m.Input.WaitForEventTimeout,
+		},
+	}
+	if err := approver.Approve(); err != nil {
+		return errors.WithMessage(err, "failed to approve the migrated chaincode definition")
+	}
+	m.printf("Approved the migrated definition of chaincode '%s' on channel '%s': Version: %s, Sequence: %d, Collections: %d\n",
+		m.Input.Name, m.Input.ChannelID, version, sequence, len(legacy.CollectionConfigPackage.GetConfig()))
+
+	if !m.Input.Commit {
+		return nil
+	}
+
+	committer := &Committer{
+		Certificate:     m.Certificate,
+		BroadcastClient: m.BroadcastClient,
+		DeliverClients:  m.DeliverClients,
+		EndorserClients: m.EndorserClients,
+		Signer:          m.Signer,
+		Input: &CommitInput{
+			ChannelID:                m.Input.ChannelID,
+			Name:                     m.Input.Name,
+			Version:                  version,
+			Sequence:                 sequence,
+			EndorsementPlugin:        legacy.EndorsementPlugin,
+			ValidationPlugin:         legacy.ValidationPlugin,
+			ValidationParameterBytes: policyBytes,
+			CollectionConfigPackage:  legacy.CollectionConfigPackage,
+			PeerAddresses:            m.Input.PeerAddresses,
+			WaitForEvent:             m.Input.WaitForEvent,
+			WaitForEventTimeout:      m.Input.WaitForEventTimeout,
+		},
+	}
+	if err := committer.Commit(); err != nil {
+		return errors.WithMessage(err, "failed to commit the migrated chaincode definition")
+	}
+	m.printf("Committed the migrated definition of chaincode '%s' on channel '%s'\n", m.Input.Name, m.Input.ChannelID)
+
+	return nil
+}
+
+// LegacyDefinition queries LSCC on the first peer for the definition
+// of the chaincode on the channel
+func (m *Migrator) LegacyDefinition() (*LegacyDefinition, error) {
+	response, err := m.queryLSCC(lsccGetCCDataFuncName)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status != int32(cb.Status_SUCCESS) {
+		return nil, errors.Errorf("query failed with status: %d - %s", response.Status, response.Message)
+	}
+
+	cd := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(response.Payload, cd); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chaincode data")
+	}
+	policy := &cb.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(cd.Policy, policy); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal endorsement policy")
+	}
+
+	legacy := &LegacyDefinition{
+		Version:           cd.Version,
+		EndorsementPlugin: cd.Escc,
+		ValidationPlugin:  cd.Vscc,
+		EndorsementPolicy: policy,
+	}
+
+	response, err = m.queryLSCC(lsccGetCollectionsFuncName)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case response.Status == int32(cb.Status_SUCCESS):
+		ccp := &pb.CollectionConfigPackage{}
+		if err := proto.Unmarshal(response.Payload, ccp); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal collections config")
+		}
+		legacy.CollectionConfigPackage = ccp
+	case strings.Contains(response.Message, fmt.Sprintf(lsccCollectionsNotDefMsgFmt, m.Input.Name)):
+		// the chaincode has no collections
+	default:
+		return nil, errors.Errorf("query failed with status: %d - %s", response.Status, response.Message)
+	}
+
+	return legacy, nil
+}
+
+func (m *Migrator) queryLSCC(function string) (*pb.Response, error) {
+	if m.Signer == nil {
+		return nil, errors.New("nil signer provided")
+	}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: lsccName},
+			Input: &pb.ChaincodeInput{
+				Args: [][]byte{[]byte(function), []byte(m.Input.ChannelID), []byte(m.Input.Name)},
+			},
+		},
+	}
+
+	creatorBytes, err := m.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	proposal, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, m.Input.ChannelID, cis, creatorBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create ChaincodeInvocationSpec proposal")
+	}
+
+	signedProposal, err := signProposal(proposal, m.Signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create signed proposal")
+	}
+
+	proposalResponse, err := m.EndorserClients[0].ProcessProposal(context.Background(), signedProposal)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to endorse proposal")
+	}
+
+	if proposalResponse == nil {
+		return nil, errors.New("received nil proposal response")
+	}
+
+	if proposalResponse.Response == nil {
+		return nil, errors.New("received proposal response with nil response")
+	}
+
+	return proposalResponse.Response, nil
+}
+
+func (m *Migrator) printf(format string, a ...interface{}) {
+	if m.Writer != nil {
+		fmt.Fprintf(m.Writer, format, a...)
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Migrator", func() {
+	Describe("Migrate", func() {
+		var (
+			mockEndorserClient  *mock.EndorserClient
+			mockSigner          *mock.Signer
+			mockBroadcastClient *mock.BroadcastClient
+			input               *chaincode.MigrateInput
+			migrator            *chaincode.Migrator
+			policy              *cb.SignaturePolicyEnvelope
+			collections         *pb.CollectionConfigPackage
+			buffer              *gbytes.Buffer
+		)
+
+		successResponse := func(payload []byte) *pb.ProposalResponse {
+			return &pb.ProposalResponse{
+				Response:    &pb.Response{Status: 200, Payload: payload},
+				Endorsement: &pb.Endorsement{},
+			}
+		}
+
+		unmarshalArgs := func(call int, args proto.Message) string {
+			_, signedProposal, _ := mockEndorserClient.ProcessProposalArgsForCall(call)
+			proposal := &pb.Proposal{}
+			err := proto.Unmarshal(signedProposal.ProposalBytes, proposal)
+			Expect(err).NotTo(HaveOccurred())
+			payload, err := protoutil.UnmarshalChaincodeProposalPayload(proposal.Payload)
+			Expect(err).NotTo(HaveOccurred())
+			cis, err := protoutil.UnmarshalChaincodeInvocationSpec(payload.Input)
+			Expect(err).NotTo(HaveOccurred())
+			ccArgs := cis.ChaincodeSpec.Input.Args
+			if args != nil {
+				err = proto.Unmarshal(ccArgs[1], args)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			return cis.ChaincodeSpec.ChaincodeId.Name + "." + string(ccArgs[0])
+		}
+
+		BeforeEach(func() {
+			policy = policydsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"})
+			collections = &pb.CollectionConfigPackage{
+				Config: []*pb.CollectionConfig{
+					{
+						Payload: &pb.CollectionConfig_StaticCollectionConfig{
+							StaticCollectionConfig: &pb.StaticCollectionConfig{Name: "coll1", RequiredPeerCount: 1, MaximumPeerCount: 2},
+						},
+					},
+				},
+			}
+
+			cd := &ccprovider.ChaincodeData{
+				Name:    "testcc",
+				Version: "1.0",
+				Escc:    "escc",
+				Vscc:    "vscc",
+				Policy:  protoutil.MarshalOrPanic(policy),
+			}
+			mockEndorserClient = &mock.EndorserClient{}
+			mockEndorserClient.ProcessProposalReturns(successResponse(nil), nil)
+			mockEndorserClient.ProcessProposalReturnsOnCall(0, successResponse(protoutil.MarshalOrPanic(cd)), nil)
+			mockEndorserClient.ProcessProposalReturnsOnCall(1, successResponse(protoutil.MarshalOrPanic(collections)), nil)
+
+			mockSigner = &mock.Signer{}
+			mockBroadcastClient = &mock.BroadcastClient{}
+			buffer = gbytes.NewBuffer()
+
+			input = &chaincode.MigrateInput{
+				ChannelID: "testchannel",
+				Name:      "testcc",
+				PackageID: "testpackageid",
+			}
+
+			migrator = &chaincode.Migrator{
+				BroadcastClient: mockBroadcastClient,
+				DeliverClients:  []pb.DeliverClient{&mock.PeerDeliverClient{}},
+				EndorserClients: []chaincode.EndorserClient{mockEndorserClient},
+				Input:           input,
+				Signer:          mockSigner,
+				Writer:          buffer,
+			}
+		})
+
+		It("approves a definition carrying over the legacy endorsement policy and collections", func() {
+			err := migrator.Migrate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buffer).To(gbytes.Say(`Approved the migrated definition of chaincode 'testcc' on channel 'testchannel': Version: 1.0, Sequence: 1, Collections: 1`))
+
+			Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(3))
+			Expect(unmarshalArgs(0, nil)).To(Equal("lscc.getccdata"))
+			Expect(unmarshalArgs(1, nil)).To(Equal("lscc.GetCollectionsConfig"))
+
+			args := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+			Expect(unmarshalArgs(2, args)).To(Equal("_lifecycle.ApproveChaincodeDefinitionForMyOrg"))
+			Expect(args.Name).To(Equal("testcc"))
+			Expect(args.Version).To(Equal("1.0"))
+			Expect(args.Sequence).To(Equal(int64(1)))
+			Expect(args.EndorsementPlugin).To(Equal("escc"))
+			Expect(args.ValidationPlugin).To(Equal("vscc"))
+			Expect(args.InitRequired).To(BeFalse())
+			Expect(args.Source.GetLocalPackage().PackageId).To(Equal("testpackageid"))
+			Expect(proto.Equal(args.Collections, collections)).To(BeTrue())
+			appPolicy := &pb.ApplicationPolicy{}
+			err = proto.Unmarshal(args.ValidationParameter, appPolicy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proto.Equal(appPolicy.GetSignaturePolicy(), policy)).To(BeTrue())
+
+			Expect(mockBroadcastClient.SendCallCount()).To(Equal(1))
+		})
+
+		Context("when the version and sequence are provided", func() {
+			BeforeEach(func() {
+				input.Version = "2.0"
+				input.Sequence = 3
+			})
+
+			It("overrides the legacy version and the initial sequence", func() {
+				err := migrator.Migrate()
+				Expect(err).NotTo(HaveOccurred())
+
+				args := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+				unmarshalArgs(2, args)
+				Expect(args.Version).To(Equal("2.0"))
+				Expect(args.Sequence).To(Equal(int64(3)))
+			})
+		})
+
+		Context("when the legacy chaincode has no collections", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturnsOnCall(1, &pb.ProposalResponse{
+					Response: &pb.Response{Status: 500, Message: "collections config not defined for chaincode testcc"},
+				}, nil)
+			})
+
+			It("approves a definition without collections", func() {
+				err := migrator.Migrate()
+				Expect(err).NotTo(HaveOccurred())
+
+				args := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+				unmarshalArgs(2, args)
+				Expect(args.Collections).To(BeNil())
+			})
+		})
+
+		Context("when commit is requested", func() {
+			BeforeEach(func() {
+				input.Commit = true
+			})
+
+			It("commits the definition once approved", func() {
+				err := migrator.Migrate()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer).To(gbytes.Say(`Committed the migrated definition of chaincode 'testcc' on channel 'testchannel'`))
+
+				Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(4))
+				args := &lb.CommitChaincodeDefinitionArgs{}
+				Expect(unmarshalArgs(3, args)).To(Equal("_lifecycle.CommitChaincodeDefinition"))
+				Expect(args.Version).To(Equal("1.0"))
+				Expect(args.Sequence).To(Equal(int64(1)))
+				Expect(proto.Equal(args.Collections, collections)).To(BeTrue())
+				Expect(mockBroadcastClient.SendCallCount()).To(Equal(2))
+			})
+
+			Context("when the commit fails", func() {
+				BeforeEach(func() {
+					mockEndorserClient.ProcessProposalReturnsOnCall(3, nil, errors.New("latte"))
+				})
+
+				It("returns an error", func() {
+					err := migrator.Migrate()
+					Expect(err).To(MatchError("failed to commit the migrated chaincode definition: failed to endorse proposal: latte"))
+				})
+			})
+		})
+
+		Context("when the package ID is not provided", func() {
+			BeforeEach(func() {
+				input.PackageID = ""
+			})
+
+			It("returns an error", func() {
+				err := migrator.Migrate()
+				Expect(err).To(MatchError("The required parameter 'package-id' is empty. Rerun the command with --package-id flag"))
+			})
+		})
+
+		Context("when the chaincode is not instantiated with the legacy lifecycle", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturnsOnCall(0, &pb.ProposalResponse{
+					Response: &pb.Response{Status: 500, Message: "could not find chaincode with name 'testcc'"},
+				}, nil)
+			})
+
+			It("returns an error", func() {
+				err := migrator.Migrate()
+				Expect(err).To(MatchError("failed to retrieve the legacy definition of chaincode 'testcc': query failed with status: 500 - could not find chaincode with name 'testcc'"))
+				Expect(mockBroadcastClient.SendCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the collections query fails", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturnsOnCall(1, &pb.ProposalResponse{
+					Response: &pb.Response{Status: 500, Message: "access denied"},
+				}, nil)
+			})
+
+			It("returns an error", func() {
+				err := migrator.Migrate()
+				Expect(err).To(MatchError("failed to retrieve the legacy definition of chaincode 'testcc': query failed with status: 500 - access denied"))
+			})
+		})
+
+		Context("when the legacy chaincode data cannot be unmarshaled", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturnsOnCall(0, successResponse([]byte("garbage")), nil)
+			})
+
+			It("returns an error", func() {
+				err := migrator.Migrate()
+				Expect(err).To(MatchError(ContainSubstring("failed to unmarshal chaincode data")))
+			})
+		})
+
+		Context("when the endorser fails", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturnsOnCall(0, nil, errors.New("cappuccino"))
+			})
+
+			It("returns an error", func() {
+				err := migrator.Migrate()
+				Expect(err).To(MatchError("failed to retrieve the legacy definition of chaincode 'testcc': failed to endorse proposal: cappuccino"))
+			})
+		})
+
+		Context("when the approval fails", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturnsOnCall(2, nil, errors.New("mocha"))
+			})
+
+			It("returns an error", func() {
+				err := migrator.Migrate()
+				Expect(err).To(MatchError("failed to approve the migrated chaincode definition: failed to endorse proposal: mocha"))
+			})
+		})
+	})
+
+	Describe("MigrateCmd", func() {
+		var migrateCmd *cobra.Command
+
+		BeforeEach(func() {
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			Expect(err).To(BeNil())
+			migrateCmd = chaincode.MigrateCmd(nil, cryptoProvider)
+			migrateCmd.SilenceErrors = true
+			migrateCmd.SilenceUsage = true
+			migrateCmd.SetArgs([]string{
+				"--channelID=testchannel",
+				"--name=testcc",
+				"--package-id=testpackageid",
+				"--commit",
+			})
+		})
+
+		AfterEach(func() {
+			chaincode.ResetFlags()
+		})
+
+		It("attempts to connect to the endorser", func() {
+			err := migrateCmd.Execute()
+			Expect(err).To(MatchError(ContainSubstring("failed to retrieve endorser client")))
+		})
+	})
+})
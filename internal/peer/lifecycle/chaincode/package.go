@@ -74,6 +74,7 @@ func PackageCmd(p *Packager) *cobra.Command {
 		Long:      "Package a chaincode and write the package to a file.",
 		ValidArgs: []string{"1"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			p := p
 			if p == nil {
 				pr := packaging.NewRegistry(packaging.SupportedPlatforms...)
 
@@ -49,6 +49,7 @@ func QueryApprovedCmd(a *ApprovedQuerier, cryptoProvider bccsp.BCCSP) *cobra.Com
 		Short: "Query an org's approved chaincode definition from its peer.",
 		Long:  "Query an organization's approved chaincode definition from its peer.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			a := a
 			if a == nil {
 				ccInput := &ClientConnectionsInput{
 					CommandName:           cmd.Name(),
@@ -163,6 +164,9 @@ func (a *ApprovedQuerier) printResponse(proposalResponse *pb.ProposalResponse) e
 	}
 	fmt.Fprintf(a.Writer, "sequence: %d, version: %s, init-required: %t, package-id: %s, endorsement plugin: %s, validation plugin: %s\n",
 		result.Sequence, result.Version, result.InitRequired, packageID, result.EndorsementPlugin, result.ValidationPlugin)
+	if len(result.Metadata) > 0 {
+		fmt.Fprintf(a.Writer, "metadata: [%s]\n", formatDefinitionMetadata(result.Metadata))
+	}
 	return nil
 }
 
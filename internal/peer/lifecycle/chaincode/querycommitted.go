@@ -8,6 +8,7 @@ package chaincode
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -39,6 +40,9 @@ type CommittedQueryInput struct {
 	ChannelID    string
 	Name         string
 	OutputFormat string
+	// AllChannels queries the committed chaincode definitions of all the
+	// channels the peer has joined, optionally restricted to the named chaincode
+	AllChannels bool
 }
 
 // QueryCommittedCmd returns the cobra command for
@@ -48,8 +52,9 @@ func QueryCommittedCmd(c *CommittedQuerier, cryptoProvider bccsp.BCCSP) *cobra.C
 	chaincodeQueryCommittedCmd := &cobra.Command{
 		Use:   "querycommitted",
 		Short: "Query the committed chaincode definitions by channel on a peer.",
-		Long:  "Query the committed chaincode definitions by channel on a peer. Optional: provide a chaincode name to query a specific definition.",
+		Long:  "Query the committed chaincode definitions by channel on a peer. Optional: provide a chaincode name to query a specific definition, or query the definitions of all the channels the peer has joined.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			c := c
 			if c == nil {
 				ccInput := &ClientConnectionsInput{
 					CommandName:           cmd.Name(),
@@ -70,6 +75,7 @@ func QueryCommittedCmd(c *CommittedQuerier, cryptoProvider bccsp.BCCSP) *cobra.C
 					ChannelID:    channelID,
 					Name:         chaincodeName,
 					OutputFormat: output,
+					AllChannels:  allChannels,
 				}
 
 				c = &CommittedQuerier{
@@ -91,6 +97,7 @@ func QueryCommittedCmd(c *CommittedQuerier, cryptoProvider bccsp.BCCSP) *cobra.C
 		"tlsRootCertFiles",
 		"connectionProfile",
 		"output",
+		"all-channels",
 	}
 	attachFlags(chaincodeQueryCommittedCmd, flagList)
 
@@ -144,6 +151,18 @@ func (c *CommittedQuerier) Query() error {
 }
 
 func (c *CommittedQuerier) printResponseAsJSON(proposalResponse *pb.ProposalResponse) error {
+	if c.Input.AllChannels {
+		result, err := c.allChannelsResult(proposalResponse)
+		if err != nil {
+			return err
+		}
+		bytes, err := json.MarshalIndent(result, "", "\t")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal output")
+		}
+		fmt.Fprintf(c.Writer, "%s\n", string(bytes))
+		return nil
+	}
 	if c.Input.Name != "" {
 		return printResponseAsJSON(proposalResponse, &lb.QueryChaincodeDefinitionResult{}, c.Writer)
 	}
@@ -153,6 +172,25 @@ func (c *CommittedQuerier) printResponseAsJSON(proposalResponse *pb.ProposalResp
 // printResponse prints the information included in the response
 // from the server as human readable plain-text.
 func (c *CommittedQuerier) printResponse(proposalResponse *pb.ProposalResponse) error {
+	if c.Input.AllChannels {
+		result, err := c.allChannelsResult(proposalResponse)
+		if err != nil {
+			return err
+		}
+		if c.Input.Name != "" {
+			fmt.Fprintf(c.Writer, "Committed chaincode definitions for chaincode '%s' on all channels:\n", c.Input.Name)
+		} else {
+			fmt.Fprintf(c.Writer, "Committed chaincode definitions on all channels:\n")
+		}
+		for _, channel := range result.Channels {
+			for _, cd := range channel.ChaincodeDefinitions {
+				fmt.Fprintf(c.Writer, "Channel: %s, Name: %s, ", channel.ChannelId, cd.Name)
+				c.printSingleChaincodeDefinition(cd)
+				fmt.Fprintf(c.Writer, "\n")
+			}
+		}
+		return nil
+	}
 	if c.Input.Name != "" {
 		result := &lb.QueryChaincodeDefinitionResult{}
 		err := proto.Unmarshal(proposalResponse.Response.Payload, result)
@@ -185,10 +223,40 @@ type ChaincodeDefinition interface {
 	GetSequence() int64
 	GetEndorsementPlugin() string
 	GetValidationPlugin() string
+	GetMetadata() map[string][]byte
 }
 
 func (c *CommittedQuerier) printSingleChaincodeDefinition(cd ChaincodeDefinition) {
 	fmt.Fprintf(c.Writer, "Version: %s, Sequence: %d, Endorsement Plugin: %s, Validation Plugin: %s", cd.GetVersion(), cd.GetSequence(), cd.GetEndorsementPlugin(), cd.GetValidationPlugin())
+	if len(cd.GetMetadata()) > 0 {
+		fmt.Fprintf(c.Writer, ", Metadata: [%s]", formatDefinitionMetadata(cd.GetMetadata()))
+	}
+}
+
+// allChannelsResult unmarshals the committed chaincode definitions of all the
+// channels, and leaves out those of the other chaincodes if a name was given.
+func (c *CommittedQuerier) allChannelsResult(proposalResponse *pb.ProposalResponse) (*lb.QueryAllChaincodeDefinitionsResult, error) {
+	result := &lb.QueryAllChaincodeDefinitionsResult{}
+	err := proto.Unmarshal(proposalResponse.Response.Payload, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal proposal response's response payload")
+	}
+	if c.Input.Name == "" {
+		return result, nil
+	}
+
+	filtered := &lb.QueryAllChaincodeDefinitionsResult{}
+	for _, channel := range result.Channels {
+		for _, cd := range channel.ChaincodeDefinitions {
+			if cd.Name == c.Input.Name {
+				filtered.Channels = append(filtered.Channels, &lb.QueryAllChaincodeDefinitionsResult_Channel{
+					ChannelId:            channel.ChannelId,
+					ChaincodeDefinitions: []*lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{cd},
+				})
+			}
+		}
+	}
+	return filtered, nil
 }
 
 func (c *CommittedQuerier) printApprovals(qcdr *lb.QueryChaincodeDefinitionResult) {
@@ -209,6 +277,13 @@ func (c *CommittedQuerier) printApprovals(qcdr *lb.QueryChaincodeDefinitionResul
 }
 
 func (c *CommittedQuerier) validateInput() error {
+	if c.Input.AllChannels {
+		if c.Input.ChannelID != "" {
+			return errors.New("channel name must not be specified when querying all channels")
+		}
+		return nil
+	}
+
 	if c.Input.ChannelID == "" {
 		return errors.New("channel name must be specified")
 	}
@@ -220,12 +295,16 @@ func (c *CommittedQuerier) createProposal() (*pb.Proposal, error) {
 	var function string
 	var args proto.Message
 
-	if c.Input.Name != "" {
+	switch {
+	case c.Input.AllChannels:
+		function = "QueryAllChaincodeDefinitions"
+		args = &lb.QueryAllChaincodeDefinitionsArgs{}
+	case c.Input.Name != "":
 		function = "QueryChaincodeDefinition"
 		args = &lb.QueryChaincodeDefinitionArgs{
 			Name: c.Input.Name,
 		}
-	} else {
+	default:
 		function = "QueryChaincodeDefinitions"
 		args = &lb.QueryChaincodeDefinitionsArgs{}
 	}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/bccsp/sw"
@@ -197,6 +198,134 @@ var _ = Describe("QueryCommitted", func() {
 			})
 		})
 
+		Context("when the definitions of all channels are requested", func() {
+			BeforeEach(func() {
+				input.ChannelID = ""
+				input.AllChannels = true
+
+				mockResult := &lb.QueryAllChaincodeDefinitionsResult{
+					Channels: []*lb.QueryAllChaincodeDefinitionsResult_Channel{
+						{
+							ChannelId: "channel1",
+							ChaincodeDefinitions: []*lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{
+								{
+									Name:              "woohoo",
+									Sequence:          93,
+									Version:           "a-version",
+									EndorsementPlugin: "e-plugin",
+									ValidationPlugin:  "v-plugin",
+								},
+							},
+						},
+						{
+							ChannelId: "channel2",
+							ChaincodeDefinitions: []*lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{
+								{
+									Name:              "woohoo",
+									Sequence:          90,
+									Version:           "an-old-version",
+									EndorsementPlugin: "e-plugin",
+									ValidationPlugin:  "v-plugin",
+								},
+								{
+									Name:              "yahoo",
+									Sequence:          20,
+									Version:           "another-version",
+									EndorsementPlugin: "e-plugin",
+									ValidationPlugin:  "v-plugin",
+								},
+							},
+						},
+					},
+				}
+
+				mockResultBytes, err := proto.Marshal(mockResult)
+				Expect(err).NotTo(HaveOccurred())
+				mockProposalResponse.Response.Payload = mockResultBytes
+			})
+
+			It("queries the peer-wide definitions and writes the output as human readable plain-text", func() {
+				err := committedQuerier.Query()
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(committedQuerier.Writer).Should(gbytes.Say("Committed chaincode definitions on all channels:\n"))
+				Eventually(committedQuerier.Writer).Should(gbytes.Say("Channel: channel1, Name: woohoo, Version: a-version, Sequence: 93, Endorsement Plugin: e-plugin, Validation Plugin: v-plugin\n"))
+				Eventually(committedQuerier.Writer).Should(gbytes.Say("Channel: channel2, Name: woohoo, Version: an-old-version, Sequence: 90, Endorsement Plugin: e-plugin, Validation Plugin: v-plugin\n"))
+				Eventually(committedQuerier.Writer).Should(gbytes.Say("Channel: channel2, Name: yahoo, Version: another-version, Sequence: 20, Endorsement Plugin: e-plugin, Validation Plugin: v-plugin\n"))
+
+				Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(1))
+				_, signedProposal, _ := mockEndorserClient.ProcessProposalArgsForCall(0)
+				proposal := &pb.Proposal{}
+				Expect(proto.Unmarshal(signedProposal.ProposalBytes, proposal)).To(Succeed())
+				header := &cb.Header{}
+				Expect(proto.Unmarshal(proposal.Header, header)).To(Succeed())
+				channelHeader := &cb.ChannelHeader{}
+				Expect(proto.Unmarshal(header.ChannelHeader, channelHeader)).To(Succeed())
+				Expect(channelHeader.ChannelId).To(Equal(""))
+				payload := &pb.ChaincodeProposalPayload{}
+				Expect(proto.Unmarshal(proposal.Payload, payload)).To(Succeed())
+				cis := &pb.ChaincodeInvocationSpec{}
+				Expect(proto.Unmarshal(payload.Input, cis)).To(Succeed())
+				Expect(string(cis.ChaincodeSpec.Input.Args[0])).To(Equal("QueryAllChaincodeDefinitions"))
+			})
+
+			Context("when a chaincode name is provided", func() {
+				BeforeEach(func() {
+					input.Name = "woohoo"
+				})
+
+				It("writes the definitions of the named chaincode only", func() {
+					err := committedQuerier.Query()
+					Expect(err).NotTo(HaveOccurred())
+					Eventually(committedQuerier.Writer).Should(gbytes.Say("Committed chaincode definitions for chaincode 'woohoo' on all channels:\n"))
+					Eventually(committedQuerier.Writer).Should(gbytes.Say("Channel: channel1, Name: woohoo, Version: a-version, Sequence: 93"))
+					Eventually(committedQuerier.Writer).Should(gbytes.Say("Channel: channel2, Name: woohoo, Version: an-old-version, Sequence: 90"))
+					Expect(committedQuerier.Writer.(*gbytes.Buffer).Contents()).NotTo(ContainSubstring("yahoo"))
+				})
+			})
+
+			Context("when JSON-formatted output is requested", func() {
+				BeforeEach(func() {
+					input.Name = "yahoo"
+					committedQuerier.Input.OutputFormat = "json"
+				})
+
+				It("writes the output as JSON", func() {
+					err := committedQuerier.Query()
+					Expect(err).NotTo(HaveOccurred())
+					expectedOutput := &lb.QueryAllChaincodeDefinitionsResult{
+						Channels: []*lb.QueryAllChaincodeDefinitionsResult_Channel{
+							{
+								ChannelId: "channel2",
+								ChaincodeDefinitions: []*lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{
+									{
+										Name:              "yahoo",
+										Sequence:          20,
+										Version:           "another-version",
+										EndorsementPlugin: "e-plugin",
+										ValidationPlugin:  "v-plugin",
+									},
+								},
+							},
+						},
+					}
+					json, err := json.MarshalIndent(expectedOutput, "", "\t")
+					Expect(err).NotTo(HaveOccurred())
+					Eventually(committedQuerier.Writer).Should(gbytes.Say(fmt.Sprintf(`\Q%s\E`, string(json))))
+				})
+			})
+
+			Context("when a channel is provided as well", func() {
+				BeforeEach(func() {
+					input.ChannelID = "test-channel"
+				})
+
+				It("returns an error", func() {
+					err := committedQuerier.Query()
+					Expect(err).To(MatchError("channel name must not be specified when querying all channels"))
+				})
+			})
+		})
+
 		Context("when the channel is not provided", func() {
 			BeforeEach(func() {
 				committedQuerier.Input.ChannelID = ""
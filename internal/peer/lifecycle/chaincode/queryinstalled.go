@@ -46,6 +46,7 @@ func QueryInstalledCmd(i *InstalledQuerier, cryptoProvider bccsp.BCCSP) *cobra.C
 		Short: "Query the installed chaincodes on a peer.",
 		Long:  "Query the installed chaincodes on a peer.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			i := i
 			if i == nil {
 				ccInput := &ClientConnectionsInput{
 					CommandName:           cmd.Name(),
@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// SBOMQuerier holds the dependencies needed to query the software
+// bill of materials of an installed chaincode
+type SBOMQuerier struct {
+	Command        *cobra.Command
+	Input          *SBOMQueryInput
+	EndorserClient EndorserClient
+	Signer         Signer
+	Writer         io.Writer
+}
+
+// SBOMQueryInput holds all of the input parameters for querying the
+// software bill of materials of an installed chaincode
+type SBOMQueryInput struct {
+	PackageID    string
+	OutputFormat string
+}
+
+// Validate checks that the required parameters are provided.
+func (i *SBOMQueryInput) Validate() error {
+	if i.PackageID == "" {
+		return errors.New("The required parameter 'package-id' is empty. Rerun the command with --package-id flag")
+	}
+
+	return nil
+}
+
+// QuerySBOMCmd returns the cobra command for querying the software
+// bill of materials of an installed chaincode
+func QuerySBOMCmd(i *SBOMQuerier, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	chaincodeQuerySBOMCmd := &cobra.Command{
+		Use:   "querysbom",
+		Short: "Query the dependencies of an installed chaincode on a peer.",
+		Long:  "Query the software bill of materials of an installed chaincode on a peer, listing its dependencies and whether they are vendored or locked to fixed versions.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			i := i
+			if i == nil {
+				ccInput := &ClientConnectionsInput{
+					CommandName:           cmd.Name(),
+					EndorserRequired:      true,
+					ChannelID:             channelID,
+					PeerAddresses:         peerAddresses,
+					TLSRootCertFiles:      tlsRootCertFiles,
+					ConnectionProfilePath: connectionProfilePath,
+					TLSEnabled:            viper.GetBool("peer.tls.enabled"),
+				}
+
+				cc, err := NewClientConnections(ccInput, cryptoProvider)
+				if err != nil {
+					return err
+				}
+
+				sqInput := &SBOMQueryInput{
+					PackageID:    packageID,
+					OutputFormat: output,
+				}
+
+				// querysbom only supports one peer connection,
+				// which is why we only wire in the first endorser
+				// client
+				i = &SBOMQuerier{
+					Command:        cmd,
+					EndorserClient: cc.EndorserClients[0],
+					Input:          sqInput,
+					Signer:         cc.Signer,
+					Writer:         os.Stdout,
+				}
+			}
+			return i.Query()
+		},
+	}
+
+	flagList := []string{
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+		"package-id",
+		"output",
+	}
+	attachFlags(chaincodeQuerySBOMCmd, flagList)
+
+	return chaincodeQuerySBOMCmd
+}
+
+// Query returns the software bill of materials of a chaincode installed
+// on a peer
+func (i *SBOMQuerier) Query() error {
+	if i.Command != nil {
+		// Parsing of the command line is done so silence cmd usage
+		i.Command.SilenceUsage = true
+	}
+
+	if err := i.Input.Validate(); err != nil {
+		return err
+	}
+
+	proposal, err := i.createProposal()
+	if err != nil {
+		return errors.WithMessage(err, "failed to create proposal")
+	}
+
+	signedProposal, err := signProposal(proposal, i.Signer)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create signed proposal")
+	}
+
+	proposalResponse, err := i.EndorserClient.ProcessProposal(context.Background(), signedProposal)
+	if err != nil {
+		return errors.WithMessage(err, "failed to endorse proposal")
+	}
+
+	if proposalResponse == nil {
+		return errors.New("received nil proposal response")
+	}
+
+	if proposalResponse.Response == nil {
+		return errors.New("received proposal response with nil response")
+	}
+
+	if proposalResponse.Response.Status != int32(cb.Status_SUCCESS) {
+		return errors.Errorf("query failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+	}
+
+	if strings.ToLower(i.Input.OutputFormat) == "json" {
+		return printResponseAsJSON(proposalResponse, &lb.QueryInstalledChaincodeSBOMResult{}, i.Writer)
+	}
+	return i.printResponse(proposalResponse)
+}
+
+// printResponse prints the information included in the response
+// from the server.
+func (i *SBOMQuerier) printResponse(proposalResponse *pb.ProposalResponse) error {
+	result := &lb.QueryInstalledChaincodeSBOMResult{}
+	err := proto.Unmarshal(proposalResponse.Response.Payload, result)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal proposal response's response payload")
+	}
+
+	fmt.Fprintf(i.Writer, "Dependencies of chaincode package %s (%s):\n", result.PackageId, result.Type)
+	for _, c := range result.Components {
+		line := c.Name
+		if c.Version != "" {
+			line += " " + c.Version
+		}
+		if c.Replace != "" {
+			line += " => " + c.Replace
+		}
+		if !c.Direct {
+			line += " (transitive)"
+		}
+		fmt.Fprintln(i.Writer, line)
+	}
+	fmt.Fprintf(i.Writer, "Vendoring verified: %t\n", result.VendoringVerified)
+	for _, issue := range result.Issues {
+		fmt.Fprintf(i.Writer, "Issue: %s\n", issue)
+	}
+	return nil
+}
+
+func (i *SBOMQuerier) createProposal() (*pb.Proposal, error) {
+	args := &lb.QueryInstalledChaincodeSBOMArgs{
+		PackageId: i.Input.PackageID,
+	}
+
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal args")
+	}
+
+	ccInput := &pb.ChaincodeInput{
+		Args: [][]byte{[]byte("QueryInstalledChaincodeSBOM"), argsBytes},
+	}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: lifecycleName},
+			Input:       ccInput,
+		},
+	}
+
+	signerSerialized, err := i.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+
+	proposal, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", cis, signerSerialized)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create ChaincodeInvocationSpec proposal")
+	}
+
+	return proposal, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode/mock"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("QuerySBOM", func() {
+	Describe("SBOMQuerier", func() {
+		var (
+			mockProposalResponse *pb.ProposalResponse
+			mockEndorserClient   *mock.EndorserClient
+			mockSigner           *mock.Signer
+			input                *chaincode.SBOMQueryInput
+			sbomQuerier          *chaincode.SBOMQuerier
+			result               *lb.QueryInstalledChaincodeSBOMResult
+		)
+
+		BeforeEach(func() {
+			mockEndorserClient = &mock.EndorserClient{}
+			result = &lb.QueryInstalledChaincodeSBOMResult{
+				PackageId: "packageid1",
+				Type:      "golang",
+				Components: []*lb.QueryInstalledChaincodeSBOMResult_Component{
+					{Name: "example.com/dep", Version: "v1.0.0", Direct: true, Replace: "example.com/fork v1.0.1"},
+					{Name: "example.com/transitive", Version: "v0.1.0"},
+				},
+				Issues: []string{"the dependencies are not vendored"},
+			}
+			resultBytes, err := proto.Marshal(result)
+			Expect(err).NotTo(HaveOccurred())
+			mockProposalResponse = &pb.ProposalResponse{
+				Response: &pb.Response{
+					Status:  200,
+					Payload: resultBytes,
+				},
+			}
+			mockEndorserClient.ProcessProposalReturns(mockProposalResponse, nil)
+
+			mockSigner = &mock.Signer{}
+			buffer := gbytes.NewBuffer()
+			input = &chaincode.SBOMQueryInput{
+				PackageID: "packageid1",
+			}
+
+			sbomQuerier = &chaincode.SBOMQuerier{
+				Input:          input,
+				EndorserClient: mockEndorserClient,
+				Signer:         mockSigner,
+				Writer:         buffer,
+			}
+		})
+
+		It("queries the SBOM and writes the output as human readable plain-text", func() {
+			err := sbomQuerier.Query()
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(sbomQuerier.Writer).Should(gbytes.Say(`Dependencies of chaincode package packageid1 \(golang\):`))
+			Eventually(sbomQuerier.Writer).Should(gbytes.Say("example.com/dep v1.0.0 => example.com/fork v1.0.1\n"))
+			Eventually(sbomQuerier.Writer).Should(gbytes.Say(`example.com/transitive v0.1.0 \(transitive\)`))
+			Eventually(sbomQuerier.Writer).Should(gbytes.Say("Vendoring verified: false"))
+			Eventually(sbomQuerier.Writer).Should(gbytes.Say("Issue: the dependencies are not vendored"))
+
+			Expect(mockEndorserClient.ProcessProposalCallCount()).To(Equal(1))
+			_, signedProposal, _ := mockEndorserClient.ProcessProposalArgsForCall(0)
+			proposal := &pb.Proposal{}
+			Expect(proto.Unmarshal(signedProposal.ProposalBytes, proposal)).To(Succeed())
+			payload := &pb.ChaincodeProposalPayload{}
+			Expect(proto.Unmarshal(proposal.Payload, payload)).To(Succeed())
+			cis := &pb.ChaincodeInvocationSpec{}
+			Expect(proto.Unmarshal(payload.Input, cis)).To(Succeed())
+			args := cis.ChaincodeSpec.Input.Args
+			Expect(args[0]).To(Equal([]byte("QueryInstalledChaincodeSBOM")))
+			queryArgs := &lb.QueryInstalledChaincodeSBOMArgs{}
+			Expect(proto.Unmarshal(args[1], queryArgs)).To(Succeed())
+			Expect(queryArgs.PackageId).To(Equal("packageid1"))
+		})
+
+		Context("when JSON-formatted output is requested", func() {
+			BeforeEach(func() {
+				sbomQuerier.Input.OutputFormat = "json"
+			})
+
+			It("queries the SBOM and writes the output as JSON", func() {
+				err := sbomQuerier.Query()
+				Expect(err).NotTo(HaveOccurred())
+				json, err := json.MarshalIndent(result, "", "\t")
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(sbomQuerier.Writer).Should(gbytes.Say(fmt.Sprintf(`\Q%s\E`, string(json))))
+			})
+		})
+
+		Context("when the package ID is not provided", func() {
+			BeforeEach(func() {
+				sbomQuerier.Input.PackageID = ""
+			})
+
+			It("returns an error", func() {
+				err := sbomQuerier.Query()
+				Expect(err).To(MatchError("The required parameter 'package-id' is empty. Rerun the command with --package-id flag"))
+			})
+		})
+
+		Context("when the signer cannot be serialized", func() {
+			BeforeEach(func() {
+				mockSigner.SerializeReturns(nil, errors.New("cafe"))
+			})
+
+			It("returns an error", func() {
+				err := sbomQuerier.Query()
+				Expect(err).To(MatchError("failed to create proposal: failed to serialize identity: cafe"))
+			})
+		})
+
+		Context("when the endorser fails to endorse the proposal", func() {
+			BeforeEach(func() {
+				mockEndorserClient.ProcessProposalReturns(nil, errors.New("latte"))
+			})
+
+			It("returns an error", func() {
+				err := sbomQuerier.Query()
+				Expect(err).To(MatchError("failed to endorse proposal: latte"))
+			})
+		})
+
+		Context("when the endorser returns a non-success status", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = &pb.Response{
+					Status:  404,
+					Message: "chaincode install package 'packageid1' not found",
+				}
+			})
+
+			It("returns an error", func() {
+				err := sbomQuerier.Query()
+				Expect(err).To(MatchError("query failed with status: 404 - chaincode install package 'packageid1' not found"))
+			})
+		})
+
+		Context("when the payload contains bytes that aren't a QueryInstalledChaincodeSBOMResult", func() {
+			BeforeEach(func() {
+				mockProposalResponse.Response = &pb.Response{
+					Payload: []byte("badpayloadbadpayload"),
+					Status:  200,
+				}
+			})
+
+			It("returns an error", func() {
+				err := sbomQuerier.Query()
+				Expect(err).To(MatchError(ContainSubstring("failed to unmarshal proposal response's response payload")))
+			})
+		})
+	})
+
+	Describe("QuerySBOMCmd", func() {
+		var querySBOMCmd *cobra.Command
+
+		BeforeEach(func() {
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			Expect(err).To(BeNil())
+			querySBOMCmd = chaincode.QuerySBOMCmd(nil, cryptoProvider)
+			querySBOMCmd.SilenceErrors = true
+			querySBOMCmd.SilenceUsage = true
+			querySBOMCmd.SetArgs([]string{
+				"--package-id=packageid1",
+				"--peerAddresses=querypeer1",
+				"--tlsRootCertFiles=tls1",
+			})
+		})
+
+		AfterEach(func() {
+			chaincode.ResetFlags()
+		})
+
+		It("attempts to connect to the endorser", func() {
+			err := querySBOMCmd.Execute()
+			Expect(err).To(MatchError(ContainSubstring("failed to retrieve endorser client")))
+		})
+	})
+})
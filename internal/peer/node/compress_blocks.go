@@ -0,0 +1,26 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func compressBlocksCmd() *cobra.Command {
+	return nodeCompressBlocksCmd
+}
+
+var nodeCompressBlocksCmd = &cobra.Command{
+	Use:   "compress-blocks",
+	Short: "Compresses the block files.",
+	Long:  "Rewrites the block files of all the channels with their blocks compressed. The block storage index is rebuilt upon peer restart. When the command is executed, the peer must be offline.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config := ledgerConfig()
+		return kvledger.CompressBlockFiles(config.RootFSPath)
+	},
+}
@@ -41,6 +41,10 @@ func ledgerConfig() *ledger.Config {
 	if viper.IsSet("ledger.pvtdataStore.purgeInterval") {
 		purgeInterval = viper.GetInt("ledger.pvtdataStore.purgeInterval")
 	}
+	compactionInterval := 24 * time.Hour
+	if viper.IsSet("ledger.compaction.interval") {
+		compactionInterval = viper.GetDuration("ledger.compaction.interval")
+	}
 	deprioritizedDataReconcilerInterval := 60 * time.Minute
 	if viper.IsSet("ledger.pvtdataStore.deprioritizedDataReconcilerInterval") {
 		deprioritizedDataReconcilerInterval = viper.GetDuration("ledger.pvtdataStore.deprioritizedDataReconcilerInterval")
@@ -51,41 +55,86 @@ func ledgerConfig() *ledger.Config {
 	if snapshotsRootDir == "" {
 		snapshotsRootDir = filepath.Join(rootFSPath, "snapshots")
 	}
+	backupsRootDir := viper.GetString("ledger.backups.rootDir")
+	if backupsRootDir == "" {
+		backupsRootDir = filepath.Join(rootFSPath, "backups")
+	}
 	conf := &ledger.Config{
 		RootFSPath: rootFSPath,
 		StateDBConfig: &ledger.StateDBConfig{
 			StateDatabase: viper.GetString("ledger.state.stateDatabase"),
 			CouchDB:       &ledger.CouchDBConfig{},
+			RangeScanCursors: &ledger.RangeScanCursorsConfig{
+				Lifetime:                   viper.GetDuration("ledger.state.rangeScanCursors.lifetime"),
+				MaxPinnedViewsPerChaincode: viper.GetInt("ledger.state.rangeScanCursors.maxPinnedViewsPerChaincode"),
+			},
 		},
 		PrivateDataConfig: &ledger.PrivateDataConfig{
 			MaxBatchSize:                        collElgProcMaxDbBatchSize,
 			BatchesInterval:                     collElgProcDbBatchesInterval,
 			PurgeInterval:                       purgeInterval,
 			DeprioritizedDataReconcilerInterval: deprioritizedDataReconcilerInterval,
+			LazyHydration:                       viper.GetBool("ledger.pvtdataStore.lazyHydration"),
+			PurgeIneligibleData:                 viper.GetBool("ledger.pvtdataStore.purgeIneligibleData"),
 		},
 		HistoryDBConfig: &ledger.HistoryDBConfig{
-			Enabled: viper.GetBool("ledger.history.enableHistoryDatabase"),
+			Enabled:            viper.GetBool("ledger.history.enableHistoryDatabase"),
+			DisabledByDefault:  viper.GetBool("ledger.history.namespaces.disabledByDefault"),
+			EnabledNamespaces:  viper.GetStringSlice("ledger.history.namespaces.enabled"),
+			DisabledNamespaces: viper.GetStringSlice("ledger.history.namespaces.disabled"),
 		},
 		SnapshotsConfig: &ledger.SnapshotsConfig{
 			RootDir: snapshotsRootDir,
 		},
+		BlockStoreConfig: &ledger.BlockStoreConfig{
+			Compression: viper.GetBool("ledger.blockchain.compression"),
+		},
+		BackupsConfig: &ledger.BackupsConfig{
+			RootDir:                  backupsRootDir,
+			CouchDBReplicationTarget: viper.GetString("ledger.backups.couchDBReplicationTarget"),
+		},
+		CompactionConfig: &ledger.CompactionConfig{
+			Enabled:     viper.GetBool("ledger.compaction.enabled"),
+			WindowStart: viper.GetString("ledger.compaction.windowStart"),
+			WindowEnd:   viper.GetString("ledger.compaction.windowEnd"),
+			Interval:    compactionInterval,
+		},
+	}
+
+	for _, path := range viper.GetStringSlice("ledger.blockchain.shards.paths") {
+		conf.BlockStoreConfig.ShardPaths = append(conf.BlockStoreConfig.ShardPaths, translateLedgerPath(path))
+	}
+	for channelID, path := range viper.GetStringMapString("ledger.blockchain.shards.channels") {
+		if conf.BlockStoreConfig.ShardChannels == nil {
+			conf.BlockStoreConfig.ShardChannels = map[string]string{}
+		}
+		conf.BlockStoreConfig.ShardChannels[channelID] = translateLedgerPath(path)
 	}
 
 	if conf.StateDBConfig.StateDatabase == "CouchDB" {
 		conf.StateDBConfig.CouchDB = &ledger.CouchDBConfig{
-			Address:                 viper.GetString("ledger.state.couchDBConfig.couchDBAddress"),
-			Username:                viper.GetString("ledger.state.couchDBConfig.username"),
-			Password:                viper.GetString("ledger.state.couchDBConfig.password"),
-			MaxRetries:              viper.GetInt("ledger.state.couchDBConfig.maxRetries"),
-			MaxRetriesOnStartup:     viper.GetInt("ledger.state.couchDBConfig.maxRetriesOnStartup"),
-			RequestTimeout:          viper.GetDuration("ledger.state.couchDBConfig.requestTimeout"),
-			InternalQueryLimit:      internalQueryLimit,
-			MaxBatchUpdateSize:      maxBatchUpdateSize,
-			WarmIndexesAfterNBlocks: warmAfterNBlocks,
-			CreateGlobalChangesDB:   viper.GetBool("ledger.state.couchDBConfig.createGlobalChangesDB"),
-			RedoLogPath:             filepath.Join(rootFSPath, "couchdbRedoLogs"),
-			UserCacheSizeMBs:        viper.GetInt("ledger.state.couchDBConfig.cacheSize"),
+			Address:                    viper.GetString("ledger.state.couchDBConfig.couchDBAddress"),
+			Username:                   viper.GetString("ledger.state.couchDBConfig.username"),
+			Password:                   viper.GetString("ledger.state.couchDBConfig.password"),
+			MaxRetries:                 viper.GetInt("ledger.state.couchDBConfig.maxRetries"),
+			MaxRetriesOnStartup:        viper.GetInt("ledger.state.couchDBConfig.maxRetriesOnStartup"),
+			RequestTimeout:             viper.GetDuration("ledger.state.couchDBConfig.requestTimeout"),
+			InternalQueryLimit:         internalQueryLimit,
+			MaxBatchUpdateSize:         maxBatchUpdateSize,
+			BulkLoadMaxBatchUpdateSize: viper.GetInt("ledger.state.couchDBConfig.bulkLoadMaxBatchUpdateSize"),
+			AttachmentThreshold:        int(viper.GetSizeInBytes("ledger.state.couchDBConfig.attachmentThreshold")),
+			WarmIndexesAfterNBlocks:    warmAfterNBlocks,
+			CreateGlobalChangesDB:      viper.GetBool("ledger.state.couchDBConfig.createGlobalChangesDB"),
+			RedoLogPath:                filepath.Join(rootFSPath, "couchdbRedoLogs"),
+			UserCacheSizeMBs:           viper.GetInt("ledger.state.couchDBConfig.cacheSize"),
+			CacheWarmupNamespaces:      viper.GetStringSlice("ledger.state.couchDBConfig.cacheWarmupNamespaces"),
 		}
 	}
 	return conf
 }
+
+// translateLedgerPath resolves a path of the ledger configuration relative to
+// the directory of the configuration file.
+func translateLedgerPath(path string) string {
+	return coreconfig.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), path)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/tls"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+)
+
+// preflightCheck is a check of the configuration and the environment of the
+// peer run by `peer node start` before any of the peer services is started.
+type preflightCheck struct {
+	name  string
+	check func() error
+}
+
+// preflightResult is the outcome of a preflight check.
+type preflightResult struct {
+	name string
+	err  error
+}
+
+// runPreflightChecks runs all the checks, rather than stopping at the first
+// failure, and logs a report of their outcomes. It returns an error if any of
+// the checks failed.
+func runPreflightChecks(checks []preflightCheck) error {
+	var results []preflightResult
+	failed := 0
+	for _, c := range checks {
+		err := c.check()
+		if err != nil {
+			failed++
+		}
+		results = append(results, preflightResult{name: c.name, err: err})
+	}
+
+	report := preflightReport(results)
+	if failed == 0 {
+		logger.Infof("Preflight checks passed:\n%s", report)
+		return nil
+	}
+	logger.Errorf("Preflight checks failed:\n%s", report)
+	return errors.Errorf("%d of %d preflight checks failed, fix the issues reported above before starting the peer", failed, len(checks))
+}
+
+func preflightReport(results []preflightResult) string {
+	var b strings.Builder
+	for _, r := range results {
+		if r.err == nil {
+			fmt.Fprintf(&b, "\t[PASS] %s\n", r.name)
+			continue
+		}
+		fmt.Fprintf(&b, "\t[FAIL] %s: %s\n", r.name, r.err)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// peerPreflightChecks returns the checks of the local MSP, the TLS configuration,
+// the ledger data, the state database and the listen addresses of the peer.
+func peerPreflightChecks(coreConfig *peer.Config, localMSP msp.MSP, ledgerConfig *ledger.Config) []preflightCheck {
+	checks := []preflightCheck{
+		{name: "local MSP", check: func() error { return checkLocalMSP(localMSP) }},
+		{name: "TLS configuration", check: checkTLSConfig},
+		{name: "ledger data format", check: func() error { return checkLedgerDataFormat(ledgerConfig.RootFSPath) }},
+	}
+	if ledgerConfig.StateDBConfig.StateDatabase == "CouchDB" {
+		checks = append(checks, preflightCheck{
+			name:  "CouchDB",
+			check: func() error { return checkCouchDB(ledgerConfig.StateDBConfig.CouchDB) },
+		})
+	}
+	checks = append(checks, preflightCheck{
+		name:  "listen addresses",
+		check: func() error { return checkListenAddresses(peerListenAddresses(coreConfig)) },
+	})
+	return checks
+}
+
+// checkLocalMSP checks that the signing certificate of the local MSP is valid and
+// that it matches the private key found in the keystore.
+func checkLocalMSP(localMSP msp.MSP) error {
+	signer, err := localMSP.GetDefaultSigningIdentity()
+	if err != nil {
+		return errors.WithMessage(err, "the local MSP has no signing identity, check the signcerts and keystore folders under peer.mspConfigPath")
+	}
+	if err := signer.Validate(); err != nil {
+		return errors.WithMessage(err, "the signing certificate of the local MSP is invalid, check that it is issued by a CA of the MSP and has not expired")
+	}
+	msg := []byte("preflight")
+	signature, err := signer.Sign(msg)
+	if err != nil {
+		return errors.WithMessage(err, "the local MSP cannot sign, check the private key in the keystore folder under peer.mspConfigPath")
+	}
+	if err := signer.Verify(msg, signature); err != nil {
+		return errors.New("the private key of the local MSP does not match its signing certificate, check the keystore folder under peer.mspConfigPath")
+	}
+	return nil
+}
+
+// checkTLSConfig checks that the TLS certificate and key of the peer form a key pair,
+// that the certificate has not expired, and that the root certificates are readable
+// PEM certificates.
+func checkTLSConfig() error {
+	serverConfig, err := peer.GetServerConfig()
+	if err != nil {
+		return errors.WithMessage(err, "check the files configured in peer.tls")
+	}
+	secOpts := serverConfig.SecOpts
+	if !secOpts.UseTLS {
+		return nil
+	}
+
+	cert, err := tls.X509KeyPair(secOpts.Certificate, secOpts.Key)
+	if err != nil {
+		return errors.WithMessage(err, "peer.tls.cert.file and peer.tls.key.file do not form a key pair")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return errors.WithMessage(err, "failed to parse peer.tls.cert.file")
+	}
+	if now := time.Now(); now.After(leaf.NotAfter) || now.Before(leaf.NotBefore) {
+		return errors.Errorf("the TLS certificate of the peer is only valid from %s to %s, renew peer.tls.cert.file", leaf.NotBefore, leaf.NotAfter)
+	}
+
+	for _, rootCert := range secOpts.ServerRootCAs {
+		if err := checkPEMCertificates(rootCert); err != nil {
+			return errors.WithMessage(err, "invalid peer.tls.rootcert.file")
+		}
+	}
+	for _, rootCert := range secOpts.ClientRootCAs {
+		if err := checkPEMCertificates(rootCert); err != nil {
+			return errors.WithMessage(err, "invalid peer.tls.clientRootCAs.files")
+		}
+	}
+	return nil
+}
+
+func checkPEMCertificates(pemBytes []byte) error {
+	found := false
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			break
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return errors.Wrap(err, "failed to parse certificate")
+		}
+		found = true
+	}
+	if !found {
+		return errors.New("no PEM encoded certificate found")
+	}
+	return nil
+}
+
+// checkLedgerDataFormat checks that the ledger data was written by a compatible version of the peer.
+func checkLedgerDataFormat(rootFSPath string) error {
+	err := kvledger.CheckDataFormat(rootFSPath)
+	if mismatch, ok := err.(*dataformat.ErrFormatMismatch); ok && mismatch.Format == dataformat.PreviousFormat {
+		return errors.WithMessage(err, "the ledger data was written by a previous version of the peer, run `peer node upgrade-dbs` before starting the peer")
+	}
+	return err
+}
+
+// checkCouchDB checks that CouchDB is reachable and that the credentials of the peer are valid.
+func checkCouchDB(config *ledger.CouchDBConfig) error {
+	if err := statecouchdb.VerifyCouchDB(config); err != nil {
+		return errors.WithMessagef(err, "check ledger.state.couchDBConfig.couchDBAddress (%s) and the CouchDB admin credentials", config.Address)
+	}
+	return nil
+}
+
+// peerListenAddresses returns the addresses the peer listens on, keyed by the
+// configuration properties setting them.
+func peerListenAddresses(coreConfig *peer.Config) map[string]string {
+	addresses := map[string]string{
+		"peer.listenAddress": coreConfig.ListenAddress,
+	}
+	if coreConfig.ChaincodeListenAddress != "" {
+		addresses[chaincodeListenAddrKey] = coreConfig.ChaincodeListenAddress
+	} else if peerHost, _, err := net.SplitHostPort(coreConfig.PeerAddress); err == nil {
+		addresses[chaincodeListenAddrKey] = net.JoinHostPort(peerHost, fmt.Sprint(defaultChaincodePort))
+	}
+	if coreConfig.OperationsListenAddress != "" {
+		addresses["operations.listenAddress"] = coreConfig.OperationsListenAddress
+	}
+	if coreConfig.ProfileEnabled {
+		addresses["peer.profile.listenAddress"] = coreConfig.ProfileListenAddress
+	}
+	return addresses
+}
+
+// checkListenAddresses checks that the peer can listen on the given addresses.
+func checkListenAddresses(addresses map[string]string) error {
+	var keys []string
+	for key := range addresses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var unavailable []string
+	for _, key := range keys {
+		address := addresses[key]
+		l, err := net.Listen("tcp", address)
+		if err != nil {
+			unavailable = append(unavailable, fmt.Sprintf("%s (%s): %s", address, key, err))
+			continue
+		}
+		l.Close()
+	}
+	if len(unavailable) != 0 {
+		return errors.Errorf("cannot listen on %s, stop the processes using these addresses or change them", strings.Join(unavailable, "; "))
+	}
+	return nil
+}
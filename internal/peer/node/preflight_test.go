@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/msp/mocks"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPreflightChecks(t *testing.T) {
+	passing := preflightCheck{name: "passing", check: func() error { return nil }}
+	failing := preflightCheck{name: "failing", check: func() error { return errors.New("boom") }}
+	ran := 0
+	counting := preflightCheck{name: "counting", check: func() error { ran++; return nil }}
+
+	assert.NoError(t, runPreflightChecks([]preflightCheck{passing, counting}))
+	assert.Equal(t, 1, ran)
+
+	// all the checks run even when some of them fail
+	err := runPreflightChecks([]preflightCheck{failing, counting, failing})
+	assert.EqualError(t, err, "2 of 3 preflight checks failed, fix the issues reported above before starting the peer")
+	assert.Equal(t, 2, ran)
+
+	report := preflightReport([]preflightResult{{name: "passing"}, {name: "failing", err: errors.New("boom")}})
+	assert.Equal(t, "\t[PASS] passing\n\t[FAIL] failing: boom", report)
+}
+
+type mismatchedSigner struct {
+	msp.SigningIdentity
+}
+
+func (mismatchedSigner) Verify(msg []byte, sig []byte) error {
+	return errors.New("signature mismatch")
+}
+
+type expiredSigner struct {
+	msp.SigningIdentity
+}
+
+func (expiredSigner) Validate() error {
+	return errors.New("certificate has expired")
+}
+
+func TestCheckLocalMSP(t *testing.T) {
+	err := msptesttools.LoadMSPSetupForTesting()
+	require.NoError(t, err)
+	localMSP := mgmt.GetLocalMSP(factory.GetDefault())
+	assert.NoError(t, checkLocalMSP(localMSP))
+
+	signer, err := localMSP.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	mismatchedMSP := &mocks.MockMSP{}
+	mismatchedMSP.On("GetDefaultSigningIdentity").Return(mismatchedSigner{signer}, nil)
+	assert.EqualError(t, checkLocalMSP(mismatchedMSP), "the private key of the local MSP does not match its signing certificate, check the keystore folder under peer.mspConfigPath")
+
+	invalidMSP := &mocks.MockMSP{}
+	invalidMSP.On("GetDefaultSigningIdentity").Return(expiredSigner{signer}, nil)
+	assert.EqualError(t, checkLocalMSP(invalidMSP), "the signing certificate of the local MSP is invalid, check that it is issued by a CA of the MSP and has not expired: certificate has expired")
+}
+
+func TestCheckTLSConfig(t *testing.T) {
+	defer viper.Reset()
+	testDir, err := ioutil.TempDir("", "preflight")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	viper.Set("peer.tls.enabled", false)
+	assert.NoError(t, checkTLSConfig())
+
+	viper.Set("peer.tls.enabled", true)
+	viper.Set("peer.tls.cert.file", filepath.Join(testDir, "missing.crt"))
+	viper.Set("peer.tls.key.file", filepath.Join(testDir, "missing.key"))
+	assert.Contains(t, checkTLSConfig().Error(), "check the files configured in peer.tls")
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	kp, err := ca.NewServerCertKeyPair("localhost")
+	require.NoError(t, err)
+	otherKp, err := ca.NewServerCertKeyPair("localhost")
+	require.NoError(t, err)
+	writeFile := func(name string, content []byte) string {
+		path := filepath.Join(testDir, name)
+		require.NoError(t, ioutil.WriteFile(path, content, 0600))
+		return path
+	}
+	viper.Set("peer.tls.cert.file", writeFile("server.crt", kp.Cert))
+	viper.Set("peer.tls.key.file", writeFile("server.key", kp.Key))
+	viper.Set("peer.tls.rootcert.file", writeFile("ca.crt", ca.CertBytes()))
+	assert.NoError(t, checkTLSConfig())
+
+	viper.Set("peer.tls.key.file", writeFile("other.key", otherKp.Key))
+	assert.Contains(t, checkTLSConfig().Error(), "peer.tls.cert.file and peer.tls.key.file do not form a key pair")
+
+	viper.Set("peer.tls.key.file", filepath.Join(testDir, "server.key"))
+	viper.Set("peer.tls.rootcert.file", writeFile("garbage.crt", []byte("garbage")))
+	assert.EqualError(t, checkTLSConfig(), "invalid peer.tls.rootcert.file: no PEM encoded certificate found")
+}
+
+func TestCheckLedgerDataFormat(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "preflight")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	assert.NoError(t, checkLedgerDataFormat(testDir))
+
+	db := leveldbhelper.CreateDB(&leveldbhelper.Conf{DBPath: kvledger.LedgerProviderPath(testDir)})
+	db.Open()
+	require.NoError(t, db.Put([]byte("f"), []byte(dataformat.PreviousFormat), true))
+	db.Close()
+	err = checkLedgerDataFormat(testDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run `peer node upgrade-dbs` before starting the peer")
+}
+
+func TestCheckListenAddresses(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	assert.NoError(t, checkListenAddresses(map[string]string{"peer.listenAddress": "127.0.0.1:0"}))
+
+	err = checkListenAddresses(map[string]string{
+		"peer.listenAddress":       "127.0.0.1:0",
+		"operations.listenAddress": l.Addr().String(),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot listen on "+l.Addr().String()+" (operations.listenAddress)")
+}
@@ -18,7 +18,7 @@ func rebuildDBsCmd() *cobra.Command {
 var nodeRebuildCmd = &cobra.Command{
 	Use:   "rebuild-dbs",
 	Short: "Rebuilds databases.",
-	Long:  "Drops the databases for all the channels and rebuilds them upon peer restart. When the command is executed, the peer must be offline.",
+	Long:  "Drops the databases for all the channels and rebuilds them upon peer restart. If a previous rebuild did not complete, the databases are kept and the rebuild resumes from its checkpoint upon peer restart. When the command is executed, the peer must be offline.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		config := ledgerConfig()
 		return kvledger.RebuildDBs(config)
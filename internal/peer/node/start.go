@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,6 +22,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/cetcxinlian/cryptogm/tls"
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -40,14 +43,20 @@ import (
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/cclifecycle"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/accesscontrol"
+	"github.com/hyperledger/fabric/core/chaincode/argschema"
+	"github.com/hyperledger/fabric/core/chaincode/debug"
 	"github.com/hyperledger/fabric/core/chaincode/extcc"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
+	ccutil "github.com/hyperledger/fabric/core/chaincode/platforms/util"
+	"github.com/hyperledger/fabric/core/chaincode/txtrace"
+	"github.com/hyperledger/fabric/core/chaincode/upgrade"
 	"github.com/hyperledger/fabric/core/committer/txvalidator/plugin"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
@@ -55,6 +64,7 @@ import (
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
 	"github.com/hyperledger/fabric/core/container/externalbuilder"
+	"github.com/hyperledger/fabric/core/container/processcontroller"
 	"github.com/hyperledger/fabric/core/deliverservice"
 	"github.com/hyperledger/fabric/core/dispatcher"
 	"github.com/hyperledger/fabric/core/endorser"
@@ -65,10 +75,17 @@ import (
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/commitlistener"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/core/ledger/customtx/notarization"
 	"github.com/hyperledger/fabric/core/ledger/kvledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/peer/cdc"
+	"github.com/hyperledger/fabric/core/peer/channelhooks"
+	coreidemixaudit "github.com/hyperledger/fabric/core/peer/idemixaudit"
+	coretriggers "github.com/hyperledger/fabric/core/peer/triggers"
 	"github.com/hyperledger/fabric/core/policy"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/core/scc/cscc"
@@ -82,15 +99,26 @@ import (
 	ccsupport "github.com/hyperledger/fabric/discovery/support/chaincode"
 	"github.com/hyperledger/fabric/discovery/support/config"
 	"github.com/hyperledger/fabric/discovery/support/gossip"
+	gossipapi "github.com/hyperledger/fabric/gossip/api"
 	gossipcommon "github.com/hyperledger/fabric/gossip/common"
 	gossipgossip "github.com/hyperledger/fabric/gossip/gossip"
 	gossipmetrics "github.com/hyperledger/fabric/gossip/metrics"
 	gossipprivdata "github.com/hyperledger/fabric/gossip/privdata"
 	"github.com/hyperledger/fabric/gossip/service"
 	gossipservice "github.com/hyperledger/fabric/gossip/service"
+	peercommon "github.com/hyperledger/fabric/internal/peer/common"
 	peergossip "github.com/hyperledger/fabric/internal/peer/gossip"
 	"github.com/hyperledger/fabric/internal/peer/version"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/enrollment"
+	"github.com/hyperledger/fabric/internal/pkg/mspreload"
+	"github.com/hyperledger/fabric/internal/pkg/peer/backup"
+	"github.com/hyperledger/fabric/internal/pkg/peer/compaction"
+	"github.com/hyperledger/fabric/internal/pkg/peer/drain"
+	"github.com/hyperledger/fabric/internal/pkg/peer/idemixaudit"
+	"github.com/hyperledger/fabric/internal/pkg/peer/proposalstream"
+	"github.com/hyperledger/fabric/internal/pkg/peer/status"
+	"github.com/hyperledger/fabric/internal/pkg/peer/triggers"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protoutil"
@@ -117,7 +145,7 @@ func startCmd() *cobra.Command {
 var nodeStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Starts the node.",
-	Long:  `Starts a node that interacts with the network.`,
+	Long:  `Starts a node that interacts with the network. Before any of its services is started, the peer checks its local MSP, its TLS configuration, the format of its ledger data, its connection to CouchDB and its listen addresses, and reports all the failed checks at once.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 0 {
 			return fmt.Errorf("trailing args detected")
@@ -184,6 +212,45 @@ func (c custodianLauncherAdapter) Stop(ccid string) error {
 	return c.launcher.Stop(ccid)
 }
 
+type upgradeRuntimeAdapter struct {
+	custodianLauncherAdapter
+	registry *chaincode.HandlerRegistry
+}
+
+func (u upgradeRuntimeAdapter) Running(ccid string) bool {
+	return u.registry.Handler(ccid) != nil
+}
+
+type upgradeInitStateAdapter struct {
+	peer      *peer.Peer
+	lifecycle chaincode.Lifecycle
+}
+
+func (u upgradeInitStateAdapter) Initialized(channelID, chaincodeName string) (bool, error) {
+	l := u.peer.GetLedger(channelID)
+	if l == nil {
+		return false, errors.Errorf("channel %s does not exist", channelID)
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return false, err
+	}
+	defer qe.Done()
+
+	info, err := u.lifecycle.ChaincodeEndorsementInfo(channelID, chaincodeName, qe)
+	if err != nil {
+		return false, err
+	}
+	if !info.EnforceInit {
+		return true, nil
+	}
+	value, err := qe.GetState(chaincodeName, chaincode.InitializedKeyName)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(value, []byte(info.Version)), nil
+}
+
 func serve(args []string) error {
 	// currently the peer only works with the standard MSP
 	// because in certain scenarios the MSP has to make sure
@@ -209,6 +276,13 @@ func serve(args []string) error {
 		return err
 	}
 
+	// validate the configuration and the environment of the peer
+	// before starting any of its services
+	preflightChecks := peerPreflightChecks(coreConfig, mgmt.GetLocalMSP(factory.GetDefault()), ledgerConfig())
+	if err := runPreflightChecks(preflightChecks); err != nil {
+		return err
+	}
+
 	platformRegistry := platforms.NewRegistry(platforms.SupportedPlatforms...)
 
 	identityDeserializerFactory := func(chainID string) msp.IdentityDeserializer {
@@ -227,8 +301,30 @@ func serve(args []string) error {
 	flogging.SetObserver(logObserver)
 
 	mspID := coreConfig.LocalMSPID
+	// the local MSP is reloaded from its directory on SIGHUP or through the operations endpoint
+	reloadLocalMSP := func() error {
+		return peercommon.ReloadCrypto(coreconfig.GetPath("peer.mspConfigPath"), mspID, msp.ProviderTypeToString(mspType))
+	}
 
-	membershipInfoProvider := privdata.NewMembershipInfoProvider(mspID, createSelfSignedData(), identityDeserializerFactory)
+	var tracer *tracing.Tracer
+	if coreConfig.TracingEnabled {
+		exporter := tracing.NewOTLPExporter(tracing.OTLPConfig{
+			Endpoint:      coreConfig.TracingEndpoint,
+			ServiceName:   "peer",
+			InstanceID:    coreConfig.PeerID,
+			FlushInterval: coreConfig.TracingFlushInterval,
+		})
+		defer exporter.Stop()
+		tracer = tracing.NewTracer(exporter)
+		logger.Infof("Exporting the spans of the transactions to %s", coreConfig.TracingEndpoint)
+	}
+
+	signingIdentity, err := peercommon.GetSigningIdentity()
+	if err != nil {
+		logger.Panicf("Could not get the default signing identity from the local MSP: [%+v]", err)
+	}
+
+	membershipInfoProvider := privdata.NewMembershipInfoProvider(mspID, createSelfSignedData(signingIdentity), identityDeserializerFactory)
 
 	chaincodeInstallPath := filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "lifecycle", "chaincodes")
 	ccStore := persistence.NewStore(chaincodeInstallPath)
@@ -266,6 +362,22 @@ func serve(args []string) error {
 		serverConfig.StreamInterceptors = append(serverConfig.StreamInterceptors, streamGrpcLimiter(semaphores))
 	}
 
+	// the drain mode refuses new proposals and deliver clients, waits for the
+	// in-flight ones and for the in-flight commits, then flushes the logs
+	drainer := drain.New(drain.Options{
+		Services:         []string{"protos.Endorser", proposalstream.ServiceName, "protos.Deliver"},
+		DetachedServices: []string{"protos.Deliver"},
+		Flushers: []drain.Flusher{
+			{Name: "logging", Flush: flogging.Global.Sync},
+		},
+	})
+	serverConfig.UnaryInterceptors = append(serverConfig.UnaryInterceptors, drainer.UnaryServerInterceptor())
+	serverConfig.StreamInterceptors = append(serverConfig.StreamInterceptors, drainer.StreamServerInterceptor())
+	if err := opsSystem.RegisterChecker("drain", drainer); err != nil {
+		return errors.WithMessage(err, "failed to register drain health check")
+	}
+	opsSystem.RegisterAdminHandler("/drain", drain.NewHandler(drainer))
+
 	cs := comm.NewCredentialSupport()
 	if serverConfig.SecOpts.UseTLS {
 		logger.Info("Starting peer with TLS enabled")
@@ -294,14 +406,25 @@ func serve(args []string) error {
 		StoreProvider:            transientStoreProvider,
 		CryptoProvider:           factory.GetDefault(),
 		OrdererEndpointOverrides: deliverServiceConfig.OrdererEndpointOverrides,
+		CommitGate:               drainer,
+		Tracer:                   tracer,
 	}
 
-	localMSP := mgmt.GetLocalMSP(factory.GetDefault())
-	signingIdentity, err := localMSP.GetDefaultSigningIdentity()
-	if err != nil {
-		logger.Panicf("Could not get the default signing identity from the local MSP: [%+v]", err)
+	if len(coreConfig.ChannelHooks) > 0 {
+		channelHooks, err := channelhooks.NewDispatcher(coreConfig.PeerID, mspID, coreConfig.ChannelHooks)
+		if err != nil {
+			logger.Panicf("Failed creating channel hooks: %s", err)
+		}
+		peerInstance.ChannelHooks = channelHooks
 	}
 
+	var queryCache *endorser.QueryCache
+	if coreConfig.QueryCacheSize > 0 {
+		queryCache = endorser.NewQueryCache(coreConfig.QueryCacheSize, coreConfig.QueryCacheTTL)
+		peerInstance.BlockObserver = queryCache
+	}
+
+	localMSP := mgmt.GetLocalMSP(factory.GetDefault())
 	signingIdentityBytes, err := signingIdentity.Serialize()
 	if err != nil {
 		logger.Panicf("Failed to serialize the signing identity: %v", err)
@@ -342,6 +465,7 @@ func serve(args []string) error {
 		aclmgmt.ResourceGetter(peerInstance.GetStableChannelConfig),
 		policyChecker,
 	)
+	peerInstance.ACLProvider = aclProvider
 
 	// TODO, unfortunately, the lifecycle initialization is very unclean at the
 	// moment. This is because ccprovider.SetChaincodePath only works after
@@ -424,30 +548,113 @@ func serve(args []string) error {
 		ebMetadataProvider,
 	)
 
-	txProcessors := map[common.HeaderType]ledger.CustomTxProcessor{
-		common.HeaderType_CONFIG: &peer.ConfigTxProcessor{},
+	if err := customtx.Register(notarization.TxType, notarization.ACLResource, &notarization.Processor{}); err != nil {
+		return errors.WithMessage(err, "failed to register the notarization transaction processor")
 	}
+	if len(coreConfig.Triggers) > 0 {
+		triggerEngine, err := coretriggers.New(coreConfig.Triggers, filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "triggers"))
+		if err != nil {
+			return errors.WithMessage(err, "failed to create the triggers")
+		}
+		defer triggerEngine.Close()
+		if err := commitlistener.Register(triggerEngine); err != nil {
+			return errors.WithMessage(err, "failed to register the triggers")
+		}
+		channelReaders := &triggers.ChannelReaders{Channels: peerInstance}
+		opsSystem.RegisterAdminHandler("/ledger/triggers/events", triggers.NewEventsHandler(triggerEngine, channelReaders))
+		opsSystem.RegisterAdminHandler("/ledger/triggers/derived", triggers.NewDerivedHandler(triggerEngine, channelReaders))
+	}
+	var cdcExporter *cdc.Exporter
+	if coreConfig.CDCEnabled {
+		sink, err := cdc.NewSink(coreConfig)
+		if err != nil {
+			return errors.WithMessage(err, "failed to create the change data capture sink")
+		}
+		cursors, err := cdc.NewCursors(filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "cdc"))
+		if err != nil {
+			return errors.WithMessage(err, "failed to create the change data capture cursors")
+		}
+		filter := cdc.Filter{
+			Channels:   coreConfig.CDCChannels,
+			Namespaces: coreConfig.CDCNamespaces,
+		}
+		blockStores := func(cid string) cdc.BlockStore {
+			if l := peerInstance.GetLedger(cid); l != nil {
+				return l
+			}
+			return nil
+		}
+		cdcExporter = cdc.NewExporter(sink, cursors, filter, blockStores, coreConfig.CDCRetryInterval)
+		defer cdcExporter.Close()
+		if err := commitlistener.Register(cdcExporter); err != nil {
+			return errors.WithMessage(err, "failed to register the change data capture")
+		}
+	}
+
+	commitTimes := status.NewCommitTimes()
+	if err := commitlistener.Register(commitTimes); err != nil {
+		return errors.WithMessage(err, "failed to register the commit times of the status report")
+	}
+	healthCheckRegistry := &status.HealthCheckRegistry{Registry: opsSystem}
+
+	configTxProcessor := &peer.ConfigTxProcessor{}
+	txProcessors := customtx.Processors()
+	txProcessors[common.HeaderType_CONFIG] = configTxProcessor
 
 	peerInstance.LedgerMgr = ledgermgmt.NewLedgerMgr(
 		&ledgermgmt.Initializer{
 			CustomTxProcessors:              txProcessors,
+			CounterCapabilityProvider:       configTxProcessor,
 			DeployedChaincodeInfoProvider:   lifecycleValidatorCommitter,
 			MembershipInfoProvider:          membershipInfoProvider,
 			ChaincodeLifecycleEventProvider: lifecycleCache,
 			MetricsProvider:                 metricsProvider,
-			HealthCheckRegistry:             opsSystem,
+			HealthCheckRegistry:             healthCheckRegistry,
 			StateListeners:                  []ledger.StateListener{lifecycleCache},
 			Config:                          ledgerConfig(),
 			HashProvider:                    factory.GetDefault(),
 			EbMetadataProvider:              ebMetadataProvider,
+			CommitListeners:                 commitlistener.Listeners(),
 		},
 	)
+	opsSystem.RegisterAdminHandler("/ledger/backup", backup.NewHandler(peerInstance.LedgerMgr))
+	opsSystem.RegisterAdminHandler("/ledger/compaction", compaction.NewHandler(peerInstance.LedgerMgr))
+	opsSystem.RegisterAdminHandler("/msp/reload", mspreload.NewHandler(reloadLocalMSP))
+
+	var idemixAuditor qscc.IdemixAuditor
+	if coreConfig.IdemixAuditEnabled {
+		auditTrail, err := coreidemixaudit.NewTrail(filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "idemixaudit"), signingIdentity)
+		if err != nil {
+			return errors.WithMessage(err, "failed to open the idemix audit trail")
+		}
+		defer auditTrail.Close()
+		idemixAuditor = coreidemixaudit.New(auditTrail)
+		opsSystem.RegisterAdminHandler("/idemixaudit/trail", idemixaudit.NewTrailHandler(auditTrail))
+	}
 
 	peerServer, err := comm.NewGRPCServer(listenAddr, serverConfig)
 	if err != nil {
 		logger.Fatalf("Failed to create peer server (%s)", err)
 	}
 
+	if viper.GetBool("peer.enrollment.enabled") {
+		enroller, err := enrollment.New(peercommon.GetEnrollmentConfig())
+		if err != nil {
+			return errors.WithMessage(err, "invalid enrollment configuration")
+		}
+		go enroller.Run(nil, map[enrollment.Identity]func() error{
+			enrollment.MSPIdentity: reloadLocalMSP,
+			enrollment.TLSIdentity: func() error {
+				cert, err := tls.LoadX509KeyPair(coreconfig.GetPath("peer.tls.cert.file"), coreconfig.GetPath("peer.tls.key.file"))
+				if err != nil {
+					return err
+				}
+				peerServer.SetServerCertificate(cert)
+				return nil
+			},
+		})
+	}
+
 	// FIXME: Creating the gossip service has the side effect of starting a bunch
 	// of go routines and registration with the grpc server.
 	gossipService, err := initGossipService(
@@ -466,6 +673,10 @@ func serve(args []string) error {
 	}
 	defer gossipService.Stop()
 
+	if err := healthCheckRegistry.RegisterChecker("gossip", gossipService); err != nil {
+		return errors.WithMessage(err, "failed to register gossip health check")
+	}
+
 	peerInstance.GossipService = gossipService
 
 	if err := lifecycleCache.InitializeLocalChaincodes(); err != nil {
@@ -498,6 +709,17 @@ func serve(args []string) error {
 			false,
 		),
 		PolicyCheckerProvider: policyCheckerProvider,
+		ChaincodeEventPolicyChecker: aclmgmt.NewChaincodeEventACLProvider(
+			aclmgmt.ResourceGetter(peerInstance.GetStableChannelConfig),
+		),
+	}
+	if coreConfig.DelegationTokensEnabled {
+		logger.Infof("Accepting delegation tokens issued by identities satisfying %s on the deliver service", coreConfig.DelegationTokenIssuerPolicy)
+		abServer.DelegationTokens = &deliver.TokenAuthenticator{
+			IssuerPolicy: coreConfig.DelegationTokenIssuerPolicy,
+			MaxLifetime:  coreConfig.DelegationTokenMaxLifetime,
+			ClockSkew:    coreConfig.AuthenticationTimeWindow,
+		}
 	}
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
 
@@ -524,8 +746,8 @@ func serve(args []string) error {
 		HandlerRegistry: chaincodeHandlerRegistry,
 	}
 
-	if coreConfig.VMEndpoint == "" && len(coreConfig.ExternalBuilders) == 0 {
-		logger.Panic("VMEndpoint not set and no ExternalBuilders defined")
+	if coreConfig.VMEndpoint == "" && len(coreConfig.ExternalBuilders) == 0 && !coreConfig.ProcessLauncherEnabled {
+		logger.Panic("VMEndpoint not set, no ExternalBuilders defined and process launcher disabled")
 	}
 
 	chaincodeConfig := chaincode.GlobalConfig()
@@ -546,6 +768,7 @@ func serve(args []string) error {
 			HostConfig:    getDockerHostConfig(),
 			ChaincodePull: coreConfig.ChaincodePull,
 			NetworkMode:   coreConfig.VMNetworkMode,
+			Architecture:  ccutil.GetArchitecture(),
 			PlatformBuilder: &platforms.Builder{
 				Registry: platformRegistry,
 				Client:   client,
@@ -558,7 +781,13 @@ func serve(args []string) error {
 				"CORE_CHAINCODE_LOGGING_SHIM=" + chaincodeConfig.ShimLogLevel,
 				"CORE_CHAINCODE_LOGGING_FORMAT=" + chaincodeConfig.LogFormat,
 			},
-			MSPID: mspID,
+			MSPID:             mspID,
+			BuildCacheEnabled: coreConfig.ChaincodeBuildCacheEnabled,
+			BuilderVersion:    metadata.Version,
+		}
+		dockerVM.Sandbox, err = getDockerSandboxPolicy()
+		if err != nil {
+			logger.Panicf("invalid chaincode sandbox configuration: %s", err)
 		}
 		if err := opsSystem.RegisterChecker("docker", dockerVM); err != nil {
 			logger.Panicf("failed to register docker health check: %s", err)
@@ -575,11 +804,55 @@ func serve(args []string) error {
 		Builders:    externalbuilder.CreateBuilders(coreConfig.ExternalBuilders, mspID),
 		DurablePath: externalBuilderOutput,
 	}
+	if coreConfig.ChaincodeBuildCacheEnabled {
+		if err := os.MkdirAll(coreConfig.ChaincodeBuildCachePath, 0700); err != nil {
+			logger.Panicf("could not create chaincode build cache dir: %s", err)
+		}
+		externalVM.BuildCache = &externalbuilder.BuildCache{Path: coreConfig.ChaincodeBuildCachePath}
+	}
+
+	var processBuilder container.ProcessBuilder
+	if coreConfig.ProcessLauncherEnabled {
+		processLauncherOutput := filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "processlauncher", "builds")
+		// the chaincode user traverses the directories to its binary
+		if err := os.MkdirAll(processLauncherOutput, 0711); err != nil {
+			logger.Panicf("could not create process launcher build output dir: %s", err)
+		}
+
+		processVM := &processcontroller.ProcessVM{
+			DurablePath: processLauncherOutput,
+			MSPID:       mspID,
+			Platforms:   processcontroller.CreatePlatforms(coreConfig.ProcessLauncherPlatforms),
+			User: &processcontroller.User{
+				UID: coreConfig.ProcessLauncherUID,
+				GID: coreConfig.ProcessLauncherGID,
+			},
+			Namespaces: coreConfig.ProcessLauncherNamespaces,
+			LoggingEnv: []string{
+				"CORE_CHAINCODE_LOGGING_LEVEL=" + chaincodeConfig.LogLevel,
+				"CORE_CHAINCODE_LOGGING_SHIM=" + chaincodeConfig.ShimLogLevel,
+				"CORE_CHAINCODE_LOGGING_FORMAT=" + chaincodeConfig.LogFormat,
+			},
+		}
+		if coreConfig.ProcessLauncherCgroupRoot != "" {
+			processVM.Cgroups = &processcontroller.Cgroups{
+				Root:        coreConfig.ProcessLauncherCgroupRoot,
+				MemoryLimit: coreConfig.ProcessLauncherMemoryLimit,
+				CPUQuota:    coreConfig.ProcessLauncherCPUQuota,
+				CPUPeriod:   coreConfig.ProcessLauncherCPUPeriod,
+				PidsLimit:   coreConfig.ProcessLauncherPidsLimit,
+			}
+		} else {
+			logger.Warning("Process launcher enabled without a cgroup root, chaincode processes will not be confined to cgroups")
+		}
+		processBuilder = processVM
+	}
 
 	buildRegistry := &container.BuildRegistry{}
 
 	containerRouter := &container.Router{
 		DockerBuilder:   dockerBuilder,
+		ProcessBuilder:  processBuilder,
 		ExternalBuilder: externalVMAdapter{externalVM},
 		PackageProvider: &persistence.FallbackPackageLocator{
 			ChaincodePackageLocator: &persistence.ChaincodePackageLocator{
@@ -612,11 +885,12 @@ func serve(args []string) error {
 	}
 
 	chaincodeEndorsementInfo := &lifecycle.ChaincodeEndorsementInfoSource{
-		LegacyImpl:  lsccInst,
-		Resources:   lifecycleResources,
-		Cache:       lifecycleCache,
-		BuiltinSCCs: builtinSCCs,
-		UserRunsCC:  userRunsCC,
+		LegacyImpl:      lsccInst,
+		Resources:       lifecycleResources,
+		Cache:           lifecycleCache,
+		BuiltinSCCs:     builtinSCCs,
+		UserRunsCC:      userRunsCC,
+		ArgumentSchemas: argschema.NewCache(),
 	}
 
 	containerRuntime := &chaincode.ContainerRuntime{
@@ -627,7 +901,10 @@ func serve(args []string) error {
 	lifecycleFunctions := &lifecycle.ExternalFunctions{
 		Resources:                 lifecycleResources,
 		InstallListener:           lifecycleCache,
+		SBOMStore:                 ccStore,
 		InstalledChaincodesLister: lifecycleCache,
+		CommittedChaincodesLister: lifecycleCache,
+		PvtDataStatsProvider:      peerInstance.LedgerMgr,
 		ChaincodeBuilder:          containerRouter,
 		BuildRegistry:             buildRegistry,
 	}
@@ -660,11 +937,38 @@ func serve(args []string) error {
 		chaincodeLauncher.CertGenerator = nil
 	}
 
+	executionTraces := txtrace.NewRecorder(chaincodeConfig.MaxExecutionTraces)
+	opsSystem.RegisterHandler("/chaincode/traces", txtrace.NewHandler(executionTraces))
+	opsSystem.RegisterHandler("/status", status.NewHandler(&status.Reporter{
+		PeerID:         coreConfig.PeerID,
+		MSPID:          mspID,
+		StateDatabase:  ledgerConfig().StateDBConfig.StateDatabase,
+		StateDBChecker: healthCheckRegistry.Checker("couchdb"),
+		ChannelIDs: func() []string {
+			var channelIDs []string
+			for _, ci := range peerInstance.GetChannelsInfo() {
+				channelIDs = append(channelIDs, ci.ChannelId)
+			}
+			return channelIDs
+		},
+		Ledger: func(cid string) status.Ledger {
+			if l := peerInstance.GetLedger(cid); l != nil {
+				return l
+			}
+			return nil
+		},
+		CommitTimes: commitTimes,
+		Chaincodes:  chaincodeHandlerRegistry,
+		Gossip:      gossipService,
+	}))
+
 	chaincodeSupport := &chaincode.ChaincodeSupport{
 		ACLProvider:            aclProvider,
 		AppConfig:              peerInstance,
 		DeployedCCInfoProvider: lifecycleValidatorCommitter,
 		ExecuteTimeout:         chaincodeConfig.ExecuteTimeout,
+		ExecutionTraces:        executionTraces,
+		GMCrypto:               &chaincode.GMCrypto{CSP: factory.GetDefault()},
 		InstallTimeout:         chaincodeConfig.InstallTimeout,
 		HandlerRegistry:        chaincodeHandlerRegistry,
 		HandlerMetrics:         chaincode.NewHandlerMetrics(opsSystem.Provider),
@@ -684,6 +988,42 @@ func serve(args []string) error {
 	}
 	go chaincodeCustodian.Work(buildRegistry, containerRouter, custodianLauncher)
 
+	var upgradeCoordinator *upgrade.Coordinator
+	if chaincodeConfig.UpgradeEnabled {
+		upgradeCoordinator = upgrade.NewCoordinator(
+			upgradeRuntimeAdapter{
+				custodianLauncherAdapter: custodianLauncher,
+				registry:                 chaincodeHandlerRegistry,
+			},
+			upgradeInitStateAdapter{
+				peer:      peerInstance,
+				lifecycle: chaincodeEndorsementInfo,
+			},
+			chaincodeConfig.UpgradeDrainTimeout,
+		)
+		chaincodeSupport.UpgradeRouter = upgradeCoordinator
+		opsSystem.RegisterHandler("/chaincode/upgrades", upgrade.NewHandler(upgradeCoordinator))
+	}
+
+	if chaincodeConfig.DebugEnabled {
+		var certIssuer debug.CertIssuer
+		if chaincodeConfig.TLSEnabled {
+			certIssuer = authenticator
+		}
+		debugManager := debug.NewManager(chaincodeHandlerRegistry, certIssuer, executionTraces, ccEndpoint, ca.CertBytes())
+		chaincodeSupport.DebugRouter = debugManager
+		opsSystem.RegisterAdminHandler("/chaincode/debug", debug.NewHandler(debugManager))
+	}
+
+	if chaincodeConfig.WarmPoolSize > 0 && !userRunsCC {
+		chaincodeSupport.WarmPool = chaincode.NewWarmPool(
+			chaincodeConfig.WarmPoolSize,
+			chaincodeConfig.WarmPoolInterval,
+			filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "warmpool", "invocations.json"),
+			chaincodeSupport,
+		)
+	}
+
 	ccSupSrv := pb.ChaincodeSupportServer(chaincodeSupport)
 	if tlsEnabled {
 		ccSupSrv = authenticator.Wrap(ccSupSrv)
@@ -698,13 +1038,18 @@ func serve(args []string) error {
 		peerInstance,
 		factory.GetDefault(),
 	)
-	qsccInst := scc.SelfDescribingSysCC(qscc.New(aclProvider, peerInstance))
+	qsccInst := scc.SelfDescribingSysCC(qscc.New(aclProvider, peerInstance, idemixAuditor))
 
 	pb.RegisterChaincodeSupportServer(ccSrv.Server(), ccSupSrv)
 
 	// start the chaincode specific gRPC listening service
 	go ccSrv.Start()
 
+	// launch the chaincodes of the warm pool once chaincodes can connect
+	if chaincodeSupport.WarmPool != nil {
+		go chaincodeSupport.WarmPool.Run()
+	}
+
 	logger.Debugf("Running peer")
 
 	libConf, err := library.LoadConfig()
@@ -721,6 +1066,9 @@ func serve(args []string) error {
 		ChaincodeSupport: chaincodeSupport,
 		ACLProvider:      aclProvider,
 		BuiltinSCCs:      builtinSCCs,
+		ChaincodeFunctionACLProvider: aclmgmt.NewChaincodeFunctionACLProvider(
+			aclmgmt.ResourceGetter(peerInstance.GetStableChannelConfig),
+		),
 	}
 	endorsementPluginsByName := reg.Lookup(library.Endorsement).(map[string]endorsement2.PluginFactory)
 	validationPluginsByName := reg.Lookup(library.Validation).(map[string]validation.PluginFactory)
@@ -737,12 +1085,30 @@ func serve(args []string) error {
 	channelFetcher := endorserChannelAdapter{
 		peer: peerInstance,
 	}
+	var admission *endorser.AdmissionController
+	if coreConfig.AdmissionCommitBacklog > 0 || coreConfig.AdmissionGoroutines > 0 || coreConfig.AdmissionMaxHeapSize > 0 {
+		admission = endorser.NewAdmissionController(
+			endorser.AdmissionThresholds{
+				CommitBacklog: coreConfig.AdmissionCommitBacklog,
+				Goroutines:    coreConfig.AdmissionGoroutines,
+				HeapBytes:     coreConfig.AdmissionMaxHeapSize,
+			},
+			coreConfig.AdmissionInterval,
+			gossipService,
+		)
+		go admission.Run()
+		defer admission.Stop()
+	}
+
 	serverEndorser := &endorser.Endorser{
 		PrivateDataDistributor: gossipService,
 		ChannelFetcher:         channelFetcher,
 		LocalMSP:               localMSP,
 		Support:                endorserSupport,
 		Metrics:                endorser.NewMetrics(metricsProvider),
+		Tracer:                 tracer,
+		QueryCache:             queryCache,
+		Admission:              admission,
 	}
 
 	// deploy system chaincodes
@@ -775,6 +1141,19 @@ func serve(args []string) error {
 			// channel but it won't fire any updates to its listeners
 			lifecycleCache.InitializeMetadata(cid)
 
+			// export the changes committed while the peer was down
+			if cdcExporter != nil {
+				if err := cdcExporter.Resume(cid); err != nil {
+					logger.Errorf("Failed resuming the change data capture of channel %s: %s", cid, err)
+				}
+			}
+
+			// launch the new chaincode packages of this channel ahead of
+			// the invocations once their definition is committed
+			if upgradeCoordinator != nil {
+				lifecycleCache.RegisterListener(cid, upgradeCoordinator.ChannelListener(cid))
+			}
+
 			// initialize the legacyMetadataManager for this channel.
 			// This call will pre-populate chaincode information from
 			// the legacy lifecycle for this channel; it will also fire
@@ -839,6 +1218,11 @@ func serve(args []string) error {
 	handleSignals(addPlatformSignals(map[os.Signal]func(){
 		syscall.SIGINT:  func() { containerRouter.Shutdown(5 * time.Second); serve <- nil },
 		syscall.SIGTERM: func() { containerRouter.Shutdown(5 * time.Second); serve <- nil },
+		syscall.SIGHUP: func() {
+			if err := reloadLocalMSP(); err != nil {
+				logger.Errorf("Failed reloading the local MSP: %+v", err)
+			}
+		},
 	}))
 
 	logger.Infof("Started peer with ID=[%s], network ID=[%s], address=[%s]", coreConfig.PeerID, coreConfig.NetworkID, coreConfig.PeerAddress)
@@ -873,6 +1257,13 @@ func serve(args []string) error {
 	auth := authHandler.ChainFilters(serverEndorser, authFilters...)
 	// Register the Endorser server
 	pb.RegisterEndorserServer(peerServer.Server(), auth)
+	if coreConfig.EndorserStreamingEnabled {
+		logger.Info("Streaming of proposal responses is enabled")
+		proposalstream.RegisterServer(peerServer.Server(), &proposalstream.ChunkingServer{
+			Endorser:  auth,
+			ChunkSize: coreConfig.EndorserStreamingChunkSize,
+		})
+	}
 
 	go func() {
 		var grpcErr error
@@ -913,8 +1304,7 @@ func localPolicy(policyObject proto.Message) policies.Policy {
 	return policy
 }
 
-func createSelfSignedData() protoutil.SignedData {
-	sID := mgmt.GetLocalSigningIdentityOrPanic(factory.GetDefault())
+func createSelfSignedData(sID msp.SigningIdentity) protoutil.SignedData {
 	msg := make([]byte, 32)
 	sig, err := sID.Sign(msg)
 	if err != nil {
@@ -979,7 +1369,7 @@ func createChaincodeServer(coreConfig *peer.Config, ca tlsgen.CA, peerHostname s
 	if err != nil {
 		if chaincode.IsDevMode() {
 			// if any error for dev mode, we use 0.0.0.0:7052
-			ccEndpoint = fmt.Sprintf("%s:%d", "0.0.0.0", defaultChaincodePort)
+			ccEndpoint = net.JoinHostPort("0.0.0.0", fmt.Sprintf("%d", defaultChaincodePort))
 			logger.Warningf("use %s as chaincode endpoint because of error in computeChaincodeEndpoint: %s", ccEndpoint, err)
 		} else {
 			// for non-dev mode, we have to return error
@@ -995,7 +1385,7 @@ func createChaincodeServer(coreConfig *peer.Config, ca tlsgen.CA, peerHostname s
 
 	cclistenAddress := coreConfig.ChaincodeListenAddress
 	if cclistenAddress == "" {
-		cclistenAddress = fmt.Sprintf("%s:%d", peerHostname, defaultChaincodePort)
+		cclistenAddress = net.JoinHostPort(peerHostname, fmt.Sprintf("%d", defaultChaincodePort))
 		logger.Warningf("%s is not set, using %s", chaincodeListenAddrKey, cclistenAddress)
 		coreConfig.ChaincodeListenAddress = cclistenAddress
 	}
@@ -1094,7 +1484,7 @@ func computeChaincodeEndpoint(chaincodeAddress string, chaincodeListenAddress st
 				logger.Error("ChaincodeAddress is nil while both chaincodeListenAddressIP and peerIP are 0.0.0.0")
 				return "", errors.New("invalid endpoint for chaincode to connect")
 			}
-			ccEndpoint = fmt.Sprintf("%s:%s", peerHostname, port)
+			ccEndpoint = net.JoinHostPort(peerHostname, port)
 		}
 		logger.Infof("Exit with ccEndpoint: %s", ccEndpoint)
 		return ccEndpoint, nil
@@ -1109,7 +1499,7 @@ func computeChaincodeEndpoint(chaincodeAddress string, chaincodeListenAddress st
 	}
 
 	// use peerAddress:defaultChaincodePort
-	ccEndpoint = fmt.Sprintf("%s:%d", peerHostname, defaultChaincodePort)
+	ccEndpoint = net.JoinHostPort(peerHostname, fmt.Sprintf("%d", defaultChaincodePort))
 
 	logger.Infof("Exit with ccEndpoint: %s", ccEndpoint)
 	return ccEndpoint, nil
@@ -1204,6 +1594,15 @@ func initGossipService(
 	if err != nil {
 		return nil, errors.Wrap(err, "failed obtaining gossip config")
 	}
+	if viper.GetBool("peer.gossip.tlsPinning.enabled") {
+		if !peerServer.TLSEnabled() {
+			return nil, errors.New("gossip TLS pinning requires TLS to be enabled")
+		}
+		gossipConfig.TLSPinner, err = newGossipTLSPinner()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return gossipservice.New(
 		signer,
@@ -1222,6 +1621,24 @@ func initGossipService(
 	)
 }
 
+func newGossipTLSPinner() (gossipapi.TLSPinner, error) {
+	var pins []peergossip.TLSPin
+	if err := viper.UnmarshalKey("peer.gossip.tlsPinning.pins", &pins); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal peer.gossip.tlsPinning.pins")
+	}
+	configDir := filepath.Dir(viper.ConfigFileUsed())
+	for _, pin := range pins {
+		for i := range pin.CertFiles {
+			coreconfig.TranslatePathInPlace(configDir, &pin.CertFiles[i])
+		}
+	}
+	pinner, err := peergossip.NewTLSPinner(mgmt.NewDeserializersManager(factory.GetDefault()), pins)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed creating gossip TLS pinner")
+	}
+	return pinner, nil
+}
+
 func newOperationsSystem(coreConfig *peer.Config) *operations.System {
 	return operations.NewSystem(operations.Options{
 		Logger:        flogging.MustGetLogger("peer.operations"),
@@ -1243,6 +1660,10 @@ func newOperationsSystem(coreConfig *peer.Config) *operations.System {
 			ClientCACertFiles:  coreConfig.OperationsTLSClientRootCAs,
 		},
 		Version: metadata.Version,
+		LogSinks: flogging.SinkAllowList{
+			Directories: coreConfig.OperationsLogSinkDirectories,
+			Hosts:       coreConfig.OperationsLogSinkHosts,
+		},
 	})
 }
 
@@ -1294,6 +1715,79 @@ func getDockerHostConfig() *docker.HostConfig {
 	}
 }
 
+// sandboxProfileConfig is the configuration of a sandbox profile of the
+// chaincode containers, under vm.docker.sandbox
+type sandboxProfileConfig struct {
+	Label           string `mapstructure:"label"`
+	SeccompProfile  string `mapstructure:"seccompProfile"`
+	AppArmorProfile string `mapstructure:"apparmorProfile"`
+	NoNewPrivileges bool   `mapstructure:"noNewPrivileges"`
+}
+
+// getDockerSandboxPolicy returns the syscall policy of the chaincode
+// containers, or nil if none is configured
+func getDockerSandboxPolicy() (*dockercontroller.SandboxPolicy, error) {
+	defaultConfig := sandboxProfileConfig{
+		SeccompProfile:  viper.GetString("vm.docker.sandbox.seccompProfile"),
+		AppArmorProfile: viper.GetString("vm.docker.sandbox.apparmorProfile"),
+		NoNewPrivileges: viper.GetBool("vm.docker.sandbox.noNewPrivileges"),
+	}
+	var overrides []sandboxProfileConfig
+	if err := viper.UnmarshalKey("vm.docker.sandbox.overrides", &overrides); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal vm.docker.sandbox.overrides")
+	}
+
+	defaultProfile, err := sandboxProfile(defaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	if defaultProfile == (dockercontroller.SandboxProfile{}) && len(overrides) == 0 {
+		return nil, nil
+	}
+
+	policy := &dockercontroller.SandboxPolicy{
+		Default:   defaultProfile,
+		Overrides: map[string]dockercontroller.SandboxProfile{},
+	}
+	for _, override := range overrides {
+		if override.Label == "" {
+			return nil, errors.New("sandbox profile override without a chaincode label")
+		}
+		if _, exists := policy.Overrides[override.Label]; exists {
+			return nil, errors.Errorf("duplicate sandbox profile override for chaincode %s", override.Label)
+		}
+		profile, err := sandboxProfile(override)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "sandbox profile override for chaincode %s", override.Label)
+		}
+		policy.Overrides[override.Label] = profile
+	}
+	return policy, nil
+}
+
+// sandboxProfile loads the seccomp profile file of the given configuration
+func sandboxProfile(conf sandboxProfileConfig) (dockercontroller.SandboxProfile, error) {
+	profile := dockercontroller.SandboxProfile{
+		Seccomp:         conf.SeccompProfile,
+		AppArmor:        conf.AppArmorProfile,
+		NoNewPrivileges: conf.NoNewPrivileges,
+	}
+	if profile.Seccomp == "" || profile.Seccomp == dockercontroller.Unconfined {
+		return profile, nil
+	}
+
+	path := coreconfig.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), profile.Seccomp)
+	seccomp, err := ioutil.ReadFile(path)
+	if err != nil {
+		return dockercontroller.SandboxProfile{}, errors.Wrap(err, "could not read seccomp profile")
+	}
+	if !json.Valid(seccomp) {
+		return dockercontroller.SandboxProfile{}, errors.Errorf("seccomp profile %s is not valid JSON", path)
+	}
+	profile.Seccomp = string(seccomp)
+	return profile, nil
+}
+
 //go:generate counterfeiter -o mock/get_ledger.go -fake-name GetLedger . getLedger
 //go:generate counterfeiter -o mock/peer_ledger.go -fake-name PeerLedger . peerLedger
 
@@ -10,11 +10,13 @@ import (
 	"bytes"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/container/dockercontroller"
 	"github.com/hyperledger/fabric/core/handlers/library"
 	"github.com/hyperledger/fabric/core/testutil"
 	"github.com/hyperledger/fabric/internal/peer/node/mock"
@@ -166,6 +168,58 @@ func TestGetDockerHostConfig(t *testing.T) {
 	assert.Equal(t, int64(0), hostConfig.CPUShares)
 }
 
+func TestGetDockerSandboxPolicy(t *testing.T) {
+	defer viper.Reset()
+	viper.Reset()
+
+	policy, err := getDockerSandboxPolicy()
+	assert.NoError(t, err)
+	assert.Nil(t, policy)
+
+	tempDir, err := ioutil.TempDir("", "sandbox")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	seccompPath := filepath.Join(tempDir, "seccomp.json")
+	assert.NoError(t, ioutil.WriteFile(seccompPath, []byte(`{"defaultAction":"SCMP_ACT_ERRNO"}`), 0644))
+
+	viper.Set("vm.docker.sandbox.seccompProfile", seccompPath)
+	viper.Set("vm.docker.sandbox.apparmorProfile", "fabric-chaincode")
+	viper.Set("vm.docker.sandbox.noNewPrivileges", true)
+	viper.Set("vm.docker.sandbox.overrides", []map[string]interface{}{
+		{"label": "legacycc", "seccompProfile": "unconfined", "apparmorProfile": "unconfined"},
+	})
+	policy, err = getDockerSandboxPolicy()
+	assert.NoError(t, err)
+	assert.Equal(t, &dockercontroller.SandboxPolicy{
+		Default: dockercontroller.SandboxProfile{
+			Seccomp:         `{"defaultAction":"SCMP_ACT_ERRNO"}`,
+			AppArmor:        "fabric-chaincode",
+			NoNewPrivileges: true,
+		},
+		Overrides: map[string]dockercontroller.SandboxProfile{
+			"legacycc": {Seccomp: "unconfined", AppArmor: "unconfined"},
+		},
+	}, policy)
+
+	viper.Set("vm.docker.sandbox.overrides", []map[string]interface{}{{"label": "mycc"}, {"label": "mycc"}})
+	_, err = getDockerSandboxPolicy()
+	assert.EqualError(t, err, "duplicate sandbox profile override for chaincode mycc")
+
+	viper.Set("vm.docker.sandbox.overrides", []map[string]interface{}{{"apparmorProfile": "unconfined"}})
+	_, err = getDockerSandboxPolicy()
+	assert.EqualError(t, err, "sandbox profile override without a chaincode label")
+
+	viper.Set("vm.docker.sandbox.overrides", nil)
+	assert.NoError(t, ioutil.WriteFile(seccompPath, []byte("not json"), 0644))
+	_, err = getDockerSandboxPolicy()
+	assert.EqualError(t, err, "seccomp profile "+seccompPath+" is not valid JSON")
+
+	viper.Set("vm.docker.sandbox.seccompProfile", filepath.Join(tempDir, "missing.json"))
+	_, err = getDockerSandboxPolicy()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not read seccomp profile")
+}
+
 func TestResetLoop(t *testing.T) {
 	peerLedger := &mock.PeerLedger{}
 	peerLedger.GetBlockchainInfoReturnsOnCall(
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const statusTimeout = 30 * time.Second
+
+var (
+	operationsAddress  string
+	operationsCAFile   string
+	operationsCertFile string
+	operationsKeyFile  string
+)
+
+func statusCmd() *cobra.Command {
+	nodeStatusCmd.ResetFlags()
+	flags := nodeStatusCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose status is reported. All the channels joined by the peer are reported when not set.")
+	flags.StringVar(&operationsAddress, "operationsAddress", "", "Address of the operations endpoint of the peer. Defaults to operations.listenAddress.")
+	flags.StringVar(&operationsCAFile, "cafile", "", "Path to the PEM encoded CA certificates trusted to verify the operations endpoint when operations.tls.enabled is set.")
+	flags.StringVar(&operationsCertFile, "certfile", "", "Path to the PEM encoded client certificate for the operations endpoint.")
+	flags.StringVar(&operationsKeyFile, "keyfile", "", "Path to the PEM encoded client key for the operations endpoint.")
+
+	return nodeStatusCmd
+}
+
+var nodeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Reports the status of the peer.",
+	Long:  `Reports as JSON the status of a running peer, read from its operations endpoint: the state database type and health, the chaincodes running and, for each channel, the height, the time of the last commit, the private data reconciliation backlog and the gossip membership.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		address := operationsAddress
+		if address == "" {
+			address = viper.GetString("operations.listenAddress")
+		}
+		var channelIDs []string
+		if channelID != common.UndefinedParamValue {
+			channelIDs = append(channelIDs, channelID)
+		}
+		client, scheme, err := operationsClient()
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		return printStatus(os.Stdout, client, scheme+"://"+address, channelIDs)
+	},
+}
+
+// operationsClient returns the HTTP client for the operations endpoint and
+// the scheme of its URL.
+func operationsClient() (*http.Client, string, error) {
+	client := &http.Client{Timeout: statusTimeout}
+	if !viper.GetBool("operations.tls.enabled") {
+		return client, "http", nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if operationsCAFile != "" {
+		caPEM, err := ioutil.ReadFile(operationsCAFile)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "failed reading %s", operationsCAFile)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, "", errors.Errorf("no certificate found in %s", operationsCAFile)
+		}
+	}
+	if operationsCertFile != "" || operationsKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(operationsCertFile, operationsKeyFile)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed loading the client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	return client, "https", nil
+}
+
+// printStatus writes the indented status report read from the operations
+// endpoint at baseURL.
+func printStatus(w io.Writer, client *http.Client, baseURL string, channelIDs []string) error {
+	query := url.Values{}
+	for _, channelID := range channelIDs {
+		query.Add("channel", channelID)
+	}
+	statusURL := baseURL + "/status"
+	if len(query) > 0 {
+		statusURL += "?" + query.Encode()
+	}
+
+	resp, err := client.Get(statusURL)
+	if err != nil {
+		return errors.Wrap(err, "failed reading the status of the peer")
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed reading the status of the peer")
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return errors.Errorf("failed reading the status of the peer: %s", errResp.Error)
+		}
+		return errors.Errorf("failed reading the status of the peer: %s", resp.Status)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		return errors.Wrap(err, "invalid status of the peer")
+	}
+	_, err = out.WriteTo(w)
+	return err
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintStatus(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		if r.URL.Path != "/status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"peer_id":"peer0","channels":[{"channel_id":"mychannel","height":10}]}` + "\n"))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	err := printStatus(&out, server.Client(), server.URL, []string{"mychannel"})
+	assert.NoError(t, err)
+	assert.Equal(t, "channel=mychannel", query)
+	assert.Equal(t, `{
+  "peer_id": "peer0",
+  "channels": [
+    {
+      "channel_id": "mychannel",
+      "height": 10
+    }
+  ]
+}
+`, out.String())
+
+	out.Reset()
+	err = printStatus(&out, server.Client(), server.URL+"/unknown", nil)
+	assert.EqualError(t, err, "failed reading the status of the peer: 404 Not Found")
+	assert.Empty(t, out.String())
+}
+
+func TestPrintStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid request method: GET"}`))
+	}))
+	defer server.Close()
+
+	err := printStatus(&bytes.Buffer{}, server.Client(), server.URL, nil)
+	assert.EqualError(t, err, "failed reading the status of the peer: invalid request method: GET")
+
+	server.Close()
+	err = printStatus(&bytes.Buffer{}, server.Client(), server.URL, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed reading the status of the peer")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	coreconfig "github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	transientChaincodes []string
+	transientDataFile   string
+)
+
+func exportTransientDataCmd() *cobra.Command {
+	nodeExportTransientDataCmd.ResetFlags()
+	flags := nodeExportTransientDataCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose transient data is exported.")
+	flags.StringSliceVarP(&transientChaincodes, "chaincode", "n", nil, "Chaincodes whose private data is exported. All the private data is exported when not set.")
+	flags.StringVarP(&transientDataFile, "output", "o", "-", "File the transient data is written to, or '-' for the standard output.")
+
+	return nodeExportTransientDataCmd
+}
+
+var nodeExportTransientDataCmd = &cobra.Command{
+	Use:   "export-transient-data",
+	Short: "Exports the transient data of a channel.",
+	Long:  `Exports the private data held in the transient store for a channel, awaiting the commit of their transactions, for diagnostics or to import it on another endorsing peer. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+
+		var w io.Writer = os.Stdout
+		if transientDataFile != "-" {
+			file, err := os.Create(transientDataFile)
+			if err != nil {
+				return errors.Wrapf(err, "failed creating %s", transientDataFile)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		return withTransientStore(channelID, func(store *transientstore.Store) error {
+			count, err := store.Export(w, transientChaincodes...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d private write sets of channel %s\n", count, channelID)
+			return nil
+		})
+	},
+}
+
+func importTransientDataCmd() *cobra.Command {
+	nodeImportTransientDataCmd.ResetFlags()
+	flags := nodeImportTransientDataCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose transient data is imported.")
+	flags.StringVarP(&transientDataFile, "input", "i", "-", "File the transient data is read from, or '-' for the standard input.")
+
+	return nodeImportTransientDataCmd
+}
+
+var nodeImportTransientDataCmd = &cobra.Command{
+	Use:   "import-transient-data",
+	Short: "Imports transient data into a channel.",
+	Long:  `Imports into the transient store of a channel the private data exported with export-transient-data, typically on another endorsing peer, so that the peer can commit the private data of transactions endorsed by a replaced endorser. When the command is executed, the peer must be offline.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+
+		var r io.Reader = os.Stdin
+		if transientDataFile != "-" {
+			file, err := os.Open(transientDataFile)
+			if err != nil {
+				return errors.Wrapf(err, "failed opening %s", transientDataFile)
+			}
+			defer file.Close()
+			r = file
+		}
+
+		return withTransientStore(channelID, func(store *transientstore.Store) error {
+			count, err := store.Import(r)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Imported %d private write sets into channel %s\n", count, channelID)
+			return nil
+		})
+	},
+}
+
+func withTransientStore(channelID string, f func(*transientstore.Store) error) error {
+	provider, err := transientstore.NewStoreProvider(
+		filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "transientstore"),
+	)
+	if err != nil {
+		return errors.WithMessage(err, "failed opening the transient store, make sure the peer is offline")
+	}
+	defer provider.Close()
+
+	store, err := provider.OpenStore(channelID)
+	if err != nil {
+		return err
+	}
+	defer store.Shutdown()
+	return f(store)
+}
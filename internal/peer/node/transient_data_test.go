@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	tspb "github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransientDataCmds(t *testing.T) {
+	t.Run("when the channelID is not supplied", func(t *testing.T) {
+		cmd := exportTransientDataCmd()
+		cmd.SetArgs([]string{})
+		require.EqualError(t, cmd.Execute(), "Must supply channel ID")
+
+		cmd = importTransientDataCmd()
+		cmd.SetArgs([]string{})
+		require.EqualError(t, cmd.Execute(), "Must supply channel ID")
+	})
+
+	t.Run("when the transient data is exported and imported", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "transient-data")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		defer viper.Reset()
+
+		pvtData := &tspb.TxPvtReadWriteSetWithConfigInfo{
+			PvtRwset: &rwset.TxPvtReadWriteSet{
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{Namespace: "cc1", CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{CollectionName: "coll", Rwset: []byte("rwset")}}},
+				},
+			},
+		}
+		sourcePath := filepath.Join(dir, "source")
+		provider, err := transientstore.NewStoreProvider(filepath.Join(sourcePath, "transientstore"))
+		require.NoError(t, err)
+		store, err := provider.OpenStore("ch1")
+		require.NoError(t, err)
+		require.NoError(t, store.Persist("txid-1", 5, pvtData))
+		provider.Close()
+
+		exportFile := filepath.Join(dir, "export.json")
+		viper.Set("peer.fileSystemPath", sourcePath)
+		cmd := exportTransientDataCmd()
+		cmd.SetArgs([]string{"-c", "ch1", "-n", "cc1", "-o", exportFile})
+		require.NoError(t, cmd.Execute())
+
+		targetPath := filepath.Join(dir, "target")
+		viper.Set("peer.fileSystemPath", targetPath)
+		cmd = importTransientDataCmd()
+		cmd.SetArgs([]string{"-c", "ch1", "-i", exportFile})
+		require.NoError(t, cmd.Execute())
+
+		provider, err = transientstore.NewStoreProvider(filepath.Join(targetPath, "transientstore"))
+		require.NoError(t, err)
+		defer provider.Close()
+		store, err = provider.OpenStore("ch1")
+		require.NoError(t, err)
+		iter, err := store.GetTxPvtRWSetByTxid("txid-1", nil)
+		require.NoError(t, err)
+		defer iter.Close()
+		result, err := iter.Next()
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, uint64(5), result.ReceivedAtBlockHeight)
+		require.Equal(t, "cc1", result.PvtSimulationResultsWithConfig.PvtRwset.NsPvtRwset[0].Namespace)
+	})
+
+	t.Run("when the input file does not exist", func(t *testing.T) {
+		cmd := importTransientDataCmd()
+		cmd.SetArgs([]string{"-c", "ch1", "-i", "/does/not/exist"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed opening /does/not/exist")
+	})
+}
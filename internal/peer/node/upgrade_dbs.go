@@ -7,11 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+var upgradeDryRun bool
+
 func upgradeDBsCmd() *cobra.Command {
+	nodeUpgradeDBsCmd.ResetFlags()
+	flags := nodeUpgradeDBsCmd.Flags()
+	flags.BoolVar(&upgradeDryRun, "dry-run", false, "Reports what the upgrade does to each database, the disk space and time needed to rebuild the dropped databases, and any issue preventing the upgrade, without modifying the databases.")
+
 	return nodeUpgradeDBsCmd
 }
 
@@ -21,6 +31,48 @@ var nodeUpgradeDBsCmd = &cobra.Command{
 	Long:  "Upgrades databases by directly updating the database format or dropping the databases. Dropped databases will be rebuilt with new format upon peer restart. When the command is executed, the peer must be offline.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		config := ledgerConfig()
-		return kvledger.UpgradeDBs(config)
+		if !upgradeDryRun {
+			return kvledger.UpgradeDBs(config)
+		}
+
+		plan, err := kvledger.UpgradeDBsDryRun(config)
+		if err != nil {
+			return err
+		}
+		printUpgradePlan(cmd.OutOrStdout(), plan)
+		if len(plan.Blockers) != 0 {
+			return errors.Errorf("%d issues prevent the upgrade of the databases, fix the issues reported above before upgrading", len(plan.Blockers))
+		}
+		return nil
 	},
 }
+
+func printUpgradePlan(w io.Writer, plan *kvledger.UpgradePlan) {
+	fmt.Fprintln(w, "Databases:")
+	for _, db := range plan.DBs {
+		if !db.Exists {
+			fmt.Fprintf(w, "\t%s [%s]: missing, action: %s\n", db.Name, db.Location, db.Action)
+			continue
+		}
+		fmt.Fprintf(w, "\t%s [%s]: format %q, %d bytes, action: %s\n", db.Name, db.Location, db.Format, db.SizeBytes, db.Action)
+	}
+	if len(plan.PendingRebuilds) != 0 {
+		fmt.Fprintf(w, "The rebuild of the databases of ledgers %s is yet to complete, no database is dropped\n", plan.PendingRebuilds)
+	}
+	if plan.BlockFilesBytes != 0 {
+		fmt.Fprintf(w, "Rebuild from %d bytes of block files: estimated duration %s\n", plan.BlockFilesBytes, plan.EstimatedRebuildDuration)
+		if plan.AvailableDiskBytes < 0 {
+			fmt.Fprintf(w, "Disk space: %d bytes required, available space unknown\n", plan.RequiredDiskBytes)
+		} else {
+			fmt.Fprintf(w, "Disk space: %d bytes required, %d bytes available\n", plan.RequiredDiskBytes, plan.AvailableDiskBytes)
+		}
+	}
+	if len(plan.Blockers) == 0 {
+		fmt.Fprintln(w, "No issue prevents the upgrade")
+		return
+	}
+	fmt.Fprintln(w, "Issues preventing the upgrade:")
+	for _, blocker := range plan.Blockers {
+		fmt.Fprintf(w, "\t[FAIL] %s\n", blocker)
+	}
+}
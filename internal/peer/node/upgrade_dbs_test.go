@@ -7,9 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"bytes"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,3 +26,43 @@ func TestUpgradeDBsCmd(t *testing.T) {
 	cmd := upgradeDBsCmd()
 	assert.NoError(t, cmd.Execute())
 }
+
+func TestUpgradeDBsCmdDryRun(t *testing.T) {
+	testPath := "/tmp/hyperledger/test"
+	os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer os.RemoveAll(testPath)
+
+	cmd := upgradeDBsCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOutput(out)
+	cmd.SetArgs([]string{"--dry-run"})
+	defer cmd.SetArgs(nil)
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "Databases:\nNo issue prevents the upgrade\n", out.String())
+	assert.NoDirExists(t, testPath)
+}
+
+func TestPrintUpgradePlan(t *testing.T) {
+	plan := &kvledger.UpgradePlan{
+		DBs: []*kvledger.DBUpgrade{
+			{Name: "idStore", Location: "/ledgersData/ledgerProvider", Exists: true, Format: "1.x", SizeBytes: 10, Action: kvledger.UpgradeActionUpgradeFormat},
+			{Name: "history", Location: "/ledgersData/historyLeveldb", Action: kvledger.UpgradeActionNone},
+		},
+		BlockFilesBytes:          100,
+		EstimatedRebuildDuration: time.Minute,
+		RequiredDiskBytes:        20,
+		AvailableDiskBytes:       -1,
+		Blockers:                 []string{"not enough space"},
+	}
+	out := &bytes.Buffer{}
+	printUpgradePlan(out, plan)
+	assert.Equal(t, `Databases:
+	idStore [/ledgersData/ledgerProvider]: format "1.x", 10 bytes, action: upgrade format
+	history [/ledgersData/historyLeveldb]: missing, action: none
+Rebuild from 100 bytes of block files: estimated duration 1m0s
+Disk space: 20 bytes required, available space unknown
+Issues preventing the upgrade:
+	[FAIL] not enough space
+`, out.String())
+}
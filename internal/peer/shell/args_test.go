@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+		err      string
+	}{
+		{line: "", expected: nil},
+		{line: "  channel   list ", expected: []string{"channel", "list"}},
+		{
+			line:     `chaincode query -n mycc -c '{"Args":["query","a"]}'`,
+			expected: []string{"chaincode", "query", "-n", "mycc", "-c", `{"Args":["query","a"]}`},
+		},
+		{line: `-c "{\"Args\":[]}"`, expected: []string{"-c", `{"Args":[]}`}},
+		{line: `a\ b "" c`, expected: []string{"a b", "", "c"}},
+		{line: `--label='my label'x`, expected: []string{"--label=my labelx"}},
+		{line: `-c '{"Args"`, err: "unterminated ' quote"},
+		{line: `-c "x`, err: `unterminated " quote`},
+		{line: `a\`, err: "unterminated escape sequence"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			args, err := SplitArgs(tt.line)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
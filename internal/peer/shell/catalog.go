@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"context"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// PeerCatalog lists the channels the peer configured for the CLI has joined,
+// and the chaincodes committed on them, through the cscc and _lifecycle
+// system chaincodes of the peer.
+type PeerCatalog struct {
+	EndorserClient pb.EndorserClient
+	Signer         identity.SignerSerializer
+}
+
+// NewPeerCatalog returns a catalog querying the peer set by the
+// peer.address configuration, with the identity of the local MSP.
+func NewPeerCatalog() (*PeerCatalog, error) {
+	endorserClient, err := common.GetEndorserClientFnc(common.UndefinedParamValue, common.UndefinedParamValue)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to retrieve endorser client")
+	}
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to retrieve default signer")
+	}
+	return &PeerCatalog{
+		EndorserClient: endorserClient,
+		Signer:         signer,
+	}, nil
+}
+
+// Channels returns the channels the peer has joined
+func (p *PeerCatalog) Channels() ([]string, error) {
+	payload, err := p.query("", "cscc", [][]byte{[]byte(cscc.GetChannels)})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pb.ChannelQueryResponse{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal channels")
+	}
+	var channels []string
+	for _, channel := range result.Channels {
+		channels = append(channels, channel.ChannelId)
+	}
+	sort.Strings(channels)
+	return channels, nil
+}
+
+// Chaincodes returns the chaincodes committed on a channel
+func (p *PeerCatalog) Chaincodes(channelID string) ([]string, error) {
+	args, err := proto.Marshal(&lb.QueryChaincodeDefinitionsArgs{})
+	if err != nil {
+		return nil, err
+	}
+	payload, err := p.query(channelID, "_lifecycle", [][]byte{[]byte("QueryChaincodeDefinitions"), args})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &lb.QueryChaincodeDefinitionsResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal chaincode definitions")
+	}
+	var chaincodes []string
+	for _, definition := range result.ChaincodeDefinitions {
+		chaincodes = append(chaincodes, definition.Name)
+	}
+	sort.Strings(chaincodes)
+	return chaincodes, nil
+}
+
+func (p *PeerCatalog) query(channelID, chaincodeName string, args [][]byte) ([]byte, error) {
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: chaincodeName},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+
+	creator, err := p.Signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to serialize identity")
+	}
+	proposal, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, channelID, cis, creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create proposal")
+	}
+	signedProposal, err := protoutil.GetSignedProposal(proposal, p.Signer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create signed proposal")
+	}
+
+	proposalResponse, err := p.EndorserClient.ProcessProposal(context.Background(), signedProposal)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to endorse proposal")
+	}
+	if proposalResponse.Response == nil {
+		return nil, errors.New("received proposal response with nil response")
+	}
+	if proposalResponse.Response.Status != int32(cb.Status_SUCCESS) {
+		return nil, errors.Errorf("query failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+	}
+	return proposalResponse.Response.Payload, nil
+}
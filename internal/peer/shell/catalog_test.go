@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(msg []byte) ([]byte, error) { return []byte("signature"), nil }
+func (fakeSigner) Serialize() ([]byte, error)      { return []byte("creator"), nil }
+
+type fakeEndorserClient struct {
+	responses map[string]*pb.Response
+	err       error
+}
+
+func (f *fakeEndorserClient) ProcessProposal(ctx context.Context, sp *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	proposal, err := protoutil.UnmarshalProposal(sp.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	cis, err := protoutil.UnmarshalChaincodeInvocationSpec(mustPayload(proposal).Input)
+	if err != nil {
+		return nil, err
+	}
+	header, err := protoutil.UnmarshalHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	key := channelHeader.ChannelId + "/" + cis.ChaincodeSpec.ChaincodeId.Name + "/" + string(cis.ChaincodeSpec.Input.Args[0])
+	return &pb.ProposalResponse{Response: f.responses[key]}, nil
+}
+
+func mustPayload(proposal *pb.Proposal) *pb.ChaincodeProposalPayload {
+	payload, err := protoutil.UnmarshalChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		panic(err)
+	}
+	return payload
+}
+
+func TestPeerCatalog(t *testing.T) {
+	channels, err := proto.Marshal(&pb.ChannelQueryResponse{
+		Channels: []*pb.ChannelInfo{{ChannelId: "otherchannel"}, {ChannelId: "mychannel"}},
+	})
+	require.NoError(t, err)
+	definitions, err := proto.Marshal(&lb.QueryChaincodeDefinitionsResult{
+		ChaincodeDefinitions: []*lb.QueryChaincodeDefinitionsResult_ChaincodeDefinition{{Name: "marbles"}, {Name: "basic"}},
+	})
+	require.NoError(t, err)
+
+	endorserClient := &fakeEndorserClient{
+		responses: map[string]*pb.Response{
+			"/cscc/GetChannels": {Status: 200, Payload: channels},
+			"mychannel/_lifecycle/QueryChaincodeDefinitions":    {Status: 200, Payload: definitions},
+			"otherchannel/_lifecycle/QueryChaincodeDefinitions": {Status: 500, Message: "access denied"},
+		},
+	}
+	catalog := &PeerCatalog{EndorserClient: endorserClient, Signer: fakeSigner{}}
+
+	result, err := catalog.Channels()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mychannel", "otherchannel"}, result)
+
+	result, err = catalog.Chaincodes("mychannel")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"basic", "marbles"}, result)
+
+	_, err = catalog.Chaincodes("otherchannel")
+	assert.EqualError(t, err, "query failed with status: 500 - access denied")
+
+	_, err = catalog.Chaincodes("unknownchannel")
+	assert.EqualError(t, err, "received proposal response with nil response")
+
+	endorserClient.err = errors.New("connection refused")
+	_, err = catalog.Channels()
+	assert.EqualError(t, err, "failed to endorse proposal: connection refused")
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Catalog lists the channels and chaincodes offered as completions.
+type Catalog interface {
+	// Channels returns the channels the peer has joined
+	Channels() ([]string, error)
+	// Chaincodes returns the chaincodes committed on a channel
+	Chaincodes(channelID string) ([]string, error)
+}
+
+// Completer completes the words of a command line of the shell with the
+// names of the commands and flags of the peer CLI, and with the names of
+// the channels and chaincodes listed by its catalog.
+type Completer struct {
+	Root     *cobra.Command
+	Catalog  Catalog
+	Builtins []string
+}
+
+// Complete returns the candidates for the last word of the line, sorted.
+// When the line ends with a white space, the candidates for a new word are
+// returned. The channel is used to list the chaincodes when the line does
+// not select one.
+func (c *Completer) Complete(line, channelID string) []string {
+	words, err := SplitArgs(line)
+	if err != nil {
+		return nil
+	}
+	current := ""
+	if len(words) > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(line); !unicode.IsSpace(r) {
+			current = words[len(words)-1]
+			words = words[:len(words)-1]
+		}
+	}
+	if len(words) > 0 && words[0] == c.Root.Name() {
+		words = words[1:]
+	}
+
+	var candidates []string
+	switch {
+	case len(words) > 0 && words[0] == "use":
+		if len(words) == 1 {
+			candidates = c.channels()
+		}
+	case len(words) > 0 && isFlag(words[len(words)-1], "channelID", "C"):
+		candidates = c.channels()
+	case len(words) > 0 && isFlag(words[len(words)-1], "name", "n"):
+		if ch := flagValue(words, "channelID", "C"); ch != "" {
+			channelID = ch
+		}
+		candidates = c.chaincodes(channelID)
+	default:
+		cmd, _, err := c.Root.Find(words)
+		if err != nil {
+			return nil
+		}
+		if strings.HasPrefix(current, "-") {
+			candidates = flagNames(cmd)
+			break
+		}
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				candidates = append(candidates, sub.Name())
+			}
+		}
+		if cmd == c.Root {
+			candidates = append(candidates, c.Builtins...)
+		}
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, current) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func (c *Completer) channels() []string {
+	channels, err := c.Catalog.Channels()
+	if err != nil {
+		logger.Debugf("Could not list the channels: %s", err)
+		return nil
+	}
+	return channels
+}
+
+func (c *Completer) chaincodes(channelID string) []string {
+	if channelID == "" {
+		return nil
+	}
+	chaincodes, err := c.Catalog.Chaincodes(channelID)
+	if err != nil {
+		logger.Debugf("Could not list the chaincodes of channel %s: %s", channelID, err)
+		return nil
+	}
+	return chaincodes
+}
+
+func flagNames(cmd *cobra.Command) []string {
+	var names []string
+	add := func(f *pflag.Flag) {
+		if !f.Hidden {
+			names = append(names, "--"+f.Name)
+		}
+	}
+	cmd.LocalFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	return names
+}
+
+// isFlag returns whether the word is the given flag without its value
+func isFlag(word, name, shorthand string) bool {
+	return word == "--"+name || (shorthand != "" && word == "-"+shorthand)
+}
+
+// hasFlag returns whether the arguments set the given flag
+func hasFlag(args []string, name, shorthand string) bool {
+	for _, arg := range args {
+		if isFlag(arg, name, shorthand) || strings.HasPrefix(arg, "--"+name+"=") ||
+			(shorthand != "" && strings.HasPrefix(arg, "-"+shorthand)) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue returns the value of the given flag in the arguments
+func flagValue(args []string, name, shorthand string) string {
+	value := ""
+	for i, arg := range args {
+		switch {
+		case isFlag(arg, name, shorthand) && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, "--"+name+"="):
+			value = strings.TrimPrefix(arg, "--"+name+"=")
+		case shorthand != "" && strings.HasPrefix(arg, "-"+shorthand) && len(arg) > 2:
+			value = strings.TrimPrefix(strings.TrimPrefix(arg, "-"+shorthand), "=")
+		}
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplete(t *testing.T) {
+	root := newTestRoot(&[]invocation{})
+	c := &Completer{
+		Root: root,
+		Catalog: &fakeCatalog{
+			channels:   []string{"mychannel", "otherchannel"},
+			chaincodes: map[string][]string{"mychannel": {"basic", "marbles"}, "otherchannel": {"fabcar"}},
+		},
+		Builtins: builtins,
+	}
+
+	tests := []struct {
+		line      string
+		channelID string
+		expected  []string
+	}{
+		{line: "", expected: []string{"chaincode", "channel", "exit", "help", "history", "quit", "shell", "use", "version"}},
+		{line: "ch", expected: []string{"chaincode", "channel"}},
+		{line: "peer ch", expected: []string{"chaincode", "channel"}},
+		{line: "channel ", expected: []string{"getinfo", "list"}},
+		{line: "channel l", expected: []string{"list"}},
+		{line: "chaincode query --", expected: []string{"--channelID", "--name", "--peerAddresses"}},
+		{line: "channel list --ch", expected: []string{"--channelID"}},
+		{line: "channel list -C ", expected: []string{"mychannel", "otherchannel"}},
+		{line: "channel list --channelID o", expected: []string{"otherchannel"}},
+		{line: "use m", expected: []string{"mychannel"}},
+		{line: "use mychannel ", expected: nil},
+		{line: "chaincode query -n ", channelID: "mychannel", expected: []string{"basic", "marbles"}},
+		{line: "chaincode query -C otherchannel -n ", channelID: "mychannel", expected: []string{"fabcar"}},
+		{line: "chaincode query --channelID=otherchannel --name ", expected: []string{"fabcar"}},
+		{line: "chaincode query -n ", expected: nil},
+		{line: "unknown ", expected: nil},
+		{line: "chaincode query -c '{", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.expected, c.Complete(tt.line, tt.channelID))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// History holds the command lines entered in the shell. When it is backed
+// by a file, the lines are appended to the file as they are entered, so that
+// they are available to the following sessions.
+type History struct {
+	path    string
+	maxSize int
+	lines   []string
+}
+
+// NewHistory returns a history holding up to maxSize lines, loaded from the
+// file at path if it exists. An empty path disables the persistence of the
+// history.
+func NewHistory(path string, maxSize int) (*History, error) {
+	h := &History{path: path, maxSize: maxSize}
+	if path == "" {
+		return h, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open history file %s", path)
+	}
+	defer f.Close()
+
+	read := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.add(scanner.Text())
+		read++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "could not read history file %s", path)
+	}
+
+	// keep the file from growing beyond the size of the history
+	if read > maxSize {
+		content := strings.Join(h.lines, "\n") + "\n"
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, errors.Wrapf(err, "could not write history file %s", path)
+		}
+	}
+	return h, nil
+}
+
+// Add records a command line in the history
+func (h *History) Add(line string) error {
+	if !h.add(line) || h.path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "could not open history file %s", h.path)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return errors.Wrapf(err, "could not write history file %s", h.path)
+	}
+	return nil
+}
+
+func (h *History) add(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return false
+	}
+	h.lines = append(h.lines, line)
+	if len(h.lines) > h.maxSize {
+		h.lines = h.lines[len(h.lines)-h.maxSize:]
+	}
+	return true
+}
+
+// Lines returns the command lines of the history, oldest first
+func (h *History) Lines() []string {
+	return h.lines
+}
+
+// Get returns the n-th command line of the history, starting from 1
+func (h *History) Get(n int) (string, bool) {
+	if n < 1 || n > len(h.lines) {
+		return "", false
+	}
+	return h.lines[n-1], true
+}
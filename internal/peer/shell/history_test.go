@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history")
+
+	h, err := NewHistory(path, 3)
+	require.NoError(t, err)
+	assert.Empty(t, h.Lines())
+
+	require.NoError(t, h.Add("channel list"))
+	require.NoError(t, h.Add("channel list"))
+	require.NoError(t, h.Add("  "))
+	require.NoError(t, h.Add("version"))
+	assert.Equal(t, []string{"channel list", "version"}, h.Lines())
+
+	line, ok := h.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "channel list", line)
+	_, ok = h.Get(3)
+	assert.False(t, ok)
+	_, ok = h.Get(0)
+	assert.False(t, ok)
+
+	require.NoError(t, h.Add("lifecycle chaincode queryinstalled"))
+	require.NoError(t, h.Add("channel getinfo -C mychannel"))
+	assert.Equal(t, []string{"version", "lifecycle chaincode queryinstalled", "channel getinfo -C mychannel"}, h.Lines())
+
+	// the history is loaded by the next session, and its file is trimmed
+	h, err = NewHistory(path, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"version", "lifecycle chaincode queryinstalled", "channel getinfo -C mychannel"}, h.Lines())
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "version\nlifecycle chaincode queryinstalled\nchannel getinfo -C mychannel\n", string(content))
+}
+
+func TestHistoryWithoutFile(t *testing.T) {
+	h, err := NewHistory("", 10)
+	require.NoError(t, err)
+	require.NoError(t, h.Add("version"))
+	assert.Equal(t, []string{"version"}, h.Lines())
+}
+
+func TestHistoryBadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewHistory(dir, 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "history file "+dir)
+
+	h, err := NewHistory("", 10)
+	require.NoError(t, err)
+	h.path = dir
+	assert.Contains(t, h.Add("version").Error(), "could not open history file "+dir)
+}
@@ -0,0 +1,257 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var logger = flogging.MustGetLogger("cli.shell")
+
+const (
+	shellFuncName = "shell"
+
+	// maxHistorySize is the number of command lines kept in the history
+	maxHistorySize = 1000
+)
+
+var builtins = []string{"exit", "help", "history", "quit", "use"}
+
+const builtinsHelp = `Shell commands:
+  use [channel]   Select the channel passed to the commands run without -C, or clear it
+  history         List the command lines entered, !N runs the N-th one again
+  help            Show this help and the commands of the peer CLI
+  exit, quit      Leave the shell
+
+Any other line runs a command of the peer CLI, with or without the leading
+'peer'. End a line with a tab to list the completions of its last word.
+`
+
+// Shell runs the commands of the peer CLI read from its input, in the same
+// process, so that the configuration and the local MSP are loaded once.
+type Shell struct {
+	Root      *cobra.Command
+	In        io.Reader
+	Out       io.Writer
+	History   *History
+	Completer *Completer
+	ChannelID string
+}
+
+var (
+	channelID   string
+	historyFile string
+)
+
+// Cmd returns the cobra command for the peer shell, which runs the
+// subcommands of root.
+func Cmd(root *cobra.Command) *cobra.Command {
+	shellCmd := &cobra.Command{
+		Use:   shellFuncName,
+		Short: "Run peer commands interactively.",
+		Long:  "Run peer commands interactively, with the configuration and the local MSP loaded once, a command history, a selected channel and completion of commands, flags, channels and chaincodes.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Parsing of the command line is done so silence cmd usage
+			cmd.SilenceUsage = true
+
+			common.InitCmd(cmd, args)
+			common.KeepContext()
+
+			path := historyFile
+			if path == "" {
+				if home, err := os.UserHomeDir(); err == nil {
+					path = filepath.Join(home, ".peer_history")
+				}
+			}
+			history, err := NewHistory(path, maxHistorySize)
+			if err != nil {
+				return err
+			}
+
+			var catalog Catalog = &noCatalog{}
+			if peerCatalog, err := NewPeerCatalog(); err != nil {
+				logger.Warningf("Channels and chaincodes will not be completed: %s", err)
+			} else {
+				catalog = peerCatalog
+			}
+
+			s := &Shell{
+				Root:      root,
+				In:        os.Stdin,
+				Out:       os.Stdout,
+				History:   history,
+				Completer: &Completer{Root: root, Catalog: catalog, Builtins: builtins},
+				ChannelID: channelID,
+			}
+			return s.Run()
+		},
+	}
+
+	flags := shellCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "C", "", "The channel selected when the shell starts")
+	flags.StringVar(&historyFile, "history-file", "", "The file keeping the command history across sessions (default $HOME/.peer_history)")
+
+	return shellCmd
+}
+
+// Run reads and runs command lines until the end of the input or an
+// exit command.
+func (s *Shell) Run() error {
+	scanner := bufio.NewScanner(s.In)
+	s.prompt()
+	for scanner.Scan() {
+		if exit := s.Exec(scanner.Text()); exit {
+			return nil
+		}
+		s.prompt()
+	}
+	fmt.Fprintln(s.Out)
+	return scanner.Err()
+}
+
+func (s *Shell) prompt() {
+	if s.ChannelID != "" {
+		fmt.Fprintf(s.Out, "%s(%s)> ", s.Root.Name(), s.ChannelID)
+		return
+	}
+	fmt.Fprintf(s.Out, "%s> ", s.Root.Name())
+}
+
+// Exec runs a command line and returns whether the shell must exit
+func (s *Shell) Exec(line string) bool {
+	if strings.HasSuffix(line, "\t") {
+		completions := s.Completer.Complete(strings.TrimRight(line, "\t"), s.ChannelID)
+		fmt.Fprintln(s.Out, strings.Join(completions, "  "))
+		return false
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+	if strings.HasPrefix(line, "!") {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			fmt.Fprintf(s.Out, "Error: invalid history reference %s\n", line)
+			return false
+		}
+		previous, ok := s.History.Get(n)
+		if !ok {
+			fmt.Fprintf(s.Out, "Error: no command line %d in the history\n", n)
+			return false
+		}
+		line = previous
+		fmt.Fprintln(s.Out, line)
+	}
+
+	args, err := SplitArgs(line)
+	if err != nil {
+		fmt.Fprintf(s.Out, "Error: %s\n", err)
+		return false
+	}
+	if err := s.History.Add(line); err != nil {
+		logger.Warningf("Could not record the command line: %s", err)
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		fmt.Fprint(s.Out, builtinsHelp)
+		fmt.Fprintln(s.Out)
+		s.run([]string{"--help"})
+	case "history":
+		for i, l := range s.History.Lines() {
+			fmt.Fprintf(s.Out, "%5d  %s\n", i+1, l)
+		}
+	case "use":
+		switch len(args) {
+		case 1:
+			s.ChannelID = ""
+		case 2:
+			s.ChannelID = args[1]
+		default:
+			fmt.Fprintln(s.Out, "Error: use takes at most one channel")
+		}
+	default:
+		if args[0] == s.Root.Name() {
+			args = args[1:]
+		}
+		s.run(args)
+	}
+	return false
+}
+
+// run executes a command of the peer CLI. The errors are reported by cobra.
+func (s *Shell) run(args []string) {
+	cmd, _, err := s.Root.Find(args)
+	if err == nil && cmd.Name() == shellFuncName {
+		fmt.Fprintln(s.Out, "Error: already running the peer shell")
+		return
+	}
+	if err == nil && s.ChannelID != "" && hasChannelFlag(cmd) && !hasFlag(args, "channelID", "C") {
+		args = append(args, "--channelID="+s.ChannelID)
+	}
+
+	resetFlags(s.Root)
+	s.Root.SetArgs(args)
+	if err := s.Root.Execute(); err != nil {
+		logger.Debugf("Command %v failed: %s", args, err)
+	}
+}
+
+func hasChannelFlag(cmd *cobra.Command) bool {
+	return cmd.Flags().Lookup("channelID") != nil || cmd.InheritedFlags().Lookup("channelID") != nil
+}
+
+// resetFlags sets back the flags of the command tree to their default
+// values, so that a command does not inherit the flags of a previous one.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(defaultSlice(f.DefValue))
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func defaultSlice(defValue string) []string {
+	values := strings.TrimSuffix(strings.TrimPrefix(defValue, "["), "]")
+	if values == "" {
+		return []string{}
+	}
+	return strings.Split(values, ",")
+}
+
+// noCatalog is used when the peer cannot be reached, it completes no
+// channels and chaincodes
+type noCatalog struct{}
+
+func (*noCatalog) Channels() ([]string, error)         { return nil, nil }
+func (*noCatalog) Chaincodes(string) ([]string, error) { return nil, nil }
@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCatalog struct {
+	channels   []string
+	chaincodes map[string][]string
+}
+
+func (f *fakeCatalog) Channels() ([]string, error) {
+	return f.channels, nil
+}
+
+func (f *fakeCatalog) Chaincodes(channelID string) ([]string, error) {
+	return f.chaincodes[channelID], nil
+}
+
+type invocation struct {
+	command   string
+	channelID string
+	name      string
+	peers     []string
+}
+
+func newTestRoot(invocations *[]invocation) *cobra.Command {
+	root := &cobra.Command{Use: "peer"}
+	root.SetOutput(&bytes.Buffer{})
+
+	var channelID, name string
+	var peers []string
+	record := func(cmd *cobra.Command, args []string) {
+		*invocations = append(*invocations, invocation{
+			command:   cmd.CommandPath(),
+			channelID: channelID,
+			name:      name,
+			peers:     peers,
+		})
+	}
+
+	channelCmd := &cobra.Command{Use: "channel"}
+	channelCmd.PersistentFlags().StringVarP(&channelID, "channelID", "C", "", "")
+	channelCmd.AddCommand(&cobra.Command{Use: "list", Run: record})
+	channelCmd.AddCommand(&cobra.Command{Use: "getinfo", Run: record})
+
+	chaincodeCmd := &cobra.Command{Use: "chaincode"}
+	queryCmd := &cobra.Command{Use: "query", Run: record}
+	queryCmd.Flags().StringVarP(&channelID, "channelID", "C", "", "")
+	queryCmd.Flags().StringVarP(&name, "name", "n", "", "")
+	queryCmd.Flags().StringArrayVar(&peers, "peerAddresses", []string{}, "")
+	queryCmd.Flags().String("hidden", "", "")
+	queryCmd.Flags().MarkHidden("hidden")
+	chaincodeCmd.AddCommand(queryCmd)
+
+	versionCmd := &cobra.Command{Use: "version", Run: record}
+
+	root.AddCommand(channelCmd, chaincodeCmd, versionCmd, Cmd(root))
+	return root
+}
+
+func newTestShell(t *testing.T, input string) (*Shell, *[]invocation, *bytes.Buffer) {
+	invocations := &[]invocation{}
+	root := newTestRoot(invocations)
+	history, err := NewHistory("", 10)
+	require.NoError(t, err)
+	out := &bytes.Buffer{}
+	s := &Shell{
+		Root:    root,
+		In:      strings.NewReader(input),
+		Out:     out,
+		History: history,
+		Completer: &Completer{
+			Root: root,
+			Catalog: &fakeCatalog{
+				channels:   []string{"mychannel", "otherchannel"},
+				chaincodes: map[string][]string{"mychannel": {"basic", "marbles"}, "otherchannel": {"fabcar"}},
+			},
+			Builtins: builtins,
+		},
+	}
+	return s, invocations, out
+}
+
+func TestShellRunsCommands(t *testing.T) {
+	s, invocations, out := newTestShell(t, strings.Join([]string{
+		"peer chaincode query -C mychannel -n basic --peerAddresses a --peerAddresses b",
+		"chaincode query",
+		"use mychannel",
+		"channel getinfo",
+		"chaincode query -C otherchannel -n fabcar",
+		"version",
+		"use",
+		"channel list",
+		"exit",
+		"version",
+	}, "\n"))
+
+	require.NoError(t, s.Run())
+	assert.Equal(t, []invocation{
+		{command: "peer chaincode query", channelID: "mychannel", name: "basic", peers: []string{"a", "b"}},
+		// the flags of the previous command are not kept
+		{command: "peer chaincode query", peers: []string{}},
+		// the selected channel is passed to the commands having a channel flag
+		{command: "peer channel getinfo", channelID: "mychannel", peers: []string{}},
+		{command: "peer chaincode query", channelID: "otherchannel", name: "fabcar", peers: []string{}},
+		{command: "peer version", peers: []string{}},
+		{command: "peer channel list", peers: []string{}},
+	}, *invocations)
+	assert.Contains(t, out.String(), "peer(mychannel)> ")
+	assert.True(t, strings.HasSuffix(out.String(), "peer> "))
+}
+
+func TestShellHistory(t *testing.T) {
+	s, invocations, out := newTestShell(t, "version\nchannel list -C mychannel\nhistory\n!1\n!7\n!x\n")
+
+	require.NoError(t, s.Run())
+	assert.Len(t, *invocations, 3)
+	assert.Equal(t, "peer version", (*invocations)[2].command)
+	assert.Contains(t, out.String(), "    1  version\n    2  channel list -C mychannel\n")
+	assert.Contains(t, out.String(), "Error: no command line 7 in the history")
+	assert.Contains(t, out.String(), "Error: invalid history reference !x")
+	assert.Equal(t, []string{"version", "channel list -C mychannel", "history", "version"}, s.History.Lines())
+}
+
+func TestShellBuiltins(t *testing.T) {
+	s, invocations, out := newTestShell(t, "help\nuse a b\nshell\nchaincode query -c '{\n")
+
+	require.NoError(t, s.Run())
+	assert.Empty(t, *invocations)
+	assert.Contains(t, out.String(), "Shell commands:")
+	assert.Contains(t, out.String(), "Error: use takes at most one channel")
+	assert.Contains(t, out.String(), "Error: already running the peer shell")
+	assert.Contains(t, out.String(), "Error: unterminated ' quote")
+}
+
+func TestShellCompletion(t *testing.T) {
+	s, _, out := newTestShell(t, "ch\t\nchaincode query --\t\n")
+
+	require.NoError(t, s.Run())
+	assert.Contains(t, out.String(), "peer> chaincode  channel\n")
+	assert.Contains(t, out.String(), "peer> --channelID  --name  --peerAddresses\n")
+}
@@ -152,19 +152,29 @@ func ExtractRawCertificateFromContext(ctx context.Context) []byte {
 	return cert.Raw
 }
 
-// GetLocalIP returns the non loopback local IP of the host
+// GetLocalIP returns the non loopback local IP of the host, preferring IPv4
+// addresses over IPv6 ones
 func GetLocalIP() (string, error) {
 	addrs, err := net.InterfaceAddrs()
 	if err != nil {
 		return "", err
 	}
+	var ipv6 string
 	for _, address := range addrs {
 		// check the address type and if it is not a loopback then display it
 		if ipnet, ok := address.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
 			if ipnet.IP.To4() != nil {
 				return ipnet.IP.String(), nil
 			}
+			// link-local addresses are not reachable without a zone
+			if ipv6 == "" && ipnet.IP.IsGlobalUnicast() {
+				ipv6 = ipnet.IP.String()
+			}
 		}
 	}
-	return "", errors.Errorf("no non-loopback, IPv4 interface detected")
+	// hosts on IPv6-only networks
+	if ipv6 != "" {
+		return ipv6, nil
+	}
+	return "", errors.Errorf("no non-loopback, IPv4 or global unicast IPv6 interface detected")
 }
@@ -0,0 +1,256 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configview renders channel configurations as JSON for operators.
+// The configuration is decoded as by protolator and annotated with
+// human-readable renderings of its policies, of the certificates of its
+// MSPs and of the capabilities of its groups.
+package configview
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// Certificate is the human-readable rendering of an X.509 certificate.
+type Certificate struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	SerialNumber       string    `json:"serial_number"`
+	NotBefore          time.Time `json:"not_before"`
+	NotAfter           time.Time `json:"not_after"`
+	PublicKey          string    `json:"public_key"`
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	SubjectKeyID       string    `json:"subject_key_id,omitempty"`
+	IsCA               bool      `json:"is_ca"`
+}
+
+// MSP is the human-readable rendering of the definition of an MSP.
+type MSP struct {
+	Name                 string         `json:"name"`
+	RootCerts            []*Certificate `json:"root_certs"`
+	IntermediateCerts    []*Certificate `json:"intermediate_certs,omitempty"`
+	Admins               []*Certificate `json:"admins,omitempty"`
+	TLSRootCerts         []*Certificate `json:"tls_root_certs,omitempty"`
+	TLSIntermediateCerts []*Certificate `json:"tls_intermediate_certs,omitempty"`
+	RevokedCerts         int            `json:"revoked_certs,omitempty"`
+	NodeOUs              bool           `json:"node_ous"`
+}
+
+// Marshal renders a channel configuration as indented JSON.
+func Marshal(config *cb.Config) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := protolator.DeepMarshalJSON(buf, config); err != nil {
+		return nil, errors.Wrap(err, "failed decoding the channel config")
+	}
+
+	decoder := json.NewDecoder(buf)
+	decoder.UseNumber()
+	tree := map[string]interface{}{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, errors.Wrap(err, "failed reading the decoded channel config")
+	}
+
+	if group, ok := tree["channel_group"].(map[string]interface{}); ok && config.ChannelGroup != nil {
+		if err := annotateGroup(config.ChannelGroup, group); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.MarshalIndent(tree, "", "\t")
+}
+
+// ConfigFromBlock extracts the channel configuration carried by a config block.
+func ConfigFromBlock(block *cb.Block) (*cb.Config, error) {
+	if !protoutil.IsConfigBlock(block) {
+		return nil, errors.New("block is not a config block")
+	}
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return configEnvelope.Config, nil
+}
+
+// annotateGroup adds to the decoded group the renderings of its capabilities,
+// policies and MSP, then recurses into its subgroups.
+func annotateGroup(group *cb.ConfigGroup, tree map[string]interface{}) error {
+	values, _ := tree["values"].(map[string]interface{})
+	if value, ok := group.Values[channelconfig.CapabilitiesKey]; ok {
+		capabilities := &cb.Capabilities{}
+		if err := proto.Unmarshal(value.Value, capabilities); err != nil {
+			return errors.Wrap(err, "failed unmarshalling capabilities")
+		}
+		names := []string{}
+		for name := range capabilities.Capabilities {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		tree["capabilities"] = names
+	}
+	if value, ok := group.Values[channelconfig.MSPKey]; ok {
+		if entry, ok := values[channelconfig.MSPKey].(map[string]interface{}); ok {
+			msp, err := decodeMSP(value.Value)
+			if err != nil {
+				return err
+			}
+			if msp != nil {
+				entry["msp"] = msp
+			}
+		}
+	}
+
+	policies, _ := tree["policies"].(map[string]interface{})
+	for name, policy := range group.Policies {
+		entry, ok := policies[name].(map[string]interface{})
+		if !ok || policy.Policy == nil {
+			continue
+		}
+		rule, err := policyRule(policy.Policy)
+		if err != nil {
+			return errors.WithMessagef(err, "failed rendering policy %s", name)
+		}
+		entry["rule"] = rule
+	}
+
+	groups, _ := tree["groups"].(map[string]interface{})
+	for name, subgroup := range group.Groups {
+		if entry, ok := groups[name].(map[string]interface{}); ok {
+			if err := annotateGroup(subgroup, entry); err != nil {
+				return errors.WithMessagef(err, "group %s", name)
+			}
+		}
+	}
+	return nil
+}
+
+func policyRule(policy *cb.Policy) (string, error) {
+	switch cb.Policy_PolicyType(policy.Type) {
+	case cb.Policy_SIGNATURE:
+		envelope := &cb.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil {
+			return "", errors.Wrap(err, "failed unmarshalling signature policy")
+		}
+		return policydsl.String(envelope)
+	case cb.Policy_IMPLICIT_META:
+		implicitMeta := &cb.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, implicitMeta); err != nil {
+			return "", errors.Wrap(err, "failed unmarshalling implicit meta policy")
+		}
+		return fmt.Sprintf("%s %s", implicitMeta.Rule, implicitMeta.SubPolicy), nil
+	default:
+		return fmt.Sprintf("%s policy", cb.Policy_PolicyType(policy.Type)), nil
+	}
+}
+
+func decodeMSP(value []byte) (*MSP, error) {
+	mspConfig := &mspproto.MSPConfig{}
+	if err := proto.Unmarshal(value, mspConfig); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling MSP config")
+	}
+	if mspConfig.Type != 0 {
+		// only the certificates of X.509 based MSPs are rendered
+		return nil, nil
+	}
+	fabricConfig := &mspproto.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling fabric MSP config")
+	}
+
+	msp := &MSP{
+		Name:         fabricConfig.Name,
+		RevokedCerts: len(fabricConfig.RevocationList),
+		NodeOUs:      fabricConfig.FabricNodeOus != nil && fabricConfig.FabricNodeOus.Enable,
+	}
+	for _, certs := range []struct {
+		pems   [][]byte
+		target *[]*Certificate
+	}{
+		{fabricConfig.RootCerts, &msp.RootCerts},
+		{fabricConfig.IntermediateCerts, &msp.IntermediateCerts},
+		{fabricConfig.Admins, &msp.Admins},
+		{fabricConfig.TlsRootCerts, &msp.TLSRootCerts},
+		{fabricConfig.TlsIntermediateCerts, &msp.TLSIntermediateCerts},
+	} {
+		for _, pemBytes := range certs.pems {
+			cert, err := decodeCertificate(pemBytes)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "MSP %s", fabricConfig.Name)
+			}
+			*certs.target = append(*certs.target, cert)
+		}
+	}
+	return msp, nil
+}
+
+func decodeCertificate(pemBytes []byte) (*Certificate, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing certificate")
+	}
+	return &Certificate{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SerialNumber:       cert.SerialNumber.String(),
+		NotBefore:          cert.NotBefore.UTC(),
+		NotAfter:           cert.NotAfter.UTC(),
+		PublicKey:          publicKeyString(cert),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SubjectKeyID:       hex.EncodeToString(cert.SubjectKeyId),
+		IsCA:               cert.IsCA,
+	}, nil
+}
+
+func publicKeyString(cert *x509.Certificate) string {
+	switch cert.PublicKeyAlgorithm {
+	case x509.SM2:
+		return "SM2"
+	case x509.ECDSA:
+		if pub, ok := cert.PublicKey.(*ecdsa.PublicKey); ok {
+			return fmt.Sprintf("ECDSA %s", pub.Curve.Params().Name)
+		}
+		return "ECDSA"
+	case x509.RSA:
+		if pub, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return fmt.Sprintf("RSA %d", pub.N.BitLen())
+		}
+		return "RSA"
+	case x509.DSA:
+		return "DSA"
+	default:
+		return "unknown"
+	}
+}
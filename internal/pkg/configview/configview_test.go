@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configview
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sm2RootCert(t *testing.T) []byte {
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(42),
+		Subject:               pkix.Name{CommonName: "ca.org1.example.com", Organization: []string{"org1.example.com"}},
+		NotBefore:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte{1, 2, 3},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func capabilitiesValue(names ...string) *cb.ConfigValue {
+	capabilities := &cb.Capabilities{Capabilities: map[string]*cb.Capability{}}
+	for _, name := range names {
+		capabilities.Capabilities[name] = &cb.Capability{}
+	}
+	return &cb.ConfigValue{Value: protoutil.MarshalOrPanic(capabilities)}
+}
+
+func testConfig(t *testing.T) *cb.Config {
+	readers, err := policydsl.FromString("OR('Org1MSP.member', 'Org1MSP.admin')")
+	require.NoError(t, err)
+
+	org1 := protoutil.NewConfigGroup()
+	org1.Values["MSP"] = &cb.ConfigValue{
+		ModPolicy: "Admins",
+		Value: protoutil.MarshalOrPanic(&mspproto.MSPConfig{
+			Config: protoutil.MarshalOrPanic(&mspproto.FabricMSPConfig{
+				Name:          "Org1MSP",
+				RootCerts:     [][]byte{sm2RootCert(t)},
+				FabricNodeOus: &mspproto.FabricNodeOUs{Enable: true},
+			}),
+		}),
+	}
+	org1.Policies["Readers"] = &cb.ConfigPolicy{
+		ModPolicy: "Admins",
+		Policy: &cb.Policy{
+			Type:  int32(cb.Policy_SIGNATURE),
+			Value: protoutil.MarshalOrPanic(readers),
+		},
+	}
+
+	application := protoutil.NewConfigGroup()
+	application.Values["Capabilities"] = capabilitiesValue("V2_0")
+	application.Groups["Org1"] = org1
+
+	channel := protoutil.NewConfigGroup()
+	channel.Values["Capabilities"] = capabilitiesValue("V2_0", "V1_4_3")
+	channel.Policies["Admins"] = &cb.ConfigPolicy{
+		ModPolicy: "Admins",
+		Policy: &cb.Policy{
+			Type:  int32(cb.Policy_IMPLICIT_META),
+			Value: protoutil.MarshalOrPanic(&cb.ImplicitMetaPolicy{Rule: cb.ImplicitMetaPolicy_MAJORITY, SubPolicy: "Admins"}),
+		},
+	}
+	channel.Groups["Application"] = application
+
+	return &cb.Config{Sequence: 3, ChannelGroup: channel}
+}
+
+func TestMarshal(t *testing.T) {
+	jsonBytes, err := Marshal(testConfig(t))
+	require.NoError(t, err)
+
+	var view struct {
+		Sequence     string `json:"sequence"`
+		ChannelGroup struct {
+			Capabilities []string `json:"capabilities"`
+			Policies     map[string]struct {
+				Rule string `json:"rule"`
+			} `json:"policies"`
+			Groups map[string]struct {
+				Capabilities []string `json:"capabilities"`
+				Groups       map[string]struct {
+					Policies map[string]struct {
+						Rule string `json:"rule"`
+					} `json:"policies"`
+					Values map[string]struct {
+						MSP   *MSP `json:"msp"`
+						Value struct {
+							Config struct {
+								Name string `json:"name"`
+							} `json:"config"`
+						} `json:"value"`
+					} `json:"values"`
+				} `json:"groups"`
+			} `json:"groups"`
+		} `json:"channel_group"`
+	}
+	require.NoError(t, json.Unmarshal(jsonBytes, &view))
+
+	assert.Equal(t, "3", view.Sequence)
+	assert.Equal(t, []string{"V1_4_3", "V2_0"}, view.ChannelGroup.Capabilities)
+	assert.Equal(t, "MAJORITY Admins", view.ChannelGroup.Policies["Admins"].Rule)
+
+	application := view.ChannelGroup.Groups["Application"]
+	assert.Equal(t, []string{"V2_0"}, application.Capabilities)
+	org1 := application.Groups["Org1"]
+	assert.Equal(t, "OR('Org1MSP.member', 'Org1MSP.admin')", org1.Policies["Readers"].Rule)
+
+	// the protolator decoding is preserved
+	assert.Equal(t, "Org1MSP", org1.Values["MSP"].Value.Config.Name)
+
+	msp := org1.Values["MSP"].MSP
+	require.NotNil(t, msp)
+	assert.Equal(t, "Org1MSP", msp.Name)
+	assert.True(t, msp.NodeOUs)
+	require.Len(t, msp.RootCerts, 1)
+	assert.Equal(t, &Certificate{
+		Subject:            "CN=ca.org1.example.com,O=org1.example.com",
+		Issuer:             "CN=ca.org1.example.com,O=org1.example.com",
+		SerialNumber:       "42",
+		NotBefore:          time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:           time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		PublicKey:          "SM2",
+		SignatureAlgorithm: "SM2-tjSM3",
+		SubjectKeyID:       "010203",
+		IsCA:               true,
+	}, msp.RootCerts[0])
+}
+
+func TestMarshalBadMSP(t *testing.T) {
+	config := testConfig(t)
+	org1 := config.ChannelGroup.Groups["Application"].Groups["Org1"]
+	org1.Values["MSP"].Value = protoutil.MarshalOrPanic(&mspproto.MSPConfig{
+		Config: protoutil.MarshalOrPanic(&mspproto.FabricMSPConfig{
+			Name:      "Org1MSP",
+			RootCerts: [][]byte{[]byte("not a certificate")},
+		}),
+	})
+
+	_, err := Marshal(config)
+	assert.EqualError(t, err, "group Application: group Org1: MSP Org1MSP: certificate is not PEM encoded")
+}
@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configview
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/pkg/errors"
+)
+
+// Membership is the membership of a channel as defined by its config at a
+// given block.
+type Membership struct {
+	ChannelID         string          `json:"channel_id,omitempty"`
+	BlockNumber       uint64          `json:"block_number"`
+	ConfigBlockNumber uint64          `json:"config_block_number"`
+	ConfigSequence    uint64          `json:"config_sequence"`
+	OrdererAddresses  []string        `json:"orderer_addresses,omitempty"`
+	Organizations     []*Organization `json:"organizations"`
+}
+
+// Organization is an organization of a channel, with its MSP and the
+// endpoints of its nodes.
+type Organization struct {
+	Name             string         `json:"name"`
+	Group            string         `json:"group"`
+	MSPID            string         `json:"msp_id"`
+	RootCerts        []*Certificate `json:"root_certs"`
+	TLSRootCerts     []*Certificate `json:"tls_root_certs,omitempty"`
+	AnchorPeers      []string       `json:"anchor_peers,omitempty"`
+	OrdererEndpoints []string       `json:"orderer_endpoints,omitempty"`
+}
+
+// MembershipFromConfig extracts the organizations of the application and
+// orderer groups of a channel config, along with their root certificates and
+// the endpoints of their anchor peers and orderers. The block numbers of the
+// returned membership are left to the caller.
+func MembershipFromConfig(config *cb.Config) (*Membership, error) {
+	if config.ChannelGroup == nil {
+		return nil, errors.New("config has no channel group")
+	}
+
+	membership := &Membership{
+		ConfigSequence: config.Sequence,
+		Organizations:  []*Organization{},
+	}
+	if value, ok := config.ChannelGroup.Values[channelconfig.OrdererAddressesKey]; ok {
+		addresses := &cb.OrdererAddresses{}
+		if err := proto.Unmarshal(value.Value, addresses); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling orderer addresses")
+		}
+		membership.OrdererAddresses = addresses.Addresses
+	}
+
+	for _, groupName := range []string{channelconfig.ApplicationGroupKey, channelconfig.OrdererGroupKey} {
+		group, ok := config.ChannelGroup.Groups[groupName]
+		if !ok {
+			continue
+		}
+		orgNames := make([]string, 0, len(group.Groups))
+		for name := range group.Groups {
+			orgNames = append(orgNames, name)
+		}
+		sort.Strings(orgNames)
+		for _, name := range orgNames {
+			org, err := organization(groupName, name, group.Groups[name])
+			if err != nil {
+				return nil, errors.WithMessagef(err, "organization %s of group %s", name, groupName)
+			}
+			membership.Organizations = append(membership.Organizations, org)
+		}
+	}
+	return membership, nil
+}
+
+func organization(groupName, name string, group *cb.ConfigGroup) (*Organization, error) {
+	org := &Organization{
+		Name:  name,
+		Group: groupName,
+	}
+	if value, ok := group.Values[channelconfig.MSPKey]; ok {
+		msp, err := decodeMSP(value.Value)
+		if err != nil {
+			return nil, err
+		}
+		if msp != nil {
+			org.MSPID = msp.Name
+			org.RootCerts = msp.RootCerts
+			org.TLSRootCerts = msp.TLSRootCerts
+		}
+	}
+	if value, ok := group.Values[channelconfig.AnchorPeersKey]; ok {
+		anchorPeers := &pb.AnchorPeers{}
+		if err := proto.Unmarshal(value.Value, anchorPeers); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling anchor peers")
+		}
+		for _, anchorPeer := range anchorPeers.AnchorPeers {
+			org.AnchorPeers = append(org.AnchorPeers, net.JoinHostPort(anchorPeer.Host, fmt.Sprintf("%d", anchorPeer.Port)))
+		}
+	}
+	if value, ok := group.Values[channelconfig.EndpointsKey]; ok {
+		endpoints := &cb.OrdererAddresses{}
+		if err := proto.Unmarshal(value.Value, endpoints); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling orderer endpoints")
+		}
+		org.OrdererEndpoints = endpoints.Addresses
+	}
+	return org, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configview
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMembershipFromConfig(t *testing.T) {
+	config := testConfig(t)
+	config.ChannelGroup.Values["OrdererAddresses"] = &cb.ConfigValue{
+		Value: protoutil.MarshalOrPanic(&cb.OrdererAddresses{Addresses: []string{"orderer0:7050"}}),
+	}
+	config.ChannelGroup.Groups["Application"].Groups["Org1"].Values["AnchorPeers"] = &cb.ConfigValue{
+		Value: protoutil.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer0.org1", Port: 7051}}}),
+	}
+	ordererOrg := protoutil.NewConfigGroup()
+	ordererOrg.Values["MSP"] = &cb.ConfigValue{
+		Value: protoutil.MarshalOrPanic(&mspproto.MSPConfig{
+			Config: protoutil.MarshalOrPanic(&mspproto.FabricMSPConfig{
+				Name:      "OrdererMSP",
+				RootCerts: [][]byte{sm2RootCert(t)},
+			}),
+		}),
+	}
+	ordererOrg.Values["Endpoints"] = &cb.ConfigValue{
+		Value: protoutil.MarshalOrPanic(&cb.OrdererAddresses{Addresses: []string{"orderer1:7050", "orderer2:7050"}}),
+	}
+	orderer := protoutil.NewConfigGroup()
+	orderer.Groups["OrdererOrg"] = ordererOrg
+	config.ChannelGroup.Groups["Orderer"] = orderer
+
+	membership, err := MembershipFromConfig(config)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), membership.ConfigSequence)
+	assert.Equal(t, []string{"orderer0:7050"}, membership.OrdererAddresses)
+	require.Len(t, membership.Organizations, 2)
+
+	org1 := membership.Organizations[0]
+	assert.Equal(t, "Org1", org1.Name)
+	assert.Equal(t, "Application", org1.Group)
+	assert.Equal(t, "Org1MSP", org1.MSPID)
+	require.Len(t, org1.RootCerts, 1)
+	assert.Equal(t, "SM2", org1.RootCerts[0].PublicKey)
+	assert.Equal(t, []string{"peer0.org1:7051"}, org1.AnchorPeers)
+	assert.Empty(t, org1.OrdererEndpoints)
+
+	ordererOrg1 := membership.Organizations[1]
+	assert.Equal(t, "OrdererOrg", ordererOrg1.Name)
+	assert.Equal(t, "Orderer", ordererOrg1.Group)
+	assert.Equal(t, "OrdererMSP", ordererOrg1.MSPID)
+	assert.Equal(t, []string{"orderer1:7050", "orderer2:7050"}, ordererOrg1.OrdererEndpoints)
+}
+
+func TestMembershipFromConfigErrors(t *testing.T) {
+	_, err := MembershipFromConfig(&cb.Config{})
+	assert.EqualError(t, err, "config has no channel group")
+
+	config := testConfig(t)
+	config.ChannelGroup.Groups["Application"].Groups["Org1"].Values["AnchorPeers"] = &cb.ConfigValue{Value: []byte("garbage")}
+	_, err = MembershipFromConfig(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "organization Org1 of group Application: failed unmarshalling anchor peers")
+}
@@ -0,0 +1,209 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package enrollment
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/tls"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/internal/cryptogen/csp"
+	"github.com/pkg/errors"
+)
+
+// Client enrolls identities against the REST API of a Fabric CA. The
+// connection to the CA uses the GM TLS stack, so CAs serving SM2 TLS
+// certificates are supported.
+type Client struct {
+	URL        string
+	CAName     string
+	HTTPClient *http.Client
+}
+
+// Response is the outcome of a successful enrollment.
+type Response struct {
+	// Cert is the PEM encoded enrollment certificate.
+	Cert []byte
+	// CAChain is the PEM encoded certificate chain of the CA.
+	CAChain []byte
+}
+
+type enrollRequest struct {
+	CertificateRequest string `json:"certificate_request"`
+	Profile            string `json:"profile,omitempty"`
+	CAName             string `json:"caname,omitempty"`
+}
+
+type serverResponse struct {
+	Success bool `json:"success"`
+	Result  struct {
+		Cert       []byte `json:"Cert"`
+		ServerInfo struct {
+			CAChain []byte `json:"CAChain"`
+		} `json:"ServerInfo"`
+	} `json:"result"`
+	Errors []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewClient returns a Client for the CA at the given URL. The PEM encoded
+// rootCAs are used to authenticate the CA when the URL uses https.
+func NewClient(url, caName string, rootCAs [][]byte, timeout time.Duration) (*Client, error) {
+	pool := x509.NewCertPool()
+	for _, rootCA := range rootCAs {
+		if !pool.AppendCertsFromPEM(rootCA) {
+			return nil, errors.New("failed to parse CA TLS root certificate")
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	transport := &http.Transport{
+		DialContext: dialer.DialContext,
+		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			return tls.DialWithDialer(dialer, network, addr, &tls.Config{
+				RootCAs:    pool,
+				ServerName: host,
+			})
+		},
+	}
+
+	return &Client{
+		URL:    strings.TrimSuffix(url, "/"),
+		CAName: caName,
+		HTTPClient: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+	}, nil
+}
+
+// Enroll enrolls the identity with the given enrollment ID and secret for
+// the PEM encoded certificate signing request.
+func (c *Client) Enroll(enrollmentID, secret string, csr []byte, profile string) (*Response, error) {
+	req, err := c.newRequest("enroll", csr, profile)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(enrollmentID, secret)
+	return c.send(req)
+}
+
+// Reenroll renews the identity owning the given PEM encoded certificate and
+// signing key for the PEM encoded certificate signing request.
+func (c *Client) Reenroll(cert []byte, key crypto.Signer, csr []byte, profile string) (*Response, error) {
+	req, err := c.newRequest("reenroll", csr, profile)
+	if err != nil {
+		return nil, err
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	rawBody, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	token, err := authToken(cert, key, req.Method, req.URL.RequestURI(), rawBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", token)
+	return c.send(req)
+}
+
+func (c *Client) newRequest(endpoint string, csr []byte, profile string) (*http.Request, error) {
+	body, err := json.Marshal(&enrollRequest{
+		CertificateRequest: string(csr),
+		Profile:            profile,
+		CAName:             c.CAName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.URL+"/api/v1/"+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid CA URL %s", c.URL)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func (c *Client) send(req *http.Request) (*Response, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to reach CA at %s", c.URL)
+	}
+	defer resp.Body.Close()
+
+	sr := &serverResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(sr); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode response of CA (status %d)", resp.StatusCode)
+	}
+	if !sr.Success || resp.StatusCode != http.StatusOK {
+		var msgs []string
+		for _, e := range sr.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return nil, errors.Errorf("CA refused request (status %d): %s", resp.StatusCode, strings.Join(msgs, "; "))
+	}
+	if len(sr.Result.Cert) == 0 {
+		return nil, errors.New("CA response carries no certificate")
+	}
+
+	return &Response{
+		Cert:    sr.Result.Cert,
+		CAChain: sr.Result.ServerInfo.CAChain,
+	}, nil
+}
+
+// authToken computes the token authenticating a request to the CA with an
+// enrollment certificate, as <base64 cert>.<base64 signature> where the
+// signature covers <method>.<base64 uri>.<base64 body>.<base64 cert>.
+func authToken(cert []byte, key crypto.Signer, method, uri string, body []byte) (string, error) {
+	b64Cert := base64.StdEncoding.EncodeToString(cert)
+	payload := method + "." +
+		base64.StdEncoding.EncodeToString([]byte(uri)) + "." +
+		base64.StdEncoding.EncodeToString(body) + "." +
+		b64Cert
+
+	var sig []byte
+	var err error
+	switch k := key.(type) {
+	case *sm2.PrivateKey:
+		// SM2 signatures hash the message with SM3 internally
+		sig, err = k.Sign(rand.Reader, []byte(payload), nil)
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(payload))
+		sig, err = (&csp.ECDSASigner{PrivateKey: k}).Sign(rand.Reader, digest[:], nil)
+	default:
+		return "", errors.Errorf("unsupported key type %T", key)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign CA request")
+	}
+
+	return b64Cert + "." + base64.StdEncoding.EncodeToString(sig), nil
+}
@@ -0,0 +1,468 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package enrollment
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("enrollment")
+
+const (
+	// KeyTypeSM2 selects SM2 keys, the default.
+	KeyTypeSM2 = "sm2"
+	// KeyTypeECDSA selects ECDSA P-256 keys.
+	KeyTypeECDSA = "ecdsa"
+
+	// DefaultRenewBefore is how long before the expiration of a certificate
+	// it is renewed.
+	DefaultRenewBefore = 7 * 24 * time.Hour
+	// DefaultCheckInterval is how often the certificates are checked for
+	// expiration.
+	DefaultCheckInterval = time.Hour
+	// DefaultTimeout bounds the requests to the CA.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Identity designates one of the identities of the node.
+type Identity string
+
+const (
+	// MSPIdentity is the enrollment certificate of the local MSP.
+	MSPIdentity Identity = "msp"
+	// TLSIdentity is the TLS certificate of the node.
+	TLSIdentity Identity = "tls"
+)
+
+// Config configures the enrollment of the node against a CA.
+type Config struct {
+	// URL is the address of the CA, e.g. https://ca.org1.example.com:7054.
+	URL string
+	// CAName selects a CA among the CAs served at URL.
+	CAName string
+	// EnrollmentID and Secret are the credentials of the node identity
+	// registered with the CA. The secret is only required for the initial
+	// enrollment.
+	EnrollmentID string
+	Secret       string
+	// TLSRootCerts are the PEM files of the TLS root certificates of the CA.
+	TLSRootCerts []string
+	// TLSProfile is the CA signing profile of the TLS certificate.
+	TLSProfile string
+	// CSRHosts are the host names and IP addresses included in the
+	// certificates.
+	CSRHosts []string
+	// KeyType is the type of the generated keys, sm2 or ecdsa.
+	KeyType string
+	// MSPDir is the local MSP directory receiving the enrollment certificate
+	// and key.
+	MSPDir string
+	// TLSCertFile and TLSKeyFile receive the TLS certificate and key. The TLS
+	// identity is not enrolled when they are empty.
+	TLSCertFile string
+	TLSKeyFile  string
+	// RenewBefore is how long before their expiration the certificates are
+	// renewed.
+	RenewBefore time.Duration
+	// CheckInterval is how often the certificates are checked for expiration.
+	CheckInterval time.Duration
+	// Timeout bounds the requests to the CA.
+	Timeout time.Duration
+}
+
+type identity struct {
+	name     Identity
+	profile  string
+	certFile string
+	keyFile  string
+	caDir    string
+	icaDir   string
+}
+
+// Enroller enrolls the identities of the node against a CA and renews
+// their certificates before they expire.
+type Enroller struct {
+	Client        *Client
+	EnrollmentID  string
+	Secret        string
+	CSRHosts      []string
+	KeyType       string
+	RenewBefore   time.Duration
+	CheckInterval time.Duration
+	Now           func() time.Time
+
+	identities []identity
+}
+
+// New creates an Enroller from the given configuration.
+func New(conf Config) (*Enroller, error) {
+	if conf.URL == "" {
+		return nil, errors.New("enrollment URL is not set")
+	}
+	if conf.EnrollmentID == "" {
+		return nil, errors.New("enrollment ID is not set")
+	}
+	if conf.MSPDir == "" {
+		return nil, errors.New("MSP directory is not set")
+	}
+	switch conf.KeyType {
+	case "":
+		conf.KeyType = KeyTypeSM2
+	case KeyTypeSM2, KeyTypeECDSA:
+	default:
+		return nil, errors.Errorf("unsupported key type %s", conf.KeyType)
+	}
+	if (conf.TLSCertFile == "") != (conf.TLSKeyFile == "") {
+		return nil, errors.New("TLS certificate and key files must both be set")
+	}
+	if conf.RenewBefore == 0 {
+		conf.RenewBefore = DefaultRenewBefore
+	}
+	if conf.CheckInterval == 0 {
+		conf.CheckInterval = DefaultCheckInterval
+	}
+	if conf.Timeout == 0 {
+		conf.Timeout = DefaultTimeout
+	}
+	if conf.TLSProfile == "" {
+		conf.TLSProfile = "tls"
+	}
+
+	var rootCAs [][]byte
+	for _, f := range conf.TLSRootCerts {
+		rootCA, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA TLS root certificate %s", f)
+		}
+		rootCAs = append(rootCAs, rootCA)
+	}
+	client, err := NewClient(conf.URL, conf.CAName, rootCAs, conf.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Enroller{
+		Client:        client,
+		EnrollmentID:  conf.EnrollmentID,
+		Secret:        conf.Secret,
+		CSRHosts:      conf.CSRHosts,
+		KeyType:       conf.KeyType,
+		RenewBefore:   conf.RenewBefore,
+		CheckInterval: conf.CheckInterval,
+		Now:           time.Now,
+		identities: []identity{
+			{
+				name:     MSPIdentity,
+				certFile: filepath.Join(conf.MSPDir, "signcerts", "cert.pem"),
+				keyFile:  filepath.Join(conf.MSPDir, "keystore", "priv_sk"),
+				caDir:    filepath.Join(conf.MSPDir, "cacerts"),
+				icaDir:   filepath.Join(conf.MSPDir, "intermediatecerts"),
+			},
+		},
+	}
+	if conf.TLSCertFile != "" {
+		e.identities = append(e.identities, identity{
+			name:     TLSIdentity,
+			profile:  conf.TLSProfile,
+			certFile: conf.TLSCertFile,
+			keyFile:  conf.TLSKeyFile,
+			caDir:    filepath.Join(conf.MSPDir, "tlscacerts"),
+			icaDir:   filepath.Join(conf.MSPDir, "tlsintermediatecerts"),
+		})
+	}
+	return e, nil
+}
+
+// Enroll enrolls the identities missing a certificate and renews the
+// certificates about to expire. It returns the identities whose certificate
+// changed.
+func (e *Enroller) Enroll() ([]Identity, error) {
+	var renewed []Identity
+	for _, id := range e.identities {
+		changed, err := e.enroll(id)
+		if err != nil {
+			return renewed, errors.WithMessagef(err, "failed to enroll %s identity", id.name)
+		}
+		if changed {
+			renewed = append(renewed, id.name)
+		}
+	}
+	return renewed, nil
+}
+
+// Run periodically renews the certificates about to expire until done is
+// closed. The reloader of an identity, if any, is invoked once its
+// certificate has been renewed, so that the node uses it without a restart.
+func (e *Enroller) Run(done <-chan struct{}, reloaders map[Identity]func() error) {
+	ticker := time.NewTicker(e.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := e.Enroll()
+		if err != nil {
+			logger.Errorf("Failed renewing certificates: %s", err)
+		}
+		for _, id := range renewed {
+			reload, ok := reloaders[id]
+			if !ok {
+				continue
+			}
+			if err := reload(); err != nil {
+				logger.Errorf("Failed reloading renewed %s certificate: %s", id, err)
+				continue
+			}
+			logger.Infof("Reloaded renewed %s certificate", id)
+		}
+	}
+}
+
+func (e *Enroller) enroll(id identity) (bool, error) {
+	rawCert, err := ioutil.ReadFile(id.certFile)
+	if os.IsNotExist(err) {
+		if e.Secret == "" {
+			return false, errors.Errorf("certificate %s does not exist and no enrollment secret is set", id.certFile)
+		}
+		logger.Infof("Enrolling %s identity of %s", id.name, e.EnrollmentID)
+		return true, e.enrollWith(id, func(csr []byte) (*Response, error) {
+			return e.Client.Enroll(e.EnrollmentID, e.Secret, csr, id.profile)
+		})
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read certificate %s", id.certFile)
+	}
+
+	cert, err := parseCertificate(rawCert)
+	if err != nil {
+		return false, errors.WithMessagef(err, "invalid certificate %s", id.certFile)
+	}
+	if e.Now().Add(e.RenewBefore).Before(cert.NotAfter) {
+		return false, nil
+	}
+
+	logger.Infof("Renewing %s certificate of %s expiring at %s", id.name, e.EnrollmentID, cert.NotAfter)
+	key, err := loadKey(id.keyFile)
+	if err != nil {
+		return false, err
+	}
+	return true, e.enrollWith(id, func(csr []byte) (*Response, error) {
+		return e.Client.Reenroll(rawCert, key, csr, id.profile)
+	})
+}
+
+func (e *Enroller) enrollWith(id identity, send func(csr []byte) (*Response, error)) error {
+	key, keyPEM, err := generateKey(e.KeyType)
+	if err != nil {
+		return err
+	}
+	csr, err := e.newCSR(key)
+	if err != nil {
+		return err
+	}
+	resp, err := send(csr)
+	if err != nil {
+		return err
+	}
+	if _, err := parseCertificate(resp.Cert); err != nil {
+		return errors.WithMessage(err, "CA returned an invalid certificate")
+	}
+
+	if err := writeKeyPair(id, keyPEM, resp.Cert); err != nil {
+		return err
+	}
+	return writeCAChain(id, resp.CAChain)
+}
+
+func (e *Enroller) newCSR(key crypto.Signer) ([]byte, error) {
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: e.EnrollmentID},
+	}
+	for _, host := range e.CSRHosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create certificate signing request")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), nil
+}
+
+// writeCAChain stores the root and intermediate certificates of the CA
+// chain, unless the MSP already holds certificates of that kind.
+func writeCAChain(id identity, chain []byte) error {
+	var roots, intermediates []byte
+	for block, rest := pem.Decode(chain); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.Wrap(err, "CA returned an invalid certificate chain")
+		}
+		if isSelfSigned(cert) {
+			roots = append(roots, pem.EncodeToMemory(block)...)
+		} else {
+			intermediates = append(intermediates, pem.EncodeToMemory(block)...)
+		}
+	}
+
+	for dir, certs := range map[string][]byte{id.caDir: roots, id.icaDir: intermediates} {
+		if len(certs) == 0 || hasFiles(dir) {
+			continue
+		}
+		if err := writeFile(filepath.Join(dir, "ca.pem"), certs, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return strings.EqualFold(cert.Subject.String(), cert.Issuer.String()) && cert.CheckSignatureFrom(cert) == nil
+}
+
+func hasFiles(dir string) bool {
+	files, err := ioutil.ReadDir(dir)
+	return err == nil && len(files) > 0
+}
+
+func generateKey(keyType string) (crypto.Signer, []byte, error) {
+	var key crypto.Signer
+	var err error
+	if keyType == KeyTypeECDSA {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	} else {
+		key, err = sm2.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to generate %s key", keyType)
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal private key")
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func loadKey(keyFile string) (crypto.Signer, error) {
+	raw, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read private key %s", keyFile)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.Errorf("private key %s is not PEM encoded", keyFile)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse private key %s", keyFile)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type %T in %s", key, keyFile)
+	}
+	return signer, nil
+}
+
+func parseCertificate(raw []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("certificate is not PEM encoded")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// writeFile atomically replaces the content of the given file.
+// writeKeyPair replaces the key and the certificate of the identity together.
+// Both are staged before either is replaced, and the previous key is restored
+// if the certificate can't be replaced, so that the key always matches the
+// certificate.
+func writeKeyPair(id identity, keyPEM, certPEM []byte) error {
+	keyTmp, err := stageFile(id.keyFile, keyPEM, 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(keyTmp)
+	certTmp, err := stageFile(id.certFile, certPEM, 0644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(certTmp)
+
+	oldKey, err := ioutil.ReadFile(id.keyFile)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to read %s", id.keyFile)
+	}
+	hadKey := err == nil
+
+	if err := os.Rename(keyTmp, id.keyFile); err != nil {
+		return errors.Wrapf(err, "failed to replace %s", id.keyFile)
+	}
+	if err := os.Rename(certTmp, id.certFile); err != nil {
+		var restoreErr error
+		if hadKey {
+			restoreErr = writeFile(id.keyFile, oldKey, 0600)
+		} else {
+			restoreErr = os.Remove(id.keyFile)
+		}
+		if restoreErr != nil {
+			logger.Errorf("Failed restoring the previous key %s: %s", id.keyFile, restoreErr)
+		}
+		return errors.Wrapf(err, "failed to replace %s", id.certFile)
+	}
+	return nil
+}
+
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := stageFile(path, data, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return errors.Wrapf(err, "failed to replace %s", path)
+	}
+	return nil
+}
+
+// stageFile writes data next to path, to replace path by renaming it.
+func stageFile(path string, data []byte, perm os.FileMode) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to create directory for %s", path)
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, perm); err != nil {
+		return "", errors.Wrapf(err, "failed to write %s", tmp)
+	}
+	return tmp, nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package enrollment
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteKeyPair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrollment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	id := identity{
+		certFile: filepath.Join(dir, "signcerts", "cert.pem"),
+		keyFile:  filepath.Join(dir, "keystore", "priv_sk"),
+	}
+	require.NoError(t, writeKeyPair(id, []byte("key1"), []byte("cert1")))
+	requireFileContent(t, id.keyFile, "key1")
+	requireFileContent(t, id.certFile, "cert1")
+
+	require.NoError(t, writeKeyPair(id, []byte("key2"), []byte("cert2")))
+	requireFileContent(t, id.keyFile, "key2")
+	requireFileContent(t, id.certFile, "cert2")
+	require.NoFileExists(t, id.keyFile+".tmp")
+	require.NoFileExists(t, id.certFile+".tmp")
+
+	// the certificate can't be replaced, the previous key is restored
+	failingID := id
+	failingID.certFile = filepath.Join(dir, "signcerts", "dir")
+	require.NoError(t, os.MkdirAll(filepath.Join(failingID.certFile, "file"), 0755))
+	err = writeKeyPair(failingID, []byte("key3"), []byte("cert3"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to replace "+failingID.certFile)
+	requireFileContent(t, id.keyFile, "key2")
+	require.NoFileExists(t, id.keyFile+".tmp")
+	require.NoFileExists(t, failingID.certFile+".tmp")
+
+	// without a previous key, the new key is removed
+	failingID.keyFile = filepath.Join(dir, "keystore", "other_sk")
+	err = writeKeyPair(failingID, []byte("key4"), []byte("cert4"))
+	require.Error(t, err)
+	require.NoFileExists(t, failingID.keyFile)
+}
+
+func requireFileContent(t *testing.T, path, expected string) {
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+}
@@ -0,0 +1,256 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package enrollment_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/hyperledger/fabric/internal/cryptogen/ca"
+	"github.com/hyperledger/fabric/internal/pkg/enrollment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCA struct {
+	t      *testing.T
+	dir    string
+	ca     *ca.CA
+	caCert []byte
+
+	mutex     sync.Mutex
+	enrolls   int
+	reenrolls int
+	profiles  []string
+}
+
+func newFakeCA(t *testing.T, dir string) *fakeCA {
+	signCA, err := ca.NewCA(filepath.Join(dir, "ca"), "org1.example.com", "ca.org1.example.com", "CN", "", "", "", "", "", true)
+	require.NoError(t, err)
+	caCert, err := ioutil.ReadFile(filepath.Join(dir, "ca", "ca.org1.example.com-cert.pem"))
+	require.NoError(t, err)
+	return &fakeCA{t: t, dir: dir, ca: signCA, caCert: caCert}
+}
+
+func (f *fakeCA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(f.t, err)
+
+	switch r.URL.Path {
+	case "/api/v1/enroll":
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "peer0" || secret != "peer0pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"success":false,"errors":[{"code":20,"message":"Authentication failure"}]}`))
+			return
+		}
+		f.enrolls++
+	case "/api/v1/reenroll":
+		token := strings.Split(r.Header.Get("Authorization"), ".")
+		require.Len(f.t, token, 2)
+		rawCert, err := base64.StdEncoding.DecodeString(token[0])
+		require.NoError(f.t, err)
+		sig, err := base64.StdEncoding.DecodeString(token[1])
+		require.NoError(f.t, err)
+		block, _ := pem.Decode(rawCert)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(f.t, err)
+		payload := r.Method + "." + base64.StdEncoding.EncodeToString([]byte(r.URL.RequestURI())) + "." +
+			base64.StdEncoding.EncodeToString(body) + "." + token[0]
+		require.True(f.t, cert.PublicKey.(*sm2.PublicKey).Verify([]byte(payload), sig), "invalid token signature")
+		f.reenrolls++
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	req := struct {
+		CertificateRequest string `json:"certificate_request"`
+		Profile            string `json:"profile"`
+	}{}
+	require.NoError(f.t, json.Unmarshal(body, &req))
+	f.profiles = append(f.profiles, req.Profile)
+	block, _ := pem.Decode([]byte(req.CertificateRequest))
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(f.t, err)
+	// the SM2 key of the request is only recognized as such by the PKIX parser
+	pub, err := x509.ParsePKIXPublicKey(csr.RawSubjectPublicKeyInfo)
+	require.NoError(f.t, err)
+	require.True(f.t, pub.(*sm2.PublicKey).Verify(csr.RawTBSCertificateRequest, csr.Signature), "invalid request signature")
+
+	_, err = f.ca.SignCertificate(f.dir, csr.Subject.CommonName, nil, csr.DNSNames, pub, x509.KeyUsageDigitalSignature, nil)
+	require.NoError(f.t, err)
+	cert, err := ioutil.ReadFile(filepath.Join(f.dir, csr.Subject.CommonName+"-cert.pem"))
+	require.NoError(f.t, err)
+
+	resp := map[string]interface{}{
+		"success": true,
+		"result": map[string]interface{}{
+			"Cert":       cert,
+			"ServerInfo": map[string]interface{}{"CAChain": f.caCert},
+		},
+	}
+	require.NoError(f.t, json.NewEncoder(w).Encode(resp))
+}
+
+func (f *fakeCA) counts() (int, int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.enrolls, f.reenrolls
+}
+
+func newEnroller(t *testing.T, dir, url, secret string) *enrollment.Enroller {
+	e, err := enrollment.New(enrollment.Config{
+		URL:          url,
+		EnrollmentID: "peer0",
+		Secret:       secret,
+		CSRHosts:     []string{"peer0.org1.example.com", "127.0.0.1"},
+		MSPDir:       filepath.Join(dir, "msp"),
+		TLSCertFile:  filepath.Join(dir, "tls", "server.crt"),
+		TLSKeyFile:   filepath.Join(dir, "tls", "server.key"),
+	})
+	require.NoError(t, err)
+	return e
+}
+
+func readCert(t *testing.T, path string) *x509.Certificate {
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestEnroll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrollment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fakeCA := newFakeCA(t, dir)
+	server := httptest.NewServer(fakeCA)
+	defer server.Close()
+
+	e := newEnroller(t, dir, server.URL, "peer0pw")
+	renewed, err := e.Enroll()
+	require.NoError(t, err)
+	assert.Equal(t, []enrollment.Identity{enrollment.MSPIdentity, enrollment.TLSIdentity}, renewed)
+	assert.Equal(t, []string{"", "tls"}, fakeCA.profiles)
+
+	signCert := readCert(t, filepath.Join(dir, "msp", "signcerts", "cert.pem"))
+	assert.IsType(t, &sm2.PublicKey{}, signCert.PublicKey)
+	assert.Equal(t, "peer0", signCert.Subject.CommonName)
+	tlsCert := readCert(t, filepath.Join(dir, "tls", "server.crt"))
+	assert.Equal(t, []string{"peer0.org1.example.com"}, tlsCert.DNSNames)
+	assert.FileExists(t, filepath.Join(dir, "msp", "keystore", "priv_sk"))
+	assert.FileExists(t, filepath.Join(dir, "tls", "server.key"))
+	caCert, err := ioutil.ReadFile(filepath.Join(dir, "msp", "cacerts", "ca.pem"))
+	require.NoError(t, err)
+	assert.Equal(t, fakeCA.caCert, caCert)
+	assert.FileExists(t, filepath.Join(dir, "msp", "tlscacerts", "ca.pem"))
+	assert.NoDirExists(t, filepath.Join(dir, "msp", "intermediatecerts"))
+
+	// the certificates are valid, nothing to do
+	renewed, err = e.Enroll()
+	require.NoError(t, err)
+	assert.Empty(t, renewed)
+
+	// the certificates are about to expire, they are renewed with new keys
+	oldKey, err := ioutil.ReadFile(filepath.Join(dir, "msp", "keystore", "priv_sk"))
+	require.NoError(t, err)
+	e.Now = func() time.Time { return signCert.NotAfter.Add(-time.Hour) }
+	renewed, err = e.Enroll()
+	require.NoError(t, err)
+	assert.Equal(t, []enrollment.Identity{enrollment.MSPIdentity, enrollment.TLSIdentity}, renewed)
+	enrolls, reenrolls := fakeCA.counts()
+	assert.Equal(t, 2, enrolls)
+	assert.Equal(t, 2, reenrolls)
+	newKey, err := ioutil.ReadFile(filepath.Join(dir, "msp", "keystore", "priv_sk"))
+	require.NoError(t, err)
+	assert.NotEqual(t, oldKey, newKey)
+}
+
+func TestEnrollFailures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrollment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewServer(newFakeCA(t, dir))
+	defer server.Close()
+
+	_, err = newEnroller(t, dir, server.URL, "").Enroll()
+	assert.EqualError(t, err, "failed to enroll msp identity: certificate "+filepath.Join(dir, "msp", "signcerts", "cert.pem")+" does not exist and no enrollment secret is set")
+
+	_, err = newEnroller(t, dir, server.URL, "wrong").Enroll()
+	assert.EqualError(t, err, "failed to enroll msp identity: CA refused request (status 401): Authentication failure")
+	assert.NoFileExists(t, filepath.Join(dir, "msp", "keystore", "priv_sk"))
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrollment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewServer(newFakeCA(t, dir))
+	defer server.Close()
+
+	e := newEnroller(t, dir, server.URL, "peer0pw")
+	_, err = e.Enroll()
+	require.NoError(t, err)
+
+	e.CheckInterval = 10 * time.Millisecond
+	e.Now = func() time.Time { return time.Now().AddDate(200, 0, 0) }
+	reloaded := make(chan enrollment.Identity, 10)
+	done := make(chan struct{})
+	go e.Run(done, map[enrollment.Identity]func() error{
+		enrollment.TLSIdentity: func() error {
+			reloaded <- enrollment.TLSIdentity
+			return nil
+		},
+	})
+	defer close(done)
+
+	select {
+	case id := <-reloaded:
+		assert.Equal(t, enrollment.TLSIdentity, id)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "renewed certificate was not reloaded")
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		conf enrollment.Config
+		err  string
+	}{
+		{enrollment.Config{}, "enrollment URL is not set"},
+		{enrollment.Config{URL: "http://ca"}, "enrollment ID is not set"},
+		{enrollment.Config{URL: "http://ca", EnrollmentID: "peer0"}, "MSP directory is not set"},
+		{enrollment.Config{URL: "http://ca", EnrollmentID: "peer0", MSPDir: "msp", KeyType: "rsa"}, "unsupported key type rsa"},
+		{enrollment.Config{URL: "http://ca", EnrollmentID: "peer0", MSPDir: "msp", TLSCertFile: "tls.crt"}, "TLS certificate and key files must both be set"},
+		{enrollment.Config{URL: "http://ca", EnrollmentID: "peer0", MSPDir: "msp", TLSRootCerts: []string{"missing.pem"}}, "failed to read CA TLS root certificate missing.pem: open missing.pem: no such file or directory"},
+	} {
+		_, err := enrollment.New(tc.conf)
+		assert.EqualError(t, err, tc.err)
+	}
+}
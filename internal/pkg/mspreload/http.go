@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mspreload
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("mspreload")
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler exposes the reload of the local MSP through the operations
+// endpoint. PUT reloads the local MSP from its directory, so that new admin
+// certificates, updated CRLs and rotated TLS CAs take effect without a restart.
+type Handler struct {
+	Reload func() error
+}
+
+// NewHandler returns a Handler which reloads the local MSP with the given function.
+func NewHandler(reload func() error) *Handler {
+	return &Handler{Reload: reload}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPut {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+
+	if err := h.Reload(); err != nil {
+		logger.Errorw("failed to reload the local MSP", "error", err)
+		h.sendResponse(resp, http.StatusInternalServerError, &errorResponse{
+			Error: fmt.Sprintf("failed to reload the local MSP: %s", err),
+		})
+		return
+	}
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mspreload_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/pkg/mspreload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var reloads int
+	var reloadErr error
+	handler := mspreload.NewHandler(func() error {
+		reloads++
+		return reloadErr
+	})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, "/msp/reload", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, 1, reloads)
+
+	reloadErr = errors.New("could not load a valid signer certificate")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, "/msp/reload", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "failed to reload the local MSP: could not load a valid signer certificate", body["error"])
+	assert.Equal(t, 2, reloads)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/msp/reload", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "invalid request method: GET", body["error"])
+	assert.Equal(t, 2, reloads)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+)
+
+var logger = flogging.MustGetLogger("peer.backup")
+
+// LedgerBackuper takes the online backups of the ledgers of the peer.
+type LedgerBackuper interface {
+	BackupLedger(ledgerID string) (*kvledger.BackupManifest, error)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// backupResponse is the manifest of a backup along with the dir holding it.
+type backupResponse struct {
+	Dir string `json:"dir"`
+	*kvledger.BackupManifest
+}
+
+// Handler exposes the online backups of the channel ledgers through the
+// operations endpoint. POST with a channel query parameter takes a backup of
+// the ledger of the channel and returns its manifest.
+type Handler struct {
+	Backuper LedgerBackuper
+}
+
+// NewHandler returns a Handler for the given LedgerBackuper.
+func NewHandler(b LedgerBackuper) *Handler {
+	return &Handler{Backuper: b}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+	channelID := req.URL.Query().Get("channel")
+	if channelID == "" {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: "missing channel query parameter",
+		})
+		return
+	}
+
+	manifest, err := h.Backuper.BackupLedger(channelID)
+	if err != nil {
+		logger.Errorw("failed to back up ledger", "channel", channelID, "error", err)
+		h.sendResponse(resp, http.StatusInternalServerError, &errorResponse{
+			Error: fmt.Sprintf("failed to back up ledger of channel %s: %s", channelID, err),
+		})
+		return
+	}
+	h.sendResponse(resp, http.StatusCreated, &backupResponse{Dir: manifest.Dir, BackupManifest: manifest})
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
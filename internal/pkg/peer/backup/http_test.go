@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/internal/pkg/peer/backup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackuper struct {
+	ledgerIDs []string
+	manifest  *kvledger.BackupManifest
+	err       error
+}
+
+func (f *fakeBackuper) BackupLedger(ledgerID string) (*kvledger.BackupManifest, error) {
+	f.ledgerIDs = append(f.ledgerIDs, ledgerID)
+	return f.manifest, f.err
+}
+
+func TestHandler(t *testing.T) {
+	backuper := &fakeBackuper{
+		manifest: &kvledger.BackupManifest{
+			LedgerID:           "mychannel",
+			Height:             10,
+			LastBlockHashInHex: "abcd",
+			StateDatabase:      "goleveldb",
+			CreatedAt:          time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+			FilesAndHashes:     map[string]string{"chains/chains/mychannel/blockfile_000000": "0123"},
+			Dir:                "/backups/completed/mychannel/10",
+		},
+	}
+	handler := backup.NewHandler(backuper)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/ledger/backup?channel=mychannel", nil))
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, []string{"mychannel"}, backuper.ledgerIDs)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "/backups/completed/mychannel/10", body["dir"])
+	assert.Equal(t, "mychannel", body["ledger_id"])
+	assert.Equal(t, float64(10), body["height"])
+	assert.Equal(t, map[string]interface{}{"chains/chains/mychannel/blockfile_000000": "0123"}, body["backup_files_raw_hashes"])
+
+	backuper.err = errors.New("Ledger not opened [yourchannel]")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/ledger/backup?channel=yourchannel", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"error":"failed to back up ledger of channel yourchannel: Ledger not opened [yourchannel]"}`, resp.Body.String())
+}
+
+func TestHandlerBadRequests(t *testing.T) {
+	backuper := &fakeBackuper{}
+	handler := backup.NewHandler(backuper)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/backup?channel=mychannel", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: GET"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/ledger/backup", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"missing channel query parameter"}`, resp.Body.String())
+	assert.Empty(t, backuper.ledgerIDs)
+}
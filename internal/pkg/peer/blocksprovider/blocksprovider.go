@@ -70,6 +70,8 @@ type BlockVerifier interface {
 //go:generate counterfeiter -o fake/orderer_connection_source.go --fake-name OrdererConnectionSource . OrdererConnectionSource
 type OrdererConnectionSource interface {
 	RandomEndpoint() (*orderers.Endpoint, error)
+	ReportFailure(address string)
+	ReportSuccess(address string)
 }
 
 //go:generate counterfeiter -o fake/dialer.go --fake-name Dialer . Dialer
@@ -168,6 +170,7 @@ func (d *Deliverer) DeliverBlocks() {
 		}
 
 		connLogger := d.Logger.With("orderer-address", endpoint.Address)
+		delivered := false
 
 		recv := make(chan *orderer.DeliverResponse)
 		go func() {
@@ -197,15 +200,21 @@ func (d *Deliverer) DeliverBlocks() {
 				if !ok {
 					connLogger.Warningf("Orderer hung up without sending status")
 					failureCounter++
+					d.Orderers.ReportFailure(endpoint.Address)
 					break RecvLoop
 				}
 				err = d.processMsg(response)
 				if err != nil {
 					connLogger.Warningf("Got error while attempting to receive blocks: %v", err)
 					failureCounter++
+					d.Orderers.ReportFailure(endpoint.Address)
 					break RecvLoop
 				}
 				failureCounter = 0
+				if !delivered {
+					delivered = true
+					d.Orderers.ReportSuccess(endpoint.Address)
+				}
 			case <-d.DoneC:
 				break RecvLoop
 			}
@@ -223,6 +232,9 @@ func (d *Deliverer) processMsg(msg *orderer.DeliverResponse) error {
 		if t.Status == common.Status_SUCCESS {
 			return errors.Errorf("received success for a seek that should never complete")
 		}
+		if t.Status == common.Status_GONE {
+			return errors.Errorf("received status %v from orderer, which no longer retains the blocks the peer is missing", t.Status)
+		}
 
 		return errors.Errorf("received bad status %v from orderer", t.Status)
 	case *orderer.DeliverResponse_Block:
@@ -292,6 +304,7 @@ func (d *Deliverer) connect(seekInfoEnv *common.Envelope) (orderer.AtomicBroadca
 
 	conn, err := d.Dialer.Dial(endpoint.Address, endpoint.CertPool)
 	if err != nil {
+		d.Orderers.ReportFailure(endpoint.Address)
 		return nil, nil, nil, errors.WithMessagef(err, "could not dial endpoint '%s'", endpoint.Address)
 	}
 
@@ -301,6 +314,7 @@ func (d *Deliverer) connect(seekInfoEnv *common.Envelope) (orderer.AtomicBroadca
 	if err != nil {
 		conn.Close()
 		ctxCancel()
+		d.Orderers.ReportFailure(endpoint.Address)
 		return nil, nil, nil, errors.WithMessagef(err, "could not create deliver client to endpoints '%s'", endpoint.Address)
 	}
 
@@ -309,6 +323,7 @@ func (d *Deliverer) connect(seekInfoEnv *common.Envelope) (orderer.AtomicBroadca
 		deliverClient.CloseSend()
 		conn.Close()
 		ctxCancel()
+		d.Orderers.ReportFailure(endpoint.Address)
 		return nil, nil, nil, errors.WithMessagef(err, "could not send deliver seek info handshake to '%s'", endpoint.Address)
 	}
 
@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package blocksprovider_test
 
 import (
-	"crypto/x509"
 	"fmt"
 	"sync"
 	"time"
@@ -15,6 +14,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/cetcxinlian/cryptogm/x509"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/gossip"
 	"github.com/hyperledger/fabric-protos-go/orderer"
@@ -232,6 +232,12 @@ var _ = Describe("Blocksprovider", func() {
 			Expect(fakeSleeper.SleepCallCount()).To(Equal(1))
 			Expect(fakeSleeper.SleepArgsForCall(0)).To(Equal(100 * time.Millisecond))
 		})
+
+		It("reports the failure of the endpoint", func() {
+			Eventually(fakeDialer.DialCallCount).Should(Equal(2))
+			Expect(fakeOrdererConnectionSource.ReportFailureCallCount()).To(Equal(1))
+			Expect(fakeOrdererConnectionSource.ReportFailureArgsForCall(0)).To(Equal("orderer-address"))
+		})
 	})
 
 	It("constructs a deliver client", func() {
@@ -458,6 +464,12 @@ var _ = Describe("Blocksprovider", func() {
 			Expect(fakeSleeper.SleepCallCount()).To(Equal(0))
 		})
 
+		It("reports the success of the endpoint", func() {
+			Eventually(fakeOrdererConnectionSource.ReportSuccessCallCount).Should(Equal(1))
+			Expect(fakeOrdererConnectionSource.ReportSuccessArgsForCall(0)).To(Equal("orderer-address"))
+			Expect(fakeOrdererConnectionSource.ReportFailureCallCount()).To(Equal(0))
+		})
+
 		It("checks the validity of the block", func() {
 			Eventually(fakeBlockVerifier.VerifyBlockCallCount).Should(Equal(1))
 			channelID, blockNum, block := fakeBlockVerifier.VerifyBlockArgsForCall(0)
@@ -482,6 +494,12 @@ var _ = Describe("Blocksprovider", func() {
 				defer mutex.Unlock()
 				Expect(len(ccs)).To(Equal(2))
 			})
+
+			It("reports the failure of the endpoint", func() {
+				Eventually(fakeOrdererConnectionSource.ReportFailureCallCount).Should(Equal(1))
+				Expect(fakeOrdererConnectionSource.ReportFailureArgsForCall(0)).To(Equal("orderer-address"))
+				Expect(fakeOrdererConnectionSource.ReportSuccessCallCount()).To(Equal(0))
+			})
 		})
 
 		It("adds the payload to gossip", func() {
@@ -599,5 +617,15 @@ var _ = Describe("Blocksprovider", func() {
 				Eventually(fakeSleeper.SleepCallCount).Should(Equal(1))
 			})
 		})
+
+		When("the orderer no longer retains the blocks", func() {
+			BeforeEach(func() {
+				status = common.Status_GONE
+			})
+
+			It("disconnects with an error", func() {
+				Eventually(fakeSleeper.SleepCallCount).Should(Equal(1))
+			})
+		})
 	})
 })
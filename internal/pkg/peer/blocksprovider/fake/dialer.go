@@ -2,9 +2,9 @@
 package fake
 
 import (
-	"crypto/x509"
 	"sync"
 
+	"github.com/cetcxinlian/cryptogm/x509"
 	"github.com/hyperledger/fabric/internal/pkg/peer/blocksprovider"
 	"google.golang.org/grpc"
 )
@@ -21,6 +21,16 @@ type OrdererConnectionSource struct {
 		result1 *orderers.Endpoint
 		result2 error
 	}
+	ReportFailureStub        func(string)
+	reportFailureMutex       sync.RWMutex
+	reportFailureArgsForCall []struct {
+		arg1 string
+	}
+	ReportSuccessStub        func(string)
+	reportSuccessMutex       sync.RWMutex
+	reportSuccessArgsForCall []struct {
+		arg1 string
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -80,11 +90,77 @@ func (fake *OrdererConnectionSource) RandomEndpointReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *OrdererConnectionSource) ReportFailure(arg1 string) {
+	fake.reportFailureMutex.Lock()
+	fake.reportFailureArgsForCall = append(fake.reportFailureArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ReportFailure", []interface{}{arg1})
+	fake.reportFailureMutex.Unlock()
+	if fake.ReportFailureStub != nil {
+		fake.ReportFailureStub(arg1)
+	}
+}
+
+func (fake *OrdererConnectionSource) ReportFailureCallCount() int {
+	fake.reportFailureMutex.RLock()
+	defer fake.reportFailureMutex.RUnlock()
+	return len(fake.reportFailureArgsForCall)
+}
+
+func (fake *OrdererConnectionSource) ReportFailureCalls(stub func(string)) {
+	fake.reportFailureMutex.Lock()
+	defer fake.reportFailureMutex.Unlock()
+	fake.ReportFailureStub = stub
+}
+
+func (fake *OrdererConnectionSource) ReportFailureArgsForCall(i int) string {
+	fake.reportFailureMutex.RLock()
+	defer fake.reportFailureMutex.RUnlock()
+	argsForCall := fake.reportFailureArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *OrdererConnectionSource) ReportSuccess(arg1 string) {
+	fake.reportSuccessMutex.Lock()
+	fake.reportSuccessArgsForCall = append(fake.reportSuccessArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ReportSuccess", []interface{}{arg1})
+	fake.reportSuccessMutex.Unlock()
+	if fake.ReportSuccessStub != nil {
+		fake.ReportSuccessStub(arg1)
+	}
+}
+
+func (fake *OrdererConnectionSource) ReportSuccessCallCount() int {
+	fake.reportSuccessMutex.RLock()
+	defer fake.reportSuccessMutex.RUnlock()
+	return len(fake.reportSuccessArgsForCall)
+}
+
+func (fake *OrdererConnectionSource) ReportSuccessCalls(stub func(string)) {
+	fake.reportSuccessMutex.Lock()
+	defer fake.reportSuccessMutex.Unlock()
+	fake.ReportSuccessStub = stub
+}
+
+func (fake *OrdererConnectionSource) ReportSuccessArgsForCall(i int) string {
+	fake.reportSuccessMutex.RLock()
+	defer fake.reportSuccessMutex.RUnlock()
+	argsForCall := fake.reportSuccessArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *OrdererConnectionSource) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.randomEndpointMutex.RLock()
 	defer fake.randomEndpointMutex.RUnlock()
+	fake.reportFailureMutex.RLock()
+	defer fake.reportFailureMutex.RUnlock()
+	fake.reportSuccessMutex.RLock()
+	defer fake.reportSuccessMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
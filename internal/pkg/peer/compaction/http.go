@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+)
+
+var logger = flogging.MustGetLogger("peer.compaction")
+
+// StoreCompactor compacts the leveldb based stores of the peer.
+type StoreCompactor interface {
+	CompactStores(stores ...string) ([]*kvledger.CompactionResult, error)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler exposes the compaction of the leveldb based stores through the
+// operations endpoint. POST compacts the stores given by the store query
+// parameters, or all of them when none is given, and returns the space
+// reclaimed from each store.
+type Handler struct {
+	Compactor StoreCompactor
+}
+
+// NewHandler returns a Handler for the given StoreCompactor.
+func NewHandler(c StoreCompactor) *Handler {
+	return &Handler{Compactor: c}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+
+	stores := req.URL.Query()["store"]
+	results, err := h.Compactor.CompactStores(stores...)
+	if err != nil {
+		logger.Errorw("failed to compact stores", "stores", stores, "error", err)
+		h.sendResponse(resp, http.StatusInternalServerError, &errorResponse{
+			Error: fmt.Sprintf("failed to compact stores: %s", err),
+		})
+		return
+	}
+	h.sendResponse(resp, http.StatusOK, results)
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compaction_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/internal/pkg/peer/compaction"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCompactor struct {
+	stores [][]string
+	err    error
+}
+
+func (f *fakeCompactor) CompactStores(stores ...string) ([]*kvledger.CompactionResult, error) {
+	f.stores = append(f.stores, stores)
+	if f.err != nil {
+		return nil, f.err
+	}
+	var results []*kvledger.CompactionResult
+	for _, store := range stores {
+		results = append(results, &kvledger.CompactionResult{
+			Store:           store,
+			SizeBeforeBytes: 1000,
+			SizeAfterBytes:  400,
+			ReclaimedBytes:  600,
+			DurationSeconds: 1.5,
+		})
+	}
+	return results, nil
+}
+
+func TestHandler(t *testing.T) {
+	compactor := &fakeCompactor{}
+	handler := compaction.NewHandler(compactor)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/ledger/compaction?store=statedb&store=historydb", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, [][]string{{"statedb", "historydb"}}, compactor.stores)
+	assert.JSONEq(t, `[
+		{"store":"statedb","size_before_bytes":1000,"size_after_bytes":400,"reclaimed_bytes":600,"duration_seconds":1.5},
+		{"store":"historydb","size_before_bytes":1000,"size_after_bytes":400,"reclaimed_bytes":600,"duration_seconds":1.5}
+	]`, resp.Body.String())
+
+	compactor.err = errors.New("unknown store [pvtdatastore]")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/ledger/compaction?store=pvtdatastore", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"error":"failed to compact stores: unknown store [pvtdatastore]"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/compaction", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: GET"}`, resp.Body.String())
+	assert.Len(t, compactor.stores, 2)
+}
@@ -0,0 +1,274 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package drain
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var logger = flogging.MustGetLogger("peer.drain")
+
+// State is the drain state of the peer.
+type State string
+
+const (
+	// Serving is the state of a peer accepting requests.
+	Serving State = "serving"
+	// Draining is the state of a peer which refuses new requests and waits
+	// for the in-flight requests and commits to complete.
+	Draining State = "draining"
+	// Drained is the state of a peer which is ready to be stopped.
+	Drained State = "drained"
+)
+
+// Flusher flushes buffered state once the peer no longer processes requests
+// or commits blocks.
+type Flusher struct {
+	Name  string
+	Flush func() error
+}
+
+// Options configures a Drainer.
+type Options struct {
+	// Services are the gRPC services refusing new requests while the peer
+	// drains.
+	Services []string
+	// DetachedServices are the services among Services whose in-flight
+	// streams are not waited for, such as long-lived deliver streams.
+	DetachedServices []string
+	// Flushers are invoked, in order, once the peer has been drained.
+	Flushers []Flusher
+}
+
+// Status reports the drain state of the peer.
+type Status struct {
+	State            State  `json:"state"`
+	InFlightRequests int    `json:"in_flight_requests"`
+	InFlightCommits  int    `json:"in_flight_commits"`
+	ReadyToStop      bool   `json:"ready_to_stop"`
+	Error            string `json:"error,omitempty"`
+}
+
+// Drainer puts the peer into drain mode: new requests to the drained services
+// are refused, commits are suspended once the in-flight ones have completed,
+// and the flushers are invoked before the peer reports that it is ready to
+// stop.
+type Drainer struct {
+	services map[string]bool
+	detached map[string]bool
+	flushers []Flusher
+
+	mutex      sync.Mutex
+	cond       *sync.Cond
+	state      State
+	generation uint64
+	requests   int
+	commits    int
+	flushErr   error
+}
+
+// New creates a Drainer serving requests.
+func New(opts Options) *Drainer {
+	d := &Drainer{
+		services: map[string]bool{},
+		detached: map[string]bool{},
+		flushers: opts.Flushers,
+		state:    Serving,
+	}
+	d.cond = sync.NewCond(&d.mutex)
+	for _, s := range opts.Services {
+		d.services[s] = true
+	}
+	for _, s := range opts.DetachedServices {
+		d.detached[s] = true
+	}
+	return d
+}
+
+// Drain starts draining the peer. It returns immediately, the progress is
+// reported by Status.
+func (d *Drainer) Drain() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.state != Serving {
+		return
+	}
+
+	logger.Infof("Draining peer: refusing new requests and suspending commits")
+	d.state = Draining
+	d.generation++
+	d.flushErr = nil
+	go d.drain(d.generation)
+}
+
+func (d *Drainer) drain(generation uint64) {
+	d.mutex.Lock()
+	for d.generation == generation && (d.requests > 0 || d.commits > 0) {
+		d.cond.Wait()
+	}
+	if d.generation != generation {
+		d.mutex.Unlock()
+		return
+	}
+	d.mutex.Unlock()
+
+	logger.Infof("In-flight requests and commits completed, flushing")
+	var flushErr error
+	for _, f := range d.flushers {
+		if err := f.Flush(); err != nil {
+			logger.Errorf("Failed to flush %s: %s", f.Name, err)
+			flushErr = errors.WithMessagef(err, "failed to flush %s", f.Name)
+			break
+		}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.generation != generation {
+		return
+	}
+	d.flushErr = flushErr
+	if flushErr != nil {
+		return
+	}
+	d.state = Drained
+	logger.Infof("Peer drained and ready to stop")
+}
+
+// Resume stops draining the peer, which accepts requests and commits blocks
+// again.
+func (d *Drainer) Resume() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.state == Serving {
+		return
+	}
+
+	logger.Infof("Resuming peer")
+	d.state = Serving
+	d.generation++
+	d.cond.Broadcast()
+}
+
+// Status returns the drain state of the peer.
+func (d *Drainer) Status() Status {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	s := Status{
+		State:            d.state,
+		InFlightRequests: d.requests,
+		InFlightCommits:  d.commits,
+		ReadyToStop:      d.state == Drained,
+	}
+	if d.flushErr != nil {
+		s.Error = d.flushErr.Error()
+	}
+	return s
+}
+
+// HealthCheck fails while the peer drains, so that load balancers stop
+// routing requests to it.
+func (d *Drainer) HealthCheck(context.Context) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.state != Serving {
+		return errors.Errorf("peer is %s", d.state)
+	}
+	return nil
+}
+
+// EnterCommit blocks while commits are suspended. Each call must be followed
+// by a call to ExitCommit once the commit completes.
+func (d *Drainer) EnterCommit() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for d.state != Serving {
+		d.cond.Wait()
+	}
+	d.commits++
+}
+
+// ExitCommit signals the completion of a commit.
+func (d *Drainer) ExitCommit() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.commits--
+	d.cond.Broadcast()
+}
+
+// admit registers a new request to the given method. It reports whether the
+// request is admitted, and whether it is tracked as an in-flight request.
+func (d *Drainer) admit(fullMethod string) (admitted, tracked bool) {
+	service := serviceName(fullMethod)
+	if !d.services[service] {
+		return true, false
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.state != Serving {
+		return false, false
+	}
+	if d.detached[service] {
+		return true, false
+	}
+	d.requests++
+	return true, true
+}
+
+func (d *Drainer) done() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.requests--
+	d.cond.Broadcast()
+}
+
+// UnaryServerInterceptor refuses new unary requests to the drained services
+// while the peer drains, and tracks the in-flight ones.
+func (d *Drainer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		admitted, tracked := d.admit(info.FullMethod)
+		if !admitted {
+			return nil, status.Error(codes.Unavailable, "peer is draining")
+		}
+		if tracked {
+			defer d.done()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor refuses new streams to the drained services while
+// the peer drains, and tracks the in-flight ones.
+func (d *Drainer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		admitted, tracked := d.admit(info.FullMethod)
+		if !admitted {
+			return status.Error(codes.Unavailable, "peer is draining")
+		}
+		if tracked {
+			defer d.done()
+		}
+		return handler(srv, ss)
+	}
+}
+
+func serviceName(fullMethod string) string {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i]
+	}
+	return fullMethod
+}
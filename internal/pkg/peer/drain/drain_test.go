@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package drain_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/internal/pkg/peer/drain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newDrainer(flushers ...drain.Flusher) *drain.Drainer {
+	return drain.New(drain.Options{
+		Services:         []string{"protos.Endorser", "protos.Deliver"},
+		DetachedServices: []string{"protos.Deliver"},
+		Flushers:         flushers,
+	})
+}
+
+func unaryCall(d *drain.Drainer, method string, handler grpc.UnaryHandler) error {
+	_, err := d.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return err
+}
+
+func streamCall(d *drain.Drainer, method string, handler grpc.StreamHandler) error {
+	return d.StreamServerInterceptor()(nil, nil, &grpc.StreamServerInfo{FullMethod: method}, handler)
+}
+
+func noopUnary(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+
+func noopStream(srv interface{}, stream grpc.ServerStream) error { return nil }
+
+func TestDrain(t *testing.T) {
+	var flushed []string
+	var flushMutex sync.Mutex
+	flusher := func(name string) drain.Flusher {
+		return drain.Flusher{Name: name, Flush: func() error {
+			flushMutex.Lock()
+			defer flushMutex.Unlock()
+			flushed = append(flushed, name)
+			return nil
+		}}
+	}
+	d := newDrainer(flusher("first"), flusher("second"))
+	assert.Equal(t, drain.Status{State: drain.Serving}, d.Status())
+	assert.NoError(t, d.HealthCheck(context.Background()))
+
+	// an in-flight proposal, a deliver stream and a commit
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	go unaryCall(d, "/protos.Endorser/ProcessProposal", func(ctx context.Context, req interface{}) (interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return nil, nil
+	})
+	go streamCall(d, "/protos.Deliver/Deliver", func(srv interface{}, stream grpc.ServerStream) error {
+		started <- struct{}{}
+		select {}
+	})
+	<-started
+	<-started
+	d.EnterCommit()
+
+	d.Drain()
+	assert.Equal(t, drain.Status{State: drain.Draining, InFlightRequests: 1, InFlightCommits: 1}, d.Status())
+	assert.EqualError(t, d.HealthCheck(context.Background()), "peer is draining")
+
+	// new proposals and deliver clients are refused, other services are not
+	err := unaryCall(d, "/protos.Endorser/ProcessProposal", noopUnary)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	err = streamCall(d, "/protos.Deliver/DeliverFiltered", noopStream)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.NoError(t, unaryCall(d, "/discovery.Discovery/Discover", noopUnary))
+
+	// new commits are suspended
+	committed := make(chan struct{})
+	go func() {
+		d.EnterCommit()
+		close(committed)
+		d.ExitCommit()
+	}()
+
+	d.ExitCommit()
+	close(release)
+	assert.Eventually(t, func() bool { return d.Status().ReadyToStop }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, drain.Status{State: drain.Drained, ReadyToStop: true}, d.Status())
+	assert.Equal(t, []string{"first", "second"}, flushed)
+
+	select {
+	case <-committed:
+		assert.Fail(t, "commit should have been suspended")
+	default:
+	}
+
+	// resuming releases the suspended commits
+	d.Resume()
+	select {
+	case <-committed:
+	case <-time.After(time.Second):
+		assert.Fail(t, "commit should have been resumed")
+	}
+	assert.NoError(t, unaryCall(d, "/protos.Endorser/ProcessProposal", noopUnary))
+	assert.Equal(t, drain.Status{State: drain.Serving}, d.Status())
+}
+
+func TestDrainResumeWhileDraining(t *testing.T) {
+	d := newDrainer()
+	d.EnterCommit()
+	d.Drain()
+	d.Drain()
+	assert.Equal(t, drain.Draining, d.Status().State)
+
+	d.Resume()
+	d.ExitCommit()
+	assert.Never(t, func() bool { return d.Status().State != drain.Serving }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestDrainFlushFailure(t *testing.T) {
+	d := newDrainer(drain.Flusher{Name: "broken", Flush: func() error { return errors.New("disk full") }})
+	d.Drain()
+	assert.Eventually(t, func() bool { return d.Status().Error != "" }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, drain.Status{State: drain.Draining, Error: "failed to flush broken: disk full"}, d.Status())
+}
+
+func TestHandler(t *testing.T) {
+	d := newDrainer()
+	handler := drain.NewHandler(d)
+
+	serve := func(method string) (int, drain.Status) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(method, "/drain", nil))
+		var s drain.Status
+		json.NewDecoder(resp.Body).Decode(&s)
+		assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+		return resp.Code, s
+	}
+
+	code, s := serve(http.MethodGet)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, drain.Serving, s.State)
+
+	code, _ = serve(http.MethodPut)
+	assert.Equal(t, http.StatusAccepted, code)
+	require.Eventually(t, func() bool {
+		_, s := serve(http.MethodGet)
+		return s.ReadyToStop
+	}, time.Second, 10*time.Millisecond)
+
+	code, s = serve(http.MethodDelete)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, drain.Serving, s.State)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/drain", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: POST"}`, resp.Body.String())
+}
@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package drain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler exposes the drain mode of the peer through the operations
+// endpoint. PUT starts draining the peer, DELETE resumes it, and GET reports
+// the drain status.
+type Handler struct {
+	Drainer *Drainer
+}
+
+// NewHandler returns a Handler for the given Drainer.
+func NewHandler(d *Drainer) *Handler {
+	return &Handler{Drainer: d}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPut:
+		h.Drainer.Drain()
+		h.sendResponse(resp, http.StatusAccepted, h.Drainer.Status())
+	case http.MethodDelete:
+		h.Drainer.Resume()
+		h.sendResponse(resp, http.StatusOK, h.Drainer.Status())
+	case http.MethodGet:
+		h.sendResponse(resp, http.StatusOK, h.Drainer.Status())
+	default:
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+	}
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
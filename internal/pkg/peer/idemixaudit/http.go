@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemixaudit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/peer/idemixaudit"
+)
+
+var logger = flogging.MustGetLogger("peer.idemixaudit")
+
+// TrailSource provides the accesses of the idemix audit trail.
+type TrailSource interface {
+	Accesses(channelID string, since uint64) ([]idemixaudit.Access, error)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// TrailHandler exposes the idemix audit trail through the operations
+// endpoint. GET returns the audit accesses whose sequence is greater than or
+// equal to the since query parameter, for the channel given by the channel
+// query parameter or for all the channels when none is given. The hash chain
+// of the trail is verified before any access is returned.
+type TrailHandler struct {
+	Source TrailSource
+}
+
+// NewTrailHandler returns a TrailHandler for the given TrailSource.
+func NewTrailHandler(s TrailSource) *TrailHandler {
+	return &TrailHandler{Source: s}
+}
+
+func (h *TrailHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+
+	var since uint64
+	if s := req.URL.Query().Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			sendResponse(resp, http.StatusBadRequest, &errorResponse{
+				Error: fmt.Sprintf("invalid since parameter: %s", s),
+			})
+			return
+		}
+	}
+
+	accesses, err := h.Source.Accesses(req.URL.Query().Get("channel"), since)
+	if err != nil {
+		logger.Errorw("failed to read the audit trail", "error", err)
+		sendResponse(resp, http.StatusInternalServerError, &errorResponse{
+			Error: fmt.Sprintf("failed to read the audit trail: %s", err),
+		})
+		return
+	}
+	sendResponse(resp, http.StatusOK, accesses)
+}
+
+func sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
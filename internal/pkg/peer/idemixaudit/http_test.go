@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemixaudit_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	coreidemixaudit "github.com/hyperledger/fabric/core/peer/idemixaudit"
+	"github.com/hyperledger/fabric/internal/pkg/peer/idemixaudit"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource struct {
+	channelID string
+	since     uint64
+	err       error
+}
+
+func (f *fakeSource) Accesses(channelID string, since uint64) ([]coreidemixaudit.Access, error) {
+	f.channelID, f.since = channelID, since
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []coreidemixaudit.Access{{
+		Sequence:  since,
+		Timestamp: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		ChannelID: "mychannel",
+		Requester: coreidemixaudit.Identity{MSPID: "Org1MSP", Fingerprint: "ab"},
+		TxIDs:     []string{"tx1"},
+		Granted:   true,
+		Hash:      "cd",
+		Signer:    []byte("peer"),
+		Signature: []byte("signature"),
+	}}, nil
+}
+
+func TestTrailHandler(t *testing.T) {
+	source := &fakeSource{}
+	handler := idemixaudit.NewTrailHandler(source)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/idemixaudit/trail?channel=mychannel&since=4", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "mychannel", source.channelID)
+	assert.Equal(t, uint64(4), source.since)
+	assert.JSONEq(t, `[{"sequence":4,"timestamp":"2020-01-02T03:04:05Z","channel_id":"mychannel","requester":{"msp_id":"Org1MSP","fingerprint":"ab"},"tx_ids":["tx1"],"granted":true,"previous_hash":"","hash":"cd","signer":"cGVlcg==","signature":"c2lnbmF0dXJl"}]`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/idemixaudit/trail?since=last", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid since parameter: last"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/idemixaudit/trail", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: POST"}`, resp.Body.String())
+
+	source.err = errors.New("hash chain is broken")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/idemixaudit/trail", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"error":"failed to read the audit trail: hash chain is broken"}`, resp.Body.String())
+}
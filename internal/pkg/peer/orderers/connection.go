@@ -12,6 +12,7 @@ import (
 	"github.com/cetcxinlian/cryptogm/x509"
 	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
@@ -19,12 +20,33 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// defaultFailureThreshold is the number of consecutive failures after
+	// which an endpoint is no longer handed out.
+	defaultFailureThreshold = 3
+	// defaultCooldown is the duration for which an endpoint is no longer
+	// handed out once it reached the failure threshold.
+	defaultCooldown = 10 * time.Second
+)
+
 type ConnectionSource struct {
 	mutex              sync.RWMutex
 	allEndpoints       []*Endpoint
 	orgToEndpointsHash map[string][]byte
 	logger             *flogging.FabricLogger
 	overrides          map[string]*Endpoint
+
+	// health tracks the consecutive failures of the endpoints by address,
+	// and acts as a circuit breaker for the endpoints which keep failing.
+	health           map[string]*endpointHealth
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+}
+
+type endpointHealth struct {
+	failures  int
+	openUntil time.Time
 }
 
 type Endpoint struct {
@@ -43,16 +65,92 @@ func NewConnectionSource(logger *flogging.FabricLogger, overrides map[string]*En
 		orgToEndpointsHash: map[string][]byte{},
 		logger:             logger,
 		overrides:          overrides,
+		health:             map[string]*endpointHealth{},
+		failureThreshold:   defaultFailureThreshold,
+		cooldown:           defaultCooldown,
+		now:                time.Now,
 	}
 }
 
+// RandomEndpoint returns an endpoint picked at random among the healthy
+// ones, so that the load spreads across the ordering service nodes. When no
+// endpoint is healthy, it is picked among all the endpoints.
 func (cs *ConnectionSource) RandomEndpoint() (*Endpoint, error) {
 	cs.mutex.RLock()
 	defer cs.mutex.RUnlock()
 	if len(cs.allEndpoints) == 0 {
 		return nil, errors.Errorf("no endpoints currently defined")
 	}
-	return cs.allEndpoints[rand.Intn(len(cs.allEndpoints))], nil
+
+	now := cs.now()
+	var healthy []*Endpoint
+	for _, endpoint := range cs.allEndpoints {
+		if cs.healthyWhileLocked(endpoint.Address, now) {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	if len(healthy) == 0 {
+		cs.logger.Debugf("No healthy orderer endpoint, picking among all the %d endpoints", len(cs.allEndpoints))
+		healthy = cs.allEndpoints
+	}
+
+	return healthy[rand.Intn(len(healthy))], nil
+}
+
+// healthyWhileLocked reports whether the endpoint is below the failure
+// threshold, or has cooled down since it reached it, in which case it is
+// handed out again until its next failure.
+func (cs *ConnectionSource) healthyWhileLocked(address string, now time.Time) bool {
+	h, ok := cs.health[address]
+	return !ok || h.failures < cs.failureThreshold || !now.Before(h.openUntil)
+}
+
+// pruneHealthWhileLocked forgets the health of the endpoints which are no
+// longer defined.
+func (cs *ConnectionSource) pruneHealthWhileLocked() {
+	addresses := map[string]struct{}{}
+	for _, endpoint := range cs.allEndpoints {
+		addresses[endpoint.Address] = struct{}{}
+	}
+	for address := range cs.health {
+		if _, ok := addresses[address]; !ok {
+			delete(cs.health, address)
+		}
+	}
+}
+
+// ReportFailure records a failure to connect to, or to receive from, the
+// endpoint with the given address.
+func (cs *ConnectionSource) ReportFailure(address string) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	h, ok := cs.health[address]
+	if !ok {
+		h = &endpointHealth{}
+		cs.health[address] = h
+	}
+	h.failures++
+	if h.failures >= cs.failureThreshold {
+		h.openUntil = cs.now().Add(cs.cooldown)
+		cs.logger.Warningf("Orderer endpoint %s failed %d consecutive times, avoiding it for %s", address, h.failures, cs.cooldown)
+	}
+}
+
+// ReportSuccess records that the endpoint with the given address delivered
+// successfully, which resets its failures.
+func (cs *ConnectionSource) ReportSuccess(address string) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	h, ok := cs.health[address]
+	if !ok {
+		return
+	}
+	if h.failures >= cs.failureThreshold {
+		cs.logger.Infof("Orderer endpoint %s recovered", address)
+	}
+	delete(cs.health, address)
 }
 
 func (cs *ConnectionSource) Update(globalAddrs []string, orgs map[string]OrdererOrg) {
@@ -147,6 +245,7 @@ func (cs *ConnectionSource) Update(globalAddrs []string, orgs map[string]Orderer
 	}
 
 	cs.allEndpoints = nil
+	defer cs.pruneHealthWhileLocked()
 
 	globalCertPool := x509.NewCertPool()
 
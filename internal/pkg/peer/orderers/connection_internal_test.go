@@ -6,8 +6,16 @@ SPDX-License-Identifier: Apache-2.0
 
 package orderers
 
+import "time"
+
 func (cs *ConnectionSource) Endpoints() []*Endpoint {
 	cs.mutex.RLock()
 	defer cs.mutex.RUnlock()
 	return cs.allEndpoints
 }
+
+func (cs *ConnectionSource) SetNow(now func() time.Time) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.now = now
+}
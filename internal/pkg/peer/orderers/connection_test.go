@@ -8,13 +8,14 @@ package orderers_test
 
 import (
 	"bytes"
-	"crypto/x509"
 	"io/ioutil"
 	"sort"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/cetcxinlian/cryptogm/x509"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/internal/pkg/peer/orderers"
 )
@@ -137,6 +138,85 @@ var _ = Describe("Connection", func() {
 		}
 	})
 
+	Describe("endpoint health", func() {
+		var now time.Time
+
+		pickedAddresses := func() map[string]bool {
+			picked := map[string]bool{}
+			for i := 0; i < 200; i++ {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				picked[endpoint.Address] = true
+			}
+			return picked
+		}
+
+		BeforeEach(func() {
+			now = time.Now()
+			cs.SetNow(func() time.Time { return now })
+		})
+
+		It("picks among all the endpoints", func() {
+			Expect(pickedAddresses()).To(HaveLen(4))
+		})
+
+		It("keeps picking the endpoints below the failure threshold", func() {
+			cs.ReportFailure("org1-address1")
+			cs.ReportFailure("org1-address1")
+			Expect(pickedAddresses()).To(HaveKey("org1-address1"))
+		})
+
+		When("an endpoint reaches the failure threshold", func() {
+			BeforeEach(func() {
+				for i := 0; i < 3; i++ {
+					cs.ReportFailure("org1-address1")
+				}
+			})
+
+			It("no longer picks it", func() {
+				picked := pickedAddresses()
+				Expect(picked).To(HaveLen(3))
+				Expect(picked).NotTo(HaveKey("org1-address1"))
+			})
+
+			It("picks it again once it cooled down", func() {
+				now = now.Add(10 * time.Second)
+				Expect(pickedAddresses()).To(HaveKey("org1-address1"))
+
+				cs.ReportFailure("org1-address1")
+				Expect(pickedAddresses()).NotTo(HaveKey("org1-address1"))
+			})
+
+			It("picks it again once it succeeded", func() {
+				cs.ReportSuccess("org1-address1")
+				Expect(pickedAddresses()).To(HaveKey("org1-address1"))
+
+				cs.ReportFailure("org1-address1")
+				Expect(pickedAddresses()).To(HaveKey("org1-address1"))
+			})
+
+			It("forgets its health once it is removed from the config", func() {
+				cs.Update(nil, map[string]orderers.OrdererOrg{"org2": org2})
+				cs.Update(nil, map[string]orderers.OrdererOrg{"org1": org1, "org2": org2})
+				Expect(pickedAddresses()).To(HaveKey("org1-address1"))
+			})
+		})
+
+		When("all the endpoints reached the failure threshold", func() {
+			BeforeEach(func() {
+				for _, endpoint := range endpoints {
+					for i := 0; i < 3; i++ {
+						cs.ReportFailure(endpoint.Address)
+					}
+				}
+			})
+
+			It("picks among all the endpoints", func() {
+				Expect(pickedAddresses()).To(HaveLen(4))
+			})
+		})
+	})
+
 	When("an update does not modify the endpoint set", func() {
 		BeforeEach(func() {
 			cs.Update(nil, map[string]orderers.OrdererOrg{
@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package proposalstream
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var logger = flogging.MustGetLogger("proposalstream")
+
+// ChunkingServer implements the streaming endorser service on top of an
+// Endorser service. The proposal response produced by the Endorser is
+// serialized and sent in chunks of ChunkSize bytes, so that its size is no
+// longer bound by the maximal gRPC message size.
+//
+// The chunking is done by the transport only: the Endorser still produces the
+// whole proposal response, and it is marshaled in memory before its first
+// chunk is sent. The memory a proposal uses on the peer is therefore not
+// reduced, and the client holds the whole response as well while it
+// reassembles it.
+type ChunkingServer struct {
+	Endorser  pb.EndorserServer
+	ChunkSize int
+}
+
+// ProcessProposalStream processes the signed proposal with the Endorser and
+// streams the resulting proposal response.
+func (s *ChunkingServer) ProcessProposalStream(signedProp *pb.SignedProposal, stream ProposalResponseSender) error {
+	resp, err := s.Endorser.ProcessProposal(stream.Context(), signedProp)
+	if err != nil {
+		return err
+	}
+
+	raw, err := proto.Marshal(resp)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal proposal response")
+	}
+
+	chunkSize := s.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	for offset := 0; ; offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(raw) {
+			end = len(raw)
+		}
+		err := stream.Send(&pb.ProposalResponse{
+			Response: &pb.Response{
+				Status:  ChunkStatus,
+				Payload: raw[offset:end],
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to send proposal response chunk")
+		}
+		if end == len(raw) {
+			return nil
+		}
+	}
+}
+
+// ReceiveProposalResponse reassembles the proposal response streamed by the
+// streaming endorser service. It fails once the chunks received exceed
+// maxSize bytes, or DefaultMaxResponseSize when maxSize is not positive.
+func ReceiveProposalResponse(stream ProposalResponseReceiver, maxSize int) (*pb.ProposalResponse, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+
+	var raw bytes.Buffer
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if chunk.Response == nil || chunk.Response.Status != ChunkStatus {
+			return nil, errors.New("received a proposal response which is not a chunk")
+		}
+		if raw.Len()+len(chunk.Response.Payload) > maxSize {
+			return nil, errors.Errorf("streamed proposal response exceeds the maximum size of %d bytes", maxSize)
+		}
+		raw.Write(chunk.Response.Payload)
+	}
+
+	resp := &pb.ProposalResponse{}
+	if err := proto.Unmarshal(raw.Bytes(), resp); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal streamed proposal response")
+	}
+	return resp, nil
+}
+
+// EndorserClient is an Endorser client which receives proposal responses
+// from the streaming endorser service. Peers which do not provide the
+// streaming endorser service are detected on the first proposal, after which
+// the client falls back to the Endorser service.
+type EndorserClient struct {
+	Endorser  pb.EndorserClient
+	Streaming Client
+	// MaxResponseSize is the maximum size, in bytes, of a reassembled
+	// proposal response. DefaultMaxResponseSize is used when it is zero.
+	MaxResponseSize int
+
+	mutex       sync.Mutex
+	unsupported bool
+}
+
+// NewEndorserClient returns an EndorserClient using the given connection,
+// which reassembles proposal responses of at most maxResponseSize bytes.
+func NewEndorserClient(cc *grpc.ClientConn, maxResponseSize int) *EndorserClient {
+	return &EndorserClient{
+		Endorser:        pb.NewEndorserClient(cc),
+		Streaming:       NewClient(cc),
+		MaxResponseSize: maxResponseSize,
+	}
+}
+
+// ProcessProposal sends the signed proposal and returns the proposal response.
+func (ec *EndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	if ec.streamingSupported() {
+		resp, err := ec.processProposalStream(ctx, in, opts...)
+		if status.Code(err) != codes.Unimplemented {
+			return resp, err
+		}
+		logger.Debugf("Peer does not support streaming proposal responses, falling back to unary responses: %s", err)
+		ec.mutex.Lock()
+		ec.unsupported = true
+		ec.mutex.Unlock()
+	}
+	return ec.Endorser.ProcessProposal(ctx, in, opts...)
+}
+
+func (ec *EndorserClient) processProposalStream(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	// the stream is canceled when the response is not received in full
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := ec.Streaming.ProcessProposalStream(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ReceiveProposalResponse(stream, ec.MaxResponseSize)
+}
+
+func (ec *EndorserClient) streamingSupported() bool {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	return !ec.unsupported
+}
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package proposalstream
+
+import (
+	"context"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"google.golang.org/grpc"
+)
+
+const (
+	// ServiceName is the name of the gRPC service streaming proposal responses.
+	ServiceName = "protos.StreamingEndorser"
+
+	// ChunkStatus is the status of the responses carrying the chunks of a
+	// streamed proposal response.
+	ChunkStatus = 206
+
+	// DefaultChunkSize is the size of the chunks used when none is configured.
+	DefaultChunkSize = 1024 * 1024
+
+	// DefaultMaxResponseSize is the maximum size of a reassembled proposal
+	// response used when none is configured.
+	DefaultMaxResponseSize = 100 * 1024 * 1024
+)
+
+// ProposalResponseSender is the server side of a proposal response stream.
+type ProposalResponseSender interface {
+	Send(*pb.ProposalResponse) error
+	grpc.ServerStream
+}
+
+// ProposalResponseReceiver is the client side of a proposal response stream.
+type ProposalResponseReceiver interface {
+	Recv() (*pb.ProposalResponse, error)
+	grpc.ClientStream
+}
+
+// Server is the server API of the streaming endorser service.
+type Server interface {
+	// ProcessProposalStream processes a signed proposal and streams the
+	// resulting proposal response in chunks.
+	ProcessProposalStream(*pb.SignedProposal, ProposalResponseSender) error
+}
+
+// Client is the client API of the streaming endorser service.
+type Client interface {
+	// ProcessProposalStream sends a signed proposal and returns the stream
+	// of chunks of the resulting proposal response.
+	ProcessProposalStream(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (ProposalResponseReceiver, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Server)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ProcessProposalStream",
+			Handler:       processProposalStreamHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+// RegisterServer registers the streaming endorser service with the gRPC server.
+func RegisterServer(s *grpc.Server, srv Server) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+func processProposalStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	signedProp := &pb.SignedProposal{}
+	if err := stream.RecvMsg(signedProp); err != nil {
+		return err
+	}
+	return srv.(Server).ProcessProposalStream(signedProp, &proposalResponseSender{stream})
+}
+
+type proposalResponseSender struct {
+	grpc.ServerStream
+}
+
+func (s *proposalResponseSender) Send(m *pb.ProposalResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+type client struct {
+	cc *grpc.ClientConn
+}
+
+// NewClient returns a client of the streaming endorser service.
+func NewClient(cc *grpc.ClientConn) Client {
+	return &client{cc: cc}
+}
+
+func (c *client) ProcessProposalStream(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (ProposalResponseReceiver, error) {
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[0], "/"+ServiceName+"/ProcessProposalStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	r := &proposalResponseReceiver{stream}
+	if err := r.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := r.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type proposalResponseReceiver struct {
+	grpc.ClientStream
+}
+
+func (r *proposalResponseReceiver) Recv() (*pb.ProposalResponse, error) {
+	m := &pb.ProposalResponse{}
+	if err := r.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package proposalstream_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/internal/pkg/peer/proposalstream"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type endorser struct {
+	response *pb.ProposalResponse
+	err      error
+	calls    int
+}
+
+func (e *endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	e.calls++
+	return e.response, e.err
+}
+
+func startServer(t *testing.T, e *endorser, streaming *proposalstream.ChunkingServer) (*grpc.ClientConn, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterEndorserServer(server, e)
+	if streaming != nil {
+		proposalstream.RegisterServer(server, streaming)
+	}
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+
+	return conn, func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func largeResponse(size int) *pb.ProposalResponse {
+	return &pb.ProposalResponse{
+		Version: 1,
+		Response: &pb.Response{
+			Status:  200,
+			Payload: bytes.Repeat([]byte("a"), size),
+		},
+		Payload: []byte("payload"),
+		Endorsement: &pb.Endorsement{
+			Endorser:  []byte("endorser"),
+			Signature: []byte("signature"),
+		},
+	}
+}
+
+func TestStreamedProposalResponse(t *testing.T) {
+	e := &endorser{response: largeResponse(10000)}
+	conn, stop := startServer(t, &endorser{}, &proposalstream.ChunkingServer{Endorser: e, ChunkSize: 1024})
+	defer stop()
+
+	streamClient := proposalstream.NewClient(conn)
+	stream, err := streamClient.ProcessProposalStream(context.Background(), &pb.SignedProposal{})
+	require.NoError(t, err)
+
+	var chunks int
+	var raw []byte
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		chunks++
+		assert.Equal(t, int32(proposalstream.ChunkStatus), chunk.Response.Status)
+		assert.True(t, len(chunk.Response.Payload) <= 1024)
+		raw = append(raw, chunk.Response.Payload...)
+	}
+
+	expected, err := proto.Marshal(e.response)
+	require.NoError(t, err)
+	assert.Equal(t, expected, raw)
+	assert.Equal(t, (len(expected)+1023)/1024, chunks)
+}
+
+func TestEndorserClient(t *testing.T) {
+	response := largeResponse(5000)
+
+	t.Run("streaming", func(t *testing.T) {
+		unary := &endorser{response: &pb.ProposalResponse{}}
+		streamed := &endorser{response: response}
+		conn, stop := startServer(t, unary, &proposalstream.ChunkingServer{Endorser: streamed, ChunkSize: 100})
+		defer stop()
+
+		client := proposalstream.NewEndorserClient(conn, 0)
+		resp, err := client.ProcessProposal(context.Background(), &pb.SignedProposal{})
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(response, resp))
+		assert.Equal(t, 1, streamed.calls)
+		assert.Equal(t, 0, unary.calls)
+	})
+
+	t.Run("default chunk size", func(t *testing.T) {
+		streamed := &endorser{response: response}
+		conn, stop := startServer(t, &endorser{}, &proposalstream.ChunkingServer{Endorser: streamed})
+		defer stop()
+
+		resp, err := proposalstream.NewEndorserClient(conn, 0).ProcessProposal(context.Background(), &pb.SignedProposal{})
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(response, resp))
+	})
+
+	t.Run("response too large", func(t *testing.T) {
+		streamed := &endorser{response: response}
+		conn, stop := startServer(t, &endorser{}, &proposalstream.ChunkingServer{Endorser: streamed, ChunkSize: 100})
+		defer stop()
+
+		_, err := proposalstream.NewEndorserClient(conn, 1000).ProcessProposal(context.Background(), &pb.SignedProposal{})
+		assert.EqualError(t, err, "streamed proposal response exceeds the maximum size of 1000 bytes")
+	})
+
+	t.Run("endorser error", func(t *testing.T) {
+		unary := &endorser{}
+		streamed := &endorser{err: errors.New("access denied")}
+		conn, stop := startServer(t, unary, &proposalstream.ChunkingServer{Endorser: streamed})
+		defer stop()
+
+		_, err := proposalstream.NewEndorserClient(conn, 0).ProcessProposal(context.Background(), &pb.SignedProposal{})
+		assert.Equal(t, codes.Unknown, status.Code(err))
+		assert.Contains(t, err.Error(), "access denied")
+		assert.Equal(t, 0, unary.calls)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		unary := &endorser{response: response}
+		conn, stop := startServer(t, unary, nil)
+		defer stop()
+
+		client := proposalstream.NewEndorserClient(conn, 0)
+		for i := 0; i < 2; i++ {
+			resp, err := client.ProcessProposal(context.Background(), &pb.SignedProposal{})
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(response, resp))
+		}
+		assert.Equal(t, 2, unary.calls)
+	})
+}
+
+type chunkStream struct {
+	grpc.ClientStream
+	chunks []*pb.ProposalResponse
+}
+
+func (cs *chunkStream) Recv() (*pb.ProposalResponse, error) {
+	if len(cs.chunks) == 0 {
+		return nil, errors.New("EOF expected")
+	}
+	chunk := cs.chunks[0]
+	cs.chunks = cs.chunks[1:]
+	return chunk, nil
+}
+
+func TestReceiveProposalResponseNotAChunk(t *testing.T) {
+	_, err := proposalstream.ReceiveProposalResponse(&chunkStream{
+		chunks: []*pb.ProposalResponse{{Response: &pb.Response{Status: 200}}},
+	}, 0)
+	assert.EqualError(t, err, "received a proposal response which is not a chunk")
+}
+
+func TestReceiveProposalResponseTooLarge(t *testing.T) {
+	chunk := &pb.ProposalResponse{Response: &pb.Response{Status: proposalstream.ChunkStatus, Payload: make([]byte, 10)}}
+	_, err := proposalstream.ReceiveProposalResponse(&chunkStream{
+		chunks: []*pb.ProposalResponse{chunk, chunk, chunk},
+	}, 25)
+	assert.EqualError(t, err, "streamed proposal response exceeds the maximum size of 25 bytes")
+}
@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler exposes the status report of the peer through the operations
+// endpoint. GET returns the status of the channels given by the channel query
+// parameters, or of all the channels joined by the peer when none is given.
+type Handler struct {
+	Reporter *Reporter
+}
+
+// NewHandler returns a Handler for the given Reporter.
+func NewHandler(r *Reporter) *Handler {
+	return &Handler{Reporter: r}
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+
+	channelIDs := req.URL.Query()["channel"]
+	h.sendResponse(resp, http.StatusOK, h.Reporter.Report(req.Context(), channelIDs...))
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/pkg/peer/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	handler := status.NewHandler(newReporter())
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/status?channel=otherchannel", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+	delete(report, "timestamp")
+	reportJSON, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"peer_id": "peer0",
+		"msp_id": "SampleOrg",
+		"state_database": {"type": "goleveldb", "healthy": true},
+		"chaincodes": ["mycc:1a2b"],
+		"gossip_peers": 4,
+		"channels": [
+			{
+				"channel_id": "otherchannel",
+				"height": 3,
+				"pvtdata_reconciliation": {"missing_blocks": 0, "missing_transactions": 0},
+				"gossip_peers": 0
+			}
+		]
+	}`, string(reportJSON))
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/status", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: POST"}`, resp.Body.String())
+}
@@ -0,0 +1,248 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
+	gossipcommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+)
+
+var logger = flogging.MustGetLogger("peer.status")
+
+// missingPvtDataBlocks bounds the blocks inspected to compute the private data
+// reconciliation backlog of a channel, so that a large backlog does not make
+// the status report expensive.
+const missingPvtDataBlocks = 1000
+
+// Ledger is the ledger of a channel.
+type Ledger interface {
+	GetBlockchainInfo() (*common.BlockchainInfo, error)
+	GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error)
+}
+
+// Chaincodes lists the chaincodes whose runtime is connected to the peer.
+type Chaincodes interface {
+	Running() []string
+}
+
+// Membership provides the alive peers known to gossip.
+type Membership interface {
+	Peers() []discovery.NetworkMember
+	PeersOfChannel(gossipcommon.ChannelID) []discovery.NetworkMember
+}
+
+// Report is the status of the peer.
+type Report struct {
+	PeerID        string     `json:"peer_id"`
+	MSPID         string     `json:"msp_id"`
+	Timestamp     time.Time  `json:"timestamp"`
+	StateDatabase StateDB    `json:"state_database"`
+	Chaincodes    []string   `json:"chaincodes"`
+	GossipPeers   int        `json:"gossip_peers"`
+	Channels      []*Channel `json:"channels"`
+}
+
+// StateDB is the status of the state database shared by the channels.
+type StateDB struct {
+	Type    string `json:"type"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Channel is the status of a channel joined by the peer.
+type Channel struct {
+	ChannelID string `json:"channel_id"`
+	Height    uint64 `json:"height"`
+	// LastCommit is the time the last block was committed since the peer
+	// started, nil if no block was committed since.
+	LastCommit            *time.Time             `json:"last_commit,omitempty"`
+	PvtDataReconciliation *PvtDataReconciliation `json:"pvtdata_reconciliation,omitempty"`
+	GossipPeers           int                    `json:"gossip_peers"`
+	Error                 string                 `json:"error,omitempty"`
+}
+
+// PvtDataReconciliation is the backlog of the private data reconciler, the
+// private data of the eligible collections still missing. Only the most
+// recent blocks missing private data are counted.
+type PvtDataReconciliation struct {
+	MissingBlocks       int `json:"missing_blocks"`
+	MissingTransactions int `json:"missing_transactions"`
+}
+
+// CommitTimes records the time of the last block committed to each channel.
+// It is registered as a ledger.CommitListener.
+type CommitTimes struct {
+	mutex sync.RWMutex
+	times map[string]time.Time
+}
+
+// NewCommitTimes returns an empty CommitTimes.
+func NewCommitTimes() *CommitTimes {
+	return &CommitTimes{times: map[string]time.Time{}}
+}
+
+// Name implements ledger.CommitListener
+func (c *CommitTimes) Name() string {
+	return "status"
+}
+
+// HandleCommittedBlock implements ledger.CommitListener
+func (c *CommitTimes) HandleCommittedBlock(event *ledger.CommitEvent) error {
+	c.mutex.Lock()
+	c.times[event.LedgerID] = time.Now().UTC()
+	c.mutex.Unlock()
+	return nil
+}
+
+// Get returns the time of the last block committed to the channel.
+func (c *CommitTimes) Get(channelID string) (time.Time, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	t, ok := c.times[channelID]
+	return t, ok
+}
+
+// HealthCheckRegistry forwards the registration of health checkers to a
+// registry, keeping the checkers so that the status of the components can be
+// reported.
+type HealthCheckRegistry struct {
+	Registry interface {
+		RegisterChecker(string, healthz.HealthChecker) error
+	}
+
+	mutex    sync.Mutex
+	checkers map[string]healthz.HealthChecker
+}
+
+// RegisterChecker implements ledger.HealthCheckRegistry
+func (h *HealthCheckRegistry) RegisterChecker(component string, checker healthz.HealthChecker) error {
+	if err := h.Registry.RegisterChecker(component, checker); err != nil {
+		return err
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.checkers == nil {
+		h.checkers = map[string]healthz.HealthChecker{}
+	}
+	h.checkers[component] = checker
+	return nil
+}
+
+// Checker returns the checker registered for the component, nil if none is.
+func (h *HealthCheckRegistry) Checker(component string) healthz.HealthChecker {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.checkers[component]
+}
+
+// Reporter builds the status report of the peer.
+type Reporter struct {
+	PeerID string
+	MSPID  string
+	// StateDatabase is the type of the state database, goleveldb or CouchDB.
+	StateDatabase string
+	// StateDBChecker checks the health of the state database, nil when the
+	// state database is embedded in the peer.
+	StateDBChecker healthz.HealthChecker
+	ChannelIDs     func() []string
+	Ledger         func(channelID string) Ledger
+	CommitTimes    *CommitTimes
+	Chaincodes     Chaincodes
+	Gossip         Membership
+}
+
+// Report returns the status of the peer and of the given channels, or of all
+// the channels joined by the peer if none is given. The errors met reading
+// the status of a channel are reported in the channel rather than failing the
+// whole report.
+func (r *Reporter) Report(ctx context.Context, channelIDs ...string) *Report {
+	report := &Report{
+		PeerID:    r.PeerID,
+		MSPID:     r.MSPID,
+		Timestamp: time.Now().UTC(),
+		StateDatabase: StateDB{
+			Type:    r.StateDatabase,
+			Healthy: true,
+		},
+		Chaincodes: []string{},
+		Channels:   []*Channel{},
+	}
+	if r.StateDBChecker != nil {
+		if err := r.StateDBChecker.HealthCheck(ctx); err != nil {
+			report.StateDatabase.Healthy = false
+			report.StateDatabase.Error = err.Error()
+		}
+	}
+	if r.Chaincodes != nil {
+		if running := r.Chaincodes.Running(); running != nil {
+			report.Chaincodes = running
+		}
+	}
+	if r.Gossip != nil {
+		report.GossipPeers = len(r.Gossip.Peers())
+	}
+
+	if len(channelIDs) == 0 {
+		channelIDs = r.ChannelIDs()
+	}
+	sort.Strings(channelIDs)
+	for _, channelID := range channelIDs {
+		report.Channels = append(report.Channels, r.channel(channelID))
+	}
+	return report
+}
+
+func (r *Reporter) channel(channelID string) *Channel {
+	c := &Channel{ChannelID: channelID}
+	if r.Gossip != nil {
+		c.GossipPeers = len(r.Gossip.PeersOfChannel(gossipcommon.ChannelID(channelID)))
+	}
+	if r.CommitTimes != nil {
+		if t, ok := r.CommitTimes.Get(channelID); ok {
+			c.LastCommit = &t
+		}
+	}
+
+	l := r.Ledger(channelID)
+	if l == nil {
+		c.Error = "channel not found"
+		return c
+	}
+	info, err := l.GetBlockchainInfo()
+	if err != nil {
+		logger.Warningf("Failed getting the height of channel [%s]: %s", channelID, err)
+		c.Error = err.Error()
+		return c
+	}
+	c.Height = info.Height
+
+	tracker, err := l.GetMissingPvtDataTracker()
+	if err == nil {
+		var missing ledger.MissingPvtDataInfo
+		missing, err = tracker.GetMissingPvtDataInfoForMostRecentBlocks(missingPvtDataBlocks)
+		if err == nil {
+			c.PvtDataReconciliation = &PvtDataReconciliation{MissingBlocks: len(missing)}
+			for _, blockInfo := range missing {
+				c.PvtDataReconciliation.MissingTransactions += len(blockInfo)
+			}
+		}
+	}
+	if err != nil {
+		logger.Warningf("Failed getting the missing private data of channel [%s]: %s", channelID, err)
+		c.Error = err.Error()
+	}
+	return c
+}
@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/core/ledger"
+	gossipcommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/internal/pkg/peer/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedger struct {
+	height  uint64
+	missing ledger.MissingPvtDataInfo
+	err     error
+}
+
+func (f *fakeLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &common.BlockchainInfo{Height: f.height}, nil
+}
+
+func (f *fakeLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, error) {
+	return f, nil
+}
+
+func (f *fakeLedger) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	return f.missing, nil
+}
+
+type fakeChaincodes []string
+
+func (f fakeChaincodes) Running() []string {
+	return f
+}
+
+type fakeGossip map[string]int
+
+func (f fakeGossip) Peers() []discovery.NetworkMember {
+	return make([]discovery.NetworkMember, f[""])
+}
+
+func (f fakeGossip) PeersOfChannel(channelID gossipcommon.ChannelID) []discovery.NetworkMember {
+	return make([]discovery.NetworkMember, f[string(channelID)])
+}
+
+type fakeChecker struct {
+	err error
+}
+
+func (f *fakeChecker) HealthCheck(context.Context) error {
+	return f.err
+}
+
+type fakeRegistry struct {
+	components []string
+}
+
+func (f *fakeRegistry) RegisterChecker(component string, checker healthz.HealthChecker) error {
+	f.components = append(f.components, component)
+	return nil
+}
+
+func newReporter() *status.Reporter {
+	missing := ledger.MissingPvtDataInfo{}
+	missing.Add(5, 0, "cc", "coll")
+	missing.Add(5, 2, "cc", "coll")
+	missing.Add(7, 1, "cc", "coll")
+	ledgers := map[string]status.Ledger{
+		"mychannel":    &fakeLedger{height: 10, missing: missing},
+		"otherchannel": &fakeLedger{height: 3},
+		"badchannel":   &fakeLedger{err: errors.New("ledger is closed")},
+	}
+	return &status.Reporter{
+		PeerID:        "peer0",
+		MSPID:         "SampleOrg",
+		StateDatabase: "goleveldb",
+		ChannelIDs: func() []string {
+			return []string{"otherchannel", "mychannel", "badchannel"}
+		},
+		Ledger: func(channelID string) status.Ledger {
+			return ledgers[channelID]
+		},
+		CommitTimes: status.NewCommitTimes(),
+		Chaincodes:  fakeChaincodes{"mycc:1a2b"},
+		Gossip:      fakeGossip{"": 4, "mychannel": 2},
+	}
+}
+
+func TestReport(t *testing.T) {
+	reporter := newReporter()
+	require.NoError(t, reporter.CommitTimes.HandleCommittedBlock(&ledger.CommitEvent{LedgerID: "mychannel"}))
+	commitTime, ok := reporter.CommitTimes.Get("mychannel")
+	require.True(t, ok)
+
+	report := reporter.Report(context.Background())
+	assert.Equal(t, "peer0", report.PeerID)
+	assert.Equal(t, "SampleOrg", report.MSPID)
+	assert.Equal(t, status.StateDB{Type: "goleveldb", Healthy: true}, report.StateDatabase)
+	assert.Equal(t, []string{"mycc:1a2b"}, report.Chaincodes)
+	assert.Equal(t, 4, report.GossipPeers)
+	assert.Equal(t, []*status.Channel{
+		{
+			ChannelID: "badchannel",
+			Error:     "ledger is closed",
+		},
+		{
+			ChannelID:             "mychannel",
+			Height:                10,
+			LastCommit:            &commitTime,
+			PvtDataReconciliation: &status.PvtDataReconciliation{MissingBlocks: 2, MissingTransactions: 3},
+			GossipPeers:           2,
+		},
+		{
+			ChannelID:             "otherchannel",
+			Height:                3,
+			PvtDataReconciliation: &status.PvtDataReconciliation{},
+		},
+	}, report.Channels)
+
+	report = reporter.Report(context.Background(), "otherchannel", "unknown")
+	assert.Equal(t, []*status.Channel{
+		{
+			ChannelID:             "otherchannel",
+			Height:                3,
+			PvtDataReconciliation: &status.PvtDataReconciliation{},
+		},
+		{
+			ChannelID: "unknown",
+			Error:     "channel not found",
+		},
+	}, report.Channels)
+}
+
+func TestReportStateDBHealth(t *testing.T) {
+	reporter := newReporter()
+	reporter.StateDatabase = "CouchDB"
+
+	registry := &fakeRegistry{}
+	healthCheckRegistry := &status.HealthCheckRegistry{Registry: registry}
+	assert.Nil(t, healthCheckRegistry.Checker("couchdb"))
+	checker := &fakeChecker{}
+	require.NoError(t, healthCheckRegistry.RegisterChecker("couchdb", checker))
+	assert.Equal(t, []string{"couchdb"}, registry.components)
+	reporter.StateDBChecker = healthCheckRegistry.Checker("couchdb")
+
+	report := reporter.Report(context.Background())
+	assert.Equal(t, status.StateDB{Type: "CouchDB", Healthy: true}, report.StateDatabase)
+
+	checker.err = errors.New("couchdb is unreachable")
+	report = reporter.Report(context.Background())
+	assert.Equal(t, status.StateDB{Type: "CouchDB", Error: "couchdb is unreachable"}, report.StateDatabase)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package triggers
+
+import (
+	"encoding/pem"
+	"net/http"
+	"sort"
+
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// ChannelConfigSource provides the configuration of the channels joined by
+// the peer, nil for the channels it has not joined.
+type ChannelConfigSource interface {
+	GetChannelConfig(cid string) channelconfig.Resources
+}
+
+// ChannelReaders authorizes the clients that satisfy the Readers policy of
+// the application of the channel. A client is identified by the verified TLS
+// client certificate of its request, which must be a valid identity of one of
+// the MSPs of the channel.
+type ChannelReaders struct {
+	Channels ChannelConfigSource
+}
+
+// Authorize implements Authorizer.
+func (c *ChannelReaders) Authorize(req *http.Request, channelID string) error {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return errors.New("request has no verified TLS client certificate")
+	}
+	resources := c.Channels.GetChannelConfig(channelID)
+	if resources == nil {
+		return errors.Errorf("channel [%s] not found", channelID)
+	}
+
+	identity, err := clientIdentity(resources.MSPManager(), req.TLS.VerifiedChains[0][0].Raw)
+	if err != nil {
+		return err
+	}
+	policy, ok := resources.PolicyManager().GetPolicy(policies.ChannelApplicationReaders)
+	if !ok {
+		return errors.Errorf("policy [%s] not found", policies.ChannelApplicationReaders)
+	}
+	return policy.EvaluateIdentities([]msp.Identity{identity})
+}
+
+// clientIdentity returns the identity of the first MSP of the channel, in the
+// order of their IDs, that validates the client certificate.
+func clientIdentity(mspManager msp.MSPManager, cert []byte) (msp.Identity, error) {
+	msps, err := mspManager.GetMSPs()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get the MSPs of the channel")
+	}
+	mspIDs := make([]string, 0, len(msps))
+	for mspID := range msps {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+
+	idBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	for _, mspID := range mspIDs {
+		m := msps[mspID]
+		identity, err := m.DeserializeIdentity(protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: idBytes}))
+		if err != nil {
+			continue
+		}
+		if err := m.Validate(identity); err != nil {
+			continue
+		}
+		return identity, nil
+	}
+	return nil, errors.New("client certificate is not a valid identity of a member of the channel")
+}
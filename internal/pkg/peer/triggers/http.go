@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package triggers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/peer/triggers"
+)
+
+var logger = flogging.MustGetLogger("peer.triggers")
+
+// EventSource provides the firings of the triggers with an event action.
+type EventSource interface {
+	Events(channelID string, since uint64) []*triggers.Firing
+}
+
+// DerivedSource provides the derived keys written by the triggers.
+type DerivedSource interface {
+	DerivedValue(channelID, key string) ([]byte, error)
+}
+
+// Authorizer checks that the client of a request is allowed to read the
+// firings of the triggers on a channel.
+type Authorizer interface {
+	Authorize(req *http.Request, channelID string) error
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// EventsHandler exposes the event feed of the triggers through the operations
+// endpoint. GET returns the firings whose sequence is greater than the since
+// query parameter, for the channel given by the channel query parameter, to
+// the clients authorized to read the channel.
+type EventsHandler struct {
+	Source     EventSource
+	Authorizer Authorizer
+}
+
+// NewEventsHandler returns an EventsHandler for the given EventSource and
+// Authorizer.
+func NewEventsHandler(s EventSource, a Authorizer) *EventsHandler {
+	return &EventsHandler{Source: s, Authorizer: a}
+}
+
+func (h *EventsHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+
+	channelID := req.URL.Query().Get("channel")
+	if channelID == "" {
+		sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: "channel parameter is required",
+		})
+		return
+	}
+
+	var since uint64
+	if s := req.URL.Query().Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			sendResponse(resp, http.StatusBadRequest, &errorResponse{
+				Error: fmt.Sprintf("invalid since parameter: %s", s),
+			})
+			return
+		}
+	}
+	if !authorize(resp, req, h.Authorizer, channelID) {
+		return
+	}
+	sendResponse(resp, http.StatusOK, h.Source.Events(channelID, since))
+}
+
+// DerivedHandler exposes the derived keys written by the triggers through the
+// operations endpoint. GET returns the value of the derived key given by the
+// key query parameter in the channel given by the channel query parameter, to
+// the clients authorized to read the channel.
+type DerivedHandler struct {
+	Source     DerivedSource
+	Authorizer Authorizer
+}
+
+// NewDerivedHandler returns a DerivedHandler for the given DerivedSource and
+// Authorizer.
+func NewDerivedHandler(s DerivedSource, a Authorizer) *DerivedHandler {
+	return &DerivedHandler{Source: s, Authorizer: a}
+}
+
+func (h *DerivedHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("invalid request method: %s", req.Method),
+		})
+		return
+	}
+
+	channelID := req.URL.Query().Get("channel")
+	key := req.URL.Query().Get("key")
+	if channelID == "" || key == "" {
+		sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: "channel and key parameters are required",
+		})
+		return
+	}
+	if !authorize(resp, req, h.Authorizer, channelID) {
+		return
+	}
+
+	value, err := h.Source.DerivedValue(channelID, key)
+	if err != nil {
+		logger.Errorw("failed to get derived key", "channel", channelID, "key", key, "error", err)
+		sendResponse(resp, http.StatusInternalServerError, &errorResponse{
+			Error: fmt.Sprintf("failed to get derived key: %s", err),
+		})
+		return
+	}
+	if value == nil {
+		sendResponse(resp, http.StatusNotFound, &errorResponse{
+			Error: fmt.Sprintf("derived key [%s] not found in channel [%s]", key, channelID),
+		})
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	resp.Write(value)
+}
+
+func authorize(resp http.ResponseWriter, req *http.Request, a Authorizer, channelID string) bool {
+	if err := a.Authorize(req, channelID); err != nil {
+		logger.Warnw("access denied to the triggers", "channel", channelID, "error", err)
+		sendResponse(resp, http.StatusForbidden, &errorResponse{
+			Error: fmt.Sprintf("access denied to channel [%s]", channelID),
+		})
+		return false
+	}
+	return true
+}
+
+func sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorw("failed to encode payload", "error", err)
+	}
+}
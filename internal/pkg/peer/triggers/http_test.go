@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package triggers_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	coretriggers "github.com/hyperledger/fabric/core/peer/triggers"
+	"github.com/hyperledger/fabric/internal/pkg/peer/triggers"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource struct {
+	channelID string
+	since     uint64
+	values    map[string][]byte
+	err       error
+}
+
+func (f *fakeSource) Events(channelID string, since uint64) []*coretriggers.Firing {
+	f.channelID, f.since = channelID, since
+	return []*coretriggers.Firing{{Sequence: since + 1, Trigger: "assets", ChannelID: "mychannel", Namespace: "assetcc", Key: "car1"}}
+}
+
+func (f *fakeSource) DerivedValue(channelID, key string) ([]byte, error) {
+	return f.values[channelID+"/"+key], f.err
+}
+
+type fakeAuthorizer struct {
+	denied map[string]bool
+}
+
+func (f *fakeAuthorizer) Authorize(req *http.Request, channelID string) error {
+	if f.denied[channelID] {
+		return errors.New("policy not satisfied")
+	}
+	return nil
+}
+
+func TestEventsHandler(t *testing.T) {
+	source := &fakeSource{}
+	handler := triggers.NewEventsHandler(source, &fakeAuthorizer{denied: map[string]bool{"otherchannel": true}})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/events?channel=mychannel&since=4", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "mychannel", source.channelID)
+	assert.Equal(t, uint64(4), source.since)
+	assert.JSONEq(t, `[{"sequence":5,"trigger":"assets","channel_id":"mychannel","block_number":0,"tx_number":0,"tx_id":"","namespace":"assetcc","key":"car1"}]`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/events?channel=otherchannel", nil))
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.JSONEq(t, `{"error":"access denied to channel [otherchannel]"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/events", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"channel parameter is required"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/events?channel=mychannel&since=last", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid since parameter: last"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/ledger/triggers/events", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method: POST"}`, resp.Body.String())
+}
+
+func TestDerivedHandler(t *testing.T) {
+	source := &fakeSource{values: map[string][]byte{"mychannel/latest/car1": []byte(`{"key":"car1"}`)}}
+	handler := triggers.NewDerivedHandler(source, &fakeAuthorizer{denied: map[string]bool{"otherchannel": true}})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/derived?channel=mychannel&key=latest/car1", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"key":"car1"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/derived?channel=mychannel&key=latest/car2", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"error":"derived key [latest/car2] not found in channel [mychannel]"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/derived?channel=otherchannel&key=latest/car1", nil))
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.JSONEq(t, `{"error":"access denied to channel [otherchannel]"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/derived?channel=mychannel", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"channel and key parameters are required"}`, resp.Body.String())
+
+	source.err = errors.New("leveldb: closed")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ledger/triggers/derived?channel=mychannel&key=latest/car1", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"error":"failed to get derived key: leveldb: closed"}`, resp.Body.String())
+}
+
+type channelConfigs map[string]channelconfig.Resources
+
+func (c channelConfigs) GetChannelConfig(cid string) channelconfig.Resources {
+	return c[cid]
+}
+
+func TestChannelReaders(t *testing.T) {
+	readers := &triggers.ChannelReaders{Channels: channelConfigs{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/ledger/triggers/events?channel=mychannel", nil)
+	assert.EqualError(t, readers.Authorize(req, "mychannel"), "request has no verified TLS client certificate")
+
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Raw: []byte("cert")}}}}
+	assert.EqualError(t, readers.Authorize(req, "mychannel"), "channel [mychannel] not found")
+}
@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+var logger = flogging.MustGetLogger("remotesigner")
+
+// DefaultTimeout bounds the signing requests.
+const DefaultTimeout = 5 * time.Second
+
+// Config configures the connection to the remote signing service.
+type Config struct {
+	// Address is the host:port of the signing service.
+	Address string
+	// KeyID identifies the signing key of the node on the signing service.
+	KeyID string
+	// Timeout bounds each signing request.
+	Timeout time.Duration
+	// ClientConfig holds the TLS settings of the connection. The GM TLS cipher
+	// suites are negotiated when the certificates hold SM2 keys.
+	ClientConfig comm.ClientConfig
+}
+
+// SigningIdentity is the signing identity of the local MSP whose private key is
+// held by a remote signing service. It implements msp.SigningIdentity so that it
+// may replace the signing identity of the local MSP.
+type SigningIdentity struct {
+	msp.Identity
+
+	keyID   string
+	timeout time.Duration
+	conn    *grpc.ClientConn
+	client  RemoteSignerClient
+}
+
+// New connects to the signing service and returns a signing identity for the
+// public identity of the local MSP.
+func New(conf Config, id msp.Identity) (*SigningIdentity, error) {
+	if conf.Address == "" {
+		return nil, errors.New("the address of the remote signing service is not set")
+	}
+	if conf.KeyID == "" {
+		return nil, errors.New("the key ID of the remote signing service is not set")
+	}
+	if conf.Timeout == 0 {
+		conf.Timeout = DefaultTimeout
+	}
+
+	client, err := comm.NewGRPCClient(conf.ClientConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed creating the client of the remote signing service")
+	}
+	conn, err := client.NewConnection(conf.Address)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed connecting to the remote signing service at %s", conf.Address)
+	}
+	logger.Infof("Signing with key %s of the remote signing service at %s", conf.KeyID, conf.Address)
+
+	return &SigningIdentity{
+		Identity: id,
+		keyID:    conf.KeyID,
+		timeout:  conf.Timeout,
+		conn:     conn,
+		client:   NewRemoteSignerClient(conn),
+	}, nil
+}
+
+// Sign signs a message on the remote signing service. The signature is verified
+// against the public identity, so that a misconfigured key is detected before
+// the signature is used.
+func (s *SigningIdentity) Sign(msg []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	resp, err := s.client.Sign(ctx, &SignRequest{KeyId: s.keyID, Message: msg})
+	if err != nil {
+		return nil, errors.WithMessage(err, "remote signing failed")
+	}
+	if err := s.Identity.Verify(msg, resp.Signature); err != nil {
+		return nil, errors.WithMessagef(err, "the signature of key %s does not match the local identity", s.keyID)
+	}
+	return resp.Signature, nil
+}
+
+// GetPublicVersion returns the public identity.
+func (s *SigningIdentity) GetPublicVersion() msp.Identity {
+	return s.Identity
+}
+
+// Close closes the connection to the signing service.
+func (s *SigningIdentity) Close() error {
+	return s.conn.Close()
+}
+
+// PublicMSPConfig returns a copy of the configuration of a local MSP without the
+// private part of its signing identity, so that the MSP may be set up without the
+// signing key, along with the certificate of the signing identity.
+func PublicMSPConfig(conf *mspproto.MSPConfig) (*mspproto.MSPConfig, []byte, error) {
+	fabricConf := &mspproto.FabricMSPConfig{}
+	if err := proto.Unmarshal(conf.Config, fabricConf); err != nil {
+		return nil, nil, errors.Wrap(err, "failed unmarshalling the MSP configuration")
+	}
+	if fabricConf.SigningIdentity == nil {
+		return nil, nil, errors.New("the MSP configuration has no signing identity")
+	}
+	signCert := fabricConf.SigningIdentity.PublicSigner
+	fabricConf.SigningIdentity = nil
+
+	fabricConfBytes, err := proto.Marshal(fabricConf)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed marshalling the MSP configuration")
+	}
+	return &mspproto.MSPConfig{Type: conf.Type, Config: fabricConfBytes}, signCert, nil
+}
+
+// PublicIdentity returns the identity of the given certificate as validated by
+// the local MSP.
+func PublicIdentity(localMSP msp.MSP, signCert []byte) (msp.Identity, error) {
+	mspID, err := localMSP.GetIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	serialized, err := proto.Marshal(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: signCert})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed serializing the signing identity")
+	}
+	id, err := localMSP.DeserializeIdentity(serialized)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed deserializing the signing identity")
+	}
+	if err := id.Validate(); err != nil {
+		return nil, errors.WithMessage(err, "the signing identity is not valid")
+	}
+	return id, nil
+}
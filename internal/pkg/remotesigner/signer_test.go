@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/golang/protobuf/proto"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signingService struct {
+	keys map[string]*sm2.PrivateKey
+}
+
+func (s *signingService) Sign(_ context.Context, req *SignRequest) (*SignResponse, error) {
+	key, ok := s.keys[req.KeyId]
+	if !ok {
+		return nil, errors.Errorf("unknown key %s", req.KeyId)
+	}
+	sig, err := key.Sign(rand.Reader, req.Message, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SignResponse{Signature: sig}, nil
+}
+
+type identity struct {
+	msp.Identity
+	pub *sm2.PublicKey
+}
+
+func (id *identity) Verify(msg []byte, sig []byte) error {
+	if !id.pub.Verify(msg, sig) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func TestSign(t *testing.T) {
+	nodeKey, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	srv, err := comm.NewGRPCServer("127.0.0.1:0", comm.ServerConfig{})
+	require.NoError(t, err)
+	RegisterRemoteSignerServer(srv.Server(), &signingService{
+		keys: map[string]*sm2.PrivateKey{"node": nodeKey, "other": otherKey},
+	})
+	go srv.Start()
+	defer srv.Stop()
+
+	id := &identity{pub: &nodeKey.PublicKey}
+	newSigner := func(keyID string) *SigningIdentity {
+		signer, err := New(Config{
+			Address:      srv.Address(),
+			KeyID:        keyID,
+			ClientConfig: comm.ClientConfig{Timeout: time.Second},
+		}, id)
+		require.NoError(t, err)
+		return signer
+	}
+
+	signer := newSigner("node")
+	defer signer.Close()
+	assert.Equal(t, DefaultTimeout, signer.timeout)
+	assert.Equal(t, id, signer.GetPublicVersion())
+	sig, err := signer.Sign([]byte("block"))
+	require.NoError(t, err)
+	assert.True(t, nodeKey.PublicKey.Verify([]byte("block"), sig))
+
+	mismatched := newSigner("other")
+	defer mismatched.Close()
+	_, err = mismatched.Sign([]byte("block"))
+	assert.EqualError(t, err, "the signature of key other does not match the local identity: invalid signature")
+
+	unknown := newSigner("unknown")
+	defer unknown.Close()
+	_, err = unknown.Sign([]byte("block"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "remote signing failed")
+}
+
+func TestNewMissingConfig(t *testing.T) {
+	_, err := New(Config{KeyID: "node"}, nil)
+	assert.EqualError(t, err, "the address of the remote signing service is not set")
+
+	_, err = New(Config{Address: "127.0.0.1:7060"}, nil)
+	assert.EqualError(t, err, "the key ID of the remote signing service is not set")
+}
+
+func TestPublicMSPConfig(t *testing.T) {
+	fabricConf := &mspproto.FabricMSPConfig{
+		Name:      "SampleOrg",
+		RootCerts: [][]byte{[]byte("root")},
+		SigningIdentity: &mspproto.SigningIdentityInfo{
+			PublicSigner: []byte("signcert"),
+		},
+	}
+	fabricConfBytes, err := proto.Marshal(fabricConf)
+	require.NoError(t, err)
+
+	conf, signCert, err := PublicMSPConfig(&mspproto.MSPConfig{Type: 0, Config: fabricConfBytes})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("signcert"), signCert)
+
+	publicConf := &mspproto.FabricMSPConfig{}
+	require.NoError(t, proto.Unmarshal(conf.Config, publicConf))
+	assert.Nil(t, publicConf.SigningIdentity)
+	assert.Equal(t, "SampleOrg", publicConf.Name)
+	assert.Equal(t, [][]byte{[]byte("root")}, publicConf.RootCerts)
+
+	fabricConf.SigningIdentity = nil
+	fabricConfBytes, err = proto.Marshal(fabricConf)
+	require.NoError(t, err)
+	_, _, err = PublicMSPConfig(&mspproto.MSPConfig{Config: fabricConfBytes})
+	assert.EqualError(t, err, "the MSP configuration has no signing identity")
+
+	_, _, err = PublicMSPConfig(&mspproto.MSPConfig{Config: []byte("garbage")})
+	assert.Error(t, err)
+}
@@ -18,6 +18,9 @@ const (
 	MSPv1_1
 	MSPv1_3
 	MSPv1_4_3
+	// MSPv2_2 additionally accepts signatures hashed with the hash family
+	// of the signature algorithm of the signer's key
+	MSPv2_2
 )
 
 // NewOpts represent
@@ -58,11 +61,15 @@ func New(opts NewOpts, cryptoProvider bccsp.BCCSP) (MSP, error) {
 			return newBccspMsp(MSPv1_3, cryptoProvider)
 		case MSPv1_4_3:
 			return newBccspMsp(MSPv1_4_3, cryptoProvider)
+		case MSPv2_2:
+			return newBccspMsp(MSPv2_2, cryptoProvider)
 		default:
 			return nil, errors.Errorf("Invalid *BCCSPNewOpts. Version not recognized [%v]", opts.GetVersion())
 		}
 	case *IdemixNewOpts:
 		switch opts.GetVersion() {
+		case MSPv2_2:
+			fallthrough
 		case MSPv1_4_3:
 			fallthrough
 		case MSPv1_3:
@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"go.uber.org/zap/zapcore"
 )
@@ -39,6 +40,10 @@ type identity struct {
 	// reference to the MSP that "owns" this identity
 	msp *bccspmsp
 
+	// sigAlg is the signature algorithm of the public key of this
+	// instance, nil if it is not known
+	sigAlg *protoutil.SignatureAlgorithm
+
 	// validationMutex is used to synchronise memory operation
 	// over validated and validationErr
 	validationMutex sync.Mutex
@@ -80,7 +85,11 @@ func newIdentity(cert *x509.Certificate, pk bccsp.Key, msp *bccspmsp) (Identity,
 		Mspid: msp.name,
 		Id:    hex.EncodeToString(digest)}
 
-	return &identity{id: id, cert: cert, pk: pk, msp: msp}, nil
+	// Unknown key types keep being handled by the BCCSP with the hash
+	// family configured for the MSP
+	sigAlg, _ := protoutil.SignatureAlgorithmOfKey(cert.PublicKey)
+
+	return &identity{id: id, cert: cert, pk: pk, msp: msp, sigAlg: sigAlg}, nil
 }
 
 // ExpiresAt returns the time at which the Identity expires.
@@ -169,8 +178,29 @@ func NewSerializedIdentity(mspID string, certPEM []byte) ([]byte, error) {
 func (id *identity) Verify(msg []byte, sig []byte) error {
 	// mspIdentityLogger.Infof("Verifying signature")
 
+	err := id.verifyWithHashFamily(msg, sig, id.msp.cryptoConfig.SignatureHashFamily)
+	if err == nil {
+		return nil
+	}
+
+	// The signer may have hashed the message with the hash family of its
+	// signature algorithm rather than with the one configured for the MSP,
+	// which happens while the identities of a channel migrate from one
+	// signature algorithm to the other. Such signatures are only accepted
+	// once the channel capabilities have moved the MSP to MSPv2_2, so that
+	// every node of the channel agrees on their validity.
+	if id.msp.version < MSPv2_2 || id.sigAlg == nil || id.sigAlg.HashFamily == id.msp.cryptoConfig.SignatureHashFamily {
+		return err
+	}
+	if id.verifyWithHashFamily(msg, sig, id.sigAlg.HashFamily) == nil {
+		return nil
+	}
+	return err
+}
+
+func (id *identity) verifyWithHashFamily(msg []byte, sig []byte, hashFamily string) error {
 	// Compute Hash
-	hashOpt, err := id.getHashOpt(id.msp.cryptoConfig.SignatureHashFamily)
+	hashOpt, err := id.getHashOpt(hashFamily)
 	if err != nil {
 		return errors.WithMessage(err, "failed getting hash function options")
 	}
@@ -219,6 +249,8 @@ func (id *identity) getHashOpt(hashFamily string) (bccsp.HashOpts, error) {
 		return bccsp.GetHashOpt(bccsp.SHA256)
 	case bccsp.SHA3:
 		return bccsp.GetHashOpt(bccsp.SHA3_256)
+	case bccsp.SM3:
+		return bccsp.GetHashOpt(bccsp.SM3)
 	}
 	return nil, errors.Errorf("hash familiy not recognized [%s]", hashFamily)
 }
@@ -239,10 +271,11 @@ func newSigningIdentity(cert *x509.Certificate, pk bccsp.Key, signer crypto.Sign
 	}
 	return &signingidentity{
 		identity: identity{
-			id:   mspId.(*identity).id,
-			cert: mspId.(*identity).cert,
-			msp:  mspId.(*identity).msp,
-			pk:   mspId.(*identity).pk,
+			id:     mspId.(*identity).id,
+			cert:   mspId.(*identity).cert,
+			msp:    mspId.(*identity).msp,
+			pk:     mspId.(*identity).pk,
+			sigAlg: mspId.(*identity).sigAlg,
 		},
 		signer: signer,
 	}, nil
@@ -252,8 +285,18 @@ func newSigningIdentity(cert *x509.Certificate, pk bccsp.Key, signer crypto.Sign
 func (id *signingidentity) Sign(msg []byte) ([]byte, error) {
 	//mspIdentityLogger.Infof("Signing message")
 
+	return id.SignWithHashFamily(msg, id.msp.cryptoConfig.SignatureHashFamily)
+}
+
+// SignatureAlgorithm returns the signature algorithm of this instance
+func (id *signingidentity) SignatureAlgorithm() *protoutil.SignatureAlgorithm {
+	return id.sigAlg
+}
+
+// SignWithHashFamily signs a message hashed with the given hash family
+func (id *signingidentity) SignWithHashFamily(msg []byte, hashFamily string) ([]byte, error) {
 	// Compute Hash
-	hashOpt, err := id.getHashOpt(id.msp.cryptoConfig.SignatureHashFamily)
+	hashOpt, err := id.getHashOpt(hashFamily)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed getting hash function options")
 	}
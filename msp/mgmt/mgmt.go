@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"sync"
 
+	pmsp "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -156,20 +157,23 @@ func loadLocalMSP(bccsp bccsp.BCCSP) msp.MSP {
 		mspLogger.Panicf("msp type " + mspType + " unknown")
 	}
 
-	mspInst, err := msp.New(newOpts, bccsp)
-	if err != nil {
-		mspLogger.Fatalf("Failed to initialize local MSP, received err %+v", err)
-	}
-	switch mspType {
-	case msp.ProviderTypeToString(msp.FABRIC):
-		mspInst, err = cache.New(mspInst)
+	mspInst, err := NewReloadableMSP(func() (msp.MSP, error) {
+		mspInst, err := msp.New(newOpts, bccsp)
 		if err != nil {
-			mspLogger.Fatalf("Failed to initialize local MSP, received err %+v", err)
+			return nil, err
 		}
-	case msp.ProviderTypeToString(msp.IDEMIX):
-		// Do nothing
-	default:
-		panic("msp type " + mspType + " unknown")
+		switch mspType {
+		case msp.ProviderTypeToString(msp.FABRIC):
+			return cache.New(mspInst)
+		case msp.ProviderTypeToString(msp.IDEMIX):
+			// Do nothing
+		default:
+			panic("msp type " + mspType + " unknown")
+		}
+		return mspInst, nil
+	})
+	if err != nil {
+		mspLogger.Fatalf("Failed to initialize local MSP, received err %+v", err)
 	}
 
 	mspLogger.Debugf("Created new local MSP")
@@ -177,6 +181,20 @@ func loadLocalMSP(bccsp bccsp.BCCSP) msp.MSP {
 	return mspInst
 }
 
+// ReloadLocalMsp sets up the local MSP anew with the given configuration,
+// in place of the configuration it was set up with
+func ReloadLocalMsp(conf *pmsp.MSPConfig) error {
+	m.Lock()
+	lm := localMsp
+	m.Unlock()
+
+	reloadable, ok := lm.(*ReloadableMSP)
+	if !ok {
+		return errors.New("the local MSP cannot be reloaded")
+	}
+	return reloadable.Reload(conf)
+}
+
 // GetIdentityDeserializer returns the IdentityDeserializer for the given chain
 func GetIdentityDeserializer(chainID string, cryptoProvider bccsp.BCCSP) msp.IdentityDeserializer {
 	if chainID == "" {
@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mgmt
+
+import (
+	"sync"
+
+	pmsp "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+)
+
+// ReloadableMSP is an MSP whose configuration can be reloaded while it is in use,
+// so that routine certificate maintenance, such as adding admin certificates,
+// updating CRLs or rotating TLS CAs, doesn't require a restart.
+// A reload sets up a fresh MSP instance with the new configuration, and swaps it
+// in only if the setup succeeds. As the fresh instance starts with empty caches,
+// the identities are revalidated against the new configuration. The identities
+// and signing identities obtained before a reload keep referring to the
+// configuration they were obtained with.
+type ReloadableMSP struct {
+	newMSP func() (msp.MSP, error)
+
+	lock sync.RWMutex
+	msp  msp.MSP
+}
+
+// NewReloadableMSP creates a ReloadableMSP which uses the given function
+// to create the MSP instances it delegates to.
+func NewReloadableMSP(newMSP func() (msp.MSP, error)) (*ReloadableMSP, error) {
+	mspInst, err := newMSP()
+	if err != nil {
+		return nil, err
+	}
+	return &ReloadableMSP{newMSP: newMSP, msp: mspInst}, nil
+}
+
+// Reload sets up a fresh MSP instance with the given configuration and,
+// if successful, uses it in place of the current one. The MSP identifier
+// cannot be changed by a reload.
+func (r *ReloadableMSP) Reload(conf *pmsp.MSPConfig) error {
+	mspInst, err := r.newMSP()
+	if err != nil {
+		return errors.WithMessage(err, "failed creating MSP instance")
+	}
+	if err := mspInst.Setup(conf); err != nil {
+		return errors.WithMessage(err, "failed setting up MSP with the new configuration")
+	}
+	newID, err := mspInst.GetIdentifier()
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	currentID, err := r.msp.GetIdentifier()
+	if err != nil {
+		return err
+	}
+	if currentID != newID {
+		return errors.Errorf("the MSP identifier cannot be changed from %s to %s", currentID, newID)
+	}
+	r.msp = mspInst
+	mspLogger.Infof("Reloaded MSP %s", newID)
+	return nil
+}
+
+func (r *ReloadableMSP) current() msp.MSP {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.msp
+}
+
+// DeserializeIdentity implements msp.IdentityDeserializer
+func (r *ReloadableMSP) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	return r.current().DeserializeIdentity(serializedIdentity)
+}
+
+// IsWellFormed implements msp.IdentityDeserializer
+func (r *ReloadableMSP) IsWellFormed(identity *pmsp.SerializedIdentity) error {
+	return r.current().IsWellFormed(identity)
+}
+
+// Setup sets up the current MSP instance with the given configuration
+func (r *ReloadableMSP) Setup(config *pmsp.MSPConfig) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.msp.Setup(config)
+}
+
+// GetVersion implements msp.MSP
+func (r *ReloadableMSP) GetVersion() msp.MSPVersion {
+	return r.current().GetVersion()
+}
+
+// GetType implements msp.MSP
+func (r *ReloadableMSP) GetType() msp.ProviderType {
+	return r.current().GetType()
+}
+
+// GetIdentifier implements msp.MSP
+func (r *ReloadableMSP) GetIdentifier() (string, error) {
+	return r.current().GetIdentifier()
+}
+
+// GetSigningIdentity implements msp.MSP
+func (r *ReloadableMSP) GetSigningIdentity(identifier *msp.IdentityIdentifier) (msp.SigningIdentity, error) {
+	return r.current().GetSigningIdentity(identifier)
+}
+
+// GetDefaultSigningIdentity implements msp.MSP
+func (r *ReloadableMSP) GetDefaultSigningIdentity() (msp.SigningIdentity, error) {
+	return r.current().GetDefaultSigningIdentity()
+}
+
+// GetTLSRootCerts implements msp.MSP
+func (r *ReloadableMSP) GetTLSRootCerts() [][]byte {
+	return r.current().GetTLSRootCerts()
+}
+
+// GetTLSIntermediateCerts implements msp.MSP
+func (r *ReloadableMSP) GetTLSIntermediateCerts() [][]byte {
+	return r.current().GetTLSIntermediateCerts()
+}
+
+// Validate implements msp.MSP
+func (r *ReloadableMSP) Validate(id msp.Identity) error {
+	return r.current().Validate(id)
+}
+
+// SatisfiesPrincipal implements msp.MSP
+func (r *ReloadableMSP) SatisfiesPrincipal(id msp.Identity, principal *pmsp.MSPPrincipal) error {
+	return r.current().SatisfiesPrincipal(id, principal)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mgmt
+
+import (
+	"testing"
+
+	pmsp "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadableMSP(t *testing.T) {
+	cryptoProvider := factory.GetDefault()
+	var created int
+	reloadable, err := NewReloadableMSP(func() (msp.MSP, error) {
+		created++
+		return msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_0}}, cryptoProvider)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+
+	mspDir := configtest.GetDevMspDir()
+	conf, err := msp.GetLocalMspConfig(mspDir, nil, "SampleOrg")
+	require.NoError(t, err)
+	require.NoError(t, reloadable.Setup(conf))
+
+	signer, err := reloadable.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	serializedSigner, err := signer.Serialize()
+	require.NoError(t, err)
+	id, err := reloadable.DeserializeIdentity(serializedSigner)
+	require.NoError(t, err)
+	require.NoError(t, reloadable.Validate(id))
+	current := reloadable.msp
+
+	// reloading the configuration swaps in a fresh instance
+	require.NoError(t, reloadable.Reload(conf))
+	require.Equal(t, 2, created)
+	require.NotEqual(t, current, reloadable.msp)
+	mspID, err := reloadable.GetIdentifier()
+	require.NoError(t, err)
+	require.Equal(t, "SampleOrg", mspID)
+	id, err = reloadable.DeserializeIdentity(serializedSigner)
+	require.NoError(t, err)
+	require.NoError(t, reloadable.Validate(id))
+	current = reloadable.msp
+
+	// the MSP identifier cannot be changed
+	otherConf, err := msp.GetLocalMspConfig(mspDir, nil, "OtherOrg")
+	require.NoError(t, err)
+	err = reloadable.Reload(otherConf)
+	require.EqualError(t, err, "the MSP identifier cannot be changed from SampleOrg to OtherOrg")
+	require.Equal(t, current, reloadable.msp)
+
+	// an invalid configuration is rejected, the current instance is kept
+	err = reloadable.Reload(&pmsp.MSPConfig{Type: int32(msp.FABRIC), Config: []byte("barf")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed setting up MSP with the new configuration")
+	require.Equal(t, current, reloadable.msp)
+
+	reloadable.newMSP = func() (msp.MSP, error) { return nil, errors.New("unknown MSP version") }
+	err = reloadable.Reload(conf)
+	require.EqualError(t, err, "failed creating MSP instance: unknown MSP version")
+	require.Equal(t, current, reloadable.msp)
+}
+
+func TestReloadLocalMsp(t *testing.T) {
+	localMsp = nil
+	defer func() { localMsp = nil }()
+
+	conf, err := msp.GetLocalMspConfig(configtest.GetDevMspDir(), nil, "SampleOrg")
+	require.NoError(t, err)
+	lm := GetLocalMSP(factory.GetDefault())
+	require.NoError(t, lm.Setup(conf))
+	require.NoError(t, ReloadLocalMsp(conf))
+
+	localMsp = lm.(*ReloadableMSP).msp
+	require.EqualError(t, ReloadLocalMsp(conf), "the local MSP cannot be reloaded")
+}
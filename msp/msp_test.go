@@ -751,6 +751,59 @@ func TestSignAndVerifyOtherHash(t *testing.T) {
 	id.(*signingidentity).msp.cryptoConfig.SignatureHashFamily = hash
 }
 
+func TestSignAndVerifySM3(t *testing.T) {
+	id, err := localMsp.GetDefaultSigningIdentity()
+	if err != nil {
+		t.Fatalf("GetSigningIdentity should have succeeded")
+		return
+	}
+
+	hash := id.(*signingidentity).msp.cryptoConfig.SignatureHashFamily
+	id.(*signingidentity).msp.cryptoConfig.SignatureHashFamily = bccsp.SM3
+	defer func() { id.(*signingidentity).msp.cryptoConfig.SignatureHashFamily = hash }()
+
+	msg := []byte("foo")
+	sig, err := id.Sign(msg)
+	assert.NoError(t, err)
+
+	err = id.Verify(msg, sig)
+	assert.NoError(t, err)
+
+	err = id.Verify([]byte("bar"), sig)
+	assert.Error(t, err)
+}
+
+func TestVerifyWithKeyHashFamily(t *testing.T) {
+	for _, tc := range []struct {
+		version MSPVersion
+		valid   bool
+	}{
+		{version: MSPv1_4_3, valid: false},
+		{version: MSPv2_2, valid: true},
+	} {
+		thisMSP := getLocalMSPWithVersion(t, configtest.GetDevMspDir(), tc.version)
+		id, err := thisMSP.GetDefaultSigningIdentity()
+		assert.NoError(t, err)
+		sid := id.(*signingidentity)
+		assert.NotNil(t, sid.SignatureAlgorithm())
+
+		// The signer hashes with the hash family of its key while the MSP
+		// is configured with another one
+		sid.msp.cryptoConfig.SignatureHashFamily = bccsp.SHA3
+		msg := []byte("foo")
+		sig, err := sid.SignWithHashFamily(msg, sid.SignatureAlgorithm().HashFamily)
+		assert.NoError(t, err)
+
+		err = id.Verify(msg, sig)
+		if tc.valid {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+		assert.Error(t, id.Verify([]byte("bar"), sig))
+	}
+}
+
 func TestSignAndVerify_longMessage(t *testing.T) {
 	id, err := localMsp.GetDefaultSigningIdentity()
 	if err != nil {
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/pkg/errors"
+)
+
+// URL is the path of the operations endpoint serving the audit log.
+const URL = "/audit"
+
+// Handler serves the records of the audit log as JSON.  The records can be
+// selected with the channel, type and since query parameters.  The response
+// status is 500 when the hash chain of the log is broken.
+type Handler struct {
+	Log *Log
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		resp.Header().Set("Allow", http.MethodGet)
+		resp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := req.URL.Query()
+	filter := Filter{
+		ChannelID: query.Get("channel"),
+		Type:      RecordType(query.Get("type")),
+	}
+	if since := query.Get("since"); since != "" {
+		seq, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			h.sendError(resp, http.StatusBadRequest, errors.Errorf("invalid since parameter '%s'", since))
+			return
+		}
+		filter.Since = seq
+	}
+
+	records, err := h.Log.Records(filter)
+	if err != nil {
+		h.sendError(resp, http.StatusInternalServerError, err)
+		return
+	}
+	h.send(resp, http.StatusOK, records)
+}
+
+func (h *Handler) sendError(resp http.ResponseWriter, code int, err error) {
+	h.send(resp, code, &types.ErrorResponse{Error: err.Error()})
+}
+
+func (h *Handler) send(resp http.ResponseWriter, code int, content interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(content); err != nil {
+		logger.Errorf("failed to encode audit response, err: %s", err)
+	}
+}
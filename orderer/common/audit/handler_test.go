@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := NewLog(dir)
+	require.NoError(t, err)
+	defer l.Close()
+	_, err = l.Append(Record{Type: ChannelJoin, ChannelID: "ch1"})
+	require.NoError(t, err)
+	_, err = l.Append(Record{Type: ConfigUpdate, ChannelID: "ch1", BlockNumber: 1, Identities: []Identity{{MSPID: "Org1MSP"}}})
+	require.NoError(t, err)
+	_, err = l.Append(Record{Type: ConfigUpdate, ChannelID: "ch2", BlockNumber: 1})
+	require.NoError(t, err)
+
+	handler := &Handler{Log: l}
+	get := func(url string) ([]Record, *httptest.ResponseRecorder) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, url, nil))
+		if resp.Code != http.StatusOK {
+			return nil, resp
+		}
+		var records []Record
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &records))
+		return records, resp
+	}
+
+	records, resp := get(URL)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"))
+	require.Len(t, records, 3)
+	assert.Equal(t, []Identity{{MSPID: "Org1MSP"}}, records[1].Identities)
+
+	records, _ = get(URL + "?channel=ch1&type=config")
+	require.Len(t, records, 1)
+	assert.Equal(t, uint64(1), records[0].Sequence)
+
+	records, _ = get(URL + "?since=2")
+	require.Len(t, records, 1)
+	assert.Equal(t, "ch2", records[0].ChannelID)
+
+	_, resp = get(URL + "?since=last")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid since parameter 'last'"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, URL, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, http.MethodGet, resp.Header().Get("Allow"))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, fileName), []byte("garbage\n"), 0640))
+	_, resp = get(URL)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "is corrupted at record 0")
+}
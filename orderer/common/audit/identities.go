@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// ConfigUpdateIdentities returns the identities which submitted and signed
+// the config update the config envelope results from.  The submitter comes
+// first, and an identity which both submitted and signed the update is only
+// returned once.
+func ConfigUpdateIdentities(configEnv *cb.ConfigEnvelope) []Identity {
+	if configEnv.GetLastUpdate() == nil {
+		return nil
+	}
+	payload, err := protoutil.UnmarshalPayload(configEnv.LastUpdate.Payload)
+	if err != nil || payload.Header == nil {
+		logger.Warningf("Failed extracting the identities from the config update: %v", err)
+		return nil
+	}
+
+	var creators [][]byte
+	if shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader); err == nil {
+		creators = append(creators, shdr.Creator)
+	}
+	if configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data); err == nil {
+		for _, sig := range configUpdateEnv.Signatures {
+			if shdr, err := protoutil.UnmarshalSignatureHeader(sig.SignatureHeader); err == nil {
+				creators = append(creators, shdr.Creator)
+			}
+		}
+	}
+
+	var identities []Identity
+	seen := map[string]struct{}{}
+	for _, creator := range creators {
+		if _, exists := seen[string(creator)]; exists {
+			continue
+		}
+		seen[string(creator)] = struct{}{}
+		identities = append(identities, newIdentity(creator))
+	}
+	return identities
+}
+
+// newIdentity describes a serialized identity.  The subject is only set for
+// identities holding a PEM encoded certificate.
+func newIdentity(creator []byte) Identity {
+	sid := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(creator, sid); err != nil {
+		return Identity{Fingerprint: fingerprint(creator)}
+	}
+	id := Identity{
+		MSPID:       sid.Mspid,
+		Fingerprint: fingerprint(sid.IdBytes),
+	}
+	if block, _ := pem.Decode(sid.IdBytes); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			id.Subject = cert.Subject.String()
+		}
+	}
+	return id
+}
+
+func fingerprint(raw []byte) string {
+	return hex.EncodeToString(sm3.SumSM3(raw))
+}
@@ -0,0 +1,223 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+const fileName = "audit.log"
+
+var logger = flogging.MustGetLogger("orderer.common.audit")
+
+// RecordType is the kind of action recorded in the audit log.
+type RecordType string
+
+const (
+	// ConfigUpdate records a config transaction applied to a channel.
+	ConfigUpdate RecordType = "config"
+	// ChannelCreate records the creation of a channel through the system channel.
+	ChannelCreate RecordType = "channel-create"
+	// ChannelJoin records the orderer joining a channel with a config block.
+	ChannelJoin RecordType = "channel-join"
+	// MaintenanceMode records a channel entering or leaving maintenance mode.
+	MaintenanceMode RecordType = "maintenance-mode"
+)
+
+// Identity is an identity which submitted a recorded action.
+type Identity struct {
+	MSPID       string `json:"mspID"`
+	Subject     string `json:"subject,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Record is an entry of the audit log.  Each record holds the SM3 hash of the
+// previous one, so that altering or removing a record breaks the chain of
+// hashes of all the records following it.
+type Record struct {
+	Sequence     uint64     `json:"sequence"`
+	Timestamp    time.Time  `json:"timestamp"`
+	Type         RecordType `json:"type"`
+	ChannelID    string     `json:"channelID"`
+	BlockNumber  uint64     `json:"blockNumber"`
+	Identities   []Identity `json:"identities,omitempty"`
+	Details      string     `json:"details,omitempty"`
+	PreviousHash string     `json:"previousHash"`
+	Hash         string     `json:"hash"`
+}
+
+// computeHash returns the hash of the record, which covers all of its fields
+// but the hash itself.
+func (r Record) computeHash() (string, error) {
+	r.Hash = ""
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return "", errors.Wrap(err, "failed marshaling audit record")
+	}
+	return hex.EncodeToString(sm3.SumSM3(raw)), nil
+}
+
+// Filter selects the records returned by Records.  The zero value selects
+// all records.
+type Filter struct {
+	ChannelID string
+	Type      RecordType
+	// Since is the sequence of the first record returned.
+	Since uint64
+}
+
+func (f Filter) matches(r Record) bool {
+	return (f.ChannelID == "" || f.ChannelID == r.ChannelID) &&
+		(f.Type == "" || f.Type == r.Type) &&
+		r.Sequence >= f.Since
+}
+
+// Log is an append only, hash chained audit log kept in a file.
+type Log struct {
+	path string
+
+	mutex    sync.Mutex
+	file     *os.File
+	next     uint64
+	lastHash string
+}
+
+// NewLog opens the audit log stored in the given directory, creating it if
+// needed.  An error is returned if the hash chain of the existing records is
+// broken.
+func NewLog(dir string) (*Log, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, errors.Wrapf(err, "failed creating audit log directory %s", dir)
+	}
+	l := &Log{path: filepath.Join(dir, fileName)}
+
+	var last *Record
+	err := l.scan(func(r Record) {
+		last = &r
+	})
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		l.next = last.Sequence + 1
+		l.lastHash = last.Hash
+	}
+
+	l.file, err = os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening audit log %s", l.path)
+	}
+	return l, nil
+}
+
+// Append completes the record with its sequence, timestamp and hashes and
+// durably appends it to the log.
+func (l *Log) Append(r Record) (Record, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	r.Sequence = l.next
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+	r.PreviousHash = l.lastHash
+	hash, err := r.computeHash()
+	if err != nil {
+		return Record{}, err
+	}
+	r.Hash = hash
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return Record{}, errors.Wrap(err, "failed marshaling audit record")
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return Record{}, errors.Wrapf(err, "failed writing audit log %s", l.path)
+	}
+	if err := l.file.Sync(); err != nil {
+		return Record{}, errors.Wrapf(err, "failed syncing audit log %s", l.path)
+	}
+
+	l.next++
+	l.lastHash = r.Hash
+	return r, nil
+}
+
+// Records returns the records selected by the filter, after verifying the
+// hash chain of the whole log.
+func (l *Log) Records(f Filter) ([]Record, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	records := []Record{}
+	err := l.scan(func(r Record) {
+		if f.matches(r) {
+			records = append(records, r)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Close closes the file of the log.
+func (l *Log) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.file.Close()
+}
+
+// scan reads the records of the log in order, verifying their hash chain.
+func (l *Log) scan(f func(Record)) error {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed opening audit log %s", l.path)
+	}
+	defer file.Close()
+
+	var expectedSequence uint64
+	var previousHash string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return errors.Wrapf(err, "audit log %s is corrupted at record %d", l.path, expectedSequence)
+		}
+		if r.Sequence != expectedSequence {
+			return errors.Errorf("audit log %s is tampered: expected record %d but found record %d", l.path, expectedSequence, r.Sequence)
+		}
+		hash, err := r.computeHash()
+		if err != nil {
+			return err
+		}
+		if r.PreviousHash != previousHash || r.Hash != hash {
+			return errors.Errorf("audit log %s is tampered: hash chain is broken at record %d", l.path, r.Sequence)
+		}
+		f(r)
+		expectedSequence++
+		previousHash = r.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "failed reading audit log %s", l.path)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := NewLog(filepath.Join(dir, "audit"))
+	require.NoError(t, err)
+
+	first, err := l.Append(Record{Type: ChannelJoin, ChannelID: "ch1"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), first.Sequence)
+	assert.Empty(t, first.PreviousHash)
+	assert.NotEmpty(t, first.Hash)
+	assert.False(t, first.Timestamp.IsZero())
+
+	second, err := l.Append(Record{Type: ConfigUpdate, ChannelID: "ch1", BlockNumber: 1})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), second.Sequence)
+	assert.Equal(t, first.Hash, second.PreviousHash)
+	require.NoError(t, l.Close())
+
+	// the log is resumed where it stopped
+	l, err = NewLog(filepath.Join(dir, "audit"))
+	require.NoError(t, err)
+	defer l.Close()
+	third, err := l.Append(Record{Type: ConfigUpdate, ChannelID: "ch2", BlockNumber: 1})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), third.Sequence)
+	assert.Equal(t, second.Hash, third.PreviousHash)
+
+	records, err := l.Records(Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, first.Hash, records[0].Hash)
+	assert.True(t, first.Timestamp.Equal(records[0].Timestamp))
+
+	records, err = l.Records(Filter{ChannelID: "ch1"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	records, err = l.Records(Filter{Type: ConfigUpdate})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	records, err = l.Records(Filter{Since: 2})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ch2", records[0].ChannelID)
+	records, err = l.Records(Filter{ChannelID: "ch3"})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestLogTampering(t *testing.T) {
+	for _, testCase := range []struct {
+		name        string
+		tamper      func([][]byte) [][]byte
+		expectedErr string
+	}{
+		{
+			name: "altered record",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[1] = bytes.Replace(lines[1], []byte(`"ch1"`), []byte(`"ch9"`), 1)
+				return lines
+			},
+			expectedErr: "hash chain is broken at record 1",
+		},
+		{
+			name: "removed record",
+			tamper: func(lines [][]byte) [][]byte {
+				return append(lines[:1], lines[2:]...)
+			},
+			expectedErr: "expected record 1 but found record 2",
+		},
+		{
+			name: "removed first record",
+			tamper: func(lines [][]byte) [][]byte {
+				return lines[1:]
+			},
+			expectedErr: "expected record 0 but found record 1",
+		},
+		{
+			name: "garbage",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[2] = []byte("garbage")
+				return lines
+			},
+			expectedErr: "is corrupted at record 2",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "audit")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			l, err := NewLog(dir)
+			require.NoError(t, err)
+			for i := 0; i < 3; i++ {
+				_, err := l.Append(Record{Type: ConfigUpdate, ChannelID: "ch1", BlockNumber: uint64(i)})
+				require.NoError(t, err)
+			}
+
+			path := filepath.Join(dir, fileName)
+			content, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+			require.Len(t, lines, 3)
+			lines = testCase.tamper(lines)
+			require.NoError(t, ioutil.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0640))
+
+			_, err = l.Records(Filter{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expectedErr)
+			require.NoError(t, l.Close())
+
+			_, err = NewLog(dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expectedErr)
+		})
+	}
+}
+
+func TestConfigUpdateIdentities(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	admin, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+
+	submitter := protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: admin.Cert})
+	signer := protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org2MSP", IdBytes: []byte("not a certificate")})
+
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{
+		Signatures: []*cb.ConfigSignature{
+			{SignatureHeader: protoutil.MarshalOrPanic(protoutil.MakeSignatureHeader(submitter, nil))},
+			{SignatureHeader: protoutil.MarshalOrPanic(protoutil.MakeSignatureHeader(signer, nil))},
+		},
+	}
+	payload := &cb.Payload{
+		Header: protoutil.MakePayloadHeader(
+			protoutil.MakeChannelHeader(cb.HeaderType_CONFIG_UPDATE, 0, "ch1", 0),
+			protoutil.MakeSignatureHeader(submitter, nil),
+		),
+		Data: protoutil.MarshalOrPanic(configUpdateEnv),
+	}
+	configEnv := &cb.ConfigEnvelope{
+		LastUpdate: &cb.Envelope{Payload: protoutil.MarshalOrPanic(payload)},
+	}
+
+	identities := ConfigUpdateIdentities(configEnv)
+	require.Len(t, identities, 2)
+	assert.Equal(t, "Org1MSP", identities[0].MSPID)
+	assert.Equal(t, admin.TLSCert.Subject.String(), identities[0].Subject)
+	assert.Equal(t, fingerprint(admin.Cert), identities[0].Fingerprint)
+	assert.Equal(t, Identity{MSPID: "Org2MSP", Fingerprint: fingerprint([]byte("not a certificate"))}, identities[1])
+
+	assert.Nil(t, ConfigUpdateIdentities(&cb.ConfigEnvelope{}))
+	assert.Nil(t, ConfigUpdateIdentities(&cb.ConfigEnvelope{LastUpdate: &cb.Envelope{Payload: []byte("garbage")}}))
+}
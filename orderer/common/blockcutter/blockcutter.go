@@ -12,6 +12,7 @@ import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/idempotency"
 )
 
 var logger = flogging.MustGetLogger("orderer.common.blockcutter")
@@ -20,6 +21,16 @@ type OrdererConfigFetcher interface {
 	OrdererConfig() (channelconfig.Orderer, bool)
 }
 
+// KeyIndex holds the idempotency keys of the envelopes already ordered on the
+// channel
+type KeyIndex interface {
+	// Duplicate returns whether an envelope with the key was already cut
+	Duplicate(key string) bool
+
+	// BatchCut records the keys of a batch cut into a block
+	BatchCut(keys []string)
+}
+
 // Receiver defines a sink for the ordered broadcast messages
 type Receiver interface {
 	// Ordered should be invoked sequentially as messages are ordered
@@ -35,16 +46,20 @@ type receiver struct {
 	sharedConfigFetcher   OrdererConfigFetcher
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
+	keyIndex              KeyIndex
+	pendingKeys           []string
 
 	PendingBatchStartTime time.Time
 	ChannelID             string
 	Metrics               *Metrics
 }
 
-// NewReceiverImpl creates a Receiver implementation based on the given configtxorderer manager
-func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher, metrics *Metrics) Receiver {
+// NewReceiverImpl creates a Receiver implementation based on the given configtxorderer manager.
+// If keyIndex is not nil, the messages whose idempotency keys were already cut are dropped.
+func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher, keyIndex KeyIndex, metrics *Metrics) Receiver {
 	return &receiver{
 		sharedConfigFetcher: sharedConfigFetcher,
+		keyIndex:            keyIndex,
 		Metrics:             metrics,
 		ChannelID:           channelID,
 	}
@@ -67,6 +82,12 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 //
 // Note that messageBatches can not be greater than 2.
 func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool) {
+	key := r.idempotencyKey(msg)
+	if key != "" && r.duplicate(key) {
+		logger.Debugf("Dropping message whose idempotency key was already cut on channel %s", r.ChannelID)
+		return nil, len(r.pendingBatch) > 0
+	}
+
 	if len(r.pendingBatch) == 0 {
 		// We are beginning a new batch, mark the time
 		r.PendingBatchStartTime = time.Now()
@@ -91,6 +112,15 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 
 		// create new batch with single message
 		messageBatches = append(messageBatches, []*cb.Envelope{msg})
+		if r.keyIndex != nil {
+			// the batch is recorded even without a key, so that the batches
+			// stay aligned with the blocks written
+			var keys []string
+			if key != "" {
+				keys = []string{key}
+			}
+			r.keyIndex.BatchCut(keys)
+		}
 
 		// Record that this batch took no time to fill
 		r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(0)
@@ -111,6 +141,9 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	logger.Debugf("Enqueuing message into batch")
 	r.pendingBatch = append(r.pendingBatch, msg)
 	r.pendingBatchSizeBytes += messageSizeBytes
+	if key != "" {
+		r.pendingKeys = append(r.pendingKeys, key)
+	}
 	pending = true
 
 	if uint32(len(r.pendingBatch)) >= batchSize.MaxMessageCount {
@@ -130,11 +163,39 @@ func (r *receiver) Cut() []*cb.Envelope {
 	}
 	r.PendingBatchStartTime = time.Time{}
 	batch := r.pendingBatch
+	if r.keyIndex != nil && len(batch) > 0 {
+		r.keyIndex.BatchCut(r.pendingKeys)
+	}
 	r.pendingBatch = nil
 	r.pendingBatchSizeBytes = 0
+	r.pendingKeys = nil
 	return batch
 }
 
+// idempotencyKey returns the idempotency key of the message, or the empty
+// string if the receiver does not deduplicate messages or the message bears
+// no key.
+func (r *receiver) idempotencyKey(msg *cb.Envelope) string {
+	if r.keyIndex == nil {
+		return ""
+	}
+	key, err := idempotency.Key(msg)
+	if err != nil {
+		logger.Warningf("Could not extract the idempotency key of a message on channel %s: %s", r.ChannelID, err)
+		return ""
+	}
+	return key
+}
+
+func (r *receiver) duplicate(key string) bool {
+	for _, pendingKey := range r.pendingKeys {
+		if pendingKey == key {
+			return true
+		}
+	}
+	return r.keyIndex.Duplicate(key)
+}
+
 func messageSizeBytes(message *cb.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
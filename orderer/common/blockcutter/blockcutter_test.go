@@ -14,6 +14,8 @@ import (
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
+	"github.com/hyperledger/fabric/orderer/common/idempotency"
+	"github.com/hyperledger/fabric/protoutil"
 )
 
 var _ = Describe("Blockcutter", func() {
@@ -37,7 +39,7 @@ var _ = Describe("Blockcutter", func() {
 			BlockFillDuration: fakeBlockFillDuration,
 		}
 
-		bc = blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, metrics)
+		bc = blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, nil, metrics)
 	})
 
 	Describe("Ordered", func() {
@@ -173,6 +175,91 @@ var _ = Describe("Blockcutter", func() {
 			})
 		})
 
+		Context("when the receiver deduplicates idempotency keys", func() {
+			var (
+				keyIndex *idempotency.Index
+				keyed    *cb.Envelope
+			)
+
+			BeforeEach(func() {
+				keyIndex = idempotency.NewIndex(10)
+				bc = blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, keyIndex, metrics)
+				keyed = &cb.Envelope{
+					Payload: protoutil.MarshalOrPanic(&cb.Payload{
+						Header: &cb.Header{
+							ChannelHeader:   protoutil.MarshalOrPanic(&cb.ChannelHeader{ChannelId: "mychannel", IdempotencyKey: "key"}),
+							SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte("creator")}),
+						},
+					}),
+				}
+				fakeConfig.BatchSizeReturns(&ab.BatchSize{
+					MaxMessageCount:   2,
+					PreferredMaxBytes: 1000,
+				})
+			})
+
+			It("drops the duplicates of a pending message", func() {
+				batches, pending := bc.Ordered(keyed)
+				Expect(batches).To(BeEmpty())
+				Expect(pending).To(BeTrue())
+
+				batches, pending = bc.Ordered(keyed)
+				Expect(batches).To(BeEmpty())
+				Expect(pending).To(BeTrue())
+
+				Expect(bc.Cut()).To(HaveLen(1))
+			})
+
+			It("drops the duplicates of a cut message", func() {
+				bc.Ordered(keyed)
+				bc.Cut()
+
+				batches, pending := bc.Ordered(keyed)
+				Expect(batches).To(BeEmpty())
+				Expect(pending).To(BeFalse())
+
+				key, err := idempotency.Key(keyed)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(keyIndex.Duplicate(key)).To(BeTrue())
+			})
+
+			It("does not drop messages without keys", func() {
+				bc.Ordered(message)
+				batches, pending := bc.Ordered(message)
+				Expect(batches).To(HaveLen(1))
+				Expect(batches[0]).To(HaveLen(2))
+				Expect(pending).To(BeFalse())
+			})
+
+			It("keeps the keys in flight until their own block is written", func() {
+				oversized := &cb.Envelope{Payload: make([]byte, 2000)}
+				batches, pending := bc.Ordered(oversized)
+				Expect(batches).To(HaveLen(1))
+				Expect(pending).To(BeFalse())
+
+				bc.Ordered(keyed)
+				keyedBatch := bc.Cut()
+				Expect(keyedBatch).To(HaveLen(1))
+
+				// the block of the keyless oversized message is written first
+				block := protoutil.NewBlock(1, nil)
+				block.Data.Data = [][]byte{protoutil.MarshalOrPanic(oversized)}
+				keyIndex.BlockWritten(block)
+
+				key, err := idempotency.Key(keyed)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(keyIndex.Duplicate(key)).To(BeTrue())
+				batches, pending = bc.Ordered(keyed)
+				Expect(batches).To(BeEmpty())
+				Expect(pending).To(BeFalse())
+
+				block = protoutil.NewBlock(2, nil)
+				block.Data.Data = [][]byte{protoutil.MarshalOrPanic(keyedBatch[0])}
+				keyIndex.BlockWritten(block)
+				Expect(keyIndex.Ordered(key)).To(BeTrue())
+			})
+		})
+
 		Context("when the orderer config cannot be retrieved", func() {
 			BeforeEach(func() {
 				fakeConfigFetcher.OrdererConfigReturns(nil, false)
@@ -13,7 +13,10 @@ import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/common/idempotency"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/pkg/errors"
 )
@@ -56,10 +59,27 @@ type Consenter interface {
 	WaitReady() error
 }
 
+// IdempotencyIndexer is implemented by the channel supports which deduplicate
+// the envelopes bearing an idempotency key
+type IdempotencyIndexer interface {
+	// IdempotencyIndex returns the idempotency keys of the last blocks of the
+	// channel, or nil if the channel does not deduplicate envelopes
+	IdempotencyIndex() *idempotency.Index
+}
+
+// DefaultIdempotencyTimeout is the time the Handler waits for an envelope
+// bearing an idempotency key to be written in a block, when the Handler has
+// no IdempotencyTimeout
+const DefaultIdempotencyTimeout = 30 * time.Second
+
 // Handler is designed to handle connections from Broadcast AB gRPC service
 type Handler struct {
 	SupportRegistrar ChannelSupportRegistrar
 	Metrics          *Metrics
+	Tracer           *tracing.Tracer
+	// IdempotencyTimeout is the time to wait for an envelope bearing an
+	// idempotency key to be written in a block before responding
+	IdempotencyTimeout time.Duration
 }
 
 // Handle reads requests from a Broadcast stream, processes them, and returns the responses to the stream
@@ -97,6 +117,7 @@ type MetricsTracker struct {
 	ValidateDuration  time.Duration
 	ChannelID         string
 	TxType            string
+	TxID              string
 	Metrics           *Metrics
 }
 
@@ -110,11 +131,15 @@ func (mt *MetricsTracker) Record(resp *ab.BroadcastResponse) {
 	if mt.ValidateDuration == 0 {
 		mt.EndValidate()
 	}
-	mt.Metrics.ValidateDuration.With(labels...).Observe(mt.ValidateDuration.Seconds())
+	var exemplar map[string]string
+	if mt.TxID != "" {
+		exemplar = map[string]string{"txid": mt.TxID}
+	}
+	metrics.ObserveWithExemplar(mt.Metrics.ValidateDuration.With(labels...), mt.ValidateDuration.Seconds(), exemplar)
 
 	if mt.EnqueueStartTime != (time.Time{}) {
 		enqueueDuration := time.Since(mt.EnqueueStartTime)
-		mt.Metrics.EnqueueDuration.With(labels...).Observe(enqueueDuration.Seconds())
+		metrics.ObserveWithExemplar(mt.Metrics.EnqueueDuration.With(labels...), enqueueDuration.Seconds(), exemplar)
 	}
 
 	mt.Metrics.ProcessedCount.With(labels...).Add(1)
@@ -139,11 +164,13 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 		TxType:    "unknown",
 		Metrics:   bh.Metrics,
 	}
+	var span *tracing.Span
 	defer func() {
 		// This looks a little unnecessary, but if done directly as
 		// a defer, resp gets the (always nil) current state of resp
 		// and not the return value
 		tracker.Record(resp)
+		finishSpan(span, resp)
 	}()
 	tracker.BeginValidate()
 
@@ -151,33 +178,65 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 	if chdr != nil {
 		tracker.ChannelID = chdr.ChannelId
 		tracker.TxType = cb.HeaderType(chdr.Type).String()
+		tracker.TxID = chdr.TxId
+		span = bh.Tracer.StartSpan(chdr.TxId, "orderer.Broadcast", tracing.SpanKindServer,
+			"fabric.channel", chdr.ChannelId,
+			"fabric.tx_type", tracker.TxType,
+		)
 	}
 	if err != nil {
 		logger.Warningf("[channel: %s] Could not get message processor for serving %s: %s", tracker.ChannelID, addr, err)
-		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+		return &ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()}
 	}
 
 	if !isConfig {
 		logger.Debugf("[channel: %s] Broadcast is processing normal message from %s with txid '%s' of type %s", chdr.ChannelId, addr, chdr.TxId, cb.HeaderType_name[chdr.Type])
 
+		validateSpan := span.StartChild("orderer.Validate")
 		configSeq, err := processor.ProcessNormalMsg(msg)
+		validateSpan.Finish(err)
 		if err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s because of error: %s", chdr.ChannelId, addr, err)
 			return &ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()}
 		}
 		tracker.EndValidate()
 
+		keyIndex, key, err := bh.idempotencyKey(msg, processor)
+		if err != nil {
+			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s because of error extracting its idempotency key: %s", chdr.ChannelId, addr, err)
+			return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+		}
+		if key != "" && keyIndex.Ordered(key) {
+			bh.Metrics.DuplicatesCount.With("channel", chdr.ChannelId).Add(1)
+			logger.Infof("[channel: %s] Broadcast of message with txid '%s' from %s duplicates the idempotency key '%s' of a message already ordered", chdr.ChannelId, chdr.TxId, addr, chdr.IdempotencyKey)
+			return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
+		}
+
 		tracker.BeginEnqueue()
+		enqueueSpan := span.StartChild("orderer.Enqueue")
 		if err = processor.WaitReady(); err != nil {
+			enqueueSpan.Finish(err)
 			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
 			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
 		}
 
+		var written <-chan struct{}
+		if key != "" {
+			var stopWaiting func()
+			written, stopWaiting = keyIndex.Await(key)
+			defer stopWaiting()
+		}
+
 		err = processor.Order(msg, configSeq)
+		enqueueSpan.Finish(err)
 		if err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with SERVICE_UNAVAILABLE: rejected by Order: %s", chdr.ChannelId, addr, err)
 			return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}
 		}
+
+		if written != nil {
+			return bh.awaitWritten(written, chdr, addr)
+		}
 	} else { // isConfig
 		logger.Debugf("[channel: %s] Broadcast is processing config update message from %s", chdr.ChannelId, addr)
 
@@ -206,6 +265,45 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 	return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
 }
 
+// idempotencyKey returns the idempotency key of a normal message and the
+// index it is deduplicated against, or the empty key if the channel does not
+// deduplicate messages or the message bears no key. The config updates are
+// not deduplicated, as the config envelopes they are ordered as do not bear
+// their keys, and retrying an applied config update fails its validation.
+func (bh *Handler) idempotencyKey(msg *cb.Envelope, processor ChannelSupport) (*idempotency.Index, string, error) {
+	indexer, ok := processor.(IdempotencyIndexer)
+	if !ok {
+		return nil, "", nil
+	}
+	keyIndex := indexer.IdempotencyIndex()
+	if keyIndex == nil {
+		return nil, "", nil
+	}
+	key, err := idempotency.Key(msg)
+	return keyIndex, key, err
+}
+
+// awaitWritten waits for a message bearing an idempotency key to be written
+// in a block. A client which is answered SERVICE_UNAVAILABLE should retry
+// with the same key, as the message may still be written.
+func (bh *Handler) awaitWritten(written <-chan struct{}, chdr *cb.ChannelHeader, addr string) *ab.BroadcastResponse {
+	timeout := bh.IdempotencyTimeout
+	if timeout == 0 {
+		timeout = DefaultIdempotencyTimeout
+	}
+	select {
+	case <-written:
+		logger.Debugf("[channel: %s] Message with txid '%s' from %s was written in a block", chdr.ChannelId, chdr.TxId, addr)
+		return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
+	case <-time.After(timeout):
+		logger.Warningf("[channel: %s] Message with txid '%s' from %s was not written in a block within %s", chdr.ChannelId, chdr.TxId, addr, timeout)
+		return &ab.BroadcastResponse{
+			Status: cb.Status_SERVICE_UNAVAILABLE,
+			Info:   "message was not written in a block in time, retry with the same idempotency key",
+		}
+	}
+}
+
 // ClassifyError converts an error type into a status code.
 func ClassifyError(err error) cb.Status {
 	switch errors.Cause(err) {
@@ -213,9 +311,18 @@ func ClassifyError(err error) cb.Status {
 		return cb.Status_NOT_FOUND
 	case msgprocessor.ErrPermissionDenied:
 		return cb.Status_FORBIDDEN
-	case msgprocessor.ErrMaintenanceMode:
+	case msgprocessor.ErrMaintenanceMode, msgprocessor.ErrStorageQuotaExceeded:
 		return cb.Status_SERVICE_UNAVAILABLE
 	default:
 		return cb.Status_BAD_REQUEST
 	}
 }
+
+func finishSpan(span *tracing.Span, resp *ab.BroadcastResponse) {
+	span.SetAttributes("fabric.broadcast_status", resp.Status.String())
+	if resp.Status != cb.Status_SUCCESS {
+		span.Finish(errors.New(resp.Info))
+		return
+	}
+	span.Finish(nil)
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	. "github.com/onsi/ginkgo"
@@ -17,9 +18,15 @@ import (
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/metrics/prometheus"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/broadcast/mock"
+	"github.com/hyperledger/fabric/orderer/common/idempotency"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
 )
 
 var _ = Describe("Broadcast", func() {
@@ -128,6 +135,92 @@ var _ = Describe("Broadcast", func() {
 			Expect(proto.Equal(fakeABServer.SendArgsForCall(0), &ab.BroadcastResponse{Status: cb.Status_SUCCESS})).To(BeTrue())
 		})
 
+		Context("when tracing is enabled", func() {
+			var spans *spanRecorder
+
+			BeforeEach(func() {
+				spans = &spanRecorder{}
+				handler.Tracer = tracing.NewTracer(spans)
+				fakeSupportRegistrar.BroadcastChannelSupportReturns(&cb.ChannelHeader{
+					Type:      3,
+					ChannelId: "fake-channel",
+					TxId:      "fake-txid",
+				}, false, fakeSupport, nil)
+			})
+
+			It("records the spans of the broadcast of the message", func() {
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(spans.spans).To(HaveLen(3))
+				validate, enqueue, broadcast := spans.spans[0], spans.spans[1], spans.spans[2]
+				Expect(validate.Name).To(Equal("orderer.Validate"))
+				Expect(enqueue.Name).To(Equal("orderer.Enqueue"))
+				Expect(broadcast.Name).To(Equal("orderer.Broadcast"))
+				Expect(broadcast.TraceID).To(Equal(tracing.TraceIDFromTxID("fake-txid")))
+				Expect(broadcast.Kind).To(Equal(tracing.SpanKindServer))
+				Expect(broadcast.Error).To(BeEmpty())
+				Expect(broadcast.Attributes).To(ConsistOf(
+					tracing.Attribute{Key: "fabric.tx_id", Value: "fake-txid"},
+					tracing.Attribute{Key: "fabric.channel", Value: "fake-channel"},
+					tracing.Attribute{Key: "fabric.tx_type", Value: "ENDORSER_TRANSACTION"},
+					tracing.Attribute{Key: "fabric.broadcast_status", Value: "SUCCESS"},
+				))
+				Expect(validate.ParentSpanID).To(Equal(broadcast.SpanID))
+				Expect(enqueue.ParentSpanID).To(Equal(broadcast.SpanID))
+			})
+
+			Context("when the consenter cannot enqueue the message", func() {
+				BeforeEach(func() {
+					fakeSupport.OrderReturns(fmt.Errorf("consenter-error"))
+				})
+
+				It("records the failure in the spans", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(spans.spans).To(HaveLen(3))
+					Expect(spans.spans[1].Error).To(Equal("consenter-error"))
+					Expect(spans.spans[2].Error).To(Equal("consenter-error"))
+					Expect(spans.spans[2].Attributes).To(ContainElement(
+						tracing.Attribute{Key: "fabric.broadcast_status", Value: "SERVICE_UNAVAILABLE"},
+					))
+				})
+			})
+		})
+
+		Context("when the histograms retain exemplars", func() {
+			var exemplars *prometheus.Exemplars
+
+			BeforeEach(func() {
+				registry := prom.NewRegistry()
+				prom.DefaultRegisterer = registry
+				prom.DefaultGatherer = registry
+
+				exemplars = prometheus.NewExemplars()
+				handler.Metrics = broadcast.NewMetrics(&prometheus.Provider{Exemplars: exemplars})
+				fakeSupportRegistrar.BroadcastChannelSupportReturns(&cb.ChannelHeader{
+					Type:      3,
+					ChannelId: "fake-channel",
+					TxId:      "fake-txid",
+				}, false, fakeSupport, nil)
+			})
+
+			It("links the durations to the transaction", func() {
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				result := exemplars.Exemplars()
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Metric).To(Equal("broadcast_enqueue_duration"))
+				Expect(result[1].Metric).To(Equal("broadcast_validate_duration"))
+				for _, e := range result {
+					Expect(e.Labels).To(HaveKeyWithValue("channel", "fake-channel"))
+					Expect(e.ExemplarLabels).To(Equal(map[string]string{"txid": "fake-txid"}))
+				}
+			})
+		})
+
 		Context("when the channel support cannot be retrieved", func() {
 			BeforeEach(func() {
 				fakeSupportRegistrar.BroadcastChannelSupportReturns(&cb.ChannelHeader{
@@ -167,6 +260,26 @@ var _ = Describe("Broadcast", func() {
 				})
 			})
 
+			Context("when the error cause is msgprocessor.ErrStorageQuotaExceeded", func() {
+				BeforeEach(func() {
+					fakeSupportRegistrar.BroadcastChannelSupportReturns(&cb.ChannelHeader{
+						Type:      3,
+						ChannelId: "fake-channel",
+					}, false, nil, errors.WithMessage(msgprocessor.ErrStorageQuotaExceeded, "channel fake-channel is full"))
+				})
+
+				It("returns the error with service unavailable status", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeABServer.SendCallCount()).To(Equal(1))
+					Expect(proto.Equal(
+						fakeABServer.SendArgsForCall(0),
+						&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "channel fake-channel is full: channel storage quota exceeded"}),
+					).To(BeTrue())
+				})
+			})
+
 		})
 
 		Context("when the receive from the client fails", func() {
@@ -398,4 +511,193 @@ var _ = Describe("Broadcast", func() {
 			})
 		})
 	})
+
+	Describe("ProcessMessage with idempotency keys", func() {
+		var (
+			fakeSupport           *mock.ChannelSupport
+			keyIndex              *idempotency.Index
+			fakeDuplicatesCounter *mock.MetricsCounter
+			chdr                  *cb.ChannelHeader
+		)
+
+		envelope := func(creator string) *cb.Envelope {
+			return &cb.Envelope{
+				Payload: protoutil.MarshalOrPanic(&cb.Payload{
+					Header: &cb.Header{
+						ChannelHeader:   protoutil.MarshalOrPanic(chdr),
+						SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte(creator)}),
+					},
+				}),
+			}
+		}
+
+		writeBlock := func(envs ...*cb.Envelope) {
+			block := protoutil.NewBlock(1, nil)
+			for _, env := range envs {
+				block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+			}
+			keyIndex.BlockWritten(block)
+		}
+
+		BeforeEach(func() {
+			fakeDuplicatesCounter = &mock.MetricsCounter{}
+			fakeDuplicatesCounter.WithReturns(fakeDuplicatesCounter)
+			handler.Metrics.DuplicatesCount = fakeDuplicatesCounter
+			handler.IdempotencyTimeout = time.Second
+
+			keyIndex = idempotency.NewIndex(10)
+			fakeSupport = &mock.ChannelSupport{}
+			fakeSupport.ProcessNormalMsgReturns(5, nil)
+			fakeSupport.OrderStub = func(env *cb.Envelope, _ uint64) error {
+				writeBlock(env)
+				return nil
+			}
+
+			chdr = &cb.ChannelHeader{
+				Type:           3,
+				ChannelId:      "fake-channel",
+				IdempotencyKey: "key",
+			}
+			fakeSupportRegistrar.BroadcastChannelSupportReturns(chdr, false, indexedChannelSupport{ChannelSupport: fakeSupport, keyIndex: keyIndex}, nil)
+		})
+
+		It("responds once the message is written in a block", func() {
+			writing := make(chan *cb.Envelope, 1)
+			fakeSupport.OrderStub = func(env *cb.Envelope, _ uint64) error {
+				writing <- env
+				return nil
+			}
+			responses := make(chan *ab.BroadcastResponse, 1)
+			go func() { responses <- handler.ProcessMessage(envelope("creator"), "addr") }()
+			Consistently(responses).ShouldNot(Receive())
+
+			writeBlock(<-writing)
+			Eventually(responses).Should(Receive(Equal(&ab.BroadcastResponse{Status: cb.Status_SUCCESS})))
+		})
+
+		It("does not order a message already written in a block", func() {
+			writeBlock(envelope("creator"))
+
+			resp := handler.ProcessMessage(envelope("creator"), "addr")
+			Expect(resp).To(Equal(&ab.BroadcastResponse{Status: cb.Status_SUCCESS}))
+			Expect(fakeSupport.OrderCallCount()).To(Equal(0))
+			Expect(fakeDuplicatesCounter.AddCallCount()).To(Equal(1))
+			Expect(fakeDuplicatesCounter.WithArgsForCall(0)).To(Equal([]string{"channel", "fake-channel"}))
+		})
+
+		It("orders the message again once its block leaves the window", func() {
+			writeBlock(envelope("creator"))
+			for i := 0; i < 10; i++ {
+				writeBlock()
+			}
+
+			resp := handler.ProcessMessage(envelope("creator"), "addr")
+			Expect(resp).To(Equal(&ab.BroadcastResponse{Status: cb.Status_SUCCESS}))
+			Expect(fakeSupport.OrderCallCount()).To(Equal(1))
+		})
+
+		It("scopes the keys by creator", func() {
+			writeBlock(envelope("creator"))
+
+			resp := handler.ProcessMessage(envelope("other-creator"), "addr")
+			Expect(resp).To(Equal(&ab.BroadcastResponse{Status: cb.Status_SUCCESS}))
+			Expect(fakeSupport.OrderCallCount()).To(Equal(1))
+		})
+
+		It("orders messages without a key every time", func() {
+			chdr.IdempotencyKey = ""
+			writeBlock(envelope("creator"))
+
+			handler.ProcessMessage(envelope("creator"), "addr")
+			handler.ProcessMessage(envelope("creator"), "addr")
+			Expect(fakeSupport.OrderCallCount()).To(Equal(2))
+		})
+
+		Context("when the message is not written in time", func() {
+			BeforeEach(func() {
+				handler.IdempotencyTimeout = 10 * time.Millisecond
+				fakeSupport.OrderReturns(nil)
+			})
+
+			It("responds that the message should be retried", func() {
+				resp := handler.ProcessMessage(envelope("creator"), "addr")
+				Expect(resp.Status).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+				Expect(resp.Info).To(ContainSubstring("retry with the same idempotency key"))
+			})
+		})
+
+		Context("when the message is rejected", func() {
+			BeforeEach(func() {
+				fakeSupport.OrderStub = func(env *cb.Envelope, _ uint64) error {
+					if fakeSupport.OrderCallCount() == 1 {
+						return errors.New("not leader")
+					}
+					writeBlock(env)
+					return nil
+				}
+			})
+
+			It("orders the message again when it is retried", func() {
+				resp := handler.ProcessMessage(envelope("creator"), "addr")
+				Expect(resp.Status).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+				resp = handler.ProcessMessage(envelope("creator"), "addr")
+				Expect(resp.Status).To(Equal(cb.Status_SUCCESS))
+				Expect(fakeSupport.OrderCallCount()).To(Equal(2))
+				Expect(fakeDuplicatesCounter.AddCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the channel does not deduplicate messages", func() {
+			BeforeEach(func() {
+				fakeSupportRegistrar.BroadcastChannelSupportReturns(chdr, false, fakeSupport, nil)
+				fakeSupport.OrderReturns(nil)
+			})
+
+			It("responds once the message is enqueued", func() {
+				resp := handler.ProcessMessage(envelope("creator"), "addr")
+				Expect(resp).To(Equal(&ab.BroadcastResponse{Status: cb.Status_SUCCESS}))
+				Expect(fakeSupport.OrderCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the message is a config update", func() {
+			BeforeEach(func() {
+				fakeSupportRegistrar.BroadcastChannelSupportReturns(chdr, true, indexedChannelSupport{ChannelSupport: fakeSupport, keyIndex: keyIndex}, nil)
+				fakeSupport.ProcessConfigUpdateMsgReturns(&cb.Envelope{}, 3, nil)
+			})
+
+			It("does not deduplicate it", func() {
+				writeBlock(envelope("creator"))
+
+				resp := handler.ProcessMessage(envelope("creator"), "addr")
+				Expect(resp).To(Equal(&ab.BroadcastResponse{Status: cb.Status_SUCCESS}))
+				Expect(fakeSupport.ConfigureCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the payload of the message is malformed", func() {
+			It("rejects the message", func() {
+				resp := handler.ProcessMessage(&cb.Envelope{Payload: []byte("garbage")}, "addr")
+				Expect(resp.Status).To(Equal(cb.Status_BAD_REQUEST))
+				Expect(fakeSupport.OrderCallCount()).To(Equal(0))
+			})
+		})
+	})
 })
+
+type indexedChannelSupport struct {
+	*mock.ChannelSupport
+	keyIndex *idempotency.Index
+}
+
+func (s indexedChannelSupport) IdempotencyIndex() *idempotency.Index {
+	return s.keyIndex
+}
+
+type spanRecorder struct {
+	spans []*tracing.Span
+}
+
+func (r *spanRecorder) Export(span *tracing.Span) {
+	r.spans = append(r.spans, span)
+}
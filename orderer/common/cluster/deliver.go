@@ -40,6 +40,8 @@ type BlockPuller struct {
 	Dialer              Dialer
 	VerifyBlockSequence BlockSequenceVerifier
 	Endpoints           []EndpointCriteria
+	// Throttle limits the catch-up traffic, and may be shared among BlockPullers
+	Throttle *CatchUpThrottle
 	// Internal state
 	stream       *ImpatientStream
 	blockBuff    []*common.Block
@@ -128,7 +130,10 @@ func (p *BlockPuller) tryFetchBlock(seq uint64) *common.Block {
 	}
 	// Else, buffer is empty. So we need to pull blocks
 	// to re-fill it.
-	if err := p.pullBlocks(seq, reConnected); err != nil {
+	p.Throttle.Acquire()
+	err := p.pullBlocks(seq, reConnected)
+	p.Throttle.Release()
+	if err != nil {
 		p.Logger.Errorf("Failed pulling blocks: %v", err)
 		// Something went wrong, disconnect. and return nil
 		p.Close()
@@ -187,6 +192,7 @@ func (p *BlockPuller) pullBlocks(seq uint64, reConnected bool) error {
 		}
 		size := blockSize(block)
 		totalSize += size
+		p.Throttle.Consume(p.Channel, size)
 		p.blockBuff = append(p.blockBuff, block)
 		nextExpectedSequence++
 		p.Logger.Infof("Got block [%d] of size %d KB from %s", seq, size/1024, p.endpoint)
@@ -419,6 +425,9 @@ func extractBlockFromResponse(resp *orderer.DeliverResponse) (*common.Block, err
 		if t.Status == common.Status_SERVICE_UNAVAILABLE {
 			return nil, ErrServiceUnavailable
 		}
+		if t.Status == common.Status_GONE {
+			return nil, ErrGone
+		}
 		return nil, errors.Errorf("faulty node, received: %v", resp)
 	default:
 		return nil, errors.Errorf("response is of type %v, but expected a block", reflect.TypeOf(resp.Type))
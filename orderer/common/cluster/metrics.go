@@ -82,6 +82,40 @@ var (
 		LabelNames:   []string{"host", "channel"},
 		StatsdFormat: "%{#fqname}.%{host}.%{channel}",
 	}
+
+	CatchUpActivePullsOpts = metrics.GaugeOpts{
+		Namespace:    "cluster",
+		Subsystem:    "catchup",
+		Name:         "active_pulls",
+		Help:         "Count of block pulls in progress for catching up with the cluster.",
+		StatsdFormat: "%{#fqname}",
+	}
+
+	CatchUpPendingPullsOpts = metrics.GaugeOpts{
+		Namespace:    "cluster",
+		Subsystem:    "catchup",
+		Name:         "pending_pulls",
+		Help:         "Count of block pulls waiting for the concurrent catch-up limit.",
+		StatsdFormat: "%{#fqname}",
+	}
+
+	CatchUpReceivedBytesOpts = metrics.CounterOpts{
+		Namespace:    "cluster",
+		Subsystem:    "catchup",
+		Name:         "received_bytes",
+		Help:         "The number of bytes of blocks pulled for catching up with the cluster.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	CatchUpThrottleDurationOpts = metrics.HistogramOpts{
+		Namespace:    "cluster",
+		Subsystem:    "catchup",
+		Name:         "throttle_duration",
+		Help:         "The time block pulls are delayed to honor the catch-up bandwidth limit in seconds.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
 
 // Metrics defines the metrics for the cluster.
@@ -441,6 +441,9 @@ var ErrForbidden = errors.New("forbidden pulling the channel")
 // ErrServiceUnavailable denotes that an ordering node is not servicing at the moment.
 var ErrServiceUnavailable = errors.New("service unavailable")
 
+// ErrGone denotes that an ordering node no longer retains the blocks requested.
+var ErrGone = errors.New("the requested blocks are pruned")
+
 // ErrNotInChannel denotes that an ordering node is not in the channel
 var ErrNotInChannel = errors.New("not in the channel")
 
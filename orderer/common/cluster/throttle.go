@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+// CatchUpThrottle limits the bandwidth consumed and the number of block pulls
+// performed concurrently by an ordering node that catches up with the rest of
+// the cluster, so that its catch-up traffic doesn't saturate the uplink of the
+// nodes it pulls from.
+// A single CatchUpThrottle is meant to be shared among the block pullers of all
+// channels. A nil CatchUpThrottle imposes no limits.
+type CatchUpThrottle struct {
+	maxBytesPerSecond int
+	slots             chan struct{}
+	metrics           *CatchUpMetrics
+
+	now   func() time.Time
+	sleep func(time.Duration)
+
+	lock sync.Mutex
+	// budgetAt is the point in time up to which the bandwidth has already been consumed
+	budgetAt time.Time
+}
+
+// NewCatchUpThrottle creates a CatchUpThrottle that caps the catch-up traffic to
+// maxBytesPerSecond, and the number of concurrent block pulls to maxConcurrentPulls.
+// A non-positive value means the corresponding limit is not enforced.
+func NewCatchUpThrottle(maxBytesPerSecond, maxConcurrentPulls int, provider MetricsProvider) *CatchUpThrottle {
+	t := &CatchUpThrottle{
+		maxBytesPerSecond: maxBytesPerSecond,
+		metrics:           NewCatchUpMetrics(provider),
+		now:               time.Now,
+		sleep:             time.Sleep,
+	}
+	if maxConcurrentPulls > 0 {
+		t.slots = make(chan struct{}, maxConcurrentPulls)
+	}
+	return t
+}
+
+// Acquire blocks until the number of block pulls in progress
+// is below the concurrent pull limit, and then registers a new pull.
+func (t *CatchUpThrottle) Acquire() {
+	if t == nil {
+		return
+	}
+	if t.slots != nil {
+		t.metrics.PendingPulls.Add(1)
+		t.slots <- struct{}{}
+		t.metrics.PendingPulls.Add(-1)
+	}
+	t.metrics.ActivePulls.Add(1)
+}
+
+// Release unregisters a block pull previously registered by Acquire.
+func (t *CatchUpThrottle) Release() {
+	if t == nil {
+		return
+	}
+	t.metrics.ActivePulls.Add(-1)
+	if t.slots != nil {
+		<-t.slots
+	}
+}
+
+// Consume accounts for the given amount of bytes received for the given channel,
+// and blocks for as long as needed in order to honor the bandwidth limit.
+// Up to a second worth of traffic may be consumed in a burst without blocking.
+func (t *CatchUpThrottle) Consume(channel string, bytes int) {
+	if t == nil {
+		return
+	}
+	t.metrics.ReceivedBytes.With("channel", channel).Add(float64(bytes))
+	if t.maxBytesPerSecond <= 0 {
+		return
+	}
+
+	t.lock.Lock()
+	now := t.now()
+	if burstStart := now.Add(-time.Second); t.budgetAt.Before(burstStart) {
+		t.budgetAt = burstStart
+	}
+	t.budgetAt = t.budgetAt.Add(time.Duration(float64(bytes) / float64(t.maxBytesPerSecond) * float64(time.Second)))
+	wait := t.budgetAt.Sub(now)
+	t.lock.Unlock()
+
+	if wait <= 0 {
+		return
+	}
+	t.metrics.ThrottleDuration.With("channel", channel).Observe(wait.Seconds())
+	t.sleep(wait)
+}
+
+// CatchUpMetrics defines the metrics of the catch-up traffic of the cluster.
+type CatchUpMetrics struct {
+	ActivePulls      metrics.Gauge
+	PendingPulls     metrics.Gauge
+	ReceivedBytes    metrics.Counter
+	ThrottleDuration metrics.Histogram
+}
+
+// NewCatchUpMetrics initializes new metrics for the catch-up traffic of the cluster.
+func NewCatchUpMetrics(provider MetricsProvider) *CatchUpMetrics {
+	return &CatchUpMetrics{
+		ActivePulls:      provider.NewGauge(CatchUpActivePullsOpts),
+		PendingPulls:     provider.NewGauge(CatchUpPendingPullsOpts),
+		ReceivedBytes:    provider.NewCounter(CatchUpReceivedBytesOpts),
+		ThrottleDuration: provider.NewHistogram(CatchUpThrottleDurationOpts),
+	}
+}
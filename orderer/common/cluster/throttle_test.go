@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/stretchr/testify/assert"
+)
+
+type catchUpMetrics struct {
+	provider         *metricsfakes.Provider
+	activePulls      *metricsfakes.Gauge
+	pendingPulls     *metricsfakes.Gauge
+	receivedBytes    *metricsfakes.Counter
+	throttleDuration *metricsfakes.Histogram
+}
+
+func newCatchUpMetrics() *catchUpMetrics {
+	cm := &catchUpMetrics{
+		provider:         &metricsfakes.Provider{},
+		activePulls:      &metricsfakes.Gauge{},
+		pendingPulls:     &metricsfakes.Gauge{},
+		receivedBytes:    &metricsfakes.Counter{},
+		throttleDuration: &metricsfakes.Histogram{},
+	}
+	cm.receivedBytes.WithReturns(cm.receivedBytes)
+	cm.throttleDuration.WithReturns(cm.throttleDuration)
+	cm.provider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
+		if opts.Name == cluster.CatchUpActivePullsOpts.Name {
+			return cm.activePulls
+		}
+		return cm.pendingPulls
+	}
+	cm.provider.NewCounterReturns(cm.receivedBytes)
+	cm.provider.NewHistogramReturns(cm.throttleDuration)
+	return cm
+}
+
+func gaugeValue(g *metricsfakes.Gauge) float64 {
+	var value float64
+	for i := 0; i < g.AddCallCount(); i++ {
+		value += g.AddArgsForCall(i)
+	}
+	return value
+}
+
+func TestCatchUpThrottleNil(t *testing.T) {
+	var throttle *cluster.CatchUpThrottle
+	throttle.Acquire()
+	throttle.Consume("mychannel", 1024)
+	throttle.Release()
+}
+
+func TestCatchUpThrottleUnlimited(t *testing.T) {
+	cm := newCatchUpMetrics()
+	throttle := cluster.NewCatchUpThrottle(0, 0, cm.provider)
+
+	throttle.Acquire()
+	throttle.Acquire()
+	assert.Equal(t, float64(2), gaugeValue(cm.activePulls))
+	assert.Equal(t, 0, cm.pendingPulls.AddCallCount())
+
+	throttle.Consume("mychannel", 1024*1024*1024)
+	assert.Equal(t, 0, cm.throttleDuration.ObserveCallCount())
+	assert.Equal(t, 1, cm.receivedBytes.AddCallCount())
+	assert.Equal(t, float64(1024*1024*1024), cm.receivedBytes.AddArgsForCall(0))
+	assert.Equal(t, []string{"channel", "mychannel"}, cm.receivedBytes.WithArgsForCall(0))
+
+	throttle.Release()
+	throttle.Release()
+	assert.Equal(t, float64(0), gaugeValue(cm.activePulls))
+}
+
+func TestCatchUpThrottleBandwidth(t *testing.T) {
+	cm := newCatchUpMetrics()
+	throttle := cluster.NewCatchUpThrottle(10000, 0, cm.provider)
+
+	// A second worth of traffic is consumed in a burst
+	start := time.Now()
+	throttle.Consume("mychannel", 10000)
+	assert.Equal(t, 0, cm.throttleDuration.ObserveCallCount())
+
+	// Any further traffic is delayed
+	throttle.Consume("mychannel", 1000)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	assert.Equal(t, 1, cm.throttleDuration.ObserveCallCount())
+	assert.InDelta(t, 0.1, cm.throttleDuration.ObserveArgsForCall(0), 0.05)
+	assert.Equal(t, []string{"channel", "mychannel"}, cm.throttleDuration.WithArgsForCall(0))
+	assert.Equal(t, 2, cm.receivedBytes.AddCallCount())
+}
+
+func TestCatchUpThrottleConcurrentPulls(t *testing.T) {
+	cm := newCatchUpMetrics()
+	throttle := cluster.NewCatchUpThrottle(0, 1, cm.provider)
+
+	throttle.Acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		throttle.Acquire()
+		close(acquired)
+	}()
+
+	assert.Eventually(t, func() bool { return gaugeValue(cm.pendingPulls) == 1 }, time.Second, 10*time.Millisecond)
+	select {
+	case <-acquired:
+		t.Fatal("second pull should wait for the first one to be released")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	throttle.Release()
+	<-acquired
+	assert.Equal(t, float64(0), gaugeValue(cm.pendingPulls))
+	assert.Equal(t, float64(1), gaugeValue(cm.activePulls))
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensusmetadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+)
+
+// URL is the path of the operations endpoint reporting the consensus metadata of a channel.
+const URL = "/consensus/metadata"
+
+var logger = flogging.MustGetLogger("orderer.common.consensusmetadata")
+
+//go:generate counterfeiter -o mock/config_block_source.go -fake-name ConfigBlockSource . ConfigBlockSource
+
+// ConfigBlockSource provides the last config block of the channels of the orderer.
+type ConfigBlockSource interface {
+	// LastConfigBlock returns the last config block of the channel, or types.ErrChannelNotExist
+	// if the orderer has no ledger for the channel.
+	LastConfigBlock(channelID string) (*cb.Block, error)
+}
+
+// InspectFunc decodes the consensus metadata configured by a config block.
+type InspectFunc func(configBlock *cb.Block) (*etcdraft.ConsensusMetadataReport, error)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler reports the consensus metadata of a channel as of its last config block: the
+// consenter set, the Raft options and the next expected config, along with the verification
+// of the consenter certificates against the TLS CA certificates of the orderer organizations
+// and whether the TLS certificate of the orderer belongs to the consenter set. It helps to
+// debug the orderers failing to find their certificate in the consenter set of a channel.
+type Handler struct {
+	Blocks  ConfigBlockSource
+	Inspect InspectFunc
+}
+
+// ServeHTTP writes the consensus metadata of the channel given by the channel query parameter
+// as JSON.
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		resp.Header().Set("Allow", http.MethodGet)
+		resp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	channelID := req.URL.Query().Get("channel")
+	if channelID == "" {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{Error: "channel parameter is required"})
+		return
+	}
+
+	block, err := h.Blocks.LastConfigBlock(channelID)
+	if err == types.ErrChannelNotExist {
+		h.sendResponse(resp, http.StatusNotFound, &errorResponse{Error: fmt.Sprintf("channel %s does not exist", channelID)})
+		return
+	}
+	if err != nil {
+		logger.Errorw("failed to retrieve the last config block", "channel", channelID, "error", err)
+		h.sendResponse(resp, http.StatusInternalServerError, &errorResponse{
+			Error: fmt.Sprintf("failed to retrieve the last config block: %s", err),
+		})
+		return
+	}
+
+	report, err := h.Inspect(block)
+	if err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, &errorResponse{
+			Error: fmt.Sprintf("failed to inspect the consensus metadata of channel %s: %s", channelID, err),
+		})
+		return
+	}
+	h.sendResponse(resp, http.StatusOK, report)
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorf("failed to encode consensus metadata response, err: %s", err)
+	}
+}
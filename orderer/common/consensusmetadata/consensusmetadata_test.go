@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensusmetadata_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/orderer/common/consensusmetadata"
+	"github.com/hyperledger/fabric/orderer/common/consensusmetadata/mock"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeHTTP(t *testing.T) {
+	blocks := &mock.ConfigBlockSource{}
+	configBlock := protoutil.NewBlock(3, nil)
+	blocks.LastConfigBlockReturns(configBlock, nil)
+	var inspected *cb.Block
+	inspectErr := error(nil)
+	handler := &consensusmetadata.Handler{
+		Blocks: blocks,
+		Inspect: func(block *cb.Block) (*etcdraft.ConsensusMetadataReport, error) {
+			inspected = block
+			if inspectErr != nil {
+				return nil, inspectErr
+			}
+			inConsenterSet := false
+			return &etcdraft.ConsensusMetadataReport{
+				ChannelID:          "mychannel",
+				ConfigBlockNumber:  block.Header.Number,
+				ConfigSequence:     2,
+				NextConfigSequence: 3,
+				ConsensusType:      "etcdraft",
+				ConsensusState:     "STATE_NORMAL",
+				NextConsenterID:    4,
+				Consenters: []*etcdraft.ConsenterReport{
+					{ID: 1, Host: "orderer1", Port: 7050, ClientTLSCert: &etcdraft.CertificateReport{VerificationError: "x509: certificate signed by unknown authority"}},
+				},
+				InConsenterSet: &inConsenterSet,
+			}, nil
+		},
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusmetadata.URL+"?channel=mychannel", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "mychannel", blocks.LastConfigBlockArgsForCall(0))
+	assert.Equal(t, configBlock, inspected)
+	assert.JSONEq(t, `{
+		"channel_id": "mychannel",
+		"config_block_number": 3,
+		"config_sequence": 2,
+		"next_config_sequence": 3,
+		"consensus_type": "etcdraft",
+		"consensus_state": "STATE_NORMAL",
+		"options": null,
+		"next_consenter_id": 4,
+		"raft_index": 0,
+		"consenters": [{
+			"id": 1,
+			"host": "orderer1",
+			"port": 7050,
+			"client_tls_cert": {
+				"not_before": "0001-01-01T00:00:00Z",
+				"not_after": "0001-01-01T00:00:00Z",
+				"verified": false,
+				"verification_error": "x509: certificate signed by unknown authority"
+			},
+			"server_tls_cert": null
+		}],
+		"in_consenter_set": false
+	}`, resp.Body.String())
+
+	inspectErr = errors.New("consensus type of the channel is solo, not etcdraft")
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusmetadata.URL+"?channel=mychannel", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"failed to inspect the consensus metadata of channel mychannel: consensus type of the channel is solo, not etcdraft"}`, resp.Body.String())
+
+	blocks.LastConfigBlockReturns(nil, types.ErrChannelNotExist)
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusmetadata.URL+"?channel=other", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.JSONEq(t, `{"error":"channel other does not exist"}`, resp.Body.String())
+
+	blocks.LastConfigBlockReturns(nil, errors.New("ledger is closed"))
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusmetadata.URL+"?channel=mychannel", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"error":"failed to retrieve the last config block: ledger is closed"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusmetadata.URL, nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"channel parameter is required"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, consensusmetadata.URL, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, http.MethodGet, resp.Header().Get("Allow"))
+}
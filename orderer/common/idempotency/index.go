@@ -0,0 +1,210 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"sync"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("orderer.common.idempotency")
+
+// Key returns the key an envelope is deduplicated by, or the empty string if
+// the channel header of the envelope bears no idempotency key. The keys are
+// scoped by the creator of the envelopes, so that clients can't suppress the
+// envelopes of each other.
+func Key(env *cb.Envelope) (string, error) {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", err
+	}
+	if payload.Header == nil {
+		return "", errors.New("missing header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+	if chdr.IdempotencyKey == "" {
+		return "", nil
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return "", err
+	}
+	return string(sm3.SumSM3(shdr.Creator)) + chdr.IdempotencyKey, nil
+}
+
+// Index holds the idempotency keys of the envelopes of the last blocks of a
+// channel. As the keys are derived from the blocks, all the ordering nodes of
+// the channel agree on them, whichever node the envelopes were broadcast to
+// and whether or not the nodes restarted in between.
+//
+// The node cutting the blocks also holds the keys of the batches it cut and
+// whose blocks are not written yet, so that the envelopes duplicating them
+// are not cut again while their blocks are being agreed upon. The blocks are
+// written in the order the batches are cut, hence each written block retires
+// the oldest batch. The batches a node cut but which never make it into a
+// block, e.g. when it loses the leadership, are retired by the next blocks.
+type Index struct {
+	lookback uint64
+
+	mutex sync.Mutex
+	// blocks holds the keys of the written blocks in the window, from the
+	// oldest to the newest
+	blocks [][]string
+	// written counts the envelopes of the written blocks in the window, by key
+	written map[string]int
+	// batches holds the keys of the batches cut and not yet written, from
+	// the oldest to the newest
+	batches [][]string
+	// inFlight counts the envelopes of the batches cut and not yet written, by key
+	inFlight map[string]int
+	waiters  map[string][]chan struct{}
+}
+
+// NewIndex creates an index of the idempotency keys of the envelopes of the
+// last lookback blocks of a channel.
+func NewIndex(lookback uint64) *Index {
+	return &Index{
+		lookback: lookback,
+		written:  map[string]int{},
+		inFlight: map[string]int{},
+		waiters:  map[string][]chan struct{}{},
+	}
+}
+
+// Load indexes the last blocks of the ledger.
+func (i *Index) Load(reader blockledger.Reader) {
+	height := reader.Height()
+	start := uint64(0)
+	if height > i.lookback {
+		start = height - i.lookback
+	}
+	for number := start; number < height; number++ {
+		block := blockledger.GetBlock(reader, number)
+		if block == nil {
+			logger.Panicf("Could not retrieve block [%d] to index its idempotency keys", number)
+		}
+		i.BlockWritten(block)
+	}
+}
+
+// Ordered returns whether an envelope with the key is in a written block of
+// the window.
+func (i *Index) Ordered(key string) bool {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.written[key] > 0
+}
+
+// Duplicate returns whether an envelope with the key is in a written block
+// of the window or in a batch cut and not yet written.
+func (i *Index) Duplicate(key string) bool {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.written[key] > 0 || i.inFlight[key] > 0
+}
+
+// BatchCut records the keys of a batch cut into a block.
+func (i *Index) BatchCut(keys []string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.batches = append(i.batches, keys)
+	for _, key := range keys {
+		i.inFlight[key]++
+	}
+}
+
+// BlockWritten records the keys of the envelopes of a written block, and
+// forgets the keys of the block which leaves the window. The waiters of the
+// keys of the block are released.
+func (i *Index) BlockWritten(block *cb.Block) {
+	var keys []string
+	for _, data := range block.GetData().GetData() {
+		env, err := protoutil.UnmarshalEnvelope(data)
+		if err != nil {
+			continue
+		}
+		key, err := Key(env)
+		if err != nil || key == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	// config blocks are not cut from batches
+	if len(i.batches) > 0 && !protoutil.IsConfigBlock(block) {
+		for _, key := range i.batches[0] {
+			decrement(i.inFlight, key)
+		}
+		i.batches = i.batches[1:]
+	}
+
+	i.blocks = append(i.blocks, keys)
+	for _, key := range keys {
+		i.written[key]++
+		for _, waiter := range i.waiters[key] {
+			close(waiter)
+		}
+		delete(i.waiters, key)
+	}
+	for uint64(len(i.blocks)) > i.lookback {
+		for _, key := range i.blocks[0] {
+			decrement(i.written, key)
+		}
+		i.blocks = i.blocks[1:]
+	}
+}
+
+// Await returns a channel which is closed once an envelope with the key is
+// written in a block, and a function to be called once the caller stops
+// waiting.
+func (i *Index) Await(key string) (<-chan struct{}, func()) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	waiter := make(chan struct{})
+	if i.written[key] > 0 {
+		close(waiter)
+		return waiter, func() {}
+	}
+	i.waiters[key] = append(i.waiters[key], waiter)
+	return waiter, func() {
+		i.mutex.Lock()
+		defer i.mutex.Unlock()
+		waiters := i.waiters[key]
+		for j, w := range waiters {
+			if w == waiter {
+				waiters = append(waiters[:j], waiters[j+1:]...)
+				break
+			}
+		}
+		if len(waiters) == 0 {
+			delete(i.waiters, key)
+			return
+		}
+		i.waiters[key] = waiters
+	}
+}
+
+func decrement(counts map[string]int, key string) {
+	if counts[key] <= 1 {
+		delete(counts, key)
+		return
+	}
+	counts[key]--
+}
@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envelope(creator, key string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader:   protoutil.MarshalOrPanic(&cb.ChannelHeader{ChannelId: "mychannel", IdempotencyKey: key}),
+				SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte(creator)}),
+			},
+		}),
+	}
+}
+
+func block(number uint64, envs ...*cb.Envelope) *cb.Block {
+	block := protoutil.NewBlock(number, nil)
+	for _, env := range envs {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+	}
+	return block
+}
+
+func mustKey(t *testing.T, env *cb.Envelope) string {
+	key, err := Key(env)
+	require.NoError(t, err)
+	return key
+}
+
+func TestKey(t *testing.T) {
+	key := mustKey(t, envelope("creator", "key"))
+	assert.NotEmpty(t, key)
+	assert.Equal(t, key, mustKey(t, envelope("creator", "key")))
+	assert.NotEqual(t, key, mustKey(t, envelope("other-creator", "key")))
+	assert.NotEqual(t, key, mustKey(t, envelope("creator", "other-key")))
+	assert.Empty(t, mustKey(t, envelope("creator", "")))
+
+	_, err := Key(&cb.Envelope{Payload: []byte("garbage")})
+	assert.Error(t, err)
+
+	_, err = Key(&cb.Envelope{Payload: protoutil.MarshalOrPanic(&cb.Payload{})})
+	assert.EqualError(t, err, "missing header")
+}
+
+func TestIndexWindow(t *testing.T) {
+	index := NewIndex(2)
+	key := mustKey(t, envelope("creator", "key"))
+
+	assert.False(t, index.Ordered(key))
+	index.BlockWritten(block(0, envelope("creator", "key")))
+	assert.True(t, index.Ordered(key))
+	assert.True(t, index.Duplicate(key))
+
+	index.BlockWritten(block(1))
+	assert.True(t, index.Ordered(key))
+
+	index.BlockWritten(block(2))
+	assert.False(t, index.Ordered(key))
+	assert.False(t, index.Duplicate(key))
+}
+
+func TestIndexBatches(t *testing.T) {
+	index := NewIndex(10)
+	key := mustKey(t, envelope("creator", "key"))
+	otherKey := mustKey(t, envelope("creator", "other-key"))
+
+	index.BatchCut([]string{key})
+	assert.True(t, index.Duplicate(key))
+	assert.False(t, index.Ordered(key))
+
+	// the batch of another node is written first, e.g. after a leader change
+	index.BlockWritten(block(1, envelope("creator", "other-key")))
+	assert.False(t, index.Duplicate(key))
+	assert.True(t, index.Duplicate(otherKey))
+
+	index.BatchCut([]string{key})
+	config := block(2, &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG)}),
+			},
+		}),
+	})
+	index.BlockWritten(config)
+	assert.True(t, index.Duplicate(key), "config blocks are not cut from batches")
+
+	index.BlockWritten(block(3, envelope("creator", "key")))
+	assert.True(t, index.Ordered(key))
+	assert.Empty(t, index.batches)
+	assert.Empty(t, index.inFlight)
+}
+
+func TestIndexAwait(t *testing.T) {
+	index := NewIndex(10)
+	key := mustKey(t, envelope("creator", "key"))
+
+	written, stopWaiting := index.Await(key)
+	select {
+	case <-written:
+		t.Fatal("key should not be written yet")
+	default:
+	}
+
+	index.BlockWritten(block(0, envelope("creator", "key")))
+	<-written
+	stopWaiting()
+	assert.Empty(t, index.waiters)
+
+	written, stopWaiting = index.Await(key)
+	<-written
+	stopWaiting()
+
+	written, stopWaiting = index.Await(mustKey(t, envelope("creator", "other-key")))
+	stopWaiting()
+	assert.Empty(t, index.waiters)
+	select {
+	case <-written:
+		t.Fatal("key should not be written")
+	default:
+	}
+}
+
+func TestIndexLoad(t *testing.T) {
+	dir := t.TempDir()
+	factory, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+	defer factory.Close()
+	ledger, err := factory.GetOrCreate("mychannel")
+	require.NoError(t, err)
+
+	genesis := block(0)
+	require.NoError(t, ledger.Append(genesis))
+	b1 := block(1, envelope("creator", "key"))
+	b1.Header.PreviousHash = protoutil.BlockHeaderHash(genesis.Header)
+	require.NoError(t, ledger.Append(b1))
+	b2 := block(2, envelope("creator", "other-key"))
+	b2.Header.PreviousHash = protoutil.BlockHeaderHash(b1.Header)
+	require.NoError(t, ledger.Append(b2))
+
+	index := NewIndex(1)
+	index.Load(ledger)
+	assert.False(t, index.Ordered(mustKey(t, envelope("creator", "key"))))
+	assert.True(t, index.Ordered(mustKey(t, envelope("creator", "other-key"))))
+
+	index = NewIndex(10)
+	index.Load(ledger)
+	assert.True(t, index.Ordered(mustKey(t, envelope("creator", "key"))))
+	assert.True(t, index.Ordered(mustKey(t, envelope("creator", "other-key"))))
+}
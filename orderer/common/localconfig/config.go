@@ -38,7 +38,12 @@ type TopLevel struct {
 	Consensus            interface{}
 	Operations           Operations
 	Metrics              Metrics
+	Tracing              Tracing
 	ChannelParticipation ChannelParticipation
+	ChannelHibernation   ChannelHibernation
+	Audit                Audit
+	StorageQuota         StorageQuota
+	Mirror               Mirror
 }
 
 // General contains config which should be common among all orderer types.
@@ -58,6 +63,11 @@ type General struct {
 	LocalMSPID        string
 	BCCSP             *bccsp.FactoryOpts
 	Authentication    Authentication
+	Enrollment        Enrollment
+	RemoteSigner      RemoteSigner
+	BlockTimestamp    BlockTimestamp
+	RangeAttestation  RangeAttestation
+	IdempotencyKeys   IdempotencyKeys
 	MaxRecvMsgSize    int32
 	MaxSendMsgSize    int32
 }
@@ -77,6 +87,8 @@ type Cluster struct {
 	ReplicationRetryTimeout              time.Duration
 	ReplicationBackgroundRefreshInterval time.Duration
 	ReplicationMaxRetries                int
+	ReplicationMaxBytesPerSecond         int
+	ReplicationMaxConcurrentPulls        int
 	SendBufferSize                       int
 	CertExpirationWarningThreshold       time.Duration
 	TLSHandshakeTimeShift                time.Duration
@@ -114,6 +126,59 @@ type Authentication struct {
 	NoExpirationChecks bool
 }
 
+// Enrollment contains configuration for the enrollment of the orderer
+// identities against a CA.
+type Enrollment struct {
+	Enabled       bool
+	URL           string
+	CAName        string
+	ID            string
+	Secret        string
+	KeyType       string
+	TLSProfile    string
+	CSRHosts      []string
+	RootCAs       []string
+	RenewBefore   time.Duration
+	CheckInterval time.Duration
+	Timeout       time.Duration
+}
+
+// RemoteSigner contains configuration for signing with the key of the local MSP
+// held by a remote signing service.
+type RemoteSigner struct {
+	Enabled           bool
+	Address           string
+	KeyID             string
+	Timeout           time.Duration
+	RootCAs           []string
+	ClientCertificate string
+	ClientPrivateKey  string
+}
+
+// BlockTimestamp contains configuration for the time attestations added by the
+// orderer to the metadata of the blocks it writes.
+type BlockTimestamp struct {
+	Enabled bool
+}
+
+// RangeAttestation contains configuration for the attestations added by the
+// orderer over ranges of blocks.
+type RangeAttestation struct {
+	Interval uint64
+}
+
+// IdempotencyKeys contains configuration for the deduplication of the
+// envelopes bearing an idempotency key.
+type IdempotencyKeys struct {
+	Enabled bool
+	// LookbackBlocks is the number of the last blocks of a channel whose
+	// keys the envelopes are deduplicated against.
+	LookbackBlocks uint64
+	// ResponseTimeout is the time to wait for an envelope to be written in
+	// a block before responding to its broadcast.
+	ResponseTimeout time.Duration
+}
+
 // Profile contains configuration for Go pprof profiling.
 type Profile struct {
 	Enabled bool
@@ -194,6 +259,14 @@ type Debug struct {
 type Operations struct {
 	ListenAddress string
 	TLS           TLS
+	LogSinks      LogSinks
+}
+
+// LogSinks restricts the destinations of the log sinks which may be added
+// through the operations endpoint.
+type LogSinks struct {
+	AllowedDirectories []string
+	AllowedHosts       []string
 }
 
 // Metrics configures the metrics provider for the orderer.
@@ -210,6 +283,97 @@ type Statsd struct {
 	Prefix        string
 }
 
+// Tracing configures the export of the spans recording the broadcast of the transactions
+// to the orderer.
+type Tracing struct {
+	Enabled       bool
+	Endpoint      string
+	FlushInterval time.Duration
+}
+
+// ChannelHibernation configures the release of the resources of the application channels
+// without activity, which are reactivated on the next request targeting them.
+type ChannelHibernation struct {
+	Enabled       bool
+	IdleTimeout   time.Duration
+	CheckInterval time.Duration
+}
+
+// Audit configures the hash chained log recording the config transactions, the channels
+// created and joined and the maintenance mode changes applied by the orderer.
+type Audit struct {
+	Enabled  bool
+	Location string
+}
+
+// StorageQuota configures the disk quotas of the ledgers of the channels. Warnings are
+// logged when a ledger exceeds its soft limit and the broadcasts of normal transactions
+// to the channel are rejected when it exceeds its hard limit. A zero limit is disabled.
+type StorageQuota struct {
+	Enabled       bool
+	CheckInterval time.Duration
+	SoftLimit     uint64
+	HardLimit     uint64
+	// Channels overrides the limits of some channels
+	Channels map[string]StorageQuotaLimits
+}
+
+// StorageQuotaLimits are the disk quotas of the ledger of a channel in bytes.
+type StorageQuotaLimits struct {
+	SoftLimit uint64
+	HardLimit uint64
+}
+
+// Limits returns the disk quotas of the ledger of a channel
+func (q StorageQuota) Limits(channelID string) StorageQuotaLimits {
+	if limits, ok := q.Channels[channelID]; ok {
+		return limits
+	}
+	return StorageQuotaLimits{SoftLimit: q.SoftLimit, HardLimit: q.HardLimit}
+}
+
+func (q StorageQuota) validate() error {
+	if q.HardLimit != 0 && q.SoftLimit > q.HardLimit {
+		return fmt.Errorf("StorageQuota.SoftLimit (%d) exceeds StorageQuota.HardLimit (%d)", q.SoftLimit, q.HardLimit)
+	}
+	for channelID, limits := range q.Channels {
+		if limits.HardLimit != 0 && limits.SoftLimit > limits.HardLimit {
+			return fmt.Errorf("StorageQuota.Channels.%s.SoftLimit (%d) exceeds StorageQuota.Channels.%s.HardLimit (%d)",
+				channelID, limits.SoftLimit, channelID, limits.HardLimit)
+		}
+	}
+	return nil
+}
+
+// Mirror configures the asynchronous mirroring of the ledgers of channels to the orderers
+// of a standby cluster over mutual TLS, and the reception of the mirrored ledgers when the
+// orderer is a standby orderer. The client TLS certificate defaults to General.TLS.
+type Mirror struct {
+	Enabled           bool
+	Channels          []string
+	Standbys          []string
+	RootCAs           []string
+	ClientCertificate string
+	ClientPrivateKey  string
+	DialTimeout       time.Duration
+	RetryInterval     time.Duration
+	Standby           MirrorStandby
+}
+
+// MirrorStandby configures the listener receiving the mirrored ledgers and where they are
+// kept until the standby orderer is promoted. The mirrored ledgers must start with the
+// genesis blocks held in GenesisBlocks.
+type MirrorStandby struct {
+	Enabled           bool
+	ListenAddress     string
+	ListenPort        uint16
+	ServerCertificate string
+	ServerPrivateKey  string
+	ClientRootCAs     []string
+	Location          string
+	GenesisBlocks     string
+}
+
 // ChannelParticipation provides the channel participation API configuration for the orderer.
 // Channel participation uses the same ListenAddress and TLS settings of the Operations service.
 type ChannelParticipation struct {
@@ -245,6 +409,20 @@ var Defaults = TopLevel{
 		Authentication: Authentication{
 			TimeWindow: time.Duration(15 * time.Minute),
 		},
+		Enrollment: Enrollment{
+			KeyType:       "sm2",
+			TLSProfile:    "tls",
+			RenewBefore:   7 * 24 * time.Hour,
+			CheckInterval: time.Hour,
+			Timeout:       10 * time.Second,
+		},
+		RemoteSigner: RemoteSigner{
+			Timeout: 5 * time.Second,
+		},
+		IdempotencyKeys: IdempotencyKeys{
+			LookbackBlocks:  1000,
+			ResponseTimeout: 30 * time.Second,
+		},
 		MaxRecvMsgSize: comm.DefaultMaxRecvMsgSize,
 		MaxSendMsgSize: comm.DefaultMaxSendMsgSize,
 	},
@@ -294,10 +472,39 @@ var Defaults = TopLevel{
 	Metrics: Metrics{
 		Provider: "disabled",
 	},
+	Tracing: Tracing{
+		Enabled:       false,
+		Endpoint:      "http://127.0.0.1:4318/v1/traces",
+		FlushInterval: 5 * time.Second,
+	},
 	ChannelParticipation: ChannelParticipation{
 		Enabled:       false,
 		RemoveStorage: false,
 	},
+	ChannelHibernation: ChannelHibernation{
+		Enabled:       false,
+		IdleTimeout:   time.Hour,
+		CheckInterval: time.Minute,
+	},
+	Audit: Audit{
+		Enabled:  false,
+		Location: "/var/hyperledger/production/orderer/audit",
+	},
+	StorageQuota: StorageQuota{
+		Enabled:       false,
+		CheckInterval: time.Minute,
+	},
+	Mirror: Mirror{
+		Enabled:       false,
+		DialTimeout:   5 * time.Second,
+		RetryInterval: 10 * time.Second,
+		Standby: MirrorStandby{
+			Enabled:       false,
+			ListenAddress: "127.0.0.1",
+			ListenPort:    7060,
+			Location:      "/var/hyperledger/production/orderer/mirror",
+		},
+	},
 }
 
 // Load parses the orderer YAML file and environment, producing
@@ -373,12 +580,40 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		// Translate any paths for general TLS configuration
 		c.General.TLS.RootCAs = translateCAs(configDir, c.General.TLS.RootCAs)
 		c.General.TLS.ClientRootCAs = translateCAs(configDir, c.General.TLS.ClientRootCAs)
+		c.General.Enrollment.RootCAs = translateCAs(configDir, c.General.Enrollment.RootCAs)
+		c.General.RemoteSigner.RootCAs = translateCAs(configDir, c.General.RemoteSigner.RootCAs)
+		if c.General.RemoteSigner.ClientCertificate != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.General.RemoteSigner.ClientCertificate)
+		}
+		if c.General.RemoteSigner.ClientPrivateKey != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.General.RemoteSigner.ClientPrivateKey)
+		}
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.PrivateKey)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.BootstrapFile)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
 		// Translate file ledger location
 		coreconfig.TranslatePathInPlace(configDir, &c.FileLedger.Location)
+		coreconfig.TranslatePathInPlace(configDir, &c.Audit.Location)
+		// Translate any paths for the mirroring
+		c.Mirror.RootCAs = translateCAs(configDir, c.Mirror.RootCAs)
+		if c.Mirror.ClientCertificate != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.Mirror.ClientCertificate)
+		}
+		if c.Mirror.ClientPrivateKey != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.Mirror.ClientPrivateKey)
+		}
+		c.Mirror.Standby.ClientRootCAs = translateCAs(configDir, c.Mirror.Standby.ClientRootCAs)
+		if c.Mirror.Standby.ServerCertificate != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.Mirror.Standby.ServerCertificate)
+		}
+		if c.Mirror.Standby.ServerPrivateKey != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.Mirror.Standby.ServerPrivateKey)
+		}
+		coreconfig.TranslatePathInPlace(configDir, &c.Mirror.Standby.Location)
+		if c.Mirror.Standby.GenesisBlocks != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.Mirror.Standby.GenesisBlocks)
+		}
 	}()
 
 	for {
@@ -446,6 +681,40 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.LocalMSPID unset, setting to %s", Defaults.General.LocalMSPID)
 			c.General.LocalMSPID = Defaults.General.LocalMSPID
 
+		case c.ChannelHibernation.Enabled && c.ChannelHibernation.IdleTimeout == 0:
+			logger.Infof("ChannelHibernation.IdleTimeout unset, setting to %v", Defaults.ChannelHibernation.IdleTimeout)
+			c.ChannelHibernation.IdleTimeout = Defaults.ChannelHibernation.IdleTimeout
+		case c.ChannelHibernation.Enabled && c.ChannelHibernation.CheckInterval == 0:
+			logger.Infof("ChannelHibernation.CheckInterval unset, setting to %v", Defaults.ChannelHibernation.CheckInterval)
+			c.ChannelHibernation.CheckInterval = Defaults.ChannelHibernation.CheckInterval
+
+		case c.Audit.Enabled && c.Audit.Location == "":
+			logger.Infof("Audit.Location unset, setting to %s", Defaults.Audit.Location)
+			c.Audit.Location = Defaults.Audit.Location
+
+		case c.StorageQuota.Enabled && c.StorageQuota.CheckInterval == 0:
+			logger.Infof("StorageQuota.CheckInterval unset, setting to %v", Defaults.StorageQuota.CheckInterval)
+			c.StorageQuota.CheckInterval = Defaults.StorageQuota.CheckInterval
+		case c.StorageQuota.Enabled && c.StorageQuota.validate() != nil:
+			logger.Panicf("Invalid storage quota: %s", c.StorageQuota.validate())
+
+		case c.Mirror.Enabled && c.Mirror.DialTimeout == 0:
+			logger.Infof("Mirror.DialTimeout unset, setting to %v", Defaults.Mirror.DialTimeout)
+			c.Mirror.DialTimeout = Defaults.Mirror.DialTimeout
+		case c.Mirror.Enabled && c.Mirror.RetryInterval == 0:
+			logger.Infof("Mirror.RetryInterval unset, setting to %v", Defaults.Mirror.RetryInterval)
+			c.Mirror.RetryInterval = Defaults.Mirror.RetryInterval
+		case c.Mirror.Standby.Enabled && c.Mirror.Standby.ListenPort == 0:
+			logger.Infof("Mirror.Standby.ListenPort unset, setting to %v", Defaults.Mirror.Standby.ListenPort)
+			c.Mirror.Standby.ListenPort = Defaults.Mirror.Standby.ListenPort
+		case c.Mirror.Standby.Enabled && c.Mirror.Standby.Location == "":
+			logger.Infof("Mirror.Standby.Location unset, setting to %s", Defaults.Mirror.Standby.Location)
+			c.Mirror.Standby.Location = Defaults.Mirror.Standby.Location
+		case c.Mirror.Standby.Enabled && (c.Mirror.Standby.ServerCertificate == "" || c.Mirror.Standby.ServerPrivateKey == ""):
+			logger.Panicf("Mirror.Standby.ServerCertificate and Mirror.Standby.ServerPrivateKey must be set if Mirror.Standby.Enabled is set to true.")
+		case c.Mirror.Standby.Enabled && c.Mirror.Standby.GenesisBlocks == "":
+			logger.Panicf("Mirror.Standby.GenesisBlocks must be set if Mirror.Standby.Enabled is set to true.")
+
 		case c.General.Authentication.TimeWindow == 0:
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", Defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = Defaults.General.Authentication.TimeWindow
@@ -499,6 +768,13 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Version unset, setting to %v", Defaults.Kafka.Version)
 			c.Kafka.Version = Defaults.Kafka.Version
 
+		case c.General.IdempotencyKeys.LookbackBlocks == 0:
+			logger.Infof("General.IdempotencyKeys.LookbackBlocks unset, setting to %d", Defaults.General.IdempotencyKeys.LookbackBlocks)
+			c.General.IdempotencyKeys.LookbackBlocks = Defaults.General.IdempotencyKeys.LookbackBlocks
+		case c.General.IdempotencyKeys.ResponseTimeout == 0:
+			logger.Infof("General.IdempotencyKeys.ResponseTimeout unset, setting to %s", Defaults.General.IdempotencyKeys.ResponseTimeout)
+			c.General.IdempotencyKeys.ResponseTimeout = Defaults.General.IdempotencyKeys.ResponseTimeout
+
 		case c.General.MaxRecvMsgSize == 0:
 			logger.Infof("General.MaxRecvMsgSize is unset, setting to %v", Defaults.General.MaxRecvMsgSize)
 			c.General.MaxRecvMsgSize = Defaults.General.MaxRecvMsgSize
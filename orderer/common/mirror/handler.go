@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirror
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// URL is the path of the operations endpoint reporting the mirroring status.
+	URL = "/mirror"
+	// PromoteURL is the path of the operations endpoint promoting a standby
+	// orderer.
+	PromoteURL = "/mirror/promote"
+)
+
+// Status is the mirroring status of an orderer, which is a primary orderer,
+// a standby orderer or both.
+type Status struct {
+	Primary []SenderStatus `json:"primary,omitempty"`
+	Standby *StandbyStatus `json:"standby,omitempty"`
+}
+
+// Handler serves the mirroring status on GET requests to URL and promotes
+// the standby orderer on POST requests to PromoteURL.
+type Handler struct {
+	Mirror  *Mirror
+	Standby *Standby
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case URL:
+		if req.Method != http.MethodGet {
+			resp.Header().Set("Allow", http.MethodGet)
+			resp.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.serveStatus(resp)
+	case PromoteURL:
+		if req.Method != http.MethodPost {
+			resp.Header().Set("Allow", http.MethodPost)
+			resp.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.servePromote(resp)
+	default:
+		h.sendError(resp, http.StatusNotFound, errors.Errorf("unknown path '%s'", req.URL.Path))
+	}
+}
+
+func (h *Handler) serveStatus(resp http.ResponseWriter) {
+	status := &Status{}
+	if h.Mirror != nil {
+		status.Primary = h.Mirror.Status()
+	}
+	if h.Standby != nil {
+		standbyStatus, err := h.Standby.Status()
+		if err != nil {
+			h.sendError(resp, http.StatusInternalServerError, err)
+			return
+		}
+		status.Standby = standbyStatus
+	}
+	h.send(resp, http.StatusOK, status)
+}
+
+func (h *Handler) servePromote(resp http.ResponseWriter) {
+	if h.Standby == nil {
+		h.sendError(resp, http.StatusBadRequest, errors.New("the orderer is not a standby orderer"))
+		return
+	}
+	status, err := h.Standby.Promote()
+	if err != nil {
+		h.sendError(resp, http.StatusInternalServerError, err)
+		return
+	}
+	h.send(resp, http.StatusOK, status)
+}
+
+// Close stops the mirroring and releases the copies of the ledgers.
+func (h *Handler) Close() {
+	if h.Mirror != nil {
+		h.Mirror.Stop()
+	}
+	if h.Standby != nil {
+		h.Standby.Close()
+	}
+}
+
+func (h *Handler) sendError(resp http.ResponseWriter, code int, err error) {
+	h.send(resp, code, &types.ErrorResponse{Error: err.Error()})
+}
+
+func (h *Handler) send(resp http.ResponseWriter, code int, content interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(content); err != nil {
+		logger.Errorf("failed to encode mirror response, err: %s", err)
+	}
+}
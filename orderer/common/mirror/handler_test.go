@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	standby, cleanup := newStandby(t)
+	defer cleanup()
+	handler := &Handler{Standby: standby}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, URL, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	status := &Status{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), status))
+	assert.Nil(t, status.Primary)
+	assert.False(t, status.Standby.Promoted)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, PromoteURL, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, http.MethodPost, resp.Header().Get("Allow"))
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, PromoteURL, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	standbyStatus := &StandbyStatus{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), standbyStatus))
+	assert.True(t, standbyStatus.Promoted)
+
+	resp = httptest.NewRecorder()
+	(&Handler{Mirror: &Mirror{}}).ServeHTTP(resp, httptest.NewRequest(http.MethodPost, PromoteURL, nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	errResp := &types.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), errResp))
+	assert.Equal(t, "the orderer is not a standby orderer", errResp.Error)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirror
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var (
+	lagOpts = metrics.GaugeOpts{
+		Namespace:    "mirror",
+		Name:         "lag_blocks",
+		Help:         "The number of blocks of a channel not yet mirrored to a standby orderer.",
+		LabelNames:   []string{"channel", "standby"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{standby}",
+	}
+
+	blocksSentOpts = metrics.CounterOpts{
+		Namespace:    "mirror",
+		Name:         "blocks_sent",
+		Help:         "The number of blocks mirrored to a standby orderer.",
+		LabelNames:   []string{"channel", "standby"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{standby}",
+	}
+
+	blocksReceivedOpts = metrics.CounterOpts{
+		Namespace:    "mirror",
+		Name:         "blocks_received",
+		Help:         "The number of blocks received by a standby orderer.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+)
+
+// Metrics are the metrics of the mirroring.
+type Metrics struct {
+	Lag            metrics.Gauge
+	BlocksSent     metrics.Counter
+	BlocksReceived metrics.Counter
+}
+
+// NewMetrics creates the metrics of the mirroring.
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		Lag:            p.NewGauge(lagOpts),
+		BlocksSent:     p.NewCounter(blocksSentOpts),
+		BlocksReceived: p.NewCounter(blocksReceivedOpts),
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mirror asynchronously copies the blocks committed by the orderer
+// on selected channels to the orderers of a standby cluster, so that a
+// disaster recovery site holds an up to date copy of the ledgers without
+// participating in the consensus.
+//
+// The primary orderer streams the blocks of each mirrored channel to every
+// standby orderer over mutual (GM)TLS, starting at the height reported by
+// the standby.  The standby orderer checks that the first block of a channel
+// is the genesis block of the channel it holds, and that each next block
+// extends the hash chain of its copy of the ledger and satisfies the
+// BlockValidation policy of the channel before appending it.  The copies are
+// kept outside of the ledger of the standby orderer until it is promoted,
+// which seals them so that they can be served by the standby orderer once it
+// starts with them as its ledger.
+package mirror
+
+import (
+	"github.com/hyperledger/fabric/common/flogging"
+	"google.golang.org/grpc"
+)
+
+var logger = flogging.MustGetLogger("orderer.common.mirror")
+
+// ReplicateMethod is the full name of the gRPC method streaming the blocks
+// of a channel to a standby orderer.
+const ReplicateMethod = "/mirror.Mirror/Replicate"
+
+// Request is sent by the primary orderer. The first request of a stream
+// names the channel, the following ones each carry a marshaled block. The
+// requests and responses are encoded in JSON.
+type Request struct {
+	Channel string `json:"channel,omitempty"`
+	Block   []byte `json:"block,omitempty"`
+}
+
+// Response is sent by the standby orderer in reply to every request with the
+// height of its copy of the ledger of the channel.
+type Response struct {
+	Height uint64 `json:"height"`
+}
+
+// ReplicateServer is the server side of a Replicate stream.
+type ReplicateServer interface {
+	Send(*Response) error
+	Recv() (*Request, error)
+}
+
+// MirrorServer is implemented by the standby orderer.
+type MirrorServer interface {
+	Replicate(ReplicateServer) error
+}
+
+// RegisterMirrorServer registers the mirror service with a gRPC server.
+func RegisterMirrorServer(s *grpc.Server, srv MirrorServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "mirror.Mirror",
+	HandlerType: (*MirrorServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Replicate",
+			Handler:       replicateHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "mirror",
+}
+
+func replicateHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MirrorServer).Replicate(&replicateServer{ServerStream: stream})
+}
+
+type replicateServer struct {
+	grpc.ServerStream
+}
+
+func (s *replicateServer) Send(resp *Response) error {
+	return s.ServerStream.SendMsg(resp)
+}
+
+func (s *replicateServer) Recv() (*Request, error) {
+	req := &Request{}
+	if err := s.ServerStream.RecvMsg(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
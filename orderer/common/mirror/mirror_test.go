@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirror
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// configEnvelope returns a config transaction of the channel
+func configEnvelope(channelID string, sequence uint64) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG), ChannelId: channelID}),
+			},
+			Data: protoutil.MarshalOrPanic(&cb.ConfigEnvelope{Config: &cb.Config{Sequence: sequence}}),
+		}),
+	}
+}
+
+// dataEnvelope returns a normal transaction of the channel
+func dataEnvelope(channelID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), ChannelId: channelID}),
+			},
+			Data: []byte("data"),
+		}),
+	}
+}
+
+func genesisBlock(channelID string) *cb.Block {
+	block := protoutil.NewBlock(0, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(configEnvelope(channelID, 0))}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+	return block
+}
+
+// appendBlocks appends the genesis block of mychannel to an empty ledger,
+// and then blocks of normal transactions
+func appendBlocks(t *testing.T, ledger blockledger.ReadWriter, count int) {
+	for i := 0; i < count; i++ {
+		block := genesisBlock("mychannel")
+		if ledger.Height() > 0 {
+			block = blockledger.CreateNextBlock(ledger, []*cb.Envelope{dataEnvelope("mychannel")})
+		}
+		require.NoError(t, ledger.Append(block))
+	}
+}
+
+func newLedgers(t *testing.T) (blockledger.Factory, func()) {
+	dir, err := ioutil.TempDir("", "mirror-primary")
+	require.NoError(t, err)
+	ledgers, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+	return ledgers, func() {
+		ledgers.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+type verifierFunc func(sd []*protoutil.SignedData, config *cb.ConfigEnvelope) error
+
+func (f verifierFunc) VerifyBlockSignature(sd []*protoutil.SignedData, config *cb.ConfigEnvelope) error {
+	return f(sd, config)
+}
+
+// verifierFactory creates verifiers rejecting the blocks signed by forgers,
+// and records the sequences of the configs they are created from.
+type verifierFactory struct {
+	mutex     sync.Mutex
+	sequences []uint64
+}
+
+func (f *verifierFactory) VerifierFromConfig(config *cb.ConfigEnvelope, channel string) (cluster.BlockVerifier, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.sequences = append(f.sequences, config.Config.Sequence)
+	return verifierFunc(func(sd []*protoutil.SignedData, _ *cb.ConfigEnvelope) error {
+		for _, signedData := range sd {
+			if string(signedData.Identity) == "forger" {
+				return errors.New("signature set did not satisfy policy")
+			}
+		}
+		return nil
+	}), nil
+}
+
+// genesisBlocks writes the genesis block of mychannel to a directory
+func genesisBlocks(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "mirror-genesis")
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "mychannel.block"), protoutil.MarshalOrPanic(genesisBlock("mychannel")), 0600)
+	require.NoError(t, err)
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func newStandby(t *testing.T) (*Standby, func()) {
+	dir, err := ioutil.TempDir("", "mirror-standby")
+	require.NoError(t, err)
+	genesisDir, removeGenesis := genesisBlocks(t)
+	standby, err := NewStandby(dir, genesisDir, &verifierFactory{}, NewMetrics(&disabled.Provider{}))
+	require.NoError(t, err)
+	return standby, func() {
+		standby.Close()
+		os.RemoveAll(dir)
+		removeGenesis()
+	}
+}
+
+func serve(t *testing.T, standby *Standby) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	RegisterMirrorServer(srv, standby)
+	go srv.Serve(lis)
+	return lis.Addr().String(), srv.Stop
+}
+
+func standbyHeight(t *testing.T, standby *Standby, channelID string) func() uint64 {
+	return func() uint64 {
+		status, err := standby.Status()
+		require.NoError(t, err)
+		for _, ch := range status.Channels {
+			if ch.Channel == channelID {
+				return ch.Height
+			}
+		}
+		return 0
+	}
+}
+
+func TestMirror(t *testing.T) {
+	ledgers, cleanup := newLedgers(t)
+	defer cleanup()
+	ledger, err := ledgers.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	appendBlocks(t, ledger, 3)
+
+	standby, cleanup := newStandby(t)
+	defer cleanup()
+	address, stop := serve(t, standby)
+	defer stop()
+
+	readers := ReadersFunc(func(channelID string) blockledger.Reader {
+		if channelID != "mychannel" {
+			return nil
+		}
+		return ledger
+	})
+	dialer := DialerFunc(func(address string) (*grpc.ClientConn, error) {
+		return grpc.Dial(address, grpc.WithInsecure(), grpc.WithBlock())
+	})
+	m := New([]string{"mychannel", "otherchannel"}, []string{address}, readers, dialer, 10*time.Millisecond, NewMetrics(&disabled.Provider{}))
+	m.Start()
+	defer m.Stop()
+
+	height := standbyHeight(t, standby, "mychannel")
+	assert.Eventually(t, func() bool { return height() == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	appendBlocks(t, ledger, 2)
+	assert.Eventually(t, func() bool { return height() == 5 }, 5*time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		statuses := m.Status()
+		return len(statuses) == 2 && statuses[0].Connected && statuses[0].Lag == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	statuses := m.Status()
+	assert.Equal(t, SenderStatus{Channel: "mychannel", Standby: address, Height: 5, StandbyHeight: 5, Connected: true}, statuses[0])
+	assert.Equal(t, "otherchannel", statuses[1].Channel)
+	assert.False(t, statuses[1].Connected)
+	assert.Equal(t, "orderer is not a member of channel otherchannel", statuses[1].Error)
+
+	status, err := standby.Promote()
+	require.NoError(t, err)
+	assert.True(t, status.Promoted)
+	assert.Equal(t, []ChannelStatus{{Channel: "mychannel", Height: 5}}, status.Channels)
+
+	appendBlocks(t, ledger, 1)
+	assert.Eventually(t, func() bool {
+		return m.Status()[0].Error != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(5), height())
+	assert.Equal(t, uint64(1), m.Status()[0].Lag)
+}
+
+func TestMirrorResumes(t *testing.T) {
+	ledgers, cleanup := newLedgers(t)
+	defer cleanup()
+	ledger, err := ledgers.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	appendBlocks(t, ledger, 2)
+
+	standby, cleanup := newStandby(t)
+	defer cleanup()
+	readers := ReadersFunc(func(string) blockledger.Reader { return ledger })
+	metrics := NewMetrics(&disabled.Provider{})
+
+	address, stop := serve(t, standby)
+	dialer := DialerFunc(func(address string) (*grpc.ClientConn, error) {
+		return grpc.Dial(address, grpc.WithInsecure(), grpc.WithBlock())
+	})
+	m := New([]string{"mychannel"}, []string{address}, readers, dialer, 10*time.Millisecond, metrics)
+	m.Start()
+	height := standbyHeight(t, standby, "mychannel")
+	assert.Eventually(t, func() bool { return height() == 2 }, 5*time.Second, 10*time.Millisecond)
+	m.Stop()
+	stop()
+
+	appendBlocks(t, ledger, 2)
+	address, stop = serve(t, standby)
+	defer stop()
+	m = New([]string{"mychannel"}, []string{address}, readers, dialer, 10*time.Millisecond, metrics)
+	m.Start()
+	defer m.Stop()
+	assert.Eventually(t, func() bool { return height() == 4 }, 5*time.Second, 10*time.Millisecond)
+}
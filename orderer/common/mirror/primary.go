@@ -0,0 +1,322 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirror
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Readers provides the ledgers of the channels of the primary orderer.
+type Readers interface {
+	// Reader returns the ledger of a channel, or nil if the orderer is not a
+	// member of the channel.
+	Reader(channelID string) blockledger.Reader
+}
+
+// ReadersFunc adapts a function to the Readers interface.
+type ReadersFunc func(channelID string) blockledger.Reader
+
+// Reader returns f(channelID).
+func (f ReadersFunc) Reader(channelID string) blockledger.Reader {
+	return f(channelID)
+}
+
+// Dialer connects to the standby orderers.
+type Dialer interface {
+	NewConnection(address string) (*grpc.ClientConn, error)
+}
+
+// DialerFunc adapts a function to the Dialer interface.
+type DialerFunc func(address string) (*grpc.ClientConn, error)
+
+// NewConnection returns f(address).
+func (f DialerFunc) NewConnection(address string) (*grpc.ClientConn, error) {
+	return f(address)
+}
+
+// SenderStatus is the mirroring status of a channel to a standby orderer.
+type SenderStatus struct {
+	Channel       string `json:"channel"`
+	Standby       string `json:"standby"`
+	Height        uint64 `json:"height"`
+	StandbyHeight uint64 `json:"standby_height"`
+	Lag           uint64 `json:"lag"`
+	Connected     bool   `json:"connected"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Mirror streams the blocks of the mirrored channels of the primary orderer
+// to the standby orderers.
+type Mirror struct {
+	senders []*sender
+}
+
+// New creates a mirror of the given channels to the standby orderers at the
+// given addresses. A failed stream is retried after retryInterval.
+func New(channels, standbys []string, readers Readers, dialer Dialer, retryInterval time.Duration, metrics *Metrics) *Mirror {
+	m := &Mirror{}
+	for _, channelID := range channels {
+		for _, standby := range standbys {
+			m.senders = append(m.senders, &sender{
+				channelID:     channelID,
+				standby:       standby,
+				readers:       readers,
+				dialer:        dialer,
+				retryInterval: retryInterval,
+				metrics:       metrics,
+				stop:          make(chan struct{}),
+				done:          make(chan struct{}),
+			})
+		}
+	}
+	return m
+}
+
+// Start starts mirroring the channels.
+func (m *Mirror) Start() {
+	for _, s := range m.senders {
+		go s.run()
+	}
+}
+
+// Stop stops mirroring the channels and waits for the streams to end.
+func (m *Mirror) Stop() {
+	for _, s := range m.senders {
+		s.halt()
+	}
+	for _, s := range m.senders {
+		<-s.done
+	}
+}
+
+// Status returns the mirroring status of every channel to every standby
+// orderer, sorted by channel and standby orderer.
+func (m *Mirror) Status() []SenderStatus {
+	statuses := make([]SenderStatus, 0, len(m.senders))
+	for _, s := range m.senders {
+		statuses = append(statuses, s.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Channel != statuses[j].Channel {
+			return statuses[i].Channel < statuses[j].Channel
+		}
+		return statuses[i].Standby < statuses[j].Standby
+	})
+	return statuses
+}
+
+// sender streams the blocks of a channel to a standby orderer.
+type sender struct {
+	channelID     string
+	standby       string
+	readers       Readers
+	dialer        Dialer
+	retryInterval time.Duration
+	metrics       *Metrics
+
+	mutex         sync.Mutex
+	standbyHeight uint64
+	connected     bool
+	lastErr       error
+	cancel        context.CancelFunc
+	iterator      blockledger.Iterator
+	stopOnce      sync.Once
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+func (s *sender) run() {
+	defer close(s.done)
+	for {
+		err := s.replicate()
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		logger.Warningf("Failed mirroring channel %s to %s, retrying in %s: %s", s.channelID, s.standby, s.retryInterval, err)
+		s.mutex.Lock()
+		s.connected = false
+		s.lastErr = err
+		s.mutex.Unlock()
+		s.reportLag()
+
+		select {
+		case <-time.After(s.retryInterval):
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *sender) halt() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.closeIterator()
+	})
+}
+
+// replicate streams the blocks of the channel until the stream fails or the
+// sender is stopped.
+func (s *sender) replicate() error {
+	reader := s.readers.Reader(s.channelID)
+	if reader == nil {
+		return errors.Errorf("orderer is not a member of channel %s", s.channelID)
+	}
+
+	conn, err := s.dialer.NewConnection(s.standby)
+	if err != nil {
+		return errors.WithMessage(err, "failed connecting to the standby orderer")
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if !s.setCancel(cancel) {
+		return nil
+	}
+
+	stream, err := conn.NewStream(ctx, &serviceDesc.Streams[0], ReplicateMethod, grpc.CallContentSubtype(comm.JSONCodecName))
+	if err != nil {
+		return errors.WithMessage(err, "failed opening the mirror stream")
+	}
+	height, err := exchange(stream, &Request{Channel: s.channelID})
+	if err != nil {
+		return err
+	}
+	s.acknowledge(height)
+	logger.Infof("Mirroring channel %s to %s from block %d", s.channelID, s.standby, height)
+
+	iterator, _ := reader.Iterator(&ab.SeekPosition{
+		Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: height}},
+	})
+	if !s.setIterator(iterator) {
+		iterator.Close()
+		return nil
+	}
+	defer func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.closeIterator()
+	}()
+
+	for {
+		block, status := iterator.Next()
+		if status != cb.Status_SUCCESS {
+			return errors.Errorf("failed reading block %d: %s", height, status)
+		}
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			return errors.Wrapf(err, "failed marshaling block %d", block.Header.Number)
+		}
+		height, err = exchange(stream, &Request{Block: blockBytes})
+		if err != nil {
+			return err
+		}
+		s.metrics.BlocksSent.With("channel", s.channelID, "standby", s.standby).Add(1)
+		s.acknowledge(height)
+	}
+}
+
+// exchange sends a request and returns the height in the response.
+func exchange(stream grpc.ClientStream, req *Request) (uint64, error) {
+	if err := stream.SendMsg(req); err != nil {
+		return 0, errors.WithMessage(err, "failed sending to the standby orderer")
+	}
+	resp := &Response{}
+	if err := stream.RecvMsg(resp); err != nil {
+		return 0, errors.WithMessage(err, "failed receiving from the standby orderer")
+	}
+	return resp.Height, nil
+}
+
+func (s *sender) setCancel(cancel context.CancelFunc) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	select {
+	case <-s.stop:
+		return false
+	default:
+		s.cancel = cancel
+		return true
+	}
+}
+
+func (s *sender) setIterator(iterator blockledger.Iterator) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	select {
+	case <-s.stop:
+		return false
+	default:
+		s.iterator = iterator
+		return true
+	}
+}
+
+// closeIterator unblocks the iterator over the ledger, if any. It must be
+// called with the mutex held.
+func (s *sender) closeIterator() {
+	if s.iterator != nil {
+		s.iterator.Close()
+		s.iterator = nil
+	}
+}
+
+func (s *sender) acknowledge(height uint64) {
+	s.mutex.Lock()
+	s.standbyHeight = height
+	s.connected = true
+	s.lastErr = nil
+	s.mutex.Unlock()
+	s.reportLag()
+}
+
+func (s *sender) reportLag() {
+	s.metrics.Lag.With("channel", s.channelID, "standby", s.standby).Set(float64(s.status().Lag))
+}
+
+func (s *sender) status() SenderStatus {
+	var height uint64
+	if reader := s.readers.Reader(s.channelID); reader != nil {
+		height = reader.Height()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	status := SenderStatus{
+		Channel:       s.channelID,
+		Standby:       s.standby,
+		Height:        height,
+		StandbyHeight: s.standbyHeight,
+		Connected:     s.connected,
+	}
+	if height > s.standbyHeight {
+		status.Lag = height - s.standbyHeight
+	}
+	if s.lastErr != nil {
+		status.Error = s.lastErr.Error()
+	}
+	return status
+}
@@ -0,0 +1,314 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirror
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// promotedFile is the file marking the copies of a standby orderer as
+// promoted.
+const promotedFile = "promoted"
+
+// ChannelStatus is the status of the copy of the ledger of a channel held by
+// a standby orderer.
+type ChannelStatus struct {
+	Channel string `json:"channel"`
+	Height  uint64 `json:"height"`
+}
+
+// StandbyStatus is the status of a standby orderer.
+type StandbyStatus struct {
+	Promoted bool            `json:"promoted"`
+	Location string          `json:"location"`
+	Channels []ChannelStatus `json:"channels"`
+}
+
+// VerifierFactory creates the verifiers of the signatures of the blocks of a
+// channel from the config of the channel.
+type VerifierFactory interface {
+	VerifierFromConfig(configuration *cb.ConfigEnvelope, channel string) (cluster.BlockVerifier, error)
+}
+
+// Standby receives the blocks of the channels mirrored by the primary
+// orderers and appends them to its copies of their ledgers.
+type Standby struct {
+	location      string
+	genesisBlocks string
+	verifiers     VerifierFactory
+	ledgers       blockledger.Factory
+	metrics       *Metrics
+
+	mutex     sync.RWMutex
+	promoted  bool
+	streaming map[string]bool
+}
+
+// NewStandby creates a standby orderer keeping the copies of the ledgers in
+// the given directory. The copies must start with the genesis blocks in the
+// genesisBlocks directory, the next blocks are verified by the verifiers.
+func NewStandby(location, genesisBlocks string, verifiers VerifierFactory, metrics *Metrics) (*Standby, error) {
+	// The ledgers of the orderer register the block storage metrics already
+	ledgers, err := fileledger.New(location, &disabled.Provider{})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed opening the mirrored ledgers in %s", location)
+	}
+	_, err = os.Stat(filepath.Join(location, promotedFile))
+	if err != nil && !os.IsNotExist(err) {
+		ledgers.Close()
+		return nil, errors.Wrap(err, "failed checking the promotion of the standby orderer")
+	}
+
+	return &Standby{
+		location:      location,
+		genesisBlocks: genesisBlocks,
+		verifiers:     verifiers,
+		ledgers:       ledgers,
+		metrics:       metrics,
+		promoted:      err == nil,
+		streaming:     map[string]bool{},
+	}, nil
+}
+
+// Replicate appends the blocks sent by a primary orderer to the copy of the
+// ledger of a channel.
+func (s *Standby) Replicate(stream ReplicateServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	channelID := req.Channel
+	if channelID == "" {
+		return errors.New("the first request must name the channel")
+	}
+
+	ledger, err := s.openStream(channelID)
+	if err != nil {
+		return err
+	}
+	defer s.closeStream(channelID)
+	verifier, err := s.lastVerifier(channelID, ledger)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Receiving the blocks of channel %s from block %d", channelID, ledger.Height())
+
+	for {
+		if err := stream.Send(&Response{Height: ledger.Height()}); err != nil {
+			return err
+		}
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if verifier, err = s.append(channelID, ledger, verifier, req.Block); err != nil {
+			logger.Warningf("Rejected block of channel %s: %s", channelID, err)
+			return err
+		}
+	}
+}
+
+// lastVerifier returns the verifier of the signatures of the blocks following
+// the copy of the ledger, built from the last config of the copy, or nil if
+// the copy is empty.
+func (s *Standby) lastVerifier(channelID string, ledger blockledger.Reader) (cluster.BlockVerifier, error) {
+	height := ledger.Height()
+	if height == 0 {
+		return nil, nil
+	}
+	lastBlock, err := ledger.RetrieveBlockByNumber(height - 1)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed retrieving block %d", height-1)
+	}
+	configIndex, err := protoutil.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed retrieving the index of the last config block of block %d", height-1)
+	}
+	configBlock, err := ledger.RetrieveBlockByNumber(configIndex)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed retrieving block %d", configIndex)
+	}
+	return s.verifierFromBlock(channelID, configBlock)
+}
+
+func (s *Standby) verifierFromBlock(channelID string, configBlock *cb.Block) (cluster.BlockVerifier, error) {
+	config, err := cluster.ConfigFromBlock(configBlock)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed extracting the config of block %d", configBlock.Header.Number)
+	}
+	verifier, err := s.verifiers.VerifierFromConfig(config, channelID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed creating the block verifier of channel %s", channelID)
+	}
+	return verifier, nil
+}
+
+func (s *Standby) openStream(channelID string) (blockledger.ReadWriter, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.promoted {
+		return nil, errors.New("the standby orderer has been promoted")
+	}
+	if s.streaming[channelID] {
+		return nil, errors.Errorf("channel %s is already being mirrored", channelID)
+	}
+	ledger, err := s.ledgers.GetOrCreate(channelID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed opening the ledger of channel %s", channelID)
+	}
+	s.streaming[channelID] = true
+	return ledger, nil
+}
+
+func (s *Standby) closeStream(channelID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.streaming, channelID)
+}
+
+// append appends a block after checking that it extends the hash chain of
+// the ledger and that its signatures satisfy the BlockValidation policy of the
+// channel, or that it is the genesis block of the channel. It returns the
+// verifier of the signatures of the next block.
+func (s *Standby) append(channelID string, ledger blockledger.ReadWriter, verifier cluster.BlockVerifier, blockBytes []byte) (cluster.BlockVerifier, error) {
+	block := &cb.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling block")
+	}
+	if block.Header == nil || block.Data == nil {
+		return nil, errors.New("block is missing its header or data")
+	}
+
+	height := ledger.Height()
+	if block.Header.Number != height {
+		return nil, errors.Errorf("expected block %d but got block %d", height, block.Header.Number)
+	}
+	if !bytes.Equal(block.Header.DataHash, protoutil.BlockDataHash(block.Data)) {
+		return nil, errors.Errorf("data hash of block %d does not match its data", block.Header.Number)
+	}
+	if height == 0 {
+		if err := s.checkGenesisBlock(channelID, block); err != nil {
+			return nil, err
+		}
+	} else {
+		previous, err := ledger.RetrieveBlockByNumber(height - 1)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed retrieving block %d", height-1)
+		}
+		if !bytes.Equal(block.Header.PreviousHash, protoutil.BlockHeaderHash(previous.Header)) {
+			return nil, errors.Errorf("previous hash of block %d does not match the hash of block %d", block.Header.Number, height-1)
+		}
+		if err := cluster.VerifyBlocks([]*cb.Block{block}, verifier); err != nil {
+			return nil, errors.WithMessagef(err, "failed verifying the signatures of block %d", block.Header.Number)
+		}
+	}
+	if protoutil.IsConfigBlock(block) {
+		var err error
+		if verifier, err = s.verifierFromBlock(channelID, block); err != nil {
+			return nil, err
+		}
+	}
+
+	// Promotion waits for the blocks being appended
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.promoted {
+		return nil, errors.New("the standby orderer has been promoted")
+	}
+	if err := ledger.Append(block); err != nil {
+		return nil, errors.WithMessagef(err, "failed appending block %d", block.Header.Number)
+	}
+	s.metrics.BlocksReceived.With("channel", channelID).Add(1)
+	return verifier, nil
+}
+
+// checkGenesisBlock checks that the block is the genesis block of the channel
+// held by the standby orderer. The data hash of the block must have been
+// checked already.
+func (s *Standby) checkGenesisBlock(channelID string, block *cb.Block) error {
+	path := filepath.Join(s.genesisBlocks, channelID+".block")
+	genesisBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed reading the genesis block of channel %s", channelID)
+	}
+	genesis, err := protoutil.UnmarshalBlock(genesisBytes)
+	if err != nil {
+		return errors.WithMessagef(err, "failed unmarshaling the genesis block of channel %s from %s", channelID, path)
+	}
+	if genesis.Header == nil || !bytes.Equal(protoutil.BlockHeaderHash(block.Header), protoutil.BlockHeaderHash(genesis.Header)) {
+		return errors.Errorf("block 0 is not the genesis block of channel %s in %s", channelID, path)
+	}
+	if !protoutil.IsConfigBlock(block) {
+		return errors.Errorf("the genesis block of channel %s in %s is not a config block", channelID, path)
+	}
+	return nil
+}
+
+// Status returns the status of the standby orderer.
+func (s *Standby) Status() (*StandbyStatus, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.status()
+}
+
+func (s *Standby) status() (*StandbyStatus, error) {
+	status := &StandbyStatus{
+		Promoted: s.promoted,
+		Location: s.location,
+		Channels: []ChannelStatus{},
+	}
+	channelIDs := s.ledgers.ChannelIDs()
+	sort.Strings(channelIDs)
+	for _, channelID := range channelIDs {
+		ledger, err := s.ledgers.GetOrCreate(channelID)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed opening the ledger of channel %s", channelID)
+		}
+		status.Channels = append(status.Channels, ChannelStatus{Channel: channelID, Height: ledger.Height()})
+	}
+	return status, nil
+}
+
+// Promote stops accepting blocks from the primary orderers and marks the
+// copies of the ledgers as promoted, so that they are not modified by the
+// standby orderer again, even after a restart.  It returns the final status
+// of the copies.
+func (s *Standby) Promote() (*StandbyStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.promoted {
+		if err := ioutil.WriteFile(filepath.Join(s.location, promotedFile), nil, 0644); err != nil {
+			return nil, errors.Wrap(err, "failed marking the standby orderer as promoted")
+		}
+		s.promoted = true
+		logger.Infof("Promoted the standby orderer, the mirrored ledgers in %s are no longer updated", s.location)
+	}
+	return s.status()
+}
+
+// Close releases the ledgers.
+func (s *Standby) Close() {
+	s.ledgers.Close()
+}
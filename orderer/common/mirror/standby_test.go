@@ -0,0 +1,249 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirror
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStream struct {
+	requests  []*Request
+	responses []*Response
+}
+
+func (s *fakeStream) Send(resp *Response) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func (s *fakeStream) Recv() (*Request, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func marshaledBlocks(t *testing.T, count int) [][]byte {
+	ledgers, cleanup := newLedgers(t)
+	defer cleanup()
+	ledger, err := ledgers.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	appendBlocks(t, ledger, count)
+
+	var blocks [][]byte
+	for i := 0; i < count; i++ {
+		block := blockledger.GetBlock(ledger, uint64(i))
+		blockBytes, err := proto.Marshal(block)
+		require.NoError(t, err)
+		blocks = append(blocks, blockBytes)
+	}
+	return blocks
+}
+
+// signedBy signs a block on behalf of the creator
+func signedBy(block *cb.Block, creator string) *cb.Block {
+	block = proto.Clone(block).(*cb.Block)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Signatures: []*cb.MetadataSignature{{
+			SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte(creator)}),
+			Signature:       []byte("signature"),
+		}},
+	})
+	return block
+}
+
+// nextBlock creates the block following the previous one, whose metadata
+// points to the last config block
+func nextBlock(previous *cb.Block, lastConfig uint64, env *cb.Envelope) *cb.Block {
+	block := protoutil.NewBlock(previous.Header.Number+1, protoutil.BlockHeaderHash(previous.Header))
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(env)}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value: protoutil.MarshalOrPanic(&cb.LastConfig{Index: lastConfig}),
+	})
+	return block
+}
+
+func TestStandbyReplicate(t *testing.T) {
+	blocks := marshaledBlocks(t, 3)
+	forgedBlock := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(blocks[2], forgedBlock))
+	forged, err := proto.Marshal(signedBy(forgedBlock, "forger"))
+	require.NoError(t, err)
+	otherGenesis, err := proto.Marshal(genesisBlock("otherchannel"))
+	require.NoError(t, err)
+	tamperedBlock := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(blocks[2], tamperedBlock))
+	tamperedBlock.Data.Data = [][]byte{[]byte("tampered")}
+	tampered, err := proto.Marshal(tamperedBlock)
+	require.NoError(t, err)
+	rechainedBlock := &cb.Block{}
+	require.NoError(t, proto.Unmarshal(blocks[2], rechainedBlock))
+	rechainedBlock.Header.PreviousHash = []byte("wrong")
+	rechained, err := proto.Marshal(rechainedBlock)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		requests    []*Request
+		expectedErr string
+		heights     []uint64
+	}{
+		{
+			name:     "in order",
+			requests: []*Request{{Channel: "mychannel"}, {Block: blocks[0]}, {Block: blocks[1]}, {Block: blocks[2]}},
+			heights:  []uint64{0, 1, 2, 3},
+		},
+		{
+			name:        "missing channel",
+			requests:    []*Request{{Block: blocks[0]}},
+			expectedErr: "the first request must name the channel",
+		},
+		{
+			name:        "gap",
+			requests:    []*Request{{Channel: "mychannel"}, {Block: blocks[1]}},
+			expectedErr: "expected block 0 but got block 1",
+			heights:     []uint64{0},
+		},
+		{
+			name:        "tampered data",
+			requests:    []*Request{{Channel: "mychannel"}, {Block: blocks[0]}, {Block: blocks[1]}, {Block: tampered}},
+			expectedErr: "data hash of block 2 does not match its data",
+			heights:     []uint64{0, 1, 2},
+		},
+		{
+			name:        "broken hash chain",
+			requests:    []*Request{{Channel: "mychannel"}, {Block: blocks[0]}, {Block: blocks[1]}, {Block: rechained}},
+			expectedErr: "previous hash of block 2 does not match the hash of block 1",
+			heights:     []uint64{0, 1, 2},
+		},
+		{
+			name:        "forged signature",
+			requests:    []*Request{{Channel: "mychannel"}, {Block: blocks[0]}, {Block: blocks[1]}, {Block: forged}},
+			expectedErr: "failed verifying the signatures of block 2: signature set did not satisfy policy",
+			heights:     []uint64{0, 1, 2},
+		},
+		{
+			name:        "not the genesis block",
+			requests:    []*Request{{Channel: "mychannel"}, {Block: otherGenesis}},
+			expectedErr: "block 0 is not the genesis block of channel mychannel",
+			heights:     []uint64{0},
+		},
+		{
+			name:        "unknown genesis block",
+			requests:    []*Request{{Channel: "otherchannel"}, {Block: otherGenesis}},
+			expectedErr: "failed reading the genesis block of channel otherchannel",
+			heights:     []uint64{0},
+		},
+		{
+			name:        "garbage",
+			requests:    []*Request{{Channel: "mychannel"}, {Block: []byte("garbage")}},
+			expectedErr: "failed unmarshaling block",
+			heights:     []uint64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standby, cleanup := newStandby(t)
+			defer cleanup()
+
+			stream := &fakeStream{requests: tt.requests}
+			err := standby.Replicate(stream)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			}
+			var heights []uint64
+			for _, resp := range stream.responses {
+				heights = append(heights, resp.Height)
+			}
+			assert.Equal(t, tt.heights, heights)
+		})
+	}
+}
+
+func TestStandbyVerifiers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mirror-standby")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	genesisDir, removeGenesis := genesisBlocks(t)
+	defer removeGenesis()
+
+	genesis := genesisBlock("mychannel")
+	block1 := nextBlock(genesis, 0, dataEnvelope("mychannel"))
+	block2 := nextBlock(block1, 2, configEnvelope("mychannel", 1))
+	block3 := nextBlock(block2, 2, dataEnvelope("mychannel"))
+	block4 := nextBlock(block3, 2, dataEnvelope("mychannel"))
+	var blocks [][]byte
+	for _, block := range []*cb.Block{genesis, block1, block2, block3, signedBy(block4, "forger")} {
+		blockBytes, err := proto.Marshal(block)
+		require.NoError(t, err)
+		blocks = append(blocks, blockBytes)
+	}
+
+	verifiers := &verifierFactory{}
+	standby, err := NewStandby(dir, genesisDir, verifiers, NewMetrics(&disabled.Provider{}))
+	require.NoError(t, err)
+	defer standby.Close()
+
+	// The verifier is replaced by the one of each config block
+	err = standby.Replicate(&fakeStream{requests: []*Request{{Channel: "mychannel"}, {Block: blocks[0]}, {Block: blocks[1]}, {Block: blocks[2]}}})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0, 1}, verifiers.sequences)
+
+	// A new stream resumes with the verifier of the last config block of the copy
+	err = standby.Replicate(&fakeStream{requests: []*Request{{Channel: "mychannel"}, {Block: blocks[3]}, {Block: blocks[4]}}})
+	assert.EqualError(t, err, "failed verifying the signatures of block 4: signature set did not satisfy policy")
+	assert.Equal(t, []uint64{0, 1, 1}, verifiers.sequences)
+	assert.Equal(t, uint64(4), standbyHeight(t, standby, "mychannel")())
+}
+
+func TestStandbyPromote(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mirror-standby")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	genesisDir, removeGenesis := genesisBlocks(t)
+	defer removeGenesis()
+	blocks := marshaledBlocks(t, 2)
+
+	standby, err := NewStandby(dir, genesisDir, &verifierFactory{}, NewMetrics(&disabled.Provider{}))
+	require.NoError(t, err)
+	err = standby.Replicate(&fakeStream{requests: []*Request{{Channel: "mychannel"}, {Block: blocks[0]}}})
+	require.NoError(t, err)
+
+	status, err := standby.Promote()
+	require.NoError(t, err)
+	assert.Equal(t, &StandbyStatus{Promoted: true, Location: dir, Channels: []ChannelStatus{{Channel: "mychannel", Height: 1}}}, status)
+	err = standby.Replicate(&fakeStream{requests: []*Request{{Channel: "mychannel"}, {Block: blocks[1]}}})
+	assert.EqualError(t, err, "the standby orderer has been promoted")
+	standby.Close()
+
+	standby, err = NewStandby(dir, genesisDir, &verifierFactory{}, NewMetrics(&disabled.Provider{}))
+	require.NoError(t, err)
+	defer standby.Close()
+	status, err = standby.Status()
+	require.NoError(t, err)
+	assert.True(t, status.Promoted)
+	err = standby.Replicate(&fakeStream{requests: []*Request{{Channel: "mychannel"}, {Block: blocks[1]}}})
+	assert.EqualError(t, err, "the standby orderer has been promoted")
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/channelconfig"
+)
+
+// channelTemplateConsortiumModPolicy is the mod_policy of the consortium value in the template configuration of a
+// channel created from a channel template.  A request referencing a channel template leaves the application group,
+// which the template configuration already holds, untouched, and sets the consortium value instead, so that the
+// request is authorized against the channel creation policy of the consortium.
+const channelTemplateConsortiumModPolicy = "/" + channelconfig.ChannelGroupKey + "/" + channelconfig.ApplicationGroupKey + "/" + channelconfig.ChannelCreationPolicyKey
+
+// channelTemplateGroup returns the application group of a channel created from the given channel template of a
+// consortium, after checking that the channel creation config update leaves the application group to the template.
+// The organizations referenced by the template are copied from the consortium group, and the channel creation policy
+// of the consortium is added to the policies of the template.
+func channelTemplateGroup(configUpdate *cb.ConfigUpdate, consortiumName string, consortiumConf channelconfig.Consortium, consortiumGroup *cb.ConfigGroup, templateName string) (*cb.ConfigGroup, error) {
+	template, ok := consortiumConf.ChannelTemplates()[templateName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown channel template %s for consortium %s", templateName, consortiumName)
+	}
+
+	if _, ok := configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey]; ok {
+		return nil, fmt.Errorf("Config update referencing channel template %s must not define the application group", templateName)
+	}
+
+	if cv := configUpdate.WriteSet.Values[channelconfig.ConsortiumKey].Version; cv != 1 {
+		return nil, fmt.Errorf("Config update referencing channel template %s does not set consortium value version to 1, was %d", templateName, cv)
+	}
+
+	// The same rule as for the requests listing the members applies to the template
+	if len(consortiumConf.Organizations()) > 0 && len(template.Groups) == 0 {
+		return nil, fmt.Errorf("Channel template %s has no application group members, but consortium contains members", templateName)
+	}
+
+	applicationGroup := proto.Clone(template).(*cb.ConfigGroup)
+	for orgName := range applicationGroup.Groups {
+		applicationGroup.Groups[orgName] = proto.Clone(consortiumGroup.Groups[orgName]).(*cb.ConfigGroup)
+	}
+	if applicationGroup.ModPolicy == "" {
+		applicationGroup.ModPolicy = channelconfig.AdminsPolicyKey
+	}
+	if applicationGroup.Policies == nil {
+		applicationGroup.Policies = map[string]*cb.ConfigPolicy{}
+	}
+	applicationGroup.Policies[channelconfig.ChannelCreationPolicyKey] = &cb.ConfigPolicy{
+		Policy:    consortiumConf.ChannelCreationPolicy(),
+		ModPolicy: channelconfig.AdminsPolicyKey,
+	}
+	zeroVersions(applicationGroup)
+
+	return applicationGroup, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/capabilities"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
+	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func standardPolicies() map[string]*genesisconfig.Policy {
+	return map[string]*genesisconfig.Policy{
+		"Admins":  {Type: "ImplicitMeta", Rule: "ANY Admins"},
+		"Readers": {Type: "ImplicitMeta", Rule: "ANY Readers"},
+		"Writers": {Type: "ImplicitMeta", Rule: "ANY Writers"},
+	}
+}
+
+func TestNewChannelConfigFromTemplate(t *testing.T) {
+	ordererPolicies := standardPolicies()
+	ordererPolicies["BlockValidation"] = &genesisconfig.Policy{Type: "ImplicitMeta", Rule: "ANY Writers"}
+	gConf := &genesisconfig.Profile{
+		Policies:     standardPolicies(),
+		Capabilities: map[string]bool{capabilities.ChannelV2_0: true, capabilities.ChannelTemplates: true},
+		Orderer: &genesisconfig.Orderer{
+			OrdererType:  "solo",
+			Addresses:    []string{"127.0.0.1:7050"},
+			BatchTimeout: 2 * time.Second,
+			BatchSize: genesisconfig.BatchSize{
+				MaxMessageCount:   10,
+				AbsoluteMaxBytes:  10 * 1024 * 1024,
+				PreferredMaxBytes: 512 * 1024,
+			},
+			Policies:     ordererPolicies,
+			Capabilities: map[string]bool{capabilities.OrdererV2_0: true},
+		},
+		Consortiums: map[string]*genesisconfig.Consortium{
+			genesisconfig.SampleConsortiumName: {
+				ChannelTemplates: map[string]*genesisconfig.ChannelTemplate{
+					"gold": {
+						Policies:     standardPolicies(),
+						Capabilities: map[string]bool{capabilities.ApplicationV2_0: true},
+						ACLs:         map[string]string{"peer/Propose": "/Channel/Application/Writers"},
+					},
+				},
+			},
+		},
+	}
+	channelGroup, err := encoder.NewChannelGroup(gConf)
+	require.NoError(t, err)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	ctxm, err := channelconfig.NewBundle("system-channel", &cb.Config{ChannelGroup: channelGroup}, cryptoProvider)
+	require.NoError(t, err)
+	templator := NewDefaultTemplator(&mockDefaultTemplatorSupport{
+		Resources: ctxm,
+	}, cryptoProvider)
+
+	t.Run("Success", func(t *testing.T) {
+		createTx, err := encoder.MakeTemplatedChannelCreationTransaction("foo", nil, genesisconfig.SampleConsortiumName, "gold")
+		require.NoError(t, err)
+		res, err := templator.NewChannelConfig(createTx)
+		require.NoError(t, err)
+
+		configEnv, err := res.ConfigtxValidator().ProposeConfigUpdate(createTx)
+		require.NoError(t, err)
+		applicationGroup := configEnv.Config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+		require.Equal(t, uint64(0), applicationGroup.Version)
+		require.Equal(t, channelconfig.AdminsPolicyKey, applicationGroup.ModPolicy)
+		require.Len(t, applicationGroup.Policies, 4)
+		require.Contains(t, applicationGroup.Policies, channelconfig.ChannelCreationPolicyKey)
+		require.Contains(t, applicationGroup.Values, channelconfig.CapabilitiesKey)
+		require.Contains(t, applicationGroup.Values, channelconfig.ACLsKey)
+		require.True(t, proto.Equal(createTx, configEnv.LastUpdate))
+		consortiumValue := configEnv.Config.ChannelGroup.Values[channelconfig.ConsortiumKey]
+		require.Equal(t, uint64(1), consortiumValue.Version)
+		require.Equal(t, channelconfig.AdminsPolicyKey, consortiumValue.ModPolicy)
+
+		bundle, err := channelconfig.NewBundle("foo", configEnv.Config, cryptoProvider)
+		require.NoError(t, err)
+		ac, ok := bundle.ApplicationConfig()
+		require.True(t, ok)
+		require.True(t, ac.Capabilities().V2_0Validation())
+		require.NoError(t, res.ValidateNew(bundle))
+	})
+
+	t.Run("UnknownTemplate", func(t *testing.T) {
+		createTx, err := encoder.MakeTemplatedChannelCreationTransaction("foo", nil, genesisconfig.SampleConsortiumName, "silver")
+		require.NoError(t, err)
+		_, err = templator.NewChannelConfig(createTx)
+		require.EqualError(t, err, "Unknown channel template silver for consortium SampleConsortium")
+	})
+
+	t.Run("ApplicationGroup", func(t *testing.T) {
+		configUpdate := encoder.NewTemplatedChannelCreateConfigUpdate("foo", genesisconfig.SampleConsortiumName, "gold")
+		configUpdate.WriteSet.Groups = map[string]*cb.ConfigGroup{channelconfig.ApplicationGroupKey: {Version: 1}}
+		createTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, "foo", nil, &cb.ConfigUpdateEnvelope{
+			ConfigUpdate: protoutil.MarshalOrPanic(configUpdate),
+		}, 0, 0)
+		require.NoError(t, err)
+		_, err = templator.NewChannelConfig(createTx)
+		require.EqualError(t, err, "Config update referencing channel template gold must not define the application group")
+	})
+
+	t.Run("ConsortiumVersion", func(t *testing.T) {
+		configUpdate := encoder.NewTemplatedChannelCreateConfigUpdate("foo", genesisconfig.SampleConsortiumName, "gold")
+		configUpdate.WriteSet.Values[channelconfig.ConsortiumKey].Version = 0
+		createTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, "foo", nil, &cb.ConfigUpdateEnvelope{
+			ConfigUpdate: protoutil.MarshalOrPanic(configUpdate),
+		}, 0, 0)
+		require.NoError(t, err)
+		_, err = templator.NewChannelConfig(createTx)
+		require.EqualError(t, err, "Config update referencing channel template gold does not set consortium value version to 1, was 0")
+	})
+
+	t.Run("MissingCapability", func(t *testing.T) {
+		delete(gConf.Capabilities, capabilities.ChannelTemplates)
+		channelGroup, err := encoder.NewChannelGroup(gConf)
+		require.NoError(t, err)
+		_, err = channelconfig.NewBundle("system-channel", &cb.Config{ChannelGroup: channelGroup}, cryptoProvider)
+		require.EqualError(t, err, "initializing channelconfig failed: could not create channel Consortiums sub-group config: Consortium SampleConsortium cannot contain channel templates until the V2_2_CHANNEL_TEMPLATES capability has been enabled")
+	})
+}
@@ -8,6 +8,36 @@ import (
 )
 
 type ChannelCapabilities struct {
+	ChaincodeMetadataStub        func() bool
+	chaincodeMetadataMutex       sync.RWMutex
+	chaincodeMetadataArgsForCall []struct {
+	}
+	chaincodeMetadataReturns struct {
+		result1 bool
+	}
+	chaincodeMetadataReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	ChannelTemplatesStub        func() bool
+	channelTemplatesMutex       sync.RWMutex
+	channelTemplatesArgsForCall []struct {
+	}
+	channelTemplatesReturns struct {
+		result1 bool
+	}
+	channelTemplatesReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	CommutativeCountersStub        func() bool
+	commutativeCountersMutex       sync.RWMutex
+	commutativeCountersArgsForCall []struct {
+	}
+	commutativeCountersReturns struct {
+		result1 bool
+	}
+	commutativeCountersReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ConsensusTypeMigrationStub        func() bool
 	consensusTypeMigrationMutex       sync.RWMutex
 	consensusTypeMigrationArgsForCall []struct {
@@ -18,6 +48,26 @@ type ChannelCapabilities struct {
 	consensusTypeMigrationReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	CustomTransactionsStub        func() bool
+	customTransactionsMutex       sync.RWMutex
+	customTransactionsArgsForCall []struct {
+	}
+	customTransactionsReturns struct {
+		result1 bool
+	}
+	customTransactionsReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	IdemixAuditEscrowStub        func() bool
+	idemixAuditEscrowMutex       sync.RWMutex
+	idemixAuditEscrowArgsForCall []struct {
+	}
+	idemixAuditEscrowReturns struct {
+		result1 bool
+	}
+	idemixAuditEscrowReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	MSPVersionStub        func() msp.MSPVersion
 	mSPVersionMutex       sync.RWMutex
 	mSPVersionArgsForCall []struct {
@@ -38,6 +88,16 @@ type ChannelCapabilities struct {
 	orgSpecificOrdererEndpointsReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	SM3HashingStub        func() bool
+	sM3HashingMutex       sync.RWMutex
+	sM3HashingArgsForCall []struct {
+	}
+	sM3HashingReturns struct {
+		result1 bool
+	}
+	sM3HashingReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	SupportedStub        func() error
 	supportedMutex       sync.RWMutex
 	supportedArgsForCall []struct {
@@ -48,10 +108,176 @@ type ChannelCapabilities struct {
 	supportedReturnsOnCall map[int]struct {
 		result1 error
 	}
+	WeightedSignaturePoliciesStub        func() bool
+	weightedSignaturePoliciesMutex       sync.RWMutex
+	weightedSignaturePoliciesArgsForCall []struct {
+	}
+	weightedSignaturePoliciesReturns struct {
+		result1 bool
+	}
+	weightedSignaturePoliciesReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ChannelCapabilities) ChaincodeMetadata() bool {
+	fake.chaincodeMetadataMutex.Lock()
+	ret, specificReturn := fake.chaincodeMetadataReturnsOnCall[len(fake.chaincodeMetadataArgsForCall)]
+	fake.chaincodeMetadataArgsForCall = append(fake.chaincodeMetadataArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ChaincodeMetadata", []interface{}{})
+	fake.chaincodeMetadataMutex.Unlock()
+	if fake.ChaincodeMetadataStub != nil {
+		return fake.ChaincodeMetadataStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.chaincodeMetadataReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) ChaincodeMetadataCallCount() int {
+	fake.chaincodeMetadataMutex.RLock()
+	defer fake.chaincodeMetadataMutex.RUnlock()
+	return len(fake.chaincodeMetadataArgsForCall)
+}
+
+func (fake *ChannelCapabilities) ChaincodeMetadataCalls(stub func() bool) {
+	fake.chaincodeMetadataMutex.Lock()
+	defer fake.chaincodeMetadataMutex.Unlock()
+	fake.ChaincodeMetadataStub = stub
+}
+
+func (fake *ChannelCapabilities) ChaincodeMetadataReturns(result1 bool) {
+	fake.chaincodeMetadataMutex.Lock()
+	defer fake.chaincodeMetadataMutex.Unlock()
+	fake.ChaincodeMetadataStub = nil
+	fake.chaincodeMetadataReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) ChaincodeMetadataReturnsOnCall(i int, result1 bool) {
+	fake.chaincodeMetadataMutex.Lock()
+	defer fake.chaincodeMetadataMutex.Unlock()
+	fake.ChaincodeMetadataStub = nil
+	if fake.chaincodeMetadataReturnsOnCall == nil {
+		fake.chaincodeMetadataReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.chaincodeMetadataReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) ChannelTemplates() bool {
+	fake.channelTemplatesMutex.Lock()
+	ret, specificReturn := fake.channelTemplatesReturnsOnCall[len(fake.channelTemplatesArgsForCall)]
+	fake.channelTemplatesArgsForCall = append(fake.channelTemplatesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ChannelTemplates", []interface{}{})
+	fake.channelTemplatesMutex.Unlock()
+	if fake.ChannelTemplatesStub != nil {
+		return fake.ChannelTemplatesStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.channelTemplatesReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) ChannelTemplatesCallCount() int {
+	fake.channelTemplatesMutex.RLock()
+	defer fake.channelTemplatesMutex.RUnlock()
+	return len(fake.channelTemplatesArgsForCall)
+}
+
+func (fake *ChannelCapabilities) ChannelTemplatesCalls(stub func() bool) {
+	fake.channelTemplatesMutex.Lock()
+	defer fake.channelTemplatesMutex.Unlock()
+	fake.ChannelTemplatesStub = stub
+}
+
+func (fake *ChannelCapabilities) ChannelTemplatesReturns(result1 bool) {
+	fake.channelTemplatesMutex.Lock()
+	defer fake.channelTemplatesMutex.Unlock()
+	fake.ChannelTemplatesStub = nil
+	fake.channelTemplatesReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) ChannelTemplatesReturnsOnCall(i int, result1 bool) {
+	fake.channelTemplatesMutex.Lock()
+	defer fake.channelTemplatesMutex.Unlock()
+	fake.ChannelTemplatesStub = nil
+	if fake.channelTemplatesReturnsOnCall == nil {
+		fake.channelTemplatesReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.channelTemplatesReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) CommutativeCounters() bool {
+	fake.commutativeCountersMutex.Lock()
+	ret, specificReturn := fake.commutativeCountersReturnsOnCall[len(fake.commutativeCountersArgsForCall)]
+	fake.commutativeCountersArgsForCall = append(fake.commutativeCountersArgsForCall, struct {
+	}{})
+	fake.recordInvocation("CommutativeCounters", []interface{}{})
+	fake.commutativeCountersMutex.Unlock()
+	if fake.CommutativeCountersStub != nil {
+		return fake.CommutativeCountersStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.commutativeCountersReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) CommutativeCountersCallCount() int {
+	fake.commutativeCountersMutex.RLock()
+	defer fake.commutativeCountersMutex.RUnlock()
+	return len(fake.commutativeCountersArgsForCall)
+}
+
+func (fake *ChannelCapabilities) CommutativeCountersCalls(stub func() bool) {
+	fake.commutativeCountersMutex.Lock()
+	defer fake.commutativeCountersMutex.Unlock()
+	fake.CommutativeCountersStub = stub
+}
+
+func (fake *ChannelCapabilities) CommutativeCountersReturns(result1 bool) {
+	fake.commutativeCountersMutex.Lock()
+	defer fake.commutativeCountersMutex.Unlock()
+	fake.CommutativeCountersStub = nil
+	fake.commutativeCountersReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) CommutativeCountersReturnsOnCall(i int, result1 bool) {
+	fake.commutativeCountersMutex.Lock()
+	defer fake.commutativeCountersMutex.Unlock()
+	fake.CommutativeCountersStub = nil
+	if fake.commutativeCountersReturnsOnCall == nil {
+		fake.commutativeCountersReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.commutativeCountersReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ChannelCapabilities) ConsensusTypeMigration() bool {
 	fake.consensusTypeMigrationMutex.Lock()
 	ret, specificReturn := fake.consensusTypeMigrationReturnsOnCall[len(fake.consensusTypeMigrationArgsForCall)]
@@ -104,6 +330,110 @@ func (fake *ChannelCapabilities) ConsensusTypeMigrationReturnsOnCall(i int, resu
 	}{result1}
 }
 
+func (fake *ChannelCapabilities) CustomTransactions() bool {
+	fake.customTransactionsMutex.Lock()
+	ret, specificReturn := fake.customTransactionsReturnsOnCall[len(fake.customTransactionsArgsForCall)]
+	fake.customTransactionsArgsForCall = append(fake.customTransactionsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("CustomTransactions", []interface{}{})
+	fake.customTransactionsMutex.Unlock()
+	if fake.CustomTransactionsStub != nil {
+		return fake.CustomTransactionsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.customTransactionsReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) CustomTransactionsCallCount() int {
+	fake.customTransactionsMutex.RLock()
+	defer fake.customTransactionsMutex.RUnlock()
+	return len(fake.customTransactionsArgsForCall)
+}
+
+func (fake *ChannelCapabilities) CustomTransactionsCalls(stub func() bool) {
+	fake.customTransactionsMutex.Lock()
+	defer fake.customTransactionsMutex.Unlock()
+	fake.CustomTransactionsStub = stub
+}
+
+func (fake *ChannelCapabilities) CustomTransactionsReturns(result1 bool) {
+	fake.customTransactionsMutex.Lock()
+	defer fake.customTransactionsMutex.Unlock()
+	fake.CustomTransactionsStub = nil
+	fake.customTransactionsReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) CustomTransactionsReturnsOnCall(i int, result1 bool) {
+	fake.customTransactionsMutex.Lock()
+	defer fake.customTransactionsMutex.Unlock()
+	fake.CustomTransactionsStub = nil
+	if fake.customTransactionsReturnsOnCall == nil {
+		fake.customTransactionsReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.customTransactionsReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) IdemixAuditEscrow() bool {
+	fake.idemixAuditEscrowMutex.Lock()
+	ret, specificReturn := fake.idemixAuditEscrowReturnsOnCall[len(fake.idemixAuditEscrowArgsForCall)]
+	fake.idemixAuditEscrowArgsForCall = append(fake.idemixAuditEscrowArgsForCall, struct {
+	}{})
+	fake.recordInvocation("IdemixAuditEscrow", []interface{}{})
+	fake.idemixAuditEscrowMutex.Unlock()
+	if fake.IdemixAuditEscrowStub != nil {
+		return fake.IdemixAuditEscrowStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.idemixAuditEscrowReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) IdemixAuditEscrowCallCount() int {
+	fake.idemixAuditEscrowMutex.RLock()
+	defer fake.idemixAuditEscrowMutex.RUnlock()
+	return len(fake.idemixAuditEscrowArgsForCall)
+}
+
+func (fake *ChannelCapabilities) IdemixAuditEscrowCalls(stub func() bool) {
+	fake.idemixAuditEscrowMutex.Lock()
+	defer fake.idemixAuditEscrowMutex.Unlock()
+	fake.IdemixAuditEscrowStub = stub
+}
+
+func (fake *ChannelCapabilities) IdemixAuditEscrowReturns(result1 bool) {
+	fake.idemixAuditEscrowMutex.Lock()
+	defer fake.idemixAuditEscrowMutex.Unlock()
+	fake.IdemixAuditEscrowStub = nil
+	fake.idemixAuditEscrowReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) IdemixAuditEscrowReturnsOnCall(i int, result1 bool) {
+	fake.idemixAuditEscrowMutex.Lock()
+	defer fake.idemixAuditEscrowMutex.Unlock()
+	fake.IdemixAuditEscrowStub = nil
+	if fake.idemixAuditEscrowReturnsOnCall == nil {
+		fake.idemixAuditEscrowReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.idemixAuditEscrowReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ChannelCapabilities) MSPVersion() msp.MSPVersion {
 	fake.mSPVersionMutex.Lock()
 	ret, specificReturn := fake.mSPVersionReturnsOnCall[len(fake.mSPVersionArgsForCall)]
@@ -208,6 +538,58 @@ func (fake *ChannelCapabilities) OrgSpecificOrdererEndpointsReturnsOnCall(i int,
 	}{result1}
 }
 
+func (fake *ChannelCapabilities) SM3Hashing() bool {
+	fake.sM3HashingMutex.Lock()
+	ret, specificReturn := fake.sM3HashingReturnsOnCall[len(fake.sM3HashingArgsForCall)]
+	fake.sM3HashingArgsForCall = append(fake.sM3HashingArgsForCall, struct {
+	}{})
+	fake.recordInvocation("SM3Hashing", []interface{}{})
+	fake.sM3HashingMutex.Unlock()
+	if fake.SM3HashingStub != nil {
+		return fake.SM3HashingStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.sM3HashingReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) SM3HashingCallCount() int {
+	fake.sM3HashingMutex.RLock()
+	defer fake.sM3HashingMutex.RUnlock()
+	return len(fake.sM3HashingArgsForCall)
+}
+
+func (fake *ChannelCapabilities) SM3HashingCalls(stub func() bool) {
+	fake.sM3HashingMutex.Lock()
+	defer fake.sM3HashingMutex.Unlock()
+	fake.SM3HashingStub = stub
+}
+
+func (fake *ChannelCapabilities) SM3HashingReturns(result1 bool) {
+	fake.sM3HashingMutex.Lock()
+	defer fake.sM3HashingMutex.Unlock()
+	fake.SM3HashingStub = nil
+	fake.sM3HashingReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) SM3HashingReturnsOnCall(i int, result1 bool) {
+	fake.sM3HashingMutex.Lock()
+	defer fake.sM3HashingMutex.Unlock()
+	fake.SM3HashingStub = nil
+	if fake.sM3HashingReturnsOnCall == nil {
+		fake.sM3HashingReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.sM3HashingReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ChannelCapabilities) Supported() error {
 	fake.supportedMutex.Lock()
 	ret, specificReturn := fake.supportedReturnsOnCall[len(fake.supportedArgsForCall)]
@@ -260,17 +642,83 @@ func (fake *ChannelCapabilities) SupportedReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ChannelCapabilities) WeightedSignaturePolicies() bool {
+	fake.weightedSignaturePoliciesMutex.Lock()
+	ret, specificReturn := fake.weightedSignaturePoliciesReturnsOnCall[len(fake.weightedSignaturePoliciesArgsForCall)]
+	fake.weightedSignaturePoliciesArgsForCall = append(fake.weightedSignaturePoliciesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("WeightedSignaturePolicies", []interface{}{})
+	fake.weightedSignaturePoliciesMutex.Unlock()
+	if fake.WeightedSignaturePoliciesStub != nil {
+		return fake.WeightedSignaturePoliciesStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.weightedSignaturePoliciesReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelCapabilities) WeightedSignaturePoliciesCallCount() int {
+	fake.weightedSignaturePoliciesMutex.RLock()
+	defer fake.weightedSignaturePoliciesMutex.RUnlock()
+	return len(fake.weightedSignaturePoliciesArgsForCall)
+}
+
+func (fake *ChannelCapabilities) WeightedSignaturePoliciesCalls(stub func() bool) {
+	fake.weightedSignaturePoliciesMutex.Lock()
+	defer fake.weightedSignaturePoliciesMutex.Unlock()
+	fake.WeightedSignaturePoliciesStub = stub
+}
+
+func (fake *ChannelCapabilities) WeightedSignaturePoliciesReturns(result1 bool) {
+	fake.weightedSignaturePoliciesMutex.Lock()
+	defer fake.weightedSignaturePoliciesMutex.Unlock()
+	fake.WeightedSignaturePoliciesStub = nil
+	fake.weightedSignaturePoliciesReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelCapabilities) WeightedSignaturePoliciesReturnsOnCall(i int, result1 bool) {
+	fake.weightedSignaturePoliciesMutex.Lock()
+	defer fake.weightedSignaturePoliciesMutex.Unlock()
+	fake.WeightedSignaturePoliciesStub = nil
+	if fake.weightedSignaturePoliciesReturnsOnCall == nil {
+		fake.weightedSignaturePoliciesReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.weightedSignaturePoliciesReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ChannelCapabilities) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.chaincodeMetadataMutex.RLock()
+	defer fake.chaincodeMetadataMutex.RUnlock()
+	fake.channelTemplatesMutex.RLock()
+	defer fake.channelTemplatesMutex.RUnlock()
+	fake.commutativeCountersMutex.RLock()
+	defer fake.commutativeCountersMutex.RUnlock()
 	fake.consensusTypeMigrationMutex.RLock()
 	defer fake.consensusTypeMigrationMutex.RUnlock()
+	fake.customTransactionsMutex.RLock()
+	defer fake.customTransactionsMutex.RUnlock()
+	fake.idemixAuditEscrowMutex.RLock()
+	defer fake.idemixAuditEscrowMutex.RUnlock()
 	fake.mSPVersionMutex.RLock()
 	defer fake.mSPVersionMutex.RUnlock()
 	fake.orgSpecificOrdererEndpointsMutex.RLock()
 	defer fake.orgSpecificOrdererEndpointsMutex.RUnlock()
+	fake.sM3HashingMutex.RLock()
+	defer fake.sM3HashingMutex.RUnlock()
 	fake.supportedMutex.RLock()
 	defer fake.supportedMutex.RUnlock()
+	fake.weightedSignaturePoliciesMutex.RLock()
+	defer fake.weightedSignaturePoliciesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -35,6 +35,10 @@ var ErrPermissionDenied = errors.New("permission denied")
 // as defined by ConsensusType.State != NORMAL. This typically happens during consensus-type migration.
 var ErrMaintenanceMode = errors.New("maintenance mode")
 
+// ErrStorageQuotaExceeded is returned when transactions are rejected because the ledger of the channel
+// exceeds the hard limit of its storage quota.
+var ErrStorageQuotaExceeded = errors.New("channel storage quota exceeded")
+
 // Classification represents the possible message types for the system.
 type Classification int
 
@@ -259,12 +259,17 @@ func (dt *DefaultTemplator) NewChannelConfig(envConfigUpdate *cb.Envelope) (chan
 		return nil, fmt.Errorf("Config update has an empty writeset")
 	}
 
-	if configUpdate.WriteSet.Groups == nil || configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey] == nil {
+	// A request referencing a channel template leaves the application group to the template
+	templateName, templated := configUpdate.IsolatedData[channelconfig.ChannelTemplateIsolatedDataKey]
+
+	if !templated && (configUpdate.WriteSet.Groups == nil || configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey] == nil) {
 		return nil, fmt.Errorf("Config update has missing application group")
 	}
 
-	if uv := configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey].Version; uv != 1 {
-		return nil, fmt.Errorf("Config update for channel creation does not set application group version to 1, was %d", uv)
+	if !templated {
+		if uv := configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey].Version; uv != 1 {
+			return nil, fmt.Errorf("Config update for channel creation does not set application group version to 1, was %d", uv)
+		}
 	}
 
 	consortiumConfigValue, ok := configUpdate.WriteSet.Values[channelconfig.ConsortiumKey]
@@ -289,6 +294,18 @@ func (dt *DefaultTemplator) NewChannelConfig(envConfigUpdate *cb.Envelope) (chan
 		return nil, fmt.Errorf("Unknown consortium name: %s", consortium.Name)
 	}
 
+	// Get the current system channel config
+	systemChannelGroup := dt.support.ConfigtxValidator().ConfigProto().ChannelGroup
+
+	if templated {
+		consortiumGroup := systemChannelGroup.Groups[channelconfig.ConsortiumsGroupKey].Groups[consortium.Name]
+		templateGroup, err := channelTemplateGroup(configUpdate, consortium.Name, consortiumConf, consortiumGroup, string(templateName))
+		if err != nil {
+			return nil, err
+		}
+		return dt.newChannelConfig(channelHeader.ChannelId, systemChannelGroup, consortium.Name, templateGroup, channelTemplateConsortiumModPolicy)
+	}
+
 	policyKey := channelconfig.ChannelCreationPolicyKey
 	if oc, ok := dt.support.OrdererConfig(); ok && oc.Capabilities().UseChannelCreationPolicyAsAdmins() {
 		// To resources the channel creation process, we use a copy of the Consortium's ChannelCreationPolicy
@@ -317,9 +334,6 @@ func (dt *DefaultTemplator) NewChannelConfig(envConfigUpdate *cb.Envelope) (chan
 	}
 	applicationGroup.ModPolicy = policyKey
 
-	// Get the current system channel config
-	systemChannelGroup := dt.support.ConfigtxValidator().ConfigProto().ChannelGroup
-
 	// If the consortium group has no members, allow the source request to have no members.  However,
 	// if the consortium group has any members, there must be at least one member in the source request
 	if len(systemChannelGroup.Groups[channelconfig.ConsortiumsGroupKey].Groups[consortium.Name].Groups) > 0 &&
@@ -345,6 +359,13 @@ func (dt *DefaultTemplator) NewChannelConfig(envConfigUpdate *cb.Envelope) (chan
 			len(configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups))
 	}
 
+	return dt.newChannelConfig(channelHeader.ChannelId, systemChannelGroup, consortium.Name, applicationGroup, channelconfig.AdminsPolicyKey)
+}
+
+// newChannelConfig creates the template channel configuration of a new channel of the given consortium, with the
+// given application group, the given mod_policy for the consortium value and the rest of the configuration copied
+// from the ordering system channel.
+func (dt *DefaultTemplator) newChannelConfig(channelID string, systemChannelGroup *cb.ConfigGroup, consortiumName string, applicationGroup *cb.ConfigGroup, consortiumModPolicy string) (channelconfig.Resources, error) {
 	channelGroup := protoutil.NewConfigGroup()
 
 	// Copy the system channel Channel level config to the new config
@@ -364,8 +385,8 @@ func (dt *DefaultTemplator) NewChannelConfig(envConfigUpdate *cb.Envelope) (chan
 	channelGroup.Groups[channelconfig.OrdererGroupKey] = proto.Clone(systemChannelGroup.Groups[channelconfig.OrdererGroupKey]).(*cb.ConfigGroup)
 	channelGroup.Groups[channelconfig.ApplicationGroupKey] = applicationGroup
 	channelGroup.Values[channelconfig.ConsortiumKey] = &cb.ConfigValue{
-		Value:     protoutil.MarshalOrPanic(channelconfig.ConsortiumValue(consortium.Name).Value()),
-		ModPolicy: channelconfig.AdminsPolicyKey,
+		Value:     protoutil.MarshalOrPanic(channelconfig.ConsortiumValue(consortiumName).Value()),
+		ModPolicy: consortiumModPolicy,
 	}
 
 	// Non-backwards compatible bugfix introduced in v1.1
@@ -375,7 +396,7 @@ func (dt *DefaultTemplator) NewChannelConfig(envConfigUpdate *cb.Envelope) (chan
 		zeroVersions(channelGroup)
 	}
 
-	bundle, err := channelconfig.NewBundle(channelHeader.ChannelId, &cb.Config{
+	bundle, err := channelconfig.NewBundle(channelID, &cb.Config{
 		ChannelGroup: channelGroup,
 	}, dt.bccsp)
 
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"fmt"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/orderer/common/audit"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// AuditLog returns the audit log of the orderer, or nil if auditing is disabled.
+func (r *Registrar) AuditLog() *audit.Log {
+	if r == nil {
+		return nil
+	}
+	return r.auditLog
+}
+
+// recordAudit appends the record to the audit log, if auditing is enabled.
+// Failing to record an action doesn't prevent the action, hence the failure
+// is only logged.
+func (r *Registrar) recordAudit(record audit.Record) {
+	if r.AuditLog() == nil {
+		return
+	}
+	if _, err := r.auditLog.Append(record); err != nil {
+		logger.Errorf("[channel: %s] Failed recording %s in the audit log: %s", record.ChannelID, record.Type, err)
+	}
+}
+
+// auditConfigUpdate records a config transaction applied to a channel, and the
+// change of the maintenance mode of the channel it results in, if any.
+func (r *Registrar) auditConfigUpdate(channelID string, blockNumber uint64, configEnv *cb.ConfigEnvelope, currentState, nextState ab.ConsensusType_State) {
+	if r.AuditLog() == nil {
+		return
+	}
+	identities := audit.ConfigUpdateIdentities(configEnv)
+	r.recordAudit(audit.Record{
+		Type:        audit.ConfigUpdate,
+		ChannelID:   channelID,
+		BlockNumber: blockNumber,
+		Identities:  identities,
+		Details:     fmt.Sprintf("config sequence %d", configEnv.GetConfig().GetSequence()),
+	})
+	if currentState != nextState {
+		r.recordAudit(audit.Record{
+			Type:        audit.MaintenanceMode,
+			ChannelID:   channelID,
+			BlockNumber: blockNumber,
+			Identities:  identities,
+			Details:     fmt.Sprintf("consensus state changed from %s to %s", currentState, nextState),
+		})
+	}
+}
+
+// auditChannelCreation records the creation of a channel by a transaction of
+// the system channel.
+func (r *Registrar) auditChannelCreation(systemChannelID string, blockNumber uint64, configTx *cb.Envelope) {
+	if r.AuditLog() == nil {
+		return
+	}
+	channelID, err := channelNameFromConfigTx(configTx)
+	if err != nil {
+		logger.Warnf("Failed extracting the name of the created channel for the audit log: %v", err)
+		return
+	}
+	var identities []audit.Identity
+	if payload, err := protoutil.UnmarshalPayload(configTx.Payload); err == nil {
+		if configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data); err == nil {
+			identities = audit.ConfigUpdateIdentities(configEnv)
+		}
+	}
+	r.recordAudit(audit.Record{
+		Type:       audit.ChannelCreate,
+		ChannelID:  channelID,
+		Identities: identities,
+		Details:    fmt.Sprintf("created by block %d of system channel %s", blockNumber, systemChannelID),
+	})
+}
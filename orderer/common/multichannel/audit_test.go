@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/orderer/common/audit"
+	"github.com/hyperledger/fabric/orderer/common/multichannel/mocks"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	auditLog, err := audit.NewLog(dir)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	signer := &mocks.SignerSerializer{}
+	signer.SerializeReturns(protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("admin")}), nil)
+	configUpdateTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, "mychannel", signer, &cb.ConfigUpdateEnvelope{}, 0, 0)
+	require.NoError(t, err)
+	configEnv := &cb.ConfigEnvelope{
+		Config:     &cb.Config{Sequence: 3},
+		LastUpdate: configUpdateTx,
+	}
+	configTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG, "mychannel", signer, configEnv, 0, 0)
+	require.NoError(t, err)
+
+	// auditing is a no-op without audit log
+	var r *Registrar
+	assert.Nil(t, r.AuditLog())
+	r.auditConfigUpdate("mychannel", 1, configEnv, ab.ConsensusType_STATE_NORMAL, ab.ConsensusType_STATE_MAINTENANCE)
+	r = &Registrar{}
+	r.auditChannelCreation("system-channel", 1, configTx)
+
+	r = &Registrar{auditLog: auditLog}
+	r.auditChannelCreation("system-channel", 1, configTx)
+	r.auditConfigUpdate("mychannel", 2, configEnv, ab.ConsensusType_STATE_NORMAL, ab.ConsensusType_STATE_NORMAL)
+	r.auditConfigUpdate("mychannel", 3, configEnv, ab.ConsensusType_STATE_NORMAL, ab.ConsensusType_STATE_MAINTENANCE)
+
+	records, err := auditLog.Records(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+
+	identities := []audit.Identity{{MSPID: "Org1MSP", Fingerprint: records[0].Identities[0].Fingerprint}}
+	assert.Equal(t, audit.ChannelCreate, records[0].Type)
+	assert.Equal(t, "mychannel", records[0].ChannelID)
+	assert.Equal(t, "created by block 1 of system channel system-channel", records[0].Details)
+	assert.Equal(t, identities, records[0].Identities)
+
+	assert.Equal(t, audit.ConfigUpdate, records[1].Type)
+	assert.Equal(t, uint64(2), records[1].BlockNumber)
+	assert.Equal(t, "config sequence 3", records[1].Details)
+	assert.Equal(t, identities, records[1].Identities)
+
+	assert.Equal(t, audit.ConfigUpdate, records[2].Type)
+	assert.Equal(t, audit.MaintenanceMode, records[3].Type)
+	assert.Equal(t, uint64(3), records[3].BlockNumber)
+	assert.Equal(t, "consensus state changed from STATE_NORMAL to STATE_MAINTENANCE", records[3].Details)
+	assert.Equal(t, identities, records[3].Identities)
+}
@@ -8,6 +8,7 @@ package multichannel
 
 import (
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -16,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/orderer/common/idempotency"
 	"github.com/hyperledger/fabric/protoutil"
 )
 
@@ -39,6 +41,16 @@ type BlockWriter struct {
 	lastConfigSeq      uint64
 	lastBlock          *cb.Block
 	committingBlock    sync.Mutex
+	attestTimestamps   bool
+
+	// the blocks are attested in ranges of rangeInterval blocks, rangeHashes
+	// holding the header hashes of the blocks from rangeStart written so far
+	rangeInterval uint64
+	rangeStart    uint64
+	rangeHashes   [][]byte
+
+	// keyIndex, if not nil, indexes the idempotency keys of the written blocks
+	keyIndex *idempotency.Index
 }
 
 func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSupport) *BlockWriter {
@@ -48,6 +60,10 @@ func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSuppor
 		lastBlock:     lastBlock,
 		registrar:     r,
 	}
+	if r != nil {
+		bw.attestTimestamps = r.config.General.BlockTimestamp.Enabled
+		bw.rangeInterval = r.config.General.RangeAttestation.Interval
+	}
 
 	// If this is the genesis block, the lastconfig field may be empty, and, the last config block is necessarily block 0
 	// so no need to initialize lastConfig
@@ -83,6 +99,12 @@ func (bw *BlockWriter) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
 	block.Header.DataHash = protoutil.BlockDataHash(data)
 	block.Data = data
 
+	if bw.attestTimestamps {
+		if err := protoutil.SetBlockTimestamp(block, time.Now()); err != nil {
+			logger.Panicf("[channel: %s] Could not stamp the time of block [%d]: %s", bw.support.ChannelID(), block.Header.Number, err)
+		}
+	}
+
 	return block
 }
 
@@ -116,6 +138,7 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 			logger.Panicf("Told to write a config block with new channel, but did not have config update embedded: %s", err)
 		}
 		bw.registrar.newChain(newChannelConfig)
+		bw.registrar.auditChannelCreation(chdr.ChannelId, block.Header.Number, newChannelConfig)
 
 	case int32(cb.HeaderType_CONFIG):
 		configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
@@ -140,6 +163,7 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 
 		currentType := bw.support.SharedConfig().ConsensusType()
 		nextType := oc.ConsensusType()
+		currentState := bw.support.SharedConfig().ConsensusState()
 		if currentType != nextType {
 			encodedMetadataValue = nil
 			logger.Debugf("[channel: %s] Consensus-type migration: maintenance mode, change from %s to %s, setting metadata to nil",
@@ -152,6 +176,7 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 		bw.committingBlock.Lock()
 		bw.committingBlock.Unlock()
 		bw.support.Update(bundle)
+		bw.registrar.auditConfigUpdate(chdr.ChannelId, block.Header.Number, configEnvelope, currentState, oc.ConsensusState())
 	default:
 		logger.Panicf("Told to write a config block with unknown header type: %v", chdr.Type)
 	}
@@ -180,12 +205,26 @@ func (bw *BlockWriter) WriteBlock(block *cb.Block, encodedMetadataValue []byte)
 func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte) {
 	bw.addLastConfig(bw.lastBlock)
 	bw.addBlockSignature(bw.lastBlock, encodedMetadataValue)
+	if bw.attestTimestamps {
+		bw.addBlockTimestamp(bw.lastBlock)
+	} else {
+		// a block cut by a leader attesting timestamps carries a time which
+		// this orderer does not sign, and which peers would reject unsigned
+		protoutil.RemoveBlockTimestamp(bw.lastBlock)
+	}
+	if bw.rangeInterval > 0 {
+		bw.addRangeAttestation(bw.lastBlock)
+	}
 
 	err := bw.support.Append(bw.lastBlock)
 	if err != nil {
 		logger.Panicf("[channel: %s] Could not append block: %s", bw.support.ChannelID(), err)
 	}
 	logger.Debugf("[channel: %s] Wrote block [%d]", bw.support.ChannelID(), bw.lastBlock.GetHeader().Number)
+
+	if bw.keyIndex != nil {
+		bw.keyIndex.BlockWritten(bw.lastBlock)
+	}
 }
 
 func (bw *BlockWriter) addBlockSignature(block *cb.Block, consenterMetadata []byte) {
@@ -211,6 +250,56 @@ func (bw *BlockWriter) addBlockSignature(block *cb.Block, consenterMetadata []by
 	})
 }
 
+// addBlockTimestamp attests the time at which the block was cut. A block which
+// was not stamped when cut, e.g. by a leader not attesting timestamps, is
+// stamped with the time at which it is written.
+func (bw *BlockWriter) addBlockTimestamp(block *cb.Block) {
+	if !protoutil.HasBlockTimestamp(block) {
+		if err := protoutil.SetBlockTimestamp(block, time.Now()); err != nil {
+			logger.Panicf("[channel: %s] Could not stamp the time of block [%d]: %s", bw.support.ChannelID(), block.Header.Number, err)
+		}
+	}
+	if err := protoutil.SignBlockTimestamp(block, bw.support); err != nil {
+		logger.Panicf("[channel: %s] Could not attest the timestamp of block [%d]: %s", bw.support.ChannelID(), block.Header.Number, err)
+	}
+}
+
+// addRangeAttestation attests the range of blocks ended by the block when its
+// number closes a range. The ranges are aligned on the interval so that the
+// attested ranges do not depend on when the orderer restarted.
+func (bw *BlockWriter) addRangeAttestation(block *cb.Block) {
+	number := block.Header.Number
+	if len(bw.rangeHashes) == 0 {
+		bw.rangeStart = number
+	}
+	bw.rangeHashes = append(bw.rangeHashes, protoutil.BlockHeaderHash(block.Header))
+	if (number+1)%bw.rangeInterval != 0 {
+		return
+	}
+
+	start := number + 1 - bw.rangeInterval
+	hashes := bw.rangeHashes
+	bw.rangeHashes = nil
+	if bw.rangeStart != start || uint64(len(hashes)) != bw.rangeInterval {
+		// some blocks of the range were written before the orderer started,
+		// or pulled from other orderers while catching up
+		hashes = make([][]byte, 0, bw.rangeInterval)
+		for n := start; n < number; n++ {
+			previous, err := bw.support.RetrieveBlockByNumber(n)
+			if err != nil {
+				logger.Panicf("[channel: %s] Could not retrieve block [%d] to attest the range of blocks [%d] to [%d]: %s", bw.support.ChannelID(), n, start, number, err)
+			}
+			hashes = append(hashes, protoutil.BlockHeaderHash(previous.Header))
+		}
+		hashes = append(hashes, protoutil.BlockHeaderHash(block.Header))
+	}
+
+	if err := protoutil.AddBlockRangeAttestation(block, bw.support, start, hashes); err != nil {
+		logger.Panicf("[channel: %s] Could not attest the range of blocks [%d] to [%d]: %s", bw.support.ChannelID(), start, number, err)
+	}
+	logger.Debugf("[channel: %s] Attested the range of blocks [%d] to [%d]", bw.support.ChannelID(), start, number)
+}
+
 func (bw *BlockWriter) addLastConfig(block *cb.Block) {
 	configSeq := bw.support.Sequence()
 	if configSeq > bw.lastConfigSeq {
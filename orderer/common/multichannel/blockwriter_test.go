@@ -10,8 +10,10 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/bccsp"
@@ -113,6 +115,118 @@ func TestBlockSignature(t *testing.T) {
 	assert.NotNil(t, md.Signatures, "Should have signature")
 }
 
+func TestBlockTimestamp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rlf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+
+	l, err := rlf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	lastBlock := protoutil.NewBlock(0, nil)
+	l.Append(lastBlock)
+
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			SignerSerializer:  mockCrypto(),
+			ConfigTXValidator: &mocks.ConfigTXValidator{},
+			ReadWriter:        l,
+		},
+		lastBlock:        protoutil.NewBlock(1, protoutil.BlockHeaderHash(lastBlock.Header)),
+		attestTimestamps: true,
+	}
+
+	before := time.Now()
+	bw.commitBlock(nil)
+
+	it, _ := l.Iterator(&orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{}})
+	committedBlock, status := it.Next()
+	require.Equal(t, cb.Status_SUCCESS, status)
+
+	bt, signatures, err := protoutil.GetBlockTimestamp(committedBlock)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), bt.Number)
+	ts, err := ptypes.Timestamp(bt.Timestamp)
+	require.NoError(t, err)
+	require.False(t, ts.Before(before))
+	require.Len(t, signatures, 1)
+
+	// the time stamped when the block was cut, e.g. by the Raft leader, is kept
+	cut := time.Unix(1600000000, 0)
+	block := protoutil.NewBlock(2, protoutil.BlockHeaderHash(committedBlock.Header))
+	require.NoError(t, protoutil.SetBlockTimestamp(block, cut))
+	bw.lastBlock = block
+	bw.commitBlock(nil)
+
+	bt, signatures, err = protoutil.GetBlockTimestamp(block)
+	require.NoError(t, err)
+	ts, err = ptypes.Timestamp(bt.Timestamp)
+	require.NoError(t, err)
+	require.True(t, ts.Equal(cut))
+	require.Len(t, signatures, 1)
+
+	// an orderer not attesting timestamps drops the unsigned time
+	block = protoutil.NewBlock(3, protoutil.BlockHeaderHash(block.Header))
+	require.NoError(t, protoutil.SetBlockTimestamp(block, cut))
+	bw.attestTimestamps = false
+	bw.lastBlock = block
+	bw.commitBlock(nil)
+	require.False(t, protoutil.HasBlockTimestamp(block))
+}
+
+func TestBlockRangeAttestation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rlf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+
+	l, err := rlf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	genesisBlock := protoutil.NewBlock(0, nil)
+	l.Append(genesisBlock)
+
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			SignerSerializer:  mockCrypto(),
+			ConfigTXValidator: &mocks.ConfigTXValidator{},
+			ReadWriter:        l,
+		},
+		lastBlock:     genesisBlock,
+		rangeInterval: 3,
+	}
+	for i := 1; i <= 5; i++ {
+		bw.lastBlock = bw.CreateNextBlock([]*cb.Envelope{{Payload: []byte{byte(i)}}})
+		bw.commitBlock(nil)
+	}
+
+	var headers []*cb.BlockHeader
+	for n := uint64(0); n <= 5; n++ {
+		block, err := l.RetrieveBlockByNumber(n)
+		require.NoError(t, err)
+		headers = append(headers, block.Header)
+
+		bra, signatures, err := protoutil.GetBlockRangeAttestation(block)
+		require.NoError(t, err)
+		switch n {
+		case 2:
+			// the genesis block was written before the block writer started
+			require.Equal(t, uint64(0), bra.Start)
+			require.NoError(t, protoutil.VerifyBlockRange(bra, headers[0:3]))
+			require.Len(t, signatures, 1)
+		case 5:
+			require.Equal(t, uint64(3), bra.Start)
+			require.NoError(t, protoutil.VerifyBlockRange(bra, headers[3:6]))
+			require.Len(t, signatures, 1)
+		default:
+			require.Nil(t, bra, "block [%d] must not carry a range attestation", n)
+		}
+	}
+}
+
 func TestBlockLastConfig(t *testing.T) {
 	lastConfigSeq := uint64(6)
 	newConfigSeq := lastConfigSeq + 1
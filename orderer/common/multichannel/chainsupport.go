@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/idempotency"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -28,6 +29,9 @@ type ChainSupport struct {
 	*BlockWriter
 	consensus.Chain
 	cutter blockcutter.Receiver
+	// keyIndex holds the idempotency keys of the last blocks of the channel,
+	// it is nil unless the idempotency keys are enabled
+	keyIndex *idempotency.Index
 	identity.SignerSerializer
 	BCCSP bccsp.BCCSP
 
@@ -40,6 +44,10 @@ type ChainSupport struct {
 	// Therefore, we let each chain report its cluster relation and status through this interface. Non cluster
 	// type chains (solo, kafka) are assigned a static reporter.
 	consensus.StatusReporter
+
+	// Chains that know about their consenter set (etcdraft) report whether it has quorum, the other
+	// chains are considered to have quorum as long as they are not errored.
+	consensus.QuorumReporter
 }
 
 func newChainSupport(
@@ -63,19 +71,17 @@ func newChainSupport(
 	cs := &ChainSupport{
 		ledgerResources:  ledgerResources,
 		SignerSerializer: signer,
-		cutter: blockcutter.NewReceiverImpl(
-			ledgerResources.ConfigtxValidator().ChannelID(),
-			ledgerResources,
-			blockcutterMetrics,
-		),
-		BCCSP: bccsp,
+		keyIndex:         newKeyIndex(registrar, ledgerResources),
+		BCCSP:            bccsp,
 	}
+	cs.cutter = newCutter(ledgerResources, cs.keyIndex, blockcutterMetrics)
 
 	// Set up the msgprocessor
 	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config), bccsp)
 
 	// Set up the block writer
 	cs.BlockWriter = newBlockWriter(lastBlock, registrar, cs)
+	cs.BlockWriter.keyIndex = cs.keyIndex
 
 	// Set up the consenter
 	consenterType := ledgerResources.SharedConfig().ConsensusType()
@@ -99,6 +105,11 @@ func newChainSupport(
 		cs.StatusReporter = consensus.StaticStatusReporter{ClusterRelation: types.ClusterRelationNone, Status: types.StatusActive}
 	}
 
+	cs.QuorumReporter, ok = cs.Chain.(consensus.QuorumReporter)
+	if !ok {
+		cs.QuorumReporter = consensus.ErroredQuorumReporter{Chain: cs.Chain}
+	}
+
 	logger.Debugf("[channel: %s] Done creating channel support resources", cs.ChannelID())
 
 	return cs, nil
@@ -126,12 +137,8 @@ func newChainSupportForJoin(
 	cs := &ChainSupport{
 		ledgerResources:  ledgerResources,
 		SignerSerializer: signer,
-		cutter: blockcutter.NewReceiverImpl(
-			ledgerResources.ConfigtxValidator().ChannelID(),
-			ledgerResources,
-			blockcutterMetrics,
-		),
-		BCCSP: bccsp,
+		cutter:           newCutter(ledgerResources, nil, blockcutterMetrics),
+		BCCSP:            bccsp,
 	}
 
 	// Set up the msgprocessor
@@ -162,11 +169,47 @@ func newChainSupportForJoin(
 		cs.StatusReporter = consensus.StaticStatusReporter{ClusterRelation: types.ClusterRelationNone, Status: types.StatusActive}
 	}
 
+	cs.QuorumReporter, ok = cs.Chain.(consensus.QuorumReporter)
+	if !ok {
+		cs.QuorumReporter = consensus.ErroredQuorumReporter{Chain: cs.Chain}
+	}
+
 	logger.Debugf("[channel: %s] Done creating channel support resources for join", cs.ChannelID())
 
 	return cs, nil
 }
 
+// newKeyIndex indexes the idempotency keys of the last blocks of the channel,
+// or returns nil if the idempotency keys are not enabled.
+func newKeyIndex(registrar *Registrar, ledgerResources *ledgerResources) *idempotency.Index {
+	if !registrar.config.General.IdempotencyKeys.Enabled {
+		return nil
+	}
+	keyIndex := idempotency.NewIndex(registrar.config.General.IdempotencyKeys.LookbackBlocks)
+	keyIndex.Load(ledgerResources)
+	return keyIndex
+}
+
+func newCutter(ledgerResources *ledgerResources, keyIndex *idempotency.Index, blockcutterMetrics *blockcutter.Metrics) blockcutter.Receiver {
+	// a nil index must not be passed as a non nil interface
+	var cutterKeyIndex blockcutter.KeyIndex
+	if keyIndex != nil {
+		cutterKeyIndex = keyIndex
+	}
+	return blockcutter.NewReceiverImpl(
+		ledgerResources.ConfigtxValidator().ChannelID(),
+		ledgerResources,
+		cutterKeyIndex,
+		blockcutterMetrics,
+	)
+}
+
+// IdempotencyIndex returns the idempotency keys of the last blocks of the
+// channel, or nil if the idempotency keys are not enabled.
+func (cs *ChainSupport) IdempotencyIndex() *idempotency.Index {
+	return cs.keyIndex
+}
+
 // Block returns a block with the following number,
 // or nil if such a block doesn't exist.
 func (cs *ChainSupport) Block(number uint64) *cb.Block {
@@ -180,6 +223,25 @@ func (cs *ChainSupport) Reader() blockledger.Reader {
 	return cs
 }
 
+// FirstRetainedBlock returns the number of the first block kept by the ledger
+// of the channel, 0 if the ledger retains all its blocks.
+func (cs *ChainSupport) FirstRetainedBlock() uint64 {
+	if pruned, ok := cs.ledgerResources.ReadWriter.(blockledger.Pruned); ok {
+		return pruned.FirstRetainedBlock()
+	}
+	return 0
+}
+
+// Prune removes the blocks of the channel numbered below the given block
+// number, but the config blocks.
+func (cs *ChainSupport) Prune(belowBlockNum uint64) error {
+	pruned, ok := cs.ledgerResources.ReadWriter.(blockledger.Pruned)
+	if !ok {
+		return errors.New("the ledger does not support pruning")
+	}
+	return pruned.Prune(belowBlockNum)
+}
+
 // Signer returns the SignerSerializer for this channel.
 func (cs *ChainSupport) Signer() identity.SignerSerializer {
 	return cs
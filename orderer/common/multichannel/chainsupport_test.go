@@ -52,6 +52,33 @@ func TestChainSupportBlock(t *testing.T) {
 	assert.Equal(t, uint64(99), cs.Block(99).Header.Number)
 }
 
+type prunedReadWriter struct {
+	*mocks.ReadWriter
+	prunedBelow uint64
+}
+
+func (rw *prunedReadWriter) FirstRetainedBlock() uint64 {
+	return rw.prunedBelow
+}
+
+func (rw *prunedReadWriter) Prune(belowBlockNum uint64) error {
+	rw.prunedBelow = belowBlockNum
+	return nil
+}
+
+func TestChainSupportPrune(t *testing.T) {
+	cs := &ChainSupport{
+		ledgerResources: &ledgerResources{ReadWriter: &mocks.ReadWriter{}},
+	}
+	assert.Zero(t, cs.FirstRetainedBlock())
+	assert.EqualError(t, cs.Prune(10), "the ledger does not support pruning")
+
+	cs.ledgerResources.ReadWriter = &prunedReadWriter{ReadWriter: &mocks.ReadWriter{}}
+	var _ blockledger.Pruned = cs
+	assert.NoError(t, cs.Prune(10))
+	assert.Equal(t, uint64(10), cs.FirstRetainedBlock())
+}
+
 type mutableResourcesMock struct {
 	*mocks.Resources
 	newConsensusMetadataVal []byte
@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"sync"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+// ledgerCloser is implemented by the ledger factories able to release the
+// resources of the ledger of a single channel.
+type ledgerCloser interface {
+	CloseLedger(channelID string)
+}
+
+// hibernatedChannel holds what is reported about a hibernated channel.
+type hibernatedChannel struct {
+	height          uint64
+	clusterRelation types.ClusterRelation
+}
+
+// channelActivity tracks the activity of a channel: the last time a transaction was
+// received or a block was written or delivered, and the number of open ledger iterators.
+type channelActivity struct {
+	mutex       sync.Mutex
+	lastActive  time.Time
+	readers     int
+	reactivated bool
+}
+
+func (a *channelActivity) touch() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.lastActive = time.Now()
+}
+
+func (a *channelActivity) openReader() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.readers++
+	a.lastActive = time.Now()
+}
+
+func (a *channelActivity) closeReader() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.readers--
+	a.lastActive = time.Now()
+}
+
+func (a *channelActivity) markReactivated() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.reactivated = true
+	a.lastActive = time.Now()
+}
+
+// idle returns whether the channel had no activity for the given timeout. A channel
+// reactivated since the previous check is not idle, so that the request which woke
+// it up is served before it may hibernate again.
+func (a *channelActivity) idle(now time.Time, timeout time.Duration) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.reactivated {
+		a.reactivated = false
+		return false
+	}
+	return a.readers == 0 && now.Sub(a.lastActive) >= timeout
+}
+
+// activityLedger records the activity of a channel on the reads and writes of its ledger.
+type activityLedger struct {
+	blockledger.ReadWriter
+	activity *channelActivity
+}
+
+func (l *activityLedger) Append(block *cb.Block) error {
+	l.activity.touch()
+	return l.ReadWriter.Append(block)
+}
+
+func (l *activityLedger) Iterator(startType *ab.SeekPosition) (blockledger.Iterator, uint64) {
+	iterator, number := l.ReadWriter.Iterator(startType)
+	l.activity.openReader()
+	return &activityIterator{Iterator: iterator, activity: l.activity}, number
+}
+
+type activityIterator struct {
+	blockledger.Iterator
+	activity  *channelActivity
+	closeOnce sync.Once
+}
+
+func (i *activityIterator) Next() (*cb.Block, cb.Status) {
+	block, status := i.Iterator.Next()
+	i.activity.touch()
+	return block, status
+}
+
+func (i *activityIterator) Close() {
+	i.closeOnce.Do(func() {
+		i.Iterator.Close()
+		i.activity.closeReader()
+	})
+}
+
+// channelActivity returns the activity tracker of a channel, which survives the
+// hibernations of the channel.
+func (r *Registrar) channelActivity(channelID string) *channelActivity {
+	r.activityLock.Lock()
+	defer r.activityLock.Unlock()
+
+	activity, ok := r.activity[channelID]
+	if !ok {
+		activity = &channelActivity{lastActive: time.Now()}
+		r.activity[channelID] = activity
+	}
+	return activity
+}
+
+// startHibernation periodically hibernates the application channels without activity.
+func (r *Registrar) startHibernation() {
+	conf := r.config.ChannelHibernation
+	logger.Infof("Channels without activity for %s hibernate, checking every %s", conf.IdleTimeout, conf.CheckInterval)
+	go func() {
+		ticker := time.NewTicker(conf.CheckInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			r.hibernateIdleChannels(now)
+		}
+	}()
+}
+
+// hibernateIdleChannels halts the application channels without activity for the idle
+// timeout and closes their ledgers. The system channel never hibernates.
+//
+// The channels are marked hibernated under the lock, and halted outside of it, as
+// halting a chain waits for its goroutines which may themselves need the lock.
+// A request for a channel being halted reactivates it only once its ledger is closed.
+func (r *Registrar) hibernateIdleChannels(now time.Time) {
+	r.hibernationLock.Lock()
+	defer r.hibernationLock.Unlock()
+
+	idle := map[string]*ChainSupport{}
+	r.lock.Lock()
+	for channelID, cs := range r.chains {
+		if channelID == r.systemChannelID {
+			continue
+		}
+		if !r.channelActivity(channelID).idle(now, r.config.ChannelHibernation.IdleTimeout) {
+			continue
+		}
+
+		relation, _ := cs.StatusReport()
+		delete(r.chains, channelID)
+		r.hibernated[channelID] = &hibernatedChannel{height: cs.Height(), clusterRelation: relation}
+		idle[channelID] = cs
+	}
+	r.lock.Unlock()
+
+	for channelID, cs := range idle {
+		cs.Halt()
+		if closer, ok := r.ledgerFactory.(ledgerCloser); ok {
+			closer.CloseLedger(channelID)
+		}
+		logger.Infof("Channel %s hibernated after %s without activity", channelID, r.config.ChannelHibernation.IdleTimeout)
+	}
+}
+
+// reactivate restarts a hibernated channel and returns its chain support, or nil if the
+// channel is not hibernated anymore and does not exist, or could not be restarted.
+func (r *Registrar) reactivate(channelID string) *ChainSupport {
+	// wait for the channels being hibernated to be halted
+	r.hibernationLock.Lock()
+	defer r.hibernationLock.Unlock()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if cs, ok := r.chains[channelID]; ok {
+		return cs
+	}
+	if _, ok := r.hibernated[channelID]; !ok {
+		return nil
+	}
+
+	rl, err := r.ledgerFactory.GetOrCreate(channelID)
+	if err != nil {
+		logger.Errorf("Failed reopening the ledger of hibernated channel %s: %s", channelID, err)
+		return nil
+	}
+	ledgerResources, err := r.newLedgerResources(configTx(rl))
+	if err != nil {
+		logger.Errorf("Failed creating the ledger resources of hibernated channel %s: %s", channelID, err)
+		return nil
+	}
+	cs, err := newChainSupport(r, ledgerResources, r.consenters, r.signer, r.blockcutterMetrics, r.bccsp)
+	if err != nil {
+		logger.Errorf("Failed creating the chain support of hibernated channel %s: %s", channelID, err)
+		return nil
+	}
+
+	delete(r.hibernated, channelID)
+	r.chains[channelID] = cs
+	r.channelActivity(channelID).markReactivated()
+	logger.Infof("Reactivating hibernated channel %s", channelID)
+	cs.start()
+	return cs
+}
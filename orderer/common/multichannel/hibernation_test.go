@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
+	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHibernationRegistrar(t *testing.T) *Registrar {
+	confSys := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
+	genesisBlockSys := encoder.New(confSys).GenesisBlock()
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	tmpdir, err := ioutil.TempDir("", "hibernation_test-")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(tmpdir) })
+
+	lf, _ := newLedgerAndFactory(tmpdir, "testchannelid", genesisBlockSys)
+
+	consenters := make(map[string]consensus.Consenter)
+	consenters[confSys.Orderer.OrdererType] = &mockConsenter{cluster: true}
+
+	config := localconfig.TopLevel{
+		ChannelHibernation: localconfig.ChannelHibernation{
+			Enabled:       true,
+			IdleTimeout:   time.Hour,
+			CheckInterval: time.Hour,
+		},
+	}
+	manager := NewRegistrar(config, lf, mockCrypto(), &disabled.Provider{}, cryptoProvider)
+	manager.Initialize(consenters)
+
+	ledger, err := lf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	require.NoError(t, ledger.Append(encoder.New(confSys).GenesisBlockForChannel("mychannel")))
+	manager.CreateChain("mychannel")
+
+	return manager
+}
+
+func TestHibernateIdleChannels(t *testing.T) {
+	manager := newHibernationRegistrar(t)
+	chain := manager.GetChain("mychannel")
+	require.NotNil(t, chain)
+
+	// Channels active within the idle timeout do not hibernate
+	manager.hibernateIdleChannels(time.Now())
+	assert.Equal(t, chain, manager.GetChain("mychannel"))
+
+	manager.hibernateIdleChannels(time.Now().Add(2 * time.Hour))
+
+	// The chain is halted, but the channel is still reported
+	_, ok := <-chain.Chain.(*mockChainCluster).queue
+	assert.False(t, ok)
+	assert.Equal(t, 2, manager.ChannelsCount())
+	assert.Equal(t,
+		types.ChannelList{
+			SystemChannel: &types.ChannelInfoShort{Name: "testchannelid"},
+			Channels:      []types.ChannelInfoShort{{Name: "mychannel"}},
+		},
+		manager.ChannelList(),
+	)
+	info, err := manager.ChannelInfo("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t,
+		types.ChannelInfo{Name: "mychannel", ClusterRelation: types.ClusterRelationMember, Status: types.StatusHibernated, Height: 1},
+		info,
+	)
+	assert.NotContains(t, manager.QuorumStatus(), "mychannel")
+
+	// The system channel never hibernates
+	info, err = manager.ChannelInfo("testchannelid")
+	assert.NoError(t, err)
+	assert.Equal(t, types.StatusActive, info.Status)
+
+	_, err = manager.JoinChannel("mychannel", nil, true)
+	assert.Equal(t, types.ErrSystemChannelExists, err)
+
+	// Looking up the active chains does not reactivate the channel
+	assert.Nil(t, manager.GetActiveChain("mychannel"))
+	info, err = manager.ChannelInfo("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, types.StatusHibernated, info.Status)
+
+	// The next request reactivates the channel from its ledger
+	reactivated := manager.GetChain("mychannel")
+	require.NotNil(t, reactivated)
+	assert.NotEqual(t, chain, reactivated)
+	assert.Equal(t, uint64(1), reactivated.Height())
+	info, err = manager.ChannelInfo("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, types.StatusActive, info.Status)
+
+	assert.Equal(t, reactivated, manager.GetActiveChain("mychannel"))
+
+	// A reactivated channel is not hibernated by the following check
+	manager.hibernateIdleChannels(time.Now().Add(2 * time.Hour))
+	assert.Equal(t, reactivated, manager.GetChain("mychannel"))
+
+	// nor before the idle timeout elapses from its reactivation
+	manager.hibernateIdleChannels(time.Now().Add(time.Minute))
+	assert.Equal(t, reactivated, manager.GetActiveChain("mychannel"))
+
+	// But hibernates again if it stays idle
+	manager.hibernateIdleChannels(time.Now().Add(2 * time.Hour))
+	_, ok = <-reactivated.Chain.(*mockChainCluster).queue
+	assert.False(t, ok)
+	info, err = manager.ChannelInfo("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, types.StatusHibernated, info.Status)
+}
+
+func TestHibernationOpenIterator(t *testing.T) {
+	manager := newHibernationRegistrar(t)
+	chain := manager.GetChain("mychannel")
+	require.NotNil(t, chain)
+
+	// A channel with an open deliver stream does not hibernate
+	iterator, _ := chain.Reader().Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})
+	manager.hibernateIdleChannels(time.Now().Add(2 * time.Hour))
+	assert.Equal(t, chain, manager.GetChain("mychannel"))
+
+	iterator.Close()
+	iterator.Close()
+	manager.hibernateIdleChannels(time.Now().Add(2 * time.Hour))
+	info, err := manager.ChannelInfo("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, types.StatusHibernated, info.Status)
+}
+
+func TestHibernationBroadcastActivity(t *testing.T) {
+	manager := newHibernationRegistrar(t)
+	chain := manager.GetChain("mychannel")
+	require.NotNil(t, chain)
+
+	manager.channelActivity("mychannel").lastActive = time.Now().Add(-2 * time.Hour)
+	_, _, cs, err := manager.BroadcastChannelSupport(makeNormalTx("mychannel", 1))
+	require.NoError(t, err)
+	assert.Equal(t, chain, cs)
+
+	manager.hibernateIdleChannels(time.Now().Add(time.Minute))
+	assert.Equal(t, chain, manager.GetChain("mychannel"))
+}
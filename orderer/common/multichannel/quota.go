@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/pkg/errors"
+)
+
+var (
+	channelStorageBytes = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Name:         "channel_storage_bytes",
+		Help:         "The disk space used by the ledger of the channel in bytes.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	channelStorageQuotaState = metrics.GaugeOpts{
+		Namespace:    "ledger",
+		Name:         "channel_storage_quota_state",
+		Help:         "The storage quota state of the channel: 0 if within its limits, 1 if over its soft limit, 2 if over its hard limit.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+)
+
+// QuotaMetrics are the metrics of the storage quotas of the channels.
+type QuotaMetrics struct {
+	StorageBytes      metrics.Gauge
+	StorageQuotaState metrics.Gauge
+}
+
+// NewQuotaMetrics creates the metrics of the storage quotas of the channels.
+func NewQuotaMetrics(p metrics.Provider) *QuotaMetrics {
+	return &QuotaMetrics{
+		StorageBytes:      p.NewGauge(channelStorageBytes),
+		StorageQuotaState: p.NewGauge(channelStorageQuotaState),
+	}
+}
+
+// ledgerSizer is implemented by the ledger factories able to report the disk space
+// used by the ledger of a channel.
+type ledgerSizer interface {
+	LedgerSize(channelID string) (int64, error)
+}
+
+// quotaState is the position of the ledger of a channel relative to its limits.
+type quotaState int
+
+const (
+	quotaOK quotaState = iota
+	quotaSoftExceeded
+	quotaHardExceeded
+)
+
+// channelQuota holds the last measured size of the ledger of a channel.
+type channelQuota struct {
+	size   int64
+	limits localconfig.StorageQuotaLimits
+	state  quotaState
+}
+
+// storageQuotas tracks the sizes of the ledgers of the channels against their limits.
+type storageQuotas struct {
+	mutex    sync.Mutex
+	channels map[string]*channelQuota
+}
+
+// exceeded returns the quota of the channel if its ledger is over its hard limit.
+func (q *storageQuotas) exceeded(channelID string) *channelQuota {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if cq, ok := q.channels[channelID]; ok && cq.state == quotaHardExceeded {
+		c := *cq
+		return &c
+	}
+	return nil
+}
+
+// startStorageQuotas periodically checks the sizes of the ledgers of the channels
+// against their storage quotas.
+func (r *Registrar) startStorageQuotas() {
+	if _, ok := r.ledgerFactory.(ledgerSizer); !ok {
+		logger.Warningf("Storage quotas are enabled but the ledger factory cannot report the size of the ledgers, ignoring them")
+		return
+	}
+	conf := r.config.StorageQuota
+	logger.Infof("Checking the storage quotas of the channels every %s", conf.CheckInterval)
+	r.checkStorageQuotas()
+	go func() {
+		ticker := time.NewTicker(conf.CheckInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			r.checkStorageQuotas()
+		}
+	}()
+}
+
+// checkStorageQuotas measures the ledgers of the active channels and updates their
+// quota states, alerting when a channel crosses its soft or hard limit.
+func (r *Registrar) checkStorageQuotas() {
+	sizer, ok := r.ledgerFactory.(ledgerSizer)
+	if !ok {
+		return
+	}
+
+	r.lock.RLock()
+	channelIDs := make([]string, 0, len(r.chains))
+	for channelID := range r.chains {
+		channelIDs = append(channelIDs, channelID)
+	}
+	r.lock.RUnlock()
+
+	for _, channelID := range channelIDs {
+		size, err := sizer.LedgerSize(channelID)
+		if err != nil {
+			logger.Debugf("Failed measuring the ledger of channel %s: %s", channelID, err)
+			continue
+		}
+		r.updateStorageQuota(channelID, size)
+	}
+}
+
+func (r *Registrar) updateStorageQuota(channelID string, size int64) {
+	limits := r.config.StorageQuota.Limits(channelID)
+	state := quotaOK
+	switch {
+	case limits.HardLimit != 0 && uint64(size) > limits.HardLimit:
+		state = quotaHardExceeded
+	case limits.SoftLimit != 0 && uint64(size) > limits.SoftLimit:
+		state = quotaSoftExceeded
+	}
+
+	r.quotas.mutex.Lock()
+	cq, ok := r.quotas.channels[channelID]
+	if !ok {
+		cq = &channelQuota{}
+		r.quotas.channels[channelID] = cq
+	}
+	previous := cq.state
+	cq.size, cq.limits, cq.state = size, limits, state
+	r.quotas.mutex.Unlock()
+
+	r.quotaMetrics.StorageBytes.With("channel", channelID).Set(float64(size))
+	r.quotaMetrics.StorageQuotaState.With("channel", channelID).Set(float64(state))
+
+	if state == previous {
+		return
+	}
+	switch state {
+	case quotaHardExceeded:
+		logger.Errorf("Channel %s uses %d bytes, over its hard limit of %d bytes: rejecting its transactions", channelID, size, limits.HardLimit)
+	case quotaSoftExceeded:
+		logger.Warningf("Channel %s uses %d bytes, over its soft limit of %d bytes", channelID, size, limits.SoftLimit)
+	default:
+		logger.Infof("Channel %s uses %d bytes, back within its storage quota", channelID, size)
+	}
+}
+
+// checkStorageQuota returns an error if the ledger of the channel is over its hard limit.
+func (r *Registrar) checkStorageQuota(channelID string) error {
+	if !r.config.StorageQuota.Enabled {
+		return nil
+	}
+	if cq := r.quotas.exceeded(channelID); cq != nil {
+		return errors.WithMessagef(msgprocessor.ErrStorageQuotaExceeded,
+			"channel %s uses %d bytes, over its hard limit of %d bytes", channelID, cq.size, cq.limits.HardLimit)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
+	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageQuotas(t *testing.T) {
+	confSys := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
+	genesisBlockSys := encoder.New(confSys).GenesisBlock()
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	tmpdir, err := ioutil.TempDir("", "quota_test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	lf, _ := newLedgerAndFactory(tmpdir, "testchannelid", genesisBlockSys)
+	size, err := lf.(ledgerSizer).LedgerSize("testchannelid")
+	require.NoError(t, err)
+	require.True(t, size > 0)
+
+	consenters := map[string]consensus.Consenter{confSys.Orderer.OrdererType: &mockConsenter{}}
+
+	fakeGauge := &metricsfakes.Gauge{}
+	fakeGauge.WithReturns(fakeGauge)
+	fakeProvider := &metricsfakes.Provider{}
+	fakeProvider.NewGaugeReturns(fakeGauge)
+	fakeProvider.NewHistogramReturns(&metricsfakes.Histogram{})
+
+	config := localconfig.TopLevel{
+		StorageQuota: localconfig.StorageQuota{
+			Enabled:       true,
+			CheckInterval: time.Hour,
+			Channels: map[string]localconfig.StorageQuotaLimits{
+				"testchannelid": {SoftLimit: uint64(size) - 1, HardLimit: uint64(size) + 100},
+			},
+		},
+	}
+	manager := NewRegistrar(config, lf, mockCrypto(), fakeProvider, cryptoProvider)
+	manager.Initialize(consenters)
+
+	normalTx := makeNormalTx("testchannelid", 1)
+	configUpdateTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, "testchannelid", mockCrypto(), &cb.ConfigUpdateEnvelope{}, msgVersion, epoch)
+	require.NoError(t, err)
+
+	// The ledger is measured on startup, over its soft limit only
+	require.Equal(t, 2, fakeGauge.WithCallCount())
+	assert.Equal(t, []string{"channel", "testchannelid"}, fakeGauge.WithArgsForCall(0))
+	assert.Equal(t, float64(size), fakeGauge.SetArgsForCall(0))
+	assert.Equal(t, float64(quotaSoftExceeded), fakeGauge.SetArgsForCall(1))
+	_, _, _, err = manager.BroadcastChannelSupport(normalTx)
+	assert.NoError(t, err)
+
+	// Over the hard limit, normal transactions are rejected but config updates are not
+	manager.updateStorageQuota("testchannelid", size+101)
+	assert.Equal(t, float64(quotaHardExceeded), fakeGauge.SetArgsForCall(3))
+	chdr, _, cs, err := manager.BroadcastChannelSupport(normalTx)
+	assert.Nil(t, cs)
+	assert.Equal(t, "testchannelid", chdr.ChannelId)
+	assert.Equal(t, msgprocessor.ErrStorageQuotaExceeded, errors.Cause(err))
+	assert.EqualError(t, err, fmt.Sprintf("channel testchannelid uses %d bytes, over its hard limit of %d bytes: channel storage quota exceeded", size+101, size+100))
+
+	_, isConfig, cs, err := manager.BroadcastChannelSupport(configUpdateTx)
+	assert.NoError(t, err)
+	assert.True(t, isConfig)
+	assert.NotNil(t, cs)
+
+	// Transactions are accepted again once the ledger is measured within its limits
+	manager.checkStorageQuotas()
+	assert.Equal(t, float64(quotaSoftExceeded), fakeGauge.SetArgsForCall(5))
+	_, _, _, err = manager.BroadcastChannelSupport(normalTx)
+	assert.NoError(t, err)
+
+	manager.updateStorageQuota("testchannelid", 10)
+	assert.Equal(t, float64(quotaOK), fakeGauge.SetArgsForCall(7))
+}
+
+func TestStorageQuotaLimits(t *testing.T) {
+	conf := localconfig.StorageQuota{
+		SoftLimit: 10,
+		HardLimit: 20,
+		Channels: map[string]localconfig.StorageQuotaLimits{
+			"mychannel": {HardLimit: 5},
+		},
+	}
+	assert.Equal(t, localconfig.StorageQuotaLimits{SoftLimit: 10, HardLimit: 20}, conf.Limits("testchannelid"))
+	assert.Equal(t, localconfig.StorageQuotaLimits{HardLimit: 5}, conf.Limits("mychannel"))
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/orderer/common/audit"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
@@ -99,6 +100,15 @@ type Registrar struct {
 	lock   sync.RWMutex
 	chains map[string]*ChainSupport
 
+	hibernated   map[string]*hibernatedChannel
+	activityLock sync.Mutex
+	activity     map[string]*channelActivity
+	// hibernationLock serializes the hibernations and reactivations of the channels
+	hibernationLock sync.Mutex
+
+	quotas       storageQuotas
+	quotaMetrics *QuotaMetrics
+
 	consenters         map[string]consensus.Consenter
 	ledgerFactory      blockledger.Factory
 	signer             identity.SignerSerializer
@@ -108,6 +118,7 @@ type Registrar struct {
 	templator          msgprocessor.ChannelConfigTemplator
 	callbacks          []channelconfig.BundleActor
 	bccsp              bccsp.BCCSP
+	auditLog           *audit.Log
 }
 
 // ConfigBlock retrieves the last configuration block from the given ledger.
@@ -144,9 +155,13 @@ func NewRegistrar(
 	r := &Registrar{
 		config:             config,
 		chains:             make(map[string]*ChainSupport),
+		hibernated:         make(map[string]*hibernatedChannel),
+		activity:           make(map[string]*channelActivity),
 		ledgerFactory:      ledgerFactory,
 		signer:             signer,
 		blockcutterMetrics: blockcutter.NewMetrics(metricsProvider),
+		quotas:             storageQuotas{channels: make(map[string]*channelQuota)},
+		quotaMetrics:       NewQuotaMetrics(metricsProvider),
 		callbacks:          callbacks,
 		bccsp:              bccsp,
 	}
@@ -155,6 +170,14 @@ func NewRegistrar(
 }
 
 func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
+	if r.config.Audit.Enabled {
+		auditLog, err := audit.NewLog(r.config.Audit.Location)
+		if err != nil {
+			logger.Panicf("Failed opening the audit log: %s", err)
+		}
+		r.auditLog = auditLog
+	}
+
 	r.consenters = consenters
 	existingChannels := r.ledgerFactory.ChannelIDs()
 
@@ -239,6 +262,14 @@ func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 			logger.Panicf("Error initializing without a system channel: failed to find an etcdraft consenter")
 		}
 	}
+
+	if r.config.ChannelHibernation.Enabled {
+		r.startHibernation()
+	}
+
+	if r.config.StorageQuota.Enabled {
+		r.startStorageQuotas()
+	}
 }
 
 // SystemChannelID returns the ChannelID for the system channel.
@@ -272,6 +303,8 @@ func (r *Registrar) BroadcastChannelSupport(msg *cb.Envelope) (*cb.ChannelHeader
 			return nil, false, nil, errors.New("channel creation request not allowed because the orderer system channel is not defined")
 		}
 		cs = sysChan
+	} else if r.config.ChannelHibernation.Enabled {
+		r.channelActivity(chdr.ChannelId).touch()
 	}
 
 	isConfig := false
@@ -281,16 +314,35 @@ func (r *Registrar) BroadcastChannelSupport(msg *cb.Envelope) (*cb.ChannelHeader
 	case msgprocessor.ConfigMsg:
 		return chdr, false, nil, errors.New("message is of type that cannot be processed directly")
 	default:
+		// Config updates are still accepted, so that the channel can be reconfigured
+		if err := r.checkStorageQuota(chdr.ChannelId); err != nil {
+			return chdr, false, nil, err
+		}
 	}
 
 	return chdr, isConfig, cs, nil
 }
 
-// GetChain retrieves the chain support for a chain if it exists.
+// GetChain retrieves the chain support for a chain if it exists, reactivating
+// the chain if it is hibernated.
 func (r *Registrar) GetChain(chainID string) *ChainSupport {
 	r.lock.RLock()
-	defer r.lock.RUnlock()
+	cs, hibernated := r.chains[chainID], r.hibernated[chainID] != nil
+	r.lock.RUnlock()
 
+	if hibernated {
+		return r.reactivate(chainID)
+	}
+	return cs
+}
+
+// GetActiveChain retrieves the chain support for a chain if it exists and is
+// not hibernated. Unlike GetChain, it never reactivates a hibernated chain, so
+// that the cluster and the operations of the orderer do not keep the chains
+// from hibernating.
+func (r *Registrar) GetActiveChain(chainID string) *ChainSupport {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
 	return r.chains[chainID]
 }
 
@@ -329,6 +381,10 @@ func (r *Registrar) newLedgerResources(configTx *cb.Envelope) (*ledgerResources,
 		return nil, errors.Wrapf(err, "error getting ledger for channel: %s", chdr.ChannelId)
 	}
 
+	if r.config.ChannelHibernation.Enabled {
+		ledger = &activityLedger{ReadWriter: ledger, activity: r.channelActivity(chdr.ChannelId)}
+	}
+
 	return &ledgerResources{
 		configResources: &configResources{
 			mutableResources: channelconfig.NewBundleSource(bundle, r.callbacks...),
@@ -344,7 +400,7 @@ func (r *Registrar) CreateChain(chainName string) {
 	if err != nil {
 		logger.Panicf("Failed obtaining ledger factory for %s: %v", chainName, err)
 	}
-	chain := r.GetChain(chainName)
+	chain := r.GetActiveChain(chainName)
 	if chain != nil {
 		logger.Infof("A chain of type %T for channel %s already exists. "+
 			"Halting it.", chain.Chain, chainName)
@@ -391,6 +447,7 @@ func (r *Registrar) newChain(configtx *cb.Envelope) {
 	}
 
 	chainID := ledgerResources.ConfigtxValidator().ChannelID()
+	delete(r.hibernated, chainID)
 	r.chains[chainID] = cs
 
 	logger.Infof("Created and starting new channel %s", chainID)
@@ -402,7 +459,7 @@ func (r *Registrar) ChannelsCount() int {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
-	return len(r.chains)
+	return len(r.chains) + len(r.hibernated)
 }
 
 // NewChannelConfig produces a new template channel configuration based on the system channel's current config.
@@ -424,7 +481,7 @@ func (r *Registrar) ChannelList() types.ChannelList {
 
 	list := types.ChannelList{}
 
-	if len(r.chains) == 0 {
+	if len(r.chains)+len(r.hibernated) == 0 {
 		return list
 	}
 
@@ -437,6 +494,9 @@ func (r *Registrar) ChannelList() types.ChannelList {
 		}
 		list.Channels = append(list.Channels, types.ChannelInfoShort{Name: name})
 	}
+	for name := range r.hibernated {
+		list.Channels = append(list.Channels, types.ChannelInfoShort{Name: name})
+	}
 
 	return list
 }
@@ -446,6 +506,13 @@ func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
 	defer r.lock.RUnlock()
 
 	info := types.ChannelInfo{}
+	if hc, ok := r.hibernated[channelID]; ok {
+		info.Name = channelID
+		info.Height = hc.height
+		info.ClusterRelation, info.Status = hc.clusterRelation, types.StatusHibernated
+		return info, nil
+	}
+
 	cs, ok := r.chains[channelID]
 	if !ok {
 		return info, types.ErrChannelNotExist
@@ -458,6 +525,56 @@ func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
 	return info, nil
 }
 
+// QuorumStatus returns the consensus quorum status of every channel this orderer participates in
+// the consensus of. Channels on which the orderer only tracks the config or follows the blocks are
+// not reported, as their quorum does not depend on this orderer.
+func (r *Registrar) QuorumStatus() map[string]consensus.QuorumStatus {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	statuses := make(map[string]consensus.QuorumStatus)
+	for name, cs := range r.chains {
+		if relation, _ := cs.StatusReport(); relation != types.ClusterRelationMember && relation != types.ClusterRelationNone {
+			continue
+		}
+		statuses[name] = cs.QuorumStatus()
+	}
+
+	return statuses
+}
+
+// LastConfigBlock returns the last config block of a channel, read from its ledger. Hibernated
+// channels are not reactivated.
+func (r *Registrar) LastConfigBlock(channelID string) (*cb.Block, error) {
+	exists := false
+	for _, id := range r.ledgerFactory.ChannelIDs() {
+		if id == channelID {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return nil, types.ErrChannelNotExist
+	}
+
+	reader, err := r.ledgerFactory.GetOrCreate(channelID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to open the ledger of channel %s", channelID)
+	}
+	if reader.Height() == 0 {
+		return nil, errors.Errorf("ledger of channel %s is empty", channelID)
+	}
+	lastBlock, err := blockledger.GetBlockByNumber(reader, reader.Height()-1)
+	if err != nil {
+		return nil, err
+	}
+	index, err := protoutil.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	return blockledger.GetBlockByNumber(reader, index)
+}
+
 func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error) {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -467,11 +584,11 @@ func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppCh
 	}
 
 	_, ok := r.chains[channelID]
-	if ok {
+	if ok || r.hibernated[channelID] != nil {
 		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
 	}
 
-	if !isAppChannel && len(r.chains) > 0 {
+	if !isAppChannel && len(r.chains)+len(r.hibernated) > 0 {
 		return types.ChannelInfo{}, types.ErrAppChannelsAlreadyExists
 	}
 
@@ -506,6 +623,13 @@ func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppCh
 	r.chains[channelID] = joinSupport
 	joinSupport.start()
 
+	r.recordAudit(audit.Record{
+		Type:        audit.ChannelJoin,
+		ChannelID:   channelID,
+		BlockNumber: configBlock.Header.Number,
+		Details:     fmt.Sprintf("joined with config block %d", configBlock.Header.Number),
+	})
+
 	return info, nil
 }
 
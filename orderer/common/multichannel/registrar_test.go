@@ -243,6 +243,7 @@ func TestNewRegistrar(t *testing.T) {
 			types.ChannelInfo{Name: "testchannelid", URL: "", ClusterRelation: "none", Status: "active", Height: 1},
 			info,
 		)
+		assert.Equal(t, map[string]consensus.QuorumStatus{"testchannelid": {HasQuorum: true}}, manager.QuorumStatus())
 
 		testMessageOrderAndRetrieval(confSys.Orderer.BatchSize.MaxMessageCount, "testchannelid", chainSupport, rl, t)
 	})
@@ -305,6 +306,9 @@ func TestCreateChain(t *testing.T) {
 			info,
 		)
 
+		noQuorum := consensus.QuorumStatus{Leader: 2, ActiveNodes: 1, TotalNodes: 3, Reason: "only 1 out of 3 nodes are reachable"}
+		assert.Equal(t, map[string]consensus.QuorumStatus{"testchannelid": noQuorum, "mychannel": noQuorum}, manager.QuorumStatus())
+
 		// A subsequent creation, replaces the chain.
 		manager.CreateChain("mychannel")
 		chain2 := manager.GetChain("mychannel")
@@ -789,3 +793,32 @@ func (c *mockRaftChain) Halt() {
 func (c *mockRaftChain) IsRaft() bool {
 	return true
 }
+
+func TestRegistrar_LastConfigBlock(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "file-ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	configBlock := protoutil.NewBlock(0, nil)
+	configBlock.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value: protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{
+			LastConfig: &cb.LastConfig{Index: 0},
+		}),
+	})
+	lf, rl := newLedgerAndFactory(tmpdir, "testchannelid", configBlock)
+	block := protoutil.NewBlock(1, protoutil.BlockHeaderHash(configBlock.Header))
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = configBlock.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES]
+	require.NoError(t, rl.Append(block))
+
+	registrar := NewRegistrar(localconfig.TopLevel{}, lf, mockCrypto(), &disabled.Provider{}, cryptoProvider)
+
+	lastConfigBlock, err := registrar.LastConfigBlock("testchannelid")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), lastConfigBlock.Header.Number)
+
+	_, err = registrar.LastConfigBlock("otherchannel")
+	require.Equal(t, types.ErrChannelNotExist, err)
+}
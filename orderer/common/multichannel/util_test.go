@@ -59,6 +59,10 @@ func (c *mockChainCluster) StatusReport() (types.ClusterRelation, types.Status)
 	return types.ClusterRelationMember, types.StatusActive
 }
 
+func (c *mockChainCluster) QuorumStatus() consensus.QuorumStatus {
+	return consensus.QuorumStatus{Leader: 2, ActiveNodes: 1, TotalNodes: 3, Reason: "only 1 out of 3 nodes are reachable"}
+}
+
 type mockChain struct {
 	queue    chan *cb.Envelope
 	cutter   blockcutter.Receiver
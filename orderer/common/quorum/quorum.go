@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quorum
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/pkg/errors"
+)
+
+// URL is the path of the operations endpoint reporting the quorum status of the channels.
+const URL = "/quorum"
+
+var logger = flogging.MustGetLogger("orderer.common.quorum")
+
+//go:generate counterfeiter -o mock/quorum_reporter.go -fake-name QuorumReporter . QuorumReporter
+
+// QuorumReporter provides the quorum status of the channels of the orderer.
+type QuorumReporter interface {
+	// QuorumStatus returns the consensus quorum status of every channel the orderer participates in
+	// the consensus of, indexed by channel name.
+	QuorumStatus() map[string]consensus.QuorumStatus
+}
+
+// Checker reports whether the orderer has consensus quorum on all of its channels, so that
+// orderers unable to order transactions can be removed from load balancer rotation. It is
+// both a health checker and an HTTP handler.
+type Checker struct {
+	Reporter QuorumReporter
+}
+
+// HealthCheck returns an error listing the channels without quorum, if any.
+func (c *Checker) HealthCheck(context.Context) error {
+	var failed []string
+	for channel, status := range c.Reporter.QuorumStatus() {
+		if !status.HasQuorum {
+			failed = append(failed, channel+": "+status.Reason)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	sort.Strings(failed)
+	return errors.Errorf("no consensus quorum on channels [%s]", strings.Join(failed, ", "))
+}
+
+// ServeHTTP writes the quorum status of each channel as JSON. The response status is
+// 200 when all channels have quorum and 503 otherwise.
+func (c *Checker) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		resp.Header().Set("Allow", http.MethodGet)
+		resp.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses := c.Reporter.QuorumStatus()
+	code := http.StatusOK
+	for _, status := range statuses {
+		if !status.HasQuorum {
+			code = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(statuses); err != nil {
+		logger.Errorf("failed to encode quorum status, err: %s", err)
+	}
+}
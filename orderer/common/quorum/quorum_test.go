@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quorum_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/common/quorum"
+	"github.com/hyperledger/fabric/orderer/common/quorum/mock"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck(t *testing.T) {
+	reporter := &mock.QuorumReporter{}
+	checker := &quorum.Checker{Reporter: reporter}
+
+	reporter.QuorumStatusReturns(map[string]consensus.QuorumStatus{})
+	assert.NoError(t, checker.HealthCheck(context.Background()))
+
+	reporter.QuorumStatusReturns(map[string]consensus.QuorumStatus{
+		"ch1": {HasQuorum: true, Leader: 1, ActiveNodes: 3, TotalNodes: 3},
+		"ch2": {Leader: 1, ActiveNodes: 1, TotalNodes: 3, Reason: "only 1 out of 3 nodes are reachable"},
+		"ch3": {TotalNodes: 3, Reason: "no leader is known"},
+	})
+	assert.EqualError(t, checker.HealthCheck(context.Background()),
+		"no consensus quorum on channels [ch2: only 1 out of 3 nodes are reachable, ch3: no leader is known]")
+}
+
+func TestServeHTTP(t *testing.T) {
+	reporter := &mock.QuorumReporter{}
+	checker := &quorum.Checker{Reporter: reporter}
+
+	healthy := map[string]consensus.QuorumStatus{
+		"ch1": {HasQuorum: true, Leader: 1, ActiveNodes: 2, TotalNodes: 3},
+	}
+	reporter.QuorumStatusReturns(healthy)
+	resp := httptest.NewRecorder()
+	checker.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, quorum.URL, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"ch1":{"hasQuorum":true,"leader":1,"activeNodes":2,"totalNodes":3}}`, resp.Body.String())
+
+	unhealthy := map[string]consensus.QuorumStatus{
+		"ch1": {HasQuorum: true, Leader: 1, ActiveNodes: 2, TotalNodes: 3},
+		"ch2": {TotalNodes: 3, Reason: "no leader is known"},
+	}
+	reporter.QuorumStatusReturns(unhealthy)
+	resp = httptest.NewRecorder()
+	checker.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, quorum.URL, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	statuses := map[string]consensus.QuorumStatus{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &statuses))
+	assert.Equal(t, unhealthy, statuses)
+
+	resp = httptest.NewRecorder()
+	checker.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, quorum.URL, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, http.MethodGet, resp.Header().Get("Allow"))
+}
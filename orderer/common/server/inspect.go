@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// inspectConsensusMetadata prints the consensus metadata configured by the config block read
+// from configBlockPath, e.g. fetched with "peer channel fetch config", as JSON. When
+// tlsCertPath is given, the report tells whether the certificate belongs to the consenter set.
+func inspectConsensusMetadata(configBlockPath, tlsCertPath string, cryptoProvider bccsp.BCCSP, out io.Writer) error {
+	data, err := ioutil.ReadFile(configBlockPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the config block")
+	}
+	block, err := protoutil.UnmarshalBlock(data)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to unmarshal the config block %s", configBlockPath)
+	}
+
+	var tlsCert []byte
+	if tlsCertPath != "" {
+		if tlsCert, err = ioutil.ReadFile(tlsCertPath); err != nil {
+			return errors.Wrap(err, "failed to read the TLS certificate")
+		}
+	}
+
+	report, err := etcdraft.InspectConsensusMetadata(block, tlsCert, cryptoProvider)
+	if err != nil {
+		return err
+	}
+	output, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the consensus metadata")
+	}
+	_, err = fmt.Fprintln(out, string(output))
+	return err
+}
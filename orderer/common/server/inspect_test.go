@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectConsensusMetadata(t *testing.T) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	configBlock := filepath.Join("..", "..", "consensus", "etcdraft", "testdata", "etcdraftgenesis.block")
+
+	out := &bytes.Buffer{}
+	err = inspectConsensusMetadata(configBlock, "", cryptoProvider, out)
+	require.NoError(t, err)
+	report := &etcdraft.ConsensusMetadataReport{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), report))
+	require.Equal(t, "testchannel", report.ChannelID)
+	require.Equal(t, uint64(1), report.ConfigSequence)
+	require.Equal(t, uint64(2), report.NextConfigSequence)
+	require.Equal(t, "etcdraft", report.ConsensusType)
+	require.Equal(t, uint32(10), report.Options.ElectionTick)
+	require.Equal(t, uint64(4), report.NextConsenterID)
+	require.Nil(t, report.InConsenterSet)
+	require.Len(t, report.Consenters, 3)
+	for i, consenter := range report.Consenters {
+		require.Equal(t, uint64(i+1), consenter.ID)
+		require.True(t, consenter.ClientTLSCert.Verified)
+		require.True(t, consenter.ServerTLSCert.Verified)
+		require.Equal(t, "CN=tlsca.example.com,O=example.com,L=San Francisco,ST=California,C=US", consenter.ServerTLSCert.Issuer)
+	}
+
+	tempDir, err := ioutil.TempDir("", "inspect-consensus")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	kp, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+	certPath := filepath.Join(tempDir, "cert.pem")
+	require.NoError(t, ioutil.WriteFile(certPath, kp.Cert, 0600))
+
+	out.Reset()
+	err = inspectConsensusMetadata(configBlock, certPath, cryptoProvider, out)
+	require.NoError(t, err)
+	report = &etcdraft.ConsensusMetadataReport{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), report))
+	require.NotNil(t, report.InConsenterSet)
+	require.False(t, *report.InConsenterSet)
+
+	err = inspectConsensusMetadata(filepath.Join(tempDir, "missing.block"), "", cryptoProvider, out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read the config block")
+
+	err = inspectConsensusMetadata(certPath, "", cryptoProvider, out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to unmarshal the config block")
+
+	err = inspectConsensusMetadata(filepath.Join("..", "..", "consensus", "etcdraft", "testdata", "mychannel.block"), "", cryptoProvider, out)
+	require.EqualError(t, err, "consensus type of the channel is kafka, not etcdraft")
+}
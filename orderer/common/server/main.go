@@ -20,9 +20,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/cetcxinlian/cryptogm/tls"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-lib-go/healthz"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
@@ -35,16 +37,25 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/enrollment"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/internal/pkg/mspreload"
+	"github.com/hyperledger/fabric/internal/pkg/remotesigner"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/orderer/common/audit"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/consensusmetadata"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
+	"github.com/hyperledger/fabric/orderer/common/mirror"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/common/onboarding"
+	"github.com/hyperledger/fabric/orderer/common/quorum"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/kafka"
@@ -64,6 +75,10 @@ var (
 	_       = app.Command("start", "Start the orderer node").Default() // preserved for cli compatibility
 	version = app.Command("version", "Show version information")
 
+	inspectConsensus     = app.Command("inspect-consensus", "Decode the consensus metadata of a channel from a config block")
+	inspectConfigBlock   = inspectConsensus.Flag("config-block", "Path to the config block of the channel").Required().String()
+	inspectConsenterCert = inspectConsensus.Flag("tls-cert", "Path to the cluster TLS certificate to look up in the consenter set").String()
+
 	clusterTypes = map[string]struct{}{"etcdraft": {}}
 )
 
@@ -77,6 +92,15 @@ func Main() {
 		return
 	}
 
+	// "inspect-consensus" command
+	if fullCmd == inspectConsensus.FullCommand() {
+		if err := inspectConsensusMetadata(*inspectConfigBlock, *inspectConsenterCert, factory.GetDefault(), os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	conf, err := localconfig.Load()
 	if err != nil {
 		logger.Error("failed to parse config: ", err)
@@ -88,9 +112,24 @@ func Main() {
 
 	cryptoProvider := factory.GetDefault()
 
-	signer, signErr := loadLocalMSP(conf).GetDefaultSigningIdentity()
-	if signErr != nil {
-		logger.Panicf("Failed to get local MSP identity: %s", signErr)
+	var enroller *enrollment.Enroller
+	if conf.General.Enrollment.Enabled {
+		enroller = newEnroller(conf)
+		if _, err := enroller.Enroll(); err != nil {
+			logger.Panicf("Failed to enroll orderer identities: %v", err)
+		}
+	}
+
+	localMSP := loadLocalMSP(conf)
+	var signer msp.SigningIdentity
+	if conf.General.RemoteSigner.Enabled {
+		signer = newRemoteSigner(conf, localMSP)
+	} else {
+		var signErr error
+		signer, signErr = localMSP.GetDefaultSigningIdentity()
+		if signErr != nil {
+			logger.Panicf("Failed to get local MSP identity: %s", signErr)
+		}
 	}
 
 	opsSystem := newOperationsSystem(conf.Operations, conf.Metrics)
@@ -100,6 +139,9 @@ func Main() {
 
 	serverConfig := initializeServerConfig(conf, metricsProvider)
 	grpcServer := initializeGrpcServer(conf, serverConfig)
+	if enroller != nil {
+		go enroller.Run(nil, enrollmentReloaders(conf, localMSP, grpcServer))
+	}
 	caMgr := &caManager{
 		appRootCAsByChain:     make(map[string][][]byte),
 		ordererRootCAsByChain: make(map[string][][]byte),
@@ -237,11 +279,58 @@ func Main() {
 		tlsCallback,
 	)
 
+	quorumChecker := &quorum.Checker{Reporter: manager}
+	if err = opsSystem.RegisterChecker("consensus.quorum", quorumChecker); err != nil {
+		logger.Panicf("failed to register consensus quorum health check: %s", err)
+	}
+	opsSystem.RegisterHandler(quorum.URL, quorumChecker)
+	opsSystem.RegisterHandler(consensusmetadata.URL, &consensusmetadata.Handler{
+		Blocks: manager,
+		Inspect: func(configBlock *cb.Block) (*etcdraft.ConsensusMetadataReport, error) {
+			return etcdraft.InspectConsensusMetadata(configBlock, clusterClientConfig.SecOpts.Certificate, cryptoProvider)
+		},
+	})
+	opsSystem.RegisterAdminHandler("/msp/reload", mspreload.NewHandler(func() error {
+		return reloadLocalMSP(conf, localMSP)
+	}))
+	if auditLog := manager.AuditLog(); auditLog != nil {
+		opsSystem.RegisterHandler(audit.URL, &audit.Handler{Log: auditLog})
+	}
+	mirrorHandler, mirrorServer := initializeMirror(conf, manager, cryptoProvider, metricsProvider)
+	if mirrorHandler != nil {
+		defer func() {
+			if mirrorServer != nil {
+				mirrorServer.Stop()
+			}
+			mirrorHandler.Close()
+		}()
+		opsSystem.RegisterHandler(mirror.URL, mirrorHandler)
+		opsSystem.RegisterAdminHandler(mirror.PromoteURL, mirrorHandler)
+	}
+
 	if err = opsSystem.Start(); err != nil {
 		logger.Panicf("failed to start operations subsystem: %s", err)
 	}
 	defer opsSystem.Stop()
 
+	var tracer *tracing.Tracer
+	if conf.Tracing.Enabled {
+		exporter := tracing.NewOTLPExporter(tracing.OTLPConfig{
+			Endpoint:      conf.Tracing.Endpoint,
+			ServiceName:   "orderer",
+			InstanceID:    net.JoinHostPort(conf.General.ListenAddress, fmt.Sprintf("%d", conf.General.ListenPort)),
+			FlushInterval: conf.Tracing.FlushInterval,
+		})
+		defer exporter.Stop()
+		tracer = tracing.NewTracer(exporter)
+		logger.Infof("Exporting the spans of the transactions to %s", conf.Tracing.Endpoint)
+	}
+
+	if conf.General.IdempotencyKeys.Enabled {
+		logger.Infof("Deduplicating transactions by idempotency key against the last %d blocks of each channel",
+			conf.General.IdempotencyKeys.LookbackBlocks)
+	}
+
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
 	server := NewServer(
 		manager,
@@ -250,6 +339,8 @@ func Main() {
 		conf.General.Authentication.TimeWindow,
 		mutualTLS,
 		conf.General.Authentication.NoExpirationChecks,
+		tracer,
+		conf.General.IdempotencyKeys.ResponseTimeout,
 	)
 
 	logger.Infof("Starting %s", metadata.GetVersionInfo())
@@ -260,6 +351,11 @@ func Main() {
 				clusterGRPCServer.Stop()
 			}
 		},
+		syscall.SIGHUP: func() {
+			if err := reloadLocalMSP(conf, localMSP); err != nil {
+				logger.Errorf("Failed reloading the local MSP: %+v", err)
+			}
+		},
 	}))
 
 	if !reuseGrpcListener && isClusterType {
@@ -493,6 +589,141 @@ func initializeClusterClientConfig(conf *localconfig.TopLevel) comm.ClientConfig
 	return cc
 }
 
+// initializeMirror starts mirroring the ledgers of the channels to the
+// standby orderers and receiving the ledgers mirrored to this orderer, as
+// configured. It returns the handler of the mirroring operations endpoints,
+// or nil if neither is enabled, and the server receiving the mirrored ledgers
+// if this orderer is a standby orderer.
+func initializeMirror(conf *localconfig.TopLevel, registrar *multichannel.Registrar, bccsp bccsp.BCCSP, metricsProvider metrics.Provider) (*mirror.Handler, *comm.GRPCServer) {
+	mc := conf.Mirror
+	if !mc.Enabled && !mc.Standby.Enabled {
+		return nil, nil
+	}
+	mirrorMetrics := mirror.NewMetrics(metricsProvider)
+	handler := &mirror.Handler{}
+
+	var srv *comm.GRPCServer
+
+	if mc.Standby.Enabled {
+		verifiers := &cluster.BlockVerifierAssembler{Logger: logger, BCCSP: bccsp}
+		standby, err := mirror.NewStandby(mc.Standby.Location, mc.Standby.GenesisBlocks, verifiers, mirrorMetrics)
+		if err != nil {
+			logger.Panicf("Failed creating the standby orderer: %v", err)
+		}
+		srv = initializeMirrorServer(conf)
+		mirror.RegisterMirrorServer(srv.Server(), standby)
+		logger.Infof("Receiving the mirrored ledgers on %s", srv.Address())
+		go func() {
+			if err := srv.Start(); err != nil {
+				logger.Errorf("Mirror gRPC server has terminated: %v", err)
+			}
+		}()
+		handler.Standby = standby
+	}
+
+	if mc.Enabled {
+		client, err := comm.NewGRPCClient(initializeMirrorClientConfig(conf))
+		if err != nil {
+			logger.Panicf("Failed creating the mirror client: %v", err)
+		}
+		readers := mirror.ReadersFunc(func(channelID string) blockledger.Reader {
+			cs := registrar.GetActiveChain(channelID)
+			if cs == nil {
+				return nil
+			}
+			return cs.Reader()
+		})
+		dialer := mirror.DialerFunc(func(address string) (*grpc.ClientConn, error) {
+			return client.NewConnection(address)
+		})
+		handler.Mirror = mirror.New(mc.Channels, mc.Standbys, readers, dialer, mc.RetryInterval, mirrorMetrics)
+		handler.Mirror.Start()
+		logger.Infof("Mirroring channels %v to %v", mc.Channels, mc.Standbys)
+	}
+
+	return handler, srv
+}
+
+func initializeMirrorServer(conf *localconfig.TopLevel) *comm.GRPCServer {
+	sc := conf.Mirror.Standby
+	cert, err := ioutil.ReadFile(sc.ServerCertificate)
+	if err != nil {
+		logger.Panicf("Failed to load mirror server certificate from '%s' (%s)", sc.ServerCertificate, err)
+	}
+	key, err := ioutil.ReadFile(sc.ServerPrivateKey)
+	if err != nil {
+		logger.Panicf("Failed to load mirror server key from '%s' (%s)", sc.ServerPrivateKey, err)
+	}
+	var clientRootCAs [][]byte
+	for _, clientRoot := range sc.ClientRootCAs {
+		rootCACert, err := ioutil.ReadFile(clientRoot)
+		if err != nil {
+			logger.Panicf("Failed to load CA cert file '%s' (%s)", clientRoot, err)
+		}
+		clientRootCAs = append(clientRootCAs, rootCACert)
+	}
+
+	bindAddr := net.JoinHostPort(sc.ListenAddress, fmt.Sprintf("%d", sc.ListenPort))
+	srv, err := comm.NewGRPCServer(bindAddr, comm.ServerConfig{
+		ConnectionTimeout: conf.General.Cluster.DialTimeout,
+		MaxRecvMsgSize:    int(conf.General.MaxRecvMsgSize),
+		MaxSendMsgSize:    int(conf.General.MaxSendMsgSize),
+		KaOpts:            comm.DefaultKeepaliveOptions,
+		SecOpts: comm.SecureOptions{
+			CipherSuites:      comm.DefaultTLSCipherSuites,
+			ClientRootCAs:     clientRootCAs,
+			RequireClientCert: true,
+			Certificate:       cert,
+			Key:               key,
+			UseTLS:            true,
+		},
+	})
+	if err != nil {
+		logger.Panicf("Failed creating mirror gRPC server on %s due to %v", bindAddr, err)
+	}
+	return srv
+}
+
+func initializeMirrorClientConfig(conf *localconfig.TopLevel) comm.ClientConfig {
+	mc := conf.Mirror
+	certFile, keyFile := mc.ClientCertificate, mc.ClientPrivateKey
+	if certFile == "" && keyFile == "" {
+		certFile = conf.General.TLS.Certificate
+		keyFile = conf.General.TLS.PrivateKey
+	}
+	certBytes, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		logger.Panicf("Failed to load mirror client TLS certificate file '%s' (%s)", certFile, err)
+	}
+	keyBytes, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		logger.Panicf("Failed to load mirror client TLS key file '%s' (%s)", keyFile, err)
+	}
+	var serverRootCAs [][]byte
+	for _, serverRoot := range mc.RootCAs {
+		rootCACert, err := ioutil.ReadFile(serverRoot)
+		if err != nil {
+			logger.Panicf("Failed to load CA cert file '%s' (%s)", serverRoot, err)
+		}
+		serverRootCAs = append(serverRootCAs, rootCACert)
+	}
+
+	return comm.ClientConfig{
+		KaOpts:         comm.DefaultKeepaliveOptions,
+		Timeout:        mc.DialTimeout,
+		MaxRecvMsgSize: int(conf.General.MaxRecvMsgSize),
+		MaxSendMsgSize: int(conf.General.MaxSendMsgSize),
+		SecOpts: comm.SecureOptions{
+			RequireClientCert: true,
+			CipherSuites:      comm.DefaultTLSCipherSuites,
+			ServerRootCAs:     serverRootCAs,
+			Certificate:       certBytes,
+			Key:               keyBytes,
+			UseTLS:            true,
+		},
+	}
+}
+
 func initializeServerConfig(conf *localconfig.TopLevel, metricsProvider metrics.Provider) comm.ServerConfig {
 	// secure server config
 	secureOpts := comm.SecureOptions{
@@ -642,7 +873,7 @@ func consensusType(genesisBlock *cb.Block, bccsp bccsp.BCCSP) string {
 }
 
 func initializeGrpcServer(conf *localconfig.TopLevel, serverConfig comm.ServerConfig) *comm.GRPCServer {
-	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort))
+	lis, err := net.Listen("tcp", net.JoinHostPort(conf.General.ListenAddress, fmt.Sprintf("%d", conf.General.ListenPort)))
 	if err != nil {
 		logger.Fatal("Failed to listen:", err)
 	}
@@ -656,10 +887,10 @@ func initializeGrpcServer(conf *localconfig.TopLevel, serverConfig comm.ServerCo
 	return grpcServer
 }
 
-func loadLocalMSP(conf *localconfig.TopLevel) msp.MSP {
+func loadLocalMSP(conf *localconfig.TopLevel) *mgmt.ReloadableMSP {
 	// MUST call GetLocalMspConfig first, so that default BCCSP is properly
 	// initialized prior to LoadByType.
-	mspConfig, err := msp.GetLocalMspConfig(conf.General.LocalMSPDir, conf.General.BCCSP, conf.General.LocalMSPID)
+	mspConfig, _, err := localMSPConfig(conf)
 	if err != nil {
 		logger.Panicf("Failed to get local msp config: %v", err)
 	}
@@ -670,7 +901,9 @@ func loadLocalMSP(conf *localconfig.TopLevel) msp.MSP {
 		logger.Panicf("MSP option for type %s is not found", typ)
 	}
 
-	localmsp, err := msp.New(opts, factory.GetDefault())
+	localmsp, err := mgmt.NewReloadableMSP(func() (msp.MSP, error) {
+		return msp.New(opts, factory.GetDefault())
+	})
 	if err != nil {
 		logger.Panicf("Failed to load local MSP: %v", err)
 	}
@@ -682,6 +915,125 @@ func loadLocalMSP(conf *localconfig.TopLevel) msp.MSP {
 	return localmsp
 }
 
+// reloadLocalMSP reloads the local MSP from its directory, so that new admin
+// certificates, updated CRLs and rotated TLS CAs take effect without a restart.
+func reloadLocalMSP(conf *localconfig.TopLevel, localMSP *mgmt.ReloadableMSP) error {
+	mspConfig, _, err := localMSPConfig(conf)
+	if err != nil {
+		return err
+	}
+	return localMSP.Reload(mspConfig)
+}
+
+// localMSPConfig returns the configuration of the local MSP. When the orderer
+// signs with a remote signing service, the signing identity is left out of the
+// configuration and its certificate is returned separately.
+func localMSPConfig(conf *localconfig.TopLevel) (*mspproto.MSPConfig, []byte, error) {
+	mspConfig, err := msp.GetLocalMspConfig(conf.General.LocalMSPDir, conf.General.BCCSP, conf.General.LocalMSPID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !conf.General.RemoteSigner.Enabled {
+		return mspConfig, nil, nil
+	}
+	return remotesigner.PublicMSPConfig(mspConfig)
+}
+
+// newRemoteSigner connects to the remote signing service and returns the
+// signing identity of the local MSP backed by it.
+func newRemoteSigner(conf *localconfig.TopLevel, localMSP msp.MSP) msp.SigningIdentity {
+	rc := conf.General.RemoteSigner
+	_, signCert, err := localMSPConfig(conf)
+	if err != nil {
+		logger.Panicf("Failed to get local msp config: %v", err)
+	}
+	id, err := remotesigner.PublicIdentity(localMSP, signCert)
+	if err != nil {
+		logger.Panicf("Failed to get local MSP identity: %v", err)
+	}
+
+	clientConfig := comm.ClientConfig{
+		Timeout: rc.Timeout,
+		KaOpts:  comm.DefaultKeepaliveOptions,
+	}
+	if len(rc.RootCAs) > 0 {
+		clientConfig.SecOpts.UseTLS = true
+		for _, rootCA := range rc.RootCAs {
+			pem, err := ioutil.ReadFile(rootCA)
+			if err != nil {
+				logger.Panicf("Failed to read the TLS root certificate of the remote signer: %v", err)
+			}
+			clientConfig.SecOpts.ServerRootCAs = append(clientConfig.SecOpts.ServerRootCAs, pem)
+		}
+	}
+	if rc.ClientCertificate != "" {
+		clientConfig.SecOpts.RequireClientCert = true
+		if clientConfig.SecOpts.Certificate, err = ioutil.ReadFile(rc.ClientCertificate); err != nil {
+			logger.Panicf("Failed to read the TLS client certificate of the remote signer: %v", err)
+		}
+		if clientConfig.SecOpts.Key, err = ioutil.ReadFile(rc.ClientPrivateKey); err != nil {
+			logger.Panicf("Failed to read the TLS client key of the remote signer: %v", err)
+		}
+	}
+
+	signer, err := remotesigner.New(remotesigner.Config{
+		Address:      rc.Address,
+		KeyID:        rc.KeyID,
+		Timeout:      rc.Timeout,
+		ClientConfig: clientConfig,
+	}, id)
+	if err != nil {
+		logger.Panicf("Failed to create the remote signer: %v", err)
+	}
+	return signer
+}
+
+func newEnroller(conf *localconfig.TopLevel) *enrollment.Enroller {
+	ec := conf.General.Enrollment
+	enrollmentConf := enrollment.Config{
+		URL:           ec.URL,
+		CAName:        ec.CAName,
+		EnrollmentID:  ec.ID,
+		Secret:        ec.Secret,
+		TLSRootCerts:  ec.RootCAs,
+		TLSProfile:    ec.TLSProfile,
+		CSRHosts:      ec.CSRHosts,
+		KeyType:       ec.KeyType,
+		MSPDir:        conf.General.LocalMSPDir,
+		RenewBefore:   ec.RenewBefore,
+		CheckInterval: ec.CheckInterval,
+		Timeout:       ec.Timeout,
+	}
+	if conf.General.TLS.Enabled {
+		enrollmentConf.TLSCertFile = conf.General.TLS.Certificate
+		enrollmentConf.TLSKeyFile = conf.General.TLS.PrivateKey
+	}
+
+	enroller, err := enrollment.New(enrollmentConf)
+	if err != nil {
+		logger.Panicf("Invalid enrollment configuration: %v", err)
+	}
+	return enroller
+}
+
+// enrollmentReloaders returns the functions applying the renewed certificates
+// to the local MSP and to the gRPC server.
+func enrollmentReloaders(conf *localconfig.TopLevel, localMSP *mgmt.ReloadableMSP, grpcServer *comm.GRPCServer) map[enrollment.Identity]func() error {
+	return map[enrollment.Identity]func() error{
+		enrollment.MSPIdentity: func() error {
+			return reloadLocalMSP(conf, localMSP)
+		},
+		enrollment.TLSIdentity: func() error {
+			cert, err := tls.LoadX509KeyPair(conf.General.TLS.Certificate, conf.General.TLS.PrivateKey)
+			if err != nil {
+				return err
+			}
+			grpcServer.SetServerCertificate(cert)
+			return nil
+		},
+	}
+}
+
 //go:generate counterfeiter -o mocks/health_checker.go -fake-name HealthChecker . healthChecker
 
 // HealthChecker defines the contract for health checker
@@ -790,6 +1142,10 @@ func newOperationsSystem(ops localconfig.Operations, metrics localconfig.Metrics
 			ClientCACertFiles:  ops.TLS.ClientRootCAs,
 		},
 		Version: metadata.Version,
+		LogSinks: flogging.SinkAllowList{
+			Directories: ops.LogSinks.AllowedDirectories,
+			Hosts:       ops.LogSinks.AllowedHosts,
+		},
 	})
 }
 
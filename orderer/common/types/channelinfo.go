@@ -59,6 +59,9 @@ const (
 	StatusOnBoarding Status = "onboarding"
 	// The orderer is not storing any blocks for this channel.
 	StatusInactive Status = "inactive"
+	// The orderer released the resources of the channel after a period without activity,
+	// the channel is reactivated on the next request targeting it.
+	StatusHibernated Status = "hibernated"
 )
 
 // ChannelInfo carries the response to an HTTP request to List a single channel.
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package etcdraft
 
 import (
+	"time"
+
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -19,6 +21,9 @@ type blockCreator struct {
 	hash   []byte
 	number uint64
 
+	// timestamp stamps the created blocks with the time at which they are cut
+	timestamp bool
+
 	logger *flogging.FabricLogger
 }
 
@@ -40,6 +45,11 @@ func (bc *blockCreator) createNextBlock(envs []*cb.Envelope) *cb.Block {
 	block := protoutil.NewBlock(bc.number, bc.hash)
 	block.Header.DataHash = protoutil.BlockDataHash(data)
 	block.Data = data
+	if bc.timestamp {
+		if err := protoutil.SetBlockTimestamp(block, time.Now()); err != nil {
+			bc.logger.Panicf("Could not stamp the time of block [%d]: %s", block.Header.Number, err)
+		}
+	}
 
 	bc.hash = protoutil.BlockHeaderHash(block.Header)
 	return block
@@ -33,4 +33,11 @@ func TestCreateNextBlock(t *testing.T) {
 	assert.Equal(t, second.Header.Number+1, third.Header.Number)
 	assert.Equal(t, protoutil.BlockDataHash(third.Data), third.Header.DataHash)
 	assert.Equal(t, protoutil.BlockHeaderHash(second.Header), third.Header.PreviousHash)
+	assert.False(t, protoutil.HasBlockTimestamp(third))
+
+	// the leader stamps the blocks it cuts, which are signed when written
+	bc.timestamp = true
+	fourth := bc.createNextBlock([]*cb.Envelope{{Payload: []byte("some other bytes")}})
+	assert.True(t, protoutil.HasBlockTimestamp(fourth))
+	assert.Equal(t, protoutil.BlockHeaderHash(third.Header), fourth.Header.PreviousHash)
 }
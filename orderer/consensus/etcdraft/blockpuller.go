@@ -63,6 +63,7 @@ func lastConfigBlockFromSupport(support consensus.ConsenterSupport) (*common.Blo
 func NewBlockPuller(support consensus.ConsenterSupport,
 	baseDialer *cluster.PredicateDialer,
 	clusterConfig localconfig.Cluster,
+	throttle *cluster.CatchUpThrottle,
 	bccsp bccsp.BCCSP,
 ) (BlockPuller, error) {
 
@@ -99,6 +100,7 @@ func NewBlockPuller(support consensus.ConsenterSupport,
 		TLSCert:             der.Bytes,
 		Channel:             support.ChannelID(),
 		Dialer:              stdDialer,
+		Throttle:            throttle,
 	}
 
 	return &LedgerBlockPuller{
@@ -148,7 +148,7 @@ func TestNewBlockPuller(t *testing.T) {
 	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
 	assert.NoError(t, err)
 
-	bp, err := etcdraft.NewBlockPuller(cs, dialer, localconfig.Cluster{}, cryptoProvider)
+	bp, err := etcdraft.NewBlockPuller(cs, dialer, localconfig.Cluster{}, nil, cryptoProvider)
 	assert.NoError(t, err)
 	assert.NotNil(t, bp)
 
@@ -179,7 +179,7 @@ func TestNewBlockPuller(t *testing.T) {
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.dialer.Config.SecOpts.Certificate = testCase.certificate
-			bp, err := etcdraft.NewBlockPuller(testCase.cs, testCase.dialer, localconfig.Cluster{}, cryptoProvider)
+			bp, err := etcdraft.NewBlockPuller(testCase.cs, testCase.dialer, localconfig.Cluster{}, nil, cryptoProvider)
 			assert.Nil(t, bp)
 			assert.EqualError(t, err, testCase.expectedError)
 		})
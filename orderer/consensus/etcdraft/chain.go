@@ -10,7 +10,9 @@ import (
 	"context"
 	"encoding/pem"
 	"fmt"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"net"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/protoutil"
@@ -60,6 +63,10 @@ const (
 	// DefaultLeaderlessCheckInterval is the interval that a chain checks
 	// its own leadership status.
 	DefaultLeaderlessCheckInterval = time.Second * 10
+
+	// DefaultPruneInterval is the minimum interval between two attempts of
+	// a chain to prune the blocks beyond the retention of its channel.
+	DefaultPruneInterval = time.Minute
 )
 
 //go:generate counterfeiter -o mocks/configurator.go . Configurator
@@ -115,10 +122,13 @@ type Options struct {
 	MaxSizePerMsg     uint64
 	MaxInflightBlocks int
 
-	// BlockMetdata and Consenters should only be modified while under lock
-	// of raftMetadataLock
+	// BlockMetdata, Consenters and Learners should only be modified while
+	// under lock of raftMetadataLock
 	BlockMetadata *etcdraft.BlockMetadata
 	Consenters    map[uint64]*etcdraft.Consenter
+	// Learners are the IDs of the consenters which replicate the channel
+	// without voting
+	Learners []uint64
 
 	// MigrationInit is set when the node starts right after consensus-type migration
 	MigrationInit bool
@@ -128,6 +138,14 @@ type Options struct {
 
 	EvictionSuspicion   time.Duration
 	LeaderCheckInterval time.Duration
+
+	// WALRecovery enables the repair of corrupted WAL and snapshot files
+	// from the block ledger when they prevent the chain from starting.
+	WALRecovery bool
+
+	// BlockTimestamp stamps the blocks cut by the leader with its clock, so
+	// that every consenter attests the same time when writing them.
+	BlockTimestamp bool
 }
 
 type submit struct {
@@ -186,6 +204,9 @@ type Chain struct {
 
 	createPuller CreateBlockPuller // func used to create BlockPuller on demand
 
+	pruning   uint32    // set while the ledger is being pruned in the background
+	lastPrune time.Time // when the ledger was last pruned
+
 	fresh bool // indicate if this is a fresh raft node
 
 	// this is exported so that test can use `Node.Status()` to get raft node status.
@@ -217,7 +238,16 @@ func NewChain(
 	lg := opts.Logger.With("channel", support.ChannelID(), "node", opts.RaftID)
 
 	fresh := !wal.Exist(opts.WALDir)
-	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage)
+	var storage *RaftStorage
+	var err error
+	if opts.WALRecovery {
+		storage, err = createStorageWithRecovery(lg, support, opts)
+	} else {
+		storage, err = CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage)
+		if err != nil {
+			err = errors.Errorf("%s; enabling Consensus.WALRecovery attempts to repair the raft data", err)
+		}
+	}
 	if err != nil {
 		return nil, errors.Errorf("failed to restore persisted raft data: %s", err)
 	}
@@ -761,9 +791,10 @@ func (c *Chain) run() {
 
 				c.logger.Infof("Start accepting requests as Raft leader at block [%d]", c.lastBlock.Header.Number)
 				bc = &blockCreator{
-					hash:   protoutil.BlockHeaderHash(c.lastBlock.Header),
-					number: c.lastBlock.Header.Number,
-					logger: c.logger,
+					hash:      protoutil.BlockHeaderHash(c.lastBlock.Header),
+					number:    c.lastBlock.Header.Number,
+					timestamp: c.opts.BlockTimestamp,
+					logger:    c.logger,
 				}
 				submitC = c.submitC
 				c.justElected = false
@@ -847,6 +878,77 @@ func (c *Chain) writeBlock(block *common.Block, index uint64) {
 	c.raftMetadataLock.Unlock()
 
 	c.support.WriteBlock(block, m)
+	c.maybePrune(block.Header.Number)
+}
+
+// maybePrune prunes in the background the blocks beyond the retention set in
+// the consensus metadata of the channel, at most once per DefaultPruneInterval.
+func (c *Chain) maybePrune(lastBlockNum uint64) {
+	pruner, ok := c.support.(blockledger.Pruned)
+	if !ok || !c.support.SharedConfig().Capabilities().BlockRetention() {
+		return
+	}
+	if now := c.clock.Now(); now.Sub(c.lastPrune) < DefaultPruneInterval {
+		return
+	}
+
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(c.support.SharedConfig().ConsensusMetadata(), metadata); err != nil {
+		c.logger.Panicf("Failed to unmarshal consensus metadata: %v", err)
+	}
+	retained := metadata.GetOptions().GetRetainedBlocks()
+	if retained == 0 || lastBlockNum+1 <= retained {
+		return
+	}
+
+	if !atomic.CompareAndSwapUint32(&c.pruning, 0, 1) {
+		return
+	}
+	c.lastPrune = c.clock.Now()
+	c.raftMetadataLock.RLock()
+	consenters := len(c.opts.Consenters)
+	c.raftMetadataLock.RUnlock()
+
+	go func() {
+		defer atomic.StoreUint32(&c.pruning, 0)
+		c.prune(pruner, lastBlockNum+1-retained, consenters)
+	}()
+}
+
+// prune removes the blocks numbered below belowBlockNum, but the config blocks.
+// The blocks which a consenter is yet to pull are kept, and nothing is pruned
+// unless all the consenters report their height.
+func (c *Chain) prune(pruner blockledger.Pruned, belowBlockNum uint64, consenters int) {
+	puller, err := c.createPuller()
+	if err != nil {
+		c.logger.Warningf("Not pruning the ledger, failed creating block puller: %s", err)
+		return
+	}
+	defer puller.Close()
+
+	heights, err := puller.HeightsByEndpoints()
+	if err != nil {
+		c.logger.Warningf("Not pruning the ledger, failed getting the heights of the consenters: %s", err)
+		return
+	}
+	if len(heights) < consenters {
+		c.logger.Infof("Not pruning the ledger, %d of the %d consenters reported their height", len(heights), consenters)
+		return
+	}
+	for endpoint, height := range heights {
+		if height < belowBlockNum {
+			c.logger.Debugf("Keeping the blocks from block [%d] which %s is yet to pull", height, endpoint)
+			belowBlockNum = height
+		}
+	}
+	if belowBlockNum <= pruner.FirstRetainedBlock() {
+		return
+	}
+
+	c.logger.Infof("Pruning the blocks below block [%d]", belowBlockNum)
+	if err := pruner.Prune(belowBlockNum); err != nil {
+		c.logger.Errorf("Failed pruning the ledger: %s", err)
+	}
 }
 
 // Orders the envelope in the `msg` content. SubmitRequest.
@@ -966,6 +1068,7 @@ func (c *Chain) commitBlock(block *common.Block) {
 		c.raftMetadataLock.Lock()
 		c.opts.BlockMetadata = configMembership.NewBlockMetadata
 		c.opts.Consenters = configMembership.NewConsenters
+		c.opts.Learners = configMembership.NewLearners
 		c.raftMetadataLock.Unlock()
 
 		if err := c.configureComm(); err != nil {
@@ -996,10 +1099,18 @@ func (c *Chain) detectConfChange(block *common.Block) *MembershipChanges {
 		c.logger.Panicf("illegal configuration change detected: %s", err)
 	}
 
+	if err := changes.ComputeLearnerChanges(c.opts.Learners, configMetadata.LearnerClientTlsCerts); err != nil {
+		c.logger.Panicf("illegal learners change detected: %s", err)
+	}
+
 	if changes.Rotated() {
 		c.logger.Infof("Config block [%d] rotates TLS certificate of node %d", block.Header.Number, changes.RotatedNode)
 	}
 
+	if changes.Promoted() {
+		c.logger.Infof("Config block [%d] promotes learner %d to voting member", block.Header.Number, changes.PromotedNode)
+	}
+
 	return changes
 }
 
@@ -1045,9 +1156,11 @@ func (c *Chain) apply(ents []raftpb.Entry) {
 
 			switch cc.Type {
 			case raftpb.ConfChangeAddNode:
-				c.logger.Infof("Applied config change to add node %d, current nodes in channel: %+v", cc.NodeID, c.confState.Nodes)
+				c.logger.Infof("Applied config change to add node %d, current nodes in channel: %+v, learners: %+v", cc.NodeID, c.confState.Nodes, c.confState.Learners)
+			case raftpb.ConfChangeAddLearnerNode:
+				c.logger.Infof("Applied config change to add learner %d, current nodes in channel: %+v, learners: %+v", cc.NodeID, c.confState.Nodes, c.confState.Learners)
 			case raftpb.ConfChangeRemoveNode:
-				c.logger.Infof("Applied config change to remove node %d, current nodes in channel: %+v", cc.NodeID, c.confState.Nodes)
+				c.logger.Infof("Applied config change to remove node %d, current nodes in channel: %+v, learners: %+v", cc.NodeID, c.confState.Nodes, c.confState.Learners)
 			default:
 				c.logger.Panic("Programming error, encountered unsupported raft config change")
 			}
@@ -1169,7 +1282,7 @@ func (c *Chain) remotePeers() ([]cluster.RemoteNode, error) {
 		}
 		nodes = append(nodes, cluster.RemoteNode{
 			ID:            raftID,
-			Endpoint:      fmt.Sprintf("%s:%d", consenter.Host, consenter.Port),
+			Endpoint:      net.JoinHostPort(consenter.Host, fmt.Sprintf("%d", consenter.Port)),
 			ServerTLSCert: serverCertAsDER,
 			ClientTLSCert: clientCertAsDER,
 		})
@@ -1206,6 +1319,7 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 		if configMembership != nil {
 			c.opts.BlockMetadata = configMembership.NewBlockMetadata
 			c.opts.Consenters = configMembership.NewConsenters
+			c.opts.Learners = configMembership.NewLearners
 		}
 		c.raftMetadataLock.Unlock()
 
@@ -1233,10 +1347,14 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 
 			c.confChangeInProgress = configMembership.ConfChange
 
-			switch configMembership.ConfChange.Type {
-			case raftpb.ConfChangeAddNode:
+			switch {
+			case configMembership.Promoted():
+				c.logger.Infof("Config block just committed promotes learner %d, pause accepting transactions till config change is applied", configMembership.ConfChange.NodeID)
+			case configMembership.ConfChange.Type == raftpb.ConfChangeAddNode:
 				c.logger.Infof("Config block just committed adds node %d, pause accepting transactions till config change is applied", configMembership.ConfChange.NodeID)
-			case raftpb.ConfChangeRemoveNode:
+			case configMembership.ConfChange.Type == raftpb.ConfChangeAddLearnerNode:
+				c.logger.Infof("Config block just committed adds learner %d, pause accepting transactions till config change is applied", configMembership.ConfChange.NodeID)
+			case configMembership.ConfChange.Type == raftpb.ConfChangeRemoveNode:
 				c.logger.Infof("Config block just committed removes node %d, pause accepting transactions till config change is applied", configMembership.ConfChange.NodeID)
 			default:
 				c.logger.Panic("Programming error, encountered unsupported raft config change")
@@ -1293,16 +1411,11 @@ func (c *Chain) getInFlightConfChange() *raftpb.ConfChange {
 	// extracting current Raft configuration state
 	confState := c.Node.ApplyConfChange(raftpb.ConfChange{})
 
-	if len(confState.Nodes) == len(c.opts.BlockMetadata.ConsenterIds) {
-		// Raft configuration change could only add one node or
-		// remove one node at a time, if raft conf state size is
-		// equal to membership stored in block metadata field,
-		// that means everything is in sync and no need to propose
-		// config update.
-		return nil
-	}
-
-	return ConfChange(c.opts.BlockMetadata, confState)
+	// Raft configuration change could only add, remove or promote
+	// one node at a time, if raft conf state is in sync with the
+	// membership stored in block metadata field and the learners,
+	// no config update is returned.
+	return ConfChange(c.opts.BlockMetadata, confState, c.opts.Learners)
 }
 
 // newMetadata extract config metadata from the configuration block
@@ -1356,7 +1469,20 @@ func (c *Chain) ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig cha
 		return errors.Wrap(err, "invalid new config metadata")
 	}
 
+	if len(newMetadata.LearnerClientTlsCerts) > 0 && !newOrdererConfig.Capabilities().RaftLearners() {
+		return errors.Errorf("raft learners require the %s orderer capability", capabilities.OrdererRaftLearners)
+	}
+
+	if newMetadata.Options.GetRetainedBlocks() > 0 && !newOrdererConfig.Capabilities().BlockRetention() {
+		return errors.Errorf("block retention requires the %s orderer capability", capabilities.OrdererBlockRetention)
+	}
+
 	if newChannel {
+		// channels are created with voting consenters only, learners are added by later config updates
+		if len(newMetadata.LearnerClientTlsCerts) > 0 {
+			return errors.New("new channel cannot have raft learners")
+		}
+
 		// check if the consenters are a subset of the existing consenters (system channel consenters)
 		set := ConsentersToMap(oldMetadata.Consenters)
 		for _, c := range newMetadata.Consenters {
@@ -1378,6 +1504,11 @@ func (c *Chain) ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig cha
 		return err
 	}
 
+	oldLearners := LearnerIDs(dummyOldConsentersMap, oldMetadata.LearnerClientTlsCerts)
+	if err := changes.ComputeLearnerChanges(oldLearners, newMetadata.LearnerClientTlsCerts); err != nil {
+		return err
+	}
+
 	//new config metadata was verified above. Additionally need to check new consenters for certificates expiration
 	for _, c := range changes.AddedNodes {
 		if err := validateConsenterTLSCerts(c, verifyOpts, false); err != nil {
@@ -1398,6 +1529,44 @@ func (c *Chain) StatusReport() (types.ClusterRelation, types.Status) {
 	return types.ClusterRelationMember, types.StatusActive
 }
 
+// QuorumStatus reports whether the chain currently has quorum, i.e. the chain is running, a
+// leader is known and a majority of the voting consenters is reachable by that leader.
+func (c *Chain) QuorumStatus() consensus.QuorumStatus {
+	c.raftMetadataLock.RLock()
+	total := len(c.opts.Consenters) - len(c.opts.Learners)
+	learners := c.opts.Learners
+	c.raftMetadataLock.RUnlock()
+
+	var active int
+	for _, nodeID := range c.ActiveNodes.Load().([]uint64) {
+		if !NodeExists(nodeID, learners) {
+			active++
+		}
+	}
+
+	status := consensus.QuorumStatus{
+		Leader:      atomic.LoadUint64(&c.lastKnownLeader),
+		ActiveNodes: active,
+		TotalNodes:  total,
+	}
+
+	if err := c.isRunning(); err != nil {
+		status.Reason = err.Error()
+		return status
+	}
+	if status.Leader == raft.None {
+		status.Reason = "no leader is known"
+		return status
+	}
+	if status.ActiveNodes < total/2+1 {
+		status.Reason = fmt.Sprintf("only %d out of %d nodes are reachable", status.ActiveNodes, total)
+		return status
+	}
+
+	status.HasQuorum = true
+	return status
+}
+
 func (c *Chain) suspectEviction() bool {
 	if c.isRunning() != nil {
 		return false
@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	orderer_types "github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft/mocks"
 	consensusmocks "github.com/hyperledger/fabric/orderer/consensus/mocks"
@@ -239,6 +240,13 @@ var _ = Describe("Chain", func() {
 		})
 
 		Context("when a node starts up", func() {
+			It("reports no quorum until a leader is elected", func() {
+				Expect(chain.QuorumStatus()).To(Equal(consensus.QuorumStatus{
+					TotalNodes: 1,
+					Reason:     "no leader is known",
+				}))
+			})
+
 			It("properly configures the communication layer", func() {
 				expectedNodeConfig := nodeConfigFromMetadata(consenterMetadata)
 				Eventually(configurator.ConfigureCallCount, LongEventualTimeout).Should(Equal(1))
@@ -313,6 +321,23 @@ var _ = Describe("Chain", func() {
 				Expect(fakeFields.fakeLeaderChanges.AddArgsForCall(0)).To(Equal(float64(1)))
 			})
 
+			It("reports quorum once the leader tracked the active nodes", func() {
+				Eventually(func() bool {
+					clock.Increment(interval)
+					return chain.QuorumStatus().HasQuorum
+				}, LongEventualTimeout).Should(BeTrue())
+				Expect(chain.QuorumStatus()).To(Equal(consensus.QuorumStatus{
+					HasQuorum:   true,
+					Leader:      1,
+					ActiveNodes: 1,
+					TotalNodes:  1,
+				}))
+
+				chain.Halt()
+				Expect(chain.QuorumStatus().HasQuorum).To(BeFalse())
+				Expect(chain.QuorumStatus().Reason).To(Equal("chain is stopped"))
+			})
+
 			It("fails to order envelope if chain is halted", func() {
 				chain.Halt()
 				err := chain.Order(env, 0)
@@ -2021,6 +2046,76 @@ var _ = Describe("Chain", func() {
 					})
 				})
 
+				It("adding a learner to the cluster and promoting it", func() {
+					addConsenterUpdate := addConsenterConfigValue()
+					configMetadata, err := etcdraft.MetadataFromConfigValue(addConsenterUpdate["ConsensusType"])
+					Expect(err).NotTo(HaveOccurred())
+					learner := configMetadata.Consenters[len(configMetadata.Consenters)-1]
+					configEnv := newConfigEnv(channelID, common.HeaderType_CONFIG, newConfigUpdateEnv(channelID, nil, withRaftLearners(addConsenterUpdate, learner.ClientTlsCert)))
+					c1.cutter.CutNext = true
+
+					By("sending config transaction adding a learner")
+					Expect(c1.Configure(configEnv, 0)).To(Succeed())
+
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteConfigBlockCallCount, defaultTimeout).Should(Equal(1))
+						Eventually(c.fakeFields.fakeClusterSize.SetCallCount, LongEventualTimeout).Should(Equal(2))
+						Expect(c.fakeFields.fakeClusterSize.SetArgsForCall(1)).To(Equal(float64(4)))
+					})
+					// the learner does not count towards the quorum
+					Expect(c1.QuorumStatus().TotalNodes).To(Equal(3))
+
+					_, raftmetabytes := c1.support.WriteConfigBlockArgsForCall(0)
+					meta := &common.Metadata{Value: raftmetabytes}
+					raftmeta, err := etcdraft.ReadBlockMetadata(meta, nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					c4Consenters := map[uint64]*raftprotos.Consenter{}
+					for id, consenter := range consenters {
+						c4Consenters[id] = consenter
+					}
+					c4Consenters[4] = learner
+
+					c4 := newChain(timeout, channelID, dataDir, 4, raftmeta, c4Consenters, cryptoProvider, nil)
+					c4.opts.Learners = []uint64{4}
+					c4.support.WriteBlock(c1.support.WriteBlockArgsForCall(0))
+					c4.support.WriteConfigBlock(c1.support.WriteConfigBlockArgsForCall(0))
+					c4.init()
+
+					network.addChain(c4)
+					c4.Start()
+
+					Eventually(func() <-chan raft.SoftState {
+						c1.clock.Increment(interval)
+						return c4.observe
+					}, defaultTimeout).Should(Receive(Equal(raft.SoftState{Lead: 1, RaftState: raft.StateFollower})))
+
+					By("submitting new transaction to the learner")
+					c1.cutter.CutNext = true
+					Expect(c4.Order(env, 0)).To(Succeed())
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteBlockCallCount, defaultTimeout).Should(Equal(2))
+					})
+
+					By("sending config transaction promoting the learner")
+					configEnv = newConfigEnv(channelID, common.HeaderType_CONFIG, newConfigUpdateEnv(channelID, nil, withRaftLearners(addConsenterUpdate)))
+					c1.cutter.CutNext = true
+					Expect(c1.Configure(configEnv, 0)).To(Succeed())
+
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteConfigBlockCallCount, defaultTimeout).Should(Equal(2))
+					})
+					Eventually(c1.fakeFields.fakeClusterSize.SetCallCount, LongEventualTimeout).Should(Equal(3))
+					Eventually(func() int { return c1.QuorumStatus().TotalNodes }, LongEventualTimeout).Should(Equal(4))
+
+					By("submitting new transaction once the learner is promoted")
+					c1.cutter.CutNext = true
+					Expect(c4.Order(env, 0)).To(Succeed())
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteBlockCallCount, defaultTimeout).Should(Equal(3))
+					})
+				})
+
 				It("does not reconfigure raft cluster if it's a channel creation tx", func() {
 					configEnv := newConfigEnv("another-channel",
 						common.HeaderType_CONFIG,
@@ -3866,6 +3961,21 @@ func newConfigEnv(chainID string, headerType common.HeaderType, configUpdateEnv
 	}
 }
 
+// withRaftLearners lists the given client TLS certificates as raft learners in the etcdraft metadata of a ConsensusType update
+func withRaftLearners(values map[string]*common.ConfigValue, learnerCerts ...[]byte) map[string]*common.ConfigValue {
+	metadata, err := etcdraft.MetadataFromConfigValue(values["ConsensusType"])
+	Expect(err).NotTo(HaveOccurred())
+	metadata.LearnerClientTlsCerts = learnerCerts
+	return map[string]*common.ConfigValue{
+		"ConsensusType": {
+			Version: values["ConsensusType"].Version,
+			Value: marshalOrPanic(&orderer.ConsensusType{
+				Metadata: marshalOrPanic(metadata),
+			}),
+		},
+	}
+}
+
 func newConfigUpdateEnv(chainID string, oldValues, newValues map[string]*common.ConfigValue) *common.ConfigUpdateEnvelope {
 	return &common.ConfigUpdateEnvelope{
 		ConfigUpdate: marshalOrPanic(&common.ConfigUpdate{
@@ -45,10 +45,11 @@ type InactiveChainRegistry interface {
 
 // ChainGetter obtains instances of ChainSupport for the given channel
 type ChainGetter interface {
-	// GetChain obtains the ChainSupport for the given channel.
-	// Returns nil, false when the ChainSupport for the given channel
-	// isn't found.
-	GetChain(chainID string) *multichannel.ChainSupport
+	// GetActiveChain obtains the ChainSupport for the given channel,
+	// without reactivating it if it is hibernated.
+	// Returns nil when the ChainSupport for the given channel
+	// isn't found or is hibernated.
+	GetActiveChain(chainID string) *multichannel.ChainSupport
 }
 
 // Config contains etcdraft configurations
@@ -57,6 +58,7 @@ type Config struct {
 	SnapDir              string // Snapshots of <my-channel> are stored in SnapDir/<my-channel>
 	EvictionSuspicion    string // Duration threshold that the node samples in order to suspect its eviction from the channel.
 	TickIntervalOverride string // Duration to use for tick interval instead of what is specified in the channel config.
+	WALRecovery          bool   // Repair corrupted WAL and snapshot files from the ledger instead of failing to start.
 }
 
 // Consenter implements etcdraft consenter
@@ -73,6 +75,8 @@ type Consenter struct {
 	Cert           []byte
 	Metrics        *Metrics
 	BCCSP          bccsp.BCCSP
+	// CatchUpThrottle limits the traffic of the block pullers of all chains
+	CatchUpThrottle *cluster.CatchUpThrottle
 }
 
 // TargetChannel extracts the channel from the given proto.Message.
@@ -91,7 +95,7 @@ func (c *Consenter) TargetChannel(message proto.Message) string {
 // ReceiverByChain returns the MessageReceiver for the given channelID or nil
 // if not found.
 func (c *Consenter) ReceiverByChain(channelID string) MessageReceiver {
-	cs := c.Chains.GetChain(channelID)
+	cs := c.Chains.GetActiveChain(channelID)
 	if cs == nil {
 		return nil
 	}
@@ -212,6 +216,7 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 
 		BlockMetadata: blockMetadata,
 		Consenters:    consenters,
+		Learners:      LearnerIDs(consenters, m.LearnerClientTlsCerts),
 
 		MigrationInit: isMigration,
 
@@ -220,6 +225,8 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		EvictionSuspicion: evictionSuspicion,
 		Cert:              c.Cert,
 		Metrics:           c.Metrics,
+		WALRecovery:       c.EtcdRaftConfig.WALRecovery,
+		BlockTimestamp:    c.OrdererConfig.General.BlockTimestamp.Enabled,
 	}
 
 	rpc := &cluster.RPC{
@@ -239,7 +246,7 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 			rpc,
 			c.BCCSP,
 			func() (BlockPuller, error) {
-				return NewBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster, c.BCCSP)
+				return NewBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster, c.CatchUpThrottle, c.BCCSP)
 			},
 			func() {
 				c.InactiveChainRegistry.TrackChain(support.ChannelID(), nil, func() { c.CreateChain(support.ChannelID()) })
@@ -256,7 +263,7 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		rpc,
 		c.BCCSP,
 		func() (BlockPuller, error) {
-			return NewBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster, c.BCCSP)
+			return NewBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster, c.CatchUpThrottle, c.BCCSP)
 		},
 		func() {
 			c.Logger.Warning("Start a follower.Chain: not yet implemented")
@@ -325,6 +332,11 @@ func New(
 		Metrics:               NewMetrics(metricsProvider),
 		InactiveChainRegistry: icr,
 		BCCSP:                 bccsp,
+		CatchUpThrottle: cluster.NewCatchUpThrottle(
+			conf.General.Cluster.ReplicationMaxBytesPerSecond,
+			conf.General.Cluster.ReplicationMaxConcurrentPulls,
+			metricsProvider,
+		),
 	}
 	consenter.Dispatcher = &Dispatcher{
 		Logger:        logger,
@@ -147,10 +147,10 @@ var _ = Describe("Consenter", func() {
 			BCCSP: cryptoProvider,
 		}
 		BeforeEach(func() {
-			chainGetter.On("GetChain", "mychannel").Return(cs)
-			chainGetter.On("GetChain", "badChainObject").Return(&multichannel.ChainSupport{})
-			chainGetter.On("GetChain", "notmychannel").Return(nil)
-			chainGetter.On("GetChain", "notraftchain").Return(&multichannel.ChainSupport{
+			chainGetter.On("GetActiveChain", "mychannel").Return(cs)
+			chainGetter.On("GetActiveChain", "badChainObject").Return(&multichannel.ChainSupport{})
+			chainGetter.On("GetActiveChain", "notmychannel").Return(nil)
+			chainGetter.On("GetActiveChain", "notraftchain").Return(&multichannel.ChainSupport{
 				Chain: &multichannel.ChainSupport{},
 			})
 		})
@@ -0,0 +1,202 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// ConsensusMetadataReport describes the Raft consensus metadata of a channel
+// as of a config block, to debug consenter set and certificate mismatches.
+type ConsensusMetadataReport struct {
+	ChannelID         string `json:"channel_id"`
+	ConfigBlockNumber uint64 `json:"config_block_number"`
+	// ConfigSequence is the sequence of the config; the next config update
+	// of the channel is expected to produce NextConfigSequence.
+	ConfigSequence     uint64            `json:"config_sequence"`
+	NextConfigSequence uint64            `json:"next_config_sequence"`
+	ConsensusType      string            `json:"consensus_type"`
+	ConsensusState     string            `json:"consensus_state"`
+	Options            *etcdraft.Options `json:"options"`
+	// NextConsenterID is the Raft ID assigned to the next consenter added
+	// to the channel.
+	NextConsenterID uint64             `json:"next_consenter_id"`
+	RaftIndex       uint64             `json:"raft_index"`
+	Learners        []uint64           `json:"learners,omitempty"`
+	Consenters      []*ConsenterReport `json:"consenters"`
+	// InConsenterSet reports whether the local TLS certificate belongs to a
+	// consenter of the channel; it is omitted when no certificate is given.
+	InConsenterSet *bool `json:"in_consenter_set,omitempty"`
+}
+
+// ConsenterReport describes a consenter of a channel.
+type ConsenterReport struct {
+	ID            uint64             `json:"id"`
+	Host          string             `json:"host"`
+	Port          uint32             `json:"port"`
+	Local         bool               `json:"local,omitempty"`
+	ClientTLSCert *CertificateReport `json:"client_tls_cert"`
+	ServerTLSCert *CertificateReport `json:"server_tls_cert"`
+}
+
+// CertificateReport describes a consenter certificate and the result of its
+// verification against the TLS CA certificates of the orderer organizations.
+type CertificateReport struct {
+	Subject           string    `json:"subject,omitempty"`
+	Issuer            string    `json:"issuer,omitempty"`
+	SerialNumber      string    `json:"serial_number,omitempty"`
+	SubjectKeyID      string    `json:"subject_key_id,omitempty"`
+	NotBefore         time.Time `json:"not_before"`
+	NotAfter          time.Time `json:"not_after"`
+	Expired           bool      `json:"expired,omitempty"`
+	Verified          bool      `json:"verified"`
+	VerificationError string    `json:"verification_error,omitempty"`
+}
+
+// InspectConsensusMetadata decodes the Raft consensus metadata of the channel
+// configured by the config block. The consenter certificates are verified
+// against the TLS root and intermediate CA certificates of the orderer
+// organizations of the channel, and matched against the local TLS certificate
+// when one is given.
+func InspectConsensusMetadata(configBlock *common.Block, localCert []byte, cryptoProvider bccsp.BCCSP) (*ConsensusMetadataReport, error) {
+	if configBlock == nil || configBlock.Header == nil {
+		return nil, errors.New("nil block or nil header")
+	}
+	if !protoutil.IsConfigBlock(configBlock) {
+		return nil, errors.Errorf("block [%d] is not a config block", configBlock.Header.Number)
+	}
+	env, err := protoutil.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env, cryptoProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load the channel config")
+	}
+	oc, exists := bundle.OrdererConfig()
+	if !exists {
+		return nil, errors.New("no orderer config in the channel config")
+	}
+	if oc.ConsensusType() != "etcdraft" {
+		return nil, errors.Errorf("consensus type of the channel is %s, not etcdraft", oc.ConsensusType())
+	}
+	configMetadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(oc.ConsensusMetadata(), configMetadata); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the consensus metadata")
+	}
+
+	var blockMetadata *common.Metadata
+	if len(configBlock.Metadata.GetMetadata()) > int(common.BlockMetadataIndex_SIGNATURES) {
+		blockMetadata, err = protoutil.GetConsenterMetadataFromBlock(configBlock)
+		if err != nil {
+			return nil, err
+		}
+	}
+	raftMetadata, err := ReadBlockMetadata(blockMetadata, configMetadata)
+	if err != nil {
+		return nil, err
+	}
+	if len(raftMetadata.ConsenterIds) != len(configMetadata.Consenters) {
+		return nil, errors.Errorf("block metadata has %d consenter IDs for %d consenters",
+			len(raftMetadata.ConsenterIds), len(configMetadata.Consenters))
+	}
+
+	verifyOpts, err := createX509VerifyOptions(oc)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	verifyOpts.CurrentTime = now
+
+	var localDER []byte
+	if len(localCert) > 0 {
+		bl, _ := pem.Decode(localCert)
+		if bl == nil {
+			return nil, errors.Errorf("local certificate %s is not a valid PEM", string(localCert))
+		}
+		localDER = bl.Bytes
+	}
+
+	chdr, err := protoutil.ChannelHeader(env)
+	if err != nil {
+		return nil, err
+	}
+	sequence := bundle.ConfigtxValidator().Sequence()
+	report := &ConsensusMetadataReport{
+		ChannelID:          chdr.ChannelId,
+		ConfigBlockNumber:  configBlock.Header.Number,
+		ConfigSequence:     sequence,
+		NextConfigSequence: sequence + 1,
+		ConsensusType:      oc.ConsensusType(),
+		ConsensusState:     oc.ConsensusState().String(),
+		Options:            configMetadata.Options,
+		NextConsenterID:    raftMetadata.NextConsenterId,
+		RaftIndex:          raftMetadata.RaftIndex,
+		Learners:           LearnerIDs(CreateConsentersMap(raftMetadata, configMetadata), configMetadata.LearnerClientTlsCerts),
+	}
+	inConsenterSet := false
+	for i, consenter := range configMetadata.Consenters {
+		cr := &ConsenterReport{
+			ID:            raftMetadata.ConsenterIds[i],
+			Host:          consenter.Host,
+			Port:          consenter.Port,
+			ClientTLSCert: inspectCertificate(consenter.ClientTlsCert, verifyOpts, now),
+			ServerTLSCert: inspectCertificate(consenter.ServerTlsCert, verifyOpts, now),
+		}
+		if localDER != nil {
+			cr.Local = samePublicKey(localDER, consenter.ClientTlsCert) || samePublicKey(localDER, consenter.ServerTlsCert)
+			inConsenterSet = inConsenterSet || cr.Local
+		}
+		report.Consenters = append(report.Consenters, cr)
+	}
+	if localDER != nil {
+		report.InConsenterSet = &inConsenterSet
+	}
+	return report, nil
+}
+
+func inspectCertificate(certPEM []byte, opts x509.VerifyOptions, now time.Time) *CertificateReport {
+	cert, err := parseCertificateFromBytes(certPEM)
+	if err != nil {
+		return &CertificateReport{VerificationError: err.Error()}
+	}
+	cr := &CertificateReport{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		SubjectKeyID: hex.EncodeToString(cert.SubjectKeyId),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		Expired:      now.After(cert.NotAfter),
+	}
+	if _, err := cert.Verify(opts); err != nil {
+		cr.VerificationError = err.Error()
+	} else {
+		cr.Verified = true
+	}
+	return cr
+}
+
+func samePublicKey(der []byte, certPEM []byte) bool {
+	bl, _ := pem.Decode(certPEM)
+	if bl == nil {
+		return false
+	}
+	return crypto.CertificatesWithSamePublicKey(der, bl.Bytes) == nil
+}
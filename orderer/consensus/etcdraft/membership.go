@@ -33,8 +33,10 @@ type MembershipChanges struct {
 	NewConsenters    map[uint64]*etcdraft.Consenter
 	AddedNodes       []*etcdraft.Consenter
 	RemovedNodes     []*etcdraft.Consenter
+	NewLearners      []uint64
 	ConfChange       *raftpb.ConfChange
 	RotatedNode      uint64
+	PromotedNode     uint64
 }
 
 // ComputeMembershipChanges computes membership update based on information about new consenters, returns
@@ -105,6 +107,53 @@ func ComputeMembershipChanges(oldMetadata *etcdraft.BlockMetadata, oldConsenters
 	return result, nil
 }
 
+// ComputeLearnerChanges completes membership changes with the learners of the new
+// configuration, given as the client TLS certificates of the consenters, and the IDs
+// of the current learners. A consenter added as a learner joins the Raft cluster as
+// a learner, and a learner no longer listed is promoted to a voting member. Learners
+// may only be promoted, one at a time and not along with other changes.
+func (mc *MembershipChanges) ComputeLearnerChanges(oldLearners []uint64, learnerCerts [][]byte) error {
+	mc.NewLearners = LearnerIDs(mc.NewConsenters, learnerCerts)
+	if len(mc.NewLearners) != len(learnerCerts) {
+		return errors.Errorf("%d raft learner(s) listed but %d match the client TLS certificate of a consenter", len(learnerCerts), len(mc.NewLearners))
+	}
+
+	var addedNodeID uint64
+	if mc.ConfChange != nil && mc.ConfChange.Type == raftpb.ConfChangeAddNode {
+		addedNodeID = mc.ConfChange.NodeID
+	}
+
+	for _, nodeID := range mc.NewLearners {
+		if nodeID != addedNodeID && !NodeExists(nodeID, oldLearners) {
+			return errors.Errorf("consenter %d cannot be demoted to a learner, it must be removed and added back instead", nodeID)
+		}
+	}
+
+	var promoted []uint64
+	for _, nodeID := range oldLearners {
+		if _, exists := mc.NewConsenters[nodeID]; exists && !NodeExists(nodeID, mc.NewLearners) {
+			promoted = append(promoted, nodeID)
+		}
+	}
+
+	switch {
+	case len(promoted) > 1:
+		return errors.Errorf("promotion of more than one learner at a time is not supported, requested promotions: %v", promoted)
+	case len(promoted) == 1 && mc.Changed():
+		return errors.Errorf("promotion of learner %d cannot be combined with other membership changes, requested changes: %s", promoted[0], mc)
+	case len(promoted) == 1:
+		mc.PromotedNode = promoted[0]
+		mc.ConfChange = &raftpb.ConfChange{
+			NodeID: promoted[0],
+			Type:   raftpb.ConfChangeAddNode,
+		}
+	case addedNodeID != raft.None && NodeExists(addedNodeID, mc.NewLearners):
+		mc.ConfChange.Type = raftpb.ConfChangeAddLearnerNode
+	}
+
+	return nil
+}
+
 // Stringer implements fmt.Stringer interface
 func (mc *MembershipChanges) String() string {
 	return fmt.Sprintf("add %d node(s), remove %d node(s)", len(mc.AddedNodes), len(mc.RemovedNodes))
@@ -112,7 +161,12 @@ func (mc *MembershipChanges) String() string {
 
 // Changed indicates whether these changes actually do anything
 func (mc *MembershipChanges) Changed() bool {
-	return len(mc.AddedNodes) > 0 || len(mc.RemovedNodes) > 0
+	return len(mc.AddedNodes) > 0 || len(mc.RemovedNodes) > 0 || mc.Promoted()
+}
+
+// Promoted indicates whether the change was the promotion of a learner
+func (mc *MembershipChanges) Promoted() bool {
+	return mc.PromotedNode != raft.None
 }
 
 // Rotated indicates whether the change was a rotation
@@ -123,18 +177,25 @@ func (mc *MembershipChanges) Rotated() bool {
 // UnacceptableQuorumLoss returns true if membership change will result in avoidable quorum loss,
 // given current number of active nodes in cluster. Avoidable means that more nodes can be started
 // to prevent quorum loss. Sometimes, quorum loss is inevitable, for example expanding 1-node cluster.
+// Learners are not part of the quorum, hence adding them never results in quorum loss.
 func (mc *MembershipChanges) UnacceptableQuorumLoss(active []uint64) bool {
 	activeMap := make(map[uint64]struct{})
 	for _, i := range active {
-		activeMap[i] = struct{}{}
+		if !NodeExists(i, mc.NewLearners) {
+			activeMap[i] = struct{}{}
+		}
 	}
 
-	isCFT := len(mc.NewConsenters) > 2 // if resulting cluster cannot tolerate any fault, quorum loss is inevitable
-	quorum := len(mc.NewConsenters)/2 + 1
+	voters := len(mc.NewConsenters) - len(mc.NewLearners)
+	isCFT := voters > 2 // if resulting cluster cannot tolerate any fault, quorum loss is inevitable
+	quorum := voters/2 + 1
 
 	switch {
-	case mc.ConfChange != nil && mc.ConfChange.Type == raftpb.ConfChangeAddNode: // Add
-		return isCFT && len(active) < quorum
+	case mc.ConfChange != nil && mc.ConfChange.Type == raftpb.ConfChangeAddLearnerNode: // Add learner
+		return false
+
+	case mc.ConfChange != nil && mc.ConfChange.Type == raftpb.ConfChangeAddNode: // Add or promote
+		return isCFT && len(activeMap) < quorum
 
 	case mc.RotatedNode != raft.None: // Rotate
 		delete(activeMap, mc.RotatedNode)
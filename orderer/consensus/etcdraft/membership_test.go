@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package etcdraft_test
 
 import (
+	"fmt"
 	"testing"
 
 	etcdraftproto "github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
@@ -22,6 +23,7 @@ func TestQuorumCheck(t *testing.T) {
 	tests := []struct {
 		Name          string
 		NewConsenters map[uint64]*etcdraftproto.Consenter
+		NewLearners   []uint64
 		ConfChange    *raftpb.ConfChange
 		RotateNode    uint64
 		ActiveNodes   []uint64
@@ -31,6 +33,7 @@ func TestQuorumCheck(t *testing.T) {
 		//  1     - node 1 is alive
 		// (1)    - node 1 is dead
 		//  1'    - node 1's cert is being rotated. Node is considered to be dead in new set
+		// [1|2]  - node 2 is a learner
 
 		// Add
 		{
@@ -112,12 +115,51 @@ func TestQuorumCheck(t *testing.T) {
 			ActiveNodes:   []uint64{1, 2},
 			QuorumLoss:    false,
 		},
+		// Learners
+		{
+			Name:          "[1,2,(3)]->[1,2,(3)|(4)]",
+			NewConsenters: map[uint64]*etcdraftproto.Consenter{1: nil, 2: nil, 3: nil, 4: nil},
+			NewLearners:   []uint64{4},
+			ConfChange:    &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddLearnerNode},
+			ActiveNodes:   []uint64{1, 2},
+			QuorumLoss:    false,
+		},
+		{
+			Name:          "[1,2,(3)|4]->[1,2,(3),4]",
+			NewConsenters: map[uint64]*etcdraftproto.Consenter{1: nil, 2: nil, 3: nil, 4: nil},
+			ConfChange:    &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddNode},
+			ActiveNodes:   []uint64{1, 2, 4},
+			QuorumLoss:    false,
+		},
+		{
+			Name:          "[1,2,(3)|(4)]->[1,2,(3),(4)]",
+			NewConsenters: map[uint64]*etcdraftproto.Consenter{1: nil, 2: nil, 3: nil, 4: nil},
+			ConfChange:    &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddNode},
+			ActiveNodes:   []uint64{1, 2},
+			QuorumLoss:    true,
+		},
+		{
+			Name:          "[1,2,(3)|4,5]->[1,2,(3),(6)|4,5]",
+			NewConsenters: map[uint64]*etcdraftproto.Consenter{1: nil, 2: nil, 3: nil, 4: nil, 5: nil, 6: nil},
+			NewLearners:   []uint64{4, 5},
+			ConfChange:    &raftpb.ConfChange{NodeID: 6, Type: raftpb.ConfChangeAddNode},
+			ActiveNodes:   []uint64{1, 2, 4, 5},
+			QuorumLoss:    true,
+		},
+		{
+			Name:          "[1,2,(3)|4]->[1,2,(3)]",
+			NewConsenters: map[uint64]*etcdraftproto.Consenter{1: nil, 2: nil, 3: nil},
+			ConfChange:    &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeRemoveNode},
+			ActiveNodes:   []uint64{1, 2, 4},
+			QuorumLoss:    false,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
 			changes := &etcdraft.MembershipChanges{
 				NewConsenters: test.NewConsenters,
+				NewLearners:   test.NewLearners,
 				ConfChange:    test.ConfChange,
 				RotatedNode:   test.RotateNode,
 			}
@@ -343,3 +385,111 @@ func TestMembershipChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestLearnerChanges(t *testing.T) {
+	blockMetadata := &etcdraftproto.BlockMetadata{
+		ConsenterIds:    []uint64{1, 2, 3},
+		NextConsenterId: 4,
+	}
+
+	tlsCA, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	var c []*etcdraftproto.Consenter
+	for i := 1; i <= 5; i++ {
+		client, err := tlsCA.NewClientCertKeyPair()
+		require.NoError(t, err)
+		c = append(c, &etcdraftproto.Consenter{
+			Host:          fmt.Sprintf("orderer%d.example.com", i),
+			Port:          7050,
+			ClientTlsCert: client.Cert,
+			ServerTlsCert: client.Cert,
+		})
+	}
+	oldConsenters := map[uint64]*etcdraftproto.Consenter{1: c[0], 2: c[1], 3: c[2]}
+
+	tests := []struct {
+		Name          string
+		NewConsenters []*etcdraftproto.Consenter
+		OldLearners   []uint64
+		Learners      [][]byte
+		NewLearners   []uint64
+		ConfChange    *raftpb.ConfChange
+		PromotedNode  uint64
+		ExpectedErr   string
+	}{
+		{
+			Name:          "No learners",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2]},
+		},
+		{
+			Name:          "Add a node as a learner",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2], c[3]},
+			Learners:      [][]byte{c[3].ClientTlsCert},
+			NewLearners:   []uint64{4},
+			ConfChange:    &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddLearnerNode},
+		},
+		{
+			Name:          "Add a voting node next to a learner",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2], c[3]},
+			OldLearners:   []uint64{3},
+			Learners:      [][]byte{c[2].ClientTlsCert},
+			NewLearners:   []uint64{3},
+			ConfChange:    &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddNode},
+		},
+		{
+			Name:          "Promote a learner",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2]},
+			OldLearners:   []uint64{3},
+			ConfChange:    &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeAddNode},
+			PromotedNode:  3,
+		},
+		{
+			Name:          "Remove a learner",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1]},
+			OldLearners:   []uint64{3},
+			ConfChange:    &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeRemoveNode},
+		},
+		{
+			Name:          "Learner which is not a consenter",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2]},
+			Learners:      [][]byte{c[4].ClientTlsCert},
+			ExpectedErr:   "1 raft learner(s) listed but 0 match the client TLS certificate of a consenter",
+		},
+		{
+			Name:          "Demote a voting node",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2]},
+			Learners:      [][]byte{c[2].ClientTlsCert},
+			ExpectedErr:   "consenter 3 cannot be demoted to a learner, it must be removed and added back instead",
+		},
+		{
+			Name:          "Promote more than one learner",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2]},
+			OldLearners:   []uint64{2, 3},
+			ExpectedErr:   "promotion of more than one learner at a time is not supported, requested promotions: [2 3]",
+		},
+		{
+			Name:          "Promote a learner while adding a node",
+			NewConsenters: []*etcdraftproto.Consenter{c[0], c[1], c[2], c[4]},
+			OldLearners:   []uint64{3},
+			ExpectedErr:   "promotion of learner 3 cannot be combined with other membership changes, requested changes: add 1 node(s), remove 0 node(s)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			changes, err := etcdraft.ComputeMembershipChanges(blockMetadata, oldConsenters, test.NewConsenters)
+			require.NoError(t, err)
+
+			err = changes.ComputeLearnerChanges(test.OldLearners, test.Learners)
+			if test.ExpectedErr != "" {
+				require.EqualError(t, err, test.ExpectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.NewLearners, changes.NewLearners)
+			require.Equal(t, test.ConfChange, changes.ConfChange)
+			require.Equal(t, test.PromotedNode, changes.PromotedNode)
+			require.Equal(t, test.PromotedNode != 0, changes.Promoted())
+		})
+	}
+}
@@ -12,8 +12,8 @@ type ChainGetter struct {
 	mock.Mock
 }
 
-// GetChain provides a mock function with given fields: chainID
-func (_m *ChainGetter) GetChain(chainID string) *multichannel.ChainSupport {
+// GetActiveChain provides a mock function with given fields: chainID
+func (_m *ChainGetter) GetActiveChain(chainID string) *multichannel.ChainSupport {
 	ret := _m.Called(chainID)
 
 	var r0 *multichannel.ChainSupport
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type heightsPuller struct {
+	BlockPuller
+	heights map[string]uint64
+	err     error
+}
+
+func (p *heightsPuller) HeightsByEndpoints() (map[string]uint64, error) {
+	return p.heights, p.err
+}
+
+func (p *heightsPuller) Close() {}
+
+type fakePruner struct {
+	firstRetainedBlock uint64
+	prunedBelow        []uint64
+}
+
+func (p *fakePruner) FirstRetainedBlock() uint64 {
+	return p.firstRetainedBlock
+}
+
+func (p *fakePruner) Prune(belowBlockNum uint64) error {
+	p.prunedBelow = append(p.prunedBelow, belowBlockNum)
+	return nil
+}
+
+func TestChainPrune(t *testing.T) {
+	for _, testCase := range []struct {
+		description        string
+		puller             BlockPuller
+		pullerErr          error
+		firstRetainedBlock uint64
+		expectedPrune      []uint64
+	}{
+		{
+			description: "block puller cannot be created",
+			pullerErr:   errors.New("oops"),
+		},
+		{
+			description: "heights cannot be retrieved",
+			puller:      &heightsPuller{err: errors.New("oops")},
+		},
+		{
+			description: "a consenter is unreachable",
+			puller:      &heightsPuller{heights: map[string]uint64{"o1": 200, "o2": 200}},
+		},
+		{
+			description:   "all the consenters are up to date",
+			puller:        &heightsPuller{heights: map[string]uint64{"o1": 200, "o2": 200, "o3": 199}},
+			expectedPrune: []uint64{100},
+		},
+		{
+			description:   "a consenter is lagging",
+			puller:        &heightsPuller{heights: map[string]uint64{"o1": 200, "o2": 60, "o3": 200}},
+			expectedPrune: []uint64{60},
+		},
+		{
+			description:        "a consenter is lagging below the first retained block",
+			puller:             &heightsPuller{heights: map[string]uint64{"o1": 200, "o2": 10, "o3": 200}},
+			firstRetainedBlock: 50,
+		},
+	} {
+		testCase := testCase
+		t.Run(testCase.description, func(t *testing.T) {
+			c := &Chain{
+				createPuller: func() (BlockPuller, error) {
+					return testCase.puller, testCase.pullerErr
+				},
+				logger: flogging.MustGetLogger("test"),
+			}
+			pruner := &fakePruner{firstRetainedBlock: testCase.firstRetainedBlock}
+
+			c.prune(pruner, 100, 3)
+			assert.Equal(t, testCase.expectedPrune, pruner.prunedBelow)
+		})
+	}
+}
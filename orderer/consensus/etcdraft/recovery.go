@@ -0,0 +1,547 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"go.etcd.io/etcd/etcdserver/api/snap"
+	"go.etcd.io/etcd/pkg/crc"
+	"go.etcd.io/etcd/raft/raftpb"
+	"go.etcd.io/etcd/wal"
+	"go.etcd.io/etcd/wal/walpb"
+)
+
+// WAL record types, see go.etcd.io/etcd/wal
+const (
+	walMetadataType int64 = iota + 1
+	walEntryType
+	walStateType
+	walCrcType
+	walSnapshotType
+)
+
+// walFrameSizeBytes is the size of the length field preceding each WAL record
+const walFrameSizeBytes = 8
+
+var walCrcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// LedgerState is the state of the block ledger of a channel that corrupted
+// etcd/raft data is repaired from.
+type LedgerState struct {
+	// LastBlock is the last block committed to the ledger.
+	LastBlock *common.Block
+	// AppliedIndex is the raft index of the last block committed to the ledger.
+	AppliedIndex uint64
+	// ConsenterIDs are the raft IDs of the consenters as of the last block.
+	ConsenterIDs []uint64
+}
+
+// EntryID identifies a raft entry.
+type EntryID struct {
+	Index uint64
+	Term  uint64
+}
+
+// RecoveryReport describes the repair of the etcd/raft data of a channel.
+type RecoveryReport struct {
+	// Cause is the error that prevented the data from being loaded.
+	Cause string
+	// CorruptedWAL and CorruptedOffset locate the first unreadable WAL
+	// record, if any.
+	CorruptedWAL    string
+	CorruptedOffset int64
+	// BrokenSnapshots are the snapshot files that could not be read.
+	BrokenSnapshots []string
+	// SnapshotIndex and SnapshotTerm identify the snapshot the repaired log
+	// starts from, if any.
+	SnapshotIndex uint64
+	SnapshotTerm  uint64
+	// SnapshotFromLedger is set when that snapshot was created from the last
+	// block of the ledger.
+	SnapshotFromLedger bool
+	// RecoveredEntries are the entries kept in the repaired WAL.
+	RecoveredEntries []EntryID
+	// DroppedEntries are the entries that were found in the WAL but could not
+	// be kept. Entries with an index greater than the ledger's applied index
+	// are replicated again by the leader once the node rejoins the cluster.
+	DroppedEntries []EntryID
+	// BackupDir is where the original WAL files were moved to.
+	BackupDir string
+}
+
+func (r *RecoveryReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cause: %s", r.Cause)
+	if r.CorruptedWAL != "" {
+		fmt.Fprintf(&b, "; first corrupted WAL record in %s at offset %d", r.CorruptedWAL, r.CorruptedOffset)
+	}
+	if len(r.BrokenSnapshots) != 0 {
+		fmt.Fprintf(&b, "; broken snapshots: %s", strings.Join(r.BrokenSnapshots, ", "))
+	}
+	switch {
+	case r.SnapshotFromLedger:
+		fmt.Fprintf(&b, "; log starts from a snapshot of the ledger at index %d, term %d", r.SnapshotIndex, r.SnapshotTerm)
+	case r.SnapshotIndex != 0:
+		fmt.Fprintf(&b, "; log starts from the snapshot at index %d, term %d", r.SnapshotIndex, r.SnapshotTerm)
+	}
+	fmt.Fprintf(&b, "; recovered entries: %s", formatEntryIDs(r.RecoveredEntries))
+	fmt.Fprintf(&b, "; dropped entries: %s", formatEntryIDs(r.DroppedEntries))
+	if r.BackupDir != "" {
+		fmt.Fprintf(&b, "; original WAL moved to %s", r.BackupDir)
+	}
+	return b.String()
+}
+
+func formatEntryIDs(ids []EntryID) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+	var s []string
+	for _, id := range ids {
+		s = append(s, fmt.Sprintf("%d (term %d)", id.Index, id.Term))
+	}
+	return fmt.Sprintf("%d [%s]", len(ids), strings.Join(s, ", "))
+}
+
+// CreateStorageWithRecovery creates a storage like CreateStorage. When the
+// persisted etcd/raft data cannot be loaded, it repairs it from the intact
+// part of the WAL and snapshots and from the state of the block ledger, and
+// reports what was recovered and dropped.
+func CreateStorageWithRecovery(
+	lg *flogging.FabricLogger,
+	walDir string,
+	snapDir string,
+	ram MemoryStorage,
+	ledger *LedgerState,
+) (*RaftStorage, *RecoveryReport, error) {
+	// the snapshotter renames the snapshot files it cannot read
+	brokenBefore := brokenSnapshots(snapDir)
+	rs, err := CreateStorage(lg, walDir, snapDir, ram)
+	if err == nil {
+		return rs, nil, nil
+	}
+
+	lg.Warnf("Failed to restore raft data, attempting to repair it: %s", err)
+	report, rerr := repairStorage(lg, walDir, snapDir, ledger)
+	if rerr != nil {
+		return nil, nil, errors.WithMessagef(rerr, "failed to repair raft data which could not be restored (%s)", err)
+	}
+	report.Cause = err.Error()
+	report.BrokenSnapshots = newlyBroken(brokenBefore, brokenSnapshots(snapDir))
+
+	rs, err = CreateStorage(lg, walDir, snapDir, ram)
+	if err != nil {
+		return nil, report, errors.WithMessage(err, "failed to restore raft data after repairing it")
+	}
+	return rs, report, nil
+}
+
+// createStorageWithRecovery creates the storage of a chain, repairing its
+// persisted raft data from the ledger of the channel if needed.
+func createStorageWithRecovery(lg *flogging.FabricLogger, support consensus.ConsenterSupport, opts Options) (*RaftStorage, error) {
+	ledger := &LedgerState{
+		LastBlock:    support.Block(support.Height() - 1),
+		AppliedIndex: opts.BlockMetadata.RaftIndex,
+		ConsenterIDs: opts.BlockMetadata.ConsenterIds,
+	}
+
+	storage, report, err := CreateStorageWithRecovery(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage, ledger)
+	if report != nil {
+		lg.Warnf("Repaired raft data: %s", report)
+	}
+	return storage, err
+}
+
+// walScan is the content of a WAL read up to its first corrupted record.
+type walScan struct {
+	metadata  []byte
+	state     raftpb.HardState
+	entries   []raftpb.Entry
+	snapshots []walpb.Snapshot
+
+	crc hash.Hash32
+
+	corruptedFile   string
+	corruptedOffset int64
+	corruption      error
+
+	// entries found past the first corrupted record
+	unreachable []EntryID
+}
+
+// scanWAL reads the records of the WAL files in walDir. Records are read in
+// order until one is found to be corrupted, after which the entries that can
+// still be decoded are only listed.
+func scanWAL(walDir string) (*walScan, error) {
+	names, err := walFileNames(walDir)
+	if err != nil {
+		return nil, err
+	}
+
+	scan := &walScan{crc: crc.New(0, walCrcTable)}
+	for _, name := range names {
+		if err := scan.scanFile(filepath.Join(walDir, name)); err != nil {
+			return nil, err
+		}
+	}
+	return scan, nil
+}
+
+func walFileNames(walDir string) ([]string, error) {
+	dir, err := os.Open(walDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open WAL directory %s", walDir)
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read WAL directory %s", walDir)
+	}
+
+	var walNames []string
+	for _, name := range names {
+		if strings.HasSuffix(name, ".wal") {
+			walNames = append(walNames, name)
+		}
+	}
+	// names are zero-padded hexadecimal sequence and index numbers
+	sort.Strings(walNames)
+	return walNames, nil
+}
+
+func (s *walScan) scanFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open WAL file %s", path)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat WAL file %s", path)
+	}
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		var lenField int64
+		err := binary.Read(r, binary.LittleEndian, &lenField)
+		if err == io.EOF || (err == nil && lenField == 0) {
+			// end of file or preallocated space
+			return nil
+		}
+		if err != nil {
+			s.corrupted(path, offset, err)
+			return nil
+		}
+
+		recBytes, padBytes := walFrameSize(lenField)
+		if recBytes < 0 || offset+walFrameSizeBytes+recBytes+padBytes > info.Size() {
+			s.corrupted(path, offset, io.ErrUnexpectedEOF)
+			return nil
+		}
+		data := make([]byte, recBytes+padBytes)
+		if _, err := io.ReadFull(r, data); err != nil {
+			s.corrupted(path, offset, io.ErrUnexpectedEOF)
+			return nil
+		}
+
+		rec := &walpb.Record{}
+		if err := rec.Unmarshal(data[:recBytes]); err != nil {
+			s.corrupted(path, offset, errors.Wrap(err, "failed to decode record"))
+			return nil
+		}
+
+		if s.corruption == nil {
+			if rec.Type == walCrcType {
+				s.crc = crc.New(rec.Crc, walCrcTable)
+			} else {
+				s.crc.Write(rec.Data)
+				if rec.Crc != s.crc.Sum32() {
+					s.corrupted(path, offset, walpb.ErrCRCMismatch)
+				}
+			}
+		}
+
+		if s.corruption == nil {
+			if err := s.apply(rec); err != nil {
+				s.corrupted(path, offset, err)
+			}
+		} else if rec.Type == walEntryType {
+			// the chain of CRCs is broken, only list what can be decoded
+			var e raftpb.Entry
+			if err := e.Unmarshal(rec.Data); err == nil {
+				s.unreachable = append(s.unreachable, EntryID{Index: e.Index, Term: e.Term})
+			}
+		}
+		offset += walFrameSizeBytes + recBytes + padBytes
+	}
+}
+
+func (s *walScan) corrupted(path string, offset int64, err error) {
+	if s.corruption != nil {
+		return
+	}
+	s.corruptedFile = path
+	s.corruptedOffset = offset
+	s.corruption = err
+}
+
+func (s *walScan) apply(rec *walpb.Record) error {
+	switch rec.Type {
+	case walEntryType:
+		var e raftpb.Entry
+		if err := e.Unmarshal(rec.Data); err != nil {
+			return errors.Wrap(err, "failed to decode entry")
+		}
+		// a later entry with the index of an earlier one replaces it along
+		// with all the entries that follow it
+		if len(s.entries) != 0 {
+			first := s.entries[0].Index
+			switch {
+			case e.Index <= first:
+				s.entries = s.entries[:0]
+			case e.Index <= first+uint64(len(s.entries)):
+				s.entries = s.entries[:e.Index-first]
+			default:
+				// entries are contiguous, a gap means the earlier ones
+				// cannot be used
+				s.entries = s.entries[:0]
+			}
+		}
+		s.entries = append(s.entries, e)
+
+	case walStateType:
+		var st raftpb.HardState
+		if err := st.Unmarshal(rec.Data); err != nil {
+			return errors.Wrap(err, "failed to decode hard state")
+		}
+		s.state = st
+
+	case walMetadataType:
+		s.metadata = rec.Data
+
+	case walSnapshotType:
+		var ws walpb.Snapshot
+		if err := ws.Unmarshal(rec.Data); err != nil {
+			return errors.Wrap(err, "failed to decode snapshot record")
+		}
+		s.snapshots = append(s.snapshots, ws)
+
+	case walCrcType:
+
+	default:
+		return errors.Errorf("unexpected record type %d", rec.Type)
+	}
+	return nil
+}
+
+// termOf returns the term of the entry at index, if it was read.
+func (s *walScan) termOf(index uint64) (uint64, bool) {
+	if len(s.entries) == 0 || index < s.entries[0].Index || index > s.entries[len(s.entries)-1].Index {
+		return 0, false
+	}
+	return s.entries[index-s.entries[0].Index].Term, true
+}
+
+func walFrameSize(lenField int64) (recBytes int64, padBytes int64) {
+	// the record size is stored in the lower 56 bits of the length field and
+	// the padding size in the lower 3 bits of its most significant byte,
+	// whose most significant bit is set when there is padding
+	recBytes = int64(uint64(lenField) & ^(uint64(0xff) << 56))
+	if lenField < 0 {
+		padBytes = int64((uint64(lenField) >> 56) & 0x7)
+	}
+	return recBytes, padBytes
+}
+
+// repairStorage rewrites the WAL of a channel from its intact records, the
+// latest readable snapshot and the ledger. The original WAL files are kept in
+// a backup directory.
+func repairStorage(lg *flogging.FabricLogger, walDir, snapDir string, ledger *LedgerState) (*RecoveryReport, error) {
+	report := &RecoveryReport{}
+
+	sn, err := createSnapshotter(lg, snapDir)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := sn.Load()
+	if err != nil && err != snap.ErrNoSnapshot {
+		return nil, errors.Errorf("failed to load snapshot: %s", err)
+	}
+
+	scan := &walScan{}
+	if wal.Exist(walDir) {
+		if scan, err = scanWAL(walDir); err != nil {
+			return nil, err
+		}
+	}
+	if scan.corruption != nil {
+		report.CorruptedWAL = scan.corruptedFile
+		report.CorruptedOffset = scan.corruptedOffset
+		lg.Warnf("WAL record at offset %d of %s is corrupted: %s", scan.corruptedOffset, scan.corruptedFile, scan.corruption)
+	}
+
+	// select the snapshot the log starts from
+	var base *raftpb.Snapshot
+	if snapshot != nil {
+		base = snapshot
+	}
+	if ledger != nil && ledger.LastBlock != nil && (base == nil || base.Metadata.Index < ledger.AppliedIndex) {
+		// the log needs to reach the ledger, otherwise it is restarted from
+		// a snapshot of the ledger as long as the term of the last applied
+		// entry is known
+		if !logReaches(scan.entries, baseIndex(base), ledger.AppliedIndex) {
+			if term, ok := scan.termOf(ledger.AppliedIndex); ok {
+				base = ledgerSnapshot(ledger, term)
+				report.SnapshotFromLedger = true
+			} else {
+				for _, ws := range scan.snapshots {
+					if ws.Index == ledger.AppliedIndex {
+						base = ledgerSnapshot(ledger, ws.Term)
+						report.SnapshotFromLedger = true
+					}
+				}
+			}
+		}
+	}
+
+	// keep the entries following the snapshot without gap
+	var kept []raftpb.Entry
+	next := baseIndex(base) + 1
+	for _, e := range scan.entries {
+		switch {
+		case e.Index <= baseIndex(base):
+			// covered by the snapshot
+		case e.Index == next:
+			kept = append(kept, e)
+			next++
+		default:
+			report.DroppedEntries = append(report.DroppedEntries, EntryID{Index: e.Index, Term: e.Term})
+		}
+	}
+	report.DroppedEntries = append(report.DroppedEntries, scan.unreachable...)
+	for _, e := range kept {
+		report.RecoveredEntries = append(report.RecoveredEntries, EntryID{Index: e.Index, Term: e.Term})
+	}
+
+	// never claim more than what is left in the log
+	lastIndex, lastTerm := baseIndex(base), uint64(0)
+	if base != nil {
+		lastTerm = base.Metadata.Term
+		report.SnapshotIndex, report.SnapshotTerm = base.Metadata.Index, base.Metadata.Term
+	}
+	if len(kept) != 0 {
+		lastIndex, lastTerm = kept[len(kept)-1].Index, kept[len(kept)-1].Term
+	}
+	st := scan.state
+	if lastTerm > st.Term {
+		st.Term, st.Vote = lastTerm, 0
+	}
+	if st.Commit > lastIndex {
+		st.Commit = lastIndex
+	}
+	if st.Commit < baseIndex(base) {
+		st.Commit = baseIndex(base)
+	}
+
+	if wal.Exist(walDir) {
+		report.BackupDir = fmt.Sprintf("%s.corrupted-%s", walDir, time.Now().UTC().Format("20060102T150405"))
+		if err := os.Rename(walDir, report.BackupDir); err != nil {
+			return nil, errors.Wrapf(err, "failed to move WAL directory %s to %s", walDir, report.BackupDir)
+		}
+		lg.Infof("Moved WAL directory %s to %s", walDir, report.BackupDir)
+	}
+
+	w, err := wal.Create(lg.Zap(), walDir, scan.metadata)
+	if err != nil {
+		return nil, errors.Errorf("failed to create WAL: %s", err)
+	}
+	defer w.Close()
+
+	if base != nil {
+		walsnap := walpb.Snapshot{Index: base.Metadata.Index, Term: base.Metadata.Term}
+		if err := w.SaveSnapshot(walsnap); err != nil {
+			return nil, errors.Errorf("failed to save snapshot to WAL: %s", err)
+		}
+		if report.SnapshotFromLedger {
+			if err := sn.SaveSnap(*base); err != nil {
+				return nil, errors.Errorf("failed to save snapshot to disk: %s", err)
+			}
+		}
+	}
+	if err := w.Save(st, kept); err != nil {
+		return nil, errors.Errorf("failed to save entries to WAL: %s", err)
+	}
+
+	return report, nil
+}
+
+func baseIndex(s *raftpb.Snapshot) uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.Metadata.Index
+}
+
+// logReaches tells whether the entries follow the snapshot at index from and
+// reach index to.
+func logReaches(entries []raftpb.Entry, from, to uint64) bool {
+	if len(entries) == 0 {
+		return from >= to
+	}
+	first, last := entries[0].Index, entries[len(entries)-1].Index
+	return first <= from+1 && last >= to
+}
+
+// ledgerSnapshot creates a snapshot of the ledger at its applied index.
+func ledgerSnapshot(ledger *LedgerState, term uint64) *raftpb.Snapshot {
+	return &raftpb.Snapshot{
+		Data: protoutil.MarshalOrPanic(ledger.LastBlock),
+		Metadata: raftpb.SnapshotMetadata{
+			Index:     ledger.AppliedIndex,
+			Term:      term,
+			ConfState: raftpb.ConfState{Nodes: ledger.ConsenterIDs},
+		},
+	}
+}
+
+func brokenSnapshots(snapDir string) map[string]struct{} {
+	broken := map[string]struct{}{}
+	names, _ := filepath.Glob(filepath.Join(snapDir, "*.broken"))
+	for _, name := range names {
+		broken[name] = struct{}{}
+	}
+	return broken
+}
+
+func newlyBroken(before, after map[string]struct{}) []string {
+	var names []string
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, strings.TrimSuffix(name, ".broken"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft"
+	"go.etcd.io/etcd/raft/raftpb"
+	"go.etcd.io/etcd/wal"
+)
+
+func storeTestEntries(t *testing.T, from, to uint64) {
+	for i := from; i <= to; i++ {
+		err := store.Store(
+			[]raftpb.Entry{{Index: i, Term: 1, Data: []byte(fmt.Sprintf("entry-%03d", i))}},
+			raftpb.HardState{Term: 1, Commit: i},
+			raftpb.Snapshot{},
+		)
+		require.NoError(t, err)
+	}
+}
+
+func entryIDs(from, to uint64) []EntryID {
+	var ids []EntryID
+	for i := from; i <= to; i++ {
+		ids = append(ids, EntryID{Index: i, Term: 1})
+	}
+	return ids
+}
+
+func corruptWALEntry(t *testing.T, index uint64) {
+	walFiles, err := filepath.Glob(filepath.Join(walDir, "*.wal"))
+	require.NoError(t, err)
+	pattern := []byte(fmt.Sprintf("entry-%03d", index))
+	for _, walFile := range walFiles {
+		content, err := ioutil.ReadFile(walFile)
+		require.NoError(t, err)
+		if i := bytes.Index(content, pattern); i >= 0 {
+			content[i] = 'E'
+			require.NoError(t, ioutil.WriteFile(walFile, content, 0600))
+			return
+		}
+	}
+	t.Fatalf("entry %d not found in WAL", index)
+}
+
+func TestCreateStorageWithRecoveryIntact(t *testing.T) {
+	setup(t)
+	defer clean(t)
+
+	storeTestEntries(t, 1, 10)
+	require.NoError(t, store.Close())
+
+	ram = raft.NewMemoryStorage()
+	var report *RecoveryReport
+	store, report, err = CreateStorageWithRecovery(logger, walDir, snapDir, ram, &LedgerState{})
+	require.NoError(t, err)
+	assert.Nil(t, report)
+	lastIndex, _ := ram.LastIndex()
+	assert.Equal(t, uint64(10), lastIndex)
+}
+
+func TestCreateStorageWithRecoveryCorruptedEntry(t *testing.T) {
+	setup(t)
+	defer clean(t)
+
+	storeTestEntries(t, 1, 20)
+	require.NoError(t, store.Close())
+	corruptWALEntry(t, 10)
+
+	_, err = CreateStorage(logger, walDir, snapDir, raft.NewMemoryStorage())
+	require.EqualError(t, err, "failed to create or read WAL: failed to read WAL and cannot repair: walpb: crc mismatch")
+
+	ram = raft.NewMemoryStorage()
+	var report *RecoveryReport
+	store, report, err = CreateStorageWithRecovery(logger, walDir, snapDir, ram, &LedgerState{
+		LastBlock:    protoutil.NewBlock(3, nil),
+		AppliedIndex: 5,
+		ConsenterIDs: []uint64{1},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	assert.Equal(t, "failed to create or read WAL: failed to read WAL and cannot repair: walpb: crc mismatch", report.Cause)
+	assert.NotEmpty(t, report.CorruptedWAL)
+	assert.False(t, report.SnapshotFromLedger)
+	assert.Equal(t, entryIDs(1, 9), report.RecoveredEntries)
+	assert.Equal(t, entryIDs(10, 20), report.DroppedEntries)
+	assert.DirExists(t, report.BackupDir)
+	assert.Contains(t, report.String(), "dropped entries: 11 [10 (term 1), 11 (term 1)")
+
+	lastIndex, _ := ram.LastIndex()
+	assert.Equal(t, uint64(9), lastIndex)
+	hs, _, _ := ram.InitialState()
+	assert.Equal(t, raftpb.HardState{Term: 1, Commit: 9}, hs)
+
+	// the repaired WAL can be appended to and read again
+	storeTestEntries(t, 10, 12)
+	require.NoError(t, store.Close())
+	ram = raft.NewMemoryStorage()
+	store, err = CreateStorage(logger, walDir, snapDir, ram)
+	require.NoError(t, err)
+	lastIndex, _ = ram.LastIndex()
+	assert.Equal(t, uint64(12), lastIndex)
+}
+
+func TestCreateStorageWithRecoveryFromLedger(t *testing.T) {
+	backup := MaxSnapshotFiles
+	MaxSnapshotFiles = 1
+	defer func() { MaxSnapshotFiles = backup }()
+	oldSegmentSizeBytes := wal.SegmentSizeBytes
+	wal.SegmentSizeBytes = 10
+	defer func() { wal.SegmentSizeBytes = oldSegmentSizeBytes }()
+
+	setup(t)
+	defer clean(t)
+
+	storeTestEntries(t, 1, 10)
+	block := protoutil.NewBlock(3, nil)
+	for _, i := range []uint64{3, 5} {
+		err = store.TakeSnapshot(i, raftpb.ConfState{Nodes: []uint64{1}}, protoutil.MarshalOrPanic(block))
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.Close())
+
+	// the only snapshot left is broken, and the WAL files preceding it are gone
+	snapFiles, err := filepath.Glob(filepath.Join(snapDir, "*.snap"))
+	require.NoError(t, err)
+	require.Len(t, snapFiles, 1)
+	require.NoError(t, ioutil.WriteFile(snapFiles[0], []byte("garbage"), 0600))
+
+	ram = raft.NewMemoryStorage()
+	lastBlock := protoutil.NewBlock(4, nil)
+	var report *RecoveryReport
+	store, report, err = CreateStorageWithRecovery(logger, walDir, snapDir, ram, &LedgerState{
+		LastBlock:    lastBlock,
+		AppliedIndex: 7,
+		ConsenterIDs: []uint64{1, 2, 3},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	assert.Equal(t, snapFiles, report.BrokenSnapshots)
+	assert.True(t, report.SnapshotFromLedger)
+	assert.Equal(t, uint64(7), report.SnapshotIndex)
+	assert.Equal(t, uint64(1), report.SnapshotTerm)
+	assert.Equal(t, entryIDs(8, 10), report.RecoveredEntries)
+	assert.Empty(t, report.DroppedEntries)
+
+	snapshot := store.Snapshot()
+	assert.Equal(t, uint64(7), snapshot.Metadata.Index)
+	assert.Equal(t, []uint64{1, 2, 3}, snapshot.Metadata.ConfState.Nodes)
+	snapBlock := &common.Block{}
+	require.NoError(t, snapBlock.XXX_Unmarshal(snapshot.Data))
+	assert.Equal(t, uint64(4), snapBlock.Header.Number)
+	firstIndex, _ := ram.FirstIndex()
+	lastIndex, _ := ram.LastIndex()
+	assert.Equal(t, uint64(8), firstIndex)
+	assert.Equal(t, uint64(10), lastIndex)
+}
+
+func TestCreateStorageWithRecoveryNoWAL(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "etcdraft-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+
+	// an unreadable snapshot directory is recovered with an empty log
+	snapDir := path.Join(dataDir, "snapshot")
+	require.NoError(t, os.MkdirAll(snapDir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(snapDir, "0000000000000001-0000000000000005.snap"), []byte("garbage"), 0600))
+
+	ram := raft.NewMemoryStorage()
+	rs, report, err := CreateStorageWithRecovery(logger, path.Join(dataDir, "wal"), snapDir, ram, &LedgerState{})
+	require.NoError(t, err)
+	defer rs.Close()
+	assert.Nil(t, report)
+	lastIndex, _ := ram.LastIndex()
+	assert.Equal(t, uint64(0), lastIndex)
+}
@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package etcdraft
 
 import (
+	"bytes"
 	"encoding/pem"
 	"github.com/cetcxinlian/cryptogm/x509"
+	"sort"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -431,34 +433,69 @@ func NodeExists(id uint64, nodes []uint64) bool {
 }
 
 // ConfChange computes Raft configuration changes based on current Raft
-// configuration state and consenters IDs stored in RaftMetadata.
-func ConfChange(blockMetadata *etcdraft.BlockMetadata, confState *raftpb.ConfState) *raftpb.ConfChange {
+// configuration state, consenters IDs stored in RaftMetadata and the IDs
+// of the learners. It returns nil if the Raft configuration state is in sync.
+func ConfChange(blockMetadata *etcdraft.BlockMetadata, confState *raftpb.ConfState, learners []uint64) *raftpb.ConfChange {
 	raftConfChange := &raftpb.ConfChange{}
 
+	members := append(append([]uint64{}, confState.Nodes...), confState.Learners...)
+
 	// need to compute conf changes to propose
-	if len(confState.Nodes) < len(blockMetadata.ConsenterIds) {
+	switch {
+	case len(members) < len(blockMetadata.ConsenterIds):
 		// adding new node
 		raftConfChange.Type = raftpb.ConfChangeAddNode
 		for _, consenterID := range blockMetadata.ConsenterIds {
-			if NodeExists(consenterID, confState.Nodes) {
+			if NodeExists(consenterID, members) {
 				continue
 			}
 			raftConfChange.NodeID = consenterID
+			if NodeExists(consenterID, learners) {
+				raftConfChange.Type = raftpb.ConfChangeAddLearnerNode
+			}
 		}
-	} else {
+	case len(members) > len(blockMetadata.ConsenterIds):
 		// removing node
 		raftConfChange.Type = raftpb.ConfChangeRemoveNode
-		for _, nodeID := range confState.Nodes {
+		for _, nodeID := range members {
 			if NodeExists(nodeID, blockMetadata.ConsenterIds) {
 				continue
 			}
 			raftConfChange.NodeID = nodeID
 		}
+	default:
+		// promoting learner
+		raftConfChange.Type = raftpb.ConfChangeAddNode
+		for _, nodeID := range confState.Learners {
+			if NodeExists(nodeID, learners) {
+				continue
+			}
+			raftConfChange.NodeID = nodeID
+		}
+		if raftConfChange.NodeID == raft.None {
+			return nil
+		}
 	}
 
 	return raftConfChange
 }
 
+// LearnerIDs returns the sorted Raft node IDs of the consenters whose client TLS
+// certificates are listed as learners.
+func LearnerIDs(consenters map[uint64]*etcdraft.Consenter, learnerCerts [][]byte) []uint64 {
+	var ids []uint64
+	for nodeID, c := range consenters {
+		for _, cert := range learnerCerts {
+			if bytes.Equal(c.ClientTlsCert, cert) {
+				ids = append(ids, nodeID)
+				break
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
 // CreateConsentersMap creates a map of Raft Node IDs to Consenter given the block metadata and the config metadata.
 func CreateConsentersMap(blockMetadata *etcdraft.BlockMetadata, configMetadata *etcdraft.ConfigMetadata) map[uint64]*etcdraft.Consenter {
 	consenters := map[uint64]*etcdraft.Consenter{}
@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft/raftpb"
 )
 
 const (
@@ -418,3 +419,34 @@ func TestVerifyConfigMetadata(t *testing.T) {
 
 	assert.Nil(t, VerifyConfigMetadata(metadataWithExpiredConsenter, expiredCertVerifyOpts))
 }
+
+func TestConfChange(t *testing.T) {
+	blockMetadata := &etcdraftproto.BlockMetadata{ConsenterIds: []uint64{1, 2, 3, 4}}
+
+	cc := ConfChange(blockMetadata, &raftpb.ConfState{Nodes: []uint64{1, 2, 3}}, nil)
+	assert.Equal(t, &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddNode}, cc)
+
+	cc = ConfChange(blockMetadata, &raftpb.ConfState{Nodes: []uint64{1, 2, 3}}, []uint64{4})
+	assert.Equal(t, &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddLearnerNode}, cc)
+
+	cc = ConfChange(blockMetadata, &raftpb.ConfState{Nodes: []uint64{1, 2, 3}, Learners: []uint64{4}}, nil)
+	assert.Equal(t, &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddNode}, cc)
+
+	cc = ConfChange(blockMetadata, &raftpb.ConfState{Nodes: []uint64{1, 2, 3}, Learners: []uint64{4, 5}}, []uint64{4})
+	assert.Equal(t, &raftpb.ConfChange{NodeID: 5, Type: raftpb.ConfChangeRemoveNode}, cc)
+
+	assert.Nil(t, ConfChange(blockMetadata, &raftpb.ConfState{Nodes: []uint64{1, 2, 3}, Learners: []uint64{4}}, []uint64{4}))
+	assert.Nil(t, ConfChange(blockMetadata, &raftpb.ConfState{Nodes: []uint64{1, 2, 3, 4}}, nil))
+}
+
+func TestLearnerIDs(t *testing.T) {
+	consenters := map[uint64]*etcdraftproto.Consenter{
+		1: {Host: "orderer1.example.com", Port: 7050, ClientTlsCert: []byte("cert1")},
+		2: {Host: "orderer2.example.com", Port: 7050, ClientTlsCert: []byte("cert2")},
+		3: {Host: "orderer3.example.com", Port: 7050, ClientTlsCert: []byte("cert3")},
+	}
+
+	assert.Empty(t, LearnerIDs(consenters, nil))
+	assert.Equal(t, []uint64{2, 3}, LearnerIDs(consenters, [][]byte{[]byte("cert3"), []byte("cert2")}))
+	assert.Empty(t, LearnerIDs(consenters, [][]byte{[]byte("cert4")}))
+}
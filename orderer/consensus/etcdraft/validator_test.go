@@ -281,6 +281,62 @@ var _ = Describe("Metadata Validation", func() {
 				Expect(chain.ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig, newChannel)).To(Succeed())
 			})
 
+			Context("adding a raft learner", func() {
+				var newMetadata etcdraftproto.ConfigMetadata
+
+				BeforeEach(func() {
+					newMetadata = metadata
+					learner := &etcdraftproto.Consenter{
+						Host:          "host4",
+						Port:          10004,
+						ClientTlsCert: clientTLSCert(tlsCA),
+						ServerTlsCert: serverTLSCert(tlsCA),
+					}
+					newMetadata.Consenters = append(newMetadata.Consenters, learner)
+					newMetadata.LearnerClientTlsCerts = [][]byte{learner.ClientTlsCert}
+					newBytes, err := proto.Marshal(&newMetadata)
+					Expect(err).NotTo(HaveOccurred())
+					newOrdererConfig.ConsensusMetadataReturns(newBytes)
+				})
+
+				It("succeeds when the raft learners capability is enabled", func() {
+					ordererCapabilities := &mocks.OrdererCapabilities{}
+					ordererCapabilities.RaftLearnersReturns(true)
+					newOrdererConfig.CapabilitiesReturns(ordererCapabilities)
+					Expect(chain.ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig, newChannel)).To(Succeed())
+				})
+
+				It("fails when the raft learners capability is not enabled", func() {
+					newOrdererConfig.CapabilitiesReturns(&mocks.OrdererCapabilities{})
+					err := chain.ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig, newChannel)
+					Expect(err).To(MatchError("raft learners require the V2_2_RAFT_LEARNERS orderer capability"))
+				})
+			})
+
+			Context("setting a block retention", func() {
+				BeforeEach(func() {
+					newMetadata := metadata
+					newMetadata.Options = proto.Clone(metadata.Options).(*etcdraftproto.Options)
+					newMetadata.Options.RetainedBlocks = 1000
+					newBytes, err := proto.Marshal(&newMetadata)
+					Expect(err).NotTo(HaveOccurred())
+					newOrdererConfig.ConsensusMetadataReturns(newBytes)
+				})
+
+				It("succeeds when the block retention capability is enabled", func() {
+					ordererCapabilities := &mocks.OrdererCapabilities{}
+					ordererCapabilities.BlockRetentionReturns(true)
+					newOrdererConfig.CapabilitiesReturns(ordererCapabilities)
+					Expect(chain.ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig, newChannel)).To(Succeed())
+				})
+
+				It("fails when the block retention capability is not enabled", func() {
+					newOrdererConfig.CapabilitiesReturns(&mocks.OrdererCapabilities{})
+					err := chain.ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig, newChannel)
+					Expect(err).To(MatchError("block retention requires the V2_2_BLOCK_RETENTION orderer capability"))
+				})
+			})
+
 			It("fails on addition of more than one consenter", func() {
 				newMetadata := metadata
 				newMetadata.Consenters = append(newMetadata.Consenters,
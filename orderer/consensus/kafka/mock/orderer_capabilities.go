@@ -6,6 +6,16 @@ import (
 )
 
 type OrdererCapabilities struct {
+	BlockRetentionStub        func() bool
+	blockRetentionMutex       sync.RWMutex
+	blockRetentionArgsForCall []struct {
+	}
+	blockRetentionReturns struct {
+		result1 bool
+	}
+	blockRetentionReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ConsensusTypeMigrationStub        func() bool
 	consensusTypeMigrationMutex       sync.RWMutex
 	consensusTypeMigrationArgsForCall []struct {
@@ -36,6 +46,16 @@ type OrdererCapabilities struct {
 	predictableChannelTemplateReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	RaftLearnersStub        func() bool
+	raftLearnersMutex       sync.RWMutex
+	raftLearnersArgsForCall []struct {
+	}
+	raftLearnersReturns struct {
+		result1 bool
+	}
+	raftLearnersReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ResubmissionStub        func() bool
 	resubmissionMutex       sync.RWMutex
 	resubmissionArgsForCall []struct {
@@ -70,6 +90,58 @@ type OrdererCapabilities struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *OrdererCapabilities) BlockRetention() bool {
+	fake.blockRetentionMutex.Lock()
+	ret, specificReturn := fake.blockRetentionReturnsOnCall[len(fake.blockRetentionArgsForCall)]
+	fake.blockRetentionArgsForCall = append(fake.blockRetentionArgsForCall, struct {
+	}{})
+	fake.recordInvocation("BlockRetention", []interface{}{})
+	fake.blockRetentionMutex.Unlock()
+	if fake.BlockRetentionStub != nil {
+		return fake.BlockRetentionStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.blockRetentionReturns
+	return fakeReturns.result1
+}
+
+func (fake *OrdererCapabilities) BlockRetentionCallCount() int {
+	fake.blockRetentionMutex.RLock()
+	defer fake.blockRetentionMutex.RUnlock()
+	return len(fake.blockRetentionArgsForCall)
+}
+
+func (fake *OrdererCapabilities) BlockRetentionCalls(stub func() bool) {
+	fake.blockRetentionMutex.Lock()
+	defer fake.blockRetentionMutex.Unlock()
+	fake.BlockRetentionStub = stub
+}
+
+func (fake *OrdererCapabilities) BlockRetentionReturns(result1 bool) {
+	fake.blockRetentionMutex.Lock()
+	defer fake.blockRetentionMutex.Unlock()
+	fake.BlockRetentionStub = nil
+	fake.blockRetentionReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *OrdererCapabilities) BlockRetentionReturnsOnCall(i int, result1 bool) {
+	fake.blockRetentionMutex.Lock()
+	defer fake.blockRetentionMutex.Unlock()
+	fake.BlockRetentionStub = nil
+	if fake.blockRetentionReturnsOnCall == nil {
+		fake.blockRetentionReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.blockRetentionReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *OrdererCapabilities) ConsensusTypeMigration() bool {
 	fake.consensusTypeMigrationMutex.Lock()
 	ret, specificReturn := fake.consensusTypeMigrationReturnsOnCall[len(fake.consensusTypeMigrationArgsForCall)]
@@ -226,6 +298,58 @@ func (fake *OrdererCapabilities) PredictableChannelTemplateReturnsOnCall(i int,
 	}{result1}
 }
 
+func (fake *OrdererCapabilities) RaftLearners() bool {
+	fake.raftLearnersMutex.Lock()
+	ret, specificReturn := fake.raftLearnersReturnsOnCall[len(fake.raftLearnersArgsForCall)]
+	fake.raftLearnersArgsForCall = append(fake.raftLearnersArgsForCall, struct {
+	}{})
+	fake.recordInvocation("RaftLearners", []interface{}{})
+	fake.raftLearnersMutex.Unlock()
+	if fake.RaftLearnersStub != nil {
+		return fake.RaftLearnersStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.raftLearnersReturns
+	return fakeReturns.result1
+}
+
+func (fake *OrdererCapabilities) RaftLearnersCallCount() int {
+	fake.raftLearnersMutex.RLock()
+	defer fake.raftLearnersMutex.RUnlock()
+	return len(fake.raftLearnersArgsForCall)
+}
+
+func (fake *OrdererCapabilities) RaftLearnersCalls(stub func() bool) {
+	fake.raftLearnersMutex.Lock()
+	defer fake.raftLearnersMutex.Unlock()
+	fake.RaftLearnersStub = stub
+}
+
+func (fake *OrdererCapabilities) RaftLearnersReturns(result1 bool) {
+	fake.raftLearnersMutex.Lock()
+	defer fake.raftLearnersMutex.Unlock()
+	fake.RaftLearnersStub = nil
+	fake.raftLearnersReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *OrdererCapabilities) RaftLearnersReturnsOnCall(i int, result1 bool) {
+	fake.raftLearnersMutex.Lock()
+	defer fake.raftLearnersMutex.Unlock()
+	fake.RaftLearnersStub = nil
+	if fake.raftLearnersReturnsOnCall == nil {
+		fake.raftLearnersReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.raftLearnersReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *OrdererCapabilities) Resubmission() bool {
 	fake.resubmissionMutex.Lock()
 	ret, specificReturn := fake.resubmissionReturnsOnCall[len(fake.resubmissionArgsForCall)]
@@ -385,12 +509,16 @@ func (fake *OrdererCapabilities) UseChannelCreationPolicyAsAdminsReturnsOnCall(i
 func (fake *OrdererCapabilities) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.blockRetentionMutex.RLock()
+	defer fake.blockRetentionMutex.RUnlock()
 	fake.consensusTypeMigrationMutex.RLock()
 	defer fake.consensusTypeMigrationMutex.RUnlock()
 	fake.expirationCheckMutex.RLock()
 	defer fake.expirationCheckMutex.RUnlock()
 	fake.predictableChannelTemplateMutex.RLock()
 	defer fake.predictableChannelTemplateMutex.RUnlock()
+	fake.raftLearnersMutex.RLock()
+	defer fake.raftLearnersMutex.RUnlock()
 	fake.resubmissionMutex.RLock()
 	defer fake.resubmissionMutex.RUnlock()
 	fake.supportedMutex.RLock()
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensus
+
+// QuorumStatus describes whether a chain is currently able to order
+// transactions, i.e. whether its consenter set has quorum.
+type QuorumStatus struct {
+	// HasQuorum is true when the chain can currently reach consensus.
+	HasQuorum bool `json:"hasQuorum"`
+	// Leader is the ID of the last known leader, or zero when unknown or not applicable.
+	Leader uint64 `json:"leader,omitempty"`
+	// ActiveNodes is the number of consenters known to be reachable, when applicable.
+	ActiveNodes int `json:"activeNodes,omitempty"`
+	// TotalNodes is the size of the consenter set, when applicable.
+	TotalNodes int `json:"totalNodes,omitempty"`
+	// Reason explains why the chain has no quorum.
+	Reason string `json:"reason,omitempty"`
+}
+
+// QuorumReporter is implemented by Chain implementations that are able to
+// tell whether the consenter set they belong to has quorum, beyond the
+// liveness of the process.
+//
+// Not all chains must implement this, chains that do not are assigned an
+// ErroredQuorumReporter at construction time.
+type QuorumReporter interface {
+	// QuorumStatus reports the current quorum status of the chain.
+	QuorumStatus() QuorumStatus
+}
+
+// ErroredQuorumReporter is intended for chains that do not implement the QuorumReporter interface.
+// It considers the chain to have quorum as long as it is not in an errored state.
+type ErroredQuorumReporter struct {
+	Chain Chain
+}
+
+func (e ErroredQuorumReporter) QuorumStatus() QuorumStatus {
+	select {
+	case <-e.Chain.Errored():
+		return QuorumStatus{Reason: "chain is in an errored state"}
+	default:
+		return QuorumStatus{HasQuorum: true}
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensus_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/inactive"
+	"github.com/stretchr/testify/assert"
+)
+
+type healthyChain struct {
+	*inactive.Chain
+}
+
+func (healthyChain) Errored() <-chan struct{} {
+	return nil
+}
+
+func TestErroredQuorumReporter(t *testing.T) {
+	var qr consensus.QuorumReporter = consensus.ErroredQuorumReporter{Chain: healthyChain{}} // make sure it implements this interface
+	assert.Equal(t, consensus.QuorumStatus{HasQuorum: true}, qr.QuorumStatus())
+
+	qr = consensus.ErroredQuorumReporter{Chain: &inactive.Chain{}}
+	assert.Equal(t, consensus.QuorumStatus{Reason: "chain is in an errored state"}, qr.QuorumStatus())
+}
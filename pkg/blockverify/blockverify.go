@@ -0,0 +1,292 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockverify verifies the blocks of a channel the way the peers and
+// the orderers of the channel do, so that light clients and block explorers
+// don't duplicate the verification logic.  A Verifier is created from a
+// config block of the channel obtained from a trusted source, and verifies
+// the blocks that follow it, in order: the hash of their data, the linkage
+// of their headers and the signatures of the orderers against the block
+// validation policy of the channel.  The config blocks it verifies update
+// the config it verifies the next blocks with, once their config update is
+// authorized by the policies of the previous config.
+//
+// The signatures are verified with a software crypto provider supporting
+// both the SM2 and the ECDSA identities, unless another crypto provider is
+// supplied.
+package blockverify
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// Verifier verifies the blocks of a channel, in order, starting from a
+// trusted config block.  It is not safe for concurrent use.
+type Verifier struct {
+	cryptoProvider bccsp.BCCSP
+	bundle         *channelconfig.Bundle
+	header         *cb.BlockHeader
+}
+
+// New returns a Verifier of the blocks following the given config block.
+func New(configBlock *cb.Block) (*Verifier, error) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create crypto provider")
+	}
+	return NewWithCryptoProvider(configBlock, cryptoProvider)
+}
+
+// NewWithCryptoProvider returns a Verifier of the blocks following the given
+// config block, which verifies the signatures with the given crypto provider.
+func NewWithCryptoProvider(configBlock *cb.Block, cryptoProvider bccsp.BCCSP) (*Verifier, error) {
+	if configBlock == nil || configBlock.Header == nil {
+		return nil, errors.New("config block has no header")
+	}
+	if err := VerifyDataHash(configBlock); err != nil {
+		return nil, err
+	}
+	if !protoutil.IsConfigBlock(configBlock) {
+		return nil, errors.Errorf("block [%d] is not a config block", configBlock.Header.Number)
+	}
+	bundle, err := bundleFromBlock(configBlock, cryptoProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{
+		cryptoProvider: cryptoProvider,
+		bundle:         bundle,
+		header:         configBlock.Header,
+	}, nil
+}
+
+// ChannelID returns the ID of the channel of the blocks.
+func (v *Verifier) ChannelID() string {
+	return v.bundle.ConfigtxValidator().ChannelID()
+}
+
+// Height returns the number of the next block to verify.
+func (v *Verifier) Height() uint64 {
+	return v.header.Number + 1
+}
+
+// Config returns the channel config the next block is verified with.
+func (v *Verifier) Config() *channelconfig.Bundle {
+	return v.bundle
+}
+
+// VerifyBlock verifies the next block of the channel and, if it is a config
+// block, verifies and applies its config to the blocks that follow.
+func (v *Verifier) VerifyBlock(block *cb.Block) error {
+	if block == nil || block.Header == nil {
+		return errors.New("block has no header")
+	}
+	if block.Header.Number != v.Height() {
+		return errors.Errorf("expected block [%d] but got block [%d]", v.Height(), block.Header.Number)
+	}
+	if err := VerifyHeaderChain([]*cb.BlockHeader{v.header, block.Header}); err != nil {
+		return err
+	}
+	if err := VerifyDataHash(block); err != nil {
+		return err
+	}
+	if err := v.VerifyBlockSignatures(block); err != nil {
+		return err
+	}
+
+	if protoutil.IsConfigBlock(block) {
+		bundle, err := v.nextBundle(block)
+		if err != nil {
+			return errors.WithMessagef(err, "invalid config in block [%d]", block.Header.Number)
+		}
+		v.bundle = bundle
+	}
+	v.header = block.Header
+	return nil
+}
+
+// VerifyBlocks verifies the next blocks of the channel, in order.
+func (v *Verifier) VerifyBlocks(blocks []*cb.Block) error {
+	for _, block := range blocks {
+		if err := v.VerifyBlock(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyBlockSignatures verifies that the signatures of the block satisfy the
+// block validation policy of the current config.  Unlike VerifyBlock, it
+// neither checks the hashes of the block nor changes the state of the
+// Verifier.
+func (v *Verifier) VerifyBlockSignatures(block *cb.Block) error {
+	signatures, err := BlockSignatures(block)
+	if err != nil {
+		return err
+	}
+	if err := v.VerifyPolicy(policies.BlockValidation, signatures); err != nil {
+		return errors.WithMessagef(err, "block [%d] signatures are invalid", block.Header.Number)
+	}
+	return nil
+}
+
+// VerifyPolicy verifies that the signed data satisfies the policy of the
+// current config with the given path, such as /Channel/Application/Writers.
+func (v *Verifier) VerifyPolicy(policyPath string, signatures []*protoutil.SignedData) error {
+	policy, ok := v.bundle.PolicyManager().GetPolicy(policyPath)
+	if !ok {
+		return errors.Errorf("policy %s not found", policyPath)
+	}
+	return policy.EvaluateSignedData(signatures)
+}
+
+// VerifyBlockRange verifies that the block carries a range attestation
+// signed according to the block validation policy of the current config, and
+// that the headers, given in order, are the headers of the attested range.
+func (v *Verifier) VerifyBlockRange(block *cb.Block, headers []*cb.BlockHeader) error {
+	attestation, signatures, err := protoutil.GetBlockRangeAttestation(block)
+	if err != nil {
+		return err
+	}
+	if attestation == nil {
+		return errors.Errorf("block [%d] carries no range attestation", block.Header.Number)
+	}
+	if err := v.VerifyPolicy(policies.BlockValidation, signatures); err != nil {
+		return errors.WithMessagef(err, "block [%d] range attestation signatures are invalid", block.Header.Number)
+	}
+	return protoutil.VerifyBlockRange(attestation, headers)
+}
+
+func (v *Verifier) nextBundle(block *cb.Block) (*channelconfig.Bundle, error) {
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	configEnv := &cb.ConfigEnvelope{}
+	if _, err := protoutil.UnmarshalEnvelopeOfType(env, cb.HeaderType_CONFIG, configEnv); err != nil {
+		return nil, err
+	}
+	if err := v.bundle.ConfigtxValidator().Validate(configEnv); err != nil {
+		return nil, err
+	}
+	bundle, err := channelconfig.NewBundle(v.ChannelID(), configEnv.Config, v.cryptoProvider)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.bundle.ValidateNew(bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func bundleFromBlock(block *cb.Block, cryptoProvider bccsp.BCCSP) (*channelconfig.Bundle, error) {
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to extract config envelope")
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env, cryptoProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load channel config")
+	}
+	return bundle, nil
+}
+
+// BlockSignatures returns the signed data of the signatures of the orderers
+// over the block.
+func BlockSignatures(block *cb.Block) ([]*protoutil.SignedData, error) {
+	if block.Header == nil {
+		return nil, errors.New("block has no header")
+	}
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_SIGNATURES) {
+		return nil, errors.Errorf("block [%d] has no signatures", block.Header.Number)
+	}
+	md, err := protoutil.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to unmarshal signatures of block [%d]", block.Header.Number)
+	}
+
+	var signatures []*protoutil.SignedData
+	for _, mdSignature := range md.Signatures {
+		shdr, err := protoutil.UnmarshalSignatureHeader(mdSignature.SignatureHeader)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to unmarshal signature header of block [%d]", block.Header.Number)
+		}
+		signatures = append(signatures, &protoutil.SignedData{
+			Identity:  shdr.Creator,
+			Data:      bytes.Join([][]byte{md.Value, mdSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header)}, nil),
+			Signature: mdSignature.Signature,
+		})
+	}
+	if len(signatures) == 0 {
+		return nil, errors.Errorf("block [%d] is not signed", block.Header.Number)
+	}
+	return signatures, nil
+}
+
+// VerifyDataHash verifies that the data hash in the header of the block is
+// the hash of its data.
+func VerifyDataHash(block *cb.Block) error {
+	if block.Header == nil {
+		return errors.New("block has no header")
+	}
+	if block.Data == nil {
+		return errors.Errorf("block [%d] has no data", block.Header.Number)
+	}
+	if dataHash := protoutil.BlockDataHash(block.Data); !bytes.Equal(dataHash, block.Header.DataHash) {
+		return errors.Errorf("computed data hash of block [%d] (%s) doesn't match claimed hash (%s)",
+			block.Header.Number, hex.EncodeToString(dataHash), hex.EncodeToString(block.Header.DataHash))
+	}
+	return nil
+}
+
+// VerifyHeaderChain verifies that the headers, given in order, are the
+// headers of consecutive blocks, each linked to the previous one by its hash.
+func VerifyHeaderChain(headers []*cb.BlockHeader) error {
+	for i, header := range headers {
+		if header == nil {
+			return errors.Errorf("header at position %d is nil", i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := headers[i-1]
+		if header.Number != prev.Number+1 {
+			return errors.Errorf("block [%d] doesn't follow block [%d]", header.Number, prev.Number)
+		}
+		if prevHash := protoutil.BlockHeaderHash(prev); !bytes.Equal(header.PreviousHash, prevHash) {
+			return errors.Errorf("previous hash of block [%d] (%s) doesn't match the hash of block [%d] (%s)",
+				header.Number, hex.EncodeToString(header.PreviousHash), prev.Number, hex.EncodeToString(prevHash))
+		}
+	}
+	return nil
+}
+
+// CommitHash returns the commit hash a peer added to the block when it
+// committed it, or nil if the peer didn't add one.  Each commit hash covers
+// the validation result and the state updates of the block, chained to the
+// commit hash of the previous block, so the same commit hash returned by
+// peers of different organizations attests that they committed the same
+// state up to the block.  It can't be computed from the block alone.
+func CommitHash(block *cb.Block) ([]byte, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_COMMIT_HASH) ||
+		len(block.Metadata.Metadata[cb.BlockMetadataIndex_COMMIT_HASH]) == 0 {
+		return nil, nil
+	}
+	md, err := protoutil.GetMetadataFromBlock(block, cb.BlockMetadataIndex_COMMIT_HASH)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal commit hash")
+	}
+	return md.Value, nil
+}
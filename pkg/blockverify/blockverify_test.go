@@ -0,0 +1,309 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockverify_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/internal/configtxlator/update"
+	"github.com/hyperledger/fabric/internal/cryptogen/ca"
+	cryptogenmsp "github.com/hyperledger/fabric/internal/cryptogen/msp"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/pkg/blockverify"
+	"github.com/hyperledger/fabric/pkg/configtx"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type network struct {
+	genesisBlock *cb.Block
+	orderer      msp.SigningIdentity
+	admin        msp.SigningIdentity
+	outsider     msp.SigningIdentity
+}
+
+func newSigner(t *testing.T, dir, name, mspID string, signCA, tlsCA *ca.CA, nodeType int) msp.SigningIdentity {
+	nodeDir := filepath.Join(dir, name)
+	require.NoError(t, cryptogenmsp.GenerateLocalMSP(nodeDir, name, nil, signCA, tlsCA, nodeType, true, true))
+
+	mspDir := filepath.Join(nodeDir, "msp")
+	conf, err := msp.GetLocalMspConfig(mspDir, nil, mspID)
+	require.NoError(t, err)
+	cryptoProvider, err := sw.NewDefaultSecurityLevel(filepath.Join(mspDir, "keystore"))
+	require.NoError(t, err)
+	localMSP, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_4_3}}, cryptoProvider)
+	require.NoError(t, err)
+	require.NoError(t, localMSP.Setup(conf))
+	signer, err := localMSP.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	return signer
+}
+
+func newNetwork(t *testing.T, dir string) *network {
+	newOrg := func(name string) (*ca.CA, *ca.CA, string) {
+		orgDir := filepath.Join(dir, name)
+		signCA, err := ca.NewCA(filepath.Join(orgDir, "ca"), name, "ca."+name, "CN", "", "", "", "", "", true)
+		require.NoError(t, err)
+		tlsCA, err := ca.NewCA(filepath.Join(orgDir, "tlsca"), name, "tlsca."+name, "CN", "", "", "", "", "", true)
+		require.NoError(t, err)
+		mspDir := filepath.Join(orgDir, "msp")
+		require.NoError(t, cryptogenmsp.GenerateVerifyingMSP(mspDir, signCA, tlsCA, true, true))
+		return signCA, tlsCA, mspDir
+	}
+	ordererCA, ordererTLSCA, ordererMSPDir := newOrg("example.com")
+	_, _, org1MSPDir := newOrg("org1.example.com")
+	outsiderCA, outsiderTLSCA, _ := newOrg("outsider.example.com")
+
+	n := &network{
+		orderer:  newSigner(t, dir, "orderer.example.com", "OrdererMSP", ordererCA, ordererTLSCA, cryptogenmsp.ORDERER),
+		admin:    newSigner(t, dir, "Admin@example.com", "OrdererMSP", ordererCA, ordererTLSCA, cryptogenmsp.ADMIN),
+		outsider: newSigner(t, dir, "orderer.outsider.example.com", "OrdererMSP", outsiderCA, outsiderTLSCA, cryptogenmsp.ORDERER),
+	}
+
+	tlsCert := filepath.Join(dir, "example.com", "tlsca", "tlsca.example.com-cert.pem")
+	profile := &configtx.Profile{
+		Orderer: &configtx.Orderer{
+			Addresses: []string{"orderer.example.com:7050"},
+			EtcdRaft: &configtx.EtcdRaft{
+				Consenters: []*configtx.Consenter{
+					{Host: "orderer.example.com", Port: 7050, ClientTLSCert: tlsCert, ServerTLSCert: tlsCert},
+				},
+			},
+			Organizations: []*configtx.Organization{
+				{Name: "OrdererOrg", ID: "OrdererMSP", MSPDir: ordererMSPDir},
+			},
+			Capabilities: map[string]bool{"V2_0": true},
+		},
+		Application: &configtx.Application{
+			Organizations: []*configtx.Organization{
+				{Name: "Org1", ID: "Org1MSP", MSPDir: org1MSPDir},
+			},
+			Capabilities: map[string]bool{"V2_0": true},
+		},
+		Capabilities: map[string]bool{"V2_0": true},
+		RequireSM2:   true,
+	}
+	block, err := configtx.NewGenesisBlock(profile, "mychannel")
+	require.NoError(t, err)
+	n.genesisBlock = block
+	return n
+}
+
+func nextBlock(t *testing.T, prev *cb.Block, signer msp.SigningIdentity, envs ...*cb.Envelope) *cb.Block {
+	block := protoutil.NewBlock(prev.Header.Number+1, protoutil.BlockHeaderHash(prev.Header))
+	for _, env := range envs {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+	}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	shdr, err := protoutil.NewSignatureHeader(signer)
+	require.NoError(t, err)
+	mdSignature := &cb.MetadataSignature{SignatureHeader: protoutil.MarshalOrPanic(shdr)}
+	value := protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{})
+	mdSignature.Signature, err = signer.Sign(append(append(append([]byte{}, value...), mdSignature.SignatureHeader...), protoutil.BlockHeaderBytes(block.Header)...))
+	require.NoError(t, err)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value:      value,
+		Signatures: []*cb.MetadataSignature{mdSignature},
+	})
+	return block
+}
+
+func txEnvelope(t *testing.T, signer msp.SigningIdentity) *cb.Envelope {
+	env, err := protoutil.CreateSignedEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", signer, &cb.ConfigValue{}, 0, 0)
+	require.NoError(t, err)
+	return env
+}
+
+// configEnvelope returns the config transaction of an update of the batch
+// size of the channel signed by the given signer.
+func configEnvelope(t *testing.T, v *blockverify.Verifier, genesisBlock *cb.Block, signer msp.SigningIdentity) *cb.Envelope {
+	env, err := protoutil.ExtractEnvelope(genesisBlock, 0)
+	require.NoError(t, err)
+	configEnv := &cb.ConfigEnvelope{}
+	_, err = protoutil.UnmarshalEnvelopeOfType(env, cb.HeaderType_CONFIG, configEnv)
+	require.NoError(t, err)
+
+	updated := proto.Clone(configEnv.Config).(*cb.Config)
+	batchSize := &ab.BatchSize{}
+	ordererGroup := updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	require.NoError(t, proto.Unmarshal(ordererGroup.Values[channelconfig.BatchSizeKey].Value, batchSize))
+	batchSize.MaxMessageCount++
+	ordererGroup.Values[channelconfig.BatchSizeKey].Value = protoutil.MarshalOrPanic(batchSize)
+
+	configUpdate, err := update.Compute(configEnv.Config, updated)
+	require.NoError(t, err)
+	configUpdate.ChannelId = "mychannel"
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{ConfigUpdate: protoutil.MarshalOrPanic(configUpdate)}
+	shdr, err := protoutil.NewSignatureHeader(signer)
+	require.NoError(t, err)
+	configSig := &cb.ConfigSignature{SignatureHeader: protoutil.MarshalOrPanic(shdr)}
+	configSig.Signature, err = signer.Sign(append(append([]byte{}, configSig.SignatureHeader...), configUpdateEnv.ConfigUpdate...))
+	require.NoError(t, err)
+	configUpdateEnv.Signatures = []*cb.ConfigSignature{configSig}
+	lastUpdate, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, "mychannel", signer, configUpdateEnv, 0, 0)
+	require.NoError(t, err)
+
+	// authorized as the orderer does, or forced through when unauthorized
+	newConfigEnv, err := v.Config().ConfigtxValidator().ProposeConfigUpdate(lastUpdate)
+	if err != nil {
+		updated.Sequence++
+		newConfigEnv = &cb.ConfigEnvelope{Config: updated, LastUpdate: lastUpdate}
+	}
+	configTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG, "mychannel", signer, newConfigEnv, 0, 0)
+	require.NoError(t, err)
+	return configTx
+}
+
+func TestVerifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blockverify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	n := newNetwork(t, dir)
+
+	v, err := blockverify.New(n.genesisBlock)
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", v.ChannelID())
+	assert.Equal(t, uint64(1), v.Height())
+
+	block1 := nextBlock(t, n.genesisBlock, n.orderer, txEnvelope(t, n.admin))
+	block2 := nextBlock(t, block1, n.orderer, configEnvelope(t, v, n.genesisBlock, n.admin))
+	block3 := nextBlock(t, block2, n.orderer, txEnvelope(t, n.admin))
+	require.NoError(t, v.VerifyBlocks([]*cb.Block{block1, block2, block3}))
+	assert.Equal(t, uint64(4), v.Height())
+	ordererConfig, ok := v.Config().OrdererConfig()
+	require.True(t, ok)
+	assert.Equal(t, configtx.DefaultBatchSize.MaxMessageCount+1, ordererConfig.BatchSize().MaxMessageCount)
+
+	signatures, err := protoutil.EnvelopeAsSignedData(txEnvelope(t, n.admin))
+	require.NoError(t, err)
+	assert.NoError(t, v.VerifyPolicy("/Channel/Orderer/Admins", signatures))
+	assert.Error(t, v.VerifyPolicy("/Channel/Application/Writers", signatures))
+	assert.EqualError(t, v.VerifyPolicy("/Channel/Missing", signatures), "policy /Channel/Missing not found")
+}
+
+func TestVerifierRejects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blockverify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	n := newNetwork(t, dir)
+
+	newVerifier := func() *blockverify.Verifier {
+		v, err := blockverify.New(n.genesisBlock)
+		require.NoError(t, err)
+		return v
+	}
+
+	t.Run("out of order", func(t *testing.T) {
+		block1 := nextBlock(t, n.genesisBlock, n.orderer, txEnvelope(t, n.admin))
+		block2 := nextBlock(t, block1, n.orderer, txEnvelope(t, n.admin))
+		assert.EqualError(t, newVerifier().VerifyBlock(block2), "expected block [1] but got block [2]")
+	})
+
+	t.Run("broken chain", func(t *testing.T) {
+		block1 := nextBlock(t, n.genesisBlock, n.orderer, txEnvelope(t, n.admin))
+		block1.Header.PreviousHash = []byte("forged")
+		err := newVerifier().VerifyBlock(block1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "previous hash of block [1]")
+	})
+
+	t.Run("tampered data", func(t *testing.T) {
+		block1 := nextBlock(t, n.genesisBlock, n.orderer, txEnvelope(t, n.admin))
+		block1.Data.Data[0] = []byte("tampered")
+		err := newVerifier().VerifyBlock(block1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "computed data hash of block [1]")
+	})
+
+	t.Run("unauthorized signer", func(t *testing.T) {
+		block1 := nextBlock(t, n.genesisBlock, n.outsider, txEnvelope(t, n.admin))
+		err := newVerifier().VerifyBlock(block1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "block [1] signatures are invalid")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		block1 := nextBlock(t, n.genesisBlock, n.orderer, txEnvelope(t, n.admin))
+		block1.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{})
+		assert.EqualError(t, newVerifier().VerifyBlock(block1), "block [1] is not signed")
+	})
+
+	t.Run("unauthorized config update", func(t *testing.T) {
+		v := newVerifier()
+		block1 := nextBlock(t, n.genesisBlock, n.orderer, configEnvelope(t, v, n.genesisBlock, n.orderer))
+		err := v.VerifyBlock(block1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid config in block [1]")
+		assert.Equal(t, uint64(1), v.Height())
+	})
+
+	t.Run("not a config block", func(t *testing.T) {
+		block1 := nextBlock(t, n.genesisBlock, n.orderer, txEnvelope(t, n.admin))
+		_, err := blockverify.New(block1)
+		assert.EqualError(t, err, "block [1] is not a config block")
+	})
+}
+
+func TestVerifyBlockRange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blockverify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	n := newNetwork(t, dir)
+
+	v, err := blockverify.New(n.genesisBlock)
+	require.NoError(t, err)
+
+	blocks := []*cb.Block{n.genesisBlock}
+	for i := 0; i < 3; i++ {
+		blocks = append(blocks, nextBlock(t, blocks[len(blocks)-1], n.orderer, txEnvelope(t, n.admin)))
+	}
+	var headers []*cb.BlockHeader
+	var hashes [][]byte
+	for _, block := range blocks[1:] {
+		headers = append(headers, block.Header)
+		hashes = append(hashes, protoutil.BlockHeaderHash(block.Header))
+	}
+	require.NoError(t, blockverify.VerifyHeaderChain(headers))
+
+	last := blocks[3]
+	assert.EqualError(t, v.VerifyBlockRange(last, headers), "block [3] carries no range attestation")
+
+	require.NoError(t, protoutil.AddBlockRangeAttestation(last, n.outsider, 1, hashes))
+	err = v.VerifyBlockRange(last, headers)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "block [3] range attestation signatures are invalid")
+
+	require.NoError(t, protoutil.AddBlockRangeAttestation(last, n.orderer, 1, hashes))
+	assert.NoError(t, v.VerifyBlockRange(last, headers))
+	assert.Error(t, v.VerifyBlockRange(last, headers[1:]))
+}
+
+func TestCommitHash(t *testing.T) {
+	block := protoutil.NewBlock(1, nil)
+	commitHash, err := blockverify.CommitHash(block)
+	require.NoError(t, err)
+	assert.Nil(t, commitHash)
+
+	block.Metadata.Metadata[cb.BlockMetadataIndex_COMMIT_HASH] = protoutil.MarshalOrPanic(&cb.Metadata{Value: []byte("commit-hash")})
+	commitHash, err = blockverify.CommitHash(block)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("commit-hash"), commitHash)
+
+	block.Metadata.Metadata[cb.BlockMetadataIndex_COMMIT_HASH] = []byte("garbage")
+	_, err = blockverify.CommitHash(block)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ccmetrics lets chaincode emit counters and histograms that the peer
+// aggregates into its operations metrics, labeled with the channel and the
+// name of the chaincode.  A metric is emitted by invoking the reserved
+// chaincode name ChaincodeName through the stub of the shim, which the peer
+// intercepts instead of invoking a chaincode, so no change to the shim
+// protocol is required.
+//
+// Metrics are recorded when the chaincode is executed, whether the
+// transaction is committed or not, and by every peer that endorses it.
+package ccmetrics
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+)
+
+// ChaincodeName is the reserved chaincode name the metrics are sent to.  It
+// isn't a valid chaincode name, so it can't clash with a deployed chaincode.
+const ChaincodeName = "_metrics"
+
+// Kind is the kind of a metric.
+type Kind string
+
+const (
+	// Counter metrics are added to the chaincode_custom_counter metric.
+	Counter Kind = "counter"
+	// Histogram metrics are observed by the chaincode_custom_histogram metric.
+	Histogram Kind = "histogram"
+)
+
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+
+// Invoker is implemented by the chaincode stub of the shim.
+type Invoker interface {
+	InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response
+}
+
+// Metric is a value emitted by chaincode.
+type Metric struct {
+	Kind  Kind
+	Name  string
+	Value float64
+}
+
+// Add adds delta to the counter with the given name.
+func Add(stub Invoker, name string, delta float64) error {
+	return Emit(stub, Metric{Kind: Counter, Name: name, Value: delta})
+}
+
+// Observe records value in the histogram with the given name.
+func Observe(stub Invoker, name string, value float64) error {
+	return Emit(stub, Metric{Kind: Histogram, Name: name, Value: value})
+}
+
+// Emit sends the metric to the peer.
+func Emit(stub Invoker, m Metric) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	resp := stub.InvokeChaincode(ChaincodeName, m.Args(), "")
+	if resp.Status != shim.OK {
+		return errors.Errorf("failed emitting metric %s: %s", m.Name, resp.Message)
+	}
+	return nil
+}
+
+// Validate returns an error if the metric can't be recorded by the peer.
+// Names are limited to 64 letters, digits and underscores, and counters
+// only go up.
+func (m Metric) Validate() error {
+	switch m.Kind {
+	case Counter, Histogram:
+	default:
+		return errors.Errorf("unknown metric kind '%s'", m.Kind)
+	}
+	if !nameRegexp.MatchString(m.Name) {
+		return errors.Errorf("invalid metric name '%s', expected to match %s", m.Name, nameRegexp)
+	}
+	if math.IsNaN(m.Value) || math.IsInf(m.Value, 0) {
+		return errors.Errorf("invalid value %v of metric %s", m.Value, m.Name)
+	}
+	if m.Kind == Counter && m.Value < 0 {
+		return errors.Errorf("counter %s can't be decreased", m.Name)
+	}
+	return nil
+}
+
+// Args returns the arguments of the invocation emitting the metric.
+func (m Metric) Args() [][]byte {
+	return [][]byte{
+		[]byte(m.Kind),
+		[]byte(m.Name),
+		[]byte(strconv.FormatFloat(m.Value, 'g', -1, 64)),
+	}
+}
+
+// ParseArgs returns the metric emitted by an invocation with the given
+// arguments.
+func ParseArgs(args [][]byte) (Metric, error) {
+	if len(args) != 3 {
+		return Metric{}, errors.Errorf("expected 3 arguments to emit a metric but got %d", len(args))
+	}
+	value, err := strconv.ParseFloat(string(args[2]), 64)
+	if err != nil {
+		return Metric{}, errors.Wrapf(err, "invalid value of metric %s", args[1])
+	}
+	m := Metric{
+		Kind:  Kind(args[0]),
+		Name:  string(args[1]),
+		Value: value,
+	}
+	if err := m.Validate(); err != nil {
+		return Metric{}, err
+	}
+	return m, nil
+}
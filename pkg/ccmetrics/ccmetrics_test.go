@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccmetrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invoker struct {
+	chaincodeName string
+	args          [][]byte
+	channel       string
+	response      pb.Response
+}
+
+func (i *invoker) InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
+	i.chaincodeName, i.args, i.channel = chaincodeName, args, channel
+	return i.response
+}
+
+func TestEmit(t *testing.T) {
+	stub := &invoker{response: shim.Success(nil)}
+
+	require.NoError(t, Add(stub, "orders_placed", 2))
+	assert.Equal(t, ChaincodeName, stub.chaincodeName)
+	assert.Equal(t, "", stub.channel)
+	assert.Equal(t, [][]byte{[]byte("counter"), []byte("orders_placed"), []byte("2")}, stub.args)
+
+	require.NoError(t, Observe(stub, "order_amount", 12.5))
+	assert.Equal(t, [][]byte{[]byte("histogram"), []byte("order_amount"), []byte("12.5")}, stub.args)
+
+	stub.response = shim.Error("metrics are disabled")
+	assert.EqualError(t, Add(stub, "orders_placed", 1), "failed emitting metric orders_placed: metrics are disabled")
+
+	stub = &invoker{}
+	assert.EqualError(t, Add(stub, "orders_placed", -1), "counter orders_placed can't be decreased")
+	assert.Nil(t, stub.args, "invalid metrics must not be sent")
+}
+
+func TestParseArgs(t *testing.T) {
+	m, err := ParseArgs(Metric{Kind: Histogram, Name: "latency", Value: -0.25}.Args())
+	require.NoError(t, err)
+	assert.Equal(t, Metric{Kind: Histogram, Name: "latency", Value: -0.25}, m)
+
+	_, err = ParseArgs([][]byte{[]byte("counter"), []byte("orders")})
+	assert.EqualError(t, err, "expected 3 arguments to emit a metric but got 2")
+	_, err = ParseArgs([][]byte{[]byte("counter"), []byte("orders"), []byte("one")})
+	assert.Contains(t, err.Error(), "invalid value of metric orders")
+	_, err = ParseArgs([][]byte{[]byte("gauge"), []byte("orders"), []byte("1")})
+	assert.EqualError(t, err, "unknown metric kind 'gauge'")
+}
+
+func TestValidate(t *testing.T) {
+	for _, m := range []Metric{
+		{Kind: Counter, Name: "a"},
+		{Kind: Counter, Name: "_orders_2"},
+		{Kind: Histogram, Name: "latency", Value: -1},
+	} {
+		assert.NoError(t, m.Validate(), "metric %v", m)
+	}
+
+	for _, m := range []Metric{
+		{Kind: Counter, Name: ""},
+		{Kind: Counter, Name: "2orders"},
+		{Kind: Counter, Name: "orders-placed"},
+		{Kind: Counter, Name: "orders.placed"},
+		{Kind: Counter, Name: string(make([]byte, 65))},
+		{Kind: Histogram, Name: "latency", Value: math.NaN()},
+		{Kind: Histogram, Name: "latency", Value: math.Inf(1)},
+	} {
+		assert.Error(t, m.Validate(), "metric %v", m)
+	}
+}
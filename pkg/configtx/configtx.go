@@ -0,0 +1,353 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configtx builds channel configurations, genesis blocks and channel
+// creation transactions programmatically. It offers the functionality of the
+// configtxgen tool as a library, with defaults suited to networks using SM2
+// identities.
+package configtx
+
+import (
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
+	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ConsensusTypeEtcdRaft identifies the Raft-based consensus implementation.
+	ConsensusTypeEtcdRaft = encoder.ConsensusTypeEtcdRaft
+
+	// ConsensusTypeSolo identifies the solo consensus implementation.
+	ConsensusTypeSolo = encoder.ConsensusTypeSolo
+
+	// SignaturePolicyType is the 'Type' string for signature policies.
+	SignaturePolicyType = encoder.SignaturePolicyType
+
+	// ImplicitMetaPolicyType is the 'Type' string for implicit meta policies.
+	ImplicitMetaPolicyType = encoder.ImplicitMetaPolicyType
+)
+
+// Profile describes a channel configuration. Orderer system channel profiles
+// define the Orderer and Consortiums, application channel profiles define the
+// Consortium and the Application.
+type Profile struct {
+	Consortium   string
+	Application  *Application
+	Orderer      *Orderer
+	Consortiums  map[string][]*Organization
+	Capabilities map[string]bool
+	Policies     map[string]*Policy
+
+	// RequireSM2 requires the root certificates of every organization MSP
+	// to carry SM2 public keys.
+	RequireSM2 bool
+}
+
+// Policy is a policy of the channel configuration, expressed as an implicit
+// meta policy or a signature policy.
+type Policy struct {
+	Type string
+	Rule string
+}
+
+// Organization describes the MSP and the policies of an organization.
+type Organization struct {
+	Name string
+	ID   string
+	// MSPDir is the directory holding the MSP material of the organization,
+	// laid out as generated by cryptogen.
+	MSPDir string
+	// MSPType defaults to the X.509 based MSP.
+	MSPType  string
+	Policies map[string]*Policy
+
+	AnchorPeers      []*AnchorPeer
+	OrdererEndpoints []string
+}
+
+// AnchorPeer is an anchor peer of an application organization.
+type AnchorPeer struct {
+	Host string
+	Port int
+}
+
+// Application describes the application section of a channel.
+type Application struct {
+	Organizations []*Organization
+	Capabilities  map[string]bool
+	Policies      map[string]*Policy
+	ACLs          map[string]string
+}
+
+// Orderer describes the ordering service of a channel.
+type Orderer struct {
+	// OrdererType defaults to etcdraft.
+	OrdererType   string
+	Addresses     []string
+	BatchTimeout  time.Duration
+	BatchSize     BatchSize
+	EtcdRaft      *EtcdRaft
+	Organizations []*Organization
+	MaxChannels   uint64
+	Capabilities  map[string]bool
+	Policies      map[string]*Policy
+}
+
+// BatchSize controls the number of messages batched into a block.
+type BatchSize struct {
+	MaxMessageCount   uint32
+	AbsoluteMaxBytes  uint32
+	PreferredMaxBytes uint32
+}
+
+// EtcdRaft holds the consenters and the options of an etcdraft ordering
+// service.
+type EtcdRaft struct {
+	Consenters []*Consenter
+	// Options defaults to DefaultEtcdRaftOptions, unset fields are
+	// defaulted individually.
+	Options *etcdraft.Options
+}
+
+// Consenter is an etcdraft consenter. The TLS certificates are paths to PEM
+// encoded certificates.
+type Consenter struct {
+	Host          string
+	Port          uint32
+	ClientTLSCert string
+	ServerTLSCert string
+}
+
+var (
+	// DefaultBatchTimeout is the batch timeout used when none is set.
+	DefaultBatchTimeout = 2 * time.Second
+
+	// DefaultBatchSize is the batch size used for the fields which are not set.
+	DefaultBatchSize = BatchSize{
+		MaxMessageCount:   500,
+		AbsoluteMaxBytes:  10 * 1024 * 1024,
+		PreferredMaxBytes: 2 * 1024 * 1024,
+	}
+
+	// DefaultEtcdRaftOptions are the etcdraft options used for the fields
+	// which are not set.
+	DefaultEtcdRaftOptions = etcdraft.Options{
+		TickInterval:         "500ms",
+		ElectionTick:         10,
+		HeartbeatTick:        1,
+		MaxInflightBlocks:    5,
+		SnapshotIntervalSize: 16 * 1024 * 1024,
+	}
+)
+
+// Signer signs channel creation transactions.
+type Signer interface {
+	Sign(message []byte) ([]byte, error)
+	Serialize() ([]byte, error)
+}
+
+// NewChannelGroup returns the root group of the channel configuration
+// described by the profile.
+func NewChannelGroup(p *Profile) (*cb.ConfigGroup, error) {
+	conf, err := toGenesisProfile(p)
+	if err != nil {
+		return nil, err
+	}
+	return encoder.NewChannelGroup(conf)
+}
+
+// NewGenesisBlock returns the genesis block of the given channel, configured
+// as described by the profile.
+func NewGenesisBlock(p *Profile, channelID string) (*cb.Block, error) {
+	conf, err := toGenesisProfile(p)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := encoder.NewBootstrapper(conf)
+	if err != nil {
+		return nil, err
+	}
+	return bs.GenesisBlockForChannel(channelID), nil
+}
+
+// NewChannelCreateTx returns the transaction creating the given application
+// channel, configured as described by the profile. The transaction is signed
+// by the signer, if any.
+func NewChannelCreateTx(p *Profile, channelID string, signer Signer) (*cb.Envelope, error) {
+	conf, err := toGenesisProfile(p)
+	if err != nil {
+		return nil, err
+	}
+	return encoder.MakeChannelCreationTransaction(channelID, signer, conf)
+}
+
+func toGenesisProfile(p *Profile) (*genesisconfig.Profile, error) {
+	if p == nil {
+		return nil, errors.New("profile is nil")
+	}
+
+	conf := &genesisconfig.Profile{
+		Consortium:   p.Consortium,
+		Capabilities: p.Capabilities,
+		Policies:     toGenesisPolicies(p.Policies, ChannelPolicies),
+	}
+
+	var err error
+	if p.Application != nil {
+		conf.Application = &genesisconfig.Application{
+			Capabilities: p.Application.Capabilities,
+			Policies:     toGenesisPolicies(p.Application.Policies, ApplicationPolicies),
+			ACLs:         p.Application.ACLs,
+		}
+		if conf.Application.Organizations, err = toGenesisOrgs(p.Application.Organizations, p.RequireSM2); err != nil {
+			return nil, errors.WithMessage(err, "invalid application organization")
+		}
+	}
+
+	if p.Orderer != nil {
+		if conf.Orderer, err = toGenesisOrderer(p.Orderer, p.RequireSM2); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Consortiums != nil {
+		conf.Consortiums = map[string]*genesisconfig.Consortium{}
+		for name, orgs := range p.Consortiums {
+			consortium := &genesisconfig.Consortium{}
+			if consortium.Organizations, err = toGenesisOrgs(orgs, p.RequireSM2); err != nil {
+				return nil, errors.WithMessagef(err, "invalid organization of consortium %s", name)
+			}
+			conf.Consortiums[name] = consortium
+		}
+	}
+
+	return conf, nil
+}
+
+func toGenesisOrderer(o *Orderer, requireSM2 bool) (*genesisconfig.Orderer, error) {
+	conf := &genesisconfig.Orderer{
+		OrdererType:  o.OrdererType,
+		Addresses:    o.Addresses,
+		BatchTimeout: o.BatchTimeout,
+		BatchSize: genesisconfig.BatchSize{
+			MaxMessageCount:   o.BatchSize.MaxMessageCount,
+			AbsoluteMaxBytes:  o.BatchSize.AbsoluteMaxBytes,
+			PreferredMaxBytes: o.BatchSize.PreferredMaxBytes,
+		},
+		MaxChannels:  o.MaxChannels,
+		Capabilities: o.Capabilities,
+		Policies:     toGenesisPolicies(o.Policies, OrdererPolicies),
+	}
+
+	if conf.OrdererType == "" {
+		conf.OrdererType = ConsensusTypeEtcdRaft
+	}
+	if conf.BatchTimeout == 0 {
+		conf.BatchTimeout = DefaultBatchTimeout
+	}
+	if conf.BatchSize.MaxMessageCount == 0 {
+		conf.BatchSize.MaxMessageCount = DefaultBatchSize.MaxMessageCount
+	}
+	if conf.BatchSize.AbsoluteMaxBytes == 0 {
+		conf.BatchSize.AbsoluteMaxBytes = DefaultBatchSize.AbsoluteMaxBytes
+	}
+	if conf.BatchSize.PreferredMaxBytes == 0 {
+		conf.BatchSize.PreferredMaxBytes = DefaultBatchSize.PreferredMaxBytes
+	}
+
+	if conf.OrdererType == ConsensusTypeEtcdRaft {
+		if o.EtcdRaft == nil || len(o.EtcdRaft.Consenters) == 0 {
+			return nil, errors.New("etcdraft orderer requires at least one consenter")
+		}
+		conf.EtcdRaft = toEtcdRaftMetadata(o.EtcdRaft)
+	}
+
+	var err error
+	if conf.Organizations, err = toGenesisOrgs(o.Organizations, requireSM2); err != nil {
+		return nil, errors.WithMessage(err, "invalid orderer organization")
+	}
+	return conf, nil
+}
+
+func toEtcdRaftMetadata(er *EtcdRaft) *etcdraft.ConfigMetadata {
+	options := DefaultEtcdRaftOptions
+	if er.Options != nil {
+		if er.Options.TickInterval != "" {
+			options.TickInterval = er.Options.TickInterval
+		}
+		if er.Options.ElectionTick != 0 {
+			options.ElectionTick = er.Options.ElectionTick
+		}
+		if er.Options.HeartbeatTick != 0 {
+			options.HeartbeatTick = er.Options.HeartbeatTick
+		}
+		if er.Options.MaxInflightBlocks != 0 {
+			options.MaxInflightBlocks = er.Options.MaxInflightBlocks
+		}
+		if er.Options.SnapshotIntervalSize != 0 {
+			options.SnapshotIntervalSize = er.Options.SnapshotIntervalSize
+		}
+	}
+
+	md := &etcdraft.ConfigMetadata{Options: &options}
+	for _, c := range er.Consenters {
+		// the encoder loads the certificates from the paths
+		md.Consenters = append(md.Consenters, &etcdraft.Consenter{
+			Host:          c.Host,
+			Port:          c.Port,
+			ClientTlsCert: []byte(c.ClientTLSCert),
+			ServerTlsCert: []byte(c.ServerTLSCert),
+		})
+	}
+	return md
+}
+
+func toGenesisOrgs(orgs []*Organization, requireSM2 bool) ([]*genesisconfig.Organization, error) {
+	var result []*genesisconfig.Organization
+	for _, org := range orgs {
+		conf := &genesisconfig.Organization{
+			Name:             org.Name,
+			ID:               org.ID,
+			MSPDir:           org.MSPDir,
+			MSPType:          org.MSPType,
+			OrdererEndpoints: org.OrdererEndpoints,
+			AdminPrincipal:   genesisconfig.AdminRoleAdminPrincipal,
+		}
+		if conf.MSPType == "" {
+			conf.MSPType = msp.ProviderTypeToString(msp.FABRIC)
+		}
+		conf.Policies = toGenesisPolicies(org.Policies, func() map[string]*Policy {
+			return OrganizationPolicies(org.ID)
+		})
+		for _, ap := range org.AnchorPeers {
+			conf.AnchorPeers = append(conf.AnchorPeers, &genesisconfig.AnchorPeer{Host: ap.Host, Port: ap.Port})
+		}
+
+		if requireSM2 && conf.MSPType == msp.ProviderTypeToString(msp.FABRIC) {
+			if err := CheckSM2MSP(conf.MSPDir); err != nil {
+				return nil, errors.WithMessagef(err, "organization %s", org.Name)
+			}
+		}
+		result = append(result, conf)
+	}
+	return result, nil
+}
+
+func toGenesisPolicies(policies map[string]*Policy, defaults func() map[string]*Policy) map[string]*genesisconfig.Policy {
+	if policies == nil {
+		policies = defaults()
+	}
+	result := map[string]*genesisconfig.Policy{}
+	for name, policy := range policies {
+		result[name] = &genesisconfig.Policy{Type: policy.Type, Rule: policy.Rule}
+	}
+	return result
+}
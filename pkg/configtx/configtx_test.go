@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	commonconfigtx "github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/internal/cryptogen/ca"
+	"github.com/hyperledger/fabric/internal/cryptogen/msp"
+	"github.com/hyperledger/fabric/pkg/configtx"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testOrg struct {
+	mspDir  string
+	tlsCert string
+}
+
+func newTestOrg(t *testing.T, dir, name string, gm bool) testOrg {
+	orgDir := filepath.Join(dir, name)
+	signCA, err := ca.NewCA(filepath.Join(orgDir, "ca"), name, "ca."+name, "CN", "", "", "", "", "", gm)
+	require.NoError(t, err)
+	tlsCA, err := ca.NewCA(filepath.Join(orgDir, "tlsca"), name, "tlsca."+name, "CN", "", "", "", "", "", gm)
+	require.NoError(t, err)
+
+	mspDir := filepath.Join(orgDir, "msp")
+	require.NoError(t, msp.GenerateVerifyingMSP(mspDir, signCA, tlsCA, false, gm))
+
+	return testOrg{
+		mspDir:  mspDir,
+		tlsCert: filepath.Join(orgDir, "tlsca", "tlsca."+name+"-cert.pem"),
+	}
+}
+
+func systemChannelProfile(ordererOrg, peerOrg testOrg) *configtx.Profile {
+	return &configtx.Profile{
+		Orderer: &configtx.Orderer{
+			Addresses: []string{"orderer.example.com:7050"},
+			EtcdRaft: &configtx.EtcdRaft{
+				Consenters: []*configtx.Consenter{
+					{
+						Host:          "orderer.example.com",
+						Port:          7050,
+						ClientTLSCert: ordererOrg.tlsCert,
+						ServerTLSCert: ordererOrg.tlsCert,
+					},
+				},
+				Options: &etcdraft.Options{ElectionTick: 20},
+			},
+			Organizations: []*configtx.Organization{
+				{Name: "OrdererOrg", ID: "OrdererMSP", MSPDir: ordererOrg.mspDir},
+			},
+			Capabilities: map[string]bool{"V2_0": true},
+		},
+		Consortiums: map[string][]*configtx.Organization{
+			"SampleConsortium": {
+				{Name: "Org1", ID: "Org1MSP", MSPDir: peerOrg.mspDir},
+			},
+		},
+		Capabilities: map[string]bool{"V2_0": true},
+		RequireSM2:   true,
+	}
+}
+
+func TestNewGenesisBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configtx")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	profile := systemChannelProfile(newTestOrg(t, dir, "orderer.example.com", true), newTestOrg(t, dir, "org1.example.com", true))
+	block, err := configtx.NewGenesisBlock(profile, "system-channel")
+	require.NoError(t, err)
+
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	configEnv := &cb.ConfigEnvelope{}
+	_, err = protoutil.UnmarshalEnvelopeOfType(env, cb.HeaderType_CONFIG, configEnv)
+	require.NoError(t, err)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundle("system-channel", configEnv.Config, cryptoProvider)
+	require.NoError(t, err)
+
+	ordererConfig, ok := bundle.OrdererConfig()
+	require.True(t, ok)
+	assert.Equal(t, configtx.ConsensusTypeEtcdRaft, ordererConfig.ConsensusType())
+	assert.Equal(t, configtx.DefaultBatchSize.MaxMessageCount, ordererConfig.BatchSize().MaxMessageCount)
+	assert.Equal(t, configtx.DefaultBatchTimeout, ordererConfig.BatchTimeout())
+
+	md := &etcdraft.ConfigMetadata{}
+	require.NoError(t, proto.Unmarshal(ordererConfig.ConsensusMetadata(), md))
+	require.Len(t, md.Consenters, 1)
+	tlsCert, err := ioutil.ReadFile(profile.Orderer.EtcdRaft.Consenters[0].ServerTLSCert)
+	require.NoError(t, err)
+	assert.Equal(t, tlsCert, md.Consenters[0].ServerTlsCert)
+	assert.Equal(t, uint32(20), md.Options.ElectionTick)
+	assert.Equal(t, configtx.DefaultEtcdRaftOptions.TickInterval, md.Options.TickInterval)
+
+	consortiums, ok := bundle.ConsortiumsConfig()
+	require.True(t, ok)
+	assert.Contains(t, consortiums.Consortiums()["SampleConsortium"].Organizations(), "Org1")
+}
+
+func TestNewChannelCreateTx(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configtx")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	org1 := newTestOrg(t, dir, "org1.example.com", true)
+	profile := &configtx.Profile{
+		Consortium: "SampleConsortium",
+		Application: &configtx.Application{
+			Organizations: []*configtx.Organization{
+				{
+					Name:        "Org1",
+					ID:          "Org1MSP",
+					MSPDir:      org1.mspDir,
+					AnchorPeers: []*configtx.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}},
+				},
+			},
+			Capabilities: map[string]bool{"V2_0": true},
+		},
+		RequireSM2: true,
+	}
+
+	env, err := configtx.NewChannelCreateTx(profile, "mychannel", nil)
+	require.NoError(t, err)
+
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", chdr.ChannelId)
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), chdr.Type)
+
+	configUpdateEnv, err := commonconfigtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	require.NoError(t, err)
+	configUpdate, err := commonconfigtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	require.NoError(t, err)
+	appGroup := configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey]
+	require.NotNil(t, appGroup)
+	assert.Contains(t, appGroup.Groups, "Org1")
+	assert.Contains(t, appGroup.Policies, "LifecycleEndorsement")
+}
+
+func TestRequireSM2(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configtx")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ordererOrg := newTestOrg(t, dir, "orderer.example.com", true)
+	ecdsaOrg := newTestOrg(t, dir, "org1.example.com", false)
+
+	profile := systemChannelProfile(ordererOrg, ecdsaOrg)
+	_, err = configtx.NewGenesisBlock(profile, "system-channel")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not carry an SM2 public key")
+
+	assert.NoError(t, configtx.CheckSM2MSP(ordererOrg.mspDir))
+	assert.Error(t, configtx.CheckSM2MSP(filepath.Join(dir, "missing")))
+}
+
+func TestMissingConsenters(t *testing.T) {
+	_, err := configtx.NewChannelGroup(&configtx.Profile{
+		Orderer: &configtx.Orderer{},
+	})
+	assert.EqualError(t, err, "etcdraft orderer requires at least one consenter")
+
+	_, err = configtx.NewChannelGroup(nil)
+	assert.EqualError(t, err, "profile is nil")
+}
+
+func TestOrganizationPolicies(t *testing.T) {
+	policies := configtx.OrganizationPolicies("Org1MSP")
+	assert.Equal(t, &configtx.Policy{Type: configtx.SignaturePolicyType, Rule: "OR('Org1MSP.admin')"}, policies["Admins"])
+	assert.Equal(t, "OR('Org1MSP.member')", policies["Endorsement"].Rule)
+}
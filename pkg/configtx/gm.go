@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/pkg/errors"
+)
+
+// ChannelPolicies returns the default policies of the channel group.
+func ChannelPolicies() map[string]*Policy {
+	return map[string]*Policy{
+		"Readers": {Type: ImplicitMetaPolicyType, Rule: "ANY Readers"},
+		"Writers": {Type: ImplicitMetaPolicyType, Rule: "ANY Writers"},
+		"Admins":  {Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admins"},
+	}
+}
+
+// OrdererPolicies returns the default policies of the orderer group.
+func OrdererPolicies() map[string]*Policy {
+	policies := ChannelPolicies()
+	policies["BlockValidation"] = &Policy{Type: ImplicitMetaPolicyType, Rule: "ANY Writers"}
+	return policies
+}
+
+// ApplicationPolicies returns the default policies of the application group.
+func ApplicationPolicies() map[string]*Policy {
+	policies := ChannelPolicies()
+	policies["Endorsement"] = &Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement"}
+	policies["LifecycleEndorsement"] = &Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement"}
+	return policies
+}
+
+// OrganizationPolicies returns the default policies of the organization with
+// the given MSP ID. The signature policies are satisfied by the SM2 signatures
+// of the members of the organization.
+func OrganizationPolicies(mspID string) map[string]*Policy {
+	member := fmt.Sprintf("OR('%s.member')", mspID)
+	return map[string]*Policy{
+		"Readers":     {Type: SignaturePolicyType, Rule: member},
+		"Writers":     {Type: SignaturePolicyType, Rule: member},
+		"Admins":      {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.admin')", mspID)},
+		"Endorsement": {Type: SignaturePolicyType, Rule: member},
+	}
+}
+
+// CheckSM2MSP checks that the root certificates of the MSP in the given
+// directory carry SM2 public keys.
+func CheckSM2MSP(mspDir string) error {
+	caDir := filepath.Join(mspDir, "cacerts")
+	files, err := ioutil.ReadDir(caDir)
+	if err != nil {
+		return errors.Wrapf(err, "could not read root certificates directory %s", caDir)
+	}
+
+	var found bool
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		path := filepath.Join(caDir, f.Name())
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "could not read root certificate %s", path)
+		}
+		for block, rest := pem.Decode(raw); block != nil; block, rest = pem.Decode(rest) {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return errors.Wrapf(err, "could not parse root certificate %s", path)
+			}
+			if _, ok := cert.PublicKey.(*sm2.PublicKey); !ok {
+				return errors.Errorf("root certificate %s does not carry an SM2 public key", path)
+			}
+			found = true
+		}
+	}
+	if !found {
+		return errors.Errorf("no root certificate found in %s", caDir)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package deterministic gives chaincode access to a source of pseudo-random
+// numbers that is identical on every endorsing peer of a transaction.  Its
+// seed is carried in the decorations of the chaincode input, which the peer
+// populates when the DeterministicDecorator is configured in
+// peer.handlers.decorators.
+//
+// The seed is sha256(channelID || 0x00 || txID).  The transaction ID is chosen
+// by the client before it submits the proposal, and it is public once the
+// transaction is committed, so the seed is predictable by the client and by
+// anyone reading the ledger.  It is not a source of randomness: it must not be
+// used for keys, nonces, lotteries or anything else whose outcome must not be
+// known in advance or chosen by the client.
+//
+// The package provides no timestamp.  The only timestamp shared by all the
+// endorsers is the one of the proposal, which chaincode reads with
+// GetTxTimestamp of the stub.  It is chosen by the client, and the peer does
+// not check it against its clock nor against the time of any block.
+package deterministic
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// SeedKey is the decoration holding the 32 byte seed of the transaction.
+const SeedKey = "fabric.deterministic.seed"
+
+// DecorationsGetter is implemented by the chaincode stub of the shim.
+type DecorationsGetter interface {
+	GetDecorations() map[string][]byte
+}
+
+// DeriveSeed returns the seed of the transaction with the given ID on the
+// given channel.  Anyone who knows both can compute it.
+func DeriveSeed(channelID, txID string) []byte {
+	h := sha256.New()
+	h.Write([]byte(channelID))
+	h.Write([]byte{0})
+	h.Write([]byte(txID))
+	return h.Sum(nil)
+}
+
+// Seed returns the deterministic seed of the transaction.
+func Seed(stub DecorationsGetter) ([]byte, error) {
+	seed, ok := stub.GetDecorations()[SeedKey]
+	if !ok {
+		return nil, errors.Errorf("decoration %s not found, is the DeterministicDecorator enabled?", SeedKey)
+	}
+	if len(seed) != sha256.Size {
+		return nil, errors.Errorf("invalid decoration %s, expected %d bytes but got %d", SeedKey, sha256.Size, len(seed))
+	}
+	return seed, nil
+}
+
+// Rand returns a pseudo-random number generator seeded with the
+// deterministic seed of the transaction.  Its output is predictable, as the
+// seed can be computed by anyone who knows the transaction ID, so it must not
+// be used where unpredictability matters.
+func Rand(stub DecorationsGetter) (*rand.Rand, error) {
+	seed, err := Seed(stub)
+	if err != nil {
+		return nil, err
+	}
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed)))), nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deterministic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decorations map[string][]byte
+
+func (d decorations) GetDecorations() map[string][]byte {
+	return d
+}
+
+func TestDeriveSeed(t *testing.T) {
+	seed := DeriveSeed("mychannel", "txid")
+	assert.Len(t, seed, 32)
+	assert.Equal(t, seed, DeriveSeed("mychannel", "txid"))
+	assert.NotEqual(t, seed, DeriveSeed("otherchannel", "txid"))
+	assert.NotEqual(t, seed, DeriveSeed("mychannel", "othertxid"))
+	assert.NotEqual(t, DeriveSeed("ab", "c"), DeriveSeed("a", "bc"))
+}
+
+func TestRand(t *testing.T) {
+	stub := decorations{SeedKey: DeriveSeed("mychannel", "txid")}
+
+	r1, err := Rand(stub)
+	require.NoError(t, err)
+	r2, err := Rand(stub)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, r1.Int63(), r2.Int63())
+	}
+
+	_, err = Rand(decorations{})
+	assert.EqualError(t, err, "decoration fabric.deterministic.seed not found, is the DeterministicDecorator enabled?")
+
+	_, err = Rand(decorations{SeedKey: []byte("short")})
+	assert.EqualError(t, err, "invalid decoration fabric.deterministic.seed, expected 32 bytes but got 5")
+}
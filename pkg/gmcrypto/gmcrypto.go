@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gmcrypto gives chaincode the SM3 hash, the SM4 encryption and the
+// SM2 signature verification of the peer, so that chaincode doesn't bundle
+// its own GM crypto implementation.  An operation is requested with the
+// GMCrypto function of the stub of the shim, which sends a GM_CRYPTO message
+// that the peer serves with its crypto provider.
+//
+// The results are identical on every endorsing peer of a transaction: the
+// SM4 encryption uses an initialization vector computed as the HMAC-SM3,
+// keyed by the SM4 key, of the transaction ID and the plaintext rather than a
+// random one, so it reveals nothing about the plaintext to whoever doesn't
+// hold the key.  As a consequence, the same plaintext encrypted twice with
+// the same key in a transaction yields the same ciphertext.
+package gmcrypto
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Operation is a crypto operation performed by the peer.
+type Operation string
+
+const (
+	// SM3 hashes a message.
+	SM3 Operation = "sm3"
+	// SM4Encrypt encrypts a plaintext with a 16 byte key in CBC mode with
+	// PKCS#7 padding.  The initialization vector is prepended to the
+	// ciphertext.
+	SM4Encrypt Operation = "sm4encrypt"
+	// SM4Decrypt decrypts a ciphertext produced by SM4Encrypt.
+	SM4Decrypt Operation = "sm4decrypt"
+	// SM2Verify verifies the SM2 signature of a message, as specified by
+	// GB/T 32918 with the default user ID, against a PEM encoded certificate
+	// or public key.
+	SM2Verify Operation = "sm2verify"
+)
+
+// numArgs is the number of arguments of each operation.
+var numArgs = map[Operation]int{
+	SM3:        1,
+	SM4Encrypt: 2,
+	SM4Decrypt: 2,
+	SM2Verify:  3,
+}
+
+// Stub is implemented by the chaincode stub of the shim.
+type Stub interface {
+	GMCrypto(operation string, args ...[]byte) ([]byte, error)
+}
+
+// TransientStub is implemented by the chaincode stub of the shim.
+type TransientStub interface {
+	Stub
+	GetTransient() (map[string][]byte, error)
+}
+
+// Hash returns the SM3 digest of msg.
+func Hash(stub Stub, msg []byte) ([]byte, error) {
+	return invoke(stub, SM3, msg)
+}
+
+// Encrypt returns the SM4 encryption of plaintext with key.
+func Encrypt(stub Stub, key, plaintext []byte) ([]byte, error) {
+	return invoke(stub, SM4Encrypt, key, plaintext)
+}
+
+// Decrypt returns the plaintext of a ciphertext returned by Encrypt with key.
+func Decrypt(stub Stub, key, ciphertext []byte) ([]byte, error) {
+	return invoke(stub, SM4Decrypt, key, ciphertext)
+}
+
+// Verify returns nil if signature is a valid SM2 signature of msg by the
+// owner of the PEM encoded certificate or public key.
+func Verify(stub Stub, pemBytes, signature, msg []byte) error {
+	_, err := invoke(stub, SM2Verify, pemBytes, signature, msg)
+	return err
+}
+
+// EncryptTransient returns the SM4 encryption of the transient field with
+// the key held by the transient field keyField, so that private inputs can be
+// stored encrypted without the key ever being part of the transaction.
+func EncryptTransient(stub TransientStub, field, keyField string) ([]byte, error) {
+	transient, err := stub.GetTransient()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get the transient data")
+	}
+	plaintext, ok := transient[field]
+	if !ok {
+		return nil, errors.Errorf("transient field %s not found", field)
+	}
+	key, ok := transient[keyField]
+	if !ok {
+		return nil, errors.Errorf("transient field %s not found", keyField)
+	}
+	return Encrypt(stub, key, plaintext)
+}
+
+func invoke(stub Stub, op Operation, args ...[]byte) ([]byte, error) {
+	result, err := stub.GMCrypto(string(op), args...)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "%s failed", op)
+	}
+	return result, nil
+}
+
+// CheckArgs checks that op is a known operation and that it is requested
+// with the expected number of arguments.
+func CheckArgs(op Operation, args [][]byte) error {
+	n, ok := numArgs[op]
+	if !ok {
+		return errors.Errorf("unknown crypto operation '%s'", op)
+	}
+	if len(args) != n {
+		return errors.Errorf("expected %d arguments for %s but got %d", n, op, len(args))
+	}
+	return nil
+}
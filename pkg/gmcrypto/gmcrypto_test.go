@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gmcrypto
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ TransientStub = &shim.ChaincodeStub{}
+
+type stub struct {
+	operation    string
+	args         [][]byte
+	result       []byte
+	err          error
+	transient    map[string][]byte
+	transientErr error
+}
+
+func (s *stub) GMCrypto(operation string, args ...[]byte) ([]byte, error) {
+	s.operation, s.args = operation, args
+	return s.result, s.err
+}
+
+func (s *stub) GetTransient() (map[string][]byte, error) {
+	return s.transient, s.transientErr
+}
+
+func TestOperations(t *testing.T) {
+	stub := &stub{result: []byte("result")}
+
+	digest, err := Hash(stub, []byte("msg"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("result"), digest)
+	assert.Equal(t, "sm3", stub.operation)
+	assert.Equal(t, [][]byte{[]byte("msg")}, stub.args)
+
+	_, err = Encrypt(stub, []byte("key"), []byte("plaintext"))
+	require.NoError(t, err)
+	assert.Equal(t, "sm4encrypt", stub.operation)
+	assert.Equal(t, [][]byte{[]byte("key"), []byte("plaintext")}, stub.args)
+
+	_, err = Decrypt(stub, []byte("key"), []byte("ciphertext"))
+	require.NoError(t, err)
+	assert.Equal(t, "sm4decrypt", stub.operation)
+	assert.Equal(t, [][]byte{[]byte("key"), []byte("ciphertext")}, stub.args)
+
+	require.NoError(t, Verify(stub, []byte("pem"), []byte("signature"), []byte("msg")))
+	assert.Equal(t, "sm2verify", stub.operation)
+	assert.Equal(t, [][]byte{[]byte("pem"), []byte("signature"), []byte("msg")}, stub.args)
+
+	stub.err = errors.New("the signature is invalid")
+	assert.EqualError(t, Verify(stub, []byte("pem"), []byte("signature"), []byte("msg")), "sm2verify failed: the signature is invalid")
+}
+
+func TestEncryptTransient(t *testing.T) {
+	stub := &stub{
+		result:    []byte("ciphertext"),
+		transient: map[string][]byte{"price": []byte("100"), "key": []byte("secret")},
+	}
+
+	ciphertext, err := EncryptTransient(stub, "price", "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ciphertext"), ciphertext)
+	assert.Equal(t, "sm4encrypt", stub.operation)
+	assert.Equal(t, [][]byte{[]byte("secret"), []byte("100")}, stub.args)
+
+	_, err = EncryptTransient(stub, "quantity", "key")
+	assert.EqualError(t, err, "transient field quantity not found")
+	_, err = EncryptTransient(stub, "price", "missing")
+	assert.EqualError(t, err, "transient field missing not found")
+
+	stub.transientErr = errors.New("boom")
+	_, err = EncryptTransient(stub, "price", "key")
+	assert.EqualError(t, err, "failed to get the transient data: boom")
+}
+
+func TestCheckArgs(t *testing.T) {
+	require.NoError(t, CheckArgs(SM2Verify, [][]byte{[]byte("pem"), []byte("signature"), []byte("msg")}))
+
+	err := CheckArgs("sm9", [][]byte{[]byte("msg")})
+	assert.EqualError(t, err, "unknown crypto operation 'sm9'")
+	err = CheckArgs(SM4Encrypt, [][]byte{[]byte("key")})
+	assert.EqualError(t, err, "expected 2 arguments for sm4encrypt but got 1")
+}
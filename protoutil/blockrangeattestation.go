@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoutil
+
+import (
+	"bytes"
+
+	"github.com/cetcxinlian/cryptogm/sm3"
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/pkg/errors"
+)
+
+// BlockMetadataIndexRangeAttestation is the position in the block metadata array
+// of the attestation of the orderer over a range of blocks, which follows the
+// position of the time attestation
+const BlockMetadataIndexRangeAttestation = BlockMetadataIndexTimestamp + 1
+
+// BlockRangeAttestation attests the hashes of the headers of a range of blocks
+// with a single signature, so that a client can verify a long chain of blocks
+// by checking the signatures of one attestation per range instead of the
+// signatures of every block. It is stored as the value of the metadata at
+// BlockMetadataIndexRangeAttestation of the last block of the range, whose
+// signatures cover the value and the signature header.
+//
+// The struct is hand written with the protobuf tags so that it can be
+// marshaled without a generated message.
+type BlockRangeAttestation struct {
+	// Start is the number of the first block of the range
+	Start uint64 `protobuf:"varint,1,opt,name=start,proto3"`
+	// End is the number of the last block of the range
+	End uint64 `protobuf:"varint,2,opt,name=end,proto3"`
+	// Digest is the SM3 digest of the concatenated header hashes of the blocks
+	// of the range, in order
+	Digest []byte `protobuf:"bytes,3,opt,name=digest,proto3"`
+}
+
+// Reset resets
+func (bra *BlockRangeAttestation) Reset() { *bra = BlockRangeAttestation{} }
+
+// String converts to string
+func (bra *BlockRangeAttestation) String() string { return proto.CompactTextString(bra) }
+
+// ProtoMessage just exists to make proto happy
+func (*BlockRangeAttestation) ProtoMessage() {}
+
+// BlockRangeDigest returns the digest attested for a range of blocks given the
+// hashes of their headers, in order
+func BlockRangeDigest(headerHashes [][]byte) []byte {
+	return sm3.SumSM3(bytes.Join(headerHashes, nil))
+}
+
+// AddBlockRangeAttestation attests the range of blocks from start to the given
+// block, whose header hashes are given in order, with a signature of the
+// signer, replacing any previous attestation of the block
+func AddBlockRangeAttestation(block *cb.Block, signer identity.SignerSerializer, start uint64, headerHashes [][]byte) error {
+	if block.Header == nil {
+		return errors.New("block has no header")
+	}
+	if start > block.Header.Number || uint64(len(headerHashes)) != block.Header.Number-start+1 {
+		return errors.Errorf("expected the header hashes of blocks [%d] to [%d] but got %d hashes", start, block.Header.Number, len(headerHashes))
+	}
+	if !bytes.Equal(headerHashes[len(headerHashes)-1], BlockHeaderHash(block.Header)) {
+		return errors.Errorf("the last header hash of the range is not the hash of block [%d]", block.Header.Number)
+	}
+
+	value, err := proto.Marshal(&BlockRangeAttestation{
+		Start:  start,
+		End:    block.Header.Number,
+		Digest: BlockRangeDigest(headerHashes),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block range attestation")
+	}
+
+	sh, err := NewSignatureHeader(signer)
+	if err != nil {
+		return errors.Wrap(err, "failed to create signature header")
+	}
+	shBytes, err := proto.Marshal(sh)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal signature header")
+	}
+	signature, err := Sign(signer, bytes.Join([][]byte{value, shBytes}, nil))
+	if err != nil {
+		return errors.Wrap(err, "failed to sign block range attestation")
+	}
+
+	md, err := proto.Marshal(&cb.Metadata{
+		Value: value,
+		Signatures: []*cb.MetadataSignature{
+			{SignatureHeader: shBytes, Signature: signature},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block range attestation metadata")
+	}
+
+	InitBlockMetadata(block)
+	for len(block.Metadata.Metadata) <= int(BlockMetadataIndexRangeAttestation) {
+		block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
+	}
+	block.Metadata.Metadata[BlockMetadataIndexRangeAttestation] = md
+	return nil
+}
+
+// GetBlockRangeAttestation returns the range attestation carried by the block
+// along with the signed data of its signatures, which must be checked against
+// the block validation policy of the channel before trusting the attestation.
+// It returns nil if the block carries no attestation.
+func GetBlockRangeAttestation(block *cb.Block) (*BlockRangeAttestation, []*SignedData, error) {
+	if block.Header == nil {
+		return nil, nil, errors.New("block has no header")
+	}
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(BlockMetadataIndexRangeAttestation) ||
+		len(block.Metadata.Metadata[BlockMetadataIndexRangeAttestation]) == 0 {
+		return nil, nil, nil
+	}
+
+	md, err := GetMetadataFromBlock(block, BlockMetadataIndexRangeAttestation)
+	if err != nil {
+		return nil, nil, err
+	}
+	bra := &BlockRangeAttestation{}
+	if err := proto.Unmarshal(md.Value, bra); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal block range attestation")
+	}
+	if bra.End != block.Header.Number || bra.Start > bra.End {
+		return nil, nil, errors.Errorf("block range attestation of blocks [%d] to [%d] found in block [%d]", bra.Start, bra.End, block.Header.Number)
+	}
+
+	var signatures []*SignedData
+	for _, mdSignature := range md.Signatures {
+		shdr, err := UnmarshalSignatureHeader(mdSignature.SignatureHeader)
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "failed to unmarshal signature header of block range attestation")
+		}
+		signatures = append(signatures, &SignedData{
+			Identity:  shdr.Creator,
+			Data:      bytes.Join([][]byte{md.Value, mdSignature.SignatureHeader}, nil),
+			Signature: mdSignature.Signature,
+		})
+	}
+	if len(signatures) == 0 {
+		return nil, nil, errors.New("block range attestation is not signed")
+	}
+	return bra, signatures, nil
+}
+
+// VerifyBlockRange checks that the headers, given in order, are the chained
+// headers of the blocks of an attested range. Once the signatures of the
+// attestation are checked, the headers and the data of the blocks, whose hash
+// is in the headers, can be trusted without checking the block signatures.
+func VerifyBlockRange(attestation *BlockRangeAttestation, headers []*cb.BlockHeader) error {
+	if uint64(len(headers)) != attestation.End-attestation.Start+1 {
+		return errors.Errorf("expected the headers of blocks [%d] to [%d] but got %d headers", attestation.Start, attestation.End, len(headers))
+	}
+
+	hashes := make([][]byte, len(headers))
+	for i, header := range headers {
+		if header == nil || header.Number != attestation.Start+uint64(i) {
+			return errors.Errorf("expected the header of block [%d] at position %d", attestation.Start+uint64(i), i)
+		}
+		if i > 0 && !bytes.Equal(header.PreviousHash, hashes[i-1]) {
+			return errors.Errorf("the previous hash of block [%d] is not the hash of block [%d]", header.Number, header.Number-1)
+		}
+		hashes[i] = BlockHeaderHash(header)
+	}
+
+	if !bytes.Equal(BlockRangeDigest(hashes), attestation.Digest) {
+		return errors.Errorf("the headers of blocks [%d] to [%d] do not match the attested digest", attestation.Start, attestation.End)
+	}
+	return nil
+}
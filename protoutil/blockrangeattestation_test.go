@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoutil_test
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric/protoutil/fakes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func chainOfBlocks(start uint64, count int) []*cb.Block {
+	var blocks []*cb.Block
+	previousHash := []byte("previous")
+	for i := 0; i < count; i++ {
+		block := protoutil.NewBlock(start+uint64(i), previousHash)
+		block.Header.DataHash = []byte{byte(i)}
+		previousHash = protoutil.BlockHeaderHash(block.Header)
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func TestBlockRangeAttestation(t *testing.T) {
+	signer := &fakes.SignerSerializer{}
+	signer.SerializeReturns([]byte("orderer"), nil)
+	signer.SignReturns([]byte("signature"), nil)
+
+	blocks := chainOfBlocks(10, 4)
+	var hashes [][]byte
+	var headers []*cb.BlockHeader
+	for _, block := range blocks {
+		hashes = append(hashes, protoutil.BlockHeaderHash(block.Header))
+		headers = append(headers, block.Header)
+	}
+	last := blocks[3]
+
+	bra, signatures, err := protoutil.GetBlockRangeAttestation(last)
+	require.NoError(t, err)
+	require.Nil(t, bra)
+	require.Nil(t, signatures)
+
+	require.NoError(t, protoutil.AddBlockRangeAttestation(last, signer, 10, hashes))
+	require.Len(t, last.Metadata.Metadata, int(protoutil.BlockMetadataIndexRangeAttestation)+1)
+	bt, _, err := protoutil.GetBlockTimestamp(last)
+	require.NoError(t, err)
+	require.Nil(t, bt, "the time attestation slot must be left empty")
+
+	bra, signatures, err = protoutil.GetBlockRangeAttestation(last)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), bra.Start)
+	require.Equal(t, uint64(13), bra.End)
+	require.Equal(t, protoutil.BlockRangeDigest(hashes), bra.Digest)
+	require.Len(t, signatures, 1)
+	require.Equal(t, []byte("orderer"), signatures[0].Identity)
+	require.Equal(t, []byte("signature"), signatures[0].Signature)
+	require.Equal(t, signer.SignArgsForCall(0), signatures[0].Data)
+
+	require.NoError(t, protoutil.VerifyBlockRange(bra, headers))
+
+	err = protoutil.VerifyBlockRange(bra, headers[1:])
+	require.EqualError(t, err, "expected the headers of blocks [10] to [13] but got 3 headers")
+
+	err = protoutil.VerifyBlockRange(bra, []*cb.BlockHeader{headers[0], headers[2], headers[1], headers[3]})
+	require.EqualError(t, err, "expected the header of block [11] at position 1")
+
+	tampered := *headers[2]
+	tampered.DataHash = []byte("tampered")
+	err = protoutil.VerifyBlockRange(bra, []*cb.BlockHeader{headers[0], headers[1], &tampered, headers[3]})
+	require.EqualError(t, err, "the previous hash of block [13] is not the hash of block [12]")
+
+	tampered = *headers[0]
+	tampered.DataHash = []byte("tampered")
+	err = protoutil.VerifyBlockRange(bra, []*cb.BlockHeader{&tampered, headers[1], headers[2], headers[3]})
+	require.EqualError(t, err, "the previous hash of block [11] is not the hash of block [10]")
+
+	otherRange := chainOfBlocks(10, 4)
+	otherRange[0].Header.PreviousHash = []byte("other")
+	var otherHeaders []*cb.BlockHeader
+	for _, block := range otherRange {
+		otherHeaders = append(otherHeaders, block.Header)
+	}
+	err = protoutil.VerifyBlockRange(bra, otherHeaders)
+	require.Error(t, err)
+
+	last.Header.Number = 14
+	_, _, err = protoutil.GetBlockRangeAttestation(last)
+	require.EqualError(t, err, "block range attestation of blocks [10] to [13] found in block [14]")
+}
+
+func TestAddBlockRangeAttestationErrors(t *testing.T) {
+	signer := &fakes.SignerSerializer{}
+	blocks := chainOfBlocks(0, 2)
+	hashes := [][]byte{protoutil.BlockHeaderHash(blocks[0].Header), protoutil.BlockHeaderHash(blocks[1].Header)}
+
+	err := protoutil.AddBlockRangeAttestation(blocks[1], signer, 0, hashes[:1])
+	require.EqualError(t, err, "expected the header hashes of blocks [0] to [1] but got 1 hashes")
+
+	err = protoutil.AddBlockRangeAttestation(blocks[1], signer, 2, nil)
+	require.EqualError(t, err, "expected the header hashes of blocks [2] to [1] but got 0 hashes")
+
+	err = protoutil.AddBlockRangeAttestation(blocks[1], signer, 0, [][]byte{hashes[1], hashes[0]})
+	require.EqualError(t, err, "the last header hash of the range is not the hash of block [1]")
+
+	signer.SignReturns(nil, errors.New("boom"))
+	err = protoutil.AddBlockRangeAttestation(blocks[1], signer, 0, hashes)
+	require.EqualError(t, err, "failed to sign block range attestation: boom")
+
+	_, _, err = protoutil.GetBlockRangeAttestation(&cb.Block{})
+	require.EqualError(t, err, "block has no header")
+}
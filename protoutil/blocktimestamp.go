@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoutil
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/pkg/errors"
+)
+
+// BlockMetadataIndexTimestamp is the position in the block metadata array of the
+// time attestation of the orderer, which follows the positions of cb.BlockMetadataIndex
+const BlockMetadataIndexTimestamp = cb.BlockMetadataIndex_COMMIT_HASH + 1
+
+// BlockTimestamp is the time at which a block was cut by the orderer which
+// created it, e.g. the Raft leader. It is the value of the metadata at
+// BlockMetadataIndexTimestamp, which is replicated along with the block,
+// and every orderer which writes the block signs the value along with the
+// signature header and the header of the block.
+//
+// The struct is hand written with the protobuf tags so that it can be
+// marshaled without a generated message.
+type BlockTimestamp struct {
+	// Number is the number of the block
+	Number uint64 `protobuf:"varint,1,opt,name=number,proto3"`
+	// Timestamp is the time at which the block was cut
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3"`
+}
+
+// Reset resets
+func (bt *BlockTimestamp) Reset() { *bt = BlockTimestamp{} }
+
+// String converts to string
+func (bt *BlockTimestamp) String() string { return proto.CompactTextString(bt) }
+
+// ProtoMessage just exists to make proto happy
+func (*BlockTimestamp) ProtoMessage() {}
+
+// SetBlockTimestamp stamps the block with the time at which it is cut, without
+// any signature. The orderers sign the stamped time with SignBlockTimestamp
+// when they write the block.
+func SetBlockTimestamp(block *cb.Block, t time.Time) error {
+	if block.Header == nil {
+		return errors.New("block has no header")
+	}
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return errors.Wrap(err, "invalid block timestamp")
+	}
+	value, err := proto.Marshal(&BlockTimestamp{Number: block.Header.Number, Timestamp: ts})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block timestamp")
+	}
+	md, err := proto.Marshal(&cb.Metadata{Value: value})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block timestamp metadata")
+	}
+
+	InitBlockMetadata(block)
+	for len(block.Metadata.Metadata) <= int(BlockMetadataIndexTimestamp) {
+		block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
+	}
+	block.Metadata.Metadata[BlockMetadataIndexTimestamp] = md
+	return nil
+}
+
+// HasBlockTimestamp returns whether the block is stamped with a time
+func HasBlockTimestamp(block *cb.Block) bool {
+	return block.Metadata != nil && len(block.Metadata.Metadata) > int(BlockMetadataIndexTimestamp) &&
+		len(block.Metadata.Metadata[BlockMetadataIndexTimestamp]) != 0
+}
+
+// RemoveBlockTimestamp removes the time stamped in the block, if any
+func RemoveBlockTimestamp(block *cb.Block) {
+	if HasBlockTimestamp(block) {
+		block.Metadata.Metadata[BlockMetadataIndexTimestamp] = []byte{}
+	}
+}
+
+// SignBlockTimestamp signs the time stamped in the block with a signature of
+// the signer, replacing any previous signature
+func SignBlockTimestamp(block *cb.Block, signer identity.SignerSerializer) error {
+	if block.Header == nil {
+		return errors.New("block has no header")
+	}
+	if !HasBlockTimestamp(block) {
+		return errors.Errorf("block [%d] is not stamped with a time", block.Header.Number)
+	}
+	md, err := GetMetadataFromBlock(block, BlockMetadataIndexTimestamp)
+	if err != nil {
+		return err
+	}
+	bt := &BlockTimestamp{}
+	if err := proto.Unmarshal(md.Value, bt); err != nil {
+		return errors.Wrap(err, "failed to unmarshal block timestamp")
+	}
+	if bt.Number != block.Header.Number {
+		return errors.Errorf("block timestamp is stamped for block [%d] but found in block [%d]", bt.Number, block.Header.Number)
+	}
+
+	sh, err := NewSignatureHeader(signer)
+	if err != nil {
+		return errors.Wrap(err, "failed to create signature header")
+	}
+	shBytes, err := proto.Marshal(sh)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal signature header")
+	}
+	signature, err := Sign(signer, bytes.Join([][]byte{md.Value, shBytes, BlockHeaderBytes(block.Header)}, nil))
+	if err != nil {
+		return errors.Wrap(err, "failed to sign block timestamp")
+	}
+
+	signed, err := proto.Marshal(&cb.Metadata{
+		Value: md.Value,
+		Signatures: []*cb.MetadataSignature{
+			{SignatureHeader: shBytes, Signature: signature},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal block timestamp metadata")
+	}
+	block.Metadata.Metadata[BlockMetadataIndexTimestamp] = signed
+	return nil
+}
+
+// GetBlockTimestamp returns the time attestation of the block along with the
+// signed data of its signatures, which must be checked against the block
+// validation policy of the channel before trusting the time. It returns nil if
+// the block carries no attestation.
+func GetBlockTimestamp(block *cb.Block) (*BlockTimestamp, []*SignedData, error) {
+	if block.Header == nil {
+		return nil, nil, errors.New("block has no header")
+	}
+	if !HasBlockTimestamp(block) {
+		return nil, nil, nil
+	}
+
+	md, err := GetMetadataFromBlock(block, BlockMetadataIndexTimestamp)
+	if err != nil {
+		return nil, nil, err
+	}
+	bt := &BlockTimestamp{}
+	if err := proto.Unmarshal(md.Value, bt); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal block timestamp")
+	}
+	if bt.Number != block.Header.Number {
+		return nil, nil, errors.Errorf("block timestamp is attested for block [%d] but found in block [%d]", bt.Number, block.Header.Number)
+	}
+	if _, err := ptypes.Timestamp(bt.Timestamp); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid block timestamp")
+	}
+
+	var signatures []*SignedData
+	for _, mdSignature := range md.Signatures {
+		shdr, err := UnmarshalSignatureHeader(mdSignature.SignatureHeader)
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "failed to unmarshal signature header of block timestamp")
+		}
+		signatures = append(signatures, &SignedData{
+			Identity:  shdr.Creator,
+			Data:      bytes.Join([][]byte{md.Value, mdSignature.SignatureHeader, BlockHeaderBytes(block.Header)}, nil),
+			Signature: mdSignature.Signature,
+		})
+	}
+	if len(signatures) == 0 {
+		return nil, nil, errors.New("block timestamp is not signed")
+	}
+	return bt, signatures, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric/protoutil/fakes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTimestamp(t *testing.T) {
+	signer := &fakes.SignerSerializer{}
+	signer.SerializeReturns([]byte("orderer"), nil)
+	signer.SignReturns([]byte("signature"), nil)
+
+	block := protoutil.NewBlock(5, []byte("previous"))
+	bt, signatures, err := protoutil.GetBlockTimestamp(block)
+	require.NoError(t, err)
+	require.Nil(t, bt)
+	require.Nil(t, signatures)
+
+	require.False(t, protoutil.HasBlockTimestamp(block))
+	require.EqualError(t, protoutil.SignBlockTimestamp(block, signer), "block [5] is not stamped with a time")
+
+	// the block is stamped when it is cut, and signed when it is written
+	now := time.Unix(1600000000, 42)
+	require.NoError(t, protoutil.SetBlockTimestamp(block, now))
+	require.Len(t, block.Metadata.Metadata, int(protoutil.BlockMetadataIndexTimestamp)+1)
+	require.True(t, protoutil.HasBlockTimestamp(block))
+	_, _, err = protoutil.GetBlockTimestamp(block)
+	require.EqualError(t, err, "block timestamp is not signed")
+	require.NoError(t, protoutil.SignBlockTimestamp(block, signer))
+
+	bt, signatures, err = protoutil.GetBlockTimestamp(block)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), bt.Number)
+	ts, err := ptypes.Timestamp(bt.Timestamp)
+	require.NoError(t, err)
+	require.True(t, now.Equal(ts))
+	require.Len(t, signatures, 1)
+	require.Equal(t, []byte("orderer"), signatures[0].Identity)
+	require.Equal(t, []byte("signature"), signatures[0].Signature)
+	require.Equal(t, 1, signer.SignCallCount())
+	require.Equal(t, signer.SignArgsForCall(0), signatures[0].Data)
+
+	// the attestation covers the header of the block
+	block.Header.DataHash = []byte("other")
+	_, signatures2, err := protoutil.GetBlockTimestamp(block)
+	require.NoError(t, err)
+	require.NotEqual(t, signatures[0].Data, signatures2[0].Data)
+
+	block.Header.Number = 6
+	_, _, err = protoutil.GetBlockTimestamp(block)
+	require.EqualError(t, err, "block timestamp is attested for block [5] but found in block [6]")
+
+	block.Metadata.Metadata[protoutil.BlockMetadataIndexTimestamp] = protoutil.MarshalOrPanic(&cb.Metadata{Signatures: []*cb.MetadataSignature{{}}})
+	block.Header.Number = 0
+	_, _, err = protoutil.GetBlockTimestamp(block)
+	require.EqualError(t, err, "invalid block timestamp: timestamp: nil Timestamp")
+
+	require.NoError(t, protoutil.SetBlockTimestamp(block, now))
+	signer.SignReturns(nil, errors.New("boom"))
+	require.EqualError(t, protoutil.SignBlockTimestamp(block, signer), "failed to sign block timestamp: boom")
+
+	protoutil.RemoveBlockTimestamp(block)
+	require.False(t, protoutil.HasBlockTimestamp(block))
+}
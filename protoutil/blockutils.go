@@ -12,6 +12,7 @@ import (
 	"encoding/asn1"
 	"math/big"
 
+	"github.com/cetcxinlian/cryptogm/sm3"
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/pkg/errors"
@@ -62,6 +63,14 @@ func BlockHeaderHash(b *cb.BlockHeader) []byte {
 	return sum[:]
 }
 
+// BlockHeaderHashSM3 returns the SM3 hash of the block header. The block
+// headers are chained by their SHA256 hash, see BlockHeaderHash, but the peers
+// also index the blocks by the SM3 hash of their header for the clients of
+// the channels using GM hashing.
+func BlockHeaderHashSM3(b *cb.BlockHeader) []byte {
+	return sm3.SumSM3(BlockHeaderBytes(b))
+}
+
 func BlockDataHash(b *cb.BlockData) []byte {
 	sum := sha256.Sum256(bytes.Join(b.Data, nil))
 	return sum[:]
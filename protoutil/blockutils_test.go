@@ -12,6 +12,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/cetcxinlian/cryptogm/sm3"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -51,6 +52,7 @@ func TestNewBlock(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, asn1Bytes, protoutil.BlockHeaderBytes(block.Header), "Incorrect marshaled blockheader bytes")
 	assert.Equal(t, headerHash[:], protoutil.BlockHeaderHash(block.Header), "Incorrect blockheader hash")
+	assert.Equal(t, sm3.SumSM3(asn1Bytes), protoutil.BlockHeaderHashSM3(block.Header), "Incorrect blockheader SM3 hash")
 }
 
 func TestGoodBlockHeaderBytes(t *testing.T) {
@@ -178,7 +178,7 @@ func SignOrPanic(signer identity.Signer, msg []byte) []byte {
 		panic(errors.New("invalid signer. cannot be nil"))
 	}
 
-	sigma, err := signer.Sign(msg)
+	sigma, err := Sign(signer, msg)
 	if err != nil {
 		panic(fmt.Errorf("failed generating signature: %s", err))
 	}
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoutil
+
+import (
+	"crypto/ecdsa"
+	"encoding/pem"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SM2 is the signature scheme of identities with an SM2 key
+	SM2 = "SM2"
+	// ECDSA is the signature scheme of identities with an ECDSA key
+	ECDSA = "ECDSA"
+)
+
+// SignatureAlgorithm is the signature scheme of an identity and the hash
+// family the messages are hashed with before they are signed
+type SignatureAlgorithm struct {
+	Scheme     string
+	HashFamily string
+}
+
+// AlgorithmSigner is implemented by signers that can hash the messages
+// they sign with the hash family of their signature algorithm, rather than
+// with the hash family configured for their MSP
+type AlgorithmSigner interface {
+	identity.Signer
+	// SignatureAlgorithm returns the signature algorithm of the signer,
+	// or nil if it is not known
+	SignatureAlgorithm() *SignatureAlgorithm
+	// SignWithHashFamily signs msg hashed with the given hash family
+	SignWithHashFamily(msg []byte, hashFamily string) ([]byte, error)
+}
+
+// SignatureAlgorithmOf returns the signature algorithm of a serialized
+// identity, which is SM2 with SM3 for identities with an SM2 key and ECDSA
+// with SHA-256 for identities with an ECDSA key
+func SignatureAlgorithmOf(serializedIdentity []byte) (*SignatureAlgorithm, error) {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, sID); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling serialized identity")
+	}
+	bl, _ := pem.Decode(sID.IdBytes)
+	if bl == nil {
+		return nil, errors.Errorf("could not decode the PEM structure of the identity of %s", sID.Mspid)
+	}
+	cert, err := x509.ParseCertificate(bl.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing the certificate of the identity of %s", sID.Mspid)
+	}
+	return SignatureAlgorithmOfKey(cert.PublicKey)
+}
+
+// SignatureAlgorithmOfKey returns the signature algorithm of an identity
+// with the given public key
+func SignatureAlgorithmOfKey(pub interface{}) (*SignatureAlgorithm, error) {
+	switch k := pub.(type) {
+	case *sm2.PublicKey:
+		return &SignatureAlgorithm{Scheme: SM2, HashFamily: bccsp.SM3}, nil
+	case *ecdsa.PublicKey:
+		if k.Curve == sm2.P256Sm2() {
+			return &SignatureAlgorithm{Scheme: SM2, HashFamily: bccsp.SM3}, nil
+		}
+		return &SignatureAlgorithm{Scheme: ECDSA, HashFamily: bccsp.SHA2}, nil
+	default:
+		return nil, errors.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// Sign signs msg with the signature algorithm of the signer if the signer
+// is an AlgorithmSigner, and with the default hash family of the signer
+// otherwise. This lets the identities of a channel migrate from one
+// signature algorithm to the other one by one.
+func Sign(signer identity.Signer, msg []byte) ([]byte, error) {
+	as, ok := signer.(AlgorithmSigner)
+	if !ok {
+		return signer.Sign(msg)
+	}
+	alg := as.SignatureAlgorithm()
+	if alg == nil {
+		return signer.Sign(msg)
+	}
+	return as.SignWithHashFamily(msg, alg.HashFamily)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protoutil_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cetcxinlian/cryptogm/sm2"
+	"github.com/cetcxinlian/cryptogm/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric/protoutil/fakes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serializedIdentity(t *testing.T, pub, priv interface{}) []byte {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	require.NoError(t, err)
+	sID, err := proto.Marshal(&msp.SerializedIdentity{
+		Mspid:   "SampleOrg",
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+	require.NoError(t, err)
+	return sID
+}
+
+func sm2Identity(t *testing.T) []byte {
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return serializedIdentity(t, &key.PublicKey, key)
+}
+
+func ecdsaIdentity(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return serializedIdentity(t, &key.PublicKey, key)
+}
+
+func TestSignatureAlgorithmOf(t *testing.T) {
+	alg, err := protoutil.SignatureAlgorithmOf(sm2Identity(t))
+	assert.NoError(t, err)
+	assert.Equal(t, &protoutil.SignatureAlgorithm{Scheme: protoutil.SM2, HashFamily: bccsp.SM3}, alg)
+
+	alg, err = protoutil.SignatureAlgorithmOf(ecdsaIdentity(t))
+	assert.NoError(t, err)
+	assert.Equal(t, &protoutil.SignatureAlgorithm{Scheme: protoutil.ECDSA, HashFamily: bccsp.SHA2}, alg)
+
+	_, err = protoutil.SignatureAlgorithmOf([]byte("garbage"))
+	assert.Contains(t, err.Error(), "error unmarshaling serialized identity")
+
+	sID, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "SampleOrg", IdBytes: []byte("no PEM")})
+	require.NoError(t, err)
+	_, err = protoutil.SignatureAlgorithmOf(sID)
+	assert.EqualError(t, err, "could not decode the PEM structure of the identity of SampleOrg")
+
+	_, err = protoutil.SignatureAlgorithmOfKey("not a key")
+	assert.EqualError(t, err, "unsupported public key type string")
+}
+
+type algorithmSigner struct {
+	*fakes.SignerSerializer
+	algorithm    *protoutil.SignatureAlgorithm
+	hashFamilies []string
+}
+
+func (s *algorithmSigner) SignatureAlgorithm() *protoutil.SignatureAlgorithm {
+	return s.algorithm
+}
+
+func (s *algorithmSigner) SignWithHashFamily(msg []byte, hashFamily string) ([]byte, error) {
+	s.hashFamilies = append(s.hashFamilies, hashFamily)
+	return append([]byte(hashFamily+":"), msg...), nil
+}
+
+func TestSign(t *testing.T) {
+	// Signers that can't select the hash family sign with their default
+	fakeSigner := &fakes.SignerSerializer{}
+	fakeSigner.SignReturns([]byte("default"), nil)
+	sig, err := protoutil.Sign(fakeSigner, []byte("msg"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("default"), sig)
+
+	// Other signers sign with the hash family of their identity
+	for _, tc := range []struct {
+		identity   []byte
+		hashFamily string
+	}{
+		{identity: sm2Identity(t), hashFamily: bccsp.SM3},
+		{identity: ecdsaIdentity(t), hashFamily: bccsp.SHA2},
+	} {
+		alg, err := protoutil.SignatureAlgorithmOf(tc.identity)
+		assert.NoError(t, err)
+		signer := &algorithmSigner{SignerSerializer: &fakes.SignerSerializer{}, algorithm: alg}
+		sig, err := protoutil.Sign(signer, []byte("msg"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(tc.hashFamily+":msg"), sig)
+		assert.Equal(t, 0, signer.SignCallCount())
+
+		// The signing helpers go through it as well
+		signed, err := protoutil.GetSignedProposal(&pb.Proposal{Payload: []byte("payload")}, signer)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.hashFamily+":", string(signed.Signature[:len(tc.hashFamily)+1]))
+		assert.Equal(t, []string{tc.hashFamily, tc.hashFamily}, signer.hashFamilies)
+	}
+
+	// Signers whose algorithm is unknown sign with their default
+	signer := &algorithmSigner{SignerSerializer: &fakes.SignerSerializer{}}
+	signer.SignReturns([]byte("default"), nil)
+	sig, err = protoutil.Sign(signer, []byte("msg"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("default"), sig)
+	assert.Empty(t, signer.hashFamilies)
+}
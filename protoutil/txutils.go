@@ -107,7 +107,7 @@ func CreateSignedEnvelopeWithTLSBinding(
 
 	var sig []byte
 	if signer != nil {
-		sig, err = signer.Sign(paylBytes)
+		sig, err = Sign(signer, paylBytes)
 		if err != nil {
 			return nil, err
 		}
@@ -146,12 +146,6 @@ func CreateSignedTx(
 		return nil, err
 	}
 
-	// the original payload
-	pPayl, err := UnmarshalChaincodeProposalPayload(proposal.Payload)
-	if err != nil {
-		return nil, err
-	}
-
 	// check that the signer is the same that is referenced in the header
 	// TODO: maybe worth removing?
 	signerBytes, err := signer.Serialize()
@@ -168,6 +162,46 @@ func CreateSignedTx(
 		return nil, errors.New("signer must be the same as the one referenced in the header")
 	}
 
+	payl, err := CreateUnsignedTx(proposal, resps...)
+	if err != nil {
+		return nil, err
+	}
+	paylBytes, err := GetBytesPayload(payl)
+	if err != nil {
+		return nil, err
+	}
+
+	// sign the payload
+	sig, err := Sign(signer, paylBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// here's the envelope
+	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
+}
+
+// CreateUnsignedTx assembles the payload of a transaction from a proposal
+// and its matching endorsements. The marshaled payload is what the creator
+// of the proposal signs to produce the transaction envelope, which allows the
+// signature to be produced outside of the process.
+func CreateUnsignedTx(proposal *peer.Proposal, resps ...*peer.ProposalResponse) (*common.Payload, error) {
+	if len(resps) == 0 {
+		return nil, errors.New("at least one proposal response is required")
+	}
+
+	// the original header
+	hdr, err := UnmarshalHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	// the original payload
+	pPayl, err := UnmarshalChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, err
+	}
+
 	// ensure that all actions are bitwise equal and that they are successful
 	var a1 []byte
 	for n, r := range resps {
@@ -220,20 +254,7 @@ func CreateSignedTx(
 	}
 
 	// create the payload
-	payl := &common.Payload{Header: hdr, Data: txBytes}
-	paylBytes, err := GetBytesPayload(payl)
-	if err != nil {
-		return nil, err
-	}
-
-	// sign the payload
-	sig, err := signer.Sign(paylBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	// here's the envelope
-	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
+	return &common.Payload{Header: hdr, Data: txBytes}, nil
 }
 
 // CreateProposalResponse creates a proposal response.
@@ -272,7 +293,7 @@ func CreateProposalResponse(
 
 	// sign the concatenation of the proposal response and the serialized
 	// endorser identity with this endorser's key
-	signature, err := signingEndorser.Sign(append(prpBytes, endorser...))
+	signature, err := Sign(signingEndorser, append(prpBytes, endorser...))
 	if err != nil {
 		return nil, errors.WithMessage(err, "could not sign the proposal response payload")
 	}
@@ -344,7 +365,7 @@ func GetSignedProposal(prop *peer.Proposal, signer Signer) (*peer.SignedProposal
 		return nil, err
 	}
 
-	signature, err := signer.Sign(propBytes)
+	signature, err := Sign(signer, propBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -234,6 +234,49 @@ func TestCreateSignedTx(t *testing.T) {
 
 }
 
+func TestCreateUnsignedTx(t *testing.T) {
+	signID := &fakes.SignerSerializer{}
+	signID.SerializeReturns([]byte("signer"), nil)
+	signID.SignReturns([]byte("signature"), nil)
+
+	prop, _, err := protoutil.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, "testchannelid", &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}},
+	}, []byte("signer"))
+	assert.NoError(t, err)
+	responses := []*pb.ProposalResponse{{
+		Payload:     []byte("payload"),
+		Endorsement: &pb.Endorsement{Endorser: []byte("endorser")},
+		Response:    &pb.Response{Status: int32(200)},
+	}}
+
+	payl, err := protoutil.CreateUnsignedTx(prop, responses...)
+	assert.NoError(t, err)
+	hdr, err := protoutil.UnmarshalHeader(prop.Header)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(hdr, payl.Header))
+
+	// the signed transaction carries the same payload
+	env, err := protoutil.CreateSignedTx(prop, signID, responses...)
+	assert.NoError(t, err)
+	assert.Equal(t, protoutil.MarshalOrPanic(payl), env.Payload)
+	assert.Equal(t, []byte("signature"), env.Signature)
+	assert.Equal(t, env.Payload, signID.SignArgsForCall(0))
+
+	// only the signed transaction requires the signer to be the creator
+	signID.SerializeReturns([]byte("someone else"), nil)
+	_, err = protoutil.CreateSignedTx(prop, signID, responses...)
+	assert.EqualError(t, err, "signer must be the same as the one referenced in the header")
+	_, err = protoutil.CreateUnsignedTx(prop, responses...)
+	assert.NoError(t, err)
+
+	_, err = protoutil.CreateUnsignedTx(prop)
+	assert.EqualError(t, err, "at least one proposal response is required")
+
+	responses[0].Response.Status = 500
+	_, err = protoutil.CreateUnsignedTx(prop, responses...)
+	assert.EqualError(t, err, "proposal response was not successful, error code 500, msg ")
+}
+
 func TestCreateSignedTxStatus(t *testing.T) {
 	serializedExtension, err := proto.Marshal(&pb.ChaincodeHeaderExtension{})
 	assert.NoError(t, err)
@@ -11,6 +11,7 @@ set -eux -o pipefail
 PROTO_DIRS="$(find "$(pwd)" \
     -path "$(pwd)/vendor" -prune -o \
     -path "$(pwd)/build" -prune -o \
+    -path "$(pwd)/third_party" -prune -o \
     -name '*.proto' -print0 | \
     xargs -0 -n 1 dirname | \
     sort -u | grep -v testdata)"
@@ -18,3 +19,13 @@ PROTO_DIRS="$(find "$(pwd)" \
 for dir in ${PROTO_DIRS}; do
     protoc --proto_path="$dir" --go_out=plugins=grpc,paths=source_relative:"$dir" "$dir"/*.proto
 done
+
+# The imports of the Fabric protos are relative to the root of their module
+PROTOS_MODULE="$(pwd)/third_party/fabric-protos-go"
+API_PROTO_DIRS="$(find "$PROTOS_MODULE" -name '*.proto' -print0 | \
+    xargs -0 -n 1 dirname | \
+    sort -u)"
+
+for dir in ${API_PROTO_DIRS}; do
+    protoc --proto_path="$PROTOS_MODULE" --go_out=plugins=grpc,paths=source_relative:"$PROTOS_MODULE" "$dir"/*.proto
+done
@@ -32,6 +32,7 @@ function filterExcludedAndGeneratedFiles {
         '^NOTICE$'
         '(^|/)testdata\/'
         '(^|/)vendor\/'
+        '(^|/)third_party\/'
         '(^|/)Pipfile$'
         '(^|/)Pipfile\.lock$'
     )
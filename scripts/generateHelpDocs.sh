@@ -44,7 +44,7 @@ generateHelpText \
         docs/wrappers/license_postscript.md \
         "${commands[@]}"
 
-commands=("peer chaincode install" "peer chaincode instantiate" "peer chaincode invoke" "peer chaincode list" "peer chaincode package" "peer chaincode query" "peer chaincode signpackage" "peer chaincode upgrade")
+commands=("peer chaincode install" "peer chaincode instantiate" "peer chaincode invoke" "peer chaincode list" "peer chaincode package" "peer chaincode query" "peer chaincode signpackage" "peer chaincode upgrade" "peer chaincode build-proposal" "peer chaincode build-transaction" "peer chaincode finalize-transaction")
 generateHelpText \
         docs/source/commands/peerchaincode.md \
         docs/wrappers/peer_chaincode_preamble.md \
@@ -65,6 +65,13 @@ generateHelpText \
         docs/wrappers/peer_channel_postscript.md \
         "${commands[@]}"
 
+commands=("peer shell")
+generateHelpText \
+        docs/source/commands/peershell.md \
+        docs/wrappers/peer_shell_preamble.md \
+        docs/wrappers/license_postscript.md \
+        "${commands[@]}"
+
 commands=("peer node start" "peer node reset" "peer node rollback")
 generateHelpText \
         docs/source/commands/peernode.md \
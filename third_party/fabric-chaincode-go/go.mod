@@ -0,0 +1,19 @@
+module github.com/hyperledger/fabric-chaincode-go
+
+go 1.12
+
+require (
+	github.com/cetcxinlian/cryptogm v0.0.0-20200806165024-f3ca35db27b0
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.3.2
+	github.com/hyperledger/fabric-protos-go v0.0.0-20190919234611-2a87503ac7c9
+	github.com/kr/pretty v0.1.0 // indirect
+	github.com/stretchr/testify v1.4.0
+	golang.org/x/net v0.0.0-20190522155817-f3200d17e092 // indirect
+	golang.org/x/sys v0.0.0-20190710143415-6ec70d6a5542 // indirect
+	google.golang.org/genproto v0.0.0-20180831171423-11092d34479b // indirect
+	google.golang.org/grpc v1.23.0
+	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
+)
+
+replace github.com/hyperledger/fabric-protos-go => ../fabric-protos-go
@@ -0,0 +1,269 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: orderer/etcdraft/configuration.proto
+
+package etcdraft
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ConfigMetadata is serialized and set as the value of ConsensusType.Metadata in
+// a channel configuration when the ConsensusType.Type is set "etcdraft".
+type ConfigMetadata struct {
+	Consenters []*Consenter `protobuf:"bytes,1,rep,name=consenters,proto3" json:"consenters,omitempty"`
+	Options    *Options     `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// The client TLS certificates of the consenters which replicate the
+	// channel as Raft learners, without voting.
+	LearnerClientTlsCerts [][]byte `protobuf:"bytes,3,rep,name=learner_client_tls_certs,json=learnerClientTlsCerts,proto3" json:"learner_client_tls_certs,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
+}
+
+func (m *ConfigMetadata) Reset()         { *m = ConfigMetadata{} }
+func (m *ConfigMetadata) String() string { return proto.CompactTextString(m) }
+func (*ConfigMetadata) ProtoMessage()    {}
+func (*ConfigMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6f12d215c949b072, []int{0}
+}
+
+func (m *ConfigMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigMetadata.Unmarshal(m, b)
+}
+func (m *ConfigMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigMetadata.Marshal(b, m, deterministic)
+}
+func (m *ConfigMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigMetadata.Merge(m, src)
+}
+func (m *ConfigMetadata) XXX_Size() int {
+	return xxx_messageInfo_ConfigMetadata.Size(m)
+}
+func (m *ConfigMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigMetadata proto.InternalMessageInfo
+
+func (m *ConfigMetadata) GetConsenters() []*Consenter {
+	if m != nil {
+		return m.Consenters
+	}
+	return nil
+}
+
+func (m *ConfigMetadata) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *ConfigMetadata) GetLearnerClientTlsCerts() [][]byte {
+	if m != nil {
+		return m.LearnerClientTlsCerts
+	}
+	return nil
+}
+
+// Consenter represents a consenting node (i.e. replica).
+type Consenter struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port                 uint32   `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	ClientTlsCert        []byte   `protobuf:"bytes,3,opt,name=client_tls_cert,json=clientTlsCert,proto3" json:"client_tls_cert,omitempty"`
+	ServerTlsCert        []byte   `protobuf:"bytes,4,opt,name=server_tls_cert,json=serverTlsCert,proto3" json:"server_tls_cert,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Consenter) Reset()         { *m = Consenter{} }
+func (m *Consenter) String() string { return proto.CompactTextString(m) }
+func (*Consenter) ProtoMessage()    {}
+func (*Consenter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6f12d215c949b072, []int{1}
+}
+
+func (m *Consenter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Consenter.Unmarshal(m, b)
+}
+func (m *Consenter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Consenter.Marshal(b, m, deterministic)
+}
+func (m *Consenter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Consenter.Merge(m, src)
+}
+func (m *Consenter) XXX_Size() int {
+	return xxx_messageInfo_Consenter.Size(m)
+}
+func (m *Consenter) XXX_DiscardUnknown() {
+	xxx_messageInfo_Consenter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Consenter proto.InternalMessageInfo
+
+func (m *Consenter) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Consenter) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Consenter) GetClientTlsCert() []byte {
+	if m != nil {
+		return m.ClientTlsCert
+	}
+	return nil
+}
+
+func (m *Consenter) GetServerTlsCert() []byte {
+	if m != nil {
+		return m.ServerTlsCert
+	}
+	return nil
+}
+
+// Options to be specified for all the etcd/raft nodes. These can be modified on a
+// per-channel basis.
+type Options struct {
+	TickInterval      string `protobuf:"bytes,1,opt,name=tick_interval,json=tickInterval,proto3" json:"tick_interval,omitempty"`
+	ElectionTick      uint32 `protobuf:"varint,2,opt,name=election_tick,json=electionTick,proto3" json:"election_tick,omitempty"`
+	HeartbeatTick     uint32 `protobuf:"varint,3,opt,name=heartbeat_tick,json=heartbeatTick,proto3" json:"heartbeat_tick,omitempty"`
+	MaxInflightBlocks uint32 `protobuf:"varint,4,opt,name=max_inflight_blocks,json=maxInflightBlocks,proto3" json:"max_inflight_blocks,omitempty"`
+	// Take snapshot when cumulative data exceeds certain size in bytes.
+	SnapshotIntervalSize uint32 `protobuf:"varint,5,opt,name=snapshot_interval_size,json=snapshotIntervalSize,proto3" json:"snapshot_interval_size,omitempty"`
+	// Retain only as many of the most recent blocks, and all the config blocks, in the ledger.
+	RetainedBlocks       uint64   `protobuf:"varint,6,opt,name=retained_blocks,json=retainedBlocks,proto3" json:"retained_blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Options) Reset()         { *m = Options{} }
+func (m *Options) String() string { return proto.CompactTextString(m) }
+func (*Options) ProtoMessage()    {}
+func (*Options) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6f12d215c949b072, []int{2}
+}
+
+func (m *Options) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Options.Unmarshal(m, b)
+}
+func (m *Options) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Options.Marshal(b, m, deterministic)
+}
+func (m *Options) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Options.Merge(m, src)
+}
+func (m *Options) XXX_Size() int {
+	return xxx_messageInfo_Options.Size(m)
+}
+func (m *Options) XXX_DiscardUnknown() {
+	xxx_messageInfo_Options.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Options proto.InternalMessageInfo
+
+func (m *Options) GetTickInterval() string {
+	if m != nil {
+		return m.TickInterval
+	}
+	return ""
+}
+
+func (m *Options) GetElectionTick() uint32 {
+	if m != nil {
+		return m.ElectionTick
+	}
+	return 0
+}
+
+func (m *Options) GetHeartbeatTick() uint32 {
+	if m != nil {
+		return m.HeartbeatTick
+	}
+	return 0
+}
+
+func (m *Options) GetMaxInflightBlocks() uint32 {
+	if m != nil {
+		return m.MaxInflightBlocks
+	}
+	return 0
+}
+
+func (m *Options) GetSnapshotIntervalSize() uint32 {
+	if m != nil {
+		return m.SnapshotIntervalSize
+	}
+	return 0
+}
+
+func (m *Options) GetRetainedBlocks() uint64 {
+	if m != nil {
+		return m.RetainedBlocks
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ConfigMetadata)(nil), "etcdraft.ConfigMetadata")
+	proto.RegisterType((*Consenter)(nil), "etcdraft.Consenter")
+	proto.RegisterType((*Options)(nil), "etcdraft.Options")
+}
+
+func init() {
+	proto.RegisterFile("orderer/etcdraft/configuration.proto", fileDescriptor_6f12d215c949b072)
+}
+
+var fileDescriptor_6f12d215c949b072 = []byte{
+	// 435 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0x4f, 0x6b, 0xdc, 0x30,
+	0x10, 0xc5, 0x71, 0xbc, 0x4d, 0x1a, 0x65, 0xbd, 0x21, 0x4a, 0x5b, 0x7c, 0x34, 0xdb, 0x7f, 0x86,
+	0x12, 0x1b, 0x92, 0x42, 0x7b, 0xce, 0x9e, 0x72, 0x28, 0x05, 0x37, 0xa7, 0x5e, 0x8c, 0x2c, 0xcf,
+	0xda, 0xea, 0x7a, 0x2d, 0x33, 0x9a, 0x84, 0x34, 0xd7, 0x9e, 0xfa, 0x59, 0xfa, 0x25, 0x8b, 0x64,
+	0x6b, 0xb3, 0xe4, 0x26, 0xde, 0xfb, 0xbd, 0x99, 0x27, 0x18, 0xf6, 0x4e, 0x63, 0x0d, 0x08, 0x98,
+	0x03, 0xc9, 0x1a, 0xc5, 0x9a, 0x72, 0xa9, 0xfb, 0xb5, 0x6a, 0xee, 0x50, 0x90, 0xd2, 0x7d, 0x36,
+	0xa0, 0x26, 0xcd, 0x5f, 0x7a, 0x77, 0xf9, 0x2f, 0x60, 0x8b, 0x95, 0x23, 0xbe, 0x01, 0x89, 0x5a,
+	0x90, 0xe0, 0x57, 0x8c, 0x49, 0xdd, 0x1b, 0xe8, 0x09, 0xd0, 0xc4, 0x41, 0x12, 0xa6, 0x27, 0x97,
+	0xe7, 0x99, 0x4f, 0x64, 0x2b, 0xef, 0x15, 0x7b, 0x18, 0xff, 0xc4, 0x8e, 0xf4, 0x60, 0x37, 0x98,
+	0xf8, 0x20, 0x09, 0xd2, 0x93, 0xcb, 0xb3, 0xa7, 0xc4, 0xf7, 0xd1, 0x28, 0x3c, 0xc1, 0xbf, 0xb0,
+	0xb8, 0x03, 0x81, 0x3d, 0x60, 0x29, 0x3b, 0x05, 0x3d, 0x95, 0xd4, 0x99, 0x52, 0x02, 0x92, 0x89,
+	0xc3, 0x24, 0x4c, 0xe7, 0xc5, 0xeb, 0xc9, 0x5f, 0x39, 0xfb, 0xb6, 0x33, 0x2b, 0x6b, 0x2e, 0xff,
+	0x04, 0xec, 0x78, 0xb7, 0x9f, 0x73, 0x36, 0x6b, 0xb5, 0xa1, 0x38, 0x48, 0x82, 0xf4, 0xb8, 0x70,
+	0x6f, 0xab, 0x0d, 0x1a, 0xc9, 0x95, 0x88, 0x0a, 0xf7, 0xe6, 0x1f, 0xd8, 0xe9, 0xb3, 0x35, 0x71,
+	0x98, 0x04, 0xe9, 0xbc, 0x88, 0xe4, 0xfe, 0x78, 0xcb, 0x19, 0xc0, 0x7b, 0xc0, 0x27, 0x6e, 0x36,
+	0x72, 0xa3, 0x3c, 0x71, 0xcb, 0xbf, 0x07, 0xec, 0x68, 0xfa, 0x13, 0x7f, 0xcb, 0x22, 0x52, 0x72,
+	0x53, 0x2a, 0xdb, 0xe8, 0x5e, 0x74, 0x53, 0x99, 0xb9, 0x15, 0x6f, 0x26, 0xcd, 0x42, 0xd0, 0x81,
+	0xb4, 0x89, 0xd2, 0x1a, 0x53, 0xbb, 0xb9, 0x17, 0x6f, 0x95, 0xdc, 0xf0, 0xf7, 0x6c, 0xd1, 0x82,
+	0x40, 0xaa, 0x40, 0xd0, 0x48, 0x85, 0x8e, 0x8a, 0x76, 0xaa, 0xc3, 0x32, 0x76, 0xbe, 0x15, 0x0f,
+	0xa5, 0xea, 0xd7, 0x9d, 0x6a, 0x5a, 0x2a, 0xab, 0x4e, 0xcb, 0x8d, 0x71, 0x45, 0xa3, 0xe2, 0x6c,
+	0x2b, 0x1e, 0x6e, 0x26, 0xe7, 0xda, 0x19, 0xfc, 0x33, 0x7b, 0x63, 0x7a, 0x31, 0x98, 0x56, 0xd3,
+	0xae, 0x64, 0x69, 0xd4, 0x23, 0xc4, 0x2f, 0x5c, 0xe4, 0x95, 0x77, 0x7d, 0xdb, 0x1f, 0xea, 0x11,
+	0xf8, 0x47, 0x76, 0x8a, 0x40, 0x42, 0xf5, 0x50, 0xfb, 0x0d, 0x87, 0x49, 0x90, 0xce, 0x8a, 0x85,
+	0x97, 0xc7, 0xf1, 0xd7, 0xbf, 0x58, 0xa6, 0xb1, 0xc9, 0xda, 0xdf, 0x03, 0x60, 0x07, 0x75, 0x03,
+	0x98, 0xad, 0x45, 0x85, 0x4a, 0x8e, 0x97, 0x66, 0xb2, 0xe9, 0x1e, 0x77, 0xd7, 0xf0, 0xf3, 0x6b,
+	0xa3, 0xa8, 0xbd, 0xab, 0x32, 0xa9, 0xb7, 0xf9, 0x5e, 0x2c, 0x1f, 0x63, 0x17, 0x63, 0xec, 0xa2,
+	0xd1, 0xf9, 0xf3, 0x4b, 0xae, 0x0e, 0x9d, 0x77, 0xf5, 0x7f, 0x00, 0xfc, 0xb3, 0x87, 0x20, 0xe4,
+	0x02, 0x00, 0x00,
+}
@@ -0,0 +1,170 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peer/lifecycle/chaincode_definition.proto
+
+package lifecycle
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ChaincodeEndorsementInfo is (most) everything the peer needs to know in order
+// to execute a chaincode
+type ChaincodeEndorsementInfo struct {
+	Version              string            `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	InitRequired         bool              `protobuf:"varint,2,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	EndorsementPlugin    string            `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	Metadata             map[string][]byte `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ChaincodeEndorsementInfo) Reset()         { *m = ChaincodeEndorsementInfo{} }
+func (m *ChaincodeEndorsementInfo) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeEndorsementInfo) ProtoMessage()    {}
+func (*ChaincodeEndorsementInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f0faa93bbd697c66, []int{0}
+}
+
+func (m *ChaincodeEndorsementInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeEndorsementInfo.Unmarshal(m, b)
+}
+func (m *ChaincodeEndorsementInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeEndorsementInfo.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeEndorsementInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeEndorsementInfo.Merge(m, src)
+}
+func (m *ChaincodeEndorsementInfo) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeEndorsementInfo.Size(m)
+}
+func (m *ChaincodeEndorsementInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeEndorsementInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeEndorsementInfo proto.InternalMessageInfo
+
+func (m *ChaincodeEndorsementInfo) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ChaincodeEndorsementInfo) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+func (m *ChaincodeEndorsementInfo) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *ChaincodeEndorsementInfo) GetMetadata() map[string][]byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// ValidationInfo is (most) everything the peer needs to know in order
+// to validate a transaction
+type ChaincodeValidationInfo struct {
+	ValidationPlugin     string   `protobuf:"bytes,1,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte   `protobuf:"bytes,2,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeValidationInfo) Reset()         { *m = ChaincodeValidationInfo{} }
+func (m *ChaincodeValidationInfo) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeValidationInfo) ProtoMessage()    {}
+func (*ChaincodeValidationInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f0faa93bbd697c66, []int{1}
+}
+
+func (m *ChaincodeValidationInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeValidationInfo.Unmarshal(m, b)
+}
+func (m *ChaincodeValidationInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeValidationInfo.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeValidationInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeValidationInfo.Merge(m, src)
+}
+func (m *ChaincodeValidationInfo) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeValidationInfo.Size(m)
+}
+func (m *ChaincodeValidationInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeValidationInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeValidationInfo proto.InternalMessageInfo
+
+func (m *ChaincodeValidationInfo) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *ChaincodeValidationInfo) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ChaincodeEndorsementInfo)(nil), "lifecycle.ChaincodeEndorsementInfo")
+	proto.RegisterMapType((map[string][]byte)(nil), "lifecycle.ChaincodeEndorsementInfo.MetadataEntry")
+	proto.RegisterType((*ChaincodeValidationInfo)(nil), "lifecycle.ChaincodeValidationInfo")
+}
+
+func init() {
+	proto.RegisterFile("peer/lifecycle/chaincode_definition.proto", fileDescriptor_f0faa93bbd697c66)
+}
+
+var fileDescriptor_f0faa93bbd697c66 = []byte{
+	// 336 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x4b, 0x4f, 0xfa, 0x40,
+	0x10, 0x4f, 0xe1, 0xff, 0x57, 0x58, 0x21, 0x81, 0x95, 0xc4, 0xc6, 0x13, 0xc1, 0x0b, 0x46, 0xd9,
+	0x06, 0x4d, 0x8c, 0xd1, 0x9b, 0x86, 0x83, 0x07, 0x12, 0xd3, 0x83, 0x07, 0x2f, 0x64, 0xe9, 0x4e,
+	0xcb, 0xc6, 0x76, 0xb7, 0x0e, 0x5b, 0x92, 0x7e, 0x0f, 0x3f, 0xb0, 0xe9, 0xbb, 0x1c, 0xbc, 0xed,
+	0xcc, 0xef, 0x31, 0xaf, 0x25, 0xd7, 0x31, 0x00, 0x3a, 0xa1, 0xf4, 0xc1, 0x4b, 0xbd, 0x10, 0x1c,
+	0x6f, 0xc7, 0xa5, 0xf2, 0xb4, 0x80, 0x8d, 0x00, 0x5f, 0x2a, 0x69, 0xa4, 0x56, 0x2c, 0x46, 0x6d,
+	0x34, 0xed, 0xd7, 0xac, 0xd9, 0x4f, 0x87, 0xd8, 0xaf, 0x15, 0x73, 0xa5, 0x84, 0xc6, 0x3d, 0x44,
+	0xa0, 0xcc, 0x9b, 0xf2, 0x35, 0xb5, 0xc9, 0xe9, 0x01, 0x70, 0x2f, 0xb5, 0xb2, 0xad, 0xa9, 0x35,
+	0xef, 0xbb, 0x55, 0x48, 0xaf, 0xc8, 0x30, 0xb3, 0xdc, 0x20, 0x7c, 0x27, 0x12, 0x41, 0xd8, 0x9d,
+	0xa9, 0x35, 0xef, 0xb9, 0x83, 0x2c, 0xe9, 0x96, 0x39, 0xba, 0x20, 0x14, 0x1a, 0xc7, 0x4d, 0x1c,
+	0x26, 0x81, 0x54, 0x76, 0x37, 0x77, 0x1a, 0xb7, 0x90, 0xf7, 0x1c, 0xa0, 0x6b, 0xd2, 0x8b, 0xc0,
+	0x70, 0xc1, 0x0d, 0xb7, 0xff, 0x4d, 0xbb, 0xf3, 0xb3, 0xbb, 0x25, 0xab, 0x1b, 0x65, 0x7f, 0x35,
+	0xc9, 0xd6, 0xa5, 0x66, 0xa5, 0x0c, 0xa6, 0x6e, 0x6d, 0x71, 0xf9, 0x4c, 0x86, 0x47, 0x10, 0x1d,
+	0x91, 0xee, 0x17, 0xa4, 0xe5, 0x24, 0xd9, 0x93, 0x4e, 0xc8, 0xff, 0x03, 0x0f, 0x13, 0xc8, 0xbb,
+	0x1f, 0xb8, 0x45, 0xf0, 0xd4, 0x79, 0xb4, 0x66, 0x29, 0xb9, 0xa8, 0x0b, 0x7e, 0xf0, 0x50, 0x0a,
+	0x9e, 0xad, 0x2f, 0x5f, 0xca, 0x0d, 0x19, 0x1f, 0xea, 0x4c, 0x35, 0x54, 0x61, 0x3a, 0x6a, 0x80,
+	0x72, 0xa6, 0x25, 0x99, 0xb4, 0xc9, 0x1c, 0x79, 0x04, 0x06, 0xb0, 0x2c, 0x78, 0xde, 0xe2, 0x57,
+	0xd0, 0x8b, 0x4f, 0x6e, 0x35, 0x06, 0x6c, 0x97, 0xc6, 0x80, 0x21, 0x88, 0x00, 0x90, 0xf9, 0x7c,
+	0x8b, 0xd2, 0x2b, 0x8e, 0xb7, 0x67, 0xd9, 0x9d, 0x9b, 0xc5, 0x7c, 0x3e, 0x04, 0xd2, 0xec, 0x92,
+	0x2d, 0xf3, 0x74, 0xe4, 0xb4, 0x44, 0x4e, 0x21, 0x5a, 0x14, 0xa2, 0x45, 0xa0, 0x9d, 0xe3, 0xff,
+	0xb1, 0x3d, 0xc9, 0x91, 0xfb, 0xdf, 0x01, 0x00, 0x82, 0xd2, 0xbe, 0xde, 0x38, 0x02, 0x00, 0x00,
+}
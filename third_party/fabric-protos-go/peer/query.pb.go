@@ -0,0 +1,279 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peer/query.proto
+
+package peer
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ChaincodeQueryResponse returns information about each chaincode that pertains
+// to a query in lscc.go, such as GetChaincodes (returns all chaincodes
+// instantiated on a channel), and GetInstalledChaincodes (returns all chaincodes
+// installed on a peer)
+type ChaincodeQueryResponse struct {
+	Chaincodes           []*ChaincodeInfo `protobuf:"bytes,1,rep,name=chaincodes,proto3" json:"chaincodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ChaincodeQueryResponse) Reset()         { *m = ChaincodeQueryResponse{} }
+func (m *ChaincodeQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeQueryResponse) ProtoMessage()    {}
+func (*ChaincodeQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d45bcf7fe2423301, []int{0}
+}
+
+func (m *ChaincodeQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeQueryResponse.Unmarshal(m, b)
+}
+func (m *ChaincodeQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeQueryResponse.Merge(m, src)
+}
+func (m *ChaincodeQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeQueryResponse.Size(m)
+}
+func (m *ChaincodeQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeQueryResponse proto.InternalMessageInfo
+
+func (m *ChaincodeQueryResponse) GetChaincodes() []*ChaincodeInfo {
+	if m != nil {
+		return m.Chaincodes
+	}
+	return nil
+}
+
+// ChaincodeInfo contains general information about an installed/instantiated
+// chaincode
+type ChaincodeInfo struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// the path as specified by the install/instantiate transaction
+	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// the chaincode function upon instantiation and its arguments. This will be
+	// blank if the query is returning information about installed chaincodes.
+	Input string `protobuf:"bytes,4,opt,name=input,proto3" json:"input,omitempty"`
+	// the name of the ESCC for this chaincode. This will be
+	// blank if the query is returning information about installed chaincodes.
+	Escc string `protobuf:"bytes,5,opt,name=escc,proto3" json:"escc,omitempty"`
+	// the name of the VSCC for this chaincode. This will be
+	// blank if the query is returning information about installed chaincodes.
+	Vscc string `protobuf:"bytes,6,opt,name=vscc,proto3" json:"vscc,omitempty"`
+	// the chaincode unique id.
+	// computed as: H(
+	//                H(name || version) ||
+	//                H(CodePackage)
+	//              )
+	Id                   []byte   `protobuf:"bytes,7,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeInfo) Reset()         { *m = ChaincodeInfo{} }
+func (m *ChaincodeInfo) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeInfo) ProtoMessage()    {}
+func (*ChaincodeInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d45bcf7fe2423301, []int{1}
+}
+
+func (m *ChaincodeInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeInfo.Unmarshal(m, b)
+}
+func (m *ChaincodeInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeInfo.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeInfo.Merge(m, src)
+}
+func (m *ChaincodeInfo) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeInfo.Size(m)
+}
+func (m *ChaincodeInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeInfo proto.InternalMessageInfo
+
+func (m *ChaincodeInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChaincodeInfo) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ChaincodeInfo) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ChaincodeInfo) GetInput() string {
+	if m != nil {
+		return m.Input
+	}
+	return ""
+}
+
+func (m *ChaincodeInfo) GetEscc() string {
+	if m != nil {
+		return m.Escc
+	}
+	return ""
+}
+
+func (m *ChaincodeInfo) GetVscc() string {
+	if m != nil {
+		return m.Vscc
+	}
+	return ""
+}
+
+func (m *ChaincodeInfo) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+// ChannelQueryResponse returns information about each channel that pertains
+// to a query in lscc.go, such as GetChannels (returns all channels for a
+// given peer)
+type ChannelQueryResponse struct {
+	Channels             []*ChannelInfo `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ChannelQueryResponse) Reset()         { *m = ChannelQueryResponse{} }
+func (m *ChannelQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*ChannelQueryResponse) ProtoMessage()    {}
+func (*ChannelQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d45bcf7fe2423301, []int{2}
+}
+
+func (m *ChannelQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChannelQueryResponse.Unmarshal(m, b)
+}
+func (m *ChannelQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChannelQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *ChannelQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelQueryResponse.Merge(m, src)
+}
+func (m *ChannelQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_ChannelQueryResponse.Size(m)
+}
+func (m *ChannelQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelQueryResponse proto.InternalMessageInfo
+
+func (m *ChannelQueryResponse) GetChannels() []*ChannelInfo {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+// ChannelInfo contains general information about channels
+type ChannelInfo struct {
+	ChannelId            string   `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChannelInfo) Reset()         { *m = ChannelInfo{} }
+func (m *ChannelInfo) String() string { return proto.CompactTextString(m) }
+func (*ChannelInfo) ProtoMessage()    {}
+func (*ChannelInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d45bcf7fe2423301, []int{3}
+}
+
+func (m *ChannelInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChannelInfo.Unmarshal(m, b)
+}
+func (m *ChannelInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChannelInfo.Marshal(b, m, deterministic)
+}
+func (m *ChannelInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelInfo.Merge(m, src)
+}
+func (m *ChannelInfo) XXX_Size() int {
+	return xxx_messageInfo_ChannelInfo.Size(m)
+}
+func (m *ChannelInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelInfo proto.InternalMessageInfo
+
+func (m *ChannelInfo) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ChaincodeQueryResponse)(nil), "protos.ChaincodeQueryResponse")
+	proto.RegisterType((*ChaincodeInfo)(nil), "protos.ChaincodeInfo")
+	proto.RegisterType((*ChannelQueryResponse)(nil), "protos.ChannelQueryResponse")
+	proto.RegisterType((*ChannelInfo)(nil), "protos.ChannelInfo")
+}
+
+func init() { proto.RegisterFile("peer/query.proto", fileDescriptor_d45bcf7fe2423301) }
+
+var fileDescriptor_d45bcf7fe2423301 = []byte{
+	// 300 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xcd, 0x6a, 0xeb, 0x30,
+	0x10, 0x85, 0x71, 0x7e, 0x6f, 0x26, 0xb7, 0xa5, 0xa8, 0x69, 0xd1, 0xa6, 0x10, 0xbc, 0xca, 0x22,
+	0xb1, 0xa1, 0xa5, 0x2f, 0xd0, 0x2c, 0x4a, 0x56, 0xa5, 0x5e, 0x76, 0x53, 0x1c, 0x79, 0x62, 0x0b,
+	0x12, 0x49, 0x95, 0xec, 0x40, 0x9e, 0xa6, 0xaf, 0x5a, 0x46, 0x8a, 0x83, 0xb3, 0xf2, 0xcc, 0x77,
+	0x3e, 0x61, 0x8e, 0x04, 0x77, 0x06, 0xd1, 0xa6, 0x3f, 0x0d, 0xda, 0x53, 0x62, 0xac, 0xae, 0x35,
+	0x1b, 0xf9, 0x8f, 0x8b, 0x3f, 0xe0, 0x71, 0x5d, 0xe5, 0x52, 0x09, 0x5d, 0xe0, 0x27, 0xe5, 0x19,
+	0x3a, 0xa3, 0x95, 0x43, 0xf6, 0x0a, 0x20, 0xda, 0xc4, 0xf1, 0x68, 0xde, 0x5f, 0x4c, 0x9f, 0x1f,
+	0xc2, 0x69, 0x97, 0x5c, 0xce, 0x6c, 0xd4, 0x4e, 0x67, 0x1d, 0x31, 0xfe, 0x8d, 0xe0, 0xe6, 0x2a,
+	0x65, 0x0c, 0x06, 0x2a, 0x3f, 0x20, 0x8f, 0xe6, 0xd1, 0x62, 0x92, 0xf9, 0x99, 0x71, 0x18, 0x1f,
+	0xd1, 0x3a, 0xa9, 0x15, 0xef, 0x79, 0xdc, 0xae, 0x64, 0x9b, 0xbc, 0xae, 0x78, 0x3f, 0xd8, 0x34,
+	0xb3, 0x19, 0x0c, 0xa5, 0x32, 0x4d, 0xcd, 0x07, 0x1e, 0x86, 0x85, 0x4c, 0x74, 0x42, 0xf0, 0x61,
+	0x30, 0x69, 0x26, 0x76, 0x24, 0x36, 0x0a, 0x8c, 0x66, 0x76, 0x0b, 0x3d, 0x59, 0xf0, 0xf1, 0x3c,
+	0x5a, 0xfc, 0xcf, 0x7a, 0xb2, 0x88, 0xdf, 0x61, 0xb6, 0xae, 0x72, 0xa5, 0x70, 0x7f, 0x5d, 0x38,
+	0x85, 0x7f, 0x22, 0xf0, 0xb6, 0xee, 0x7d, 0xa7, 0x2e, 0x71, 0x5f, 0xf6, 0x22, 0xc5, 0x4b, 0x98,
+	0x76, 0x02, 0xf6, 0xe4, 0x2f, 0x8c, 0xd6, 0x6f, 0x59, 0x9c, 0xdb, 0x4e, 0xce, 0x64, 0x53, 0xbc,
+	0x65, 0x10, 0x6b, 0x5b, 0x26, 0xd5, 0xc9, 0xa0, 0xdd, 0x63, 0x51, 0xa2, 0x4d, 0x76, 0xf9, 0xd6,
+	0x4a, 0xd1, 0xfe, 0x84, 0xde, 0xe8, 0x6b, 0x59, 0xca, 0xba, 0x6a, 0xb6, 0x89, 0xd0, 0x87, 0xb4,
+	0xa3, 0xa6, 0x41, 0x5d, 0x05, 0x75, 0x55, 0xea, 0x94, 0xec, 0x6d, 0x78, 0xc5, 0x97, 0xbf, 0x00,
+	0x00, 0x00, 0xff, 0xff, 0x22, 0x43, 0xb6, 0x12, 0xe0, 0x01, 0x00, 0x00,
+}
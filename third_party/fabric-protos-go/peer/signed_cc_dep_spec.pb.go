@@ -0,0 +1,112 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peer/signed_cc_dep_spec.proto
+
+package peer
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// SignedChaincodeDeploymentSpec carries the CDS along with endorsements
+type SignedChaincodeDeploymentSpec struct {
+	// This is the bytes of the ChaincodeDeploymentSpec
+	ChaincodeDeploymentSpec []byte `protobuf:"bytes,1,opt,name=chaincode_deployment_spec,json=chaincodeDeploymentSpec,proto3" json:"chaincode_deployment_spec,omitempty"`
+	// This is the instantiation policy which is identical in structure
+	// to endorsement policy.  This policy is checked by the VSCC at commit
+	// time on the instantiation (all peers will get the same policy as it
+	// will be part of the LSCC instantation record and will be part of the
+	// hash as well)
+	InstantiationPolicy []byte `protobuf:"bytes,2,opt,name=instantiation_policy,json=instantiationPolicy,proto3" json:"instantiation_policy,omitempty"`
+	// The endorsements of the above deployment spec, the owner's signature over
+	// chaincode_deployment_spec and Endorsement.endorser.
+	OwnerEndorsements    []*Endorsement `protobuf:"bytes,3,rep,name=owner_endorsements,json=ownerEndorsements,proto3" json:"owner_endorsements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SignedChaincodeDeploymentSpec) Reset()         { *m = SignedChaincodeDeploymentSpec{} }
+func (m *SignedChaincodeDeploymentSpec) String() string { return proto.CompactTextString(m) }
+func (*SignedChaincodeDeploymentSpec) ProtoMessage()    {}
+func (*SignedChaincodeDeploymentSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e007a7c0644e7e6f, []int{0}
+}
+
+func (m *SignedChaincodeDeploymentSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedChaincodeDeploymentSpec.Unmarshal(m, b)
+}
+func (m *SignedChaincodeDeploymentSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedChaincodeDeploymentSpec.Marshal(b, m, deterministic)
+}
+func (m *SignedChaincodeDeploymentSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedChaincodeDeploymentSpec.Merge(m, src)
+}
+func (m *SignedChaincodeDeploymentSpec) XXX_Size() int {
+	return xxx_messageInfo_SignedChaincodeDeploymentSpec.Size(m)
+}
+func (m *SignedChaincodeDeploymentSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedChaincodeDeploymentSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedChaincodeDeploymentSpec proto.InternalMessageInfo
+
+func (m *SignedChaincodeDeploymentSpec) GetChaincodeDeploymentSpec() []byte {
+	if m != nil {
+		return m.ChaincodeDeploymentSpec
+	}
+	return nil
+}
+
+func (m *SignedChaincodeDeploymentSpec) GetInstantiationPolicy() []byte {
+	if m != nil {
+		return m.InstantiationPolicy
+	}
+	return nil
+}
+
+func (m *SignedChaincodeDeploymentSpec) GetOwnerEndorsements() []*Endorsement {
+	if m != nil {
+		return m.OwnerEndorsements
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*SignedChaincodeDeploymentSpec)(nil), "protos.SignedChaincodeDeploymentSpec")
+}
+
+func init() { proto.RegisterFile("peer/signed_cc_dep_spec.proto", fileDescriptor_e007a7c0644e7e6f) }
+
+var fileDescriptor_e007a7c0644e7e6f = []byte{
+	// 258 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0x41, 0x4b, 0xf3, 0x40,
+	0x10, 0x86, 0xc9, 0x57, 0xf8, 0x0e, 0xab, 0x17, 0x53, 0xc1, 0x28, 0x16, 0x4a, 0x4f, 0x3d, 0xd8,
+	0x04, 0xf5, 0xe6, 0xb1, 0xea, 0x5d, 0xd2, 0x9b, 0x97, 0x25, 0x99, 0x8c, 0xc9, 0x42, 0xba, 0x33,
+	0xcc, 0xac, 0x48, 0xfe, 0xa6, 0xbf, 0x48, 0xb2, 0xb1, 0x58, 0x0f, 0x9e, 0x16, 0xf6, 0x79, 0xde,
+	0x99, 0xe1, 0x35, 0x0b, 0x46, 0x94, 0x42, 0x5d, 0xeb, 0xb1, 0xb1, 0x00, 0xb6, 0x41, 0xb6, 0xca,
+	0x08, 0x39, 0x0b, 0x05, 0x4a, 0xff, 0xc7, 0x47, 0xaf, 0xae, 0xa3, 0xc6, 0x42, 0x4c, 0x5a, 0xf5,
+	0x56, 0x50, 0x99, 0xbc, 0xe2, 0x64, 0xad, 0x3e, 0x13, 0xb3, 0xd8, 0xc5, 0x11, 0x8f, 0x5d, 0xe5,
+	0x3c, 0x50, 0x83, 0x4f, 0xc8, 0x3d, 0x0d, 0x7b, 0xf4, 0x61, 0xc7, 0x08, 0xe9, 0x83, 0xb9, 0x84,
+	0x03, 0x1a, 0x77, 0x7c, 0xb3, 0xb8, 0x2a, 0x4b, 0x96, 0xc9, 0xfa, 0xb4, 0xbc, 0x80, 0x3f, 0xb2,
+	0xb7, 0xe6, 0xdc, 0x79, 0x0d, 0x95, 0x0f, 0xae, 0x0a, 0x8e, 0xbc, 0x65, 0xea, 0x1d, 0x0c, 0xd9,
+	0xbf, 0x18, 0x9b, 0xff, 0x62, 0x2f, 0x11, 0xa5, 0x5b, 0x93, 0xd2, 0x87, 0x47, 0xb1, 0xe8, 0x1b,
+	0x12, 0xc5, 0x71, 0x96, 0x66, 0xb3, 0xe5, 0x6c, 0x7d, 0x72, 0x37, 0x9f, 0x8e, 0xd6, 0xfc, 0xf9,
+	0x87, 0x95, 0x67, 0x51, 0x3f, 0xfa, 0xd1, 0x6d, 0x69, 0x56, 0x24, 0x6d, 0xde, 0x0d, 0x8c, 0xd2,
+	0x63, 0xd3, 0xa2, 0xe4, 0x6f, 0x55, 0x2d, 0x0e, 0x0e, 0xf9, 0xb1, 0x92, 0xd7, 0x9b, 0xd6, 0x85,
+	0xee, 0xbd, 0xce, 0x81, 0xf6, 0xc5, 0x91, 0x5a, 0x4c, 0xea, 0x66, 0x52, 0x37, 0x2d, 0x15, 0xa3,
+	0x5d, 0x4f, 0x75, 0xde, 0x7f, 0x05, 0x00, 0x00, 0xff, 0xff, 0xa0, 0xc6, 0x22, 0xdb, 0x76, 0x01,
+	0x00, 0x00,
+}